@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/network"
+	"coinflip-game/internal/storage"
+)
+
+// newArchiveCommand creates the archive command for triggering result archival
+func newArchiveCommand(app *AdminApp) *cobra.Command {
+	return &cobra.Command{
+		Use:   "archive",
+		Short: "Archive old game results on the server",
+		Long: `Trigger an immediate archival pass on the server. Results older than the
+configured age are moved from hot storage into compressed archive files, and
+archive files past their retention period are deleted.`,
+		Example: `  coinflip-admin archive`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runArchive(cmd.Context(), app)
+		},
+	}
+}
+
+// runArchive calls the server's archive endpoint and prints the report
+func runArchive(ctx context.Context, app *AdminApp) error {
+	req, err := app.newRequest(ctx, http.MethodPost, "/admin/archive", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := app.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorData network.ErrorData
+		if err := json.NewDecoder(resp.Body).Decode(&errorData); err != nil || errorData.Message == "" {
+			return fmt.Errorf("archive request failed: %s", resp.Status)
+		}
+		return fmt.Errorf("archive request failed: %s", errorData.Message)
+	}
+
+	var report storage.ArchiveReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return fmt.Errorf("failed to decode archive report: %w", err)
+	}
+
+	fmt.Println("🗄️  Archive Report")
+	fmt.Println("=================")
+	fmt.Printf("Cutoff: %s\n", report.Cutoff.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Archived results: %d\n", report.ArchivedResults)
+	if report.ArchiveFile != "" {
+		fmt.Printf("Archive file: %s\n", report.ArchiveFile)
+	}
+	fmt.Printf("Expired archives removed: %d\n", report.PurgedFiles)
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/logger"
+)
+
+// newAuditCommand creates the audit command for checking audit trails
+func newAuditCommand(app *AdminApp) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect gameplay audit trails",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "verify [file]",
+		Short: "Verify the hash chain of an audit log",
+		Long: `Verify that an audit log has not been modified, truncated in the middle or
+reordered since it was written. Defaults to the configured logging.audit_file.`,
+		Example: `  coinflip-admin audit verify
+  coinflip-admin audit verify /var/log/coinflip/audit.jsonl`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := app.Config.Logging.AuditFile
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return runAuditVerify(path)
+		},
+	})
+
+	return cmd
+}
+
+// runAuditVerify checks an audit log file and prints the outcome
+func runAuditVerify(path string) error {
+	if path == "" {
+		return fmt.Errorf("no audit file given and logging.audit_file is not set")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	count, err := logger.VerifyAuditLog(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w (after %d valid events)", path, err, count)
+	}
+
+	fmt.Printf("✅ %s: %d events verified\n", path, count)
+	return nil
+}
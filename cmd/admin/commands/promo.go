@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// newPromoCommand creates the promo command for managing promo codes
+func newPromoCommand(app *AdminApp) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promo",
+		Short: "Issue and inspect promo codes",
+	}
+
+	var spec network.PromoSpec
+	var value float64
+	var expiresIn time.Duration
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a promo code",
+		Long: `Create a promo code that credits --value to each player redeeming it, for up
+to --uses players. Without --code a random code is generated.`,
+		Example: `  coinflip-admin promo create --value 50 --uses 100 --expires 72h
+  coinflip-admin promo create --code WELCOME50 --value 50 --uses 1000`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if spec.Value, err = game.MoneyFromFloat(value); err != nil {
+				return fmt.Errorf("invalid --value: %w", err)
+			}
+			if expiresIn > 0 {
+				spec.ExpiresAt = time.Now().Add(expiresIn)
+			}
+			return runPromoCreate(cmd.Context(), app, spec)
+		},
+	}
+	createCmd.Flags().StringVar(&spec.Code, "code", "", "Code to issue (default: generated)")
+	createCmd.Flags().Float64Var(&value, "value", 0, "Amount credited per redemption (required)")
+	createCmd.Flags().IntVar(&spec.MaxUses, "uses", 1, "Number of players who can redeem the code")
+	createCmd.Flags().DurationVar(&expiresIn, "expires", 0, "Time until the code expires (default: never)")
+	createCmd.MarkFlagRequired("value")
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List promo codes and their usage",
+		Example: `  coinflip-admin promo list`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPromoList(cmd.Context(), app)
+		},
+	}
+
+	cmd.AddCommand(createCmd, listCmd)
+	return cmd
+}
+
+// runPromoCreate asks the server to issue a promo code and prints it
+func runPromoCreate(ctx context.Context, app *AdminApp, spec network.PromoSpec) error {
+	body, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode promo code: %w", err)
+	}
+
+	var promo network.PromoCode
+	if err := app.doPromoRequest(ctx, http.MethodPost, bytes.NewReader(body), &promo); err != nil {
+		return err
+	}
+
+	fmt.Printf("🎁 Created promo code %s\n", promo.Code)
+	fmt.Printf("Value: %s per player, %d uses\n", promo.Value.Format(), promo.MaxUses)
+	if !promo.ExpiresAt.IsZero() {
+		fmt.Printf("Expires: %s\n", promo.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+// runPromoList prints the server's promo codes
+func runPromoList(ctx context.Context, app *AdminApp) error {
+	var promos []network.PromoCode
+	if err := app.doPromoRequest(ctx, http.MethodGet, nil, &promos); err != nil {
+		return err
+	}
+
+	if len(promos) == 0 {
+		fmt.Println("No promo codes issued")
+		return nil
+	}
+
+	fmt.Printf("%-12s %10s %9s  %s\n", "CODE", "VALUE", "USES", "EXPIRES")
+	for _, promo := range promos {
+		expires := "never"
+		if !promo.ExpiresAt.IsZero() {
+			expires = promo.ExpiresAt.Local().Format("2006-01-02 15:04")
+			if promo.Expired(time.Now()) {
+				expires += " (expired)"
+			}
+		}
+		fmt.Printf("%-12s %10s %4d/%-4d  %s\n", promo.Code, promo.Value, promo.Uses, promo.MaxUses, expires)
+	}
+	return nil
+}
+
+// doPromoRequest calls the server's promo endpoint and decodes the reply
+func (app *AdminApp) doPromoRequest(ctx context.Context, method string, body io.Reader, out interface{}) error {
+	req, err := app.newRequest(ctx, method, "/admin/promos", body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := app.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errorData network.ErrorData
+		if err := json.NewDecoder(resp.Body).Decode(&errorData); err != nil || errorData.Message == "" {
+			return fmt.Errorf("promo request failed: %s", resp.Status)
+		}
+		return fmt.Errorf("promo request failed: %s", errorData.Message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode promo response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/network"
+)
+
+// newPromotionCommand creates the promotion command for scheduling happy
+// hours that boost payouts
+func newPromotionCommand(app *AdminApp) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promotion",
+		Short: "Schedule happy hours that boost payouts",
+	}
+
+	var spec network.PromotionSpec
+	var startsIn, lasts time.Duration
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Schedule a happy hour",
+		Long: `Schedule a happy hour multiplying winning payouts by --boost for --for,
+starting at once or after --in. It runs in --room, or in every room without
+it; parimutuel and duel rooms are never boosted. Happy hours repeated every
+day are set in the server's multiplayer.happy_hours configuration.`,
+		Example: `  coinflip-admin promotion create --name "Happy hour" --boost 1.5 --for 1h
+  coinflip-admin promotion create --name "VIP night" --room vip --boost 2 --in 3h --for 2h`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if lasts <= 0 {
+				return fmt.Errorf("--for must be positive")
+			}
+			spec.StartsAt = time.Now().Add(startsIn)
+			spec.EndsAt = spec.StartsAt.Add(lasts)
+			return runPromotionCreate(cmd.Context(), app, spec)
+		},
+	}
+	createCmd.Flags().StringVar(&spec.Name, "name", "Happy hour", "Name players see")
+	createCmd.Flags().StringVar(&spec.RoomID, "room", "", "Room to boost (default: every room)")
+	createCmd.Flags().Float64Var(&spec.Boost, "boost", 0, "Multiplier applied to winning payouts (required)")
+	createCmd.Flags().DurationVar(&startsIn, "in", 0, "Time until the happy hour starts (default: now)")
+	createCmd.Flags().DurationVar(&lasts, "for", time.Hour, "How long the happy hour lasts")
+	createCmd.MarkFlagRequired("boost")
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List running and upcoming happy hours",
+		Example: `  coinflip-admin promotion list`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPromotionList(cmd.Context(), app)
+		},
+	}
+
+	cancelCmd := &cobra.Command{
+		Use:     "cancel <id>",
+		Short:   "End a happy hour early or call it off",
+		Example: `  coinflip-admin promotion cancel promo1`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.doPromotionRequest(cmd.Context(), http.MethodDelete, args[0], nil, nil); err != nil {
+				return err
+			}
+			fmt.Printf("🛑 Cancelled promotion %s\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.AddCommand(createCmd, listCmd, cancelCmd)
+	return cmd
+}
+
+// runPromotionCreate asks the server to schedule a happy hour and prints it
+func runPromotionCreate(ctx context.Context, app *AdminApp, spec network.PromotionSpec) error {
+	body, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode promotion: %w", err)
+	}
+
+	var promotion network.Promotion
+	if err := app.doPromotionRequest(ctx, http.MethodPost, "", bytes.NewReader(body), &promotion); err != nil {
+		return err
+	}
+
+	fmt.Printf("🎉 Scheduled %s (%s)\n", promotion.Name, promotion.ID)
+	fmt.Printf("Boost: %gx payouts in %s\n", promotion.Boost, promotionRoom(promotion))
+	fmt.Printf("From %s until %s\n",
+		promotion.StartsAt.Local().Format("2006-01-02 15:04"), promotion.EndsAt.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+// runPromotionList prints the server's running and upcoming happy hours
+func runPromotionList(ctx context.Context, app *AdminApp) error {
+	var promotions []network.Promotion
+	if err := app.doPromotionRequest(ctx, http.MethodGet, "", nil, &promotions); err != nil {
+		return err
+	}
+
+	if len(promotions) == 0 {
+		fmt.Println("No happy hours scheduled")
+		return nil
+	}
+
+	fmt.Printf("%-16s %-20s %6s  %-12s %s\n", "ID", "NAME", "BOOST", "ROOM", "WHEN")
+	now := time.Now()
+	for _, promotion := range promotions {
+		when := promotion.StartsAt.Local().Format("2006-01-02 15:04") + " - " + promotion.EndsAt.Local().Format("15:04")
+		if promotion.Running(now) {
+			when += " (running)"
+		}
+		fmt.Printf("%-16s %-20s %5gx  %-12s %s\n", promotion.ID, promotion.Name, promotion.Boost, promotionRoom(promotion), when)
+	}
+	return nil
+}
+
+// promotionRoom names the room a promotion boosts
+func promotionRoom(promotion network.Promotion) string {
+	if promotion.RoomID == "" {
+		return "every room"
+	}
+	return promotion.RoomID
+}
+
+// doPromotionRequest calls the server's promotion endpoint, or the one of
+// the promotion with the given ID, and decodes the reply into out if set
+func (app *AdminApp) doPromotionRequest(ctx context.Context, method, id string, body io.Reader, out interface{}) error {
+	path := "/admin/promotions"
+	if id != "" {
+		path += "/" + id
+	}
+	req, err := app.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := app.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		var errorData network.ErrorData
+		if err := json.NewDecoder(resp.Body).Decode(&errorData); err != nil || errorData.Message == "" {
+			return fmt.Errorf("promotion request failed: %s", resp.Status)
+		}
+		return fmt.Errorf("promotion request failed: %s", errorData.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode promotion response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/network"
+	"coinflip-game/internal/storage"
+)
+
+// newSnapshotCommand creates the snapshot command for taking a balance
+// snapshot at once
+func newSnapshotCommand(app *AdminApp) *cobra.Command {
+	return &cobra.Command{
+		Use:   "snapshot",
+		Short: "Snapshot every player's balance and stats now",
+		Long: `Take a balance snapshot on the server at once instead of waiting for the
+next scheduled one, for example before a risky deployment. The server must
+have balance_snapshots enabled.`,
+		Example: `  coinflip-admin snapshot`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var report storage.BalanceSnapshotReport
+			if err := app.doBalancesRequest(cmd.Context(), "snapshot", nil, &report); err != nil {
+				return err
+			}
+			fmt.Printf("📸 Snapshot of %d players taken at %s\n", report.Players, report.TakenAt.Local().Format("2006-01-02 15:04:05"))
+			fmt.Printf("Snapshot file: %s\n", report.SnapshotFile)
+			if report.PurgedFiles > 0 {
+				fmt.Printf("Expired snapshots removed: %d\n", report.PurgedFiles)
+			}
+			return nil
+		},
+	}
+}
+
+// newRestoreCommand creates the restore command for putting balances back
+// to an earlier snapshot
+func newRestoreCommand(app *AdminApp) *cobra.Command {
+	var at string
+
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore player balances and stats to an earlier point in time",
+		Long: `Put every player's balance and stats back to the latest snapshot taken at or
+before --at, undoing whatever a bug or crash did to them since. Players seated
+in a room have their room balance moved too, recorded as a credit in the audit
+trail. Players who first played after the snapshot keep what they have.
+
+--at takes an RFC 3339 time, or a local "2006-01-02 15:04".`,
+		Example: `  coinflip-admin restore --at 2024-06-01T12:00:00Z
+  coinflip-admin restore --at "2024-06-01 14:30"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			when, err := parseRestoreTime(at)
+			if err != nil {
+				return err
+			}
+			return runRestore(cmd.Context(), app, when)
+		},
+	}
+	cmd.Flags().StringVar(&at, "at", "", "Point in time to restore balances to (required)")
+	cmd.MarkFlagRequired("at")
+	return cmd
+}
+
+// runRestore asks the server to restore balances and prints the report
+func runRestore(ctx context.Context, app *AdminApp, at time.Time) error {
+	query := url.Values{"at": {at.UTC().Format(time.RFC3339)}}
+	var report network.BalanceRestoreReport
+	if err := app.doBalancesRequest(ctx, "restore", query, &report); err != nil {
+		return err
+	}
+
+	fmt.Println("⏪ Balance Restore")
+	fmt.Println("=================")
+	fmt.Printf("Snapshot taken: %s\n", report.TakenAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("Snapshot file: %s\n", report.SnapshotFile)
+	fmt.Printf("Players restored: %d\n", report.Players)
+	fmt.Printf("Seated players adjusted: %d\n", report.SeatedPlayers)
+	return nil
+}
+
+// parseRestoreTime reads an RFC 3339 time or a local date and time
+func parseRestoreTime(value string) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	if at, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local); err == nil {
+		return at, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --at %q: use an RFC 3339 time or \"2006-01-02 15:04\"", value)
+}
+
+// doBalancesRequest posts to one of the server's balance snapshot
+// endpoints and decodes the reply into out
+func (app *AdminApp) doBalancesRequest(ctx context.Context, action string, query url.Values, out interface{}) error {
+	path := "/admin/balances/" + action
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := app.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := app.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorData network.ErrorData
+		if err := json.NewDecoder(resp.Body).Decode(&errorData); err != nil || errorData.Message == "" {
+			return fmt.Errorf("%s request failed: %s", action, resp.Status)
+		}
+		return fmt.Errorf("%s request failed: %s", action, errorData.Message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s report: %w", action, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Package commands provides the admin CLI command structure for the coin flip server.
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/config"
+)
+
+// AdminApp holds the application dependencies for admin commands
+type AdminApp struct {
+	Config     *config.Config
+	Logger     *zap.Logger
+	HTTPClient *http.Client
+	ServerURL  string
+}
+
+// NewRootCommand creates the root admin command with all subcommands
+func NewRootCommand(cfg *config.Config, logger *zap.Logger) *cobra.Command {
+	app := &AdminApp{
+		Config:     cfg,
+		Logger:     logger,
+		HTTPClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+
+	rootCmd := &cobra.Command{
+		Use:   "coinflip-admin",
+		Short: "Administer a running coin flip server",
+		Long: `Coinflip-admin performs maintenance tasks against a running multiplayer
+server through its admin HTTP endpoints. Requests authenticate with the
+admin.username and admin.password the server is configured with, which can
+also be set with COINFLIP_ADMIN_PASSWORD.`,
+		Example: `  # Archive old results now instead of waiting for the next scheduled pass
+  coinflip-admin archive
+
+  # Target a specific server
+  coinflip-admin archive --server http://game.example.com:8080
+
+  # Check the audit trail for tampering
+  coinflip-admin audit verify
+
+  # Issue a promo code worth $50 to the first 100 players redeeming it
+  coinflip-admin promo create --value 50 --uses 100 --expires 72h
+
+  # Put balances back as they were before a bad deployment
+  coinflip-admin restore --at 2024-06-01T12:00:00Z`,
+	}
+
+	rootCmd.PersistentFlags().StringVarP(&app.ServerURL, "server", "s",
+		fmt.Sprintf("http://%s:%d", cfg.Multiplayer.ServerHost, cfg.Multiplayer.ServerPort),
+		"Base URL of the coin flip server")
+
+	// Add subcommands
+	rootCmd.AddCommand(
+		newArchiveCommand(app),
+		newAuditCommand(app),
+		newPromoCommand(app),
+		newPromotionCommand(app),
+		newSnapshotCommand(app),
+		newRestoreCommand(app),
+	)
+
+	return rootCmd
+}
+
+// newRequest builds a request to one of the server's admin endpoints,
+// authenticated with the configured admin credentials. Requests are sent as
+// JSON, which the server accepts without the dashboard's form token.
+func (app *AdminApp) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	url := strings.TrimRight(app.ServerURL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(app.Config.Admin.Username, app.Config.Admin.Password)
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
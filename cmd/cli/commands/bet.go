@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -11,83 +12,194 @@ import (
 
 // newBetCommand creates the bet command for placing a single bet
 func newBetCommand(app *CLIApp) *cobra.Command {
-	var amount float64
+	var amount string
 	var choice string
+	var favorite string
+	var insure, practice bool
+	var opts multiplayerBetOptions
 
 	cmd := &cobra.Command{
 		Use:   "bet",
 		Short: "Place a single bet and flip the coin",
 		Long: `Place a single bet on heads or tails and immediately flip the coin 
-to see the result. This is useful for scripting or one-off bets.`,
+to see the result. This is useful for scripting or one-off bets.
+
+With --room the bet is submitted to a multiplayer room instead: the command 
+connects, joins the room, bets in the next betting phase, waits for the round 
+result, prints it as JSON and disconnects.`,
 		Example: `  coinflip bet --amount 10 --choice heads
-  coinflip bet -a 25.5 -c tails`,
+  coinflip bet -a 25.5 -c tails
+
+  # Bet a share of the balance: a percentage, half or the most allowed
+  coinflip bet -a 10% -c heads
+  coinflip bet -a half -c tails
+  coinflip bet -a max -c heads
+
+  # Insure the stake, when the game offers insurance
+  coinflip bet -a 20 -c heads --insure
+
+  # Place a saved favorite bet
+  coinflip bet --favorite lucky25
+
+  # Practice without touching the balance or stats
+  coinflip bet -a 50 -c tails --practice
+
+  # Bet in a multiplayer room and print the round result as JSON
+  coinflip bet -a 10 -c heads --room lobby --server ws://localhost:8080/ws`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSingleBet(cmd.Context(), app, amount, choice)
+			var stake game.BetAmount
+			if favorite != "" {
+				saved, err := lookupFavorite(app, favorite)
+				if err != nil {
+					return err
+				}
+				stake, choice = saved.Amount, string(saved.Choice)
+				insure = insure || saved.Insured
+				fmt.Printf("⭐ %s: %s\n", saved.Name, saved.Describe())
+			} else {
+				parsed, err := parseBetAmount(amount)
+				if err != nil {
+					return err
+				}
+				stake = parsed
+			}
+			if insure && practice {
+				return invalidInput(game.ErrPracticeInsured)
+			}
+			if opts.RoomID != "" {
+				opts.Insure = insure
+				opts.Practice = practice
+				return runMultiplayerBet(cmd.Context(), app, stake, choice, opts)
+			}
+			return runSingleBet(cmd.Context(), app, stake, choice, insure, practice)
 		},
 	}
 
-	cmd.Flags().Float64VarP(&amount, "amount", "a", 0, "Bet amount (required)")
-	cmd.Flags().StringVarP(&choice, "choice", "c", "", "Choice: heads or tails (required)")
-
-	cmd.MarkFlagRequired("amount")
-	cmd.MarkFlagRequired("choice")
+	cmd.Flags().StringVarP(&amount, "amount", "a", "", "Bet amount in dollars, a percentage of the balance such as 10%, half or max (required without --favorite)")
+	cmd.Flags().StringVarP(&choice, "choice", "c", "", "Choice: heads or tails (required without --favorite)")
+	cmd.Flags().StringVarP(&favorite, "favorite", "f", "", "Place the favorite bet saved under this name")
+	cmd.Flags().BoolVar(&insure, "insure", false, "Buy bet insurance, refunding part of a lost stake")
+	cmd.Flags().BoolVar(&practice, "practice", false, "Practice bet that leaves the balance and stats alone")
+	cmd.Flags().StringVar(&opts.RoomID, "room", "", "Multiplayer room to bet in")
+	cmd.Flags().StringVar(&opts.ServerURL, "server",
+		fmt.Sprintf("ws://%s:%d/ws", app.Config.Multiplayer.ServerHost, app.Config.Multiplayer.ServerPort),
+		"Multiplayer server WebSocket URL (used with --room)")
+	cmd.Flags().StringVar(&opts.PlayerName, "name", "", "Player name in the room (default: generated)")
+	cmd.Flags().Float64Var(&opts.Balance, "balance", app.Config.Game.StartingBalance, "Balance to join the room with")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 3*time.Minute, "Maximum time to wait for the round result")
+	cmd.Flags().MarkDeprecated("balance", "the server keeps each player's balance and ignores it")
+
+	cmd.MarkFlagsOneRequired("amount", "favorite")
+	cmd.MarkFlagsOneRequired("choice", "favorite")
+	cmd.MarkFlagsMutuallyExclusive("amount", "favorite")
+	cmd.MarkFlagsMutuallyExclusive("choice", "favorite")
+	cmd.RegisterFlagCompletionFunc("choice", completeValues(string(game.Heads), string(game.Tails)))
+	cmd.RegisterFlagCompletionFunc("amount", completeValues(game.AmountHalf, game.AmountMax))
+	cmd.RegisterFlagCompletionFunc("favorite", completeFavorites(app))
 
 	return cmd
 }
 
 // runSingleBet executes a single bet operation
-func runSingleBet(ctx context.Context, app *CLIApp, amount float64, choiceStr string) error {
-	playerID := getPlayerID()
-
-	// Validate and parse choice
-	var choice game.Side
-	switch choiceStr {
-	case "heads", "h":
-		choice = game.Heads
-	case "tails", "t":
-		choice = game.Tails
-	default:
-		return fmt.Errorf("invalid choice '%s', must be 'heads' or 'tails'", choiceStr)
+func runSingleBet(ctx context.Context, app *CLIApp, amount game.BetAmount, choiceStr string, insure, practice bool) error {
+	choice, err := parseChoice(choiceStr)
+	if err != nil {
+		return err
 	}
 
+	syncBankroll(ctx, app)
+	defer syncBankroll(ctx, app)
+	openHistory(ctx, app)
+
 	// Get player info
-	player, err := app.Engine.GetPlayer(ctx, playerID)
+	player, err := app.Session.Player(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get player: %w", err)
 	}
 
-	fmt.Printf("💰 Current balance: $%.2f\n", player.Balance)
+	fmt.Printf("💰 Current balance: %s\n", player.Balance.Format())
+	stake := resolveBetAmount(amount, player.Balance, app.Engine.GetConfig().MaxBet)
 
 	// Check for existing bet
-	if currentBet := app.Engine.GetCurrentBet(); currentBet != nil {
-		return fmt.Errorf("you already have an active bet of $%.2f on %s, please flip the coin first",
-			currentBet.Amount, currentBet.Choice)
+	if currentBet := app.Session.CurrentBet(); currentBet != nil {
+		return fmt.Errorf("you already have an active bet of %s on %s, please flip the coin first",
+			currentBet.Amount.Format(), currentBet.Choice)
 	}
 
 	// Place bet
-	bet, err := app.Engine.PlaceBet(ctx, playerID, amount, choice)
+	placeBet := app.Session.PlaceBet
+	if insure {
+		placeBet = app.Session.PlaceInsuredBet
+	}
+	if practice {
+		placeBet = app.Session.PlacePracticeBet
+	}
+	bet, err := placeBet(ctx, stake, choice)
 	if err != nil {
 		return fmt.Errorf("failed to place bet: %w", err)
 	}
 
-	fmt.Printf("✅ Bet placed: $%.2f on %s\n", bet.Amount, bet.Choice)
+	displayBetPlaced(bet)
 	fmt.Println("🎲 Flipping coin...")
 
 	// Flip the coin
-	result, err := app.Engine.FlipCoin(ctx, playerID)
+	result, err := app.Session.FlipCoin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to flip coin: %w", err)
 	}
+	recordResult(app, result)
 
 	// Display result
 	displayResult(result)
 
 	// Get updated balance
-	player, err = app.Engine.GetPlayer(ctx, playerID)
+	player, err = app.Session.Player(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get updated player info: %w", err)
 	}
 
-	fmt.Printf("\n💰 New balance: $%.2f\n", player.Balance)
+	fmt.Printf("\n💰 New balance: %s\n", player.Balance.Format())
 	return nil
 }
+
+// parseChoice parses a heads/tails flag value
+func parseChoice(choiceStr string) (game.Side, error) {
+	switch choiceStr {
+	case "heads", "h":
+		return game.Heads, nil
+	case "tails", "t":
+		return game.Tails, nil
+	default:
+		return "", invalidInput(fmt.Errorf("invalid choice '%s', must be 'heads' or 'tails'", choiceStr))
+	}
+}
+
+// parseBetAmount parses a bet amount flag value, which may be relative to
+// the balance
+func parseBetAmount(amount string) (game.BetAmount, error) {
+	parsed, err := game.ParseBetAmount(amount)
+	if err != nil {
+		return game.BetAmount{}, invalidInput(err)
+	}
+	return parsed, nil
+}
+
+// resolveBetAmount turns a bet amount into a stake against the balance,
+// showing what a relative amount came to
+func resolveBetAmount(amount game.BetAmount, balance, maxBet game.Money) game.Money {
+	stake := amount.Resolve(balance, maxBet)
+	if amount.Relative() {
+		fmt.Printf("🧮 %s of %s is %s\n", amount, balance.Format(), stake.Format())
+	}
+	return stake
+}
+
+// parseAmount converts an amount flag value to Money, refusing fractions
+// of a cent
+func parseAmount(amount float64) (game.Money, error) {
+	money, err := game.MoneyFromFloat(amount)
+	if err != nil {
+		return 0, invalidInput(err)
+	}
+	return money, nil
+}
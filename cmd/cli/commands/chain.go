@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+// newVerifyChainCommand creates the verify-chain command for checking the
+// saved single-player history
+func newVerifyChainCommand(app *CLIApp) *cobra.Command {
+	var path string
+
+	cmd := &cobra.Command{
+		Use:   "verify-chain",
+		Short: "Check the saved game history has not been tampered with",
+		Long: `Check every result in the saved single-player history. Each result carries
+a hash of itself and of the player's result before it, so editing a result,
+removing one or reordering them breaks the chain and is reported with the
+first result affected. Results removed from the very end cannot be told
+apart from games never played.
+
+The history is kept in the ui.history_file setting.`,
+		Example: `  coinflip verify-chain
+  coinflip verify-chain --file backup/history.jsonl`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return verifyChain(path)
+		},
+	}
+
+	cmd.Flags().StringVar(&path, "file", app.Config.UI.HistoryFile, "History file to check")
+
+	return cmd
+}
+
+// verifyChain checks the chain of results in the history file at path
+func verifyChain(path string) error {
+	if path == "" {
+		return invalidInput(errors.New("no history file is set; set ui.history_file or pass --file"))
+	}
+
+	results, err := storage.ReadHistory(path)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("📭 No history at %s yet. Play some games first!\n", path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := game.VerifyChain(results); err != nil {
+		var chainErr *game.ChainError
+		if errors.As(err, &chainErr) {
+			fmt.Printf("❌ Chain broken at result %d of %d\n", chainErr.Index+1, len(results))
+		}
+		return fmt.Errorf("history %s was tampered with: %w", path, err)
+	}
+
+	players := make(map[string]bool)
+	for _, result := range results {
+		players[result.PlayerID] = true
+	}
+	fmt.Printf("🔗 Chain intact: %d results, %d player(s), in %s\n", len(results), len(players), path)
+	return nil
+}
+
+// openHistory opens the saved history and chains the player's next result
+// to their latest one. A history that cannot be opened leaves this run's
+// results unsaved, so this only prints a notice.
+func openHistory(ctx context.Context, app *CLIApp) {
+	if app.History != nil {
+		return
+	}
+
+	history, err := storage.OpenHistoryLog(app.Config.UI.HistoryFile)
+	if err != nil {
+		app.Logger.Warn("History not saved", zap.Error(err))
+		fmt.Println("⚠️ Game history could not be opened; this run's results will not be saved")
+		return
+	}
+	if err := app.Engine.ResumeChain(ctx, app.Session.PlayerID(), history.Head(app.Session.PlayerID())); err != nil {
+		app.Logger.Warn("History not saved", zap.Error(err))
+		return
+	}
+	app.History = history
+}
+
+// recordResult appends a settled result to the saved history
+func recordResult(app *CLIApp, result *game.Result) {
+	if app.History == nil {
+		return
+	}
+	if err := app.History.Append(result); err != nil {
+		app.Logger.Warn("Failed to save result to history", zap.String("result_id", result.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/config"
+)
+
+// newCompletionCommand creates the command that prints shell completion
+// scripts. It replaces Cobra's default so the help can say where each
+// shell expects the script.
+func newCompletionCommand(rootCmd *cobra.Command) *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion bash|zsh|fish|powershell",
+		Short: "Generate a shell completion script",
+		Long: `Print a script that completes coinflip's commands, flags and flag values,
+such as --choice heads or tails, room modes and saved profile names.
+
+Bash (needs the bash-completion package):
+  source <(coinflip completion bash)
+  # or, to load it in every session:
+  coinflip completion bash > /etc/bash_completion.d/coinflip
+
+Zsh (needs compinit enabled):
+  coinflip completion zsh > "${fpath[1]}/_coinflip"
+
+Fish:
+  coinflip completion fish > ~/.config/fish/completions/coinflip.fish
+
+PowerShell:
+  coinflip completion powershell | Out-String | Invoke-Expression
+  # or add that line to your $PROFILE`,
+		Example: `  coinflip completion bash > ~/.local/share/bash-completion/completions/coinflip
+  coinflip completion zsh > "${fpath[1]}/_coinflip"`,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return rootCmd.GenBashCompletionV2(out, true)
+			case "zsh":
+				return rootCmd.GenZshCompletion(out)
+			case "fish":
+				return rootCmd.GenFishCompletion(out, true)
+			default:
+				return rootCmd.GenPowerShellCompletionWithDesc(out)
+			}
+		},
+	}
+}
+
+// completeValues completes a flag with a fixed set of values
+func completeValues(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeProfiles completes --profile with the saved profile names
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		cobra.CompDebugln(fmt.Sprintf("listing profiles: %v", err), false)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
@@ -1,29 +1,119 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/config"
 )
 
 // newConfigCommand creates the config command for displaying configuration
+// and managing profiles
 func newConfigCommand(app *CLIApp) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Display current game configuration",
 		Long: `Display the current game configuration including betting limits, 
 payout ratios, and other game settings.`,
-		Example: `  coinflip config`,
+		Example: `  coinflip config
+  coinflip --profile tournament config`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return showConfiguration(app)
 		},
 	}
+
+	cmd.AddCommand(
+		newSaveProfileCommand(app),
+		newProfilesCommand(),
+	)
+	return cmd
+}
+
+// newSaveProfileCommand creates the command that captures the current
+// settings as a named profile
+func newSaveProfileCommand(app *CLIApp) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "save-profile NAME",
+		Short: "Save the current settings as a named profile",
+		Long: `Save the current settings, including any environment variable overrides, 
+as a named profile under ~/.coinflip/profiles/. Select it later with 
+--profile NAME or the COINFLIP_PROFILE environment variable.`,
+		Example: `  coinflip config save-profile local
+  COINFLIP_GAME_MIN_BET=10 coinflip config save-profile tournament
+  coinflip --profile tournament play`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return saveProfile(app, args[0], force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing profile of the same name")
+	return cmd
+}
+
+// newProfilesCommand creates the command listing the saved profiles
+func newProfilesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "profiles",
+		Short:   "List the saved configuration profiles",
+		Example: `  coinflip config profiles`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listProfiles()
+		},
+	}
+}
+
+// saveProfile writes the current settings as a named profile
+func saveProfile(app *CLIApp, name string, force bool) error {
+	path, err := config.ProfilePath(name)
+	if err != nil {
+		return invalidInput(err)
+	}
+	if _, err := os.Stat(path); err == nil && !force {
+		return invalidInput(fmt.Errorf("profile %s already exists, use --force to replace it", name))
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to check profile %s: %w", name, err)
+	}
+
+	if err := app.Config.SaveProfile(name); err != nil {
+		return fmt.Errorf("failed to save profile %s: %w", name, err)
+	}
+
+	fmt.Printf("💾 Saved profile %s to %s\n", name, path)
+	return nil
+}
+
+// listProfiles prints the saved profiles
+func listProfiles() error {
+	names, err := config.ListProfiles()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println("No profiles saved yet. Create one with: coinflip config save-profile NAME")
+		return nil
+	}
+
+	fmt.Println("📁 Profiles in " + config.ProfilesDir())
+	for _, name := range names {
+		fmt.Println("  " + name)
+	}
+	return nil
 }
 
 // showConfiguration displays the current game configuration
 func showConfiguration(app *CLIApp) error {
 	fmt.Println("⚙️  Game Configuration")
 	fmt.Println("======================")
+	if profile := app.Config.Profile(); profile != "" {
+		fmt.Printf("📁 Profile: %s (%s)\n", profile, app.Config.Path())
+	}
 
 	// Game settings
 	fmt.Println("🎯 Game Settings:")
@@ -31,6 +121,13 @@ func showConfiguration(app *CLIApp) error {
 	fmt.Printf("  Minimum bet: $%.2f\n", app.Config.Game.MinBet)
 	fmt.Printf("  Maximum bet: $%.2f\n", app.Config.Game.MaxBet)
 	fmt.Printf("  Payout ratio: %.1fx\n", app.Config.Game.PayoutRatio)
+	fmt.Printf("  Streak bonus: %.2fx per win", app.Config.Game.StreakBonus)
+	if app.Config.Game.MaxStreakMultiplier > 0 {
+		fmt.Printf(" (max %.2fx)", app.Config.Game.MaxStreakMultiplier)
+	}
+	fmt.Println()
+	fmt.Printf("  Insurance: cost %.2f, coverage %.2f of the stake\n",
+		app.Config.Game.InsuranceCost, app.Config.Game.InsuranceCoverage)
 
 	// Logging settings
 	fmt.Println("\n📝 Logging Settings:")
@@ -47,6 +144,7 @@ func showConfiguration(app *CLIApp) error {
 	fmt.Println("  • Edit configs/config.json to change settings")
 	fmt.Println("  • Use environment variables with COINFLIP_ prefix")
 	fmt.Println("  • Example: COINFLIP_GAME_MIN_BET=5.0")
+	fmt.Println("  • Save settings as a profile with: coinflip config save-profile NAME")
 
 	return nil
 }
@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+	"coinflip-game/internal/tracing"
+)
+
+// tracer traces each command run
+var tracer = tracing.Tracer("coinflip-game/cmd/cli")
+
+// Exit codes, so scripts can branch on why a command failed
+const (
+	ExitOK                  = 0
+	ExitFailure             = 1
+	ExitInvalidInput        = 2
+	ExitInsufficientBalance = 3
+	ExitNetworkFailure      = 4
+	ExitServerRejected      = 5
+)
+
+// Error output formats for --error-format
+const (
+	ErrorFormatText = "text"
+	ErrorFormatJSON = "json"
+)
+
+// exitKinds names each exit code in JSON error output
+var exitKinds = map[int]string{
+	ExitFailure:             "error",
+	ExitInvalidInput:        "invalid_input",
+	ExitInsufficientBalance: "insufficient_balance",
+	ExitNetworkFailure:      "network_failure",
+	ExitServerRejected:      "server_rejected",
+}
+
+// ExitError is a command failure with the exit code it should produce
+type ExitError struct {
+	Code int
+	// ServerCode is the server's error code for server rejections
+	ServerCode string
+	Err        error
+}
+
+// Error implements the error interface
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// invalidInput marks err as a problem with the command's arguments
+func invalidInput(err error) error {
+	return &ExitError{Code: ExitInvalidInput, Err: err}
+}
+
+// networkFailure marks err as a failure to reach or stay connected to the
+// server
+func networkFailure(err error) error {
+	return &ExitError{Code: ExitNetworkFailure, Err: err}
+}
+
+// serverRejection turns an error message from the server into an
+// ExitError. Bets refused for lack of funds get their own exit code, told
+// by the rejection's reason since the message is in the room's language.
+func serverRejection(data network.ErrorData, format string, args ...interface{}) error {
+	code := ExitServerRejected
+	if data.Rejection != nil && data.Rejection.Reason == network.RejectInsufficientBalance {
+		code = ExitInsufficientBalance
+	}
+	return &ExitError{
+		Code:       code,
+		ServerCode: data.Code,
+		Err:        fmt.Errorf(format, args...),
+	}
+}
+
+// ExitCode maps a command error to the process exit code
+func ExitCode(err error) int {
+	var exitErr *ExitError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	case errors.Is(err, game.ErrInsufficientBalance):
+		return ExitInsufficientBalance
+	case errors.Is(err, game.ErrInvalidBetAmount), errors.Is(err, game.ErrInvalidChoice):
+		return ExitInvalidInput
+	default:
+		return ExitFailure
+	}
+}
+
+// argsAcceptedAnnotation marks a command whose flags and arguments were
+// accepted, so its errors are not usage errors
+const argsAcceptedAnnotation = "coinflip/args-accepted"
+
+// acceptArgs records that cmd got past argument validation
+func acceptArgs(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[argsAcceptedAnnotation] = "true"
+}
+
+// errorReport is the JSON written to stderr with --error-format json
+type errorReport struct {
+	Error      string `json:"error"`
+	Kind       string `json:"kind"`
+	ExitCode   int    `json:"exit_code"`
+	ServerCode string `json:"server_code,omitempty"`
+}
+
+// Execute runs the root command and returns the process exit code. Errors
+// are written to stderr in the format chosen with --error-format.
+func Execute(ctx context.Context, rootCmd *cobra.Command) int {
+	// The command's span is the root of the trace its requests continue;
+	// it is named once Cobra has worked out which command ran
+	ctx, span := tracer.Start(ctx, rootCmd.Name())
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	span.SetName(cmd.CommandPath())
+	tracing.End(span, err)
+	if err == nil {
+		return ExitOK
+	}
+
+	code := ExitCode(err)
+	usageError := cmd.Annotations[argsAcceptedAnnotation] == ""
+	if usageError && code == ExitFailure {
+		code = ExitInvalidInput
+	}
+
+	format, _ := rootCmd.PersistentFlags().GetString("error-format")
+	writeError(os.Stderr, format, err, code)
+	if usageError && format != ErrorFormatJSON {
+		fmt.Fprintln(os.Stderr, cmd.UsageString())
+	}
+	return code
+}
+
+// writeError reports a failed command as text or JSON
+func writeError(w io.Writer, format string, err error, code int) {
+	if format != ErrorFormatJSON {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return
+	}
+
+	report := errorReport{
+		Error:    err.Error(),
+		Kind:     exitKinds[code],
+		ExitCode: code,
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		report.ServerCode = exitErr.ServerCode
+	}
+	json.NewEncoder(w).Encode(report)
+}
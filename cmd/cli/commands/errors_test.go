@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, ExitOK},
+		{"unclassified", errors.New("disk full"), ExitFailure},
+		{"invalid input", invalidInput(errors.New("bad flag")), ExitInvalidInput},
+		{"invalid bet amount", fmt.Errorf("bet: %w", game.ErrInvalidBetAmount), ExitInvalidInput},
+		{"invalid choice", game.ErrInvalidChoice, ExitInvalidInput},
+		{"insufficient balance", fmt.Errorf("bet: %w", game.ErrInsufficientBalance), ExitInsufficientBalance},
+		{"network failure", networkFailure(errors.New("connection refused")), ExitNetworkFailure},
+		{"wrapped network failure", fmt.Errorf("join: %w", networkFailure(errors.New("timeout"))), ExitNetworkFailure},
+		{"server rejection", serverRejection(network.ErrorData{Code: "bet_failed", Message: "betting is closed"}, "bet failed"), ExitServerRejected},
+		{"server balance rejection", serverRejection(network.ErrorData{
+			Code: "bet_failed", Message: game.ErrInsufficientBalance.Error(),
+			Rejection: &network.BetRejection{Reason: network.RejectInsufficientBalance},
+		}, "bet failed"), ExitInsufficientBalance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, ExitCode(tt.err))
+		})
+	}
+}
+
+func TestServerRejection_LocalizedRoom(t *testing.T) {
+	config := network.DefaultRoomConfig()
+	config.Locale = "es"
+	config.UpdateInterval = 0
+	room := network.NewGameRoom("r1", "Sala 1", config, nil, zaptest.NewLogger(t))
+	t.Cleanup(room.Stop)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 5*game.Dollar))
+
+	// The error a Spanish room sends for a bet past the balance
+	bet := &network.BetData{Amount: 10 * game.Dollar, Choice: game.Heads}
+	data := network.ErrorData{
+		Code:      "bet_failed",
+		Message:   network.Localize(room.Locale(), game.ErrInsufficientBalance.Error()),
+		Rejection: room.BetRejection("p1", bet, game.ErrInsufficientBalance),
+	}
+	require.NotEqual(t, game.ErrInsufficientBalance.Error(), data.Message)
+	assert.Equal(t, ExitInsufficientBalance, ExitCode(serverRejection(data, "bet failed")))
+}
+
+func TestWriteError(t *testing.T) {
+	err := serverRejection(network.ErrorData{Code: "bet_failed", Message: "betting is closed"}, "server error (%s): %s", "bet_failed", "betting is closed")
+
+	var text bytes.Buffer
+	writeError(&text, ErrorFormatText, err, ExitCode(err))
+	assert.Equal(t, "Error: server error (bet_failed): betting is closed\n", text.String())
+
+	var output bytes.Buffer
+	writeError(&output, ErrorFormatJSON, err, ExitCode(err))
+	var report errorReport
+	require.NoError(t, json.Unmarshal(output.Bytes(), &report))
+	assert.Equal(t, errorReport{
+		Error:      "server error (bet_failed): betting is closed",
+		Kind:       "server_rejected",
+		ExitCode:   ExitServerRejected,
+		ServerCode: "bet_failed",
+	}, report)
+}
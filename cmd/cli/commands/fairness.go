@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+	"coinflip-game/internal/tracing"
+)
+
+// newFairnessCommand creates the fairness command for checking stored results
+func newFairnessCommand(app *CLIApp) *cobra.Command {
+	var (
+		limit     int
+		serverURL string
+		asJSON    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fairness",
+		Short: "Check stored results for signs of an unfair coin",
+		Long: `Analyze stored results and report the observed heads/tails ratio, the longest
+streaks, a chi-square test against a fair coin and whether every bet was paid
+at the configured ratio. Results that are statistically unlikely for a fair
+coin, or payouts that don't match, are flagged as anomalies.
+
+With --server the report is produced by a multiplayer server from the results
+of every room instead of this session's local history.`,
+		Example: `  coinflip fairness
+  coinflip fairness --limit 500
+  coinflip fairness --server http://localhost:8080 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				report *game.FairnessReport
+				err    error
+			)
+			if serverURL != "" {
+				report, err = fetchFairnessReport(cmd.Context(), serverURL, limit)
+			} else {
+				report, err = localFairnessReport(cmd.Context(), app, limit)
+			}
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				return printJSON(report)
+			}
+			displayFairnessReport(report)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", network.DefaultFairnessLimit, "Maximum number of recent results to analyze")
+	cmd.Flags().StringVar(&serverURL, "server", "", "Multiplayer server HTTP URL to fetch the report from")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+
+	return cmd
+}
+
+// localFairnessReport analyzes the engine's own result history
+func localFairnessReport(ctx context.Context, app *CLIApp, limit int) (*game.FairnessReport, error) {
+	results, err := app.Engine.GetGameHistory(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game history: %w", err)
+	}
+	return game.AnalyzeFairness(results, app.Engine.GetConfig().PayoutRatio), nil
+}
+
+// fetchFairnessReport asks a multiplayer server for its fairness report
+func fetchFairnessReport(ctx context.Context, serverURL string, limit int) (*game.FairnessReport, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	endpoint := strings.TrimRight(serverURL, "/") + "/stats/fairness?" + url.Values{
+		"limit": {fmt.Sprint(limit)},
+	}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	tracing.InjectHeader(ctx, req.Header)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorData network.ErrorData
+		if err := json.NewDecoder(resp.Body).Decode(&errorData); err != nil || errorData.Message == "" {
+			return nil, fmt.Errorf("fairness request failed: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("fairness request failed: %s", errorData.Message)
+	}
+
+	var report game.FairnessReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode fairness report: %w", err)
+	}
+	return &report, nil
+}
+
+// displayFairnessReport prints a fairness report
+func displayFairnessReport(report *game.FairnessReport) {
+	if report.Flips == 0 {
+		fmt.Println("📭 No results to analyze. Play some games first!")
+		return
+	}
+
+	fmt.Printf("⚖️  Fairness Report (%d flips)\n", report.Flips)
+	fmt.Println("============================")
+	displayDistribution("Coin results", report.Outcomes)
+	fmt.Printf("Heads ratio: %.1f%%\n", report.HeadsRatio*100)
+	fmt.Printf("Longest streaks: %d heads, %d tails (expected about %.0f)\n",
+		report.LongestHeadsStreak, report.LongestTailsStreak, report.ExpectedLongestStreak)
+	fmt.Printf("Chi-square: %.3f (p = %.4f)\n", report.ChiSquare, report.PValue)
+	fmt.Printf("Payouts: %d of %d bets paid at %.2fx\n",
+		report.BetsChecked-report.PayoutMismatches, report.BetsChecked, report.PayoutRatio)
+
+	if report.Flips < game.MinFairnessSample {
+		fmt.Printf("\nℹ️  Fewer than %d flips; statistical checks are not conclusive yet\n", game.MinFairnessSample)
+	}
+
+	if report.Fair() {
+		fmt.Println("\n✅ No anomalies found")
+		return
+	}
+
+	fmt.Println("\n⚠️  Anomalies")
+	for _, anomaly := range report.Anomalies {
+		fmt.Printf("  - %s\n", anomaly)
+	}
+}
@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+// newFavoritesCommand creates the favorites command for listing, saving and
+// removing favorite bets
+func newFavoritesCommand(app *CLIApp) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "favorites",
+		Short: "List your favorite bets",
+		Long: `List the bets you saved under a name. Place one in a single step with
+coinflip bet --favorite NAME. Favorites are kept per player in the
+ui.favorites_file setting.`,
+		Example: `  coinflip favorites
+  coinflip favorites add lucky25 --amount 25 --choice tails
+  coinflip bet --favorite lucky25`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listFavorites(app)
+		},
+	}
+
+	cmd.AddCommand(
+		newAddFavoriteCommand(app),
+		newRemoveFavoriteCommand(app),
+	)
+	return cmd
+}
+
+// newAddFavoriteCommand creates the command that saves a favorite bet
+func newAddFavoriteCommand(app *CLIApp) *cobra.Command {
+	var amount, choice string
+	var insure bool
+
+	cmd := &cobra.Command{
+		Use:   "add NAME",
+		Short: "Save a bet as a favorite",
+		Long: `Save a bet under a name, replacing any favorite of the same name. Shares
+of the balance such as 10% or half are kept relative and worked out when
+the favorite is placed.`,
+		Example: `  coinflip favorites add lucky25 --amount 25 --choice tails
+  coinflip favorites add safe -a 10% -c heads --insure`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stake, err := parseBetAmount(amount)
+			if err != nil {
+				return err
+			}
+			side, err := parseChoice(choice)
+			if err != nil {
+				return err
+			}
+			return addFavorite(app, game.FavoriteBet{Name: args[0], Amount: stake, Choice: side, Insured: insure})
+		},
+	}
+
+	cmd.Flags().StringVarP(&amount, "amount", "a", "", "Bet amount in dollars, a percentage of the balance such as 10%, half or max (required)")
+	cmd.Flags().StringVarP(&choice, "choice", "c", "", "Choice: heads or tails (required)")
+	cmd.Flags().BoolVar(&insure, "insure", false, "Insure the bet when it is placed")
+	cmd.MarkFlagRequired("amount")
+	cmd.MarkFlagRequired("choice")
+	cmd.RegisterFlagCompletionFunc("choice", completeValues(string(game.Heads), string(game.Tails)))
+	cmd.RegisterFlagCompletionFunc("amount", completeValues(game.AmountHalf, game.AmountMax))
+	return cmd
+}
+
+// newRemoveFavoriteCommand creates the command that deletes a favorite bet
+func newRemoveFavoriteCommand(app *CLIApp) *cobra.Command {
+	return &cobra.Command{
+		Use:               "remove NAME",
+		Short:             "Delete a favorite bet",
+		Example:           `  coinflip favorites remove lucky25`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeFavorites(app),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			book, err := app.openFavorites()
+			if err != nil {
+				return err
+			}
+			if err := book.Remove(app.Session.PlayerID(), args[0]); err != nil {
+				return favoriteError(err)
+			}
+			fmt.Printf("🗑️ Removed favorite %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+// listFavorites prints the player's favorite bets
+func listFavorites(app *CLIApp) error {
+	book, err := app.openFavorites()
+	if err != nil {
+		return err
+	}
+
+	favorites := book.List(app.Session.PlayerID())
+	if len(favorites) == 0 {
+		fmt.Println("⭐ No favorite bets yet. Save one with: coinflip favorites add NAME -a 25 -c tails")
+		return nil
+	}
+
+	fmt.Println("⭐ Favorite Bets")
+	fmt.Println("================")
+	for _, favorite := range favorites {
+		fmt.Printf("%-*s  %s\n", game.MaxFavoriteNameLength/2, favorite.Name, favorite.Describe())
+	}
+	return nil
+}
+
+// addFavorite saves a favorite bet
+func addFavorite(app *CLIApp, favorite game.FavoriteBet) error {
+	book, err := app.openFavorites()
+	if err != nil {
+		return err
+	}
+	if err := book.Save(app.Session.PlayerID(), favorite); err != nil {
+		return favoriteError(err)
+	}
+	fmt.Printf("⭐ Saved %s: %s\n", favorite.Name, favorite.Describe())
+	return nil
+}
+
+// lookupFavorite returns the player's favorite bet with the name
+func lookupFavorite(app *CLIApp, name string) (game.FavoriteBet, error) {
+	book, err := app.openFavorites()
+	if err != nil {
+		return game.FavoriteBet{}, err
+	}
+	favorite, err := book.Get(app.Session.PlayerID(), name)
+	if err != nil {
+		return game.FavoriteBet{}, favoriteError(err)
+	}
+	return favorite, nil
+}
+
+// openFavorites opens the favorite bets the first time they are needed
+func (app *CLIApp) openFavorites() (*storage.FavoriteBook, error) {
+	if app.Favorites != nil {
+		return app.Favorites, nil
+	}
+
+	book, err := storage.OpenFavoriteBook(app.Config.UI.FavoritesFile)
+	if err != nil {
+		return nil, err
+	}
+	app.Favorites = book
+	return book, nil
+}
+
+// favoriteError marks mistakes in naming or describing a favorite as
+// invalid input
+func favoriteError(err error) error {
+	if errors.Is(err, game.ErrFavoriteNotFound) || errors.Is(err, game.ErrInvalidFavorite) ||
+		errors.Is(err, game.ErrTooManyFavorites) {
+		return invalidInput(err)
+	}
+	return err
+}
+
+// completeFavorites completes the names of the player's favorite bets
+func completeFavorites(app *CLIApp) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		book, err := app.openFavorites()
+		if err != nil {
+			cobra.CompDebugln(fmt.Sprintf("opening favorites: %v", err), false)
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var names []string
+		for _, favorite := range book.List(app.Session.PlayerID()) {
+			names = append(names, favorite.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
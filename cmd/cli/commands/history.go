@@ -12,7 +12,10 @@ import (
 
 // newHistoryCommand creates the history command for viewing game results
 func newHistoryCommand(app *CLIApp) *cobra.Command {
-	var limit int
+	var (
+		limit   int
+		summary bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "history",
@@ -21,13 +24,18 @@ func newHistoryCommand(app *CLIApp) *cobra.Command {
 bet details, and winnings. Results are shown in reverse chronological order 
 (most recent first).`,
 		Example: `  coinflip history
-  coinflip history --limit 5`,
+  coinflip history --limit 5
+  coinflip history --summary --limit 100`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if summary {
+				return showHistorySummary(cmd.Context(), app, limit)
+			}
 			return showGameHistory(cmd.Context(), app, limit)
 		},
 	}
 
 	cmd.Flags().IntVarP(&limit, "limit", "l", 10, "Maximum number of results to show")
+	cmd.Flags().BoolVar(&summary, "summary", false, "Summarize results and heads/tails distributions instead of listing games")
 
 	return cmd
 }
@@ -72,21 +80,24 @@ func displayHistoryEntry(index int, result *game.Result) {
 
 	// Bet details if available
 	if result.Bet != nil {
-		fmt.Printf("💸 Bet: $%.2f on %s\n", result.Bet.Amount, strings.ToUpper(string(result.Bet.Choice)))
+		fmt.Printf("💸 Bet: %s on %s\n", result.Bet.Amount.Format(), strings.ToUpper(string(result.Bet.Choice)))
 	}
 
 	// Outcome
 	if result.Won {
-		fmt.Printf("✅ Won: $%.2f", result.Payout)
+		fmt.Printf("✅ Won: %s", result.Payout.Format())
 		if result.Bet != nil {
 			profit := result.Payout - result.Bet.Amount
-			fmt.Printf(" (profit: +$%.2f)", profit)
+			fmt.Printf(" (profit: %s)", profit.FormatSigned())
+		}
+		if result.Multiplier > 0 {
+			fmt.Printf(" 🔥 %.2fx streak bonus", result.Multiplier)
 		}
 		fmt.Println()
 	} else {
 		fmt.Printf("❌ Lost")
 		if result.Bet != nil {
-			fmt.Printf(": -$%.2f", result.Bet.Amount)
+			fmt.Printf(": -%s", result.Bet.Amount.Format())
 		}
 		fmt.Println()
 	}
@@ -96,3 +107,52 @@ func displayHistoryEntry(index int, result *game.Result) {
 		fmt.Printf("🔍 Seed: %s\n", result.Seed[:16]+"...") // Show first 16 chars
 	}
 }
+
+// showHistorySummary aggregates recent results across all players alongside
+// the current player's lifetime statistics
+func showHistorySummary(ctx context.Context, app *CLIApp, limit int) error {
+	results, err := app.Engine.GetGameHistory(ctx, limit)
+	if err != nil {
+		return fmt.Errorf("failed to get game history: %w", err)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("📭 No game history found. Play some games first!")
+		return nil
+	}
+
+	summary := game.SummarizeResults(results)
+
+	fmt.Printf("📊 History Summary (last %d games)\n", len(results))
+	fmt.Println("===================================")
+	fmt.Printf("Bets settled: %d\n", summary.GamesPlayed)
+	fmt.Printf("Bets won: %d (%.1f%%)\n", summary.GamesWon, summary.WinRate)
+	fmt.Printf("Total wagered: %s\n", summary.TotalWagered.Format())
+	fmt.Printf("Net profit: %s\n", summary.NetProfit.Format())
+	displayDistribution("Coin results", summary.Outcomes)
+	displayDistribution("Choices", summary.Choices)
+
+	player, err := app.Session.Player(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get player: %w", err)
+	}
+
+	fmt.Printf("\n👤 Your Lifetime Statistics\n")
+	fmt.Println("===========================")
+	displayStats(&player.Stats)
+
+	return nil
+}
+
+// displayDistribution prints a heads/tails distribution as a bar chart
+func displayDistribution(title string, dist game.Distribution) {
+	const width = 20
+
+	fmt.Printf("%s:\n", title)
+	for _, side := range []game.Side{game.Heads, game.Tails} {
+		percent := dist.Percent(side)
+		filled := int(percent/100*width + 0.5)
+		fmt.Printf("  %-5s %s%s %4d (%5.1f%%)\n", side,
+			strings.Repeat("█", filled), strings.Repeat("░", width-filled), dist.Count(side), percent)
+	}
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+func TestParseStore(t *testing.T) {
+	tests := []struct {
+		spec string
+		want migrationStore
+	}{
+		{"backup/coinflip.json", migrationStore{path: "backup/coinflip.json", kind: storeExport}},
+		{"file://data/history.jsonl", migrationStore{path: "data/history.jsonl", kind: storeHistory}},
+		{"archive/results.jsonl.gz", migrationStore{path: "archive/results.jsonl.gz", kind: storeArchive}},
+		{"sqlite://data/coinflip.db", migrationStore{path: "sqlite://data/coinflip.db", kind: storeDatabase}},
+		{"mysql://user:pw@tcp(localhost:3306)/coinflip", migrationStore{path: "mysql://user:pw@tcp(localhost:3306)/coinflip", kind: storeDatabase}},
+	}
+	for _, tt := range tests {
+		store, err := parseStore(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, store)
+	}
+
+	_, err := parseStore("postgres://localhost/coinflip")
+	assert.Error(t, err)
+	_, err = parseStore("coinflip.db")
+	assert.Error(t, err)
+}
+
+func TestRunMigrate_Database(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	from := filepath.Join(dir, "coinflip.json")
+	database := "sqlite://" + filepath.Join(dir, "coinflip.db")
+	to := filepath.Join(dir, "copy.json")
+
+	require.NoError(t, storage.WriteExport(from, &storage.Export{
+		Version: storage.ExportVersion,
+		Results: []*game.Result{{ID: "r1", PlayerID: "alice", Side: game.Heads, Hash: "h1"}},
+		Players: []*game.Player{{ID: "alice", Balance: 42 * game.Dollar}},
+	}))
+
+	// A missing database is not taken for an empty one
+	err := runMigrate(ctx, database, to, false)
+	assert.Equal(t, ExitInvalidInput, ExitCode(err))
+
+	require.NoError(t, runMigrate(ctx, from, database, false))
+	require.NoError(t, runMigrate(ctx, from, database, false), "migrating twice merges")
+	require.NoError(t, runMigrate(ctx, database, to, false))
+
+	export, err := storage.ReadExport(to)
+	require.NoError(t, err)
+	require.Len(t, export.Results, 1)
+	assert.Equal(t, "h1", export.Results[0].Hash)
+	require.Len(t, export.Players, 1)
+	assert.Equal(t, 42*game.Dollar, export.Players[0].Balance)
+}
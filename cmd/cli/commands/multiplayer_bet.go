@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// multiplayerBetOptions holds the flags for submitting a bet to a room
+type multiplayerBetOptions struct {
+	RoomID     string
+	ServerURL  string
+	PlayerName string
+	Balance    float64
+	Insure     bool
+	Practice   bool
+	Timeout    time.Duration
+}
+
+// multiplayerBetResult is the JSON printed once the round has been settled
+type multiplayerBetResult struct {
+	RoomID     string     `json:"room_id"`
+	RoundID    string     `json:"round_id"`
+	PlayerID   string     `json:"player_id"`
+	PlayerName string     `json:"player_name"`
+	Amount     game.Money `json:"amount"`
+	Choice     game.Side  `json:"choice"`
+	CoinResult game.Side  `json:"coin_result"`
+	Won        bool       `json:"won"`
+	Payout     game.Money `json:"payout"`
+	Insurance  game.Money `json:"insurance,omitempty"`
+	Multiplier float64    `json:"multiplier,omitempty"`
+	Boost      float64    `json:"boost,omitempty"`
+	WinStreak  int        `json:"win_streak"`
+	NewBalance game.Money `json:"new_balance"`
+	Practice   bool       `json:"practice,omitempty"`
+	FinalSeed  string     `json:"final_seed"`
+	Timestamp  time.Time  `json:"timestamp"`
+}
+
+// runMultiplayerBet connects to a server, bets once in the given room and
+// prints the settled result as JSON
+func runMultiplayerBet(ctx context.Context, app *CLIApp, amount game.BetAmount, choiceStr string, opts multiplayerBetOptions) error {
+	choice, err := parseChoice(choiceStr)
+	if err != nil {
+		return err
+	}
+	balance, err := parseAmount(opts.Balance)
+	if err != nil {
+		return err
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	// Unique per run so several scripted bots can share a room
+	playerID := fmt.Sprintf("cli_%d", time.Now().UnixNano())
+	playerName := opts.PlayerName
+	if playerName == "" {
+		playerName = playerID
+	}
+
+	clientConfig := network.DefaultClientConfig()
+	clientConfig.ServerURL = opts.ServerURL
+	clientConfig.Encoding = network.Encoding(app.Config.Multiplayer.Encoding)
+	clientConfig.EnableCompression = app.Config.Multiplayer.Compression
+	clientConfig.MaxReconnects = 0
+
+	client := network.NewNetworkClient(clientConfig, playerID, playerName, app.Logger)
+	client.SetTraceContext(ctx)
+	if err := client.Connect(); err != nil {
+		return networkFailure(err)
+	}
+	defer client.Disconnect()
+
+	if err := client.JoinRoom(opts.RoomID, balance); err != nil {
+		return networkFailure(err)
+	}
+
+	// Bets wait for a seat, which may mean waiting in line for a full
+	// room, and relative amounts for the balance the server holds for us
+	var stake, roomBalance game.Money
+	seated := false
+
+	betPlaced := false
+	placeBet := func() error {
+		if betPlaced || !seated {
+			return nil
+		}
+		stake = amount.Resolve(roomBalance, 0)
+		send := client.PlaceBet
+		if opts.Insure {
+			send = client.PlaceInsuredBet
+		}
+		if opts.Practice {
+			send = client.PlacePracticeBet
+		}
+		if err := send(stake, choice); err != nil {
+			return networkFailure(err)
+		}
+		betPlaced = true
+		return nil
+	}
+
+	events := client.GetEventChannel()
+	errs := client.GetErrorChannel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return networkFailure(fmt.Errorf("timed out waiting for round result in room %s: %w", opts.RoomID, ctx.Err()))
+
+		case err := <-errs:
+			return networkFailure(fmt.Errorf("multiplayer connection failed: %w", err))
+
+		case msg := <-events:
+			switch msg.Type {
+			case network.MsgRoomUpdate:
+				var update network.RoomUpdateData
+				if err := msg.GetData(&update); err != nil {
+					continue
+				}
+				for _, player := range update.Players {
+					if player.ID == playerID {
+						roomBalance, seated = player.Balance, true
+					}
+				}
+				if update.GameState == network.StateBetting {
+					if err := placeBet(); err != nil {
+						return err
+					}
+				}
+
+			case network.MsgBetPhase:
+				if err := placeBet(); err != nil {
+					return err
+				}
+
+			case network.MsgBetPlaced:
+				// The room's betting limits may have lowered the stake
+				var bet network.BetData
+				if msg.PlayerID == playerID && msg.GetData(&bet) == nil {
+					stake = bet.Amount
+				}
+
+			case network.MsgError:
+				var errorData network.ErrorData
+				if err := msg.GetData(&errorData); err != nil {
+					return &ExitError{Code: ExitServerRejected, Err: errors.New("server rejected the request")}
+				}
+				return serverRejection(errorData, "server error (%s): %s", errorData.Code, errorData.Message)
+
+			case network.MsgRoomClosed:
+				var closed network.RoomClosedData
+				if err := msg.GetData(&closed); err != nil || closed.RoomID != opts.RoomID {
+					continue
+				}
+				return &ExitError{Code: ExitServerRejected, Err: fmt.Errorf("room %s was closed by the server (%s)", closed.RoomID, closed.Reason)}
+
+			case network.MsgRoundCancelled:
+				if !betPlaced {
+					continue
+				}
+
+				var cancelled network.RoundCancelledData
+				if err := msg.GetData(&cancelled); err != nil {
+					return errors.New("round was cancelled by the server")
+				}
+				return fmt.Errorf("round %s was cancelled and the bet refunded: %s", cancelled.RoundID, cancelled.Reason)
+
+			case network.MsgGameResult:
+				if !betPlaced {
+					continue
+				}
+
+				var resultData network.GameResultData
+				if err := msg.GetData(&resultData); err != nil {
+					return fmt.Errorf("invalid game result: %w", err)
+				}
+
+				result, ok := findPlayerResult(&resultData, playerID)
+				if !ok {
+					return fmt.Errorf("round %s settled without a bet from %s", resultData.RoundID, playerID)
+				}
+
+				return printJSON(&multiplayerBetResult{
+					RoomID:     opts.RoomID,
+					RoundID:    resultData.RoundID,
+					PlayerID:   playerID,
+					PlayerName: playerName,
+					Amount:     stake,
+					Choice:     choice,
+					CoinResult: resultData.CoinResult,
+					Won:        result.Won,
+					Payout:     result.Payout,
+					Insurance:  result.Insurance,
+					Multiplier: result.Multiplier,
+					Boost:      result.Boost,
+					WinStreak:  result.WinStreak,
+					NewBalance: result.NewBalance,
+					Practice:   result.Practice,
+					FinalSeed:  resultData.FinalSeed,
+					Timestamp:  resultData.Timestamp,
+				})
+			}
+		}
+	}
+}
+
+// findPlayerResult looks up a player's outcome in a round result
+func findPlayerResult(data *network.GameResultData, playerID string) (network.PlayerResult, bool) {
+	for _, results := range [][]network.PlayerResult{data.Winners, data.Losers} {
+		for _, result := range results {
+			if result.PlayerID == playerID {
+				return result, true
+			}
+		}
+	}
+	return network.PlayerResult{}, false
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// notifyOptions holds the flags for notification settings
+type notifyOptions struct {
+	ServerURL string
+	PlayerID  string
+	On        bool
+	Off       bool
+	Email     string
+	Telegram  string
+	WinAbove  float64
+	Milestone float64
+	Season    bool
+	Timeout   time.Duration
+}
+
+// newNotifyCommand creates the notify command for email and Telegram
+// notifications
+func newNotifyCommand(app *CLIApp) *cobra.Command {
+	var opts notifyOptions
+
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Show or change email and Telegram notifications",
+		Long: `Show or change the notifications a multiplayer server sends you while you
+are away: a round won by at least --win-above, your balance passing each
+multiple of --milestone, and your finish when a season ends. Notifications
+go to an email address, a Telegram chat, or both, and are only sent once
+you opt in with --on. --off opts out and keeps the settings for later.
+
+A Telegram chat is a numeric chat ID, which @userinfobot tells you, or a
+public @channel the server's bot posts in. Start a chat with the server's
+bot first so it may message you. Settings left out keep their current
+value, an empty address removes it, and 0 turns a trigger off. Guests must
+register an account first.`,
+		Example: `  coinflip notify
+  coinflip notify --email alice@example.com --win-above 100 --season --on
+  coinflip notify --telegram 123456789 --milestone 5000
+  coinflip notify --off`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNotify(cmd.Context(), app, cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ServerURL, "server",
+		fmt.Sprintf("ws://%s:%d/ws", app.Config.Multiplayer.ServerHost, app.Config.Multiplayer.ServerPort),
+		"Multiplayer server WebSocket URL")
+	cmd.Flags().StringVar(&opts.PlayerID, "player", getPlayerID(), "Registered player to set notifications for")
+	cmd.Flags().BoolVar(&opts.On, "on", false, "Opt in to notifications")
+	cmd.Flags().BoolVar(&opts.Off, "off", false, "Opt out of notifications, keeping the settings")
+	cmd.Flags().StringVar(&opts.Email, "email", "", "Email address to notify")
+	cmd.Flags().StringVar(&opts.Telegram, "telegram", "", "Telegram chat ID or @channel to notify")
+	cmd.Flags().Float64Var(&opts.WinAbove, "win-above", 0, "Notify of rounds won by at least this much")
+	cmd.Flags().Float64Var(&opts.Milestone, "milestone", 0, "Notify each time the balance passes a multiple of this")
+	cmd.Flags().BoolVar(&opts.Season, "season", false, "Notify of your finish when a season ends")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "Maximum time to wait for the server")
+	cmd.MarkFlagsMutuallyExclusive("on", "off")
+
+	return cmd
+}
+
+// runNotify fetches the player's notification settings, applies the flags
+// that were given and prints the result
+func runNotify(ctx context.Context, app *CLIApp, cmd *cobra.Command, opts notifyOptions) error {
+	winAbove, err := parseAmount(opts.WinAbove)
+	if err != nil {
+		return err
+	}
+	milestone, err := parseAmount(opts.Milestone)
+	if err != nil {
+		return err
+	}
+	changed := func(names ...string) bool {
+		for _, name := range names {
+			if cmd.Flags().Changed(name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	clientConfig := network.DefaultClientConfig()
+	clientConfig.ServerURL = opts.ServerURL
+	clientConfig.Encoding = network.Encoding(app.Config.Multiplayer.Encoding)
+	clientConfig.EnableCompression = app.Config.Multiplayer.Compression
+	clientConfig.MaxReconnects = 0
+
+	client := network.NewNetworkClient(clientConfig, opts.PlayerID, opts.PlayerID, app.Logger)
+	client.SetTraceContext(ctx)
+	if err := client.Connect(); err != nil {
+		return networkFailure(err)
+	}
+	defer client.Disconnect()
+
+	if err := client.RequestNotifySettings(); err != nil {
+		return networkFailure(err)
+	}
+
+	events := client.GetEventChannel()
+	errs := client.GetErrorChannel()
+	updated := !changed("on", "off", "email", "telegram", "win-above", "milestone", "season")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return networkFailure(fmt.Errorf("timed out waiting for the server's notification settings: %w", ctx.Err()))
+
+		case err := <-errs:
+			return networkFailure(fmt.Errorf("multiplayer connection failed: %w", err))
+
+		case msg := <-events:
+			switch msg.Type {
+			case network.MsgError:
+				var errorData network.ErrorData
+				if err := msg.GetData(&errorData); err != nil {
+					return &ExitError{Code: ExitServerRejected, Err: errors.New("server rejected the notification settings")}
+				}
+				return serverRejection(errorData, "failed to set notifications: %s", errorData.Message)
+
+			case network.MsgNotifySettings:
+				var reply network.NotifySettingsData
+				if err := msg.GetData(&reply); err != nil {
+					return fmt.Errorf("invalid notification settings response: %w", err)
+				}
+				if reply.Settings == nil {
+					reply.Settings = &game.NotifySettings{}
+				}
+				if updated {
+					printNotifySettings(opts.PlayerID, &reply)
+					return nil
+				}
+
+				settings := *reply.Settings
+				if changed("on", "off") {
+					settings.Enabled = opts.On
+				}
+				if changed("email") {
+					settings.Email = opts.Email
+				}
+				if changed("telegram") {
+					settings.TelegramChat = opts.Telegram
+				}
+				if changed("win-above") {
+					settings.WinAbove = winAbove
+				}
+				if changed("milestone") {
+					settings.BalanceMilestone = milestone
+				}
+				if changed("season") {
+					settings.SeasonResults = opts.Season
+				}
+				if err := settings.Validate(); err != nil {
+					return invalidInput(err)
+				}
+				if err := client.SetNotifySettings(settings); err != nil {
+					return networkFailure(err)
+				}
+				updated = true
+			}
+		}
+	}
+}
+
+// printNotifySettings shows a player's notification settings
+func printNotifySettings(playerID string, reply *network.NotifySettingsData) {
+	settings := reply.Settings
+	if settings.Enabled {
+		fmt.Printf("🔔 Notifications for %s: on\n", playerID)
+	} else {
+		fmt.Printf("🔕 Notifications for %s: off\n", playerID)
+	}
+	if settings.Email != "" {
+		fmt.Printf("📧 Email: %s\n", settings.Email)
+	}
+	if settings.TelegramChat != "" {
+		fmt.Printf("✈️ Telegram: %s\n", settings.TelegramChat)
+	}
+	if settings.WinAbove > 0 {
+		fmt.Printf("🎉 Wins of at least %s\n", settings.WinAbove.Format())
+	}
+	if settings.BalanceMilestone > 0 {
+		fmt.Printf("💰 Balance milestones every %s\n", settings.BalanceMilestone.Format())
+	}
+	if settings.SeasonResults {
+		fmt.Println("🏆 Season results")
+	}
+	if len(reply.Channels) == 0 {
+		fmt.Println("⚠️ This server does not send notifications")
+	} else {
+		fmt.Printf("📡 The server sends: %s\n", strings.Join(reply.Channels, ", "))
+	}
+}
@@ -1,11 +1,8 @@
 package commands
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"os"
-	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -30,51 +27,59 @@ view your balance, and play continuously until you choose to quit.`,
 
 // runInteractiveGame runs the main interactive game loop
 func runInteractiveGame(ctx context.Context, app *CLIApp) error {
-	playerID := getPlayerID()
-	scanner := bufio.NewScanner(os.Stdin)
+	playerID := app.Session.PlayerID()
+	prompt := newPrompter()
+
+	syncBankroll(ctx, app)
+	defer syncBankroll(ctx, app)
+	openHistory(ctx, app)
 
 	// Get or create player
-	player, err := app.Engine.GetPlayer(ctx, playerID)
+	player, err := app.Session.Player(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get player: %w", err)
 	}
 
 	fmt.Println("🪙 Welcome to Coin Flip!")
 	fmt.Println("========================")
-	fmt.Printf("Starting balance: $%.2f\n", player.Balance)
+	fmt.Printf("Starting balance: %s\n", player.Balance.Format())
 	fmt.Printf("Minimum bet: $%.2f, Maximum bet: $%.2f\n", app.Config.Game.MinBet, app.Config.Game.MaxBet)
 	fmt.Printf("Payout ratio: %.1fx\n", app.Config.Game.PayoutRatio)
+	displayStreakBonus(app.Engine.GetConfig())
+	displayInsurance(app.Engine.GetConfig())
 	fmt.Println()
 
+	// The last bet is offered again as the default, a share of the balance
+	// staying a share
+	lastAmount := game.BetAmount{Fixed: app.Engine.GetConfig().MinBet}
+	lastChoice := game.Heads
+
 	for {
 		// Check if player can continue playing
-		player, err = app.Engine.GetPlayer(ctx, playerID)
+		player, err = app.Session.Player(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get player: %w", err)
 		}
 
-		if player.Balance < app.Config.Game.MinBet {
-			fmt.Printf("🚫 Game Over! Your balance ($%.2f) is below the minimum bet ($%.2f)\n",
-				player.Balance, app.Config.Game.MinBet)
+		if minBet := app.Engine.GetConfig().MinBet; player.Balance < minBet {
+			fmt.Printf("🚫 Game Over! Your balance (%s) is below the minimum bet (%s)\n",
+				player.Balance.Format(), minBet.Format())
 			break
 		}
 
 		// Show current status
-		fmt.Printf("💰 Current balance: $%.2f\n", player.Balance)
+		fmt.Printf("💰 Current balance: %s\n", player.Balance.Format())
 
 		// Check for active bet
-		currentBet := app.Engine.GetCurrentBet()
-		if currentBet != nil {
-			fmt.Printf("🎲 Active bet: $%.2f on %s\n", currentBet.Amount, currentBet.Choice)
-			fmt.Print("Press Enter to flip the coin, or type 'cancel' to cancel the bet: ")
-
-			if !scanner.Scan() {
+		if currentBet := app.Session.CurrentBet(); currentBet != nil {
+			fmt.Printf("🎲 Active bet: %s on %s\n", currentBet.Amount.Format(), currentBet.Choice)
+			action, err := prompt.Select("What next?", []string{"flip", "cancel"}, "flip")
+			if err != nil {
 				break
 			}
 
-			input := strings.TrimSpace(scanner.Text())
-			if strings.ToLower(input) == "cancel" {
-				if err := app.Engine.CancelCurrentBet(ctx, playerID); err != nil {
+			if action == "cancel" {
+				if err := app.Session.CancelBet(ctx); err != nil {
 					fmt.Printf("❌ Failed to cancel bet: %v\n", err)
 					continue
 				}
@@ -82,72 +87,63 @@ func runInteractiveGame(ctx context.Context, app *CLIApp) error {
 				continue
 			}
 
-			// Flip the coin
-			result, err := app.Engine.FlipCoin(ctx, playerID)
-			if err != nil {
-				fmt.Printf("❌ Failed to flip coin: %v\n", err)
-				continue
-			}
-
-			displayResult(result)
+			flipAndShow(ctx, app)
 			continue
 		}
 
 		// Prompt for new bet
-		fmt.Print("💸 Enter bet amount (or 'quit' to exit): $")
-		if !scanner.Scan() {
+		input, err := prompt.Input("💸 Bet amount in dollars, a percentage such as 10%, half or max, or 'quit' to exit", lastAmount.String(),
+			betAmountValidator(app.Engine.GetConfig(), player.Balance))
+		if err != nil {
 			break
 		}
-
-		input := strings.TrimSpace(scanner.Text())
-		if strings.ToLower(input) == "quit" || strings.ToLower(input) == "q" {
+		if isQuit(input) {
 			break
 		}
 
-		// Parse bet amount
-		amount, err := strconv.ParseFloat(input, 64)
-		if err != nil {
-			fmt.Printf("❌ Invalid amount: %v\n", err)
-			continue
-		}
+		// Validated above, so parsing cannot fail
+		parsed, _ := game.ParseBetAmount(input)
+		amount := resolveBetAmount(parsed, player.Balance, app.Engine.GetConfig().MaxBet)
 
-		// Get choice
-		fmt.Print("🪙 Choose heads (h) or tails (t): ")
-		if !scanner.Scan() {
+		side, err := prompt.Select("🪙 Heads or tails?", []string{string(game.Heads), string(game.Tails)}, string(lastChoice))
+		if err != nil {
 			break
 		}
+		choice := game.Side(side)
 
-		choiceInput := strings.ToLower(strings.TrimSpace(scanner.Text()))
-		var choice game.Side
-		switch choiceInput {
-		case "h", "heads":
-			choice = game.Heads
-		case "t", "tails":
-			choice = game.Tails
-		default:
-			fmt.Println("❌ Invalid choice. Please enter 'h' for heads or 't' for tails.")
-			continue
+		// Offer insurance when the game sells it
+		placeBet := app.Session.PlaceBet
+		if insurance := app.Engine.GetConfig().Insurance; insurance.Enabled() {
+			insure, err := prompt.Confirm(fmt.Sprintf("☂️ Insure for %s to get %s back on a loss?",
+				insurance.Premium(amount).Format(), insurance.Refund(amount).Format()), false)
+			if err != nil {
+				break
+			}
+			if insure {
+				placeBet = app.Session.PlaceInsuredBet
+			}
 		}
 
 		// Place bet
-		bet, err := app.Engine.PlaceBet(ctx, playerID, amount, choice)
+		bet, err := placeBet(ctx, amount, choice)
 		if err != nil {
 			fmt.Printf("❌ Failed to place bet: %v\n", err)
 			continue
 		}
+		lastAmount, lastChoice = parsed, choice
 
-		fmt.Printf("✅ Bet placed: $%.2f on %s\n", bet.Amount, bet.Choice)
-		fmt.Print("🎲 Press Enter to flip the coin...")
-		scanner.Scan()
+		displayBetPlaced(bet)
 
-		// Flip the coin
-		result, err := app.Engine.FlipCoin(ctx, playerID)
+		// Declining leaves the bet active, to flip or cancel next time round
+		flip, err := prompt.Confirm("🎲 Flip the coin?", true)
 		if err != nil {
-			fmt.Printf("❌ Failed to flip coin: %v\n", err)
+			break
+		}
+		if !flip {
 			continue
 		}
 
-		displayResult(result)
+		flipAndShow(ctx, app)
 		fmt.Println()
 	}
 
@@ -164,6 +160,46 @@ func runInteractiveGame(ctx context.Context, app *CLIApp) error {
 	return nil
 }
 
+// flipAndShow flips the coin for the active bet and shows the result
+func flipAndShow(ctx context.Context, app *CLIApp) {
+	result, err := app.Session.FlipCoin(ctx)
+	if err != nil {
+		fmt.Printf("❌ Failed to flip coin: %v\n", err)
+		return
+	}
+	recordResult(app, result)
+	displayResult(result)
+}
+
+// isQuit reports whether an answer asks to end the session
+func isQuit(input string) bool {
+	input = strings.ToLower(input)
+	return input == "quit" || input == "q"
+}
+
+// betAmountValidator checks a bet amount as it is typed: a whole number of
+// cents, or a share of the balance, within the game's limits that the
+// balance covers, or a request to quit
+func betAmountValidator(config game.Config, balance game.Money) func(string) error {
+	return func(input string) error {
+		if isQuit(input) {
+			return nil
+		}
+		parsed, err := game.ParseBetAmount(input)
+		if err != nil {
+			return fmt.Errorf("invalid amount: %v", err)
+		}
+		amount := parsed.Resolve(balance, config.MaxBet)
+		if amount < config.MinBet || amount > config.MaxBet {
+			return fmt.Errorf("bets must be between %s and %s", config.MinBet.Format(), config.MaxBet.Format())
+		}
+		if amount > balance {
+			return fmt.Errorf("your balance is %s", balance.Format())
+		}
+		return nil
+	}
+}
+
 // displayResult shows the result of a coin flip in a formatted way
 func displayResult(result *game.Result) {
 	coinEmoji := "🟡"
@@ -176,25 +212,73 @@ func displayResult(result *game.Result) {
 	fmt.Printf("\n🎯 Coin flip result: %s %s\n", coinEmoji, strings.ToUpper(string(result.Side)))
 
 	if result.Won {
-		fmt.Printf("🎉 You won! Payout: $%.2f\n", result.Payout)
+		fmt.Printf("🎉 You won! Payout: %s\n", result.Payout.Format())
+		if result.Multiplier > 0 {
+			fmt.Printf("🔥 Streak bonus: %.2fx payout\n", result.Multiplier)
+		}
 		if result.Bet != nil {
 			profit := result.Payout - result.Bet.Amount
-			fmt.Printf("💵 Profit: +$%.2f\n", profit)
+			fmt.Printf("💵 Profit: %s\n", profit.FormatSigned())
 		}
 	} else {
 		fmt.Printf("😞 You lost! Better luck next time.\n")
+		if result.Insurance > 0 {
+			fmt.Printf("☂️ Insurance refund: %s\n", result.Insurance.Format())
+		}
 		if result.Bet != nil {
-			fmt.Printf("💸 Loss: -$%.2f\n", result.Bet.Amount)
+			fmt.Printf("💸 Loss: -%s\n", (result.Bet.Cost() - result.Insurance).Format())
 		}
 	}
 }
 
+// displayBetPlaced confirms a placed bet and any insurance bought with it
+func displayBetPlaced(bet *game.Bet) {
+	fmt.Printf("✅ Bet placed: %s on %s\n", bet.Amount.Format(), bet.Choice)
+	if bet.Practice {
+		fmt.Println("🎯 Practice bet: your balance and stats are left alone")
+	}
+	if bet.Insured() {
+		fmt.Printf("☂️ Insured for %s, refunding %s on a loss\n", bet.Premium.Format(), bet.Coverage.Format())
+	}
+}
+
+// displayStreakBonus describes the streak payout bonus when it is enabled
+func displayStreakBonus(config game.Config) {
+	if config.StreakBonus <= 0 {
+		return
+	}
+	fmt.Printf("🔥 Streak bonus: +%.2fx payout per consecutive win", config.StreakBonus)
+	if config.MaxStreakMultiplier > 0 {
+		fmt.Printf(" (up to %.2fx)", config.MaxStreakMultiplier)
+	}
+	fmt.Println()
+}
+
+// displayInsurance describes the bet insurance on offer and how it moves a
+// bet's expected value, when it is enabled
+func displayInsurance(config game.Config) {
+	insurance := config.Insurance
+	if !insurance.Enabled() {
+		return
+	}
+	fmt.Printf("☂️ Insurance: pay %.0f%% of the stake to get %.0f%% back on a loss\n",
+		insurance.Cost*100, insurance.Coverage*100)
+	fmt.Printf("   Expected value per $1 bet: %+.3f, or %+.3f insured\n",
+		game.ExpectedValue(1, config.PayoutRatio),
+		game.ExpectedValue(1, config.PayoutRatio)+insurance.ExpectedValue(1))
+}
+
 // displayStats shows player statistics in a formatted way
 func displayStats(stats *game.Stats) {
 	fmt.Printf("Games played: %d\n", stats.GamesPlayed)
 	fmt.Printf("Games won: %d\n", stats.GamesWon)
 	fmt.Printf("Win rate: %.1f%%\n", stats.WinRate)
-	fmt.Printf("Total wagered: $%.2f\n", stats.TotalWagered)
-	fmt.Printf("Total winnings: $%.2f\n", stats.TotalWinnings)
-	fmt.Printf("Net profit: $%.2f\n", stats.NetProfit)
+	fmt.Printf("Total wagered: %s\n", stats.TotalWagered.Format())
+	fmt.Printf("Total winnings: %s\n", stats.TotalWinnings.Format())
+	fmt.Printf("Net profit: %s\n", stats.NetProfit.Format())
+	fmt.Printf("🔥 Win streak: %d (longest: %d)\n", stats.CurrentStreak, stats.LongestStreak)
+	if stats.GamesPlayed > 0 {
+		displayDistribution("Your choices", stats.Choices)
+		displayDistribution("Coin results", stats.Outcomes)
+	}
 }
@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"golang.org/x/term"
+)
+
+// errPromptClosed is returned once the player leaves a prompt, with Ctrl+C
+// on a terminal or when piped input runs out
+var errPromptClosed = errors.New("prompt closed")
+
+// prompter asks the player questions. On a terminal it shows survey prompts
+// with arrow-key selection and validation as the player types; with piped
+// input it reads one answer per line, so scripted sessions keep working.
+// Either way an empty answer takes the default and an invalid one is
+// explained and asked again.
+type prompter struct {
+	interactive bool
+	in          *bufio.Scanner
+	out         io.Writer
+}
+
+// newPrompter creates a prompter on the process's standard input and output
+func newPrompter() *prompter {
+	return &prompter{
+		interactive: term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())),
+		in:          bufio.NewScanner(os.Stdin),
+		out:         os.Stdout,
+	}
+}
+
+// Input asks for a line of text, offering def when it is not empty.
+// validate, when set, vets the answer before it is accepted.
+func (p *prompter) Input(message, def string, validate func(string) error) (string, error) {
+	if p.interactive {
+		var answer string
+		opts := []survey.AskOpt{}
+		if validate != nil {
+			opts = append(opts, survey.WithValidator(func(ans interface{}) error {
+				return validate(strings.TrimSpace(ans.(string)))
+			}))
+		}
+		err := survey.AskOne(&survey.Input{Message: message, Default: def}, &answer, opts...)
+		return strings.TrimSpace(answer), surveyError(err)
+	}
+
+	for {
+		answer, err := p.readLine(message, def)
+		if err != nil {
+			return "", err
+		}
+		if validate != nil {
+			if err := validate(answer); err != nil {
+				fmt.Fprintf(p.out, "❌ %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// Select asks the player to pick one of options, starting on def. Piped
+// input may name an option or any unambiguous start of one, such as "h"
+// for "heads".
+func (p *prompter) Select(message string, options []string, def string) (string, error) {
+	if p.interactive {
+		var answer string
+		err := survey.AskOne(&survey.Select{Message: message, Options: options, Default: def}, &answer)
+		return answer, surveyError(err)
+	}
+
+	for {
+		answer, err := p.readLine(fmt.Sprintf("%s (%s)", message, strings.Join(options, "/")), def)
+		if err != nil {
+			return "", err
+		}
+		if option, ok := matchOption(options, answer); ok {
+			return option, nil
+		}
+		fmt.Fprintf(p.out, "❌ Please choose %s\n", strings.Join(options, " or "))
+	}
+}
+
+// Confirm asks a yes or no question
+func (p *prompter) Confirm(message string, def bool) (bool, error) {
+	if p.interactive {
+		answer := def
+		err := survey.AskOne(&survey.Confirm{Message: message, Default: def}, &answer)
+		return answer, surveyError(err)
+	}
+
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		answer, err := p.readLine(fmt.Sprintf("%s (%s)", message, hint), "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "❌ Please answer yes or no")
+	}
+}
+
+// readLine prints a prompt and reads one trimmed answer, or def for an
+// empty line
+func (p *prompter) readLine(message, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", message, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", message)
+	}
+	if !p.in.Scan() {
+		if err := p.in.Err(); err != nil {
+			return "", err
+		}
+		return "", errPromptClosed
+	}
+
+	answer := strings.TrimSpace(p.in.Text())
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// matchOption finds the option an answer names, in full or by an
+// unambiguous prefix, ignoring case
+func matchOption(options []string, answer string) (string, bool) {
+	answer = strings.ToLower(answer)
+	if answer == "" {
+		return "", false
+	}
+
+	match := ""
+	for _, option := range options {
+		lower := strings.ToLower(option)
+		if lower == answer {
+			return option, true
+		}
+		if strings.HasPrefix(lower, answer) {
+			if match != "" {
+				return "", false
+			}
+			match = option
+		}
+	}
+	return match, match != ""
+}
+
+// surveyError reports Ctrl+C in a survey prompt as the player leaving
+func surveyError(err error) error {
+	if errors.Is(err, terminal.InterruptErr) || errors.Is(err, io.EOF) {
+		return errPromptClosed
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/network"
+)
+
+// redeemOptions holds the flags for redeeming a promo code
+type redeemOptions struct {
+	ServerURL string
+	PlayerID  string
+	Timeout   time.Duration
+}
+
+// newRedeemCommand creates the redeem command for promo codes
+func newRedeemCommand(app *CLIApp) *cobra.Command {
+	var opts redeemOptions
+
+	cmd := &cobra.Command{
+		Use:   "redeem CODE",
+		Short: "Redeem a promo code on a multiplayer server",
+		Long: `Redeem a promo code issued by the server administrator. The code's value is
+credited to the player's bankroll on the server and recorded in its audit
+trail. Each player can redeem a code once, and codes may expire or run out
+of uses.`,
+		Example: `  coinflip redeem WELCOME50
+  coinflip redeem WELCOME50 --player alice --server ws://game.example.com:8080/ws`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRedeem(cmd.Context(), app, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ServerURL, "server",
+		fmt.Sprintf("ws://%s:%d/ws", app.Config.Multiplayer.ServerHost, app.Config.Multiplayer.ServerPort),
+		"Multiplayer server WebSocket URL")
+	cmd.Flags().StringVar(&opts.PlayerID, "player", getPlayerID(), "Player ID to credit")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "Maximum time to wait for the server")
+
+	return cmd
+}
+
+// runRedeem connects to the server, redeems the code and prints the credit
+func runRedeem(ctx context.Context, app *CLIApp, code string, opts redeemOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	clientConfig := network.DefaultClientConfig()
+	clientConfig.ServerURL = opts.ServerURL
+	clientConfig.Encoding = network.Encoding(app.Config.Multiplayer.Encoding)
+	clientConfig.EnableCompression = app.Config.Multiplayer.Compression
+	clientConfig.MaxReconnects = 0
+
+	client := network.NewNetworkClient(clientConfig, opts.PlayerID, opts.PlayerID, app.Logger)
+	client.SetTraceContext(ctx)
+	if err := client.Connect(); err != nil {
+		return networkFailure(err)
+	}
+	defer client.Disconnect()
+
+	if err := client.RedeemCode(code); err != nil {
+		return networkFailure(err)
+	}
+
+	events := client.GetEventChannel()
+	errs := client.GetErrorChannel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return networkFailure(fmt.Errorf("timed out waiting for the server to redeem %s: %w", code, ctx.Err()))
+
+		case err := <-errs:
+			return networkFailure(fmt.Errorf("multiplayer connection failed: %w", err))
+
+		case msg := <-events:
+			switch msg.Type {
+			case network.MsgError:
+				var errorData network.ErrorData
+				if err := msg.GetData(&errorData); err != nil {
+					return &ExitError{Code: ExitServerRejected, Err: errors.New("server rejected the promo code")}
+				}
+				return serverRejection(errorData, "failed to redeem %s: %s", code, errorData.Message)
+
+			case network.MsgRedeemCode:
+				var credited network.RedeemCodeData
+				if err := msg.GetData(&credited); err != nil {
+					return fmt.Errorf("invalid redeem response: %w", err)
+				}
+
+				fmt.Printf("🎁 Redeemed %s: %s credited to %s\n", credited.Code, credited.Amount.Format(), opts.PlayerID)
+				fmt.Printf("💰 New balance: %s\n", credited.Balance.Format())
+				return nil
+			}
+		}
+	}
+}
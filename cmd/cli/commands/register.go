@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// registerOptions holds the flags for registering a guest account
+type registerOptions struct {
+	ServerURL string
+	GuestID   string
+	Timeout   time.Duration
+}
+
+// newRegisterCommand creates the register command for upgrading guests
+func newRegisterCommand(app *CLIApp) *cobra.Command {
+	var opts registerOptions
+
+	cmd := &cobra.Command{
+		Use:   "register ACCOUNT",
+		Short: "Upgrade a guest player to a registered account",
+		Long: `Upgrade a guest player on a multiplayer server to a registered account.
+The guest's balance, statistics, cosmetics and game history move to the
+account in one step, and the guest ID stops working. Account names are 1-32
+letters, digits, '_', '-' or '.', and must not already be taken.
+
+The guest must not be seated in a room while registering.`,
+		Example: `  coinflip register alice --player guest_1a2b3c4d5e6f
+  coinflip register alice --player guest_1a2b3c4d5e6f --server ws://game.example.com:8080/ws`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRegister(cmd.Context(), app, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ServerURL, "server",
+		fmt.Sprintf("ws://%s:%d/ws", app.Config.Multiplayer.ServerHost, app.Config.Multiplayer.ServerPort),
+		"Multiplayer server WebSocket URL")
+	cmd.Flags().StringVar(&opts.GuestID, "player", "", "Guest player ID to register")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "Maximum time to wait for the server")
+	cmd.MarkFlagRequired("player")
+
+	return cmd
+}
+
+// runRegister connects to the server as the guest and registers the account
+func runRegister(ctx context.Context, app *CLIApp, account string, opts registerOptions) error {
+	if !game.IsGuest(opts.GuestID) {
+		return fmt.Errorf("%s is not a guest player ID", opts.GuestID)
+	}
+	if err := game.ValidateAccount(account); err != nil {
+		return err
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	clientConfig := network.DefaultClientConfig()
+	clientConfig.ServerURL = opts.ServerURL
+	clientConfig.Encoding = network.Encoding(app.Config.Multiplayer.Encoding)
+	clientConfig.EnableCompression = app.Config.Multiplayer.Compression
+	clientConfig.MaxReconnects = 0
+
+	client := network.NewNetworkClient(clientConfig, opts.GuestID, opts.GuestID, app.Logger)
+	client.SetTraceContext(ctx)
+	if err := client.Connect(); err != nil {
+		return networkFailure(err)
+	}
+	defer client.Disconnect()
+
+	if err := client.Register(account); err != nil {
+		return networkFailure(err)
+	}
+
+	events := client.GetEventChannel()
+	errs := client.GetErrorChannel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return networkFailure(fmt.Errorf("timed out waiting for the server to register %s: %w", account, ctx.Err()))
+
+		case err := <-errs:
+			return networkFailure(fmt.Errorf("multiplayer connection failed: %w", err))
+
+		case msg := <-events:
+			switch msg.Type {
+			case network.MsgError:
+				var errorData network.ErrorData
+				if err := msg.GetData(&errorData); err != nil {
+					return &ExitError{Code: ExitServerRejected, Err: errors.New("server rejected the registration")}
+				}
+				return serverRejection(errorData, "failed to register %s: %s", account, errorData.Message)
+
+			case network.MsgRegister:
+				var registered network.RegisterData
+				if err := msg.GetData(&registered); err != nil {
+					return fmt.Errorf("invalid register response: %w", err)
+				}
+
+				fmt.Printf("👤 Registered %s as %s\n", opts.GuestID, registered.Account)
+				fmt.Printf("💰 Balance: %s\n", registered.Balance.Format())
+				if registered.Stats != nil {
+					fmt.Printf("🎲 Games played: %d\n", registered.Stats.GamesPlayed)
+				}
+				return nil
+			}
+		}
+	}
+}
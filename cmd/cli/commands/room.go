@@ -0,0 +1,273 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// roomCreateOptions holds the flags for creating a multiplayer room
+type roomCreateOptions struct {
+	ServerURL      string
+	Name           string
+	MinBet         float64
+	MaxBet         float64
+	BettingSeconds int
+	MaxPlayers     int
+	Mode           string
+	Rake           float64
+	Private        bool
+	EarlyClose     bool
+	Turbo          bool
+	Locale         string
+	IdleRounds     int
+	IdleRelease    int
+	StartingStack  float64
+	Timeout        time.Duration
+}
+
+// newRoomCommand creates the room command for managing multiplayer rooms
+func newRoomCommand(app *CLIApp) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "room",
+		Short: "Manage multiplayer rooms",
+	}
+
+	cmd.AddCommand(newRoomCreateCommand(app))
+	return cmd
+}
+
+// newRoomCreateCommand creates the command that opens a room with custom
+// settings
+func newRoomCreateCommand(app *CLIApp) *cobra.Command {
+	var opts roomCreateOptions
+
+	cmd := &cobra.Command{
+		Use:   "create ROOM_ID",
+		Short: "Create a multiplayer room with custom settings",
+		Long: `Create a room on a multiplayer server with its own settings, ready for
+players to join by ID. Settings left out take the server's defaults.
+
+Modes: classic plays plain rounds, streak pays a growing bonus for
+consecutive wins, insured offers insurance with every bet, practice
+settles every bet for practice, and parimutuel pools the stakes for the
+winners to split by stake, less the --rake the house keeps. A parimutuel
+round with stakes on one side only is refunded. duel seats two players
+whose stakes are pooled the same way, and rates each on the outcome, so it
+cannot be combined with --max-players. Private rooms are left out of the
+room list.
+With --early-close, betting ends a few seconds after every connected player
+has bet instead of running the full betting time. --turbo plays fast rounds
+of 10 seconds betting and 3 seconds of results, so it cannot be combined
+with --betting-seconds. --locale sets the language of the room's
+announcements and error messages. --idle-rounds marks players idle after that
+many rounds without a bet, so they no longer hold up the minimum players, and
+--idle-release gives their seat to the queue after that many; players are
+warned a round before each. --starting-stack makes the room a freeroll: every
+player starts with that stack instead of their bankroll, and what they win on
+it is paid into the bankroll at the server's conversion rate when they leave.
+
+A room nobody joins is removed after 30 minutes.`,
+		Example: `  coinflip room create friday --name "Friday Flips" --max-bet 50
+  coinflip room create highrollers --min-bet 25 --max-bet 500 --betting-seconds 20 --private
+  coinflip room create warmup --mode practice
+  coinflip room create pool --mode parimutuel --rake 0.03
+  coinflip room create showdown --mode duel
+  coinflip room create quick --early-close
+  coinflip room create blitz --turbo
+  coinflip room create mesa --locale es
+  coinflip room create busy --idle-rounds 3 --idle-release 5
+  coinflip room create sunday --starting-stack 500`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRoomCreate(cmd.Context(), app, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ServerURL, "server",
+		fmt.Sprintf("ws://%s:%d/ws", app.Config.Multiplayer.ServerHost, app.Config.Multiplayer.ServerPort),
+		"Multiplayer server WebSocket URL")
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Room name shown to players (default \"Room ROOM_ID\")")
+	cmd.Flags().Float64Var(&opts.MinBet, "min-bet", 0, "Minimum bet")
+	cmd.Flags().Float64Var(&opts.MaxBet, "max-bet", 0, "Maximum bet")
+	cmd.Flags().IntVar(&opts.BettingSeconds, "betting-seconds", 0, "Length of the betting phase in seconds")
+	cmd.Flags().IntVar(&opts.MaxPlayers, "max-players", 0, "Most players the room seats")
+	cmd.Flags().StringVar(&opts.Mode, "mode", string(network.ModeClassic), "Game mode: classic, streak, insured, practice, parimutuel or duel")
+	cmd.Flags().Float64Var(&opts.Rake, "rake", 0, "Share of a parimutuel or duel pool the house keeps (default 0.05)")
+	cmd.Flags().BoolVar(&opts.Private, "private", false, "Leave the room out of the room list")
+	cmd.Flags().BoolVar(&opts.EarlyClose, "early-close", false, "Close betting shortly after every connected player has bet")
+	cmd.Flags().BoolVar(&opts.Turbo, "turbo", false, "Play fast rounds with 10 second betting and 3 second results")
+	cmd.Flags().StringVar(&opts.Locale, "locale", "", "Language of the room's messages: "+strings.Join(network.SupportedLocales(), ", ")+" (default en)")
+	cmd.Flags().IntVar(&opts.IdleRounds, "idle-rounds", 0, "Rounds without a bet before a player is marked idle")
+	cmd.Flags().IntVar(&opts.IdleRelease, "idle-release", 0, "Rounds without a bet before a player's seat goes to the queue")
+	cmd.Flags().Float64Var(&opts.StartingStack, "starting-stack", 0, "Stack every player starts with instead of their bankroll, making the room a freeroll")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Second, "Maximum time to wait for the server")
+	cmd.RegisterFlagCompletionFunc("mode", completeValues(roomModeNames()...))
+	cmd.RegisterFlagCompletionFunc("locale", completeValues(network.SupportedLocales()...))
+
+	return cmd
+}
+
+// roomModeNames lists the room modes for --mode completion
+func roomModeNames() []string {
+	names := make([]string, 0, len(network.RoomModes()))
+	for _, mode := range network.RoomModes() {
+		names = append(names, string(mode))
+	}
+	return names
+}
+
+// runRoomCreate asks the server to create the room and prints the settings
+// it applied
+func runRoomCreate(ctx context.Context, app *CLIApp, roomID string, opts roomCreateOptions) error {
+	if !network.RoomMode(opts.Mode).Valid() {
+		return invalidInput(fmt.Errorf("unknown room mode %q", opts.Mode))
+	}
+	if opts.BettingSeconds < 0 || opts.MaxPlayers < 0 || opts.IdleRounds < 0 || opts.IdleRelease < 0 {
+		return invalidInput(errors.New("betting seconds, max players and idle rounds must not be negative"))
+	}
+	if opts.Turbo && opts.BettingSeconds != 0 {
+		return invalidInput(errors.New("--turbo sets its own betting time, so it cannot be used with --betting-seconds"))
+	}
+	if _, ok := network.NormalizeLocale(opts.Locale); !ok {
+		return invalidInput(fmt.Errorf("unsupported locale %q, choose from %s", opts.Locale, strings.Join(network.SupportedLocales(), ", ")))
+	}
+	if opts.MaxPlayers != 0 && network.RoomMode(opts.Mode) == network.ModeDuel {
+		return invalidInput(fmt.Errorf("--mode duel seats %d players, so it cannot be used with --max-players", network.DuelSeats))
+	}
+	if opts.Rake != 0 && !network.RoomMode(opts.Mode).Pooled() {
+		return invalidInput(errors.New("--rake only applies to --mode parimutuel or duel"))
+	}
+	if err := game.ValidateRake(opts.Rake); err != nil {
+		return invalidInput(err)
+	}
+	minBet, err := parseAmount(opts.MinBet)
+	if err != nil {
+		return err
+	}
+	maxBet, err := parseAmount(opts.MaxBet)
+	if err != nil {
+		return err
+	}
+	startingStack, err := parseAmount(opts.StartingStack)
+	if err != nil {
+		return err
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	clientConfig := network.DefaultClientConfig()
+	clientConfig.ServerURL = opts.ServerURL
+	clientConfig.Encoding = network.Encoding(app.Config.Multiplayer.Encoding)
+	clientConfig.EnableCompression = app.Config.Multiplayer.Compression
+	clientConfig.MaxReconnects = 0
+
+	playerID := fmt.Sprintf("cli_%d", time.Now().UnixNano())
+	client := network.NewNetworkClient(clientConfig, playerID, playerID, app.Logger)
+	client.SetTraceContext(ctx)
+	if err := client.Connect(); err != nil {
+		return networkFailure(err)
+	}
+	defer client.Disconnect()
+
+	settings := &network.RoomSettings{
+		MinBet:            minBet,
+		MaxBet:            maxBet,
+		BettingSeconds:    opts.BettingSeconds,
+		MaxPlayers:        opts.MaxPlayers,
+		Mode:              opts.Mode,
+		Rake:              opts.Rake,
+		Private:           opts.Private,
+		EarlyClose:        opts.EarlyClose,
+		Turbo:             opts.Turbo,
+		Locale:            opts.Locale,
+		IdleRounds:        opts.IdleRounds,
+		IdleReleaseRounds: opts.IdleRelease,
+		StartingStack:     startingStack,
+	}
+	if err := client.CreateRoom(roomID, opts.Name, settings); err != nil {
+		return networkFailure(err)
+	}
+
+	events := client.GetEventChannel()
+	errs := client.GetErrorChannel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return networkFailure(fmt.Errorf("timed out waiting for the server to create room %s: %w", roomID, ctx.Err()))
+
+		case err := <-errs:
+			return networkFailure(fmt.Errorf("multiplayer connection failed: %w", err))
+
+		case msg := <-events:
+			switch msg.Type {
+			case network.MsgError:
+				var errorData network.ErrorData
+				if err := msg.GetData(&errorData); err != nil {
+					return &ExitError{Code: ExitServerRejected, Err: errors.New("server rejected the room")}
+				}
+				return serverRejection(errorData, "failed to create room %s: %s", roomID, errorData.Message)
+
+			case network.MsgCreateRoom:
+				var created network.RoomCreateData
+				if err := msg.GetData(&created); err != nil {
+					return fmt.Errorf("invalid create room response: %w", err)
+				}
+
+				fmt.Printf("➕ Created %s (%s)\n", created.RoomName, msg.RoomID)
+				if applied := created.Settings; applied != nil {
+					mode := network.RoomMode(applied.Mode)
+					if mode == "" {
+						mode = network.ModeClassic
+					}
+					fmt.Printf("🎮 Mode: %s - %s\n", mode, mode.Description())
+					if mode.Pooled() {
+						fmt.Printf("🏦 Rake: %.0f%% of the pool\n", applied.Rake*100)
+					}
+					fmt.Printf("💰 Bets: %s to %s\n", applied.MinBet.Format(), applied.MaxBet.Format())
+					if applied.Turbo {
+						fmt.Printf("⚡ Turbo: %ds betting, %ds results\n", applied.BettingSeconds, applied.ResultSeconds)
+					} else {
+						fmt.Printf("⏱️ Betting: %ds\n", applied.BettingSeconds)
+					}
+					if applied.EarlyClose {
+						fmt.Printf("⏩ Early close: %ds after everyone has bet\n", applied.EarlyCloseSeconds)
+					}
+					fmt.Printf("👥 Players: %d to %d\n", applied.MinPlayers, applied.MaxPlayers)
+					if applied.IdleRounds > 0 {
+						fmt.Printf("💤 Idle after %d rounds without a bet\n", applied.IdleRounds)
+					}
+					if applied.IdleReleaseRounds > 0 {
+						fmt.Printf("🪑 Seat released after %d rounds without a bet\n", applied.IdleReleaseRounds)
+					}
+					if applied.StartingStack > 0 {
+						fmt.Printf("🎟️ Freeroll: %s stacks, winnings paid to the bankroll at %.0f%%\n",
+							applied.StartingStack.Format(), applied.FreerollRate*100)
+					}
+					if applied.Locale != "" {
+						fmt.Printf("🌐 Language: %s\n", applied.Locale)
+					}
+					if applied.Private {
+						fmt.Println("🔒 Private: join by room ID or invite")
+					}
+				}
+				return nil
+			}
+		}
+	}
+}
@@ -2,20 +2,37 @@
 package commands
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"coinflip-game/internal/config"
 	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+	"coinflip-game/internal/network"
 	"coinflip-game/internal/storage"
 )
 
 // CLIApp holds the application dependencies for CLI commands
 type CLIApp struct {
-	Config *config.Config
-	Engine *game.Engine
-	Logger *zap.Logger
-	Repo   *storage.MemoryRepository
+	Config  *config.Config
+	Engine  *game.Engine
+	Session *game.Session
+	Logger  *zap.Logger
+	Repo    *storage.MemoryRepository
+	Audit   *logger.AuditLogger
+
+	// Favorites holds the player's favorite bets; nil until first used
+	Favorites *storage.FavoriteBook
+
+	// History saves single-player results between runs; nil until a game
+	// is first played
+	History *storage.HistoryLog
+
+	// Bankroll shares the balance with the server wallet; nil until
+	// syncBankroll first runs with wallet sync on
+	Bankroll *network.Bankroll
 }
 
 // NewRootCommand creates the root CLI command with all subcommands
@@ -26,12 +43,14 @@ func NewRootCommand(cfg *config.Config, logger *zap.Logger) *cobra.Command {
 	engine := game.NewEngine(cfg.ToGameConfig(), repo, rng, logger)
 
 	app := &CLIApp{
-		Config: cfg,
-		Engine: engine,
-		Logger: logger,
-		Repo:   repo,
+		Config:  cfg,
+		Engine:  engine,
+		Session: engine.NewSession(getPlayerID()),
+		Logger:  logger,
+		Repo:    repo,
 	}
 
+	var errorFormat string
 	rootCmd := &cobra.Command{
 		Use:   "coinflip",
 		Short: "A coin flip betting game",
@@ -47,25 +66,112 @@ comprehensive testing, and modern development practices.`,
   # Place a specific bet
   coinflip bet --amount 10 --choice heads
 
+  # Save a favorite bet and place it in one step
+  coinflip favorites add lucky25 --amount 25 --choice tails
+  coinflip bet --favorite lucky25
+
   # Check your balance and statistics
   coinflip status
 
   # View game history
-  coinflip history`,
+  coinflip history
+
+  # Redeem a promo code on a multiplayer server
+  coinflip redeem WELCOME50
+
+  # Run the multiplayer server on another port
+  coinflip serve --port 9090
+
+  # Move your history to another machine
+  coinflip migrate --from data/history.jsonl --to backup/coinflip.json
+
+  # Keep a guest's progress under a registered account
+  coinflip register alice --player guest_1a2b3c4d5e6f
+
+  # Play with the settings saved in a profile
+  coinflip --profile tournament play
+
+  # Report failures as JSON and branch on the exit code
+  coinflip bet -a 10 -c heads --error-format json || echo "failed with $?"`,
+		// Errors and usage are reported by Execute
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if errorFormat != ErrorFormatText && errorFormat != ErrorFormatJSON {
+				return fmt.Errorf("invalid --error-format %q, must be %q or %q", errorFormat, ErrorFormatText, ErrorFormatJSON)
+			}
+			// Cobra checks required flags after this hook; check them
+			// first so a missing flag counts as a usage error
+			if err := cmd.ValidateRequiredFlags(); err != nil {
+				return err
+			}
+			if err := cmd.ValidateFlagGroups(); err != nil {
+				return err
+			}
+			acceptArgs(cmd)
+			return app.openAuditLog()
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			return app.Audit.Close()
+		},
 	}
 
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", ErrorFormatText,
+		"How to report errors on stderr: text or json")
+	rootCmd.RegisterFlagCompletionFunc("error-format", completeValues(ErrorFormatText, ErrorFormatJSON))
+	addProfileFlag(rootCmd)
+	rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+
 	// Add subcommands
 	rootCmd.AddCommand(
 		newPlayCommand(app),
 		newBetCommand(app),
 		newStatusCommand(app),
+		newStatsCommand(app),
+		newSuggestCommand(app),
+		newFavoritesCommand(app),
 		newHistoryCommand(app),
 		newConfigCommand(app),
+		newRedeemCommand(app),
+		newRegisterCommand(app),
+		newNotifyCommand(app),
+		newRoomCommand(app),
+		newWatchCommand(app),
+		newFairnessCommand(app),
+		newVerifyChainCommand(app),
+		newMigrateCommand(app),
+		newServeCommand(app),
+		newCompletionCommand(rootCmd),
 	)
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	return rootCmd
 }
 
+// openAuditLog starts the gameplay audit trail if one is configured
+func (app *CLIApp) openAuditLog() error {
+	if app.Config.Logging.AuditFile == "" || app.Audit != nil {
+		return nil
+	}
+
+	audit, err := logger.NewAuditLogger(app.Config.Logging.AuditFile)
+	if err != nil {
+		return err
+	}
+
+	app.Audit = audit
+	app.Engine.SetAuditLogger(audit)
+	return nil
+}
+
+// addProfileFlag registers --profile. The entry points load the profile
+// before the command line is parsed (see config.ProfileFromArgs), so the
+// flag only needs to be accepted here.
+func addProfileFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("profile", "",
+		"Configuration profile to use, from ~/.coinflip/profiles (or $"+config.ProfileEnv+")")
+}
+
 // getPlayerID returns a default player ID for single-player CLI mode
 func getPlayerID() string {
 	return "cli_player"
@@ -0,0 +1,273 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/config"
+	"coinflip-game/internal/logger"
+	"coinflip-game/internal/network"
+	"coinflip-game/internal/notify"
+	"coinflip-game/internal/storage"
+)
+
+// durationFlag binds a duration flag to a whole-unit multiplayer setting
+type durationFlag struct {
+	name  string
+	usage string
+	unit  time.Duration
+	field *int
+	value time.Duration
+}
+
+// NewServeCommand creates a standalone serve command, for the server
+// binary, that runs the multiplayer server from cfg
+func NewServeCommand(cfg *config.Config, logger *zap.Logger) *cobra.Command {
+	cmd := newServeCommand(&CLIApp{Config: cfg, Logger: logger})
+	cmd.Use = "coinflip-server"
+	addProfileFlag(cmd)
+	return cmd
+}
+
+// newServeCommand creates the serve command for running the multiplayer
+// server. Flags override the configuration file and environment.
+func newServeCommand(app *CLIApp) *cobra.Command {
+	m := &app.Config.Multiplayer
+
+	durations := []*durationFlag{
+		{name: "read-timeout", usage: "Maximum time to read a request", unit: time.Second, field: &m.ReadTimeoutSeconds},
+		{name: "write-timeout", usage: "Maximum time to write a message to a client", unit: time.Second, field: &m.WriteTimeoutSeconds},
+		{name: "ping-period", usage: "How often to ping clients to keep connections alive", unit: time.Second, field: &m.PingPeriodSeconds},
+		{name: "pong-wait", usage: "How long a client may go without answering a ping", unit: time.Second, field: &m.PongWaitSeconds},
+		{name: "latency-interval", usage: "How often to ping clients to measure latency", unit: time.Second, field: &m.LatencyIntervalSeconds},
+		{name: "cleanup-interval", usage: "How often to remove empty rooms", unit: time.Second, field: &m.CleanupIntervalSeconds},
+		{name: "snapshot-interval", usage: "How often to save room snapshots", unit: time.Second, field: &m.SnapshotIntervalSeconds},
+		{name: "max-latency-grace", usage: "Longest betting is held open for slow connections", unit: time.Millisecond, field: &m.MaxLatencyGraceMs},
+		{name: "update-interval", usage: "How long rooms gather player changes into one update", unit: time.Millisecond, field: &m.UpdateIntervalMs},
+		{name: "countdown-interval", usage: "How often rooms send the betting countdown", unit: time.Second, field: &m.CountdownIntervalSeconds},
+		{name: "early-close-delay", usage: "How long betting stays open after every player has bet, with --early-close", unit: time.Second, field: &m.EarlyCloseSeconds},
+		{name: "busy-retry-after", usage: "How long clients turned away as busy are asked to wait", unit: time.Second, field: &m.BusyRetryAfterSeconds},
+		{name: "stored-ttl", usage: "How long players and results are kept in memory without use; players with a wallet or other state to lose are kept; 0 keeps them", unit: time.Hour, field: &m.StoredTTLHours},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the multiplayer server",
+		Long: `Run the multiplayer server until interrupted. One listener serves the
+WebSocket endpoint, the REST API, Prometheus metrics and the admin dashboard;
+each can be turned off, for example --admin=false, and /health is always on.
+
+Every setting comes from the multiplayer section of the configuration file,
+can be overridden with COINFLIP_MULTIPLAYER_* environment variables, and with
+the flags below, which take precedence over both. Durations take units, for
+example 30s or 2m; settings stored in whole seconds are rounded down.`,
+		Example: `  coinflip serve
+  coinflip serve --port 9090 --max-rooms 20
+  coinflip serve --ping-period 20s --pong-wait 30s --max-message-size 8192
+  coinflip serve --metrics=false --admin=false
+
+  # The same through the environment
+  COINFLIP_MULTIPLAYER_PING_PERIOD_SECONDS=20 coinflip serve`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, flag := range durations {
+				if cmd.Flags().Changed(flag.name) {
+					*flag.field = int(flag.value / flag.unit)
+				}
+			}
+			if err := app.Config.Validate(); err != nil {
+				return invalidInput(err)
+			}
+			return runServe(cmd.Context(), app)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&m.ServerHost, "host", m.ServerHost, "Address to listen on")
+	flags.IntVar(&m.ServerPort, "port", m.ServerPort, "Port to listen on")
+	flags.IntVar(&m.MaxRooms, "max-rooms", m.MaxRooms, "Maximum number of rooms")
+	flags.IntVar(&m.MaxPlayers, "max-players", m.MaxPlayers, "Maximum players per room")
+	flags.IntVar(&m.MaxConnections, "max-connections", m.MaxConnections, "Maximum open client connections; 0 for no cap")
+	flags.IntVar(&m.MaxStoredResults, "max-stored-results", m.MaxStoredResults, "Most game results kept in memory; 0 for no cap")
+	flags.IntVar(&m.MaxStoredPlayers, "max-stored-players", m.MaxStoredPlayers, "Most players kept in memory; players with a wallet or other state to lose are never evicted; 0 for no cap")
+	flags.IntVar(&m.MaxMessageSize, "max-message-size", m.MaxMessageSize, "Largest message accepted from a client, in bytes")
+	flags.BoolVar(&m.Compression, "compression", m.Compression, "Negotiate permessage-deflate compression")
+	flags.StringVar(&m.SnapshotFile, "snapshot-file", m.SnapshotFile, "File to persist rooms to; empty keeps them in memory")
+	flags.StringVar(&m.RoundsDatabase, "rounds-database", m.RoundsDatabase, "Database to store settled rounds and promo codes in, as sqlite://path or mysql://dsn; empty keeps them in memory")
+	flags.Float64Var(&m.MaxPot, "max-pot", m.MaxPot, "Most a room's round may hold in stakes, in dollars; 0 for no limit")
+	flags.Float64Var(&m.MaxRoundPayout, "max-round-payout", m.MaxRoundPayout, "Most a room's round may pay out, in dollars; 0 for no limit")
+	flags.Float64Var(&m.MaxLiability, "max-liability", m.MaxLiability, "Most every room's open round may pay out together, in dollars; 0 for no limit")
+	flags.Float64Var(&m.MaxOfflineWinnings, "max-offline-winnings", m.MaxOfflineWinnings, "Most offline play may add to a wallet in one day, in dollars; 0 for no limit")
+	flags.Float64Var(&m.MaxRoomBet, "max-room-bet", m.MaxRoomBet, "Largest maximum bet a room's settings may choose, in dollars; 0 for no cap")
+	flags.Float64Var(&m.MaxPayoutRatio, "max-payout-ratio", m.MaxPayoutRatio, "Largest payout ratio a room's settings may choose; 0 for no cap")
+	flags.Float64Var(&m.MaxStreakBonus, "max-streak-bonus", m.MaxStreakBonus, "Largest streak bonus a room's settings may choose; 0 for no cap")
+	flags.Float64Var(&m.MaxRoomStreakMultiplier, "max-room-streak-multiplier", m.MaxRoomStreakMultiplier, "Largest streak multiplier a room's settings may choose; 0 for no cap")
+	flags.Float64Var(&m.MinInsuranceCost, "min-insurance-cost", m.MinInsuranceCost, "Smallest insurance cost a room's settings may choose, as a share of the stake")
+	flags.Float64Var(&m.MaxInsuranceCoverage, "max-insurance-coverage", m.MaxInsuranceCoverage, "Largest insurance coverage a room's settings may choose, as a share of the stake; 0 for no cap")
+	flags.BoolVar(&m.ScaleBets, "scale-bets", m.ScaleBets, "Lower bets past a betting limit to fit instead of refusing them")
+	flags.BoolVar(&m.EarlyClose, "early-close", m.EarlyClose, "Close betting shortly after every connected player has bet")
+	flags.BoolVar(&m.EnableWebSocket, "websocket", m.EnableWebSocket, "Serve the WebSocket endpoint players connect to")
+	flags.BoolVar(&m.EnableREST, "rest", m.EnableREST, "Serve the read-only REST API")
+	flags.BoolVar(&m.EnableMetrics, "metrics", m.EnableMetrics, "Serve Prometheus metrics at /metrics")
+	flags.BoolVar(&m.EnableAdmin, "admin", m.EnableAdmin, "Serve the admin dashboard and endpoints")
+	for _, flag := range durations {
+		flags.DurationVar(&flag.value, flag.name, time.Duration(*flag.field)*flag.unit, flag.usage)
+	}
+
+	return cmd
+}
+
+// runServe starts the server and blocks until it fails or the process is
+// interrupted
+func runServe(ctx context.Context, app *CLIApp) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg := app.Config
+	serverConfig := cfg.ToServerConfig()
+	if err := serverConfig.RoomDefaults.Validate(); err != nil {
+		return invalidInput(fmt.Errorf("invalid multiplayer configuration: %w", err))
+	}
+
+	// Open the gameplay audit trail if configured
+	serverConfig.Audit = app.Audit
+	if serverConfig.Audit == nil && cfg.Logging.AuditFile != "" {
+		audit, err := logger.NewAuditLogger(cfg.Logging.AuditFile)
+		if err != nil {
+			return err
+		}
+		defer audit.Close()
+		serverConfig.Audit = audit
+	}
+	if serverConfig.Audit != nil {
+		app.Logger.Info("Audit logging enabled", zap.String("file", cfg.Logging.AuditFile))
+	}
+
+	// Store settled rounds and promo codes in a database if configured
+	if cfg.Multiplayer.RoundsDatabase != "" {
+		rounds, err := storage.OpenSQLRoundRepository(ctx, cfg.Multiplayer.RoundsDatabase)
+		if err != nil {
+			return err
+		}
+		defer rounds.Close()
+		serverConfig.Rounds = rounds
+		promos, err := storage.OpenSQLPromoRepository(ctx, cfg.Multiplayer.RoundsDatabase)
+		if err != nil {
+			return err
+		}
+		defer promos.Close()
+		serverConfig.Promos = promos
+		scheme, _, _ := strings.Cut(cfg.Multiplayer.RoundsDatabase, "://")
+		app.Logger.Info("Round database enabled", zap.String("database", scheme))
+	}
+
+	// Export completed rounds for analytics if configured
+	if cfg.Export.Enabled {
+		exporter, err := network.NewRoundExporter(cfg.ToExportConfig())
+		if err != nil {
+			return err
+		}
+		defer exporter.Close()
+		serverConfig.Export = exporter
+		app.Logger.Info("Round export enabled",
+			zap.String("path", cfg.Export.Path),
+			zap.String("format", cfg.Export.Format),
+			zap.Int("rotate_hours", cfg.Export.RotateHours),
+			zap.Int("rotate_mb", cfg.Export.RotateMB),
+		)
+	}
+
+	// Send the notifications players opt into if configured
+	if cfg.Notify.Enabled {
+		notifier, err := notify.New(cfg.ToNotifyConfig(), app.Logger)
+		if err != nil {
+			return invalidInput(fmt.Errorf("invalid notification configuration: %w", err))
+		}
+		defer notifier.Close()
+		serverConfig.Notifier = notifier
+		app.Logger.Info("Notifications enabled",
+			zap.Bool("telegram", cfg.Notify.TelegramToken != ""),
+			zap.String("smtp_host", cfg.Notify.SMTPHost),
+		)
+	}
+
+	server := network.NewServer(serverConfig, app.Logger)
+
+	// Bring back the rooms saved before the last shutdown
+	if serverConfig.SnapshotFile != "" {
+		restored, err := server.RestoreSnapshot(serverConfig.SnapshotFile)
+		if err != nil {
+			return fmt.Errorf("failed to restore rooms: %w", err)
+		}
+		app.Logger.Info("Room snapshots enabled",
+			zap.String("file", serverConfig.SnapshotFile),
+			zap.Int("restored_rooms", restored),
+		)
+	}
+
+	// Enable result archival if configured
+	if cfg.Archive.Enabled {
+		archiver := storage.NewArchiver(server.Results(), storage.ArchiveConfig{
+			Directory:    cfg.Archive.Directory,
+			ArchiveAfter: time.Duration(cfg.Archive.ArchiveAfterDays) * 24 * time.Hour,
+			Retention:    time.Duration(cfg.Archive.RetentionDays) * 24 * time.Hour,
+		})
+		server.SetArchiver(archiver, time.Duration(cfg.Archive.IntervalMinutes)*time.Minute)
+		app.Logger.Info("Result archival enabled",
+			zap.String("directory", cfg.Archive.Directory),
+			zap.Int("archive_after_days", cfg.Archive.ArchiveAfterDays),
+			zap.Int("retention_days", cfg.Archive.RetentionDays),
+		)
+	}
+
+	// Enable balance snapshots if configured
+	if cfg.Balances.Enabled {
+		snapshotter := storage.NewBalanceSnapshotter(server.Results(), storage.BalanceSnapshotConfig{
+			Directory: cfg.Balances.Directory,
+			Retention: time.Duration(cfg.Balances.RetentionDays) * 24 * time.Hour,
+		})
+		server.SetBalanceSnapshotter(snapshotter, time.Duration(cfg.Balances.IntervalMinutes)*time.Minute)
+		app.Logger.Info("Balance snapshots enabled",
+			zap.String("directory", cfg.Balances.Directory),
+			zap.Int("interval_minutes", cfg.Balances.IntervalMinutes),
+			zap.Int("retention_days", cfg.Balances.RetentionDays),
+		)
+	}
+
+	app.Logger.Info("Starting multiplayer coin flip server",
+		zap.String("host", serverConfig.Host),
+		zap.Int("port", serverConfig.Port),
+		zap.Int("max_rooms", serverConfig.MaxRooms),
+		zap.Int("max_players_per_room", serverConfig.MaxClientsRoom),
+		zap.Int("min_players_per_room", serverConfig.RoomDefaults.MinPlayers),
+		zap.Duration("betting_duration", serverConfig.RoomDefaults.BettingDuration),
+		zap.Duration("ping_period", serverConfig.PingPeriod),
+		zap.Duration("pong_wait", serverConfig.PongWait),
+		zap.Int64("max_message_size", serverConfig.MaxMessageSize),
+	)
+
+	failed := make(chan error, 1)
+	go func() {
+		failed <- server.Start()
+	}()
+
+	select {
+	case err := <-failed:
+		return networkFailure(fmt.Errorf("server failed: %w", err))
+	case <-ctx.Done():
+		app.Logger.Info("Shutting down server...")
+		server.Stop()
+		return nil
+	}
+}
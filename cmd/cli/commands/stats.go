@@ -0,0 +1,260 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/game"
+)
+
+// statsOptions are the flags of the stats command
+type statsOptions struct {
+	Chart  bool
+	Limit  int
+	Window int
+	Width  int
+}
+
+// Chart sizes
+const (
+	minChartWidth = 10
+	chartHeight   = 8
+	streakHeight  = 4
+)
+
+// newStatsCommand creates the stats command for the player's statistics
+// and, with --chart, terminal charts of their recent bets
+func newStatsCommand(app *CLIApp) *cobra.Command {
+	opts := statsOptions{Limit: 200, Window: game.DefaultTrendWindow, Width: 60}
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Display your statistics and charts of your recent bets",
+		Long: `Display the current player's statistics. With --chart, the player's recent
+bets are drawn as terminal charts: their balance over time, their win rate
+averaged over a rolling window of bets, their winning and losing streaks and
+how the coin landed against the sides they backed.
+
+Balances are worked back from the current balance through each bet's
+winnings and losses, so deposits and promo credits between bets are not
+shown. Practice bets are left out.`,
+		Example: `  coinflip stats
+  coinflip stats --chart
+  coinflip stats --chart --limit 500 --window 50 --width 80`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case opts.Limit <= 0:
+				return invalidInput(errors.New("--limit must be positive"))
+			case opts.Window <= 0:
+				return invalidInput(errors.New("--window must be positive"))
+			case opts.Width < minChartWidth:
+				return invalidInput(fmt.Errorf("--width must be at least %d", minChartWidth))
+			}
+			return showStats(cmd.Context(), app, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Chart, "chart", false, "Chart balance, win rate, streaks and heads/tails distribution")
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", opts.Limit, "Number of recent bets to chart")
+	cmd.Flags().IntVar(&opts.Window, "window", opts.Window, "Bets the rolling win rate averages over")
+	cmd.Flags().IntVar(&opts.Width, "width", opts.Width, "Chart width in columns")
+
+	return cmd
+}
+
+// showStats displays the player's statistics, then charts their recent
+// bets when asked to
+func showStats(ctx context.Context, app *CLIApp, opts statsOptions) error {
+	syncBankroll(ctx, app)
+
+	player, err := app.Session.Player(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get player: %w", err)
+	}
+
+	fmt.Println("📊 Statistics")
+	fmt.Println("=============")
+	fmt.Printf("💰 Balance: %s\n", player.Balance.Format())
+	displayStats(&player.Stats)
+
+	if !opts.Chart {
+		return nil
+	}
+
+	page, err := app.Engine.QueryHistory(ctx, game.ResultQuery{
+		PlayerID: player.ID,
+		Limit:    opts.Limit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get game history: %w", err)
+	}
+
+	trend := game.NewTrend(page.Results, player.Balance, opts.Window)
+	fmt.Println()
+	if len(trend.Balances) == 0 {
+		fmt.Println("📭 No bets to chart yet. Play some games first!")
+		return nil
+	}
+	displayTrend(trend, opts)
+	return nil
+}
+
+// displayTrend draws the charts of a player's trend
+func displayTrend(trend game.Trend, opts statsOptions) {
+	bets := len(trend.Balances)
+	title := fmt.Sprintf("📈 Charts (last %d bets)", bets)
+	fmt.Println(title)
+	// The emoji takes two columns
+	fmt.Println(strings.Repeat("=", utf8.RuneCountInString(title)+1))
+
+	balances := make([]float64, bets)
+	for i, balance := range trend.Balances {
+		balances[i] = balance.Float64()
+	}
+	fmt.Printf("\n💰 Balance: %s → %s\n", trend.Balances[0].Format(), trend.Balances[bets-1].Format())
+	printChart(lineChart(balances, opts.Width, chartHeight, func(v float64) string {
+		return game.NewMoney(v).Format()
+	}))
+
+	low, high := valueRange(trend.WinRates)
+	fmt.Printf("\n🎯 Win rate over %d bets, %.0f%%–%.0f%%:\n", min(opts.Window, bets), low, high)
+	fmt.Printf("   %s\n", sparkline(trend.WinRates, opts.Width))
+
+	streaks := make([]float64, bets)
+	for i, streak := range trend.Streaks {
+		streaks[i] = float64(streak)
+	}
+	wins, losses := trend.LongestStreaks()
+	fmt.Printf("\n🔥 Streaks: longest %d won, %d lost; now %s\n", wins, losses, describeStreak(trend.Streaks[bets-1]))
+	printChart(lineChart(streaks, opts.Width, streakHeight, func(v float64) string {
+		return fmt.Sprintf("%+.0f", v)
+	}))
+
+	fmt.Println()
+	displayDistribution("Coin results", trend.Stats.Outcomes)
+	displayDistribution("Your choices", trend.Stats.Choices)
+}
+
+// describeStreak describes a streak counted up for wins and down for losses
+func describeStreak(streak int) string {
+	switch {
+	case streak == 1:
+		return "1 win"
+	case streak > 1:
+		return fmt.Sprintf("%d wins in a row", streak)
+	case streak == -1:
+		return "1 loss"
+	default:
+		return fmt.Sprintf("%d losses in a row", -streak)
+	}
+}
+
+// printChart prints the rows of a chart
+func printChart(rows []string) {
+	for _, row := range rows {
+		fmt.Println(row)
+	}
+}
+
+// sparkBlocks are the eighths of a character cell, from lowest to full
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a single row of blocks, at most width wide
+func sparkline(values []float64, width int) string {
+	values = downsample(values, width)
+	low, high := valueRange(values)
+
+	var line strings.Builder
+	for _, v := range values {
+		level := 0
+		if high > low {
+			level = int((v - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
+
+// lineChart draws values as filled columns height rows tall and at most
+// width wide, with the top and bottom of the range labelled on the axis
+func lineChart(values []float64, width, height int, label func(float64) string) []string {
+	values = downsample(values, width)
+	low, high := valueRange(values)
+	if high == low {
+		// A flat line sits in the middle of the chart
+		low, high = low-1, high+1
+	}
+
+	top, bottom := label(high), label(low)
+	labelWidth := max(len([]rune(top)), len([]rune(bottom)))
+
+	rows := make([]string, 0, height+1)
+	for row := height - 1; row >= 0; row-- {
+		axis := strings.Repeat(" ", labelWidth) + " │"
+		switch row {
+		case height - 1:
+			axis = fmt.Sprintf("%*s ┤", labelWidth, top)
+		case 0:
+			axis = fmt.Sprintf("%*s ┤", labelWidth, bottom)
+		}
+
+		var line strings.Builder
+		line.WriteString(axis)
+		for _, v := range values {
+			// Each column is filled to its value in eighths of a row,
+			// and never empty so the lowest values still show
+			eighths := max(int(math.Round((v-low)/(high-low)*float64(height*8))), 1)
+			filled := eighths - row*8
+			switch {
+			case filled >= 8:
+				line.WriteRune(sparkBlocks[7])
+			case filled > 0:
+				line.WriteRune(sparkBlocks[filled-1])
+			default:
+				line.WriteRune(' ')
+			}
+		}
+		rows = append(rows, strings.TrimRight(line.String(), " "))
+	}
+	rows = append(rows, strings.Repeat(" ", labelWidth)+" └"+strings.Repeat("─", len(values)))
+	return rows
+}
+
+// downsample averages values into at most width buckets, keeping their
+// order
+func downsample(values []float64, width int) []float64 {
+	if len(values) <= width {
+		return values
+	}
+
+	buckets := make([]float64, width)
+	for i := range buckets {
+		start := i * len(values) / width
+		end := (i + 1) * len(values) / width
+		sum := 0.0
+		for _, v := range values[start:end] {
+			sum += v
+		}
+		buckets[i] = sum / float64(end-start)
+	}
+	return buckets
+}
+
+// valueRange returns the smallest and largest of values
+func valueRange(values []float64) (low, high float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	low, high = values[0], values[0]
+	for _, v := range values[1:] {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+	return low, high
+}
@@ -23,10 +23,10 @@ balance, game statistics, and current bet status.`,
 
 // showPlayerStatus displays comprehensive player information
 func showPlayerStatus(ctx context.Context, app *CLIApp) error {
-	playerID := getPlayerID()
+	syncBankroll(ctx, app)
 
 	// Get player info
-	player, err := app.Engine.GetPlayer(ctx, playerID)
+	player, err := app.Session.Player(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get player: %w", err)
 	}
@@ -34,13 +34,18 @@ func showPlayerStatus(ctx context.Context, app *CLIApp) error {
 	fmt.Println("👤 Player Status")
 	fmt.Println("================")
 	fmt.Printf("Player ID: %s\n", player.ID)
-	fmt.Printf("💰 Balance: $%.2f\n", player.Balance)
+	fmt.Printf("💰 Balance: %s\n", player.Balance.Format())
 
 	// Show game configuration
 	config := app.Engine.GetConfig()
-	fmt.Printf("🎯 Min bet: $%.2f\n", config.MinBet)
-	fmt.Printf("🎯 Max bet: $%.2f\n", config.MaxBet)
+	fmt.Printf("🎯 Min bet: %s\n", config.MinBet.Format())
+	fmt.Printf("🎯 Max bet: %s\n", config.MaxBet.Format())
 	fmt.Printf("💎 Payout ratio: %.1fx\n", config.PayoutRatio)
+	displayStreakBonus(config)
+	displayInsurance(config)
+	if multiplier := config.StreakMultiplier(player.Stats.CurrentStreak); multiplier > 1 {
+		fmt.Printf("🔥 Next win pays %.2fx the usual payout\n", multiplier)
+	}
 
 	// Check if player can play
 	if player.Balance < config.MinBet {
@@ -50,9 +55,9 @@ func showPlayerStatus(ctx context.Context, app *CLIApp) error {
 	}
 
 	// Show current bet if any
-	if currentBet := app.Engine.GetCurrentBet(); currentBet != nil {
+	if currentBet := app.Session.CurrentBet(); currentBet != nil {
 		fmt.Printf("\n🎲 Active Bet\n")
-		fmt.Printf("Amount: $%.2f\n", currentBet.Amount)
+		fmt.Printf("Amount: %s\n", currentBet.Amount.Format())
 		fmt.Printf("Choice: %s\n", currentBet.Choice)
 		fmt.Printf("Placed: %s\n", currentBet.Timestamp.Format("2006-01-02 15:04:05"))
 	}
@@ -62,5 +67,11 @@ func showPlayerStatus(ctx context.Context, app *CLIApp) error {
 	fmt.Println("=============")
 	displayStats(&player.Stats)
 
+	if player.Practice.GamesPlayed > 0 {
+		fmt.Printf("\n🎯 Practice\n")
+		fmt.Println("===========")
+		displayStats(&player.Practice)
+	}
+
 	return nil
 }
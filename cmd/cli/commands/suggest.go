@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/game"
+)
+
+// suggestOptions holds the flags for suggesting a bet
+type suggestOptions struct {
+	Balance string
+}
+
+// newSuggestCommand creates the suggest command for Kelly bet sizing
+func newSuggestCommand(app *CLIApp) *cobra.Command {
+	var opts suggestOptions
+	ui := &app.Config.UI
+
+	cmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Suggest a bet size with the Kelly criterion",
+		Long: `Suggest how much to bet using the Kelly criterion, which stakes the share of
+the balance that grows it fastest over many bets given the payout ratio and
+the chance of winning.
+
+A fair coin gives no edge, so the suggestion is to sit out unless you assume
+one: --edge 0.02 supposes you win 52% of the time. Betting the full Kelly
+stake swings the balance hard, so by default half of it is suggested. The
+edge and fraction default to ui.suggest_edge and ui.kelly_fraction.`,
+		Example: `  coinflip suggest --edge 0.02
+  coinflip suggest --edge 0.05 --fraction 0.25 --balance 500`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// A configured fraction of zero means half Kelly, but asking for
+			// none is a mistake
+			if cmd.Flags().Changed("fraction") && ui.KellyFraction == 0 {
+				return invalidInput(fmt.Errorf("kelly fraction must be above 0 and at most 1, got 0"))
+			}
+			return runSuggest(cmd.Context(), app, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Balance, "balance", "", "Balance to size the bet for; defaults to the player's")
+	cmd.Flags().Float64Var(&ui.SuggestEdge, "edge", ui.SuggestEdge, "How far above an even chance you believe you win, such as 0.02")
+	cmd.Flags().Float64Var(&ui.KellyFraction, "fraction", ui.KellyFraction, "Share of the full Kelly stake to suggest, above 0 and at most 1")
+
+	return cmd
+}
+
+// runSuggest prints the suggested bet for the balance
+func runSuggest(ctx context.Context, app *CLIApp, opts suggestOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var balance game.Money
+	if opts.Balance != "" {
+		amount, err := game.ParseMoney(opts.Balance)
+		if err != nil {
+			return invalidInput(err)
+		}
+		balance = amount
+	} else {
+		syncBankroll(ctx, app)
+		player, err := app.Session.Player(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get player: %w", err)
+		}
+		balance = player.Balance
+	}
+
+	suggestion, err := app.Config.SuggestBet(balance)
+	if err != nil {
+		return invalidInput(err)
+	}
+
+	config := app.Engine.GetConfig()
+	fmt.Println("📐 Suggested Bet")
+	fmt.Println("================")
+	fmt.Printf("💰 Balance: %s\n", balance.Format())
+	fmt.Printf("🎯 Assumed win chance: %.1f%% at %.2fx payout\n", suggestion.WinProbability*100, config.PayoutRatio)
+	fmt.Printf("📈 Expected return: %+.2f%% per bet\n", suggestion.ExpectedReturn*100)
+	fmt.Printf("🧮 Full Kelly: %.2f%% of the balance, suggesting %.2f%%\n", suggestion.Kelly*100, suggestion.Share*100)
+
+	switch {
+	case suggestion.Share == 0:
+		fmt.Println("🚫 Sit out: the bet does not pay on average at this edge")
+	case suggestion.Amount == 0:
+		fmt.Printf("🚫 Sit out: the suggested share is below the %s minimum bet\n", config.MinBet.Format())
+	case suggestion.Capped:
+		fmt.Printf("✅ Bet %s, the maximum bet\n", suggestion.Amount.Format())
+	default:
+		fmt.Printf("✅ Bet %s\n", suggestion.Amount.Format())
+	}
+	return nil
+}
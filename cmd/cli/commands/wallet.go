@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/network"
+	"coinflip-game/internal/storage"
+)
+
+// walletSyncTimeout bounds reaching the server to sync the wallet
+const walletSyncTimeout = 10 * time.Second
+
+// syncBankroll reconciles the local balance with the player's wallet on
+// the multiplayer server when wallet sync is on. The first call restores
+// the balance cached by earlier runs. When the server cannot be reached
+// the play stays cached for next time, so this only prints a notice.
+func syncBankroll(ctx context.Context, app *CLIApp) {
+	if !app.Config.Wallet.Sync {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if app.Bankroll == nil {
+		cache, err := storage.OpenWalletCache(app.Config.Wallet.CacheFile)
+		if err != nil {
+			app.Logger.Warn("Wallet sync disabled", zap.Error(err))
+			return
+		}
+
+		clientConfig := network.DefaultClientConfig()
+		clientConfig.ServerURL = fmt.Sprintf("ws://%s:%d/ws", app.Config.Multiplayer.ServerHost, app.Config.Multiplayer.ServerPort)
+		clientConfig.Encoding = network.Encoding(app.Config.Multiplayer.Encoding)
+		clientConfig.EnableCompression = app.Config.Multiplayer.Compression
+
+		app.Bankroll = network.NewBankroll(app.Engine, cache, clientConfig, app.Session.PlayerID(), app.Logger)
+		if _, err := app.Bankroll.Restore(ctx); err != nil {
+			app.Logger.Warn("Failed to restore cached balance", zap.Error(err))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, walletSyncTimeout)
+	defer cancel()
+
+	synced, err := app.Bankroll.Sync(ctx)
+	switch {
+	case errors.Is(err, network.ErrWalletInUse):
+		fmt.Println("🪑 You are seated in an online room; this play will sync once you leave it")
+	case err != nil:
+		app.Logger.Debug("Wallet not synced", zap.Error(err))
+		fmt.Printf("📴 Server unreachable; %s of play will sync next time\n", app.Bankroll.Pending().FormatSigned())
+	case synced.Applied != synced.Net:
+		fmt.Printf("🔄 Wallet synced: %s of %s offline play counted, balance %s\n",
+			synced.Applied.FormatSigned(), synced.Net.FormatSigned(), synced.Balance.Format())
+	default:
+		fmt.Printf("🔄 Wallet synced: balance %s\n", synced.Balance.Format())
+	}
+}
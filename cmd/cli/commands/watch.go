@@ -0,0 +1,450 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// Output formats for watch --format
+const (
+	watchFormatText = "text"
+	watchFormatJSON = "json"
+)
+
+// Kinds of event in the watch feed
+const (
+	eventWatching       = "watching"
+	eventJoin           = "join"
+	eventLeave          = "leave"
+	eventBettingOpen    = "betting_open"
+	eventBet            = "bet"
+	eventBetChanged     = "bet_changed"
+	eventBetCancelled   = "bet_cancelled"
+	eventResult         = "result"
+	eventRoundCancelled = "round_cancelled"
+	eventChat           = "chat"
+	eventRoomClosed     = "room_closed"
+)
+
+// lastRound stands for a room's last round with watch --replay
+const lastRound = "last"
+
+// watchOptions holds the flags for following a room's events
+type watchOptions struct {
+	ServerURL string
+	Format    string
+	// Replay is a recorded round to play back instead, or lastRound
+	Replay string
+	Speed  float64
+}
+
+// watchEvent is one entry of the live feed, printed as a JSON line with
+// --format json
+type watchEvent struct {
+	Time       time.Time     `json:"time"`
+	Type       string        `json:"type"`
+	RoomID     string        `json:"room_id"`
+	RoundID    string        `json:"round_id,omitempty"`
+	PlayerID   string        `json:"player_id,omitempty"`
+	PlayerName string        `json:"player_name,omitempty"`
+	Amount     game.Money    `json:"amount,omitempty"`
+	Choice     game.Side     `json:"choice,omitempty"`
+	Insured    bool          `json:"insured,omitempty"`
+	CoinResult game.Side     `json:"coin_result,omitempty"`
+	Results    []watchResult `json:"results,omitempty"`
+	// Players are the names of the seated players when watching starts
+	Players []string `json:"players,omitempty"`
+	// Seconds is how long betting stays open
+	Seconds int `json:"seconds,omitempty"`
+	// Text is a chat message or why a round or the room was closed
+	Text string `json:"text,omitempty"`
+}
+
+// watchResult is one player's outcome in a result event
+type watchResult struct {
+	PlayerID   string     `json:"player_id"`
+	PlayerName string     `json:"player_name"`
+	Won        bool       `json:"won"`
+	Wagered    game.Money `json:"wagered"`
+	Payout     game.Money `json:"payout"`
+	NewBalance game.Money `json:"new_balance"`
+}
+
+// newWatchCommand creates the watch command that prints a room's events
+// as a live feed
+func newWatchCommand(app *CLIApp) *cobra.Command {
+	var opts watchOptions
+
+	cmd := &cobra.Command{
+		Use:   "watch ROOM_ID",
+		Short: "Print a multiplayer room's events as they happen",
+		Long: `Follow a multiplayer room without taking a seat and print its joins,
+bets and results as they happen, until interrupted. Watching does not
+create the room, bet or hold up an early close.
+
+With --format json every event is printed as one JSON object per line,
+with a type of watching, join, leave, betting_open, bet, bet_changed,
+bet_cancelled, result, round_cancelled, chat or room_closed, for piping
+into other tools. The feed ends when the room closes.
+
+With --replay the room's last round, or the round named, is played back
+from the server's recording instead, at --speed times real time, and the
+feed ends with the round. Rooms keep their last few rounds.`,
+		Example: `  coinflip watch friday
+  coinflip watch friday --format json | jq 'select(.type == "result")'
+
+  # Catch up on the round before you arrived, four times faster
+  coinflip watch friday --replay --speed 4`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Format != watchFormatText && opts.Format != watchFormatJSON {
+				return invalidInput(fmt.Errorf("invalid format %q: use %s or %s", opts.Format, watchFormatText, watchFormatJSON))
+			}
+			if opts.Speed <= 0 || opts.Speed > network.MaxReplaySpeed {
+				return invalidInput(fmt.Errorf("--speed must be above 0 and at most %g", network.MaxReplaySpeed))
+			}
+			return runWatch(cmd.Context(), app, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ServerURL, "server",
+		fmt.Sprintf("ws://%s:%d/ws", app.Config.Multiplayer.ServerHost, app.Config.Multiplayer.ServerPort),
+		"Multiplayer server WebSocket URL")
+	cmd.Flags().StringVar(&opts.Format, "format", watchFormatText, "Output format: text or json")
+	cmd.Flags().StringVar(&opts.Replay, "replay", "", "Play back the room's last round, or the round with this ID")
+	cmd.Flags().Lookup("replay").NoOptDefVal = lastRound
+	cmd.Flags().Float64Var(&opts.Speed, "speed", 1, "How many times faster than real time to play back a round")
+	cmd.RegisterFlagCompletionFunc("format", completeValues(watchFormatText, watchFormatJSON))
+	return cmd
+}
+
+// runWatch connects to a server and prints the room's events until the
+// room closes, the connection is lost for good or the process is
+// interrupted
+func runWatch(ctx context.Context, app *CLIApp, roomID string, opts watchOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	clientConfig := network.DefaultClientConfig()
+	clientConfig.ServerURL = opts.ServerURL
+	clientConfig.Encoding = network.Encoding(app.Config.Multiplayer.Encoding)
+	clientConfig.EnableCompression = app.Config.Multiplayer.Compression
+
+	// Unique per run so several feeds can watch one room
+	playerID := fmt.Sprintf("watch_%d", time.Now().UnixNano())
+	client := network.NewNetworkClient(clientConfig, playerID, "Watcher", app.Logger)
+
+	// Reconnects watch the room again; only giving up ends the feed
+	down := make(chan error, 1)
+	client.SetConnectionHandler(func(event network.ConnectionEvent) {
+		if event.Status == network.ConnectionDown {
+			select {
+			case down <- event.Err:
+			default:
+			}
+		}
+	})
+
+	if err := client.Connect(); err != nil {
+		return networkFailure(err)
+	}
+	defer client.Disconnect()
+
+	var err error
+	if opts.Replay != "" {
+		roundID := opts.Replay
+		if roundID == lastRound {
+			roundID = ""
+		}
+		err = client.ReplayRound(roomID, roundID, opts.Speed, 0)
+	} else {
+		err = client.WatchRoom(roomID)
+	}
+	if err != nil {
+		return networkFailure(err)
+	}
+
+	feed := newWatchFeed(roomID)
+	events := client.GetEventChannel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err := <-down:
+			return networkFailure(fmt.Errorf("lost connection while watching room %s: %w", roomID, err))
+
+		case msg := <-events:
+			// A replay's events are the room's messages as they were sent
+			switch msg.Type {
+			case network.MsgReplayRound:
+				var replay network.ReplayData
+				if msg.GetData(&replay) == nil && replay.Replay != nil && opts.Format == watchFormatText {
+					fmt.Printf("⏪ Replaying round %s of room %s from %s\n",
+						replay.Replay.RoundID, roomID, replay.Replay.StartedAt.Local().Format("15:04:05"))
+				}
+				continue
+			case network.MsgReplayEvent:
+				var event network.ReplayEventData
+				if msg.GetData(&event) != nil || event.Event == nil {
+					continue
+				}
+				msg = event.Event
+			case network.MsgReplayEnd:
+				return nil
+			}
+
+			if msg.Type == network.MsgError {
+				var errorData network.ErrorData
+				if err := msg.GetData(&errorData); err != nil {
+					return &ExitError{Code: ExitServerRejected, Err: errors.New("server rejected the request")}
+				}
+				return serverRejection(errorData, "cannot watch room %s (%s): %s", roomID, errorData.Code, errorData.Message)
+			}
+
+			for _, event := range feed.events(msg) {
+				if err := printWatchEvent(os.Stdout, opts.Format, event); err != nil {
+					return err
+				}
+				if event.Type == eventRoomClosed {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// watchFeed turns a room's messages into feed events, remembering the
+// seated players to report joins and leaves and to name bettors
+type watchFeed struct {
+	roomID  string
+	started bool
+	names   map[string]string
+}
+
+// newWatchFeed creates the feed for a room
+func newWatchFeed(roomID string) *watchFeed {
+	return &watchFeed{roomID: roomID, names: make(map[string]string)}
+}
+
+// events returns the feed events a room message stands for, if any
+func (f *watchFeed) events(msg *network.Message) []watchEvent {
+	event := watchEvent{Time: msg.Timestamp, RoomID: f.roomID}
+
+	switch msg.Type {
+	case network.MsgRoomUpdate:
+		var update network.RoomUpdateData
+		if msg.GetData(&update) != nil {
+			return nil
+		}
+		return f.seatChanges(event, &update)
+
+	case network.MsgBetPhase:
+		var timer network.TimerData
+		if msg.GetData(&timer) != nil {
+			return nil
+		}
+		event.Type = eventBettingOpen
+		event.Seconds = timer.SecondsLeft
+		return []watchEvent{event}
+
+	case network.MsgBetPlaced, network.MsgUpdateBet, network.MsgCancelBet:
+		var bet network.BetData
+		if msg.GetData(&bet) != nil {
+			return nil
+		}
+		switch msg.Type {
+		case network.MsgBetPlaced:
+			event.Type = eventBet
+		case network.MsgUpdateBet:
+			event.Type = eventBetChanged
+		default:
+			event.Type = eventBetCancelled
+		}
+		event.PlayerID = msg.PlayerID
+		event.PlayerName = f.name(msg.PlayerID)
+		event.Amount = bet.Amount
+		event.Choice = bet.Choice
+		event.Insured = bet.Insured
+		return []watchEvent{event}
+
+	case network.MsgGameResult:
+		var result network.GameResultData
+		if msg.GetData(&result) != nil {
+			return nil
+		}
+		event.Type = eventResult
+		event.RoundID = result.RoundID
+		event.CoinResult = result.CoinResult
+		for _, players := range [][]network.PlayerResult{result.Winners, result.Losers} {
+			for _, player := range players {
+				event.Results = append(event.Results, watchResult{
+					PlayerID:   player.PlayerID,
+					PlayerName: player.PlayerName,
+					Won:        player.Won,
+					Wagered:    player.Wagered,
+					Payout:     player.Payout,
+					NewBalance: player.NewBalance,
+				})
+			}
+		}
+		return []watchEvent{event}
+
+	case network.MsgRoundCancelled:
+		var cancelled network.RoundCancelledData
+		if msg.GetData(&cancelled) != nil {
+			return nil
+		}
+		event.Type = eventRoundCancelled
+		event.RoundID = cancelled.RoundID
+		event.Text = cancelled.Reason
+		return []watchEvent{event}
+
+	case network.MsgChat:
+		var chat network.ChatData
+		if msg.GetData(&chat) != nil {
+			return nil
+		}
+		event.Type = eventChat
+		event.PlayerID = msg.PlayerID
+		event.PlayerName = chat.PlayerName
+		event.Text = chat.Text
+		return []watchEvent{event}
+
+	case network.MsgRoomClosed:
+		var closed network.RoomClosedData
+		if msg.GetData(&closed) != nil || closed.RoomID != f.roomID {
+			return nil
+		}
+		event.Type = eventRoomClosed
+		event.Text = closed.Reason
+		return []watchEvent{event}
+	}
+	return nil
+}
+
+// seatChanges reports the seated players when watching starts, then who
+// joined or left since the previous update
+func (f *watchFeed) seatChanges(event watchEvent, update *network.RoomUpdateData) []watchEvent {
+	seated := make(map[string]string, len(update.Players))
+	for _, player := range update.Players {
+		seated[player.ID] = player.Name
+	}
+
+	if !f.started {
+		f.started = true
+		f.names = seated
+		event.Type = eventWatching
+		for _, player := range update.Players {
+			event.Players = append(event.Players, player.Name)
+		}
+		return []watchEvent{event}
+	}
+
+	var changes []watchEvent
+	for _, player := range update.Players {
+		if _, known := f.names[player.ID]; !known {
+			joined := event
+			joined.Type, joined.PlayerID, joined.PlayerName = eventJoin, player.ID, player.Name
+			changes = append(changes, joined)
+		}
+	}
+	left := make([]string, 0)
+	for id := range f.names {
+		if _, still := seated[id]; !still {
+			left = append(left, id)
+		}
+	}
+	slices.Sort(left)
+	for _, id := range left {
+		leave := event
+		leave.Type, leave.PlayerID, leave.PlayerName = eventLeave, id, f.names[id]
+		changes = append(changes, leave)
+	}
+
+	f.names = seated
+	return changes
+}
+
+// name returns a seated player's name, or their ID before it is known
+func (f *watchFeed) name(playerID string) string {
+	if name, ok := f.names[playerID]; ok && name != "" {
+		return name
+	}
+	return playerID
+}
+
+// printWatchEvent writes one event in the chosen format
+func printWatchEvent(w io.Writer, format string, event watchEvent) error {
+	if format == watchFormatJSON {
+		return json.NewEncoder(w).Encode(event)
+	}
+	_, err := fmt.Fprintf(w, "[%s] %s\n", event.Time.Local().Format("15:04:05"), event.describe())
+	return err
+}
+
+// describe renders an event as a line of the text feed
+func (e watchEvent) describe() string {
+	switch e.Type {
+	case eventWatching:
+		if len(e.Players) == 0 {
+			return fmt.Sprintf("👀 Watching room %s, no one seated yet", e.RoomID)
+		}
+		return fmt.Sprintf("👀 Watching room %s with %s", e.RoomID, strings.Join(e.Players, ", "))
+	case eventJoin:
+		return fmt.Sprintf("🪑 %s joined", e.PlayerName)
+	case eventLeave:
+		return fmt.Sprintf("🚪 %s left", e.PlayerName)
+	case eventBettingOpen:
+		return fmt.Sprintf("⏰ Betting open for %ds", e.Seconds)
+	case eventBet, eventBetChanged:
+		verb := "bet"
+		if e.Type == eventBetChanged {
+			verb = "changed their bet to"
+		}
+		insured := ""
+		if e.Insured {
+			insured = " ☂️"
+		}
+		return fmt.Sprintf("💸 %s %s %s on %s%s", e.PlayerName, verb, e.Amount.Format(), e.Choice, insured)
+	case eventBetCancelled:
+		return fmt.Sprintf("❌ %s cancelled their bet", e.PlayerName)
+	case eventResult:
+		outcomes := make([]string, 0, len(e.Results))
+		for _, result := range e.Results {
+			if result.Won {
+				outcomes = append(outcomes, fmt.Sprintf("%s won %s", result.PlayerName, result.Payout.Format()))
+			} else {
+				outcomes = append(outcomes, fmt.Sprintf("%s lost %s", result.PlayerName, result.Wagered.Format()))
+			}
+		}
+		if len(outcomes) == 0 {
+			outcomes = append(outcomes, "no bets")
+		}
+		return fmt.Sprintf("🎲 %s: %s", strings.ToUpper(string(e.CoinResult)), strings.Join(outcomes, ", "))
+	case eventRoundCancelled:
+		return fmt.Sprintf("↩️ Round cancelled and bets refunded: %s", e.Text)
+	case eventChat:
+		return fmt.Sprintf("💬 %s: %s", e.PlayerName, e.Text)
+	case eventRoomClosed:
+		return fmt.Sprintf("🔒 Room %s closed (%s)", e.RoomID, e.Text)
+	}
+	return e.Type
+}
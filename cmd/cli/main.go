@@ -5,17 +5,19 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"coinflip-game/cmd/cli/commands"
 	"coinflip-game/internal/config"
 	"coinflip-game/internal/logger"
+	"coinflip-game/internal/tracing"
 
 	"go.uber.org/zap"
 )
 
 func main() {
-	// Load configuration
-	cfg, err := config.Load("")
+	// Load configuration, from the profile chosen with --profile if any
+	cfg, err := config.LoadProfile(config.ProfileFromArgs(os.Args[1:]))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
@@ -29,12 +31,29 @@ func main() {
 	}
 	defer log.Sync()
 
+	// Export traces if configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.ToTracingConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize tracing: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create and execute root command
 	ctx := context.Background()
 	rootCmd := commands.NewRootCommand(cfg, log)
 
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
-		log.Error("Command execution failed", zap.Error(err))
-		os.Exit(1)
+	code := commands.Execute(ctx, rootCmd)
+
+	// Flush spans, without hanging on an unreachable collector
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to flush traces: %v\n", err)
+	}
+	cancel()
+
+	if code != commands.ExitOK {
+		log.Debug("Command execution failed", zap.Int("exit_code", code))
+		log.Sync()
+		os.Exit(code)
 	}
 }
@@ -3,28 +3,41 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
-	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
-	"fyne.io/fyne/v2/theme"
 
 	"coinflip-game/cmd/gui/ui"
 	"coinflip-game/internal/config"
 	"coinflip-game/internal/game"
+	"coinflip-game/internal/instance"
 	"coinflip-game/internal/logger"
 	"coinflip-game/internal/storage"
 )
 
 func main() {
-	// Load configuration
-	cfg, err := config.Load("")
+	// Load configuration, from the profile chosen with --profile if any
+	cfg, err := config.LoadProfile(config.ProfileFromArgs(os.Args[1:]))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
 
+	// One GUI runs per profile; launching it again brings the running one
+	// to the front instead
+	guard, err := instance.Acquire(cfg.InstancePath(), instance.Request{Command: instance.CommandFocus})
+	if errors.Is(err, instance.ErrRunning) {
+		fmt.Println("Coin flip is already running with this profile")
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Running without the single-instance guard: %v\n", err)
+	} else {
+		defer guard.Close()
+	}
+
 	// Initialize logger (use no-op logger for GUI to avoid console spam)
 	log := logger.NewNop()
 
@@ -33,26 +46,38 @@ func main() {
 	rng := game.NewDefaultRandomGenerator()
 	engine := game.NewEngine(cfg.ToGameConfig(), repo, rng, log)
 
+	// Record gameplay to the audit trail if configured
+	if cfg.Logging.AuditFile != "" {
+		audit, err := logger.NewAuditLogger(cfg.Logging.AuditFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open audit log: %v\n", err)
+			os.Exit(1)
+		}
+		defer audit.Close()
+		engine.SetAuditLogger(audit)
+	}
+
 	// Create Fyne application
 	myApp := app.New()
 	myApp.SetIcon(nil) // You can set a custom icon here
 
 	// Set theme based on configuration
-	// Note: Using deprecated themes for educational purposes
-	// In production, consider implementing custom themes
-	if cfg.UI.Theme == "light" {
-		myApp.Settings().SetTheme(theme.LightTheme())
-	} else {
-		myApp.Settings().SetTheme(theme.DarkTheme())
-	}
+	ui.ApplyTheme(myApp, cfg.UI)
 
-	// Create the main window
+	// Start on the home screen, which switches between practice and online play
 	ctx := context.Background()
-	gameUI := ui.NewGameUI(ctx, myApp, engine, cfg, log)
+	home := ui.NewHomeUI(ctx, myApp, engine, cfg, log)
+
+	// Bring the window forward for each later launch
+	if guard != nil {
+		go func() {
+			for range guard.Requests() {
+				home.Raise()
+			}
+		}()
+	}
 
-	// Set window properties
-	window := gameUI.GetWindow()
-	window.Resize(fyne.NewSize(float32(cfg.UI.WindowWidth), float32(cfg.UI.WindowHeight)))
+	window := home.GetWindow()
 	window.CenterOnScreen()
 
 	// Show and run the application
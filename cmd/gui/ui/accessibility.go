@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"coinflip-game/internal/config"
+)
+
+// shortcutActions are the game actions reachable from the keyboard. Nil
+// actions are ignored.
+type shortcutActions struct {
+	heads  func()
+	tails  func()
+	flip   func()
+	cancel func()
+}
+
+// bindShortcuts routes configured keys to game actions. Keys typed into a
+// focused entry stay with the entry, so bet amounts can still be typed.
+func bindShortcuts(window fyne.Window, bindings config.KeyBindings, actions shortcutActions) {
+	bindings = bindings.WithDefaults()
+
+	window.Canvas().SetOnTypedKey(func(event *fyne.KeyEvent) {
+		pressed := normalizeKey(string(event.Name))
+
+		var action func()
+		switch pressed {
+		case normalizeKey(bindings.Heads):
+			action = actions.heads
+		case normalizeKey(bindings.Tails):
+			action = actions.tails
+		case normalizeKey(bindings.Flip):
+			action = actions.flip
+		case normalizeKey(bindings.Cancel):
+			action = actions.cancel
+		}
+
+		if action != nil {
+			action()
+		}
+	})
+}
+
+// normalizeKey compares key names case-insensitively, treating the keypad
+// Enter and common abbreviations as their main keys
+func normalizeKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	switch key {
+	case "enter", "kp_enter":
+		return "return"
+	case "esc":
+		return "escape"
+	}
+	return key
+}
+
+// keyHint labels a button with its shortcut, so the binding is discoverable
+// and announced along with the button text
+func keyHint(label, key string) string {
+	switch normalizeKey(key) {
+	case "":
+		return label
+	case "return":
+		key = "Enter"
+	case "escape":
+		key = "Esc"
+	default:
+		if len(key) == 1 {
+			key = strings.ToUpper(key)
+		}
+	}
+	return label + " [" + key + "]"
+}
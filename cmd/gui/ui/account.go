@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// showRegister asks a guest for an account name to keep their progress
+// under. Registering leaves the current room, since the server only moves
+// balances it holds outside rooms.
+func (ui *MultiplayerGameUI) showRegister() {
+	accountEntry := widget.NewEntry()
+	accountEntry.SetPlaceHolder("alice")
+	accountEntry.Validator = func(s string) error {
+		return game.ValidateAccount(strings.TrimSpace(s))
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Account", accountEntry),
+		widget.NewFormItem("", widget.NewLabel("Your balance, stats and history move to the account.")),
+	}
+
+	form := dialog.NewForm("👤 Register Account", "Register", "Cancel", items, func(confirmed bool) {
+		if confirmed {
+			ui.register(strings.TrimSpace(accountEntry.Text))
+		}
+	}, ui.window)
+	form.Resize(fyne.NewSize(380, 0))
+	form.Show()
+}
+
+// register leaves the room and asks the server to upgrade the guest
+func (ui *MultiplayerGameUI) register(account string) {
+	ui.discardPendingBet()
+
+	go func() {
+		if ui.networkClient.GetCurrentRoom() != "" {
+			if err := ui.networkClient.LeaveRoom(); err != nil {
+				ui.logger.Error("Failed to leave room", zap.Error(err))
+			}
+		}
+
+		if err := ui.networkClient.Register(account); err != nil {
+			ui.logger.Error("Failed to register", zap.Error(err))
+			ui.queueUIUpdate(func() {
+				dialog.ShowError(fmt.Errorf("failed to register: %v", err), ui.window)
+			})
+		}
+	}()
+}
+
+// handleRegistered saves the new account and reconnects as it
+func (ui *MultiplayerGameUI) handleRegistered(msg *network.Message) {
+	var registered network.RegisterData
+	if err := msg.GetData(&registered); err != nil {
+		ui.logger.Error("Failed to parse registration", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		guestID := ui.playerID
+
+		updated := *ui.config
+		updated.UI.Account = registered.Account
+		if err := updated.Save(ui.config.Path()); err != nil {
+			dialog.ShowError(fmt.Errorf("registered as %s, but failed to save it: %w", registered.Account, err), ui.window)
+		}
+		*ui.config = updated
+
+		ui.playerID = registered.Account
+		if ui.config.UI.PlayerName == "" {
+			ui.playerName = registered.Account
+		}
+		ui.balance = registered.Balance
+		ui.registerButton.Hide()
+
+		ui.logger.Info("Registered account",
+			zap.String("guest_id", guestID),
+			zap.String("account", registered.Account),
+		)
+		dialog.ShowInformation("👤 Registered",
+			fmt.Sprintf("You are now playing as %s with %s.", registered.Account, registered.Balance.Format()), ui.window)
+
+		ui.reconnectToServer()
+	})
+}
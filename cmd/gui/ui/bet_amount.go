@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"coinflip-game/internal/config"
+	"coinflip-game/internal/game"
+)
+
+// errBetAmount explains what the bet amount entries accept
+var errBetAmount = errors.New("enter dollars and cents, a percentage such as 10%, half or max")
+
+// parseBetInput resolves a typed bet amount, fixed or a share of the
+// balance, to the stake it comes to
+func parseBetInput(input string, balance, maxBet game.Money) (game.Money, error) {
+	amount, err := game.ParseBetAmount(input)
+	if err != nil {
+		return 0, err
+	}
+	return amount.Resolve(balance, maxBet), nil
+}
+
+// quickBetButton fills in the bet amount from input, working out shares of
+// the balance when clicked
+func (ui *MultiplayerGameUI) quickBetButton(label, input string) *widget.Button {
+	return widget.NewButton(label, func() {
+		stake, err := parseBetInput(input, ui.balance, ui.config.ToGameConfig().MaxBet)
+		if err != nil {
+			return
+		}
+		ui.betInput.SetAmount(stake)
+	})
+}
+
+// updateBetLimits bounds the bet amount control by the bet limits and the
+// player's balance
+func (ui *MultiplayerGameUI) updateBetLimits() {
+	config := ui.config.ToGameConfig()
+	ui.betInput.SetLimits(config.MinBet, config.MaxBet, ui.balance)
+}
+
+// showBelowMinimumBet tells the player their balance cannot cover a bet
+func (ui *MultiplayerGameUI) showBelowMinimumBet() {
+	dialog.ShowInformation("Insufficient Balance",
+		fmt.Sprintf("You need at least %s to bet.", ui.config.ToGameConfig().MinBet.Format()), ui.window)
+}
+
+// betPlaceholder hints in the empty bet amount entry at the stake the
+// Kelly criterion suggests for the balance
+func betPlaceholder(cfg *config.Config, balance game.Money) string {
+	suggestion, err := cfg.SuggestBet(balance)
+	switch {
+	case err != nil:
+		return "Enter bet amount..."
+	case suggestion.Amount == 0:
+		return "Enter bet amount... (Kelly suggests sitting out)"
+	}
+	return fmt.Sprintf("Enter bet amount... (Kelly suggests %s)", suggestion.Amount.Format())
+}
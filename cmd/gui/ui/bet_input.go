@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"coinflip-game/internal/game"
+)
+
+// betInput is the bet amount control: a slider from the smallest to the
+// largest stake the player can bet, steppers moving the stake by one step
+// and a field to type it in, all kept showing the same stake. The field
+// also takes the shares of the balance parseBetInput reads. A typo only
+// leaves the stake where it was, so there is always a valid stake to bet.
+type betInput struct {
+	entry   *widget.Entry
+	slider  *widget.Slider
+	minus   *widget.Button
+	plus    *widget.Button
+	content fyne.CanvasObject
+
+	// The stake, kept between low and high, the most the player can bet
+	// with their balance
+	amount    game.Money
+	low, high game.Money
+	maxBet    game.Money
+	balance   game.Money
+	step      game.Money
+
+	// syncing is set while the controls are moved to the stake, so they
+	// do not report it back
+	syncing   bool
+	disabled  bool
+	onChanged func()
+}
+
+// newBetInput creates a bet amount control calling onChanged whenever the
+// stake changes
+func newBetInput(onChanged func()) *betInput {
+	input := &betInput{step: game.Dollar}
+
+	input.entry = widget.NewEntry()
+	input.entry.Validator = input.validate
+	input.entry.OnChanged = input.typed
+	// Enter puts the stake back in the field in place of a typo or a share
+	input.entry.OnSubmitted = func(string) { input.SetAmount(input.amount) }
+
+	input.slider = widget.NewSlider(0, 1)
+	input.slider.OnChanged = input.slid
+
+	input.minus = widget.NewButton("−", func() { input.SetAmount(input.amount - input.step) })
+	input.plus = widget.NewButton("+", func() { input.SetAmount(input.amount + input.step) })
+
+	input.content = container.NewVBox(
+		container.NewBorder(nil, nil, input.minus, input.plus, input.entry),
+		input.slider,
+	)
+	input.SetLimits(game.Dollar, game.Dollar, 0)
+	input.onChanged = onChanged
+	return input
+}
+
+// SetLimits bounds the stake by the bet limits and the balance. The steppers
+// move it by the minimum bet, and a stake typed as a share of the balance
+// is worked out again for the new balance.
+func (b *betInput) SetLimits(minBet, maxBet, balance game.Money) {
+	b.low, b.maxBet, b.balance = minBet, maxBet, balance
+	b.high = min(maxBet, balance)
+	b.step = game.Dollar
+	if minBet > 0 {
+		b.step = minBet
+	}
+
+	b.syncing = true
+	b.slider.Min = minBet.Float64()
+	b.slider.Max = max(b.high, minBet).Float64()
+	b.slider.Step = b.step.Float64()
+	b.slider.Refresh()
+	b.syncing = false
+
+	if b.Valid() && !b.disabled {
+		b.slider.Enable()
+	} else {
+		b.slider.Disable()
+	}
+
+	if amount, err := parseBetInput(b.entry.Text, balance, maxBet); err == nil && b.inRange(amount) {
+		b.set(amount, false)
+		return
+	}
+	// A half-typed stake stays in the field unless the stake had to move
+	clamped := b.clamp(b.amount)
+	b.set(clamped, clamped != b.amount)
+}
+
+// SetAmount makes amount, kept within the limits, the stake
+func (b *betInput) SetAmount(amount game.Money) {
+	b.set(b.clamp(amount), true)
+}
+
+// SetText fills in the field as if typed, for amounts such as 10% or half
+func (b *betInput) SetText(text string) {
+	b.entry.SetText(text)
+}
+
+// Stake returns the stake to bet, and false when the balance is below the
+// minimum bet. What was typed is replaced by the stake when it was not a
+// valid one, so the field shows what is bet.
+func (b *betInput) Stake() (game.Money, bool) {
+	if !b.Valid() {
+		return 0, false
+	}
+	if b.entry.Validate() != nil || b.entry.Text == "" {
+		b.set(b.amount, true)
+	}
+	return b.amount, true
+}
+
+// Amount returns the stake, which stays the last valid one while a typo
+// is in the field
+func (b *betInput) Amount() game.Money {
+	return b.amount
+}
+
+// Text returns the stake as typed when that is valid, keeping shares of
+// the balance, or as an amount otherwise
+func (b *betInput) Text() string {
+	if b.entry.Text != "" && b.entry.Validate() == nil {
+		return b.entry.Text
+	}
+	return formatAmount(b.amount.Float64())
+}
+
+// Valid reports whether the player can afford the minimum bet
+func (b *betInput) Valid() bool {
+	return b.high >= b.low && b.low > 0
+}
+
+// Enable lets the player change the stake
+func (b *betInput) Enable() {
+	b.disabled = false
+	b.entry.Enable()
+	b.minus.Enable()
+	b.plus.Enable()
+	if b.Valid() {
+		b.slider.Enable()
+	}
+}
+
+// Disable stops the player changing the stake
+func (b *betInput) Disable() {
+	b.disabled = true
+	b.entry.Disable()
+	b.slider.Disable()
+	b.minus.Disable()
+	b.plus.Disable()
+}
+
+// typed takes a stake typed in the field, when it is a valid one
+func (b *betInput) typed(text string) {
+	if b.syncing {
+		return
+	}
+	amount, err := parseBetInput(text, b.balance, b.maxBet)
+	if err != nil || !b.inRange(amount) {
+		return
+	}
+	b.set(amount, false)
+}
+
+// slid takes the stake the slider was moved to
+func (b *betInput) slid(value float64) {
+	if b.syncing {
+		return
+	}
+	b.set(b.clamp(game.NewMoney(value)), true)
+}
+
+// set makes amount the stake, moving the slider, and the field too unless
+// the stake was typed there
+func (b *betInput) set(amount game.Money, showText bool) {
+	b.amount = amount
+
+	b.syncing = true
+	b.slider.SetValue(amount.Float64())
+	if showText {
+		b.entry.SetText(formatAmount(amount.Float64()))
+	}
+	b.syncing = false
+
+	if b.onChanged != nil {
+		b.onChanged()
+	}
+}
+
+// validate explains what is wrong with a typed stake, leaving an empty
+// field to its placeholder
+func (b *betInput) validate(text string) error {
+	if text == "" {
+		return nil
+	}
+	amount, err := parseBetInput(text, b.balance, b.maxBet)
+	if err != nil {
+		return errBetAmount
+	}
+	if !b.inRange(amount) {
+		return fmt.Errorf("bet must be between %s and %s", b.low.Format(), b.high.Format())
+	}
+	return nil
+}
+
+// inRange reports whether amount is a stake the player can bet
+func (b *betInput) inRange(amount game.Money) bool {
+	return amount >= b.low && amount <= b.high
+}
+
+// clamp brings amount within the stakes the player can bet
+func (b *betInput) clamp(amount game.Money) game.Money {
+	if amount > b.high {
+		amount = b.high
+	}
+	if amount < b.low {
+		amount = b.low
+	}
+	return amount
+}
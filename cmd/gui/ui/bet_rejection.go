@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// betRejectionCodes are the errors the server sends for refused bets, shown
+// next to the bet controls rather than in a dialog
+var betRejectionCodes = map[string]bool{
+	"bet_failed":        true,
+	"update_bet_failed": true,
+	"invalid_bet_data":  true,
+}
+
+// newBetRejectionBox builds the inline notice for refused bets, hidden
+// until a bet is refused
+func (ui *MultiplayerGameUI) newBetRejectionBox() *fyne.Container {
+	ui.betErrorLabel = widget.NewLabel("")
+	ui.betErrorLabel.Importance = widget.DangerImportance
+	ui.betErrorLabel.Wrapping = fyne.TextWrapWord
+	ui.betFixButton = widget.NewButton("", nil)
+	ui.betFixButton.Importance = widget.WarningImportance
+
+	box := container.NewBorder(nil, nil, nil, ui.betFixButton, ui.betErrorLabel)
+	box.Hide()
+	return box
+}
+
+// showBetRejection explains a refused bet next to the bet controls, with a
+// button applying the suggested fix where there is one
+func (ui *MultiplayerGameUI) showBetRejection(errorData network.ErrorData) {
+	ui.betErrorLabel.SetText("⚠️ " + betRejectionText(errorData))
+
+	label, fix := ui.betRejectionFix(errorData.Rejection)
+	if fix == nil {
+		ui.betFixButton.Hide()
+	} else {
+		ui.betFixButton.SetText(label)
+		ui.betFixButton.OnTapped = func() {
+			fix()
+			ui.clearBetRejection()
+		}
+		ui.betFixButton.Show()
+	}
+	ui.betErrorBox.Show()
+}
+
+// clearBetRejection hides the notice once it no longer applies
+func (ui *MultiplayerGameUI) clearBetRejection() {
+	ui.betErrorBox.Hide()
+}
+
+// betRejectionFix returns the label and action of the button fixing a
+// refused bet, or a nil action when there is nothing to change
+func (ui *MultiplayerGameUI) betRejectionFix(rejection *network.BetRejection) (string, func()) {
+	if rejection == nil {
+		return "", nil
+	}
+
+	stake := func(amount game.Money) (string, func()) {
+		return "Bet " + amount.Format(), func() { ui.betInput.SetAmount(amount) }
+	}
+	switch rejection.Reason {
+	case network.RejectInsufficientBalance, network.RejectBetLimit:
+		if rejection.MaxAmount == 0 {
+			return "", nil
+		}
+		return stake(rejection.MaxAmount)
+	case network.RejectBetAmount:
+		switch {
+		case rejection.Amount > 0 && rejection.Amount < rejection.MinAmount:
+			return stake(rejection.MinAmount)
+		case rejection.Amount > rejection.MaxAmount:
+			return stake(rejection.MaxAmount)
+		}
+	case network.RejectInsurance:
+		return "Bet uninsured", func() { ui.insureCheck.SetChecked(false) }
+	}
+	return "", nil
+}
+
+// betRejectionText says why the server refused a bet and what would get it
+// accepted, falling back to the server's message for reasons it does not
+// know
+func betRejectionText(errorData network.ErrorData) string {
+	rejection := errorData.Rejection
+	if rejection == nil {
+		return errorData.Message
+	}
+
+	switch rejection.Reason {
+	case network.RejectInsufficientBalance:
+		if rejection.MaxAmount == 0 {
+			return fmt.Sprintf("Not enough balance for the minimum bet of %s", rejection.MinAmount.Format())
+		}
+		return fmt.Sprintf("Not enough balance for this bet: reduce to %s max", rejection.MaxAmount.Format())
+	case network.RejectBetLimit:
+		if rejection.MaxAmount == 0 {
+			return "The room's betting limits leave no room for another bet this round"
+		}
+		return fmt.Sprintf("Over the room's betting limits: reduce to %s max", rejection.MaxAmount.Format())
+	case network.RejectBetAmount:
+		switch {
+		case rejection.Amount > 0 && rejection.Amount < rejection.MinAmount:
+			return fmt.Sprintf("Below the minimum bet: raise to %s", rejection.MinAmount.Format())
+		case rejection.Amount > rejection.MaxAmount:
+			return fmt.Sprintf("Over the maximum bet: reduce to %s max", rejection.MaxAmount.Format())
+		}
+		return fmt.Sprintf("Bets must be from %s to %s", rejection.MinAmount.Format(), rejection.MaxAmount.Format())
+	case network.RejectBettingClosed:
+		return "Too late, betting has closed: bet again to queue it for the next round"
+	case network.RejectAlreadyBet:
+		return "You have already bet this round: change the stake on your side instead"
+	case network.RejectNoBet:
+		return "There is no bet on that side to change"
+	case network.RejectInsurance:
+		return "Insurance isn't offered on this bet: bet without it"
+	case network.RejectPracticeMixed:
+		return "Practice and real-money bets can't be mixed in one round"
+	}
+	return errorData.Message
+}
@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// betSlip is the bet slip panel: bets staged one per upcoming round, and
+// the slip the server is placing from
+type betSlip struct {
+	// staged are bets added here and not yet submitted; active are the
+	// submitted bets the server has still to place, the next one first
+	staged []network.BetData
+	active []network.BetData
+
+	list          *widget.List
+	exposureLabel *widget.Label
+	activeLabel   *widget.Label
+	submitButton  *widget.Button
+	cancelButton  *widget.Button
+}
+
+// newBetSlipSection builds the bet slip panel. Bets are added from the bet
+// amount and insurance above, one per round in order, and submitted in one
+// go; the server then places one as each betting phase opens.
+func (ui *MultiplayerGameUI) newBetSlipSection() fyne.CanvasObject {
+	slip := &betSlip{}
+	ui.betSlip = slip
+
+	slip.list = widget.NewList(
+		func() int { return len(slip.staged) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButton("✖", nil), widget.NewLabel("Round"))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			if id >= len(slip.staged) {
+				return
+			}
+			cont := item.(*fyne.Container)
+			cont.Objects[0].(*widget.Label).SetText(fmt.Sprintf("Round +%d  %s", id+1, ui.describeSlipBet(slip.staged[id])))
+			cont.Objects[1].(*widget.Button).OnTapped = func() { ui.removeSlipBet(id) }
+		},
+	)
+	scroll := container.NewScroll(slip.list)
+	scroll.SetMinSize(fyne.NewSize(500, 120))
+
+	slip.exposureLabel = widget.NewLabel("")
+	slip.activeLabel = widget.NewLabel("")
+	slip.activeLabel.Wrapping = fyne.TextWrapWord
+	slip.submitButton = widget.NewButton("📤 Submit Slip", ui.submitBetSlip)
+	slip.submitButton.Importance = widget.HighImportance
+	slip.cancelButton = widget.NewButton("❌ Cancel Slip", ui.cancelBetSlip)
+	ui.refreshBetSlip()
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("🎟️ Bet Slip"), slip.exposureLabel),
+		container.NewGridWithColumns(3,
+			widget.NewButton("➕ Heads", func() { ui.addSlipBet(game.Heads) }),
+			widget.NewButton("➕ Tails", func() { ui.addSlipBet(game.Tails) }),
+			widget.NewButton("🗑️ Clear", func() {
+				slip.staged = nil
+				ui.refreshBetSlip()
+			}),
+		),
+		scroll,
+		slip.submitButton,
+		container.NewBorder(nil, nil, nil, slip.cancelButton, slip.activeLabel),
+	)
+}
+
+// describeSlipBet says what a slip bet stakes, such as "$10.00 on 👑 heads ☂️"
+func (ui *MultiplayerGameUI) describeSlipBet(bet network.BetData) string {
+	text := fmt.Sprintf("%s on %s", bet.Amount.Format(), ui.sideText(bet.Choice))
+	if bet.Insured {
+		text += " ☂️"
+	}
+	return text
+}
+
+// addSlipBet stages the typed bet on a side for the round after the last
+// one staged
+func (ui *MultiplayerGameUI) addSlipBet(choice game.Side) {
+	slip := ui.betSlip
+	if len(slip.staged) >= network.MaxBetSlipBets {
+		dialog.ShowInformation("🎟️ Bet Slip", fmt.Sprintf("A slip holds at most %d bets.", network.MaxBetSlipBets), ui.window)
+		return
+	}
+
+	amount, ok := ui.betInput.Stake()
+	if !ok {
+		ui.showBelowMinimumBet()
+		return
+	}
+
+	slip.staged = append(slip.staged, network.BetData{
+		Amount:  amount,
+		Choice:  choice,
+		Insured: ui.insurance.Enabled() && ui.insureCheck.Checked,
+	})
+	ui.refreshBetSlip()
+}
+
+// removeSlipBet drops a staged bet; the ones after it move up a round
+func (ui *MultiplayerGameUI) removeSlipBet(index int) {
+	slip := ui.betSlip
+	if index >= len(slip.staged) {
+		return
+	}
+	slip.staged = slices.Delete(slip.staged, index, index+1)
+	ui.refreshBetSlip()
+}
+
+// submitBetSlip sends the staged bets to the server as the player's slip,
+// replacing any slip it is placing from
+func (ui *MultiplayerGameUI) submitBetSlip() {
+	if ui.networkClient.GetCurrentRoom() == "" {
+		dialog.ShowInformation("No Room", "Join a room first", ui.window)
+		return
+	}
+
+	bets := slices.Clone(ui.betSlip.staged)
+	go func() {
+		if err := ui.networkClient.SetBetSlip(bets); err != nil {
+			ui.queueUIUpdate(func() {
+				dialog.ShowError(fmt.Errorf("failed to submit bet slip: %v", err), ui.window)
+			})
+		}
+	}()
+}
+
+// cancelBetSlip withdraws the slip the server is placing from
+func (ui *MultiplayerGameUI) cancelBetSlip() {
+	go func() {
+		if err := ui.networkClient.ClearBetSlip(); err != nil {
+			ui.queueUIUpdate(func() {
+				dialog.ShowError(fmt.Errorf("failed to cancel bet slip: %v", err), ui.window)
+			})
+		}
+	}()
+}
+
+// refreshBetSlip shows the staged bets with what they stake in all against
+// the balance, and the bets the server has still to place
+func (ui *MultiplayerGameUI) refreshBetSlip() {
+	slip := ui.betSlip
+	slip.list.Refresh()
+
+	exposure := network.BetSlipExposure(slip.staged, ui.insurance)
+	text := fmt.Sprintf("Exposure %s of %s", exposure.Format(), ui.balance.Format())
+	if exposure > ui.balance {
+		text = "⚠️ " + text
+	}
+	slip.exposureLabel.SetText(text)
+
+	if len(slip.staged) == 0 || exposure > ui.balance {
+		slip.submitButton.Disable()
+	} else {
+		slip.submitButton.Enable()
+	}
+
+	if len(slip.active) == 0 {
+		slip.activeLabel.SetText("No bets on the way")
+		slip.cancelButton.Hide()
+		return
+	}
+	slip.activeLabel.SetText(fmt.Sprintf("⏭️ %d to come, next %s", len(slip.active), ui.describeSlipBet(slip.active[0])))
+	slip.cancelButton.Show()
+}
+
+// handleBetSlip tracks the local player's slip as the server stages,
+// places from or drops it
+func (ui *MultiplayerGameUI) handleBetSlip(msg *network.Message) {
+	if msg.PlayerID != ui.playerID {
+		return
+	}
+
+	var data network.BetSlipData
+	if err := msg.GetData(&data); err != nil {
+		ui.logger.Error("Failed to parse bet slip", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		slip := ui.betSlip
+		slip.active = data.Bets
+		switch data.Status {
+		case network.QueuedBetWaiting:
+			// Submitted, so the staged bets are now on their way
+			slip.staged = nil
+			ui.gameResult.SetText(fmt.Sprintf("🎟️ Bet slip submitted: %d bets, one a round", len(data.Bets)))
+		case network.QueuedBetPlaced:
+			if data.Bet != nil {
+				ui.gameResult.SetText("🎟️ Slip bet placed: " + ui.describeSlipBet(*data.Bet))
+			}
+		case network.QueuedBetWithdrawn:
+			ui.gameResult.SetText("🎟️ Bet slip cancelled")
+		case network.QueuedBetFailed:
+			ui.gameResult.SetText("⚠️ Slip bet not placed, the rest of the slip was dropped: " + data.Reason)
+		}
+		ui.refreshBetSlip()
+	})
+}
@@ -0,0 +1,36 @@
+// Package ui provides small chart widgets for the statistics panels
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"coinflip-game/internal/game"
+)
+
+// newDistributionChart draws a heads/tails distribution as two labelled bars
+func newDistributionChart(title string, dist game.Distribution) fyne.CanvasObject {
+	rows := container.NewVBox(widget.NewLabel(title))
+
+	for _, side := range []game.Side{game.Heads, game.Tails} {
+		count := dist.Count(side)
+		percent := dist.Percent(side)
+
+		bar := widget.NewProgressBar()
+		bar.TextFormatter = func() string {
+			return fmt.Sprintf("%d (%.1f%%)", count, percent)
+		}
+		bar.SetValue(percent / 100)
+
+		icon := "👑 Heads"
+		if side == game.Tails {
+			icon = "🦅 Tails"
+		}
+		rows.Add(container.NewBorder(nil, nil, widget.NewLabel(icon), nil, bar))
+	}
+
+	return rows
+}
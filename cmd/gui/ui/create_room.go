@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// showCreateRoom lets the player open a room with their own settings. Empty
+// fields take the server's defaults; the player joins the room once the
+// server has created it.
+func (ui *MultiplayerGameUI) showCreateRoom() {
+	if ui.networkClient == nil || !ui.networkClient.IsConnected() {
+		dialog.ShowInformation("➕ Create Room", "Connect to the server to create a room.", ui.window)
+		return
+	}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Friday flips")
+	nameEntry.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return errors.New("enter a room name")
+		}
+		return nil
+	}
+
+	idEntry := widget.NewEntry()
+	idEntry.SetPlaceHolder("From the name")
+	idEntry.Validator = func(s string) error {
+		if strings.IndexFunc(s, unicode.IsSpace) >= 0 {
+			return errors.New("room IDs cannot contain spaces")
+		}
+		return nil
+	}
+
+	money := optionalPositive(func(s string) (float64, error) {
+		amount, err := game.ParseMoney(s)
+		return amount.Float64(), err
+	})
+	count := optionalPositive(func(s string) (float64, error) {
+		n, err := strconv.Atoi(s)
+		return float64(n), err
+	})
+	minBetEntry := widget.NewEntry()
+	minBetEntry.SetPlaceHolder("Server default")
+	minBetEntry.Validator = money
+	maxBetEntry := widget.NewEntry()
+	maxBetEntry.SetPlaceHolder("Server default")
+	maxBetEntry.Validator = money
+	bettingEntry := widget.NewEntry()
+	bettingEntry.SetPlaceHolder("Server default")
+	bettingEntry.Validator = count
+	maxPlayersEntry := widget.NewEntry()
+	maxPlayersEntry.SetPlaceHolder("Server default")
+	maxPlayersEntry.Validator = count
+	// A starting stack makes the room a freeroll, played with the room's
+	// chips rather than the bankroll
+	stackEntry := widget.NewEntry()
+	stackEntry.SetPlaceHolder("Your bankroll")
+	stackEntry.Validator = money
+
+	modes := make([]string, 0, len(network.RoomModes()))
+	for _, mode := range network.RoomModes() {
+		modes = append(modes, string(mode))
+	}
+	modeHint := widget.NewLabel(network.ModeClassic.Description())
+	modeSelect := widget.NewSelect(modes, func(mode string) {
+		modeHint.SetText(network.RoomMode(mode).Description())
+	})
+	modeSelect.SetSelected(string(network.ModeClassic))
+
+	privateCheck := widget.NewCheck("Hide from the room list", nil)
+	earlyCloseCheck := widget.NewCheck("Close betting once everyone has bet", nil)
+	// Turbo rounds have fixed phase lengths, so the betting time gives way
+	turboCheck := widget.NewCheck("10s betting, 3s results", func(on bool) {
+		if on {
+			bettingEntry.SetText("")
+			bettingEntry.Disable()
+		} else {
+			bettingEntry.Enable()
+		}
+	})
+
+	localeSelect := widget.NewSelect(network.SupportedLocales(), nil)
+	localeSelect.SetSelected(network.DefaultLocale)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Room ID", idEntry),
+		widget.NewFormItem("Minimum bet", minBetEntry),
+		widget.NewFormItem("Maximum bet", maxBetEntry),
+		widget.NewFormItem("Betting time (s)", bettingEntry),
+		widget.NewFormItem("Max players", maxPlayersEntry),
+		widget.NewFormItem("Starting stack", stackEntry),
+		widget.NewFormItem("Mode", modeSelect),
+		widget.NewFormItem("", modeHint),
+		widget.NewFormItem("Private", privateCheck),
+		widget.NewFormItem("Early close", earlyCloseCheck),
+		widget.NewFormItem("Turbo", turboCheck),
+		widget.NewFormItem("Language", localeSelect),
+	}
+
+	form := dialog.NewForm("➕ Create Room", "Create", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		// Validators have already run, so parsing cannot fail here
+		name := strings.TrimSpace(nameEntry.Text)
+		roomID := idEntry.Text
+		if roomID == "" {
+			roomID = roomIDFromName(name)
+		}
+		settings := &network.RoomSettings{
+			Mode:       modeSelect.Selected,
+			Private:    privateCheck.Checked,
+			EarlyClose: earlyCloseCheck.Checked,
+			Turbo:      turboCheck.Checked,
+			Locale:     localeSelect.Selected,
+		}
+		settings.MinBet, _ = game.ParseMoney(minBetEntry.Text)
+		settings.MaxBet, _ = game.ParseMoney(maxBetEntry.Text)
+		settings.BettingSeconds, _ = strconv.Atoi(bettingEntry.Text)
+		settings.MaxPlayers, _ = strconv.Atoi(maxPlayersEntry.Text)
+		settings.StartingStack, _ = game.ParseMoney(stackEntry.Text)
+
+		go func() {
+			if err := ui.networkClient.CreateRoom(roomID, name, settings); err != nil {
+				ui.queueUIUpdate(func() {
+					dialog.ShowError(fmt.Errorf("failed to create room: %v", err), ui.window)
+				})
+			}
+		}()
+	}, ui.window)
+
+	form.Resize(fyne.NewSize(420, 0))
+	form.Show()
+}
+
+// handleRoomCreated moves the player into the room they created
+func (ui *MultiplayerGameUI) handleRoomCreated(msg *network.Message) {
+	var created network.RoomCreateData
+	if err := msg.GetData(&created); err != nil {
+		ui.logger.Error("Failed to parse created room", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		ui.gameResult.SetText(fmt.Sprintf("➕ Created %s (%s): %s",
+			created.RoomName, msg.RoomID, describeSettings(created.Settings)))
+		ui.switchRoom(msg.RoomID)
+	})
+}
+
+// roomIDFromName turns a room name into an ID friends can type, such as
+// "friday-flips" for "Friday Flips!"
+func roomIDFromName(name string) string {
+	var id strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && id.Len() > 0 {
+				id.WriteByte('-')
+			}
+			id.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	if id.Len() == 0 {
+		return fmt.Sprintf("room-%d", time.Now().Unix())
+	}
+	return id.String()
+}
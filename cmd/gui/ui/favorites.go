@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/config"
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+// favoritesBar is the favorites dropdown: picking a favorite places it at
+// once, the star saves the bet being typed as a new favorite and the bin
+// removes one
+type favoritesBar struct {
+	book     *storage.FavoriteBook
+	playerID string
+	window   fyne.Window
+	selector *widget.Select
+	content  fyne.CanvasObject
+
+	// current returns the typed amount and whether insurance is ticked
+	current func() (string, bool)
+	place   func(game.FavoriteBet)
+}
+
+// newFavoritesBar opens the player's favorite bets. When the favorites
+// file cannot be read they are kept for this run only.
+func newFavoritesBar(cfg *config.Config, playerID string, window fyne.Window, logger *zap.Logger,
+	current func() (string, bool), place func(game.FavoriteBet)) *favoritesBar {
+	book, err := storage.OpenFavoriteBook(cfg.UI.FavoritesFile)
+	if err != nil {
+		logger.Warn("Favorite bets will not be saved", zap.Error(err))
+		book, _ = storage.OpenFavoriteBook("")
+	}
+
+	bar := &favoritesBar{
+		book:     book,
+		playerID: playerID,
+		window:   window,
+		current:  current,
+		place:    place,
+	}
+	bar.selector = widget.NewSelect(nil, bar.pick)
+	bar.selector.PlaceHolder = "⭐ Favorites"
+	bar.content = container.NewBorder(nil, nil, nil,
+		container.NewHBox(
+			widget.NewButton("⭐", bar.showSave),
+			widget.NewButton("🗑️", bar.showRemove),
+		),
+		bar.selector)
+	bar.refresh()
+	return bar
+}
+
+// refresh lists the player's favorites in the dropdown
+func (b *favoritesBar) refresh() {
+	favorites := b.book.List(b.playerID)
+	options := make([]string, len(favorites))
+	for i, favorite := range favorites {
+		options[i] = favorite.Name
+	}
+	b.selector.SetOptions(options)
+}
+
+// pick places the chosen favorite and clears the dropdown for the next one
+func (b *favoritesBar) pick(name string) {
+	if name == "" {
+		return
+	}
+	b.selector.ClearSelected()
+
+	favorite, err := b.book.Get(b.playerID, name)
+	if err != nil {
+		dialog.ShowError(err, b.window)
+		return
+	}
+	b.place(favorite)
+}
+
+// showSave asks for a name and side to save the typed bet under
+func (b *favoritesBar) showSave() {
+	amount, insured := b.current()
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("e.g. lucky25")
+	amountEntry := widget.NewEntry()
+	amountEntry.SetText(amount)
+	amountEntry.SetPlaceHolder("25, 10%, half or max")
+	choiceRadio := widget.NewRadioGroup([]string{string(game.Heads), string(game.Tails)}, nil)
+	choiceRadio.Horizontal = true
+	choiceRadio.SetSelected(string(game.Heads))
+	insureCheck := widget.NewCheck("☂️ Insure", nil)
+	insureCheck.SetChecked(insured)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Amount", amountEntry),
+		widget.NewFormItem("Side", choiceRadio),
+		widget.NewFormItem("", insureCheck),
+	}
+	dialog.ShowForm("⭐ Save Favorite Bet", "Save", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		stake, err := game.ParseBetAmount(amountEntry.Text)
+		if err != nil {
+			dialog.ShowError(errBetAmount, b.window)
+			return
+		}
+		favorite := game.FavoriteBet{
+			Name:    nameEntry.Text,
+			Amount:  stake,
+			Choice:  game.Side(choiceRadio.Selected),
+			Insured: insureCheck.Checked,
+		}
+		if err := b.book.Save(b.playerID, favorite); err != nil {
+			dialog.ShowError(err, b.window)
+			return
+		}
+		b.refresh()
+	}, b.window)
+}
+
+// showRemove asks which favorite to delete
+func (b *favoritesBar) showRemove() {
+	favorites := b.book.List(b.playerID)
+	if len(favorites) == 0 {
+		dialog.ShowInformation("⭐ Favorites", "You have no favorite bets yet. Type a bet and press ⭐ to save it.", b.window)
+		return
+	}
+
+	options := make([]string, len(favorites))
+	for i, favorite := range favorites {
+		options[i] = favorite.Name + " · " + favorite.Describe()
+	}
+	choice := widget.NewSelect(options, nil)
+	choice.SetSelectedIndex(0)
+
+	items := []*widget.FormItem{widget.NewFormItem("Favorite", choice)}
+	dialog.ShowForm("🗑️ Remove Favorite Bet", "Remove", "Cancel", items, func(confirmed bool) {
+		index := choice.SelectedIndex()
+		if !confirmed || index < 0 {
+			return
+		}
+		if err := b.book.Remove(b.playerID, favorites[index].Name); err != nil {
+			dialog.ShowError(err, b.window)
+			return
+		}
+		b.refresh()
+	}, b.window)
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+// showSaveFile asks where to save a file, offering fileName in dataDir,
+// and passes the chosen file to save. save is not called if the player
+// cancels; it must close the writer.
+func showSaveFile(parent fyne.Window, dataDir, fileName string, extensions []string, save func(fyne.URIWriteCloser)) {
+	picker := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to open file: %w", err), parent)
+			return
+		}
+		if writer != nil {
+			save(writer)
+		}
+	}, parent)
+	picker.SetFileName(fileName)
+	picker.SetFilter(storage.NewExtensionFileFilter(extensions))
+	if location := dialogLocation(dataDir); location != nil {
+		picker.SetLocation(location)
+	}
+	picker.Show()
+}
+
+// showOpenFile asks for a file to open, starting in dataDir, and passes
+// it to open. open is not called if the player cancels; it must close the
+// reader.
+func showOpenFile(parent fyne.Window, dataDir string, extensions []string, open func(fyne.URIReadCloser)) {
+	picker := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to open file: %w", err), parent)
+			return
+		}
+		if reader != nil {
+			open(reader)
+		}
+	}, parent)
+	picker.SetFilter(storage.NewExtensionFileFilter(extensions))
+	if location := dialogLocation(dataDir); location != nil {
+		picker.SetLocation(location)
+	}
+	picker.Show()
+}
+
+// showPickFolder asks for a directory, starting in dataDir, and passes its
+// path to pick unless the player cancels
+func showPickFolder(parent fyne.Window, dataDir string, pick func(path string)) {
+	picker := dialog.NewFolderOpen(func(folder fyne.ListableURI, err error) {
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to open folder: %w", err), parent)
+			return
+		}
+		if folder != nil {
+			pick(folder.Path())
+		}
+	}, parent)
+	if location := dialogLocation(dataDir); location != nil {
+		picker.SetLocation(location)
+	}
+	picker.Show()
+}
+
+// dialogLocation returns the directory file dialogs start in, or nil to
+// leave them at the platform default when dir is unset or missing
+func dialogLocation(dir string) fyne.ListableURI {
+	if dir == "" {
+		return nil
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil
+	}
+	location, err := storage.ListerForURI(storage.NewFileURI(dir))
+	if err != nil {
+		return nil
+	}
+	return location
+}
+
+// validateDataDir accepts an empty data directory or an existing one
+func validateDataDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
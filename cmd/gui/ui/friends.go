@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/network"
+)
+
+// newFriendList shows the player's friend code, a field to add friends by
+// name or code, and each friend with whether they are online. Online friends
+// can be invited to the current room.
+func newFriendList(reply *network.FriendsData, inRoom bool, add, remove, invite func(friend string)) fyne.CanvasObject {
+	code := widget.NewEntry()
+	code.SetText(reply.Code)
+	code.Disable()
+
+	friendEntry := widget.NewEntry()
+	friendEntry.SetPlaceHolder("Account name or friend code")
+	addButton := widget.NewButton("Add", func() {
+		if friendEntry.Text != "" {
+			add(friendEntry.Text)
+		}
+	})
+	addButton.Importance = widget.HighImportance
+
+	rows := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("Your friend code:"), nil, code),
+		container.NewBorder(nil, nil, nil, addButton, friendEntry),
+		widget.NewSeparator(),
+	)
+	if len(reply.Friends) == 0 {
+		rows.Add(widget.NewLabel("No friends yet. Share your code to be added."))
+	}
+
+	for _, friend := range reply.Friends {
+		friend := friend
+		status := "⚫ Offline"
+		if friend.Online {
+			status = "🟢 Online"
+			if friend.RoomID != "" {
+				status += " in " + friend.RoomID
+			}
+		}
+		info := widget.NewLabel(fmt.Sprintf("%s  %s\n%s", friend.Name, friend.Code, status))
+
+		inviteButton := widget.NewButton("Invite", func() { invite(friend.PlayerID) })
+		if !friend.Online || !inRoom {
+			inviteButton.Disable()
+		}
+		removeButton := widget.NewButton("Remove", func() { remove(friend.PlayerID) })
+
+		rows.Add(container.NewBorder(nil, nil, nil, container.NewHBox(inviteButton, removeButton), info))
+	}
+
+	return rows
+}
+
+// showFriends asks the server for the player's friends; the list opens when
+// it arrives
+func (ui *MultiplayerGameUI) showFriends() {
+	if ui.networkClient == nil || !ui.networkClient.IsConnected() {
+		dialog.ShowInformation("👥 Friends", "Connect to the server to see your friends.", ui.window)
+		return
+	}
+
+	go func() {
+		if err := ui.networkClient.RequestFriends(); err != nil {
+			ui.queueUIUpdate(func() {
+				dialog.ShowError(err, ui.window)
+			})
+		}
+	}()
+}
+
+// handleFriendsReply shows the friend list after a friends request. The
+// list reply opens it; adding and removing friends refresh it while open.
+func (ui *MultiplayerGameUI) handleFriendsReply(msg *network.Message) {
+	var reply network.FriendsData
+	if err := msg.GetData(&reply); err != nil {
+		ui.logger.Error("Failed to parse friends reply", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		if ui.friendList != nil {
+			ui.friendList.Hide()
+		} else if msg.Type != network.MsgFriends {
+			return
+		}
+		send := func(request func(string) error) func(string) {
+			return func(friend string) {
+				go func() {
+					if err := request(friend); err != nil {
+						ui.queueUIUpdate(func() {
+							dialog.ShowError(err, ui.window)
+						})
+					}
+				}()
+			}
+		}
+		content := newFriendList(&reply, ui.networkClient.GetCurrentRoom() != "",
+			send(ui.networkClient.AddFriend), send(ui.networkClient.RemoveFriend), send(ui.networkClient.InviteFriend))
+
+		ui.friendList = dialog.NewCustom("👥 Friends", "Close", container.NewVScroll(content), ui.window)
+		ui.friendList.SetOnClosed(func() { ui.friendList = nil })
+		ui.friendList.Resize(fyne.NewSize(480, 420))
+		ui.friendList.Show()
+	})
+}
+
+// handleRoomInvite confirms an invite the player sent, or offers to join
+// the room a friend invited them to
+func (ui *MultiplayerGameUI) handleRoomInvite(msg *network.Message) {
+	var invite network.InviteData
+	if err := msg.GetData(&invite); err != nil {
+		ui.logger.Error("Failed to parse room invite", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		if invite.To != ui.playerID {
+			ui.appendChat(fmt.Sprintf("✉️ Invited %s to %s", invite.To, invite.RoomName))
+			return
+		}
+
+		text := fmt.Sprintf("%s invited you to %s", invite.FromName, invite.RoomName)
+		ui.tray.Notify("✉️ Room invite", text)
+		dialog.ShowConfirm("✉️ Room invite", text+". Join now?", func(join bool) {
+			if join {
+				ui.switchRoom(invite.RoomID)
+			}
+		}, ui.window)
+	})
+}
+
+// switchRoom leaves the current room, if any, and joins roomID
+func (ui *MultiplayerGameUI) switchRoom(roomID string) {
+	if ui.networkClient.GetCurrentRoom() == roomID {
+		return
+	}
+
+	go func() {
+		if ui.networkClient.GetCurrentRoom() != "" {
+			if err := ui.networkClient.LeaveRoom(); err != nil {
+				ui.logger.Error("Failed to leave room", zap.Error(err))
+			}
+		}
+		ui.queueUIUpdate(func() {
+			ui.currentPlayers = nil
+			ui.queuedBet = nil
+			ui.joinRoom(roomID)
+		})
+	}()
+}
@@ -4,7 +4,6 @@ package ui
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +15,7 @@ import (
 
 	"coinflip-game/internal/config"
 	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
 )
 
 // GameUI manages the main game interface
@@ -24,37 +24,54 @@ type GameUI struct {
 	app      fyne.App
 	window   fyne.Window
 	engine   *game.Engine
+	session  *game.Session
 	config   *config.Config
 	logger   *zap.Logger
 	playerID string
+	onHome   func()            // Set when opened from the home screen
+	bankroll *network.Bankroll // Set when practice shares the server wallet
 
 	// UI components
 	balanceLabel   *widget.Label
-	betAmountEntry *widget.Entry
+	streakLabel    *widget.Label
+	skin           string
+	betInput       *betInput
 	headsButton    *widget.Button
 	tailsButton    *widget.Button
+	insureCheck    *widget.Check
+	practiceCheck  *widget.Check
+	insuranceLabel *widget.Label
+	favorites      *favoritesBar
 	flipButton     *widget.Button
 	cancelButton   *widget.Button
 	resultLabel    *widget.Label
 	statusLabel    *widget.Label
-	historyList    *widget.List
+	history        *historyView
 	statsContainer *fyne.Container
 
 	// Game state
-	currentBet  *game.Bet
-	gameHistory []*game.Result
+	currentBet *game.Bet
+	balance    game.Money // As of the last refresh, for relative bet amounts
 }
 
 // NewGameUI creates a new game UI instance
 func NewGameUI(ctx context.Context, app fyne.App, engine *game.Engine, cfg *config.Config, logger *zap.Logger) *GameUI {
+	return newGameUI(ctx, app, engine, cfg, "gui_player", nil, logger)
+}
+
+// newGameUI creates a game UI for a player. With onHome set the window gets
+// a home button and closing it calls onHome instead of quitting.
+func newGameUI(ctx context.Context, app fyne.App, engine *game.Engine, cfg *config.Config, playerID string, onHome func(), logger *zap.Logger) *GameUI {
 	ui := &GameUI{
 		ctx:      ctx,
 		app:      app,
 		engine:   engine,
 		config:   cfg,
 		logger:   logger,
-		playerID: "gui_player",
+		playerID: playerID,
+		onHome:   onHome,
 	}
+	ui.session = engine.NewSession(ui.playerID)
 
 	ui.window = app.NewWindow("🪙 Coin Flip Game")
 	ui.setupUI()
@@ -73,24 +90,31 @@ func (ui *GameUI) setupUI() {
 	// Player info section
 	ui.balanceLabel = widget.NewLabel("Balance: $0.00")
 	ui.balanceLabel.TextStyle = fyne.TextStyle{Bold: true}
+	ui.streakLabel = widget.NewLabel("")
+	ui.streakLabel.TextStyle = fyne.TextStyle{Bold: true}
 
 	// Betting section
-	ui.betAmountEntry = widget.NewEntry()
-	ui.betAmountEntry.SetPlaceHolder("Enter bet amount...")
-	ui.betAmountEntry.Validator = func(s string) error {
-		if s == "" {
-			return nil // Allow empty for placeholder
-		}
-		amount, err := strconv.ParseFloat(s, 64)
-		if err != nil {
-			return fmt.Errorf("invalid number")
-		}
-		if amount < ui.config.Game.MinBet || amount > ui.config.Game.MaxBet {
-			return fmt.Errorf("bet must be between $%.2f and $%.2f",
-				ui.config.Game.MinBet, ui.config.Game.MaxBet)
-		}
-		return nil
+	ui.betInput = newBetInput(nil)
+	ui.betInput.entry.SetPlaceHolder("Enter bet amount...")
+
+	// Insurance is only offered when the game config enables it
+	ui.insureCheck = widget.NewCheck("☂️ Insure", nil)
+	ui.insuranceLabel = widget.NewLabel("")
+	ui.insuranceLabel.Wrapping = fyne.TextWrapWord
+	ui.betInput.onChanged = ui.updateInsuranceLabel
+	if !ui.engine.GetConfig().Insurance.Enabled() {
+		ui.insureCheck.Hide()
+		ui.insuranceLabel.Hide()
 	}
+	ui.updateInsuranceLabel()
+
+	// Practice bets resolve normally but leave the balance and stats alone
+	ui.practiceCheck = widget.NewCheck("🎯 Practice", nil)
+
+	// Saved bets, placed in one step
+	ui.favorites = newFavoritesBar(ui.config, ui.playerID, ui.window, ui.logger,
+		func() (string, bool) { return ui.betInput.Text(), ui.insureCheck.Checked },
+		ui.placeFavorite)
 
 	ui.headsButton = widget.NewButton("👑 Heads", func() {
 		ui.placeBet(game.Heads)
@@ -110,7 +134,11 @@ func (ui *GameUI) setupUI() {
 
 	bettingForm := container.NewVBox(
 		widget.NewLabel("💸 Place Your Bet"),
-		ui.betAmountEntry,
+		ui.betInput.content,
+		ui.favorites.content,
+		ui.insureCheck,
+		ui.insuranceLabel,
+		ui.practiceCheck,
 		container.NewGridWithColumns(2, ui.headsButton, ui.tailsButton),
 	)
 
@@ -131,51 +159,16 @@ func (ui *GameUI) setupUI() {
 		widget.NewLabel("📊 Statistics"),
 	)
 
-	// History section
-	ui.gameHistory = make([]*game.Result, 0)
-	ui.historyList = widget.NewList(
-		func() int {
-			return len(ui.gameHistory)
-		},
-		func() fyne.CanvasObject {
-			return container.NewHBox(
-				widget.NewLabel("Game"),
-				widget.NewLabel("Result"),
-				widget.NewLabel("Outcome"),
-			)
-		},
-		func(id widget.ListItemID, item fyne.CanvasObject) {
-			if id >= len(ui.gameHistory) {
-				return
-			}
-			result := ui.gameHistory[id]
-			cont := item.(*fyne.Container)
-
-			// Game info
-			gameLabel := cont.Objects[0].(*widget.Label)
-			gameLabel.SetText(fmt.Sprintf("#%d", len(ui.gameHistory)-id))
-
-			// Result
-			resultLabel := cont.Objects[1].(*widget.Label)
-			coinEmoji := "👑"
-			if result.Side == game.Tails {
-				coinEmoji = "🦅"
-			}
-			resultLabel.SetText(fmt.Sprintf("%s %s", coinEmoji, strings.ToUpper(string(result.Side))))
-
-			// Outcome
-			outcomeLabel := cont.Objects[2].(*widget.Label)
-			if result.Won {
-				outcomeLabel.SetText(fmt.Sprintf("✅ +$%.2f", result.Payout-result.Bet.Amount))
-			} else {
-				outcomeLabel.SetText(fmt.Sprintf("❌ -$%.2f", result.Bet.Amount))
-			}
-		},
-	)
+	// History section, searchable and loaded a page at a time
+	ui.history = newHistoryView(ui.ctx, ui.window, ui.engine,
+		func() string { return ui.skin },
+		func() string { return ui.config.UI.DataDir },
+		ui.logger)
 
 	// Layout
 	leftPanel := container.NewVBox(
 		ui.balanceLabel,
+		ui.streakLabel,
 		widget.NewSeparator(),
 		bettingForm,
 		widget.NewSeparator(),
@@ -183,36 +176,91 @@ func (ui *GameUI) setupUI() {
 		widget.NewSeparator(),
 		ui.resultLabel,
 		ui.statusLabel,
+		widget.NewButton("🎨 Coin Skins", ui.showSkinShop),
 	)
+	if ui.onHome != nil {
+		leftPanel.Add(widget.NewButton("🏠 Home", ui.onHome))
+		ui.window.SetCloseIntercept(ui.onHome)
+	}
 
-	rightPanel := container.NewVBox(
-		ui.statsContainer,
-		widget.NewSeparator(),
-		widget.NewLabel("📜 Recent Games"),
-		container.NewScroll(ui.historyList),
+	rightPanel := container.NewBorder(
+		container.NewVBox(
+			ui.statsContainer,
+			widget.NewSeparator(),
+			widget.NewLabel("📜 Game History"),
+		),
+		nil, nil, nil,
+		ui.history.content,
 	)
 
 	content := container.NewHSplit(leftPanel, rightPanel)
 	content.SetOffset(0.6) // 60% left, 40% right
 
 	ui.window.SetContent(content)
+	ui.setupShortcuts()
 	ui.updateButtonStates()
 }
 
+// setupShortcuts binds the configured keys to the betting buttons and labels
+// each button with its key. Disabled buttons ignore their key.
+func (ui *GameUI) setupShortcuts() {
+	keys := ui.config.UI.KeyBindings.WithDefaults()
+	ui.headsButton.SetText(keyHint("👑 Heads", keys.Heads))
+	ui.tailsButton.SetText(keyHint("🦅 Tails", keys.Tails))
+	ui.flipButton.SetText(keyHint("🎲 Flip Coin!", keys.Flip))
+	ui.cancelButton.SetText(keyHint("❌ Cancel Bet", keys.Cancel))
+
+	press := func(button *widget.Button) func() {
+		return func() {
+			if !button.Disabled() {
+				button.OnTapped()
+			}
+		}
+	}
+	bindShortcuts(ui.window, keys, shortcutActions{
+		heads:  press(ui.headsButton),
+		tails:  press(ui.tailsButton),
+		flip:   press(ui.flipButton),
+		cancel: press(ui.cancelButton),
+	})
+}
+
 // refreshPlayerInfo updates the player information display
 func (ui *GameUI) refreshPlayerInfo() {
-	player, err := ui.engine.GetPlayer(ui.ctx, ui.playerID)
+	player, err := ui.session.Player(ui.ctx)
 	if err != nil {
 		ui.logger.Error("Failed to get player info", zap.Error(err))
 		ui.statusLabel.SetText("Error loading player info")
 		return
 	}
 
-	ui.balanceLabel.SetText(fmt.Sprintf("💰 Balance: $%.2f", player.Balance))
+	ui.balance = player.Balance
+	ui.balanceLabel.SetText(fmt.Sprintf("💰 Balance: %s", player.Balance.Format()))
+	ui.betInput.entry.SetPlaceHolder(betPlaceholder(ui.config, player.Balance))
+	config := ui.engine.GetConfig()
+	ui.betInput.SetLimits(config.MinBet, config.MaxBet, player.Balance)
+	ui.skin = player.Inventory.EquippedSkin()
+	ui.streakLabel.SetText(streakText(player.Stats.CurrentStreak,
+		ui.engine.GetConfig().StreakMultiplier(player.Stats.CurrentStreak)))
 	ui.updateStats(&player.Stats)
 	ui.updateButtonStates()
 }
 
+// streakText describes a win streak and the multiplier the next win earns
+func streakText(streak int, next float64) string {
+	if streak == 0 {
+		return "🔥 Streak: 0"
+	}
+	text := fmt.Sprintf("🔥 Streak: %d win", streak)
+	if streak > 1 {
+		text += "s"
+	}
+	if next > 1 {
+		text += fmt.Sprintf(" (next win pays %.2fx)", next)
+	}
+	return text
+}
+
 // updateStats refreshes the statistics display
 func (ui *GameUI) updateStats(stats *game.Stats) {
 	ui.statsContainer.RemoveAll()
@@ -221,42 +269,52 @@ func (ui *GameUI) updateStats(stats *game.Stats) {
 	ui.statsContainer.Add(widget.NewLabel(fmt.Sprintf("Games: %d", stats.GamesPlayed)))
 	ui.statsContainer.Add(widget.NewLabel(fmt.Sprintf("Won: %d", stats.GamesWon)))
 	ui.statsContainer.Add(widget.NewLabel(fmt.Sprintf("Win Rate: %.1f%%", stats.WinRate)))
-	ui.statsContainer.Add(widget.NewLabel(fmt.Sprintf("Wagered: $%.2f", stats.TotalWagered)))
-	ui.statsContainer.Add(widget.NewLabel(fmt.Sprintf("Winnings: $%.2f", stats.TotalWinnings)))
-	ui.statsContainer.Add(widget.NewLabel(fmt.Sprintf("Net: $%.2f", stats.NetProfit)))
+	ui.statsContainer.Add(widget.NewLabel(fmt.Sprintf("Wagered: %s", stats.TotalWagered.Format())))
+	ui.statsContainer.Add(widget.NewLabel(fmt.Sprintf("Winnings: %s", stats.TotalWinnings.Format())))
+	ui.statsContainer.Add(widget.NewLabel(fmt.Sprintf("Net: %s", stats.NetProfit.Format())))
+	ui.statsContainer.Add(widget.NewLabel(fmt.Sprintf("Longest Streak: %d", stats.LongestStreak)))
+
+	if stats.GamesPlayed > 0 {
+		ui.statsContainer.Add(newDistributionChart("Your choices", stats.Choices))
+		ui.statsContainer.Add(newDistributionChart("Coin results", stats.Outcomes))
+	}
 }
 
 // updateButtonStates enables/disables buttons based on game state
 func (ui *GameUI) updateButtonStates() {
-	ui.currentBet = ui.engine.GetCurrentBet()
+	ui.currentBet = ui.session.CurrentBet()
 
 	hasBet := ui.currentBet != nil
-	validAmount := ui.betAmountEntry.Validate() == nil && ui.betAmountEntry.Text != ""
+	validAmount := ui.betInput.Valid()
 
 	// Disable betting buttons if we have an active bet
 	ui.headsButton.Enable()
 	ui.tailsButton.Enable()
-	ui.betAmountEntry.Enable()
+	ui.betInput.Enable()
+	ui.insureCheck.Enable()
+	ui.practiceCheck.Enable()
 
 	if hasBet {
 		ui.headsButton.Disable()
 		ui.tailsButton.Disable()
-		ui.betAmountEntry.Disable()
+		ui.betInput.Disable()
+		ui.insureCheck.Disable()
+		ui.practiceCheck.Disable()
 	}
 
 	// Enable/disable action buttons
 	if hasBet {
 		ui.flipButton.Enable()
 		ui.cancelButton.Enable()
-		ui.statusLabel.SetText(fmt.Sprintf("🎲 Bet placed: $%.2f on %s",
-			ui.currentBet.Amount, ui.currentBet.Choice))
+		ui.statusLabel.SetText(fmt.Sprintf("🎲 Bet placed: %s on %s",
+			ui.currentBet.Amount.Format(), ui.currentBet.Choice))
 	} else {
 		ui.flipButton.Disable()
 		ui.cancelButton.Disable()
 		if validAmount {
 			ui.statusLabel.SetText("🎯 Choose heads or tails")
 		} else {
-			ui.statusLabel.SetText("💸 Enter a valid bet amount")
+			ui.statusLabel.SetText("💸 Your balance is below the minimum bet")
 		}
 	}
 }
@@ -268,14 +326,25 @@ func (ui *GameUI) placeBet(choice game.Side) {
 		return
 	}
 
-	amountStr := ui.betAmountEntry.Text
-	amount, err := strconv.ParseFloat(amountStr, 64)
-	if err != nil {
-		dialog.ShowError(fmt.Errorf("invalid bet amount: %v", err), ui.window)
+	amount, ok := ui.betInput.Stake()
+	if !ok {
+		dialog.ShowInformation("Insufficient Balance",
+			fmt.Sprintf("You need at least %s to bet.", ui.engine.GetConfig().MinBet.Format()), ui.window)
 		return
 	}
 
-	bet, err := ui.engine.PlaceBet(ui.ctx, ui.playerID, amount, choice)
+	placeBet := ui.session.PlaceBet
+	if ui.insureCheck.Checked {
+		placeBet = ui.session.PlaceInsuredBet
+	}
+	if ui.practiceCheck.Checked {
+		if ui.insureCheck.Checked {
+			dialog.ShowError(game.ErrPracticeInsured, ui.window)
+			return
+		}
+		placeBet = ui.session.PlacePracticeBet
+	}
+	bet, err := placeBet(ui.ctx, amount, choice)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("failed to place bet: %v", err), ui.window)
 		return
@@ -283,14 +352,23 @@ func (ui *GameUI) placeBet(choice game.Side) {
 
 	ui.logger.Info("Bet placed via GUI",
 		zap.String("bet_id", bet.ID),
-		zap.Float64("amount", amount),
+		zap.Float64("amount", amount.Float64()),
 		zap.String("choice", choice.String()),
+		zap.Float64("premium", bet.Premium.Float64()),
+		zap.Bool("practice", bet.Practice),
 	)
 
 	ui.refreshPlayerInfo()
 	ui.resultLabel.SetText("🎲 Bet placed! Click 'Flip Coin' to play.")
 }
 
+// placeFavorite fills in a favorite bet and places it
+func (ui *GameUI) placeFavorite(favorite game.FavoriteBet) {
+	ui.betInput.SetText(favorite.Amount.String())
+	ui.insureCheck.SetChecked(favorite.Insured)
+	ui.placeBet(favorite.Choice)
+}
+
 // flipCoin executes the coin flip
 func (ui *GameUI) flipCoin() {
 	if ui.currentBet == nil {
@@ -307,7 +385,7 @@ func (ui *GameUI) flipCoin() {
 	go func() {
 		time.Sleep(1 * time.Second)
 
-		result, err := ui.engine.FlipCoin(ui.ctx, ui.playerID)
+		result, err := ui.session.FlipCoin(ui.ctx)
 		if err != nil {
 			fyne.CurrentApp().SendNotification(&fyne.Notification{
 				Title:   "Error",
@@ -319,8 +397,9 @@ func (ui *GameUI) flipCoin() {
 
 		// Update UI on main thread
 		ui.showResult(result)
-		ui.addToHistory(result)
+		ui.history.Reload()
 		ui.refreshPlayerInfo()
+		ui.recordBankroll()
 	}()
 }
 
@@ -330,7 +409,7 @@ func (ui *GameUI) cancelBet() {
 		return
 	}
 
-	err := ui.engine.CancelCurrentBet(ui.ctx, ui.playerID)
+	err := ui.session.CancelBet(ui.ctx)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("failed to cancel bet: %v", err), ui.window)
 		return
@@ -342,38 +421,44 @@ func (ui *GameUI) cancelBet() {
 
 // showResult displays the game result
 func (ui *GameUI) showResult(result *game.Result) {
-	coinEmoji := "👑"
-	if result.Side == game.Tails {
-		coinEmoji = "🦅"
-	}
-
-	resultText := fmt.Sprintf("%s %s", coinEmoji, strings.ToUpper(string(result.Side)))
+	resultText := fmt.Sprintf("%s %s", game.SkinFace(ui.skin, result.Side), strings.ToUpper(string(result.Side)))
 
 	if result.Won {
 		profit := result.Payout - result.Bet.Amount
-		ui.resultLabel.SetText(fmt.Sprintf("🎉 %s - You won $%.2f! (Profit: +$%.2f)",
-			resultText, result.Payout, profit))
+		ui.resultLabel.SetText(fmt.Sprintf("🎉 %s - You won %s! (Profit: %s)",
+			resultText, result.Payout.Format(), profit.FormatSigned()))
+		if result.Multiplier > 0 {
+			ui.resultLabel.SetText(ui.resultLabel.Text + fmt.Sprintf("\n🔥 Streak bonus: %.2fx payout", result.Multiplier))
+		}
 
 		// Show celebration notification
 		fyne.CurrentApp().SendNotification(&fyne.Notification{
 			Title:   "You Won!",
-			Content: fmt.Sprintf("Congratulations! You won $%.2f", result.Payout),
+			Content: fmt.Sprintf("Congratulations! You won %s", result.Payout.Format()),
 		})
 	} else {
-		ui.resultLabel.SetText(fmt.Sprintf("😞 %s - You lost $%.2f. Better luck next time!",
-			resultText, result.Bet.Amount))
+		ui.resultLabel.SetText(fmt.Sprintf("😞 %s - You lost %s. Better luck next time!",
+			resultText, (result.Bet.Cost() - result.Insurance).Format()))
+		if result.Insurance > 0 {
+			ui.resultLabel.SetText(ui.resultLabel.Text + fmt.Sprintf("\n☂️ Insurance refunded %s", result.Insurance.Format()))
+		}
 	}
 }
 
-// addToHistory adds a result to the game history
-func (ui *GameUI) addToHistory(result *game.Result) {
-	// Add to beginning of slice (most recent first)
-	ui.gameHistory = append([]*game.Result{result}, ui.gameHistory...)
-
-	// Keep only last 50 games for performance
-	if len(ui.gameHistory) > 50 {
-		ui.gameHistory = ui.gameHistory[:50]
+// updateInsuranceLabel prices insurance for the entered stake
+func (ui *GameUI) updateInsuranceLabel() {
+	config := ui.engine.GetConfig()
+	amount := ui.betInput.Amount()
+	if amount <= 0 {
+		amount = config.MinBet
 	}
+	ui.insuranceLabel.SetText(insuranceText(config.Insurance, config.PayoutRatio, amount))
+}
 
-	ui.historyList.Refresh()
+// insuranceText describes what insuring a stake costs, refunds and does to
+// the bet's expected value
+func insuranceText(insurance game.Insurance, payoutRatio float64, stake game.Money) string {
+	plain := game.ExpectedValue(stake.Float64(), payoutRatio)
+	return fmt.Sprintf("Pay %s to get %s back on a loss. Expected value: %+.2f, or %+.2f insured.",
+		insurance.Premium(stake).Format(), insurance.Refund(stake).Format(), plain, plain+insurance.ExpectedValue(stake.Float64()))
 }
@@ -0,0 +1,357 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+)
+
+// historyPageSize is how many results the history view loads at a time
+const historyPageSize = 25
+
+// historyDateLayout is how dates are entered in the history filters
+const historyDateLayout = "2006-01-02"
+
+// Filter choices offered by the history view
+const (
+	historyAnyOutcome = "All results"
+	historyWon        = "Won"
+	historyLost       = "Lost"
+	historyAnySide    = "Any side"
+)
+
+// historyView is a searchable game history. Results are read from the
+// repository a page at a time, loading more as the player scrolls down.
+type historyView struct {
+	ctx     context.Context
+	engine  *game.Engine
+	logger  *zap.Logger
+	window  fyne.Window   // Parent of the export file dialog
+	skin    func() string // The equipped coin skin, for drawing sides
+	dataDir func() string // Where the export file dialog opens
+
+	query   game.ResultQuery
+	results []*game.Result
+	total   int
+	loading bool
+
+	outcomeSelect *widget.Select
+	sideSelect    *widget.Select
+	minEntry      *widget.Entry
+	maxEntry      *widget.Entry
+	fromEntry     *widget.Entry
+	toEntry       *widget.Entry
+	sortButtons   map[game.ResultSort]*widget.Button
+	summary       *widget.Label
+	list          *widget.List
+
+	content fyne.CanvasObject
+}
+
+// newHistoryView creates the history view and loads its first page
+func newHistoryView(ctx context.Context, window fyne.Window, engine *game.Engine, skin, dataDir func() string, logger *zap.Logger) *historyView {
+	view := &historyView{
+		ctx:     ctx,
+		engine:  engine,
+		logger:  logger,
+		window:  window,
+		skin:    skin,
+		dataDir: dataDir,
+		query:   game.ResultQuery{Sort: game.SortByTime, Limit: historyPageSize},
+	}
+	view.setupUI()
+	view.Reload()
+	return view
+}
+
+// setupUI lays out the filters, the sortable column headers and the list
+func (view *historyView) setupUI() {
+	search := func(string) { view.Reload() }
+
+	// Selecting the defaults must not search before the list exists
+	view.outcomeSelect = widget.NewSelect([]string{historyAnyOutcome, historyWon, historyLost}, nil)
+	view.outcomeSelect.SetSelected(historyAnyOutcome)
+	view.outcomeSelect.OnChanged = search
+	view.sideSelect = widget.NewSelect([]string{historyAnySide, "Heads", "Tails"}, nil)
+	view.sideSelect.SetSelected(historyAnySide)
+	view.sideSelect.OnChanged = search
+
+	view.minEntry = newFilterEntry("Min $", search)
+	view.maxEntry = newFilterEntry("Max $", search)
+	view.fromEntry = newFilterEntry("From "+historyDateLayout, search)
+	view.toEntry = newFilterEntry("To "+historyDateLayout, search)
+
+	clearButton := widget.NewButton("Clear", view.clearFilters)
+	exportButton := widget.NewButton("💾 Export", view.export)
+
+	filters := container.NewVBox(
+		container.NewGridWithColumns(2, view.outcomeSelect, view.sideSelect),
+		container.NewGridWithColumns(2, view.minEntry, view.maxEntry),
+		container.NewGridWithColumns(2, view.fromEntry, view.toEntry),
+		container.NewGridWithColumns(3, widget.NewButton("🔍 Search", view.Reload), clearButton, exportButton),
+	)
+
+	view.sortButtons = map[game.ResultSort]*widget.Button{
+		game.SortByTime:   widget.NewButton("", func() { view.sortBy(game.SortByTime) }),
+		game.SortByAmount: widget.NewButton("", func() { view.sortBy(game.SortByAmount) }),
+		game.SortByPayout: widget.NewButton("", func() { view.sortBy(game.SortByPayout) }),
+	}
+	for _, button := range view.sortButtons {
+		button.Importance = widget.LowImportance
+	}
+	view.refreshSortButtons()
+
+	header := container.NewGridWithColumns(4,
+		view.sortButtons[game.SortByTime],
+		widget.NewLabel("Result"),
+		view.sortButtons[game.SortByAmount],
+		view.sortButtons[game.SortByPayout],
+	)
+
+	view.list = widget.NewList(
+		func() int {
+			return len(view.results)
+		},
+		func() fyne.CanvasObject {
+			return container.NewGridWithColumns(4,
+				widget.NewLabel("When"),
+				widget.NewLabel("Result"),
+				widget.NewLabel("Stake"),
+				widget.NewLabel("Outcome"),
+			)
+		},
+		view.updateItem,
+	)
+
+	view.summary = widget.NewLabel("")
+
+	view.content = container.NewBorder(
+		container.NewVBox(filters, header),
+		view.summary,
+		nil, nil,
+		view.list,
+	)
+}
+
+// newFilterEntry creates a filter entry that searches when submitted
+func newFilterEntry(placeholder string, search func(string)) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(placeholder)
+	entry.OnSubmitted = search
+	return entry
+}
+
+// updateItem fills in one history row, loading the next page when the
+// player scrolls near the end of the loaded results
+func (view *historyView) updateItem(id widget.ListItemID, item fyne.CanvasObject) {
+	if id >= len(view.results) {
+		return
+	}
+	result := view.results[id]
+	labels := item.(*fyne.Container).Objects
+
+	labels[0].(*widget.Label).SetText(result.Timestamp.Format("Jan 02 15:04"))
+	labels[1].(*widget.Label).SetText(fmt.Sprintf("%s %s", game.SkinFace(view.skin(), result.Side), strings.ToUpper(string(result.Side))))
+	if result.Bet == nil {
+		labels[2].(*widget.Label).SetText("-")
+		labels[3].(*widget.Label).SetText("-")
+	} else {
+		labels[2].(*widget.Label).SetText(fmt.Sprintf("%s on %s", result.Bet.Amount.Format(), strings.ToUpper(string(result.Bet.Choice))))
+		if result.Won {
+			labels[3].(*widget.Label).SetText(fmt.Sprintf("✅ %s", (result.Payout - result.Bet.Amount).FormatSigned()))
+		} else {
+			labels[3].(*widget.Label).SetText(fmt.Sprintf("❌ -%s", result.Bet.Amount.Format()))
+		}
+	}
+
+	// Refreshing the list from inside its own update is not allowed, so
+	// the next page is loaded once this update is done
+	if id >= len(view.results)-5 && len(view.results) < view.total && !view.loading {
+		view.loading = true
+		go fyne.Do(view.loadMore)
+	}
+}
+
+// Reload applies the current filters and loads the first page of results
+func (view *historyView) Reload() {
+	query, err := view.filterQuery()
+	if err != nil {
+		view.summary.SetText("⚠️ " + err.Error())
+		return
+	}
+
+	view.query = query
+	view.results = nil
+	view.total = 0
+	view.loading = true
+	view.loadMore()
+	view.list.ScrollToTop()
+}
+
+// loadMore appends the next page of results
+func (view *historyView) loadMore() {
+	defer func() { view.loading = false }()
+
+	query := view.query
+	query.Offset = len(view.results)
+	page, err := view.engine.QueryHistory(view.ctx, query)
+	if err != nil {
+		view.logger.Error("Failed to load game history", zap.Error(err))
+		view.summary.SetText("⚠️ Failed to load history")
+		return
+	}
+
+	view.results = append(view.results, page.Results...)
+	view.total = page.Total
+	view.summary.SetText(fmt.Sprintf("Showing %d of %d games", len(view.results), view.total))
+	view.list.Refresh()
+}
+
+// filterQuery builds a query from the filter widgets, keeping the sort
+func (view *historyView) filterQuery() (game.ResultQuery, error) {
+	query := game.ResultQuery{
+		Sort:      view.query.Sort,
+		Ascending: view.query.Ascending,
+		Limit:     historyPageSize,
+	}
+
+	switch view.outcomeSelect.Selected {
+	case historyWon:
+		won := true
+		query.Won = &won
+	case historyLost:
+		won := false
+		query.Won = &won
+	}
+	switch view.sideSelect.Selected {
+	case "Heads":
+		query.Choice = game.Heads
+	case "Tails":
+		query.Choice = game.Tails
+	}
+
+	var err error
+	if query.MinAmount, err = parseFilterAmount(view.minEntry.Text); err != nil {
+		return query, err
+	}
+	if query.MaxAmount, err = parseFilterAmount(view.maxEntry.Text); err != nil {
+		return query, err
+	}
+	if query.From, err = parseFilterDate(view.fromEntry.Text); err != nil {
+		return query, err
+	}
+	if query.To, err = parseFilterDate(view.toEntry.Text); err != nil {
+		return query, err
+	}
+	// The To date includes the whole day
+	if !query.To.IsZero() {
+		query.To = query.To.AddDate(0, 0, 1)
+	}
+
+	return query, nil
+}
+
+// parseFilterAmount parses an optional non-negative amount
+func parseFilterAmount(s string) (game.Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	amount, err := game.ParseMoney(s)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
+
+// parseFilterDate parses an optional local date
+func parseFilterDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.ParseInLocation(historyDateLayout, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("dates must look like %s", historyDateLayout)
+	}
+	return date, nil
+}
+
+// export saves every game matching the filters as CSV, in the order shown,
+// to a file the player picks
+func (view *historyView) export() {
+	query, err := view.filterQuery()
+	if err != nil {
+		view.summary.SetText("⚠️ " + err.Error())
+		return
+	}
+
+	fileName := fmt.Sprintf("coinflip-history-%s.csv", time.Now().Format(historyDateLayout))
+	showSaveFile(view.window, view.dataDir(), fileName, []string{".csv"}, func(writer fyne.URIWriteCloser) {
+		written, err := view.engine.ExportHistory(view.ctx, writer, query)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			view.logger.Error("Failed to export game history", zap.Error(err))
+			dialog.ShowError(err, view.window)
+			return
+		}
+		view.summary.SetText(fmt.Sprintf("💾 Exported %d games to %s", written, writer.URI().Name()))
+	})
+}
+
+// clearFilters resets every filter and shows all results again
+func (view *historyView) clearFilters() {
+	// Setting the selects would search once per widget
+	view.outcomeSelect.Selected = historyAnyOutcome
+	view.outcomeSelect.Refresh()
+	view.sideSelect.Selected = historyAnySide
+	view.sideSelect.Refresh()
+	for _, entry := range []*widget.Entry{view.minEntry, view.maxEntry, view.fromEntry, view.toEntry} {
+		entry.SetText("")
+	}
+	view.Reload()
+}
+
+// sortBy orders the history by a column, flipping the direction when it
+// is already sorted by that column
+func (view *historyView) sortBy(sort game.ResultSort) {
+	if view.query.Sort == sort {
+		view.query.Ascending = !view.query.Ascending
+	} else {
+		view.query.Sort = sort
+		view.query.Ascending = false
+	}
+	view.refreshSortButtons()
+	view.Reload()
+}
+
+// refreshSortButtons marks the sorted column and its direction
+func (view *historyView) refreshSortButtons() {
+	titles := map[game.ResultSort]string{
+		game.SortByTime:   "When",
+		game.SortByAmount: "Stake",
+		game.SortByPayout: "Outcome",
+	}
+	for sort, button := range view.sortButtons {
+		title := titles[sort]
+		if sort == view.query.Sort {
+			if view.query.Ascending {
+				title += " ▲"
+			} else {
+				title += " ▼"
+			}
+		}
+		button.SetText(title)
+	}
+}
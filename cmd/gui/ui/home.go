@@ -0,0 +1,313 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/config"
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/lan"
+	"coinflip-game/internal/network"
+)
+
+// Identity is the player both game modes play as
+type Identity struct {
+	ID   string
+	Name string
+}
+
+// NewIdentity creates the player identity for this run of the app: the
+// registered account when there is one, otherwise a fresh guest. It is
+// named after the configured player name when there is one.
+func NewIdentity(cfg *config.Config) Identity {
+	nano := time.Now().UnixNano()
+	identity := Identity{
+		ID:   game.NewGuestID(),
+		Name: fmt.Sprintf("Player%d", nano%10000), // Last 4 digits for readability
+	}
+	if cfg.UI.Account != "" {
+		identity.ID = cfg.UI.Account
+		identity.Name = cfg.UI.Account
+	}
+	if cfg.UI.PlayerName != "" {
+		identity.Name = cfg.UI.PlayerName
+	}
+	return identity
+}
+
+// HomeUI is the start screen that switches between practice against the
+// local engine and online play. Both modes share the configuration and
+// player identity; leaving a mode returns here instead of quitting.
+type HomeUI struct {
+	ctx      context.Context
+	app      fyne.App
+	window   fyne.Window
+	engine   *game.Engine
+	config   *config.Config
+	logger   *zap.Logger
+	identity Identity
+	tray     *Tray
+	presence *Presence
+
+	// bankroll shares the practice balance with the server wallet; nil
+	// unless wallet sync is on
+	bankroll *network.Bankroll
+
+	playerLabel   *widget.Label
+	practiceLabel *widget.Label
+	walletLabel   *widget.Label
+
+	// The practice game is kept between visits; online play is closed on
+	// leaving so the player leaves their room
+	practice *GameUI
+	online   *MultiplayerGameUI
+	// host is the local network game this app serves, while hosting
+	host *lan.Host
+
+	// current is the window the tray brings back
+	current fyne.Window
+}
+
+// NewHomeUI creates the home screen. engine runs practice games.
+func NewHomeUI(ctx context.Context, app fyne.App, engine *game.Engine, cfg *config.Config, logger *zap.Logger) *HomeUI {
+	home := &HomeUI{
+		ctx:      ctx,
+		app:      app,
+		engine:   engine,
+		config:   cfg,
+		logger:   logger,
+		identity: NewIdentity(cfg),
+		tray:     NewTray(app, cfg),
+		presence: NewPresence(ctx, cfg, logger),
+	}
+
+	home.window = app.NewWindow("🪙 Coin Flip")
+	home.window.SetMaster()
+	home.current = home.window
+	home.setupBankroll()
+	home.setupUI()
+	home.refresh()
+	home.syncWallet(nil)
+	home.offerTutorial()
+
+	// With a tray icon, closing the home window keeps the app running
+	if home.tray.Install(home.showFromTray) {
+		home.window.SetCloseIntercept(home.window.Hide)
+	}
+
+	return home
+}
+
+// GetWindow returns the home window
+func (home *HomeUI) GetWindow() fyne.Window {
+	return home.window
+}
+
+// setupUI lays out the mode selector
+func (home *HomeUI) setupUI() {
+	title := widget.NewLabel("🪙 Coin Flip")
+	title.Alignment = fyne.TextAlignCenter
+	title.TextStyle = fyne.TextStyle{Bold: true}
+
+	home.playerLabel = widget.NewLabel("")
+	home.playerLabel.Alignment = fyne.TextAlignCenter
+
+	home.practiceLabel = widget.NewLabel("")
+	home.practiceLabel.Alignment = fyne.TextAlignCenter
+
+	home.walletLabel = widget.NewLabel("")
+	home.walletLabel.Alignment = fyne.TextAlignCenter
+	if home.bankroll == nil {
+		home.walletLabel.Hide()
+	}
+
+	practiceButton := widget.NewButton("🎯 Practice offline", home.startPractice)
+	practiceButton.Importance = widget.HighImportance
+	onlineButton := widget.NewButton("🌐 Play online", home.startOnline)
+	onlineButton.Importance = widget.HighImportance
+
+	server := widget.NewLabel(fmt.Sprintf("Server: %s:%d",
+		home.config.Multiplayer.ServerHost, home.config.Multiplayer.ServerPort))
+	server.Alignment = fyne.TextAlignCenter
+
+	hostButton := widget.NewButton("🏠 Host a LAN game", home.hostLAN)
+	findButton := widget.NewButton("📡 Find LAN games", home.findLAN)
+
+	tutorialButton := widget.NewButton("❓ Tutorial", home.showTutorial)
+
+	home.window.SetContent(container.NewCenter(container.NewVBox(
+		title,
+		home.playerLabel,
+		widget.NewSeparator(),
+		practiceButton,
+		home.practiceLabel,
+		home.walletLabel,
+		widget.NewSeparator(),
+		onlineButton,
+		server,
+		container.NewGridWithColumns(2, hostButton, findButton),
+		widget.NewSeparator(),
+		tutorialButton,
+	)))
+	home.window.Resize(fyne.NewSize(420, 320))
+}
+
+// refresh shows who is playing and the practice balance, which carries
+// over between visits and, with wallet sync, to online play
+func (home *HomeUI) refresh() {
+	if game.IsGuest(home.identity.ID) {
+		home.playerLabel.SetText("Playing as " + home.identity.Name + " (guest)")
+	} else {
+		home.playerLabel.SetText("Playing as " + home.identity.Name + " · 👤 " + home.identity.ID)
+	}
+
+	player, err := home.engine.GetPlayer(home.ctx, home.identity.ID)
+	if err != nil {
+		home.logger.Warn("Failed to load practice balance", zap.Error(err))
+		home.practiceLabel.SetText("Play against the computer")
+		return
+	}
+	text := fmt.Sprintf("Play against the computer · 💰 %s", player.Balance.Format())
+	if home.bankroll != nil {
+		if pending := home.bankroll.Pending(); pending != 0 {
+			text += fmt.Sprintf(" (%s not synced)", pending.FormatSigned())
+		}
+	}
+	home.practiceLabel.SetText(text)
+}
+
+// startPractice opens the practice game, resuming it if it was open before
+func (home *HomeUI) startPractice() {
+	if home.practice == nil {
+		home.practice = newGameUI(home.ctx, home.app, home.engine, home.config, home.identity.ID, home.showHome, home.logger)
+		home.practice.bankroll = home.bankroll
+	}
+	home.openMode(home.practice.GetWindow())
+	home.practice.refreshPlayerInfo()
+}
+
+// startOnline connects to the multiplayer server in a fresh online game,
+// first bringing practice play to the server wallet it joins rooms with
+func (home *HomeUI) startOnline() {
+	home.syncWallet(func() {
+		home.online = newMultiplayerGameUI(home.ctx, home.app, home.config, home.identity, home.tray, home.presence, nil, home.showHome, home.logger)
+		home.openMode(home.online.GetWindow())
+	})
+}
+
+// openMode swaps the home window for a game window. With a tray icon,
+// closing the game window hides it so an online game keeps running.
+func (home *HomeUI) openMode(window fyne.Window) {
+	if home.tray.Installed() {
+		window.SetCloseIntercept(window.Hide)
+	}
+
+	window.Resize(fyne.NewSize(float32(home.config.UI.WindowWidth), float32(home.config.UI.WindowHeight)))
+	window.CenterOnScreen()
+	window.Show()
+	home.window.Hide()
+	home.current = window
+}
+
+// showHome leaves the current mode and returns to the home screen
+func (home *HomeUI) showHome() {
+	if home.practice != nil {
+		home.practice.GetWindow().Hide()
+	}
+	if home.online != nil {
+		home.online.Close()
+		home.online = nil
+	}
+	home.stopHosting()
+
+	home.adoptAccount()
+	home.refresh()
+	home.syncWallet(nil)
+	home.window.Show()
+	home.current = home.window
+	home.offerTutorial()
+}
+
+// offerTutorial shows the tutorial on first run, or after it was turned
+// back on in the settings
+func (home *HomeUI) offerTutorial() {
+	if home.config.UI.ShowTutorial {
+		home.showTutorial()
+	}
+}
+
+// showTutorial walks the player through the game over the home screen
+func (home *HomeUI) showTutorial() {
+	ShowTutorial(home.window, home.config, home.finishTutorial)
+}
+
+// finishTutorial stops the tutorial opening on its own once it was seen
+func (home *HomeUI) finishTutorial() {
+	if !home.config.UI.ShowTutorial {
+		return
+	}
+
+	updated := *home.config
+	updated.UI.ShowTutorial = false
+	if err := updated.Save(home.config.Path()); err != nil {
+		home.logger.Warn("Failed to save tutorial progress", zap.Error(err))
+	}
+	*home.config = updated
+}
+
+// adoptAccount switches to an account the guest registered while playing
+// online, moving their practice balance, stats and history to it
+func (home *HomeUI) adoptAccount() {
+	account := home.config.UI.Account
+	if account == "" || !game.IsGuest(home.identity.ID) {
+		return
+	}
+
+	// The practice session plays as the guest, so any bet is refunded and
+	// the session starts over as the account
+	if home.practice != nil {
+		if home.practice.session.CurrentBet() != nil {
+			if err := home.practice.session.CancelBet(home.ctx); err != nil {
+				home.logger.Warn("Failed to refund practice bet", zap.Error(err))
+			}
+		}
+		home.practice.GetWindow().Close()
+		home.practice = nil
+	}
+
+	if _, err := home.engine.Register(home.ctx, home.identity.ID, account); err != nil {
+		home.logger.Warn("Practice progress stays with the guest",
+			zap.String("account", account),
+			zap.Error(err),
+		)
+	}
+
+	home.identity.ID = account
+	if home.bankroll != nil {
+		if err := home.bankroll.SetPlayer(account); err != nil {
+			home.logger.Warn("Failed to move cached balance to the account", zap.Error(err))
+		}
+	}
+	if home.config.UI.PlayerName == "" {
+		home.identity.Name = account
+	}
+}
+
+// Raise brings the app's window to the front when the GUI is launched
+// again with the same profile. It may be called from any goroutine.
+func (home *HomeUI) Raise() {
+	fyne.Do(home.showFromTray)
+}
+
+// showFromTray brings back the window that was open when the app was
+// hidden to the tray
+func (home *HomeUI) showFromTray() {
+	home.current.Show()
+	home.current.RequestFocus()
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/network"
+)
+
+// handleIdle warns the player before the room marks them idle or gives
+// their seat away for not betting, and tells them once it has
+func (ui *MultiplayerGameUI) handleIdle(msg *network.Message) {
+	if msg.PlayerID != ui.playerID {
+		return
+	}
+
+	var idle network.IdleData
+	if err := msg.GetData(&idle); err != nil {
+		ui.logger.Error("Failed to parse idle warning", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		ui.appendChat(idleText(idle))
+	})
+}
+
+// idleText describes an idle warning or step to the player it concerns
+func idleText(idle network.IdleData) string {
+	switch {
+	case idle.Step == network.IdleStepIdle && idle.RoundsLeft > 0:
+		return "💤 Bet this round or you will be marked idle"
+	case idle.Step == network.IdleStepIdle:
+		return "💤 You are idle and no longer count towards starting rounds. Bet to come back"
+	case idle.RoundsLeft > 0:
+		return "💤 Bet this round or your seat goes to the next player in line"
+	default:
+		return "💤 Your seat went to the next player in line. Join again to play"
+	}
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/lan"
+	"coinflip-game/internal/network"
+)
+
+// defaultLANRoom is the room a hosted game plays in when no default room
+// is configured
+const defaultLANRoom = "lan"
+
+// serverURL is the server the online game connects to: the local network
+// game when playing one, otherwise the configured server
+func (ui *MultiplayerGameUI) serverURL() string {
+	if ui.lanGame != nil {
+		return ui.lanGame.URL()
+	}
+	return fmt.Sprintf("ws://%s:%d/ws", ui.config.Multiplayer.ServerHost, ui.config.Multiplayer.ServerPort)
+}
+
+// autoJoinRoom is the room joined on connecting, "" for none. A local
+// network game always joins its host's room.
+func (ui *MultiplayerGameUI) autoJoinRoom() string {
+	if ui.lanGame != nil {
+		return ui.lanGame.Room
+	}
+	if ui.config.Multiplayer.AutoJoin {
+		return ui.config.Multiplayer.DefaultRoom
+	}
+	return ""
+}
+
+// hostLAN serves a game from this app and announces it on the local
+// network, then joins it like any other player
+func (home *HomeUI) hostLAN() {
+	room := home.config.Multiplayer.DefaultRoom
+	if room == "" {
+		room = defaultLANRoom
+	}
+
+	// The hosted game keeps nothing once the app closes, and serves its
+	// players but no operators
+	serverConfig := home.config.ToServerConfig()
+	serverConfig.Port = 0
+	serverConfig.SnapshotFile = ""
+	serverConfig.Features = network.Features{WebSocket: true, REST: true}
+
+	host, err := lan.StartHost(serverConfig, home.identity.Name+"'s game", room, home.logger)
+	if err != nil {
+		dialog.ShowError(err, home.window)
+		return
+	}
+	home.host = host
+	home.playLAN(host.Game())
+}
+
+// stopHosting ends the hosted game, if any, disconnecting its players
+func (home *HomeUI) stopHosting() {
+	if home.host == nil {
+		return
+	}
+	home.host.Stop()
+	home.host = nil
+}
+
+// findLAN looks for games hosted on the local network and offers to join
+// one
+func (home *HomeUI) findLAN() {
+	searching := dialog.NewCustomWithoutButtons("📡 Find LAN games",
+		widget.NewLabel("Looking for games on your network..."), home.window)
+	searching.Show()
+
+	go func() {
+		games, err := lan.Browse(home.ctx, lan.DefaultBrowseTimeout)
+		fyne.Do(func() {
+			searching.Hide()
+			if err != nil {
+				home.logger.Warn("Failed to browse for LAN games", zap.Error(err))
+				dialog.ShowError(fmt.Errorf("failed to look for LAN games: %w", err), home.window)
+				return
+			}
+			home.showLANGames(games)
+		})
+	}()
+}
+
+// showLANGames lists the games found on the local network, each with a
+// button to join it
+func (home *HomeUI) showLANGames(games []lan.Game) {
+	if len(games) == 0 {
+		dialog.ShowInformation("📡 Find LAN games",
+			"No games found. Ask a player on your network to host one.", home.window)
+		return
+	}
+
+	list := container.NewVBox()
+	var picker dialog.Dialog
+	for _, game := range games {
+		join := widget.NewButton("Join", func() {
+			picker.Hide()
+			home.playLAN(game)
+		})
+		label := widget.NewLabel(fmt.Sprintf("%s · %s", game.Name, game.Addr))
+		list.Add(container.NewBorder(nil, nil, nil, join, label))
+	}
+	picker = dialog.NewCustom("📡 LAN games", "Cancel", list, home.window)
+	picker.Show()
+}
+
+// playLAN opens online play in a local network game
+func (home *HomeUI) playLAN(game lan.Game) {
+	home.online = newMultiplayerGameUI(home.ctx, home.app, home.config, home.identity, home.tray, home.presence, &game, home.showHome, home.logger)
+	home.openMode(home.online.GetWindow())
+}
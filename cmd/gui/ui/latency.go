@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// slowLatency is the round trip time from which a connection is flagged as
+// lagging
+const slowLatency = 250 * time.Millisecond
+
+// latencyText describes a round trip time, flagging slow connections, or
+// returns "" before one was measured
+func latencyText(latency time.Duration) string {
+	switch {
+	case latency <= 0:
+		return ""
+	case latency >= slowLatency:
+		return fmt.Sprintf("🐢 %dms", latency.Milliseconds())
+	default:
+		return fmt.Sprintf("📶 %dms", latency.Milliseconds())
+	}
+}
+
+// watchLatency shows the measured round trip to the server in the status
+// bar, refreshed every second until the UI closes
+func (ui *MultiplayerGameUI) watchLatency() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ui.ctx.Done():
+			return
+		case <-ticker.C:
+			// The client is replaced on reconnect, so read it on the main thread
+			ui.queueUIUpdate(func() {
+				latency := time.Duration(0)
+				if ui.networkClient.IsConnected() {
+					latency = ui.networkClient.Latency()
+				}
+				ui.latencyLabel.SetText(latencyText(latency))
+			})
+		}
+	}
+}
@@ -4,7 +4,6 @@ package ui
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
@@ -16,7 +15,9 @@ import (
 
 	"coinflip-game/internal/config"
 	"coinflip-game/internal/game"
+	"coinflip-game/internal/lan"
 	"coinflip-game/internal/network"
+	"coinflip-game/internal/storage"
 )
 
 // UIUpdate represents a UI update to be executed on the main thread
@@ -26,55 +27,146 @@ type UIUpdate struct {
 
 // PlayerStats tracks comprehensive player statistics
 type PlayerStats struct {
+	PlayerID      string
 	PlayerName    string
 	TotalGames    int
 	GamesWon      int
 	GamesLost     int
-	TotalBet      float64
-	TotalWon      float64
-	NetProfit     float64
-	CurrentBalance float64
+	TotalBet      game.Money
+	TotalWon      game.Money
+	NetProfit     game.Money
+	BiggestWin    game.Money
+	Choices       game.Distribution
+	Outcomes      game.Distribution
+	CurrentBalance game.Money
 	LastSeen      time.Time
+	Rating        *game.DuelRating // Duel rating, from the server's stats
+}
+
+// pendingBet is a bet held locally during the undo window before it is sent
+type pendingBet struct {
+	amount   game.Money
+	choice   game.Side
+	insured  bool
+	timer    *time.Timer
 }
 
 // MultiplayerGameUI manages the multiplayer game interface
 type MultiplayerGameUI struct {
 	ctx          context.Context
+	cancel       context.CancelFunc
+	onHome       func() // Set when opened from the home screen
+	tray         *Tray  // Notifies about the game while it is in the background
+	presence     *Presence // Shows the game on the player's Discord profile
 	app          fyne.App
 	window       fyne.Window
 	config       *config.Config
 	logger       *zap.Logger
 	networkClient *network.NetworkClient
+	networkStop   chan struct{}
+	// lanGame is the local network game played instead of the configured
+	// server, if any
+	lanGame       *lan.Game
 	
 	// Player info
 	playerID     string
 	playerName   string
-	balance      float64
+	balance      game.Money
+	// Place in line for a seat while watching a full room, 0 once seated
+	queuePosition int
+	
+	// Coin skins: the equipped skin and, once the shop was opened, the
+	// player's whole inventory
+	skin         string
+	inventory    game.Inventory
+	skinShop     dialog.Dialog
+	
+	// Friend list, while it is open
+	friendList   dialog.Dialog
+	replay       *replayViewer // Replay of the room's last round, while open
 	
 	// UI components
 	connectionStatus *widget.Label
+	latencyLabel     *widget.Label // Round trip to the server
 	roomInfo         *widget.Label
+	registerButton   *widget.Button // Shown while playing as a guest
+	pauseButton      *widget.Button // Pause, or resume once paused
 	playersList      *widget.List
 	timerLabel       *widget.Label
 	progressBar      *widget.ProgressBar
 	
-	betAmountEntry   *widget.Entry
+	betInput         *betInput
+	quickBetsBox     *fyne.Container
+	favorites        *favoritesBar
+	notes            *storage.NoteBook // Private notes on other players
 	headsButton      *widget.Button
 	tailsButton      *widget.Button
+	cancelBetButton  *widget.Button
+	positionsLabel   *widget.Label
+	
+	// Why the server refused the last bet, with a suggested fix
+	betErrorLabel    *widget.Label
+	betFixButton     *widget.Button
+	betErrorBox      *fyne.Container
+	
+	// Bet insurance, offered only in rooms that enable it
+	insureCheck      *widget.Check
+	insuranceLabel   *widget.Label
+	insurance        game.Insurance
+	payoutRatio      float64
+	
+	// Happy hour boosting the room's payouts, if any
+	promotionLabel   *widget.Label
+	promotion        *network.Promotion
+	
+	// Bet held by the server for the next round while betting is closed
+	queuedBet        *network.BetData
+	// Bets staged one per upcoming round
+	betSlip          *betSlip
+	
+	// Bet confirmation window
+	pendingBet       *pendingBet
+	pendingLabel     *widget.Label
+	pendingBox       *fyne.Container
+	confirmButton    *widget.Button
+	undoButton       *widget.Button
 	
 	gameResult       *widget.Label
 	chatMessages     *widget.List
 	chatEntry        *widget.Entry
+	chatLog          []string
+	chatQueue        []string
+	
+	// Offline mode while the server is unreachable
+	offline            bool
+	offlineLabel       *widget.Label
+	offlineBox         *fyne.Container
+	retryCountdownStop chan struct{}
 	
 	// History/Scoreboard components
 	historyList      *widget.List
 	scoreboardList   *widget.List
+	scoreboardHeader *widget.Label // Titles the scoreboard with the season rank
+	distributionBox  *fyne.Container
+	
+	// Room stats: how the room has played since it opened
+	roomStatsLabels  []*widget.Label
+	
+	// My bets: the player's own bets since joining the room
+	sessionBetsList  *widget.List
+	sessionNetLabel  *widget.Label
+	sessionBets      []sessionBet
+	sessionRounds    int
 	
 	// Room state
 	currentPlayers   []network.PlayerInfo
+	maxPlayers       int
 	gameState        network.GameState
 	timerSeconds     int
 	totalSeconds     int
+	// pausedBy is the player who paused the room, empty when it is waiting
+	// for players
+	pausedBy         string
 	
 	// Game history and player statistics
 	gameHistory      []*network.GameResultData
@@ -86,16 +178,28 @@ type MultiplayerGameUI struct {
 
 // NewMultiplayerGameUI creates a new multiplayer game UI
 func NewMultiplayerGameUI(ctx context.Context, app fyne.App, cfg *config.Config, logger *zap.Logger) *MultiplayerGameUI {
-	// Generate unique player ID and name with suffix
-	playerIDNano := time.Now().UnixNano()
+	return newMultiplayerGameUI(ctx, app, cfg, NewIdentity(cfg), NewTray(app, cfg), NewPresence(ctx, cfg, logger), nil, nil, logger)
+}
+
+// newMultiplayerGameUI creates a multiplayer UI for a player. With onHome set
+// the window gets a home button and closing it calls onHome instead of
+// quitting. With lanGame set it plays that local network game instead of
+// connecting to the configured server.
+func newMultiplayerGameUI(ctx context.Context, app fyne.App, cfg *config.Config, identity Identity, tray *Tray, presence *Presence, lanGame *lan.Game, onHome func(), logger *zap.Logger) *MultiplayerGameUI {
+	ctx, cancel := context.WithCancel(ctx)
 	ui := &MultiplayerGameUI{
 		ctx:          ctx,
+		cancel:       cancel,
+		onHome:       onHome,
+		lanGame:      lanGame,
+		tray:         tray,
+		presence:     presence,
 		app:          app,
 		config:       cfg,
 		logger:       logger,
-		playerID:     fmt.Sprintf("player_%d", playerIDNano),
-		playerName:   fmt.Sprintf("Player%d", playerIDNano%10000), // Last 4 digits for readability
-		balance:      cfg.Game.StartingBalance,
+		playerID:     identity.ID,
+		playerName:   identity.Name,
+		balance:      game.NewMoney(cfg.Game.StartingBalance),
 		gameHistory:  make([]*network.GameResultData, 0),
 		playerStats:  make(map[string]*PlayerStats),
 		uiUpdateChan: make(chan UIUpdate, 100), // Buffered channel for UI updates
@@ -105,8 +209,17 @@ func NewMultiplayerGameUI(ctx context.Context, app fyne.App, cfg *config.Config,
 	ui.setupNetworking()
 	ui.setupUI()
 	
+	// Accepting an invite in Discord moves the player to its room
+	ui.presence.SetJoinHandler(func(roomID string) {
+		ui.queueUIUpdate(func() {
+			ui.switchRoom(roomID)
+		})
+	})
+	ui.updatePresence()
+	
 	// Start UI update processor on main thread
 	go ui.processUIUpdates()
+	go ui.watchLatency()
 	
 	return ui
 }
@@ -144,42 +257,79 @@ func (ui *MultiplayerGameUI) queueUIUpdate(updateFunc func()) {
 func (ui *MultiplayerGameUI) setupNetworking() {
 	// Start with default configuration to avoid zero values
 	clientConfig := network.DefaultClientConfig()
-	// Override only the server URL
-	clientConfig.ServerURL = fmt.Sprintf("ws://%s:%d/ws", 
-		ui.config.Multiplayer.ServerHost, 
-		ui.config.Multiplayer.ServerPort)
+	// Override the server URL and wire format preferences
+	clientConfig.ServerURL = ui.serverURL()
+	clientConfig.Encoding = network.Encoding(ui.config.Multiplayer.Encoding)
+	clientConfig.EnableCompression = ui.config.Multiplayer.Compression
 	
 	ui.networkClient = network.NewNetworkClient(clientConfig, ui.playerID, ui.playerName, ui.logger)
 	
 	// Set up message handlers
 	ui.setupMessageHandlers()
+	ui.networkClient.SetConnectionHandler(ui.handleConnectionEvent)
 	
 	// Start event processing
-	go ui.processNetworkEvents()
+	ui.networkStop = make(chan struct{})
+	go ui.processNetworkEvents(ui.networkClient, ui.networkStop)
 }
 
-// setupMessageHandlers sets up handlers for network messages
+// setupMessageHandlers adds the UI's handlers for network messages,
+// alongside the client's own
 func (ui *MultiplayerGameUI) setupMessageHandlers() {
-	ui.networkClient.SetMessageHandler(network.MsgRoomUpdate, ui.handleRoomUpdate)
-	ui.networkClient.SetMessageHandler(network.MsgTimerUpdate, ui.handleTimerUpdate)
-	ui.networkClient.SetMessageHandler(network.MsgGameResult, ui.handleGameResult)
-	ui.networkClient.SetMessageHandler(network.MsgBetPhase, ui.handleBetPhase)
-	ui.networkClient.SetMessageHandler(network.MsgError, ui.handleError)
+	ui.networkClient.AddMessageHandler(network.MsgRoomUpdate, ui.handleRoomUpdate)
+	ui.networkClient.AddMessageHandler(network.MsgTimerUpdate, ui.handleTimerUpdate)
+	ui.networkClient.AddMessageHandler(network.MsgGameResult, ui.handleGameResult)
+	ui.networkClient.AddMessageHandler(network.MsgBetPhase, ui.handleBetPhase)
+	ui.networkClient.AddMessageHandler(network.MsgError, ui.handleError)
+	ui.networkClient.AddMessageHandler(network.MsgPlayerStats, ui.handlePlayerStats)
+	ui.networkClient.AddMessageHandler(network.MsgRoundCancelled, ui.handleRoundCancelled)
+	ui.networkClient.AddMessageHandler(network.MsgRoomClosed, ui.handleRoomClosed)
+	ui.networkClient.AddMessageHandler(network.MsgBetPlaced, ui.handleBetPlaced)
+	ui.networkClient.AddMessageHandler(network.MsgCancelBet, ui.handleBetCancelled)
+	ui.networkClient.AddMessageHandler(network.MsgUpdateBet, ui.handleBetUpdated)
+	ui.networkClient.AddMessageHandler(network.MsgQueuedBet, ui.handleQueuedBet)
+	ui.networkClient.AddMessageHandler(network.MsgBetSlip, ui.handleBetSlip)
+	ui.networkClient.AddMessageHandler(network.MsgConfigProposal, ui.handleConfigProposal)
+	ui.networkClient.AddMessageHandler(network.MsgConfigVote, ui.handleConfigVote)
+	ui.networkClient.AddMessageHandler(network.MsgConfigChanged, ui.handleConfigChanged)
+	ui.networkClient.AddMessageHandler(network.MsgPauseProposal, ui.handlePauseProposal)
+	ui.networkClient.AddMessageHandler(network.MsgPauseVote, ui.handlePauseVote)
+	ui.networkClient.AddMessageHandler(network.MsgChat, ui.handleChat)
+	ui.networkClient.AddMessageHandler(network.MsgInventory, ui.handleSkinReply)
+	ui.networkClient.AddMessageHandler(network.MsgBuySkin, ui.handleSkinReply)
+	ui.networkClient.AddMessageHandler(network.MsgEquipSkin, ui.handleSkinReply)
+	ui.networkClient.AddMessageHandler(network.MsgRegister, ui.handleRegistered)
+	ui.networkClient.AddMessageHandler(network.MsgFriends, ui.handleFriendsReply)
+	ui.networkClient.AddMessageHandler(network.MsgAddFriend, ui.handleFriendsReply)
+	ui.networkClient.AddMessageHandler(network.MsgRemoveFriend, ui.handleFriendsReply)
+	ui.networkClient.AddMessageHandler(network.MsgRoomInvite, ui.handleRoomInvite)
+	ui.networkClient.AddMessageHandler(network.MsgCreateRoom, ui.handleRoomCreated)
+	ui.networkClient.AddMessageHandler(network.MsgFindDuel, ui.handleDuelFound)
+	ui.networkClient.AddMessageHandler(network.MsgReplayRound, ui.handleReplayStarted)
+	ui.networkClient.AddMessageHandler(network.MsgReplayEvent, ui.handleReplayEvent)
+	ui.networkClient.AddMessageHandler(network.MsgReplayEnd, ui.handleReplayEnd)
+	ui.networkClient.AddMessageHandler(network.MsgSeasonUpdate, ui.handleSeasonUpdate)
+	ui.networkClient.AddMessageHandler(network.MsgJoinRoom, ui.handleSeatName)
+	ui.networkClient.AddMessageHandler(network.MsgPromotion, ui.handlePromotion)
+	ui.networkClient.AddMessageHandler(network.MsgIdle, ui.handleIdle)
+	ui.networkClient.AddMessageHandler(network.MsgRoomStats, ui.handleRoomStats)
 }
 
-// processNetworkEvents processes network events
-func (ui *MultiplayerGameUI) processNetworkEvents() {
+// processNetworkEvents processes network events from client until stop is closed
+func (ui *MultiplayerGameUI) processNetworkEvents(client *network.NetworkClient, stop <-chan struct{}) {
 	for {
 		select {
 		case <-ui.ctx.Done():
 			return
-		case err := <-ui.networkClient.GetErrorChannel():
+		case <-stop:
+			return
+		case err := <-client.GetErrorChannel():
 			ui.logger.Error("Network error", zap.Error(err))
 			// Queue UI update to be executed on main thread
 			ui.queueUIUpdate(func() {
 				ui.connectionStatus.SetText("❌ Disconnected: " + err.Error())
 			})
-		case <-ui.networkClient.GetEventChannel():
+		case <-client.GetEventChannel():
 			// Events are handled by specific handlers
 		}
 	}
@@ -189,10 +339,31 @@ func (ui *MultiplayerGameUI) processNetworkEvents() {
 func (ui *MultiplayerGameUI) setupUI() {
 	// Minimal connection status (no manual buttons - auto-connects)
 	ui.connectionStatus = widget.NewLabel("🔄 Connecting...")
+	ui.latencyLabel = widget.NewLabel("")
 	ui.roomInfo = widget.NewLabel("Not in room")
 	
+	settingsButton := widget.NewButton("⚙️ Settings", ui.showSettings)
+	proposeButton := widget.NewButton("🗳️ Room Vote", ui.showProposeSettings)
+	skinsButton := widget.NewButton("🎨 Skins", ui.showSkinShop)
+	friendsButton := widget.NewButton("👥 Friends", ui.showFriends)
+	replayButton := widget.NewButton("⏪ Last Round", ui.showLastRound)
+	notesButton := widget.NewButton("📝 Notes", ui.showNotes)
+	createButton := widget.NewButton("➕ Create Room", ui.showCreateRoom)
+	duelButton := widget.NewButton("⚔️ Find Duel", ui.findDuel)
+	ui.registerButton = widget.NewButton("👤 Register", ui.showRegister)
+	if !game.IsGuest(ui.playerID) {
+		ui.registerButton.Hide()
+	}
+	ui.pauseButton = widget.NewButton("⏸️ Pause", ui.togglePause)
+	toolbar := container.NewHBox(ui.registerButton, createButton, duelButton, friendsButton, notesButton, replayButton, skinsButton, ui.pauseButton, proposeButton, settingsButton)
+	if ui.onHome != nil {
+		toolbar.Add(widget.NewButton("🏠 Home", ui.onHome))
+		ui.window.SetCloseIntercept(ui.onHome)
+	}
+	
 	statusSection := container.NewVBox(
-		ui.connectionStatus,
+		container.NewBorder(nil, nil, nil, toolbar, container.NewHBox(ui.connectionStatus, ui.latencyLabel)),
+		ui.newOfflineBar(),
 		ui.roomInfo,
 	)
 	
@@ -210,7 +381,8 @@ func (ui *MultiplayerGameUI) setupUI() {
 		widget.NewSeparator(),
 	)
 	
-	// Players list
+	// Players list, each with the icons of the player's note on them
+	ui.notes = openNoteBook(ui.config, ui.logger)
 	ui.playersList = widget.NewList(
 		func() int { return len(ui.currentPlayers) },
 		func() fyne.CanvasObject {
@@ -231,7 +403,7 @@ func (ui *MultiplayerGameUI) setupUI() {
 			statusLabel := cont.Objects[1].(*widget.Label)
 			balanceLabel := cont.Objects[2].(*widget.Label)
 			
-			nameLabel.SetText(player.Name)
+			nameLabel.SetText(player.Name + ui.noteIcons(player.ID))
 			
 			status := "⚪"
 			if player.IsOnline {
@@ -240,12 +412,35 @@ func (ui *MultiplayerGameUI) setupUI() {
 			if player.HasBet {
 				status += " 🎲"
 			}
+			if player.Idle {
+				status += " 💤"
+			}
+			if len(player.Bets) > 1 {
+				status += "×2"
+			}
+			if player.WinStreak > 1 {
+				status += fmt.Sprintf(" 🔥%d", player.WinStreak)
+			}
+			if player.Rating > 0 {
+				status += fmt.Sprintf(" ⚔️%d", player.Rating)
+			}
+			if latency := latencyText(time.Duration(player.LatencyMs) * time.Millisecond); latency != "" {
+				status += " " + latency
+			}
 			statusLabel.SetText(status)
 			
-			balanceLabel.SetText(fmt.Sprintf("$%.2f", player.Balance))
+			balanceLabel.SetText(player.Balance.Format())
 		},
 	)
 	
+	// Picking a player edits the private note on them
+	ui.playersList.OnSelected = func(id widget.ListItemID) {
+		ui.playersList.UnselectAll()
+		if id < len(ui.currentPlayers) {
+			ui.showPlayerNote(ui.currentPlayers[id].ID, ui.currentPlayers[id].Name)
+		}
+	}
+	
 	// Create scroll container with fixed height for players
 	playersScroll := container.NewScroll(ui.playersList)
 	playersScroll.SetMinSize(fyne.NewSize(500, 120)) // Increased height
@@ -256,23 +451,23 @@ func (ui *MultiplayerGameUI) setupUI() {
 	)
 	
 	// Simple betting section - prominently displayed
-	ui.betAmountEntry = widget.NewEntry()
-	ui.betAmountEntry.SetPlaceHolder("Enter bet amount (e.g., 10)")
-	ui.betAmountEntry.SetText("10") // Default bet amount
-	ui.betAmountEntry.Validator = func(s string) error {
-		if s == "" {
-			return nil
-		}
-		amount, err := strconv.ParseFloat(s, 64)
-		if err != nil {
-			return fmt.Errorf("invalid number")
-		}
-		if amount < ui.config.Game.MinBet || amount > ui.config.Game.MaxBet {
-			return fmt.Errorf("bet must be between $%.2f and $%.2f", 
-				ui.config.Game.MinBet, ui.config.Game.MaxBet)
-		}
-		return nil
-	}
+	ui.betInput = newBetInput(nil)
+	ui.betInput.entry.SetPlaceHolder("Enter bet amount (e.g., 10)")
+	ui.updateBetLimits()
+	// Kept as typed until the balance arrives with the first room update
+	ui.betInput.SetText(formatAmount(ui.defaultBet().Float64()))
+	
+	ui.insureCheck = widget.NewCheck("☂️ Insure", nil)
+	ui.insureCheck.Hide()
+	ui.insuranceLabel = widget.NewLabel("")
+	ui.insuranceLabel.Wrapping = fyne.TextWrapWord
+	ui.insuranceLabel.Hide()
+	ui.promotionLabel = widget.NewLabel("")
+	ui.promotionLabel.TextStyle = fyne.TextStyle{Bold: true}
+	ui.promotionLabel.Wrapping = fyne.TextWrapWord
+	ui.promotionLabel.Hide()
+	ui.betInput.onChanged = ui.updateInsurance
+	ui.betErrorBox = ui.newBetRejectionBox()
 	
 	// Large, prominent betting buttons
 	ui.headsButton = widget.NewButton("👑 BET HEADS", func() {
@@ -285,12 +480,48 @@ func (ui *MultiplayerGameUI) setupUI() {
 	})
 	ui.tailsButton.Importance = widget.HighImportance
 	
+	// Withdraws a bet already sent while betting is still open
+	ui.cancelBetButton = widget.NewButton("❌ CANCEL BET", ui.cancelBet)
+	ui.cancelBetButton.Importance = widget.DangerImportance
+	ui.cancelBetButton.Hide()
+	
+	// Both positions when hedging
+	ui.positionsLabel = widget.NewLabel("")
+	ui.positionsLabel.Hide()
+	
+	// Preset amounts from the settings
+	ui.quickBetsBox = container.NewHBox()
+	ui.refreshQuickBets()
+	
+	// Saved bets, placed in one step
+	ui.favorites = newFavoritesBar(ui.config, ui.playerID, ui.window, ui.logger,
+		func() (string, bool) { return ui.betInput.Text(), ui.insureCheck.Checked },
+		ui.placeFavorite)
+	
+	// Confirm/undo bar, shown only while a bet is pending
+	ui.pendingLabel = widget.NewLabel("")
+	ui.confirmButton = widget.NewButton("✅ Confirm", ui.confirmPendingBet)
+	ui.confirmButton.Importance = widget.SuccessImportance
+	ui.undoButton = widget.NewButton("↩️ Undo", ui.undoPendingBet)
+	ui.pendingBox = container.NewBorder(nil, nil, nil, 
+		container.NewHBox(ui.confirmButton, ui.undoButton), ui.pendingLabel)
+	ui.pendingBox.Hide()
+	
 	bettingSection := container.NewVBox(
 		widget.NewLabel("💰 Place Your Bet"),
-		ui.betAmountEntry,
+		ui.promotionLabel,
+		ui.betInput.content,
+		ui.betErrorBox,
+		ui.quickBetsBox,
+		ui.favorites.content,
+		ui.insureCheck,
+		ui.insuranceLabel,
 		widget.NewSeparator(),
 		ui.headsButton,
 		ui.tailsButton,
+		ui.positionsLabel,
+		ui.cancelBetButton,
+		ui.pendingBox,
 	)
 	
 	// Game result
@@ -321,11 +552,7 @@ func (ui *MultiplayerGameUI) setupUI() {
 			
 			roundLabel.SetText(fmt.Sprintf("#%d", len(ui.gameHistory)-id))
 			
-			coinEmoji := "👑"
-			if history.CoinResult == game.Tails {
-				coinEmoji = "🦅"
-			}
-			resultLabel.SetText(fmt.Sprintf("%s %s", coinEmoji, strings.ToUpper(history.CoinResult.String())))
+			resultLabel.SetText(fmt.Sprintf("%s %s", ui.coinFace(history.CoinResult), strings.ToUpper(history.CoinResult.String())))
 			
 			winnerText := "No winners"
 			if len(history.Winners) > 0 {
@@ -374,8 +601,12 @@ func (ui *MultiplayerGameUI) setupUI() {
 			wlLabel := cont.Objects[2].(*widget.Label)
 			profitLabel := cont.Objects[3].(*widget.Label)
 			
-			nameLabel.SetText(stat.PlayerName)
-			balanceLabel.SetText(fmt.Sprintf("$%.0f", stat.CurrentBalance))
+			name := stat.PlayerName + ui.noteIcons(stat.PlayerID)
+			if stat.Rating != nil && stat.Rating.Duels > 0 {
+				name += fmt.Sprintf(" ⚔️%d", stat.Rating.Rating)
+			}
+			nameLabel.SetText(name)
+			balanceLabel.SetText(fmt.Sprintf("$%.0f", stat.CurrentBalance.Float64()))
 			
 			if stat.TotalGames > 0 {
 				wlLabel.SetText(fmt.Sprintf("%d/%d", stat.GamesWon, stat.GamesLost))
@@ -383,7 +614,7 @@ func (ui *MultiplayerGameUI) setupUI() {
 				if stat.NetProfit < 0 {
 					profitColor = "🔴"
 				}
-				profitLabel.SetText(fmt.Sprintf("%s$%.0f", profitColor, stat.NetProfit))
+				profitLabel.SetText(fmt.Sprintf("%s$%.0f", profitColor, stat.NetProfit.Float64()))
 			} else {
 				wlLabel.SetText("0/0")
 				profitLabel.SetText("$0")
@@ -395,9 +626,14 @@ func (ui *MultiplayerGameUI) setupUI() {
 	scoreboardScroll := container.NewScroll(ui.scoreboardList)
 	scoreboardScroll.SetMinSize(fyne.NewSize(500, 150)) // Increased height
 	
+	// Heads/tails charts for the local player, filled in from server stats
+	ui.distributionBox = container.NewGridWithColumns(2)
+	
+	ui.scoreboardHeader = widget.NewLabel(seasonHeader(nil))
 	scoreboardSection := container.NewVBox(
-		widget.NewLabel("🏆 Scoreboard"),
+		ui.scoreboardHeader,
 		scoreboardScroll,
+		ui.distributionBox,
 	)
 	
 	// Comprehensive layout with history and scoreboard
@@ -411,8 +647,16 @@ func (ui *MultiplayerGameUI) setupUI() {
 		widget.NewSeparator(),
 		playersSection,
 		widget.NewSeparator(),
+		ui.newChatSection(),
+		widget.NewSeparator(),
+		ui.newMyBetsSection(),
+		widget.NewSeparator(),
+		ui.newBetSlipSection(),
+		widget.NewSeparator(),
 		historySection,
 		widget.NewSeparator(),
+		ui.newRoomStatsSection(),
+		widget.NewSeparator(),
 		scoreboardSection,
 	)
 	
@@ -422,6 +666,7 @@ func (ui *MultiplayerGameUI) setupUI() {
 	
 	ui.window.SetContent(scrollContent)
 	ui.window.Resize(fyne.NewSize(580, 1000))
+	ui.setupShortcuts()
 	
 	// Auto-connect to server
 	go func() {
@@ -438,7 +683,9 @@ func (ui *MultiplayerGameUI) connectToServer() {
 			ui.logger.Error("Failed to connect", zap.Error(err))
 			// Queue UI update to be executed on main thread
 			ui.queueUIUpdate(func() {
-				ui.connectionStatus.SetText("❌ Connection failed: " + err.Error())
+				ui.enterOffline()
+				ui.connectionStatus.SetText("❌ Connection failed")
+				ui.offlineLabel.SetText("📴 Offline - " + err.Error())
 			})
 			return
 		}
@@ -449,9 +696,9 @@ func (ui *MultiplayerGameUI) connectToServer() {
 		})
 		
 		// Auto-join default room if configured
-		if ui.config.Multiplayer.AutoJoin && ui.config.Multiplayer.DefaultRoom != "" {
+		if room := ui.autoJoinRoom(); room != "" {
 			time.Sleep(1 * time.Second) // Brief delay for connection to stabilize
-			ui.joinRoom(ui.config.Multiplayer.DefaultRoom)
+			ui.joinRoom(room)
 		}
 	}()
 }
@@ -463,9 +710,23 @@ func (ui *MultiplayerGameUI) disconnectFromServer() {
 		ui.updateConnectionStatus("🔄 Disconnected")
 		ui.roomInfo.SetText("Not in room")
 		ui.currentPlayers = nil
+		ui.updatePresence()
 	})
 }
 
+// Close leaves the server and closes the window, stopping the UI's
+// background work
+func (ui *MultiplayerGameUI) Close() {
+	ui.presence.SetJoinHandler(nil)
+	ui.presence.Show(nil)
+	ui.discardPendingBet()
+	ui.stopRetryCountdown()
+	ui.networkClient.Disconnect()
+	close(ui.networkStop)
+	ui.cancel()
+	ui.window.Close()
+}
+
 // joinRoom joins a multiplayer room
 func (ui *MultiplayerGameUI) joinRoom(roomID string) {
 	if !ui.networkClient.IsConnected() {
@@ -473,6 +734,9 @@ func (ui *MultiplayerGameUI) joinRoom(roomID string) {
 		return
 	}
 	
+	// Cleared before joining, as the new room sends its stats on join
+	ui.updateRoomStats(nil)
+	
 	go func() {
 		if err := ui.networkClient.JoinRoom(roomID, ui.balance); err != nil {
 			ui.logger.Error("Failed to join room", zap.Error(err))
@@ -485,11 +749,59 @@ func (ui *MultiplayerGameUI) joinRoom(roomID string) {
 		// Queue UI update to be executed on main thread
 		ui.queueUIUpdate(func() {
 			ui.roomInfo.SetText(fmt.Sprintf("📍 Room: %s", roomID))
+			ui.resetSessionBets()
+			// Slips stay with the room they were submitted in
+			ui.betSlip.active = nil
+			ui.refreshBetSlip()
 		})
 		ui.logger.Info("Joined room", zap.String("room_id", roomID))
 	}()
 }
 
+// findDuel asks the server for a duel against a player rated close to
+// this one; the room it is matched to arrives as a MsgFindDuel
+func (ui *MultiplayerGameUI) findDuel() {
+	if !ui.networkClient.IsConnected() {
+		dialog.ShowError(fmt.Errorf("not connected to server"), ui.window)
+		return
+	}
+	
+	// Cleared before joining, as the duel room sends its stats on join
+	ui.updateRoomStats(nil)
+	
+	go func() {
+		if err := ui.networkClient.FindDuel(); err != nil {
+			ui.logger.Error("Failed to find a duel", zap.Error(err))
+			ui.queueUIUpdate(func() {
+				dialog.ShowError(fmt.Errorf("failed to find a duel: %v", err), ui.window)
+			})
+		}
+	}()
+}
+
+// handleDuelFound shows the duel room the server matched the player to,
+// with the rating they were matched on
+func (ui *MultiplayerGameUI) handleDuelFound(msg *network.Message) {
+	var found network.FindDuelData
+	if err := msg.GetData(&found); err != nil {
+		ui.logger.Error("Failed to parse duel match", zap.Error(err))
+		return
+	}
+	
+	ui.queueUIUpdate(func() {
+		info := fmt.Sprintf("⚔️ Duel: %s", msg.RoomID)
+		if found.Rating != nil {
+			info += fmt.Sprintf(" - your rating %d, matched within %s", found.Rating.Rating, game.RatingBucketName(found.Bucket))
+		}
+		ui.roomInfo.SetText(info)
+		ui.resetSessionBets()
+		// Slips stay with the room they were submitted in
+		ui.betSlip.active = nil
+		ui.refreshBetSlip()
+	})
+	ui.logger.Info("Matched for a duel", zap.String("room_id", msg.RoomID))
+}
+
 // leaveRoom leaves the current room
 func (ui *MultiplayerGameUI) leaveRoom() {
 	go func() {
@@ -502,6 +814,9 @@ func (ui *MultiplayerGameUI) leaveRoom() {
 		ui.queueUIUpdate(func() {
 			ui.roomInfo.SetText("Not in room")
 			ui.currentPlayers = nil
+			ui.queuedBet = nil
+			ui.updateRoomStats(nil)
+			ui.updatePresence()
 		})
 		ui.logger.Info("Left room")
 	}()
@@ -514,25 +829,51 @@ func (ui *MultiplayerGameUI) placeBet(choice game.Side) {
 		return
 	}
 	
-	if ui.gameState != network.StateBetting {
-		dialog.ShowInformation("Betting Closed", "Betting phase is not active", ui.window)
+	amount, ok := ui.betInput.Stake()
+	if !ok {
+		ui.showBelowMinimumBet()
 		return
 	}
 	
-	amountStr := ui.betAmountEntry.Text
-	if amountStr == "" {
-		dialog.ShowError(fmt.Errorf("enter bet amount"), ui.window)
+	insured := ui.insurance.Enabled() && ui.insureCheck.Checked
+	
+	// Outside the betting phase the bet waits on the server for the next round
+	if ui.gameState != network.StateBetting {
+		ui.queueBet(amount, choice, insured)
 		return
 	}
 	
-	amount, err := strconv.ParseFloat(amountStr, 64)
-	if err != nil {
-		dialog.ShowError(fmt.Errorf("invalid bet amount"), ui.window)
+	if ui.config.UI.ConfirmBets && ui.config.UI.BetUndoSeconds > 0 {
+		ui.startPendingBet(amount, choice, insured)
 		return
 	}
 	
+	ui.commitBet(amount, choice, insured)
+}
+
+// placeFavorite fills in a favorite bet and places it like one typed in
+func (ui *MultiplayerGameUI) placeFavorite(favorite game.FavoriteBet) {
+	ui.betInput.SetText(favorite.Amount.String())
+	ui.insureCheck.SetChecked(favorite.Insured)
+	ui.placeBet(favorite.Choice)
+}
+
+// commitBet sends a bet to the server, changing the stake instead if the
+// player has already bet on that side this round. A changed bet keeps the
+// insurance it was placed with.
+func (ui *MultiplayerGameUI) commitBet(amount game.Money, choice game.Side, insured bool) {
+	update := ui.betOn(choice) != nil
+	
 	go func() {
-		if err := ui.networkClient.PlaceBet(amount, choice); err != nil {
+		send, verb := ui.networkClient.PlaceBet, "placed"
+		if insured {
+			send, verb = ui.networkClient.PlaceInsuredBet, "placed and insured"
+		}
+		if update {
+			send, verb = ui.networkClient.UpdateBet, "changed"
+		}
+		
+		if err := send(amount, choice); err != nil {
 			ui.queueUIUpdate(func() {
 				dialog.ShowError(fmt.Errorf("failed to place bet: %v", err), ui.window)
 			})
@@ -541,12 +882,121 @@ func (ui *MultiplayerGameUI) placeBet(choice game.Side) {
 		
 		// Queue UI update to be executed on main thread
 		ui.queueUIUpdate(func() {
+			ui.clearBetRejection()
 			ui.updateBettingButtons()
-			ui.gameResult.SetText(fmt.Sprintf("🎲 Bet placed: $%.2f on %s", amount, strings.ToUpper(choice.String())))
+			ui.gameResult.SetText(fmt.Sprintf("🎲 Bet %s: %s on %s", verb, amount.Format(), strings.ToUpper(choice.String())))
 		})
 	}()
 }
 
+// cancelBet withdraws the bets already sent this round, or the bet queued
+// for the next one while betting is closed
+func (ui *MultiplayerGameUI) cancelBet() {
+	if ui.queuedBet != nil && ui.gameState != network.StateBetting {
+		ui.cancelQueuedBet()
+		return
+	}
+	
+	go func() {
+		if err := ui.networkClient.CancelBet(); err != nil {
+			ui.queueUIUpdate(func() {
+				dialog.ShowError(fmt.Errorf("failed to cancel bet: %v", err), ui.window)
+			})
+		}
+	}()
+}
+
+// myBets returns the local player's positions as last reported by the server
+func (ui *MultiplayerGameUI) myBets() []network.BetData {
+	for _, player := range ui.currentPlayers {
+		if player.ID == ui.playerID {
+			return player.Bets
+		}
+	}
+	return nil
+}
+
+// betOn returns the local player's bet on side, if any
+func (ui *MultiplayerGameUI) betOn(side game.Side) *network.BetData {
+	for _, bet := range ui.myBets() {
+		if bet.Choice == side {
+			return &bet
+		}
+	}
+	return nil
+}
+
+// formatPositions describes the local player's bets, e.g. "👑 $30.00 · 🦅 $10.00"
+func formatPositions(bets []network.BetData) string {
+	parts := make([]string, 0, len(bets))
+	for _, bet := range bets {
+		emoji := "👑"
+		if bet.Choice == game.Tails {
+			emoji = "🦅"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s", emoji, bet.Amount.Format()))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// startPendingBet holds a bet for the configured undo window, after which
+// it is sent unless the player undoes it first
+func (ui *MultiplayerGameUI) startPendingBet(amount game.Money, choice game.Side, insured bool) {
+	ui.discardPendingBet()
+	
+	window := time.Duration(ui.config.UI.BetUndoSeconds) * time.Second
+	pending := &pendingBet{amount: amount, choice: choice, insured: insured}
+	pending.timer = time.AfterFunc(window, func() {
+		ui.queueUIUpdate(func() {
+			// Ignore timers of bets already confirmed, undone or replaced
+			if ui.pendingBet == pending {
+				ui.confirmPendingBet()
+			}
+		})
+	})
+	ui.pendingBet = pending
+	
+	ui.pendingLabel.SetText(fmt.Sprintf("⏳ %s on %s - sending in %ds", 
+		amount.Format(), strings.ToUpper(choice.String()), ui.config.UI.BetUndoSeconds))
+	ui.pendingBox.Show()
+	ui.updateBettingButtons()
+}
+
+// confirmPendingBet sends the pending bet without waiting for the window to end
+func (ui *MultiplayerGameUI) confirmPendingBet() {
+	pending := ui.pendingBet
+	if pending == nil {
+		return
+	}
+	ui.discardPendingBet()
+	
+	if ui.gameState != network.StateBetting {
+		ui.gameResult.SetText("⚠️ Betting closed before your bet was sent")
+		return
+	}
+	ui.commitBet(pending.amount, pending.choice, pending.insured)
+}
+
+// undoPendingBet drops the pending bet without sending it
+func (ui *MultiplayerGameUI) undoPendingBet() {
+	if ui.pendingBet == nil {
+		return
+	}
+	ui.discardPendingBet()
+	ui.gameResult.SetText("↩️ Bet undone")
+}
+
+// discardPendingBet stops the undo window and hides the confirm/undo bar
+func (ui *MultiplayerGameUI) discardPendingBet() {
+	if ui.pendingBet == nil {
+		return
+	}
+	ui.pendingBet.timer.Stop()
+	ui.pendingBet = nil
+	ui.pendingBox.Hide()
+	ui.updateBettingButtons()
+}
+
 // Message handlers
 
 // handleRoomUpdate handles room state updates
@@ -558,17 +1008,31 @@ func (ui *MultiplayerGameUI) handleRoomUpdate(msg *network.Message) {
 	}
 	
 	ui.currentPlayers = roomUpdate.Players
+	ui.maxPlayers = roomUpdate.MaxPlayers
 	ui.gameState = roomUpdate.GameState
+	ui.pausedBy = roomUpdate.PausedBy
+	if roomUpdate.GameState == network.StatePaused {
+		ui.timerSeconds = roomUpdate.Timer
+	}
+	ui.payoutRatio = roomUpdate.PayoutRatio
+	ui.queuePosition = roomUpdate.QueuePosition(ui.playerID)
+	ui.insurance = game.Insurance{}
+	if roomUpdate.Insurance != nil {
+		ui.insurance = *roomUpdate.Insurance
+	}
+	ui.promotion = roomUpdate.Promotion
 	
 	// Update local player balance from server state and track player stats
 	for _, player := range roomUpdate.Players {
 		if player.ID == ui.playerID {
 			ui.balance = player.Balance
+			ui.skin = player.Skin
 		}
 		
 		// Update or create player stats
 		if ui.playerStats[player.ID] == nil {
 			ui.playerStats[player.ID] = &PlayerStats{
+				PlayerID:       player.ID,
 				PlayerName:     player.Name,
 				CurrentBalance: player.Balance,
 				LastSeen:       time.Now(),
@@ -583,9 +1047,24 @@ func (ui *MultiplayerGameUI) handleRoomUpdate(msg *network.Message) {
 	// Queue UI updates to be executed on main thread
 	ui.queueUIUpdate(func() {
 		playerCount := len(roomUpdate.Players)
-		ui.roomInfo.SetText(fmt.Sprintf("📍 Room: %s (%d/%d players)", 
-			roomUpdate.RoomID, playerCount, roomUpdate.MaxPlayers))
+		info := fmt.Sprintf("📍 Room: %s (%d/%d players)", 
+			roomUpdate.RoomID, playerCount, roomUpdate.MaxPlayers)
+		if ui.queuePosition > 0 {
+			info += fmt.Sprintf(" - 🪑 waiting for a seat, %d of %d in line",
+				ui.queuePosition, len(roomUpdate.SeatQueue))
+		}
+		ui.roomInfo.SetText(info)
+		ui.updateBetLimits()
+		if ui.pendingBet != nil && ui.gameState != network.StateBetting {
+			ui.discardPendingBet()
+			ui.gameResult.SetText("⚠️ Betting closed before your bet was sent")
+		}
+		ui.updateInsurance()
+		ui.updatePromotion()
 		ui.updateBettingButtons()
+		ui.refreshBetSlip()
+		ui.updatePauseStatus()
+		ui.updatePresence()
 		ui.historyList.Refresh()
 		ui.scoreboardList.Refresh()
 	})
@@ -604,17 +1083,30 @@ func (ui *MultiplayerGameUI) handleTimerUpdate(msg *network.Message) {
 	
 	// Queue UI updates to be executed on main thread
 	ui.queueUIUpdate(func() {
-		// Update timer display
-		minutes := timerData.SecondsLeft / 60
-		seconds := timerData.SecondsLeft % 60
-		ui.timerLabel.SetText(fmt.Sprintf("⏱️ %s: %d:%02d", 
-			strings.Title(string(timerData.Phase)), minutes, seconds))
+		// Update timer display, in tenths of a second for turbo rounds
+		phase := strings.Title(string(timerData.Phase))
+		if timerData.Turbo && timerData.TotalMillis > 0 {
+			tenths := (timerData.MillisLeft + 50) / 100
+			ui.timerLabel.SetText(fmt.Sprintf("⚡ %s: %d:%02d.%d",
+				phase, tenths/600, tenths/10%60, tenths%10))
+		} else {
+			minutes := timerData.SecondsLeft / 60
+			seconds := timerData.SecondsLeft % 60
+			ui.timerLabel.SetText(fmt.Sprintf("⏱️ %s: %d:%02d", phase, minutes, seconds))
+		}
 		
-		// Update progress bar
-		if timerData.TotalSeconds > 0 {
+		// Update progress bar, to the millisecond when the server sends them
+		if timerData.TotalMillis > 0 {
+			progress := float64(timerData.TotalMillis-timerData.MillisLeft) / float64(timerData.TotalMillis)
+			ui.progressBar.SetValue(progress)
+		} else if timerData.TotalSeconds > 0 {
 			progress := float64(timerData.TotalSeconds-timerData.SecondsLeft) / float64(timerData.TotalSeconds)
 			ui.progressBar.SetValue(progress)
 		}
+		
+		if timerData.EarlyClose {
+			ui.gameResult.SetText(fmt.Sprintf("⏩ Everyone has bet - betting closes in %ds", timerData.SecondsLeft))
+		}
 	})
 }
 
@@ -632,16 +1124,11 @@ func (ui *MultiplayerGameUI) handleGameResult(msg *network.Message) {
 		ui.gameHistory = ui.gameHistory[:10]
 	}
 	
-	// Update player statistics for all participants
-	ui.updatePlayerStatistics(&result)
+	// Refresh authoritative statistics for all participants
+	ui.requestPlayerStatistics(&result)
 	
 	// Display result
-	coinEmoji := "👑"
-	if result.CoinResult == game.Tails {
-		coinEmoji = "🦅"
-	}
-	
-	resultText := fmt.Sprintf("%s %s", coinEmoji, strings.ToUpper(result.CoinResult.String()))
+	resultText := fmt.Sprintf("%s %s", ui.coinFace(result.CoinResult), strings.ToUpper(result.CoinResult.String()))
 	
 	// Check if we won
 	var playerResult *network.PlayerResult
@@ -664,16 +1151,44 @@ func (ui *MultiplayerGameUI) handleGameResult(msg *network.Message) {
 	ui.queueUIUpdate(func() {
 		if playerResult != nil {
 			ui.balance = playerResult.NewBalance
+			ui.recordSessionBets(&result, playerResult)
+			ui.notifyResult(resultText, playerResult)
 			if playerResult.Won {
-				ui.gameResult.SetText(fmt.Sprintf("🎉 %s - You won $%.2f!", 
-					resultText, playerResult.Payout))
+				ui.gameResult.SetText(fmt.Sprintf("🎉 %s - You won %s!", 
+					resultText, playerResult.Payout.Format()))
+				if playerResult.WinStreak > 1 {
+					ui.gameResult.SetText(ui.gameResult.Text + "\n" + streakText(playerResult.WinStreak, 0))
+				}
+				if playerResult.Multiplier > 0 {
+					ui.gameResult.SetText(ui.gameResult.Text + fmt.Sprintf("\n🔥 Streak bonus: %.2fx payout", playerResult.Multiplier))
+				}
+				if playerResult.Boost > 0 {
+					ui.gameResult.SetText(ui.gameResult.Text + fmt.Sprintf("\n🎉 Happy hour boost: %.2fx payout", playerResult.Boost))
+				}
+				if playerResult.PoolShare > 0 {
+					ui.gameResult.SetText(ui.gameResult.Text + fmt.Sprintf("\n🏦 Your share of the winning side: %.1f%%", playerResult.PoolShare*100))
+				}
 			} else {
-				ui.gameResult.SetText(fmt.Sprintf("😞 %s - You lost $%.2f", 
-					resultText, playerResult.Bet.Amount))
+				ui.gameResult.SetText(fmt.Sprintf("😞 %s - You lost %s", 
+					resultText, (playerResult.Wagered-playerResult.Payout-playerResult.Insurance).Format()))
+			}
+			if playerResult.Insurance > 0 {
+				ui.gameResult.SetText(ui.gameResult.Text + fmt.Sprintf("\n☂️ Insurance refunded %s", playerResult.Insurance.Format()))
+			}
+			if len(playerResult.Bets) > 1 {
+				ui.gameResult.SetText(ui.gameResult.Text + fmt.Sprintf("\n🛡️ Hedged: %s wagered, %s returned", 
+					playerResult.Wagered.Format(), playerResult.Payout.Format()))
 			}
 		} else {
 			ui.gameResult.SetText(fmt.Sprintf("🎲 %s (You didn't bet)", resultText))
 		}
+		if pool := result.Pool; pool != nil {
+			ui.gameResult.SetText(ui.gameResult.Text + fmt.Sprintf("\n🏦 Pool %s (heads %s, tails %s): %s shared, %s rake",
+				pool.Total.Format(), pool.Heads.Format(), pool.Tails.Format(), pool.Paid.Format(), pool.Rake.Format()))
+		}
+		if others := winnerSkins(&result, ui.playerID); others != "" {
+			ui.gameResult.SetText(ui.gameResult.Text + "\n" + others)
+		}
 		
 		ui.updateBettingButtons()
 		ui.historyList.Refresh()
@@ -681,14 +1196,163 @@ func (ui *MultiplayerGameUI) handleGameResult(msg *network.Message) {
 	})
 }
 
+// notifyResult tells a player who is not watching, for example one whose
+// bet was queued for the round, how their bet went
+func (ui *MultiplayerGameUI) notifyResult(resultText string, result *network.PlayerResult) {
+	if result.Won {
+		ui.tray.Notify("🎉 You won!", fmt.Sprintf("%s - you won %s, balance %s",
+			resultText, result.Payout.Format(), result.NewBalance.Format()))
+		return
+	}
+	ui.tray.Notify("😞 You lost", fmt.Sprintf("%s - you lost %s, balance %s",
+		resultText, (result.Wagered-result.Payout).Format(), result.NewBalance.Format()))
+}
+
 // handleBetPhase handles betting phase start
 func (ui *MultiplayerGameUI) handleBetPhase(msg *network.Message) {
 	ui.gameState = network.StateBetting
 	
 	// Queue UI updates to be executed on main thread
 	ui.queueUIUpdate(func() {
+		ui.clearBetRejection()
 		ui.updateBettingButtons()
 		ui.gameResult.SetText("🎲 Betting phase started! Place your bets!")
+		ui.tray.Notify("🎲 Betting is open", "Place your bets for the next flip")
+	})
+}
+
+// handleRoundCancelled handles rounds aborted by the server
+func (ui *MultiplayerGameUI) handleRoundCancelled(msg *network.Message) {
+	var cancelled network.RoundCancelledData
+	if err := msg.GetData(&cancelled); err != nil {
+		ui.logger.Error("Failed to parse round cancellation", zap.Error(err))
+		return
+	}
+	
+	refunded := game.Money(0)
+	for _, refund := range cancelled.Refunds {
+		if refund.PlayerID == ui.playerID {
+			refunded = refund.Amount
+			ui.balance = refund.NewBalance
+		}
+	}
+	// Taken now, before the room update that clears them
+	bets := ui.myBets()
+	
+	ui.gameState = network.StateWaiting
+	
+	// Queue UI updates to be executed on main thread
+	ui.queueUIUpdate(func() {
+		text := fmt.Sprintf("⚠️ Round cancelled: %s", cancelled.Reason)
+		if refunded > 0 {
+			text += fmt.Sprintf("\n💸 Your bet of %s was refunded", refunded.Format())
+			ui.recordSessionRefunds(bets)
+		}
+		ui.gameResult.SetText(text)
+		ui.discardPendingBet()
+		ui.updateBettingButtons()
+	})
+}
+
+// handleRoomClosed returns the player to the lobby when the server closes
+// their room, such as one removed as empty just as they rejoined it
+func (ui *MultiplayerGameUI) handleRoomClosed(msg *network.Message) {
+	var closed network.RoomClosedData
+	if err := msg.GetData(&closed); err != nil {
+		ui.logger.Error("Failed to parse room closure", zap.Error(err))
+		return
+	}
+	
+	why := "it was closed"
+	switch closed.Reason {
+	case network.RoomClosedEmpty:
+		why = "everyone left"
+	case network.RoomClosedAbandoned:
+		why = "it was inactive for too long"
+	case network.RoomClosedByAdmin:
+		why = "an administrator closed it"
+	}
+	notice := fmt.Sprintf("🚪 Room %s was closed because %s. Join a room to keep playing.", closed.RoomID, why)
+	if closed.Reason == network.RoomClosedKicked {
+		notice = fmt.Sprintf("🚪 An administrator removed you from room %s. Join a room to keep playing.", closed.RoomID)
+	}
+	
+	ui.gameState = network.StateWaiting
+	
+	// Queue UI updates to be executed on main thread
+	ui.queueUIUpdate(func() {
+		ui.roomInfo.SetText("Not in room")
+		ui.currentPlayers = nil
+		ui.queuedBet = nil
+		ui.discardPendingBet()
+		ui.updateBettingButtons()
+		ui.updatePresence()
+		ui.gameResult.SetText(notice)
+	})
+	ui.logger.Info("Room closed by the server",
+		zap.String("room_id", closed.RoomID),
+		zap.String("reason", closed.Reason),
+	)
+}
+
+// handleBetPlaced tells the player when the room's betting limits scaled
+// their bet down
+func (ui *MultiplayerGameUI) handleBetPlaced(msg *network.Message) {
+	if msg.PlayerID != ui.playerID {
+		return
+	}
+	
+	var bet network.BetData
+	if err := msg.GetData(&bet); err != nil {
+		ui.logger.Error("Failed to parse placed bet", zap.Error(err))
+		return
+	}
+	if bet.Requested == 0 {
+		return
+	}
+	
+	// Queue UI updates to be executed on main thread
+	ui.queueUIUpdate(func() {
+		ui.gameResult.SetText(fmt.Sprintf("📉 Bet lowered to %s on %s (you asked for %s) to stay within the room's betting limits",
+			bet.Amount.Format(), strings.ToUpper(bet.Choice.String()), bet.Requested.Format()))
+	})
+}
+
+// handleBetUpdated handles bets changed while betting was open
+func (ui *MultiplayerGameUI) handleBetUpdated(msg *network.Message) {
+	if msg.PlayerID != ui.playerID {
+		return
+	}
+	
+	var bet network.BetData
+	if err := msg.GetData(&bet); err != nil {
+		ui.logger.Error("Failed to parse updated bet", zap.Error(err))
+		return
+	}
+	
+	// Queue UI updates to be executed on main thread
+	ui.queueUIUpdate(func() {
+		ui.gameResult.SetText(fmt.Sprintf("✏️ Bet changed: %s on %s", bet.Amount.Format(), strings.ToUpper(bet.Choice.String())))
+		ui.updateBettingButtons()
+	})
+}
+
+// handleBetCancelled handles bets withdrawn while betting was open
+func (ui *MultiplayerGameUI) handleBetCancelled(msg *network.Message) {
+	if msg.PlayerID != ui.playerID {
+		return
+	}
+	
+	var bet network.BetData
+	if err := msg.GetData(&bet); err != nil {
+		ui.logger.Error("Failed to parse cancelled bet", zap.Error(err))
+		return
+	}
+	
+	// Queue UI updates to be executed on main thread
+	ui.queueUIUpdate(func() {
+		ui.gameResult.SetText(fmt.Sprintf("↩️ Bet of %s withdrawn and refunded", bet.Amount.Format()))
+		ui.updateBettingButtons()
 	})
 }
 
@@ -702,12 +1366,117 @@ func (ui *MultiplayerGameUI) handleError(msg *network.Message) {
 	
 	// Queue UI updates to be executed on main thread
 	ui.queueUIUpdate(func() {
+		// Refused bets are explained next to the bet controls
+		if betRejectionCodes[errorData.Code] {
+			ui.showBetRejection(errorData)
+			return
+		}
 		dialog.ShowError(fmt.Errorf("%s: %s", errorData.Code, errorData.Message), ui.window)
 	})
 }
 
+// setupShortcuts binds the configured keys: heads and tails bet with the
+// entered amount, flip confirms a pending bet, and cancel undoes a pending
+// bet or withdraws placed ones
+func (ui *MultiplayerGameUI) setupShortcuts() {
+	keys := ui.config.UI.KeyBindings.WithDefaults()
+	ui.confirmButton.SetText(keyHint("✅ Confirm", keys.Flip))
+	ui.undoButton.SetText(keyHint("↩️ Undo", keys.Cancel))
+	
+	bindShortcuts(ui.window, keys, shortcutActions{
+		heads: func() {
+			if !ui.headsButton.Disabled() {
+				ui.placeBet(game.Heads)
+			}
+		},
+		tails: func() {
+			if !ui.tailsButton.Disabled() {
+				ui.placeBet(game.Tails)
+			}
+		},
+		flip: ui.confirmPendingBet,
+		cancel: func() {
+			if ui.pendingBet != nil {
+				ui.undoPendingBet()
+			} else if ui.cancelBetButton.Visible() {
+				ui.cancelBet()
+			}
+		},
+	})
+}
+
+// showSettings opens the settings dialog and applies saved changes
+func (ui *MultiplayerGameUI) showSettings() {
+	ShowSettingsDialog(ui.window, ui.config, ui.applySettings)
+}
+
+// applySettings applies saved settings without a restart where possible
+func (ui *MultiplayerGameUI) applySettings(updated *config.Config) {
+	serverChanged := updated.Multiplayer.ServerHost != ui.config.Multiplayer.ServerHost ||
+		updated.Multiplayer.ServerPort != ui.config.Multiplayer.ServerPort
+	nameChanged := updated.UI.PlayerName != "" && updated.UI.PlayerName != ui.playerName
+	
+	*ui.config = *updated
+	
+	ApplyTheme(ui.app, updated.UI)
+	ui.setupShortcuts()
+	ui.updateBettingButtons()
+	ui.updateBetLimits()
+	ui.betInput.SetText(formatAmount(ui.defaultBet().Float64()))
+	ui.refreshQuickBets()
+	ui.updatePresence()
+	
+	// The player name is sent on join, so a new name needs a fresh connection
+	if nameChanged {
+		ui.playerName = updated.UI.PlayerName
+	}
+	
+	if serverChanged || nameChanged {
+		ui.reconnectToServer()
+	}
+	
+	ui.logger.Info("Settings applied",
+		zap.String("config_file", updated.Path()),
+		zap.Bool("server_changed", serverChanged),
+	)
+}
+
+// reconnectToServer replaces the network client so server address and
+// player name changes take effect
+func (ui *MultiplayerGameUI) reconnectToServer() {
+	close(ui.networkStop)
+	ui.networkClient.Disconnect()
+	
+	ui.currentPlayers = nil
+	ui.roomInfo.SetText("Not in room")
+	ui.updatePresence()
+	
+	ui.setupNetworking()
+	ui.connectToServer()
+}
+
 // Helper methods
 
+// defaultBet returns the configured default bet, falling back to the minimum
+func (ui *MultiplayerGameUI) defaultBet() game.Money {
+	if ui.config.UI.DefaultBet > 0 {
+		return game.NewMoney(ui.config.UI.DefaultBet)
+	}
+	return game.NewMoney(ui.config.Game.MinBet)
+}
+
+// refreshQuickBets rebuilds the quick bet buttons from the settings,
+// followed by half and all of the balance
+func (ui *MultiplayerGameUI) refreshQuickBets() {
+	ui.quickBetsBox.RemoveAll()
+	for _, amount := range ui.config.UI.QuickBets {
+		text := formatAmount(amount)
+		ui.quickBetsBox.Add(ui.quickBetButton("$"+text, text))
+	}
+	ui.quickBetsBox.Add(ui.quickBetButton("½", game.AmountHalf))
+	ui.quickBetsBox.Add(ui.quickBetButton("Max", game.AmountMax))
+}
+
 // updateConnectionStatus updates the connection status label
 func (ui *MultiplayerGameUI) updateConnectionStatus(status string) {
 	// Ensure UI updates happen on the main thread
@@ -716,24 +1485,49 @@ func (ui *MultiplayerGameUI) updateConnectionStatus(status string) {
 
 // updateBettingButtons enables/disables betting buttons based on game state
 func (ui *MultiplayerGameUI) updateBettingButtons() {
-	inRoom := ui.networkClient.GetCurrentRoom() != ""
-	validAmount := ui.betAmountEntry.Validate() == nil && ui.betAmountEntry.Text != ""
+	// Spectators waiting for a seat cannot bet yet
+	inRoom := ui.networkClient.GetCurrentRoom() != "" && ui.queuePosition == 0
+	validAmount := ui.betInput.Valid()
 	bettingActive := ui.gameState == network.StateBetting
 	
-	// Enable betting if in room, amount is valid, and betting is active
-	canBet := inRoom && validAmount && bettingActive
+	pending := ui.pendingBet != nil
+	bets := ui.myBets()
+	placed := len(bets) > 0
+	
+	// Enable betting if online and in room, amount is valid, betting is
+	// active and no bet is waiting in the undo window
+	canBet := !ui.offline && inRoom && validAmount && bettingActive && !pending
+	// Between rounds the same buttons queue a bet for the next one
+	canQueue := !ui.offline && inRoom && validAmount && !bettingActive
 	
-	if canBet {
+	if canQueue {
+		ui.headsButton.Enable()
+		ui.tailsButton.Enable()
+		keys := ui.config.UI.KeyBindings.WithDefaults()
+		ui.headsButton.SetText(keyHint("⏭️ QUEUE HEADS", keys.Heads))
+		ui.tailsButton.SetText(keyHint("⏭️ QUEUE TAILS", keys.Tails))
+	} else if canBet {
 		ui.headsButton.Enable()
 		ui.tailsButton.Enable()
-		ui.headsButton.SetText("👑 BET HEADS")
-		ui.tailsButton.SetText("🦅 BET TAILS")
+		// A second side hedges; betting the same side again changes the stake
+		keys := ui.config.UI.KeyBindings.WithDefaults()
+		ui.headsButton.SetText(keyHint("👑 BET HEADS", keys.Heads))
+		if ui.betOn(game.Heads) != nil {
+			ui.headsButton.SetText(keyHint("👑 CHANGE HEADS BET", keys.Heads))
+		}
+		ui.tailsButton.SetText(keyHint("🦅 BET TAILS", keys.Tails))
+		if ui.betOn(game.Tails) != nil {
+			ui.tailsButton.SetText(keyHint("🦅 CHANGE TAILS BET", keys.Tails))
+		}
 	} else {
 		ui.headsButton.Disable()
 		ui.tailsButton.Disable()
 		
 		// Show helpful messages on buttons
-		if !inRoom {
+		if ui.offline {
+			ui.headsButton.SetText("👑 (Offline)")
+			ui.tailsButton.SetText("🦅 (Offline)")
+		} else if !inRoom {
 			ui.headsButton.SetText("👑 (Join room first)")
 			ui.tailsButton.SetText("🦅 (Join room first)")
 		} else if !validAmount {
@@ -742,7 +1536,38 @@ func (ui *MultiplayerGameUI) updateBettingButtons() {
 		} else if !bettingActive {
 			ui.headsButton.SetText("👑 (Waiting for round)")
 			ui.tailsButton.SetText("🦅 (Waiting for round)")
+		} else if pending {
+			ui.headsButton.SetText("👑 (Confirm or undo)")
+			ui.tailsButton.SetText("🦅 (Confirm or undo)")
+		}
+	}
+	
+	// Placed bets can be withdrawn until betting closes, queued ones until
+	// it opens
+	queued := ui.queuedBet != nil && !bettingActive
+	if !ui.offline && inRoom && queued {
+		ui.cancelBetButton.SetText(keyHint("❌ CANCEL QUEUED BET", ui.config.UI.KeyBindings.WithDefaults().Cancel))
+		ui.cancelBetButton.Show()
+	} else if !ui.offline && inRoom && bettingActive && placed && !pending {
+		cancelKey := ui.config.UI.KeyBindings.WithDefaults().Cancel
+		ui.cancelBetButton.SetText(keyHint("❌ CANCEL BET", cancelKey))
+		if len(bets) > 1 {
+			ui.cancelBetButton.SetText(keyHint("❌ CANCEL BOTH BETS", cancelKey))
 		}
+		ui.cancelBetButton.Show()
+	} else {
+		ui.cancelBetButton.Hide()
+	}
+	
+	if queued {
+		ui.positionsLabel.SetText(fmt.Sprintf("⏭️ Next round: %s on %s",
+			ui.queuedBet.Amount.Format(), strings.ToUpper(ui.queuedBet.Choice.String())))
+		ui.positionsLabel.Show()
+	} else if placed {
+		ui.positionsLabel.SetText("🎯 Your bets: " + formatPositions(bets))
+		ui.positionsLabel.Show()
+	} else {
+		ui.positionsLabel.Hide()
 	}
 	
 	// Debug logging
@@ -755,40 +1580,133 @@ func (ui *MultiplayerGameUI) updateBettingButtons() {
 	)
 }
 
-// updatePlayerStatistics updates player statistics based on game results
-func (ui *MultiplayerGameUI) updatePlayerStatistics(result *network.GameResultData) {
-	// Process winners
-	for _, winner := range result.Winners {
-		if ui.playerStats[winner.PlayerID] == nil {
-			ui.playerStats[winner.PlayerID] = &PlayerStats{
-				PlayerName: fmt.Sprintf("Player%s", winner.PlayerID[len(winner.PlayerID)-4:]),
-			}
+// requestPlayerStatistics asks the server for the lifetime stats of every
+// player who took part in a round
+func (ui *MultiplayerGameUI) requestPlayerStatistics(result *network.GameResultData) {
+	participants := append(append([]network.PlayerResult{}, result.Winners...), result.Losers...)
+	for _, participant := range participants {
+		if err := ui.networkClient.RequestPlayerStats(participant.PlayerID); err != nil {
+			ui.logger.Warn("Failed to request player stats",
+				zap.String("player_id", participant.PlayerID),
+				zap.Error(err),
+			)
 		}
-		
-		stats := ui.playerStats[winner.PlayerID]
-		stats.TotalGames++
-		stats.GamesWon++
-		stats.TotalBet += winner.Bet.Amount
-		stats.TotalWon += winner.Payout
-		stats.NetProfit += (winner.Payout - winner.Bet.Amount)
-		stats.CurrentBalance = winner.NewBalance
-		stats.LastSeen = time.Now()
-	}
-	
-	// Process losers
-	for _, loser := range result.Losers {
-		if ui.playerStats[loser.PlayerID] == nil {
-			ui.playerStats[loser.PlayerID] = &PlayerStats{
-				PlayerName: fmt.Sprintf("Player%s", loser.PlayerID[len(loser.PlayerID)-4:]),
+	}
+}
+
+// handlePlayerStats applies server-computed statistics to the scoreboard
+func (ui *MultiplayerGameUI) handlePlayerStats(msg *network.Message) {
+	var statsData network.PlayerStatsData
+	if err := msg.GetData(&statsData); err != nil || statsData.Stats == nil {
+		ui.logger.Error("Failed to parse player stats", zap.Error(err))
+		return
+	}
+	
+	stats := ui.playerStats[statsData.PlayerID]
+	if stats == nil {
+		stats = &PlayerStats{PlayerID: statsData.PlayerID, PlayerName: statsData.PlayerID}
+		ui.playerStats[statsData.PlayerID] = stats
+	}
+	
+	stats.TotalGames = statsData.Stats.GamesPlayed
+	stats.GamesWon = statsData.Stats.GamesWon
+	stats.GamesLost = statsData.Stats.GamesPlayed - statsData.Stats.GamesWon
+	stats.TotalBet = statsData.Stats.TotalWagered
+	stats.TotalWon = statsData.Stats.TotalWinnings
+	stats.NetProfit = statsData.Stats.NetProfit
+	stats.BiggestWin = statsData.Stats.BiggestWin
+	stats.Choices = statsData.Stats.Choices
+	stats.Outcomes = statsData.Stats.Outcomes
+	stats.Rating = statsData.Rating
+	stats.LastSeen = time.Now()
+	
+	ui.queueUIUpdate(func() {
+		ui.scoreboardList.Refresh()
+		if statsData.PlayerID == ui.playerID {
+			ui.distributionBox.RemoveAll()
+			ui.distributionBox.Add(newDistributionChart("Your choices", stats.Choices))
+			ui.distributionBox.Add(newDistributionChart("Coin results", stats.Outcomes))
+		}
+	})
+}
+// queueBet asks the server to place a bet when the next betting phase opens
+func (ui *MultiplayerGameUI) queueBet(amount game.Money, choice game.Side, insured bool) {
+	go func() {
+		send := ui.networkClient.QueueBet
+		if insured {
+			send = ui.networkClient.QueueInsuredBet
+		}
+		if err := send(amount, choice); err != nil {
+			ui.queueUIUpdate(func() {
+				dialog.ShowError(fmt.Errorf("failed to queue bet: %v", err), ui.window)
+			})
+		}
+	}()
+}
+
+// cancelQueuedBet withdraws the bet queued for the next round
+func (ui *MultiplayerGameUI) cancelQueuedBet() {
+	go func() {
+		if err := ui.networkClient.CancelQueuedBet(); err != nil {
+			ui.queueUIUpdate(func() {
+				dialog.ShowError(fmt.Errorf("failed to cancel queued bet: %v", err), ui.window)
+			})
+		}
+	}()
+}
+
+// handleQueuedBet tracks the local player's bet for the next round as the
+// server queues, places or drops it
+func (ui *MultiplayerGameUI) handleQueuedBet(msg *network.Message) {
+	if msg.PlayerID != ui.playerID {
+		return
+	}
+	
+	var queued network.QueuedBetData
+	if err := msg.GetData(&queued); err != nil {
+		ui.logger.Error("Failed to parse queued bet", zap.Error(err))
+		return
+	}
+	
+	ui.queueUIUpdate(func() {
+		bet := queued.Bet
+		side := strings.ToUpper(bet.Choice.String())
+		switch queued.Status {
+		case network.QueuedBetWaiting:
+			ui.queuedBet = &bet
+			ui.gameResult.SetText(fmt.Sprintf("⏭️ Queued %s on %s for the next round", bet.Amount.Format(), side))
+		case network.QueuedBetPlaced:
+			ui.queuedBet = nil
+			text := fmt.Sprintf("🎲 Queued bet placed: %s on %s", bet.Amount.Format(), side)
+			if bet.Requested > 0 {
+				text += fmt.Sprintf(", lowered from %s by the room's betting limits", bet.Requested.Format())
 			}
+			ui.gameResult.SetText(text)
+		case network.QueuedBetWithdrawn:
+			ui.queuedBet = nil
+			ui.gameResult.SetText("⏭️ Queued bet cancelled")
+		case network.QueuedBetFailed:
+			ui.queuedBet = nil
+			ui.gameResult.SetText("⚠️ Queued bet not placed: " + queued.Reason)
 		}
-		
-		stats := ui.playerStats[loser.PlayerID]
-		stats.TotalGames++
-		stats.GamesLost++
-		stats.TotalBet += loser.Bet.Amount
-		stats.NetProfit -= loser.Bet.Amount
-		stats.CurrentBalance = loser.NewBalance
-		stats.LastSeen = time.Now()
-	}
-}
\ No newline at end of file
+		ui.updateBettingButtons()
+	})
+}
+
+// updateInsurance shows the insurance option when the room offers it,
+// priced for the entered stake
+func (ui *MultiplayerGameUI) updateInsurance() {
+	if !ui.insurance.Enabled() {
+		ui.insureCheck.Hide()
+		ui.insuranceLabel.Hide()
+		return
+	}
+	
+	amount := ui.betInput.Amount()
+	if amount <= 0 {
+		amount = ui.defaultBet()
+	}
+	ui.insuranceLabel.SetText(insuranceText(ui.insurance, ui.payoutRatio, amount))
+	ui.insureCheck.Show()
+	ui.insuranceLabel.Show()
+}
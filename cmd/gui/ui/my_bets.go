@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// sessionBet is one of the local player's bets in the current room
+// session, as listed in the My bets panel
+type sessionBet struct {
+	Round   int
+	Choice  game.Side
+	Amount  game.Money
+	Outcome string
+	// Net is what the bet won or lost; RunningNet totals the session up to
+	// and including it
+	Net        game.Money
+	RunningNet game.Money
+}
+
+// newMyBetsSection builds the My bets panel, the player's own bets since
+// joining the room, newest first, apart from the room's recent games
+func (ui *MultiplayerGameUI) newMyBetsSection() fyne.CanvasObject {
+	ui.sessionBetsList = widget.NewList(
+		func() int { return len(ui.sessionBets) },
+		func() fyne.CanvasObject {
+			return container.NewGridWithColumns(5,
+				widget.NewLabel("Round"),
+				widget.NewLabel("Choice"),
+				widget.NewLabel("Amount"),
+				widget.NewLabel("Outcome"),
+				widget.NewLabel("Net"),
+			)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			if id >= len(ui.sessionBets) {
+				return
+			}
+			bet := ui.sessionBets[len(ui.sessionBets)-1-id]
+			cont := item.(*fyne.Container)
+
+			cont.Objects[0].(*widget.Label).SetText(fmt.Sprintf("#%d", bet.Round))
+			cont.Objects[1].(*widget.Label).SetText(ui.sideText(bet.Choice))
+			cont.Objects[2].(*widget.Label).SetText(bet.Amount.Format())
+			cont.Objects[3].(*widget.Label).SetText(bet.Outcome)
+			cont.Objects[4].(*widget.Label).SetText(bet.RunningNet.FormatSigned())
+		},
+	)
+
+	scroll := container.NewScroll(ui.sessionBetsList)
+	scroll.SetMinSize(fyne.NewSize(500, 150))
+
+	ui.sessionNetLabel = widget.NewLabel("")
+	ui.updateSessionNet()
+
+	header := container.NewGridWithColumns(5,
+		widget.NewLabel("Round"),
+		widget.NewLabel("Choice"),
+		widget.NewLabel("Amount"),
+		widget.NewLabel("Outcome"),
+		widget.NewLabel("Running net"),
+	)
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("🧾 My Bets"), ui.sessionNetLabel),
+		header,
+		scroll,
+	)
+}
+
+// resetSessionBets starts a new session in the My bets panel, on joining a
+// room
+func (ui *MultiplayerGameUI) resetSessionBets() {
+	ui.sessionBets = nil
+	ui.sessionRounds = 0
+	ui.sessionBetsList.Refresh()
+	ui.updateSessionNet()
+}
+
+// recordSessionBets adds the player's bets of a settled round, one row per
+// position so a hedged round shows both sides
+func (ui *MultiplayerGameUI) recordSessionBets(result *network.GameResultData, outcome *network.PlayerResult) {
+	ui.sessionRounds++
+	for _, bet := range outcome.Bets {
+		won := bet.Choice == result.CoinResult
+		var net game.Money
+		text := "❌ Lost"
+		switch {
+		case outcome.Practice:
+			text = "🎯 Practice"
+			if won {
+				text = "🎯 Practice win"
+			}
+		case won:
+			net = outcome.Payout - bet.Amount - bet.Premium
+			text = "✅ Won"
+		case bet.Insured:
+			net = bet.Coverage - bet.Amount - bet.Premium
+			text = "☂️ Insured"
+		default:
+			net = -bet.Amount - bet.Premium
+		}
+		if !outcome.Practice {
+			text += " " + net.FormatSigned()
+		}
+		ui.addSessionBet(sessionBet{
+			Round:   ui.sessionRounds,
+			Choice:  bet.Choice,
+			Amount:  bet.Amount,
+			Outcome: text,
+			Net:     net,
+		})
+	}
+	ui.sessionBetsList.Refresh()
+	ui.updateSessionNet()
+}
+
+// recordSessionRefunds adds the player's bets of a cancelled round, which
+// were refunded in full
+func (ui *MultiplayerGameUI) recordSessionRefunds(bets []network.BetData) {
+	if len(bets) == 0 {
+		return
+	}
+
+	ui.sessionRounds++
+	for _, bet := range bets {
+		ui.addSessionBet(sessionBet{
+			Round:   ui.sessionRounds,
+			Choice:  bet.Choice,
+			Amount:  bet.Amount,
+			Outcome: "↩️ Refunded",
+		})
+	}
+	ui.sessionBetsList.Refresh()
+	ui.updateSessionNet()
+}
+
+// addSessionBet appends a bet, carrying the running net forward
+func (ui *MultiplayerGameUI) addSessionBet(bet sessionBet) {
+	bet.RunningNet = ui.sessionNet() + bet.Net
+	ui.sessionBets = append(ui.sessionBets, bet)
+}
+
+// sessionNet returns the player's net result this session
+func (ui *MultiplayerGameUI) sessionNet() game.Money {
+	if len(ui.sessionBets) == 0 {
+		return 0
+	}
+	return ui.sessionBets[len(ui.sessionBets)-1].RunningNet
+}
+
+// updateSessionNet shows the session's bet count and net result
+func (ui *MultiplayerGameUI) updateSessionNet() {
+	if len(ui.sessionBets) == 0 {
+		ui.sessionNetLabel.SetText("No bets yet")
+		return
+	}
+
+	icon := "🟢"
+	if ui.sessionNet() < 0 {
+		icon = "🔴"
+	}
+	ui.sessionNetLabel.SetText(fmt.Sprintf("%d bets · %s %s", len(ui.sessionBets), icon, ui.sessionNet().FormatSigned()))
+}
+
+// sideText labels a side with the coin face of the player's skin
+func (ui *MultiplayerGameUI) sideText(side game.Side) string {
+	return fmt.Sprintf("%s %s", ui.coinFace(side), side)
+}
@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/config"
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+// openNoteBook opens the private notes players keep about each other. When
+// the notes file cannot be read they are kept for this run only.
+func openNoteBook(cfg *config.Config, logger *zap.Logger) *storage.NoteBook {
+	book, err := storage.OpenNoteBook(cfg.UI.NotesFile)
+	if err != nil {
+		logger.Warn("Player notes will not be saved", zap.Error(err))
+		book, _ = storage.OpenNoteBook("")
+	}
+	return book
+}
+
+// noteIcons returns the icons of the player's note about another player,
+// prefixed with a space, or "" without a note
+func (ui *MultiplayerGameUI) noteIcons(playerID string) string {
+	note, ok := ui.notes.Get(ui.playerID, playerID)
+	if !ok {
+		return ""
+	}
+	if icons := note.Icons(); icons != "" {
+		return " " + icons
+	}
+	return ""
+}
+
+// tagLabel shows an offered tag with its icon, such as "🤝 friend"
+func tagLabel(tag game.NoteTag) string {
+	return tag.Icon + " " + tag.Name
+}
+
+// showPlayerNote edits the player's note about another player: the offered
+// tags as checkboxes, other tags typed in and free text. Saving an empty
+// note deletes it.
+func (ui *MultiplayerGameUI) showPlayerNote(playerID, name string) {
+	if playerID == ui.playerID {
+		return
+	}
+	note, _ := ui.notes.Get(ui.playerID, playerID)
+
+	offered := make([]string, len(game.NoteTags))
+	var checked, typed []string
+	for i, tag := range game.NoteTags {
+		offered[i] = tagLabel(tag)
+	}
+	for _, tag := range note.Tags {
+		if i := indexOfTag(tag); i >= 0 {
+			checked = append(checked, offered[i])
+		} else {
+			typed = append(typed, tag)
+		}
+	}
+
+	tagChecks := widget.NewCheckGroup(offered, nil)
+	tagChecks.Horizontal = true
+	tagChecks.SetSelected(checked)
+	otherEntry := widget.NewEntry()
+	otherEntry.SetPlaceHolder("e.g. bluffs, tilts")
+	otherEntry.SetText(strings.Join(typed, ", "))
+	textEntry := widget.NewMultiLineEntry()
+	textEntry.SetPlaceHolder("Only you can see this note")
+	textEntry.SetText(note.Text)
+	textEntry.SetMinRowsVisible(4)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Tags", tagChecks),
+		widget.NewFormItem("Other tags", otherEntry),
+		widget.NewFormItem("Note", textEntry),
+	}
+	form := dialog.NewForm(fmt.Sprintf("📝 Note on %s", name), "Save", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		var tags []string
+		for _, tag := range game.NoteTags {
+			for _, label := range tagChecks.Selected {
+				if label == tagLabel(tag) {
+					tags = append(tags, tag.Name)
+				}
+			}
+		}
+		for _, tag := range game.ParseNoteTags(otherEntry.Text) {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
+		updated := game.PlayerNote{PlayerID: playerID, Name: name, Tags: tags, Text: strings.TrimSpace(textEntry.Text)}
+		if err := ui.notes.Save(ui.playerID, updated); err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		ui.playersList.Refresh()
+		ui.scoreboardList.Refresh()
+	}, ui.window)
+	form.Resize(fyne.NewSize(520, 360))
+	form.Show()
+}
+
+// showNotes lists the player's notes, narrowed by a search over names, tags
+// and text; picking one edits it
+func (ui *MultiplayerGameUI) showNotes() {
+	var found []game.PlayerNote
+	var notesDialog dialog.Dialog
+
+	list := widget.NewList(
+		func() int { return len(found) },
+		func() fyne.CanvasObject { return widget.NewLabel("Player") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			if id >= len(found) {
+				return
+			}
+			note := found[id]
+			text := note.Name + " " + note.Icons()
+			if len(note.Tags) > 0 {
+				text += "  " + strings.Join(note.Tags, ", ")
+			}
+			if note.Text != "" {
+				text += " · " + note.Text
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		if id >= len(found) {
+			return
+		}
+		notesDialog.Hide()
+		ui.showPlayerNote(found[id].PlayerID, found[id].Name)
+	}
+
+	empty := widget.NewLabel("")
+	search := widget.NewEntry()
+	search.SetPlaceHolder("🔍 Search names, tags and notes")
+	search.OnChanged = func(query string) {
+		found = ui.notes.Search(ui.playerID, query)
+		list.Refresh()
+		switch {
+		case len(found) > 0:
+			empty.Hide()
+		case strings.TrimSpace(query) == "":
+			empty.SetText("No notes yet. Pick a player in the room to add one.")
+			empty.Show()
+		default:
+			empty.SetText(fmt.Sprintf("No notes match %q.", query))
+			empty.Show()
+		}
+	}
+	search.OnChanged("")
+
+	content := container.NewBorder(container.NewVBox(search, empty), nil, nil, nil, list)
+	notesDialog = dialog.NewCustom("📝 Player Notes", "Close", content, ui.window)
+	notesDialog.Resize(fyne.NewSize(480, 420))
+	notesDialog.Show()
+}
+
+// indexOfTag returns the index of an offered tag, or -1 for a typed-in one
+func indexOfTag(name string) int {
+	return slices.IndexFunc(game.NoteTags, func(tag game.NoteTag) bool { return tag.Name == name })
+}
@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/network"
+)
+
+// newOfflineBar builds the banner shown while the server is unreachable
+func (ui *MultiplayerGameUI) newOfflineBar() fyne.CanvasObject {
+	ui.offlineLabel = widget.NewLabel("")
+	ui.offlineLabel.Wrapping = fyne.TextWrapWord
+
+	retryButton := widget.NewButton("🔄 Retry now", ui.retryConnection)
+	retryButton.Importance = widget.WarningImportance
+
+	ui.offlineBox = container.NewBorder(nil, nil, nil, retryButton, ui.offlineLabel)
+	ui.offlineBox.Hide()
+	return ui.offlineBox
+}
+
+// newChatSection builds the room chat. Messages typed while offline are
+// queued and sent once the connection returns.
+func (ui *MultiplayerGameUI) newChatSection() fyne.CanvasObject {
+	ui.chatMessages = widget.NewList(
+		func() int { return len(ui.chatLog) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Wrapping = fyne.TextWrapWord
+			return label
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			if id < len(ui.chatLog) {
+				item.(*widget.Label).SetText(ui.chatLog[id])
+			}
+		},
+	)
+
+	ui.chatEntry = widget.NewEntry()
+	ui.chatEntry.SetPlaceHolder("Say something to the room...")
+	ui.chatEntry.OnSubmitted = ui.sendChat
+
+	sendButton := widget.NewButton("Send", func() {
+		ui.sendChat(ui.chatEntry.Text)
+	})
+
+	chatScroll := container.NewScroll(ui.chatMessages)
+	chatScroll.SetMinSize(fyne.NewSize(500, 120))
+
+	return container.NewVBox(
+		widget.NewLabel("💬 Chat"),
+		chatScroll,
+		container.NewBorder(nil, nil, nil, sendButton, ui.chatEntry),
+	)
+}
+
+// sendChat sends a chat message, or queues it while offline
+func (ui *MultiplayerGameUI) sendChat(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	if len(text) > network.MaxChatLength {
+		text = text[:network.MaxChatLength]
+	}
+	ui.chatEntry.SetText("")
+
+	if ui.offline || !ui.networkClient.IsConnected() {
+		ui.chatQueue = append(ui.chatQueue, text)
+		ui.appendChat(fmt.Sprintf("⏳ %s: %s (queued)", ui.playerName, text))
+		return
+	}
+
+	go func() {
+		if err := ui.networkClient.SendChat(text); err != nil {
+			ui.queueUIUpdate(func() {
+				ui.chatQueue = append(ui.chatQueue, text)
+				ui.appendChat(fmt.Sprintf("⏳ %s: %s (queued)", ui.playerName, text))
+			})
+		}
+	}()
+}
+
+// flushChatQueue sends chat messages queued while offline
+func (ui *MultiplayerGameUI) flushChatQueue() {
+	queued := ui.chatQueue
+	ui.chatQueue = nil
+
+	go func() {
+		for i, text := range queued {
+			if err := ui.networkClient.SendChat(text); err != nil {
+				ui.logger.Warn("Failed to send queued chat message", zap.Error(err))
+				remaining := append([]string(nil), queued[i:]...)
+				ui.queueUIUpdate(func() {
+					ui.chatQueue = append(remaining, ui.chatQueue...)
+				})
+				return
+			}
+		}
+	}()
+}
+
+// appendChat adds a line to the chat log, keeping the most recent lines
+func (ui *MultiplayerGameUI) appendChat(line string) {
+	ui.chatLog = append(ui.chatLog, line)
+	if len(ui.chatLog) > 100 {
+		ui.chatLog = ui.chatLog[len(ui.chatLog)-100:]
+	}
+	ui.chatMessages.Refresh()
+	ui.chatMessages.ScrollToBottom()
+}
+
+// handleChat shows chat messages relayed by the server
+func (ui *MultiplayerGameUI) handleChat(msg *network.Message) {
+	var chat network.ChatData
+	if err := msg.GetData(&chat); err != nil {
+		ui.logger.Error("Failed to parse chat message", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		ui.appendChat(fmt.Sprintf("%s: %s", chat.PlayerName, chat.Text))
+	})
+}
+
+// handleConnectionEvent switches between online and offline mode as the
+// network client loses and regains the server
+func (ui *MultiplayerGameUI) handleConnectionEvent(event network.ConnectionEvent) {
+	ui.queueUIUpdate(func() {
+		switch event.Status {
+		case network.ConnectionRetrying:
+			ui.enterOffline()
+			ui.startRetryCountdown(event)
+		case network.ConnectionDown:
+			ui.enterOffline()
+			ui.stopRetryCountdown()
+			var upgrade *network.UpgradeRequiredError
+			if errors.As(event.Err, &upgrade) {
+				ui.offlineLabel.SetText("⬆️ This server needs a newer version of the game: " + upgrade.Message)
+				break
+			}
+			ui.offlineLabel.SetText("📴 Offline - automatic reconnection stopped. Bets and chat resume once reconnected.")
+		case network.ConnectionUp:
+			ui.leaveOffline()
+		}
+	})
+}
+
+// enterOffline disables betting and shows the offline banner
+func (ui *MultiplayerGameUI) enterOffline() {
+	if !ui.offline {
+		ui.offline = true
+		ui.discardPendingBet()
+		ui.connectionStatus.SetText("📴 Offline")
+		ui.offlineBox.Show()
+	}
+	ui.updateBettingButtons()
+}
+
+// leaveOffline restores online mode after a reconnect. The rejoin brings a
+// fresh room update; statistics and queued chat are resynced here.
+func (ui *MultiplayerGameUI) leaveOffline() {
+	ui.stopRetryCountdown()
+	ui.offline = false
+	ui.offlineBox.Hide()
+	ui.connectionStatus.SetText("✅ Reconnected")
+	ui.updateBettingButtons()
+
+	// A failed first connection never got as far as joining a room
+	if room := ui.autoJoinRoom(); room != "" && ui.networkClient.GetCurrentRoom() == "" {
+		ui.joinRoom(room)
+	}
+
+	ui.flushChatQueue()
+	go func() {
+		if err := ui.networkClient.RequestPlayerStats(ui.playerID); err != nil {
+			ui.logger.Warn("Failed to resync player stats", zap.Error(err))
+		}
+	}()
+}
+
+// retryConnection reconnects immediately instead of waiting for the countdown
+func (ui *MultiplayerGameUI) retryConnection() {
+	ui.stopRetryCountdown()
+	ui.offlineLabel.SetText("🔄 Reconnecting...")
+	ui.networkClient.Reconnect()
+}
+
+// startRetryCountdown counts down to the next automatic reconnect attempt
+func (ui *MultiplayerGameUI) startRetryCountdown(event network.ConnectionEvent) {
+	ui.stopRetryCountdown()
+
+	stop := make(chan struct{})
+	ui.retryCountdownStop = stop
+
+	update := func() {
+		remaining := time.Until(event.RetryAt).Round(time.Second)
+		if remaining <= 0 {
+			ui.offlineLabel.SetText(fmt.Sprintf("🔄 Reconnecting (attempt %d/%d)...", event.Attempt, event.MaxAttempts))
+			return
+		}
+		ui.offlineLabel.SetText(fmt.Sprintf("📴 Offline - reconnecting in %ds (attempt %d/%d)",
+			int(remaining.Seconds()), event.Attempt, event.MaxAttempts))
+	}
+	update()
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ui.ctx.Done():
+				return
+			case <-ticker.C:
+				ui.queueUIUpdate(func() {
+					// Ignore ticks from a countdown that has been replaced
+					if ui.retryCountdownStop == stop {
+						update()
+					}
+				})
+			}
+		}
+	}()
+}
+
+// stopRetryCountdown stops the reconnect countdown, if running
+func (ui *MultiplayerGameUI) stopRetryCountdown() {
+	if ui.retryCountdownStop != nil {
+		close(ui.retryCountdownStop)
+		ui.retryCountdownStop = nil
+	}
+}
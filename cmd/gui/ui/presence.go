@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/config"
+	"coinflip-game/internal/network"
+	"coinflip-game/internal/presence"
+)
+
+// Presence shows the player's online game on their Discord profile while
+// the setting is on, and passes the rooms of invites accepted in Discord to
+// the online game. Discord is reached in the background, and reconnected
+// to on the next update if it was not running.
+type Presence struct {
+	config *config.Config
+	logger *zap.Logger
+
+	// The activity to show, the settings it was asked for with and when
+	// the player entered its room, guarded by mu
+	mu       sync.Mutex
+	activity *presence.Activity
+	enabled  bool
+	appID    string
+	since    time.Time
+	onJoin   func(roomID string)
+
+	// wake asks the background loop to show the latest activity
+	wake chan struct{}
+}
+
+// NewPresence starts keeping the Discord profile up to date until ctx ends
+func NewPresence(ctx context.Context, cfg *config.Config, logger *zap.Logger) *Presence {
+	p := &Presence{
+		config: cfg,
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+	}
+	go p.run(ctx)
+	return p
+}
+
+// SetJoinHandler sets what happens when the player accepts an invite in
+// Discord; nil ignores invites
+func (p *Presence) SetJoinHandler(join func(roomID string)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onJoin = join
+}
+
+// Show puts activity on the player's profile, or clears it when nil. The
+// elapsed time counts from when the player entered the activity's room.
+// It reads the settings, so it must be called from the UI thread.
+func (p *Presence) Show(activity *presence.Activity) {
+	p.mu.Lock()
+	if activity != nil {
+		if p.activity == nil || p.activity.PartyID != activity.PartyID {
+			p.since = time.Now()
+		}
+		activity.Start = p.since
+	}
+	p.activity = activity
+	p.enabled = p.config.UI.DiscordPresence
+	p.appID = p.config.UI.DiscordAppID
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run shows each new activity, connecting to Discord as needed
+func (p *Presence) run(ctx context.Context) {
+	var client *presence.Client
+	var connectedAs string
+	disconnect := func() {
+		if client != nil {
+			client.Close()
+			client = nil
+		}
+	}
+	defer disconnect()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.wake:
+		}
+
+		p.mu.Lock()
+		activity, enabled, appID := p.activity, p.enabled, p.appID
+		p.mu.Unlock()
+
+		if client != nil {
+			select {
+			case <-client.Done():
+				client = nil
+			default:
+			}
+		}
+		if !enabled || appID == "" || appID != connectedAs {
+			disconnect()
+		}
+		if !enabled || appID == "" {
+			continue
+		}
+
+		if client == nil {
+			// Nothing to show is nothing to connect for
+			if activity == nil {
+				continue
+			}
+			var err error
+			if client, err = presence.Dial(appID, p.join); err != nil {
+				p.logger.Debug("Discord rich presence unavailable", zap.Error(err))
+				continue
+			}
+			connectedAs = appID
+		}
+
+		if err := client.SetActivity(activity); err != nil {
+			p.logger.Debug("Failed to update Discord rich presence", zap.Error(err))
+			disconnect()
+		}
+	}
+}
+
+// join passes the room of an invite accepted in Discord to the join handler
+func (p *Presence) join(secret string) {
+	roomID, ok := presence.RoomFromSecret(secret)
+	if !ok {
+		p.logger.Warn("Ignoring Discord invite for another game")
+		return
+	}
+
+	p.mu.Lock()
+	join := p.onJoin
+	p.mu.Unlock()
+	if join == nil {
+		p.logger.Info("Open online play to join rooms from Discord", zap.String("room_id", roomID))
+		return
+	}
+	join(roomID)
+}
+
+// updatePresence shows the room the player is in and their balance on
+// their Discord profile, with how full the room is so friends can join
+func (ui *MultiplayerGameUI) updatePresence() {
+	roomID := ui.networkClient.GetCurrentRoom()
+	balance := "Balance " + ui.balance.Format()
+	if roomID == "" || ui.currentPlayers == nil {
+		ui.presence.Show(&presence.Activity{Details: "In the lobby", State: balance})
+		return
+	}
+
+	details := "Waiting in room " + roomID
+	switch {
+	case ui.queuePosition > 0:
+		details = "Waiting for a seat in room " + roomID
+	case ui.gameState == network.StateBetting:
+		details = "Betting in room " + roomID
+	case ui.gameState == network.StateResult:
+		details = "Watching the flip in room " + roomID
+	case ui.gameState == network.StatePaused:
+		details = "Paused in room " + roomID
+	}
+
+	ui.presence.Show(&presence.Activity{
+		Details:    details,
+		State:      balance,
+		PartyID:    roomID,
+		PartySize:  len(ui.currentPlayers),
+		PartyMax:   ui.maxPlayers,
+		JoinSecret: presence.JoinSecret(roomID),
+	})
+}
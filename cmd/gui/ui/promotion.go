@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/network"
+)
+
+// handlePromotion shows a happy hour starting or ending in the room and
+// announces it in the chat
+func (ui *MultiplayerGameUI) handlePromotion(msg *network.Message) {
+	var data network.PromotionData
+	if err := msg.GetData(&data); err != nil || data.Promotion == nil {
+		ui.logger.Error("Failed to parse promotion", zap.Error(err))
+		return
+	}
+
+	if data.Active {
+		ui.promotion = data.Promotion
+	} else {
+		ui.promotion = nil
+	}
+
+	ui.queueUIUpdate(func() {
+		ui.updatePromotion()
+		if data.Active {
+			ui.appendChat(fmt.Sprintf("🎉 %s! Wins pay %gx until %s", data.Promotion.Name,
+				data.Promotion.Boost, data.Promotion.EndsAt.Local().Format("15:04")))
+		} else {
+			ui.appendChat(fmt.Sprintf("🎉 %s is over", data.Promotion.Name))
+		}
+	})
+}
+
+// updatePromotion shows the running happy hour above the bet controls,
+// with what a winning bet pays during it
+func (ui *MultiplayerGameUI) updatePromotion() {
+	if ui.promotion == nil {
+		ui.promotionLabel.Hide()
+		return
+	}
+	ui.promotionLabel.SetText(promotionText(ui.promotion, ui.payoutRatio))
+	ui.promotionLabel.Show()
+}
+
+// promotionText describes a happy hour and what it makes a bet pay
+func promotionText(promotion *network.Promotion, payoutRatio float64) string {
+	text := fmt.Sprintf("🎉 %s: wins pay %gx", promotion.Name, promotion.Boost)
+	if payoutRatio > 0 {
+		text += fmt.Sprintf(" (%.2fx your bet)", payoutRatio*promotion.Boost)
+	}
+	return text + " until " + promotion.EndsAt.Local().Format("15:04")
+}
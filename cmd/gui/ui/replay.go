@@ -0,0 +1,266 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/network"
+)
+
+// replaySpeeds are the speeds offered while watching a round, as
+// multiples of real time
+var replaySpeeds = []string{"1×", "4×", "16×"}
+
+// replayViewer plays back a room's last round in a dialog, one line per
+// event, for players who arrived after it
+type replayViewer struct {
+	dialog  dialog.Dialog
+	lines   *fyne.Container
+	scroll  *container.Scroll
+	roomID  string
+	roundID string
+
+	// Events received since the server started the current playback, and
+	// how many of them are already shown. Changing speed replays the round
+	// from the last shown event, sending the earlier ones again at once.
+	received int
+	shown    int
+	offset   time.Duration
+	ended    bool
+
+	// Seated players' names by ID, from the room updates
+	names map[string]string
+}
+
+// showLastRound asks the server to replay the current room's last round;
+// the viewer opens when playback starts
+func (ui *MultiplayerGameUI) showLastRound() {
+	roomID := ui.networkClient.GetCurrentRoom()
+	if !ui.networkClient.IsConnected() || roomID == "" {
+		dialog.ShowInformation("⏪ Last Round", "Join a room to watch its last round.", ui.window)
+		return
+	}
+	if ui.replay != nil && ui.replay.dialog != nil {
+		return
+	}
+
+	ui.replay = &replayViewer{roomID: roomID, names: make(map[string]string)}
+	go func() {
+		if err := ui.networkClient.ReplayRound(roomID, "", 1, 0); err != nil {
+			ui.queueUIUpdate(func() {
+				ui.replay = nil
+				dialog.ShowError(err, ui.window)
+			})
+		}
+	}()
+}
+
+// handleReplayStarted opens the viewer when the server starts playing the
+// round back, or restarts it after a change of speed
+func (ui *MultiplayerGameUI) handleReplayStarted(msg *network.Message) {
+	var reply network.ReplayData
+	if err := msg.GetData(&reply); err != nil || reply.Replay == nil {
+		ui.logger.Error("Failed to parse replay reply", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		viewer := ui.replay
+		if viewer == nil {
+			return
+		}
+		viewer.received = 0
+		if viewer.dialog != nil {
+			return
+		}
+
+		viewer.roundID = reply.Replay.RoundID
+		viewer.lines = container.NewVBox(widget.NewLabel(fmt.Sprintf("Round %s, played at %s",
+			reply.Replay.RoundID, reply.Replay.StartedAt.Local().Format("15:04:05"))))
+		viewer.scroll = container.NewVScroll(viewer.lines)
+
+		speed := widget.NewRadioGroup(replaySpeeds, nil)
+		speed.Horizontal = true
+		speed.SetSelected(replaySpeeds[0])
+		speed.OnChanged = func(label string) {
+			if multiple, err := strconv.ParseFloat(strings.TrimSuffix(label, "×"), 64); err == nil {
+				ui.changeReplaySpeed(multiple)
+			}
+		}
+
+		content := container.NewBorder(nil, container.NewHBox(widget.NewLabel("Speed:"), speed), nil, nil, viewer.scroll)
+		viewer.dialog = dialog.NewCustom("⏪ Last Round", "Close", content, ui.window)
+		viewer.dialog.SetOnClosed(ui.closeReplay)
+		viewer.dialog.Resize(fyne.NewSize(480, 420))
+		viewer.dialog.Show()
+	})
+}
+
+// changeReplaySpeed plays the rest of the round at a new speed
+func (ui *MultiplayerGameUI) changeReplaySpeed(speed float64) {
+	viewer := ui.replay
+	if viewer == nil || viewer.dialog == nil || viewer.ended {
+		return
+	}
+	roomID, roundID, from := viewer.roomID, viewer.roundID, viewer.offset
+	go func() {
+		if err := ui.networkClient.ReplayRound(roomID, roundID, speed, from); err != nil {
+			ui.logger.Warn("Failed to change replay speed", zap.Error(err))
+		}
+	}()
+}
+
+// handleReplayEvent shows the next event of the round being replayed
+func (ui *MultiplayerGameUI) handleReplayEvent(msg *network.Message) {
+	var event network.ReplayEventData
+	if err := msg.GetData(&event); err != nil || event.Event == nil {
+		ui.logger.Error("Failed to parse replay event", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		viewer := ui.replay
+		if viewer == nil || viewer.dialog == nil || event.RoundID != viewer.roundID {
+			return
+		}
+		viewer.received++
+		if viewer.received <= viewer.shown {
+			return
+		}
+		viewer.shown++
+		viewer.offset = time.Duration(event.OffsetMs) * time.Millisecond
+
+		if line := viewer.describe(event.Event); line != "" {
+			viewer.addLine(fmt.Sprintf("%s  %s", formatOffset(viewer.offset), line))
+		}
+	})
+}
+
+// handleReplayEnd marks the end of the round being replayed
+func (ui *MultiplayerGameUI) handleReplayEnd(msg *network.Message) {
+	var end network.ReplayEndData
+	if err := msg.GetData(&end); err != nil {
+		ui.logger.Error("Failed to parse replay end", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		viewer := ui.replay
+		if viewer == nil || viewer.dialog == nil || end.Stopped || end.RoundID != viewer.roundID {
+			return
+		}
+		viewer.ended = true
+		viewer.addLine("🏁 End of the round")
+	})
+}
+
+// closeReplay stops the replay when its viewer closes
+func (ui *MultiplayerGameUI) closeReplay() {
+	ui.replay = nil
+	go func() {
+		if err := ui.networkClient.StopReplay(); err != nil {
+			ui.logger.Debug("Failed to stop replay", zap.Error(err))
+		}
+	}()
+}
+
+// addLine appends a line to the viewer and keeps it in view
+func (v *replayViewer) addLine(text string) {
+	v.lines.Add(widget.NewLabel(text))
+	v.scroll.ScrollToBottom()
+}
+
+// describe renders one of the room's messages as a line of the replay, or
+// "" for those that say nothing worth showing, such as countdowns
+func (v *replayViewer) describe(msg *network.Message) string {
+	switch msg.Type {
+	case network.MsgRoomUpdate:
+		var update network.RoomUpdateData
+		if msg.GetData(&update) != nil {
+			return ""
+		}
+		var joined []string
+		seated := make(map[string]string, len(update.Players))
+		for _, player := range update.Players {
+			seated[player.ID] = player.Name
+			if _, known := v.names[player.ID]; !known {
+				joined = append(joined, player.Name)
+			}
+		}
+		first := len(v.names) == 0
+		v.names = seated
+		switch {
+		case len(joined) == 0:
+			return ""
+		case first:
+			return "👥 Seated: " + strings.Join(joined, ", ")
+		default:
+			return "🪑 Joined: " + strings.Join(joined, ", ")
+		}
+
+	case network.MsgBetPhase:
+		var timer network.TimerData
+		if msg.GetData(&timer) != nil {
+			return ""
+		}
+		return fmt.Sprintf("⏰ Betting open for %ds", timer.SecondsLeft)
+
+	case network.MsgBetPlaced, network.MsgUpdateBet:
+		var bet network.BetData
+		if msg.GetData(&bet) != nil {
+			return ""
+		}
+		verb := "bet"
+		if msg.Type == network.MsgUpdateBet {
+			verb = "changed their bet to"
+		}
+		return fmt.Sprintf("💸 %s %s %s on %s", v.name(msg.PlayerID), verb, bet.Amount.Format(), bet.Choice)
+
+	case network.MsgCancelBet:
+		return fmt.Sprintf("❌ %s cancelled their bet", v.name(msg.PlayerID))
+
+	case network.MsgGameResult:
+		var result network.GameResultData
+		if msg.GetData(&result) != nil {
+			return ""
+		}
+		outcomes := make([]string, 0, len(result.Winners)+len(result.Losers))
+		for _, winner := range result.Winners {
+			outcomes = append(outcomes, fmt.Sprintf("%s won %s", winner.PlayerName, winner.Payout.Format()))
+		}
+		for _, loser := range result.Losers {
+			outcomes = append(outcomes, fmt.Sprintf("%s lost %s", loser.PlayerName, loser.Wagered.Format()))
+		}
+		return fmt.Sprintf("🎲 %s: %s", strings.ToUpper(result.CoinResult.String()), strings.Join(outcomes, ", "))
+
+	case network.MsgRoundCancelled:
+		var cancelled network.RoundCancelledData
+		if msg.GetData(&cancelled) != nil {
+			return ""
+		}
+		return "↩️ Round cancelled and bets refunded: " + cancelled.Reason
+	}
+	return ""
+}
+
+// name returns a seated player's name, or their ID before it is known
+func (v *replayViewer) name(playerID string) string {
+	if name := v.names[playerID]; name != "" {
+		return name
+	}
+	return playerID
+}
+
+// formatOffset shows how far into the round an event happened, as m:ss
+func formatOffset(offset time.Duration) string {
+	seconds := int(offset.Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2/dialog"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/network"
+)
+
+// togglePause asks the room to pause, or to resume when it is paused
+func (ui *MultiplayerGameUI) togglePause() {
+	if ui.networkClient.GetCurrentRoom() == "" {
+		dialog.ShowInformation("No Room", "Join a room first", ui.window)
+		return
+	}
+
+	action := network.PauseActionPause
+	if ui.gameState == network.StatePaused {
+		action = network.PauseActionResume
+	}
+
+	go func() {
+		if err := ui.networkClient.ProposePause(action); err != nil {
+			ui.queueUIUpdate(func() {
+				dialog.ShowError(fmt.Errorf("failed to request %s: %v", action, err), ui.window)
+			})
+		}
+	}()
+}
+
+// handlePauseProposal asks the player to vote on other players' requests
+// to pause or resume
+func (ui *MultiplayerGameUI) handlePauseProposal(msg *network.Message) {
+	var proposal network.PauseProposalData
+	if err := msg.GetData(&proposal); err != nil {
+		ui.logger.Error("Failed to parse pause request", zap.Error(err))
+		return
+	}
+
+	requester := ui.playerNameByID(proposal.RequesterID)
+	if proposal.RequesterID == ui.playerID {
+		ui.queueUIUpdate(func() {
+			ui.gameResult.SetText(fmt.Sprintf("%s Asked the room to %s", pauseIcon(proposal.Action), proposal.Action))
+		})
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		dialog.ShowConfirm(fmt.Sprintf("%s Room Vote", pauseIcon(proposal.Action)),
+			fmt.Sprintf("%s asks to %s the game.\n\nAgree?", requester, proposal.Action),
+			func(approve bool) {
+				go func() {
+					if err := ui.networkClient.VotePause(proposal.ProposalID, approve); err != nil {
+						ui.queueUIUpdate(func() {
+							dialog.ShowError(fmt.Errorf("failed to vote: %v", err), ui.window)
+						})
+					}
+				}()
+			}, ui.window)
+	})
+}
+
+// handlePauseVote shows the tally and outcome of a pause vote
+func (ui *MultiplayerGameUI) handlePauseVote(msg *network.Message) {
+	var tally network.PauseProposalData
+	if err := msg.GetData(&tally); err != nil {
+		ui.logger.Error("Failed to parse pause vote", zap.Error(err))
+		return
+	}
+
+	var text string
+	switch {
+	case tally.DecidedBy != "" && tally.Status == network.ProposalPassed:
+		text = fmt.Sprintf("%s Room owner %s agreed to %s", pauseIcon(tally.Action), ui.playerNameByID(tally.DecidedBy), tally.Action)
+	case tally.DecidedBy != "":
+		text = fmt.Sprintf("❌ Room owner %s declined to %s", ui.playerNameByID(tally.DecidedBy), tally.Action)
+	case tally.Status == network.ProposalPassed:
+		text = fmt.Sprintf("%s Room agreed to %s (%d/%d)", pauseIcon(tally.Action), tally.Action, tally.Yes, tally.Eligible)
+	case tally.Status == network.ProposalRejected:
+		text = fmt.Sprintf("❌ Request to %s rejected (%d against)", tally.Action, tally.No)
+	default:
+		text = fmt.Sprintf("%s Vote to %s: %d for, %d against of %d players",
+			pauseIcon(tally.Action), tally.Action, tally.Yes, tally.No, tally.Eligible)
+	}
+
+	ui.queueUIUpdate(func() {
+		ui.gameResult.SetText(text)
+	})
+}
+
+// updatePauseStatus shows who paused the room and offers to resume it.
+// Must run on the UI thread.
+func (ui *MultiplayerGameUI) updatePauseStatus() {
+	if ui.gameState != network.StatePaused {
+		ui.pauseButton.SetText("⏸️ Pause")
+		return
+	}
+
+	ui.pauseButton.SetText("▶️ Resume")
+	if ui.pausedBy == "" {
+		ui.timerLabel.SetText("⏸️ Paused - waiting for players")
+		return
+	}
+	ui.timerLabel.SetText(fmt.Sprintf("⏸️ Paused by %s - %ds left to bet",
+		ui.playerNameByID(ui.pausedBy), ui.timerSeconds))
+}
+
+// pauseIcon returns the icon for a pause action
+func pauseIcon(action network.PauseAction) string {
+	if action == network.PauseActionResume {
+		return "▶️"
+	}
+	return "⏸️"
+}
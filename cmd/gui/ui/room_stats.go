@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/network"
+)
+
+// newRoomStatsSection builds the collapsible Room stats panel, filled in
+// from the statistics the room broadcasts
+func (ui *MultiplayerGameUI) newRoomStatsSection() fyne.CanvasObject {
+	ui.roomStatsLabels = make([]*widget.Label, len(roomStatsRows(nil)))
+	grid := container.NewGridWithColumns(2)
+	for i, row := range roomStatsRows(nil) {
+		ui.roomStatsLabels[i] = widget.NewLabel(row[1])
+		grid.Add(widget.NewLabel(row[0]))
+		grid.Add(ui.roomStatsLabels[i])
+	}
+
+	return widget.NewAccordion(widget.NewAccordionItem("📈 Room stats", grid))
+}
+
+// handleRoomStats shows the statistics the room broadcast
+func (ui *MultiplayerGameUI) handleRoomStats(msg *network.Message) {
+	var stats network.RoomStatsData
+	if err := msg.GetData(&stats); err != nil {
+		ui.logger.Error("Failed to parse room stats", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		ui.updateRoomStats(&stats)
+	})
+}
+
+// updateRoomStats fills in the Room stats panel, or clears it with nil
+func (ui *MultiplayerGameUI) updateRoomStats(stats *network.RoomStatsData) {
+	for i, row := range roomStatsRows(stats) {
+		ui.roomStatsLabels[i].SetText(row[1])
+	}
+}
+
+// roomStatsRows lists the Room stats panel's rows as label and value,
+// with dashes for a room that has no stats yet
+func roomStatsRows(stats *network.RoomStatsData) [][2]string {
+	if stats == nil || stats.RoundsPlayed == 0 {
+		return [][2]string{
+			{"Rounds played", "—"},
+			{"Volume", "—"},
+			{"Return to player", "—"},
+			{"Biggest win", "—"},
+			{"Current streak", "—"},
+		}
+	}
+
+	rtp := "—"
+	if stats.Volume > 0 {
+		rtp = fmt.Sprintf("%.1f%%", stats.RTP*100)
+	}
+	biggest := "—"
+	if stats.BiggestWin > 0 {
+		biggest = fmt.Sprintf("%s by %s", stats.BiggestWin.Format(), stats.BiggestWinner)
+	}
+	return [][2]string{
+		{"Rounds played", fmt.Sprintf("%d", stats.RoundsPlayed)},
+		{"Volume", stats.Volume.Format()},
+		{"Return to player", rtp},
+		{"Biggest win", biggest},
+		{"Current streak", fmt.Sprintf("%d× %s", stats.StreakLength, strings.ToUpper(stats.StreakSide.String()))},
+	}
+}
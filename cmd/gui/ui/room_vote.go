@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// showProposeSettings lets the player put new room settings to a vote.
+// Empty fields keep the room's current value.
+func (ui *MultiplayerGameUI) showProposeSettings() {
+	if ui.networkClient.GetCurrentRoom() == "" {
+		dialog.ShowInformation("No Room", "Join a room first", ui.window)
+		return
+	}
+
+	bettingEntry := widget.NewEntry()
+	bettingEntry.SetPlaceHolder("Keep current")
+	bettingEntry.Validator = optionalPositive(func(s string) (float64, error) {
+		seconds, err := strconv.Atoi(s)
+		return float64(seconds), err
+	})
+
+	minBetEntry := widget.NewEntry()
+	minBetEntry.SetPlaceHolder("Keep current")
+	minBetEntry.Validator = optionalPositive(func(s string) (float64, error) {
+		amount, err := game.ParseMoney(s)
+		return amount.Float64(), err
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Betting time (s)", bettingEntry),
+		widget.NewFormItem("Minimum bet", minBetEntry),
+	}
+
+	form := dialog.NewForm("🗳️ Propose Room Settings", "Propose", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		// Validators have already run, so parsing cannot fail here
+		settings := &network.RoomSettings{}
+		settings.BettingSeconds, _ = strconv.Atoi(bettingEntry.Text)
+		settings.MinBet, _ = game.ParseMoney(minBetEntry.Text)
+		if *settings == (network.RoomSettings{}) {
+			return
+		}
+
+		go func() {
+			if err := ui.networkClient.ProposeConfig(settings); err != nil {
+				ui.queueUIUpdate(func() {
+					dialog.ShowError(fmt.Errorf("failed to propose settings: %v", err), ui.window)
+				})
+			}
+		}()
+	}, ui.window)
+
+	form.Resize(fyne.NewSize(360, 0))
+	form.Show()
+}
+
+// optionalPositive accepts an empty field or a positive number that parse
+// accepts
+func optionalPositive(parse func(string) (float64, error)) fyne.StringValidator {
+	return func(s string) error {
+		if s == "" {
+			return nil
+		}
+		if value, err := parse(s); err != nil || value <= 0 {
+			return fmt.Errorf("enter a positive number or leave empty")
+		}
+		return nil
+	}
+}
+
+// handleConfigProposal asks the player to vote on other players' proposals
+func (ui *MultiplayerGameUI) handleConfigProposal(msg *network.Message) {
+	var proposal network.ConfigProposalData
+	if err := msg.GetData(&proposal); err != nil {
+		ui.logger.Error("Failed to parse settings proposal", zap.Error(err))
+		return
+	}
+
+	// Proposers vote in favour of their own proposal
+	if proposal.ProposerID == ui.playerID {
+		ui.queueUIUpdate(func() {
+			ui.gameResult.SetText("🗳️ Settings proposed: " + describeSettings(proposal.Settings))
+		})
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		dialog.ShowConfirm("🗳️ Room Settings Vote",
+			fmt.Sprintf("%s proposes: %s\n\nApprove?", ui.playerNameByID(proposal.ProposerID), describeSettings(proposal.Settings)),
+			func(approve bool) {
+				go func() {
+					if err := ui.networkClient.VoteConfig(proposal.ProposalID, approve); err != nil {
+						ui.queueUIUpdate(func() {
+							dialog.ShowError(fmt.Errorf("failed to vote: %v", err), ui.window)
+						})
+					}
+				}()
+			}, ui.window)
+	})
+}
+
+// handleConfigVote shows the outcome of a decided settings vote
+func (ui *MultiplayerGameUI) handleConfigVote(msg *network.Message) {
+	var tally network.ConfigProposalData
+	if err := msg.GetData(&tally); err != nil {
+		ui.logger.Error("Failed to parse settings vote", zap.Error(err))
+		return
+	}
+
+	var text string
+	switch tally.Status {
+	case network.ProposalPassed:
+		text = fmt.Sprintf("✅ Settings vote passed (%d/%d) - applies between rounds", tally.Yes, tally.Eligible)
+	case network.ProposalRejected:
+		text = fmt.Sprintf("❌ Settings vote rejected (%d against)", tally.No)
+	default:
+		text = fmt.Sprintf("🗳️ Settings vote: %d for, %d against of %d players", tally.Yes, tally.No, tally.Eligible)
+	}
+
+	ui.queueUIUpdate(func() {
+		ui.gameResult.SetText(text)
+	})
+}
+
+// handleConfigChanged applies new room settings announced by the server
+func (ui *MultiplayerGameUI) handleConfigChanged(msg *network.Message) {
+	var settings network.RoomSettings
+	if err := msg.GetData(&settings); err != nil {
+		ui.logger.Error("Failed to parse room settings", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		ui.gameResult.SetText("⚙️ Room settings changed: " + describeSettings(&settings))
+	})
+}
+
+// playerNameByID returns the display name of a player in the room
+func (ui *MultiplayerGameUI) playerNameByID(playerID string) string {
+	for _, player := range ui.currentPlayers {
+		if player.ID == playerID {
+			return player.Name
+		}
+	}
+	return playerID
+}
+
+// describeSettings summarises the non-zero fields of room settings
+func describeSettings(settings *network.RoomSettings) string {
+	if settings == nil {
+		return "no changes"
+	}
+
+	parts := make([]string, 0, 4)
+	if settings.BettingSeconds > 0 {
+		parts = append(parts, fmt.Sprintf("betting %ds", settings.BettingSeconds))
+	}
+	if settings.MinBet > 0 {
+		parts = append(parts, fmt.Sprintf("min bet %s", settings.MinBet.Format()))
+	}
+	if settings.MaxBet > 0 {
+		parts = append(parts, fmt.Sprintf("max bet %s", settings.MaxBet.Format()))
+	}
+	if settings.MinPlayers > 0 {
+		parts = append(parts, fmt.Sprintf("min %d players", settings.MinPlayers))
+	}
+	if settings.MaxPlayers > 0 {
+		parts = append(parts, fmt.Sprintf("max %d players", settings.MaxPlayers))
+	}
+	if settings.StreakBonus > 0 {
+		parts = append(parts, fmt.Sprintf("streak bonus +%.2fx", settings.StreakBonus))
+	}
+	if settings.InsuranceCost > 0 || settings.InsuranceCoverage > 0 {
+		parts = append(parts, fmt.Sprintf("insurance %.0f%% for %.0f%% cover",
+			settings.InsuranceCost*100, settings.InsuranceCoverage*100))
+	}
+	if settings.Mode != "" && network.RoomMode(settings.Mode) != network.ModeClassic {
+		parts = append(parts, settings.Mode+" mode")
+	}
+	if settings.Rake > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f%% rake", settings.Rake*100))
+	}
+	if settings.StartingStack > 0 {
+		parts = append(parts, fmt.Sprintf("freeroll with %s stacks, winnings paid at %.0f%%",
+			settings.StartingStack.Format(), settings.FreerollRate*100))
+	}
+	if settings.Private {
+		parts = append(parts, "private")
+	}
+	if settings.EarlyClose {
+		parts = append(parts, fmt.Sprintf("closes %ds after everyone bets", settings.EarlyCloseSeconds))
+	}
+	if settings.Turbo {
+		parts = append(parts, "turbo rounds")
+	}
+	if settings.IdleRounds > 0 {
+		parts = append(parts, fmt.Sprintf("idle after %d rounds without a bet", settings.IdleRounds))
+	}
+	if settings.IdleReleaseRounds > 0 {
+		parts = append(parts, fmt.Sprintf("seats released after %d", settings.IdleReleaseRounds))
+	}
+	if settings.Locale != "" {
+		parts = append(parts, "in "+settings.Locale)
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, ", ")
+}
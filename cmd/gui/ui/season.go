@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// seasonHeader is the scoreboard's title with the player's place in the
+// current season
+func seasonHeader(update *network.SeasonUpdateData) string {
+	header := "🏆 Scoreboard"
+	if update == nil {
+		return header
+	}
+
+	header += "  ·  Season " + update.Season
+	if update.Standing == nil {
+		header += ": unranked"
+	} else {
+		header += fmt.Sprintf(": #%d, best streak %d", update.Standing.Rank, update.Standing.BestStreak)
+	}
+	if left := time.Until(update.EndsAt); left > 0 {
+		header += fmt.Sprintf(" (ends in %s)", seasonTimeLeft(left))
+	}
+	return header
+}
+
+// seasonTimeLeft rounds the time until a season ends to days or hours
+func seasonTimeLeft(left time.Duration) string {
+	if left >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(left/(24*time.Hour)))
+	}
+	return fmt.Sprintf("%dh", int(left/time.Hour)+1)
+}
+
+// seasonResult describes how a season ended for the player
+func seasonResult(ended *network.SeasonEndData) string {
+	switch {
+	case ended.Badge != nil:
+		return fmt.Sprintf("Season %s is over: you finished #%d and earned the %s badge!",
+			ended.Season, ended.Badge.Rank, ended.Badge.Name())
+	case ended.Standing != nil:
+		return fmt.Sprintf("Season %s is over: you finished #%d.", ended.Season, ended.Standing.Rank)
+	}
+	return fmt.Sprintf("Season %s is over.", ended.Season)
+}
+
+// handleSeasonUpdate shows the player's season rank above the scoreboard,
+// and how the last season ended when a new one begins
+func (ui *MultiplayerGameUI) handleSeasonUpdate(msg *network.Message) {
+	var update network.SeasonUpdateData
+	if err := msg.GetData(&update); err != nil {
+		ui.logger.Error("Failed to parse season update", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		ui.scoreboardHeader.SetText(seasonHeader(&update))
+		if update.Ended == nil {
+			return
+		}
+
+		text := seasonResult(update.Ended)
+		ui.appendChat("🏁 " + text)
+		if update.Ended.Badge != nil {
+			ui.tray.Notify(seasonBadgeIcon(*update.Ended.Badge)+" Season badge", text)
+		}
+	})
+}
+
+// seasonBadgeIcon returns the medal for a badge
+func seasonBadgeIcon(badge game.SeasonBadge) string {
+	switch badge.Rank {
+	case 1:
+		return "🥇"
+	case 2:
+		return "🥈"
+	}
+	return "🥉"
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/network"
+)
+
+// handleSeatName tells the player the server seated them under another
+// name because someone in the room already had theirs
+func (ui *MultiplayerGameUI) handleSeatName(msg *network.Message) {
+	var joined network.RoomJoinData
+	if err := msg.GetData(&joined); err != nil {
+		ui.logger.Error("Failed to parse join reply", zap.Error(err))
+		return
+	}
+	if joined.RequestedName == "" {
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		text := fmt.Sprintf("🏷️ Someone here is already called %s, so you play as %s", joined.RequestedName, joined.PlayerName)
+		ui.gameResult.SetText(text)
+		ui.appendChat(text)
+	})
+}
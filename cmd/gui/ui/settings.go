@@ -0,0 +1,452 @@
+// Package ui provides the settings dialog for editing configuration at runtime
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"coinflip-game/internal/config"
+)
+
+// ShowSettingsDialog lets the player edit the user-facing configuration. On
+// confirmation the edited copy is validated, saved to the configuration file
+// and passed to onSave; cfg itself is left untouched. Picking another profile
+// switches to it straight away and reopens the dialog on its settings.
+func ShowSettingsDialog(parent fyne.Window, cfg *config.Config, onSave func(updated *config.Config)) {
+	var form dialog.Dialog
+
+	profileSelect := widget.NewSelect(profileNames(), nil)
+	profileSelect.SetSelected(profileLabel(cfg.Profile()))
+	profileSelect.OnChanged = func(selected string) {
+		name := ""
+		if selected != defaultProfileLabel {
+			name = selected
+		}
+		if name == cfg.Profile() {
+			return
+		}
+
+		loaded, err := config.LoadProfile(name)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to load profile: %w", err), parent)
+			profileSelect.SetSelected(profileLabel(cfg.Profile()))
+			return
+		}
+
+		form.Hide()
+		onSave(loaded)
+		ShowSettingsDialog(parent, loaded, onSave)
+	}
+
+	themeSelect := widget.NewSelect([]string{"dark", "light"}, nil)
+	themeSelect.SetSelected(cfg.UI.Theme)
+
+	hostEntry := widget.NewEntry()
+	hostEntry.SetText(cfg.Multiplayer.ServerHost)
+	hostEntry.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("server host is required")
+		}
+		return nil
+	}
+
+	portEntry := widget.NewEntry()
+	portEntry.SetText(strconv.Itoa(cfg.Multiplayer.ServerPort))
+	portEntry.Validator = func(s string) error {
+		port, err := strconv.Atoi(s)
+		if err != nil || port <= 0 || port > 65535 {
+			return fmt.Errorf("port must be between 1 and 65535")
+		}
+		return nil
+	}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Generated for each session")
+	nameEntry.SetText(cfg.UI.PlayerName)
+
+	defaultBetEntry := widget.NewEntry()
+	defaultBetEntry.SetText(formatAmount(cfg.UI.DefaultBet))
+	defaultBetEntry.Validator = func(s string) error {
+		amount, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number")
+		}
+		if amount < cfg.Game.MinBet || amount > cfg.Game.MaxBet {
+			return fmt.Errorf("bet must be between $%.2f and $%.2f", cfg.Game.MinBet, cfg.Game.MaxBet)
+		}
+		return nil
+	}
+
+	soundCheck := widget.NewCheck("Play sounds", nil)
+	soundCheck.SetChecked(cfg.UI.Sound)
+
+	quickBetsEntry := widget.NewEntry()
+	quickBetsEntry.SetPlaceHolder("e.g. 5, 10, 25, 50")
+	quickBetsEntry.SetText(formatQuickBets(cfg.UI.QuickBets))
+	quickBetsEntry.Validator = func(s string) error {
+		_, err := parseQuickBets(s)
+		return err
+	}
+
+	confirmBetsCheck := widget.NewCheck("Hold multiplayer bets for confirmation", nil)
+	confirmBetsCheck.SetChecked(cfg.UI.ConfirmBets)
+
+	undoSecondsEntry := widget.NewEntry()
+	undoSecondsEntry.SetText(strconv.Itoa(cfg.UI.BetUndoSeconds))
+	undoSecondsEntry.Validator = func(s string) error {
+		seconds, err := strconv.Atoi(s)
+		if err != nil || seconds <= 0 {
+			return fmt.Errorf("undo window must be a positive number of seconds")
+		}
+		return nil
+	}
+
+	highContrastCheck := widget.NewCheck("High contrast colours", nil)
+	highContrastCheck.SetChecked(cfg.UI.HighContrast)
+
+	textSizeSelect := widget.NewSelect(textSizeNames(), nil)
+	textSizeSelect.SetSelected(textSizeName(cfg.UI.TextScale))
+
+	accentEntry := widget.NewEntry()
+	accentEntry.SetPlaceHolder("Theme default, or #RRGGBB")
+	accentEntry.SetText(cfg.UI.AccentColor)
+	accentEntry.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return nil
+		}
+		_, err := config.ParseColor(s)
+		return err
+	}
+	pickAccentButton := widget.NewButton("Pick…", func() {
+		picker := dialog.NewColorPicker("🎨 Accent Colour", "Buttons, links and focus", func(c color.Color) {
+			accentEntry.SetText(hexColor(c))
+		}, parent)
+		picker.Advanced = true
+		picker.Show()
+	})
+
+	fontSizeSelect := widget.NewSelect(fontSizeNames(cfg.UI.FontSize), nil)
+	fontSizeSelect.SetSelected(fontSizeName(cfg.UI.FontSize))
+
+	// The theme settings are previewed on the whole app as they are edited
+	// and put back if the dialog is cancelled
+	themed := func() config.UIConfig {
+		ui := cfg.UI
+		ui.Theme = themeSelect.Selected
+		ui.HighContrast = highContrastCheck.Checked
+		ui.TextScale = textSizes[textSizeSelect.Selected]
+		ui.AccentColor = strings.TrimSpace(accentEntry.Text)
+		ui.FontSize = fontSize(fontSizeSelect.Selected)
+		return ui
+	}
+	preview := func() {
+		ApplyTheme(fyne.CurrentApp(), themed())
+	}
+	themeSelect.OnChanged = func(string) { preview() }
+	highContrastCheck.OnChanged = func(bool) { preview() }
+	textSizeSelect.OnChanged = func(string) { preview() }
+	accentEntry.OnChanged = func(string) { preview() }
+	fontSizeSelect.OnChanged = func(string) { preview() }
+
+	previewButton := widget.NewButton("🪙 Flip", nil)
+	previewButton.Importance = widget.HighImportance
+	themePreview := container.NewHBox(previewButton, widget.NewLabel("Balance: $1000.00"),
+		widget.NewHyperlink("Leaderboard", nil))
+
+	trayCheck := widget.NewCheck("Keep running in the system tray (after restart)", nil)
+	trayCheck.SetChecked(cfg.UI.SystemTray)
+
+	notificationsCheck := widget.NewCheck("Notify me while in the background", nil)
+	notificationsCheck.SetChecked(cfg.UI.Notifications)
+
+	discordCheck := widget.NewCheck("Show my online game on Discord", nil)
+	discordCheck.SetChecked(cfg.UI.DiscordPresence)
+
+	discordAppEntry := widget.NewEntry()
+	discordAppEntry.SetPlaceHolder("Discord application ID")
+	discordAppEntry.SetText(cfg.UI.DiscordAppID)
+	discordAppEntry.Validator = func(s string) error {
+		return config.ValidateDiscordAppID(strings.TrimSpace(s))
+	}
+
+	tutorialCheck := widget.NewCheck("Show the tutorial on the home screen", nil)
+	tutorialCheck.SetChecked(cfg.UI.ShowTutorial)
+
+	dataDirEntry := widget.NewEntry()
+	dataDirEntry.SetPlaceHolder("Default location")
+	dataDirEntry.SetText(cfg.UI.DataDir)
+	dataDirEntry.Validator = func(s string) error {
+		return validateDataDir(strings.TrimSpace(s))
+	}
+	browseButton := widget.NewButton("Browse…", func() {
+		showPickFolder(parent, strings.TrimSpace(dataDirEntry.Text), dataDirEntry.SetText)
+	})
+
+	// Importing switches to the new profile like picking it from the list
+	importButton := widget.NewButton("📥 Import…", func() {
+		importProfile(parent, cfg.UI.DataDir, func(imported *config.Config) {
+			form.Hide()
+			onSave(imported)
+			ShowSettingsDialog(parent, imported, onSave)
+		})
+	})
+	exportButton := widget.NewButton("📤 Export…", func() {
+		exportProfile(parent, cfg)
+	})
+
+	bindings := cfg.UI.KeyBindings.WithDefaults()
+	headsKeyEntry := newKeyEntry(bindings.Heads)
+	tailsKeyEntry := newKeyEntry(bindings.Tails)
+	flipKeyEntry := newKeyEntry(bindings.Flip)
+	cancelKeyEntry := newKeyEntry(bindings.Cancel)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Profile", profileSelect),
+		widget.NewFormItem("Profile file", container.NewGridWithColumns(2, importButton, exportButton)),
+		widget.NewFormItem("Theme", themeSelect),
+		widget.NewFormItem("Server host", hostEntry),
+		widget.NewFormItem("Server port", portEntry),
+		widget.NewFormItem("Player name", nameEntry),
+		widget.NewFormItem("Default bet", defaultBetEntry),
+		widget.NewFormItem("Sound", soundCheck),
+		widget.NewFormItem("Quick bets", quickBetsEntry),
+		widget.NewFormItem("Confirm bets", confirmBetsCheck),
+		widget.NewFormItem("Undo window (s)", undoSecondsEntry),
+		widget.NewFormItem("Contrast", highContrastCheck),
+		widget.NewFormItem("Text size", textSizeSelect),
+		widget.NewFormItem("Font size", fontSizeSelect),
+		widget.NewFormItem("Accent colour", container.NewBorder(nil, nil, nil, pickAccentButton, accentEntry)),
+		widget.NewFormItem("Preview", themePreview),
+		widget.NewFormItem("System tray", trayCheck),
+		widget.NewFormItem("Notifications", notificationsCheck),
+		widget.NewFormItem("Discord", discordCheck),
+		widget.NewFormItem("Discord app ID", discordAppEntry),
+		widget.NewFormItem("Tutorial", tutorialCheck),
+		widget.NewFormItem("Data directory", container.NewBorder(nil, nil, nil, browseButton, dataDirEntry)),
+		widget.NewFormItem("Bet heads key", headsKeyEntry),
+		widget.NewFormItem("Bet tails key", tailsKeyEntry),
+		widget.NewFormItem("Flip / confirm key", flipKeyEntry),
+		widget.NewFormItem("Cancel key", cancelKeyEntry),
+	}
+
+	form = dialog.NewForm("⚙️ Settings", "Save", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			ApplyTheme(fyne.CurrentApp(), cfg.UI)
+			return
+		}
+
+		// Validators have already run, so parsing cannot fail here
+		port, _ := strconv.Atoi(portEntry.Text)
+		defaultBet, _ := strconv.ParseFloat(defaultBetEntry.Text, 64)
+		quickBets, _ := parseQuickBets(quickBetsEntry.Text)
+		undoSeconds, _ := strconv.Atoi(undoSecondsEntry.Text)
+
+		updated := *cfg
+		updated.UI = themed()
+		updated.UI.PlayerName = strings.TrimSpace(nameEntry.Text)
+		updated.UI.DefaultBet = defaultBet
+		updated.UI.Sound = soundCheck.Checked
+		updated.UI.QuickBets = quickBets
+		updated.UI.ConfirmBets = confirmBetsCheck.Checked
+		updated.UI.BetUndoSeconds = undoSeconds
+		updated.UI.SystemTray = trayCheck.Checked
+		updated.UI.Notifications = notificationsCheck.Checked
+		updated.UI.DiscordPresence = discordCheck.Checked
+		updated.UI.DiscordAppID = strings.TrimSpace(discordAppEntry.Text)
+		updated.UI.ShowTutorial = tutorialCheck.Checked
+		updated.UI.DataDir = strings.TrimSpace(dataDirEntry.Text)
+		updated.UI.KeyBindings = config.KeyBindings{
+			Heads:  strings.TrimSpace(headsKeyEntry.Text),
+			Tails:  strings.TrimSpace(tailsKeyEntry.Text),
+			Flip:   strings.TrimSpace(flipKeyEntry.Text),
+			Cancel: strings.TrimSpace(cancelKeyEntry.Text),
+		}
+		updated.Multiplayer.ServerHost = strings.TrimSpace(hostEntry.Text)
+		updated.Multiplayer.ServerPort = port
+
+		if err := updated.Save(cfg.Path()); err != nil {
+			ApplyTheme(fyne.CurrentApp(), cfg.UI)
+			dialog.ShowError(fmt.Errorf("failed to save settings: %w", err), parent)
+			return
+		}
+
+		onSave(&updated)
+	}, parent)
+
+	form.Resize(fyne.NewSize(420, 0))
+	form.Show()
+}
+
+// importProfile asks for a configuration file and a name to save it under
+// as a profile, then passes the imported profile to done
+func importProfile(parent fyne.Window, dataDir string, done func(*config.Config)) {
+	showOpenFile(parent, dataDir, []string{".json"}, func(reader fyne.URIReadCloser) {
+		path := reader.URI().Path()
+		reader.Close()
+
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(strings.TrimSuffix(reader.URI().Name(), reader.URI().Extension()))
+		nameEntry.Validator = config.ValidateProfileName
+
+		dialog.ShowForm("📥 Import Profile", "Import", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Profile name", nameEntry),
+		}, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			imported, err := config.ImportProfile(path, nameEntry.Text)
+			if err != nil {
+				dialog.ShowError(err, parent)
+				return
+			}
+			done(imported)
+		}, parent)
+	})
+}
+
+// exportProfile saves the configuration as last saved to a file the
+// player picks, for importing on another machine
+func exportProfile(parent fyne.Window, cfg *config.Config) {
+	fileName := "coinflip.json"
+	if cfg.Profile() != "" {
+		fileName = cfg.Profile() + ".json"
+	}
+
+	showSaveFile(parent, cfg.UI.DataDir, fileName, []string{".json"}, func(writer fyne.URIWriteCloser) {
+		// The configuration writes the file itself, by path
+		path := writer.URI().Path()
+		writer.Close()
+
+		if err := cfg.ExportTo(path); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to export settings: %w", err), parent)
+			return
+		}
+		dialog.ShowInformation("📤 Export Profile", fmt.Sprintf("Settings exported to %s", path), parent)
+	})
+}
+
+// defaultProfileLabel stands for the regular configuration file in the
+// profile list
+const defaultProfileLabel = "(default)"
+
+// profileNames lists the regular configuration followed by the saved
+// profiles; an unreadable profiles directory just leaves the default
+func profileNames() []string {
+	names, _ := config.ListProfiles()
+	return append([]string{defaultProfileLabel}, names...)
+}
+
+// profileLabel returns the list entry for a profile name
+func profileLabel(name string) string {
+	if name == "" {
+		return defaultProfileLabel
+	}
+	return name
+}
+
+// textSizes are the text scales offered in the settings dialog
+var textSizes = map[string]float64{
+	"Normal":      1.0,
+	"Large":       1.25,
+	"Extra large": 1.5,
+	"Huge":        2.0,
+}
+
+// textSizeNames lists the text size choices from smallest to largest
+func textSizeNames() []string {
+	return []string{"Normal", "Large", "Extra large", "Huge"}
+}
+
+// textSizeName returns the choice closest to a configured text scale
+func textSizeName(scale float64) string {
+	closest := "Normal"
+	for _, name := range textSizeNames() {
+		if math.Abs(textSizes[name]-scale) < math.Abs(textSizes[closest]-scale) {
+			closest = name
+		}
+	}
+	return closest
+}
+
+// defaultFontSizeLabel keeps the theme's own font size
+const defaultFontSizeLabel = "Theme default"
+
+// fontSizeNames lists the base font sizes offered in the settings dialog,
+// in points from smallest to largest, adding a configured size that is not
+// among them
+func fontSizeNames(configured float64) []string {
+	names := []string{defaultFontSizeLabel, "12", "13", "14", "16", "18", "20", "24"}
+	if name := fontSizeName(configured); !slices.Contains(names, name) {
+		names = append(names, name)
+	}
+	return names
+}
+
+// fontSizeName returns the choice for a configured font size
+func fontSizeName(size float64) string {
+	if size == 0 {
+		return defaultFontSizeLabel
+	}
+	return formatAmount(size)
+}
+
+// fontSize returns the font size a choice stands for
+func fontSize(name string) float64 {
+	size, _ := strconv.ParseFloat(name, 64)
+	return size
+}
+
+// hexColor renders a colour as #rrggbb
+func hexColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+}
+
+// newKeyEntry creates an entry for a shortcut key name such as "H" or "Return"
+func newKeyEntry(key string) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("e.g. H, Space, Return")
+	entry.SetText(key)
+	return entry
+}
+
+// parseQuickBets parses a comma-separated list of positive bet amounts
+func parseQuickBets(s string) ([]float64, error) {
+	amounts := make([]float64, 0)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		amount, err := strconv.ParseFloat(field, 64)
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("invalid quick bet %q", field)
+		}
+		amounts = append(amounts, amount)
+	}
+	return amounts, nil
+}
+
+// formatQuickBets renders quick bet amounts for editing
+func formatQuickBets(amounts []float64) string {
+	fields := make([]string, 0, len(amounts))
+	for _, amount := range amounts {
+		fields = append(fields, formatAmount(amount))
+	}
+	return strings.Join(fields, ", ")
+}
+
+// formatAmount renders an amount without trailing zeros
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
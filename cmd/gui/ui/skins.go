@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// newSkinShop lists every coin skin with its faces and price. Owned skins
+// can be equipped; the rest can be bought when the balance allows.
+func newSkinShop(inventory game.Inventory, balance game.Money, buy, equip func(skinID string)) fyne.CanvasObject {
+	rows := container.NewVBox(widget.NewLabel(fmt.Sprintf("💰 Balance: %s", balance.Format())))
+
+	for _, skin := range game.Skins() {
+		skin := skin
+		info := widget.NewLabel(fmt.Sprintf("%s %s  %s\n%s",
+			skin.Heads, skin.Tails, skin.Name, skin.Description))
+
+		var action *widget.Button
+		switch {
+		case inventory.EquippedSkin() == skin.ID:
+			action = widget.NewButton("✓ Equipped", nil)
+			action.Disable()
+		case inventory.Owns(skin.ID):
+			action = widget.NewButton("Equip "+skin.Name, func() { equip(skin.ID) })
+		default:
+			action = widget.NewButton(fmt.Sprintf("Buy %s for %s", skin.Name, skin.Price.Format()), func() { buy(skin.ID) })
+			action.Importance = widget.HighImportance
+			if balance < skin.Price {
+				action.Disable()
+			}
+		}
+
+		rows.Add(container.NewBorder(nil, nil, nil, action, info))
+	}
+
+	return rows
+}
+
+// showSkinShop opens the coin skin shop for the single-player session
+func (ui *GameUI) showSkinShop() {
+	player, err := ui.session.Player(ui.ctx)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to load inventory: %w", err), ui.window)
+		return
+	}
+
+	var shop dialog.Dialog
+	reopen := func(err error) {
+		shop.Hide()
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		ui.refreshPlayerInfo()
+		ui.showSkinShop()
+	}
+	content := newSkinShop(player.Inventory, player.Balance,
+		func(skinID string) {
+			_, err := ui.session.BuySkin(ui.ctx, skinID)
+			reopen(err)
+		},
+		func(skinID string) {
+			_, err := ui.session.EquipSkin(ui.ctx, skinID)
+			reopen(err)
+		},
+	)
+
+	shop = dialog.NewCustom("🎨 Coin Skins", "Close", content, ui.window)
+	shop.Show()
+}
+
+// showSkinShop asks the server for the player's inventory; the shop opens
+// when it arrives
+func (ui *MultiplayerGameUI) showSkinShop() {
+	if ui.networkClient == nil || !ui.networkClient.IsConnected() {
+		dialog.ShowInformation("🎨 Coin Skins", "Connect to the server to manage coin skins.", ui.window)
+		return
+	}
+
+	go func() {
+		if err := ui.networkClient.RequestInventory(); err != nil {
+			ui.queueUIUpdate(func() {
+				dialog.ShowError(err, ui.window)
+			})
+		}
+	}()
+}
+
+// handleSkinReply updates the player's inventory after a cosmetics request.
+// The inventory reply opens the shop; purchases refresh it while open.
+func (ui *MultiplayerGameUI) handleSkinReply(msg *network.Message) {
+	var reply network.SkinData
+	if err := msg.GetData(&reply); err != nil || reply.Inventory == nil {
+		ui.logger.Error("Failed to parse skin reply", zap.Error(err))
+		return
+	}
+
+	ui.queueUIUpdate(func() {
+		ui.inventory = *reply.Inventory
+		ui.skin = ui.inventory.EquippedSkin()
+
+		if ui.skinShop != nil {
+			ui.skinShop.Hide()
+		} else if msg.Type != network.MsgInventory {
+			return
+		}
+		send := func(request func(string) error) func(string) {
+			return func(skinID string) {
+				go func() {
+					if err := request(skinID); err != nil {
+						ui.queueUIUpdate(func() {
+							dialog.ShowError(err, ui.window)
+						})
+					}
+				}()
+			}
+		}
+		content := newSkinShop(ui.inventory, reply.Balance,
+			send(ui.networkClient.BuySkin), send(ui.networkClient.EquipSkin))
+
+		ui.skinShop = dialog.NewCustom("🎨 Coin Skins", "Close", content, ui.window)
+		ui.skinShop.SetOnClosed(func() { ui.skinShop = nil })
+		ui.skinShop.Show()
+	})
+}
+
+// coinFace shows a coin side in the player's equipped skin
+func (ui *MultiplayerGameUI) coinFace(side game.Side) string {
+	return game.SkinFace(ui.skin, side)
+}
+
+// winnerSkins lists the other winners of a round with the coin they won on,
+// shown in their own skins
+func winnerSkins(result *network.GameResultData, playerID string) string {
+	var winners []string
+	for _, winner := range result.Winners {
+		if winner.PlayerID == playerID {
+			continue
+		}
+		winners = append(winners, fmt.Sprintf("%s %s", game.SkinFace(winner.Skin, result.CoinResult), winner.PlayerName))
+	}
+	if len(winners) == 0 {
+		return ""
+	}
+	return "🏅 " + strings.Join(winners, ", ")
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+
+	"coinflip-game/internal/config"
+)
+
+// ApplyTheme switches the application to the configured theme: dark or
+// light whatever the desktop prefers, with the player's accent colour and
+// font size, scaled text and raised contrast when the accessibility options
+// ask for them
+func ApplyTheme(app fyne.App, cfg config.UIConfig) {
+	app.Settings().SetTheme(newAppTheme(cfg))
+}
+
+// appTheme is Fyne's default theme held to one variant, with the
+// configured accent, font size and accessibility options on top
+type appTheme struct {
+	variant      fyne.ThemeVariant
+	accent       color.Color // nil keeps the default accent
+	fontSize     float32     // zero keeps the default size
+	textScale    float32
+	highContrast bool
+}
+
+// newAppTheme builds the theme for a UI configuration. Settings that fail
+// to parse fall back to the defaults, as Validate has already reported them.
+func newAppTheme(cfg config.UIConfig) *appTheme {
+	t := &appTheme{
+		variant:      theme.VariantDark,
+		fontSize:     float32(cfg.FontSize),
+		textScale:    float32(cfg.TextScale),
+		highContrast: cfg.HighContrast,
+	}
+	if cfg.Theme == "light" {
+		t.variant = theme.VariantLight
+	}
+	if accent, err := config.ParseColor(cfg.AccentColor); err == nil {
+		t.accent = accent
+	}
+	if t.textScale == 0 {
+		t.textScale = 1
+	}
+	return t
+}
+
+// Color returns the variant's colours with the accent swapped in, or pure
+// black and white with a bold accent in high-contrast mode. The variant
+// Fyne asks for is ignored so the configured one wins.
+func (t *appTheme) Color(name fyne.ThemeColorName, _ fyne.ThemeVariant) color.Color {
+	light := t.variant == theme.VariantLight
+
+	if t.highContrast {
+		background, foreground := color.Color(color.Black), color.Color(color.White)
+		accent := t.accent
+		if accent == nil {
+			accent = color.NRGBA{R: 0xff, G: 0xd7, A: 0xff}
+			if light {
+				accent = color.NRGBA{B: 0xb0, A: 0xff}
+			}
+		}
+		if light {
+			background, foreground = color.White, color.Black
+		}
+
+		switch name {
+		case theme.ColorNameBackground, theme.ColorNameInputBackground,
+			theme.ColorNameMenuBackground, theme.ColorNameOverlayBackground:
+			return background
+		case theme.ColorNameForeground, theme.ColorNamePlaceHolder,
+			theme.ColorNameInputBorder, theme.ColorNameSeparator:
+			return foreground
+		case theme.ColorNamePrimary, theme.ColorNameFocus, theme.ColorNameHyperlink:
+			return accent
+		case theme.ColorNameForegroundOnPrimary:
+			return contrastingText(accent)
+		case theme.ColorNameDisabled:
+			return color.NRGBA{R: 0x99, G: 0x99, B: 0x99, A: 0xff}
+		}
+	} else if t.accent != nil {
+		switch name {
+		case theme.ColorNamePrimary, theme.ColorNameHyperlink:
+			return t.accent
+		case theme.ColorNameFocus, theme.ColorNameSelection:
+			return withAlpha(t.accent, 0x7f)
+		case theme.ColorNameForegroundOnPrimary:
+			return contrastingText(t.accent)
+		}
+	}
+	return theme.DefaultTheme().Color(name, t.variant)
+}
+
+// Font returns the default theme's fonts
+func (t *appTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return theme.DefaultTheme().Font(style)
+}
+
+// Icon returns the default theme's icons, which follow the theme's colours
+func (t *appTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return theme.DefaultTheme().Icon(name)
+}
+
+// Size sets the base font size, keeping headings and captions in
+// proportion, then scales text and the icons that sit inline with it
+func (t *appTheme) Size(name fyne.ThemeSizeName) float32 {
+	size := theme.DefaultTheme().Size(name)
+	switch name {
+	case theme.SizeNameText, theme.SizeNameHeadingText, theme.SizeNameSubHeadingText,
+		theme.SizeNameCaptionText, theme.SizeNameInlineIcon:
+		if t.fontSize > 0 {
+			size *= t.fontSize / theme.DefaultTheme().Size(theme.SizeNameText)
+		}
+		return size * t.textScale
+	}
+	return size
+}
+
+// contrastingText returns black or white, whichever reads better on c
+func contrastingText(c color.Color) color.Color {
+	r, g, b, _ := c.RGBA()
+	// Rec. 601 luma on 16-bit channels
+	if 299*r+587*g+114*b > 1000*0x8000 {
+		return color.Black
+	}
+	return color.White
+}
+
+// withAlpha returns c with its opacity replaced
+func withAlpha(c color.Color, alpha uint8) color.Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	n.A = alpha
+	return n
+}
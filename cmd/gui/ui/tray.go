@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"sync/atomic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+
+	"coinflip-game/internal/config"
+)
+
+// Tray keeps the app running in the system tray while its windows are
+// hidden and notifies the player about online games they are not watching
+type Tray struct {
+	app    fyne.App
+	config *config.Config
+
+	// installed is set once the tray menu is shown; background tracks
+	// whether any of the app's windows has focus
+	installed  bool
+	background atomic.Bool
+}
+
+// NewTray creates a tray for the app and starts tracking whether it is in
+// the background. Notifications follow cfg as it is edited in the settings.
+func NewTray(app fyne.App, cfg *config.Config) *Tray {
+	tray := &Tray{app: app, config: cfg}
+
+	lifecycle := app.Lifecycle()
+	lifecycle.SetOnEnteredForeground(func() { tray.background.Store(false) })
+	lifecycle.SetOnExitedForeground(func() { tray.background.Store(true) })
+
+	return tray
+}
+
+// Install adds the tray icon when the configuration asks for it and the
+// platform has a system tray; show brings the app back from the tray. It
+// reports whether the tray is available to hide windows into.
+func (tray *Tray) Install(show func()) bool {
+	desk, ok := tray.app.(desktop.App)
+	if !ok || !tray.config.UI.SystemTray {
+		return false
+	}
+
+	// Fyne adds its own Quit item to the menu
+	desk.SetSystemTrayMenu(fyne.NewMenu("Coin Flip",
+		fyne.NewMenuItem("🪙 Show Coin Flip", show),
+	))
+	tray.installed = true
+	return true
+}
+
+// Installed reports whether closing a window hides it to the tray
+func (tray *Tray) Installed() bool {
+	return tray.installed
+}
+
+// Notify shows a native notification while the app is in the background,
+// unless notifications are turned off in the settings
+func (tray *Tray) Notify(title, content string) {
+	if !tray.config.UI.Notifications || !tray.background.Load() {
+		return
+	}
+	tray.app.SendNotification(fyne.NewNotification(title, content))
+}
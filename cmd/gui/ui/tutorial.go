@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"coinflip-game/internal/config"
+)
+
+// tutorialStep is one page of the guided tutorial
+type tutorialStep struct {
+	title string
+	text  string
+}
+
+// tutorialSteps walks through a practice round and then online play, using
+// the configured bet limits and shortcut keys
+func tutorialSteps(cfg *config.Config) []tutorialStep {
+	keys := cfg.UI.KeyBindings.WithDefaults()
+	return []tutorialStep{
+		{
+			title: "👋 Welcome to Coin Flip",
+			text: "This short tour shows you how to play a round, read the result " +
+				"and join other players online. You can open it again at any time " +
+				"from the home screen.",
+		},
+		{
+			title: "💸 Place a bet",
+			text: fmt.Sprintf("Choose 🎯 Practice offline, type an amount between $%.2f and $%.2f "+
+				"and pick 👑 Heads (%s) or 🦅 Tails (%s). The stake leaves your balance "+
+				"until the coin lands; ❌ Cancel Bet (%s) gives it back.",
+				cfg.Game.MinBet, cfg.Game.MaxBet, keys.Heads, keys.Tails, keys.Cancel),
+		},
+		{
+			title: "🎲 Flip the coin",
+			text: fmt.Sprintf("Press 🎲 Flip Coin! (%s) once your bet is placed. "+
+				"A right call pays %.1fx your stake, and winning streaks add a bonus.",
+				keys.Flip, cfg.Game.PayoutRatio),
+		},
+		{
+			title: "📊 Read the result and stats",
+			text: "The result under the buttons shows how the coin landed and what you " +
+				"won or lost, and your balance and streak update above. 📊 Statistics " +
+				"tracks your win rate, net profit and longest streak, and 📜 Game History lists every flip.",
+		},
+		{
+			title: "🌐 Play online",
+			text: "🌐 Play online connects to the server and seats you in a room. " +
+				"Everyone bets while the 🕐 Game Timer counts down, then the coin is " +
+				"flipped for the whole room. Chat, votes and the scoreboard are on the same screen.",
+		},
+	}
+}
+
+// ShowTutorial shows the guided tutorial over parent one step at a time.
+// onDone runs when the player finishes or skips it.
+func ShowTutorial(parent fyne.Window, cfg *config.Config, onDone func()) {
+	steps := tutorialSteps(cfg)
+	current := 0
+
+	title := widget.NewLabel("")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	progress := widget.NewLabel("")
+	body := widget.NewLabel("")
+	body.Wrapping = fyne.TextWrapWord
+
+	var popup *widget.PopUp
+	finish := func() {
+		popup.Hide()
+		if onDone != nil {
+			onDone()
+		}
+	}
+
+	backButton := widget.NewButton("◀ Back", nil)
+	nextButton := widget.NewButton("", nil)
+	nextButton.Importance = widget.HighImportance
+	skipButton := widget.NewButton("Skip tutorial", finish)
+
+	show := func() {
+		step := steps[current]
+		title.SetText(step.title)
+		progress.SetText(fmt.Sprintf("Step %d of %d", current+1, len(steps)))
+		body.SetText(step.text)
+
+		if current == 0 {
+			backButton.Disable()
+		} else {
+			backButton.Enable()
+		}
+		if current == len(steps)-1 {
+			nextButton.SetText("✅ Done")
+			skipButton.Hide()
+		} else {
+			nextButton.SetText("Next ▶")
+			skipButton.Show()
+		}
+	}
+
+	backButton.OnTapped = func() {
+		current--
+		show()
+	}
+	nextButton.OnTapped = func() {
+		if current == len(steps)-1 {
+			finish()
+			return
+		}
+		current++
+		show()
+	}
+
+	content := container.NewBorder(
+		container.NewVBox(container.NewBorder(nil, nil, nil, progress, title), widget.NewSeparator()),
+		container.NewBorder(nil, nil, skipButton, container.NewHBox(backButton, nextButton)),
+		nil, nil,
+		body,
+	)
+
+	popup = widget.NewModalPopUp(content, parent.Canvas())
+	show()
+	popup.Resize(fyne.NewSize(440, 240))
+	popup.Show()
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/config"
+	"coinflip-game/internal/network"
+	"coinflip-game/internal/storage"
+)
+
+// walletSyncTimeout bounds one attempt to reach the server for a sync
+const walletSyncTimeout = 10 * time.Second
+
+// walletSyncRetries is how often a sync is retried while the server still
+// holds the player's seat, such as just after leaving online play
+const walletSyncRetries = 5
+
+// serverClientConfig returns the connection settings for the configured
+// multiplayer server
+func serverClientConfig(cfg *config.Config) *network.ClientConfig {
+	clientConfig := network.DefaultClientConfig()
+	clientConfig.ServerURL = fmt.Sprintf("ws://%s:%d/ws", cfg.Multiplayer.ServerHost, cfg.Multiplayer.ServerPort)
+	clientConfig.Encoding = network.Encoding(cfg.Multiplayer.Encoding)
+	clientConfig.EnableCompression = cfg.Multiplayer.Compression
+	return clientConfig
+}
+
+// setupBankroll shares the practice balance with the server wallet when
+// wallet sync is on, restoring the balance cached by the last run
+func (home *HomeUI) setupBankroll() {
+	if !home.config.Wallet.Sync {
+		return
+	}
+
+	cache, err := storage.OpenWalletCache(home.config.Wallet.CacheFile)
+	if err != nil {
+		home.logger.Warn("Wallet sync disabled", zap.Error(err))
+		return
+	}
+	home.bankroll = network.NewBankroll(home.engine, cache, serverClientConfig(home.config), home.identity.ID, home.logger)
+	if _, err := home.bankroll.Restore(home.ctx); err != nil {
+		home.logger.Warn("Failed to restore cached balance", zap.Error(err))
+	}
+}
+
+// syncWallet reconciles the practice balance with the server wallet in the
+// background, then calls done on the main thread. Play while the server is
+// unreachable stays cached for the next sync.
+func (home *HomeUI) syncWallet(done func()) {
+	if home.bankroll == nil {
+		if done != nil {
+			done()
+		}
+		return
+	}
+
+	home.walletLabel.SetText("🔄 Syncing wallet...")
+	go func() {
+		synced, err := home.trySyncWallet()
+		fyne.Do(func() {
+			switch {
+			case errors.Is(err, network.ErrWalletInUse):
+				home.walletLabel.SetText("🪑 Seated in an online room, practice play will sync later")
+			case err != nil:
+				home.logger.Info("Wallet not synced", zap.Error(err))
+				home.walletLabel.SetText("📴 Server unreachable, practice play will sync later")
+			case synced.Applied != synced.Net:
+				home.walletLabel.SetText(fmt.Sprintf("✅ Wallet synced, %s of %s offline play counted",
+					synced.Applied.FormatSigned(), synced.Net.FormatSigned()))
+			default:
+				home.walletLabel.SetText("✅ Wallet synced with the server")
+			}
+			home.refresh()
+			if done != nil {
+				done()
+			}
+		})
+	}()
+}
+
+// trySyncWallet syncs the bankroll, waiting while the server still holds
+// the player's seat
+func (home *HomeUI) trySyncWallet() (*network.WalletSyncData, error) {
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(home.ctx, walletSyncTimeout)
+		synced, err := home.bankroll.Sync(ctx)
+		cancel()
+		if !errors.Is(err, network.ErrWalletInUse) || attempt == walletSyncRetries {
+			return synced, err
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// recordBankroll caches the practice balance for the next wallet sync
+func (ui *GameUI) recordBankroll() {
+	if ui.bankroll == nil {
+		return
+	}
+	if err := ui.bankroll.Record(ui.ctx); err != nil {
+		ui.logger.Warn("Failed to cache practice balance", zap.Error(err))
+	}
+}
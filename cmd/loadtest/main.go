@@ -0,0 +1,98 @@
+// Package main provides a load-testing tool for the multiplayer coin flip server.
+// It simulates many WebSocket players that join rooms and bet every round,
+// then reports message latency, dropped updates and round throughput.
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/config"
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+	"coinflip-game/internal/network"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := newRootCommand(cfg).Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand creates the load test command
+func newRootCommand(cfg *config.Config) *cobra.Command {
+	opts := Options{
+		ServerURL: fmt.Sprintf("ws://%s:%d/ws", cfg.Multiplayer.ServerHost, cfg.Multiplayer.ServerPort),
+		Encoding:  network.Encoding(cfg.Multiplayer.Encoding),
+	}
+	var betAmount, balance float64
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "coinflip-loadtest",
+		Short: "Load test a multiplayer coin flip server",
+		Long: `Coinflip-loadtest connects simulated players to a running multiplayer server.
+Each player joins a room, bets in every betting phase and records what it
+receives. When the run ends a summary of message latency, dropped updates and
+round throughput is printed.`,
+		Example: `  # 40 players spread over 5 rooms for two minutes
+  coinflip-loadtest --clients 40 --rooms 5 --duration 2m
+
+  # Target a remote server using msgpack frames
+  coinflip-loadtest --server ws://game.example.com:8080/ws --encoding msgpack`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if opts.BetAmount, err = game.MoneyFromFloat(betAmount); err != nil {
+				return fmt.Errorf("invalid --amount: %w", err)
+			}
+			if opts.Balance, err = game.MoneyFromFloat(balance); err != nil {
+				return fmt.Errorf("invalid --balance: %w", err)
+			}
+
+			log := zap.NewNop()
+			if verbose {
+				if log, err = logger.New("debug", true); err != nil {
+					return err
+				}
+				defer log.Sync()
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			report, err := Run(ctx, opts, log)
+			if err != nil {
+				return err
+			}
+
+			report.Print(os.Stdout)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.ServerURL, "server", "s", opts.ServerURL, "Server WebSocket URL")
+	cmd.Flags().IntVarP(&opts.Clients, "clients", "c", 16, "Number of simulated players")
+	cmd.Flags().IntVarP(&opts.Rooms, "rooms", "r", 2, "Number of rooms to spread players over")
+	cmd.Flags().DurationVarP(&opts.Duration, "duration", "d", time.Minute, "How long to run the test")
+	cmd.Flags().DurationVar(&opts.RampUp, "ramp-up", 5*time.Second, "Time over which clients connect")
+	cmd.Flags().Float64VarP(&betAmount, "amount", "a", cfg.Game.MinBet, "Amount each player bets per round")
+	cmd.Flags().Float64Var(&balance, "balance", cfg.Game.StartingBalance, "Balance each player joins with")
+	cmd.Flags().StringVar((*string)(&opts.Encoding), "encoding", string(opts.Encoding), "Wire encoding: json or msgpack")
+	cmd.Flags().StringVar(&opts.RoomPrefix, "room-prefix", "loadtest", "Prefix for generated room IDs")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Log client activity")
+	cmd.Flags().MarkDeprecated("balance", "the server keeps each player's balance and ignores it")
+
+	return cmd
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"coinflip-game/internal/network"
+)
+
+// Report summarises a load test run
+type Report struct {
+	Clients          int
+	Connected        int
+	ConnectFailures  int
+	Disconnects      int
+	Elapsed          time.Duration
+	MessagesReceived int
+	BetsPlaced       int
+	Errors           int
+	DroppedUpdates   int
+	RoundsStarted    int
+	RoundsCompleted  int
+	RoundsCancelled  int
+	LatencyP50       time.Duration
+	LatencyP95       time.Duration
+	LatencyP99       time.Duration
+	LatencyMax       time.Duration
+}
+
+// RoundsPerMinute returns completed rounds per minute across all rooms
+func (r *Report) RoundsPerMinute() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.RoundsCompleted) / r.Elapsed.Minutes()
+}
+
+// MessagesPerSecond returns the rate of messages received by all clients
+func (r *Report) MessagesPerSecond() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.MessagesReceived) / r.Elapsed.Seconds()
+}
+
+// Print writes a human-readable summary
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintln(w, "📈 Load Test Report")
+	fmt.Fprintln(w, "===================")
+	fmt.Fprintf(w, "Duration:          %s\n", r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "Clients:           %d connected / %d requested (%d failed, %d disconnected)\n",
+		r.Connected, r.Clients, r.ConnectFailures, r.Disconnects)
+	fmt.Fprintf(w, "Messages received: %d (%.1f/s)\n", r.MessagesReceived, r.MessagesPerSecond())
+	fmt.Fprintf(w, "Bets placed:       %d\n", r.BetsPlaced)
+	fmt.Fprintf(w, "Errors:            %d\n", r.Errors)
+	fmt.Fprintf(w, "Dropped updates:   %d\n", r.DroppedUpdates)
+	fmt.Fprintf(w, "Rounds:            %d started, %d completed, %d cancelled (%.2f/min)\n",
+		r.RoundsStarted, r.RoundsCompleted, r.RoundsCancelled, r.RoundsPerMinute())
+	fmt.Fprintf(w, "Latency:           p50 %s, p95 %s, p99 %s, max %s\n",
+		r.LatencyP50, r.LatencyP95, r.LatencyP99, r.LatencyMax)
+}
+
+// collector accumulates metrics from every simulated player
+type collector struct {
+	mu              sync.Mutex
+	connects        int
+	connectFailures int
+	disconnects     int
+	messages        int
+	bets            int
+	errors          int
+	droppedUpdates  int
+	latencies       []time.Duration
+	startedRounds   map[string]bool
+	completedRounds map[string]bool
+	cancelledRounds map[string]bool
+}
+
+// newCollector creates an empty collector
+func newCollector() *collector {
+	return &collector{
+		startedRounds:   make(map[string]bool),
+		completedRounds: make(map[string]bool),
+		cancelledRounds: make(map[string]bool),
+	}
+}
+
+func (c *collector) connected() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connects++
+}
+
+func (c *collector) connectFailed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connectFailures++
+}
+
+func (c *collector) disconnected() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.disconnects++
+}
+
+func (c *collector) betPlaced() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bets++
+}
+
+func (c *collector) failed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errors++
+}
+
+func (c *collector) dropped(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.droppedUpdates += n
+}
+
+// received records a message and its delivery latency from the server timestamp
+func (c *collector) received(msg *network.Message, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages++
+	if !msg.Timestamp.IsZero() {
+		c.latencies = append(c.latencies, at.Sub(msg.Timestamp))
+	}
+}
+
+// Rounds are keyed by room so every player in a room counts a round once
+func (c *collector) roundStarted(roomID, roundID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.startedRounds[roomID+"/"+roundID] = true
+}
+
+func (c *collector) roundCompleted(roomID, roundID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.completedRounds[roomID+"/"+roundID] = true
+}
+
+func (c *collector) roundCancelled(roomID, roundID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancelledRounds[roomID+"/"+roundID] = true
+}
+
+// report builds the final report
+func (c *collector) report(opts Options, elapsed time.Duration) *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &Report{
+		Clients:          opts.Clients,
+		Connected:        c.connects,
+		ConnectFailures:  c.connectFailures,
+		Disconnects:      c.disconnects,
+		Elapsed:          elapsed,
+		MessagesReceived: c.messages,
+		BetsPlaced:       c.bets,
+		Errors:           c.errors,
+		DroppedUpdates:   c.droppedUpdates,
+		RoundsStarted:    len(c.startedRounds),
+		RoundsCompleted:  len(c.completedRounds),
+		RoundsCancelled:  len(c.cancelledRounds),
+	}
+
+	if len(c.latencies) > 0 {
+		sorted := append([]time.Duration(nil), c.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		report.LatencyP50 = percentile(sorted, 0.50)
+		report.LatencyP95 = percentile(sorted, 0.95)
+		report.LatencyP99 = percentile(sorted, 0.99)
+		report.LatencyMax = sorted[len(sorted)-1]
+	}
+
+	return report
+}
+
+// percentile returns the q-th percentile of sorted durations
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	index := int(q * float64(len(sorted)-1))
+	return sorted[index]
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// Options configures a load test run
+type Options struct {
+	ServerURL  string
+	Clients    int
+	Rooms      int
+	Duration   time.Duration
+	RampUp     time.Duration
+	BetAmount  game.Money
+	Balance    game.Money
+	Encoding   network.Encoding
+	RoomPrefix string
+}
+
+// Validate checks that the options describe a runnable test
+func (o Options) Validate() error {
+	if o.ServerURL == "" {
+		return errors.New("server URL is required")
+	}
+	if o.Clients <= 0 {
+		return errors.New("clients must be positive")
+	}
+	if o.Rooms <= 0 {
+		return errors.New("rooms must be positive")
+	}
+	if o.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+	if o.RampUp < 0 {
+		return errors.New("ramp-up cannot be negative")
+	}
+	if o.BetAmount <= 0 {
+		return errors.New("bet amount must be positive")
+	}
+	return nil
+}
+
+// Run connects the simulated players, lets them play until the duration
+// elapses or ctx is cancelled, and returns the collected report
+func Run(ctx context.Context, opts Options, logger *zap.Logger) (*Report, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.RampUp+opts.Duration)
+	defer cancel()
+
+	stats := newCollector()
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Clients; i++ {
+		// Spread connections evenly across the ramp-up window
+		if i > 0 && opts.RampUp > 0 {
+			select {
+			case <-time.After(opts.RampUp / time.Duration(opts.Clients)):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		player := &simulatedPlayer{
+			id:     fmt.Sprintf("%s_player_%d", opts.RoomPrefix, i),
+			roomID: fmt.Sprintf("%s_room_%d", opts.RoomPrefix, i%opts.Rooms),
+			opts:   opts,
+			stats:  stats,
+			logger: logger,
+			rng:    rand.New(rand.NewSource(time.Now().UnixNano() + int64(i))),
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			player.run(ctx)
+		}()
+	}
+
+	wg.Wait()
+	return stats.report(opts, time.Since(start)), nil
+}
+
+// simulatedPlayer is one WebSocket client that bets every round
+type simulatedPlayer struct {
+	id     string
+	roomID string
+	opts   Options
+	stats  *collector
+	logger *zap.Logger
+	rng    *rand.Rand
+
+	lastSecondsLeft int
+	hasBet          bool
+}
+
+// run plays until ctx is done
+func (p *simulatedPlayer) run(ctx context.Context) {
+	clientConfig := network.DefaultClientConfig()
+	clientConfig.ServerURL = p.opts.ServerURL
+	clientConfig.Encoding = p.opts.Encoding
+	clientConfig.MaxReconnects = 0
+
+	client := network.NewNetworkClient(clientConfig, p.id, p.id, p.logger)
+	if err := client.Connect(); err != nil {
+		p.stats.connectFailed()
+		p.logger.Warn("Load test client failed to connect", zap.String("player_id", p.id), zap.Error(err))
+		return
+	}
+	defer client.Disconnect()
+	p.stats.connected()
+
+	if err := client.JoinRoom(p.roomID, p.opts.Balance); err != nil {
+		p.stats.failed()
+		return
+	}
+
+	events := client.GetEventChannel()
+	errs := client.GetErrorChannel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-errs:
+			p.stats.disconnected()
+			return
+		case msg := <-events:
+			p.stats.received(msg, time.Now())
+			p.handle(client, msg)
+		}
+	}
+}
+
+// handle reacts to one server message
+func (p *simulatedPlayer) handle(client *network.NetworkClient, msg *network.Message) {
+	switch msg.Type {
+	case network.MsgBetPhase:
+		var timer network.TimerData
+		if err := msg.GetData(&timer); err == nil {
+			p.lastSecondsLeft = timer.SecondsLeft
+		}
+		p.placeBet(client)
+
+	case network.MsgRoomUpdate:
+		var update network.RoomUpdateData
+		if err := msg.GetData(&update); err == nil && update.GameState == network.StateBetting {
+			p.placeBet(client)
+		}
+
+	case network.MsgGameStart:
+		var roundID string
+		if err := msg.GetData(&roundID); err == nil {
+			p.stats.roundStarted(p.roomID, roundID)
+		}
+
+	case network.MsgTimerUpdate:
+		var timer network.TimerData
+		if err := msg.GetData(&timer); err != nil {
+			return
+		}
+		// Countdown updates arrive once per second; gaps mean updates were dropped
+		if p.lastSecondsLeft > 0 && timer.SecondsLeft < p.lastSecondsLeft-1 {
+			p.stats.dropped(p.lastSecondsLeft - 1 - timer.SecondsLeft)
+		}
+		p.lastSecondsLeft = timer.SecondsLeft
+
+	case network.MsgGameResult:
+		var result network.GameResultData
+		if err := msg.GetData(&result); err == nil {
+			p.stats.roundCompleted(p.roomID, result.RoundID)
+		}
+		p.hasBet = false
+
+	case network.MsgRoundCancelled:
+		var cancelled network.RoundCancelledData
+		if err := msg.GetData(&cancelled); err == nil {
+			p.stats.roundCancelled(p.roomID, cancelled.RoundID)
+		}
+		p.hasBet = false
+
+	case network.MsgError:
+		p.stats.failed()
+	}
+}
+
+// placeBet bets once per round on a random side
+func (p *simulatedPlayer) placeBet(client *network.NetworkClient) {
+	if p.hasBet {
+		return
+	}
+
+	choice := game.Heads
+	if p.rng.Intn(2) == 1 {
+		choice = game.Tails
+	}
+
+	if err := client.PlaceBet(p.opts.BetAmount, choice); err != nil {
+		p.stats.failed()
+		return
+	}
+	p.hasBet = true
+	p.stats.betPlaced()
+}
@@ -0,0 +1,201 @@
+// Package clock provides an injectable time source so time-based game logic
+// (betting deadlines, reconnect delays, timestamps) can be tested deterministically.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts the parts of the time package the game depends on
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker abstracts time.Ticker
+type Ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+}
+
+// Real is a Clock backed by the system clock
+type Real struct{}
+
+// New returns the system clock
+func New() Clock {
+	return Real{}
+}
+
+// Now returns the current system time
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse and then sends the current time
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewTicker returns a ticker backed by time.Ticker
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+// realTicker adapts time.Ticker to the Ticker interface
+type realTicker struct {
+	*time.Ticker
+}
+
+// Chan returns the channel on which ticks are delivered
+func (t realTicker) Chan() <-chan time.Time {
+	return t.C
+}
+
+// Fake is a manually advanced Clock for tests. Time only moves when
+// Advance or Set is called, firing any timers and tickers that become due.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is a pending After channel or ticker
+type fakeWaiter struct {
+	until  time.Time
+	period time.Duration // zero for one-shot waiters
+	ch     chan time.Time
+}
+
+// NewFake creates a fake clock starting at the given time
+func NewFake(start time.Time) *Fake {
+	f := &Fake{now: start}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now returns the fake current time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After returns a channel that receives once the clock has advanced by d
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+
+	f.addWaiter(&fakeWaiter{until: f.now.Add(d), ch: ch})
+	return ch
+}
+
+// NewTicker returns a ticker that fires each time the clock advances past a period
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	waiter := &fakeWaiter{until: f.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	f.addWaiter(waiter)
+	return &fakeTicker{clock: f, waiter: waiter}
+}
+
+// Advance moves the clock forward by d, firing due timers and tickers
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(f.now.Add(d))
+}
+
+// Set moves the clock to t, firing due timers and tickers
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(t)
+}
+
+// BlockUntil blocks until at least n timers or tickers are waiting on the clock.
+// It lets tests synchronise with goroutines before advancing time.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+// addWaiter registers a waiter; callers must hold f.mu
+func (f *Fake) addWaiter(w *fakeWaiter) {
+	f.waiters = append(f.waiters, w)
+	f.cond.Broadcast()
+}
+
+// removeWaiter unregisters a waiter; callers must hold f.mu
+func (f *Fake) removeWaiter(w *fakeWaiter) {
+	for i, existing := range f.waiters {
+		if existing == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// setLocked moves time forward and fires waiters; callers must hold f.mu
+func (f *Fake) setLocked(t time.Time) {
+	if t.Before(f.now) {
+		return
+	}
+	f.now = t
+
+	remaining := f.waiters[:0]
+	for _, w := range f.waiters {
+		if t.Before(w.until) {
+			remaining = append(remaining, w)
+			continue
+		}
+
+		// Like the real implementations, drop ticks nobody has received yet
+		select {
+		case w.ch <- t:
+		default:
+		}
+
+		if w.period > 0 {
+			for !t.Before(w.until) {
+				w.until = w.until.Add(w.period)
+			}
+			remaining = append(remaining, w)
+		}
+	}
+	f.waiters = remaining
+}
+
+// fakeTicker is a ticker driven by a Fake clock
+type fakeTicker struct {
+	clock  *Fake
+	waiter *fakeWaiter
+}
+
+// Chan returns the channel on which ticks are delivered
+func (t *fakeTicker) Chan() <-chan time.Time {
+	return t.waiter.ch
+}
+
+// Stop turns off the ticker
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.clock.removeWaiter(t.waiter)
+}
@@ -0,0 +1,97 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReal_Now(t *testing.T) {
+	before := time.Now()
+	now := New().Now()
+	after := time.Now()
+
+	assert.False(t, now.Before(before))
+	assert.False(t, now.After(after))
+}
+
+func TestFake_After(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	ch := fake.After(5 * time.Second)
+
+	fake.Advance(4 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	fake.Advance(time.Second)
+	select {
+	case fired := <-ch:
+		assert.Equal(t, start.Add(5*time.Second), fired)
+	default:
+		t.Fatal("timer did not fire")
+	}
+
+	assert.Equal(t, start.Add(5*time.Second), fake.Now())
+}
+
+func TestFake_AfterNonPositive(t *testing.T) {
+	fake := NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	select {
+	case <-fake.After(0):
+	default:
+		t.Fatal("zero-duration timer should fire immediately")
+	}
+}
+
+func TestFake_Ticker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	ticker := fake.NewTicker(time.Second)
+
+	fake.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Second), <-ticker.Chan())
+
+	// Ticks that are not received are dropped rather than queued
+	fake.Advance(3 * time.Second)
+	assert.Equal(t, start.Add(4*time.Second), <-ticker.Chan())
+	select {
+	case <-ticker.Chan():
+		t.Fatal("unexpected queued tick")
+	default:
+	}
+
+	ticker.Stop()
+	fake.Advance(time.Second)
+	select {
+	case <-ticker.Chan():
+		t.Fatal("stopped ticker fired")
+	default:
+	}
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	fake := NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	done := make(chan struct{})
+
+	go func() {
+		<-fake.After(time.Minute)
+		close(done)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not released")
+	}
+}
@@ -1,12 +1,19 @@
 package config
 
 import (
+	"image/color"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+	"coinflip-game/internal/storage"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -22,6 +29,9 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, "dark", config.UI.Theme)
 	assert.Equal(t, 800, config.UI.WindowWidth)
 	assert.Equal(t, 600, config.UI.WindowHeight)
+	assert.True(t, config.UI.SystemTray)
+	assert.True(t, config.UI.Notifications)
+	assert.True(t, config.UI.ShowTutorial)
 }
 
 func TestConfig_Validate(t *testing.T) {
@@ -104,6 +114,20 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedError: "max_bet (100.000000) must be greater than min_bet (100.000000)",
 		},
+		{
+			name: "min bet with a fraction of a cent",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance: 1000,
+					MinBet:          0.005,
+					MaxBet:          100,
+					PayoutRatio:     2.0,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI:      UIConfig{Theme: "dark", WindowWidth: 800, WindowHeight: 600},
+			},
+			expectedError: "min_bet must be a whole number of cents",
+		},
 		{
 			name: "payout ratio too low",
 			config: &Config{
@@ -118,6 +142,37 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedError: "payout_ratio must be greater than 1.0",
 		},
+		{
+			name: "streak multiplier cap below one",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance:     1000,
+					MinBet:              1,
+					MaxBet:              100,
+					PayoutRatio:         2.0,
+					StreakBonus:         0.1,
+					MaxStreakMultiplier: 0.5,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI:      UIConfig{Theme: "dark", WindowWidth: 800, WindowHeight: 600},
+			},
+			expectedError: "max_streak_multiplier must be at least 1.0",
+		},
+		{
+			name: "insurance cost without coverage",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance: 1000,
+					MinBet:          1,
+					MaxBet:          100,
+					PayoutRatio:     2.0,
+					InsuranceCost:   0.1,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI:      UIConfig{Theme: "dark", WindowWidth: 800, WindowHeight: 600},
+			},
+			expectedError: "insurance cost and coverage must both be set",
+		},
 		{
 			name: "invalid logging level",
 			config: &Config{
@@ -174,6 +229,470 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedError: "invalid theme 'invalid'",
 		},
+		{
+			name: "text scale out of range",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance: 1000,
+					MinBet:          1,
+					MaxBet:          100,
+					PayoutRatio:     2.0,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI:      UIConfig{Theme: "dark", WindowWidth: 800, WindowHeight: 600, TextScale: 5},
+			},
+			expectedError: "text_scale must be between",
+		},
+		{
+			name: "invalid accent colour",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance: 1000,
+					MinBet:          1,
+					MaxBet:          100,
+					PayoutRatio:     2.0,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI:      UIConfig{Theme: "dark", WindowWidth: 800, WindowHeight: 600, AccentColor: "#12345g"},
+			},
+			expectedError: "accent_color: invalid colour",
+		},
+		{
+			name: "font size out of range",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance: 1000,
+					MinBet:          1,
+					MaxBet:          100,
+					PayoutRatio:     2.0,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI:      UIConfig{Theme: "dark", WindowWidth: 800, WindowHeight: 600, FontSize: 4},
+			},
+			expectedError: "font_size must be between",
+		},
+		{
+			name: "suggest edge out of range",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance: 1000,
+					MinBet:          1,
+					MaxBet:          100,
+					PayoutRatio:     2.0,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI:      UIConfig{Theme: "dark", WindowWidth: 800, WindowHeight: 600, SuggestEdge: 0.5},
+			},
+			expectedError: "suggest_edge must be at least -0.5",
+		},
+		{
+			name: "kelly fraction out of range",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance: 1000,
+					MinBet:          1,
+					MaxBet:          100,
+					PayoutRatio:     2.0,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI:      UIConfig{Theme: "dark", WindowWidth: 800, WindowHeight: 600, KellyFraction: 1.5},
+			},
+			expectedError: "kelly_fraction must be between 0 and 1",
+		},
+		{
+			name: "invalid Discord application ID",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance: 1000,
+					MinBet:          1,
+					MaxBet:          100,
+					PayoutRatio:     2.0,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI:      UIConfig{Theme: "dark", WindowWidth: 800, WindowHeight: 600, DiscordAppID: "coinflip"},
+			},
+			expectedError: "discord_app_id must be a Discord application ID",
+		},
+		{
+			name: "guest ID as account",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance: 1000,
+					MinBet:          1,
+					MaxBet:          100,
+					PayoutRatio:     2.0,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI:      UIConfig{Theme: "dark", WindowWidth: 800, WindowHeight: 600, Account: "guest_1234"},
+			},
+			expectedError: "account: invalid account name",
+		},
+		{
+			name: "key bound twice",
+			config: &Config{
+				Game: GameConfig{
+					StartingBalance: 1000,
+					MinBet:          1,
+					MaxBet:          100,
+					PayoutRatio:     2.0,
+				},
+				Logging: LoggingConfig{Level: "info"},
+				UI: UIConfig{
+					Theme:        "dark",
+					WindowWidth:  800,
+					WindowHeight: 600,
+					KeyBindings:  KeyBindings{Flip: "h"},
+				},
+			},
+			expectedError: "key_bindings.heads and key_bindings.flip both use",
+		},
+		{
+			name: "message size out of bounds",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MaxMessageSize = 100
+				return config
+			}(),
+			expectedError: "max_message_size must be between 1024 and 1048576",
+		},
+		{
+			name: "pong wait not after ping",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.PingPeriodSeconds = 30
+				config.Multiplayer.PongWaitSeconds = 30
+				return config
+			}(),
+			expectedError: "pong_wait_seconds (30) must be greater than ping_period_seconds (30)",
+		},
+		{
+			name: "pong wait before default ping",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.PingPeriodSeconds = 0
+				config.Multiplayer.PongWaitSeconds = 20
+				return config
+			}(),
+			expectedError: "pong_wait_seconds (20) must be greater than ping_period_seconds (54)",
+		},
+		{
+			name: "countdown interval out of bounds",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.CountdownIntervalSeconds = 120
+				return config
+			}(),
+			expectedError: "countdown_interval_seconds must be between 1 and 60",
+		},
+		{
+			name: "early close longer than betting",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.BettingDuration = 10
+				config.Multiplayer.EarlyClose = true
+				config.Multiplayer.EarlyCloseSeconds = 20
+				return config
+			}(),
+			expectedError: "early_close_seconds (20) must not exceed betting_duration_seconds (10)",
+		},
+		{
+			name: "idle seats released before players go idle",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.IdleRounds = 3
+				config.Multiplayer.IdleReleaseRounds = 2
+				return config
+			}(),
+			expectedError: "idle_release_rounds (2) must be 0 or more than idle_rounds (3)",
+		},
+		{
+			name: "negative betting limit",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MaxLiability = -100
+				return config
+			}(),
+			expectedError: "max_liability must be zero or a positive whole number of cents, got -100",
+		},
+		{
+			name: "betting limit with a fraction of a cent",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MaxPot = 500.125
+				return config
+			}(),
+			expectedError: "max_pot must be zero or a positive whole number of cents",
+		},
+		{
+			name: "negative big win",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.BigWin = -5
+				return config
+			}(),
+			expectedError: "big_win must be zero or a positive whole number of cents, got -5",
+		},
+		{
+			name: "payout ratio cap of 1",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MaxPayoutRatio = 1
+				return config
+			}(),
+			expectedError: "max_payout_ratio must be greater than 1.0 or 0 for no cap, got 1",
+		},
+		{
+			name: "streak multiplier cap below 1",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MaxRoomStreakMultiplier = 0.5
+				return config
+			}(),
+			expectedError: "max_room_streak_multiplier must be at least 1.0 or 0 for no cap, got 0.5",
+		},
+		{
+			name: "insurance cost floor above 1",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MinInsuranceCost = 1.5
+				return config
+			}(),
+			expectedError: "min_insurance_cost must be between 0 and 1, got 1.5",
+		},
+		{
+			name: "negative insurance coverage cap",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MaxInsuranceCoverage = -0.5
+				return config
+			}(),
+			expectedError: "max_insurance_coverage must be between 0 and 1, got -0.5",
+		},
+		{
+			name: "freeroll conversion rate above 1",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.FreerollConversionRate = 1.5
+				return config
+			}(),
+			expectedError: "freeroll_conversion_rate must be between 0 and 1, got 1.5",
+		},
+		{
+			name: "every server feature off",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.EnableWebSocket = false
+				config.Multiplayer.EnableREST = false
+				config.Multiplayer.EnableMetrics = false
+				config.Multiplayer.EnableAdmin = false
+				return config
+			}(),
+			expectedError: "at least one of enable_websocket, enable_rest, enable_metrics and enable_admin must be on",
+		},
+		{
+			name: "negative server port",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.ServerPort = -1
+				return config
+			}(),
+			expectedError: "server_port must be between 1 and 65535, got -1",
+		},
+		{
+			name: "server port out of range",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.ServerPort = 70000
+				return config
+			}(),
+			expectedError: "server_port must be between 1 and 65535, got 70000",
+		},
+		{
+			name: "server host with a scheme",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.ServerHost = "ws://localhost"
+				return config
+			}(),
+			expectedError: `server_host must be a host name or address without scheme or path, got "ws://localhost"`,
+		},
+		{
+			name: "zero max rooms",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MaxRooms = 0
+				return config
+			}(),
+			expectedError: "max_rooms must be at least 1, got 0",
+		},
+		{
+			name: "zero min players",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MinPlayers = 0
+				return config
+			}(),
+			expectedError: "min_players must be at least 1, got 0",
+		},
+		{
+			name: "zero max players",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MaxPlayers = 0
+				return config
+			}(),
+			expectedError: "max_players (0) must be at least min_players (2)",
+		},
+		{
+			name: "min players above max",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.MinPlayers = 6
+				config.Multiplayer.MaxPlayers = 4
+				return config
+			}(),
+			expectedError: "max_players (4) must be at least min_players (6)",
+		},
+		{
+			name: "zero betting duration",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.BettingDuration = 0
+				return config
+			}(),
+			expectedError: "betting_duration_seconds must be positive, got 0",
+		},
+		{
+			name: "negative result duration",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.ResultDuration = -5
+				return config
+			}(),
+			expectedError: "result_duration_seconds must be positive, got -5",
+		},
+		{
+			name: "latency grace as long as betting",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.BettingDuration = 1
+				config.Multiplayer.MaxLatencyGraceMs = 1000
+				return config
+			}(),
+			expectedError: "max_latency_grace_ms (1000) must be less than betting_duration_seconds (1)",
+		},
+		{
+			name: "auto join without a room",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.DefaultRoom = ""
+				return config
+			}(),
+			expectedError: "default_room must be set when auto_join is enabled",
+		},
+		{
+			name: "default room with spaces",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.DefaultRoom = "high rollers"
+				return config
+			}(),
+			expectedError: `default_room must not contain spaces, got "high rollers"`,
+		},
+		{
+			name: "default room too long",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.DefaultRoom = strings.Repeat("r", 65)
+				return config
+			}(),
+			expectedError: "default_room must be at most 64 characters",
+		},
+		{
+			name: "unknown encoding",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.Encoding = "xml"
+				return config
+			}(),
+			expectedError: `encoding must be json or msgpack, got "xml"`,
+		},
+		{
+			name: "archive enabled without directory",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Archive = ArchiveConfig{Enabled: true, ArchiveAfterDays: 30, IntervalMinutes: 60}
+				return config
+			}(),
+			expectedError: "archive directory must be set",
+		},
+		{
+			name: "balance snapshots every 0 minutes",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Balances.Enabled = true
+				config.Balances.IntervalMinutes = 0
+				return config
+			}(),
+			expectedError: "balance snapshot interval_minutes must be positive",
+		},
+		{
+			name: "export in an unsupported format",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Export.Enabled = true
+				config.Export.Format = "parquet"
+				return config
+			}(),
+			expectedError: `export format must be csv, got "parquet"`,
+		},
+		{
+			name: "tracing with a sample ratio above one",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Tracing.Enabled = true
+				config.Tracing.SampleRatio = 1.5
+				return config
+			}(),
+			expectedError: "tracing sample_ratio must be between 0 and 1",
+		},
+		{
+			name: "notifications without a channel",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Notify.Enabled = true
+				return config
+			}(),
+			expectedError: "notifications need a telegram_token or an smtp_host",
+		},
+		{
+			name: "email notifications without a sender",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Notify.Enabled = true
+				config.Notify.SMTPHost = "smtp.example.com"
+				return config
+			}(),
+			expectedError: "notify email_from must be an email address",
+		},
+		{
+			name: "happy hour with an unknown day",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.HappyHours = []HappyHourConfig{{Boost: 1.5, Start: "18:00", DurationMinutes: 60, Days: []string{"someday"}}}
+				return config
+			}(),
+			expectedError: `happy_hours[0]: unknown day "someday"`,
+		},
+		{
+			name: "happy hour that lowers payouts",
+			config: func() *Config {
+				config := DefaultConfig()
+				config.Multiplayer.HappyHours = []HappyHourConfig{{Boost: 0.5, Start: "18:00", DurationMinutes: 60}}
+				return config
+			}(),
+			expectedError: "boost must be above 1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -197,15 +716,115 @@ func TestConfig_ToGameConfig(t *testing.T) {
 			MinBet:          5.0,
 			MaxBet:          50.0,
 			PayoutRatio:     1.5,
+
+			RepositoryTimeoutMs: 250,
 		},
 	}
 
 	gameConfig := config.ToGameConfig()
 
-	assert.Equal(t, 500.0, gameConfig.StartingBalance)
-	assert.Equal(t, 5.0, gameConfig.MinBet)
-	assert.Equal(t, 50.0, gameConfig.MaxBet)
+	assert.Equal(t, 500*game.Dollar, gameConfig.StartingBalance)
+	assert.Equal(t, 5*game.Dollar, gameConfig.MinBet)
+	assert.Equal(t, 50*game.Dollar, gameConfig.MaxBet)
 	assert.Equal(t, 1.5, gameConfig.PayoutRatio)
+	assert.Equal(t, 250*time.Millisecond, gameConfig.RepositoryTimeout)
+}
+
+func TestConfig_ToServerConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.Multiplayer.PingPeriodSeconds = 20
+	config.Multiplayer.PongWaitSeconds = 25
+	config.Multiplayer.MaxMessageSize = 8192
+	config.Multiplayer.MaxLatencyGraceMs = 250
+	config.Multiplayer.UpdateIntervalMs = 250
+	config.Multiplayer.CountdownIntervalSeconds = 5
+	config.Multiplayer.CleanupIntervalSeconds = 0
+	config.Multiplayer.MaxRoundPayout = 2500
+	config.Multiplayer.MaxLiability = 10000
+	config.Multiplayer.MaxOfflineWinnings = 250
+	config.Multiplayer.BigWin = 75
+	config.Multiplayer.MaxStartingStack = 300
+	config.Multiplayer.FreerollConversionRate = 0.2
+	config.Multiplayer.EnableMetrics = false
+	config.Multiplayer.MaxConnections = 500
+	config.Multiplayer.BusyRetryAfterSeconds = 30
+	config.Multiplayer.MaxStoredPlayers = 10000
+	config.Multiplayer.StoredTTLHours = 48
+	config.Multiplayer.ScaleBets = true
+	config.Multiplayer.MaxRoomBet = 400
+	config.Multiplayer.MaxPayoutRatio = 1.95
+	config.Multiplayer.MaxStreakBonus = 0.1
+	config.Multiplayer.MaxRoomStreakMultiplier = 2
+	config.Multiplayer.MinInsuranceCost = 0.3
+	config.Multiplayer.MaxInsuranceCoverage = 0.4
+	config.Game.PayoutRatio = 1.9
+	config.Multiplayer.EarlyClose = true
+	config.Multiplayer.EarlyCloseSeconds = 3
+	config.Multiplayer.IdleRounds = 3
+	config.Multiplayer.IdleReleaseRounds = 5
+	config.Multiplayer.HappyHours = []HappyHourConfig{{Room: "vip", Boost: 1.5, Start: "18:30", DurationMinutes: 90, Days: []string{"Sat", "sunday"}}}
+	config.Game.StartingBalance = 500
+	config.Admin.Password = "hunter2"
+
+	serverConfig := config.ToServerConfig()
+
+	assert.Equal(t, 20*time.Second, serverConfig.PingPeriod)
+	assert.Equal(t, 25*time.Second, serverConfig.PongWait)
+	assert.Equal(t, int64(8192), serverConfig.MaxMessageSize)
+	assert.Equal(t, 5*time.Second, serverConfig.LatencyInterval)
+	assert.Equal(t, 5*time.Minute, serverConfig.CleanupInterval, "zero keeps the server default")
+	assert.Equal(t, 8, serverConfig.MaxClientsRoom)
+	assert.Equal(t, 250*time.Millisecond, serverConfig.RoomDefaults.MaxLatencyGrace)
+	assert.Equal(t, 250*time.Millisecond, serverConfig.RoomDefaults.UpdateInterval)
+	assert.Equal(t, 5*time.Second, serverConfig.CountdownInterval)
+	assert.Equal(t, 60*time.Second, serverConfig.RoomDefaults.BettingDuration)
+	assert.Equal(t, network.BetLimits{
+		MaxRoundPayout:       2500 * game.Dollar,
+		ScaleBets:            true,
+		MaxBet:               400 * game.Dollar,
+		MaxPayoutRatio:       1.95,
+		MaxStreakBonus:       0.1,
+		MaxStreakMultiplier:  2,
+		MinInsuranceCost:     0.3,
+		MaxInsuranceCoverage: 0.4,
+	}, serverConfig.RoomDefaults.Limits)
+
+	// Caps never refuse the server's own settings
+	config.Game.MaxBet = 500
+	config.Game.PayoutRatio = 2.5
+	config.Game.StreakBonus = 0.5
+	config.Game.InsuranceCost = 0.1
+	config.Game.InsuranceCoverage = 0.5
+	limits := config.ToServerConfig().RoomDefaults.Limits
+	assert.Equal(t, 500*game.Dollar, limits.MaxBet)
+	assert.Equal(t, 2.5, limits.MaxPayoutRatio)
+	assert.Equal(t, 0.5, limits.MaxStreakBonus)
+	assert.Equal(t, 0.0, limits.MaxStreakMultiplier, "the game's streak multiplier has no cap")
+	assert.Equal(t, 0.1, limits.MinInsuranceCost)
+	assert.Equal(t, 0.5, limits.MaxInsuranceCoverage)
+	assert.Equal(t, 10000*game.Dollar, serverConfig.MaxLiability)
+	assert.Equal(t, 250*game.Dollar, serverConfig.MaxOfflineWinnings)
+	assert.Equal(t, 75*game.Dollar, serverConfig.BigWin)
+	assert.Equal(t, network.Freeroll{MaxStack: 300 * game.Dollar, ConversionRate: 0.2}, serverConfig.RoomDefaults.Freeroll)
+	assert.Equal(t, network.Features{WebSocket: true, REST: true, Admin: true}, serverConfig.Features)
+	assert.Equal(t, 500, serverConfig.MaxConnections)
+	assert.Equal(t, 30*time.Second, serverConfig.BusyRetryAfter)
+	assert.Equal(t, storage.MemoryLimits{MaxPlayers: 10000, TTL: 48 * time.Hour}, serverConfig.Storage)
+	assert.Equal(t, 500*game.Dollar, serverConfig.StartingBalance)
+	assert.Equal(t, network.AdminCredentials{Username: "admin", Password: "hunter2"}, serverConfig.Admin)
+	assert.True(t, serverConfig.RoomDefaults.EarlyCloseEnabled)
+	assert.Equal(t, 3*time.Second, serverConfig.RoomDefaults.EarlyCloseDelay)
+	assert.Equal(t, 3, serverConfig.RoomDefaults.IdleRounds)
+	assert.Equal(t, 5, serverConfig.RoomDefaults.IdleReleaseRounds)
+	assert.Equal(t, []network.PromotionRule{{
+		Name:     "Happy hour",
+		RoomID:   "vip",
+		Boost:    1.5,
+		Start:    18*time.Hour + 30*time.Minute,
+		Duration: 90 * time.Minute,
+		Weekdays: []time.Weekday{time.Saturday, time.Sunday},
+	}}, serverConfig.Promotions)
+	assert.NoError(t, serverConfig.RoomDefaults.Validate())
 }
 
 func TestLoad_DefaultsOnly(t *testing.T) {
@@ -220,6 +839,12 @@ func TestLoad_DefaultsOnly(t *testing.T) {
 	assert.Equal(t, defaultConfig.Game, config.Game)
 	assert.Equal(t, defaultConfig.Logging, config.Logging)
 	assert.Equal(t, defaultConfig.UI, config.UI)
+	assert.Equal(t, defaultConfig.Multiplayer, config.Multiplayer)
+	assert.Equal(t, defaultConfig.Archive, config.Archive)
+	assert.Equal(t, defaultConfig.Balances, config.Balances)
+	assert.Equal(t, defaultConfig.Export, config.Export)
+	assert.Equal(t, defaultConfig.Tracing, config.Tracing)
+	assert.Equal(t, defaultConfig.Notify, config.Notify)
 }
 
 func TestLoad_WithConfigFile(t *testing.T) {
@@ -266,6 +891,81 @@ func TestLoad_WithConfigFile(t *testing.T) {
 	assert.Equal(t, 768, config.UI.WindowHeight)
 }
 
+func TestConfig_SaveRoundTrip(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "nested", "config.json")
+
+	config := DefaultConfig()
+	config.Game.InsuranceCost = 0.1
+	config.Game.InsuranceCoverage = 0.5
+	config.UI.Theme = "light"
+	config.UI.PlayerName = "Alice"
+	config.UI.Account = "alice"
+	config.UI.DefaultBet = 15
+	config.UI.Sound = false
+	config.UI.QuickBets = []float64{1, 2.5, 20}
+	config.UI.ConfirmBets = true
+	config.UI.BetUndoSeconds = 5
+	config.UI.KeyBindings = KeyBindings{Heads: "J", Tails: "K", Flip: "Space", Cancel: "BackSpace"}
+	config.UI.HighContrast = true
+	config.UI.TextScale = 1.5
+	config.UI.AccentColor = "#ff8800"
+	config.UI.FontSize = 16
+	config.UI.SystemTray = false
+	config.UI.Notifications = false
+	config.UI.ShowTutorial = false
+	config.UI.FavoritesFile = "favorites/mine.json"
+	config.UI.NotesFile = "notes/mine.json"
+	config.UI.HistoryFile = "history/mine.jsonl"
+	config.UI.DiscordPresence = true
+	config.UI.DiscordAppID = "1234567890123456789"
+	config.Multiplayer.ServerHost = "game.example.com"
+	config.Multiplayer.ServerPort = 9090
+	config.Multiplayer.PongWaitSeconds = 90
+	config.Multiplayer.MaxMessageSize = 16384
+	config.Multiplayer.HappyHours = []HappyHourConfig{
+		{Name: "Friday night", Boost: 2, Start: "20:00", DurationMinutes: 120, Days: []string{"fri"}},
+		{Name: "Lunch", Room: "lobby", Boost: 1.25, Start: "12:00", DurationMinutes: 60},
+	}
+
+	require.NoError(t, config.Save(configFile))
+	assert.Equal(t, configFile, config.Path())
+
+	loaded, err := Load(configFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, config.Game, loaded.Game)
+	assert.Equal(t, config.UI, loaded.UI)
+	assert.Equal(t, config.Multiplayer, loaded.Multiplayer)
+	assert.Equal(t, configFile, loaded.Path())
+}
+
+func TestParseColor(t *testing.T) {
+	c, err := ParseColor("#FF8800")
+	require.NoError(t, err)
+	assert.Equal(t, color.NRGBA{R: 0xff, G: 0x88, A: 0xff}, c)
+
+	c, err = ParseColor("1e90ff")
+	require.NoError(t, err)
+	assert.Equal(t, color.NRGBA{R: 0x1e, G: 0x90, B: 0xff, A: 0xff}, c)
+
+	for _, invalid := range []string{"", "#fff", "#ff880", "#ff88001", "orange!"} {
+		_, err := ParseColor(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestConfig_SaveRejectsInvalid(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+
+	config := DefaultConfig()
+	config.UI.QuickBets = []float64{10, -5}
+
+	err := config.Save(configFile)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "quick_bets must be positive")
+	assert.NoFileExists(t, configFile)
+}
+
 func TestLoad_InvalidConfigFile(t *testing.T) {
 	// Create temporary invalid config file
 	tempDir := t.TempDir()
@@ -316,6 +1016,7 @@ func TestLoad_WithEnvironmentVariables(t *testing.T) {
 	os.Setenv("COINFLIP_GAME_MIN_BET", "2")
 	os.Setenv("COINFLIP_LOGGING_LEVEL", "warn")
 	os.Setenv("COINFLIP_UI_THEME", "light")
+	os.Setenv("COINFLIP_MULTIPLAYER_PING_PERIOD_SECONDS", "15")
 
 	// Clean up environment variables after test
 	defer func() {
@@ -323,6 +1024,7 @@ func TestLoad_WithEnvironmentVariables(t *testing.T) {
 		os.Unsetenv("COINFLIP_GAME_MIN_BET")
 		os.Unsetenv("COINFLIP_LOGGING_LEVEL")
 		os.Unsetenv("COINFLIP_UI_THEME")
+		os.Unsetenv("COINFLIP_MULTIPLAYER_PING_PERIOD_SECONDS")
 	}()
 
 	// Load config (should pick up environment variables)
@@ -336,6 +1038,7 @@ func TestLoad_WithEnvironmentVariables(t *testing.T) {
 	assert.Equal(t, 2.0, config.Game.MinBet)
 	assert.Equal(t, "warn", config.Logging.Level)
 	assert.Equal(t, "light", config.UI.Theme)
+	assert.Equal(t, 15, config.Multiplayer.PingPeriodSeconds)
 
 	// Other values should be defaults
 	assert.Equal(t, 100.0, config.Game.MaxBet)
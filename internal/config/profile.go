@@ -0,0 +1,174 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ProfileEnv names the environment variable that selects a profile when
+// no --profile flag is given
+const ProfileEnv = "COINFLIP_PROFILE"
+
+// ErrProfileNotFound is returned when loading a profile that was never saved
+var ErrProfileNotFound = errors.New("profile not found")
+
+// profileName limits profile names to what is safe as a file name
+var profileName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// ValidateProfileName checks a profile name is 1-64 letters, digits,
+// dashes or underscores, starting with a letter or digit
+func ValidateProfileName(name string) error {
+	if !profileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// ProfilesDir returns the directory holding the per-user profiles
+func ProfilesDir() string {
+	return filepath.Join(filepath.Dir(DefaultPath()), "profiles")
+}
+
+// ProfilePath returns the file a named profile is stored in
+func ProfilePath(name string) (string, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(ProfilesDir(), name+".json"), nil
+}
+
+// LoadProfile loads a named profile, with environment variables still
+// taking precedence over it. An empty name loads the regular configuration.
+func LoadProfile(name string) (*Config, error) {
+	if name == "" {
+		return Load("")
+	}
+
+	path, err := ProfilePath(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	config, err := Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	config.profile = name
+	return config, nil
+}
+
+// SaveProfile writes the configuration as a named profile, replacing any
+// profile of that name, and makes it the configuration's file
+func (c *Config) SaveProfile(name string) error {
+	path, err := ProfilePath(name)
+	if err != nil {
+		return err
+	}
+	if err := c.Save(path); err != nil {
+		return err
+	}
+	c.profile = name
+	return nil
+}
+
+// ImportProfile loads a configuration file, such as one exported on
+// another machine, and saves it as a named profile, replacing any profile
+// of that name
+func ImportProfile(path, name string) (*Config, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+
+	imported, err := Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import %s: %w", path, err)
+	}
+	if err := imported.SaveProfile(name); err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+// ExportTo writes a copy of the configuration to path for importing
+// elsewhere. Unlike Save, the configuration keeps its own file.
+func (c *Config) ExportTo(path string) error {
+	exported := *c
+	return exported.Save(path)
+}
+
+// Profile returns the name of the profile the configuration was loaded
+// from or saved as, empty for the regular configuration
+func (c *Config) Profile() string {
+	return c.profile
+}
+
+// maxSocketPath is the longest socket path every platform accepts
+const maxSocketPath = 100
+
+// InstancePath returns the socket the GUI holds while it runs with the
+// configuration's profile, so a second launch of the same profile finds
+// the first while other profiles run alongside it. Paths too long for a
+// socket move to the temporary directory.
+func (c *Config) InstancePath() string {
+	name := "gui.sock"
+	if c.profile != "" {
+		name = "gui-" + c.profile + ".sock"
+	}
+	path := filepath.Join(filepath.Dir(DefaultPath()), name)
+	if len(path) <= maxSocketPath {
+		return path
+	}
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(os.TempDir(), "coinflip-"+hex.EncodeToString(sum[:8])+".sock")
+}
+
+// ListProfiles returns the names of the saved profiles in order
+func ListProfiles() ([]string, error) {
+	entries, err := os.ReadDir(ProfilesDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || ValidateProfileName(name) != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ProfileFromArgs finds the profile selected on a command line, as
+// --profile NAME or --profile=NAME, falling back to COINFLIP_PROFILE. The
+// configuration is loaded before flags are parsed, so entry points look
+// the flag up here first.
+func ProfileFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--profile="); ok {
+			return value
+		}
+		if arg == "--profile" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return os.Getenv(ProfileEnv)
+}
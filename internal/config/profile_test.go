@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles_SaveLoadAndList(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	names, err := ListProfiles()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	tournament := DefaultConfig()
+	tournament.Game.MinBet = 10
+	tournament.Game.MaxBet = 500
+	require.NoError(t, tournament.SaveProfile("tournament"))
+	assert.Equal(t, "tournament", tournament.Profile())
+	assert.Equal(t, filepath.Join(home, ".coinflip", "profiles", "tournament.json"), tournament.Path())
+
+	require.NoError(t, DefaultConfig().SaveProfile("high-stakes"))
+	require.NoError(t, os.WriteFile(filepath.Join(ProfilesDir(), "notes.txt"), []byte("ignored"), 0644))
+
+	names, err = ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"high-stakes", "tournament"}, names)
+
+	loaded, err := LoadProfile("tournament")
+	require.NoError(t, err)
+	assert.Equal(t, "tournament", loaded.Profile())
+	assert.Equal(t, 10.0, loaded.Game.MinBet)
+	assert.Equal(t, 500.0, loaded.Game.MaxBet)
+
+	_, err = LoadProfile("local")
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+	_, err = LoadProfile("../config")
+	assert.Error(t, err)
+	assert.Error(t, DefaultConfig().SaveProfile(""))
+}
+
+func TestProfileFromArgs(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+
+	assert.Equal(t, "local", ProfileFromArgs([]string{"bet", "--profile", "local", "-a", "5"}))
+	assert.Equal(t, "tournament", ProfileFromArgs([]string{"--profile=tournament", "status"}))
+	assert.Empty(t, ProfileFromArgs([]string{"status", "--", "--profile", "local"}))
+
+	t.Setenv(ProfileEnv, "high-stakes")
+	assert.Equal(t, "high-stakes", ProfileFromArgs([]string{"status"}))
+}
+
+func TestProfiles_ExportImport(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := DefaultConfig()
+	config.Game.MinBet = 5
+	config.UI.DataDir = filepath.Join(home, "coinflip")
+	require.NoError(t, config.SaveProfile("home"))
+
+	// Exporting leaves the configuration saving to its own file
+	exported := filepath.Join(home, "shared", "settings.json")
+	require.NoError(t, config.ExportTo(exported))
+	assert.Equal(t, filepath.Join(ProfilesDir(), "home.json"), config.Path())
+
+	imported, err := ImportProfile(exported, "laptop")
+	require.NoError(t, err)
+	assert.Equal(t, "laptop", imported.Profile())
+	assert.Equal(t, 5.0, imported.Game.MinBet)
+	assert.Equal(t, filepath.Join(home, "coinflip"), imported.UI.DataDir)
+
+	names, err := ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"home", "laptop"}, names)
+
+	_, err = ImportProfile(exported, "../laptop")
+	assert.Error(t, err)
+	_, err = ImportProfile(filepath.Join(home, "missing.json"), "missing")
+	assert.Error(t, err)
+}
+
+func TestConfig_InstancePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	assert.Equal(t, filepath.Join(home, ".coinflip", "gui.sock"), DefaultConfig().InstancePath())
+
+	profile := DefaultConfig()
+	require.NoError(t, profile.SaveProfile("tournament"))
+	assert.Equal(t, filepath.Join(home, ".coinflip", "gui-tournament.sock"), profile.InstancePath())
+
+	// Each profile gets its own socket, however long its name
+	long := DefaultConfig()
+	require.NoError(t, long.SaveProfile(strings.Repeat("a", 64)))
+	other := DefaultConfig()
+	require.NoError(t, other.SaveProfile(strings.Repeat("b", 64)))
+	assert.LessOrEqual(t, len(long.InstancePath()), maxSocketPath)
+	assert.NotEqual(t, long.InstancePath(), other.InstancePath())
+}
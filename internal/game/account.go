@@ -0,0 +1,121 @@
+package game
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/logger"
+)
+
+// Account errors
+var (
+	ErrNotGuest            = errors.New("player is not a guest")
+	ErrInvalidAccount      = errors.New("invalid account name")
+	ErrAccountExists       = errors.New("account already exists")
+	ErrRegisterUnsupported = errors.New("repository cannot register accounts")
+)
+
+// GuestIDPrefix starts the ID of every guest player. Guests play with an
+// ephemeral ID until they register an account, which takes over their
+// balance, stats, cosmetics and history.
+const GuestIDPrefix = "guest_"
+
+// MaxAccountLength is the longest account name
+const MaxAccountLength = 32
+
+// NewGuestID returns a fresh random guest player ID
+func NewGuestID() string {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("failed to generate guest ID: %v", err))
+	}
+	return GuestIDPrefix + hex.EncodeToString(buf)
+}
+
+// IsGuest reports whether a player ID belongs to a guest
+func IsGuest(playerID string) bool {
+	return strings.HasPrefix(playerID, GuestIDPrefix)
+}
+
+// ValidateAccount checks a name can be registered as an account: 1 to
+// MaxAccountLength letters, digits, '_', '-' or '.', not starting like a
+// guest ID
+func ValidateAccount(account string) error {
+	if account == "" || len([]rune(account)) > MaxAccountLength {
+		return fmt.Errorf("%w: must be 1-%d characters", ErrInvalidAccount, MaxAccountLength)
+	}
+	if IsGuest(account) {
+		return fmt.Errorf("%w: must not start with %q", ErrInvalidAccount, GuestIDPrefix)
+	}
+	for _, r := range account {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-.", r) {
+			return fmt.Errorf("%w: %q is not allowed", ErrInvalidAccount, r)
+		}
+	}
+	return nil
+}
+
+// AccountMigrator is implemented by repositories that can move a player to
+// a new ID in one step
+type AccountMigrator interface {
+	// MigratePlayer moves the player fromID, with their balance, stats,
+	// inventory and results, to toID and returns the moved player. It
+	// fails with ErrAccountExists if toID is taken, and then changes
+	// nothing.
+	MigratePlayer(ctx context.Context, fromID, toID string) (*Player, error)
+}
+
+// RegisterGuest upgrades a guest to a registered account, migrating the
+// guest's data through the repository so it either all moves or none does
+func RegisterGuest(ctx context.Context, repo Repository, guestID, account string) (*Player, error) {
+	if !IsGuest(guestID) {
+		return nil, ErrNotGuest
+	}
+	if err := ValidateAccount(account); err != nil {
+		return nil, err
+	}
+
+	migrator, ok := repo.(AccountMigrator)
+	if !ok {
+		return nil, ErrRegisterUnsupported
+	}
+	return migrator.MigratePlayer(ctx, guestID, account)
+}
+
+// Register upgrades a guest playing on this engine to a registered account
+// and records it in the audit trail. Sessions keep the player ID they were
+// started with, so callers should settle the guest's bet first and play on
+// in a new session.
+func (e *Engine) Register(ctx context.Context, guestID, account string) (*Player, error) {
+	unlock := e.lockPlayer(guestID)
+	defer unlock()
+
+	player, err := repoCall(ctx, e, func(ctx context.Context) (*Player, error) {
+		return RegisterGuest(ctx, e.repo, guestID, account)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	e.logger.Info("Guest registered",
+		zap.String("guest_id", guestID),
+		zap.String("player_id", account),
+		zap.Float64("balance", player.Balance.Float64()),
+	)
+	e.audit.Record(logger.AuditEvent{
+		Time:     e.clock.Now(),
+		Event:    logger.AuditRegister,
+		PlayerID: account,
+		Balance:  player.Balance.Float64(),
+		Reason:   "registered guest " + guestID,
+	})
+
+	return player, nil
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// migratingRepository adds AccountMigrator to mapRepository
+type migratingRepository struct {
+	*mapRepository
+}
+
+func (r migratingRepository) MigratePlayer(ctx context.Context, fromID, toID string) (*Player, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.players[fromID]
+	if !exists {
+		return nil, errors.New("player not found")
+	}
+	if _, taken := r.players[toID]; taken {
+		return nil, ErrAccountExists
+	}
+
+	player.ID = toID
+	r.players[toID] = player
+	delete(r.players, fromID)
+	for _, result := range r.results {
+		if result.PlayerID == fromID {
+			result.PlayerID = toID
+		}
+	}
+	return &player, nil
+}
+
+func TestValidateAccount(t *testing.T) {
+	tests := []struct {
+		account string
+		valid   bool
+	}{
+		{account: "alice", valid: true},
+		{account: "Bob_99.x-y", valid: true},
+		{account: "žydrūnas", valid: true},
+		{account: "", valid: false},
+		{account: strings.Repeat("a", MaxAccountLength+1), valid: false},
+		{account: "has space", valid: false},
+		{account: "emoji🪙", valid: false},
+		{account: GuestIDPrefix + "abc", valid: false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateAccount(tt.account)
+		if tt.valid {
+			assert.NoError(t, err, tt.account)
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidAccount, tt.account)
+		}
+	}
+}
+
+func TestNewGuestID(t *testing.T) {
+	first, second := NewGuestID(), NewGuestID()
+	assert.True(t, IsGuest(first))
+	assert.NotEqual(t, first, second)
+	assert.False(t, IsGuest("alice"))
+}
+
+func TestEngine_RegisterMigratesGuest(t *testing.T) {
+	repo := migratingRepository{newMapRepository()}
+	engine := NewEngine(Config{StartingBalance: 100 * Dollar, MinBet: Dollar, MaxBet: 50 * Dollar, PayoutRatio: 2},
+		repo, fixedGenerator{side: Heads}, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	guestID := NewGuestID()
+	session := engine.NewSession(guestID)
+	_, err := session.PlaceBet(ctx, 10*Dollar, Heads)
+	require.NoError(t, err)
+	_, err = session.FlipCoin(ctx)
+	require.NoError(t, err)
+
+	player, err := engine.Register(ctx, guestID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", player.ID)
+	assert.Equal(t, 110*Dollar, player.Balance)
+	assert.Equal(t, 1, player.Stats.GamesPlayed)
+
+	// The guest is gone and their history belongs to the account
+	_, err = repo.GetPlayer(ctx, guestID)
+	assert.Error(t, err)
+	page, err := engine.QueryHistory(ctx, ResultQuery{PlayerID: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+
+	// Accounts cannot be registered again or taken by another guest
+	_, err = engine.Register(ctx, "alice", "alice2")
+	assert.ErrorIs(t, err, ErrNotGuest)
+
+	other := NewGuestID()
+	require.NoError(t, repo.SavePlayer(ctx, &Player{ID: other, Balance: 100 * Dollar}))
+	_, err = engine.Register(ctx, other, "alice")
+	assert.ErrorIs(t, err, ErrAccountExists)
+}
+
+func TestRegisterGuest_Unsupported(t *testing.T) {
+	_, err := RegisterGuest(context.Background(), newMapRepository(), NewGuestID(), "alice")
+	assert.ErrorIs(t, err, ErrRegisterUnsupported)
+}
@@ -0,0 +1,104 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Keywords for bet amounts relative to the balance
+const (
+	AmountHalf = "half"
+	AmountMax  = "max"
+)
+
+// BetAmount is a bet amount as a player typed it: a fixed amount, or a
+// share of their balance that is only known when the bet is placed
+type BetAmount struct {
+	// Fixed is the amount of a plain bet
+	Fixed Money
+	// Percent is the share of the balance bet, from 0 to 100, when set
+	Percent float64
+	// Max bets the whole balance, capped at the maximum bet
+	Max bool
+}
+
+// ParseBetAmount parses a bet amount such as "12.50", "$12.50", "10%",
+// "half" or "max"
+func ParseBetAmount(s string) (BetAmount, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	switch text {
+	case AmountHalf:
+		return BetAmount{Percent: 50}, nil
+	case AmountMax:
+		return BetAmount{Max: true}, nil
+	}
+
+	if number, ok := strings.CutSuffix(text, "%"); ok {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil || math.IsNaN(percent) || percent <= 0 || percent > 100 {
+			return BetAmount{}, fmt.Errorf("%w: %q is not a percentage between 0 and 100", ErrInvalidMoney, s)
+		}
+		return BetAmount{Percent: percent}, nil
+	}
+
+	fixed, err := ParseMoney(text)
+	if err != nil {
+		return BetAmount{}, err
+	}
+	return BetAmount{Fixed: fixed}, nil
+}
+
+// Relative reports whether the amount depends on the balance
+func (a BetAmount) Relative() bool {
+	return a.Max || a.Percent > 0
+}
+
+// Resolve returns the amount to bet from a balance. Shares of the balance
+// round down to the cent, so they never bet more than the balance; max is
+// capped at maxBet unless it is zero.
+func (a BetAmount) Resolve(balance, maxBet Money) Money {
+	switch {
+	case a.Max:
+		if maxBet > 0 && balance > maxBet {
+			return maxBet
+		}
+		return max(balance, 0)
+	case a.Percent > 0:
+		// The tolerance keeps float error from dropping a whole cent
+		return max(Money(math.Floor(float64(balance)*a.Percent/100+centTolerance)), 0)
+	default:
+		return a.Fixed
+	}
+}
+
+// String formats the amount as a player would type it
+func (a BetAmount) String() string {
+	switch {
+	case a.Max:
+		return AmountMax
+	case a.Percent == 50:
+		return AmountHalf
+	case a.Percent > 0:
+		return strconv.FormatFloat(a.Percent, 'f', -1, 64) + "%"
+	default:
+		return a.Fixed.String()
+	}
+}
+
+// MarshalText writes the amount as a player would type it, so favorites
+// keep shares of the balance relative
+func (a BetAmount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText parses an amount written by MarshalText
+func (a *BetAmount) UnmarshalText(text []byte) error {
+	parsed, err := ParseBetAmount(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBetAmount(t *testing.T) {
+	balance := Money(12345) // $123.45
+	maxBet := 100 * Dollar
+
+	tests := []struct {
+		input string
+		want  Money
+		text  string
+		err   bool
+	}{
+		{input: "12.50", want: 1250, text: "12.50"},
+		{input: "$5", want: 500, text: "5.00"},
+		{input: "10%", want: 1234, text: "10%"},
+		{input: " 12.5 % ", want: 1543, text: "12.5%"},
+		{input: "100%", want: balance, text: "100%"},
+		{input: "Half", want: 6172, text: "half"},
+		{input: "MAX", want: maxBet, text: "max"},
+		{input: "0%", err: true},
+		{input: "150%", err: true},
+		{input: "ten%", err: true},
+		{input: "all", err: true},
+		{input: "10.555", err: true},
+	}
+
+	for _, tt := range tests {
+		amount, err := ParseBetAmount(tt.input)
+		if tt.err {
+			assert.ErrorIs(t, err, ErrInvalidMoney, "input %q", tt.input)
+			continue
+		}
+		require.NoError(t, err, "input %q", tt.input)
+		assert.Equal(t, tt.want, amount.Resolve(balance, maxBet), "input %q", tt.input)
+		assert.Equal(t, tt.text, amount.String(), "input %q", tt.input)
+	}
+}
+
+func TestBetAmount_Resolve(t *testing.T) {
+	allIn := BetAmount{Max: true}
+	assert.Equal(t, 40*Dollar, allIn.Resolve(40*Dollar, 100*Dollar), "max below the bet limit is the balance")
+	assert.Equal(t, 250*Dollar, allIn.Resolve(250*Dollar, 0), "no limit bets it all")
+	assert.Zero(t, allIn.Resolve(-Dollar, 0))
+	assert.True(t, allIn.Relative())
+
+	// Shares round down rather than to the nearest cent
+	third := BetAmount{Percent: 100.0 / 3}
+	assert.Equal(t, Money(33), third.Resolve(Dollar, 0))
+	assert.Equal(t, Money(10), BetAmount{Percent: 0.1}.Resolve(100*Dollar, 0))
+
+	fixed := BetAmount{Fixed: 5 * Dollar}
+	assert.Equal(t, 5*Dollar, fixed.Resolve(Dollar, 0), "fixed amounts ignore the balance")
+	assert.False(t, fixed.Relative())
+}
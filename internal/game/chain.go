@@ -0,0 +1,118 @@
+package game
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrChainBroken is returned by VerifyChain for a history with results
+// edited, removed or reordered
+var ErrChainBroken = errors.New("result chain is broken")
+
+// ChainError tells where a result chain breaks and why
+type ChainError struct {
+	// Index is the position of the result in the history checked
+	Index    int
+	ResultID string
+	PlayerID string
+	Reason   string
+}
+
+// Error implements the error interface
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("result %d (%s): %s", e.Index+1, e.ResultID, e.Reason)
+}
+
+// Unwrap lets errors.Is match ErrChainBroken
+func (e *ChainError) Unwrap() error {
+	return ErrChainBroken
+}
+
+// HashResult returns the hash a result is chained with. It covers every
+// field but Hash itself, PrevHash included, so changing any of them or the
+// result before it changes the hash.
+func HashResult(result *Result) (string, error) {
+	unhashed := *result
+	unhashed.Hash = ""
+	data, err := json.Marshal(&unhashed)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode result for hashing: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// chain links the result to the player's previous one, whose hash is
+// prevHash, and hashes it
+func (r *Result) chain(prevHash string) error {
+	r.PrevHash = prevHash
+	r.Hash = ""
+	hash, err := HashResult(r)
+	if err != nil {
+		return err
+	}
+	r.Hash = hash
+	return nil
+}
+
+// ResumeChain chains the player's next result to head, the hash of their
+// latest result in a history kept between runs, so the history stays one
+// chain across engines
+func (e *Engine) ResumeChain(ctx context.Context, playerID, head string) error {
+	unlock := e.lockPlayer(playerID)
+	defer unlock()
+
+	player, err := e.GetPlayer(ctx, playerID)
+	if err != nil {
+		return fmt.Errorf("failed to get player: %w", err)
+	}
+	if player.ChainHead == head {
+		return nil
+	}
+	player.ChainHead = head
+	if err := e.savePlayer(ctx, player); err != nil {
+		return fmt.Errorf("failed to save player: %w", err)
+	}
+	return nil
+}
+
+// VerifyChain checks that results, oldest first, are unbroken chains: each
+// player's first result starts a chain, every later one names the hash of
+// the one before it, and each hash still matches its result. Results of
+// different players may be interleaved. Results removed from the end of a
+// chain leave no trace, so the latest hash should be kept elsewhere when
+// that matters.
+func VerifyChain(results []*Result) error {
+	heads := make(map[string]string)
+	for i, result := range results {
+		fail := func(format string, args ...any) error {
+			return &ChainError{Index: i, ResultID: result.ID, PlayerID: result.PlayerID, Reason: fmt.Sprintf(format, args...)}
+		}
+
+		if result.Hash == "" {
+			return fail("is not chained")
+		}
+		hash, err := HashResult(result)
+		if err != nil {
+			return err
+		}
+		if hash != result.Hash {
+			return fail("was edited after it was recorded")
+		}
+
+		head := heads[result.PlayerID]
+		switch {
+		case result.PrevHash == head:
+		case head == "":
+			return fail("follows a result of %s that is missing", result.PlayerID)
+		default:
+			return fail("does not follow the result before it of %s; results were removed or reordered", result.PlayerID)
+		}
+		heads[result.PlayerID] = result.Hash
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playChain settles rounds bets for each player in turn and returns the
+// results, oldest first
+func playChain(t *testing.T, engine *Engine, repo *mapRepository, rounds int, players ...string) []*Result {
+	ctx := context.Background()
+	for i := 0; i < rounds; i++ {
+		for _, playerID := range players {
+			session := engine.NewSession(playerID)
+			_, err := session.PlaceBet(ctx, 10*Dollar, Heads)
+			require.NoError(t, err)
+			_, err = session.FlipCoin(ctx)
+			require.NoError(t, err)
+		}
+	}
+	results, err := repo.GetResults(ctx, 0)
+	require.NoError(t, err)
+	return results
+}
+
+// reload round-trips results through JSON, as a history file does
+func reload(t *testing.T, results []*Result) []*Result {
+	data, err := json.Marshal(results)
+	require.NoError(t, err)
+	var loaded []*Result
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	return loaded
+}
+
+func TestEngine_ChainsResults(t *testing.T) {
+	engine, repo := newSessionEngine(t, Heads)
+	results := playChain(t, engine, repo, 3, "alice", "bob")
+	require.Len(t, results, 6)
+
+	assert.Empty(t, results[0].PrevHash, "a player's first result starts the chain")
+	assert.Empty(t, results[1].PrevHash)
+	assert.Equal(t, results[0].Hash, results[2].PrevHash)
+	assert.Equal(t, results[3].Hash, results[5].PrevHash)
+
+	player, err := engine.GetPlayer(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, results[4].Hash, player.ChainHead)
+
+	assert.NoError(t, VerifyChain(results))
+	assert.NoError(t, VerifyChain(reload(t, results)), "the chain survives being saved")
+}
+
+func TestVerifyChain_DetectsTampering(t *testing.T) {
+	engine, repo := newSessionEngine(t, Heads)
+	results := playChain(t, engine, repo, 3, "alice")
+
+	tests := []struct {
+		name   string
+		tamper func([]*Result) []*Result
+		index  int
+	}{
+		{
+			name: "edited payout",
+			tamper: func(r []*Result) []*Result {
+				r[1].Payout *= 10
+				return r
+			},
+			index: 1,
+		},
+		{
+			name:   "removed result",
+			tamper: func(r []*Result) []*Result { return append(r[:1], r[2:]...) },
+			index:  1,
+		},
+		{
+			name:   "removed first result",
+			tamper: func(r []*Result) []*Result { return r[1:] },
+			index:  0,
+		},
+		{
+			name: "reordered results",
+			tamper: func(r []*Result) []*Result {
+				r[1], r[2] = r[2], r[1]
+				return r
+			},
+			index: 1,
+		},
+		{
+			name: "unchained result",
+			tamper: func(r []*Result) []*Result {
+				r[2].Hash = ""
+				return r
+			},
+			index: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyChain(tt.tamper(reload(t, results)))
+			require.ErrorIs(t, err, ErrChainBroken)
+			var chainErr *ChainError
+			require.ErrorAs(t, err, &chainErr)
+			assert.Equal(t, tt.index, chainErr.Index)
+			assert.Equal(t, "alice", chainErr.PlayerID)
+		})
+	}
+}
+
+func TestEngine_ResumeChain(t *testing.T) {
+	ctx := context.Background()
+	engine, repo := newSessionEngine(t, Heads)
+	earlier := playChain(t, engine, repo, 2, "alice")
+
+	// A new engine continues the chain from the history's latest hash
+	resumed, resumedRepo := newSessionEngine(t, Tails)
+	require.NoError(t, resumed.ResumeChain(ctx, "alice", earlier[1].Hash))
+	later := playChain(t, resumed, resumedRepo, 1, "alice")
+
+	assert.Equal(t, earlier[1].Hash, later[0].PrevHash)
+	assert.NoError(t, VerifyChain(append(earlier, later...)))
+}
@@ -0,0 +1,188 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/logger"
+)
+
+// Cosmetics errors
+var (
+	ErrUnknownSkin  = errors.New("unknown coin skin")
+	ErrSkinOwned    = errors.New("coin skin already owned")
+	ErrSkinNotOwned = errors.New("coin skin not owned")
+)
+
+// DefaultSkin is the coin every player owns
+const DefaultSkin = "classic"
+
+// CoinSkin is a cosmetic look for the coin. Skins only change how results
+// are shown, never the odds or payouts.
+type CoinSkin struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       Money  `json:"price"`
+	// Heads and Tails are the faces shown for each side
+	Heads string `json:"heads"`
+	Tails string `json:"tails"`
+}
+
+// Face returns the skin's face for a side
+func (s CoinSkin) Face(side Side) string {
+	if side == Tails {
+		return s.Tails
+	}
+	return s.Heads
+}
+
+// skinCatalog lists every skin in shop order
+var skinCatalog = []CoinSkin{
+	{ID: DefaultSkin, Name: "Classic Coin", Description: "The coin everyone starts with", Heads: "👑", Tails: "🦅"},
+	{ID: "pixel", Name: "Pixel Coin", Description: "Chunky 8-bit faces", Price: 100 * Dollar, Heads: "🟨", Tails: "⬛"},
+	{ID: "golden", Name: "Golden Coin", Description: "Solid gold, for high rollers", Price: 500 * Dollar, Heads: "🌕", Tails: "🏆"},
+}
+
+// Skins returns every coin skin in shop order
+func Skins() []CoinSkin {
+	return slices.Clone(skinCatalog)
+}
+
+// SkinByID looks up a coin skin
+func SkinByID(id string) (CoinSkin, bool) {
+	for _, skin := range skinCatalog {
+		if skin.ID == id {
+			return skin, true
+		}
+	}
+	return CoinSkin{}, false
+}
+
+// SkinFace returns the face of the skin with the given ID for a side,
+// falling back to the classic coin for unknown skins
+func SkinFace(skinID string, side Side) string {
+	skin, ok := SkinByID(skinID)
+	if !ok {
+		skin = skinCatalog[0]
+	}
+	return skin.Face(side)
+}
+
+// Inventory holds the cosmetics a player has unlocked and the skin in use.
+// The classic coin is always owned and need not be listed.
+type Inventory struct {
+	Skins    []string `json:"skins,omitempty"`
+	Equipped string   `json:"equipped,omitempty"`
+}
+
+// Owns reports whether the inventory holds a skin
+func (inv Inventory) Owns(skinID string) bool {
+	return skinID == DefaultSkin || slices.Contains(inv.Skins, skinID)
+}
+
+// EquippedSkin returns the skin in use, the classic coin by default
+func (inv Inventory) EquippedSkin() string {
+	if inv.Equipped == "" {
+		return DefaultSkin
+	}
+	return inv.Equipped
+}
+
+// Clone returns a copy that shares no memory with the inventory
+func (inv Inventory) Clone() Inventory {
+	inv.Skins = slices.Clone(inv.Skins)
+	return inv
+}
+
+// Unlock checks that a skin can be bought and adds it to the inventory
+func (inv *Inventory) Unlock(skinID string) (CoinSkin, error) {
+	skin, ok := SkinByID(skinID)
+	if !ok {
+		return CoinSkin{}, fmt.Errorf("%w: %s", ErrUnknownSkin, skinID)
+	}
+	if inv.Owns(skinID) {
+		return CoinSkin{}, ErrSkinOwned
+	}
+	inv.Skins = append(inv.Skins, skinID)
+	return skin, nil
+}
+
+// Equip switches to an owned skin
+func (inv *Inventory) Equip(skinID string) error {
+	if _, ok := SkinByID(skinID); !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSkin, skinID)
+	}
+	if !inv.Owns(skinID) {
+		return ErrSkinNotOwned
+	}
+	inv.Equipped = skinID
+	return nil
+}
+
+// BuySkin unlocks a coin skin for a player, paying its price from their
+// balance. The purchase is recorded in the audit trail.
+func (e *Engine) BuySkin(ctx context.Context, playerID, skinID string) (*Player, error) {
+	unlock := e.lockPlayer(playerID)
+	defer unlock()
+
+	player, err := e.GetPlayer(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player: %w", err)
+	}
+
+	skin, ok := SkinByID(skinID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSkin, skinID)
+	}
+	if !player.Inventory.Owns(skinID) && player.Balance < skin.Price {
+		return nil, ErrInsufficientBalance
+	}
+	if _, err := player.Inventory.Unlock(skinID); err != nil {
+		return nil, err
+	}
+	player.Balance -= skin.Price
+
+	if err := e.savePlayer(ctx, player); err != nil {
+		return nil, fmt.Errorf("failed to save player: %w", err)
+	}
+
+	e.audit.Record(logger.AuditEvent{
+		Time:     e.clock.Now(),
+		Event:    logger.AuditPurchase,
+		PlayerID: playerID,
+		Amount:   skin.Price.Float64(),
+		Balance:  player.Balance.Float64(),
+		Reason:   "coin skin " + skin.ID,
+	})
+	e.logger.Info("Coin skin purchased",
+		zap.String("player_id", playerID),
+		zap.String("skin", skin.ID),
+		zap.Float64("price", skin.Price.Float64()),
+	)
+
+	return player, nil
+}
+
+// EquipSkin switches a player to a coin skin they own
+func (e *Engine) EquipSkin(ctx context.Context, playerID, skinID string) (*Player, error) {
+	unlock := e.lockPlayer(playerID)
+	defer unlock()
+
+	player, err := e.GetPlayer(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player: %w", err)
+	}
+	if err := player.Inventory.Equip(skinID); err != nil {
+		return nil, err
+	}
+
+	if err := e.savePlayer(ctx, player); err != nil {
+		return nil, fmt.Errorf("failed to save player: %w", err)
+	}
+	return player, nil
+}
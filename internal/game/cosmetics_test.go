@@ -0,0 +1,41 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_BuyAndEquipSkin(t *testing.T) {
+	engine, repo := newSessionEngine(t, Heads)
+	session := engine.NewSession("player")
+	ctx := context.Background()
+
+	player, err := session.Player(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSkin, player.Inventory.EquippedSkin())
+
+	_, err = session.EquipSkin(ctx, "golden")
+	assert.ErrorIs(t, err, ErrSkinNotOwned)
+	_, err = session.BuySkin(ctx, "rainbow")
+	assert.ErrorIs(t, err, ErrUnknownSkin)
+
+	player, err = session.BuySkin(ctx, "golden")
+	require.NoError(t, err)
+	assert.Equal(t, 500*Dollar, player.Balance)
+
+	_, err = session.BuySkin(ctx, "golden")
+	assert.ErrorIs(t, err, ErrSkinOwned)
+
+	_, err = session.EquipSkin(ctx, "golden")
+	require.NoError(t, err)
+
+	// The inventory is stored with the player
+	stored, err := repo.GetPlayer(ctx, "player")
+	require.NoError(t, err)
+	assert.Equal(t, "golden", stored.Inventory.EquippedSkin())
+	assert.Equal(t, 500*Dollar, stored.Balance)
+	assert.Equal(t, "🌕", SkinFace(stored.Inventory.EquippedSkin(), Heads))
+}
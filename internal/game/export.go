@@ -0,0 +1,71 @@
+package game
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// historyHeader names the columns of an exported history. Each row is one
+// result; the bet columns are empty for results without a bet.
+var historyHeader = []string{
+	"result_id", "player_id", "timestamp", "side", "seed",
+	"bet_id", "choice", "amount", "premium", "practice",
+	"won", "payout", "multiplier", "insurance",
+}
+
+// ExportHistory writes every result matching the query to w as CSV, in the
+// query's order, and returns how many were written. The query's Offset and
+// Limit are ignored so the export is never cut to one page.
+func (e *Engine) ExportHistory(ctx context.Context, w io.Writer, query ResultQuery) (int, error) {
+	query.Offset = 0
+	query.Limit = 0
+	page, err := e.QueryHistory(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load history: %w", err)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(historyHeader); err != nil {
+		return 0, fmt.Errorf("failed to export history: %w", err)
+	}
+	for _, result := range page.Results {
+		if err := writer.Write(historyRow(result)); err != nil {
+			return 0, fmt.Errorf("failed to export result %s: %w", result.ID, err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return 0, fmt.Errorf("failed to export history: %w", err)
+	}
+	return len(page.Results), nil
+}
+
+// historyRow returns the CSV columns of one result
+func historyRow(result *Result) []string {
+	row := []string{
+		result.ID,
+		result.PlayerID,
+		result.Timestamp.UTC().Format(time.RFC3339Nano),
+		result.Side.String(),
+		result.Seed,
+	}
+	if result.Bet == nil {
+		return append(row, make([]string, len(historyHeader)-len(row))...)
+	}
+
+	return append(row,
+		result.Bet.ID,
+		result.Bet.Choice.String(),
+		result.Bet.Amount.String(),
+		result.Bet.Premium.String(),
+		strconv.FormatBool(result.Bet.Practice),
+		strconv.FormatBool(result.Won),
+		result.Payout.String(),
+		strconv.FormatFloat(result.Multiplier, 'f', -1, 64),
+		result.Insurance.String(),
+	)
+}
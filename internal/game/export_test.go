@@ -0,0 +1,49 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestEngine_ExportHistory(t *testing.T) {
+	engine := NewEngine(Config{StartingBalance: 100 * Dollar, MinBet: Dollar, MaxBet: 50 * Dollar, PayoutRatio: 2},
+		newMapRepository(), fixedGenerator{side: Heads}, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	session := engine.NewSession("p1")
+	for _, choice := range []Side{Heads, Tails, Heads} {
+		_, err := session.PlaceBet(ctx, 10*Dollar, choice)
+		require.NoError(t, err)
+		_, err = session.FlipCoin(ctx)
+		require.NoError(t, err)
+	}
+
+	// The page limit does not cut the export short
+	won := true
+	var out bytes.Buffer
+	written, err := engine.ExportHistory(ctx, &out, ResultQuery{Won: &won, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, written)
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, historyHeader, rows[0])
+	for _, row := range rows[1:] {
+		record := make(map[string]string)
+		for i, column := range historyHeader {
+			record[column] = row[i]
+		}
+		assert.Equal(t, "p1", record["player_id"])
+		assert.Equal(t, "heads", record["choice"])
+		assert.Equal(t, "10.00", record["amount"])
+		assert.Equal(t, "true", record["won"])
+		assert.Equal(t, "20.00", record["payout"])
+	}
+}
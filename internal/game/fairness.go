@@ -0,0 +1,152 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Fairness thresholds. Below MinFairnessSample flips the statistical checks
+// are reported but never flagged, since small samples swing too widely.
+const (
+	MinFairnessSample = 30
+	FairnessAlpha     = 0.01
+	payoutTolerance   = Cent // Payouts are rounded to the cent
+	streakMarginFlips = 7
+)
+
+// FairnessReport summarizes how fair a set of stored results looks: the
+// heads/tails balance, streaks, a chi-square test against a fair coin and
+// whether payouts match the configured ratio
+type FairnessReport struct {
+	// Flips counts coin flips; multiplayer rounds settle several bets on
+	// one flip and count once
+	Flips      int          `json:"flips"`
+	Outcomes   Distribution `json:"outcomes"`
+	HeadsRatio float64      `json:"heads_ratio"`
+
+	LongestHeadsStreak int `json:"longest_heads_streak"`
+	LongestTailsStreak int `json:"longest_tails_streak"`
+	// ExpectedLongestStreak is the typical longest run for this many fair flips
+	ExpectedLongestStreak float64 `json:"expected_longest_streak"`
+
+	ChiSquare float64 `json:"chi_square"`
+	PValue    float64 `json:"p_value"`
+
+	PayoutRatio      float64 `json:"payout_ratio"`
+	BetsChecked      int     `json:"bets_checked"`
+	PayoutMismatches int     `json:"payout_mismatches"`
+
+	Anomalies []string `json:"anomalies,omitempty"`
+}
+
+// Fair reports whether no anomalies were flagged
+func (r *FairnessReport) Fair() bool {
+	return len(r.Anomalies) == 0
+}
+
+// AnalyzeFairness checks results against a fair coin and the expected
+// payout ratio. Results may be in any order.
+func AnalyzeFairness(results []*Result, payoutRatio float64) *FairnessReport {
+	report := &FairnessReport{PayoutRatio: payoutRatio}
+
+	ordered := make([]*Result, len(results))
+	copy(ordered, results)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Timestamp.Before(ordered[j].Timestamp)
+	})
+
+	var (
+		lastSeed string
+		run      int
+		runSide  Side
+	)
+	for _, result := range ordered {
+		if result.Bet != nil {
+			report.checkPayout(result)
+		}
+
+		// Bets settled by the same flip share its seed
+		if result.Seed != "" && result.Seed == lastSeed {
+			continue
+		}
+		lastSeed = result.Seed
+
+		report.Flips++
+		report.Outcomes.Add(result.Side)
+
+		if result.Side == runSide {
+			run++
+		} else {
+			runSide, run = result.Side, 1
+		}
+		switch runSide {
+		case Heads:
+			report.LongestHeadsStreak = max(report.LongestHeadsStreak, run)
+		case Tails:
+			report.LongestTailsStreak = max(report.LongestTailsStreak, run)
+		}
+	}
+
+	if report.Flips == 0 {
+		report.PValue = 1
+		return report
+	}
+
+	n := float64(report.Flips)
+	report.HeadsRatio = float64(report.Outcomes.Heads) / n
+	report.ExpectedLongestStreak = math.Log2(n)
+
+	// One degree of freedom against an expected n/2 of each side
+	expected := n / 2
+	heads := float64(report.Outcomes.Heads) - expected
+	tails := float64(report.Outcomes.Tails) - expected
+	report.ChiSquare = (heads*heads + tails*tails) / expected
+	report.PValue = math.Erfc(math.Sqrt(report.ChiSquare / 2))
+
+	report.flagAnomalies()
+	return report
+}
+
+// checkPayout compares one settled bet with the outcome and payout ratio
+func (r *FairnessReport) checkPayout(result *Result) {
+	r.BetsChecked++
+
+	won := result.Bet.Choice == result.Side
+	var expected Money
+	if won {
+		ratio := r.PayoutRatio
+		if result.Multiplier > 0 {
+			ratio *= result.Multiplier
+		}
+		expected = result.Bet.Amount.Mul(ratio)
+	}
+
+	if result.Won != won || (result.Payout-expected).Abs() > payoutTolerance {
+		r.PayoutMismatches++
+	}
+}
+
+// flagAnomalies records the checks the results failed
+func (r *FairnessReport) flagAnomalies() {
+	if r.PayoutMismatches > 0 {
+		r.Anomalies = append(r.Anomalies, fmt.Sprintf(
+			"%d of %d bets were not paid at %.2fx", r.PayoutMismatches, r.BetsChecked, r.PayoutRatio))
+	}
+
+	if r.Flips < MinFairnessSample {
+		return
+	}
+
+	if r.PValue < FairnessAlpha {
+		r.Anomalies = append(r.Anomalies, fmt.Sprintf(
+			"heads ratio %.1f%% is unlikely for a fair coin (p = %.4f)", r.HeadsRatio*100, r.PValue))
+	}
+
+	limit := int(math.Ceil(r.ExpectedLongestStreak)) + streakMarginFlips
+	if longest := max(r.LongestHeadsStreak, r.LongestTailsStreak); longest >= limit {
+		r.Anomalies = append(r.Anomalies, fmt.Sprintf(
+			"streak of %d identical results is unlikely in %d flips (expected about %.0f)",
+			longest, r.Flips, r.ExpectedLongestStreak))
+	}
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flipResults builds one settled heads bet per side in sides
+func flipResults(sides []Side) []*Result {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	results := make([]*Result, len(sides))
+	for i, side := range sides {
+		won := side == Heads
+		var payout Money
+		if won {
+			payout = 20 * Dollar
+		}
+		results[i] = &Result{
+			ID:        fmt.Sprintf("result_%d", i),
+			Side:      side,
+			Bet:       &Bet{Amount: 10 * Dollar, Choice: Heads},
+			Won:       won,
+			Payout:    payout,
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Seed:      fmt.Sprintf("seed_%d", i),
+		}
+	}
+	return results
+}
+
+func TestAnalyzeFairness_BalancedResults(t *testing.T) {
+	sides := make([]Side, 100)
+	for i := range sides {
+		sides[i] = Heads
+		if i%2 == 1 {
+			sides[i] = Tails
+		}
+	}
+	// Out of order input is analyzed chronologically
+	results := flipResults(sides)
+	results[0], results[99] = results[99], results[0]
+
+	report := AnalyzeFairness(results, 2.0)
+	assert.Equal(t, 100, report.Flips)
+	assert.Equal(t, 0.5, report.HeadsRatio)
+	assert.Equal(t, 1, report.LongestHeadsStreak)
+	assert.Equal(t, 1, report.LongestTailsStreak)
+	assert.InDelta(t, 0, report.ChiSquare, 1e-9)
+	assert.InDelta(t, 1, report.PValue, 1e-9)
+	assert.Equal(t, 100, report.BetsChecked)
+	assert.True(t, report.Fair(), report.Anomalies)
+}
+
+func TestAnalyzeFairness_FlagsAnomalies(t *testing.T) {
+	sides := make([]Side, 60)
+	for i := range sides {
+		sides[i] = Heads
+		if i >= 50 {
+			sides[i] = Tails
+		}
+	}
+	results := flipResults(sides)
+	results[0].Payout = 15 * Dollar
+
+	report := AnalyzeFairness(results, 2.0)
+	assert.Equal(t, 50, report.LongestHeadsStreak)
+	assert.Less(t, report.PValue, FairnessAlpha)
+	assert.Equal(t, 1, report.PayoutMismatches)
+	require.Len(t, report.Anomalies, 3)
+	assert.False(t, report.Fair())
+}
+
+func TestAnalyzeFairness_SharedFlipCountsOnce(t *testing.T) {
+	results := flipResults([]Side{Heads, Heads})
+	results[1].Seed = results[0].Seed
+
+	report := AnalyzeFairness(results, 2.0)
+	assert.Equal(t, 1, report.Flips)
+	assert.Equal(t, 2, report.BetsChecked)
+
+	// Small samples are reported but never flagged
+	assert.True(t, report.Fair())
+}
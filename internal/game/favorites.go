@@ -0,0 +1,63 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MaxFavoriteNameLength and MaxFavorites bound a player's favorite bets
+const (
+	MaxFavoriteNameLength = 32
+	MaxFavorites          = 20
+)
+
+// Favorite bet errors
+var (
+	ErrFavoriteNotFound = errors.New("no favorite bet has that name")
+	ErrInvalidFavorite  = errors.New("invalid favorite bet")
+	ErrTooManyFavorites = fmt.Errorf("a player keeps at most %d favorite bets", MaxFavorites)
+)
+
+// FavoriteBet is a bet a player saved under a name, such as "lucky25" for
+// $25 on tails, to place again in one step
+type FavoriteBet struct {
+	Name    string    `json:"name"`
+	Amount  BetAmount `json:"amount"`
+	Choice  Side      `json:"choice"`
+	Insured bool      `json:"insured,omitempty"`
+}
+
+// Validate checks the favorite names a positive amount on a side of the
+// coin. Names are 1 to MaxFavoriteNameLength letters, digits, '_' or '-'.
+func (f FavoriteBet) Validate() error {
+	if f.Name == "" || len([]rune(f.Name)) > MaxFavoriteNameLength {
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidFavorite, MaxFavoriteNameLength)
+	}
+	for _, r := range f.Name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-", r) {
+			return fmt.Errorf("%w: %q is not allowed in a name", ErrInvalidFavorite, r)
+		}
+	}
+	if !f.Amount.Relative() && f.Amount.Fixed <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidFavorite)
+	}
+	if !f.Choice.IsValid() {
+		return fmt.Errorf("%w: %v", ErrInvalidFavorite, ErrInvalidChoice)
+	}
+	return nil
+}
+
+// Describe says what the favorite bets, such as "$25.00 on tails, insured"
+func (f FavoriteBet) Describe() string {
+	amount := f.Amount.String()
+	if !f.Amount.Relative() {
+		amount = f.Amount.Fixed.Format()
+	}
+	text := fmt.Sprintf("%s on %s", amount, f.Choice)
+	if f.Insured {
+		text += ", insured"
+	}
+	return text
+}
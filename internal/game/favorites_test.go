@@ -0,0 +1,41 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavoriteBet_Describe(t *testing.T) {
+	lucky := FavoriteBet{Name: "lucky25", Amount: BetAmount{Fixed: 25 * Dollar}, Choice: Tails}
+	require.NoError(t, lucky.Validate())
+	assert.Equal(t, "$25.00 on tails", lucky.Describe())
+
+	share := FavoriteBet{Name: "tenth", Amount: BetAmount{Percent: 10}, Choice: Heads, Insured: true}
+	require.NoError(t, share.Validate())
+	assert.Equal(t, "10% on heads, insured", share.Describe())
+
+	assert.ErrorIs(t, FavoriteBet{Name: "a b", Amount: share.Amount, Choice: Heads}.Validate(), ErrInvalidFavorite)
+	assert.ErrorIs(t, FavoriteBet{Name: "none", Choice: Heads}.Validate(), ErrInvalidFavorite)
+}
+
+func TestFavoriteBet_KeepsAmountAsTyped(t *testing.T) {
+	favorites := []FavoriteBet{
+		{Name: "fixed", Amount: BetAmount{Fixed: 1250}, Choice: Heads},
+		{Name: "share", Amount: BetAmount{Percent: 12.5}, Choice: Tails},
+		{Name: "half", Amount: BetAmount{Percent: 50}, Choice: Heads},
+		{Name: "max", Amount: BetAmount{Max: true}, Choice: Tails},
+	}
+
+	data, err := json.Marshal(favorites)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"amount":"12.5%"`)
+
+	var decoded []FavoriteBet
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, favorites, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`[{"name":"bad","amount":"lots"}]`), &decoded))
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Friend errors
+var (
+	ErrFriendSelf     = errors.New("players cannot add themselves as a friend")
+	ErrFriendGuest    = errors.New("guests must register an account before they can be added as a friend")
+	ErrFriendExists   = errors.New("player is already a friend")
+	ErrNotFriend      = errors.New("player is not a friend")
+	ErrTooManyFriends = fmt.Errorf("friend lists hold at most %d players", MaxFriends)
+)
+
+// MaxFriends is the most players a friend list holds
+const MaxFriends = 100
+
+// friendCodeLength is the number of characters in a friend code, not
+// counting the dash in the middle
+const friendCodeLength = 8
+
+// friendCodeEncoding spells friend codes in upper case letters and digits
+var friendCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// FriendCode returns the short code others can add a player by, such as
+// "K3QF-7ZPA". It is derived from the player ID, so an account keeps its
+// code for good.
+func FriendCode(playerID string) string {
+	sum := sha256.Sum256([]byte(playerID))
+	code := friendCodeEncoding.EncodeToString(sum[:])[:friendCodeLength]
+	return code[:friendCodeLength/2] + "-" + code[friendCodeLength/2:]
+}
+
+// ParseFriendCode normalizes a friend code typed by a player, accepting
+// lower case and a missing dash. It reports false for text that is not a
+// friend code, such as an account name.
+func ParseFriendCode(s string) (string, bool) {
+	text := strings.ToUpper(strings.TrimSpace(s))
+	text = strings.Replace(text, "-", "", 1)
+	if len(text) != friendCodeLength {
+		return "", false
+	}
+	for _, r := range text {
+		if !strings.ContainsRune("ABCDEFGHIJKLMNOPQRSTUVWXYZ234567", r) {
+			return "", false
+		}
+	}
+	return text[:friendCodeLength/2] + "-" + text[friendCodeLength/2:], true
+}
+
+// IsFriend reports whether the player has added friendID as a friend
+func (p *Player) IsFriend(friendID string) bool {
+	return slices.Contains(p.Friends, friendID)
+}
+
+// AddFriend adds a registered account to the player's friend list
+func (p *Player) AddFriend(friendID string) error {
+	switch {
+	case friendID == p.ID:
+		return ErrFriendSelf
+	case IsGuest(friendID):
+		return ErrFriendGuest
+	case p.IsFriend(friendID):
+		return ErrFriendExists
+	case len(p.Friends) >= MaxFriends:
+		return ErrTooManyFriends
+	}
+	p.Friends = append(p.Friends, friendID)
+	return nil
+}
+
+// RemoveFriend takes a player off the friend list
+func (p *Player) RemoveFriend(friendID string) error {
+	i := slices.Index(p.Friends, friendID)
+	if i < 0 {
+		return ErrNotFriend
+	}
+	p.Friends = slices.Delete(p.Friends, i, i+1)
+	return nil
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFriendCode(t *testing.T) {
+	code := FriendCode("alice")
+	assert.Regexp(t, `^[A-Z2-7]{4}-[A-Z2-7]{4}$`, code)
+	assert.Equal(t, code, FriendCode("alice"))
+	assert.NotEqual(t, code, FriendCode("bob"))
+
+	// Codes are accepted however they are typed
+	for _, typed := range []string{code, " " + code + " ", "abcd2345", "ABCD-2345"} {
+		parsed, ok := ParseFriendCode(typed)
+		require.True(t, ok, typed)
+		assert.Len(t, parsed, friendCodeLength+1)
+	}
+	parsed, _ := ParseFriendCode("abcd2345")
+	assert.Equal(t, "ABCD-2345", parsed)
+
+	for _, text := range []string{"alice", "abcd-23456", "abcd-2381", ""} {
+		_, ok := ParseFriendCode(text)
+		assert.False(t, ok, text)
+	}
+}
+
+func TestPlayer_Friends(t *testing.T) {
+	player := &Player{ID: "alice"}
+
+	assert.ErrorIs(t, player.AddFriend("alice"), ErrFriendSelf)
+	assert.ErrorIs(t, player.AddFriend("guest_1234"), ErrFriendGuest)
+
+	require.NoError(t, player.AddFriend("bob"))
+	assert.True(t, player.IsFriend("bob"))
+	assert.ErrorIs(t, player.AddFriend("bob"), ErrFriendExists)
+
+	require.NoError(t, player.RemoveFriend("bob"))
+	assert.False(t, player.IsFriend("bob"))
+	assert.ErrorIs(t, player.RemoveFriend("bob"), ErrNotFriend)
+
+	for i := 0; i < MaxFriends; i++ {
+		require.NoError(t, player.AddFriend(fmt.Sprintf("friend%d", i)))
+	}
+	assert.ErrorIs(t, player.AddFriend("carol"), ErrTooManyFriends)
+}
@@ -9,11 +9,22 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/logger"
+	"coinflip-game/internal/tracing"
 )
 
+// tracer traces bets and flips through the engine
+var tracer = tracing.Tracer("coinflip-game/internal/game")
+
 // Common errors returned by the game engine
 var (
 	ErrInsufficientBalance = errors.New("insufficient balance for bet")
@@ -43,45 +54,238 @@ func (s Side) IsValid() bool {
 // Bet represents a single bet placed by a player
 type Bet struct {
 	ID        string    `json:"id"`
-	Amount    float64   `json:"amount"`
+	Amount    Money     `json:"amount"`
 	Choice    Side      `json:"choice"`
 	Timestamp time.Time `json:"timestamp"`
+	// Premium was paid on top of Amount to insure the bet, which then
+	// refunds Coverage if it loses
+	Premium  Money `json:"premium,omitempty"`
+	Coverage Money `json:"coverage,omitempty"`
+	// Practice bets resolve like any other but leave the balance and
+	// lifetime stats alone
+	Practice bool `json:"practice,omitempty"`
 }
 
 // Result represents the outcome of a coin flip game
 type Result struct {
 	ID        string    `json:"id"`
+	PlayerID  string    `json:"player_id,omitempty"`
 	Side      Side      `json:"side"`
 	Bet       *Bet      `json:"bet,omitempty"`
 	Won       bool      `json:"won"`
-	Payout    float64   `json:"payout"`
+	Payout    Money     `json:"payout"`
 	Timestamp time.Time `json:"timestamp"`
 	Seed      string    `json:"seed"`
+	// Multiplier is the streak bonus applied to a winning payout, if any
+	Multiplier float64 `json:"multiplier,omitempty"`
+	// Insurance is what an insured losing bet refunded
+	Insurance Money `json:"insurance,omitempty"`
+	// PrevHash is the Hash of the player's previous result, empty for their
+	// first, and Hash covers this result and PrevHash. Together they chain
+	// a player's results so edits and removals can be detected.
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
 }
 
 // Stats represents player statistics
 type Stats struct {
 	GamesPlayed   int     `json:"games_played"`
 	GamesWon      int     `json:"games_won"`
-	TotalWagered  float64 `json:"total_wagered"`
-	TotalWinnings float64 `json:"total_winnings"`
-	NetProfit     float64 `json:"net_profit"`
+	TotalWagered  Money   `json:"total_wagered"`
+	TotalWinnings Money   `json:"total_winnings"`
+	NetProfit     Money   `json:"net_profit"`
 	WinRate       float64 `json:"win_rate"`
+	BiggestWin    Money   `json:"biggest_win"`
+	// CurrentStreak counts consecutive winning bets up to the latest one;
+	// a loss resets it
+	CurrentStreak int `json:"current_streak"`
+	LongestStreak int `json:"longest_streak"`
+	// Outcomes counts how the coin landed, Choices which side was backed
+	Outcomes Distribution `json:"outcomes"`
+	Choices  Distribution `json:"choices"`
+}
+
+// Distribution counts occurrences of each coin side
+type Distribution struct {
+	Heads int `json:"heads"`
+	Tails int `json:"tails"`
+}
+
+// Add counts one occurrence of side
+func (d *Distribution) Add(side Side) {
+	switch side {
+	case Heads:
+		d.Heads++
+	case Tails:
+		d.Tails++
+	}
+}
+
+// Count returns the number of occurrences of side
+func (d Distribution) Count(side Side) int {
+	if side == Tails {
+		return d.Tails
+	}
+	return d.Heads
+}
+
+// Total returns the number of counted occurrences
+func (d Distribution) Total() int {
+	return d.Heads + d.Tails
+}
+
+// Percent returns the share of side as a percentage of the total
+func (d Distribution) Percent(side Side) float64 {
+	total := d.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(d.Count(side)) / float64(total) * 100
+}
+
+// Record adds the outcome of one settled bet to the statistics. The wager
+// includes any insurance premium, and the payout of a losing bet is its
+// insurance refund.
+func (s *Stats) Record(choice, outcome Side, wager, payout Money) {
+	won := choice == outcome
+
+	s.GamesPlayed++
+	s.Choices.Add(choice)
+	s.Outcomes.Add(outcome)
+	s.TotalWagered += wager
+	s.TotalWinnings += payout
+	if won {
+		s.GamesWon++
+		if payout > s.BiggestWin {
+			s.BiggestWin = payout
+		}
+		s.CurrentStreak++
+		s.LongestStreak = max(s.LongestStreak, s.CurrentStreak)
+	} else {
+		s.CurrentStreak = 0
+	}
+	s.NetProfit = s.TotalWinnings - s.TotalWagered
+	if s.GamesPlayed > 0 {
+		s.WinRate = float64(s.GamesWon) / float64(s.GamesPlayed) * 100
+	}
+}
+
+// SummarizeResults aggregates a set of results into statistics. Results
+// without a bet only contribute to the outcome distribution, and practice
+// bets are left out.
+func SummarizeResults(results []*Result) Stats {
+	var stats Stats
+	for _, result := range results {
+		if result.Bet != nil && result.Bet.Practice {
+			continue
+		}
+		if result.Bet == nil {
+			stats.Outcomes.Add(result.Side)
+			continue
+		}
+		stats.Record(result.Bet.Choice, result.Side, result.Bet.Cost(), result.Payout+result.Insurance)
+	}
+	return stats
 }
 
 // Config holds game configuration
 type Config struct {
-	StartingBalance float64 `json:"starting_balance"`
-	MinBet          float64 `json:"min_bet"`
-	MaxBet          float64 `json:"max_bet"`
+	StartingBalance Money   `json:"starting_balance"`
+	MinBet          Money   `json:"min_bet"`
+	MaxBet          Money   `json:"max_bet"`
 	PayoutRatio     float64 `json:"payout_ratio"`
+	// StreakBonus enables streak payouts: each consecutive win before a bet
+	// adds this much to its payout multiplier. Zero disables the bonus.
+	StreakBonus float64 `json:"streak_bonus"`
+	// MaxStreakMultiplier caps the streak multiplier; zero means no cap
+	MaxStreakMultiplier float64 `json:"max_streak_multiplier"`
+	// Insurance is offered with every bet when enabled
+	Insurance Insurance `json:"insurance"`
+	// RepositoryTimeout bounds each repository read or write the engine
+	// makes; zero leaves them bounded only by the caller's context
+	RepositoryTimeout time.Duration `json:"repository_timeout"`
+}
+
+// StreakMultiplier returns the payout multiplier for a bet placed after
+// streak consecutive wins. It is 1 while the bonus is disabled.
+func (c Config) StreakMultiplier(streak int) float64 {
+	return StreakMultiplier(streak, c.StreakBonus, c.MaxStreakMultiplier)
+}
+
+// StreakMultiplier grows the payout multiplier by bonus per consecutive win,
+// capped at maxMultiplier when it is positive
+func StreakMultiplier(streak int, bonus, maxMultiplier float64) float64 {
+	if streak <= 0 || bonus <= 0 {
+		return 1
+	}
+	multiplier := 1 + bonus*float64(streak)
+	if maxMultiplier > 0 && multiplier > maxMultiplier {
+		multiplier = max(maxMultiplier, 1)
+	}
+	return multiplier
 }
 
 // Player represents a game player with their current state
 type Player struct {
-	ID      string  `json:"id"`
-	Balance float64 `json:"balance"`
-	Stats   Stats   `json:"stats"`
+	ID      string `json:"id"`
+	Balance Money  `json:"balance"`
+	Stats   Stats  `json:"stats"`
+	// Inventory holds the player's cosmetics
+	Inventory Inventory `json:"inventory"`
+	// Practice is the ledger of the player's practice bets, kept apart
+	// from Balance and Stats
+	Practice Stats `json:"practice"`
+	// Friends lists the accounts the player has added as friends
+	Friends []string `json:"friends,omitempty"`
+	// Role is what the player may do on a multiplayer server; empty is
+	// RolePlayer
+	Role Role `json:"role,omitempty"`
+	// Badges are the player's top finishes in multiplayer seasons, oldest
+	// first
+	Badges []SeasonBadge `json:"badges,omitempty"`
+	// Notify is where and when the player hears of their play away from
+	// the game; nil until they set it up
+	Notify *NotifySettings `json:"notify,omitempty"`
+	// Duel is the player's rating in head-to-head duels; nil until their
+	// first duel
+	Duel *DuelRating `json:"duel,omitempty"`
+	// Offline is what offline play has added to the player's wallet on a
+	// multiplayer server on the latest day it added any; nil until then
+	Offline *OfflineCredit `json:"offline,omitempty"`
+	// ChainHead is the Hash of the player's latest result, which their next
+	// one is chained to
+	ChainHead string `json:"chain_head,omitempty"`
+	// OpenBets are the bets whose cost has been taken from Balance but
+	// that are not yet settled or refunded
+	OpenBets []*Bet `json:"open_bets,omitempty"`
+}
+
+// Rating returns the player's duel rating, the starting one until they
+// have duelled
+func (p *Player) Rating() DuelRating {
+	if p.Duel == nil {
+		return NewDuelRating()
+	}
+	return *p.Duel
+}
+
+// Fresh reports whether the player holds nothing a new player opened with
+// startingBalance would not: no change to the balance, no cosmetics,
+// friends, role, badges, notification settings, duel rating, offline
+// credit or open bets. Their stats and results are not counted.
+func (p *Player) Fresh(startingBalance Money) bool {
+	return p.Balance == startingBalance &&
+		len(p.Inventory.Skins) == 0 && p.Inventory.Equipped == "" &&
+		len(p.Friends) == 0 && p.Role == "" && len(p.Badges) == 0 &&
+		p.Notify == nil && p.Duel == nil && p.Offline == nil &&
+		len(p.OpenBets) == 0
+}
+
+// closeBet drops a settled or refunded bet from the player's open bets
+func (p *Player) closeBet(betID string) {
+	p.OpenBets = slices.DeleteFunc(slices.Clone(p.OpenBets), func(bet *Bet) bool {
+		return bet.ID == betID
+	})
 }
 
 // Repository interface for persisting game data
@@ -101,25 +305,48 @@ type RandomGenerator interface {
 	FlipCoin(seed string) (Side, error)
 }
 
-// Engine is the main game engine that orchestrates coin flip games
+// Engine is the main game engine that orchestrates coin flip games. One
+// engine can serve many players at once: each gets a Session holding its
+// own bet state, while configuration, storage and randomness are shared.
 type Engine struct {
-	config     Config
-	repo       Repository
-	rng        RandomGenerator
-	logger     *zap.Logger
+	config Config
+	repo   Repository
+	rng    RandomGenerator
+	logger *zap.Logger
+	clock  clock.Clock
+	audit  *logger.AuditLogger
+
+	// Per-player locks around balance updates
+	playersMu   sync.Mutex
+	playerLocks map[string]*sync.Mutex
+	idSeq       atomic.Uint64
+
+	// Bet of the deprecated single-session API
 	currentBet *Bet
 }
 
 // NewEngine creates a new game engine with the provided dependencies
 func NewEngine(config Config, repo Repository, rng RandomGenerator, logger *zap.Logger) *Engine {
 	return &Engine{
-		config: config,
-		repo:   repo,
-		rng:    rng,
-		logger: logger,
+		config:      config,
+		repo:        repo,
+		rng:         rng,
+		logger:      logger,
+		clock:       clock.New(),
+		playerLocks: make(map[string]*sync.Mutex),
 	}
 }
 
+// SetClock replaces the time source used for bet and result timestamps
+func (e *Engine) SetClock(clk clock.Clock) {
+	e.clock = clk
+}
+
+// SetAuditLogger records bets, flips, payouts and refunds to an audit trail
+func (e *Engine) SetAuditLogger(audit *logger.AuditLogger) {
+	e.audit = audit
+}
+
 // GetConfig returns the current game configuration
 func (e *Engine) GetConfig() Config {
 	return e.config
@@ -133,18 +360,23 @@ func (e *Engine) CreatePlayer(ctx context.Context, playerID string) (*Player, er
 		Stats:   Stats{},
 	}
 
-	if err := e.repo.SavePlayer(ctx, player); err != nil {
+	if err := e.savePlayer(ctx, player); err != nil {
 		e.logger.Error("Failed to save new player", zap.String("player_id", playerID), zap.Error(err))
 		return nil, fmt.Errorf("failed to save player: %w", err)
 	}
 
-	e.logger.Info("Created new player", zap.String("player_id", playerID), zap.Float64("starting_balance", e.config.StartingBalance))
+	e.logger.Info("Created new player", zap.String("player_id", playerID), zap.Float64("starting_balance", e.config.StartingBalance.Float64()))
 	return player, nil
 }
 
-// GetPlayer retrieves a player by ID, creating one if it doesn't exist
+// GetPlayer retrieves a player by ID, creating one if it doesn't exist. A
+// lookup that timed out or was cancelled fails instead.
 func (e *Engine) GetPlayer(ctx context.Context, playerID string) (*Player, error) {
-	player, err := e.repo.GetPlayer(ctx, playerID)
+	player, err := e.loadPlayer(ctx, playerID)
+	if IsContextError(err) {
+		// Not knowing the player is not the same as not having one
+		return nil, err
+	}
 	if err != nil {
 		e.logger.Info("Player not found, creating new player", zap.String("player_id", playerID))
 		return e.CreatePlayer(ctx, playerID)
@@ -152,8 +384,74 @@ func (e *Engine) GetPlayer(ctx context.Context, playerID string) (*Player, error
 	return player, nil
 }
 
-// PlaceBet validates and places a bet for the current game round
-func (e *Engine) PlaceBet(ctx context.Context, playerID string, amount float64, choice Side) (*Bet, error) {
+// PlaceBet validates and places a bet for the engine's single current round.
+// Deprecated: engines shared between users should give each one a Session
+// from NewSession, which keeps its own bet state.
+func (e *Engine) PlaceBet(ctx context.Context, playerID string, amount Money, choice Side) (*Bet, error) {
+	bet, err := e.debit(ctx, playerID, amount, choice, false)
+	if err != nil {
+		return nil, err
+	}
+
+	e.currentBet = bet
+	return bet, nil
+}
+
+// FlipCoin executes the coin flip for the engine's current bet.
+// Deprecated: use Session.FlipCoin.
+func (e *Engine) FlipCoin(ctx context.Context, playerID string) (*Result, error) {
+	if e.currentBet == nil {
+		return nil, ErrGameNotActive
+	}
+
+	result, err := e.settle(ctx, playerID, e.currentBet)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clear current bet
+	e.currentBet = nil
+	return result, nil
+}
+
+// GetGameHistory returns the recent game results
+func (e *Engine) GetGameHistory(ctx context.Context, limit int) ([]*Result, error) {
+	return e.loadResults(ctx, limit)
+}
+
+// GetCurrentBet returns the engine's current bet, if any.
+// Deprecated: use Session.CurrentBet.
+func (e *Engine) GetCurrentBet() *Bet {
+	return e.currentBet
+}
+
+// CancelCurrentBet cancels the engine's current bet and refunds the player.
+// Deprecated: use Session.CancelBet.
+func (e *Engine) CancelCurrentBet(ctx context.Context, playerID string) error {
+	if e.currentBet == nil {
+		return ErrGameNotActive
+	}
+
+	if err := e.refund(ctx, playerID, e.currentBet); err != nil {
+		return err
+	}
+
+	e.currentBet = nil
+	return nil
+}
+
+// debit validates a bet and takes its amount, and the premium if it is
+// insured, from the player's balance
+func (e *Engine) debit(ctx context.Context, playerID string, amount Money, choice Side, insured bool) (_ *Bet, err error) {
+	ctx, span := tracer.Start(ctx, "game.place_bet")
+	span.SetAttributes(
+		attribute.String("player.id", playerID),
+		attribute.String("bet.choice", choice.String()),
+		attribute.Float64("bet.amount", amount.Float64()),
+		attribute.Bool("bet.insured", insured),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// Validate input parameters
 	if !choice.IsValid() {
 		return nil, ErrInvalidChoice
@@ -163,76 +461,100 @@ func (e *Engine) PlaceBet(ctx context.Context, playerID string, amount float64,
 		return nil, ErrInvalidBetAmount
 	}
 
-	// Get player and validate balance
+	if insured && !e.config.Insurance.Enabled() {
+		return nil, ErrInsuranceUnavailable
+	}
+
+	// Sessions of the same player share one balance
+	unlock := e.lockPlayer(playerID)
+	defer unlock()
+
+	// Make sure the player exists before taking the bet
 	player, err := e.GetPlayer(ctx, playerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get player: %w", err)
 	}
 
-	if player.Balance < amount {
-		return nil, ErrInsufficientBalance
-	}
-
 	// Create the bet
 	bet := &Bet{
 		ID:        e.generateBetID(),
 		Amount:    amount,
 		Choice:    choice,
-		Timestamp: time.Now(),
+		Timestamp: e.clock.Now(),
+	}
+	if insured {
+		bet.Insure(e.config.Insurance)
 	}
 
-	// Deduct amount from player balance
-	player.Balance -= amount
-	if err := e.repo.SavePlayer(ctx, player); err != nil {
-		return nil, fmt.Errorf("failed to update player balance: %w", err)
+	// Take the cost from the balance and record the open bet together, so
+	// a failure in between never takes money without a bet to show for it
+	err = e.WithTransaction(ctx, func(ctx context.Context, tx Repository) error {
+		player, err = tx.GetPlayer(ctx, playerID)
+		if err != nil {
+			return fmt.Errorf("failed to get player: %w", err)
+		}
+		if player.Balance < bet.Cost() {
+			return ErrInsufficientBalance
+		}
+
+		player.Balance -= bet.Cost()
+		player.OpenBets = append(player.OpenBets, bet)
+		if err := tx.SavePlayer(ctx, player); err != nil {
+			return fmt.Errorf("failed to update player balance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	e.currentBet = bet
 	e.logger.Info("Bet placed",
 		zap.String("player_id", playerID),
 		zap.String("bet_id", bet.ID),
-		zap.Float64("amount", amount),
+		zap.Float64("amount", amount.Float64()),
 		zap.String("choice", choice.String()),
+		zap.Float64("premium", bet.Premium.Float64()),
 	)
+	e.audit.Record(logger.AuditEvent{
+		Time:     bet.Timestamp,
+		Event:    logger.AuditBet,
+		PlayerID: playerID,
+		BetID:    bet.ID,
+		Amount:   amount.Float64(),
+		Choice:   choice.String(),
+		Balance:  (player.Balance + bet.Premium).Float64(),
+	})
+	if bet.Insured() {
+		e.audit.Record(logger.AuditEvent{
+			Time:     bet.Timestamp,
+			Event:    logger.AuditInsurance,
+			PlayerID: playerID,
+			BetID:    bet.ID,
+			Amount:   bet.Premium.Float64(),
+			Balance:  player.Balance.Float64(),
+		})
+	}
 
 	return bet, nil
 }
 
-// FlipCoin executes the coin flip and determines the result
-func (e *Engine) FlipCoin(ctx context.Context, playerID string) (*Result, error) {
-	if e.currentBet == nil {
-		return nil, ErrGameNotActive
-	}
-
-	// Generate secure random seed for the coin flip
-	seed, err := e.rng.GenerateSecureSeed()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate random seed: %w", err)
-	}
+// settle flips the coin for a bet and pays out the player if it won
+func (e *Engine) settle(ctx context.Context, playerID string, bet *Bet) (_ *Result, err error) {
+	ctx, span := tracer.Start(ctx, "game.settle")
+	span.SetAttributes(
+		attribute.String("player.id", playerID),
+		attribute.String("bet.id", bet.ID),
+		attribute.Bool("bet.practice", bet.Practice),
+	)
+	defer func() { tracing.End(span, err) }()
 
-	// Flip the coin using the seed
-	coinSide, err := e.rng.FlipCoin(seed)
+	coinSide, seed, err := e.flip(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to flip coin: %w", err)
+		return nil, err
 	}
 
-	// Determine if the bet won
-	won := e.currentBet.Choice == coinSide
-	var payout float64
-	if won {
-		payout = e.currentBet.Amount * e.config.PayoutRatio
-	}
-
-	// Create the result
-	result := &Result{
-		ID:        e.generateResultID(),
-		Side:      coinSide,
-		Bet:       e.currentBet,
-		Won:       won,
-		Payout:    payout,
-		Timestamp: time.Now(),
-		Seed:      seed,
-	}
+	unlock := e.lockPlayer(playerID)
+	defer unlock()
 
 	// Update player balance and stats
 	player, err := e.GetPlayer(ctx, playerID)
@@ -240,96 +562,217 @@ func (e *Engine) FlipCoin(ctx context.Context, playerID string) (*Result, error)
 		return nil, fmt.Errorf("failed to get player for result processing: %w", err)
 	}
 
-	// Add payout to balance if won
-	if won {
-		player.Balance += payout
+	// Practice bets are settled against the practice ledger only
+	stats := &player.Stats
+	if bet.Practice {
+		stats = &player.Practice
 	}
 
-	// Update statistics
-	player.Stats.GamesPlayed++
-	player.Stats.TotalWagered += e.currentBet.Amount
+	// Determine if the bet won; wins on a streak earn the streak bonus
+	won := bet.Choice == coinSide
+	var payout Money
+	var multiplier float64
 	if won {
-		player.Stats.GamesWon++
-		player.Stats.TotalWinnings += payout
+		ratio := e.config.PayoutRatio
+		if m := e.config.StreakMultiplier(stats.CurrentStreak); m > 1 {
+			multiplier = m
+			ratio *= m
+		}
+		payout = bet.Amount.Mul(ratio)
 	}
-	player.Stats.NetProfit = player.Stats.TotalWinnings - player.Stats.TotalWagered
-	if player.Stats.GamesPlayed > 0 {
-		player.Stats.WinRate = float64(player.Stats.GamesWon) / float64(player.Stats.GamesPlayed) * 100
+
+	// An insured bet gets part of its stake back when it loses
+	var insurance Money
+	if !won && bet.Insured() {
+		insurance = bet.Coverage
 	}
 
-	// Save updated player data
-	if err := e.repo.SavePlayer(ctx, player); err != nil {
-		e.logger.Error("Failed to save player after game", zap.String("player_id", playerID), zap.Error(err))
-		return nil, fmt.Errorf("failed to save player: %w", err)
+	// Create the result
+	result := &Result{
+		ID:         e.generateResultID(),
+		PlayerID:   playerID,
+		Side:       coinSide,
+		Bet:        bet,
+		Won:        won,
+		Payout:     payout,
+		Timestamp:  e.clock.Now(),
+		Seed:       seed,
+		Multiplier: multiplier,
+		Insurance:  insurance,
+	}
+	if err := result.chain(player.ChainHead); err != nil {
+		return nil, err
 	}
+	player.ChainHead = result.Hash
 
-	// Save the result
-	if err := e.repo.SaveResult(ctx, result); err != nil {
-		e.logger.Error("Failed to save game result", zap.String("result_id", result.ID), zap.Error(err))
-		return nil, fmt.Errorf("failed to save result: %w", err)
+	// Pay out the win or the insurance refund
+	if !bet.Practice {
+		player.Balance += payout + insurance
+		player.closeBet(bet.ID)
 	}
 
-	// Clear current bet
-	e.currentBet = nil
+	// Update statistics
+	stats.Record(bet.Choice, coinSide, bet.Cost(), payout+insurance)
+
+	// Save the payout, stats and result together, so a failure in between
+	// leaves the player as they were
+	err = e.WithTransaction(ctx, func(ctx context.Context, tx Repository) error {
+		if err := tx.SavePlayer(ctx, player); err != nil {
+			e.logger.Error("Failed to save player after game", zap.String("player_id", playerID), zap.Error(err))
+			return fmt.Errorf("failed to save player: %w", err)
+		}
+		if err := tx.SaveResult(ctx, result); err != nil {
+			e.logger.Error("Failed to save game result", zap.String("result_id", result.ID), zap.Error(err))
+			return fmt.Errorf("failed to save result: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	span.SetAttributes(
+		attribute.String("coin.result", coinSide.String()),
+		attribute.Bool("bet.won", won),
+	)
 	e.logger.Info("Game completed",
 		zap.String("player_id", playerID),
 		zap.String("result_id", result.ID),
 		zap.String("coin_side", coinSide.String()),
 		zap.Bool("won", won),
-		zap.Float64("payout", payout),
+		zap.Float64("payout", payout.Float64()),
+		zap.Float64("insurance", insurance.Float64()),
+		zap.Bool("practice", bet.Practice),
 	)
 
+	// No money moves on a practice bet, so the audit trail skips it
+	if bet.Practice {
+		return result, nil
+	}
+
+	e.audit.Record(logger.AuditEvent{
+		Time:     result.Timestamp,
+		Event:    logger.AuditFlip,
+		PlayerID: playerID,
+		RoundID:  result.ID,
+		BetID:    bet.ID,
+		Choice:   bet.Choice.String(),
+		Result:   coinSide.String(),
+		Seed:     seed,
+	})
+	if won {
+		e.audit.Record(logger.AuditEvent{
+			Time:     result.Timestamp,
+			Event:    logger.AuditPayout,
+			PlayerID: playerID,
+			RoundID:  result.ID,
+			BetID:    bet.ID,
+			Amount:   payout.Float64(),
+			Balance:  player.Balance.Float64(),
+		})
+	}
+	if insurance > 0 {
+		e.audit.Record(logger.AuditEvent{
+			Time:     result.Timestamp,
+			Event:    logger.AuditPayout,
+			PlayerID: playerID,
+			RoundID:  result.ID,
+			BetID:    bet.ID,
+			Amount:   insurance.Float64(),
+			Balance:  player.Balance.Float64(),
+			Reason:   "insurance",
+		})
+	}
+
 	return result, nil
 }
 
-// GetGameHistory returns the recent game results
-func (e *Engine) GetGameHistory(ctx context.Context, limit int) ([]*Result, error) {
-	return e.repo.GetResults(ctx, limit)
-}
+// flip draws a secure seed and flips the coin with it
+func (e *Engine) flip(ctx context.Context) (_ Side, _ string, err error) {
+	_, span := tracer.Start(ctx, "game.flip")
+	defer func() { tracing.End(span, err) }()
 
-// GetCurrentBet returns the current active bet, if any
-func (e *Engine) GetCurrentBet() *Bet {
-	return e.currentBet
+	// Generate secure random seed for the coin flip
+	seed, err := e.rng.GenerateSecureSeed()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate random seed: %w", err)
+	}
+
+	// Flip the coin using the seed
+	side, err := e.rng.FlipCoin(seed)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to flip coin: %w", err)
+	}
+	return side, seed, nil
 }
 
-// CancelCurrentBet cancels the current bet and refunds the player
-func (e *Engine) CancelCurrentBet(ctx context.Context, playerID string) error {
-	if e.currentBet == nil {
-		return ErrGameNotActive
+// refund returns an unsettled bet's amount and premium to the player
+func (e *Engine) refund(ctx context.Context, playerID string, bet *Bet) error {
+	if bet.Practice {
+		e.logger.Info("Practice bet cancelled",
+			zap.String("player_id", playerID),
+			zap.String("bet_id", bet.ID),
+		)
+		return nil
 	}
 
-	// Refund the bet amount to player
+	unlock := e.lockPlayer(playerID)
+	defer unlock()
+
 	player, err := e.GetPlayer(ctx, playerID)
 	if err != nil {
 		return fmt.Errorf("failed to get player for refund: %w", err)
 	}
 
-	player.Balance += e.currentBet.Amount
-	if err := e.repo.SavePlayer(ctx, player); err != nil {
+	player.Balance += bet.Cost()
+	player.closeBet(bet.ID)
+	if err := e.savePlayer(ctx, player); err != nil {
 		return fmt.Errorf("failed to refund player: %w", err)
 	}
 
 	e.logger.Info("Bet cancelled and refunded",
 		zap.String("player_id", playerID),
-		zap.String("bet_id", e.currentBet.ID),
-		zap.Float64("refund_amount", e.currentBet.Amount),
+		zap.String("bet_id", bet.ID),
+		zap.Float64("refund_amount", bet.Cost().Float64()),
 	)
+	e.audit.Record(logger.AuditEvent{
+		Time:     e.clock.Now(),
+		Event:    logger.AuditRefund,
+		PlayerID: playerID,
+		BetID:    bet.ID,
+		Amount:   bet.Cost().Float64(),
+		Balance:  player.Balance.Float64(),
+	})
 
-	e.currentBet = nil
 	return nil
 }
 
-// generateBetID creates a unique identifier for a bet
+// lockPlayer serializes balance updates for one player across sessions
+// and returns the matching unlock
+func (e *Engine) lockPlayer(playerID string) func() {
+	e.playersMu.Lock()
+	lock, exists := e.playerLocks[playerID]
+	if !exists {
+		lock = &sync.Mutex{}
+		e.playerLocks[playerID] = lock
+	}
+	e.playersMu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// generateBetID creates a unique identifier for a bet. The sequence keeps
+// IDs unique when sessions bet in the same nanosecond.
 func (e *Engine) generateBetID() string {
 	timestamp := time.Now().UnixNano()
-	return fmt.Sprintf("bet_%d", timestamp)
+	return fmt.Sprintf("bet_%d_%d", timestamp, e.idSeq.Add(1))
 }
 
 // generateResultID creates a unique identifier for a game result
 func (e *Engine) generateResultID() string {
 	timestamp := time.Now().UnixNano()
-	return fmt.Sprintf("result_%d", timestamp)
+	return fmt.Sprintf("result_%d_%d", timestamp, e.idSeq.Add(1))
 }
 
 // DefaultRandomGenerator implements RandomGenerator using crypto/rand
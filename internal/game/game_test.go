@@ -8,7 +8,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/clock"
 )
 
 // MockRepository implements the Repository interface for testing
@@ -71,11 +78,55 @@ func TestSide_IsValid(t *testing.T) {
 	assert.False(t, Side("").IsValid())
 }
 
+func TestStats_Record(t *testing.T) {
+	var stats Stats
+
+	stats.Record(Heads, Heads, 10*Dollar, 20*Dollar)
+	stats.Record(Heads, Tails, 30*Dollar, 0)
+	stats.Record(Tails, Tails, 5*Dollar, 10*Dollar)
+
+	assert.Equal(t, 3, stats.GamesPlayed)
+	assert.Equal(t, 2, stats.GamesWon)
+	assert.Equal(t, 45*Dollar, stats.TotalWagered)
+	assert.Equal(t, 30*Dollar, stats.TotalWinnings)
+	assert.Equal(t, -15*Dollar, stats.NetProfit)
+	assert.InDelta(t, 66.67, stats.WinRate, 0.01)
+	assert.Equal(t, 20*Dollar, stats.BiggestWin)
+	assert.Equal(t, Distribution{Heads: 2, Tails: 1}, stats.Choices)
+	assert.Equal(t, Distribution{Heads: 1, Tails: 2}, stats.Outcomes)
+}
+
+func TestDistribution_Percent(t *testing.T) {
+	var empty Distribution
+	assert.Equal(t, 0.0, empty.Percent(Heads))
+
+	d := Distribution{Heads: 3, Tails: 1}
+	assert.Equal(t, 4, d.Total())
+	assert.Equal(t, 75.0, d.Percent(Heads))
+	assert.Equal(t, 25.0, d.Percent(Tails))
+}
+
+func TestSummarizeResults(t *testing.T) {
+	results := []*Result{
+		{Side: Heads, Bet: &Bet{Amount: 10 * Dollar, Choice: Heads}, Won: true, Payout: 20 * Dollar},
+		{Side: Heads, Bet: &Bet{Amount: 10 * Dollar, Choice: Tails}},
+		{Side: Tails},
+	}
+
+	stats := SummarizeResults(results)
+
+	assert.Equal(t, 2, stats.GamesPlayed)
+	assert.Equal(t, 1, stats.GamesWon)
+	assert.Equal(t, Money(0), stats.NetProfit)
+	assert.Equal(t, Distribution{Heads: 2, Tails: 1}, stats.Outcomes)
+	assert.Equal(t, Distribution{Heads: 1, Tails: 1}, stats.Choices)
+}
+
 func TestNewEngine(t *testing.T) {
 	config := Config{
-		StartingBalance: 1000,
-		MinBet:          1,
-		MaxBet:          100,
+		StartingBalance: 1000 * Dollar,
+		MinBet:          Dollar,
+		MaxBet:          100 * Dollar,
 		PayoutRatio:     2.0,
 	}
 	repo := &MockRepository{}
@@ -110,7 +161,7 @@ func TestEngine_CreatePlayer(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			config := Config{StartingBalance: 1000, MinBet: 1, MaxBet: 100, PayoutRatio: 2.0}
+			config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
 			repo := &MockRepository{}
 			rng := &MockRandomGenerator{}
 			logger := zaptest.NewLogger(t)
@@ -119,8 +170,8 @@ func TestEngine_CreatePlayer(t *testing.T) {
 			ctx := context.Background()
 
 			// Set up mock expectations
-			repo.On("SavePlayer", ctx, mock.MatchedBy(func(p *Player) bool {
-				return p.ID == tt.playerID && p.Balance == 1000
+			repo.On("SavePlayer", mock.Anything, mock.MatchedBy(func(p *Player) bool {
+				return p.ID == tt.playerID && p.Balance == 1000*Dollar
 			})).Return(tt.saveError)
 
 			player, err := engine.CreatePlayer(ctx, tt.playerID)
@@ -133,7 +184,7 @@ func TestEngine_CreatePlayer(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotNil(t, player)
 				assert.Equal(t, tt.playerID, player.ID)
-				assert.Equal(t, 1000.0, player.Balance)
+				assert.Equal(t, 1000*Dollar, player.Balance)
 			}
 
 			repo.AssertExpectations(t)
@@ -155,7 +206,7 @@ func TestEngine_GetPlayer(t *testing.T) {
 			playerID: "existing_player",
 			existingPlayer: &Player{
 				ID:      "existing_player",
-				Balance: 500,
+				Balance: 500 * Dollar,
 			},
 		},
 		{
@@ -175,7 +226,7 @@ func TestEngine_GetPlayer(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			config := Config{StartingBalance: 1000, MinBet: 1, MaxBet: 100, PayoutRatio: 2.0}
+			config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
 			repo := &MockRepository{}
 			rng := &MockRandomGenerator{}
 			logger := zaptest.NewLogger(t)
@@ -184,9 +235,9 @@ func TestEngine_GetPlayer(t *testing.T) {
 			ctx := context.Background()
 
 			// Set up mock expectations
-			repo.On("GetPlayer", ctx, tt.playerID).Return(tt.existingPlayer, tt.getError)
+			repo.On("GetPlayer", mock.Anything, tt.playerID).Return(tt.existingPlayer, tt.getError)
 			if tt.getError != nil {
-				repo.On("SavePlayer", ctx, mock.MatchedBy(func(p *Player) bool {
+				repo.On("SavePlayer", mock.Anything, mock.MatchedBy(func(p *Player) bool {
 					return p.ID == tt.playerID
 				})).Return(tt.saveError)
 			}
@@ -210,9 +261,9 @@ func TestEngine_GetPlayer(t *testing.T) {
 func TestEngine_PlaceBet(t *testing.T) {
 	tests := []struct {
 		name          string
-		amount        float64
+		amount        Money
 		choice        Side
-		playerBalance float64
+		playerBalance Money
 		existingBet   *Bet
 		getError      error
 		saveError     error
@@ -220,40 +271,40 @@ func TestEngine_PlaceBet(t *testing.T) {
 	}{
 		{
 			name:          "successful bet",
-			amount:        10,
+			amount:        10 * Dollar,
 			choice:        Heads,
-			playerBalance: 100,
+			playerBalance: 100 * Dollar,
 		},
 		{
 			name:          "invalid choice",
-			amount:        10,
+			amount:        10 * Dollar,
 			choice:        Side("invalid"),
 			expectedError: "invalid choice",
 		},
 		{
 			name:          "bet too low",
-			amount:        0.5,
+			amount:        50 * Cent,
 			choice:        Heads,
 			expectedError: "invalid bet amount",
 		},
 		{
 			name:          "bet too high",
-			amount:        150,
+			amount:        150 * Dollar,
 			choice:        Heads,
 			expectedError: "invalid bet amount",
 		},
 		{
 			name:          "insufficient balance",
-			amount:        10,
+			amount:        10 * Dollar,
 			choice:        Heads,
-			playerBalance: 5,
+			playerBalance: 5 * Dollar,
 			expectedError: "insufficient balance",
 		},
 		{
 			name:          "save player error",
-			amount:        10,
+			amount:        10 * Dollar,
 			choice:        Heads,
-			playerBalance: 100,
+			playerBalance: 100 * Dollar,
 			saveError:     errors.New("save failed"),
 			expectedError: "failed to update player balance",
 		},
@@ -261,7 +312,7 @@ func TestEngine_PlaceBet(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			config := Config{StartingBalance: 1000, MinBet: 1, MaxBet: 100, PayoutRatio: 2.0}
+			config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
 			repo := &MockRepository{}
 			rng := &MockRandomGenerator{}
 			logger := zaptest.NewLogger(t)
@@ -276,19 +327,19 @@ func TestEngine_PlaceBet(t *testing.T) {
 			}
 
 			// Set up mock expectations
-			if tt.getError == nil && tt.choice.IsValid() && tt.amount >= 1 && tt.amount <= 100 {
+			if tt.getError == nil && tt.choice.IsValid() && tt.amount >= Dollar && tt.amount <= 100*Dollar {
 				player := &Player{
 					ID:      playerID,
 					Balance: tt.playerBalance,
 				}
-				repo.On("GetPlayer", ctx, playerID).Return(player, nil)
+				repo.On("GetPlayer", mock.Anything, playerID).Return(player, nil)
 
 				if tt.playerBalance >= tt.amount {
 					updatedPlayer := &Player{
 						ID:      playerID,
 						Balance: tt.playerBalance - tt.amount,
 					}
-					repo.On("SavePlayer", ctx, mock.MatchedBy(func(p *Player) bool {
+					repo.On("SavePlayer", mock.Anything, mock.MatchedBy(func(p *Player) bool {
 						return p.Balance == updatedPlayer.Balance
 					})).Return(tt.saveError)
 				}
@@ -388,7 +439,7 @@ func TestEngine_FlipCoin(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			config := Config{StartingBalance: 1000, MinBet: 1, MaxBet: 100, PayoutRatio: 2.0}
+			config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
 			repo := &MockRepository{}
 			rng := &MockRandomGenerator{}
 			logger := zaptest.NewLogger(t)
@@ -401,7 +452,7 @@ func TestEngine_FlipCoin(t *testing.T) {
 			if tt.hasBet {
 				engine.currentBet = &Bet{
 					ID:        "test_bet",
-					Amount:    10,
+					Amount:    10 * Dollar,
 					Choice:    tt.betChoice,
 					Timestamp: time.Now(),
 				}
@@ -419,24 +470,24 @@ func TestEngine_FlipCoin(t *testing.T) {
 						if tt.getPlayerError == nil {
 							player := &Player{
 								ID:      playerID,
-								Balance: 100,
+								Balance: 100 * Dollar,
 								Stats:   Stats{},
 							}
-							repo.On("GetPlayer", ctx, playerID).Return(player, tt.getPlayerError)
+							repo.On("GetPlayer", mock.Anything, playerID).Return(player, tt.getPlayerError)
 
 							if tt.savePlayerError != nil {
-								repo.On("SavePlayer", ctx, mock.AnythingOfType("*game.Player")).Return(tt.savePlayerError)
+								repo.On("SavePlayer", mock.Anything, mock.AnythingOfType("*game.Player")).Return(tt.savePlayerError)
 							} else if tt.saveResultError != nil {
-								repo.On("SavePlayer", ctx, mock.AnythingOfType("*game.Player")).Return(nil)
-								repo.On("SaveResult", ctx, mock.AnythingOfType("*game.Result")).Return(tt.saveResultError)
+								repo.On("SavePlayer", mock.Anything, mock.AnythingOfType("*game.Player")).Return(nil)
+								repo.On("SaveResult", mock.Anything, mock.AnythingOfType("*game.Result")).Return(tt.saveResultError)
 							} else {
-								repo.On("SavePlayer", ctx, mock.AnythingOfType("*game.Player")).Return(nil)
-								repo.On("SaveResult", ctx, mock.AnythingOfType("*game.Result")).Return(nil)
+								repo.On("SavePlayer", mock.Anything, mock.AnythingOfType("*game.Player")).Return(nil)
+								repo.On("SaveResult", mock.Anything, mock.AnythingOfType("*game.Result")).Return(nil)
 							}
 						} else {
 							// When GetPlayer fails, engine will try to create a new player
-							repo.On("GetPlayer", ctx, playerID).Return(nil, tt.getPlayerError)
-							repo.On("SavePlayer", ctx, mock.AnythingOfType("*game.Player")).Return(tt.getPlayerError)
+							repo.On("GetPlayer", mock.Anything, playerID).Return(nil, tt.getPlayerError)
+							repo.On("SavePlayer", mock.Anything, mock.AnythingOfType("*game.Player")).Return(tt.getPlayerError)
 						}
 					}
 				}
@@ -456,9 +507,9 @@ func TestEngine_FlipCoin(t *testing.T) {
 				assert.Nil(t, engine.GetCurrentBet()) // Bet should be cleared
 
 				if tt.expectedWin {
-					assert.Equal(t, 20.0, result.Payout) // 10 * 2.0 payout ratio
+					assert.Equal(t, 20*Dollar, result.Payout) // 10 * 2.0 payout ratio
 				} else {
-					assert.Equal(t, 0.0, result.Payout)
+					assert.Equal(t, Money(0), result.Payout)
 				}
 			}
 
@@ -468,6 +519,80 @@ func TestEngine_FlipCoin(t *testing.T) {
 	}
 }
 
+func TestEngine_TimestampsUseClock(t *testing.T) {
+	config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
+	repo := &MockRepository{}
+	rng := &MockRandomGenerator{}
+	engine := NewEngine(config, repo, rng, zaptest.NewLogger(t))
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	engine.SetClock(fake)
+
+	ctx := context.Background()
+	player := &Player{ID: "test_player", Balance: 100 * Dollar}
+	repo.On("GetPlayer", mock.Anything, "test_player").Return(player, nil)
+	repo.On("SavePlayer", mock.Anything, mock.AnythingOfType("*game.Player")).Return(nil)
+	repo.On("SaveResult", mock.Anything, mock.AnythingOfType("*game.Result")).Return(nil)
+	rng.On("GenerateSecureSeed").Return("test_seed", nil)
+	rng.On("FlipCoin", "test_seed").Return(string(Heads), nil)
+
+	bet, err := engine.PlaceBet(ctx, "test_player", 10*Dollar, Heads)
+	assert.NoError(t, err)
+	assert.Equal(t, start, bet.Timestamp)
+
+	fake.Advance(5 * time.Second)
+	result, err := engine.FlipCoin(ctx, "test_player")
+	assert.NoError(t, err)
+	assert.Equal(t, start.Add(5*time.Second), result.Timestamp)
+}
+
+// recordedSpans keeps the spans ended by this package's tests
+var recordedSpans = tracetest.NewSpanRecorder()
+
+func init() {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recordedSpans)))
+}
+
+func TestEngine_Tracing(t *testing.T) {
+	config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
+	repo := &MockRepository{}
+	rng := &MockRandomGenerator{}
+	engine := NewEngine(config, repo, rng, zaptest.NewLogger(t))
+
+	ctx, caller := otel.Tracer("test").Start(context.Background(), "caller")
+	player := &Player{ID: "test_player", Balance: 100 * Dollar}
+	repo.On("GetPlayer", mock.Anything, "test_player").Return(player, nil)
+	repo.On("SavePlayer", mock.Anything, mock.AnythingOfType("*game.Player")).Return(nil)
+	repo.On("SaveResult", mock.Anything, mock.AnythingOfType("*game.Result")).Return(nil)
+	rng.On("GenerateSecureSeed").Return("test_seed", nil)
+	rng.On("FlipCoin", "test_seed").Return(string(Heads), nil)
+
+	_, err := engine.PlaceBet(ctx, "test_player", 10*Dollar, Heads)
+	require.NoError(t, err)
+	_, err = engine.FlipCoin(ctx, "test_player")
+	require.NoError(t, err)
+	_, err = engine.PlaceBet(ctx, "test_player", 1000*Dollar, Heads)
+	require.ErrorIs(t, err, ErrInvalidBetAmount)
+	caller.End()
+
+	spans := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range recordedSpans.Ended() {
+		if span.SpanContext().TraceID() == caller.SpanContext().TraceID() {
+			spans[span.Name()] = append(spans[span.Name()], span)
+		}
+	}
+	require.Len(t, spans["game.place_bet"], 2)
+	require.Len(t, spans["game.settle"], 1)
+	require.Len(t, spans["game.flip"], 1)
+	assert.Equal(t, caller.SpanContext().SpanID(), spans["game.place_bet"][0].Parent().SpanID())
+	assert.Equal(t, caller.SpanContext().SpanID(), spans["game.settle"][0].Parent().SpanID())
+	assert.Equal(t, spans["game.settle"][0].SpanContext().SpanID(), spans["game.flip"][0].Parent().SpanID())
+
+	// A refused bet is marked failed
+	assert.Equal(t, codes.Error, spans["game.place_bet"][1].Status().Code)
+}
+
 func TestEngine_CancelCurrentBet(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -501,7 +626,7 @@ func TestEngine_CancelCurrentBet(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			config := Config{StartingBalance: 1000, MinBet: 1, MaxBet: 100, PayoutRatio: 2.0}
+			config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
 			repo := &MockRepository{}
 			rng := &MockRandomGenerator{}
 			logger := zaptest.NewLogger(t)
@@ -514,7 +639,7 @@ func TestEngine_CancelCurrentBet(t *testing.T) {
 			if tt.hasBet {
 				engine.currentBet = &Bet{
 					ID:     "test_bet",
-					Amount: 10,
+					Amount: 10 * Dollar,
 					Choice: Heads,
 				}
 			}
@@ -523,16 +648,16 @@ func TestEngine_CancelCurrentBet(t *testing.T) {
 			if tt.hasBet && tt.getError == nil {
 				player := &Player{
 					ID:      playerID,
-					Balance: 90, // Already deducted bet amount
+					Balance: 90 * Dollar, // Already deducted bet amount
 				}
-				repo.On("GetPlayer", ctx, playerID).Return(player, tt.getError)
-				repo.On("SavePlayer", ctx, mock.MatchedBy(func(p *Player) bool {
-					return p.Balance == 100 // Refunded amount
+				repo.On("GetPlayer", mock.Anything, playerID).Return(player, tt.getError)
+				repo.On("SavePlayer", mock.Anything, mock.MatchedBy(func(p *Player) bool {
+					return p.Balance == 100*Dollar // Refunded amount
 				})).Return(tt.saveError)
 			} else if tt.hasBet {
-				repo.On("GetPlayer", ctx, playerID).Return(nil, tt.getError)
+				repo.On("GetPlayer", mock.Anything, playerID).Return(nil, tt.getError)
 				// When GetPlayer fails, engine will try to create a new player
-				repo.On("SavePlayer", ctx, mock.AnythingOfType("*game.Player")).Return(tt.getError)
+				repo.On("SavePlayer", mock.Anything, mock.AnythingOfType("*game.Player")).Return(tt.getError)
 			}
 
 			err := engine.CancelCurrentBet(ctx, playerID)
@@ -551,7 +676,7 @@ func TestEngine_CancelCurrentBet(t *testing.T) {
 }
 
 func TestEngine_GetGameHistory(t *testing.T) {
-	config := Config{StartingBalance: 1000, MinBet: 1, MaxBet: 100, PayoutRatio: 2.0}
+	config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
 	repo := &MockRepository{}
 	rng := &MockRandomGenerator{}
 	logger := zaptest.NewLogger(t)
@@ -565,7 +690,7 @@ func TestEngine_GetGameHistory(t *testing.T) {
 		{ID: "2", Side: Tails, Won: false},
 	}
 
-	repo.On("GetResults", ctx, limit).Return(expectedResults, nil)
+	repo.On("GetResults", mock.Anything, limit).Return(expectedResults, nil)
 
 	results, err := engine.GetGameHistory(ctx, limit)
 
@@ -574,6 +699,52 @@ func TestEngine_GetGameHistory(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestEngine_StreakBonus(t *testing.T) {
+	config := Config{
+		StartingBalance:     1000 * Dollar,
+		MinBet:              Dollar,
+		MaxBet:              100 * Dollar,
+		PayoutRatio:         2.0,
+		StreakBonus:         0.5,
+		MaxStreakMultiplier: 2.0,
+	}
+	engine := NewEngine(config, newMapRepository(), fixedGenerator{side: Heads}, zaptest.NewLogger(t))
+	session := engine.NewSession("player")
+	ctx := context.Background()
+
+	play := func(choice Side) *Result {
+		_, err := session.PlaceBet(ctx, 10*Dollar, choice)
+		require.NoError(t, err)
+		result, err := session.FlipCoin(ctx)
+		require.NoError(t, err)
+		return result
+	}
+
+	// The multiplier grows with each prior win and stops at the cap
+	for _, want := range []Money{20 * Dollar, 30 * Dollar, 40 * Dollar, 40 * Dollar} {
+		assert.Equal(t, want, play(Heads).Payout)
+	}
+
+	lost := play(Tails)
+	assert.Zero(t, lost.Payout)
+	assert.Zero(t, lost.Multiplier)
+
+	// A loss resets the streak to a plain payout
+	won := play(Heads)
+	assert.Equal(t, 20*Dollar, won.Payout)
+	assert.Zero(t, won.Multiplier)
+
+	player, err := session.Player(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, player.Stats.CurrentStreak)
+	assert.Equal(t, 4, player.Stats.LongestStreak)
+
+	// Bonus payouts still pass the fairness payout check
+	results, err := engine.GetGameHistory(ctx, 0)
+	require.NoError(t, err)
+	assert.Zero(t, AnalyzeFairness(results, config.PayoutRatio).PayoutMismatches)
+}
+
 func TestDefaultRandomGenerator_GenerateSecureSeed(t *testing.T) {
 	rng := NewDefaultRandomGenerator()
 
@@ -647,3 +818,12 @@ func BenchmarkDefaultRandomGenerator_FlipCoin(b *testing.B) {
 		}
 	}
 }
+
+func TestPlayer_Fresh(t *testing.T) {
+	start := 1000 * Dollar
+	assert.True(t, (&Player{ID: "p1", Balance: start, Stats: Stats{GamesPlayed: 3}}).Fresh(start))
+	assert.False(t, (&Player{ID: "p1", Balance: start - Dollar}).Fresh(start))
+	assert.False(t, (&Player{ID: "p1", Balance: start, Inventory: Inventory{Skins: []string{"gold"}}}).Fresh(start))
+	assert.False(t, (&Player{ID: "p1", Balance: start, Badges: []SeasonBadge{{Season: "2026-W41", Rank: 1}}}).Fresh(start))
+	assert.False(t, (&Player{ID: "p1", Balance: start, OpenBets: []*Bet{{ID: "b1"}}}).Fresh(start))
+}
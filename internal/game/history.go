@@ -0,0 +1,160 @@
+package game
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+// ResultSort is the field a result query orders by
+type ResultSort string
+
+const (
+	SortByTime   ResultSort = "time"
+	SortByAmount ResultSort = "amount"
+	SortByPayout ResultSort = "payout"
+)
+
+// ResultQuery selects one page of stored results. Zero-valued filters match
+// every result; filters on the bet skip results without one.
+type ResultQuery struct {
+	// PlayerID keeps only the player's results
+	PlayerID string
+	// Won keeps only won (true) or lost (false) bets
+	Won *bool
+	// Choice keeps only bets on this side
+	Choice Side
+	// MinAmount and MaxAmount bound the stake; a zero MaxAmount has no limit
+	MinAmount Money
+	MaxAmount Money
+	// From and To bound the result time, From inclusive and To exclusive
+	From time.Time
+	To   time.Time
+
+	// Sort orders the results, newest or largest first unless Ascending.
+	// The zero value sorts by time.
+	Sort      ResultSort
+	Ascending bool
+
+	// Offset skips that many matching results; Limit caps the page size,
+	// zero meaning no cap
+	Offset int
+	Limit  int
+}
+
+// ResultPage is one page of query results and how many results matched
+// the query in total
+type ResultPage struct {
+	Results []*Result `json:"results"`
+	Total   int       `json:"total"`
+}
+
+// HasMore reports whether results matched the query beyond this page
+func (p *ResultPage) HasMore(query ResultQuery) bool {
+	return query.Offset+len(p.Results) < p.Total
+}
+
+// ResultQuerier is implemented by repositories that can search their
+// results a page at a time
+type ResultQuerier interface {
+	QueryResults(ctx context.Context, query ResultQuery) (*ResultPage, error)
+}
+
+// Matches reports whether a result passes the query's filters
+func (q ResultQuery) Matches(result *Result) bool {
+	if q.PlayerID != "" && result.PlayerID != q.PlayerID {
+		return false
+	}
+	if !q.From.IsZero() && result.Timestamp.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !result.Timestamp.Before(q.To) {
+		return false
+	}
+
+	filtersBet := q.Won != nil || q.Choice != "" || q.MinAmount > 0 || q.MaxAmount > 0
+	if !filtersBet {
+		return true
+	}
+	if result.Bet == nil {
+		return false
+	}
+
+	if q.Won != nil && result.Won != *q.Won {
+		return false
+	}
+	if q.Choice != "" && result.Bet.Choice != q.Choice {
+		return false
+	}
+	if result.Bet.Amount < q.MinAmount {
+		return false
+	}
+	if q.MaxAmount > 0 && result.Bet.Amount > q.MaxAmount {
+		return false
+	}
+	return true
+}
+
+// Apply filters, sorts and pages results by the query. The results slice
+// is left untouched.
+func (q ResultQuery) Apply(results []*Result) *ResultPage {
+	matched := make([]*Result, 0, len(results))
+	for _, result := range results {
+		if q.Matches(result) {
+			matched = append(matched, result)
+		}
+	}
+
+	// Ties fall back to time, then ID, so pages never overlap
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if q.Ascending {
+			a, b = b, a
+		}
+		switch {
+		case q.Sort == SortByAmount && betAmount(a) != betAmount(b):
+			return betAmount(a) > betAmount(b)
+		case q.Sort == SortByPayout && a.Payout != b.Payout:
+			return a.Payout > b.Payout
+		case !a.Timestamp.Equal(b.Timestamp):
+			return a.Timestamp.After(b.Timestamp)
+		default:
+			return a.ID > b.ID
+		}
+	})
+
+	page := &ResultPage{Total: len(matched)}
+	start := min(max(q.Offset, 0), len(matched))
+	end := len(matched)
+	if q.Limit > 0 {
+		end = min(start+q.Limit, end)
+	}
+	page.Results = matched[start:end]
+	return page
+}
+
+// betAmount returns a result's stake, zero for results without a bet
+func betAmount(result *Result) Money {
+	if result.Bet == nil {
+		return 0
+	}
+	return result.Bet.Amount
+}
+
+// QueryHistory returns one page of game results matching the query. Like
+// GetGameHistory it covers every result the repository stores.
+func (e *Engine) QueryHistory(ctx context.Context, query ResultQuery) (*ResultPage, error) {
+	if querier, ok := e.repo.(ResultQuerier); ok {
+		return repoCall(ctx, e, func(ctx context.Context) (*ResultPage, error) {
+			return querier.QueryResults(ctx, query)
+		})
+	}
+
+	// Repositories without search are filtered here
+	results, err := e.loadResults(ctx, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+	return query.Apply(results), nil
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestResultQuery_Matches(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	won := &Result{PlayerID: "p1", Side: Heads, Bet: &Bet{Amount: 25 * Dollar, Choice: Heads}, Won: true, Payout: 50 * Dollar, Timestamp: now}
+	noBet := &Result{Side: Tails, Timestamp: now}
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		query  ResultQuery
+		result *Result
+		want   bool
+	}{
+		{name: "empty query", query: ResultQuery{}, result: noBet, want: true},
+		{name: "other player", query: ResultQuery{PlayerID: "p2"}, result: won, want: false},
+		{name: "won", query: ResultQuery{Won: &yes}, result: won, want: true},
+		{name: "lost", query: ResultQuery{Won: &no}, result: won, want: false},
+		{name: "bet filters skip results without a bet", query: ResultQuery{Won: &no}, result: noBet, want: false},
+		{name: "side", query: ResultQuery{Choice: Tails}, result: won, want: false},
+		{name: "amount in range", query: ResultQuery{MinAmount: 25 * Dollar, MaxAmount: 25 * Dollar}, result: won, want: true},
+		{name: "amount below range", query: ResultQuery{MinAmount: 26 * Dollar}, result: won, want: false},
+		{name: "from is inclusive", query: ResultQuery{From: now}, result: won, want: true},
+		{name: "to is exclusive", query: ResultQuery{To: now}, result: won, want: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.query.Matches(tt.result), tt.name)
+	}
+}
+
+func TestEngine_QueryHistoryWithoutSearchableRepository(t *testing.T) {
+	repo := newMapRepository()
+	engine := NewEngine(Config{StartingBalance: 100 * Dollar, MinBet: Dollar, MaxBet: 50 * Dollar, PayoutRatio: 2},
+		repo, fixedGenerator{side: Heads}, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	session := engine.NewSession("p1")
+	for _, choice := range []Side{Heads, Tails, Heads} {
+		_, err := session.PlaceBet(ctx, 10*Dollar, choice)
+		require.NoError(t, err)
+		_, err = session.FlipCoin(ctx)
+		require.NoError(t, err)
+	}
+
+	lost := false
+	page, err := engine.QueryHistory(ctx, ResultQuery{Won: &lost})
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, Tails, page.Results[0].Bet.Choice)
+
+	page, err = engine.QueryHistory(ctx, ResultQuery{Offset: 1, Limit: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Len(t, page.Results, 2)
+}
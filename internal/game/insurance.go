@@ -0,0 +1,79 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInsuranceUnavailable is returned when insuring a bet where insurance
+// is not offered
+var ErrInsuranceUnavailable = errors.New("bet insurance is not offered")
+
+// Insurance is an optional cover a player buys with a bet: Cost of the stake
+// is paid on top of it, and Coverage of the stake comes back if the bet
+// loses. Cost 0.1 and Coverage 0.5 charge 10% of the stake to refund half
+// of it on a loss. The zero value offers no insurance.
+type Insurance struct {
+	Cost     float64 `json:"cost"`
+	Coverage float64 `json:"coverage"`
+}
+
+// Enabled reports whether insurance is offered
+func (i Insurance) Enabled() bool {
+	return i.Cost > 0 && i.Coverage > 0
+}
+
+// Validate checks the cost and coverage are shares of the stake, set
+// together or not at all
+func (i Insurance) Validate() error {
+	if i.Cost < 0 || i.Cost > 1 || i.Coverage < 0 || i.Coverage > 1 {
+		return fmt.Errorf("insurance cost and coverage must be between 0 and 1, got %.2f and %.2f", i.Cost, i.Coverage)
+	}
+	if (i.Cost > 0) != (i.Coverage > 0) {
+		return errors.New("insurance cost and coverage must both be set or both be 0")
+	}
+	return nil
+}
+
+// Premium returns the price of insuring a stake, rounded to the cent
+func (i Insurance) Premium(stake Money) Money {
+	return stake.Mul(i.Cost)
+}
+
+// Refund returns what an insured stake pays back when it loses, rounded
+// to the cent
+func (i Insurance) Refund(stake Money) Money {
+	return stake.Mul(i.Coverage)
+}
+
+// ExpectedValue returns how much insuring a stake changes the bet's
+// expected return on a fair coin: the refund half the time, less the
+// premium. It is negative when insurance costs more than it pays back.
+// Expected values are averages rather than amounts paid, so they are not
+// rounded to the cent.
+func (i Insurance) ExpectedValue(stake float64) float64 {
+	return stake*i.Coverage/2 - stake*i.Cost
+}
+
+// ExpectedValue returns a bet's expected return on a fair coin at a payout
+// ratio, before any streak bonus: the payout half the time, less the stake
+func ExpectedValue(stake, payoutRatio float64) float64 {
+	return stake*payoutRatio/2 - stake
+}
+
+// Insure buys the insurance for a bet
+func (b *Bet) Insure(insurance Insurance) {
+	b.Premium = insurance.Premium(b.Amount)
+	b.Coverage = insurance.Refund(b.Amount)
+}
+
+// Insured reports whether the bet was insured
+func (b *Bet) Insured() bool {
+	return b.Premium > 0
+}
+
+// Cost returns everything the bet took from the balance: the stake and
+// any insurance premium
+func (b *Bet) Cost() Money {
+	return b.Amount + b.Premium
+}
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestInsurance_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		insurance Insurance
+		wantErr   bool
+	}{
+		{"disabled", Insurance{}, false},
+		{"enabled", Insurance{Cost: 0.1, Coverage: 0.5}, false},
+		{"full cover", Insurance{Cost: 1, Coverage: 1}, false},
+		{"cost without coverage", Insurance{Cost: 0.1}, true},
+		{"coverage without cost", Insurance{Coverage: 0.5}, true},
+		{"negative cost", Insurance{Cost: -0.1, Coverage: 0.5}, true},
+		{"coverage above stake", Insurance{Cost: 0.1, Coverage: 1.5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.insurance.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInsurance_ExpectedValue(t *testing.T) {
+	insurance := Insurance{Cost: 0.1, Coverage: 0.5}
+
+	assert.True(t, insurance.Enabled())
+	assert.False(t, Insurance{}.Enabled())
+	assert.Equal(t, 2*Dollar, insurance.Premium(20*Dollar))
+	assert.Equal(t, 10*Dollar, insurance.Refund(20*Dollar))
+	assert.InDelta(t, 3.0, insurance.ExpectedValue(20), 1e-9)
+
+	assert.InDelta(t, 0.0, ExpectedValue(20, 2), 1e-9)
+	assert.InDelta(t, -1.0, ExpectedValue(20, 1.9), 1e-9)
+
+	// Premiums and refunds are rounded to the cent
+	assert.Equal(t, 11*Cent, insurance.Premium(105*Cent))
+	assert.Equal(t, 53*Cent, insurance.Refund(105*Cent))
+}
+
+func newInsuredEngine(t *testing.T, side Side) *Engine {
+	config := Config{
+		StartingBalance: 1000 * Dollar,
+		MinBet:          Dollar,
+		MaxBet:          100 * Dollar,
+		PayoutRatio:     2.0,
+		Insurance:       Insurance{Cost: 0.1, Coverage: 0.5},
+	}
+	return NewEngine(config, newMapRepository(), fixedGenerator{side: side}, zaptest.NewLogger(t))
+}
+
+func TestSession_PlaceInsuredBet(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("loss refunds coverage", func(t *testing.T) {
+		session := newInsuredEngine(t, Tails).NewSession("alice")
+
+		bet, err := session.PlaceInsuredBet(ctx, 20*Dollar, Heads)
+		require.NoError(t, err)
+		assert.True(t, bet.Insured())
+		assert.Equal(t, 2*Dollar, bet.Premium)
+		assert.Equal(t, 10*Dollar, bet.Coverage)
+
+		player, err := session.Player(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 978*Dollar, player.Balance)
+
+		result, err := session.FlipCoin(ctx)
+		require.NoError(t, err)
+		assert.False(t, result.Won)
+		assert.Zero(t, result.Payout)
+		assert.Equal(t, 10*Dollar, result.Insurance)
+
+		player, err = session.Player(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 988*Dollar, player.Balance)
+		assert.Equal(t, 22*Dollar, player.Stats.TotalWagered)
+		assert.Equal(t, 10*Dollar, player.Stats.TotalWinnings)
+	})
+
+	t.Run("win pays out without refund", func(t *testing.T) {
+		session := newInsuredEngine(t, Heads).NewSession("alice")
+
+		_, err := session.PlaceInsuredBet(ctx, 20*Dollar, Heads)
+		require.NoError(t, err)
+
+		result, err := session.FlipCoin(ctx)
+		require.NoError(t, err)
+		assert.True(t, result.Won)
+		assert.Equal(t, 40*Dollar, result.Payout)
+		assert.Zero(t, result.Insurance)
+
+		player, err := session.Player(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1018*Dollar, player.Balance)
+	})
+
+	t.Run("cancel returns the premium", func(t *testing.T) {
+		session := newInsuredEngine(t, Heads).NewSession("alice")
+
+		_, err := session.PlaceInsuredBet(ctx, 20*Dollar, Heads)
+		require.NoError(t, err)
+		require.NoError(t, session.CancelBet(ctx))
+
+		player, err := session.Player(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1000*Dollar, player.Balance)
+	})
+
+	t.Run("unavailable when disabled", func(t *testing.T) {
+		engine, _ := newSessionEngine(t, Heads)
+		session := engine.NewSession("alice")
+
+		_, err := session.PlaceInsuredBet(ctx, 20*Dollar, Heads)
+		assert.ErrorIs(t, err, ErrInsuranceUnavailable)
+
+		player, err := session.Player(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1000*Dollar, player.Balance)
+	})
+}
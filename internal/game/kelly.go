@@ -0,0 +1,74 @@
+package game
+
+import "fmt"
+
+// DefaultKellyFraction is the share of the full Kelly stake suggested
+// unless asked otherwise. Half Kelly gives up a quarter of the growth for
+// much smaller swings in the balance.
+const DefaultKellyFraction = 0.5
+
+// BetSuggestion is the stake the Kelly criterion suggests for a balance
+type BetSuggestion struct {
+	// WinProbability is the chance of winning the suggestion assumes
+	WinProbability float64 `json:"win_probability"`
+	// ExpectedReturn is the profit expected per dollar staked, before any
+	// streak bonus
+	ExpectedReturn float64 `json:"expected_return"`
+	// Kelly is the full Kelly share of the balance, and Share what is
+	// left of it after scaling by the Kelly fraction
+	Kelly float64 `json:"kelly"`
+	Share float64 `json:"share"`
+	// Amount is the suggested stake within the bet limits. It is zero when
+	// the bet is not worth making or its share comes to less than the
+	// minimum bet.
+	Amount Money `json:"amount"`
+	// Capped is set when the maximum bet lowered the amount
+	Capped bool `json:"capped,omitempty"`
+}
+
+// KellyShare returns the share of a balance the Kelly criterion stakes on
+// a bet paying payoutRatio times the stake with winProbability:
+// p - (1 - p) / (payoutRatio - 1). It is 0 when the bet loses money on
+// average, and never more than the whole balance.
+func KellyShare(payoutRatio, winProbability float64) float64 {
+	odds := payoutRatio - 1
+	if odds <= 0 {
+		return 0
+	}
+	return min(max(winProbability-(1-winProbability)/odds, 0), 1)
+}
+
+// SuggestBet returns the stake the Kelly criterion suggests for a balance
+// at the config's payout ratio and bet limits. edge is how far above an
+// even chance the player believes they win, 0.02 meaning 52% of the time,
+// and fraction the share of the full Kelly stake to suggest.
+func (c Config) SuggestBet(balance Money, edge, fraction float64) (BetSuggestion, error) {
+	if edge < -0.5 || edge >= 0.5 {
+		return BetSuggestion{}, fmt.Errorf("edge must be at least -0.5 and below 0.5, got %v", edge)
+	}
+	if fraction <= 0 || fraction > 1 {
+		return BetSuggestion{}, fmt.Errorf("kelly fraction must be above 0 and at most 1, got %v", fraction)
+	}
+
+	probability := 0.5 + edge
+	suggestion := BetSuggestion{
+		WinProbability: probability,
+		ExpectedReturn: probability*c.PayoutRatio - 1,
+		Kelly:          KellyShare(c.PayoutRatio, probability),
+	}
+	suggestion.Share = suggestion.Kelly * fraction
+	if suggestion.Share == 0 || balance <= 0 {
+		return suggestion, nil
+	}
+
+	amount := BetAmount{Percent: suggestion.Share * 100}.Resolve(balance, 0)
+	switch {
+	case amount < c.MinBet:
+		amount = 0
+	case c.MaxBet > 0 && amount > c.MaxBet:
+		amount = c.MaxBet
+		suggestion.Capped = true
+	}
+	suggestion.Amount = amount
+	return suggestion, nil
+}
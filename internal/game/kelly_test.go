@@ -0,0 +1,60 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKellyShare(t *testing.T) {
+	assert.InDelta(t, 0.04, KellyShare(2, 0.52), 1e-9)
+	assert.InDelta(t, 0.1, KellyShare(3, 0.4), 1e-9)
+	assert.Zero(t, KellyShare(2, 0.5), "a fair bet is not worth making")
+	assert.Zero(t, KellyShare(1.9, 0.5), "nor is one the house wins")
+	assert.Zero(t, KellyShare(1, 0.9))
+	assert.Equal(t, 1.0, KellyShare(2, 1))
+}
+
+func TestConfig_SuggestBet(t *testing.T) {
+	config := Config{MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2}
+
+	tests := []struct {
+		name     string
+		balance  Money
+		edge     float64
+		fraction float64
+		amount   Money
+		capped   bool
+	}{
+		{"half kelly", 1000 * Dollar, 0.02, 0.5, 20 * Dollar, false},
+		{"full kelly", 1000 * Dollar, 0.02, 1, 40 * Dollar, false},
+		{"rounds down to the cent", 1234*Dollar + 56, 0.02, 0.5, 24*Dollar + 69, false},
+		{"capped at the maximum bet", 1000 * Dollar, 0.2, 0.5, 100 * Dollar, true},
+		{"below the minimum bet", 30 * Dollar, 0.02, 0.5, 0, false},
+		{"no edge", 1000 * Dollar, 0, 0.5, 0, false},
+		{"negative edge", 1000 * Dollar, -0.1, 0.5, 0, false},
+		{"no balance", 0, 0.02, 0.5, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion, err := config.SuggestBet(tt.balance, tt.edge, tt.fraction)
+			require.NoError(t, err)
+			assert.Equal(t, tt.amount, suggestion.Amount)
+			assert.Equal(t, tt.capped, suggestion.Capped)
+			assert.InDelta(t, 0.5+tt.edge, suggestion.WinProbability, 1e-9)
+		})
+	}
+
+	suggestion, err := config.SuggestBet(1000*Dollar, 0.02, 0.5)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.04, suggestion.ExpectedReturn, 1e-9)
+	assert.InDelta(t, 0.04, suggestion.Kelly, 1e-9)
+	assert.InDelta(t, 0.02, suggestion.Share, 1e-9)
+
+	for _, invalid := range []struct{ edge, fraction float64 }{{0.5, 0.5}, {-0.6, 0.5}, {0.02, 0}, {0.02, 1.5}} {
+		_, err := config.SuggestBet(1000*Dollar, invalid.edge, invalid.fraction)
+		assert.Error(t, err, "edge %v, fraction %v", invalid.edge, invalid.fraction)
+	}
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidMoney is returned for amounts that are not a finite number of
+// whole cents
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// Money is an amount of currency in whole cents. Balances, stakes and
+// payouts are kept in Money so sums never pick up floating point rounding
+// errors; floats only appear at the edges, such as configuration and the
+// JSON wire format, where amounts are written as decimal numbers.
+type Money int64
+
+// Units of Money
+const (
+	Cent   Money = 1
+	Dollar Money = 100
+)
+
+// maxMoney is the largest amount a float64 holds to the cent
+const maxMoney = Money(1 << 53)
+
+// centTolerance is how far from a whole cent, relative to the amount, a
+// float may be and still count as that cent. It absorbs the drift left
+// in amounts stored by float arithmetic without accepting a real fraction
+// of a cent.
+const centTolerance = 1e-9
+
+// NewMoney rounds a float amount to the nearest cent. It is meant for
+// trusted values such as configuration; amounts from players go through
+// MoneyFromFloat or ParseMoney, which reject fractions of a cent.
+func NewMoney(amount float64) Money {
+	return Money(math.Round(amount * float64(Dollar)))
+}
+
+// MoneyFromFloat converts a float amount to Money, failing unless it is
+// finite, in range and a whole number of cents
+func MoneyFromFloat(amount float64) (Money, error) {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidMoney, amount)
+	}
+
+	cents := amount * float64(Dollar)
+	rounded := math.Round(cents)
+	if math.Abs(rounded) > float64(maxMoney) {
+		return 0, fmt.Errorf("%w: %v is out of range", ErrInvalidMoney, amount)
+	}
+	if math.Abs(cents-rounded) > centTolerance*math.Max(1, math.Abs(cents)) {
+		return 0, fmt.Errorf("%w: %v has a fraction of a cent", ErrInvalidMoney, amount)
+	}
+	return Money(rounded), nil
+}
+
+// ParseMoney parses an amount typed by a player, such as "12.50" or
+// "$12.50"
+func ParseMoney(s string) (Money, error) {
+	text := strings.TrimSpace(s)
+	text = strings.Replace(text, "$", "", 1)
+	amount, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a number", ErrInvalidMoney, s)
+	}
+	return MoneyFromFloat(amount)
+}
+
+// Float64 returns the amount in dollars
+func (m Money) Float64() float64 {
+	return float64(m) / float64(Dollar)
+}
+
+// Mul scales the amount by a factor such as a payout ratio, rounding half
+// away from zero to the nearest cent
+func (m Money) Mul(factor float64) Money {
+	return Money(math.Round(float64(m) * factor))
+}
+
+// Abs returns the amount without its sign
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// String formats the amount as a plain decimal, such as "12.50" or "-3.00"
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign = "-"
+	}
+	abs := m.Abs()
+	return fmt.Sprintf("%s%d.%02d", sign, abs/Dollar, abs%Dollar)
+}
+
+// Format formats the amount as currency, such as "$12.50" or "-$3.00"
+func (m Money) Format() string {
+	if m < 0 {
+		return "-$" + m.Abs().String()
+	}
+	return "$" + m.String()
+}
+
+// FormatSigned formats the amount as currency with its sign, such as
+// "+$12.50" or "-$3.00", for gains and losses
+func (m Money) FormatSigned() string {
+	if m < 0 {
+		return m.Format()
+	}
+	return "+" + m.Format()
+}
+
+// MarshalJSON writes the amount as a decimal number of dollars
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(m.Float64(), 'f', -1, 64)), nil
+}
+
+// UnmarshalJSON reads a decimal number of dollars rounded to the nearest
+// cent, so amounts saved by earlier float arithmetic still load. Amounts
+// from players are checked with MoneyFromFloat before they get here.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var amount float64
+	if err := json.Unmarshal(data, &amount); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidMoney, data)
+	}
+	if math.Abs(amount) > maxMoney.Float64() {
+		return fmt.Errorf("%w: %v is out of range", ErrInvalidMoney, amount)
+	}
+	*m = NewMoney(amount)
+	return nil
+}
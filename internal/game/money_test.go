@@ -0,0 +1,98 @@
+package game
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_FromFloat(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   Money
+		err    bool
+	}{
+		{amount: 12.5, want: 1250},
+		{amount: 0.1 + 0.2, want: 30},
+		{amount: -3, want: -300},
+		{amount: 1e9, want: 1e9 * Dollar},
+		{amount: 10.555, err: true},
+		{amount: 0.001, err: true},
+		{amount: math.NaN(), err: true},
+		{amount: math.Inf(1), err: true},
+		{amount: 1e300, err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := MoneyFromFloat(tt.amount)
+		if tt.err {
+			assert.ErrorIs(t, err, ErrInvalidMoney, "amount %v", tt.amount)
+			continue
+		}
+		require.NoError(t, err, "amount %v", tt.amount)
+		assert.Equal(t, tt.want, got, "amount %v", tt.amount)
+	}
+
+	assert.Equal(t, Money(1013), NewMoney(10.125))
+}
+
+func TestMoney_Parse(t *testing.T) {
+	amount, err := ParseMoney(" $12.34 ")
+	require.NoError(t, err)
+	assert.Equal(t, 12*Dollar+34*Cent, amount)
+
+	_, err = ParseMoney("twelve")
+	assert.ErrorIs(t, err, ErrInvalidMoney)
+	_, err = ParseMoney("1.234")
+	assert.ErrorIs(t, err, ErrInvalidMoney)
+}
+
+func TestMoney_ArithmeticHasNoDrift(t *testing.T) {
+	// Ten cents added a thousand times drifts as a float
+	var balance Money
+	var floatBalance float64
+	for i := 0; i < 1000; i++ {
+		balance += 10 * Cent
+		floatBalance += 0.1
+	}
+	assert.Equal(t, 100*Dollar, balance)
+	assert.NotEqual(t, 100.0, floatBalance)
+
+	assert.Equal(t, Money(1508), Money(1005).Mul(1.5))
+	assert.Equal(t, Money(-1508), Money(-1005).Mul(1.5))
+	assert.Equal(t, 33*Cent, Dollar.Mul(1.0/3))
+}
+
+func TestMoney_Format(t *testing.T) {
+	assert.Equal(t, "12.50", (12*Dollar + 50*Cent).String())
+	assert.Equal(t, "-0.05", (-5 * Cent).String())
+	assert.Equal(t, "$1000.00", (1000 * Dollar).Format())
+	assert.Equal(t, "-$3.25", (-325 * Cent).Format())
+	assert.Equal(t, "+$0.00", Money(0).FormatSigned())
+	assert.Equal(t, "-$3.25", (-325 * Cent).FormatSigned())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Amount Money `json:"amount"`
+		Whole  Money `json:"whole"`
+	}{Amount: 1234 * Cent, Whole: 5 * Dollar})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":12.34,"whole":5}`, string(data))
+
+	var decoded struct {
+		Amount Money `json:"amount"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":0.30000000000000004}`), &decoded))
+	assert.Equal(t, 30*Cent, decoded.Amount)
+
+	// Payouts stored before amounts were kept in cents may hold fractions
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":19.5195}`), &decoded))
+	assert.Equal(t, 1952*Cent, decoded.Amount)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":1e300}`), &decoded), ErrInvalidMoney)
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"12"}`), &decoded))
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Limits on the notes a player keeps about others
+const (
+	MaxNoteLength    = 500
+	MaxNoteTags      = 8
+	MaxNoteTagLength = 24
+	MaxNotes         = 500
+)
+
+// Player note errors
+var (
+	ErrNoteNotFound = errors.New("no note about that player")
+	ErrInvalidNote  = errors.New("invalid player note")
+	ErrTooManyNotes = fmt.Errorf("a player keeps notes about at most %d players", MaxNotes)
+)
+
+// NoteTag is a tag offered for notes, shown as its icon next to the tagged
+// player
+type NoteTag struct {
+	Name string
+	Icon string
+}
+
+// NoteTags are the tags offered when tagging a player. Other tags may be
+// typed in and share customTagIcon.
+var NoteTags = []NoteTag{
+	{"friend", "🤝"},
+	{"aggressive", "⚡"},
+	{"cautious", "🐢"},
+	{"high roller", "💎"},
+	{"lucky", "🍀"},
+	{"avoid", "🚫"},
+}
+
+// Icons for a note's typed-in tags and for its text
+const (
+	customTagIcon = "🏷️"
+	noteTextIcon  = "📝"
+)
+
+// PlayerNote is what a player privately noted about another player: tags
+// such as "friend" or "aggressive" and free text. It keeps the other
+// player's name as last seen, to search by.
+type PlayerNote struct {
+	PlayerID  string    `json:"player_id"`
+	Name      string    `json:"name,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Text      string    `json:"text,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NormalizeTag lowercases a tag and collapses its spaces, so "High  Roller"
+// and "high roller" are the same tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// ParseNoteTags splits comma-separated tags, normalizing them and dropping
+// empty and repeated ones
+func ParseNoteTags(text string) []string {
+	var tags []string
+	for _, tag := range strings.Split(text, ",") {
+		tag = NormalizeTag(tag)
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// Empty reports whether the note has neither tags nor text
+func (n PlayerNote) Empty() bool {
+	return len(n.Tags) == 0 && strings.TrimSpace(n.Text) == ""
+}
+
+// Validate checks the note is about a player and its tags and text are
+// within bounds. Tags must be normalized, and are letters, digits, spaces
+// or '-'.
+func (n PlayerNote) Validate() error {
+	if n.PlayerID == "" {
+		return fmt.Errorf("%w: player ID is required", ErrInvalidNote)
+	}
+	if len(n.Tags) > MaxNoteTags {
+		return fmt.Errorf("%w: at most %d tags", ErrInvalidNote, MaxNoteTags)
+	}
+	for i, tag := range n.Tags {
+		if tag == "" || len([]rune(tag)) > MaxNoteTagLength || tag != NormalizeTag(tag) {
+			return fmt.Errorf("%w: tag %q must be 1-%d lowercase characters", ErrInvalidNote, tag, MaxNoteTagLength)
+		}
+		for _, r := range tag {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' {
+				return fmt.Errorf("%w: %q is not allowed in a tag", ErrInvalidNote, r)
+			}
+		}
+		if slices.Contains(n.Tags[:i], tag) {
+			return fmt.Errorf("%w: tag %q is repeated", ErrInvalidNote, tag)
+		}
+	}
+	if len([]rune(n.Text)) > MaxNoteLength {
+		return fmt.Errorf("%w: text must be at most %d characters", ErrInvalidNote, MaxNoteLength)
+	}
+	return nil
+}
+
+// Icons returns the icons the note is shown with: one per offered tag, in
+// the order they are offered, then one for any typed-in tags and one for
+// text, such as "🤝⚡📝"
+func (n PlayerNote) Icons() string {
+	var icons strings.Builder
+	for _, tag := range NoteTags {
+		if slices.Contains(n.Tags, tag.Name) {
+			icons.WriteString(tag.Icon)
+		}
+	}
+	for _, tag := range n.Tags {
+		if !isOfferedTag(tag) {
+			icons.WriteString(customTagIcon)
+			break
+		}
+	}
+	if strings.TrimSpace(n.Text) != "" {
+		icons.WriteString(noteTextIcon)
+	}
+	return icons.String()
+}
+
+// Matches reports whether the query appears in the player's name, a tag or
+// the text, ignoring case. An empty query matches every note.
+func (n PlayerNote) Matches(query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	if strings.Contains(strings.ToLower(n.Name), query) || strings.Contains(strings.ToLower(n.Text), query) {
+		return true
+	}
+	for _, tag := range n.Tags {
+		if strings.Contains(tag, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOfferedTag reports whether the tag is one of NoteTags
+func isOfferedTag(name string) bool {
+	return slices.ContainsFunc(NoteTags, func(tag NoteTag) bool { return tag.Name == name })
+}
@@ -0,0 +1,57 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNoteTags(t *testing.T) {
+	assert.Equal(t, []string{"friend", "high roller", "tilts"}, ParseNoteTags(" Friend, High  Roller,,tilts, friend "))
+	assert.Empty(t, ParseNoteTags(" , "))
+}
+
+func TestPlayerNote_Icons(t *testing.T) {
+	note := PlayerNote{PlayerID: "p2", Tags: []string{"aggressive", "friend"}}
+	require.NoError(t, note.Validate())
+	assert.Equal(t, "🤝⚡", note.Icons(), "in the order tags are offered")
+
+	note.Tags = append(note.Tags, "bluffs", "tilts")
+	note.Text = "Doubles up after a loss"
+	assert.Equal(t, "🤝⚡🏷️📝", note.Icons(), "one icon for all typed-in tags")
+
+	assert.Empty(t, PlayerNote{PlayerID: "p2"}.Icons())
+	assert.True(t, PlayerNote{PlayerID: "p2", Text: "  "}.Empty())
+}
+
+func TestPlayerNote_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		note PlayerNote
+	}{
+		{"no player", PlayerNote{Tags: []string{"friend"}}},
+		{"tag not normalized", PlayerNote{PlayerID: "p2", Tags: []string{"Friend"}}},
+		{"tag with punctuation", PlayerNote{PlayerID: "p2", Tags: []string{"friend!"}}},
+		{"tag too long", PlayerNote{PlayerID: "p2", Tags: []string{strings.Repeat("a", MaxNoteTagLength+1)}}},
+		{"repeated tag", PlayerNote{PlayerID: "p2", Tags: []string{"lucky", "lucky"}}},
+		{"too many tags", PlayerNote{PlayerID: "p2", Tags: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"}}},
+		{"text too long", PlayerNote{PlayerID: "p2", Text: strings.Repeat("é", MaxNoteLength+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.note.Validate(), ErrInvalidNote)
+		})
+	}
+}
+
+func TestPlayerNote_Matches(t *testing.T) {
+	note := PlayerNote{PlayerID: "p2", Name: "Alice", Tags: []string{"high roller"}, Text: "Always bets Tails"}
+
+	for _, query := range []string{"", "ali", "ROLLER", "tails", " bets "} {
+		assert.True(t, note.Matches(query), query)
+	}
+	assert.False(t, note.Matches("heads"))
+	assert.False(t, note.Matches("p2"), "IDs are not searched")
+}
@@ -0,0 +1,116 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+// Notification setting errors
+var (
+	ErrNotifyAddress = errors.New("notifications need an email address or Telegram chat")
+	ErrNotifyTrigger = errors.New("notifications need at least one trigger")
+	ErrNotifyGuest   = errors.New("guests must register an account before setting up notifications")
+)
+
+// MaxEmailLength is the longest email address notifications go to
+const MaxEmailLength = 254
+
+// NotifySettings are where and when a player hears of their play while
+// away from the game. Nothing is sent until the player opts in with
+// Enabled; opting out keeps the addresses and triggers for next time.
+type NotifySettings struct {
+	Enabled bool `json:"enabled"`
+	// Email and TelegramChat are where notifications go, either or both
+	Email        string `json:"email,omitempty"`
+	TelegramChat string `json:"telegram_chat,omitempty"`
+	// WinAbove notifies of a round won by at least this much profit, and
+	// BalanceMilestone of the balance reaching each multiple of it. Zero
+	// turns either off.
+	WinAbove         Money `json:"win_above,omitempty"`
+	BalanceMilestone Money `json:"balance_milestone,omitempty"`
+	// SeasonResults notifies of the player's finish when a season ends
+	SeasonResults bool `json:"season_results,omitempty"`
+	// MilestoneReached is the highest milestone already notified, so each
+	// is only sent once
+	MilestoneReached Money `json:"milestone_reached,omitempty"`
+}
+
+// Validate checks the addresses are well formed and that enabled
+// settings have somewhere to send and something to send about
+func (s NotifySettings) Validate() error {
+	if len(s.Email) > MaxEmailLength {
+		return fmt.Errorf("email addresses are at most %d characters", MaxEmailLength)
+	}
+	if s.Email != "" {
+		address, err := mail.ParseAddress(s.Email)
+		if err != nil || address.Address != s.Email {
+			return fmt.Errorf("invalid email address %q", s.Email)
+		}
+	}
+	if s.TelegramChat != "" && !validTelegramChat(s.TelegramChat) {
+		return fmt.Errorf("invalid Telegram chat %q: use a numeric chat ID or @channel", s.TelegramChat)
+	}
+	if s.WinAbove < 0 || s.BalanceMilestone < 0 {
+		return errors.New("notification amounts cannot be negative")
+	}
+	if !s.Enabled {
+		return nil
+	}
+	if s.Email == "" && s.TelegramChat == "" {
+		return ErrNotifyAddress
+	}
+	if s.WinAbove == 0 && s.BalanceMilestone == 0 && !s.SeasonResults {
+		return ErrNotifyTrigger
+	}
+	return nil
+}
+
+// validTelegramChat reports whether chat is a numeric chat ID, negative
+// for groups, or a public channel's @username
+func validTelegramChat(chat string) bool {
+	if _, err := strconv.ParseInt(chat, 10, 64); err == nil {
+		return true
+	}
+	name, ok := strings.CutPrefix(chat, "@")
+	if !ok || len(name) < 5 || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// WantsWin reports whether a round won with profit should be notified
+func (s *NotifySettings) WantsWin(profit Money) bool {
+	return s != nil && s.Enabled && s.WinAbove > 0 && profit >= s.WinAbove
+}
+
+// ReachMilestone records the balance and reports the milestone it reached
+// for the first time, if any. Only the highest milestone crossed counts,
+// so a big win sends one notification.
+func (s *NotifySettings) ReachMilestone(balance Money) (Money, bool) {
+	if s == nil || s.BalanceMilestone <= 0 {
+		return 0, false
+	}
+	milestone := balance / s.BalanceMilestone * s.BalanceMilestone
+	if milestone <= 0 || milestone <= s.MilestoneReached {
+		return 0, false
+	}
+	s.MilestoneReached = milestone
+	return milestone, s.Enabled
+}
+
+// Clone returns a copy of the settings, or nil for nil
+func (s *NotifySettings) Clone() *NotifySettings {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	return &clone
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifySettings_Validate(t *testing.T) {
+	valid := []NotifySettings{
+		{},
+		{Email: "alice@example.com"},
+		{Enabled: true, TelegramChat: "123456789", WinAbove: 100 * Dollar},
+		{Enabled: true, TelegramChat: "-1001234567890", SeasonResults: true},
+		{Enabled: true, TelegramChat: "@coinflip_news", Email: "a@b.co", BalanceMilestone: 1000 * Dollar},
+	}
+	for _, settings := range valid {
+		assert.NoError(t, settings.Validate(), "%+v", settings)
+	}
+
+	invalid := []NotifySettings{
+		{Email: "not an email"},
+		{Email: "Alice <alice@example.com>"},
+		{TelegramChat: "@abc"},
+		{TelegramChat: "chat"},
+		{WinAbove: -Dollar},
+	}
+	for _, settings := range invalid {
+		assert.Error(t, settings.Validate(), "%+v", settings)
+	}
+
+	assert.ErrorIs(t, NotifySettings{Enabled: true, WinAbove: Dollar}.Validate(), ErrNotifyAddress)
+	assert.ErrorIs(t, NotifySettings{Enabled: true, Email: "a@b.co"}.Validate(), ErrNotifyTrigger)
+}
+
+func TestNotifySettings_Triggers(t *testing.T) {
+	settings := &NotifySettings{Enabled: true, WinAbove: 50 * Dollar, BalanceMilestone: 1000 * Dollar}
+	assert.True(t, settings.WantsWin(50*Dollar))
+	assert.False(t, settings.WantsWin(49*Dollar))
+
+	_, reached := settings.ReachMilestone(999 * Dollar)
+	assert.False(t, reached)
+	milestone, reached := settings.ReachMilestone(3500 * Dollar)
+	assert.True(t, reached)
+	assert.Equal(t, 3000*Dollar, milestone, "only the highest milestone crossed is sent")
+	_, reached = settings.ReachMilestone(3200 * Dollar)
+	assert.False(t, reached, "each milestone is sent once")
+
+	// Opted out players still have their milestones recorded
+	settings.Enabled = false
+	assert.False(t, settings.WantsWin(100*Dollar))
+	_, reached = settings.ReachMilestone(4000 * Dollar)
+	assert.False(t, reached)
+	assert.Equal(t, 4000*Dollar, settings.MilestoneReached)
+
+	var none *NotifySettings
+	assert.False(t, none.WantsWin(100*Dollar))
+	_, reached = none.ReachMilestone(4000 * Dollar)
+	assert.False(t, reached)
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// PoolStake is one stake in a parimutuel pool
+type PoolStake struct {
+	ID     string
+	Side   Side
+	Amount Money
+}
+
+// PoolSettlement is how a parimutuel pool was split once the coin landed.
+// Payouts holds what each stake on the winning side is paid, by stake ID;
+// stakes on the losing side pay nothing.
+type PoolSettlement struct {
+	Total   Money
+	Heads   Money
+	Tails   Money
+	Rake    Money
+	Paid    Money
+	Payouts map[string]Money
+}
+
+// Contested reports whether both sides of the pool hold stakes, so there is
+// money to win from the other side
+func (p *PoolSettlement) Contested() bool {
+	return p.Heads > 0 && p.Tails > 0
+}
+
+// Staked returns the stakes on one side of the pool
+func (p *PoolSettlement) Staked(side Side) Money {
+	if side == Heads {
+		return p.Heads
+	}
+	return p.Tails
+}
+
+// ValidateRake checks the rake is a share of the pool below all of it
+func ValidateRake(rake float64) error {
+	if rake < 0 || rake >= 1 {
+		return fmt.Errorf("rake must be at least 0 and below 1, got %.2f", rake)
+	}
+	return nil
+}
+
+// SettlePool splits a parimutuel pool: every stake goes in, the house takes
+// rake of it, and the stakes on the winning side share the rest in
+// proportion to their amounts. Payouts round down to the cent, and the
+// cents left over go to the rake. When nobody backed the winning side the
+// pool pays nothing; callers refund pools that are not Contested instead.
+func SettlePool(stakes []PoolStake, result Side, rake float64) *PoolSettlement {
+	settlement := &PoolSettlement{Payouts: make(map[string]Money)}
+	var winning Money
+	for _, stake := range stakes {
+		settlement.Total += stake.Amount
+		switch stake.Side {
+		case Heads:
+			settlement.Heads += stake.Amount
+		case Tails:
+			settlement.Tails += stake.Amount
+		}
+		if stake.Side == result {
+			winning += stake.Amount
+		}
+	}
+	if winning == 0 {
+		settlement.Rake = settlement.Total
+		return settlement
+	}
+
+	shared := settlement.Total - settlement.Total.Mul(rake)
+	for _, stake := range stakes {
+		if stake.Side != result {
+			continue
+		}
+		payout := proportion(shared, stake.Amount, winning)
+		settlement.Payouts[stake.ID] = payout
+		settlement.Paid += payout
+	}
+	settlement.Rake = settlement.Total - settlement.Paid
+	return settlement
+}
+
+// proportion returns amount * part / whole rounded down to the cent,
+// without overflowing on large pools
+func proportion(amount, part, whole Money) Money {
+	product := new(big.Int).Mul(big.NewInt(int64(amount)), big.NewInt(int64(part)))
+	return Money(product.Quo(product, big.NewInt(int64(whole))).Int64())
+}
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettlePool_SplitsByStake(t *testing.T) {
+	stakes := []PoolStake{
+		{ID: "a", Side: Heads, Amount: 30 * Dollar},
+		{ID: "b", Side: Heads, Amount: 10 * Dollar},
+		{ID: "c", Side: Tails, Amount: 60 * Dollar},
+	}
+
+	settlement := SettlePool(stakes, Heads, 0.05)
+	assert.True(t, settlement.Contested())
+	assert.Equal(t, 100*Dollar, settlement.Total)
+	assert.Equal(t, 40*Dollar, settlement.Heads)
+	assert.Equal(t, 60*Dollar, settlement.Tails)
+	// $95 after the rake, split 3:1
+	assert.Equal(t, map[string]Money{"a": 7125 * Cent, "b": 2375 * Cent}, settlement.Payouts)
+	assert.Equal(t, 95*Dollar, settlement.Paid)
+	assert.Equal(t, 5*Dollar, settlement.Rake)
+}
+
+func TestSettlePool_RoundsDownToTheRake(t *testing.T) {
+	stakes := []PoolStake{
+		{ID: "a", Side: Tails, Amount: 1 * Dollar},
+		{ID: "b", Side: Tails, Amount: 1 * Dollar},
+		{ID: "c", Side: Tails, Amount: 1 * Dollar},
+		{ID: "d", Side: Heads, Amount: 1 * Dollar},
+	}
+
+	settlement := SettlePool(stakes, Tails, 0)
+	for _, id := range []string{"a", "b", "c"} {
+		assert.Equal(t, 133*Cent, settlement.Payouts[id])
+	}
+	assert.Equal(t, 399*Cent, settlement.Paid)
+	assert.Equal(t, Cent, settlement.Rake, "the cent left over is kept")
+	assert.Equal(t, settlement.Total, settlement.Paid+settlement.Rake)
+}
+
+func TestSettlePool_Uncontested(t *testing.T) {
+	stakes := []PoolStake{{ID: "a", Side: Heads, Amount: 20 * Dollar}}
+
+	settlement := SettlePool(stakes, Tails, 0.05)
+	assert.False(t, settlement.Contested())
+	assert.Empty(t, settlement.Payouts)
+	assert.Equal(t, 20*Dollar, settlement.Rake)
+
+	assert.NoError(t, ValidateRake(0))
+	assert.Error(t, ValidateRake(1))
+	assert.Error(t, ValidateRake(-0.1))
+}
@@ -0,0 +1,38 @@
+package game
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// ErrPracticeInsured is returned when insuring a practice bet, which has no
+// stake to cover
+var ErrPracticeInsured = errors.New("practice bets cannot be insured")
+
+// practiceBet validates a practice bet like a real one, but takes nothing
+// from the player's balance, which may even be empty
+func (e *Engine) practiceBet(playerID string, amount Money, choice Side) (*Bet, error) {
+	if !choice.IsValid() {
+		return nil, ErrInvalidChoice
+	}
+	if amount < e.config.MinBet || amount > e.config.MaxBet {
+		return nil, ErrInvalidBetAmount
+	}
+
+	bet := &Bet{
+		ID:        e.generateBetID(),
+		Amount:    amount,
+		Choice:    choice,
+		Timestamp: e.clock.Now(),
+		Practice:  true,
+	}
+
+	e.logger.Info("Practice bet placed",
+		zap.String("player_id", playerID),
+		zap.String("bet_id", bet.ID),
+		zap.Float64("amount", amount.Float64()),
+		zap.String("choice", choice.String()),
+	)
+	return bet, nil
+}
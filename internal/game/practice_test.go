@@ -0,0 +1,83 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_PlacePracticeBet(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("win goes to the practice ledger", func(t *testing.T) {
+		engine, repo := newSessionEngine(t, Heads)
+		session := engine.NewSession("alice")
+
+		bet, err := session.PlacePracticeBet(ctx, 20*Dollar, Heads)
+		require.NoError(t, err)
+		assert.True(t, bet.Practice)
+
+		player, err := session.Player(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1000*Dollar, player.Balance)
+
+		result, err := session.FlipCoin(ctx)
+		require.NoError(t, err)
+		assert.True(t, result.Won)
+		assert.Equal(t, 40*Dollar, result.Payout)
+
+		player, err = session.Player(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1000*Dollar, player.Balance)
+		assert.Zero(t, player.Stats.GamesPlayed)
+		assert.Equal(t, 1, player.Practice.GamesWon)
+		assert.Equal(t, 20*Dollar, player.Practice.NetProfit)
+
+		// The result is kept for history but not counted in lifetime stats
+		results, err := repo.GetResults(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Zero(t, SummarizeResults(results).GamesPlayed)
+	})
+
+	t.Run("loss leaves the balance alone", func(t *testing.T) {
+		engine, _ := newSessionEngine(t, Tails)
+		session := engine.NewSession("alice")
+
+		_, err := session.PlacePracticeBet(ctx, 20*Dollar, Heads)
+		require.NoError(t, err)
+		_, err = session.FlipCoin(ctx)
+		require.NoError(t, err)
+
+		player, err := session.Player(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1000*Dollar, player.Balance)
+		assert.Equal(t, -20*Dollar, player.Practice.NetProfit)
+	})
+
+	t.Run("needs no balance", func(t *testing.T) {
+		engine, repo := newSessionEngine(t, Heads)
+		require.NoError(t, repo.SavePlayer(ctx, &Player{ID: "broke"}))
+		session := engine.NewSession("broke")
+
+		_, err := session.PlacePracticeBet(ctx, 50*Dollar, Heads)
+		require.NoError(t, err)
+		require.NoError(t, session.CancelBet(ctx))
+
+		player, err := session.Player(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, player.Balance)
+	})
+
+	t.Run("validates like a real bet", func(t *testing.T) {
+		engine, _ := newSessionEngine(t, Heads)
+		session := engine.NewSession("alice")
+
+		_, err := session.PlacePracticeBet(ctx, 500*Dollar, Heads)
+		assert.ErrorIs(t, err, ErrInvalidBetAmount)
+		_, err = session.PlacePracticeBet(ctx, 10*Dollar, Side("edge"))
+		assert.ErrorIs(t, err, ErrInvalidChoice)
+	})
+}
@@ -0,0 +1,103 @@
+package game
+
+import (
+	"fmt"
+	"math"
+)
+
+// Duel ratings follow the Elo system: every player starts at
+// DefaultRating, and a duel moves both players' ratings by up to RatingK
+// points, more when the lower rated player wins
+const (
+	DefaultRating = 1500
+	RatingK       = 32
+)
+
+// RatingBucketSize is how wide a band of ratings duel matchmaking pairs
+// players within
+const RatingBucketSize = 200
+
+// Outcomes of a duel, as the score of the player it is told from
+const (
+	DuelLoss = 0.0
+	DuelDraw = 0.5
+	DuelWin  = 1.0
+)
+
+// DuelRating is a player's rating in head-to-head duels and the record it
+// was earned with
+type DuelRating struct {
+	Rating int `json:"rating"`
+	// Peak is the highest rating the player has reached
+	Peak   int `json:"peak"`
+	Duels  int `json:"duels"`
+	Wins   int `json:"wins"`
+	Draws  int `json:"draws"`
+	Losses int `json:"losses"`
+}
+
+// NewDuelRating returns the rating of a player yet to duel
+func NewDuelRating() DuelRating {
+	return DuelRating{Rating: DefaultRating, Peak: DefaultRating}
+}
+
+// Bucket returns the lowest rating of the matchmaking band the rating
+// falls in
+func (r DuelRating) Bucket() int {
+	return RatingBucket(r.Rating)
+}
+
+// Clone returns a copy of the rating, or nil for nil
+func (r *DuelRating) Clone() *DuelRating {
+	if r == nil {
+		return nil
+	}
+	clone := *r
+	return &clone
+}
+
+// ExpectedScore returns the score a player rated rating is expected to
+// take from a duel against one rated opponent, between 0 and 1
+func ExpectedScore(rating, opponent int) float64 {
+	return 1 / (1 + math.Pow(10, float64(opponent-rating)/400))
+}
+
+// RateDuel returns both players' ratings after a duel in which a scored
+// score, one of DuelWin, DuelDraw and DuelLoss, against b. The points a
+// gains are the points b loses.
+func RateDuel(a, b DuelRating, score float64) (DuelRating, DuelRating) {
+	change := int(math.Round(RatingK * (score - ExpectedScore(a.Rating, b.Rating))))
+	return a.record(change, score), b.record(-change, 1-score)
+}
+
+// record applies a duel's rating change and outcome to the record
+func (r DuelRating) record(change int, score float64) DuelRating {
+	r.Rating += change
+	r.Peak = max(r.Peak, r.Rating)
+	r.Duels++
+	switch score {
+	case DuelWin:
+		r.Wins++
+	case DuelLoss:
+		r.Losses++
+	default:
+		r.Draws++
+	}
+	return r
+}
+
+// RatingBucket returns the lowest rating of the matchmaking band rating
+// falls in, such as 1400 for 1400 to 1599
+func RatingBucket(rating int) int {
+	bucket := rating / RatingBucketSize * RatingBucketSize
+	if rating < 0 && rating%RatingBucketSize != 0 {
+		bucket -= RatingBucketSize
+	}
+	return bucket
+}
+
+// RatingBucketName describes the band starting at bucket, such as
+// "1400-1599"
+func RatingBucketName(bucket int) string {
+	return fmt.Sprintf("%d-%d", bucket, bucket+RatingBucketSize-1)
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpectedScore(t *testing.T) {
+	assert.Equal(t, 0.5, ExpectedScore(1500, 1500))
+	assert.InDelta(t, 0.909, ExpectedScore(1900, 1500), 0.001, "400 points ahead wins ten duels to one")
+	assert.InDelta(t, 1, ExpectedScore(1500, 1700)+ExpectedScore(1700, 1500), 1e-9)
+}
+
+func TestRateDuel(t *testing.T) {
+	// Evenly rated players trade half of K
+	winner, loser := RateDuel(NewDuelRating(), NewDuelRating(), DuelWin)
+	assert.Equal(t, DuelRating{Rating: 1516, Peak: 1516, Duels: 1, Wins: 1}, winner)
+	assert.Equal(t, DuelRating{Rating: 1484, Peak: 1500, Duels: 1, Losses: 1}, loser)
+
+	// An upset moves the ratings further than the favourite winning
+	favourite := DuelRating{Rating: 1700, Peak: 1700}
+	underdog := DuelRating{Rating: 1500, Peak: 1600}
+	upset, _ := RateDuel(underdog, favourite, DuelWin)
+	expected, _ := RateDuel(favourite, underdog, DuelWin)
+	assert.Equal(t, 1524, upset.Rating)
+	assert.Equal(t, 1600, upset.Peak, "the peak is kept when the rating stays under it")
+	assert.Equal(t, 1708, expected.Rating)
+
+	// A draw takes points from the favourite
+	drawn, held := RateDuel(underdog, favourite, DuelDraw)
+	assert.Equal(t, 1508, drawn.Rating)
+	assert.Equal(t, 1692, held.Rating)
+	assert.Equal(t, 1, drawn.Draws)
+	assert.Equal(t, 1, held.Draws)
+}
+
+func TestRatingBucket(t *testing.T) {
+	assert.Equal(t, 1400, RatingBucket(1500))
+	assert.Equal(t, 1400, RatingBucket(1599))
+	assert.Equal(t, 1600, RatingBucket(1600))
+	assert.Equal(t, -200, RatingBucket(-1))
+	assert.Equal(t, "1400-1599", RatingBucketName(NewDuelRating().Bucket()))
+}
+
+func TestPlayer_Rating(t *testing.T) {
+	player := &Player{ID: "p1"}
+	assert.Equal(t, NewDuelRating(), player.Rating())
+
+	player.Duel = &DuelRating{Rating: 1620, Peak: 1650, Duels: 9}
+	assert.Equal(t, 1620, player.Rating().Rating)
+}
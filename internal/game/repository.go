@@ -0,0 +1,140 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrRepositoryTimeout is returned when a repository operation runs past
+// the engine's RepositoryTimeout or the caller's deadline. It wraps
+// context.DeadlineExceeded, so either can be checked for.
+var ErrRepositoryTimeout = errors.New("repository operation timed out")
+
+// IsContextError reports whether err came from a cancelled or timed out
+// context rather than from the repository itself
+func IsContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// repoCall runs one repository operation bounded by the engine's
+// RepositoryTimeout, reporting one that ran out of time as
+// ErrRepositoryTimeout
+func repoCall[T any](ctx context.Context, e *Engine, op func(context.Context) (T, error)) (T, error) {
+	if e.config.RepositoryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.config.RepositoryTimeout)
+		defer cancel()
+	}
+
+	value, err := op(ctx)
+	if errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrRepositoryTimeout) {
+		err = fmt.Errorf("%w: %w", ErrRepositoryTimeout, err)
+	}
+	return value, err
+}
+
+// savePlayer saves the player within the repository timeout
+func (e *Engine) savePlayer(ctx context.Context, player *Player) error {
+	_, err := repoCall(ctx, e, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, e.repo.SavePlayer(ctx, player)
+	})
+	return err
+}
+
+// loadPlayer loads the player within the repository timeout
+func (e *Engine) loadPlayer(ctx context.Context, playerID string) (*Player, error) {
+	return repoCall(ctx, e, func(ctx context.Context) (*Player, error) {
+		return e.repo.GetPlayer(ctx, playerID)
+	})
+}
+
+// loadResults loads the most recent results within the repository timeout
+func (e *Engine) loadResults(ctx context.Context, limit int) ([]*Result, error) {
+	return repoCall(ctx, e, func(ctx context.Context) ([]*Result, error) {
+		return e.repo.GetResults(ctx, limit)
+	})
+}
+
+// Transactor is implemented by repositories that can apply several writes
+// atomically
+type Transactor interface {
+	// WithTransaction runs fn against tx, a view of the repository whose
+	// writes all take effect when fn returns nil and none of them when it
+	// returns an error. Reads through tx see its own writes. fn must use
+	// only tx, never the repository itself.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context, tx Repository) error) error
+}
+
+// WithTransaction runs fn so the writes it makes through tx take effect
+// together, or not at all when fn returns an error. Repositories that are
+// a Transactor provide this themselves. For others the writes go straight
+// through, and players fn saved are put back as they were if it fails; a
+// result it saved cannot be taken back, so save results last. The whole
+// transaction is bounded by RepositoryTimeout.
+func (e *Engine) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx Repository) error) error {
+	_, err := repoCall(ctx, e, func(ctx context.Context) (struct{}, error) {
+		if transactor, ok := e.repo.(Transactor); ok {
+			return struct{}{}, transactor.WithTransaction(ctx, fn)
+		}
+		return struct{}{}, compensate(ctx, e.repo, fn)
+	})
+	return err
+}
+
+// undoRepository writes through to a repository, remembering each
+// player's state before its first write so the writes can be undone
+type undoRepository struct {
+	Repository
+	// originals holds players as they were; nil for players that were new
+	originals map[string]*Player
+	order     []string
+}
+
+// SavePlayer remembers the player as stored before saving over them
+func (r *undoRepository) SavePlayer(ctx context.Context, player *Player) error {
+	if player == nil {
+		return r.Repository.SavePlayer(ctx, player)
+	}
+	if _, seen := r.originals[player.ID]; seen {
+		return r.Repository.SavePlayer(ctx, player)
+	}
+
+	original, err := r.Repository.GetPlayer(ctx, player.ID)
+	if IsContextError(err) {
+		return err
+	}
+	if err == nil {
+		copied := *original
+		original = &copied
+	}
+	if err := r.Repository.SavePlayer(ctx, player); err != nil {
+		return err
+	}
+	r.originals[player.ID] = original
+	r.order = append(r.order, player.ID)
+	return nil
+}
+
+// compensate runs fn with writes going straight to repo, and saves the
+// players fn changed back as they were when it fails
+func compensate(ctx context.Context, repo Repository, fn func(ctx context.Context, tx Repository) error) error {
+	tx := &undoRepository{Repository: repo, originals: make(map[string]*Player)}
+	err := fn(ctx, tx)
+	if err == nil {
+		return nil
+	}
+
+	// Undo even when fn failed because ctx ran out
+	undoCtx := context.WithoutCancel(ctx)
+	for i := len(tx.order) - 1; i >= 0; i-- {
+		original := tx.originals[tx.order[i]]
+		if original == nil {
+			continue
+		}
+		if undoErr := repo.SavePlayer(undoCtx, original); undoErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to roll back player %s: %w", original.ID, undoErr))
+		}
+	}
+	return err
+}
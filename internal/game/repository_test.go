@@ -0,0 +1,154 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// stalledRepository never finishes looking players up, only giving up
+// when the context does
+type stalledRepository struct {
+	*mapRepository
+}
+
+func (r stalledRepository) GetPlayer(ctx context.Context, playerID string) (*Player, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestEngine_RepositoryTimeout(t *testing.T) {
+	config := Config{
+		StartingBalance:   1000 * Dollar,
+		MinBet:            Dollar,
+		MaxBet:            100 * Dollar,
+		PayoutRatio:       2.0,
+		RepositoryTimeout: 20 * time.Millisecond,
+	}
+	repo := stalledRepository{newMapRepository()}
+	engine := NewEngine(config, repo, fixedGenerator{side: Heads}, zaptest.NewLogger(t))
+
+	_, err := engine.NewSession("alice").PlaceBet(context.Background(), 10*Dollar, Heads)
+	assert.ErrorIs(t, err, ErrRepositoryTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, repo.players, "a lookup that timed out does not create the player")
+
+	// A caller giving up is reported as such, not as a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = engine.GetPlayer(ctx, "alice")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrRepositoryTimeout)
+	assert.True(t, IsContextError(err))
+	assert.Empty(t, repo.players)
+}
+
+func TestEngine_RepositoryTimeoutDisabled(t *testing.T) {
+	engine, repo := newSessionEngine(t, Heads)
+	require.Zero(t, engine.GetConfig().RepositoryTimeout)
+
+	player, err := engine.GetPlayer(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1000*Dollar, player.Balance)
+	assert.Contains(t, repo.players, "alice")
+}
+
+// failingResultsRepository saves players but never results
+type failingResultsRepository struct {
+	*mapRepository
+}
+
+func (r failingResultsRepository) SaveResult(ctx context.Context, result *Result) error {
+	return errors.New("disk full")
+}
+
+func TestEngine_SettlementRollsBack(t *testing.T) {
+	config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
+	repo := failingResultsRepository{newMapRepository()}
+	engine := NewEngine(config, repo, fixedGenerator{side: Heads}, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	session := engine.NewSession("alice")
+	_, err := session.PlaceBet(ctx, 10*Dollar, Heads)
+	require.NoError(t, err)
+
+	_, err = session.FlipCoin(ctx)
+	assert.ErrorContains(t, err, "failed to save result")
+
+	// Without the result, the payout and stats are not kept either
+	player, err := engine.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 990*Dollar, player.Balance)
+	assert.Zero(t, player.Stats.GamesPlayed)
+	assert.Empty(t, player.ChainHead)
+	assert.Empty(t, repo.results)
+}
+
+// failingPlayersRepository stops saving players once failing is set
+type failingPlayersRepository struct {
+	*mapRepository
+	failing bool
+}
+
+func (r *failingPlayersRepository) SavePlayer(ctx context.Context, player *Player) error {
+	if r.failing {
+		return errors.New("disk full")
+	}
+	return r.mapRepository.SavePlayer(ctx, player)
+}
+
+func TestEngine_PlacementRollsBack(t *testing.T) {
+	config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
+	repo := &failingPlayersRepository{mapRepository: newMapRepository()}
+	engine := NewEngine(config, repo, fixedGenerator{side: Heads}, zaptest.NewLogger(t))
+	ctx := context.Background()
+	_, err := engine.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+
+	// The balance was checked, but taking the stake fails
+	repo.failing = true
+	session := engine.NewSession("alice")
+	_, err = session.PlaceBet(ctx, 10*Dollar, Heads)
+	assert.ErrorContains(t, err, "failed to update player balance")
+	assert.Nil(t, session.CurrentBet())
+	assert.Equal(t, 1000*Dollar, repo.players["alice"].Balance)
+	assert.Empty(t, repo.players["alice"].OpenBets)
+
+	repo.failing = false
+	bet, err := session.PlaceBet(ctx, 10*Dollar, Heads)
+	require.NoError(t, err)
+	assert.Equal(t, 990*Dollar, repo.players["alice"].Balance)
+	assert.Equal(t, []*Bet{bet}, repo.players["alice"].OpenBets)
+}
+
+func TestEngine_WithTransaction(t *testing.T) {
+	engine, repo := newSessionEngine(t, Heads)
+	ctx := context.Background()
+	_, err := engine.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+
+	failed := errors.New("failed")
+	err = engine.WithTransaction(ctx, func(ctx context.Context, tx Repository) error {
+		player, err := tx.GetPlayer(ctx, "alice")
+		require.NoError(t, err)
+		player.Balance = 0
+		require.NoError(t, tx.SavePlayer(ctx, player))
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	assert.Equal(t, 1000*Dollar, repo.players["alice"].Balance)
+
+	err = engine.WithTransaction(ctx, func(ctx context.Context, tx Repository) error {
+		player, err := tx.GetPlayer(ctx, "alice")
+		require.NoError(t, err)
+		player.Balance = 5 * Dollar
+		return tx.SavePlayer(ctx, player)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5*Dollar, repo.players["alice"].Balance)
+}
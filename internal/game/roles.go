@@ -0,0 +1,84 @@
+package game
+
+import (
+	"errors"
+	"strings"
+)
+
+// Role is what a player may do on a multiplayer server beyond playing.
+// Servers decide what each role allows; the role itself is kept on the
+// player's record, so it follows the account.
+type Role string
+
+// Roles
+const (
+	RoleAdmin     Role = "admin"
+	RoleModerator Role = "moderator"
+	RolePlayer    Role = "player"
+	RoleBot       Role = "bot"
+)
+
+// Roles lists every role, most trusted first
+var Roles = []Role{RoleAdmin, RoleModerator, RolePlayer, RoleBot}
+
+// Role errors
+var (
+	ErrInvalidRole = errors.New("role must be admin, moderator, player or bot")
+	ErrRoleGuest   = errors.New("guests must register an account before they can be given a role")
+)
+
+// ParseRole reads a role name, ignoring case and surrounding space
+func ParseRole(s string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(s)))
+	if !role.Valid() {
+		return "", ErrInvalidRole
+	}
+	return role, nil
+}
+
+// Valid reports whether r is one of the roles
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleModerator, RolePlayer, RoleBot:
+		return true
+	}
+	return false
+}
+
+// Outranks reports whether r is more trusted than other
+func (r Role) Outranks(other Role) bool {
+	rank := func(role Role) int {
+		for i, candidate := range Roles {
+			if candidate == role {
+				return len(Roles) - i
+			}
+		}
+		return 0
+	}
+	return rank(r) > rank(other)
+}
+
+// EffectiveRole returns the player's role; a player never given one is a
+// player
+func (p *Player) EffectiveRole() Role {
+	if p.Role == "" {
+		return RolePlayer
+	}
+	return p.Role
+}
+
+// SetRole gives the player a role. Guest IDs can be claimed by anyone, so
+// only registered accounts may hold a role other than player.
+func (p *Player) SetRole(role Role) error {
+	if !role.Valid() {
+		return ErrInvalidRole
+	}
+	if role != RolePlayer && IsGuest(p.ID) {
+		return ErrRoleGuest
+	}
+	if role == RolePlayer {
+		role = ""
+	}
+	p.Role = role
+	return nil
+}
@@ -0,0 +1,44 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRole(t *testing.T) {
+	role, err := ParseRole(" Moderator ")
+	require.NoError(t, err)
+	assert.Equal(t, RoleModerator, role)
+
+	for _, name := range []string{"", "owner", "admins"} {
+		_, err := ParseRole(name)
+		assert.ErrorIs(t, err, ErrInvalidRole, name)
+	}
+}
+
+func TestRole_Outranks(t *testing.T) {
+	assert.True(t, RoleAdmin.Outranks(RoleModerator))
+	assert.True(t, RoleModerator.Outranks(RolePlayer))
+	assert.True(t, RolePlayer.Outranks(RoleBot))
+	assert.False(t, RoleModerator.Outranks(RoleModerator))
+	assert.False(t, RoleBot.Outranks(RoleAdmin))
+}
+
+func TestPlayer_SetRole(t *testing.T) {
+	player := &Player{ID: "alice"}
+	assert.Equal(t, RolePlayer, player.EffectiveRole())
+
+	require.NoError(t, player.SetRole(RoleModerator))
+	assert.Equal(t, RoleModerator, player.EffectiveRole())
+	assert.ErrorIs(t, player.SetRole("owner"), ErrInvalidRole)
+
+	// Back to player clears the role from the record
+	require.NoError(t, player.SetRole(RolePlayer))
+	assert.Empty(t, player.Role)
+
+	guest := &Player{ID: GuestIDPrefix + "123"}
+	assert.ErrorIs(t, guest.SetRole(RoleAdmin), ErrRoleGuest)
+	assert.NoError(t, guest.SetRole(RolePlayer))
+}
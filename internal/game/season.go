@@ -0,0 +1,175 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SeasonLength is how long a multiplayer season runs. Seasons start each
+// Monday at midnight UTC.
+const SeasonLength = 7 * 24 * time.Hour
+
+// SeasonBadgeRanks is how many of a season's leaders earn a badge
+const SeasonBadgeRanks = 3
+
+// SeasonStart returns when the season containing t began
+func SeasonStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	sinceMonday := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -sinceMonday)
+}
+
+// SeasonID names the season containing t by its ISO week, such as
+// "2026-W42"
+func SeasonID(t time.Time) string {
+	year, week := SeasonStart(t).ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// SeasonStanding is a player's place in a season. Players are ranked by
+// their longest run of winning rounds, then by rounds won, then by net
+// winnings.
+type SeasonStanding struct {
+	Rank       int    `json:"rank"`
+	PlayerID   string `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	// BestStreak is the longest run of winning rounds this season, and
+	// Streak the run still going
+	BestStreak int   `json:"best_streak"`
+	Streak     int   `json:"streak"`
+	Rounds     int   `json:"rounds"`
+	Wins       int   `json:"wins"`
+	Net        Money `json:"net"`
+}
+
+// Season is a week of competition and its final or running standings
+type Season struct {
+	ID        string           `json:"id"`
+	Start     time.Time        `json:"start"`
+	End       time.Time        `json:"end"`
+	Standings []SeasonStanding `json:"standings"`
+}
+
+// Standing returns a player's place in the season
+func (s *Season) Standing(playerID string) (SeasonStanding, bool) {
+	for _, standing := range s.Standings {
+		if standing.PlayerID == playerID {
+			return standing, true
+		}
+	}
+	return SeasonStanding{}, false
+}
+
+// Badges returns the badges the season's leaders earn: one for each of the
+// top SeasonBadgeRanks players who won at least one round
+func (s *Season) Badges() map[string]SeasonBadge {
+	badges := make(map[string]SeasonBadge)
+	for _, standing := range s.Standings {
+		if standing.Rank > SeasonBadgeRanks || standing.Wins == 0 {
+			break
+		}
+		badges[standing.PlayerID] = SeasonBadge{Season: s.ID, Rank: standing.Rank, BestStreak: standing.BestStreak}
+	}
+	return badges
+}
+
+// SeasonBadge records a top finish in a season on the player's record
+type SeasonBadge struct {
+	Season     string `json:"season"`
+	Rank       int    `json:"rank"`
+	BestStreak int    `json:"best_streak"`
+}
+
+// Name returns the badge's medal, by the rank it was earned for
+func (b SeasonBadge) Name() string {
+	switch b.Rank {
+	case 1:
+		return "Gold"
+	case 2:
+		return "Silver"
+	case 3:
+		return "Bronze"
+	}
+	return fmt.Sprintf("Top %d", b.Rank)
+}
+
+// String describes the badge, such as "Gold 2026-W42"
+func (b SeasonBadge) String() string {
+	return b.Name() + " " + b.Season
+}
+
+// SeasonBoard keeps the running standings of the current season. It is
+// not safe for concurrent use.
+type SeasonBoard struct {
+	id        string
+	start     time.Time
+	standings map[string]*SeasonStanding
+}
+
+// NewSeasonBoard returns an empty board for the season containing now
+func NewSeasonBoard(now time.Time) *SeasonBoard {
+	return &SeasonBoard{
+		id:        SeasonID(now),
+		start:     SeasonStart(now),
+		standings: make(map[string]*SeasonStanding),
+	}
+}
+
+// ID returns the season's name
+func (b *SeasonBoard) ID() string { return b.id }
+
+// End returns when the season ends and the next begins
+func (b *SeasonBoard) End() time.Time { return b.start.Add(SeasonLength) }
+
+// Ended reports whether the season is over at now
+func (b *SeasonBoard) Ended(now time.Time) bool { return !now.Before(b.End()) }
+
+// Record counts one round a player bet in, won when it paid out more than
+// was wagered
+func (b *SeasonBoard) Record(playerID, playerName string, won bool, net Money) {
+	standing, exists := b.standings[playerID]
+	if !exists {
+		standing = &SeasonStanding{PlayerID: playerID}
+		b.standings[playerID] = standing
+	}
+	if playerName != "" {
+		standing.PlayerName = playerName
+	}
+
+	standing.Rounds++
+	standing.Net += net
+	if won {
+		standing.Wins++
+		standing.Streak++
+		standing.BestStreak = max(standing.BestStreak, standing.Streak)
+	} else {
+		standing.Streak = 0
+	}
+}
+
+// Season returns the season so far, every player ranked
+func (b *SeasonBoard) Season() *Season {
+	standings := make([]SeasonStanding, 0, len(b.standings))
+	for _, standing := range b.standings {
+		standings = append(standings, *standing)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, c := standings[i], standings[j]
+		switch {
+		case a.BestStreak != c.BestStreak:
+			return a.BestStreak > c.BestStreak
+		case a.Wins != c.Wins:
+			return a.Wins > c.Wins
+		case a.Net != c.Net:
+			return a.Net > c.Net
+		}
+		return a.PlayerID < c.PlayerID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+
+	return &Season{ID: b.id, Start: b.start, End: b.End(), Standings: standings}
+}
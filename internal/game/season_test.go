@@ -0,0 +1,78 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeasonStart(t *testing.T) {
+	// Seasons run Monday to Monday, UTC
+	sunday := time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), SeasonStart(sunday))
+	assert.Equal(t, "2026-W42", SeasonID(sunday))
+
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, monday, SeasonStart(monday))
+	assert.Equal(t, "2026-W43", SeasonID(monday))
+
+	// The first days of January can belong to the previous year's last week
+	assert.Equal(t, "2026-W53", SeasonID(time.Date(2027, 1, 2, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestSeasonBoard_RanksByStreak(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	board := NewSeasonBoard(now)
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), board.End())
+	assert.False(t, board.Ended(now))
+	assert.True(t, board.Ended(board.End()))
+
+	// Alice wins three in a row, then loses; Bob wins more rounds but never
+	// more than two in a row; Carol only loses
+	for _, won := range []bool{true, true, true, false} {
+		board.Record("alice", "Alice", won, 0)
+	}
+	for _, won := range []bool{true, true, false, true, true, false, true} {
+		board.Record("bob", "Bob", won, Dollar)
+	}
+	board.Record("carol", "Carol", false, -5*Dollar)
+	board.Record("dave", "Dave", true, 10*Dollar)
+
+	season := board.Season()
+	require.Len(t, season.Standings, 4)
+	assert.Equal(t, []string{"alice", "bob", "dave", "carol"}, []string{
+		season.Standings[0].PlayerID, season.Standings[1].PlayerID,
+		season.Standings[2].PlayerID, season.Standings[3].PlayerID,
+	})
+
+	alice, ranked := season.Standing("alice")
+	require.True(t, ranked)
+	assert.Equal(t, SeasonStanding{Rank: 1, PlayerID: "alice", PlayerName: "Alice", BestStreak: 3, Streak: 0, Rounds: 4, Wins: 3}, alice)
+	bob, _ := season.Standing("bob")
+	assert.Equal(t, 1, bob.Streak)
+	assert.Equal(t, 5, bob.Wins)
+	assert.Equal(t, 7*Dollar, bob.Net)
+
+	_, ranked = season.Standing("erin")
+	assert.False(t, ranked)
+}
+
+func TestSeason_Badges(t *testing.T) {
+	board := NewSeasonBoard(time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC))
+	board.Record("alice", "Alice", true, 0)
+	board.Record("alice", "Alice", true, 0)
+	board.Record("bob", "Bob", true, 0)
+	board.Record("carol", "Carol", false, 0)
+	board.Record("dave", "Dave", false, 0)
+
+	// Only leaders who won something earn a badge
+	badges := board.Season().Badges()
+	assert.Equal(t, map[string]SeasonBadge{
+		"alice": {Season: "2026-W42", Rank: 1, BestStreak: 2},
+		"bob":   {Season: "2026-W42", Rank: 2, BestStreak: 1},
+	}, badges)
+	assert.Equal(t, "Gold 2026-W42", badges["alice"].String())
+	assert.Equal(t, "Silver", badges["bob"].Name())
+}
@@ -0,0 +1,143 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBetInProgress is returned when a session already has an unsettled bet
+var ErrBetInProgress = errors.New("a bet is already in progress")
+
+// Session is one player's view of a shared Engine. Each session keeps its
+// own current bet, so concurrent users never see or settle each other's
+// bets. Sessions for the same player share that player's balance.
+type Session struct {
+	engine   *Engine
+	playerID string
+
+	mu         sync.Mutex
+	currentBet *Bet
+}
+
+// NewSession starts an independent betting session for a player. Sessions
+// are safe for concurrent use and cost nothing to discard.
+func (e *Engine) NewSession(playerID string) *Session {
+	return &Session{
+		engine:   e,
+		playerID: playerID,
+	}
+}
+
+// PlayerID returns the player the session bets for
+func (s *Session) PlayerID() string {
+	return s.playerID
+}
+
+// Player returns the session's player, creating it with the starting
+// balance if it doesn't exist
+func (s *Session) Player(ctx context.Context) (*Player, error) {
+	return s.engine.GetPlayer(ctx, s.playerID)
+}
+
+// PlaceBet validates and places the session's bet for the next flip
+func (s *Session) PlaceBet(ctx context.Context, amount Money, choice Side) (*Bet, error) {
+	return s.placeBet(ctx, amount, choice, false)
+}
+
+// PlaceInsuredBet places the session's bet with the engine's insurance,
+// paying its premium on top of the stake
+func (s *Session) PlaceInsuredBet(ctx context.Context, amount Money, choice Side) (*Bet, error) {
+	return s.placeBet(ctx, amount, choice, true)
+}
+
+// PlacePracticeBet places a practice bet for the next flip. It resolves
+// like any other bet, but its outcome goes to the player's practice ledger
+// instead of their balance and stats.
+func (s *Session) PlacePracticeBet(ctx context.Context, amount Money, choice Side) (*Bet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.currentBet != nil {
+		return nil, ErrBetInProgress
+	}
+
+	bet, err := s.engine.practiceBet(s.playerID, amount, choice)
+	if err != nil {
+		return nil, err
+	}
+
+	s.currentBet = bet
+	return bet, nil
+}
+
+// placeBet places the session's bet, insured or not
+func (s *Session) placeBet(ctx context.Context, amount Money, choice Side, insured bool) (*Bet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.currentBet != nil {
+		return nil, ErrBetInProgress
+	}
+
+	bet, err := s.engine.debit(ctx, s.playerID, amount, choice, insured)
+	if err != nil {
+		return nil, err
+	}
+
+	s.currentBet = bet
+	return bet, nil
+}
+
+// FlipCoin settles the session's current bet
+func (s *Session) FlipCoin(ctx context.Context) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.currentBet == nil {
+		return nil, ErrGameNotActive
+	}
+
+	result, err := s.engine.settle(ctx, s.playerID, s.currentBet)
+	if err != nil {
+		return nil, err
+	}
+
+	s.currentBet = nil
+	return result, nil
+}
+
+// CurrentBet returns the session's unsettled bet, if any
+func (s *Session) CurrentBet() *Bet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.currentBet
+}
+
+// CancelBet withdraws the session's current bet and refunds the player
+func (s *Session) CancelBet(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.currentBet == nil {
+		return ErrGameNotActive
+	}
+
+	if err := s.engine.refund(ctx, s.playerID, s.currentBet); err != nil {
+		return err
+	}
+
+	s.currentBet = nil
+	return nil
+}
+
+// BuySkin unlocks a coin skin for the session's player
+func (s *Session) BuySkin(ctx context.Context, skinID string) (*Player, error) {
+	return s.engine.BuySkin(ctx, s.playerID, skinID)
+}
+
+// EquipSkin switches the session's player to a coin skin they own
+func (s *Session) EquipSkin(ctx context.Context, skinID string) (*Player, error) {
+	return s.engine.EquipSkin(ctx, s.playerID, skinID)
+}
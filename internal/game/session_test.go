@@ -0,0 +1,159 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// mapRepository is a minimal thread-safe Repository for session tests
+type mapRepository struct {
+	mu      sync.Mutex
+	players map[string]Player
+	results []*Result
+}
+
+func newMapRepository() *mapRepository {
+	return &mapRepository{players: make(map[string]Player)}
+}
+
+func (r *mapRepository) SaveResult(ctx context.Context, result *Result) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, result)
+	return nil
+}
+
+func (r *mapRepository) GetResults(ctx context.Context, limit int) ([]*Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Result(nil), r.results...), nil
+}
+
+func (r *mapRepository) GetStats(ctx context.Context, playerID string) (*Stats, error) {
+	player, err := r.GetPlayer(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+	return &player.Stats, nil
+}
+
+func (r *mapRepository) SavePlayer(ctx context.Context, player *Player) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.players[player.ID] = *player
+	return nil
+}
+
+func (r *mapRepository) GetPlayer(ctx context.Context, playerID string) (*Player, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	player, exists := r.players[playerID]
+	if !exists {
+		return nil, errors.New("player not found")
+	}
+	return &player, nil
+}
+
+// fixedGenerator always lands on the same side
+type fixedGenerator struct {
+	side Side
+}
+
+func (g fixedGenerator) GenerateSecureSeed() (string, error) { return "seed", nil }
+func (g fixedGenerator) FlipCoin(seed string) (Side, error)  { return g.side, nil }
+
+func newSessionEngine(t *testing.T, side Side) (*Engine, *mapRepository) {
+	config := Config{StartingBalance: 1000 * Dollar, MinBet: Dollar, MaxBet: 100 * Dollar, PayoutRatio: 2.0}
+	repo := newMapRepository()
+	return NewEngine(config, repo, fixedGenerator{side: side}, zaptest.NewLogger(t)), repo
+}
+
+func TestSession_IsolatesBets(t *testing.T) {
+	engine, _ := newSessionEngine(t, Heads)
+	ctx := context.Background()
+
+	alice := engine.NewSession("alice")
+	bob := engine.NewSession("bob")
+
+	_, err := alice.PlaceBet(ctx, 10*Dollar, Heads)
+	require.NoError(t, err)
+	_, err = alice.PlaceBet(ctx, 10*Dollar, Tails)
+	assert.ErrorIs(t, err, ErrBetInProgress)
+
+	assert.Nil(t, bob.CurrentBet())
+	_, err = bob.FlipCoin(ctx)
+	assert.ErrorIs(t, err, ErrGameNotActive)
+
+	_, err = bob.PlaceBet(ctx, 20*Dollar, Tails)
+	require.NoError(t, err)
+	require.NoError(t, bob.CancelBet(ctx))
+
+	result, err := alice.FlipCoin(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Won)
+	assert.Nil(t, alice.CurrentBet())
+
+	player, err := alice.Player(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1010*Dollar, player.Balance)
+
+	player, err = bob.Player(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1000*Dollar, player.Balance)
+
+	// The deprecated engine-wide bet is untouched by sessions
+	assert.Nil(t, engine.GetCurrentBet())
+}
+
+func TestSession_ConcurrentSessions(t *testing.T) {
+	engine, repo := newSessionEngine(t, Tails)
+	ctx := context.Background()
+
+	const players, rounds = 8, 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < players; i++ {
+		// Two sessions per player share the player's balance
+		for j := 0; j < 2; j++ {
+			session := engine.NewSession(fmt.Sprintf("player_%d", i))
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for r := 0; r < rounds; r++ {
+					if _, err := session.PlaceBet(ctx, 5*Dollar, Heads); err != nil {
+						t.Error(err)
+						return
+					}
+					if _, err := session.FlipCoin(ctx); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	for i := 0; i < players; i++ {
+		player, err := repo.GetPlayer(ctx, fmt.Sprintf("player_%d", i))
+		require.NoError(t, err)
+		assert.Equal(t, 1000*Dollar-2*rounds*5*Dollar, player.Balance)
+		assert.Equal(t, 2*rounds, player.Stats.GamesPlayed)
+	}
+
+	// Every bet got a distinct ID
+	results, err := repo.GetResults(ctx, 0)
+	require.NoError(t, err)
+	ids := make(map[string]bool)
+	for _, result := range results {
+		ids[result.Bet.ID] = true
+	}
+	assert.Len(t, ids, players*2*rounds)
+}
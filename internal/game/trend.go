@@ -0,0 +1,89 @@
+package game
+
+import "sort"
+
+// DefaultTrendWindow is how many bets the rolling win rate averages over
+const DefaultTrendWindow = 20
+
+// Trend follows a player's settled bets over time, oldest first: the
+// balance after each bet, the win rate over a rolling window and the
+// streak each bet left the player on. Practice bets and results without a
+// bet are left out.
+type Trend struct {
+	// Balances are reconstructed back from the current balance, so they
+	// ignore deposits and withdrawals made between bets
+	Balances []Money
+	// WinRates is the percentage of wins among each bet and the ones
+	// before it, up to the window
+	WinRates []float64
+	// Streaks counts consecutive wins up and consecutive losses down, so a
+	// third loss in a row is -3
+	Streaks []int
+	// Stats summarizes the bets, including how the coin landed and which
+	// sides were backed
+	Stats Stats
+}
+
+// NewTrend builds the trend of results, in any order, ending at the
+// player's current balance. A window of zero or less uses
+// DefaultTrendWindow.
+func NewTrend(results []*Result, balance Money, window int) Trend {
+	if window <= 0 {
+		window = DefaultTrendWindow
+	}
+
+	bets := make([]*Result, 0, len(results))
+	for _, result := range results {
+		if result.Bet != nil && !result.Bet.Practice {
+			bets = append(bets, result)
+		}
+	}
+	sort.SliceStable(bets, func(i, j int) bool {
+		return bets[i].Timestamp.Before(bets[j].Timestamp)
+	})
+
+	trend := Trend{
+		Balances: make([]Money, len(bets)),
+		WinRates: make([]float64, len(bets)),
+		Streaks:  make([]int, len(bets)),
+		Stats:    SummarizeResults(bets),
+	}
+
+	// Walk back from the current balance to where the first bet started
+	for _, bet := range bets {
+		balance -= betChange(bet)
+	}
+
+	wins, streak := 0, 0
+	for i, bet := range bets {
+		balance += betChange(bet)
+		trend.Balances[i] = balance
+
+		if bet.Won {
+			wins++
+			streak = max(streak, 0) + 1
+		} else {
+			streak = min(streak, 0) - 1
+		}
+		if i >= window && bets[i-window].Won {
+			wins--
+		}
+		trend.WinRates[i] = float64(wins) / float64(min(i+1, window)) * 100
+		trend.Streaks[i] = streak
+	}
+	return trend
+}
+
+// LongestStreaks returns the longest runs of wins and of losses
+func (t Trend) LongestStreaks() (wins, losses int) {
+	for _, streak := range t.Streaks {
+		wins = max(wins, streak)
+		losses = max(losses, -streak)
+	}
+	return wins, losses
+}
+
+// betChange is what a settled bet did to the balance
+func betChange(result *Result) Money {
+	return result.Payout + result.Insurance - result.Bet.Cost()
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTrend(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bet := func(minute int, choice, side Side, amount Money) *Result {
+		result := &Result{
+			Side:      side,
+			Bet:       &Bet{Amount: amount, Choice: choice},
+			Won:       choice == side,
+			Timestamp: start.Add(time.Duration(minute) * time.Minute),
+		}
+		if result.Won {
+			result.Payout = 2 * amount
+		}
+		return result
+	}
+
+	// Newest first, as history queries return them
+	results := []*Result{
+		bet(5, Tails, Heads, 10*Dollar),
+		bet(4, Heads, Heads, 20*Dollar),
+		{Side: Tails, Timestamp: start.Add(3 * time.Minute)},
+		bet(3, Heads, Tails, 5*Dollar),
+		{Side: Heads, Bet: &Bet{Amount: 50 * Dollar, Choice: Heads, Practice: true}, Won: true, Payout: 100 * Dollar, Timestamp: start.Add(2 * time.Minute)},
+		bet(2, Heads, Tails, 5*Dollar),
+		bet(1, Tails, Tails, 10*Dollar),
+	}
+
+	trend := NewTrend(results, 110*Dollar, 2)
+	assert.Equal(t, []Money{110 * Dollar, 105 * Dollar, 100 * Dollar, 120 * Dollar, 110 * Dollar}, trend.Balances)
+	assert.Equal(t, []float64{100, 50, 0, 50, 50}, trend.WinRates)
+	assert.Equal(t, []int{1, -1, -2, 1, -1}, trend.Streaks)
+
+	wins, losses := trend.LongestStreaks()
+	assert.Equal(t, 1, wins)
+	assert.Equal(t, 2, losses)
+
+	assert.Equal(t, 5, trend.Stats.GamesPlayed)
+	assert.Equal(t, 2, trend.Stats.GamesWon)
+	assert.Equal(t, 3, trend.Stats.Outcomes.Count(Tails))
+	assert.Equal(t, 3, trend.Stats.Choices.Count(Heads))
+}
+
+func TestNewTrend_Empty(t *testing.T) {
+	trend := NewTrend(nil, 100*Dollar, 0)
+	assert.Empty(t, trend.Balances)
+
+	wins, losses := trend.LongestStreaks()
+	assert.Zero(t, wins)
+	assert.Zero(t, losses)
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/logger"
+)
+
+// AdjustBalance adds delta, which may be negative, to the player's balance
+// and records it in the audit trail with the reason. The balance never
+// drops below zero. It is how a balance kept elsewhere, such as a wallet
+// on a multiplayer server, is brought to this engine.
+func (e *Engine) AdjustBalance(ctx context.Context, playerID string, delta Money, reason string) (*Player, error) {
+	unlock := e.lockPlayer(playerID)
+	defer unlock()
+
+	player, err := e.GetPlayer(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player: %w", err)
+	}
+	if delta == 0 {
+		return player, nil
+	}
+
+	if player.Balance+delta < 0 {
+		delta = -player.Balance
+	}
+	player.Balance += delta
+	if err := e.savePlayer(ctx, player); err != nil {
+		return nil, fmt.Errorf("failed to update player balance: %w", err)
+	}
+
+	e.logger.Info("Balance adjusted",
+		zap.String("player_id", playerID),
+		zap.Float64("delta", delta.Float64()),
+		zap.Float64("balance", player.Balance.Float64()),
+		zap.String("reason", reason),
+	)
+	e.audit.Record(logger.AuditEvent{
+		Time:     e.clock.Now(),
+		Event:    logger.AuditCredit,
+		PlayerID: playerID,
+		Amount:   delta.Float64(),
+		Balance:  player.Balance.Float64(),
+		Reason:   reason,
+	})
+
+	return player, nil
+}
+
+// OfflineCredit is what a player's offline play has added to their wallet
+// on a multiplayer server during one day, net of offline losses
+type OfflineCredit struct {
+	// Day is the UTC date of the credit, as formatted by time.DateOnly
+	Day    string `json:"day"`
+	Amount Money  `json:"amount"`
+}
+
+// Clone returns a copy of the credit, or nil for nil
+func (c *OfflineCredit) Clone() *OfflineCredit {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	return &clone
+}
+
+// CreditedOffline returns what offline play has added to the player's
+// wallet on day, as formatted by time.DateOnly
+func (p *Player) CreditedOffline(day string) Money {
+	if p.Offline == nil || p.Offline.Day != day {
+		return 0
+	}
+	return p.Offline.Amount
+}
@@ -0,0 +1,40 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_AdjustBalance(t *testing.T) {
+	engine, repo := newSessionEngine(t, Heads)
+	ctx := context.Background()
+
+	player, err := engine.AdjustBalance(ctx, "alice", 250*Dollar, "wallet sync")
+	require.NoError(t, err)
+	assert.Equal(t, 1250*Dollar, player.Balance)
+
+	player, err = engine.AdjustBalance(ctx, "alice", -50*Dollar, "wallet sync")
+	require.NoError(t, err)
+	assert.Equal(t, 1200*Dollar, player.Balance)
+
+	stored, err := repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1200*Dollar, stored.Balance)
+
+	// Losses past the balance stop at zero
+	player, err = engine.AdjustBalance(ctx, "alice", -5000*Dollar, "wallet sync")
+	require.NoError(t, err)
+	assert.Zero(t, player.Balance)
+}
+
+func TestPlayer_CreditedOffline(t *testing.T) {
+	player := &Player{ID: "alice"}
+	assert.Zero(t, player.CreditedOffline("2026-10-16"))
+
+	player.Offline = &OfflineCredit{Day: "2026-10-16", Amount: 300 * Dollar}
+	assert.Equal(t, 300*Dollar, player.CreditedOffline("2026-10-16"))
+	assert.Zero(t, player.CreditedOffline("2026-10-17"), "each day has its own allowance")
+}
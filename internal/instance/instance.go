@@ -0,0 +1,154 @@
+// Package instance keeps one GUI running per profile. The first launch
+// holds a local socket, and later launches hand their request to it, such
+// as bringing its window to the front, instead of opening a second window
+// that races it on the same player data.
+package instance
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrRunning is returned by Acquire when another instance holds the
+// socket; the request was handed to it
+var ErrRunning = errors.New("another instance is running")
+
+// CommandFocus asks the running instance to show and focus its window
+const CommandFocus = "focus"
+
+// Timeout bounds a request's exchange with the running instance
+const Timeout = 2 * time.Second
+
+// requestBuffer is how many requests wait for the running instance before
+// more are dropped. Requests only ask for the window, so one is as good as
+// several.
+const requestBuffer = 8
+
+// Request is what a later launch asks of the running instance
+type Request struct {
+	Command string `json:"command"`
+}
+
+// reply acknowledges a request
+type reply struct {
+	OK bool `json:"ok"`
+}
+
+// Guard is the running instance's hold on its socket. It receives the
+// requests of later launches until closed.
+type Guard struct {
+	listener net.Listener
+	requests chan Request
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Acquire makes this process the running instance for the socket at path.
+// When another instance already holds it, the request is sent there and
+// ErrRunning returned. A socket left behind by an instance that crashed is
+// replaced.
+func Acquire(path string, request Request) (*Guard, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create instance directory: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		if Send(path, request) == nil {
+			return nil, ErrRunning
+		}
+
+		// Nobody answers, so the socket is stale
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale instance socket: %w", err)
+		}
+		listener, err = net.Listen("unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hold instance socket %s: %w", path, err)
+		}
+	}
+
+	guard := &Guard{
+		listener: listener,
+		requests: make(chan Request, requestBuffer),
+		done:     make(chan struct{}),
+	}
+	go guard.serve()
+	return guard, nil
+}
+
+// Send hands a request to the instance holding the socket at path
+func Send(path string, request Request) error {
+	conn, err := net.DialTimeout("unix", path, Timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(Timeout))
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return err
+	}
+	var answer reply
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&answer); err != nil {
+		return err
+	}
+	if !answer.OK {
+		return fmt.Errorf("instance refused %q", request.Command)
+	}
+	return nil
+}
+
+// Requests returns the requests of later launches. It is closed when the
+// guard is.
+func (g *Guard) Requests() <-chan Request {
+	return g.requests
+}
+
+// Close releases the socket, so the next launch becomes the running
+// instance
+func (g *Guard) Close() error {
+	var err error
+	g.once.Do(func() {
+		err = g.listener.Close()
+		<-g.done
+	})
+	return err
+}
+
+// serve accepts requests until the guard is closed
+func (g *Guard) serve() {
+	defer close(g.done)
+	defer close(g.requests)
+
+	for {
+		conn, err := g.listener.Accept()
+		if err != nil {
+			return
+		}
+		g.handle(conn)
+	}
+}
+
+// handle reads one request from a later launch and acknowledges it
+func (g *Guard) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(Timeout))
+
+	var request Request
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&request); err != nil {
+		return
+	}
+	select {
+	case g.requests <- request:
+	default:
+	}
+	json.NewEncoder(conn).Encode(reply{OK: true})
+}
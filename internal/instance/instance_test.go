@@ -0,0 +1,57 @@
+package instance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire_SecondLaunchFocusesFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gui.sock")
+
+	first, err := Acquire(path, Request{Command: CommandFocus})
+	require.NoError(t, err)
+	defer first.Close()
+
+	second, err := Acquire(path, Request{Command: CommandFocus})
+	assert.ErrorIs(t, err, ErrRunning)
+	assert.Nil(t, second)
+
+	select {
+	case request := <-first.Requests():
+		assert.Equal(t, CommandFocus, request.Command)
+	case <-time.After(time.Second):
+		t.Fatal("the running instance never heard of the second launch")
+	}
+}
+
+func TestAcquire_AfterClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gui.sock")
+
+	first, err := Acquire(path, Request{Command: CommandFocus})
+	require.NoError(t, err)
+	require.NoError(t, first.Close())
+	_, open := <-first.Requests()
+	assert.False(t, open, "closing ends the requests")
+
+	// The next launch takes over
+	second, err := Acquire(path, Request{Command: CommandFocus})
+	require.NoError(t, err)
+	assert.NoError(t, second.Close())
+}
+
+func TestAcquire_StaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gui.sock")
+
+	// An instance that crashed leaves its socket file with nobody on it
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	guard, err := Acquire(path, Request{Command: CommandFocus})
+	require.NoError(t, err)
+	defer guard.Close()
+	assert.NoError(t, Send(path, Request{Command: CommandFocus}))
+}
@@ -0,0 +1,198 @@
+package lan
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Announcer answers mDNS queries for the service with a hosted game, until
+// closed
+type Announcer struct {
+	conn     net.PacketConn
+	logger   *zap.Logger
+	instance dnsmessage.Name
+	target   dnsmessage.Name
+	port     uint16
+	room     string
+	done     chan struct{}
+}
+
+// Announce starts announcing a game named name, served on port, whose
+// players meet in room. Close stops it.
+func Announce(name string, port int, room string, logger *zap.Logger) (*Announcer, error) {
+	group, err := net.ResolveUDPAddr("udp4", mdnsAddress)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		return nil, err
+	}
+	return newAnnouncer(conn, name, port, room, logger)
+}
+
+// newAnnouncer answers the queries arriving on conn
+func newAnnouncer(conn net.PacketConn, name string, port int, room string, logger *zap.Logger) (*Announcer, error) {
+	instance, err := dnsmessage.NewName(instanceLabel(name) + "." + Service)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	target, err := dnsmessage.NewName(hostLabel() + ".local.")
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	a := &Announcer{
+		conn:     conn,
+		logger:   logger,
+		instance: instance,
+		target:   target,
+		port:     uint16(port),
+		room:     room,
+		done:     make(chan struct{}),
+	}
+	go a.serve()
+	return a, nil
+}
+
+// Name returns the name the game is announced under
+func (a *Announcer) Name() string {
+	return instanceName(a.instance.String())
+}
+
+// Close stops announcing the game
+func (a *Announcer) Close() error {
+	err := a.conn.Close()
+	<-a.done
+	return err
+}
+
+// serve answers queries until the connection is closed
+func (a *Announcer) serve() {
+	defer close(a.done)
+
+	buf := make([]byte, maxPacket)
+	for {
+		n, from, err := a.conn.ReadFrom(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			a.logger.Warn("Failed to read mDNS query", zap.Error(err))
+			continue
+		}
+
+		answer, err := a.answer(buf[:n])
+		if err != nil || answer == nil {
+			continue
+		}
+		if _, err := a.conn.WriteTo(answer, from); err != nil {
+			a.logger.Warn("Failed to answer mDNS query", zap.String("from", from.String()), zap.Error(err))
+		}
+	}
+}
+
+// answer builds the answer to a query for the service or the announced
+// instance, or returns nil for any other packet. Queriers are answered
+// directly, so the answer repeats the query's ID and question.
+func (a *Announcer) answer(packet []byte) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(packet)
+	if err != nil || header.Response {
+		return nil, err
+	}
+	questions, err := parser.AllQuestions()
+	if err != nil {
+		return nil, err
+	}
+
+	var asked *dnsmessage.Question
+	for i, question := range questions {
+		name := question.Name.String()
+		if strings.EqualFold(name, Service) || strings.EqualFold(name, a.instance.String()) {
+			asked = &questions[i]
+			break
+		}
+	}
+	if asked == nil {
+		return nil, nil
+	}
+
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: header.ID, Response: true, Authoritative: true})
+	builder.EnableCompression()
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(dnsmessage.Question{Name: asked.Name, Type: asked.Type, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	if err := builder.StartAnswers(); err != nil {
+		return nil, err
+	}
+
+	resource := func(name dnsmessage.Name) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: recordTTL}
+	}
+	if err := builder.PTRResource(resource(dnsmessage.MustNewName(Service)), dnsmessage.PTRResource{PTR: a.instance}); err != nil {
+		return nil, err
+	}
+	if err := builder.SRVResource(resource(a.instance), dnsmessage.SRVResource{Target: a.target, Port: a.port}); err != nil {
+		return nil, err
+	}
+	if err := builder.TXTResource(resource(a.instance), dnsmessage.TXTResource{TXT: []string{"room=" + a.room}}); err != nil {
+		return nil, err
+	}
+	for _, ip := range localIPv4() {
+		if err := builder.AResource(resource(a.target), dnsmessage.AResource{A: ip}); err != nil {
+			return nil, err
+		}
+	}
+	return builder.Finish()
+}
+
+// hostLabel is this machine's name as a single DNS label
+func hostLabel() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "coinflip"
+	}
+	label, _, _ := strings.Cut(hostname, ".")
+	return instanceLabel(label)
+}
+
+// localIPv4 lists the IPv4 addresses of the interfaces that take part in
+// multicast, for the A records of the announced host
+func localIPv4() [][4]byte {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	var ips [][4]byte
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			if ip := ipNet.IP.To4(); ip != nil {
+				ips = append(ips, [4]byte(ip))
+			}
+		}
+	}
+	return ips
+}
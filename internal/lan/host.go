@@ -0,0 +1,72 @@
+package lan
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/network"
+)
+
+// Host runs a game server inside the hosting player's app and announces
+// it on the local network
+type Host struct {
+	server    *network.Server
+	listener  net.Listener
+	announcer *Announcer
+	room      string
+}
+
+// StartHost serves a game with config on every interface, on config's
+// port or any free one when it is 0, and announces it as name with room
+// as the room to join. Stop ends both.
+func StartHost(config *network.ServerConfig, name, room string, logger *zap.Logger) (*Host, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(config.Port)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for LAN players: %w", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	announcer, err := Announce(name, port, room, logger)
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to announce the game: %w", err)
+	}
+
+	server := network.NewServer(config, logger)
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, net.ErrClosed) && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("LAN game server stopped", zap.Error(err))
+		}
+	}()
+
+	logger.Info("Hosting LAN game",
+		zap.String("name", announcer.Name()),
+		zap.Int("port", port),
+		zap.String("room", room),
+	)
+	return &Host{server: server, listener: listener, announcer: announcer, room: room}, nil
+}
+
+// Game is the hosted game as the hosting player reaches it, on this machine
+func (h *Host) Game() Game {
+	port := h.listener.Addr().(*net.TCPAddr).Port
+	return Game{
+		Name: h.announcer.Name(),
+		Addr: net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+		Room: h.room,
+	}
+}
+
+// Stop stops announcing the game and shuts its server down, disconnecting
+// every player
+func (h *Host) Stop() {
+	h.announcer.Close()
+	h.server.Stop()
+	h.listener.Close()
+}
@@ -0,0 +1,199 @@
+// Package lan lets players on a local network play together without a
+// central server. The hosting player's app runs the game server itself and
+// announces it over multicast DNS (mDNS) as a _coinflip._tcp service; the
+// others browse for it and connect to the host's WebSocket server directly.
+package lan
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// Service is the DNS-SD service games are announced as
+	Service = "_coinflip._tcp.local."
+	// DefaultBrowseTimeout is how long Browse waits for hosts to answer
+	DefaultBrowseTimeout = 2 * time.Second
+	// mdnsAddress is the IPv4 multicast group and port of mDNS
+	mdnsAddress = "224.0.0.251:5353"
+	// recordTTL is how long, in seconds, answers may be cached
+	recordTTL = 120
+	// maxPacket is the largest mDNS packet read
+	maxPacket = 9000
+)
+
+// Game is a game hosted on the local network
+type Game struct {
+	// Name is the host's name for the game, unique on the network
+	Name string
+	// Addr is where the host's server listens, as host:port
+	Addr string
+	// Room is the room the host plays in, which joining players join too
+	Room string
+}
+
+// URL returns the WebSocket URL of the game's server
+func (g Game) URL() string {
+	return "ws://" + g.Addr + "/ws"
+}
+
+// Browse asks the local network for hosted games and returns those that
+// answer within timeout, by name
+func Browse(ctx context.Context, timeout time.Duration) ([]Game, error) {
+	group, err := net.ResolveUDPAddr("udp4", mdnsAddress)
+	if err != nil {
+		return nil, err
+	}
+	return browse(ctx, group, timeout)
+}
+
+// browse sends a query for the service to target and gathers the games
+// answering it. The query comes from an ephemeral port, so hosts answer
+// it directly rather than to the multicast group.
+func browse(ctx context.Context, target net.Addr, timeout time.Duration) ([]Game, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	id := uint16(rand.N(1<<16-1)) + 1
+	query, err := serviceQuery(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteTo(query, target); err != nil {
+		return nil, err
+	}
+
+	// Stop waiting early if the context ends
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	games := make(map[string]Game)
+	buf := make([]byte, maxPacket)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		udp, ok := from.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+		for _, game := range parseAnswer(buf[:n], id, udp.IP) {
+			games[game.Name] = game
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	found := make([]Game, 0, len(games))
+	for _, game := range games {
+		found = append(found, game)
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	return found, nil
+}
+
+// serviceQuery builds a query for the hosts of the service
+func serviceQuery(id uint16) ([]byte, error) {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id})
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(dnsmessage.Question{
+		Name:  dnsmessage.MustNewName(Service),
+		Type:  dnsmessage.TypePTR,
+		Class: dnsmessage.ClassINET,
+	}); err != nil {
+		return nil, err
+	}
+	return builder.Finish()
+}
+
+// parseAnswer reads the games in a host's answer to the query with id. The
+// host is reached at the address the answer came from, on the port its
+// SRV record gives.
+func parseAnswer(packet []byte, id uint16, from net.IP) []Game {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(packet)
+	if err != nil || !header.Response || header.ID != id {
+		return nil
+	}
+	if err := parser.SkipAllQuestions(); err != nil {
+		return nil
+	}
+	answers, err := parser.AllAnswers()
+	if err != nil {
+		return nil
+	}
+
+	var instances []string
+	ports := make(map[string]uint16)
+	rooms := make(map[string]string)
+	for _, answer := range answers {
+		name := strings.ToLower(answer.Header.Name.String())
+		switch body := answer.Body.(type) {
+		case *dnsmessage.PTRResource:
+			if strings.EqualFold(answer.Header.Name.String(), Service) {
+				instances = append(instances, body.PTR.String())
+			}
+		case *dnsmessage.SRVResource:
+			ports[name] = body.Port
+		case *dnsmessage.TXTResource:
+			for _, entry := range body.TXT {
+				if room, ok := strings.CutPrefix(entry, "room="); ok {
+					rooms[name] = room
+				}
+			}
+		}
+	}
+
+	var games []Game
+	for _, instance := range instances {
+		port, ok := ports[strings.ToLower(instance)]
+		if !ok {
+			continue
+		}
+		games = append(games, Game{
+			Name: instanceName(instance),
+			Addr: net.JoinHostPort(from.String(), strconv.Itoa(int(port))),
+			Room: rooms[strings.ToLower(instance)],
+		})
+	}
+	return games
+}
+
+// instanceLabel turns a game name into the first label of its service
+// instance name, which cannot hold dots and is at most 63 bytes
+func instanceLabel(name string) string {
+	label := strings.ReplaceAll(strings.TrimSpace(name), ".", " ")
+	if label == "" {
+		label = "Coin flip"
+	}
+	for len(label) > 63 {
+		runes := []rune(label)
+		label = string(runes[:len(runes)-1])
+	}
+	return label
+}
+
+// instanceName returns the game name of a service instance name
+func instanceName(instance string) string {
+	return strings.TrimSuffix(instance, "."+Service)
+}
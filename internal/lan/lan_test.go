@@ -0,0 +1,91 @@
+package lan
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/network"
+)
+
+// localAnnouncer announces a game on a loopback socket, which browse can
+// query without multicast
+func localAnnouncer(t *testing.T, name string, port int, room string) *Announcer {
+	t.Helper()
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	announcer, err := newAnnouncer(conn, name, port, room, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { announcer.Close() })
+	return announcer
+}
+
+func TestBrowse_FindsAnnouncedGame(t *testing.T) {
+	announcer := localAnnouncer(t, "Alice's game", 4242, "lan")
+
+	games, err := browse(context.Background(), announcer.conn.LocalAddr(), 500*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, []Game{{Name: "Alice's game", Addr: "127.0.0.1:4242", Room: "lan"}}, games)
+	assert.Equal(t, "ws://127.0.0.1:4242/ws", games[0].URL())
+}
+
+func TestBrowse_StopsWithContext(t *testing.T) {
+	announcer := localAnnouncer(t, "Game", 4242, "lan")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	_, err := browse(ctx, announcer.conn.LocalAddr(), 5*time.Second)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestAnnouncer_IgnoresOtherQuestions(t *testing.T) {
+	announcer := localAnnouncer(t, "Game", 4242, "lan")
+
+	query, err := serviceQuery(7)
+	require.NoError(t, err)
+	answer, err := announcer.answer(query)
+	require.NoError(t, err)
+	games := parseAnswer(answer, 7, net.IPv4(10, 0, 0, 5))
+	assert.Equal(t, []Game{{Name: "Game", Addr: "10.0.0.5:4242", Room: "lan"}}, games)
+	assert.Empty(t, parseAnswer(answer, 8, net.IPv4(10, 0, 0, 5)), "answers to another query are ignored")
+
+	// A packet that is not a query for the service gets no answer
+	answer, err = announcer.answer(answer)
+	require.NoError(t, err)
+	assert.Nil(t, answer)
+}
+
+func TestInstanceLabel(t *testing.T) {
+	assert.Equal(t, "Bob s game", instanceLabel("Bob.s game"))
+	assert.Equal(t, "Coin flip", instanceLabel("  "))
+	assert.Len(t, instanceLabel(string(make([]byte, 100))), 63)
+}
+
+func TestStartHost(t *testing.T) {
+	config := network.DefaultServerConfig()
+	config.Port = 0
+	// The host and client may log the connection after the test ends
+	host, err := StartHost(config, "Carol's game", "lan", zap.NewNop())
+	if err != nil {
+		t.Skipf("multicast is not available: %v", err)
+	}
+	defer host.Stop()
+
+	game := host.Game()
+	assert.Equal(t, "Carol's game", game.Name)
+	assert.Equal(t, "lan", game.Room)
+
+	clientConfig := network.DefaultClientConfig()
+	clientConfig.ServerURL = game.URL()
+	client := network.NewNetworkClient(clientConfig, "p1", "Carol", zap.NewNop())
+	require.NoError(t, client.Connect())
+	client.Disconnect()
+}
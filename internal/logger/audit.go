@@ -0,0 +1,259 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrAuditChainBroken is returned when an audit log fails verification
+var ErrAuditChainBroken = errors.New("audit chain broken")
+
+// AuditEventType identifies a gameplay action in the audit trail
+type AuditEventType string
+
+const (
+	AuditJoin      AuditEventType = "join"
+	AuditLeave     AuditEventType = "leave"
+	AuditBet       AuditEventType = "bet"
+	AuditBetUpdate AuditEventType = "bet_update"
+	AuditFlip      AuditEventType = "flip"
+	AuditPayout    AuditEventType = "payout"
+	AuditRefund    AuditEventType = "refund"
+	AuditCredit    AuditEventType = "credit"
+	AuditPurchase  AuditEventType = "purchase"
+	AuditRegister  AuditEventType = "register"
+	AuditInsurance AuditEventType = "insurance"
+	AuditRole      AuditEventType = "role"
+)
+
+// AuditEvent is one line of the audit trail. Hash covers every other field,
+// including PrevHash, so altering, dropping or reordering lines breaks the chain.
+type AuditEvent struct {
+	Seq      uint64         `json:"seq"`
+	Time     time.Time      `json:"time"`
+	Event    AuditEventType `json:"event"`
+	PlayerID string         `json:"player_id,omitempty"`
+	RoomID   string         `json:"room_id,omitempty"`
+	RoundID  string         `json:"round_id,omitempty"`
+	BetID    string         `json:"bet_id,omitempty"`
+	Amount   float64        `json:"amount,omitempty"`
+	Balance  float64        `json:"balance,omitempty"`
+	Choice   string         `json:"choice,omitempty"`
+	Result   string         `json:"result,omitempty"`
+	Seed     string         `json:"seed,omitempty"`
+	Reason   string         `json:"reason,omitempty"`
+	PrevHash string         `json:"prev_hash"`
+	Hash     string         `json:"hash"`
+}
+
+// MarshalLogObject writes the event with the same keys as its JSON form
+func (e *AuditEvent) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddUint64("seq", e.Seq)
+	enc.AddString("time", e.Time.Format(time.RFC3339Nano))
+	enc.AddString("event", string(e.Event))
+	addString(enc, "player_id", e.PlayerID)
+	addString(enc, "room_id", e.RoomID)
+	addString(enc, "round_id", e.RoundID)
+	addString(enc, "bet_id", e.BetID)
+	if e.Amount != 0 {
+		enc.AddFloat64("amount", e.Amount)
+	}
+	if e.Balance != 0 {
+		enc.AddFloat64("balance", e.Balance)
+	}
+	addString(enc, "choice", e.Choice)
+	addString(enc, "result", e.Result)
+	addString(enc, "seed", e.Seed)
+	addString(enc, "reason", e.Reason)
+	enc.AddString("prev_hash", e.PrevHash)
+	enc.AddString("hash", e.Hash)
+	return nil
+}
+
+// addString omits empty values, mirroring the JSON omitempty tags
+func addString(enc zapcore.ObjectEncoder, key, value string) {
+	if value != "" {
+		enc.AddString(key, value)
+	}
+}
+
+// computeHash returns the chain hash of the event with Hash cleared
+func (e AuditEvent) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AuditLogger appends hash-chained gameplay events as JSON lines to a
+// dedicated file, separate from the operational log. A nil *AuditLogger
+// discards events, so callers can leave auditing unconfigured.
+type AuditLogger struct {
+	mu       sync.Mutex
+	logger   *zap.Logger
+	file     *os.File
+	seq      uint64
+	prevHash string
+}
+
+// NewAuditLogger opens (or creates) the audit file at path. Events appended
+// to an existing file continue its hash chain.
+func NewAuditLogger(path string) (*AuditLogger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	last, err := lastAuditEvent(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	// Bare JSON lines: the event carries its own time and no level or message
+	encoderConfig := zapcore.EncoderConfig{
+		LineEnding: zapcore.DefaultLineEnding,
+	}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(file), zapcore.InfoLevel)
+
+	audit := &AuditLogger{
+		logger: zap.New(core),
+		file:   file,
+	}
+	if last != nil {
+		audit.seq = last.Seq
+		audit.prevHash = last.Hash
+	}
+
+	return audit, nil
+}
+
+// lastAuditEvent returns the final event of an existing audit file, if any
+func lastAuditEvent(path string) (*AuditEvent, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var last *AuditEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+		last = &event
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return last, nil
+}
+
+// Record appends an event to the audit trail, assigning its sequence
+// number, timestamp (if unset) and chain hashes
+func (a *AuditLogger) Record(event AuditEvent) {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	// Normalise so the hash survives a round trip through the file
+	event.Time = event.Time.UTC().Round(0)
+
+	a.seq++
+	event.Seq = a.seq
+	event.PrevHash = a.prevHash
+
+	hash, err := event.computeHash()
+	if err != nil {
+		return
+	}
+	event.Hash = hash
+	a.prevHash = hash
+
+	a.logger.Info("", zap.Inline(&event))
+}
+
+// Close flushes and closes the audit file
+func (a *AuditLogger) Close() error {
+	if a == nil {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.logger.Sync()
+	return a.file.Close()
+}
+
+// VerifyAuditLog checks the hash chain of an audit trail and returns the
+// number of events verified
+func VerifyAuditLog(r io.Reader) (int, error) {
+	var (
+		count    int
+		prevHash string
+		lastSeq  uint64
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return count, fmt.Errorf("%w: line %d is not a valid event: %v", ErrAuditChainBroken, count+1, err)
+		}
+
+		if event.PrevHash != prevHash {
+			return count, fmt.Errorf("%w: event %d does not follow its predecessor", ErrAuditChainBroken, event.Seq)
+		}
+		if count > 0 && event.Seq != lastSeq+1 {
+			return count, fmt.Errorf("%w: expected event %d, found %d", ErrAuditChainBroken, lastSeq+1, event.Seq)
+		}
+
+		hash, err := event.computeHash()
+		if err != nil {
+			return count, err
+		}
+		if hash != event.Hash {
+			return count, fmt.Errorf("%w: event %d has been modified", ErrAuditChainBroken, event.Seq)
+		}
+
+		prevHash = event.Hash
+		lastSeq = event.Seq
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
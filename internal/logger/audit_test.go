@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAuditEvents(t *testing.T, path string, events ...AuditEvent) {
+	t.Helper()
+
+	audit, err := NewAuditLogger(path)
+	require.NoError(t, err)
+	for _, event := range events {
+		audit.Record(event)
+	}
+	require.NoError(t, audit.Close())
+}
+
+func TestAuditLogger_ChainVerifies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+
+	writeAuditEvents(t, path,
+		AuditEvent{Event: AuditJoin, PlayerID: "p1", RoomID: "room", Balance: 1000},
+		AuditEvent{Event: AuditBet, PlayerID: "p1", RoomID: "room", RoundID: "r1", BetID: "b1", Amount: 12.5, Choice: "heads"},
+	)
+	// Reopening continues the existing chain
+	writeAuditEvents(t, path,
+		AuditEvent{Event: AuditFlip, RoomID: "room", RoundID: "r1", Result: "heads", Seed: "abc"},
+		AuditEvent{Event: AuditPayout, PlayerID: "p1", RoomID: "room", RoundID: "r1", Amount: 25, Balance: 1012.5},
+	)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	count, err := VerifyAuditLog(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	require.Len(t, lines, 4)
+
+	var last AuditEvent
+	require.NoError(t, json.Unmarshal(lines[3], &last))
+	assert.Equal(t, uint64(4), last.Seq)
+	assert.Equal(t, AuditPayout, last.Event)
+	assert.Equal(t, 25.0, last.Amount)
+}
+
+func TestVerifyAuditLog_DetectsTampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	writeAuditEvents(t, path,
+		AuditEvent{Event: AuditBet, PlayerID: "p1", Amount: 10},
+		AuditEvent{Event: AuditPayout, PlayerID: "p1", Amount: 20},
+		AuditEvent{Event: AuditLeave, PlayerID: "p1"},
+	)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+
+	t.Run("modified", func(t *testing.T) {
+		tampered := bytes.Replace(data, []byte(`"amount":20`), []byte(`"amount":200`), 1)
+		_, err := VerifyAuditLog(bytes.NewReader(tampered))
+		assert.ErrorIs(t, err, ErrAuditChainBroken)
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		dropped := bytes.Join([][]byte{lines[0], lines[2]}, []byte("\n"))
+		_, err := VerifyAuditLog(bytes.NewReader(dropped))
+		assert.ErrorIs(t, err, ErrAuditChainBroken)
+	})
+}
+
+func TestAuditLogger_NilDiscards(t *testing.T) {
+	var audit *AuditLogger
+	audit.Record(AuditEvent{Event: AuditJoin})
+	assert.NoError(t, audit.Close())
+}
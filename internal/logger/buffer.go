@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogEntry is one log line kept by a LogBuffer
+type LogEntry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Logger  string         `json:"logger,omitempty"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// LogBuffer is a zapcore.Core that keeps the most recent log lines in
+// memory, so they can be shown without reading the log output back
+type LogBuffer struct {
+	ring   *logRing
+	level  zapcore.LevelEnabler
+	fields []zapcore.Field
+}
+
+// logRing holds the lines shared by a LogBuffer and the cores derived
+// from it with With
+type logRing struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	next    int
+	full    bool
+}
+
+// NewLogBuffer keeps the last size lines logged at level or above
+func NewLogBuffer(size int, level zapcore.LevelEnabler) *LogBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &LogBuffer{
+		ring:  &logRing{entries: make([]LogEntry, size)},
+		level: level,
+	}
+}
+
+// Attach returns a logger writing to both log and the buffer, keeping
+// log's options
+func (b *LogBuffer) Attach(log *zap.Logger) *zap.Logger {
+	return log.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, b)
+	}))
+}
+
+// Entries returns the buffered lines, oldest first
+func (b *LogBuffer) Entries() []LogEntry {
+	b.ring.mu.Lock()
+	defer b.ring.mu.Unlock()
+
+	if !b.ring.full {
+		return append([]LogEntry(nil), b.ring.entries[:b.ring.next]...)
+	}
+	entries := make([]LogEntry, 0, len(b.ring.entries))
+	entries = append(entries, b.ring.entries[b.ring.next:]...)
+	return append(entries, b.ring.entries[:b.ring.next]...)
+}
+
+// Enabled reports whether lines at the level are kept
+func (b *LogBuffer) Enabled(level zapcore.Level) bool {
+	return b.level.Enabled(level)
+}
+
+// With returns a core adding the fields to every line, sharing the buffer
+func (b *LogBuffer) With(fields []zapcore.Field) zapcore.Core {
+	return &LogBuffer{
+		ring:   b.ring,
+		level:  b.level,
+		fields: append(append([]zapcore.Field(nil), b.fields...), fields...),
+	}
+}
+
+// Check adds the buffer to the entry's cores when its level is kept
+func (b *LogBuffer) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if b.Enabled(entry.Level) {
+		return checked.AddCore(entry, b)
+	}
+	return checked
+}
+
+// Write keeps the line, replacing the oldest once the buffer is full
+func (b *LogBuffer) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range b.fields {
+		field.AddTo(encoder)
+	}
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	line := LogEntry{
+		Time:    entry.Time,
+		Level:   entry.Level.String(),
+		Logger:  entry.LoggerName,
+		Message: entry.Message,
+	}
+	if len(encoder.Fields) > 0 {
+		line.Fields = encoder.Fields
+	}
+
+	b.ring.mu.Lock()
+	defer b.ring.mu.Unlock()
+
+	b.ring.entries[b.ring.next] = line
+	b.ring.next = (b.ring.next + 1) % len(b.ring.entries)
+	if b.ring.next == 0 {
+		b.ring.full = true
+	}
+	return nil
+}
+
+// Sync does nothing, as lines are only kept in memory
+func (b *LogBuffer) Sync() error {
+	return nil
+}
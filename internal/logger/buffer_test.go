@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogBuffer_KeepsRecentLines(t *testing.T) {
+	buffer := NewLogBuffer(3, zapcore.InfoLevel)
+	log := buffer.Attach(zap.NewNop()).With(zap.String("room_id", "r1"))
+
+	log.Debug("too quiet to keep")
+	log.Info("first")
+	log.Warn("second", zap.Int("players", 2))
+	assert.Len(t, buffer.Entries(), 2)
+
+	log.Info("third")
+	log.Error("fourth")
+
+	entries := buffer.Entries()
+	require.Len(t, entries, 3)
+	messages := []string{entries[0].Message, entries[1].Message, entries[2].Message}
+	assert.Equal(t, []string{"second", "third", "fourth"}, messages, "oldest first, the first line dropped")
+	assert.Equal(t, "warn", entries[0].Level)
+	assert.Equal(t, map[string]any{"room_id": "r1", "players": int64(2)}, entries[0].Fields)
+}
@@ -0,0 +1,79 @@
+package network
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+)
+
+// ErrPlayerInRoom is returned when registering a guest who is still seated
+// in a room, whose balance would not follow them to the new account
+var ErrPlayerInRoom = errors.New("leave the room before registering")
+
+// Register upgrades a guest to a registered account, moving the balance,
+// stats, cosmetics and results the server holds for the guest. A guest the
+// server has no record of registers with an empty record, which still
+// claims the account name.
+func (s *Server) Register(ctx context.Context, guestID, account string) (*RegisterData, error) {
+	if !game.IsGuest(guestID) {
+		return nil, game.ErrNotGuest
+	}
+
+	// Holding joinMu keeps the guest from taking a seat, and inventoryMu
+	// any other change to their record, while it moves to the account
+	s.joinMu.Lock()
+	defer s.joinMu.Unlock()
+	if s.isSeated(guestID) {
+		return nil, ErrPlayerInRoom
+	}
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	if _, err := s.results.GetPlayer(ctx, guestID); err != nil {
+		if err := s.results.SavePlayer(ctx, s.playerRecord(ctx, guestID)); err != nil {
+			return nil, err
+		}
+	}
+
+	player, err := game.RegisterGuest(ctx, s.results, guestID, account)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Guest registered",
+		zap.String("guest_id", guestID),
+		zap.String("player_id", account),
+		zap.Float64("balance", player.Balance.Float64()),
+	)
+	s.config.Audit.Record(logger.AuditEvent{
+		Time:     s.scheduler.Clock().Now(),
+		Event:    logger.AuditRegister,
+		PlayerID: account,
+		Balance:  player.Balance.Float64(),
+		Reason:   "registered guest " + guestID,
+	})
+
+	stats := player.Stats
+	return &RegisterData{
+		Account: account,
+		Balance: player.Balance,
+		Stats:   &stats,
+	}, nil
+}
+
+// isSeated reports whether a player is in any of the server's rooms
+func (s *Server) isSeated(playerID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, room := range s.rooms {
+		if _, ok := room.GetPlayers()[playerID]; ok {
+			return true
+		}
+	}
+	return false
+}
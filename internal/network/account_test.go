@@ -0,0 +1,78 @@
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestServer_RegisterMigratesGuest(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+
+	guestID := game.NewGuestID()
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{
+		ID:      guestID,
+		Balance: 250 * game.Dollar,
+		Stats:   game.Stats{GamesPlayed: 3, GamesWon: 2},
+	}))
+
+	// A guest seated in a room must leave first
+	room, err := server.CreateRoom("r1", "Room 1", DefaultRoomConfig())
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer(guestID, "Guest", 250*game.Dollar))
+	_, err = server.Register(ctx, guestID, "alice")
+	assert.ErrorIs(t, err, ErrPlayerInRoom)
+	room.RemovePlayer(guestID)
+
+	registered, err := server.Register(ctx, guestID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", registered.Account)
+	assert.Equal(t, 250*game.Dollar, registered.Balance)
+	assert.Equal(t, 3, registered.Stats.GamesPlayed)
+
+	stats, err := server.PlayerStats(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.GamesWon)
+
+	// Guests the server has never seen can still claim a name, starting
+	// with the starting balance
+	registered, err = server.Register(ctx, game.NewGuestID(), "bob")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStartingBalance, registered.Balance)
+
+	_, err = server.Register(ctx, game.NewGuestID(), "alice")
+	assert.ErrorIs(t, err, game.ErrAccountExists)
+	_, err = server.Register(ctx, "alice", "carol")
+	assert.ErrorIs(t, err, game.ErrNotGuest)
+}
+
+func TestClient_RegistersOnlyItsOwnGuest(t *testing.T) {
+	server, _ := newCleanupServer(t)
+	ctx := context.Background()
+	victim, own := game.NewGuestID(), game.NewGuestID()
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: victim, Balance: 900 * game.Dollar}))
+
+	// Naming another guest on the message claims nothing of theirs
+	stranger := newCleanupClient(t, server, "")
+	stranger.handleRegister(NewMessage(MsgRegister, "", victim, RegisterData{Account: "mallory"}))
+	assert.Equal(t, []ErrorData{{Code: "register_failed", Message: ErrNotIdentified.Error()}}, sentErrors(t, stranger))
+
+	client := newCleanupClient(t, server, own)
+	client.handleRegister(NewMessage(MsgRegister, "", victim, RegisterData{Account: "mallory"}))
+	assert.Empty(t, sentErrors(t, client))
+	assert.Equal(t, "mallory", client.playerID)
+	mallory, err := server.Results().GetPlayer(ctx, "mallory")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStartingBalance, mallory.Balance)
+
+	guest, err := server.Results().GetPlayer(ctx, victim)
+	require.NoError(t, err)
+	assert.Equal(t, 900*game.Dollar, guest.Balance)
+}
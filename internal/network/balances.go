@@ -0,0 +1,185 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/storage"
+)
+
+// BalanceRestoreReport describes a point-in-time restore of player balances
+type BalanceRestoreReport struct {
+	// SnapshotFile and TakenAt name the snapshot the balances came from:
+	// the latest one taken at or before the requested time
+	SnapshotFile string    `json:"snapshot_file"`
+	TakenAt      time.Time `json:"taken_at"`
+	Players      int       `json:"players"`
+	// SeatedPlayers had their room balance moved to the restored one
+	SeatedPlayers int `json:"seated_players"`
+}
+
+// SetBalanceSnapshotter enables periodic snapshots of player balances and
+// stats. Must be called before Start.
+func (s *Server) SetBalanceSnapshotter(snapshotter *storage.BalanceSnapshotter, interval time.Duration) {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	s.balanceSnapshotter = snapshotter
+	s.balanceInterval = interval
+}
+
+// balanceSnapshotLoop periodically snapshots player balances
+func (s *Server) balanceSnapshotLoop() {
+	ticker := time.NewTicker(s.balanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.SnapshotBalances(s.ctx)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// SnapshotBalances writes every player's balance and stats to a new
+// snapshot and logs the outcome
+func (s *Server) SnapshotBalances(ctx context.Context) (*storage.BalanceSnapshotReport, error) {
+	report, err := s.balanceSnapshotter.Snapshot(ctx)
+	if err != nil {
+		s.logger.Error("Balance snapshot failed", zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Balance snapshot taken",
+		zap.Int("players", report.Players),
+		zap.String("snapshot_file", report.SnapshotFile),
+		zap.Int("purged_files", report.PurgedFiles),
+	)
+	return report, nil
+}
+
+// RestoreBalances puts every player's balance and stats back to the latest
+// snapshot taken at or before at. Players seated in a room have their room
+// balance moved to the restored one too, recorded as a credit in the audit
+// trail, so the room does not write the corrupted balance back. Seats in
+// a freeroll hold the room's stack rather than the balance, so they are
+// left alone and only the balance on record is restored. Players who
+// joined after the snapshot keep what they have.
+func (s *Server) RestoreBalances(ctx context.Context, at time.Time) (*BalanceRestoreReport, error) {
+	snapshot, path, err := s.balanceSnapshotter.Load(at)
+	if err != nil {
+		return nil, err
+	}
+
+	seated := make(map[string]*GameRoom)
+	s.mu.RLock()
+	for _, room := range s.rooms {
+		if bankrollRoom(room) == nil {
+			continue
+		}
+		for playerID := range room.GetPlayers() {
+			seated[playerID] = room
+		}
+	}
+	s.mu.RUnlock()
+
+	report := &BalanceRestoreReport{
+		SnapshotFile: path,
+		TakenAt:      snapshot.TakenAt,
+		Players:      len(snapshot.Players),
+	}
+	reason := "balance restore to " + snapshot.TakenAt.Format(time.RFC3339)
+	for _, record := range snapshot.Players {
+		room, ok := seated[record.PlayerID]
+		if !ok {
+			continue
+		}
+		balance, ok := room.PlayerBalance(record.PlayerID)
+		if !ok {
+			continue
+		}
+		if _, err := room.Credit(record.PlayerID, record.Balance-balance, reason); err != nil && !errors.Is(err, ErrPlayerNotFound) {
+			return nil, err
+		}
+		report.SeatedPlayers++
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+	if err := s.results.RestoreBalances(ctx, snapshot.Players); err != nil {
+		return nil, err
+	}
+
+	s.logger.Warn("Player balances restored",
+		zap.Time("taken_at", snapshot.TakenAt),
+		zap.String("snapshot_file", path),
+		zap.Int("players", report.Players),
+		zap.Int("seated_players", report.SeatedPlayers),
+	)
+	return report, nil
+}
+
+// handleSnapshotBalances takes a balance snapshot at once
+func (s *Server) handleSnapshotBalances(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !s.balanceSnapshotsEnabled(w) {
+		return
+	}
+
+	report, err := s.SnapshotBalances(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorData{Code: "snapshot_failed", Message: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleRestoreBalances restores balances as they were at the time in the
+// at query parameter, in RFC 3339
+func (s *Server) handleRestoreBalances(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !s.balanceSnapshotsEnabled(w) {
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, r.URL.Query().Get("at"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{Code: "invalid_time", Message: "at must be an RFC 3339 time"})
+		return
+	}
+
+	report, err := s.RestoreBalances(r.Context(), at)
+	switch {
+	case errors.Is(err, storage.ErrNoBalanceSnapshot):
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorData{Code: "snapshot_not_found", Message: err.Error()})
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorData{Code: "restore_failed", Message: err.Error()})
+	default:
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+// balanceSnapshotsEnabled answers that snapshots are off when the server
+// takes none, reporting whether they are on
+func (s *Server) balanceSnapshotsEnabled(w http.ResponseWriter) bool {
+	if s.balanceSnapshotter != nil {
+		return true
+	}
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(ErrorData{
+		Code:    "snapshots_disabled",
+		Message: "balance snapshots are not enabled on this server",
+	})
+	return false
+}
@@ -0,0 +1,80 @@
+package network
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+func TestServer_RestoreBalances(t *testing.T) {
+	server, _ := newCleanupServer(t)
+	ctx := context.Background()
+	handler := server.Handler()
+	post := func(path string) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, adminAPIRequest(http.MethodPost, path, ""))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, post("/admin/balances/snapshot"))
+
+	// Only administrators may snapshot or roll back balances
+	for _, path := range []string{"/admin/balances/snapshot", "/admin/balances/restore?at=2000-01-01T00:00:00Z"} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, path)
+	}
+	server.SetBalanceSnapshotter(storage.NewBalanceSnapshotter(server.Results(), storage.BalanceSnapshotConfig{Directory: t.TempDir()}), time.Hour)
+
+	room, err := server.CreateRoom("r1", "Room 1", nil)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("seated", "Seated", 100*game.Dollar))
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "seated", Balance: 100 * game.Dollar}))
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "away", Balance: 50 * game.Dollar}))
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "freerolling", Balance: 500 * game.Dollar}))
+	freeroll := newFreerollRoom(t, server)
+	balance, err := server.JoinBalance(ctx, "freerolling", freeroll)
+	require.NoError(t, err)
+	require.NoError(t, freeroll.AddPlayer("freerolling", "Freerolling", balance))
+	require.Equal(t, http.StatusOK, post("/admin/balances/snapshot"))
+
+	// A bug corrupts both balances
+	_, err = room.Credit("seated", -70*game.Dollar, "bug")
+	require.NoError(t, err)
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "away", Balance: 5 * game.Dollar}))
+
+	assert.Equal(t, http.StatusBadRequest, post("/admin/balances/restore?at=yesterday"))
+	assert.Equal(t, http.StatusNotFound, post("/admin/balances/restore?at=2000-01-01T00:00:00Z"))
+
+	report, err := server.RestoreBalances(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Players)
+	assert.Equal(t, 1, report.SeatedPlayers)
+
+	// A freeroll stack is not the balance on record, so restoring leaves
+	// it alone and leaving pays out only what was won on it
+	stack, ok := freeroll.PlayerBalance("freerolling")
+	require.True(t, ok)
+	assert.Equal(t, 100*game.Dollar, stack)
+	left, err := freeroll.removePlayer("freerolling")
+	require.NoError(t, err)
+	server.storeBalance(ctx, "freerolling", freeroll, left)
+	freerolling, err := server.Results().GetPlayer(ctx, "freerolling")
+	require.NoError(t, err)
+	assert.Equal(t, 500*game.Dollar, freerolling.Balance)
+
+	balance, ok = room.PlayerBalance("seated")
+	require.True(t, ok)
+	assert.Equal(t, 100*game.Dollar, balance)
+	away, err := server.Results().GetPlayer(ctx, "away")
+	require.NoError(t, err)
+	assert.Equal(t, 50*game.Dollar, away.Balance)
+}
@@ -0,0 +1,208 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+// Bankroll keeps a player's balance on a local engine, used for play
+// against the computer, in step with their wallet on the server, so one
+// bankroll follows them between offline and online play. Offline play is
+// kept in a wallet cache and reconciled with the server whenever it can be
+// reached.
+type Bankroll struct {
+	mu       sync.Mutex
+	engine   *game.Engine
+	cache    *storage.WalletCache
+	client   *ClientConfig
+	playerID string
+	logger   *zap.Logger
+}
+
+// NewBankroll links the player's balance on engine to their server wallet.
+// client is the connection used to reach the server.
+func NewBankroll(engine *game.Engine, cache *storage.WalletCache, client *ClientConfig, playerID string, logger *zap.Logger) *Bankroll {
+	return &Bankroll{
+		engine:   engine,
+		cache:    cache,
+		client:   client,
+		playerID: playerID,
+		logger:   logger,
+	}
+}
+
+// PlayerID returns the player whose bankroll this is
+func (b *Bankroll) PlayerID() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.playerID
+}
+
+// SetPlayer moves the bankroll to a new player ID, such as when a guest
+// registers an account, keeping any offline play not yet synced
+func (b *Bankroll) SetPlayer(playerID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if playerID == b.playerID {
+		return nil
+	}
+	previous := b.playerID
+	b.playerID = playerID
+	return b.cache.Rename(previous, playerID)
+}
+
+// Restore sets the engine's balance to the one cached when the player last
+// played, so a new run carries on where the last one stopped
+func (b *Bankroll) Restore(ctx context.Context) (game.Money, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry, exists := b.cache.Get(b.playerID)
+	if !exists {
+		entry, err := b.record(ctx)
+		return entry.Balance, err
+	}
+
+	player, err := b.engine.GetPlayer(ctx, b.playerID)
+	if err != nil {
+		return 0, err
+	}
+	player, err = b.engine.AdjustBalance(ctx, b.playerID, entry.Balance-player.Balance, "restored offline balance")
+	if err != nil {
+		return 0, err
+	}
+	return player.Balance, nil
+}
+
+// Record caches the engine's balance after offline play, to be synced
+// with the server later
+func (b *Bankroll) Record(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, err := b.record(ctx)
+	return err
+}
+
+// Pending returns what offline play changed the balance by since the
+// last sync
+func (b *Bankroll) Pending() game.Money {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry, _ := b.cache.Get(b.playerID)
+	return entry.Pending()
+}
+
+// Sync sends offline play to the server and brings the server's balance
+// to the engine. Play on the engine while the sync is under way is kept
+// and sent with the next one. When the server cannot be reached the play
+// stays cached and the error is returned.
+func (b *Bankroll) Sync(ctx context.Context) (*WalletSyncData, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry, err := b.record(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	synced, err := RequestWalletSync(ctx, b.client, b.playerID, entry.Pending(), b.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := b.engine.AdjustBalance(ctx, b.playerID, synced.Balance-entry.Balance, "wallet sync")
+	if err != nil {
+		return nil, err
+	}
+	entry = storage.WalletEntry{
+		Balance:  player.Balance,
+		Synced:   synced.Balance,
+		SyncedAt: time.Now(),
+	}
+	if err := b.cache.Put(b.playerID, entry); err != nil {
+		b.logger.Warn("Failed to cache synced balance", zap.Error(err))
+	}
+	return synced, nil
+}
+
+// record caches the engine's balance and returns the entry. Callers must
+// hold b.mu.
+func (b *Bankroll) record(ctx context.Context) (storage.WalletEntry, error) {
+	player, err := b.engine.GetPlayer(ctx, b.playerID)
+	if err != nil {
+		return storage.WalletEntry{}, err
+	}
+
+	entry, exists := b.cache.Get(b.playerID)
+	if !exists {
+		// The server opens new wallets with the same starting balance
+		entry.Synced = b.engine.GetConfig().StartingBalance
+	}
+	entry.Balance = player.Balance
+	if err := b.cache.Put(b.playerID, entry); err != nil {
+		return storage.WalletEntry{}, err
+	}
+	return entry, nil
+}
+
+// RequestWalletSync connects to the server just long enough to send a
+// player's offline net change and returns the server's reply. It returns
+// ErrWalletInUse while the player is seated in a room.
+func RequestWalletSync(ctx context.Context, config *ClientConfig, playerID string, net game.Money, logger *zap.Logger) (*WalletSyncData, error) {
+	oneShot := *config
+	oneShot.MaxReconnects = 0
+
+	client := NewNetworkClient(&oneShot, playerID, playerID, logger)
+	client.SetTraceContext(ctx)
+	if err := client.Connect(); err != nil {
+		return nil, err
+	}
+	defer client.Disconnect()
+
+	if err := client.SyncWallet(net); err != nil {
+		return nil, err
+	}
+
+	events := client.GetEventChannel()
+	errs := client.GetErrorChannel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for the server to sync the wallet: %w", ctx.Err())
+
+		case err := <-errs:
+			return nil, fmt.Errorf("multiplayer connection failed: %w", err)
+
+		case msg := <-events:
+			switch msg.Type {
+			case MsgError:
+				var errorData ErrorData
+				if err := msg.GetData(&errorData); err != nil {
+					return nil, errors.New("server refused the wallet sync")
+				}
+				if errorData.Code == "wallet_busy" {
+					return nil, ErrWalletInUse
+				}
+				return nil, fmt.Errorf("server refused the wallet sync: %s", errorData.Message)
+
+			case MsgWalletSync:
+				var synced WalletSyncData
+				if err := msg.GetData(&synced); err != nil {
+					return nil, fmt.Errorf("invalid wallet sync response: %w", err)
+				}
+				return &synced, nil
+			}
+		}
+	}
+}
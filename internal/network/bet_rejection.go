@@ -0,0 +1,120 @@
+package network
+
+import (
+	"errors"
+
+	"coinflip-game/internal/game"
+)
+
+// Reasons a bet is refused, sent in BetRejection so clients can explain
+// the refusal and suggest a fix
+const (
+	RejectInsufficientBalance = "insufficient_balance"
+	RejectBettingClosed       = "betting_closed"
+	RejectBetAmount           = "bet_amount"
+	RejectBetLimit            = "bet_limit"
+	RejectAlreadyBet          = "already_bet"
+	RejectNoBet               = "no_bet"
+	RejectInsurance           = "insurance_unavailable"
+	RejectPracticeMixed       = "practice_mixed"
+)
+
+// betRejections is the catalog of bet errors and the reasons they are sent
+// as, checked in order with errors.Is
+var betRejections = []struct {
+	err    error
+	reason string
+}{
+	{game.ErrInsufficientBalance, RejectInsufficientBalance},
+	{ErrBettingClosed, RejectBettingClosed},
+	{ErrInvalidGamePhase, RejectBettingClosed},
+	{ErrNoActiveRound, RejectBettingClosed},
+	{game.ErrInvalidBetAmount, RejectBetAmount},
+	{ErrBetLimit, RejectBetLimit},
+	{ErrPlayerAlreadyBet, RejectAlreadyBet},
+	{ErrNoBetToCancel, RejectNoBet},
+	{game.ErrInsuranceUnavailable, RejectInsurance},
+	{game.ErrPracticeInsured, RejectInsurance},
+	{ErrPracticeMixed, RejectPracticeMixed},
+}
+
+// BetRejection says why the room refused a bet, sent with bet errors.
+// MinAmount and MaxAmount are the stakes the room takes; for a bet over
+// the player's balance or the room's limits, MaxAmount is the largest
+// stake that would have been accepted instead, zero when none would.
+type BetRejection struct {
+	Reason    string     `json:"reason"`
+	Amount    game.Money `json:"amount,omitempty"`
+	MinAmount game.Money `json:"min_amount"`
+	MaxAmount game.Money `json:"max_amount"`
+}
+
+// RejectionReason returns the catalog's reason for a bet error, or "" for
+// an error it does not list
+func RejectionReason(err error) string {
+	for _, rejection := range betRejections {
+		if errors.Is(err, rejection.err) {
+			return rejection.reason
+		}
+	}
+	return ""
+}
+
+// BetRejection explains why the room refused the player's bet with err,
+// working out the stake that would fit where one would. bet is the
+// refused bet, or nil when the error covers several.
+func (r *GameRoom) BetRejection(playerID string, bet *BetData, err error) *BetRejection {
+	reason := RejectionReason(err)
+	if reason == "" {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rejection := &BetRejection{
+		Reason:    reason,
+		MinAmount: r.config.MinBet,
+		MaxAmount: r.config.MaxBet,
+	}
+	player, seated := r.players[playerID]
+	if bet == nil || !seated {
+		return rejection
+	}
+	rejection.Amount = bet.Amount
+
+	candidate := &BetData{PlayerID: playerID, Choice: bet.Choice, Insured: bet.Insured}
+	switch reason {
+	case RejectInsufficientBalance:
+		rejection.MaxAmount = largestStake(r.config.MinBet, r.config.MaxBet, func(stake game.Money) bool {
+			r.setStake(candidate, stake)
+			return betCost(candidate) <= player.Balance
+		})
+	case RejectBetLimit:
+		if r.currentRound == nil {
+			break
+		}
+		rejection.MaxAmount = largestStake(r.config.MinBet, r.config.MaxBet, func(stake game.Money) bool {
+			r.setStake(candidate, stake)
+			return r.checkLimits(candidate) == nil
+		})
+	}
+	return rejection
+}
+
+// largestStake finds the largest stake from low to high, in cents, that
+// fits, or zero when none does. A stake that fits must mean every smaller
+// one does too.
+func largestStake(low, high game.Money, fits func(stake game.Money) bool) game.Money {
+	largest := game.Money(0)
+	for low <= high {
+		stake := low + (high-low)/2
+		if fits(stake) {
+			largest = stake
+			low = stake + game.Cent
+		} else {
+			high = stake - game.Cent
+		}
+	}
+	return largest
+}
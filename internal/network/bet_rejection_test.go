@@ -0,0 +1,75 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+func TestRejectionReason(t *testing.T) {
+	assert.Equal(t, RejectInsufficientBalance, RejectionReason(game.ErrInsufficientBalance))
+	assert.Equal(t, RejectBetLimit, RejectionReason(fmt.Errorf("%w: the pot would reach $60.00", ErrBetLimit)))
+	assert.Equal(t, RejectBetAmount, RejectionReason(fmt.Errorf("bet 2: %w", game.ErrInvalidBetAmount)))
+	assert.Equal(t, RejectBettingClosed, RejectionReason(ErrBettingClosed))
+	assert.Empty(t, RejectionReason(ErrRoomFull))
+}
+
+func TestGameRoom_BetRejection(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	room.config.Limits.MaxPot = 50 * game.Dollar
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 40*game.Dollar))
+	require.NoError(t, room.PlaceBet("p1", 30*game.Dollar, game.Heads))
+
+	// The pot has $20 left
+	bet := &BetData{Amount: 30 * game.Dollar, Choice: game.Tails}
+	err := room.PlaceBet("p2", bet.Amount, bet.Choice)
+	require.ErrorIs(t, err, ErrBetLimit)
+	assert.Equal(t, &BetRejection{
+		Reason:    RejectBetLimit,
+		Amount:    30 * game.Dollar,
+		MinAmount: game.Dollar,
+		MaxAmount: 20 * game.Dollar,
+	}, room.BetRejection("p2", bet, err))
+
+	// The balance covers $40
+	room.config.Limits.MaxPot = 0
+	bet.Amount = 60 * game.Dollar
+	err = room.PlaceBet("p2", bet.Amount, bet.Choice)
+	require.ErrorIs(t, err, game.ErrInsufficientBalance)
+	rejection := room.BetRejection("p2", bet, err)
+	assert.Equal(t, RejectInsufficientBalance, rejection.Reason)
+	assert.Equal(t, 40*game.Dollar, rejection.MaxAmount)
+
+	// Errors covering several bets give the room's range only
+	rejection = room.BetRejection("p2", nil, fmt.Errorf("bet 2: %w", game.ErrInvalidBetAmount))
+	assert.Equal(t, &BetRejection{Reason: RejectBetAmount, MinAmount: game.Dollar, MaxAmount: 100 * game.Dollar}, rejection)
+	assert.Nil(t, room.BetRejection("p2", bet, ErrRoomFull))
+}
+
+func TestClient_SendsBetRejection(t *testing.T) {
+	server, fake := newCleanupServer(t)
+	config := DefaultRoomConfig()
+	config.MinPlayers = 1
+	room, err := server.CreateRoom("r1", "Room 1", config)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	server.scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+	client := &Client{server: server, send: make(chan []byte, 1), protocol: ProtocolVersion, playerID: "p1", room: room}
+
+	client.handlePlaceBet(NewMessage(MsgBetPlaced, room.ID(), "p1", BetData{Amount: 500 * game.Dollar, Choice: game.Heads}))
+	var msg Message
+	require.NoError(t, json.Unmarshal(<-client.send, &msg))
+	var reply ErrorData
+	require.NoError(t, msg.GetData(&reply))
+	assert.Equal(t, "bet_failed", reply.Code)
+	require.NotNil(t, reply.Rejection)
+	assert.Equal(t, RejectBetAmount, reply.Rejection.Reason)
+	assert.Equal(t, 500*game.Dollar, reply.Rejection.Amount)
+	assert.Equal(t, 100*game.Dollar, reply.Rejection.MaxAmount)
+}
@@ -0,0 +1,168 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+)
+
+// Bet slip errors
+var (
+	ErrNoBetSlip      = errors.New("player has no bet slip")
+	ErrBetSlipTooLong = fmt.Errorf("a bet slip holds at most %d bets", MaxBetSlipBets)
+)
+
+// BetSlipExposure returns what a slip's bets stake in all, with the
+// premiums of insured bets at the given insurance
+func BetSlipExposure(bets []BetData, insurance game.Insurance) game.Money {
+	var total game.Money
+	for _, bet := range bets {
+		total += bet.Amount
+		if bet.Insured {
+			total += insurance.Premium(bet.Amount)
+		}
+	}
+	return total
+}
+
+// SetBetSlip stages a bet for each of the next rounds, replacing the
+// player's slip; no bets clears it. The first bet is placed when the next
+// betting phase opens, one bet a round after that. Every bet is checked
+// against the room limits now, and the whole slip against the player's
+// balance, and each again when it is placed.
+func (r *GameRoom) SetBetSlip(playerID string, bets []BetData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.players[playerID]
+	if !exists {
+		return ErrPlayerNotFound
+	}
+	if len(bets) == 0 {
+		return r.cancelBetSlip(playerID)
+	}
+	if len(bets) > MaxBetSlipBets {
+		return ErrBetSlipTooLong
+	}
+
+	slip := make([]*BetData, len(bets))
+	for i, bet := range bets {
+		if bet.Amount < r.config.MinBet || bet.Amount > r.config.MaxBet {
+			return fmt.Errorf("bet %d: %w", i+1, game.ErrInvalidBetAmount)
+		}
+		if !bet.Choice.IsValid() {
+			return fmt.Errorf("bet %d: %w", i+1, game.ErrInvalidChoice)
+		}
+		if bet.Insured && !r.config.Insurance.Enabled() {
+			return fmt.Errorf("bet %d: %w", i+1, game.ErrInsuranceUnavailable)
+		}
+		slip[i] = &BetData{PlayerID: playerID, Amount: bet.Amount, Choice: bet.Choice, Insured: bet.Insured}
+	}
+	if player.Balance < BetSlipExposure(bets, r.config.Insurance) {
+		return game.ErrInsufficientBalance
+	}
+
+	r.betSlips[playerID] = slip
+	r.lastActivity = r.clock.Now()
+
+	r.logger.Info("Bet slip staged",
+		zap.String("room_id", r.id),
+		zap.String("player_id", playerID),
+		zap.Int("bets", len(slip)),
+	)
+	r.broadcastBetSlip(playerID, nil, QueuedBetWaiting, "")
+	r.markActive(player)
+	return nil
+}
+
+// CancelBetSlip withdraws the bets a player staged for the next rounds
+func (r *GameRoom) CancelBetSlip(playerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cancelBetSlip(playerID)
+}
+
+// cancelBetSlip withdraws a player's slip. Callers must hold r.mu.
+func (r *GameRoom) cancelBetSlip(playerID string) error {
+	if _, exists := r.betSlips[playerID]; !exists {
+		return ErrNoBetSlip
+	}
+	delete(r.betSlips, playerID)
+
+	r.broadcastBetSlip(playerID, nil, QueuedBetWithdrawn, "")
+	return nil
+}
+
+// BetSlip returns the bets a player has still to come on their slip, the
+// next one first
+func (r *GameRoom) BetSlip(playerID string) []BetData {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.slipBets(playerID)
+}
+
+// slipBets copies a player's slip. Callers must hold r.mu.
+func (r *GameRoom) slipBets(playerID string) []BetData {
+	slip := r.betSlips[playerID]
+	bets := make([]BetData, len(slip))
+	for i, bet := range slip {
+		bets[i] = *bet
+	}
+	return bets
+}
+
+// placeSlipBets places the next bet of every slip as betting opens. A
+// player whose queued bet was just placed keeps their slip for the next
+// round. A bet the round refuses drops the rest of the slip, since the
+// bets after it were staged expecting it. Callers must hold r.mu.
+func (r *GameRoom) placeSlipBets() {
+	playerIDs := make([]string, 0, len(r.betSlips))
+	for playerID := range r.betSlips {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Strings(playerIDs)
+
+	for _, playerID := range playerIDs {
+		if len(r.currentRound.Bets[playerID]) > 0 {
+			continue
+		}
+
+		slip := r.betSlips[playerID]
+		bet := slip[0]
+		if len(slip) == 1 {
+			delete(r.betSlips, playerID)
+		} else {
+			r.betSlips[playerID] = slices.Clone(slip[1:])
+		}
+
+		if err := r.placeBet(playerID, bet.Amount, bet.Choice, bet.Insured, false, r.clock.Now()); err != nil {
+			r.logger.Info("Bet slip bet rejected",
+				zap.String("room_id", r.id),
+				zap.String("player_id", playerID),
+				zap.Error(err),
+			)
+			delete(r.betSlips, playerID)
+			r.broadcastBetSlip(playerID, bet, QueuedBetFailed, err.Error())
+			continue
+		}
+		r.broadcastBetSlip(playerID, r.placedBet(bet), QueuedBetPlaced, "")
+	}
+}
+
+// broadcastBetSlip announces a change to a player's slip, with the bet
+// just placed or refused if any
+func (r *GameRoom) broadcastBetSlip(playerID string, bet *BetData, status QueuedBetStatus, reason string) {
+	r.broadcastMessage(NewMessage(MsgBetSlip, r.id, playerID, &BetSlipData{
+		Bets:   r.slipBets(playerID),
+		Bet:    bet,
+		Status: status,
+		Reason: r.localize(reason),
+	}))
+}
@@ -0,0 +1,148 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+)
+
+// nextRound plays the room's open betting phase out and opens the next one
+func nextRound(t *testing.T, room *GameRoom, scheduler *TimerScheduler, fake *clock.Fake) {
+	t.Helper()
+	fake.Advance(room.config.BettingDuration)
+	scheduler.advance(fake.Now())
+	fake.Advance(room.config.ResultDuration)
+	scheduler.advance(fake.Now())
+	fake.Advance(RoundBreakDuration)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+}
+
+// lastBetSlip returns the last change to the player's slip the room sent
+func lastBetSlip(room *GameRoom, playerID string) *BetSlipData {
+	var last *BetSlipData
+	for _, msg := range drainEvents(room) {
+		if msg.Type == MsgBetSlip && msg.PlayerID == playerID {
+			last = msg.Data.(*BetSlipData)
+		}
+	}
+	return last
+}
+
+func TestGameRoom_SetBetSlipChecks(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	heads := BetData{Amount: 10 * game.Dollar, Choice: game.Heads}
+
+	tooMany := make([]BetData, MaxBetSlipBets+1)
+	for i := range tooMany {
+		tooMany[i] = heads
+	}
+	assert.ErrorIs(t, room.SetBetSlip("p1", tooMany), ErrBetSlipTooLong)
+	assert.ErrorIs(t, room.SetBetSlip("p1", []BetData{heads, {Amount: 500 * game.Dollar, Choice: game.Tails}}), game.ErrInvalidBetAmount)
+	assert.ErrorIs(t, room.SetBetSlip("p1", []BetData{{Amount: 10 * game.Dollar, Choice: game.Heads, Insured: true}}), game.ErrInsuranceUnavailable)
+	assert.ErrorIs(t, room.SetBetSlip("p2", []BetData{heads}), ErrPlayerNotFound)
+
+	// The whole slip must be covered by the balance
+	big := BetData{Amount: 60 * game.Dollar, Choice: game.Tails}
+	assert.ErrorIs(t, room.SetBetSlip("p1", []BetData{big, big}), game.ErrInsufficientBalance)
+	assert.Empty(t, room.BetSlip("p1"))
+
+	require.NoError(t, room.SetBetSlip("p1", []BetData{heads, big}))
+	assert.Len(t, room.BetSlip("p1"), 2)
+	slip := lastBetSlip(room, "p1")
+	require.NotNil(t, slip)
+	assert.Equal(t, QueuedBetWaiting, slip.Status)
+
+	// No bets clears the slip
+	require.NoError(t, room.SetBetSlip("p1", nil))
+	assert.Empty(t, room.BetSlip("p1"))
+	assert.Equal(t, QueuedBetWithdrawn, lastBetSlip(room, "p1").Status)
+	assert.ErrorIs(t, room.CancelBetSlip("p1"), ErrNoBetSlip)
+}
+
+func TestGameRoom_BetSlipPlacesOneBetARound(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.SetRandomGenerator(&fixedCoin{side: game.Heads, seed: "seed-1"})
+
+	// Betting is open, but the slip starts with the next round
+	require.NoError(t, room.SetBetSlip("p1", []BetData{
+		{Amount: 10 * game.Dollar, Choice: game.Heads},
+		{Amount: 20 * game.Dollar, Choice: game.Tails},
+		{Amount: 5 * game.Dollar, Choice: game.Heads},
+	}))
+	require.NoError(t, room.PlaceBet("p1", 1*game.Dollar, game.Heads))
+	drainEvents(room)
+
+	nextRound(t, room, scheduler, fake)
+	bets := room.GetPlayers()["p1"].CurrentBets
+	require.Len(t, bets, 1)
+	assert.Equal(t, 10*game.Dollar, bets[0].Amount)
+	assert.Equal(t, game.Heads, bets[0].Choice)
+	slip := lastBetSlip(room, "p1")
+	require.NotNil(t, slip)
+	assert.Equal(t, QueuedBetPlaced, slip.Status)
+	assert.Equal(t, 10*game.Dollar, slip.Bet.Amount)
+	assert.Len(t, slip.Bets, 2)
+
+	nextRound(t, room, scheduler, fake)
+	bets = room.GetPlayers()["p1"].CurrentBets
+	require.Len(t, bets, 1)
+	assert.Equal(t, game.Tails, bets[0].Choice)
+
+	// A bet queued for a round comes first, and the slip waits a round
+	fake.Advance(room.config.BettingDuration)
+	scheduler.advance(fake.Now())
+	require.NoError(t, room.QueueBet("p1", 2*game.Dollar, game.Tails))
+	fake.Advance(room.config.ResultDuration)
+	scheduler.advance(fake.Now())
+	fake.Advance(RoundBreakDuration)
+	scheduler.advance(fake.Now())
+	bets = room.GetPlayers()["p1"].CurrentBets
+	require.Len(t, bets, 1)
+	assert.Equal(t, 2*game.Dollar, bets[0].Amount)
+	assert.Len(t, room.BetSlip("p1"), 1)
+
+	nextRound(t, room, scheduler, fake)
+	bets = room.GetPlayers()["p1"].CurrentBets
+	require.Len(t, bets, 1)
+	assert.Equal(t, 5*game.Dollar, bets[0].Amount)
+	assert.Empty(t, room.BetSlip("p1"))
+}
+
+func TestGameRoom_BetSlipDroppedWhenBetRefused(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.SetRandomGenerator(&fixedCoin{side: game.Heads, seed: "seed-1"})
+
+	require.NoError(t, room.SetBetSlip("p1", []BetData{
+		{Amount: 90 * game.Dollar, Choice: game.Heads},
+		{Amount: 5 * game.Dollar, Choice: game.Heads},
+	}))
+
+	// Losing this round leaves too little for the slip's first bet
+	require.NoError(t, room.PlaceBet("p1", 20*game.Dollar, game.Tails))
+	drainEvents(room)
+	nextRound(t, room, scheduler, fake)
+
+	assert.Empty(t, room.GetPlayers()["p1"].CurrentBets)
+	assert.Empty(t, room.BetSlip("p1"), "the rest of the slip is dropped")
+	slip := lastBetSlip(room, "p1")
+	require.NotNil(t, slip)
+	assert.Equal(t, QueuedBetFailed, slip.Status)
+	assert.Equal(t, 90*game.Dollar, slip.Bet.Amount)
+	assert.NotEmpty(t, slip.Reason)
+	assert.Empty(t, slip.Bets)
+}
+
+func TestBetSlipExposure(t *testing.T) {
+	insurance := game.Insurance{Cost: 0.1, Coverage: 0.5}
+	bets := []BetData{
+		{Amount: 10 * game.Dollar, Choice: game.Heads},
+		{Amount: 20 * game.Dollar, Choice: game.Tails, Insured: true},
+	}
+	assert.Equal(t, 32*game.Dollar, BetSlipExposure(bets, insurance))
+	assert.Equal(t, game.Money(0), BetSlipExposure(nil, insurance))
+}
@@ -0,0 +1,223 @@
+package network
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultUpdateInterval is how long a room gathers player changes before
+// broadcasting them as one update
+const DefaultUpdateInterval = 100 * time.Millisecond
+
+// fullUpdateEvery sends a complete room update after this many deltas, so a
+// client that missed one catches up
+const fullUpdateEvery = 20
+
+// equal reports whether two snapshots of a player are the same
+func (p PlayerInfo) equal(other PlayerInfo) bool {
+	return p.ID == other.ID &&
+		p.Name == other.Name &&
+		p.Balance == other.Balance &&
+		p.IsReady == other.IsReady &&
+		p.HasBet == other.HasBet &&
+		slices.Equal(p.Bets, other.Bets) &&
+		p.IsOnline == other.IsOnline &&
+		p.WinStreak == other.WinStreak &&
+		p.Skin == other.Skin &&
+		p.LatencyMs == other.LatencyMs &&
+		p.Idle == other.Idle
+}
+
+// updateKey is the scheduler key of the room's coalesced update
+func (r *GameRoom) updateKey() string {
+	return r.id + "#update"
+}
+
+// broadcastRoomUpdate sends room state to all players. Player changes are
+// coalesced over the room's UpdateInterval; a change of game state goes out
+// at once, with anything pending, so it never trails the phase messages.
+// Callers must hold r.mu.
+func (r *GameRoom) broadcastRoomUpdate() {
+	if r.config.UpdateInterval <= 0 || r.gameState != r.sentState {
+		r.flushRoomUpdate()
+		return
+	}
+	if r.updatePending {
+		return
+	}
+
+	r.updatePending = true
+	r.scheduler.Schedule(r.updateKey(), r.clock.Now().Add(r.config.UpdateInterval), nil, r.flushPendingUpdate)
+}
+
+// flushPendingUpdate sends the coalesced room update when it falls due
+func (r *GameRoom) flushPendingUpdate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	select {
+	case <-r.stopChan:
+		return
+	default:
+	}
+	if r.updatePending {
+		r.flushRoomUpdate()
+	}
+}
+
+// flushRoomUpdate sends the players that changed since the last update, or
+// every player when a full update is due. Callers must hold r.mu.
+func (r *GameRoom) flushRoomUpdate() {
+	if r.updatePending {
+		r.updatePending = false
+		r.scheduler.Cancel(r.updateKey())
+	}
+
+	updateData, current := r.roomUpdate()
+	players := updateData.Players
+	full := r.sentPlayers == nil || r.sinceFull >= fullUpdateEvery
+	if !full {
+		updateData.Delta = true
+		updateData.full = players
+		updateData.Players = make([]PlayerInfo, 0)
+		for _, info := range players {
+			if sent, ok := r.sentPlayers[info.ID]; !ok || !sent.equal(info) {
+				updateData.Players = append(updateData.Players, info)
+			}
+		}
+		for id := range r.sentPlayers {
+			if _, ok := current[id]; !ok {
+				updateData.Removed = append(updateData.Removed, id)
+			}
+		}
+	}
+
+	if !r.broadcastMessage(NewMessage(MsgRoomUpdate, r.id, "", updateData)) {
+		// Clients missed this update, so the next one must be complete
+		r.sentPlayers = nil
+		return
+	}
+	r.sentPlayers = current
+	r.sentState = r.gameState
+	if full {
+		r.sinceFull = 0
+	} else {
+		r.sinceFull++
+	}
+}
+
+// roomUpdate returns the room's state as a complete update, with its
+// players by ID. Callers must hold r.mu.
+func (r *GameRoom) roomUpdate() (*RoomUpdateData, map[string]PlayerInfo) {
+	players := make([]PlayerInfo, 0, len(r.players))
+	current := make(map[string]PlayerInfo, len(r.players))
+	for _, player := range r.players {
+		info := PlayerInfo{
+			ID:        player.ID,
+			Name:      player.Name,
+			Balance:   player.Balance,
+			IsReady:   player.IsReady,
+			HasBet:    len(player.CurrentBets) > 0,
+			Bets:      copyBets(player.CurrentBets),
+			IsOnline:  player.IsOnline,
+			WinStreak: player.WinStreak,
+			Skin:      player.Skin,
+			LatencyMs: player.Latency.Milliseconds(),
+			Idle:      player.Idle,
+			Rating:    player.Rating,
+		}
+		players = append(players, info)
+		current[player.ID] = info
+	}
+
+	updateData := &RoomUpdateData{
+		RoomID:      r.id,
+		Players:     players,
+		GameState:   r.gameState,
+		Timer:       int(r.timerEnd.Sub(r.clock.Now()).Seconds()),
+		MinPlayers:  r.config.MinPlayers,
+		MaxPlayers:  r.config.MaxPlayers,
+		PayoutRatio: r.config.PayoutRatio,
+		Owner:       r.owner,
+		PausedBy:    r.pausedBy,
+		SeatQueue:   slices.Clone(r.seatQueue),
+	}
+	if r.gameState == StatePaused {
+		updateData.Timer = int(r.pausedRemaining.Seconds())
+	}
+	if r.config.Insurance.Enabled() {
+		insurance := r.config.Insurance
+		updateData.Insurance = &insurance
+	}
+	if r.promotion != nil {
+		promotion := *r.promotion
+		updateData.Promotion = &promotion
+	}
+	return updateData, current
+}
+
+// fullRoomUpdate returns a delta room update as the complete update it
+// stands for
+func (m *Message) fullRoomUpdate() *Message {
+	data, ok := m.Data.(*RoomUpdateData)
+	if !ok || !data.Delta {
+		return m
+	}
+
+	full := *data
+	full.Players = data.full
+	full.Delta = false
+	full.Removed = nil
+	shimmed := *m
+	shimmed.Data = &full
+	return &shimmed
+}
+
+// roomView rebuilds complete room updates from the deltas a client
+// receives
+type roomView struct {
+	mu      sync.Mutex
+	roomID  string
+	players []PlayerInfo
+}
+
+// apply merges a room update into the view and returns it as a complete
+// update. Full updates replace the view.
+func (v *roomView) apply(update *RoomUpdateData) *RoomUpdateData {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if !update.Delta || update.RoomID != v.roomID {
+		v.roomID = update.RoomID
+		v.players = slices.Clone(update.Players)
+		return update
+	}
+
+	for _, changed := range update.Players {
+		index := slices.IndexFunc(v.players, func(p PlayerInfo) bool { return p.ID == changed.ID })
+		if index >= 0 {
+			v.players[index] = changed
+		} else {
+			v.players = append(v.players, changed)
+		}
+	}
+	v.players = slices.DeleteFunc(v.players, func(p PlayerInfo) bool {
+		return slices.Contains(update.Removed, p.ID)
+	})
+
+	merged := *update
+	merged.Players = slices.Clone(v.players)
+	merged.Delta = false
+	merged.Removed = nil
+	return &merged
+}
+
+// reset forgets the room, such as after leaving it or losing the connection
+func (v *roomView) reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.roomID = ""
+	v.players = nil
+}
@@ -0,0 +1,160 @@
+package network
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+// roomUpdates returns the room updates among the room's pending events
+func roomUpdates(room *GameRoom) []*RoomUpdateData {
+	var updates []*RoomUpdateData
+	for _, message := range drainEvents(room) {
+		if data, ok := message.Data.(*RoomUpdateData); ok {
+			updates = append(updates, data)
+		}
+	}
+	return updates
+}
+
+func TestGameRoom_CoalescesDeltaUpdates(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	drainEvents(room)
+
+	// A newcomer is sent every player
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	fake.Advance(DefaultUpdateInterval)
+	scheduler.advance(fake.Now())
+	updates := roomUpdates(room)
+	require.NotEmpty(t, updates)
+	assert.False(t, updates[len(updates)-1].Delta)
+	assert.Len(t, updates[len(updates)-1].Players, 2)
+
+	// Changes within the update interval go out together, carrying only
+	// the players that changed
+	require.NoError(t, room.PlaceBet("p2", 20*game.Dollar, game.Tails))
+	require.NoError(t, room.UpdateBet("p2", 30*game.Dollar, game.Tails))
+	assert.Empty(t, roomUpdates(room))
+
+	fake.Advance(DefaultUpdateInterval)
+	scheduler.advance(fake.Now())
+	updates = roomUpdates(room)
+	require.Len(t, updates, 1)
+	require.True(t, updates[0].Delta)
+	require.Len(t, updates[0].Players, 1)
+	assert.Equal(t, "p2", updates[0].Players[0].ID)
+	assert.Equal(t, 70*game.Dollar, updates[0].Players[0].Balance)
+
+	// Older clients still get every player
+	legacy := NewMessage(MsgRoomUpdate, "room", "", updates[0]).ForProtocol(2)
+	data := legacy.Data.(*RoomUpdateData)
+	assert.False(t, data.Delta)
+	assert.Len(t, data.Players, 2)
+
+	require.NoError(t, room.RemovePlayer("p2"))
+	fake.Advance(DefaultUpdateInterval)
+	scheduler.advance(fake.Now())
+	updates = roomUpdates(room)
+	require.Len(t, updates, 1)
+	assert.Empty(t, updates[0].Players)
+	assert.Equal(t, []string{"p2"}, updates[0].Removed)
+}
+
+func TestGameRoom_StateChangeUpdatesAtOnce(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	drainEvents(room)
+
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	require.NoError(t, room.CancelRound("server maintenance"))
+
+	// The pending bet update is flushed along with the new state
+	updates := roomUpdates(room)
+	require.NotEmpty(t, updates)
+	assert.Equal(t, StateWaiting, updates[len(updates)-1].GameState)
+}
+
+func TestRoomView_Apply(t *testing.T) {
+	var view roomView
+
+	full := view.apply(&RoomUpdateData{RoomID: "r1", Players: []PlayerInfo{
+		{ID: "p1", Balance: 100 * game.Dollar},
+		{ID: "p2", Balance: 100 * game.Dollar},
+	}})
+	assert.Len(t, full.Players, 2)
+
+	merged := view.apply(&RoomUpdateData{
+		RoomID:  "r1",
+		Delta:   true,
+		Players: []PlayerInfo{{ID: "p1", Balance: 90 * game.Dollar}, {ID: "p3", Balance: 50 * game.Dollar}},
+		Removed: []string{"p2"},
+	})
+	assert.False(t, merged.Delta)
+	assert.Equal(t, []PlayerInfo{{ID: "p1", Balance: 90 * game.Dollar}, {ID: "p3", Balance: 50 * game.Dollar}}, merged.Players)
+
+	// A delta for another room cannot be merged and starts over
+	other := view.apply(&RoomUpdateData{RoomID: "r2", Delta: true, Players: []PlayerInfo{{ID: "p4"}}})
+	assert.Len(t, other.Players, 1)
+}
+
+func TestNetworkClient_ReceivesCompleteRoomUpdates(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+
+	config := DefaultClientConfig()
+	config.ServerURL = "ws://" + listener.Addr().String() + "/ws"
+
+	var mu sync.Mutex
+	var latest RoomUpdateData
+	first := NewNetworkClient(config, "p1", "Player 1", zaptest.NewLogger(t))
+	defer first.Disconnect()
+	first.SetMessageHandler(MsgRoomUpdate, func(msg *Message) {
+		var update RoomUpdateData
+		if err := msg.GetData(&update); err == nil {
+			mu.Lock()
+			latest = update
+			mu.Unlock()
+		}
+	})
+	require.NoError(t, first.Connect())
+	require.NoError(t, first.JoinRoom("r1", 100*game.Dollar))
+
+	second := NewNetworkClient(config, "p2", "Player 2", zaptest.NewLogger(t))
+	defer second.Disconnect()
+	require.NoError(t, second.Connect())
+	require.NoError(t, second.JoinRoom("r1", 100*game.Dollar))
+
+	// Once betting opens, the second player's bet arrives as a delta that
+	// the client merges with what it already knew
+	waitFor(t, func() bool {
+		room, ok := server.GetRoom("r1")
+		return ok && room.GetGameState() == StateBetting
+	})
+	require.NoError(t, second.PlaceBet(10*game.Dollar, game.Heads))
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, player := range latest.Players {
+			if player.ID == "p2" && player.HasBet {
+				return len(latest.Players) == 2
+			}
+		}
+		return false
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	assert.False(t, latest.Delta)
+}
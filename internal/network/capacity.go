@@ -0,0 +1,124 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CodeServerBusy is the error code of requests turned away for lack of
+// capacity. Its ErrorData carries a retry_after_ms hint.
+const CodeServerBusy = "server_busy"
+
+// DefaultBusyRetryAfter is how long busy responses ask clients to wait
+// before trying again
+const DefaultBusyRetryAfter = 10 * time.Second
+
+// ErrServerBusy is matched by ServerBusyError
+var ErrServerBusy = errors.New("server is busy")
+
+// ServerBusyError reports the server turning a request away because it is
+// at capacity, with how long to wait before retrying
+type ServerBusyError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *ServerBusyError) Error() string {
+	if e.RetryAfter <= 0 {
+		return fmt.Sprintf("%s: %s", ErrServerBusy, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s, retry in %s", ErrServerBusy, e.Reason, e.RetryAfter)
+}
+
+// Is makes the error match ErrServerBusy
+func (e *ServerBusyError) Is(target error) bool {
+	return target == ErrServerBusy
+}
+
+// errorData returns the busy response sent to clients
+func (e *ServerBusyError) errorData() ErrorData {
+	return ErrorData{
+		Code:         CodeServerBusy,
+		Message:      e.Reason,
+		RetryAfterMs: e.RetryAfter.Milliseconds(),
+	}
+}
+
+// busyError returns the error for a request turned away for reason, with
+// the server's retry hint
+func (s *Server) busyError(reason string) *ServerBusyError {
+	retryAfter := s.config.BusyRetryAfter
+	if retryAfter <= 0 {
+		retryAfter = DefaultBusyRetryAfter
+	}
+	return &ServerBusyError{Reason: reason, RetryAfter: retryAfter}
+}
+
+// acquireConnection takes one of the MaxConnections slots, reporting false
+// when every slot is taken
+func (s *Server) acquireConnection() bool {
+	open := s.connections.Add(1)
+	if s.config.MaxConnections > 0 && open > int64(s.config.MaxConnections) {
+		s.connections.Add(-1)
+		return false
+	}
+	return true
+}
+
+// releaseConnection frees the slot of a connection that has closed or was
+// never upgraded
+func (s *Server) releaseConnection() {
+	s.connections.Add(-1)
+}
+
+// rejectBusy turns a WebSocket upgrade away with 503 Service Unavailable,
+// a Retry-After header and the busy error as JSON
+func (s *Server) rejectBusy(w http.ResponseWriter, r *http.Request, err *ServerBusyError) {
+	s.logger.Warn("Rejected connection, server busy",
+		zap.String("remote_addr", r.RemoteAddr),
+		zap.String("reason", err.Reason),
+	)
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(err.RetryAfter.Seconds()))))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(err.errorData())
+}
+
+// sendBusy tells the client a request was turned away for lack of capacity
+func (c *Client) sendBusy(err *ServerBusyError) {
+	c.sendMessage(NewMessage(MsgError, "", c.playerID, err.errorData()))
+}
+
+// busyResponse reads the ServerBusyError from a refused WebSocket
+// handshake, reporting false when the server was not busy
+func busyResponse(resp *http.Response) (*ServerBusyError, bool) {
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, false
+	}
+
+	busy := &ServerBusyError{Reason: "server at capacity"}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		busy.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	if resp.Body != nil {
+		var data ErrorData
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(body, &data) == nil && data.Code == CodeServerBusy {
+			busy.Reason = data.Message
+			if data.RetryAfterMs > 0 {
+				busy.RetryAfter = time.Duration(data.RetryAfterMs) * time.Millisecond
+			}
+		}
+	}
+	return busy, true
+}
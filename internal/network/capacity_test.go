@@ -0,0 +1,89 @@
+package network
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+// startTestServer serves config on a free port, returning the
+// WebSocket URL. Clients of the server should log nowhere, as they may
+// still log after the test returns.
+func startTestServer(t *testing.T, config *ServerConfig) (*Server, string) {
+	server := NewServer(config, zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+	return server, "ws://" + listener.Addr().String() + "/ws"
+}
+
+func TestServer_ConnectionCap(t *testing.T) {
+	config := DefaultServerConfig()
+	config.MaxConnections = 1
+	config.BusyRetryAfter = 3 * time.Second
+	server, url := startTestServer(t, config)
+
+	clientConfig := DefaultClientConfig()
+	clientConfig.ServerURL = url
+	first := NewNetworkClient(clientConfig, "p1", "Player 1", zap.NewNop())
+	require.NoError(t, first.Connect())
+
+	// Past the cap the upgrade is refused with a retry hint
+	second := NewNetworkClient(clientConfig, "p2", "Player 2", zap.NewNop())
+	defer second.Disconnect()
+	err := second.Connect()
+	require.ErrorIs(t, err, ErrServerBusy)
+	var busy *ServerBusyError
+	require.True(t, errors.As(err, &busy))
+	assert.Equal(t, 3*time.Second, busy.RetryAfter)
+	assert.Equal(t, "connection limit reached", busy.Reason)
+
+	// A closed connection frees its slot
+	first.Disconnect()
+	require.Eventually(t, func() bool { return server.connections.Load() == 0 },
+		time.Second, 10*time.Millisecond)
+	require.NoError(t, second.Connect())
+}
+
+func TestServer_RoomCapBusy(t *testing.T) {
+	config := DefaultServerConfig()
+	config.MaxRooms = 1
+	config.BusyRetryAfter = 2 * time.Second
+	server, url := startTestServer(t, config)
+
+	_, err := server.CreateRoom("r1", "Room 1", DefaultRoomConfig())
+	require.NoError(t, err)
+	_, err = server.CreateRoom("r2", "Room 2", DefaultRoomConfig())
+	assert.ErrorIs(t, err, ErrServerBusy)
+
+	// Clients asking for another room are told to come back later
+	clientConfig := DefaultClientConfig()
+	clientConfig.ServerURL = url
+	client := NewNetworkClient(clientConfig, "p1", "Player 1", zap.NewNop())
+	defer client.Disconnect()
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.JoinRoom("r2", 100*game.Dollar))
+
+	select {
+	case err := <-client.GetErrorChannel():
+		var busy *ServerBusyError
+		require.True(t, errors.As(err, &busy), err)
+		assert.Equal(t, 2*time.Second, busy.RetryAfter)
+		assert.Equal(t, "maximum number of rooms reached", busy.Reason)
+	case <-time.After(time.Second):
+		t.Fatal("no server busy error")
+	}
+}
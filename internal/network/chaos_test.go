@@ -0,0 +1,343 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+// errInjectedDisconnect is what a write returns when the fault injector
+// cuts the connection
+var errInjectedDisconnect = errors.New("fault injector: connection cut")
+
+// faultConfig is what a fault injector does to the data frames written
+// through the connections it wraps. Control frames such as pings pass
+// untouched, so connections are only lost when the injector cuts them.
+type faultConfig struct {
+	// DropRate is the chance a frame is silently lost
+	DropRate float64
+	// Jitter delays each frame by up to this long
+	Jitter time.Duration
+	// DisconnectRate is the chance a write cuts the connection instead
+	DisconnectRate float64
+}
+
+// faultInjector wraps WebSocket connections on either end of a link and
+// injects its faults while it is on. Its counts cover every connection.
+type faultInjector struct {
+	config faultConfig
+	on     atomic.Bool
+
+	mu  sync.Mutex
+	rng *rand.Rand
+
+	frames      atomic.Int64
+	dropped     atomic.Int64
+	disconnects atomic.Int64
+}
+
+// newFaultInjector returns an injector, off until start, whose faults
+// follow from seed
+func newFaultInjector(config faultConfig, seed int64) *faultInjector {
+	return &faultInjector{config: config, rng: rand.New(rand.NewSource(seed))}
+}
+
+func (f *faultInjector) start() { f.on.Store(true) }
+func (f *faultInjector) stop()  { f.on.Store(false) }
+
+// wrap is the hook servers and clients pass new connections through
+func (f *faultInjector) wrap(conn wsConn) wsConn {
+	return &faultyConn{wsConn: conn, faults: f}
+}
+
+// roll picks what happens to the next frame
+func (f *faultInjector) roll() (delay time.Duration, drop, disconnect bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.config.Jitter > 0 {
+		delay = time.Duration(f.rng.Int63n(int64(f.config.Jitter)))
+	}
+	disconnect = f.rng.Float64() < f.config.DisconnectRate
+	drop = f.rng.Float64() < f.config.DropRate
+	return delay, drop, disconnect
+}
+
+// faultyConn is a connection whose data frames the injector may delay,
+// lose or turn into a disconnect
+type faultyConn struct {
+	wsConn
+	faults *faultInjector
+}
+
+func (c *faultyConn) WriteMessage(messageType int, data []byte) error {
+	if !c.faults.on.Load() || (messageType != websocket.TextMessage && messageType != websocket.BinaryMessage) {
+		return c.wsConn.WriteMessage(messageType, data)
+	}
+	c.faults.frames.Add(1)
+
+	delay, drop, disconnect := c.faults.roll()
+	time.Sleep(delay)
+	switch {
+	case disconnect:
+		c.faults.disconnects.Add(1)
+		c.wsConn.Close()
+		return errInjectedDisconnect
+	case drop:
+		c.faults.dropped.Add(1)
+		return nil
+	}
+	return c.wsConn.WriteMessage(messageType, data)
+}
+
+// chaosPlayers is how many clients play through the faults
+const chaosPlayers = 3
+
+// chaosTable is a server with fast rounds and clients seated at one of its
+// rooms, every connection on both ends passing through the injector
+type chaosTable struct {
+	server  *Server
+	clients []*NetworkClient
+}
+
+// newChaosTable starts the server and seats the clients, with the injector
+// still off
+func newChaosTable(t *testing.T, faults *faultInjector) *chaosTable {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("plays rounds in real time")
+	}
+
+	serverConfig := DefaultServerConfig()
+	serverConfig.RoomDefaults.MinPlayers = 1
+	serverConfig.RoomDefaults.BettingDuration = 300 * time.Millisecond
+	serverConfig.RoomDefaults.ResultDuration = 100 * time.Millisecond
+	serverConfig.RoomDefaults.UpdateInterval = 0
+	serverConfig.RoomDefaults.MaxLatencyGrace = 50 * time.Millisecond
+	server := NewServer(serverConfig, zap.NewNop())
+	server.wrapConn = faults.wrap
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+
+	table := &chaosTable{server: server}
+	for i := range chaosPlayers {
+		config := DefaultClientConfig()
+		config.ServerURL = "ws://" + listener.Addr().String() + "/ws"
+		config.ReconnectDelay = 20 * time.Millisecond
+		config.MaxReconnects = 1000
+		config.WriteWait = time.Second
+
+		client := NewNetworkClient(config, fmt.Sprintf("p%d", i+1), fmt.Sprintf("Player %d", i+1), zap.NewNop())
+		client.wrapConn = faults.wrap
+		require.NoError(t, client.Connect())
+		require.NoError(t, client.JoinRoom("chaos", 0))
+		table.clients = append(table.clients, client)
+	}
+	t.Cleanup(func() {
+		faults.stop()
+		for _, client := range table.clients {
+			client.Disconnect()
+		}
+		server.Stop()
+		listener.Close()
+	})
+
+	table.waitSeated(t)
+	return table
+}
+
+// waitSeated waits until every client holds a seat in the room
+func (c *chaosTable) waitSeated(t *testing.T) {
+	t.Helper()
+	waitFor(t, func() bool {
+		room, exists := c.server.GetRoom("chaos")
+		if !exists {
+			return false
+		}
+		players := room.GetPlayers()
+		for _, client := range c.clients {
+			if player, seated := players[client.playerID]; !seated || !player.IsOnline {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// play has every client bet on a random side as often as it can until
+// the room has settled rounds more rounds with bets in them
+func (c *chaosTable) play(t *testing.T, rounds int) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i, client := range c.clients {
+		wg.Add(1)
+		go func(client *NetworkClient, seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for ctx.Err() == nil {
+				side := game.Heads
+				if rng.Intn(2) == 1 {
+					side = game.Tails
+				}
+				// Refusals are expected: betting closed, already bet on
+				// the side, or the connection down
+				client.PlaceBet(game.Money(1+rng.Intn(10))*game.Dollar, side)
+				time.Sleep(40 * time.Millisecond)
+			}
+		}(client, int64(i))
+	}
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	deadline := time.Now().Add(30 * time.Second)
+	for len(c.roundsWithBets(t)) < rounds {
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d rounds with bets settled in time", len(c.roundsWithBets(t)))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// roundsWithBets returns the settled rounds someone bet in, oldest first
+func (c *chaosTable) roundsWithBets(t *testing.T) []*storage.Round {
+	t.Helper()
+	rounds, err := c.server.Rounds().GetRounds(context.Background(), "chaos", 0)
+	require.NoError(t, err)
+
+	var played []*storage.Round
+	for i := len(rounds) - 1; i >= 0; i-- {
+		if len(rounds[i].Outcomes) > 0 {
+			played = append(played, rounds[i])
+		}
+	}
+	return played
+}
+
+// assertSettledCorrectly checks every round paid by the rules and that no
+// money was made or lost along the way: once everyone has left, each
+// player's balance on record is the starting balance plus their net over
+// the stored rounds. Bets refunded when a connection dropped mid-round
+// never reach a stored round, and must have come back in full.
+func (c *chaosTable) assertSettledCorrectly(t *testing.T) {
+	t.Helper()
+	for _, client := range c.clients {
+		client.Disconnect()
+	}
+	waitFor(t, func() bool {
+		room, exists := c.server.GetRoom("chaos")
+		return !exists || len(room.GetPlayers()) == 0
+	})
+
+	seen := make(map[string]bool)
+	net := make(map[string]game.Money)
+	for _, round := range c.roundsWithBets(t) {
+		assert.False(t, seen[round.ID], "round %s stored twice", round.ID)
+		seen[round.ID] = true
+		require.True(t, round.CoinResult.IsValid(), "round %s", round.ID)
+
+		for _, outcome := range round.Outcomes {
+			var wagered, payout game.Money
+			sides := make(map[game.Side]bool)
+			for _, bet := range outcome.Bets {
+				assert.False(t, sides[bet.Choice], "round %s: %s bet on %s twice", round.ID, outcome.PlayerID, bet.Choice)
+				sides[bet.Choice] = true
+				wagered += bet.Amount + bet.Premium
+				if bet.Choice == round.CoinResult {
+					payout += bet.Amount.Mul(2)
+				}
+			}
+			assert.Equal(t, wagered, outcome.Wagered, "round %s: %s wagered", round.ID, outcome.PlayerID)
+			assert.Equal(t, payout, outcome.Payout, "round %s: %s payout", round.ID, outcome.PlayerID)
+			assert.Equal(t, payout > wagered, outcome.Won, "round %s: %s won", round.ID, outcome.PlayerID)
+			net[outcome.PlayerID] += outcome.Payout + outcome.Insurance - outcome.Wagered
+		}
+	}
+
+	for _, client := range c.clients {
+		player, err := c.server.results.GetPlayer(context.Background(), client.playerID)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultStartingBalance+net[client.playerID], player.Balance, "balance of %s", client.playerID)
+	}
+}
+
+func TestChaos_RoundsSettleUnderPacketLoss(t *testing.T) {
+	faults := newFaultInjector(faultConfig{DropRate: 0.2, Jitter: 20 * time.Millisecond}, 1)
+	table := newChaosTable(t, faults)
+
+	faults.start()
+	table.play(t, 3)
+	faults.stop()
+
+	assert.Positive(t, faults.dropped.Load(), "frames were lost")
+	assert.Zero(t, faults.disconnects.Load())
+	table.assertSettledCorrectly(t)
+}
+
+func TestChaos_RoundsSettleThroughReconnectStorm(t *testing.T) {
+	faults := newFaultInjector(faultConfig{DisconnectRate: 0.05, Jitter: 5 * time.Millisecond}, 2)
+	table := newChaosTable(t, faults)
+
+	faults.start()
+	table.play(t, 3)
+	faults.stop()
+	require.Positive(t, faults.disconnects.Load(), "connections were cut")
+
+	// Everyone finds their way back to their seat once the storm passes
+	table.waitSeated(t)
+	table.assertSettledCorrectly(t)
+}
+
+func TestFaultInjector_PassesFramesWhileOff(t *testing.T) {
+	faults := newFaultInjector(faultConfig{DropRate: 1}, 1)
+	conn := &recordingConn{}
+	wrapped := faults.wrap(conn)
+
+	require.NoError(t, wrapped.WriteMessage(websocket.TextMessage, []byte("a")))
+	faults.start()
+	require.NoError(t, wrapped.WriteMessage(websocket.TextMessage, []byte("b")))
+	require.NoError(t, wrapped.WriteMessage(websocket.PingMessage, []byte("c")))
+
+	assert.Equal(t, []string{"a", "c"}, conn.written, "data frames are lost, pings are not")
+	assert.Equal(t, int64(1), faults.dropped.Load())
+
+	cut := newFaultInjector(faultConfig{DisconnectRate: 1}, 1)
+	cut.start()
+	assert.ErrorIs(t, cut.wrap(conn).WriteMessage(websocket.TextMessage, []byte("d")), errInjectedDisconnect)
+	assert.True(t, conn.closed)
+}
+
+// recordingConn records the frames written to it
+type recordingConn struct {
+	wsConn
+	written []string
+	closed  bool
+}
+
+func (c *recordingConn) WriteMessage(messageType int, data []byte) error {
+	c.written = append(c.written, string(data))
+	return nil
+}
+
+func (c *recordingConn) Close() error {
+	c.closed = true
+	return nil
+}
@@ -3,48 +3,99 @@ package network
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 
+	"coinflip-game/internal/clock"
 	"coinflip-game/internal/game"
+	"coinflip-game/internal/tracing"
 )
 
 // NetworkClient handles WebSocket connection to the multiplayer server
 type NetworkClient struct {
 	mu           sync.RWMutex
-	conn         *websocket.Conn
+	writeMu      sync.Mutex // One writer at a time on the connection
+	conn         wsConn
 	serverURL    string
 	playerID     string
 	playerName   string
+	seatName     string // Name seated under in the current room, if the server changed it
 	currentRoom  string
 	logger       *zap.Logger
 	
-	// Event handling
-	messageHandlers map[MessageType]func(*Message)
+	// Event handling: frames pass through the built-in middleware, then
+	// any added with Use, before dispatch
+	messageHandlers *handlerSet
+	builtin         []Middleware
+	middleware      []Middleware
+	pipeline        InboundHandler
+	metrics         *MessageMetrics
 	eventChan       chan *Message
 	errorChan       chan error
+	onConnection    func(ConnectionEvent)
+	room            roomView // Rebuilds complete room updates from deltas
 	
 	// Connection state
 	connected       bool
+	encoding        Encoding // Negotiated with the server on connect
+	protocol        int      // Protocol version negotiated on connect
+	upgradeErr      error    // Set when the server refused our protocol version
+	preferEncoding  Encoding
+	compression     bool
 	reconnectDelay  time.Duration
 	maxReconnects   int
 	reconnectCount  int
+	reconnecting    bool
+	retryNow        chan struct{}
+	retryAfter      time.Duration // Wait asked for by a busy server before the next attempt
+	balance         game.Money // Last known balance, used to rejoin after a reconnect
+	watching        bool       // The current room is watched without a seat
+	clock           clock.Clock
 	
 	// Context for graceful shutdown
 	ctx             context.Context
 	cancel          context.CancelFunc
 	
-	// Ping/pong for connection health
+	// traceCtx holds the span the client's messages continue, if any
+	traceCtx        context.Context
+	
+	// Ping/pong for connection health and latency
 	pingPeriod      time.Duration
 	pongWait        time.Duration
 	writeWait       time.Duration
+	latencyInterval time.Duration
+	latency         latencyTracker
+	
+	// wrapConn, when set, wraps each connection; tests use it to inject
+	// faults
+	wrapConn        func(wsConn) wsConn
+}
+
+// ConnectionStatus describes the client's link to the server
+type ConnectionStatus string
+
+const (
+	ConnectionUp       ConnectionStatus = "up"
+	ConnectionRetrying ConnectionStatus = "retrying"
+	ConnectionDown     ConnectionStatus = "down"
+)
+
+// ConnectionEvent reports a change in connection status. While retrying,
+// Attempt counts up to MaxAttempts and RetryAt is when the next attempt
+// starts; Down means automatic reconnection has stopped.
+type ConnectionEvent struct {
+	Status      ConnectionStatus
+	Attempt     int
+	MaxAttempts int
+	RetryAt     time.Time
+	Err         error
 }
 
 // ClientConfig contains client configuration
@@ -55,8 +106,22 @@ type ClientConfig struct {
 	PingPeriod      time.Duration
 	PongWait        time.Duration
 	WriteWait       time.Duration
+	// LatencyInterval pings the server this often to measure latency;
+	// zero measures only with the PingPeriod keepalive
+	LatencyInterval time.Duration
 	ReadBufferSize  int
 	WriteBufferSize int
+	
+	// Encoding is the preferred wire format; the server may fall back to JSON
+	Encoding          Encoding
+	EnableCompression bool
+	
+	// Clock times reconnect delays; nil uses the system clock
+	Clock clock.Clock
+	
+	// DedupWindow is how many recent frames are checked for duplicates;
+	// zero uses DefaultDedupWindow
+	DedupWindow int
 }
 
 // DefaultClientConfig returns default client configuration
@@ -68,8 +133,11 @@ func DefaultClientConfig() *ClientConfig {
 		PingPeriod:      54 * time.Second,
 		PongWait:        60 * time.Second,
 		WriteWait:       10 * time.Second,
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
+		LatencyInterval: DefaultLatencyInterval,
+		ReadBufferSize:    1024,
+		WriteBufferSize:   1024,
+		Encoding:          EncodingJSON,
+		EnableCompression: true,
 	}
 }
 
@@ -79,6 +147,11 @@ func NewNetworkClient(config *ClientConfig, playerID, playerName string, logger
 		config = DefaultClientConfig()
 	}
 	
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.New()
+	}
+	
 	ctx, cancel := context.WithCancel(context.Background())
 	
 	client := &NetworkClient{
@@ -86,18 +159,34 @@ func NewNetworkClient(config *ClientConfig, playerID, playerName string, logger
 		playerID:        playerID,
 		playerName:      playerName,
 		logger:          logger,
-		messageHandlers: make(map[MessageType]func(*Message)),
+		messageHandlers: newHandlerSet(),
+		metrics:         NewMessageMetrics(),
 		eventChan:       make(chan *Message, 100),
 		errorChan:       make(chan error, 10),
+		retryNow:        make(chan struct{}, 1),
 		reconnectDelay:  config.ReconnectDelay,
 		maxReconnects:   config.MaxReconnects,
+		clock:           clk,
 		pingPeriod:      config.PingPeriod,
 		pongWait:        config.PongWait,
 		writeWait:       config.WriteWait,
+		latencyInterval: config.LatencyInterval,
+		encoding:        EncodingJSON,
+		preferEncoding:  config.Encoding,
+		compression:     config.EnableCompression,
 		ctx:             ctx,
 		cancel:          cancel,
 	}
 	
+	// Logging and metrics see every frame, including those dropped later
+	client.builtin = []Middleware{
+		LoggingMiddleware(logger),
+		client.metrics.Middleware(),
+		DecodingMiddleware(),
+		DedupMiddleware(config.DedupWindow),
+	}
+	client.pipeline = chain(client.dispatch, client.builtin...)
+	
 	// Set up default message handlers
 	client.setupDefaultHandlers()
 	
@@ -120,21 +209,47 @@ func (c *NetworkClient) Connect() error {
 	
 	c.logger.Info("Connecting to server", zap.String("url", c.serverURL))
 	
-	dialer := websocket.DefaultDialer
-	conn, _, err := dialer.Dial(u.String(), nil)
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = c.compression
+	if c.preferEncoding != "" && c.preferEncoding != EncodingJSON {
+		dialer.Subprotocols = []string{c.preferEncoding.Subprotocol(), SubprotocolJSON}
+	}
+	
+	conn, resp, err := dialer.Dial(withPlayer(withProtocol(u), c.playerID).String(), nil)
+	if busy, ok := busyResponse(resp); ok {
+		c.retryAfter = busy.RetryAfter
+		return fmt.Errorf("failed to connect to server: %w", busy)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to connect to server: %w", err)
 	}
 	
-	c.conn = conn
+	c.conn = wrapConn(c.wrapConn, conn)
 	c.connected = true
 	c.reconnectCount = 0
+	c.upgradeErr = nil
+	c.encoding = EncodingForSubprotocol(conn.Subprotocol())
+	c.protocol = 1
+	if version, err := strconv.Atoi(resp.Header.Get(ProtocolHeader)); err == nil {
+		c.protocol = version
+	}
+	if c.protocol < ProtocolVersion {
+		c.logger.Warn("Server speaks an older protocol; some features may be unavailable",
+			zap.Int("server_protocol", c.protocol),
+			zap.Int("client_protocol", ProtocolVersion),
+		)
+	}
 	
 	// Set connection options - increased for game result messages
 	c.conn.SetReadLimit(4096)
 	c.conn.SetReadDeadline(time.Now().Add(c.pongWait))
-	c.conn.SetPongHandler(func(string) error {
+	c.latency.reset()
+	c.room.reset()
+	c.conn.SetPongHandler(func(appData string) error {
 		c.conn.SetReadDeadline(time.Now().Add(c.pongWait))
+		if rtt, ok := roundTrip(appData, time.Now()); ok {
+			c.latency.observe(rtt)
+		}
 		return nil
 	})
 	
@@ -143,7 +258,10 @@ func (c *NetworkClient) Connect() error {
 	go c.writePump()
 	go c.pingPump()
 	
-	c.logger.Info("Connected to server successfully")
+	c.logger.Info("Connected to server successfully",
+		zap.String("encoding", string(c.encoding)),
+		zap.Int("protocol", c.protocol),
+	)
 	return nil
 }
 
@@ -168,7 +286,13 @@ func (c *NetworkClient) Disconnect() {
 }
 
 // JoinRoom joins a multiplayer room
-func (c *NetworkClient) JoinRoom(roomID string, balance float64) error {
+func (c *NetworkClient) JoinRoom(roomID string, balance game.Money) error {
+	return c.JoinRoomWithSettings(roomID, balance, nil)
+}
+
+// JoinRoomWithSettings joins a multiplayer room, supplying the settings to use
+// if the room does not exist yet and is created by this join
+func (c *NetworkClient) JoinRoomWithSettings(roomID string, balance game.Money, settings *RoomSettings) error {
 	if !c.IsConnected() {
 		return errors.New("not connected to server")
 	}
@@ -176,6 +300,7 @@ func (c *NetworkClient) JoinRoom(roomID string, balance float64) error {
 	joinData := RoomJoinData{
 		PlayerName: c.playerName,
 		Balance:    balance,
+		Settings:   settings,
 	}
 	
 	msg := NewMessage(MsgJoinRoom, roomID, c.playerID, joinData)
@@ -186,6 +311,9 @@ func (c *NetworkClient) JoinRoom(roomID string, balance float64) error {
 	
 	c.mu.Lock()
 	c.currentRoom = roomID
+	c.balance = balance
+	c.watching = false
+	c.seatName = ""
 	c.mu.Unlock()
 	
 	c.logger.Info("Joining room", 
@@ -196,6 +324,77 @@ func (c *NetworkClient) JoinRoom(roomID string, balance float64) error {
 	return nil
 }
 
+// FindDuel asks the server for a duel against a player rated close to this
+// one. The server replies with a MsgFindDuel naming the duel room, which
+// the client then counts as joined, and seats the player there.
+func (c *NetworkClient) FindDuel() error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgFindDuel, "", c.playerID, FindDuelData{
+		PlayerName: c.playerName,
+	})
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send find duel message: %w", err)
+	}
+	
+	c.logger.Info("Looking for a duel", zap.String("player_name", c.playerName))
+	return nil
+}
+
+// WatchRoom follows an existing room's bets, results and players without
+// taking a seat. The room's messages arrive on the event channel as for a
+// joined room.
+func (c *NetworkClient) WatchRoom(roomID string) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgJoinRoom, roomID, c.playerID, RoomJoinData{
+		PlayerName: c.playerName,
+		Watch:      true,
+	})
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send watch room message: %w", err)
+	}
+	
+	c.mu.Lock()
+	c.currentRoom = roomID
+	c.watching = true
+	c.seatName = ""
+	c.mu.Unlock()
+	
+	c.logger.Info("Watching room", zap.String("room_id", roomID))
+	return nil
+}
+
+// CreateRoom asks the server to create a room with the given settings.
+// The server echoes a MsgCreateRoom with the effective settings on success.
+func (c *NetworkClient) CreateRoom(roomID, roomName string, settings *RoomSettings) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	createData := RoomCreateData{
+		RoomName: roomName,
+		Settings: settings,
+	}
+	
+	msg := NewMessage(MsgCreateRoom, roomID, c.playerID, createData)
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send create room message: %w", err)
+	}
+	
+	c.logger.Info("Creating room",
+		zap.String("room_id", roomID),
+		zap.String("room_name", roomName),
+	)
+	
+	return nil
+}
+
 // LeaveRoom leaves the current room
 func (c *NetworkClient) LeaveRoom() error {
 	c.mu.RLock()
@@ -225,7 +424,24 @@ func (c *NetworkClient) LeaveRoom() error {
 }
 
 // PlaceBet places a bet in the current room
-func (c *NetworkClient) PlaceBet(amount float64, choice game.Side) error {
+func (c *NetworkClient) PlaceBet(amount game.Money, choice game.Side) error {
+	return c.sendBet(amount, choice, false, false)
+}
+
+// PlaceInsuredBet places a bet with the room's insurance, whose premium is
+// taken from the balance along with the stake
+func (c *NetworkClient) PlaceInsuredBet(amount game.Money, choice game.Side) error {
+	return c.sendBet(amount, choice, true, false)
+}
+
+// PlacePracticeBet places a bet that resolves with the round but leaves the
+// balance and lifetime stats alone
+func (c *NetworkClient) PlacePracticeBet(amount game.Money, choice game.Side) error {
+	return c.sendBet(amount, choice, false, true)
+}
+
+// sendBet places a bet, insured, practice or neither
+func (c *NetworkClient) sendBet(amount game.Money, choice game.Side, insured, practice bool) error {
 	c.mu.RLock()
 	roomID := c.currentRoom
 	c.mu.RUnlock()
@@ -243,6 +459,9 @@ func (c *NetworkClient) PlaceBet(amount float64, choice game.Side) error {
 		Amount:   amount,
 		Choice:   choice,
 		BetID:    fmt.Sprintf("bet_%d", time.Now().UnixNano()),
+		Insured:  insured,
+		Practice: practice,
+		SentAtMs: time.Now().UnixMilli(),
 	}
 	
 	msg := NewMessage(MsgBetPlaced, roomID, c.playerID, betData)
@@ -253,9 +472,492 @@ func (c *NetworkClient) PlaceBet(amount float64, choice game.Side) error {
 	
 	c.logger.Info("Placed bet",
 		zap.String("room_id", roomID),
-		zap.Float64("amount", amount),
+		zap.Float64("amount", amount.Float64()),
 		zap.String("choice", choice.String()),
+		zap.Bool("insured", insured),
+		zap.Bool("practice", practice),
+	)
+	
+	return nil
+}
+
+// UpdateBet changes the amount and side of the bet placed this round. The
+// server echoes MsgUpdateBet if betting is still open.
+func (c *NetworkClient) UpdateBet(amount game.Money, choice game.Side) error {
+	roomID := c.GetCurrentRoom()
+	if roomID == "" {
+		return errors.New("not in a room")
+	}
+	
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgUpdateBet, roomID, c.playerID, BetData{
+		PlayerID: c.playerID,
+		Amount:   amount,
+		Choice:   choice,
+		SentAtMs: time.Now().UnixMilli(),
+	})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send update bet message: %w", err)
+	}
+	
+	c.logger.Info("Updating bet",
+		zap.String("room_id", roomID),
+		zap.Float64("amount", amount.Float64()),
+		zap.String("choice", choice.String()),
+	)
+	return nil
+}
+
+// CancelBet withdraws the bet placed this round. The server refunds it and
+// echoes MsgCancelBet if betting is still open.
+func (c *NetworkClient) CancelBet() error {
+	roomID := c.GetCurrentRoom()
+	if roomID == "" {
+		return errors.New("not in a room")
+	}
+	
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgCancelBet, roomID, c.playerID, BetData{
+		PlayerID: c.playerID,
+		SentAtMs: time.Now().UnixMilli(),
+	})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send cancel bet message: %w", err)
+	}
+	
+	c.logger.Info("Cancelling bet", zap.String("room_id", roomID))
+	return nil
+}
+
+// QueueBet asks the server to place a bet as soon as the next betting phase
+// opens. The server broadcasts MsgQueuedBet as the queued bet changes state.
+func (c *NetworkClient) QueueBet(amount game.Money, choice game.Side) error {
+	return c.sendQueuedBet(amount, choice, false)
+}
+
+// QueueInsuredBet queues a bet to be placed with the room's insurance
+func (c *NetworkClient) QueueInsuredBet(amount game.Money, choice game.Side) error {
+	return c.sendQueuedBet(amount, choice, true)
+}
+
+// CancelQueuedBet withdraws the bet queued for the next round
+func (c *NetworkClient) CancelQueuedBet() error {
+	return c.sendQueuedBet(0, "", false)
+}
+
+// SetBetSlip stages a bet for each of the next rounds, placed by the server
+// one a round as betting opens, replacing any slip already staged. The
+// server broadcasts MsgBetSlip as the slip changes.
+func (c *NetworkClient) SetBetSlip(bets []BetData) error {
+	roomID := c.GetCurrentRoom()
+	if roomID == "" {
+		return errors.New("not in a room")
+	}
+	
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	slip := make([]BetData, len(bets))
+	for i, bet := range bets {
+		slip[i] = BetData{PlayerID: c.playerID, Amount: bet.Amount, Choice: bet.Choice, Insured: bet.Insured}
+	}
+	msg := NewMessage(MsgBetSlip, roomID, c.playerID, BetSlipData{Bets: slip})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send bet slip message: %w", err)
+	}
+	
+	c.logger.Info("Staging bet slip",
+		zap.String("room_id", roomID),
+		zap.Int("bets", len(slip)),
+	)
+	return nil
+}
+
+// ClearBetSlip withdraws the bets staged for the next rounds
+func (c *NetworkClient) ClearBetSlip() error {
+	return c.SetBetSlip(nil)
+}
+
+// sendQueuedBet queues a bet, or withdraws it with a zero amount
+func (c *NetworkClient) sendQueuedBet(amount game.Money, choice game.Side, insured bool) error {
+	roomID := c.GetCurrentRoom()
+	if roomID == "" {
+		return errors.New("not in a room")
+	}
+	
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgQueuedBet, roomID, c.playerID, QueuedBetData{
+		Bet: BetData{PlayerID: c.playerID, Amount: amount, Choice: choice, Insured: insured},
+	})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send queued bet message: %w", err)
+	}
+	
+	c.logger.Info("Queueing bet for next round",
+		zap.String("room_id", roomID),
+		zap.Float64("amount", amount.Float64()),
+		zap.String("choice", choice.String()),
+	)
+	return nil
+}
+
+// SendChat sends a chat message to everyone in the current room
+func (c *NetworkClient) SendChat(text string) error {
+	roomID := c.GetCurrentRoom()
+	if roomID == "" {
+		return errors.New("not in a room")
+	}
+	
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgChat, roomID, c.playerID, ChatData{Text: text})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send chat message: %w", err)
+	}
+	return nil
+}
+
+// ReplayRound asks the server to play back a recorded round of a room, or
+// of the current room when roomID is empty; an empty roundID replays the
+// room's last round. Speed is how many times faster than real time to play
+// it, real time when zero, and from how far into the round to start. The
+// server replies with MsgReplayRound, then sends a MsgReplayEvent for each
+// event, those before from at once, and a MsgReplayEnd, or MsgError if it
+// has no such recording.
+func (c *NetworkClient) ReplayRound(roomID, roundID string, speed float64, from time.Duration) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgReplayRound, roomID, c.playerID, ReplayData{
+		RoundID: roundID,
+		Speed:   speed,
+		FromMs:  from.Milliseconds(),
+	})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send replay request: %w", err)
+	}
+	return nil
+}
+
+// StopReplay stops the replay being played back, which the server confirms
+// with a MsgReplayEnd marked Stopped
+func (c *NetworkClient) StopReplay() error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	if err := c.sendMessage(NewMessage(MsgStopReplay, "", c.playerID, nil)); err != nil {
+		return fmt.Errorf("failed to stop replay: %w", err)
+	}
+	return nil
+}
+
+// ProposeConfig asks the other players in the room to vote on new room
+// settings. Zero fields keep their current value.
+func (c *NetworkClient) ProposeConfig(settings *RoomSettings) error {
+	roomID := c.GetCurrentRoom()
+	if roomID == "" {
+		return errors.New("not in a room")
+	}
+	
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgConfigProposal, roomID, c.playerID, ConfigProposalData{Settings: settings})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send settings proposal: %w", err)
+	}
+	
+	c.logger.Info("Proposed room settings", zap.String("room_id", roomID))
+	return nil
+}
+
+// VoteConfig votes on the room's open settings proposal
+func (c *NetworkClient) VoteConfig(proposalID string, approve bool) error {
+	roomID := c.GetCurrentRoom()
+	if roomID == "" {
+		return errors.New("not in a room")
+	}
+	
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgConfigVote, roomID, c.playerID, ConfigVoteData{
+		ProposalID: proposalID,
+		Approve:    approve,
+	})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send settings vote: %w", err)
+	}
+	
+	c.logger.Info("Voted on room settings",
+		zap.String("room_id", roomID),
+		zap.String("proposal_id", proposalID),
+		zap.Bool("approve", approve),
+	)
+	return nil
+}
+
+// ProposePause asks the room to pause or resume. The other players vote
+// on it unless the room owner decides.
+func (c *NetworkClient) ProposePause(action PauseAction) error {
+	roomID := c.GetCurrentRoom()
+	if roomID == "" {
+		return errors.New("not in a room")
+	}
+	
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgPauseProposal, roomID, c.playerID, PauseProposalData{Action: action})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send pause request: %w", err)
+	}
+	
+	c.logger.Info("Proposed room pause",
+		zap.String("room_id", roomID),
+		zap.String("action", string(action)),
+	)
+	return nil
+}
+
+// VotePause votes on the room's open pause proposal
+func (c *NetworkClient) VotePause(proposalID string, approve bool) error {
+	roomID := c.GetCurrentRoom()
+	if roomID == "" {
+		return errors.New("not in a room")
+	}
+	
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgPauseVote, roomID, c.playerID, PauseVoteData{
+		ProposalID: proposalID,
+		Approve:    approve,
+	})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send pause vote: %w", err)
+	}
+	
+	c.logger.Info("Voted on room pause",
+		zap.String("room_id", roomID),
+		zap.String("proposal_id", proposalID),
+		zap.Bool("approve", approve),
 	)
+	return nil
+}
+
+// RequestPlayerStats asks the server for a player's lifetime statistics.
+// The reply arrives as a MsgPlayerStats message carrying PlayerStatsData.
+func (c *NetworkClient) RequestPlayerStats(playerID string) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgPlayerStats, c.GetCurrentRoom(), c.playerID, PlayerStatsData{
+		PlayerID: playerID,
+	})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send player stats request: %w", err)
+	}
+	
+	return nil
+}
+
+// RedeemCode asks the server to credit a promo code to this player. The
+// server replies with MsgRedeemCode on success or MsgError otherwise.
+func (c *NetworkClient) RedeemCode(code string) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgRedeemCode, c.GetCurrentRoom(), c.playerID, RedeemCodeData{Code: code})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send promo code: %w", err)
+	}
+	
+	return nil
+}
+
+// SyncWallet reports what this player's balance changed by playing
+// offline since the last sync. The server replies with MsgWalletSync and
+// the new balance on success or MsgError otherwise; the client must not
+// be seated in a room.
+func (c *NetworkClient) SyncWallet(net game.Money) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgWalletSync, "", c.playerID, WalletSyncData{Net: net})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to sync wallet: %w", err)
+	}
+	
+	return nil
+}
+
+// Register asks the server to upgrade this guest player to a registered
+// account. The server replies with MsgRegister on success or MsgError
+// otherwise; the client must not be in a room.
+func (c *NetworkClient) Register(account string) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgRegister, "", c.playerID, RegisterData{Account: account})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send registration: %w", err)
+	}
+	
+	return nil
+}
+
+// RequestInventory asks the server for this player's coin skins. The reply
+// arrives as a MsgInventory message carrying SkinData.
+func (c *NetworkClient) RequestInventory() error {
+	return c.sendSkinMessage(MsgInventory, "")
+}
+
+// BuySkin asks the server to unlock a coin skin, paid from the player's
+// balance. The server replies with MsgBuySkin on success or MsgError.
+func (c *NetworkClient) BuySkin(skinID string) error {
+	return c.sendSkinMessage(MsgBuySkin, skinID)
+}
+
+// EquipSkin asks the server to switch to an owned coin skin. The server
+// replies with MsgEquipSkin on success or MsgError.
+func (c *NetworkClient) EquipSkin(skinID string) error {
+	return c.sendSkinMessage(MsgEquipSkin, skinID)
+}
+
+// RequestFriends asks the server for this player's friends and friend
+// code. The reply arrives as a MsgFriends message carrying FriendsData.
+func (c *NetworkClient) RequestFriends() error {
+	return c.sendFriendsMessage(MsgFriends, "")
+}
+
+// AddFriend asks the server to add a registered player, by account name or
+// friend code, to this player's friends. The server replies with
+// MsgAddFriend on success or MsgError.
+func (c *NetworkClient) AddFriend(friend string) error {
+	return c.sendFriendsMessage(MsgAddFriend, friend)
+}
+
+// RemoveFriend asks the server to take a friend off this player's friends.
+// The server replies with MsgRemoveFriend on success or MsgError.
+func (c *NetworkClient) RemoveFriend(friend string) error {
+	return c.sendFriendsMessage(MsgRemoveFriend, friend)
+}
+
+// RequestNotifySettings asks the server for this player's notification
+// settings. The reply arrives as a MsgNotifySettings message carrying
+// NotifySettingsData.
+func (c *NetworkClient) RequestNotifySettings() error {
+	return c.sendNotifySettings(nil)
+}
+
+// SetNotifySettings asks the server to replace this player's notification
+// settings. The server replies with MsgNotifySettings on success or
+// MsgError.
+func (c *NetworkClient) SetNotifySettings(settings game.NotifySettings) error {
+	return c.sendNotifySettings(&settings)
+}
+
+// InviteFriend invites a friend, by player ID, to the current room. The
+// server echoes the MsgRoomInvite once delivered, or replies with MsgError
+// if the friend is offline.
+func (c *NetworkClient) InviteFriend(friendID string) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	roomID := c.GetCurrentRoom()
+	if roomID == "" {
+		return errors.New("not in a room")
+	}
+	
+	msg := NewMessage(MsgRoomInvite, roomID, c.playerID, InviteData{To: friendID})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send invite: %w", err)
+	}
+	
+	return nil
+}
+
+// sendFriendsMessage sends one of the friend list requests
+func (c *NetworkClient) sendFriendsMessage(msgType MessageType, friend string) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(msgType, c.GetCurrentRoom(), c.playerID, FriendsData{Friend: friend})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send %s request: %w", msgType, err)
+	}
+	
+	return nil
+}
+
+// sendNotifySettings sends a notification settings request, replacing
+// the settings unless they are nil
+func (c *NetworkClient) sendNotifySettings(settings *game.NotifySettings) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(MsgNotifySettings, c.GetCurrentRoom(), c.playerID, NotifySettingsData{Settings: settings})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send notification settings: %w", err)
+	}
+	
+	return nil
+}
+
+// sendSkinMessage sends one of the cosmetics requests
+func (c *NetworkClient) sendSkinMessage(msgType MessageType, skinID string) error {
+	if !c.IsConnected() {
+		return errors.New("not connected to server")
+	}
+	
+	msg := NewMessage(msgType, c.GetCurrentRoom(), c.playerID, SkinData{SkinID: skinID})
+	
+	if err := c.sendMessage(msg); err != nil {
+		return fmt.Errorf("failed to send %s request: %w", msgType, err)
+	}
 	
 	return nil
 }
@@ -267,6 +969,13 @@ func (c *NetworkClient) IsConnected() bool {
 	return c.connected
 }
 
+// Protocol returns the protocol version negotiated with the server
+func (c *NetworkClient) Protocol() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.protocol
+}
+
 // GetCurrentRoom returns the current room ID
 func (c *NetworkClient) GetCurrentRoom() string {
 	c.mu.RLock()
@@ -274,11 +983,53 @@ func (c *NetworkClient) GetCurrentRoom() string {
 	return c.currentRoom
 }
 
-// SetMessageHandler sets a handler for a specific message type
+// SeatName returns the name the player is seated under in the current
+// room. It is the name the client joined with unless another player in the
+// room already had it, in which case the server picked a free one.
+func (c *NetworkClient) SeatName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.seatName != "" {
+		return c.seatName
+	}
+	return c.playerName
+}
+
+// SetTraceContext makes messages sent from now on continue the trace of
+// the span in ctx, so the server's handling shows up under the caller's
+// trace. A nil ctx stops propagating.
+func (c *NetworkClient) SetTraceContext(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.traceCtx = ctx
+}
+
+// SetMessageHandler makes handler the only handler of a message type,
+// replacing the client's default and any added before
 func (c *NetworkClient) SetMessageHandler(msgType MessageType, handler func(*Message)) {
+	c.messageHandlers.set(msgType, handler)
+}
+
+// AddMessageHandler adds a handler for a message type, called after those
+// added before it, and returns a function that removes it again
+func (c *NetworkClient) AddMessageHandler(msgType MessageType, handler func(*Message)) (remove func()) {
+	return c.messageHandlers.add(msgType, handler)
+}
+
+// Use adds middleware to the chain incoming frames pass through. It runs
+// after the built-in logging, metrics, decoding and deduplication stages,
+// in the order added, and before the message handlers.
+func (c *NetworkClient) Use(middleware ...Middleware) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.messageHandlers[msgType] = handler
+	
+	c.middleware = append(c.middleware, middleware...)
+	c.pipeline = chain(c.dispatch, append(append([]Middleware(nil), c.builtin...), c.middleware...)...)
+}
+
+// MessageStats returns counts of the frames received so far
+func (c *NetworkClient) MessageStats() MessageStats {
+	return c.metrics.Stats()
 }
 
 // GetEventChannel returns the event channel
@@ -286,6 +1037,51 @@ func (c *NetworkClient) GetEventChannel() <-chan *Message {
 	return c.eventChan
 }
 
+// SetConnectionHandler sets a callback for connection losses, reconnect
+// attempts and restored connections. It runs on the client's goroutines and
+// must not block.
+func (c *NetworkClient) SetConnectionHandler(handler func(ConnectionEvent)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onConnection = handler
+}
+
+// notifyConnection reports a connection state change to the handler, if set
+func (c *NetworkClient) notifyConnection(event ConnectionEvent) {
+	c.mu.RLock()
+	handler := c.onConnection
+	c.mu.RUnlock()
+	
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// Reconnect retries the connection now, cutting short the wait between
+// automatic attempts or starting over once they have been exhausted
+func (c *NetworkClient) Reconnect() {
+	c.mu.Lock()
+	if c.connected {
+		c.mu.Unlock()
+		return
+	}
+	
+	select {
+	case c.retryNow <- struct{}{}:
+	default:
+	}
+	
+	if c.reconnecting {
+		c.mu.Unlock()
+		return
+	}
+	c.reconnecting = true
+	c.reconnectCount = 0
+	c.mu.Unlock()
+	
+	go c.attemptReconnect()
+}
+
 // GetErrorChannel returns the error channel
 func (c *NetworkClient) GetErrorChannel() <-chan error {
 	return c.errorChan
@@ -293,38 +1089,88 @@ func (c *NetworkClient) GetErrorChannel() <-chan error {
 
 // setupDefaultHandlers sets up default message handlers
 func (c *NetworkClient) setupDefaultHandlers() {
-	c.messageHandlers[MsgError] = func(msg *Message) {
+	c.messageHandlers.add(MsgError, func(msg *Message) {
 		var errorData ErrorData
-		if err := msg.GetData(&errorData); err == nil {
-			c.logger.Error("Server error",
-				zap.String("code", errorData.Code),
-				zap.String("message", errorData.Message),
-			)
+		if err := msg.GetData(&errorData); err != nil {
+			return
 		}
-	}
+		c.logger.Error("Server error",
+			zap.String("code", errorData.Code),
+			zap.String("message", errorData.Message),
+		)
+		
+		if errorData.Code == CodeServerBusy {
+			busy := &ServerBusyError{
+				Reason:     errorData.Message,
+				RetryAfter: time.Duration(errorData.RetryAfterMs) * time.Millisecond,
+			}
+			select {
+			case c.errorChan <- busy:
+			default:
+			}
+		}
+	})
 	
-	c.messageHandlers[MsgRoomUpdate] = func(msg *Message) {
+	c.messageHandlers.add(MsgUpgradeRequired, func(msg *Message) {
+		var data UpgradeRequiredData
+		if err := msg.GetData(&data); err != nil {
+			return
+		}
+		
+		err := &UpgradeRequiredError{data}
+		c.logger.Error("Server requires a newer client", zap.Error(err))
+		
+		c.mu.Lock()
+		c.upgradeErr = err
+		c.mu.Unlock()
+		
+		select {
+		case c.errorChan <- err:
+		default:
+		}
+	})
+	
+	c.messageHandlers.add(MsgRoomUpdate, func(msg *Message) {
 		c.logger.Debug("Room update received", zap.String("room_id", msg.RoomID))
-	}
+	})
 	
-	c.messageHandlers[MsgGameResult] = func(msg *Message) {
+	c.messageHandlers.add(MsgGameResult, func(msg *Message) {
 		c.logger.Info("Game result received", zap.String("room_id", msg.RoomID))
-	}
+	})
 }
 
-// sendMessage sends a message to the server
+// sendMessage sends a message to the server. Writes are serialized, since
+// a connection takes one writer at a time and a rejoin after a reconnect
+// can race the caller's own messages.
 func (c *NetworkClient) sendMessage(msg *Message) error {
-	if !c.connected || c.conn == nil {
+	c.mu.RLock()
+	conn := c.conn
+	connected := c.connected
+	encoding := c.encoding
+	traceCtx := c.traceCtx
+	c.mu.RUnlock()
+	if !connected || conn == nil {
 		return errors.New("not connected")
 	}
 	
-	data, err := msg.ToJSON()
+	if traceCtx != nil && msg.TraceParent == "" {
+		msg.TraceParent = tracing.Inject(traceCtx)
+	}
+	
+	data, err := msg.Encode(encoding)
 	if err != nil {
 		return fmt.Errorf("failed to serialize message: %w", err)
 	}
 	
-	c.conn.SetWriteDeadline(time.Now().Add(c.writeWait))
-	return c.conn.WriteMessage(websocket.TextMessage, data)
+	frameType := websocket.TextMessage
+	if encoding.IsBinary() {
+		frameType = websocket.BinaryMessage
+	}
+	
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(c.writeWait))
+	return conn.WriteMessage(frameType, data)
 }
 
 // readPump handles reading messages from the WebSocket
@@ -338,7 +1184,7 @@ func (c *NetworkClient) readPump() {
 		case <-c.ctx.Done():
 			return
 		default:
-			_, messageBytes, err := c.conn.ReadMessage()
+			frameType, messageBytes, err := c.conn.ReadMessage()
 			if err != nil {
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					c.logger.Error("WebSocket read error", zap.Error(err))
@@ -346,7 +1192,7 @@ func (c *NetworkClient) readPump() {
 				return
 			}
 			
-			c.handleMessage(messageBytes)
+			c.handleMessage(frameType, messageBytes)
 		}
 	}
 }
@@ -370,12 +1216,21 @@ func (c *NetworkClient) writePump() {
 	}
 }
 
-// pingPump sends periodic ping messages
+// Latency returns the smoothed round trip time to the server, zero until
+// the first pong of the current connection
+func (c *NetworkClient) Latency() time.Duration {
+	return c.latency.latency()
+}
+
+// pingPump sends periodic ping messages, timestamped to measure latency
 func (c *NetworkClient) pingPump() {
 	pingPeriod := c.pingPeriod
 	if pingPeriod <= 0 {
 		pingPeriod = 54 * time.Second // Default fallback
 	}
+	if c.latencyInterval > 0 && c.latencyInterval < pingPeriod {
+		pingPeriod = c.latencyInterval
+	}
 	ticker := time.NewTicker(pingPeriod)
 	defer ticker.Stop()
 	
@@ -393,8 +1248,8 @@ func (c *NetworkClient) pingPump() {
 				return
 			}
 			
-			conn.SetWriteDeadline(time.Now().Add(c.writeWait))
-			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			deadline := time.Now().Add(c.writeWait)
+			if err := conn.WriteControl(websocket.PingMessage, pingPayload(time.Now()), deadline); err != nil {
 				c.logger.Error("Failed to send ping", zap.Error(err))
 				return
 			}
@@ -402,30 +1257,113 @@ func (c *NetworkClient) pingPump() {
 	}
 }
 
-// handleMessage processes incoming messages
-func (c *NetworkClient) handleMessage(messageBytes []byte) {
-	var msg Message
-	if err := json.Unmarshal(messageBytes, &msg); err != nil {
-		c.logger.Error("Failed to parse message", zap.Error(err))
-		return
+// handleMessage passes an incoming frame through the middleware chain
+func (c *NetworkClient) handleMessage(frameType int, messageBytes []byte) {
+	encoding := EncodingJSON
+	if frameType == websocket.BinaryMessage {
+		encoding = EncodingMsgPack
+	}
+	
+	c.mu.RLock()
+	pipeline := c.pipeline
+	c.mu.RUnlock()
+	
+	pipeline(&Inbound{
+		Raw:      messageBytes,
+		Encoding: encoding,
+		Received: time.Now(),
+	})
+}
+
+// dispatch ends the middleware chain: it updates the client's own state
+// from the message, then hands it to the event channel and every handler
+// of its type
+func (c *NetworkClient) dispatch(in *Inbound) {
+	msg := in.Message
+	if msg.Type == MsgRoomUpdate {
+		msg = c.completeRoomUpdate(msg)
+		c.trackBalance(msg)
+	}
+	if msg.Type == MsgRoomClosed {
+		c.leaveClosedRoom(msg)
+	}
+	if msg.Type == MsgJoinRoom {
+		c.trackSeatName(msg)
+	}
+	if msg.Type == MsgFindDuel {
+		c.trackDuelRoom(msg)
 	}
 	
 	// Send to event channel
 	select {
-	case c.eventChan <- &msg:
+	case c.eventChan <- msg:
 	default:
 		c.logger.Warn("Event channel full, dropping message")
 	}
 	
-	// Call specific handler if available
-	c.mu.RLock()
-	if handler, exists := c.messageHandlers[msg.Type]; exists {
-		c.mu.RUnlock()
-		handler(&msg)
-	} else {
-		c.mu.RUnlock()
+	// Call the handlers of the type, if any
+	handlers := c.messageHandlers.lookup(msg.Type)
+	if len(handlers) == 0 {
 		c.logger.Debug("No handler for message type", zap.String("type", string(msg.Type)))
 	}
+	for _, entry := range handlers {
+		entry.handler(msg)
+	}
+}
+
+// completeRoomUpdate turns a delta room update into the complete update
+// every handler expects
+func (c *NetworkClient) completeRoomUpdate(msg *Message) *Message {
+	var update RoomUpdateData
+	if err := msg.GetData(&update); err != nil {
+		return msg
+	}
+	
+	complete := *msg
+	complete.Data = c.room.apply(&update)
+	return &complete
+}
+
+// trackSeatName remembers the name the server seated the player under
+func (c *NetworkClient) trackSeatName(msg *Message) {
+	var joined RoomJoinData
+	if err := msg.GetData(&joined); err != nil {
+		return
+	}
+	
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if msg.RoomID == c.currentRoom {
+		c.seatName = joined.PlayerName
+	}
+}
+
+// trackDuelRoom moves the client to the duel room the server matched it
+// to, which it is seated in next
+func (c *NetworkClient) trackDuelRoom(msg *Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.currentRoom = msg.RoomID
+	c.watching = false
+	c.seatName = ""
+}
+
+// trackBalance remembers the player's balance from a room update so a
+// reconnect rejoins with it
+func (c *NetworkClient) trackBalance(msg *Message) {
+	var update RoomUpdateData
+	if err := msg.GetData(&update); err != nil {
+		return
+	}
+	
+	for _, player := range update.Players {
+		if player.ID == c.playerID {
+			c.mu.Lock()
+			c.balance = player.Balance
+			c.mu.Unlock()
+			return
+		}
+	}
 }
 
 // handleDisconnect handles connection loss and potential reconnection
@@ -436,8 +1374,21 @@ func (c *NetworkClient) handleDisconnect() {
 		c.conn.Close()
 		c.conn = nil
 	}
+	
+	// Attempt reconnection if configured; retrying is pointless once the
+	// server has refused our protocol version
+	upgradeErr := c.upgradeErr
+	reconnect := c.maxReconnects > 0 && c.reconnectCount < c.maxReconnects && c.ctx.Err() == nil && upgradeErr == nil
+	c.reconnecting = reconnect
 	c.mu.Unlock()
 	
+	if upgradeErr != nil {
+		if c.ctx.Err() == nil {
+			c.notifyConnection(ConnectionEvent{Status: ConnectionDown, Err: upgradeErr})
+		}
+		return
+	}
+	
 	c.logger.Warn("Connection lost")
 	
 	// Send error to error channel
@@ -446,9 +1397,10 @@ func (c *NetworkClient) handleDisconnect() {
 	default:
 	}
 	
-	// Attempt reconnection if configured
-	if c.maxReconnects > 0 && c.reconnectCount < c.maxReconnects {
+	if reconnect {
 		go c.attemptReconnect()
+	} else if c.ctx.Err() == nil {
+		c.notifyConnection(ConnectionEvent{Status: ConnectionDown, Err: errors.New("connection lost")})
 	}
 }
 
@@ -456,12 +1408,29 @@ func (c *NetworkClient) handleDisconnect() {
 func (c *NetworkClient) attemptReconnect() {
 	c.reconnectCount++
 	
+	// A busy server's retry hint stretches the wait for this attempt
+	c.mu.Lock()
+	delay := max(c.reconnectDelay, c.retryAfter)
+	c.retryAfter = 0
+	c.mu.Unlock()
+	
 	c.logger.Info("Attempting to reconnect",
 		zap.Int("attempt", c.reconnectCount),
 		zap.Int("max_attempts", c.maxReconnects),
 	)
+	c.notifyConnection(ConnectionEvent{
+		Status:      ConnectionRetrying,
+		Attempt:     c.reconnectCount,
+		MaxAttempts: c.maxReconnects,
+		RetryAt:     c.clock.Now().Add(delay),
+	})
 	
-	time.Sleep(c.reconnectDelay)
+	select {
+	case <-c.clock.After(delay):
+	case <-c.retryNow:
+	case <-c.ctx.Done():
+		return
+	}
 	
 	if err := c.Connect(); err != nil {
 		c.logger.Error("Reconnection failed", zap.Error(err))
@@ -469,22 +1438,38 @@ func (c *NetworkClient) attemptReconnect() {
 		if c.reconnectCount < c.maxReconnects {
 			go c.attemptReconnect()
 		} else {
+			c.mu.Lock()
+			c.reconnecting = false
+			c.mu.Unlock()
+			
+			err := errors.New("max reconnection attempts reached")
 			select {
-			case c.errorChan <- errors.New("max reconnection attempts reached"):
+			case c.errorChan <- err:
 			default:
 			}
+			c.notifyConnection(ConnectionEvent{Status: ConnectionDown, Err: err})
 		}
 		return
 	}
 	
 	// Re-join room if we were in one
-	c.mu.RLock()
+	c.mu.Lock()
+	c.reconnecting = false
 	roomID := c.currentRoom
-	c.mu.RUnlock()
+	balance := c.balance
+	watching := c.watching
+	c.mu.Unlock()
 	
-	if roomID != "" {
-		if err := c.JoinRoom(roomID, 1000); err != nil {
+	switch {
+	case roomID == "":
+	case watching:
+		if err := c.WatchRoom(roomID); err != nil {
+			c.logger.Error("Failed to watch room again after reconnect", zap.Error(err))
+		}
+	default:
+		if err := c.JoinRoom(roomID, balance); err != nil {
 			c.logger.Error("Failed to rejoin room after reconnect", zap.Error(err))
 		}
 	}
+	c.notifyConnection(ConnectionEvent{Status: ConnectionUp})
 }
\ No newline at end of file
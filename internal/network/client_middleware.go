@@ -0,0 +1,242 @@
+package network
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultDedupWindow is how many recent frames the client remembers to
+// drop a frame the server delivered twice
+const DefaultDedupWindow = 64
+
+// Inbound is a frame from the server on its way through the client's
+// middleware chain. Message is set by the decoding stage; a stage that
+// drops the frame sets Err or Duplicate and does not call the next one.
+type Inbound struct {
+	Raw       []byte
+	Encoding  Encoding
+	Received  time.Time
+	Message   *Message
+	Err       error
+	Duplicate bool
+}
+
+// InboundHandler handles a frame in the client's middleware chain
+type InboundHandler func(*Inbound)
+
+// Middleware wraps the rest of the client's middleware chain. It may look
+// at or change the frame before calling next, skip next to drop it, or
+// act once next has returned.
+type Middleware func(next InboundHandler) InboundHandler
+
+// chain composes middleware around the final handler, the first one
+// running first
+func chain(final InboundHandler, middleware ...Middleware) InboundHandler {
+	handler := final
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// LoggingMiddleware logs every frame once the rest of the chain has
+// handled it, and frames that failed to decode
+func LoggingMiddleware(logger *zap.Logger) Middleware {
+	return func(next InboundHandler) InboundHandler {
+		return func(in *Inbound) {
+			next(in)
+
+			switch {
+			case in.Err != nil:
+				logger.Error("Failed to parse message", zap.Int("bytes", len(in.Raw)), zap.Error(in.Err))
+			case in.Duplicate:
+				logger.Debug("Dropped duplicate message", zap.String("type", string(in.Message.Type)))
+			case in.Message != nil:
+				logger.Debug("Message handled",
+					zap.String("type", string(in.Message.Type)),
+					zap.Int("bytes", len(in.Raw)),
+					zap.Duration("took", time.Since(in.Received)),
+				)
+			}
+		}
+	}
+}
+
+// MessageStats counts the frames a client has received
+type MessageStats struct {
+	Frames     int
+	Bytes      int
+	Failed     int
+	Duplicates int
+	ByType     map[MessageType]int
+	// HandleTime is the total time spent handling frames
+	HandleTime time.Duration
+}
+
+// MessageMetrics collects MessageStats from a client's middleware chain
+type MessageMetrics struct {
+	mu    sync.Mutex
+	stats MessageStats
+}
+
+// NewMessageMetrics creates empty message metrics
+func NewMessageMetrics() *MessageMetrics {
+	return &MessageMetrics{stats: MessageStats{ByType: make(map[MessageType]int)}}
+}
+
+// Middleware records every frame once the rest of the chain has handled it
+func (m *MessageMetrics) Middleware() Middleware {
+	return func(next InboundHandler) InboundHandler {
+		return func(in *Inbound) {
+			next(in)
+			took := time.Since(in.Received)
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			m.stats.Frames++
+			m.stats.Bytes += len(in.Raw)
+			m.stats.HandleTime += took
+			switch {
+			case in.Err != nil:
+				m.stats.Failed++
+			case in.Duplicate:
+				m.stats.Duplicates++
+			case in.Message != nil:
+				m.stats.ByType[in.Message.Type]++
+			}
+		}
+	}
+}
+
+// Stats returns a copy of the counts so far
+func (m *MessageMetrics) Stats() MessageStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats
+	stats.ByType = make(map[MessageType]int, len(m.stats.ByType))
+	for msgType, count := range m.stats.ByType {
+		stats.ByType[msgType] = count
+	}
+	return stats
+}
+
+// DecodingMiddleware decodes the frame into its Message, dropping frames
+// that fail to decode
+func DecodingMiddleware() Middleware {
+	return func(next InboundHandler) InboundHandler {
+		return func(in *Inbound) {
+			msg, err := DecodeMessage(in.Raw, in.Encoding)
+			if err != nil {
+				in.Err = err
+				return
+			}
+			in.Message = msg
+			next(in)
+		}
+	}
+}
+
+// DedupMiddleware drops a frame identical to one of the last window
+// frames, such as one the server delivered twice. Every message carries
+// the time it was sent, so distinct messages never match.
+func DedupMiddleware(window int) Middleware {
+	if window < 1 {
+		window = DefaultDedupWindow
+	}
+
+	var mu sync.Mutex
+	seen := make(map[uint64]struct{}, window)
+	recent := make([]uint64, 0, window)
+
+	return func(next InboundHandler) InboundHandler {
+		return func(in *Inbound) {
+			hash := fnv.New64a()
+			hash.Write(in.Raw)
+			sum := hash.Sum64()
+
+			mu.Lock()
+			if _, duplicate := seen[sum]; duplicate {
+				mu.Unlock()
+				in.Duplicate = true
+				return
+			}
+			if len(recent) == window {
+				delete(seen, recent[0])
+				recent = recent[1:]
+			}
+			seen[sum] = struct{}{}
+			recent = append(recent, sum)
+			mu.Unlock()
+
+			next(in)
+		}
+	}
+}
+
+// handlerEntry is one handler registered for a message type
+type handlerEntry struct {
+	id      uint64
+	handler func(*Message)
+}
+
+// handlerSet holds the handlers of every message type, called in the
+// order they were added
+type handlerSet struct {
+	mu       sync.RWMutex
+	next     uint64
+	handlers map[MessageType][]handlerEntry
+}
+
+func newHandlerSet() *handlerSet {
+	return &handlerSet{handlers: make(map[MessageType][]handlerEntry)}
+}
+
+// add registers a handler and returns a function removing it
+func (s *handlerSet) add(msgType MessageType, handler func(*Message)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next++
+	id := s.next
+	s.handlers[msgType] = append(s.handlers[msgType], handlerEntry{id: id, handler: handler})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { s.remove(msgType, id) })
+	}
+}
+
+// set replaces every handler of the type with handler
+func (s *handlerSet) set(msgType MessageType, handler func(*Message)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next++
+	s.handlers[msgType] = []handlerEntry{{id: s.next, handler: handler}}
+}
+
+func (s *handlerSet) remove(msgType MessageType, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.handlers[msgType]
+	for i, entry := range entries {
+		if entry.id == id {
+			// Copied, so a dispatch already holding the old slice is unaffected
+			s.handlers[msgType] = append(append([]handlerEntry(nil), entries[:i]...), entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// lookup returns the handlers of a message type
+func (s *handlerSet) lookup(msgType MessageType) []handlerEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.handlers[msgType]
+}
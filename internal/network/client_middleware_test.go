@@ -0,0 +1,90 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// receive feeds a message to the client as if it came from the server
+func receive(t *testing.T, client *NetworkClient, msg *Message) []byte {
+	t.Helper()
+
+	data, err := msg.Encode(EncodingJSON)
+	require.NoError(t, err)
+	client.handleMessage(websocket.TextMessage, data)
+	return data
+}
+
+func TestNetworkClient_LayersHandlers(t *testing.T) {
+	client := NewNetworkClient(nil, "p1", "Player 1", zaptest.NewLogger(t))
+	defer client.Disconnect()
+
+	var calls []string
+	client.AddMessageHandler(MsgChat, func(*Message) { calls = append(calls, "first") })
+	removeSecond := client.AddMessageHandler(MsgChat, func(*Message) { calls = append(calls, "second") })
+	client.AddMessageHandler(MsgChat, func(*Message) { calls = append(calls, "third") })
+
+	receive(t, client, NewMessage(MsgChat, "r1", "p2", ChatData{Text: "hi"}))
+	assert.Equal(t, []string{"first", "second", "third"}, calls)
+
+	calls = nil
+	removeSecond()
+	removeSecond()
+	receive(t, client, NewMessage(MsgChat, "r1", "p2", ChatData{Text: "again"}))
+	assert.Equal(t, []string{"first", "third"}, calls)
+
+	calls = nil
+	client.SetMessageHandler(MsgChat, func(*Message) { calls = append(calls, "only") })
+	receive(t, client, NewMessage(MsgChat, "r1", "p2", ChatData{Text: "once more"}))
+	assert.Equal(t, []string{"only"}, calls)
+}
+
+func TestNetworkClient_MiddlewareChain(t *testing.T) {
+	client := NewNetworkClient(nil, "p1", "Player 1", zaptest.NewLogger(t))
+	defer client.Disconnect()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next InboundHandler) InboundHandler {
+			return func(in *Inbound) {
+				require.NotNil(t, in.Message, "added middleware sees decoded messages")
+				order = append(order, name)
+				if in.Message.Type == MsgTimerUpdate {
+					return
+				}
+				next(in)
+			}
+		}
+	}
+	client.Use(trace("outer"), trace("inner"))
+
+	handled := 0
+	client.AddMessageHandler(MsgChat, func(*Message) {
+		order = append(order, "handler")
+		handled++
+	})
+	client.AddMessageHandler(MsgTimerUpdate, func(*Message) { handled++ })
+
+	frame := receive(t, client, NewMessage(MsgChat, "r1", "p2", ChatData{Text: "hi"}))
+	assert.Equal(t, []string{"outer", "inner", "handler"}, order)
+
+	// A frame delivered twice reaches the handlers once
+	client.handleMessage(websocket.TextMessage, frame)
+	assert.Equal(t, 1, handled)
+
+	// Middleware can drop a message before the handlers
+	receive(t, client, NewMessage(MsgTimerUpdate, "r1", "", TimerData{SecondsLeft: 5}))
+	assert.Equal(t, 1, handled)
+
+	client.handleMessage(websocket.TextMessage, []byte("{not json"))
+
+	stats := client.MessageStats()
+	assert.Equal(t, 4, stats.Frames)
+	assert.Equal(t, 1, stats.Duplicates)
+	assert.Equal(t, 1, stats.Failed)
+	assert.Equal(t, map[MessageType]int{MsgChat: 1, MsgTimerUpdate: 1}, stats.ByType)
+}
@@ -0,0 +1,80 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNetworkClient_ReconnectsAndRejoins(t *testing.T) {
+	serverConfig := DefaultServerConfig()
+	serverConfig.StartingBalance = 250 * game.Dollar
+	server := NewServer(serverConfig, zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+
+	config := DefaultClientConfig()
+	config.ServerURL = "ws://" + listener.Addr().String() + "/ws"
+	config.ReconnectDelay = 20 * time.Millisecond
+
+	client := NewNetworkClient(config, "p1", "Player 1", zaptest.NewLogger(t))
+	defer client.Disconnect()
+
+	events := make(chan ConnectionEvent, 10)
+	client.SetConnectionHandler(func(event ConnectionEvent) {
+		events <- event
+	})
+
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.JoinRoom("r1", 250*game.Dollar))
+	waitFor(t, func() bool {
+		room, ok := server.GetRoom("r1")
+		return ok && len(room.GetPlayers()) == 1
+	})
+
+	// Drop the connection from the server side
+	server.clients.each(func(c *Client) {
+		c.conn.Close()
+	})
+
+	retrying := <-events
+	assert.Equal(t, ConnectionRetrying, retrying.Status)
+	assert.Equal(t, 1, retrying.Attempt)
+	assert.False(t, retrying.RetryAt.IsZero())
+
+	up := <-events
+	assert.Equal(t, ConnectionUp, up.Status)
+	assert.True(t, client.IsConnected())
+
+	// The client rejoins its room with the balance the server kept for it
+	waitFor(t, func() bool {
+		room, _ := server.GetRoom("r1")
+		player, ok := room.GetPlayers()["p1"]
+		return ok && player.IsOnline && player.Balance == 250*game.Dollar
+	})
+}
@@ -0,0 +1,112 @@
+// Package network provides message encoding for multiplayer coin flip games
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"coinflip-game/internal/game"
+)
+
+// Encoding identifies the wire format used for the Message envelope
+type Encoding string
+
+const (
+	// EncodingJSON is the default text encoding understood by every client
+	EncodingJSON Encoding = "json"
+	// EncodingMsgPack is a compact binary encoding negotiated at connect time
+	EncodingMsgPack Encoding = "msgpack"
+)
+
+// Money is sent as a decimal number of dollars in both encodings, the
+// same as before amounts were kept in cents
+func init() {
+	msgpack.Register(game.Money(0),
+		func(enc *msgpack.Encoder, v reflect.Value) error {
+			return enc.EncodeFloat64(v.Interface().(game.Money).Float64())
+		},
+		func(dec *msgpack.Decoder, v reflect.Value) error {
+			amount, err := dec.DecodeFloat64()
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(game.NewMoney(amount)))
+			return nil
+		},
+	)
+}
+
+// Subprotocols offered during the WebSocket handshake, in server preference order.
+// A client that offers no subprotocol gets JSON for backward compatibility.
+const (
+	SubprotocolJSON    = "coinflip.json"
+	SubprotocolMsgPack = "coinflip.msgpack"
+)
+
+// SupportedSubprotocols lists the subprotocols the server accepts
+var SupportedSubprotocols = []string{SubprotocolMsgPack, SubprotocolJSON}
+
+// Subprotocol returns the WebSocket subprotocol name for the encoding
+func (e Encoding) Subprotocol() string {
+	if e == EncodingMsgPack {
+		return SubprotocolMsgPack
+	}
+	return SubprotocolJSON
+}
+
+// IsBinary reports whether the encoding must be sent as binary frames
+func (e Encoding) IsBinary() bool {
+	return e == EncodingMsgPack
+}
+
+// EncodingForSubprotocol maps a negotiated subprotocol back to an encoding
+func EncodingForSubprotocol(subprotocol string) Encoding {
+	if subprotocol == SubprotocolMsgPack {
+		return EncodingMsgPack
+	}
+	return EncodingJSON
+}
+
+// Encode serializes the message using the given encoding
+func (m *Message) Encode(encoding Encoding) ([]byte, error) {
+	switch encoding {
+	case EncodingJSON, "":
+		return m.ToJSON()
+	case EncodingMsgPack:
+		var buf bytes.Buffer
+		enc := msgpack.NewEncoder(&buf)
+		enc.SetCustomStructTag("json")
+		if err := enc.Encode(m); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s", encoding)
+	}
+}
+
+// DecodeMessage deserializes a message using the given encoding
+func DecodeMessage(data []byte, encoding Encoding) (*Message, error) {
+	switch encoding {
+	case EncodingJSON, "":
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, err
+		}
+		return &msg, nil
+	case EncodingMsgPack:
+		var msg Message
+		dec := msgpack.NewDecoder(bytes.NewReader(data))
+		dec.SetCustomStructTag("json")
+		if err := dec.Decode(&msg); err != nil {
+			return nil, err
+		}
+		return &msg, nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s", encoding)
+	}
+}
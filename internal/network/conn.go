@@ -0,0 +1,30 @@
+package network
+
+import (
+	"net"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsConn is the part of a *websocket.Conn that clients on either end use.
+// Tests wrap it to drop, delay or cut off frames.
+type wsConn interface {
+	ReadMessage() (messageType int, data []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+	SetReadLimit(limit int64)
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+	SetPongHandler(handler func(appData string) error)
+	RemoteAddr() net.Addr
+	Close() error
+}
+
+// wrapConn passes a new connection through wrap, when one is set
+func wrapConn(wrap func(wsConn) wsConn, conn *websocket.Conn) wsConn {
+	if wrap == nil {
+		return conn
+	}
+	return wrap(conn)
+}
@@ -0,0 +1,188 @@
+package network
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+)
+
+// Charge takes funds from a player's balance outside of a round, such as
+// for a purchase, recording the reason in the audit trail
+func (r *GameRoom) Charge(playerID string, amount game.Money, reason string) (game.Money, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.players[playerID]
+	if !exists {
+		return 0, ErrPlayerNotFound
+	}
+	if player.Balance < amount {
+		return 0, game.ErrInsufficientBalance
+	}
+
+	player.Balance -= amount
+	r.lastActivity = r.clock.Now()
+
+	r.audit.Record(logger.AuditEvent{
+		Time:     r.clock.Now(),
+		Event:    logger.AuditPurchase,
+		PlayerID: playerID,
+		RoomID:   r.id,
+		Amount:   amount.Float64(),
+		Balance:  player.Balance.Float64(),
+		Reason:   reason,
+	})
+
+	r.broadcastRoomUpdate()
+	return player.Balance, nil
+}
+
+// SetSkin changes the coin skin a player shows to the room
+func (r *GameRoom) SetSkin(playerID, skin string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.players[playerID]
+	if !exists {
+		return ErrPlayerNotFound
+	}
+
+	player.Skin = skin
+	r.broadcastRoomUpdate()
+	return nil
+}
+
+// Inventory returns a player's cosmetics and the balance of their bankroll
+func (s *Server) Inventory(ctx context.Context, playerID string, room *GameRoom) (*SkinData, error) {
+	if playerID == "" {
+		return nil, ErrPlayerNotFound
+	}
+
+	player := s.playerRecord(ctx, playerID)
+	return s.skinReply(playerID, room, player), nil
+}
+
+// BuySkin unlocks a coin skin for a player, paying for it from their
+// bankroll: the balance of the room they are playing in, or otherwise the
+// balance the server keeps on record for them. A freeroll's stack cannot
+// pay for skins.
+func (s *Server) BuySkin(ctx context.Context, playerID string, room *GameRoom, skinID string) (*SkinData, error) {
+	if playerID == "" {
+		return nil, ErrPlayerNotFound
+	}
+	room = bankrollRoom(room)
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	player := s.playerRecord(ctx, playerID)
+	skin, err := player.Inventory.Unlock(skinID)
+	if err != nil {
+		return nil, err
+	}
+	reason := "coin skin " + skin.ID
+
+	if room != nil {
+		balance, err := room.Charge(playerID, skin.Price, reason)
+		if err != nil {
+			return nil, err
+		}
+		player.Balance = balance
+	} else {
+		if player.Balance < skin.Price {
+			return nil, game.ErrInsufficientBalance
+		}
+		player.Balance -= skin.Price
+		s.config.Audit.Record(logger.AuditEvent{
+			Time:     s.scheduler.Clock().Now(),
+			Event:    logger.AuditPurchase,
+			PlayerID: playerID,
+			Amount:   skin.Price.Float64(),
+			Balance:  player.Balance.Float64(),
+			Reason:   reason,
+		})
+	}
+
+	if err := s.results.SavePlayer(ctx, player); err != nil {
+		s.logger.Error("Failed to record skin purchase",
+			zap.String("player_id", playerID),
+			zap.Error(err),
+		)
+	}
+
+	s.logger.Info("Coin skin purchased",
+		zap.String("player_id", playerID),
+		zap.String("skin", skin.ID),
+		zap.Float64("price", skin.Price.Float64()),
+	)
+
+	reply := s.skinReply(playerID, room, player)
+	reply.SkinID = skin.ID
+	return reply, nil
+}
+
+// EquipSkin switches a player to a coin skin they own and shows it to
+// their room
+func (s *Server) EquipSkin(ctx context.Context, playerID string, room *GameRoom, skinID string) (*SkinData, error) {
+	if playerID == "" {
+		return nil, ErrPlayerNotFound
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	player := s.playerRecord(ctx, playerID)
+	if err := player.Inventory.Equip(skinID); err != nil {
+		return nil, err
+	}
+
+	if err := s.results.SavePlayer(ctx, player); err != nil {
+		return nil, err
+	}
+	if room != nil {
+		room.SetSkin(playerID, skinID)
+	}
+
+	reply := s.skinReply(playerID, room, player)
+	reply.SkinID = skinID
+	return reply, nil
+}
+
+// equippedSkin returns the coin skin a player has equipped
+func (s *Server) equippedSkin(ctx context.Context, playerID string) string {
+	player, err := s.results.GetPlayer(ctx, playerID)
+	if err != nil {
+		return game.DefaultSkin
+	}
+	return player.Inventory.EquippedSkin()
+}
+
+// playerRecord returns the player the server keeps on record, or a new
+// one with the starting balance for players it hasn't seen
+func (s *Server) playerRecord(ctx context.Context, playerID string) *game.Player {
+	player, err := s.results.GetPlayer(ctx, playerID)
+	if err != nil {
+		return &game.Player{ID: playerID, Balance: s.config.StartingBalance}
+	}
+	return player
+}
+
+// skinReply reports a player's inventory with the balance of the bankroll
+// purchases are paid from
+func (s *Server) skinReply(playerID string, room *GameRoom, player *game.Player) *SkinData {
+	balance := player.Balance
+	if room := bankrollRoom(room); room != nil {
+		if seat, exists := room.GetPlayers()[playerID]; exists {
+			balance = seat.Balance
+		}
+	}
+
+	inventory := player.Inventory.Clone()
+	return &SkinData{
+		Balance:   balance,
+		Inventory: &inventory,
+	}
+}
@@ -0,0 +1,69 @@
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestServer_BuyAndEquipSkin(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+
+	room, err := server.CreateRoom("r1", "Room 1", DefaultRoomConfig())
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 150*game.Dollar))
+
+	_, err = server.EquipSkin(ctx, "p1", room, "pixel")
+	assert.ErrorIs(t, err, game.ErrSkinNotOwned)
+
+	// Purchases are paid from the room balance
+	bought, err := server.BuySkin(ctx, "p1", room, "pixel")
+	require.NoError(t, err)
+	assert.Equal(t, 50*game.Dollar, bought.Balance)
+	assert.True(t, bought.Inventory.Owns("pixel"))
+	assert.Equal(t, 50*game.Dollar, room.GetPlayers()["p1"].Balance)
+
+	_, err = server.BuySkin(ctx, "p1", room, "pixel")
+	assert.ErrorIs(t, err, game.ErrSkinOwned)
+	_, err = server.BuySkin(ctx, "p1", room, "golden")
+	assert.ErrorIs(t, err, game.ErrInsufficientBalance)
+	assert.Equal(t, 50*game.Dollar, room.GetPlayers()["p1"].Balance)
+
+	// The equipped skin is shown to the room and kept for the next join
+	_, err = server.EquipSkin(ctx, "p1", room, "pixel")
+	require.NoError(t, err)
+	assert.Equal(t, "pixel", room.GetPlayers()["p1"].Skin)
+	assert.Equal(t, "pixel", server.equippedSkin(ctx, "p1"))
+
+	inventory, err := server.Inventory(ctx, "p1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "pixel", inventory.Inventory.EquippedSkin())
+}
+
+func TestClient_SkinsAreTheConnectionsOwn(t *testing.T) {
+	server, _ := newCleanupServer(t)
+	ctx := context.Background()
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "alice", Balance: 500 * game.Dollar}))
+
+	// A connection acting for no one cannot spend another player's
+	// balance by naming them on the message
+	stranger := newCleanupClient(t, server, "")
+	stranger.handleSkin(NewMessage(MsgBuySkin, "", "alice", SkinData{SkinID: "pixel"}))
+	stranger.handleRedeemCode(NewMessage(MsgRedeemCode, "", "alice", RedeemCodeData{Code: "WELCOME"}))
+	assert.Equal(t, []ErrorData{
+		{Code: "skin_failed", Message: ErrNotIdentified.Error()},
+		{Code: "redeem_failed", Message: ErrNotIdentified.Error()},
+	}, sentErrors(t, stranger))
+
+	alice, err := server.Results().GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 500*game.Dollar, alice.Balance)
+	assert.False(t, alice.Inventory.Owns("pixel"))
+}
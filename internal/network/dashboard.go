@@ -0,0 +1,317 @@
+package network
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"mime"
+	"net/http"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+	"coinflip-game/internal/storage"
+)
+
+const (
+	// DashboardLogLines is how many recent log lines the dashboard shows
+	DashboardLogLines = 100
+	// DashboardRounds is how many recent rounds the dashboard lists
+	DashboardRounds = 20
+	// DashboardVolumeWindow is how far back the dashboard sums bet volume
+	DashboardVolumeWindow = 24 * time.Hour
+	// dashboardVolumeRounds bounds how many rounds are read to sum it
+	dashboardVolumeRounds = 10000
+)
+
+// AdminCredentials are the username and password the admin dashboard asks
+// for with HTTP basic authentication
+type AdminCredentials struct {
+	Username string
+	Password string
+}
+
+// Enabled reports whether both a username and a password are set
+func (c AdminCredentials) Enabled() bool {
+	return c.Username != "" && c.Password != ""
+}
+
+//go:embed dashboard.html
+var dashboardHTML string
+
+// dashboardTemplate renders the admin dashboard page
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"money": func(m game.Money) string { return m.Format() },
+	"clock": func(t time.Time) string { return t.Format("15:04:05") },
+	"pot":   roomPot,
+}).Parse(dashboardHTML))
+
+// Dashboard is what the admin dashboard shows: live rooms and players,
+// recent bet volume and rounds, and recent log lines
+type Dashboard struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Rooms       []*RoomSnapshot     `json:"rooms"`
+	Players     []PlayerSession     `json:"players"`
+	Clients     int                 `json:"clients"`
+	Volume      BetVolume           `json:"volume"`
+	Rounds      []*storage.Round    `json:"rounds"`
+	Logs        []logger.LogEntry   `json:"logs"`
+	Storage     storage.MemoryStats `json:"storage"`
+
+	// Token must accompany the dashboard's actions
+	Token string `json:"-"`
+}
+
+// BetVolume sums the rounds settled since a time
+type BetVolume struct {
+	Since   time.Time  `json:"since"`
+	Rounds  int        `json:"rounds"`
+	Bets    int        `json:"bets"`
+	Wagered game.Money `json:"wagered"`
+	PaidOut game.Money `json:"paid_out"`
+}
+
+// newDashboardLogs keeps the server's recent log lines for the dashboard
+func newDashboardLogs() *logger.LogBuffer {
+	return logger.NewLogBuffer(DashboardLogLines, zapcore.InfoLevel)
+}
+
+// newAdminToken returns a random token the dashboard's forms send back,
+// so another site cannot submit them with the administrator's credentials
+func newAdminToken() string {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		panic("failed to generate admin token: " + err.Error())
+	}
+	return hex.EncodeToString(token)
+}
+
+// Dashboard gathers what the admin dashboard shows
+func (s *Server) Dashboard(ctx context.Context) (*Dashboard, error) {
+	now := s.scheduler.Clock().Now()
+	dashboard := &Dashboard{
+		GeneratedAt: now,
+		Players:     s.sessions.Sessions(),
+		Logs:        s.logs.Entries(),
+		Storage:     s.results.Stats(),
+		Token:       s.adminToken,
+	}
+
+	dashboard.Clients = s.clients.len()
+	s.mu.RLock()
+	for _, room := range s.rooms {
+		dashboard.Rooms = append(dashboard.Rooms, room.Snapshot())
+	}
+	s.mu.RUnlock()
+	sort.Slice(dashboard.Rooms, func(i, j int) bool {
+		return dashboard.Rooms[i].ID < dashboard.Rooms[j].ID
+	})
+
+	rounds, err := s.rounds.GetRounds(ctx, "", dashboardVolumeRounds)
+	if err != nil {
+		return nil, err
+	}
+	dashboard.Volume = betVolume(rounds, now.Add(-DashboardVolumeWindow))
+	dashboard.Rounds = rounds[:min(len(rounds), DashboardRounds)]
+	return dashboard, nil
+}
+
+// betVolume sums the rounds, newest first, settled after since
+func betVolume(rounds []*storage.Round, since time.Time) BetVolume {
+	volume := BetVolume{Since: since}
+	for _, round := range rounds {
+		if round.SettledAt.Before(since) {
+			break
+		}
+		volume.Rounds++
+		for _, outcome := range round.Outcomes {
+			volume.Bets += len(outcome.Bets)
+			volume.Wagered += outcome.Wagered
+			volume.PaidOut += outcome.Payout + outcome.Insurance
+		}
+	}
+	return volume
+}
+
+// roomPot sums the bets open in a room's round
+func roomPot(room *RoomSnapshot) game.Money {
+	var pot game.Money
+	for _, player := range room.Players {
+		for _, bet := range player.Bets {
+			if !bet.Practice {
+				pot += bet.Amount
+			}
+		}
+	}
+	return pot
+}
+
+// CloseRoom closes a room on an administrator's behalf. Seated players
+// leave first, so their open bets are refunded and their balances go back
+// to their wallets.
+func (s *Server) CloseRoom(roomID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[roomID]
+	if !exists {
+		return ErrRoomNotFound
+	}
+	for playerID := range room.GetPlayers() {
+		s.unseat(room, playerID)
+	}
+	s.closeRoom(room, RoomClosedByAdmin)
+	return nil
+}
+
+// KickPlayer removes a player from a room on an administrator's behalf.
+// Their open bets are refunded and their client is sent back to the
+// lobby; they may join a room again.
+func (s *Server) KickPlayer(roomID, playerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[roomID]
+	if !exists {
+		return ErrRoomNotFound
+	}
+	if err := s.unseat(room, playerID); err != nil {
+		return err
+	}
+
+	for _, client := range s.clients.members(room) {
+		if client.playerID != playerID || !s.clients.leave(client, room) {
+			continue
+		}
+		client.room = nil
+		client.sendMessage(NewMessage(MsgRoomClosed, roomID, playerID, RoomClosedData{
+			RoomID: roomID,
+			Reason: RoomClosedKicked,
+		}))
+	}
+
+	s.logger.Info("Player kicked",
+		zap.String("room_id", roomID),
+		zap.String("player_id", playerID),
+	)
+	s.recordEvent(ServerEvent{
+		Type:     EventPlayerKicked,
+		RoomID:   roomID,
+		PlayerID: playerID,
+		Message:  "Player kicked",
+	})
+	return nil
+}
+
+// unseat removes a player from a room and records the balance they leave
+// with. Callers must hold s.mu.
+func (s *Server) unseat(room *GameRoom, playerID string) error {
+	balance, err := room.removePlayer(playerID)
+	if err != nil {
+		return err
+	}
+	s.sessions.LeaveRoom(playerID, room.ID(), balance)
+	s.storeBalance(s.ctx, playerID, room, balance)
+	return nil
+}
+
+// requireAdmin serves the handler only to requests carrying the admin
+// credentials. Without credentials configured the dashboard is disabled.
+// Posts from the dashboard's forms must also carry its token; JSON
+// requests need none, as another site cannot send them without the
+// browser asking first.
+func (s *Server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		credentials := s.config.Admin
+		if !credentials.Enabled() {
+			http.Error(w, "admin dashboard is disabled", http.StatusNotFound)
+			return
+		}
+
+		username, password, ok := r.BasicAuth()
+		if !ok || !secureEqual(username, credentials.Username) || !secureEqual(password, credentials.Password) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="coinflip admin", charset="UTF-8"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method == http.MethodPost && !isJSON(r) && !secureEqual(r.FormValue("token"), s.adminToken) {
+			http.Error(w, "invalid dashboard token", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// isJSON reports whether the request says its body is JSON
+func isJSON(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// secureEqual compares secrets in constant time
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// handleDashboard renders the admin dashboard page
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	dashboard, err := s.Dashboard(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTemplate.Execute(w, dashboard); err != nil {
+		s.logger.Error("Failed to render admin dashboard", zap.Error(err))
+	}
+}
+
+// handleDashboardData returns what the dashboard shows as JSON
+func (s *Server) handleDashboardData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	dashboard, err := s.Dashboard(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "dashboard_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(dashboard)
+}
+
+// handleCloseRoom closes a room from the dashboard
+func (s *Server) handleCloseRoom(w http.ResponseWriter, r *http.Request) {
+	s.dashboardAction(w, r, s.CloseRoom(r.PathValue("id")))
+}
+
+// handleKickPlayer removes a player from a room from the dashboard
+func (s *Server) handleKickPlayer(w http.ResponseWriter, r *http.Request) {
+	s.dashboardAction(w, r, s.KickPlayer(r.PathValue("id"), r.PathValue("player")))
+}
+
+// dashboardAction returns to the dashboard after an action, or reports
+// why it failed
+func (s *Server) dashboardAction(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrRoomNotFound), errors.Is(err, ErrPlayerNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		http.Redirect(w, r, "/admin", http.StatusSeeOther)
+	}
+}
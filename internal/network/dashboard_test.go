@@ -0,0 +1,192 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+// adminRequest builds a dashboard request carrying the admin credentials
+func adminRequest(method, target string, form url.Values) *http.Request {
+	var request *http.Request
+	if form != nil {
+		request = httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		request = httptest.NewRequest(method, target, nil)
+	}
+	request.SetBasicAuth("admin", "secret")
+	return request
+}
+
+// adminAPIRequest builds a request with the admin credentials and a JSON
+// body, as coinflip-admin sends them
+func adminAPIRequest(method, target, body string) *http.Request {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.SetBasicAuth("admin", "secret")
+	return request
+}
+
+func TestServer_AdminEndpointsRequireCredentials(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Admin = AdminCredentials{Username: "admin", Password: "secret"}
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	handler := server.Handler()
+
+	endpoints := []struct {
+		method, target, body string
+		status               int
+	}{
+		{http.MethodPost, "/admin/archive", "", http.StatusServiceUnavailable},
+		{http.MethodGet, "/admin/promos", "", http.StatusOK},
+		{http.MethodPost, "/admin/promos", `{"code":"WELCOME","value":5,"max_uses":1}`, http.StatusCreated},
+		{http.MethodGet, "/admin/sessions", "", http.StatusOK},
+		{http.MethodGet, "/admin/promotions", "", http.StatusOK},
+		{http.MethodDelete, "/admin/promotions/none", "", http.StatusNotFound},
+	}
+	for _, endpoint := range endpoints {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(endpoint.method, endpoint.target, strings.NewReader(endpoint.body)))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, "%s %s without credentials", endpoint.method, endpoint.target)
+
+		// JSON requests need no dashboard token
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, adminAPIRequest(endpoint.method, endpoint.target, endpoint.body))
+		assert.Equal(t, endpoint.status, recorder.Code, "%s %s", endpoint.method, endpoint.target)
+	}
+	promos, err := server.promos.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, promos, 1, "only the authenticated request created a promo code")
+}
+
+func TestServer_DashboardRequiresCredentials(t *testing.T) {
+	disabled := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(disabled.Stop)
+
+	recorder := httptest.NewRecorder()
+	disabled.Handler().ServeHTTP(recorder, adminRequest(http.MethodGet, "/admin", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "no credentials configured")
+
+	config := DefaultServerConfig()
+	config.Admin = AdminCredentials{Username: "admin", Password: "secret"}
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	handler := server.Handler()
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.NotEmpty(t, recorder.Header().Get("WWW-Authenticate"))
+
+	wrong := httptest.NewRequest(http.MethodGet, "/admin/dashboard", nil)
+	wrong.SetBasicAuth("admin", "guess")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, wrong)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	room, err := server.CreateRoom("r1", "High Rollers", nil)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Big Spender", 100*game.Dollar))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, adminRequest(http.MethodGet, "/admin", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "High Rollers")
+	assert.Contains(t, recorder.Body.String(), "/admin/rooms/r1/players/p1/kick")
+
+	// Actions need the token the page embeds in its forms
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, adminRequest(http.MethodPost, "/admin/rooms/r1/close", url.Values{"token": {"forged"}}))
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	_, exists := server.GetRoom("r1")
+	assert.True(t, exists)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, adminRequest(http.MethodPost, "/admin/rooms/r1/close", url.Values{"token": {server.adminToken}}))
+	assert.Equal(t, http.StatusSeeOther, recorder.Code)
+	_, exists = server.GetRoom("r1")
+	assert.False(t, exists)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, adminRequest(http.MethodPost, "/admin/rooms/r1/close", url.Values{"token": {server.adminToken}}))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestServer_KickPlayerRefundsBets(t *testing.T) {
+	server, fake := newCleanupServer(t)
+	config := DefaultRoomConfig()
+	config.MinPlayers = 1
+	room, err := server.CreateRoom("r1", "Room 1", config)
+	require.NoError(t, err)
+
+	client := newCleanupClient(t, server, "p1")
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	server.mu.Lock()
+	server.clients.move(client, room)
+	client.room = room
+	server.mu.Unlock()
+
+	server.scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+	require.NoError(t, room.PlaceBet("p1", 30*game.Dollar, game.Heads))
+
+	require.NoError(t, server.KickPlayer("r1", "p1"))
+	assert.NotContains(t, room.GetPlayers(), "p1")
+	assert.Contains(t, room.GetPlayers(), "p2")
+	assert.Nil(t, client.room)
+	assert.Equal(t, []RoomClosedData{{RoomID: "r1", Reason: RoomClosedKicked}}, sentRoomClosed(t, client))
+	assert.Equal(t, 100*game.Dollar, server.playerRecord(context.Background(), "p1").Balance, "the open bet is refunded")
+
+	assert.ErrorIs(t, server.KickPlayer("r1", "p1"), ErrPlayerNotFound)
+	assert.ErrorIs(t, server.KickPlayer("r2", "p2"), ErrRoomNotFound)
+}
+
+func TestServer_CloseRoomReturnsBalances(t *testing.T) {
+	server, fake := newCleanupServer(t)
+	config := DefaultRoomConfig()
+	config.MinPlayers = 1
+	room, err := server.CreateRoom("r1", "Room 1", config)
+	require.NoError(t, err)
+
+	client := newCleanupClient(t, server, "p1")
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	server.mu.Lock()
+	server.clients.move(client, room)
+	client.room = room
+	server.mu.Unlock()
+
+	server.scheduler.advance(fake.Now())
+	require.NoError(t, room.PlaceBet("p1", 40*game.Dollar, game.Tails))
+
+	require.NoError(t, server.CloseRoom("r1"))
+	_, exists := server.GetRoom("r1")
+	assert.False(t, exists)
+	assert.Equal(t, []RoomClosedData{{RoomID: "r1", Reason: RoomClosedByAdmin}}, sentRoomClosed(t, client))
+	assert.Equal(t, 100*game.Dollar, server.playerRecord(context.Background(), "p1").Balance)
+
+	dashboard, err := server.Dashboard(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, dashboard.Rooms)
+	data, err := json.Marshal(dashboard)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), server.adminToken, "the action token stays out of the JSON")
+
+	var closed bool
+	for _, entry := range dashboard.Logs {
+		closed = closed || entry.Message == "Room closed"
+	}
+	assert.True(t, closed, "recent logs are kept for the dashboard")
+}
@@ -0,0 +1,267 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+)
+
+// DuelRoom is a duel room open to everyone, as the duel lobby lists it
+type DuelRoom struct {
+	RoomID    string    `json:"room_id"`
+	Name      string    `json:"name"`
+	GameState GameState `json:"game_state"`
+	Duelists  []Duelist `json:"duelists"`
+	// Open rooms have a duelist waiting for an opponent in the rating
+	// band starting at Bucket
+	Open   bool `json:"open"`
+	Bucket int  `json:"bucket"`
+}
+
+// Duelist is a player seated in a duel room, with their rating
+type Duelist struct {
+	PlayerID string `json:"player_id"`
+	Name     string `json:"name"`
+	Rating   int    `json:"rating"`
+}
+
+// SetRatings shows the players' duel ratings to the room. Players not
+// seated are skipped.
+func (r *GameRoom) SetRatings(ratings map[string]int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := false
+	for playerID, rating := range ratings {
+		if player, exists := r.players[playerID]; exists && player.Rating != rating {
+			player.Rating = rating
+			changed = true
+		}
+	}
+	if changed {
+		r.broadcastRoomUpdate()
+	}
+}
+
+// DuelRating returns a player's duel rating
+func (s *Server) DuelRating(ctx context.Context, playerID string) game.DuelRating {
+	return s.playerRecord(ctx, playerID).Rating()
+}
+
+// recordDuel rates the two players of a settled duel round: the one whose
+// stakes netted more wins, and equal nets are a draw. Rounds of other
+// modes, and duels with practice bets or a player who did not bet, are not
+// rated.
+func (s *Server) recordDuel(ctx context.Context, room *GameRoom, data *GameResultData) {
+	if room.GetConfig().Mode != ModeDuel {
+		return
+	}
+	var duelists []PlayerResult
+	for _, outcomes := range [][]PlayerResult{data.Winners, data.Losers} {
+		for _, outcome := range outcomes {
+			if len(outcome.Bets) > 0 && !outcome.Practice {
+				duelists = append(duelists, outcome)
+			}
+		}
+	}
+	if len(duelists) != DuelSeats {
+		return
+	}
+
+	first, second := duelists[0], duelists[1]
+	score := game.DuelDraw
+	switch net := first.Payout + first.Insurance - first.Wagered; {
+	case net > second.Payout+second.Insurance-second.Wagered:
+		score = game.DuelWin
+	case net < second.Payout+second.Insurance-second.Wagered:
+		score = game.DuelLoss
+	}
+
+	// Serialized with the other changes to players' records
+	s.inventoryMu.Lock()
+	players := []*game.Player{s.playerRecord(ctx, first.PlayerID), s.playerRecord(ctx, second.PlayerID)}
+	firstRating, secondRating := game.RateDuel(players[0].Rating(), players[1].Rating(), score)
+	players[0].Duel, players[1].Duel = &firstRating, &secondRating
+	for _, player := range players {
+		if err := s.results.SavePlayer(ctx, player); err != nil {
+			s.logger.Error("Failed to record duel rating",
+				zap.String("player_id", player.ID),
+				zap.String("round_id", data.RoundID),
+				zap.Error(err),
+			)
+		}
+	}
+	s.inventoryMu.Unlock()
+
+	room.SetRatings(map[string]int{
+		first.PlayerID:  firstRating.Rating,
+		second.PlayerID: secondRating.Rating,
+	})
+	s.logger.Info("Duel rated",
+		zap.String("room_id", room.ID()),
+		zap.String("round_id", data.RoundID),
+		zap.String("player_id", first.PlayerID),
+		zap.Int("rating", firstRating.Rating),
+		zap.String("opponent_id", second.PlayerID),
+		zap.Int("opponent_rating", secondRating.Rating),
+		zap.Float64("score", score),
+	)
+}
+
+// showRating shows a player just seated in a duel room their rating
+func (s *Server) showRating(room *GameRoom, playerID string) {
+	if room.GetConfig().Mode != ModeDuel {
+		return
+	}
+	room.SetRatings(map[string]int{playerID: s.DuelRating(s.ctx, playerID).Rating})
+}
+
+// matchDuel returns the duel room a player rated rating should join: the
+// one they already wait in, or the open room whose waiting duelist is in
+// the same rating band and closest to them, or else a new room for the
+// band, opened with role. Callers must hold s.duelMu, so the room is still
+// open when joined.
+func (s *Server) matchDuel(playerID string, role game.Role, rating game.DuelRating) (*GameRoom, error) {
+	var match *GameRoom
+	closest := 0
+	for _, duel := range s.DuelLobby() {
+		if !duel.Open || duel.Bucket != rating.Bucket() {
+			continue
+		}
+		waiting := duel.Duelists[0]
+		if waiting.PlayerID == playerID {
+			room, exists := s.GetRoom(duel.RoomID)
+			if exists {
+				return room, nil
+			}
+			continue
+		}
+		gap := max(waiting.Rating-rating.Rating, rating.Rating-waiting.Rating)
+		if match != nil && gap >= closest {
+			continue
+		}
+		if room, exists := s.GetRoom(duel.RoomID); exists {
+			match, closest = room, gap
+		}
+	}
+	if match != nil {
+		return match, nil
+	}
+
+	config, err := s.NewRoomConfig(&RoomSettings{Mode: string(ModeDuel)})
+	if err != nil {
+		return nil, err
+	}
+	roomID := ""
+	for roomID == "" {
+		s.duelRooms++
+		roomID = fmt.Sprintf("duel-%d-%d", rating.Bucket(), s.duelRooms)
+		if _, exists := s.GetRoom(roomID); exists {
+			roomID = ""
+		}
+	}
+	return s.createRoomAs(playerID, role, roomID, "Duel "+game.RatingBucketName(rating.Bucket()), config)
+}
+
+// DuelLobby lists the duel rooms open to everyone, those with a duelist
+// waiting for an opponent first, then by room ID
+func (s *Server) DuelLobby() []DuelRoom {
+	s.mu.RLock()
+	rooms := make([]*GameRoom, 0, len(s.rooms))
+	for _, room := range s.rooms {
+		rooms = append(rooms, room)
+	}
+	s.mu.RUnlock()
+
+	duels := make([]DuelRoom, 0)
+	for _, room := range rooms {
+		// Votes change a room's settings under its own lock
+		config := room.GetConfig()
+		if config.Mode != ModeDuel || config.Private {
+			continue
+		}
+		duel := DuelRoom{
+			RoomID:    room.ID(),
+			Name:      room.Name(),
+			GameState: room.GetGameState(),
+			Duelists:  make([]Duelist, 0, DuelSeats),
+		}
+		for _, player := range room.GetPlayers() {
+			duel.Duelists = append(duel.Duelists, Duelist{PlayerID: player.ID, Name: player.Name, Rating: player.Rating})
+		}
+		sort.Slice(duel.Duelists, func(i, j int) bool { return duel.Duelists[i].PlayerID < duel.Duelists[j].PlayerID })
+		if len(duel.Duelists) == 1 {
+			duel.Open = true
+			duel.Bucket = game.RatingBucket(duel.Duelists[0].Rating)
+		}
+		duels = append(duels, duel)
+	}
+	sort.Slice(duels, func(i, j int) bool {
+		if duels[i].Open != duels[j].Open {
+			return duels[i].Open
+		}
+		return duels[i].RoomID < duels[j].RoomID
+	})
+	return duels
+}
+
+// handleDuels returns the duel lobby
+func (s *Server) handleDuels(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	duels := s.DuelLobby()
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"duels": duels,
+		"total": len(duels),
+	})
+}
+
+// handleFindDuel seats the client's player in a duel against a player
+// rated close to them, opening a duel room for them to wait in when there
+// is none
+func (c *Client) handleFindDuel(msg *Message) {
+	var find FindDuelData
+	if err := msg.GetData(&find); err != nil {
+		c.sendError("invalid_data", "Invalid find duel data")
+		return
+	}
+
+	// A connection acts for the player it connected or first joined as
+	if c.playerID != "" && msg.PlayerID != c.playerID {
+		c.sendError("duel_failed", ErrWrongPlayer.Error())
+		return
+	}
+
+	// Held until the player is seated, so the next player looking for a
+	// duel finds them waiting
+	c.server.duelMu.Lock()
+	defer c.server.duelMu.Unlock()
+
+	rating := c.server.DuelRating(c.server.ctx, msg.PlayerID)
+	room, err := c.server.matchDuel(msg.PlayerID, c.connectionRole(), rating)
+	var busy *ServerBusyError
+	if errors.As(err, &busy) {
+		c.sendBusy(busy)
+		return
+	}
+	if err != nil {
+		c.sendError("duel_failed", err.Error())
+		return
+	}
+
+	c.sendMessage(NewMessage(MsgFindDuel, room.ID(), msg.PlayerID, FindDuelData{
+		PlayerName: find.PlayerName,
+		Rating:     &rating,
+		Bucket:     rating.Bucket(),
+	}))
+	c.handleJoinRoom(NewMessage(MsgJoinRoom, room.ID(), msg.PlayerID, RoomJoinData{
+		PlayerName: find.PlayerName,
+	}))
+}
@@ -0,0 +1,141 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestRoomConfig_DuelMode(t *testing.T) {
+	duel, err := DefaultRoomConfig().WithSettings(&RoomSettings{Mode: string(ModeDuel), MaxPlayers: 6})
+	require.NoError(t, err)
+	assert.Equal(t, DuelSeats, duel.MinPlayers)
+	assert.Equal(t, DuelSeats, duel.MaxPlayers, "the mode seats two whatever else was asked for")
+	assert.Equal(t, ParimutuelModeRake, duel.Rake)
+	assert.True(t, duel.Mode.Pooled())
+
+	// A vote cannot open a third seat
+	_, err = duel.WithSettings(&RoomSettings{MaxPlayers: 3})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+}
+
+func TestServer_RecordDuel(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+	duelConfig, err := DefaultRoomConfig().WithSettings(&RoomSettings{Mode: string(ModeDuel)})
+	require.NoError(t, err)
+	room, err := server.CreateRoom("duel", "Duel", duelConfig)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "p2", Duel: &game.DuelRating{Rating: 1700, Peak: 1700}}))
+
+	round := &GameResultData{
+		RoundID:    "round_1",
+		CoinResult: game.Heads,
+		Winners: []PlayerResult{
+			{PlayerID: "p1", Bets: []*BetData{{Amount: 10 * game.Dollar, Choice: game.Heads}}, Wagered: 10 * game.Dollar, Won: true, Payout: 19 * game.Dollar},
+		},
+		Losers: []PlayerResult{
+			{PlayerID: "p2", Bets: []*BetData{{Amount: 10 * game.Dollar, Choice: game.Tails}}, Wagered: 10 * game.Dollar},
+		},
+	}
+	server.recordDuel(ctx, room, round)
+
+	// The underdog's win takes 24 points from the favourite
+	assert.Equal(t, game.DuelRating{Rating: 1524, Peak: 1524, Duels: 1, Wins: 1}, server.DuelRating(ctx, "p1"))
+	assert.Equal(t, game.DuelRating{Rating: 1676, Peak: 1700, Duels: 1, Losses: 1}, server.DuelRating(ctx, "p2"))
+	players := room.GetPlayers()
+	assert.Equal(t, 1524, players["p1"].Rating)
+	assert.Equal(t, 1676, players["p2"].Rating)
+
+	// Rounds outside duel rooms are not rated
+	classic, err := server.CreateRoom("classic", "Classic", DefaultRoomConfig())
+	require.NoError(t, err)
+	server.recordDuel(ctx, classic, round)
+	assert.Equal(t, 1, server.DuelRating(ctx, "p1").Duels)
+
+	// Nor are duels one player sat out
+	round.Losers[0].Bets = nil
+	server.recordDuel(ctx, room, round)
+	assert.Equal(t, 1, server.DuelRating(ctx, "p1").Duels)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /players/{id}/stats", server.handlePlayerStats)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/players/p2/stats", nil))
+	var response PlayerStatsData
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.NotNil(t, response.Rating)
+	assert.Equal(t, 1676, response.Rating.Rating)
+}
+
+func TestServer_FindDuel(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+	require.NoError(t, server.Results().SavePlayer(context.Background(),
+		&game.Player{ID: "expert", Duel: &game.DuelRating{Rating: 1900, Peak: 1900, Duels: 40}}))
+
+	findDuel := func(playerID string) *NetworkClient {
+		t.Helper()
+		config := DefaultClientConfig()
+		config.ServerURL = "ws://" + listener.Addr().String() + "/ws"
+		client := NewNetworkClient(config, playerID, playerID, zaptest.NewLogger(t))
+		t.Cleanup(client.Disconnect)
+		require.NoError(t, client.Connect())
+		require.NoError(t, client.FindDuel())
+		waitFor(t, func() bool {
+			room, ok := server.GetRoom(client.GetCurrentRoom())
+			return ok && room.GetPlayers()[playerID] != nil
+		})
+		return client
+	}
+
+	// The first player waits in a room for their band, which the next
+	// player of the band joins
+	first := findDuel("p1")
+	assert.Equal(t, "duel-1400-1", first.GetCurrentRoom())
+	lobby := server.DuelLobby()
+	require.Len(t, lobby, 1)
+	assert.True(t, lobby[0].Open)
+	assert.Equal(t, 1400, lobby[0].Bucket)
+	assert.Equal(t, []Duelist{{PlayerID: "p1", Name: "p1", Rating: game.DefaultRating}}, lobby[0].Duelists)
+
+	second := findDuel("p2")
+	assert.Equal(t, first.GetCurrentRoom(), second.GetCurrentRoom())
+
+	// A player far above them is not matched with a waiting player
+	// outside their band
+	expert := findDuel("expert")
+	assert.Equal(t, "duel-1800-2", expert.GetCurrentRoom())
+
+	recorder := httptest.NewRecorder()
+	server.handleDuels(recorder, httptest.NewRequest(http.MethodGet, "/duels", nil))
+	var response struct {
+		Duels []DuelRoom `json:"duels"`
+		Total int        `json:"total"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.Equal(t, 2, response.Total)
+	assert.Equal(t, "duel-1800-2", response.Duels[0].RoomID, "open rooms are listed first")
+	assert.Equal(t, 1900, response.Duels[0].Duelists[0].Rating)
+	assert.False(t, response.Duels[1].Open)
+	assert.Len(t, response.Duels[1].Duelists, 2)
+}
@@ -0,0 +1,57 @@
+package network
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultEarlyCloseDelay is how long betting stays open after the last
+// connected player has bet, in rooms that close betting early
+const DefaultEarlyCloseDelay = 5 * time.Second
+
+// checkEarlyClose brings the end of betting forward to EarlyCloseDelay from
+// now once every connected player has bet, so small rooms need not wait
+// out the full betting phase. The deadline only ever moves earlier. Callers
+// must hold r.mu.
+func (r *GameRoom) checkEarlyClose() {
+	if !r.config.EarlyCloseEnabled || r.gameState != StateBetting || r.currentRound == nil {
+		return
+	}
+	if !r.everyoneHasBet() {
+		return
+	}
+
+	end := r.clock.Now().Add(r.config.EarlyCloseDelay)
+	if !end.Before(r.timerEnd) {
+		return
+	}
+	r.timerEnd = end
+	r.scheduleBettingEnd(r.timerEnd.Add(r.bettingGrace))
+
+	r.logger.Info("Closing betting early",
+		zap.String("room_id", r.id),
+		zap.String("round_id", r.currentRound.ID),
+		zap.Duration("delay", r.config.EarlyCloseDelay),
+	)
+	timer := r.bettingTimer(r.config.EarlyCloseDelay)
+	timer.EarlyClose = true
+	r.broadcastMessage(NewMessage(MsgTimerUpdate, r.id, "", timer))
+}
+
+// everyoneHasBet reports whether the room has connected players and each
+// of them has a bet in the current round. Idle players are not waited for.
+// Callers must hold r.mu.
+func (r *GameRoom) everyoneHasBet() bool {
+	online := 0
+	for id, player := range r.players {
+		if !player.IsOnline || player.Idle {
+			continue
+		}
+		online++
+		if len(r.currentRound.Bets[id]) == 0 {
+			return false
+		}
+	}
+	return online > 0
+}
@@ -0,0 +1,85 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+func TestGameRoom_EarlyClose(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.config.EarlyCloseEnabled = true
+	room.config.EarlyCloseDelay = 2 * time.Second
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	// Betting stays open while a connected player has yet to bet
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	fake.Advance(3 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+
+	// The last bet brings the deadline forward
+	drainEvents(room)
+	require.NoError(t, room.PlaceBet("p2", 10*game.Dollar, game.Tails))
+	var closing *TimerData
+	for _, msg := range drainEvents(room) {
+		var timer TimerData
+		if msg.Type == MsgTimerUpdate && msg.GetData(&timer) == nil && timer.EarlyClose {
+			closing = &timer
+		}
+	}
+	require.NotNil(t, closing)
+	assert.Equal(t, 2, closing.SecondsLeft)
+
+	fake.Advance(time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateBetting, room.GetGameState())
+	fake.Advance(time.Second)
+	scheduler.advance(fake.Now())
+	assert.NotEqual(t, StateBetting, room.GetGameState())
+}
+
+func TestGameRoom_EarlyCloseDisabled(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.config.EarlyCloseDelay = 2 * time.Second
+
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	fake.Advance(5 * time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateBetting, room.GetGameState())
+}
+
+func TestGameRoom_EarlyCloseWhenLastBettorLeaves(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.config.EarlyCloseEnabled = true
+	room.config.EarlyCloseDelay = 2 * time.Second
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	_, err := room.removePlayer("p2")
+	require.NoError(t, err)
+
+	fake.Advance(2 * time.Second)
+	scheduler.advance(fake.Now())
+	assert.NotEqual(t, StateBetting, room.GetGameState())
+}
+
+func TestRoomConfig_EarlyCloseSettings(t *testing.T) {
+	config, err := DefaultRoomConfig().WithSettings(&RoomSettings{EarlyClose: true, EarlyCloseSeconds: 3})
+	require.NoError(t, err)
+	assert.True(t, config.EarlyCloseEnabled)
+	assert.Equal(t, 3*time.Second, config.EarlyCloseDelay)
+
+	restored, err := DefaultRoomConfig().WithSettings(config.Settings())
+	require.NoError(t, err)
+	assert.True(t, restored.EarlyCloseEnabled)
+	assert.Equal(t, 3*time.Second, restored.EarlyCloseDelay)
+
+	// The delay cannot outlast the betting phase it shortens
+	_, err = DefaultRoomConfig().WithSettings(&RoomSettings{BettingSeconds: 5, EarlyClose: true, EarlyCloseSeconds: 10})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+}
@@ -0,0 +1,278 @@
+package network
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"coinflip-game/internal/game"
+)
+
+const (
+	// DefaultEventLogSize is how many server events are kept
+	DefaultEventLogSize = 1000
+	// DefaultBigWin is the profit from one round at which a win is listed
+	// in the event log
+	DefaultBigWin = 100 * game.Dollar
+	// eventPageSize is how many events the admin event page lists
+	eventPageSize = 200
+)
+
+//go:embed events.html
+var eventsHTML string
+
+// eventsTemplate renders the admin event page
+var eventsTemplate = template.Must(template.New("events").Funcs(template.FuncMap{
+	"money": func(m game.Money) string { return m.Format() },
+	"stamp": func(t time.Time) string { return t.Format("2006-01-02 15:04:05") },
+}).Parse(eventsHTML))
+
+// Kinds of server event
+const (
+	EventRoomCreated  = "room_created"
+	EventRoomClosed   = "room_closed"
+	EventPlayerKicked = "player_kicked"
+	EventError        = "error"
+	EventBigWin       = "big_win"
+)
+
+// EventTypes lists the kinds of server event, in the order the admin event
+// page offers them
+func EventTypes() []string {
+	return []string{EventRoomCreated, EventRoomClosed, EventPlayerKicked, EventError, EventBigWin}
+}
+
+// ServerEvent is something operators may want to look back on: a room
+// opening or closing, a kick, an error, or a big win
+type ServerEvent struct {
+	Time     time.Time  `json:"time"`
+	Type     string     `json:"type"`
+	RoomID   string     `json:"room_id,omitempty"`
+	PlayerID string     `json:"player_id,omitempty"`
+	Message  string     `json:"message"`
+	Amount   game.Money `json:"amount,omitempty"`
+}
+
+// EventFilter picks server events; empty fields match every event
+type EventFilter struct {
+	Type   string
+	RoomID string
+	// Limit caps how many events are returned; 0 returns all that match
+	Limit int
+}
+
+// matches reports whether the event passes the filter
+func (f EventFilter) matches(event ServerEvent) bool {
+	return (f.Type == "" || event.Type == f.Type) && (f.RoomID == "" || event.RoomID == f.RoomID)
+}
+
+// EventLog keeps the most recent server events in a ring buffer
+type EventLog struct {
+	mu     sync.Mutex
+	events []ServerEvent
+	next   int
+	full   bool
+}
+
+// NewEventLog keeps the last size events
+func NewEventLog(size int) *EventLog {
+	if size < 1 {
+		size = 1
+	}
+	return &EventLog{events: make([]ServerEvent, size)}
+}
+
+// Record adds an event, replacing the oldest once the log is full
+func (l *EventLog) Record(event ServerEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events[l.next] = event
+	l.next = (l.next + 1) % len(l.events)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Events returns the events passing the filter, newest first
+func (l *EventLog) Events(filter EventFilter) []ServerEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.events)
+	}
+	events := make([]ServerEvent, 0)
+	for i := 1; i <= count; i++ {
+		event := l.events[(l.next-i+len(l.events))%len(l.events)]
+		if !filter.matches(event) {
+			continue
+		}
+		events = append(events, event)
+		if filter.Limit > 0 && len(events) == filter.Limit {
+			break
+		}
+	}
+	return events
+}
+
+// Attach returns a logger that also records every line logged at error
+// level or above as an EventError, keeping log's options
+func (l *EventLog) Attach(log *zap.Logger) *zap.Logger {
+	return log.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, &eventCore{log: l})
+	}))
+}
+
+// eventCore is a zapcore.Core recording error lines in an EventLog, with
+// the room and player they name
+type eventCore struct {
+	log    *EventLog
+	fields []zapcore.Field
+}
+
+// Enabled reports whether lines at the level are recorded
+func (c *eventCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel
+}
+
+// With returns a core adding the fields to every line, sharing the log
+func (c *eventCore) With(fields []zapcore.Field) zapcore.Core {
+	return &eventCore{
+		log:    c.log,
+		fields: append(append([]zapcore.Field(nil), c.fields...), fields...),
+	}
+}
+
+// Check adds the core to the entry's cores when its level is recorded
+func (c *eventCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write records the line as an error event
+func (c *eventCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(encoder)
+	}
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	event := ServerEvent{Time: entry.Time, Type: EventError, Message: entry.Message}
+	event.RoomID, _ = encoder.Fields["room_id"].(string)
+	event.PlayerID, _ = encoder.Fields["player_id"].(string)
+	if err, ok := encoder.Fields["error"].(string); ok {
+		event.Message += ": " + err
+	}
+	c.log.Record(event)
+	return nil
+}
+
+// Sync has nothing to flush
+func (c *eventCore) Sync() error {
+	return nil
+}
+
+// recordEvent adds an event to the server's event log, stamped now
+func (s *Server) recordEvent(event ServerEvent) {
+	event.Time = s.scheduler.Clock().Now()
+	s.events.Record(event)
+}
+
+// recordBigWins lists every player whose profit from the round reached the
+// server's big win threshold
+func (s *Server) recordBigWins(room *GameRoom, data *GameResultData) {
+	if s.config.BigWin <= 0 {
+		return
+	}
+	for _, outcome := range data.Winners {
+		profit := outcome.Payout + outcome.Insurance - outcome.Wagered
+		if outcome.Practice || profit < s.config.BigWin {
+			continue
+		}
+		s.recordEvent(ServerEvent{
+			Type:     EventBigWin,
+			RoomID:   room.ID(),
+			PlayerID: outcome.PlayerID,
+			Message:  fmt.Sprintf("%s won %s on %s", outcome.PlayerName, profit.Format(), data.CoinResult),
+			Amount:   profit,
+		})
+	}
+}
+
+// eventFilter reads an event filter from the type, room and limit query
+// parameters
+func eventFilter(r *http.Request) (EventFilter, error) {
+	query := r.URL.Query()
+	filter := EventFilter{Type: query.Get("type"), RoomID: query.Get("room")}
+	if filter.Type != "" {
+		known := false
+		for _, eventType := range EventTypes() {
+			known = known || eventType == filter.Type
+		}
+		if !known {
+			return filter, fmt.Errorf("unknown event type %q", filter.Type)
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return filter, fmt.Errorf("limit must be a positive number")
+		}
+		filter.Limit = n
+	}
+	return filter, nil
+}
+
+// handleEvents lists recent server events as JSON, newest first
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter, err := eventFilter(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{Code: "invalid_filter", Message: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events": s.events.Events(filter),
+	})
+}
+
+// eventPage is what the admin event page shows
+type eventPage struct {
+	Filter EventFilter
+	Types  []string
+	Events []ServerEvent
+}
+
+// handleEventPage renders the admin event page
+func (s *Server) handleEventPage(w http.ResponseWriter, r *http.Request) {
+	filter, err := eventFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.Limit == 0 {
+		filter.Limit = eventPageSize
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	page := eventPage{Filter: filter, Types: EventTypes(), Events: s.events.Events(filter)}
+	if err := eventsTemplate.Execute(w, page); err != nil {
+		s.logger.Error("Failed to render admin event page", zap.Error(err))
+	}
+}
@@ -0,0 +1,108 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestEventLog_KeepsNewestAndFilters(t *testing.T) {
+	log := NewEventLog(3)
+	log.Record(ServerEvent{Type: EventRoomCreated, RoomID: "r1"})
+	log.Record(ServerEvent{Type: EventRoomCreated, RoomID: "r2"})
+	log.Record(ServerEvent{Type: EventPlayerKicked, RoomID: "r1", PlayerID: "p1"})
+	log.Record(ServerEvent{Type: EventRoomClosed, RoomID: "r1"})
+
+	rooms := func(events []ServerEvent) []string {
+		ids := make([]string, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.Type+":"+event.RoomID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"room_closed:r1", "player_kicked:r1", "room_created:r2"}, rooms(log.Events(EventFilter{})),
+		"the oldest event makes way, newest first")
+	assert.Equal(t, []string{"room_closed:r1", "player_kicked:r1"}, rooms(log.Events(EventFilter{RoomID: "r1"})))
+	assert.Equal(t, []string{"room_created:r2"}, rooms(log.Events(EventFilter{Type: EventRoomCreated})))
+	assert.Equal(t, []string{"room_closed:r1"}, rooms(log.Events(EventFilter{Limit: 1})))
+	assert.Empty(t, log.Events(EventFilter{Type: EventBigWin}))
+}
+
+func TestEventLog_RecordsErrorLines(t *testing.T) {
+	log := NewEventLog(10)
+	logger := log.Attach(zaptest.NewLogger(t)).With(zap.String("room_id", "r1"))
+
+	logger.Info("Round started")
+	logger.Error("Failed to save round", zap.String("player_id", "p1"), zap.Error(errors.New("disk full")))
+
+	events := log.Events(EventFilter{})
+	require.Len(t, events, 1)
+	assert.Equal(t, EventError, events[0].Type)
+	assert.Equal(t, "r1", events[0].RoomID)
+	assert.Equal(t, "p1", events[0].PlayerID)
+	assert.Equal(t, "Failed to save round: disk full", events[0].Message)
+}
+
+func TestServer_Events(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Admin = AdminCredentials{Username: "admin", Password: "secret"}
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+
+	room, err := server.CreateRoom("r1", "High Rollers", nil)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Big Spender", 100*game.Dollar))
+	require.NoError(t, server.KickPlayer("r1", "p1"))
+	server.recordBigWins(room, &GameResultData{
+		CoinResult: game.Heads,
+		Winners: []PlayerResult{
+			{PlayerID: "p2", PlayerName: "Lucky", Wagered: 100 * game.Dollar, Payout: 200 * game.Dollar},
+			{PlayerID: "p3", PlayerName: "Modest", Wagered: 10 * game.Dollar, Payout: 20 * game.Dollar},
+		},
+	})
+	require.NoError(t, server.CloseRoom("r1"))
+
+	events := func(query string) []ServerEvent {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, adminRequest(http.MethodGet, "/admin/events"+query, nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Events []ServerEvent `json:"events"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+		return response.Events
+	}
+	all := events("?room=r1")
+	require.Len(t, all, 4)
+	assert.Equal(t, EventRoomClosed, all[0].Type)
+	assert.Equal(t, EventBigWin, all[1].Type)
+	assert.Equal(t, "p2", all[1].PlayerID)
+	assert.Equal(t, 100*game.Dollar, all[1].Amount)
+	assert.Equal(t, EventPlayerKicked, all[2].Type)
+	assert.Equal(t, EventRoomCreated, all[3].Type)
+	assert.Len(t, events("?type=big_win"), 1)
+	assert.Empty(t, events("?room=r2"))
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, adminRequest(http.MethodGet, "/admin/events?type=nope", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin/events", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, adminRequest(http.MethodGet, "/admin/events/view?type=big_win", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Lucky won $100.00 on heads")
+	assert.NotContains(t, recorder.Body.String(), "Player kicked")
+}
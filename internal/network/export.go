@@ -0,0 +1,253 @@
+package network
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExportFormat is the file format rounds are exported in
+type ExportFormat string
+
+const (
+	ExportCSV ExportFormat = "csv"
+)
+
+// exportTimeLayout stamps rotated export files, as in rounds-20240101T000000Z.csv
+const exportTimeLayout = "20060102T150405Z"
+
+// ErrUnsupportedExportFormat is returned for export formats this build
+// cannot write
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+// exportHeader names the columns of an export file. Each row is one bet;
+// a round nobody bet on still gets a row with the player columns empty.
+var exportHeader = []string{
+	"round_id", "room_id", "timestamp", "coin_result", "final_seed",
+	"player_id", "player_name", "bet_id", "choice", "amount", "premium",
+	"insured", "practice", "won", "payout", "multiplier", "insurance",
+	"new_balance", "win_streak",
+}
+
+// ExportConfig controls where completed rounds are exported and when the
+// export file is rotated
+type ExportConfig struct {
+	// Path is the export file. Rotated files are stamped with the time
+	// they were started, rounds.csv becoming rounds-20240101T000000Z.csv.
+	Path   string
+	Format ExportFormat
+	// RotateInterval starts a new file every interval, aligned to UTC;
+	// RotateSize starts one once the current file reaches that many bytes.
+	// With neither set every round is appended to Path.
+	RotateInterval time.Duration
+	RotateSize     int64
+}
+
+// RoundExporter appends every completed round to a dataset that analytics
+// tools can load. A nil *RoundExporter discards rounds, so the server can
+// leave exporting unconfigured.
+type RoundExporter struct {
+	mu     sync.Mutex
+	config ExportConfig
+	now    func() time.Time
+
+	file   *os.File
+	writer *csv.Writer
+	period time.Time
+	size   int64
+}
+
+// NewRoundExporter creates an exporter, creating the export directory.
+// The first file is opened with the first round.
+func NewRoundExporter(config ExportConfig) (*RoundExporter, error) {
+	if config.Path == "" {
+		return nil, errors.New("export path cannot be empty")
+	}
+	if config.Format == "" {
+		config.Format = ExportCSV
+	}
+	if config.Format != ExportCSV {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedExportFormat, config.Format)
+	}
+	if config.RotateInterval < 0 || config.RotateSize < 0 {
+		return nil, errors.New("export rotation cannot be negative")
+	}
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	return &RoundExporter{
+		config: config,
+		now:    time.Now,
+	}, nil
+}
+
+// Export appends a completed round, one row per bet
+func (e *RoundExporter) Export(roomID string, data *GameResultData) error {
+	if e == nil || data == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.rotate(); err != nil {
+		return err
+	}
+
+	rows := exportRows(roomID, data)
+	for _, row := range rows {
+		if err := e.writer.Write(row); err != nil {
+			return fmt.Errorf("failed to export round %s: %w", data.RoundID, err)
+		}
+	}
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		return fmt.Errorf("failed to export round %s: %w", data.RoundID, err)
+	}
+
+	info, err := e.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to export round %s: %w", data.RoundID, err)
+	}
+	e.size = info.Size()
+	return nil
+}
+
+// Close closes the current export file
+func (e *RoundExporter) Close() error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.closeFile()
+}
+
+// rotate opens the file the next round belongs in, closing the current one
+// when its interval has passed or it has grown past the size limit.
+// Callers must hold e.mu.
+func (e *RoundExporter) rotate() error {
+	now := e.now().UTC()
+	period := time.Time{}
+	if e.config.RotateInterval > 0 {
+		period = now.Truncate(e.config.RotateInterval)
+	}
+
+	full := false
+	if e.file != nil {
+		full = e.config.RotateSize > 0 && e.size >= e.config.RotateSize
+		if !full && period.Equal(e.period) {
+			return nil
+		}
+		if err := e.closeFile(); err != nil {
+			return err
+		}
+	}
+
+	path := e.config.Path
+	if e.config.RotateInterval > 0 || e.config.RotateSize > 0 {
+		// A file started for a new interval is named for the interval, and
+		// one started because the last filled up for now
+		stamp := period
+		if full || period.IsZero() {
+			stamp = now
+		}
+		ext := filepath.Ext(path)
+		path = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(path, ext), stamp.Format(exportTimeLayout), ext)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open export file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open export file: %w", err)
+	}
+
+	e.file = file
+	e.writer = csv.NewWriter(file)
+	e.period = period
+	e.size = info.Size()
+
+	// Files appended to after a restart already have their header
+	if e.size == 0 {
+		if err := e.writer.Write(exportHeader); err != nil {
+			return fmt.Errorf("failed to write export header: %w", err)
+		}
+	}
+	return nil
+}
+
+// closeFile flushes and closes the current export file. Callers must hold
+// e.mu.
+func (e *RoundExporter) closeFile() error {
+	if e.file == nil {
+		return nil
+	}
+
+	e.writer.Flush()
+	err := e.writer.Error()
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+	e.file = nil
+	e.writer = nil
+	e.size = 0
+	if err != nil {
+		return fmt.Errorf("failed to close export file: %w", err)
+	}
+	return nil
+}
+
+// exportRows flattens a round into one row per bet, winners first
+func exportRows(roomID string, data *GameResultData) [][]string {
+	round := []string{
+		data.RoundID,
+		roomID,
+		data.Timestamp.UTC().Format(time.RFC3339Nano),
+		data.CoinResult.String(),
+		data.FinalSeed,
+	}
+
+	var rows [][]string
+	for _, outcomes := range [][]PlayerResult{data.Winners, data.Losers} {
+		for _, outcome := range outcomes {
+			for _, bet := range outcome.Bets {
+				row := append(append([]string(nil), round...),
+					outcome.PlayerID,
+					outcome.PlayerName,
+					bet.BetID,
+					bet.Choice.String(),
+					bet.Amount.String(),
+					bet.Premium.String(),
+					strconv.FormatBool(bet.Insured),
+					strconv.FormatBool(bet.Practice),
+					strconv.FormatBool(bet.Choice == data.CoinResult),
+					betPayout(bet, data.CoinResult, outcome).String(),
+					strconv.FormatFloat(betMultiplier(bet, data.CoinResult, outcome), 'f', -1, 64),
+					betInsurance(bet, data.CoinResult).String(),
+					outcome.NewBalance.String(),
+					strconv.Itoa(outcome.WinStreak),
+				)
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		row := append(round, make([]string, len(exportHeader)-len(round))...)
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,122 @@
+package network
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+// readExport returns the rows of an export file, header first
+func readExport(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	return rows
+}
+
+func exportedRound(roundID string) *GameResultData {
+	return &GameResultData{
+		RoundID:    roundID,
+		CoinResult: game.Heads,
+		FinalSeed:  "seed-" + roundID,
+		Timestamp:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Winners: []PlayerResult{{
+			PlayerID:   "alice",
+			PlayerName: "Alice",
+			Bets:       []*BetData{{BetID: "b1", Amount: 10 * game.Dollar, Choice: game.Heads}},
+			Payout:     20 * game.Dollar,
+			NewBalance: 110 * game.Dollar,
+			Multiplier: 1.25,
+			WinStreak:  2,
+		}},
+		Losers: []PlayerResult{{
+			PlayerID:   "bob",
+			PlayerName: "Bob, Jr.",
+			Bets: []*BetData{{BetID: "b2", Amount: 5 * game.Dollar, Choice: game.Tails,
+				Insured: true, Premium: 50 * game.Cent, Coverage: 250 * game.Cent}},
+			NewBalance: 97 * game.Dollar,
+			Insurance:  250 * game.Cent,
+		}},
+	}
+}
+
+func TestRoundExporter_CSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exports", "rounds.csv")
+	exporter, err := NewRoundExporter(ExportConfig{Path: path})
+	require.NoError(t, err)
+
+	require.NoError(t, exporter.Export("lobby", exportedRound("r1")))
+	require.NoError(t, exporter.Export("lobby", &GameResultData{RoundID: "r2", CoinResult: game.Tails}))
+	require.NoError(t, exporter.Close())
+
+	rows := readExport(t, path)
+	require.Len(t, rows, 4)
+	assert.Equal(t, exportHeader, rows[0])
+	assert.Equal(t, []string{"r1", "lobby", "2024-01-01T12:00:00Z", "heads", "seed-r1",
+		"alice", "Alice", "b1", "heads", "10.00", "0.00", "false", "false", "true",
+		"20.00", "1.25", "0.00", "110.00", "2"}, rows[1])
+	assert.Equal(t, []string{"r1", "lobby", "2024-01-01T12:00:00Z", "heads", "seed-r1",
+		"bob", "Bob, Jr.", "b2", "tails", "5.00", "0.50", "true", "false", "false",
+		"0.00", "0", "2.50", "97.00", "0"}, rows[2])
+
+	// A round without bets still appears, with the player columns empty
+	assert.Equal(t, "r2", rows[3][0])
+	assert.Equal(t, "", rows[3][5])
+
+	// Reopening appends to the file without repeating the header
+	exporter, err = NewRoundExporter(ExportConfig{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, exporter.Export("lobby", exportedRound("r3")))
+	require.NoError(t, exporter.Close())
+	rows = readExport(t, path)
+	require.Len(t, rows, 6)
+	assert.Equal(t, "r3", rows[5][0])
+}
+
+func TestRoundExporter_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rounds.csv")
+	exporter, err := NewRoundExporter(ExportConfig{Path: path, RotateInterval: time.Hour, RotateSize: 1 << 20})
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+	exporter.now = func() time.Time { return now }
+
+	require.NoError(t, exporter.Export("lobby", exportedRound("r1")))
+	require.NoError(t, exporter.Export("lobby", exportedRound("r2")))
+	now = now.Add(time.Hour)
+	require.NoError(t, exporter.Export("lobby", exportedRound("r3")))
+
+	// Files are named for the hour they cover
+	assert.Len(t, readExport(t, filepath.Join(dir, "rounds-20240101T120000Z.csv")), 5)
+	assert.Len(t, readExport(t, filepath.Join(dir, "rounds-20240101T130000Z.csv")), 3)
+
+	// A file past the size limit is followed by one named for now
+	exporter.config.RotateSize = 1
+	now = now.Add(time.Minute)
+	require.NoError(t, exporter.Export("lobby", exportedRound("r4")))
+	require.NoError(t, exporter.Close())
+	rows := readExport(t, filepath.Join(dir, "rounds-20240101T133100Z.csv"))
+	require.Len(t, rows, 3)
+	assert.Equal(t, "r4", rows[1][0])
+}
+
+func TestRoundExporter_Formats(t *testing.T) {
+	_, err := NewRoundExporter(ExportConfig{Path: filepath.Join(t.TempDir(), "rounds.parquet"), Format: "parquet"})
+	assert.ErrorIs(t, err, ErrUnsupportedExportFormat)
+
+	// A nil exporter discards rounds
+	var exporter *RoundExporter
+	assert.NoError(t, exporter.Export("lobby", exportedRound("r1")))
+	assert.NoError(t, exporter.Close())
+}
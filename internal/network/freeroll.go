@@ -0,0 +1,97 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+
+	"coinflip-game/internal/game"
+)
+
+// Freeroll seats every player in a room with the same stack instead of
+// their bankroll. The bankroll on record is left alone while they play:
+// when they leave, what they won above the stack is paid into it at the
+// conversion rate, and what they lost costs them nothing.
+type Freeroll struct {
+	// Stack is what each player is seated with; zero plays from the
+	// bankroll
+	Stack game.Money
+	// MaxStack is the largest stack room settings may choose, zero
+	// allowing any. The operator sets it; players cannot.
+	MaxStack game.Money
+	// ConversionRate is what each dollar won above Stack is worth in the
+	// bankroll, from 0 to 1. The operator sets it; players cannot.
+	ConversionRate float64
+}
+
+// Enabled reports whether the room seats players with its own stack
+func (f Freeroll) Enabled() bool {
+	return f.Stack > 0
+}
+
+// Validate checks the stack is within the operator's cap and the
+// conversion rate is a share
+func (f Freeroll) Validate() error {
+	if f.Stack < 0 || f.MaxStack < 0 {
+		return errors.New("starting stacks must not be negative")
+	}
+	if f.MaxStack > 0 && f.Stack > f.MaxStack {
+		return fmt.Errorf("starting stack %s is above the server's limit of %s", f.Stack.Format(), f.MaxStack.Format())
+	}
+	if f.ConversionRate < 0 || f.ConversionRate > 1 {
+		return fmt.Errorf("freeroll conversion rate must be between 0 and 1, got %.2f", f.ConversionRate)
+	}
+	return nil
+}
+
+// Winnings returns what a player leaving with stack is paid into their
+// bankroll: their profit on the starting stack at the conversion rate, and
+// nothing for breaking even or losing
+func (f Freeroll) Winnings(stack game.Money) game.Money {
+	if stack <= f.Stack {
+		return 0
+	}
+	return (stack - f.Stack).Mul(f.ConversionRate)
+}
+
+// Freeroll returns the room's freeroll settings, which stay as the room
+// opened with
+func (r *GameRoom) Freeroll() Freeroll {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.config.Freeroll
+}
+
+// seatBalance returns the balance a player with bankroll is seated with.
+// A freeroll seats a player with its stack on their first visit and with
+// what they kept of it after that, so leaving after a loss does not buy a
+// fresh stack.
+func (r *GameRoom) seatBalance(playerID string, bankroll game.Money) game.Money {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.config.Freeroll.Enabled() {
+		return bankroll
+	}
+	if stack, kept := r.freerollStacks[playerID]; kept {
+		return stack
+	}
+	return r.config.Freeroll.Stack
+}
+
+// keepStack records what a player leaving the freeroll with stack keeps for
+// their next visit. Winnings above the starting stack are paid into their
+// bankroll as they leave, so they keep at most the starting stack.
+func (r *GameRoom) keepStack(playerID string, stack game.Money) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.freerollStacks[playerID] = min(stack, r.config.Freeroll.Stack)
+}
+
+// bankrollRoom returns room when a seat in it holds the player's bankroll.
+// A freeroll's seats hold its own stack, so it returns nil and purchases
+// and credits go to the bankroll on record instead.
+func bankrollRoom(room *GameRoom) *GameRoom {
+	if room != nil && room.Freeroll().Enabled() {
+		return nil
+	}
+	return room
+}
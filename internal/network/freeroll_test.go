@@ -0,0 +1,163 @@
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+// newFreerollRoom opens a freeroll room seating players with $100 and
+// paying their winnings at half their value
+func newFreerollRoom(t *testing.T, server *Server) *GameRoom {
+	t.Helper()
+	config := DefaultRoomConfig()
+	config.Freeroll = Freeroll{Stack: 100 * game.Dollar, ConversionRate: 0.5}
+	room, err := server.CreateRoom("freeroll", "Freeroll", config)
+	require.NoError(t, err)
+	return room
+}
+
+func TestFreeroll_Winnings(t *testing.T) {
+	freeroll := Freeroll{Stack: 100 * game.Dollar, ConversionRate: 0.25}
+
+	assert.Equal(t, 10*game.Dollar, freeroll.Winnings(140*game.Dollar))
+	assert.Equal(t, game.Money(0), freeroll.Winnings(100*game.Dollar), "breaking even pays nothing")
+	assert.Equal(t, game.Money(0), freeroll.Winnings(20*game.Dollar), "losses cost nothing")
+}
+
+func TestFreeroll_Validate(t *testing.T) {
+	assert.NoError(t, Freeroll{}.Validate())
+	assert.NoError(t, Freeroll{Stack: 500 * game.Dollar, MaxStack: 500 * game.Dollar, ConversionRate: 1}.Validate())
+	assert.Error(t, Freeroll{Stack: -game.Dollar}.Validate())
+	assert.Error(t, Freeroll{Stack: 600 * game.Dollar, MaxStack: 500 * game.Dollar}.Validate())
+	assert.Error(t, Freeroll{Stack: 100 * game.Dollar, ConversionRate: 1.5}.Validate())
+}
+
+func TestRoomConfig_StartingStackSetting(t *testing.T) {
+	base := DefaultRoomConfig()
+	base.Freeroll = Freeroll{MaxStack: 500 * game.Dollar, ConversionRate: 0.1}
+
+	config, err := base.WithSettings(&RoomSettings{StartingStack: 200 * game.Dollar})
+	require.NoError(t, err)
+	assert.Equal(t, Freeroll{Stack: 200 * game.Dollar, MaxStack: 500 * game.Dollar, ConversionRate: 0.1}, config.Freeroll)
+	assert.Equal(t, 200*game.Dollar, config.Settings().StartingStack)
+	assert.Equal(t, 0.1, config.Settings().FreerollRate)
+
+	// The operator's rate is not the settings' to choose
+	config, err = base.WithSettings(&RoomSettings{StartingStack: 200 * game.Dollar, FreerollRate: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, config.Freeroll.ConversionRate)
+
+	_, err = base.WithSettings(&RoomSettings{StartingStack: 600 * game.Dollar})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+}
+
+func TestServer_FreerollKeepsBankrollApart(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+	room := newFreerollRoom(t, server)
+
+	for _, playerID := range []string{"winner", "loser"} {
+		require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: playerID, Balance: 40 * game.Dollar}))
+		balance, err := server.JoinBalance(ctx, playerID, room)
+		require.NoError(t, err)
+		assert.Equal(t, 100*game.Dollar, balance, "players are seated with the room's stack")
+		require.NoError(t, room.AddPlayer(playerID, playerID, balance))
+	}
+	_, err := room.Credit("winner", 60*game.Dollar, "test")
+	require.NoError(t, err)
+	_, err = room.Charge("loser", 90*game.Dollar, "test")
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	require.NoError(t, server.unseat(room, "winner"))
+	require.NoError(t, server.unseat(room, "loser"))
+	server.mu.Unlock()
+
+	// The $60 won on the stack is worth $30 in the bankroll, and the $90
+	// lost costs nothing
+	winner, err := server.Results().GetPlayer(ctx, "winner")
+	require.NoError(t, err)
+	assert.Equal(t, 70*game.Dollar, winner.Balance)
+	loser, err := server.Results().GetPlayer(ctx, "loser")
+	require.NoError(t, err)
+	assert.Equal(t, 40*game.Dollar, loser.Balance)
+}
+
+func TestServer_FreerollStackKeptAcrossRejoins(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+	room := newFreerollRoom(t, server)
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "p1", Balance: 40 * game.Dollar}))
+	rejoin := func() game.Money {
+		t.Helper()
+		server.mu.Lock()
+		require.NoError(t, server.unseat(room, "p1"))
+		server.mu.Unlock()
+		balance, err := server.JoinBalance(ctx, "p1", room)
+		require.NoError(t, err)
+		require.NoError(t, room.AddPlayer("p1", "Player 1", balance))
+		return balance
+	}
+
+	balance, err := server.JoinBalance(ctx, "p1", room)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", balance))
+
+	// Leaving after a loss does not buy a fresh stack
+	_, err = room.Charge("p1", 90*game.Dollar, "test")
+	require.NoError(t, err)
+	assert.Equal(t, 10*game.Dollar, rejoin())
+
+	// Nor does losing the lot
+	_, err = room.Charge("p1", 10*game.Dollar, "test")
+	require.NoError(t, err)
+	assert.Equal(t, game.Money(0), rejoin())
+
+	// Winnings are paid out on leaving, so a winner returns to the
+	// starting stack and is not paid for the same win twice
+	_, err = room.Credit("p1", 160*game.Dollar, "test")
+	require.NoError(t, err)
+	assert.Equal(t, 100*game.Dollar, rejoin())
+	assert.Equal(t, 100*game.Dollar, rejoin())
+	player, err := server.Results().GetPlayer(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, 70*game.Dollar, player.Balance)
+}
+
+func TestServer_FreerollPurchasesUseBankroll(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+	room := newFreerollRoom(t, server)
+
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "p1", Balance: 40 * game.Dollar}))
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+
+	// The stack would cover the skin, but it is not the player's to spend
+	_, err := server.BuySkin(ctx, "p1", room, "pixel")
+	assert.ErrorIs(t, err, game.ErrInsufficientBalance)
+
+	inventory, err := server.Inventory(ctx, "p1", room)
+	require.NoError(t, err)
+	assert.Equal(t, 40*game.Dollar, inventory.Balance)
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+}
+
+func TestGameRoom_StartingStackFixed(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	room := newFreerollRoom(t, server)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+
+	err := room.ProposeConfig("p1", &RoomSettings{StartingStack: 1000 * game.Dollar})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+	assert.NoError(t, room.ProposeConfig("p1", &RoomSettings{MaxBet: 50 * game.Dollar}))
+}
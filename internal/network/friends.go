@@ -0,0 +1,165 @@
+package network
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+)
+
+// Friend errors
+var (
+	ErrFriendNotFound = errors.New("no registered player has that name or friend code")
+	ErrFriendOffline  = errors.New("friend is not online")
+)
+
+// Friends returns a player's friend code and their friends, with who is
+// online and where they are playing
+func (s *Server) Friends(ctx context.Context, playerID string) (*FriendsData, error) {
+	if playerID == "" {
+		return nil, ErrPlayerNotFound
+	}
+	return s.friendsReply(s.playerRecord(ctx, playerID)), nil
+}
+
+// AddFriend adds a registered player, named by account or friend code, to
+// a player's friends
+func (s *Server) AddFriend(ctx context.Context, playerID, friend string) (*FriendsData, error) {
+	if playerID == "" {
+		return nil, ErrPlayerNotFound
+	}
+	friendID, err := s.findFriend(ctx, friend)
+	if err != nil {
+		return nil, err
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	player := s.playerRecord(ctx, playerID)
+	if err := player.AddFriend(friendID); err != nil {
+		return nil, err
+	}
+	if err := s.results.SavePlayer(ctx, player); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Friend added",
+		zap.String("player_id", playerID),
+		zap.String("friend_id", friendID),
+	)
+	return s.friendsReply(player), nil
+}
+
+// RemoveFriend takes a friend, named by account or friend code, off a
+// player's friends
+func (s *Server) RemoveFriend(ctx context.Context, playerID, friend string) (*FriendsData, error) {
+	if playerID == "" {
+		return nil, ErrPlayerNotFound
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	player := s.playerRecord(ctx, playerID)
+	friendID := friend
+	if code, ok := game.ParseFriendCode(friend); ok && !player.IsFriend(friend) {
+		for _, id := range player.Friends {
+			if game.FriendCode(id) == code {
+				friendID = id
+			}
+		}
+	}
+	if err := player.RemoveFriend(friendID); err != nil {
+		return nil, err
+	}
+	if err := s.results.SavePlayer(ctx, player); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Friend removed",
+		zap.String("player_id", playerID),
+		zap.String("friend_id", friendID),
+	)
+	return s.friendsReply(player), nil
+}
+
+// Invite sends a player's friend an invitation to the room the player is
+// in. The friend must be online to receive it.
+func (s *Server) Invite(ctx context.Context, playerID string, room *GameRoom, friendID string) (*InviteData, error) {
+	if playerID == "" {
+		return nil, ErrPlayerNotFound
+	}
+	if !s.playerRecord(ctx, playerID).IsFriend(friendID) {
+		return nil, game.ErrNotFriend
+	}
+	invite := &InviteData{
+		To:       friendID,
+		From:     playerID,
+		FromName: playerID,
+		RoomID:   room.ID(),
+		RoomName: room.Name(),
+	}
+	if session, ok := s.sessions.Get(playerID); ok && session.Name != "" {
+		invite.FromName = session.Name
+	}
+	if !s.sendToPlayer(friendID, NewMessage(MsgRoomInvite, invite.RoomID, playerID, invite)) {
+		return nil, ErrFriendOffline
+	}
+
+	s.logger.Info("Room invite sent",
+		zap.String("player_id", playerID),
+		zap.String("friend_id", friendID),
+		zap.String("room_id", invite.RoomID),
+	)
+	return invite, nil
+}
+
+// findFriend resolves an account name or friend code to the ID of a
+// registered player. Text that reads as a friend code but matches no one
+// is tried as an account name.
+func (s *Server) findFriend(ctx context.Context, friend string) (string, error) {
+	if code, ok := game.ParseFriendCode(friend); ok {
+		if player, err := s.results.FindByFriendCode(ctx, code); err == nil {
+			return player.ID, nil
+		}
+	}
+	if game.IsGuest(friend) {
+		return "", game.ErrFriendGuest
+	}
+	if game.ValidateAccount(friend) != nil {
+		return "", ErrFriendNotFound
+	}
+	if _, err := s.results.GetPlayer(ctx, friend); err != nil {
+		return "", ErrFriendNotFound
+	}
+	return friend, nil
+}
+
+// friendsReply reports a player's friend code and their friends' status
+func (s *Server) friendsReply(player *game.Player) *FriendsData {
+	reply := &FriendsData{
+		Code:    game.FriendCode(player.ID),
+		Friends: make([]FriendStatus, 0, len(player.Friends)),
+	}
+	for _, friendID := range player.Friends {
+		status := FriendStatus{
+			PlayerID: friendID,
+			Name:     friendID,
+			Code:     game.FriendCode(friendID),
+		}
+		if session, ok := s.sessions.Get(friendID); ok {
+			if session.Name != "" {
+				status.Name = session.Name
+			}
+			status.Online = session.Online
+			if session.Online && len(session.Rooms) > 0 {
+				status.RoomID = session.Rooms[0]
+			}
+		}
+		reply.Friends = append(reply.Friends, status)
+	}
+	return reply
+}
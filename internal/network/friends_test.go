@@ -0,0 +1,103 @@
+package network
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestServer_AddAndRemoveFriends(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+	require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: "alice"}))
+	require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: "bob"}))
+	require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: "carol"}))
+
+	_, err := server.AddFriend(ctx, "alice", "dave")
+	assert.ErrorIs(t, err, ErrFriendNotFound)
+	_, err = server.AddFriend(ctx, "alice", "guest_1234")
+	assert.ErrorIs(t, err, game.ErrFriendGuest)
+
+	reply, err := server.AddFriend(ctx, "alice", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, game.FriendCode("alice"), reply.Code)
+	require.Len(t, reply.Friends, 1)
+	assert.Equal(t, "bob", reply.Friends[0].PlayerID)
+	assert.False(t, reply.Friends[0].Online)
+
+	// Friend codes work however they are typed
+	reply, err = server.AddFriend(ctx, "alice", strings.ToLower(game.FriendCode("carol")))
+	require.NoError(t, err)
+	require.Len(t, reply.Friends, 2)
+	assert.Equal(t, "carol", reply.Friends[1].PlayerID)
+
+	_, err = server.AddFriend(ctx, "alice", "bob")
+	assert.ErrorIs(t, err, game.ErrFriendExists)
+
+	reply, err = server.RemoveFriend(ctx, "alice", game.FriendCode("bob"))
+	require.NoError(t, err)
+	require.Len(t, reply.Friends, 1)
+	assert.Equal(t, "carol", reply.Friends[0].PlayerID)
+
+	// The friend list is kept with the account
+	reply, err = server.Friends(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, reply.Friends, 1)
+
+	// A connection only changes its own player's friends
+	stranger := newCleanupClient(t, server, "")
+	stranger.handleFriends(NewMessage(MsgRemoveFriend, "", "alice", FriendsData{Friend: "carol"}))
+	assert.Equal(t, []ErrorData{{Code: "friends_failed", Message: ErrNotIdentified.Error()}}, sentErrors(t, stranger))
+	reply, err = server.Friends(ctx, "alice")
+	require.NoError(t, err)
+	assert.Len(t, reply.Friends, 1)
+}
+
+func TestServer_Invite(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+	require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: "alice"}))
+	require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: "bob"}))
+
+	room, err := server.CreateRoom("r1", "Room 1", DefaultRoomConfig())
+	require.NoError(t, err)
+
+	_, err = server.Invite(ctx, "alice", room, "bob")
+	assert.ErrorIs(t, err, game.ErrNotFriend)
+
+	_, err = server.AddFriend(ctx, "alice", "bob")
+	require.NoError(t, err)
+	_, err = server.Invite(ctx, "alice", room, "bob")
+	assert.ErrorIs(t, err, ErrFriendOffline)
+
+	bob := newCleanupClient(t, server, "bob")
+	server.clients.add(bob)
+	server.sessions.Attach("bob", "Bob", bob)
+	server.sessions.JoinRoom("bob", "lobby", 100*game.Dollar)
+	server.sessions.Attach("alice", "Alice", &Client{})
+
+	reply, err := server.Friends(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, reply.Friends, 1)
+	assert.Equal(t, FriendStatus{PlayerID: "bob", Name: "Bob", Code: game.FriendCode("bob"), Online: true, RoomID: "lobby"}, reply.Friends[0])
+
+	invite, err := server.Invite(ctx, "alice", room, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", invite.FromName)
+
+	require.Len(t, bob.send, 1)
+	msg, err := DecodeMessage(<-bob.send, EncodingJSON)
+	require.NoError(t, err)
+	assert.Equal(t, MsgRoomInvite, msg.Type)
+	var received InviteData
+	require.NoError(t, msg.GetData(&received))
+	assert.Equal(t, InviteData{To: "bob", From: "alice", FromName: "Alice", RoomID: "r1", RoomName: "Room 1"}, received)
+}
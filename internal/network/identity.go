@@ -0,0 +1,43 @@
+package network
+
+import (
+	"errors"
+	"net/url"
+)
+
+// PlayerParam is the query parameter on the WebSocket URL naming the
+// player a client connects as. The connection acts for that player alone:
+// joining or watching as anyone else is refused, and requests made outside
+// a room, such as wallet syncs, apply to them.
+const PlayerParam = "player"
+
+var (
+	// ErrNotIdentified is returned for requests from a connection that has
+	// not said which player it acts for
+	ErrNotIdentified = errors.New("connect or join a room as a player first")
+	// ErrWrongPlayer is returned when a connection tries to act for a
+	// player other than its own
+	ErrWrongPlayer = errors.New("this connection acts for another player")
+)
+
+// withPlayer adds the player a client connects as to a WebSocket URL
+func withPlayer(u *url.URL, playerID string) *url.URL {
+	if playerID == "" {
+		return u
+	}
+	identified := *u
+	query := identified.Query()
+	query.Set(PlayerParam, playerID)
+	identified.RawQuery = query.Encode()
+	return &identified
+}
+
+// identified returns the player the client acts for. A client acting for
+// no one yet is sent an error with code, and false is returned.
+func (c *Client) identified(code string) (string, bool) {
+	if c.playerID == "" {
+		c.sendError(code, ErrNotIdentified.Error())
+		return "", false
+	}
+	return c.playerID, true
+}
@@ -0,0 +1,77 @@
+package network
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+// sentErrors returns the errors queued for a client
+func sentErrors(t *testing.T, client *Client) []ErrorData {
+	t.Helper()
+
+	var errs []ErrorData
+	for {
+		select {
+		case data := <-client.send:
+			msg, err := DecodeMessage(data, EncodingJSON)
+			require.NoError(t, err)
+			if msg.Type != MsgError {
+				continue
+			}
+			var sent ErrorData
+			require.NoError(t, msg.GetData(&sent))
+			errs = append(errs, sent)
+		default:
+			return errs
+		}
+	}
+}
+
+func TestClient_ActsForItsOwnPlayer(t *testing.T) {
+	server, _ := newCleanupServer(t)
+	ctx := context.Background()
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "alice", Balance: 100 * game.Dollar}))
+
+	// A connection that named no player cannot sync anyone's wallet by
+	// naming them on the message
+	stranger := newCleanupClient(t, server, "")
+	stranger.handleWalletSync(NewMessage(MsgWalletSync, "", "alice", WalletSyncData{Net: 500 * game.Dollar}))
+	assert.Equal(t, []ErrorData{{Code: "sync_failed", Message: ErrNotIdentified.Error()}}, sentErrors(t, stranger))
+	assert.Equal(t, 100*game.Dollar, server.playerRecord(ctx, "alice").Balance)
+
+	// Nor can one acting for another player join as them
+	bob := newCleanupClient(t, server, "bob")
+	bob.handleJoinRoom(NewMessage(MsgJoinRoom, "r1", "alice", RoomJoinData{PlayerName: "Alice", Balance: 100 * game.Dollar}))
+	bob.handleJoinRoom(NewMessage(MsgJoinRoom, "r1", "alice", RoomJoinData{PlayerName: "Alice", Watch: true}))
+	assert.Equal(t, []ErrorData{
+		{Code: "join_failed", Message: ErrWrongPlayer.Error()},
+		{Code: "join_failed", Message: ErrWrongPlayer.Error()},
+	}, sentErrors(t, bob))
+	_, exists := server.GetRoom("r1")
+	assert.False(t, exists)
+}
+
+func TestServer_RefusesInvalidPlayerParam(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+
+	response, err := http.Get("http://" + listener.Addr().String() + "/ws?player=two%20words")
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
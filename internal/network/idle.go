@@ -0,0 +1,114 @@
+package network
+
+import (
+	"go.uber.org/zap"
+)
+
+// Steps taken with a player who stops betting, announced in IdleData
+const (
+	// IdleStepIdle marks the player idle: they no longer count towards
+	// the room's minimum players, and early close stops waiting for them
+	IdleStepIdle = "idle"
+	// IdleStepRelease gives the player's seat up to the queue; they stay
+	// in the room as a spectator
+	IdleStepRelease = "release"
+)
+
+// activePlayers counts the seated players who are not idle, those a round
+// needs to start. Callers must hold r.mu.
+func (r *GameRoom) activePlayers() int {
+	active := 0
+	for _, player := range r.players {
+		if !player.Idle {
+			active++
+		}
+	}
+	return active
+}
+
+// trackIdlePlayers counts another round without a bet for every seated
+// player who placed none in the settled round, warning them one round
+// before each step the room's config takes. Callers must hold r.mu.
+func (r *GameRoom) trackIdlePlayers() {
+	if r.config.IdleRounds <= 0 {
+		return
+	}
+
+	for id, player := range r.players {
+		if len(r.currentRound.Bets[id]) > 0 {
+			player.MissedRounds = 0
+			continue
+		}
+		player.MissedRounds++
+
+		r.idleStep(player, IdleStepIdle, r.config.IdleRounds)
+		if r.config.IdleReleaseRounds > 0 {
+			r.idleStep(player, IdleStepRelease, r.config.IdleReleaseRounds)
+		}
+	}
+}
+
+// idleStep warns the player the round before they have missed after
+// rounds, and takes the step once they have. Callers must hold r.mu.
+func (r *GameRoom) idleStep(player *RoomPlayer, step string, after int) {
+	switch player.MissedRounds {
+	case after - 1:
+		r.broadcastMessage(NewMessage(MsgIdle, r.id, player.ID, &IdleData{Step: step, RoundsLeft: 1}))
+	case after:
+		if step == IdleStepIdle {
+			player.Idle = true
+		}
+		r.logger.Info("Player idle",
+			zap.String("room_id", r.id),
+			zap.String("player_id", player.ID),
+			zap.String("step", step),
+			zap.Int("missed_rounds", player.MissedRounds),
+		)
+		r.broadcastMessage(NewMessage(MsgIdle, r.id, player.ID, &IdleData{Step: step}))
+		r.broadcastRoomUpdate()
+	}
+}
+
+// markActive brings a player back from idle once they bet again, starting
+// a round if the room was waiting on them. Callers must hold r.mu.
+func (r *GameRoom) markActive(player *RoomPlayer) {
+	player.MissedRounds = 0
+	if !player.Idle {
+		return
+	}
+	player.Idle = false
+	r.broadcastRoomUpdate()
+	r.checkAndStartGame()
+}
+
+// releasable reports whether the player is still seated, without a bet in
+// the open round, and has missed enough rounds to lose their seat
+func (r *GameRoom) releasable(playerID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	player, ok := r.players[playerID]
+	return ok && r.config.IdleReleaseRounds > 0 &&
+		player.MissedRounds >= r.config.IdleReleaseRounds && len(player.CurrentBets) == 0
+}
+
+// releaseIdleSeat gives the seat of a player the room released for
+// idling to the next spectator in line. The player's client stays in the
+// room, watching it.
+func (s *Server) releaseIdleSeat(room *GameRoom, playerID string) {
+	// The player may have bet again, or left, since
+	if !room.releasable(playerID) {
+		return
+	}
+	s.mu.Lock()
+	err := s.unseat(room, playerID)
+	s.mu.Unlock()
+	if err != nil {
+		return
+	}
+
+	s.logger.Info("Released idle player's seat",
+		zap.String("room_id", room.ID()),
+		zap.String("player_id", playerID),
+	)
+}
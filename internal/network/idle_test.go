@@ -0,0 +1,103 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+// idleSteps returns the idle notices the room sent the player
+func idleSteps(room *GameRoom, playerID string) []IdleData {
+	var steps []IdleData
+	for _, msg := range drainEvents(room) {
+		if msg.Type == MsgIdle && msg.PlayerID == playerID {
+			steps = append(steps, *msg.Data.(*IdleData))
+		}
+	}
+	return steps
+}
+
+func TestGameRoom_IdlePlayers(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.config.IdleRounds = 2
+	room.config.IdleReleaseRounds = 3
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	drainEvents(room)
+
+	// The round before the step comes with a warning
+	require.NoError(t, room.PlaceBet("p2", 10*game.Dollar, game.Heads))
+	nextRound(t, room, scheduler, fake)
+	assert.Equal(t, []IdleData{{Step: IdleStepIdle, RoundsLeft: 1}}, idleSteps(room, "p1"))
+	assert.Empty(t, idleSteps(room, "p2"))
+
+	require.NoError(t, room.PlaceBet("p2", 10*game.Dollar, game.Heads))
+	nextRound(t, room, scheduler, fake)
+	assert.Equal(t, []IdleData{{Step: IdleStepIdle}, {Step: IdleStepRelease, RoundsLeft: 1}}, idleSteps(room, "p1"))
+
+	players := room.GetPlayers()
+	assert.True(t, players["p1"].Idle)
+	assert.False(t, players["p2"].Idle)
+	room.mu.RLock()
+	assert.Equal(t, 1, room.activePlayers())
+	room.mu.RUnlock()
+	assert.False(t, room.releasable("p1"), "the seat is released a round later")
+
+	// Betting again brings the player back
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Tails))
+	players = room.GetPlayers()
+	assert.False(t, players["p1"].Idle)
+	assert.Zero(t, players["p1"].MissedRounds)
+}
+
+func TestGameRoom_IdleDisabled(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, room.PlaceBet("p2", 10*game.Dollar, game.Heads))
+		nextRound(t, room, scheduler, fake)
+	}
+	assert.Empty(t, idleSteps(room, "p1"))
+	assert.False(t, room.GetPlayers()["p1"].Idle)
+}
+
+func TestRoomConfig_IdleValidation(t *testing.T) {
+	_, err := DefaultRoomConfig().WithSettings(&RoomSettings{IdleRounds: 1})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig, "a player must be warned before going idle")
+	_, err = DefaultRoomConfig().WithSettings(&RoomSettings{IdleRounds: 3, IdleReleaseRounds: 3})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+
+	config, err := DefaultRoomConfig().WithSettings(&RoomSettings{IdleRounds: 3, IdleReleaseRounds: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 3, config.Settings().IdleRounds)
+	assert.Equal(t, 5, config.Settings().IdleReleaseRounds)
+}
+
+func TestServer_ReleaseIdleSeat(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+
+	config := DefaultRoomConfig()
+	config.IdleRounds = 2
+	config.IdleReleaseRounds = 3
+	room, err := server.CreateRoom("room", "Room", config)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+
+	// Not idle long enough yet
+	room.mu.Lock()
+	room.players["p1"].MissedRounds = 2
+	room.mu.Unlock()
+	server.releaseIdleSeat(room, "p1")
+	assert.Contains(t, room.GetPlayers(), "p1")
+
+	room.mu.Lock()
+	room.players["p1"].MissedRounds = 3
+	room.mu.Unlock()
+	server.releaseIdleSeat(room, "p1")
+	assert.NotContains(t, room.GetPlayers(), "p1")
+}
@@ -0,0 +1,147 @@
+package network
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyInterval is how often each side of a connection pings the
+// other to measure latency
+const DefaultLatencyInterval = 5 * time.Second
+
+// DefaultMaxLatencyGrace caps how long a room holds betting open past its
+// deadline for players with slow connections
+const DefaultMaxLatencyGrace = 500 * time.Millisecond
+
+// pingPayload returns the application data of a latency ping: the time it
+// was sent, which the peer echoes back in its pong
+func pingPayload(now time.Time) []byte {
+	return []byte(strconv.FormatInt(now.UnixNano(), 10))
+}
+
+// roundTrip returns how long ago the ping a pong answers was sent, or false
+// for pongs that do not echo a latency ping
+func roundTrip(appData string, now time.Time) (time.Duration, bool) {
+	sent, err := strconv.ParseInt(appData, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	rtt := now.Sub(time.Unix(0, sent))
+	if rtt < 0 {
+		return 0, false
+	}
+	return rtt, true
+}
+
+// latencyTracker smooths round trip samples so a single slow pong does not
+// swing the reported latency
+type latencyTracker struct {
+	mu  sync.Mutex
+	rtt time.Duration
+}
+
+// observe records a round trip sample and returns the smoothed latency
+func (t *latencyTracker) observe(sample time.Duration) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.rtt == 0 {
+		t.rtt = sample
+	} else {
+		t.rtt += (sample - t.rtt) / 4
+	}
+	return t.rtt
+}
+
+// latency returns the smoothed round trip time, zero before the first pong
+func (t *latencyTracker) latency() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rtt
+}
+
+// reset forgets the samples of a closed connection
+func (t *latencyTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rtt = 0
+}
+
+// clockSkew estimates how far a peer's clock runs behind ours from the
+// timestamps on its messages, so the times it reports can be moved onto our
+// clock
+type clockSkew struct {
+	mu      sync.Mutex
+	offset  time.Duration
+	sampled bool
+}
+
+// observe records a message the peer sent at sent, by its clock, that
+// arrived at received, by ours, over a link with the given round trip time.
+// Half the round trip is taken as the message's transit.
+func (s *clockSkew) observe(sent, received time.Time, rtt time.Duration) {
+	if sent.IsZero() {
+		return
+	}
+	sample := received.Sub(sent) - rtt/2
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.sampled {
+		s.offset, s.sampled = sample, true
+	} else {
+		s.offset += (sample - s.offset) / 4
+	}
+}
+
+// correct moves a time on the peer's clock onto ours, reporting false
+// before the first message was observed
+func (s *clockSkew) correct(t time.Time) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.sampled {
+		return time.Time{}, false
+	}
+	return t.Add(s.offset), true
+}
+
+// SetPlayerLatency records the round trip time measured to a player's
+// client. It is shown to the room with the next room update.
+func (r *GameRoom) SetPlayerLatency(playerID string, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if player, exists := r.players[playerID]; exists {
+		player.Latency = latency
+	}
+}
+
+// latencyGrace returns how long to keep betting open past its deadline:
+// the one-way delay of the slowest online player, capped by the room
+// config. Callers must hold r.mu.
+func (r *GameRoom) latencyGrace() time.Duration {
+	var grace time.Duration
+	for _, player := range r.players {
+		if player.IsOnline {
+			grace = max(grace, player.Latency/2)
+		}
+	}
+	return min(grace, max(r.config.MaxLatencyGrace, 0))
+}
+
+// checkBetDeadline refuses a bet arriving after the advertised betting
+// deadline, unless it was sent before the deadline and is late by no more
+// than the player's one-way delay. Callers must hold r.mu.
+func (r *GameRoom) checkBetDeadline(player *RoomPlayer, sentAt time.Time) error {
+	now := r.clock.Now()
+	if !now.After(r.timerEnd) {
+		return nil
+	}
+	if sentAt.After(r.timerEnd) || now.Sub(r.timerEnd) > min(player.Latency/2, r.bettingGrace) {
+		return ErrBettingClosed
+	}
+	return nil
+}
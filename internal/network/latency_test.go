@@ -0,0 +1,200 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestRoundTrip(t *testing.T) {
+	sent := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	rtt, ok := roundTrip(string(pingPayload(sent)), sent.Add(80*time.Millisecond))
+	require.True(t, ok)
+	assert.Equal(t, 80*time.Millisecond, rtt)
+
+	_, ok = roundTrip("", sent)
+	assert.False(t, ok, "keepalive pongs carry no timestamp")
+	_, ok = roundTrip(string(pingPayload(sent)), sent.Add(-time.Second))
+	assert.False(t, ok)
+}
+
+func TestLatencyTracker_Smooths(t *testing.T) {
+	var tracker latencyTracker
+
+	assert.Equal(t, 100*time.Millisecond, tracker.observe(100*time.Millisecond))
+	assert.Equal(t, 125*time.Millisecond, tracker.observe(200*time.Millisecond))
+
+	tracker.reset()
+	assert.Zero(t, tracker.latency())
+}
+
+func TestGameRoom_LatencyGrace(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.SetPlayerLatency("p1", 400*time.Millisecond)
+	room.SetPlayerLatency("unknown", time.Second)
+
+	// Finish the open round so the grace applies from the next one
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+	fake.Advance(room.config.ResultDuration)
+	scheduler.advance(fake.Now())
+	fake.Advance(RoundBreakDuration)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+
+	// Betting stays open for the player's one-way delay past the deadline,
+	// for bets sent before it
+	fake.Advance(10*time.Second + 100*time.Millisecond)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+	assert.ErrorIs(t, room.PlaceBet("p1", 10*game.Dollar, game.Tails), ErrBettingClosed, "sent after the deadline")
+	late := BetData{Amount: 10 * game.Dollar, Choice: game.Tails}
+	require.NoError(t, room.PlaceTimedBet("p1", late, fake.Now().Add(-150*time.Millisecond)))
+
+	fake.Advance(100 * time.Millisecond)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateResult, room.GetGameState())
+
+	var update *RoomUpdateData
+	for _, message := range drainEvents(room) {
+		if data, ok := message.Data.(*RoomUpdateData); ok {
+			update = data
+		}
+	}
+	require.NotNil(t, update)
+	require.Len(t, update.Players, 1)
+	assert.Equal(t, int64(400), update.Players[0].LatencyMs)
+}
+
+func TestGameRoom_LateBetWithinOneWayDelay(t *testing.T) {
+	room, _, fake := newTestRoom(t)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	room.SetPlayerLatency("p1", 100*time.Millisecond)
+	room.SetPlayerLatency("p2", 400*time.Millisecond)
+
+	room.mu.Lock()
+	deadline := fake.Now()
+	room.timerEnd = deadline
+	room.bettingGrace = room.latencyGrace()
+	room.mu.Unlock()
+
+	// 80ms late is beyond p1's 50ms one-way delay but within p2's 200ms
+	fake.Advance(80 * time.Millisecond)
+	bet := BetData{Amount: 10 * game.Dollar, Choice: game.Heads}
+	sent := deadline.Add(-10 * time.Millisecond)
+	assert.ErrorIs(t, room.PlaceTimedBet("p1", bet, sent), ErrBettingClosed)
+	assert.ErrorIs(t, room.PlaceTimedBet("p2", bet, deadline.Add(time.Millisecond)), ErrBettingClosed)
+	require.NoError(t, room.PlaceTimedBet("p2", bet, sent))
+
+	bet.Insured, bet.Practice = true, true
+	assert.ErrorIs(t, room.PlaceTimedBet("p2", bet, sent), game.ErrPracticeInsured)
+}
+
+func TestGameRoom_LateBetChangesWithinOneWayDelay(t *testing.T) {
+	room, _, fake := newTestRoom(t)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	room.SetPlayerLatency("p1", 100*time.Millisecond)
+	room.SetPlayerLatency("p2", 400*time.Millisecond)
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p2", 10*game.Dollar, game.Heads))
+
+	room.mu.Lock()
+	deadline := fake.Now()
+	room.timerEnd = deadline
+	room.bettingGrace = room.latencyGrace()
+	room.mu.Unlock()
+
+	// Past the deadline, changes follow the same rule as new bets: 80ms
+	// late is beyond p1's 50ms one-way delay but within p2's 200ms
+	fake.Advance(80 * time.Millisecond)
+	sent := deadline.Add(-10 * time.Millisecond)
+	assert.ErrorIs(t, room.UpdateBet("p1", 50*game.Dollar, game.Heads), ErrBettingClosed)
+	assert.ErrorIs(t, room.UpdateTimedBet("p1", 50*game.Dollar, game.Heads, sent), ErrBettingClosed)
+	assert.ErrorIs(t, room.CancelBet("p1"), ErrBettingClosed)
+	assert.ErrorIs(t, room.CancelTimedBet("p1", sent), ErrBettingClosed)
+	assert.Equal(t, 90*game.Dollar, room.GetPlayers()["p1"].Balance, "the bet stands as placed")
+
+	assert.ErrorIs(t, room.UpdateTimedBet("p2", 50*game.Dollar, game.Heads, deadline.Add(time.Millisecond)), ErrBettingClosed)
+	assert.ErrorIs(t, room.CancelTimedBet("p2", deadline.Add(time.Millisecond)), ErrBettingClosed)
+	require.NoError(t, room.UpdateTimedBet("p2", 50*game.Dollar, game.Heads, sent))
+	assert.Equal(t, 50*game.Dollar, room.GetPlayers()["p2"].Balance)
+	require.NoError(t, room.CancelTimedBet("p2", sent))
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p2"].Balance)
+}
+
+func TestClockSkew_CorrectsPeerTimes(t *testing.T) {
+	var skew clockSkew
+	peer := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	_, ok := skew.correct(peer)
+	assert.False(t, ok)
+
+	// The peer runs 2s behind; each message takes half the 100ms round trip
+	ours := peer.Add(2 * time.Second)
+	skew.observe(peer, ours.Add(50*time.Millisecond), 100*time.Millisecond)
+	corrected, ok := skew.correct(peer)
+	require.True(t, ok)
+	assert.Equal(t, ours, corrected)
+
+	// A message held up on the way only shifts the estimate by a quarter
+	skew.observe(peer, ours.Add(450*time.Millisecond), 100*time.Millisecond)
+	corrected, _ = skew.correct(peer)
+	assert.Equal(t, ours.Add(100*time.Millisecond), corrected)
+
+	skew.observe(time.Time{}, ours, 0)
+	corrected, _ = skew.correct(peer)
+	assert.Equal(t, ours.Add(100*time.Millisecond), corrected, "messages without a timestamp are ignored")
+}
+
+func TestGameRoom_LatencyGraceCapped(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	room.SetPlayerLatency("p1", 5*time.Second)
+
+	assert.Equal(t, DefaultMaxLatencyGrace, room.latencyGrace())
+
+	room.config.MaxLatencyGrace = 0
+	assert.Zero(t, room.latencyGrace())
+}
+
+func TestLatency_MeasuredBothWays(t *testing.T) {
+	serverConfig := DefaultServerConfig()
+	serverConfig.LatencyInterval = 20 * time.Millisecond
+	server := NewServer(serverConfig, zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+
+	config := DefaultClientConfig()
+	config.ServerURL = "ws://" + listener.Addr().String() + "/ws"
+	config.LatencyInterval = 20 * time.Millisecond
+
+	client := NewNetworkClient(config, "p1", "Player 1", zaptest.NewLogger(t))
+	defer client.Disconnect()
+
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.JoinRoom("r1", 100*game.Dollar))
+
+	waitFor(t, func() bool { return client.Latency() > 0 })
+	waitFor(t, func() bool {
+		room, ok := server.GetRoom("r1")
+		if !ok {
+			return false
+		}
+		player, ok := room.GetPlayers()["p1"]
+		return ok && player.Latency > 0
+	})
+}
@@ -0,0 +1,307 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"coinflip-game/internal/game"
+)
+
+// ErrBetLimit is returned for bets that would take a round past the
+// operator's betting limits
+var ErrBetLimit = errors.New("bet exceeds the betting limits")
+
+// BetLimits caps what the house has at stake in a room's rounds. A zero
+// limit is off.
+type BetLimits struct {
+	// MaxPot caps the real stakes in a round across every player
+	MaxPot game.Money
+	// MaxRoundPayout caps the most a round pays out however the coin
+	// lands, counting streak multipliers and insurance refunds
+	MaxRoundPayout game.Money
+	// ScaleBets lowers a bet that would break a limit to the largest
+	// amount that fits, rather than refusing it, as long as that is still
+	// at least the minimum bet
+	ScaleBets bool
+	// MaxBet and MaxPayoutRatio cap the maximum bet and payout ratio a
+	// room's settings may choose
+	MaxBet         game.Money
+	MaxPayoutRatio float64
+	// MaxStreakBonus and MaxStreakMultiplier cap the streak bonus and
+	// streak multiplier a room's settings may choose
+	MaxStreakBonus      float64
+	MaxStreakMultiplier float64
+	// MinInsuranceCost and MaxInsuranceCoverage bound the insurance a
+	// room's settings may offer
+	MinInsuranceCost     float64
+	MaxInsuranceCoverage float64
+}
+
+// Validate checks the limits are usable with the given minimum bet
+func (l BetLimits) Validate(minBet game.Money) error {
+	if l.MaxPot < 0 || l.MaxRoundPayout < 0 || l.MaxBet < 0 || l.MaxPayoutRatio < 0 ||
+		l.MaxStreakBonus < 0 || l.MaxStreakMultiplier < 0 || l.MinInsuranceCost < 0 || l.MaxInsuranceCoverage < 0 {
+		return errors.New("betting limits must not be negative")
+	}
+	if l.MaxPot > 0 && l.MaxPot < minBet {
+		return fmt.Errorf("pot limit %s is below the minimum bet %s", l.MaxPot.Format(), minBet.Format())
+	}
+	return nil
+}
+
+// clamp brings the house economics a room's settings chose within the
+// limits
+func (l BetLimits) clamp(config *RoomConfig) {
+	if l.MaxBet > 0 && config.MaxBet > l.MaxBet {
+		config.MaxBet = l.MaxBet
+	}
+	if l.MaxPayoutRatio > 0 && config.PayoutRatio > l.MaxPayoutRatio {
+		config.PayoutRatio = l.MaxPayoutRatio
+	}
+	if l.MaxStreakBonus > 0 && config.StreakBonus > l.MaxStreakBonus {
+		config.StreakBonus = l.MaxStreakBonus
+	}
+	// A streak multiplier without a cap of its own gets the operator's
+	if l.MaxStreakMultiplier > 0 && config.StreakBonus > 0 &&
+		(config.MaxStreakMultiplier == 0 || config.MaxStreakMultiplier > l.MaxStreakMultiplier) {
+		config.MaxStreakMultiplier = l.MaxStreakMultiplier
+	}
+	if config.Insurance.Enabled() {
+		if l.MaxInsuranceCoverage > 0 && config.Insurance.Coverage > l.MaxInsuranceCoverage {
+			config.Insurance.Coverage = l.MaxInsuranceCoverage
+		}
+		if config.Insurance.Cost < l.MinInsuranceCost {
+			config.Insurance.Cost = l.MinInsuranceCost
+		}
+	}
+}
+
+// LiabilityLedger tracks the most each room's open round could pay out,
+// keeping the total across rooms under a server-wide cap. Its methods are
+// safe to call on a nil ledger, which has no cap.
+type LiabilityLedger struct {
+	mu    sync.Mutex
+	max   game.Money
+	rooms map[string]game.Money
+}
+
+// NewLiabilityLedger creates a ledger capping the total at max; zero means
+// no cap, with liabilities still tracked
+func NewLiabilityLedger(max game.Money) *LiabilityLedger {
+	return &LiabilityLedger{
+		max:   max,
+		rooms: make(map[string]game.Money),
+	}
+}
+
+// Headroom returns the most roomID's round may pay out alongside every
+// other room's, and false when there is no cap
+func (l *LiabilityLedger) Headroom(roomID string) (game.Money, bool) {
+	if l == nil || l.max <= 0 {
+		return 0, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	headroom := l.max - l.total() + l.rooms[roomID]
+	if headroom < 0 {
+		headroom = 0
+	}
+	return headroom, true
+}
+
+// Reserve records roomID's liability, failing with ErrBetLimit if it
+// would take the total past the cap
+func (l *LiabilityLedger) Reserve(roomID string, liability game.Money) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.max > 0 && liability > l.rooms[roomID] {
+		if headroom := l.max - l.total() + l.rooms[roomID]; liability > headroom {
+			return fmt.Errorf("%w: the round could pay out %s, more than the %s the server can still cover",
+				ErrBetLimit, liability.Format(), headroom.Format())
+		}
+	}
+	l.set(roomID, liability)
+	return nil
+}
+
+// Set records roomID's liability without checking the cap, for changes
+// such as refunds and settled rounds
+func (l *LiabilityLedger) Set(roomID string, liability game.Money) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.set(roomID, liability)
+}
+
+// Total returns the liability across every room
+func (l *LiabilityLedger) Total() game.Money {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.total()
+}
+
+// set records a liability. Callers must hold l.mu.
+func (l *LiabilityLedger) set(roomID string, liability game.Money) {
+	if liability <= 0 {
+		delete(l.rooms, roomID)
+		return
+	}
+	l.rooms[roomID] = liability
+}
+
+// total sums every room's liability. Callers must hold l.mu.
+func (l *LiabilityLedger) total() game.Money {
+	var total game.Money
+	for _, liability := range l.rooms {
+		total += liability
+	}
+	return total
+}
+
+// SetLiabilityLedger counts this room's open round against a server-wide
+// liability cap
+func (r *GameRoom) SetLiabilityLedger(ledger *LiabilityLedger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.liability = ledger
+	r.trackLiability()
+}
+
+// fitBet checks a new or changed bet against the room's betting limits and
+// the server's liability cap. With ScaleBets a bet that does not fit is
+// lowered to the largest whole-cent stake that does, no lower than the
+// minimum bet, and the amount asked for is kept in Requested. Callers must
+// hold r.mu.
+func (r *GameRoom) fitBet(bet *BetData) error {
+	if bet.Practice {
+		return nil
+	}
+	err := r.checkLimits(bet)
+	if err == nil || !r.config.Limits.ScaleBets {
+		return err
+	}
+
+	// A round's exposure only grows with a stake, so the largest stake
+	// that fits can be found by bisection
+	requested := bet.Amount
+	fits := largestStake(r.config.MinBet, requested-game.Cent, func(stake game.Money) bool {
+		r.setStake(bet, stake)
+		return r.checkLimits(bet) == nil
+	})
+	if fits == 0 {
+		r.setStake(bet, requested)
+		return err
+	}
+
+	r.setStake(bet, fits)
+	bet.Requested = requested
+	return nil
+}
+
+// checkLimits returns why the round would break a limit with bet in it, or
+// nil. Callers must hold r.mu.
+func (r *GameRoom) checkLimits(bet *BetData) error {
+	pot, payout := r.roundExposure(bet)
+	limits := r.config.Limits
+	if limits.MaxPot > 0 && pot > limits.MaxPot {
+		return fmt.Errorf("%w: the pot would reach %s, over this room's %s limit",
+			ErrBetLimit, pot.Format(), limits.MaxPot.Format())
+	}
+	if limits.MaxRoundPayout > 0 && payout > limits.MaxRoundPayout {
+		return fmt.Errorf("%w: the round could pay out %s, over this room's %s limit",
+			ErrBetLimit, payout.Format(), limits.MaxRoundPayout.Format())
+	}
+	if headroom, capped := r.liability.Headroom(r.id); capped && payout > headroom {
+		return fmt.Errorf("%w: the round could pay out %s, more than the %s the server can still cover",
+			ErrBetLimit, payout.Format(), headroom.Format())
+	}
+	return nil
+}
+
+// reserveLiability claims the round's payout with bet in it from the
+// server's liability cap. Callers must hold r.mu.
+func (r *GameRoom) reserveLiability(bet *BetData) error {
+	if bet.Practice {
+		return nil
+	}
+	_, payout := r.roundExposure(bet)
+	return r.liability.Reserve(r.id, payout)
+}
+
+// trackLiability records what the open round could still pay out with the
+// server's ledger, after bets are withdrawn or the round is settled.
+// Callers must hold r.mu.
+func (r *GameRoom) trackLiability() {
+	var payout game.Money
+	if r.currentRound != nil && r.gameState != StateResult {
+		_, payout = r.roundExposure(nil)
+	}
+	r.liability.Set(r.id, payout)
+}
+
+// roundExposure returns the real stakes in the current round and the most
+// it pays out on either side of the coin, with bet replacing its player's
+// bet of the same ID or added alongside the others. Callers must hold r.mu.
+func (r *GameRoom) roundExposure(bet *BetData) (pot, payout game.Money) {
+	paid := make(map[game.Side]game.Money, 2)
+	add := func(b *BetData) {
+		if b.Practice {
+			return
+		}
+		var streak int
+		if player, exists := r.players[b.PlayerID]; exists {
+			streak = player.WinStreak
+		}
+		multiplier := r.payoutMultiplier(streak)
+
+		pot += b.Amount
+		for _, side := range []game.Side{game.Heads, game.Tails} {
+			paid[side] += r.betPayout(b, side, multiplier) + betInsurance(b, side)
+		}
+	}
+
+	replaced := false
+	for playerID, bets := range r.currentRound.Bets {
+		for _, existing := range bets {
+			if bet != nil && !replaced && playerID == bet.PlayerID && existing.BetID == bet.BetID {
+				add(bet)
+				replaced = true
+				continue
+			}
+			add(existing)
+		}
+	}
+	if bet != nil && !replaced {
+		add(bet)
+	}
+
+	for _, side := range paid {
+		if side > payout {
+			payout = side
+		}
+	}
+	// A pooled round pays out at most its pool
+	if r.config.Mode.Pooled() {
+		payout = pot
+	}
+	return pot, payout
+}
+
+// setStake changes a bet's stake, repricing its insurance
+func (r *GameRoom) setStake(bet *BetData, amount game.Money) {
+	bet.Amount = amount
+	if bet.Insured {
+		r.insure(bet)
+	}
+}
@@ -0,0 +1,242 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestGameRoom_PotLimit(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	room.config.Limits.MaxPot = 50 * game.Dollar
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	require.NoError(t, room.PlaceBet("p1", 30*game.Dollar, game.Heads))
+
+	err := room.PlaceBet("p2", 30*game.Dollar, game.Tails)
+	require.ErrorIs(t, err, ErrBetLimit)
+	assert.Contains(t, err.Error(), "the pot would reach $60.00, over this room's $50.00 limit")
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p2"].Balance)
+
+	require.NoError(t, room.PlaceBet("p2", 20*game.Dollar, game.Tails))
+
+	// Practice bets put nothing at stake
+	require.NoError(t, room.AddPlayer("p3", "Player 3", 100*game.Dollar))
+	require.NoError(t, room.PlacePracticeBet("p3", 50*game.Dollar, game.Heads))
+}
+
+func TestGameRoom_RoundPayoutLimit(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	room.config.Limits.MaxRoundPayout = 100 * game.Dollar
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	// Only one side of the coin pays, so bets on opposite sides do not add up
+	require.NoError(t, room.PlaceBet("p1", 40*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p2", 40*game.Dollar, game.Tails))
+
+	err := room.PlaceBet("p2", 20*game.Dollar, game.Heads)
+	require.ErrorIs(t, err, ErrBetLimit)
+	assert.Contains(t, err.Error(), "the round could pay out $120.00, over this room's $100.00 limit")
+
+	// A changed bet is checked in place of the old one
+	require.NoError(t, room.UpdateBet("p1", 50*game.Dollar, game.Heads))
+	assert.ErrorIs(t, room.UpdateBet("p1", 60*game.Dollar, game.Heads), ErrBetLimit)
+	assert.Equal(t, 50*game.Dollar, room.GetPlayers()["p1"].Balance)
+}
+
+func TestGameRoom_RoundExposureReplacesOwnBet(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	// Bets of different players under the same ID are both counted, and a
+	// changed bet replaces only its own player's
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	room.currentRound.Bets["p1"] = []*BetData{{PlayerID: "p1", BetID: "same", Amount: 40 * game.Dollar, Choice: game.Heads}}
+	room.currentRound.Bets["p2"] = []*BetData{{PlayerID: "p2", BetID: "same", Amount: 30 * game.Dollar, Choice: game.Heads}}
+
+	pot, _ := room.roundExposure(nil)
+	assert.Equal(t, 70*game.Dollar, pot)
+	pot, _ = room.roundExposure(&BetData{PlayerID: "p2", BetID: "same", Amount: 10 * game.Dollar, Choice: game.Heads})
+	assert.Equal(t, 50*game.Dollar, pot)
+	pot, _ = room.roundExposure(&BetData{PlayerID: "p1", BetID: "same", Amount: 10 * game.Dollar, Choice: game.Heads})
+	assert.Equal(t, 40*game.Dollar, pot)
+}
+
+func TestGameRoom_RoundPayoutLimitCountsStreaks(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	room.config.Limits.MaxRoundPayout = 100 * game.Dollar
+	room.config.StreakBonus = 0.5
+	room.players["p1"].WinStreak = 1
+
+	// A 1.5x streak multiplier turns a $40 stake into a $120 payout
+	assert.ErrorIs(t, room.PlaceBet("p1", 40*game.Dollar, game.Heads), ErrBetLimit)
+	require.NoError(t, room.PlaceBet("p1", 30*game.Dollar, game.Heads))
+}
+
+func TestGameRoom_ScaleBets(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.config.Limits = BetLimits{MaxRoundPayout: 100 * game.Dollar, ScaleBets: true}
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	drainEvents(room)
+
+	require.NoError(t, room.PlaceBet("p1", 80*game.Dollar, game.Heads))
+	player := room.GetPlayers()["p1"]
+	require.Len(t, player.CurrentBets, 1)
+	assert.Equal(t, 50*game.Dollar, player.CurrentBets[0].Amount)
+	assert.Equal(t, 80*game.Dollar, player.CurrentBets[0].Requested)
+	assert.Equal(t, 50*game.Dollar, player.Balance)
+
+	messages := drainEvents(room)
+	require.NotEmpty(t, messages)
+	var placed BetData
+	require.NoError(t, messages[0].GetData(&placed))
+	assert.Equal(t, 50*game.Dollar, placed.Amount)
+	assert.Equal(t, 80*game.Dollar, placed.Requested)
+
+	// Nothing at or above the minimum bet fits on a full side
+	assert.ErrorIs(t, room.PlaceBet("p2", 10*game.Dollar, game.Heads), ErrBetLimit)
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p2"].Balance)
+
+	// The stake settles at the scaled amount
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+	result := room.currentRound.Results["p1"]
+	assert.Equal(t, 50*game.Dollar, result.Wagered)
+}
+
+func TestLiabilityLedger_SharedAcrossRooms(t *testing.T) {
+	ledger := NewLiabilityLedger(150 * game.Dollar)
+	first, scheduler, fake := newTestRoom(t)
+	first.SetLiabilityLedger(ledger)
+
+	second := NewGameRoom("second", "Second", first.config, scheduler, zaptest.NewLogger(t))
+	t.Cleanup(second.Stop)
+	require.NoError(t, second.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	scheduler.advance(fake.Now())
+	second.SetLiabilityLedger(ledger)
+
+	require.NoError(t, first.PlaceBet("p1", 50*game.Dollar, game.Heads))
+	assert.Equal(t, 100*game.Dollar, ledger.Total())
+
+	err := second.PlaceBet("p1", 30*game.Dollar, game.Heads)
+	require.ErrorIs(t, err, ErrBetLimit)
+	assert.Contains(t, err.Error(), "more than the $50.00 the server can still cover")
+
+	require.NoError(t, second.PlaceBet("p1", 25*game.Dollar, game.Heads))
+	assert.Equal(t, 150*game.Dollar, ledger.Total())
+
+	// Withdrawn bets free their share of the cap
+	require.NoError(t, first.CancelBet("p1"))
+	assert.Equal(t, 50*game.Dollar, ledger.Total())
+
+	// Settled rounds owe nothing more
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, second.GetGameState())
+	assert.Equal(t, game.Money(0), ledger.Total())
+}
+
+func TestBetLimits_Validate(t *testing.T) {
+	config := DefaultRoomConfig()
+	config.Limits.MaxPot = 50 * game.Cent
+	assert.ErrorIs(t, config.Validate(), ErrInvalidRoomConfig)
+
+	config.Limits.MaxPot = -game.Dollar
+	assert.ErrorIs(t, config.Validate(), ErrInvalidRoomConfig)
+
+	config.Limits = BetLimits{MaxPot: 500 * game.Dollar, MaxRoundPayout: 1000 * game.Dollar}
+	assert.NoError(t, config.Validate())
+
+	// Room settings from players leave the operator's limits alone
+	merged, err := config.WithSettings(&RoomSettings{MaxBet: 250 * game.Dollar})
+	require.NoError(t, err)
+	assert.Equal(t, config.Limits, merged.Limits)
+}
+
+func TestBetLimits_CapSettings(t *testing.T) {
+	config := DefaultRoomConfig()
+	config.Limits = BetLimits{MaxBet: 500 * game.Dollar, MaxPayoutRatio: 2}
+
+	merged, err := config.WithSettings(&RoomSettings{MaxBet: 5000 * game.Dollar, PayoutRatio: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 500*game.Dollar, merged.MaxBet)
+	assert.Equal(t, 2.0, merged.PayoutRatio)
+
+	// Settings within the caps are kept
+	merged, err = config.WithSettings(&RoomSettings{MaxBet: 50 * game.Dollar, PayoutRatio: 1.9})
+	require.NoError(t, err)
+	assert.Equal(t, 50*game.Dollar, merged.MaxBet)
+	assert.Equal(t, 1.9, merged.PayoutRatio)
+
+	// A room cannot vote itself past them either
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	room, err := server.CreateRoom("capped", "Capped", config)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	require.NoError(t, room.ProposeConfig("p1", &RoomSettings{PayoutRatio: 3}))
+	assert.Equal(t, 2.0, room.GetConfig().PayoutRatio)
+}
+
+func TestBetLimits_CapStreakSettings(t *testing.T) {
+	config := DefaultRoomConfig()
+	config.Limits = BetLimits{MaxStreakBonus: 0.25, MaxStreakMultiplier: 3}
+
+	merged, err := config.WithSettings(&RoomSettings{StreakBonus: 5, MaxStreakMultiplier: 100})
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, merged.StreakBonus)
+	assert.Equal(t, 3.0, merged.MaxStreakMultiplier)
+
+	// Without a cap of its own the multiplier would grow with every win
+	merged, err = config.WithSettings(&RoomSettings{StreakBonus: 0.1})
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, merged.StreakBonus)
+	assert.Equal(t, 3.0, merged.MaxStreakMultiplier)
+
+	merged, err = config.WithSettings(&RoomSettings{StreakBonus: 0.1, MaxStreakMultiplier: 1.5})
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, merged.MaxStreakMultiplier)
+
+	// Rooms without streaks are left alone
+	merged, err = config.WithSettings(&RoomSettings{MaxBet: 50 * game.Dollar})
+	require.NoError(t, err)
+	assert.Zero(t, merged.MaxStreakMultiplier)
+}
+
+func TestBetLimits_BoundInsuranceSettings(t *testing.T) {
+	config := DefaultRoomConfig()
+	config.Limits = BetLimits{MinInsuranceCost: 0.3, MaxInsuranceCoverage: 0.5}
+
+	merged, err := config.WithSettings(&RoomSettings{InsuranceCost: 0.05, InsuranceCoverage: 1})
+	require.NoError(t, err)
+	assert.Equal(t, game.Insurance{Cost: 0.3, Coverage: 0.5}, merged.Insurance)
+
+	merged, err = config.WithSettings(&RoomSettings{InsuranceCost: 0.4, InsuranceCoverage: 0.2})
+	require.NoError(t, err)
+	assert.Equal(t, game.Insurance{Cost: 0.4, Coverage: 0.2}, merged.Insurance)
+
+	// Within the limits, insurance paying back more than it costs is
+	// still refused
+	config.Limits = BetLimits{MaxInsuranceCoverage: 0.8}
+	_, err = config.WithSettings(&RoomSettings{InsuranceCost: 0.1, InsuranceCoverage: 0.5})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+	merged, err = config.WithSettings(&RoomSettings{InsuranceCost: 0.25, InsuranceCoverage: 0.5})
+	require.NoError(t, err)
+	assert.Zero(t, merged.Insurance.ExpectedValue(1))
+
+	// The operator's own insurance is theirs to price, and a vote on other
+	// settings keeps it
+	config.Insurance = game.Insurance{Cost: 0.1, Coverage: 0.5}
+	merged, err = config.WithSettings(&RoomSettings{MaxBet: 50 * game.Dollar})
+	require.NoError(t, err)
+	assert.Equal(t, config.Insurance, merged.Insurance)
+	_, err = config.WithSettings(&RoomSettings{InsuranceCoverage: 0.6})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+}
@@ -0,0 +1,173 @@
+package network
+
+import (
+	"slices"
+	"strings"
+
+	"coinflip-game/internal/game"
+)
+
+// DefaultLocale is the language of rooms that do not declare one, and of
+// every message the catalogs have no translation for
+const DefaultLocale = "en"
+
+// catalogs translate what the server tells players, by locale and then by
+// the English message. Errors wrapped as "message: detail" translate the
+// message and keep the detail.
+var catalogs = map[string]map[string]string{
+	"es": {
+		"Not currently in a room":           "No estás en ninguna sala",
+		"Failed to parse message":           "No se pudo leer el mensaje",
+		"Invalid bet data":                  "Datos de apuesta no válidos",
+		"Invalid chat message":              "Mensaje de chat no válido",
+		"Invalid settings proposal":         "Propuesta de ajustes no válida",
+		"Invalid settings vote":             "Voto de ajustes no válido",
+		"Invalid pause request":             "Solicitud de pausa no válida",
+		"Invalid pause vote":                "Voto de pausa no válido",
+		"Invalid invite data":               "Datos de invitación no válidos",
+		ErrRoomFull.Error():                 "la sala está llena",
+		ErrRoomNotFound.Error():             "sala no encontrada",
+		ErrRoomClosed.Error():               "la sala se ha cerrado",
+		ErrPlayerNotFound.Error():           "no estás sentado en esta sala",
+		ErrInvalidGamePhase.Error():         "no se puede hacer en esta fase de la partida",
+		ErrBettingClosed.Error():            "la fase de apuestas ha terminado",
+		ErrPlayerAlreadyBet.Error():         "ya has apostado a ese lado en esta ronda",
+		ErrNoActiveRound.Error():            "no hay ninguna ronda en curso",
+		ErrNoBetToCancel.Error():            "no tienes ninguna apuesta en esta ronda",
+		ErrNoQueuedBet.Error():              "no tienes ninguna apuesta guardada para la próxima ronda",
+		ErrPracticeMixed.Error():            "no se pueden mezclar apuestas de práctica y reales en una ronda",
+		ErrBetLimit.Error():                 "la apuesta supera los límites de la sala",
+		roundCancelledOneSided:              "nadie apostó al otro lado del bote",
+		roundCancelledBroadcastFailure:      "no se pudo anunciar el resultado de la ronda",
+		game.ErrInsufficientBalance.Error(): "saldo insuficiente para la apuesta",
+		game.ErrInvalidBetAmount.Error():    "importe de apuesta no válido",
+		game.ErrGameNotActive.Error():       "la partida no está activa",
+		game.ErrInvalidChoice.Error():       "elección no válida, debe ser cara o cruz",
+	},
+	"de": {
+		"Not currently in a room":           "Du bist in keinem Raum",
+		"Failed to parse message":           "Die Nachricht konnte nicht gelesen werden",
+		"Invalid bet data":                  "Ungültige Wettdaten",
+		"Invalid chat message":              "Ungültige Chatnachricht",
+		"Invalid settings proposal":         "Ungültiger Einstellungsvorschlag",
+		"Invalid settings vote":             "Ungültige Abstimmung über Einstellungen",
+		"Invalid pause request":             "Ungültige Pausenanfrage",
+		"Invalid pause vote":                "Ungültige Abstimmung über die Pause",
+		"Invalid invite data":               "Ungültige Einladungsdaten",
+		ErrRoomFull.Error():                 "der Raum ist voll",
+		ErrRoomNotFound.Error():             "Raum nicht gefunden",
+		ErrRoomClosed.Error():               "der Raum wurde geschlossen",
+		ErrPlayerNotFound.Error():           "du sitzt nicht in diesem Raum",
+		ErrInvalidGamePhase.Error():         "in dieser Spielphase nicht möglich",
+		ErrBettingClosed.Error():            "die Wettphase ist vorbei",
+		ErrPlayerAlreadyBet.Error():         "du hast in dieser Runde schon auf diese Seite gesetzt",
+		ErrNoActiveRound.Error():            "es läuft keine Runde",
+		ErrNoBetToCancel.Error():            "du hast in dieser Runde keine Wette",
+		ErrNoQueuedBet.Error():              "du hast keine Wette für die nächste Runde vorgemerkt",
+		ErrPracticeMixed.Error():            "Übungswetten und echte Wetten können in einer Runde nicht gemischt werden",
+		ErrBetLimit.Error():                 "die Wette überschreitet die Limits des Raums",
+		roundCancelledOneSided:              "niemand hat auf die andere Seite des Pools gesetzt",
+		roundCancelledBroadcastFailure:      "das Rundenergebnis konnte nicht verkündet werden",
+		game.ErrInsufficientBalance.Error(): "dein Guthaben reicht für die Wette nicht aus",
+		game.ErrInvalidBetAmount.Error():    "ungültiger Einsatz",
+		game.ErrGameNotActive.Error():       "das Spiel läuft nicht",
+		game.ErrInvalidChoice.Error():       "ungültige Wahl, nur Kopf oder Zahl",
+	},
+	"fr": {
+		"Not currently in a room":           "Vous n'êtes dans aucune salle",
+		"Failed to parse message":           "Impossible de lire le message",
+		"Invalid bet data":                  "Données de pari invalides",
+		"Invalid chat message":              "Message de chat invalide",
+		"Invalid settings proposal":         "Proposition de réglages invalide",
+		"Invalid settings vote":             "Vote sur les réglages invalide",
+		"Invalid pause request":             "Demande de pause invalide",
+		"Invalid pause vote":                "Vote de pause invalide",
+		"Invalid invite data":               "Données d'invitation invalides",
+		ErrRoomFull.Error():                 "la salle est pleine",
+		ErrRoomNotFound.Error():             "salle introuvable",
+		ErrRoomClosed.Error():               "la salle a été fermée",
+		ErrPlayerNotFound.Error():           "vous n'êtes pas assis dans cette salle",
+		ErrInvalidGamePhase.Error():         "action impossible dans cette phase de jeu",
+		ErrBettingClosed.Error():            "la phase de paris est terminée",
+		ErrPlayerAlreadyBet.Error():         "vous avez déjà parié sur ce côté pendant cette manche",
+		ErrNoActiveRound.Error():            "aucune manche en cours",
+		ErrNoBetToCancel.Error():            "vous n'avez pas de pari pendant cette manche",
+		ErrNoQueuedBet.Error():              "vous n'avez pas de pari en attente pour la prochaine manche",
+		ErrPracticeMixed.Error():            "les paris d'entraînement et réels ne peuvent pas être mélangés dans une manche",
+		ErrBetLimit.Error():                 "le pari dépasse les limites de la salle",
+		roundCancelledOneSided:              "personne n'a misé de l'autre côté de la cagnotte",
+		roundCancelledBroadcastFailure:      "le résultat de la manche n'a pas pu être annoncé",
+		game.ErrInsufficientBalance.Error(): "solde insuffisant pour ce pari",
+		game.ErrInvalidBetAmount.Error():    "montant de pari invalide",
+		game.ErrGameNotActive.Error():       "la partie n'est pas active",
+		game.ErrInvalidChoice.Error():       "choix invalide, pile ou face uniquement",
+	},
+}
+
+// Reasons a round is cancelled, announced in RoundCancelledData
+const (
+	roundCancelledOneSided         = "no stakes on the other side of the pool"
+	roundCancelledBroadcastFailure = "failed to broadcast round result"
+)
+
+// SupportedLocales returns the locales rooms can declare, the default first
+func SupportedLocales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales[1:])
+	return locales
+}
+
+// NormalizeLocale reduces a language tag such as "es-MX" to the locale it
+// is served in, reporting whether that is supported. Empty is the default.
+func NormalizeLocale(tag string) (string, bool) {
+	locale := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "" || locale == DefaultLocale {
+		return DefaultLocale, true
+	}
+	_, ok := catalogs[locale]
+	return locale, ok
+}
+
+// Localize translates a message into the locale, leaving it in English
+// when the locale's catalog has no translation
+func Localize(locale, message string) string {
+	catalog := catalogs[locale]
+	if catalog == nil {
+		return message
+	}
+	if translated, ok := catalog[message]; ok {
+		return translated
+	}
+	if head, detail, ok := strings.Cut(message, ": "); ok {
+		if translated, ok := catalog[head]; ok {
+			return translated + ": " + detail
+		}
+	}
+	return message
+}
+
+// Locale returns the locale the room's announcements and errors are in
+func (r *GameRoom) Locale() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.locale()
+}
+
+// locale returns the room's locale. Callers must hold r.mu.
+func (r *GameRoom) locale() string {
+	locale, _ := NormalizeLocale(r.config.Locale)
+	return locale
+}
+
+// localize translates a message into the room's locale. Callers must hold
+// r.mu.
+func (r *GameRoom) localize(message string) string {
+	return Localize(r.locale(), message)
+}
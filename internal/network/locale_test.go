@@ -0,0 +1,80 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestLocalize(t *testing.T) {
+	assert.Equal(t, "la sala está llena", Localize("es", ErrRoomFull.Error()))
+	// Wrapped errors keep their detail
+	wrapped := fmt.Errorf("%w: max 100", ErrBetLimit).Error()
+	assert.Equal(t, "die Wette überschreitet die Limits des Raums: max 100", Localize("de", wrapped))
+	// Anything without a translation stays in English
+	assert.Equal(t, "something new", Localize("fr", "something new"))
+	assert.Equal(t, ErrRoomFull.Error(), Localize(DefaultLocale, ErrRoomFull.Error()))
+
+	locale, ok := NormalizeLocale("es-MX")
+	assert.True(t, ok)
+	assert.Equal(t, "es", locale)
+	locale, ok = NormalizeLocale("")
+	assert.True(t, ok)
+	assert.Equal(t, DefaultLocale, locale)
+	_, ok = NormalizeLocale("xx")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"en", "de", "es", "fr"}, SupportedLocales())
+
+	_, err := DefaultRoomConfig().WithSettings(&RoomSettings{Locale: "xx"})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+}
+
+func TestServer_LocalizedErrorsAndLobby(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+
+	_, err := server.CreateRoom("open", "Open", DefaultRoomConfig())
+	require.NoError(t, err)
+	config, err := server.NewRoomConfig(&RoomSettings{Locale: "es-MX"})
+	require.NoError(t, err)
+	mesa, err := server.CreateRoom("mesa", "Mesa", config)
+	require.NoError(t, err)
+	assert.Equal(t, "es", mesa.Locale())
+	require.NoError(t, mesa.AddPlayer("p1", "Player 1", 100*game.Dollar))
+
+	client := &Client{server: server, send: make(chan []byte, 1), protocol: ProtocolVersion, playerID: "p1", room: mesa}
+	client.sendError("bet_failed", ErrNoActiveRound.Error())
+	var msg Message
+	require.NoError(t, json.Unmarshal(<-client.send, &msg))
+	var reply ErrorData
+	require.NoError(t, msg.GetData(&reply))
+	assert.Equal(t, "no hay ninguna ronda en curso", reply.Message)
+
+	rooms := func(query string) []string {
+		recorder := httptest.NewRecorder()
+		server.handleRooms(recorder, httptest.NewRequest(http.MethodGet, "/rooms"+query, nil))
+		var response struct {
+			Rooms []struct {
+				ID string `json:"id"`
+			} `json:"rooms"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+		ids := make([]string, 0, len(response.Rooms))
+		for _, room := range response.Rooms {
+			ids = append(ids, room.ID)
+		}
+		return ids
+	}
+	assert.ElementsMatch(t, []string{"open", "mesa"}, rooms(""))
+	assert.Equal(t, []string{"mesa"}, rooms("?locale=es"))
+	assert.Equal(t, []string{"open"}, rooms("?locale=en-GB"))
+	assert.Empty(t, rooms("?locale=xx"))
+}
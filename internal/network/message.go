@@ -15,23 +15,82 @@ type MessageType string
 const (
 	// Room management messages
 	MsgJoinRoom    MessageType = "join_room"
+	MsgCreateRoom  MessageType = "create_room"
 	MsgLeaveRoom   MessageType = "leave_room"
 	MsgRoomUpdate  MessageType = "room_update"
+	MsgRoomClosed  MessageType = "room_closed"
 	MsgPlayerList  MessageType = "player_list"
+	MsgPlayerStats MessageType = "player_stats"
+	MsgRedeemCode  MessageType = "redeem_code"
+	MsgRegister    MessageType = "register"
+	MsgWalletSync  MessageType = "wallet_sync"
+	MsgFindDuel    MessageType = "find_duel"
+	
+	// Cosmetics
+	MsgInventory   MessageType = "inventory"
+	MsgBuySkin     MessageType = "buy_skin"
+	MsgEquipSkin   MessageType = "equip_skin"
+	
+	// Friends and room invites
+	MsgFriends      MessageType = "friends"
+	MsgAddFriend    MessageType = "add_friend"
+	MsgRemoveFriend MessageType = "remove_friend"
+	MsgRoomInvite   MessageType = "room_invite"
+	
+	// Room settings negotiation
+	MsgConfigProposal MessageType = "config_proposal"
+	MsgConfigVote     MessageType = "config_vote"
+	MsgConfigChanged  MessageType = "config_changed"
+	
+	// Pausing and resuming by vote
+	MsgPauseProposal  MessageType = "pause_proposal"
+	MsgPauseVote      MessageType = "pause_vote"
 	
 	// Game flow messages
 	MsgGameStart   MessageType = "game_start"
 	MsgBetPhase    MessageType = "bet_phase"
 	MsgBetPlaced   MessageType = "bet_placed"
+	MsgCancelBet   MessageType = "cancel_bet"
+	MsgUpdateBet   MessageType = "update_bet"
 	MsgRevealPhase MessageType = "reveal_phase"
 	MsgGameResult  MessageType = "game_result"
 	MsgRoundEnd    MessageType = "round_end"
+	MsgRoundCancelled MessageType = "round_cancelled"
+	MsgQueuedBet   MessageType = "queued_bet"
+	MsgBetSlip     MessageType = "bet_slip"
 	
 	// Synchronization messages
 	MsgTimerUpdate MessageType = "timer_update"
 	MsgSeedCommit  MessageType = "seed_commit"
 	MsgSeedReveal  MessageType = "seed_reveal"
 	
+	// Room chat
+	MsgChat        MessageType = "chat"
+	
+	// Replays of recorded rounds
+	MsgReplayRound MessageType = "replay_round"
+	MsgReplayEvent MessageType = "replay_event"
+	MsgReplayEnd   MessageType = "replay_end"
+	MsgStopReplay  MessageType = "stop_replay"
+	
+	// Moderation
+	MsgKickPlayer  MessageType = "kick_player"
+	
+	// Weekly season standings
+	MsgSeasonUpdate MessageType = "season_update"
+	
+	// Email and Telegram notifications
+	MsgNotifySettings MessageType = "notify_settings"
+	
+	// Happy hours boosting payouts
+	MsgPromotion   MessageType = "promotion"
+	
+	// Players who stopped betting
+	MsgIdle        MessageType = "idle"
+	
+	// Room statistics, broadcast periodically
+	MsgRoomStats   MessageType = "room_stats"
+	
 	// Error handling
 	MsgError       MessageType = "error"
 )
@@ -43,12 +102,287 @@ type Message struct {
 	PlayerID  string      `json:"player_id"`
 	Timestamp time.Time   `json:"timestamp"`
 	Data      interface{} `json:"data"`
+	// TraceParent carries the W3C trace context of the span that sent the
+	// message, so a request or round can be traced across client and server
+	TraceParent string    `json:"traceparent,omitempty"`
 }
 
 // RoomJoinData contains information for joining a room
 type RoomJoinData struct {
-	PlayerName string  `json:"player_name"`
-	Balance    float64 `json:"balance"`
+	PlayerName string        `json:"player_name"`
+	Balance    game.Money    `json:"balance"` // Ignored: the server keeps every player's balance
+	Settings   *RoomSettings `json:"settings,omitempty"` // Applied only if the join creates the room
+	// Watch follows an existing room's events without taking a seat
+	Watch      bool          `json:"watch,omitempty"`
+	// RequestedName is set when the server echoes a join back because
+	// another player in the room had the name: PlayerName is then the name
+	// the player was seated under instead
+	RequestedName string     `json:"requested_name,omitempty"`
+}
+
+// RoomCreateData contains information for creating a room
+type RoomCreateData struct {
+	RoomName string        `json:"room_name"`
+	Settings *RoomSettings `json:"settings,omitempty"`
+}
+
+// FindDuelData asks the server for an opponent rated close to the player.
+// The server replies with the same message type, naming the duel room in
+// the message's RoomID and filling in the player's Rating and the Bucket
+// they were matched in, then seats them there as a join would.
+type FindDuelData struct {
+	PlayerName string           `json:"player_name"`
+	Rating     *game.DuelRating `json:"rating,omitempty"`
+	Bucket     int              `json:"bucket,omitempty"`
+}
+
+// PlayerStatsData requests a player's lifetime statistics; the server
+// replies with the same message type and Stats and Rating filled in
+type PlayerStatsData struct {
+	PlayerID string      `json:"player_id"`
+	Stats    *game.Stats `json:"stats,omitempty"`
+	// Rating is the player's duel rating
+	Rating   *game.DuelRating `json:"rating,omitempty"`
+}
+
+// RedeemCodeData redeems a promo code; the server replies with the same
+// message type, the credited Amount and the player's new Balance
+type RedeemCodeData struct {
+	Code    string     `json:"code"`
+	Amount  game.Money `json:"amount,omitempty"`
+	Balance game.Money `json:"balance,omitempty"`
+}
+
+// WalletSyncData reconciles a player's offline play with their wallet on
+// the server. The client sends Net, what its practice balance changed by
+// since the last sync; the server replies with the same message type, the
+// part of Net it Applied and the player's new Balance.
+type WalletSyncData struct {
+	Net     game.Money `json:"net"`
+	Applied game.Money `json:"applied,omitempty"`
+	Balance game.Money `json:"balance,omitempty"`
+}
+
+// RegisterData upgrades a guest to a registered account; the server
+// replies with the same message type and the account's migrated Balance
+// and Stats
+type RegisterData struct {
+	Account string      `json:"account"`
+	Balance game.Money  `json:"balance,omitempty"`
+	Stats   *game.Stats `json:"stats,omitempty"`
+}
+
+// SkinData asks to buy or equip a coin skin, or with MsgInventory for the
+// player's cosmetics. The server replies with the same message type, the
+// player's Balance and their Inventory.
+type SkinData struct {
+	SkinID    string          `json:"skin_id,omitempty"`
+	Balance   game.Money      `json:"balance,omitempty"`
+	Inventory *game.Inventory `json:"inventory,omitempty"`
+}
+
+// FriendsData asks for the player's friends, or with MsgAddFriend and
+// MsgRemoveFriend names the Friend to add or remove by account name or
+// friend code. The server replies with the same message type, the
+// player's own friend Code and their Friends.
+type FriendsData struct {
+	Friend  string         `json:"friend,omitempty"`
+	Code    string         `json:"code,omitempty"`
+	Friends []FriendStatus `json:"friends,omitempty"`
+}
+
+// FriendStatus is one friend on a player's list and whether they are
+// online. RoomID is the room they are playing in, if any.
+type FriendStatus struct {
+	PlayerID string `json:"player_id"`
+	Name     string `json:"name"`
+	Code     string `json:"code"`
+	Online   bool   `json:"online"`
+	RoomID   string `json:"room_id,omitempty"`
+}
+
+// InviteData invites a friend, by player ID, to the sender's room. The
+// server fills in who sent it and the room, delivers it to the friend and
+// echoes it back to the sender as confirmation.
+type InviteData struct {
+	To       string `json:"to"`
+	From     string `json:"from,omitempty"`
+	FromName string `json:"from_name,omitempty"`
+	RoomID   string `json:"room_id,omitempty"`
+	RoomName string `json:"room_name,omitempty"`
+}
+
+// PromotionData announces a happy hour starting in the room, or with
+// Active unset, ending
+type PromotionData struct {
+	Promotion *Promotion `json:"promotion"`
+	Active    bool       `json:"active"`
+}
+
+// IdleData warns the player in the message's PlayerID that missing one more
+// round takes Step, or with RoundsLeft zero that it was just taken
+type IdleData struct {
+	Step       string `json:"step"`
+	RoundsLeft int    `json:"rounds_left,omitempty"`
+}
+
+// RoomStatsData is how a room has played since it opened. Practice bets
+// are left out of the money figures.
+type RoomStatsData struct {
+	RoundsPlayed int        `json:"rounds_played"`
+	Volume       game.Money `json:"volume"`
+	// Paid is what the room paid back out, insurance refunds included,
+	// and RTP its share of Volume
+	Paid         game.Money `json:"paid"`
+	RTP          float64    `json:"rtp"`
+	// BiggestWin is the most one player made on a single round
+	BiggestWin    game.Money `json:"biggest_win"`
+	BiggestWinner string     `json:"biggest_winner,omitempty"`
+	// The coin has come up StreakSide the last StreakLength rounds
+	StreakSide   game.Side  `json:"streak_side,omitempty"`
+	StreakLength int        `json:"streak_length"`
+}
+
+// SeasonUpdateData is a player's view of the season: the leaders, their
+// own standing, and when the season ends. At rollover it also carries the
+// season that just ended, with the player's final standing and badge.
+type SeasonUpdateData struct {
+	Season  string                `json:"season"`
+	EndsAt  time.Time             `json:"ends_at"`
+	Leaders []game.SeasonStanding `json:"leaders"`
+	// Standing is the player's place, nil until they settle a round
+	Standing *game.SeasonStanding `json:"standing,omitempty"`
+	Ended    *SeasonEndData       `json:"ended,omitempty"`
+}
+
+// NotifySettingsData asks for the player's notification settings, or with
+// Settings replaces them. The reply carries the settings and the Channels
+// the server can send on.
+type NotifySettingsData struct {
+	Settings *game.NotifySettings `json:"settings,omitempty"`
+	Channels []string             `json:"channels,omitempty"`
+}
+
+// SeasonEndData is how a season ended for the player it is sent to
+type SeasonEndData struct {
+	Season   string                `json:"season"`
+	Leaders  []game.SeasonStanding `json:"leaders"`
+	Standing *game.SeasonStanding  `json:"standing,omitempty"`
+	Badge    *game.SeasonBadge     `json:"badge,omitempty"`
+}
+
+// KickData names the player a moderator removes from the message's room
+type KickData struct {
+	PlayerID string `json:"player_id"`
+}
+
+// DistributionData reports how often the coin landed on each side across
+// all rounds and which sides players backed across all bets
+type DistributionData struct {
+	Outcomes game.Distribution `json:"outcomes"`
+	Choices  game.Distribution `json:"choices"`
+}
+
+// ProposalStatus is the state of a room settings proposal
+type ProposalStatus string
+
+const (
+	ProposalOpen     ProposalStatus = "open"
+	ProposalPassed   ProposalStatus = "passed"
+	ProposalRejected ProposalStatus = "rejected"
+)
+
+// ConfigProposalData proposes new room settings. Clients send only
+// Settings; the server broadcasts it with the ID and running tally.
+type ConfigProposalData struct {
+	ProposalID string         `json:"proposal_id,omitempty"`
+	ProposerID string         `json:"proposer_id,omitempty"`
+	Settings   *RoomSettings  `json:"settings"`
+	Status     ProposalStatus `json:"status,omitempty"`
+	Yes        int            `json:"yes"`
+	No         int            `json:"no"`
+	Eligible   int            `json:"eligible"`
+}
+
+// ConfigVoteData casts a player's vote on an open proposal
+type ConfigVoteData struct {
+	ProposalID string `json:"proposal_id"`
+	Approve    bool   `json:"approve"`
+}
+
+// PauseAction is what a pause proposal asks of the room
+type PauseAction string
+
+const (
+	PauseActionPause  PauseAction = "pause"
+	PauseActionResume PauseAction = "resume"
+)
+
+// PauseProposalData asks the room to pause or resume. Clients send only
+// Action; the server broadcasts it with the ID, requester and running
+// tally. DecidedBy is set when the room owner settled it on their own.
+type PauseProposalData struct {
+	ProposalID  string         `json:"proposal_id,omitempty"`
+	Action      PauseAction    `json:"action"`
+	RequesterID string         `json:"requester_id,omitempty"`
+	Status      ProposalStatus `json:"status,omitempty"`
+	Yes         int            `json:"yes"`
+	No          int            `json:"no"`
+	Eligible    int            `json:"eligible"`
+	DecidedBy   string         `json:"decided_by,omitempty"`
+}
+
+// PauseVoteData casts a player's vote on an open pause proposal
+type PauseVoteData struct {
+	ProposalID string `json:"proposal_id"`
+	Approve    bool   `json:"approve"`
+}
+
+// RoomSettings contains per-room overrides of the server defaults.
+// Zero values keep the server default for that field.
+type RoomSettings struct {
+	MinPlayers     int     `json:"min_players,omitempty"`
+	MaxPlayers     int     `json:"max_players,omitempty"`
+	MinBet         game.Money `json:"min_bet,omitempty"`
+	MaxBet         game.Money `json:"max_bet,omitempty"`
+	PayoutRatio    float64 `json:"payout_ratio,omitempty"`
+	BettingSeconds int     `json:"betting_seconds,omitempty"`
+	ResultSeconds  int     `json:"result_seconds,omitempty"`
+	// StreakBonus grows the payout multiplier per consecutive winning round,
+	// up to MaxStreakMultiplier when that is set
+	StreakBonus         float64 `json:"streak_bonus,omitempty"`
+	MaxStreakMultiplier float64 `json:"max_streak_multiplier,omitempty"`
+	// InsuranceCost and InsuranceCoverage offer bet insurance: the share of
+	// the stake it costs and the share refunded when the bet loses
+	InsuranceCost     float64 `json:"insurance_cost,omitempty"`
+	InsuranceCoverage float64 `json:"insurance_coverage,omitempty"`
+	// Mode picks one of the RoomModes. Private hides a new room from the
+	// room list; it cannot be turned off once set.
+	Mode    string `json:"mode,omitempty"`
+	Private bool   `json:"private,omitempty"`
+	// EarlyClose ends betting EarlyCloseSeconds after every connected
+	// player has bet
+	EarlyClose        bool `json:"early_close,omitempty"`
+	EarlyCloseSeconds int  `json:"early_close_seconds,omitempty"`
+	// Rake is the share of a parimutuel pool the house keeps
+	Rake float64 `json:"rake,omitempty"`
+	// Turbo plays 10 second betting and 3 second result phases in place
+	// of BettingSeconds and ResultSeconds; it cannot be turned off once set
+	Turbo bool `json:"turbo,omitempty"`
+	// Locale is the language of the room's announcements and error
+	// messages, such as "es"; English when empty
+	Locale string `json:"locale,omitempty"`
+	// IdleRounds marks a player idle after that many rounds in a row
+	// without a bet, and IdleReleaseRounds gives their seat to the queue
+	// after that many; 0 turns either off
+	IdleRounds        int `json:"idle_rounds,omitempty"`
+	IdleReleaseRounds int `json:"idle_release_rounds,omitempty"`
+	// StartingStack makes the room a freeroll: every player is seated with
+	// it instead of their bankroll, and what they win above it is paid
+	// into the bankroll at FreerollRate when they leave. It is set when the
+	// room opens. FreerollRate is the server's; it is reported, not chosen.
+	StartingStack game.Money `json:"starting_stack,omitempty"`
+	FreerollRate  float64    `json:"freeroll_rate,omitempty"`
 }
 
 // RoomUpdateData contains current room state
@@ -59,16 +393,67 @@ type RoomUpdateData struct {
 	Timer       int          `json:"timer_seconds"`
 	MinPlayers  int          `json:"min_players"`
 	MaxPlayers  int          `json:"max_players"`
+	// PayoutRatio and Insurance let clients show what a bet is worth
+	PayoutRatio float64         `json:"payout_ratio,omitempty"`
+	Insurance   *game.Insurance `json:"insurance,omitempty"`
+	// Promotion is the happy hour boosting the room's payouts, if any
+	Promotion   *Promotion      `json:"promotion,omitempty"`
+	// Owner is the player who opened the room. PausedBy is the player
+	// whose request paused it, empty when it paused for lack of players;
+	// while paused, Timer holds the betting time left.
+	Owner       string       `json:"owner,omitempty"`
+	PausedBy    string       `json:"paused_by,omitempty"`
+	// SeatQueue lists the spectators waiting for a seat in a full room,
+	// first in line first
+	SeatQueue   []QueuedPlayer `json:"seat_queue,omitempty"`
+	// A delta update carries in Players only the players that changed
+	// since the previous update, and the IDs of those who left in Removed
+	Delta       bool         `json:"delta,omitempty"`
+	Removed     []string     `json:"removed,omitempty"`
+	
+	// full lists every player of a delta update, for clients that predate
+	// delta updates
+	full        []PlayerInfo
 }
 
 // PlayerInfo contains public player information
 type PlayerInfo struct {
 	ID       string  `json:"id"`
 	Name     string  `json:"name"`
-	Balance  float64 `json:"balance"`
+	Balance  game.Money `json:"balance"`
 	IsReady  bool    `json:"is_ready"`
 	HasBet   bool    `json:"has_bet"`
+	Bets     []BetData `json:"bets,omitempty"`
 	IsOnline bool    `json:"is_online"`
+	// WinStreak counts the player's consecutive winning rounds in the room
+	WinStreak int    `json:"win_streak,omitempty"`
+	// Skin is the coin skin the player has equipped
+	Skin     string  `json:"skin,omitempty"`
+	// LatencyMs is the round trip time to the player's client, in
+	// milliseconds; zero until measured
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+	// Idle players have missed enough rounds that the room no longer
+	// counts them towards its minimum players
+	Idle     bool    `json:"idle,omitempty"`
+	// Rating is the player's duel rating, shown in duel rooms only
+	Rating   int     `json:"rating,omitempty"`
+}
+
+// QueuedPlayer is a spectator waiting for a seat in a full room
+type QueuedPlayer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// QueuePosition returns the player's place in line for a seat, counting
+// from 1, or 0 when they are not waiting
+func (d *RoomUpdateData) QueuePosition(playerID string) int {
+	for i, queued := range d.SeatQueue {
+		if queued.ID == playerID {
+			return i + 1
+		}
+	}
+	return 0
 }
 
 // GameState represents the current state of a multiplayer game
@@ -85,9 +470,23 @@ const (
 // BetData contains betting information
 type BetData struct {
 	PlayerID string     `json:"player_id"`
-	Amount   float64    `json:"amount"`
+	Amount   game.Money `json:"amount"`
 	Choice   game.Side  `json:"choice"`
 	BetID    string     `json:"bet_id"`
+	// Insured asks for the room's insurance. The server fills in the
+	// Premium paid on top of Amount and the Coverage refunded on a loss.
+	Insured  bool       `json:"insured,omitempty"`
+	Premium  game.Money `json:"premium,omitempty"`
+	Coverage game.Money `json:"coverage,omitempty"`
+	// Practice bets resolve with the round but move no money
+	Practice bool       `json:"practice,omitempty"`
+	// Requested is the amount asked for when the room's betting limits
+	// scaled the bet down to Amount
+	Requested game.Money `json:"requested,omitempty"`
+	// SentAtMs is when the player placed the bet, in Unix milliseconds on
+	// the client's clock, so a bet sent just before the deadline still
+	// counts when it arrives just after
+	SentAtMs int64      `json:"sent_at_ms,omitempty"`
 }
 
 // TimerData contains timer information
@@ -95,6 +494,15 @@ type TimerData struct {
 	Phase         GameState `json:"phase"`
 	SecondsLeft   int       `json:"seconds_left"`
 	TotalSeconds  int       `json:"total_seconds"`
+	// EarlyClose is set once betting was cut short because every
+	// connected player has bet
+	EarlyClose    bool      `json:"early_close,omitempty"`
+	// MillisLeft and TotalMillis give the countdown to the millisecond
+	// for smooth progress bars. Turbo rooms send them several times a
+	// second, and clients show tenths of a second.
+	MillisLeft  int64 `json:"millis_left,omitempty"`
+	TotalMillis int64 `json:"total_millis,omitempty"`
+	Turbo       bool  `json:"turbo,omitempty"`
 }
 
 // SeedCommitData contains committed seed hash for consensus
@@ -119,16 +527,162 @@ type GameResultData struct {
 	Winners    []PlayerResult   `json:"winners"`
 	Losers     []PlayerResult   `json:"losers"`
 	Timestamp  time.Time        `json:"timestamp"`
+	// Pool breaks down a parimutuel round's pool
+	Pool       *PoolData        `json:"pool,omitempty"`
+}
+
+// PoolData is how a parimutuel round's pool was split: the stakes on each
+// side, the rake the house kept and what the winners shared
+type PoolData struct {
+	Total game.Money `json:"total"`
+	Heads game.Money `json:"heads"`
+	Tails game.Money `json:"tails"`
+	Rake  game.Money `json:"rake"`
+	Paid  game.Money `json:"paid"`
 }
 
 // PlayerResult contains individual player's result
 type PlayerResult struct {
 	PlayerID     string     `json:"player_id"`
 	PlayerName   string     `json:"player_name"`
-	Bet          *BetData   `json:"bet,omitempty"`
+	Bets         []*BetData `json:"bets"`
+	Wagered      game.Money `json:"wagered"`
+	// Won is set when the round paid out more than was wagered, so a hedged
+	// player whose smaller position won counts as a loser
 	Won          bool       `json:"won"`
-	Payout       float64    `json:"payout"`
-	NewBalance   float64    `json:"new_balance"`
+	Payout       game.Money `json:"payout"`
+	NewBalance   game.Money `json:"new_balance"`
+	// Multiplier is the streak bonus applied to the winning bet, if any,
+	// and WinStreak the player's streak after this round
+	Multiplier   float64    `json:"multiplier,omitempty"`
+	WinStreak    int        `json:"win_streak"`
+	// Boost is the happy hour boost applied to the winning bet, if any
+	Boost        float64    `json:"boost,omitempty"`
+	// Insurance is what the player's insured losing bets refunded; it is
+	// part of NewBalance but not of Payout
+	Insurance    game.Money `json:"insurance,omitempty"`
+	Skin         string     `json:"skin,omitempty"`
+	// Practice results leave NewBalance and WinStreak as they were and
+	// count only towards the player's practice ledger
+	Practice     bool       `json:"practice,omitempty"`
+	// PoolShare is the player's share of the winning side of a parimutuel
+	// pool, which their Payout is the same share of
+	PoolShare    float64    `json:"pool_share,omitempty"`
+}
+
+// QueuedBetStatus is the state of a bet queued for the next round
+type QueuedBetStatus string
+
+const (
+	QueuedBetWaiting   QueuedBetStatus = "queued"    // Held until betting opens
+	QueuedBetPlaced    QueuedBetStatus = "placed"    // Submitted when betting opened
+	QueuedBetWithdrawn QueuedBetStatus = "withdrawn" // Cancelled by the player
+	QueuedBetFailed    QueuedBetStatus = "failed"    // Rejected when betting opened
+)
+
+// QueuedBetData queues a bet for the next betting phase; clients send only
+// Bet, with an amount of zero to withdraw it. The server broadcasts the
+// bet's Status whenever it changes.
+type QueuedBetData struct {
+	Bet    BetData         `json:"bet"`
+	Status QueuedBetStatus `json:"status,omitempty"`
+	Reason string          `json:"reason,omitempty"`
+}
+
+// MaxBetSlipBets is how many upcoming rounds a bet slip can cover
+const MaxBetSlipBets = 10
+
+// BetSlipData stages a bet for each of the next rounds, the first for the
+// next betting phase to open; clients send only Bets, with none to clear
+// the slip. The server broadcasts the slip with its Status whenever it
+// changes: Bets are then the bets still to come and Bet the one just
+// placed or refused. A refused bet drops the rest of the slip.
+type BetSlipData struct {
+	Bets   []BetData       `json:"bets"`
+	Bet    *BetData        `json:"bet,omitempty"`
+	Status QueuedBetStatus `json:"status,omitempty"`
+	Reason string          `json:"reason,omitempty"`
+}
+
+// RoundCancelledData announces an aborted round and the refunds issued
+type RoundCancelledData struct {
+	RoundID string         `json:"round_id"`
+	Reason  string         `json:"reason"`
+	Refunds []PlayerRefund `json:"refunds"`
+}
+
+// Reasons a room was closed, sent in RoomClosedData
+const (
+	RoomClosedEmpty     = "empty"
+	RoomClosedAbandoned = "abandoned"
+	RoomClosedByAdmin   = "closed_by_admin"
+	// RoomClosedKicked is sent only to a player an administrator removed;
+	// the room itself stays open
+	RoomClosedKicked = "kicked"
+)
+
+// RoomClosedData tells a client the room it joined no longer exists, or
+// that it was removed from it, and it is back in the lobby
+type RoomClosedData struct {
+	RoomID string `json:"room_id"`
+	Reason string `json:"reason"`
+}
+
+// PlayerRefund contains a bet returned to a player
+type PlayerRefund struct {
+	PlayerID   string     `json:"player_id"`
+	Amount     game.Money `json:"amount"`
+	NewBalance game.Money `json:"new_balance"`
+}
+
+// MaxChatLength is the longest chat message the server relays
+const MaxChatLength = 200
+
+// ChatData is a chat message sent to everyone in the room
+type ChatData struct {
+	PlayerName string `json:"player_name,omitempty"`
+	Text       string `json:"text"`
+}
+
+// MaxReplaySpeed is the fastest a recorded round can be played back
+const MaxReplaySpeed = 50.0
+
+// ReplayData asks to play back a recorded round of the room the message
+// names, or the client's own room. An empty RoundID plays the room's last
+// round. Speed is how many times faster than real time to play it, real
+// time when zero, and FromMs starts that far into the round, sending the
+// events before it at once. The server replies with the same message type
+// and the Replay it is playing, then a MsgReplayEvent for each event and a
+// MsgReplayEnd.
+type ReplayData struct {
+	RoundID string         `json:"round_id,omitempty"`
+	Speed   float64        `json:"speed,omitempty"`
+	FromMs  int64          `json:"from_ms,omitempty"`
+	Replay  *ReplaySummary `json:"replay,omitempty"`
+}
+
+// ReplaySummary describes a recorded round
+type ReplaySummary struct {
+	RoundID    string    `json:"round_id"`
+	RoomID     string    `json:"room_id"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Events     int       `json:"events"`
+}
+
+// ReplayEventData carries one event of a round being played back: the
+// message the room sent, OffsetMs into the round
+type ReplayEventData struct {
+	RoundID  string   `json:"round_id"`
+	OffsetMs int64    `json:"offset_ms"`
+	Event    *Message `json:"event"`
+}
+
+// ReplayEndData ends the playback of a round, after its last event or, with
+// Stopped, when the client stopped it with MsgStopReplay
+type ReplayEndData struct {
+	RoundID string `json:"round_id"`
+	Stopped bool   `json:"stopped,omitempty"`
 }
 
 // ErrorData contains error information
@@ -136,6 +690,12 @@ type ErrorData struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
+	// RetryAfterMs asks the client to wait before trying again, as with
+	// server_busy
+	RetryAfterMs int64 `json:"retry_after_ms,omitempty"`
+	// Rejection says why a bet was refused and what stake would fit, with
+	// bet_failed and update_bet_failed
+	Rejection *BetRejection `json:"rejection,omitempty"`
 }
 
 // NewMessage creates a new network message
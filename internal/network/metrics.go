@@ -0,0 +1,84 @@
+package network
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// metric is one sample in the Prometheus text format
+type metric struct {
+	name  string
+	help  string
+	kind  string // gauge or counter
+	value float64
+	// labels, such as state="betting", for one series of several
+	labels string
+}
+
+// sampleMetrics samples the server for the /metrics endpoint
+func (s *Server) sampleMetrics() []metric {
+	s.mu.RLock()
+	rooms := make([]*GameRoom, 0, len(s.rooms))
+	for _, room := range s.rooms {
+		rooms = append(rooms, room)
+	}
+	s.mu.RUnlock()
+
+	states := make(map[GameState]int)
+	var seated, rounds int
+	for _, room := range rooms {
+		states[room.GetGameState()]++
+		seated += len(room.GetPlayers())
+		rounds += room.RoomStats().RoundsPlayed
+	}
+	stored := s.results.Stats()
+
+	metrics := []metric{
+		{name: "coinflip_rooms", help: "Open rooms", kind: "gauge", value: float64(len(rooms))},
+	}
+	for _, state := range []GameState{StateWaiting, StateBetting, StateRevealing, StateResult, StatePaused} {
+		metrics = append(metrics, metric{
+			name: "coinflip_rooms_by_state", help: "Open rooms in each game state", kind: "gauge",
+			value: float64(states[state]), labels: fmt.Sprintf("state=%q", state),
+		})
+	}
+	return append(metrics,
+		metric{name: "coinflip_seated_players", help: "Players seated in a room", kind: "gauge", value: float64(seated)},
+		metric{name: "coinflip_room_rounds", help: "Rounds settled by the open rooms since they opened", kind: "gauge", value: float64(rounds)},
+		metric{name: "coinflip_clients", help: "Connected clients", kind: "gauge", value: float64(s.clients.len())},
+		metric{name: "coinflip_open_connections", help: "Open WebSocket connections", kind: "gauge", value: float64(s.connections.Load())},
+		metric{name: "coinflip_online_players", help: "Players with a live session", kind: "gauge", value: float64(s.sessions.Online())},
+		metric{name: "coinflip_open_liability_dollars", help: "Most the open rounds could pay out together", kind: "gauge", value: s.liability.Total().Float64()},
+		metric{name: "coinflip_stored_results", help: "Game results kept in memory", kind: "gauge", value: float64(stored.Results)},
+		metric{name: "coinflip_stored_players", help: "Players kept in memory", kind: "gauge", value: float64(stored.Players)},
+		metric{name: "coinflip_evicted_results_total", help: "Game results removed to stay within the memory cap", kind: "counter", value: float64(stored.EvictedResults)},
+		metric{name: "coinflip_evicted_players_total", help: "Players removed to stay within the memory cap", kind: "counter", value: float64(stored.EvictedPlayers)},
+	)
+}
+
+// handleMetrics serves the server's metrics in the Prometheus text format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w, s.sampleMetrics())
+}
+
+// writeMetrics writes metrics in the Prometheus text format, with the HELP
+// and TYPE lines once per name
+func writeMetrics(w io.Writer, metrics []metric) {
+	sort.SliceStable(metrics, func(i, j int) bool { return metrics[i].name < metrics[j].name })
+
+	previous := ""
+	for _, m := range metrics {
+		if m.name != previous {
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+			previous = m.name
+		}
+		if m.labels != "" {
+			fmt.Fprintf(w, "%s{%s} %g\n", m.name, m.labels, m.value)
+		} else {
+			fmt.Fprintf(w, "%s %g\n", m.name, m.value)
+		}
+	}
+}
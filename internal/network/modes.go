@@ -0,0 +1,113 @@
+package network
+
+import (
+	"coinflip-game/internal/game"
+)
+
+// RoomMode is a room's style of play, chosen when the room is created
+type RoomMode string
+
+const (
+	// ModeClassic plays plain rounds with the room's settings
+	ModeClassic RoomMode = "classic"
+	// ModeStreak pays a growing bonus for consecutive wins
+	ModeStreak RoomMode = "streak"
+	// ModeInsured offers insurance with every bet
+	ModeInsured RoomMode = "insured"
+	// ModePractice settles every bet for practice, with no money at stake
+	ModePractice RoomMode = "practice"
+	// ModeParimutuel pools every stake and splits the pool between the
+	// winners in proportion to their stakes, less the rake
+	ModeParimutuel RoomMode = "parimutuel"
+	// ModeDuel seats two players whose stakes form a pool, as in a
+	// parimutuel room, and rates them on each duel's outcome
+	ModeDuel RoomMode = "duel"
+)
+
+// Streak and insurance settings a mode brings when the room's settings do
+// not choose their own. Insurance costs a little more than it pays back.
+const (
+	StreakModeBonus         = 0.25
+	StreakModeMaxMultiplier = 3.0
+	InsuredModeCost         = 0.3
+	InsuredModeCoverage     = 0.5
+)
+
+// ParimutuelModeRake is the share of the pool the house keeps in a
+// parimutuel room whose settings do not choose their own
+const ParimutuelModeRake = 0.05
+
+// DuelSeats is how many players a duel room seats
+const DuelSeats = 2
+
+// RoomModes lists every room mode in the order clients offer them
+func RoomModes() []RoomMode {
+	return []RoomMode{ModeClassic, ModeStreak, ModeInsured, ModePractice, ModeParimutuel, ModeDuel}
+}
+
+// Valid reports whether m is a known mode. The empty mode is classic.
+func (m RoomMode) Valid() bool {
+	if m == "" {
+		return true
+	}
+	for _, mode := range RoomModes() {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Description explains the mode to players
+func (m RoomMode) Description() string {
+	switch m {
+	case ModeStreak:
+		return "Consecutive wins pay a growing bonus"
+	case ModeInsured:
+		return "Every bet can be insured against a loss"
+	case ModePractice:
+		return "Every bet is for practice; no money changes hands"
+	case ModeParimutuel:
+		return "Stakes form a pool the winners split by stake, less a rake"
+	case ModeDuel:
+		return "Two players stake against each other for rating points"
+	default:
+		return "Plain coin flips at the room's payout ratio"
+	}
+}
+
+// Pooled reports whether the mode's rounds pay only from a pool of the
+// stakes, less the rake
+func (m RoomMode) Pooled() bool {
+	return m == ModeParimutuel || m == ModeDuel
+}
+
+// applyMode switches the config to mode, filling in the streak bonus,
+// insurance or rake the mode relies on when they are not set. Pooled rooms
+// pay only from the pool, so they drop any streak bonus and insurance, and
+// a duel room seats two.
+func (c *RoomConfig) applyMode(mode RoomMode) {
+	c.Mode = mode
+	switch mode {
+	case ModeStreak:
+		if c.StreakBonus == 0 {
+			c.StreakBonus = StreakModeBonus
+			c.MaxStreakMultiplier = StreakModeMaxMultiplier
+		}
+	case ModeInsured:
+		if !c.Insurance.Enabled() {
+			c.Insurance = game.Insurance{Cost: InsuredModeCost, Coverage: InsuredModeCoverage}
+		}
+	case ModeParimutuel, ModeDuel:
+		c.StreakBonus = 0
+		c.MaxStreakMultiplier = 0
+		c.Insurance = game.Insurance{}
+		if c.Rake == 0 {
+			c.Rake = ParimutuelModeRake
+		}
+		if mode == ModeDuel {
+			c.MinPlayers = DuelSeats
+			c.MaxPlayers = DuelSeats
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package network
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+)
+
+func TestRoomConfig_Modes(t *testing.T) {
+	streak, err := DefaultRoomConfig().WithSettings(&RoomSettings{Mode: string(ModeStreak)})
+	require.NoError(t, err)
+	assert.Equal(t, StreakModeBonus, streak.StreakBonus)
+	assert.Equal(t, StreakModeMaxMultiplier, streak.MaxStreakMultiplier)
+
+	// Settings chosen alongside a mode win over its presets
+	insured, err := DefaultRoomConfig().WithSettings(&RoomSettings{Mode: string(ModeInsured), InsuranceCost: 0.4, InsuranceCoverage: 0.8})
+	require.NoError(t, err)
+	assert.Equal(t, game.Insurance{Cost: 0.4, Coverage: 0.8}, insured.Insurance)
+	insured, err = DefaultRoomConfig().WithSettings(&RoomSettings{Mode: string(ModeInsured)})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, insured.Insurance.ExpectedValue(1), 0.0, "the mode's insurance does not favour players")
+
+	// Modes and privacy survive the round trip through wire settings
+	private, err := DefaultRoomConfig().WithSettings(&RoomSettings{Mode: string(ModePractice), Private: true})
+	require.NoError(t, err)
+	restored, err := DefaultRoomConfig().WithSettings(private.Settings())
+	require.NoError(t, err)
+	assert.Equal(t, ModePractice, restored.Mode)
+	assert.True(t, restored.Private)
+
+	_, err = DefaultRoomConfig().WithSettings(&RoomSettings{Mode: "roulette"})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+}
+
+func TestGameRoom_PracticeMode(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.config.Mode = ModePractice
+
+	// Every bet is for practice, so no money leaves the balance
+	require.NoError(t, room.PlaceBet("p1", 50*game.Dollar, game.Heads))
+	player := room.GetPlayers()["p1"]
+	require.Len(t, player.CurrentBets, 1)
+	assert.True(t, player.CurrentBets[0].Practice)
+	assert.Equal(t, 100*game.Dollar, player.Balance)
+	assert.ErrorIs(t, room.PlaceInsuredBet("p1", 10*game.Dollar, game.Tails), game.ErrPracticeInsured)
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+}
+
+func TestGameRoom_ParimutuelMode(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.config.applyMode(ModeParimutuel)
+	require.NoError(t, room.config.Validate())
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p3", "Player 3", 100*game.Dollar))
+
+	require.NoError(t, room.PlaceBet("p1", 30*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p2", 10*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p3", 60*game.Dollar, game.Tails))
+	drainEvents(room)
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	var result GameResultData
+	for _, msg := range drainEvents(room) {
+		if msg.Type == MsgGameResult {
+			require.NoError(t, msg.GetData(&result))
+		}
+	}
+	require.NotNil(t, result.Pool)
+	assert.Equal(t, PoolData{Total: 100 * game.Dollar, Heads: 40 * game.Dollar, Tails: 60 * game.Dollar, Rake: 5 * game.Dollar, Paid: 95 * game.Dollar}, *result.Pool)
+
+	// The winners split the $95 left after the rake by stake
+	balances := map[string]game.Money{}
+	for id, player := range room.GetPlayers() {
+		balances[id] = player.Balance
+	}
+	if result.CoinResult == game.Heads {
+		assert.Equal(t, map[string]game.Money{"p1": 14125 * game.Cent, "p2": 11375 * game.Cent, "p3": 40 * game.Dollar}, balances)
+		require.Len(t, result.Winners, 2)
+		for _, winner := range result.Winners {
+			assert.InDelta(t, winner.Payout.Float64()/95, winner.PoolShare, 1e-9)
+		}
+	} else {
+		assert.Equal(t, map[string]game.Money{"p1": 70 * game.Dollar, "p2": 90 * game.Dollar, "p3": 135 * game.Dollar}, balances)
+		require.Len(t, result.Winners, 1)
+		assert.Equal(t, 1.0, result.Winners[0].PoolShare)
+	}
+}
+
+func TestGameRoom_ParimutuelPaysWinningSideOnly(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	room.config.applyMode(ModeParimutuel)
+
+	// A pool payout is only ever paid to a bet on the side that came up,
+	// whatever bet it is recorded under
+	room.mu.Lock()
+	defer room.mu.Unlock()
+	room.currentRound.Pool = &game.PoolSettlement{Payouts: map[string]game.Money{"b1": 50 * game.Dollar}}
+	assert.Equal(t, 50*game.Dollar, room.roundPayout(&BetData{BetID: "b1", Choice: game.Heads, Amount: 20 * game.Dollar}, game.Heads, 0))
+	assert.Zero(t, room.roundPayout(&BetData{BetID: "b1", Choice: game.Tails, Amount: 20 * game.Dollar}, game.Heads, 0))
+}
+
+func TestGameRoom_ParimutuelRefundsOneSidedPool(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.config.applyMode(ModeParimutuel)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	require.NoError(t, room.PlaceBet("p1", 30*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p2", 10*game.Dollar, game.Heads))
+	drainEvents(room)
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateWaiting, room.GetGameState())
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p2"].Balance)
+
+	cancelled := false
+	for _, msg := range drainEvents(room) {
+		cancelled = cancelled || msg.Type == MsgRoundCancelled
+	}
+	assert.True(t, cancelled)
+
+	// Pools pay only from stakes, so streak bonuses and insurance are out
+	_, err := DefaultRoomConfig().WithSettings(&RoomSettings{Mode: string(ModeParimutuel), StreakBonus: 0.5})
+	require.NoError(t, err, "the mode drops presets chosen alongside it")
+	pool, err := DefaultRoomConfig().WithSettings(&RoomSettings{Mode: string(ModeParimutuel), Rake: 0.1})
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, pool.Rake)
+	_, err = pool.WithSettings(&RoomSettings{InsuranceCost: 0.1, InsuranceCoverage: 0.5})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+	_, err = pool.WithSettings(&RoomSettings{Rake: 1})
+	assert.ErrorIs(t, err, ErrInvalidRoomConfig)
+}
+
+func TestServer_PrivateRoomsAreUnlisted(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+
+	_, err := server.CreateRoom("open", "Open", DefaultRoomConfig())
+	require.NoError(t, err)
+	config, err := server.NewRoomConfig(&RoomSettings{Private: true})
+	require.NoError(t, err)
+	_, err = server.CreateRoom("hidden", "Hidden", config)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.handleRooms(recorder, httptest.NewRequest(http.MethodGet, "/rooms", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Rooms []struct {
+			ID string `json:"id"`
+		} `json:"rooms"`
+		Total int `json:"total"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.Equal(t, 1, response.Total)
+	assert.Equal(t, "open", response.Rooms[0].ID)
+
+	// Private rooms are still joined by ID
+	room, exists := server.GetRoom("hidden")
+	require.True(t, exists)
+	assert.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+}
+
+func TestServer_CreatedRoomsWaitForPlayers(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	config := DefaultServerConfig()
+	config.Clock = fake
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+
+	_, err := server.CreateRoom("waiting", "Waiting", DefaultRoomConfig())
+	require.NoError(t, err)
+	emptied, err := server.CreateRoom("emptied", "Emptied", DefaultRoomConfig())
+	require.NoError(t, err)
+	require.NoError(t, emptied.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	_, err = emptied.removePlayer("p1")
+	require.NoError(t, err)
+
+	server.performCleanup()
+	_, exists := server.GetRoom("waiting")
+	assert.True(t, exists)
+	_, exists = server.GetRoom("emptied")
+	assert.False(t, exists)
+
+	fake.Advance(DefaultRoomTimeout + time.Minute)
+	server.performCleanup()
+	_, exists = server.GetRoom("waiting")
+	assert.False(t, exists)
+}
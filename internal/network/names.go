@@ -0,0 +1,63 @@
+package network
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// PlayerName returns the name a player is seated under, which may differ
+// from the one they joined with when another player already had it
+func (r *GameRoom) PlayerName(playerID string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	player, exists := r.players[playerID]
+	if !exists {
+		return "", false
+	}
+	return player.Name, true
+}
+
+// uniqueName returns name, or when another seated player goes by it, name
+// with the lowest " (2)", " (3)"... suffix nobody has, so the scoreboard
+// tells the players apart. Names are compared ignoring case and the space
+// around them, and the result still fits MaxPlayerNameLength. Callers must
+// hold r.mu.
+func (r *GameRoom) uniqueName(playerID, name string) string {
+	base := strings.TrimSpace(name)
+	if base == "" {
+		return name
+	}
+
+	for n := 1; ; n++ {
+		candidate := name
+		if n > 1 {
+			suffix := fmt.Sprintf(" (%d)", n)
+			candidate = truncateRunes(base, MaxPlayerNameLength-utf8.RuneCountInString(suffix)) + suffix
+		}
+		if !r.nameTaken(playerID, candidate) {
+			return candidate
+		}
+	}
+}
+
+// nameTaken reports whether a seated player other than playerID goes by
+// name. Callers must hold r.mu.
+func (r *GameRoom) nameTaken(playerID, name string) bool {
+	name = strings.TrimSpace(name)
+	for id, player := range r.players {
+		if id != playerID && strings.EqualFold(strings.TrimSpace(player.Name), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateRunes shortens s to at most n runes
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
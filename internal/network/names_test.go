@@ -0,0 +1,101 @@
+package network
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+// sentJoins returns the join echoes queued for a client
+func sentJoins(t *testing.T, client *Client) []RoomJoinData {
+	t.Helper()
+
+	var joins []RoomJoinData
+	for {
+		select {
+		case data := <-client.send:
+			msg, err := DecodeMessage(data, EncodingJSON)
+			require.NoError(t, err)
+			if msg.Type != MsgJoinRoom {
+				continue
+			}
+			var joined RoomJoinData
+			require.NoError(t, msg.GetData(&joined))
+			joins = append(joins, joined)
+		default:
+			return joins
+		}
+	}
+}
+
+func TestGameRoom_UniqueNames(t *testing.T) {
+	config := DefaultRoomConfig()
+	config.UpdateInterval = 0
+	room := NewGameRoom("room", "Room", config, nil, zaptest.NewLogger(t))
+	t.Cleanup(room.Stop)
+
+	require.NoError(t, room.AddPlayer("p1", "Alice", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p2", "alice ", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p3", "Alice", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p4", "Bob", 100*game.Dollar))
+
+	names := map[string]string{}
+	for id := range room.GetPlayers() {
+		names[id], _ = room.PlayerName(id)
+	}
+	assert.Equal(t, map[string]string{"p1": "Alice", "p2": "alice (2)", "p3": "Alice (3)", "p4": "Bob"}, names)
+
+	// A freed name goes to the next player who asks for it
+	require.NoError(t, room.RemovePlayer("p1"))
+	require.NoError(t, room.AddPlayer("p5", "Alice", 100*game.Dollar))
+	name, _ := room.PlayerName("p5")
+	assert.Equal(t, "Alice", name)
+
+	// Suffixed names still fit the length limit
+	long := strings.Repeat("x", MaxPlayerNameLength)
+	require.NoError(t, room.AddPlayer("p6", long, 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p7", long, 100*game.Dollar))
+	name, _ = room.PlayerName("p7")
+	assert.Equal(t, strings.Repeat("x", MaxPlayerNameLength-4)+" (2)", name)
+
+	_, ok := room.PlayerName("nobody")
+	assert.False(t, ok)
+}
+
+func TestServer_EchoesResolvedName(t *testing.T) {
+	server, _ := newCleanupServer(t)
+	config := DefaultRoomConfig()
+	config.UpdateInterval = 0
+	room, err := server.CreateRoom("r1", "Room 1", config)
+	require.NoError(t, err)
+
+	first := newCleanupClient(t, server, "p1")
+	first.handleJoinRoom(NewMessage(MsgJoinRoom, "r1", "p1", RoomJoinData{PlayerName: "Alice"}))
+	assert.Empty(t, sentJoins(t, first), "a free name is not echoed")
+
+	second := newCleanupClient(t, server, "p2")
+	second.handleJoinRoom(NewMessage(MsgJoinRoom, "r1", "p2", RoomJoinData{PlayerName: "Alice"}))
+	joins := sentJoins(t, second)
+	require.Len(t, joins, 1)
+	assert.Equal(t, "Alice (2)", joins[0].PlayerName)
+	assert.Equal(t, "Alice", joins[0].RequestedName)
+	assert.Equal(t, "Alice (2)", room.GetPlayers()["p2"].Name)
+
+	// Chat goes out under the seated name
+	second.handleChat(NewMessage(MsgChat, "r1", "p2", ChatData{Text: "hi"}))
+	var chat *ChatData
+	for chat == nil {
+		msg, err := DecodeMessage(<-first.send, EncodingJSON)
+		require.NoError(t, err)
+		if msg.Type == MsgChat {
+			chat = &ChatData{}
+			require.NoError(t, msg.GetData(chat))
+		}
+	}
+	assert.Equal(t, "Alice (2)", chat.PlayerName)
+}
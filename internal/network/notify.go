@@ -0,0 +1,183 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/notify"
+)
+
+// ErrNotificationsDisabled is returned when a player sets up notifications
+// on a server that sends none
+var ErrNotificationsDisabled = errors.New("this server does not send notifications")
+
+// NotifySettings returns a player's notification settings and the
+// channels the server can send on
+func (s *Server) NotifySettings(ctx context.Context, playerID string) (*NotifySettingsData, error) {
+	if playerID == "" {
+		return nil, ErrPlayerNotFound
+	}
+	return s.notifyReply(s.playerRecord(ctx, playerID)), nil
+}
+
+// SetNotifySettings replaces a player's notification settings. Only
+// registered players can set them, and only for channels the server can
+// send on. The balance the player already has does not count as a
+// milestone reached.
+func (s *Server) SetNotifySettings(ctx context.Context, playerID string, settings game.NotifySettings) (*NotifySettingsData, error) {
+	if playerID == "" {
+		return nil, ErrPlayerNotFound
+	}
+	if game.IsGuest(playerID) {
+		return nil, game.ErrNotifyGuest
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	channels := s.config.Notifier.Channels()
+	if len(channels) == 0 {
+		return nil, ErrNotificationsDisabled
+	}
+	if settings.Email != "" && !channels[notify.ChannelEmail] {
+		return nil, errors.New("this server does not send email")
+	}
+	if settings.TelegramChat != "" && !channels[notify.ChannelTelegram] {
+		return nil, errors.New("this server does not send Telegram messages")
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	player := s.playerRecord(ctx, playerID)
+	settings.MilestoneReached = 0
+	if settings.BalanceMilestone > 0 {
+		settings.MilestoneReached = player.Balance / settings.BalanceMilestone * settings.BalanceMilestone
+		if previous := player.Notify; previous != nil && previous.BalanceMilestone == settings.BalanceMilestone {
+			settings.MilestoneReached = max(settings.MilestoneReached, previous.MilestoneReached)
+		}
+	}
+	player.Notify = &settings
+	if err := s.results.SavePlayer(ctx, player); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Notification settings changed",
+		zap.String("player_id", playerID),
+		zap.Bool("enabled", settings.Enabled),
+		zap.Bool("email", settings.Email != ""),
+		zap.Bool("telegram", settings.TelegramChat != ""),
+	)
+	return s.notifyReply(player), nil
+}
+
+// notifyReply reports a player's notification settings with the channels
+// the server can send on
+func (s *Server) notifyReply(player *game.Player) *NotifySettingsData {
+	reply := &NotifySettingsData{Settings: player.Notify.Clone()}
+	if reply.Settings == nil {
+		reply.Settings = &game.NotifySettings{}
+	}
+	for channel := range s.config.Notifier.Channels() {
+		reply.Channels = append(reply.Channels, string(channel))
+	}
+	sort.Strings(reply.Channels)
+	return reply
+}
+
+// notifyOutcome sends the notifications a settled round earns the player:
+// a big win, or a new balance milestone. The milestone is recorded on the
+// player, who the caller saves.
+func (s *Server) notifyOutcome(player *game.Player, coinResult game.Side, outcome PlayerResult) {
+	settings := player.Notify
+	if settings == nil {
+		return
+	}
+
+	profit := outcome.Payout + outcome.Insurance - outcome.Wagered
+	if outcome.Won && settings.WantsWin(profit) {
+		s.sendNotification(player.ID, settings, notify.Message{
+			Subject: fmt.Sprintf("🎉 You won %s", profit.Format()),
+			Text: fmt.Sprintf("Your bet on %s won %s, leaving your balance at %s.",
+				coinResult, profit.Format(), outcome.NewBalance.Format()),
+		})
+	}
+	if milestone, reached := settings.ReachMilestone(outcome.NewBalance); reached {
+		s.sendNotification(player.ID, settings, notify.Message{
+			Subject: fmt.Sprintf("💰 Your balance passed %s", milestone.Format()),
+			Text:    fmt.Sprintf("Your balance is now %s.", outcome.NewBalance.Format()),
+		})
+	}
+}
+
+// notifySeason tells the players who asked for season results how they
+// finished
+func (s *Server) notifySeason(ctx context.Context, season *game.Season, badges map[string]game.SeasonBadge) {
+	if s.config.Notifier == nil {
+		return
+	}
+	for _, standing := range season.Standings {
+		player, err := s.results.GetPlayer(ctx, standing.PlayerID)
+		if err != nil || player.Notify == nil || !player.Notify.Enabled || !player.Notify.SeasonResults {
+			continue
+		}
+
+		text := fmt.Sprintf("You finished #%d of %d, winning %d of %d rounds for %s net, with a best streak of %d.",
+			standing.Rank, len(season.Standings), standing.Wins, standing.Rounds,
+			standing.Net.FormatSigned(), standing.BestStreak)
+		if badge, earned := badges[standing.PlayerID]; earned {
+			text += fmt.Sprintf(" You earned the %s badge.", badge.Name())
+		}
+		s.sendNotification(player.ID, player.Notify, notify.Message{
+			Subject: fmt.Sprintf("🏆 Season %s results", season.ID),
+			Text:    text,
+		})
+	}
+}
+
+// sendNotification queues a message to every address the player set up
+func (s *Server) sendNotification(playerID string, settings *game.NotifySettings, message notify.Message) {
+	queued := s.config.Notifier.Notify(notify.ChannelEmail, settings.Email, message)
+	queued = s.config.Notifier.Notify(notify.ChannelTelegram, settings.TelegramChat, message) || queued
+	if queued {
+		s.logger.Debug("Notification queued",
+			zap.String("player_id", playerID),
+			zap.String("subject", message.Subject),
+		)
+	}
+}
+
+// handleNotifySettings reports or replaces the client's notification
+// settings
+func (c *Client) handleNotifySettings(msg *Message) {
+	var data NotifySettingsData
+	if err := msg.GetData(&data); err != nil {
+		c.sendError("invalid_data", "Invalid notification settings")
+		return
+	}
+
+	playerID, ok := c.identified("notify_failed")
+	if !ok {
+		return
+	}
+
+	var (
+		reply *NotifySettingsData
+		err   error
+	)
+	if data.Settings != nil {
+		reply, err = c.server.SetNotifySettings(c.server.ctx, playerID, *data.Settings)
+	} else {
+		reply, err = c.server.NotifySettings(c.server.ctx, playerID)
+	}
+	if err != nil {
+		c.sendError("notify_failed", err.Error())
+		return
+	}
+
+	c.sendMessage(NewMessage(MsgNotifySettings, msg.RoomID, playerID, reply))
+}
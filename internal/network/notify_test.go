@@ -0,0 +1,177 @@
+package network
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/notify"
+)
+
+// notifyOutbox keeps the notifications a server sends, by address
+type notifyOutbox struct {
+	mu   sync.Mutex
+	sent map[string][]notify.Message
+}
+
+func (o *notifyOutbox) Send(ctx context.Context, to string, message notify.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.sent[to] = append(o.sent[to], message)
+	return nil
+}
+
+// newNotifyServer returns a server sending email to outbox. Closing the
+// notifier waits for what it queued.
+func newNotifyServer(t *testing.T) (*Server, *notify.Notifier, *notifyOutbox, *clock.Fake) {
+	outbox := &notifyOutbox{sent: make(map[string][]notify.Message)}
+	notifier := notify.NewWithSenders(map[notify.Channel]notify.Sender{notify.ChannelEmail: outbox}, 0, 0, zaptest.NewLogger(t))
+	t.Cleanup(func() { notifier.Close() })
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	config := DefaultServerConfig()
+	config.Clock = fake
+	config.Notifier = notifier
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	return server, notifier, outbox, fake
+}
+
+func TestServer_SetNotifySettings(t *testing.T) {
+	ctx := context.Background()
+	email := game.NotifySettings{Enabled: true, Email: "alice@example.com", WinAbove: 50 * game.Dollar}
+
+	plain := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(plain.Stop)
+	_, err := plain.SetNotifySettings(ctx, "alice", email)
+	assert.ErrorIs(t, err, ErrNotificationsDisabled)
+	reply, err := plain.NotifySettings(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, reply.Channels)
+
+	server, _, _, _ := newNotifyServer(t)
+	_, err = server.SetNotifySettings(ctx, "guest_1234", email)
+	assert.ErrorIs(t, err, game.ErrNotifyGuest)
+	_, err = server.SetNotifySettings(ctx, "alice", game.NotifySettings{Enabled: true, TelegramChat: "12345", SeasonResults: true})
+	assert.ErrorContains(t, err, "Telegram")
+	_, err = server.SetNotifySettings(ctx, "alice", game.NotifySettings{Enabled: true, Email: "alice@example.com"})
+	assert.ErrorIs(t, err, game.ErrNotifyTrigger)
+
+	// The balance the player already has is not a milestone reached
+	require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: "alice", Balance: 2500 * game.Dollar}))
+	email.BalanceMilestone = 1000 * game.Dollar
+	email.MilestoneReached = 9000 * game.Dollar
+	reply, err = server.SetNotifySettings(ctx, "alice", email)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email"}, reply.Channels)
+	assert.Equal(t, 2000*game.Dollar, reply.Settings.MilestoneReached)
+
+	// Opting out keeps the settings with the account
+	email.Enabled = false
+	_, err = server.SetNotifySettings(ctx, "alice", email)
+	require.NoError(t, err)
+	reply, err = server.NotifySettings(ctx, "alice")
+	require.NoError(t, err)
+	assert.False(t, reply.Settings.Enabled)
+	assert.Equal(t, "alice@example.com", reply.Settings.Email)
+}
+
+func TestClient_NotifySettingsAreTheConnectionsOwn(t *testing.T) {
+	server, _, _, _ := newNotifyServer(t)
+	ctx := context.Background()
+	_, err := server.SetNotifySettings(ctx, "alice", game.NotifySettings{Enabled: true, Email: "alice@example.com", WinAbove: game.Dollar})
+	require.NoError(t, err)
+
+	// A second player naming Alice on the message reads and changes
+	// their own settings, never hers
+	mallory := newCleanupClient(t, server, "mallory")
+	mallory.handleNotifySettings(NewMessage(MsgNotifySettings, "", "alice", NotifySettingsData{}))
+	mallory.handleNotifySettings(NewMessage(MsgNotifySettings, "", "alice", NotifySettingsData{
+		Settings: &game.NotifySettings{Enabled: true, Email: "mallory@example.com", WinAbove: game.Dollar},
+	}))
+	var replies []NotifySettingsData
+	for len(mallory.send) > 0 {
+		msg, err := DecodeMessage(<-mallory.send, EncodingJSON)
+		require.NoError(t, err)
+		require.Equal(t, MsgNotifySettings, msg.Type)
+		assert.Equal(t, "mallory", msg.PlayerID)
+		var reply NotifySettingsData
+		require.NoError(t, msg.GetData(&reply))
+		replies = append(replies, reply)
+	}
+	require.Len(t, replies, 2)
+	assert.Empty(t, replies[0].Settings.Email)
+	assert.Equal(t, "mallory@example.com", replies[1].Settings.Email)
+
+	// A connection acting for no one is refused
+	stranger := newCleanupClient(t, server, "")
+	stranger.handleNotifySettings(NewMessage(MsgNotifySettings, "", "alice", NotifySettingsData{}))
+	assert.Equal(t, []ErrorData{{Code: "notify_failed", Message: ErrNotIdentified.Error()}}, sentErrors(t, stranger))
+
+	reply, err := server.NotifySettings(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", reply.Settings.Email)
+}
+
+func TestServer_NotifiesWinsAndMilestones(t *testing.T) {
+	ctx := context.Background()
+	server, notifier, outbox, _ := newNotifyServer(t)
+	require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: "alice", Balance: 900 * game.Dollar}))
+	_, err := server.SetNotifySettings(ctx, "alice", game.NotifySettings{
+		Enabled: true, Email: "alice@example.com", WinAbove: 50 * game.Dollar, BalanceMilestone: 1000 * game.Dollar,
+	})
+	require.NoError(t, err)
+
+	round := func(wagered, payout, balance game.Money) {
+		server.recordResults(ctx, &GameResultData{RoundID: "round", CoinResult: game.Heads, Winners: []PlayerResult{{
+			PlayerID: "alice", PlayerName: "alice", Won: true,
+			Bets:    []*BetData{{Amount: wagered, Choice: game.Heads}},
+			Wagered: wagered, Payout: payout, NewBalance: balance,
+		}}})
+	}
+	round(10*game.Dollar, 20*game.Dollar, 910*game.Dollar)
+	round(100*game.Dollar, 200*game.Dollar, 1010*game.Dollar)
+	round(10*game.Dollar, 20*game.Dollar, 1020*game.Dollar)
+	require.NoError(t, notifier.Close())
+
+	sent := outbox.sent["alice@example.com"]
+	require.Len(t, sent, 2, "one big win and one milestone")
+	assert.Equal(t, "🎉 You won $100.00", sent[0].Subject)
+	assert.Contains(t, sent[0].Text, "heads")
+	assert.Equal(t, "💰 Your balance passed $1000.00", sent[1].Subject)
+
+	player, err := server.results.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1000*game.Dollar, player.Notify.MilestoneReached)
+}
+
+func TestServer_NotifiesSeasonResults(t *testing.T) {
+	ctx := context.Background()
+	server, notifier, outbox, fake := newNotifyServer(t)
+	for _, playerID := range []string{"alice", "bob"} {
+		require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: playerID}))
+		_, err := server.SetNotifySettings(ctx, playerID, game.NotifySettings{
+			Enabled: true, Email: playerID + "@example.com", SeasonResults: playerID == "alice", WinAbove: 1000 * game.Dollar,
+		})
+		require.NoError(t, err)
+	}
+
+	settleRound(server, []string{"alice"}, []string{"bob"})
+	fake.Advance(7 * 24 * time.Hour)
+	server.rolloverSeason(ctx)
+	require.NoError(t, notifier.Close())
+
+	sent := outbox.sent["alice@example.com"]
+	require.Len(t, sent, 1)
+	assert.Equal(t, "🏆 Season 2024-W01 results", sent[0].Subject)
+	assert.Contains(t, sent[0].Text, "#1 of 2")
+	assert.Contains(t, sent[0].Text, "Gold badge")
+	assert.Empty(t, outbox.sent["bob@example.com"], "bob did not ask for season results")
+}
@@ -0,0 +1,261 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrPauseVoteOpen = errors.New("a pause vote is already open")
+	ErrNoPauseVote   = errors.New("no matching pause vote")
+	ErrRoomPaused    = errors.New("room is already paused")
+	ErrRoomNotPaused = errors.New("room is not paused")
+)
+
+// pauseProposal is a request to pause or resume the room awaiting a
+// majority of players, or the owner's say
+type pauseProposal struct {
+	id          string
+	action      PauseAction
+	requesterID string
+	votes       map[string]bool
+}
+
+// ProposePause asks the room to pause or resume. The requester's vote
+// counts in favour, and the room owner's vote decides on its own, so the
+// owner and a player alone in the room pause or resume at once.
+func (r *GameRoom) ProposePause(playerID string, action PauseAction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.players[playerID]; !exists {
+		return ErrPlayerNotFound
+	}
+	if r.pauseVote != nil {
+		return ErrPauseVoteOpen
+	}
+
+	switch action {
+	case PauseActionPause:
+		if r.gameState == StatePaused || r.pausePending != "" {
+			return ErrRoomPaused
+		}
+	case PauseActionResume:
+		if r.gameState != StatePaused && r.pausePending == "" {
+			return ErrRoomNotPaused
+		}
+	default:
+		return fmt.Errorf("unknown pause action %q", action)
+	}
+
+	r.pauseVote = &pauseProposal{
+		id:          fmt.Sprintf("pause_%s_%d", r.id, r.clock.Now().UnixNano()),
+		action:      action,
+		requesterID: playerID,
+		votes:       map[string]bool{playerID: true},
+	}
+	r.lastActivity = r.clock.Now()
+
+	r.logger.Info("Room pause proposed",
+		zap.String("room_id", r.id),
+		zap.String("proposal_id", r.pauseVote.id),
+		zap.String("player_id", playerID),
+		zap.String("action", string(action)),
+	)
+
+	r.broadcastMessage(NewMessage(MsgPauseProposal, r.id, playerID, r.pauseData(ProposalOpen, "")))
+	r.tallyPause()
+	return nil
+}
+
+// VotePause records a player's vote on the open pause proposal. Players
+// may change their vote until it is decided.
+func (r *GameRoom) VotePause(playerID, proposalID string, approve bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.players[playerID]; !exists {
+		return ErrPlayerNotFound
+	}
+	if r.pauseVote == nil || r.pauseVote.id != proposalID {
+		return ErrNoPauseVote
+	}
+
+	r.pauseVote.votes[playerID] = approve
+	r.lastActivity = r.clock.Now()
+
+	r.tallyPause()
+	return nil
+}
+
+// tallyPause decides the open pause proposal once the owner has voted, a
+// majority of the players in the room agrees or it can no longer pass,
+// and otherwise broadcasts the running tally. Callers must hold r.mu.
+func (r *GameRoom) tallyPause() {
+	proposal := r.pauseVote
+	if proposal == nil {
+		return
+	}
+
+	yes, no := r.countPauseVotes()
+	eligible := len(r.players)
+
+	status := ProposalOpen
+	decidedBy := ""
+	approve, ownerVoted := proposal.votes[r.owner]
+	if _, seated := r.players[r.owner]; !seated {
+		ownerVoted = false
+	}
+	switch {
+	case eligible == 0:
+		r.pauseVote = nil
+		return
+	case ownerVoted && approve:
+		status, decidedBy = ProposalPassed, r.owner
+	case ownerVoted:
+		status, decidedBy = ProposalRejected, r.owner
+	case yes*2 > eligible:
+		status = ProposalPassed
+	case no*2 >= eligible:
+		status = ProposalRejected
+	}
+
+	r.broadcastMessage(NewMessage(MsgPauseVote, r.id, "", r.pauseData(status, decidedBy)))
+	if status == ProposalOpen {
+		return
+	}
+
+	r.pauseVote = nil
+	r.logger.Info("Room pause vote decided",
+		zap.String("room_id", r.id),
+		zap.String("proposal_id", proposal.id),
+		zap.String("action", string(proposal.action)),
+		zap.String("status", string(status)),
+		zap.Int("yes", yes),
+		zap.Int("no", no),
+	)
+
+	if status != ProposalPassed {
+		return
+	}
+	if proposal.action == PauseActionResume {
+		r.resumeGame()
+		return
+	}
+
+	// The flip is already decided once betting closes, so a pause agreed
+	// while the result is showing holds the room once the round is over
+	if r.gameState == StateRevealing || r.gameState == StateResult {
+		r.pausePending = proposal.requesterID
+		return
+	}
+	r.pauseGame(proposal.requesterID)
+}
+
+// countPauseVotes counts the pause votes of players still in the room
+func (r *GameRoom) countPauseVotes() (yes, no int) {
+	for playerID, approve := range r.pauseVote.votes {
+		if _, exists := r.players[playerID]; !exists {
+			continue
+		}
+		if approve {
+			yes++
+		} else {
+			no++
+		}
+	}
+	return yes, no
+}
+
+// pauseData describes the open pause proposal for broadcasting. Callers
+// must hold r.mu.
+func (r *GameRoom) pauseData(status ProposalStatus, decidedBy string) *PauseProposalData {
+	yes, no := r.countPauseVotes()
+	return &PauseProposalData{
+		ProposalID:  r.pauseVote.id,
+		Action:      r.pauseVote.action,
+		RequesterID: r.pauseVote.requesterID,
+		Status:      status,
+		Yes:         yes,
+		No:          no,
+		Eligible:    len(r.players),
+		DecidedBy:   decidedBy,
+	}
+}
+
+// pauseGame freezes the room: the phase timer stops with the betting time
+// left kept for the resume, and no bets are taken. pausedBy is the player
+// whose request paused it, empty when the room ran short of players.
+// Callers must hold r.mu.
+func (r *GameRoom) pauseGame(pausedBy string) {
+	r.scheduler.Cancel(r.id)
+
+	r.pausedPhase = r.gameState
+	r.pausedRemaining = 0
+	if r.gameState == StateBetting {
+		r.pausedRemaining = max(r.timerEnd.Sub(r.clock.Now()), 0)
+	}
+	r.pausedBy = pausedBy
+	r.pausePending = ""
+	r.gameState = StatePaused
+
+	r.logger.Info("Game paused",
+		zap.String("room_id", r.id),
+		zap.String("paused_by", pausedBy),
+		zap.Duration("betting_left", r.pausedRemaining),
+	)
+	r.broadcastRoomUpdate()
+}
+
+// resumeGame picks the room up where it paused. Betting reopens with the
+// time that was left; a room still short of players stays paused until
+// enough join. Callers must hold r.mu.
+func (r *GameRoom) resumeGame() {
+	if r.pausePending != "" {
+		r.pausePending = ""
+		return
+	}
+	if r.gameState != StatePaused {
+		return
+	}
+	if r.activePlayers() < r.config.MinPlayers {
+		r.pausedBy = ""
+		r.broadcastRoomUpdate()
+		return
+	}
+
+	phase := r.pausedPhase
+	remaining := r.pausedRemaining
+	r.pausedBy = ""
+	r.pausedPhase = ""
+	r.pausedRemaining = 0
+
+	r.logger.Info("Game resumed",
+		zap.String("room_id", r.id),
+		zap.Duration("betting_left", remaining),
+	)
+
+	if phase != StateBetting || r.currentRound == nil {
+		r.gameState = StateWaiting
+		r.broadcastRoomUpdate()
+		r.checkAndStartGame()
+		return
+	}
+
+	r.gameState = StateBetting
+	r.timerEnd = r.clock.Now().Add(remaining)
+	r.scheduleBettingEnd(r.timerEnd.Add(r.bettingGrace))
+
+	r.broadcastMessage(NewMessage(MsgBetPhase, r.id, "", r.bettingTimer(remaining)))
+	r.placeQueuedBets()
+	r.broadcastRoomUpdate()
+}
+
+// Owner returns the player who opened the room
+func (r *GameRoom) Owner() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.owner
+}
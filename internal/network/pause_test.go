@@ -0,0 +1,149 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+// lastPauseVote returns the most recent pause proposal broadcast among
+// messages
+func lastPauseVote(t *testing.T, messages []*Message) *PauseProposalData {
+	t.Helper()
+
+	var proposal *PauseProposalData
+	for _, msg := range messages {
+		if data, ok := msg.Data.(*PauseProposalData); ok {
+			proposal = data
+		}
+	}
+	require.NotNil(t, proposal, "no pause proposal broadcast")
+	return proposal
+}
+
+func TestGameRoom_PauseByMajorityFreezesBetting(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p3", "Player 3", 100*game.Dollar))
+	require.NoError(t, room.PlaceBet("p2", 10*game.Dollar, game.Heads))
+	drainEvents(room)
+
+	fake.Advance(4 * time.Second)
+	scheduler.advance(fake.Now())
+
+	require.NoError(t, room.ProposePause("p2", PauseActionPause))
+	assert.ErrorIs(t, room.ProposePause("p3", PauseActionPause), ErrPauseVoteOpen)
+	proposal := lastPauseVote(t, drainEvents(room))
+	assert.Equal(t, ProposalOpen, proposal.Status)
+	assert.Equal(t, "p2", proposal.RequesterID)
+	assert.Equal(t, 1, proposal.Yes)
+
+	assert.ErrorIs(t, room.VotePause("p3", "unknown", true), ErrNoPauseVote)
+	require.NoError(t, room.VotePause("p3", proposal.ProposalID, true))
+	assert.Equal(t, StatePaused, room.GetGameState())
+
+	updates := roomUpdates(room)
+	require.NotEmpty(t, updates)
+	paused := updates[len(updates)-1]
+	assert.Equal(t, "p2", paused.PausedBy)
+	assert.Equal(t, "p1", paused.Owner)
+	assert.Equal(t, 6, paused.Timer)
+
+	// Time passes without the round moving on or taking bets
+	fake.Advance(time.Minute)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StatePaused, room.GetGameState())
+	assert.ErrorIs(t, room.PlaceBet("p3", 10*game.Dollar, game.Tails), ErrInvalidGamePhase)
+
+	// Betting resumes with the six seconds it had left
+	require.NoError(t, room.ProposePause("p3", PauseActionResume))
+	proposal = lastPauseVote(t, drainEvents(room))
+	require.NoError(t, room.VotePause("p2", proposal.ProposalID, true))
+	assert.Equal(t, StateBetting, room.GetGameState())
+	require.NoError(t, room.PlaceBet("p3", 10*game.Dollar, game.Tails))
+
+	fake.Advance(5 * time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateBetting, room.GetGameState())
+	fake.Advance(time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateResult, room.GetGameState())
+}
+
+func TestGameRoom_OwnerDecidesPause(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p3", "Player 3", 100*game.Dollar))
+	assert.Equal(t, "p1", room.Owner())
+
+	// The owner's vote settles a request on its own
+	require.NoError(t, room.ProposePause("p2", PauseActionPause))
+	proposal := lastPauseVote(t, drainEvents(room))
+	require.NoError(t, room.VotePause("p1", proposal.ProposalID, false))
+	decided := lastPauseVote(t, drainEvents(room))
+	assert.Equal(t, ProposalRejected, decided.Status)
+	assert.Equal(t, "p1", decided.DecidedBy)
+	assert.Equal(t, StateBetting, room.GetGameState())
+
+	require.NoError(t, room.ProposePause("p1", PauseActionPause))
+	assert.Equal(t, StatePaused, room.GetGameState())
+	assert.ErrorIs(t, room.ProposePause("p2", PauseActionPause), ErrRoomPaused)
+
+	require.NoError(t, room.ProposePause("p1", PauseActionResume))
+	assert.Equal(t, StateBetting, room.GetGameState())
+	assert.ErrorIs(t, room.ProposePause("p2", PauseActionResume), ErrRoomNotPaused)
+}
+
+func TestGameRoom_PauseAgreedDuringResultWaitsForRoundEnd(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	require.NoError(t, room.ProposePause("p1", PauseActionPause))
+	assert.Equal(t, StateResult, room.GetGameState())
+
+	fake.Advance(ResultPhaseDuration)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StatePaused, room.GetGameState())
+
+	// No new round starts until the room resumes
+	fake.Advance(time.Minute)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StatePaused, room.GetGameState())
+
+	require.NoError(t, room.ProposePause("p1", PauseActionResume))
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateBetting, room.GetGameState())
+}
+
+func TestGameRoom_ResumesWhenPlayersReturn(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.SetRandomGenerator(&fixedCoin{side: game.Heads, seed: "seed"})
+	room.config.MinPlayers = 2
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+
+	fake.Advance(3 * time.Second)
+	scheduler.advance(fake.Now())
+	require.NoError(t, room.RemovePlayer("p2"))
+	assert.Equal(t, StatePaused, room.GetGameState())
+
+	fake.Advance(time.Minute)
+	scheduler.advance(fake.Now())
+	require.NoError(t, room.AddPlayer("p3", "Player 3", 100*game.Dollar))
+	assert.Equal(t, StateBetting, room.GetGameState())
+	assert.Equal(t, 90*game.Dollar, room.GetPlayers()["p1"].Balance)
+
+	// The bet placed before the pause is settled when betting closes
+	fake.Advance(7 * time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateResult, room.GetGameState())
+	assert.Equal(t, 110*game.Dollar, room.GetPlayers()["p1"].Balance)
+}
@@ -0,0 +1,321 @@
+package network
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+	"coinflip-game/internal/storage"
+)
+
+// Promo code errors
+var (
+	ErrPromoNotFound  = errors.New("promo code not found")
+	ErrPromoExpired   = errors.New("promo code has expired")
+	ErrPromoExhausted = errors.New("promo code has no uses left")
+	ErrPromoRedeemed  = errors.New("promo code already redeemed by this player")
+	ErrPromoExists    = errors.New("promo code already exists")
+	ErrInvalidPromo   = errors.New("invalid promo code")
+)
+
+// promoCodeLength is the length of generated promo codes
+const promoCodeLength = 10
+
+// PromoSpec describes a promo code to create. An empty Code generates a
+// random one, and a zero ExpiresAt never expires.
+type PromoSpec struct {
+	Code      string     `json:"code,omitempty"`
+	Value     game.Money `json:"value"`
+	MaxUses   int        `json:"max_uses"`
+	ExpiresAt time.Time  `json:"expires_at,omitempty"`
+}
+
+// PromoCode is an admin-issued code that credits a fixed value to each
+// player redeeming it, up to MaxUses players in total
+type PromoCode struct {
+	Code      string     `json:"code"`
+	Value     game.Money `json:"value"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt time.Time  `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Redeemers []string   `json:"redeemers,omitempty"`
+}
+
+// Expired reports whether the code can no longer be redeemed at now
+func (p *PromoCode) Expired(now time.Time) bool {
+	return !p.ExpiresAt.IsZero() && !now.Before(p.ExpiresAt)
+}
+
+// redeemedBy reports whether a player has already used the code
+func (p *PromoCode) redeemedBy(playerID string) bool {
+	for _, id := range p.Redeemers {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// PromoBook issues and redeems the server's promo codes, keeping them in a
+// storage.PromoRepository
+type PromoBook struct {
+	// mu serializes changes, so a code's uses are checked and counted as
+	// one step
+	mu    sync.Mutex
+	repo  storage.PromoRepository
+	clock clock.Clock
+}
+
+// NewPromoBook creates a promo book keeping its codes in repo. A nil repo
+// keeps them in memory, and a nil clock uses system time.
+func NewPromoBook(repo storage.PromoRepository, clk clock.Clock) *PromoBook {
+	if repo == nil {
+		repo = storage.NewMemoryPromoRepository()
+	}
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &PromoBook{repo: repo, clock: clk}
+}
+
+// normalizePromoCode makes codes case-insensitive
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Create adds a new promo code
+func (b *PromoBook) Create(ctx context.Context, spec PromoSpec) (*PromoCode, error) {
+	if spec.Value <= 0 {
+		return nil, fmt.Errorf("%w: value must be positive", ErrInvalidPromo)
+	}
+	if spec.MaxUses <= 0 {
+		return nil, fmt.Errorf("%w: max uses must be positive", ErrInvalidPromo)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	if !spec.ExpiresAt.IsZero() && !spec.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expiry is in the past", ErrInvalidPromo)
+	}
+
+	code := normalizePromoCode(spec.Code)
+	if code == "" {
+		generated, err := generatePromoCode()
+		if err != nil {
+			return nil, err
+		}
+		code = generated
+	}
+	_, err := b.repo.GetPromo(ctx, code)
+	if err == nil {
+		return nil, ErrPromoExists
+	}
+	if !errors.Is(err, storage.ErrPromoNotFound) {
+		return nil, err
+	}
+
+	promo := &PromoCode{
+		Code:      code,
+		Value:     spec.Value,
+		MaxUses:   spec.MaxUses,
+		ExpiresAt: spec.ExpiresAt,
+		CreatedAt: now,
+	}
+	if err := b.repo.SavePromo(ctx, (*storage.PromoRecord)(promo)); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+// Redeemable returns the code if playerID may redeem it now, without using
+// it. Each player may redeem a code once.
+func (b *PromoBook) Redeemable(ctx context.Context, code, playerID string) (*PromoCode, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.redeemable(ctx, code, playerID)
+}
+
+// redeemable is Redeemable for callers holding b.mu
+func (b *PromoBook) redeemable(ctx context.Context, code, playerID string) (*PromoCode, error) {
+	record, err := b.repo.GetPromo(ctx, normalizePromoCode(code))
+	if errors.Is(err, storage.ErrPromoNotFound) {
+		return nil, ErrPromoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	promo := (*PromoCode)(record)
+	if promo.Expired(b.clock.Now()) {
+		return nil, ErrPromoExpired
+	}
+	if promo.redeemedBy(playerID) {
+		return nil, ErrPromoRedeemed
+	}
+	if promo.Uses >= promo.MaxUses {
+		return nil, ErrPromoExhausted
+	}
+	return promo, nil
+}
+
+// Redeem uses one of a code's remaining uses for playerID and returns the
+// code as redeemed
+func (b *PromoBook) Redeem(ctx context.Context, code, playerID string) (*PromoCode, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	promo, err := b.redeemable(ctx, code, playerID)
+	if err != nil {
+		return nil, err
+	}
+	promo.Uses++
+	promo.Redeemers = append(promo.Redeemers, playerID)
+	if err := b.repo.SavePromo(ctx, (*storage.PromoRecord)(promo)); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+// List returns all promo codes in the order they were created
+func (b *PromoBook) List(ctx context.Context) ([]PromoCode, error) {
+	records, err := b.repo.GetPromos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]PromoCode, 0, len(records))
+	for _, record := range records {
+		codes = append(codes, PromoCode(*record))
+	}
+	return codes, nil
+}
+
+// generatePromoCode returns a random code that is easy to type
+func generatePromoCode() (string, error) {
+	buf := make([]byte, promoCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate promo code: %w", err)
+	}
+	return base32.StdEncoding.EncodeToString(buf)[:promoCodeLength], nil
+}
+
+// Credit adds funds to a player's balance outside of a round, recording
+// the reason in the audit trail
+func (r *GameRoom) Credit(playerID string, amount game.Money, reason string) (game.Money, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.players[playerID]
+	if !exists {
+		return 0, ErrPlayerNotFound
+	}
+
+	player.Balance += amount
+	r.lastActivity = r.clock.Now()
+
+	r.audit.Record(logger.AuditEvent{
+		Time:     r.clock.Now(),
+		Event:    logger.AuditCredit,
+		PlayerID: playerID,
+		RoomID:   r.id,
+		Amount:   amount.Float64(),
+		Balance:  player.Balance.Float64(),
+		Reason:   reason,
+	})
+
+	r.broadcastRoomUpdate()
+	return player.Balance, nil
+}
+
+// RedeemPromo redeems a promo code for a player and credits its value to
+// their bankroll: the balance of the room they are playing in, or
+// otherwise the balance the server keeps on record for them. A freeroll's
+// stack is not the bankroll, so the value goes on record. The redemption
+// only uses up the code once the value is credited.
+func (s *Server) RedeemPromo(ctx context.Context, playerID string, room *GameRoom, code string) (*RedeemCodeData, error) {
+	if playerID == "" {
+		return nil, ErrPlayerNotFound
+	}
+	room = bankrollRoom(room)
+
+	// Serialized with the other changes to players' records, so a player
+	// redeeming twice at once is credited once
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	promo, err := s.promos.Redeemable(ctx, code, playerID)
+	if err != nil {
+		return nil, err
+	}
+	reason := "promo " + promo.Code
+
+	player := s.playerRecord(ctx, playerID)
+
+	if room != nil {
+		balance, err := room.Credit(playerID, promo.Value, reason)
+		if err != nil {
+			return nil, err
+		}
+		player.Balance = balance
+		if err := s.results.SavePlayer(ctx, player); err != nil {
+			s.logger.Error("Failed to record promo credit",
+				zap.String("player_id", playerID),
+				zap.Error(err),
+			)
+		}
+	} else {
+		player.Balance += promo.Value
+		if err := s.results.SavePlayer(ctx, player); err != nil {
+			return nil, err
+		}
+		s.config.Audit.Record(logger.AuditEvent{
+			Time:     s.scheduler.Clock().Now(),
+			Event:    logger.AuditCredit,
+			PlayerID: playerID,
+			Amount:   promo.Value.Float64(),
+			Balance:  player.Balance.Float64(),
+			Reason:   reason,
+		})
+	}
+
+	redeemed, err := s.promos.Redeem(ctx, promo.Code, playerID)
+	if err != nil {
+		// The player has been credited, so the code is not refused now
+		s.logger.Error("Failed to record promo redemption",
+			zap.String("player_id", playerID),
+			zap.String("code", promo.Code),
+			zap.Error(err),
+		)
+		redeemed = promo
+	}
+
+	s.logger.Info("Promo code redeemed",
+		zap.String("player_id", playerID),
+		zap.String("code", redeemed.Code),
+		zap.Float64("value", redeemed.Value.Float64()),
+		zap.Int("uses", redeemed.Uses),
+	)
+
+	return &RedeemCodeData{
+		Code:    redeemed.Code,
+		Amount:  redeemed.Value,
+		Balance: player.Balance,
+	}, nil
+}
+
+// Promos returns the server's promo codes
+func (s *Server) Promos() *PromoBook {
+	return s.promos
+}
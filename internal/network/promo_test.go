@@ -0,0 +1,136 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+func TestPromoBook_RedeemLimits(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	book := NewPromoBook(nil, fake)
+	ctx := context.Background()
+
+	_, err := book.Create(ctx, PromoSpec{Value: game.Money(0), MaxUses: 1})
+	assert.ErrorIs(t, err, ErrInvalidPromo)
+	_, err = book.Create(ctx, PromoSpec{Value: 10 * game.Dollar, MaxUses: 1, ExpiresAt: fake.Now()})
+	assert.ErrorIs(t, err, ErrInvalidPromo)
+
+	promo, err := book.Create(ctx, PromoSpec{Code: "welcome", Value: 25 * game.Dollar, MaxUses: 2, ExpiresAt: fake.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, "WELCOME", promo.Code)
+	_, err = book.Create(ctx, PromoSpec{Code: "WELCOME", Value: 5 * game.Dollar, MaxUses: 1})
+	assert.ErrorIs(t, err, ErrPromoExists)
+
+	_, err = book.Redeem(ctx, "nope", "p1")
+	assert.ErrorIs(t, err, ErrPromoNotFound)
+
+	redeemed, err := book.Redeem(ctx, "Welcome", "p1")
+	require.NoError(t, err)
+	assert.Equal(t, 25*game.Dollar, redeemed.Value)
+	assert.Equal(t, 1, redeemed.Uses)
+
+	_, err = book.Redeem(ctx, "WELCOME", "p1")
+	assert.ErrorIs(t, err, ErrPromoRedeemed)
+
+	_, err = book.Redeem(ctx, "WELCOME", "p2")
+	require.NoError(t, err)
+	_, err = book.Redeem(ctx, "WELCOME", "p3")
+	assert.ErrorIs(t, err, ErrPromoExhausted)
+
+	generated, err := book.Create(ctx, PromoSpec{Value: 5 * game.Dollar, MaxUses: 10, ExpiresAt: fake.Now().Add(time.Minute)})
+	require.NoError(t, err)
+	assert.Len(t, generated.Code, promoCodeLength)
+
+	fake.Advance(time.Minute)
+	_, err = book.Redeem(ctx, generated.Code, "p1")
+	assert.ErrorIs(t, err, ErrPromoExpired)
+
+	// Checking a code leaves its uses as they are
+	_, err = book.Redeemable(ctx, "WELCOME", "p4")
+	assert.ErrorIs(t, err, ErrPromoExhausted)
+	missing, err := book.Redeemable(ctx, "BONUS", "p1")
+	assert.ErrorIs(t, err, ErrPromoNotFound)
+	assert.Nil(t, missing)
+
+	codes, err := book.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, codes, 2)
+	assert.Equal(t, []string{"p1", "p2"}, codes[0].Redeemers)
+}
+
+func TestServer_RedeemPromoCreditsBankroll(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+
+	promo, err := server.Promos().Create(ctx, PromoSpec{Value: 50 * game.Dollar, MaxUses: 5})
+	require.NoError(t, err)
+
+	room, err := server.CreateRoom("r1", "Room 1", DefaultRoomConfig())
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+
+	// A player in a room has the credit added to their room balance
+	credited, err := server.RedeemPromo(ctx, "p1", room, promo.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 50*game.Dollar, credited.Amount)
+	assert.Equal(t, 150*game.Dollar, credited.Balance)
+	assert.Equal(t, 150*game.Dollar, room.GetPlayers()["p1"].Balance)
+
+	// Otherwise the server's record of the player is credited, opened with
+	// the starting balance for a player it has not seen
+	credited, err = server.RedeemPromo(ctx, "p2", nil, promo.Code)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStartingBalance+50*game.Dollar, credited.Balance)
+
+	player, err := server.Results().GetPlayer(ctx, "p2")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStartingBalance+50*game.Dollar, player.Balance)
+
+	_, err = server.RedeemPromo(ctx, "p1", room, promo.Code)
+	assert.ErrorIs(t, err, ErrPromoRedeemed)
+}
+
+func TestServer_RedeemPromoUsesCodeOnceCredited(t *testing.T) {
+	promos := storage.NewMemoryPromoRepository()
+	config := DefaultServerConfig()
+	config.Promos = promos
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+
+	promo, err := server.Promos().Create(ctx, PromoSpec{Code: "welcome", Value: 50 * game.Dollar, MaxUses: 1})
+	require.NoError(t, err)
+
+	// A credit that fails leaves the code unused
+	room, err := server.CreateRoom("r1", "Room 1", DefaultRoomConfig())
+	require.NoError(t, err)
+	_, err = server.RedeemPromo(ctx, "p1", room, promo.Code)
+	assert.ErrorIs(t, err, ErrPlayerNotFound)
+	stored, err := promos.GetPromo(ctx, "WELCOME")
+	require.NoError(t, err)
+	assert.Zero(t, stored.Uses)
+
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	_, err = server.RedeemPromo(ctx, "p1", room, promo.Code)
+	require.NoError(t, err)
+
+	// The code and its uses outlive the server keeping them
+	restarted := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(restarted.Stop)
+	codes, err := restarted.Promos().List(ctx)
+	require.NoError(t, err)
+	require.Len(t, codes, 1)
+	assert.Equal(t, []string{"p1"}, codes[0].Redeemers)
+	_, err = restarted.RedeemPromo(ctx, "p2", nil, promo.Code)
+	assert.ErrorIs(t, err, ErrPromoExhausted)
+}
@@ -0,0 +1,472 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/clock"
+)
+
+// Promotion errors
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrInvalidPromotion  = errors.New("invalid promotion")
+)
+
+// MaxPromotionBoost is the most a promotion may multiply payouts by
+const MaxPromotionBoost = 5.0
+
+// promotionTimerKey is the scheduler key of the next promotion starting or
+// ending
+const promotionTimerKey = "promotions"
+
+// promotionHorizon is how far ahead recurring promotions are looked for
+const promotionHorizon = 8 * 24 * time.Hour
+
+// Promotion is a happy hour: from StartsAt until EndsAt, wins in RoomID, or
+// in every room when that is empty, pay Boost times their usual payout
+type Promotion struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	RoomID   string    `json:"room_id,omitempty"`
+	Boost    float64   `json:"boost"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// Running reports whether the promotion is on at now
+func (p *Promotion) Running(now time.Time) bool {
+	return !now.Before(p.StartsAt) && now.Before(p.EndsAt)
+}
+
+// Covers reports whether the promotion boosts the room
+func (p *Promotion) Covers(roomID string) bool {
+	return p.RoomID == "" || p.RoomID == roomID
+}
+
+// PromotionSpec describes a promotion to schedule. A zero StartsAt starts
+// it at once.
+type PromotionSpec struct {
+	Name     string    `json:"name"`
+	RoomID   string    `json:"room_id,omitempty"`
+	Boost    float64   `json:"boost"`
+	StartsAt time.Time `json:"starts_at,omitempty"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// PromotionRule repeats a promotion every day, or only on Weekdays when
+// set, from Start past midnight UTC for Duration
+type PromotionRule struct {
+	Name     string
+	RoomID   string
+	Boost    float64
+	Start    time.Duration
+	Duration time.Duration
+	Weekdays []time.Weekday
+}
+
+// Validate checks the rule's boost and times
+func (r PromotionRule) Validate() error {
+	if err := validateBoost(r.Boost); err != nil {
+		return err
+	}
+	if r.Start < 0 || r.Start >= 24*time.Hour {
+		return fmt.Errorf("%w: start must be within the day", ErrInvalidPromotion)
+	}
+	if r.Duration <= 0 || r.Duration > 24*time.Hour {
+		return fmt.Errorf("%w: duration must be between 1 minute and 24 hours", ErrInvalidPromotion)
+	}
+	return nil
+}
+
+// on returns the rule's promotion on the UTC day starting at midnight, if
+// the rule runs that day. Its ID names the rule's position and the day.
+func (r PromotionRule) on(index int, midnight time.Time) (*Promotion, bool) {
+	if len(r.Weekdays) > 0 && !slices.Contains(r.Weekdays, midnight.Weekday()) {
+		return nil, false
+	}
+	start := midnight.Add(r.Start)
+	return &Promotion{
+		ID:       fmt.Sprintf("rule%d-%s", index+1, midnight.Format("20060102")),
+		Name:     r.Name,
+		RoomID:   r.RoomID,
+		Boost:    r.Boost,
+		StartsAt: start,
+		EndsAt:   start.Add(r.Duration),
+	}, true
+}
+
+// validateBoost checks a boost raises payouts without running away
+func validateBoost(boost float64) error {
+	if boost <= 1 || boost > MaxPromotionBoost {
+		return fmt.Errorf("%w: boost must be above 1 and at most %g", ErrInvalidPromotion, MaxPromotionBoost)
+	}
+	return nil
+}
+
+// PromotionSchedule holds the server's recurring promotions from its
+// configuration and the one-off promotions added through the admin
+// endpoints
+type PromotionSchedule struct {
+	mu         sync.Mutex
+	rules      []PromotionRule
+	promotions map[string]*Promotion
+	seq        int
+	clock      clock.Clock
+}
+
+// NewPromotionSchedule creates a schedule repeating the rules. A nil clock
+// uses system time.
+func NewPromotionSchedule(rules []PromotionRule, clk clock.Clock) *PromotionSchedule {
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &PromotionSchedule{
+		rules:      slices.Clone(rules),
+		promotions: make(map[string]*Promotion),
+		clock:      clk,
+	}
+}
+
+// Add schedules a one-off promotion
+func (s *PromotionSchedule) Add(spec PromotionSpec) (*Promotion, error) {
+	if err := validateBoost(spec.Boost); err != nil {
+		return nil, err
+	}
+	if spec.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidPromotion)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if spec.StartsAt.IsZero() {
+		spec.StartsAt = now
+	}
+	if !spec.EndsAt.After(spec.StartsAt) || !spec.EndsAt.After(now) {
+		return nil, fmt.Errorf("%w: it must end after it starts, in the future", ErrInvalidPromotion)
+	}
+
+	s.seq++
+	promotion := &Promotion{
+		ID:       fmt.Sprintf("promo%d", s.seq),
+		Name:     spec.Name,
+		RoomID:   spec.RoomID,
+		Boost:    spec.Boost,
+		StartsAt: spec.StartsAt,
+		EndsAt:   spec.EndsAt,
+	}
+	s.promotions[promotion.ID] = promotion
+	s.prune(now)
+
+	clone := *promotion
+	return &clone, nil
+}
+
+// Cancel removes a one-off promotion, ending it if it is running.
+// Recurring promotions are changed in the configuration instead.
+func (s *PromotionSchedule) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.promotions[id]; !exists {
+		return ErrPromotionNotFound
+	}
+	delete(s.promotions, id)
+	return nil
+}
+
+// List returns the running and upcoming promotions, the next of each
+// recurring one included, in the order they start
+func (s *PromotionSchedule) List() []Promotion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	s.prune(now)
+
+	var list []Promotion
+	for _, promotion := range s.promotions {
+		list = append(list, *promotion)
+	}
+	for i, rule := range s.rules {
+		for midnight := firstMidnight(now); midnight.Before(now.Add(promotionHorizon)); midnight = midnight.Add(24 * time.Hour) {
+			if promotion, ok := rule.on(i, midnight); ok && promotion.EndsAt.After(now) {
+				list = append(list, *promotion)
+				break
+			}
+		}
+	}
+	slices.SortFunc(list, func(a, b Promotion) int {
+		return a.StartsAt.Compare(b.StartsAt)
+	})
+	return list
+}
+
+// Active returns the promotion boosting the room now, the biggest boost if
+// several are running, or nil
+func (s *PromotionSchedule) Active(roomID string) *Promotion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	var active *Promotion
+	consider := func(promotion *Promotion) {
+		if promotion.Running(now) && promotion.Covers(roomID) && (active == nil || promotion.Boost > active.Boost) {
+			active = promotion
+		}
+	}
+	for _, promotion := range s.promotions {
+		consider(promotion)
+	}
+	for _, promotion := range s.occurrences(now, now) {
+		consider(promotion)
+	}
+
+	if active == nil {
+		return nil
+	}
+	clone := *active
+	return &clone
+}
+
+// NextChange returns when a promotion next starts or ends
+func (s *PromotionSchedule) NextChange() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	var next time.Time
+	consider := func(promotion *Promotion) {
+		for _, at := range []time.Time{promotion.StartsAt, promotion.EndsAt} {
+			if at.After(now) && (next.IsZero() || at.Before(next)) {
+				next = at
+			}
+		}
+	}
+	for _, promotion := range s.promotions {
+		consider(promotion)
+	}
+	for _, promotion := range s.occurrences(now, now.Add(promotionHorizon)) {
+		consider(promotion)
+	}
+	return next, !next.IsZero()
+}
+
+// occurrences returns the recurring promotions running at some point from
+// from to to. Callers must hold s.mu.
+func (s *PromotionSchedule) occurrences(from, to time.Time) []*Promotion {
+	var found []*Promotion
+	for midnight := firstMidnight(from); !midnight.After(to); midnight = midnight.Add(24 * time.Hour) {
+		for i, rule := range s.rules {
+			promotion, ok := rule.on(i, midnight)
+			if ok && promotion.EndsAt.After(from) && !promotion.StartsAt.After(to) {
+				found = append(found, promotion)
+			}
+		}
+	}
+	return found
+}
+
+// firstMidnight returns the UTC midnight of the day before t. Promotions
+// last at most a day, so one that started then may still be running at t.
+func firstMidnight(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour).Add(-24 * time.Hour)
+}
+
+// prune forgets one-off promotions that have ended. Callers must hold s.mu.
+func (s *PromotionSchedule) prune(now time.Time) {
+	for id, promotion := range s.promotions {
+		if !promotion.EndsAt.After(now) {
+			delete(s.promotions, id)
+		}
+	}
+}
+
+// SetPromotion boosts the room's payouts with the promotion, or stops
+// boosting them for nil, and tells the room. A round whose betting is open
+// pays the biggest boost that ran while it was. Pooled rooms pay what was
+// staked, so they are never boosted.
+func (r *GameRoom) SetPromotion(promotion *Promotion) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.config.Mode.Pooled() {
+		promotion = nil
+	}
+	previous := r.promotion
+	if previous == nil && promotion == nil ||
+		previous != nil && promotion != nil && *previous == *promotion {
+		return
+	}
+
+	r.promotion = promotion
+	if promotion != nil && r.gameState == StateBetting && r.currentRound != nil && promotion.Boost > r.currentRound.Boost {
+		r.currentRound.Boost = promotion.Boost
+	}
+
+	data := PromotionData{Promotion: promotion, Active: promotion != nil}
+	if promotion == nil {
+		data.Promotion = previous
+	}
+	r.logger.Info("Room promotion changed",
+		zap.String("room_id", r.id),
+		zap.String("promotion", data.Promotion.Name),
+		zap.Float64("boost", data.Promotion.Boost),
+		zap.Bool("active", data.Active),
+	)
+	r.broadcastMessage(NewMessage(MsgPromotion, r.id, "", data))
+}
+
+// Promotion returns the promotion boosting the room, or nil
+func (r *GameRoom) Promotion() *Promotion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.promotion == nil {
+		return nil
+	}
+	clone := *r.promotion
+	return &clone
+}
+
+// roundBoost returns the boost a round opening now pays. Callers must hold
+// r.mu.
+func (r *GameRoom) roundBoost() float64 {
+	if r.promotion == nil {
+		return 0
+	}
+	return r.promotion.Boost
+}
+
+// payoutMultiplier returns what a winning bet of the current round is
+// multiplied by for the streak and any promotion. Callers must hold r.mu.
+func (r *GameRoom) payoutMultiplier(streak int) float64 {
+	multiplier := r.streakMultiplier(streak)
+	if boost := r.currentRound.Boost; boost > 1 {
+		multiplier *= boost
+	}
+	return multiplier
+}
+
+// PayoutMultiplier returns what the player's winning bet was multiplied by
+// for their streak and any promotion, 1 meaning neither
+func (p *PlayerResult) PayoutMultiplier() float64 {
+	multiplier := 1.0
+	if p.Multiplier > 0 {
+		multiplier *= p.Multiplier
+	}
+	if p.Boost > 0 {
+		multiplier *= p.Boost
+	}
+	return multiplier
+}
+
+// applyPromotions gives every room the promotion running for it now
+func (s *Server) applyPromotions() {
+	s.mu.RLock()
+	rooms := make([]*GameRoom, 0, len(s.rooms))
+	for _, room := range s.rooms {
+		rooms = append(rooms, room)
+	}
+	s.mu.RUnlock()
+
+	for _, room := range rooms {
+		room.SetPromotion(s.promotions.Active(room.ID()))
+	}
+}
+
+// schedulePromotions sets the scheduler to update the rooms when a
+// promotion next starts or ends
+func (s *Server) schedulePromotions() {
+	next, ok := s.promotions.NextChange()
+	if !ok {
+		s.scheduler.Cancel(promotionTimerKey)
+		return
+	}
+	s.scheduler.Schedule(promotionTimerKey, next, nil, func() {
+		// Rooms are locked one at a time, off the scheduler's loop
+		go func() {
+			s.applyPromotions()
+			s.schedulePromotions()
+		}()
+	})
+}
+
+// Promotions returns the server's promotion schedule
+func (s *Server) Promotions() *PromotionSchedule {
+	return s.promotions
+}
+
+// handleListPromotions returns the running and upcoming promotions
+func (s *Server) handleListPromotions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.promotions.List())
+}
+
+// handleCreatePromotion schedules a one-off promotion
+func (s *Server) handleCreatePromotion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var spec PromotionSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "invalid_data",
+			Message: "Invalid promotion data",
+		})
+		return
+	}
+
+	promotion, err := s.promotions.Add(spec)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "promotion_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+	s.applyPromotions()
+	s.schedulePromotions()
+
+	s.logger.Info("Promotion scheduled",
+		zap.String("id", promotion.ID),
+		zap.String("name", promotion.Name),
+		zap.String("room_id", promotion.RoomID),
+		zap.Float64("boost", promotion.Boost),
+		zap.Time("starts_at", promotion.StartsAt),
+		zap.Time("ends_at", promotion.EndsAt),
+	)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(promotion)
+}
+
+// handleCancelPromotion ends a one-off promotion early or calls it off
+func (s *Server) handleCancelPromotion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := r.PathValue("id")
+	if err := s.promotions.Cancel(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "promotion_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+	s.applyPromotions()
+	s.schedulePromotions()
+
+	s.logger.Info("Promotion cancelled", zap.String("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,142 @@
+package network
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+)
+
+func TestPromotionSchedule(t *testing.T) {
+	// A Monday
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	schedule := NewPromotionSchedule([]PromotionRule{
+		{Name: "Evening", Boost: 2, Start: 18 * time.Hour, Duration: time.Hour},
+		{Name: "Weekend", RoomID: "vip", Boost: 3, Start: 0, Duration: 24 * time.Hour, Weekdays: []time.Weekday{time.Saturday}},
+	}, fake)
+
+	assert.Nil(t, schedule.Active("lobby"))
+	next, ok := schedule.NextChange()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC), next)
+
+	list := schedule.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, "rule1-20240101", list[0].ID)
+	assert.Equal(t, time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), list[1].StartsAt)
+
+	fake.Set(time.Date(2024, 1, 1, 18, 30, 0, 0, time.UTC))
+	active := schedule.Active("lobby")
+	require.NotNil(t, active)
+	assert.Equal(t, 2.0, active.Boost)
+	next, _ = schedule.NextChange()
+	assert.Equal(t, time.Date(2024, 1, 1, 19, 0, 0, 0, time.UTC), next)
+
+	// The biggest running boost wins, and room promotions stay in their room
+	launch, err := schedule.Add(PromotionSpec{Name: "Launch", Boost: 2.5, EndsAt: fake.Now().Add(10 * time.Minute)})
+	require.NoError(t, err)
+	_, err = schedule.Add(PromotionSpec{Name: "VIP", RoomID: "vip", Boost: 4, EndsAt: fake.Now().Add(10 * time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, launch.ID, schedule.Active("lobby").ID)
+	assert.Equal(t, 4.0, schedule.Active("vip").Boost)
+
+	require.NoError(t, schedule.Cancel(launch.ID))
+	assert.Equal(t, 2.0, schedule.Active("lobby").Boost)
+	assert.ErrorIs(t, schedule.Cancel(launch.ID), ErrPromotionNotFound)
+
+	// Saturday's promotion only boosts its room
+	fake.Set(time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC))
+	assert.Nil(t, schedule.Active("lobby"))
+	assert.Equal(t, 3.0, schedule.Active("vip").Boost)
+
+	_, err = schedule.Add(PromotionSpec{Name: "Too much", Boost: 10, EndsAt: fake.Now().Add(time.Hour)})
+	assert.ErrorIs(t, err, ErrInvalidPromotion)
+	_, err = schedule.Add(PromotionSpec{Name: "Over", Boost: 2, EndsAt: fake.Now().Add(-time.Hour)})
+	assert.ErrorIs(t, err, ErrInvalidPromotion)
+}
+
+func TestGameRoom_PromotionBoostsPayouts(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	room.SetPromotion(&Promotion{ID: "promo1", Name: "Happy hour", Boost: 1.5, StartsAt: fake.Now(), EndsAt: fake.Now().Add(time.Minute)})
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p2", 10*game.Dollar, game.Tails))
+
+	// Ending during betting still pays the round the boost
+	room.SetPromotion(nil)
+	var announced []PromotionData
+	for _, msg := range drainEvents(room) {
+		if msg.Type == MsgPromotion {
+			var data PromotionData
+			require.NoError(t, msg.GetData(&data))
+			announced = append(announced, data)
+		}
+	}
+	require.Len(t, announced, 2)
+	assert.True(t, announced[0].Active)
+	assert.False(t, announced[1].Active)
+	assert.Equal(t, "Happy hour", announced[1].Promotion.Name)
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	var result GameResultData
+	for _, msg := range drainEvents(room) {
+		if msg.Type == MsgGameResult {
+			require.NoError(t, msg.GetData(&result))
+		}
+	}
+	require.Len(t, result.Winners, 1)
+	winner := result.Winners[0]
+	assert.Equal(t, 1.5, winner.Boost)
+	assert.Equal(t, 30*game.Dollar, winner.Payout)
+	assert.Equal(t, 120*game.Dollar, room.GetPlayers()[winner.PlayerID].Balance)
+}
+
+func TestServer_PromotionEndpoints(t *testing.T) {
+	server, fake := newCleanupServer(t)
+	config := DefaultRoomConfig()
+	config.UpdateInterval = 0
+	room, err := server.CreateRoom("r1", "Room 1", config)
+	require.NoError(t, err)
+	handler := server.Handler()
+
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, adminAPIRequest(method, path, body))
+		return recorder
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin/promotions", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	ends := fake.Now().Add(time.Hour).Format(time.RFC3339)
+	recorder = request(http.MethodPost, "/admin/promotions", `{"name":"Launch","boost":2,"ends_at":"`+ends+`"}`)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	var created Promotion
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&created))
+	require.NotNil(t, room.Promotion())
+	assert.Equal(t, 2.0, room.Promotion().Boost)
+
+	recorder = request(http.MethodGet, "/admin/promotions", "")
+	var list []Promotion
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&list))
+	require.Len(t, list, 1)
+	assert.Equal(t, created.ID, list[0].ID)
+
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/admin/promotions", `{"name":"Bad","boost":0.5,"ends_at":"`+ends+`"}`).Code)
+
+	assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/admin/promotions/"+created.ID, "").Code)
+	assert.Nil(t, room.Promotion())
+	assert.Equal(t, http.StatusNotFound, request(http.MethodDelete, "/admin/promotions/"+created.ID, "").Code)
+}
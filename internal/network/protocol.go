@@ -0,0 +1,218 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+)
+
+// Protocol versions. Clients send the newest version they speak when they
+// connect; the server answers with the version both sides will use.
+const (
+	// ProtocolVersion is the version this build speaks. Version 2 reports
+	// every bet a player placed in a round (PlayerResult.Bets); version 3
+	// sends delta room updates (RoomUpdateData.Delta).
+	ProtocolVersion = 3
+	// MinProtocolVersion is the oldest version the server still serves.
+	// Version 1 clients predate negotiation and send no version at all.
+	MinProtocolVersion = 1
+)
+
+// ProtocolParam is the query parameter carrying the client's version on
+// the WebSocket URL. The handshake response advertises the negotiated
+// version in ProtocolHeader and every supported one in
+// ProtocolSupportedHeader.
+const (
+	ProtocolParam           = "protocol"
+	ProtocolHeader          = "Coinflip-Protocol"
+	ProtocolSupportedHeader = "Coinflip-Protocol-Supported"
+)
+
+// MsgUpgradeRequired tells a client its protocol is no longer supported.
+// The server closes the connection after sending it.
+const MsgUpgradeRequired MessageType = "upgrade_required"
+
+// ErrUpgradeRequired is matched by UpgradeRequiredError
+var ErrUpgradeRequired = errors.New("client upgrade required")
+
+// UpgradeRequiredData explains why a client was turned away
+type UpgradeRequiredData struct {
+	ClientVersion     int    `json:"client_version"`
+	SupportedVersions []int  `json:"supported_versions"`
+	Message           string `json:"message"`
+}
+
+// UpgradeRequiredError is reported by the client when the server refuses
+// its protocol version
+type UpgradeRequiredError struct {
+	UpgradeRequiredData
+}
+
+// Error implements the error interface
+func (e *UpgradeRequiredError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrUpgradeRequired, e.Message)
+}
+
+// Is makes the error match ErrUpgradeRequired
+func (e *UpgradeRequiredError) Is(target error) bool {
+	return target == ErrUpgradeRequired
+}
+
+// SupportedProtocolVersions lists the versions the server accepts, oldest
+// first
+func SupportedProtocolVersions() []int {
+	versions := make([]int, 0, ProtocolVersion-MinProtocolVersion+1)
+	for v := MinProtocolVersion; v <= ProtocolVersion; v++ {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// NegotiateProtocol picks the version to speak with a client that asked for
+// requested. Clients that send nothing are treated as version 1, and newer
+// clients are served the server's own version.
+func NegotiateProtocol(requested string) (int, error) {
+	if requested == "" {
+		return 1, nil
+	}
+
+	version, err := strconv.Atoi(requested)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid protocol version %q", requested)
+	}
+	if version < MinProtocolVersion {
+		return version, fmt.Errorf("protocol version %d is no longer supported; this server speaks versions %s",
+			version, versionList())
+	}
+	return min(version, ProtocolVersion), nil
+}
+
+// versionList formats the supported versions for messages and headers
+func versionList() string {
+	versions := SupportedProtocolVersions()
+	parts := make([]string, len(versions))
+	for i, v := range versions {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// withProtocol adds the client's protocol version to a WebSocket URL
+func withProtocol(u *url.URL) *url.URL {
+	versioned := *u
+	query := versioned.Query()
+	query.Set(ProtocolParam, strconv.Itoa(ProtocolVersion))
+	versioned.RawQuery = query.Encode()
+	return &versioned
+}
+
+// legacyPlayerResult is a result as version 1 clients read it, with a
+// single bet
+type legacyPlayerResult struct {
+	PlayerResult
+	Bet *BetData `json:"bet,omitempty"`
+}
+
+// legacyGameResult is GameResultData for version 1 clients. Its fields are
+// spelled out rather than embedded, since msgpack would see winners and
+// losers twice; fields added to GameResultData are added here too.
+type legacyGameResult struct {
+	RoundID    string               `json:"round_id"`
+	CoinResult game.Side            `json:"coin_result"`
+	FinalSeed  string               `json:"final_seed"`
+	Winners    []legacyPlayerResult `json:"winners"`
+	Losers     []legacyPlayerResult `json:"losers"`
+	Timestamp  time.Time            `json:"timestamp"`
+	Pool       *PoolData            `json:"pool,omitempty"`
+}
+
+// ForProtocol returns the message as a client speaking version should see
+// it. Messages that did not change are returned as is.
+func (m *Message) ForProtocol(version int) *Message {
+	switch {
+	case m.Type == MsgGameResult && version < 2:
+		return m.legacyGameResult()
+	case m.Type == MsgRoomUpdate && version < 3:
+		return m.fullRoomUpdate()
+	}
+	return m
+}
+
+// legacyGameResult reports a result with a single bet per player
+func (m *Message) legacyGameResult() *Message {
+	var result GameResultData
+	if err := m.GetData(&result); err != nil {
+		return m
+	}
+
+	legacy := legacyGameResult{
+		RoundID:    result.RoundID,
+		CoinResult: result.CoinResult,
+		FinalSeed:  result.FinalSeed,
+		Winners:    legacyResults(result.Winners),
+		Losers:     legacyResults(result.Losers),
+		Timestamp:  result.Timestamp,
+		Pool:       result.Pool,
+	}
+	shimmed := *m
+	shimmed.Data = legacy
+	return &shimmed
+}
+
+// legacyResults reports each player's largest bet as their only one
+func legacyResults(results []PlayerResult) []legacyPlayerResult {
+	legacy := make([]legacyPlayerResult, len(results))
+	for i, result := range results {
+		legacy[i].PlayerResult = result
+		for _, bet := range result.Bets {
+			if legacy[i].Bet == nil || bet.Amount > legacy[i].Bet.Amount {
+				legacy[i].Bet = bet
+			}
+		}
+	}
+	return legacy
+}
+
+// rejectProtocol tells a client its protocol version is unsupported and
+// closes the connection
+func (s *Server) rejectProtocol(conn *websocket.Conn, requested string, reason error) {
+	defer conn.Close()
+
+	clientVersion, _ := strconv.Atoi(requested)
+	s.logger.Warn("Rejected client protocol",
+		zap.String("remote_addr", conn.RemoteAddr().String()),
+		zap.String("protocol", requested),
+		zap.Error(reason),
+	)
+
+	encoding := EncodingForSubprotocol(conn.Subprotocol())
+	msg := NewMessage(MsgUpgradeRequired, "", "", UpgradeRequiredData{
+		ClientVersion:     clientVersion,
+		SupportedVersions: SupportedProtocolVersions(),
+		Message:           reason.Error(),
+	})
+	data, err := msg.Encode(encoding)
+	if err != nil {
+		return
+	}
+
+	frameType := websocket.TextMessage
+	if encoding.IsBinary() {
+		frameType = websocket.BinaryMessage
+	}
+	deadline := time.Now().Add(s.config.WriteTimeout)
+	conn.SetWriteDeadline(deadline)
+	if err := conn.WriteMessage(frameType, data); err != nil {
+		return
+	}
+	conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseProtocolError, "upgrade required"), deadline)
+}
@@ -0,0 +1,187 @@
+package network
+
+import (
+	"bytes"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestNegotiateProtocol(t *testing.T) {
+	tests := []struct {
+		requested string
+		want      int
+		wantErr   bool
+	}{
+		{requested: "", want: 1},
+		{requested: "1", want: 1},
+		{requested: "2", want: 2},
+		{requested: "7", want: ProtocolVersion},
+		{requested: "0", wantErr: true},
+		{requested: "latest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		version, err := NegotiateProtocol(tt.requested)
+		if tt.wantErr {
+			assert.Error(t, err, tt.requested)
+			continue
+		}
+		require.NoError(t, err, tt.requested)
+		assert.Equal(t, tt.want, version, tt.requested)
+	}
+}
+
+func TestMessage_ForProtocol(t *testing.T) {
+	msg := NewMessage(MsgGameResult, "r1", "", GameResultData{
+		RoundID:    "round_1",
+		CoinResult: game.Heads,
+		Winners: []PlayerResult{{
+			PlayerID: "p1",
+			Bets: []*BetData{
+				{PlayerID: "p1", Amount: 10 * game.Dollar, Choice: game.Tails, BetID: "b1"},
+				{PlayerID: "p1", Amount: 30 * game.Dollar, Choice: game.Heads, BetID: "b2"},
+			},
+			Won:    true,
+			Payout: 60 * game.Dollar,
+		}},
+	})
+
+	assert.Same(t, msg, msg.ForProtocol(ProtocolVersion))
+
+	// Version 1 clients read a single bet, in either encoding
+	for _, encoding := range []Encoding{EncodingJSON, EncodingMsgPack} {
+		data, err := msg.ForProtocol(1).Encode(encoding)
+		require.NoError(t, err)
+		decoded, err := DecodeMessage(data, encoding)
+		require.NoError(t, err)
+
+		var legacy struct {
+			RoundID string `json:"round_id"`
+			Winners []struct {
+				PlayerID string   `json:"player_id"`
+				Bet      *BetData `json:"bet"`
+				Payout   float64  `json:"payout"`
+			} `json:"winners"`
+		}
+		require.NoError(t, decoded.GetData(&legacy), encoding)
+		assert.Equal(t, "round_1", legacy.RoundID)
+		require.Len(t, legacy.Winners, 1)
+		require.NotNil(t, legacy.Winners[0].Bet, encoding)
+		assert.Equal(t, "b2", legacy.Winners[0].Bet.BetID)
+		assert.Equal(t, 60.0, legacy.Winners[0].Payout)
+	}
+}
+
+func TestMessage_ForProtocolWireKeys(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	msg := NewMessage(MsgGameResult, "r1", "", GameResultData{
+		RoundID:    "round_1",
+		CoinResult: game.Heads,
+		FinalSeed:  "seed",
+		Winners: []PlayerResult{{
+			PlayerID: "p1",
+			Bets:     []*BetData{{PlayerID: "p1", Amount: 10 * game.Dollar, Choice: game.Heads, BetID: "b1"}},
+			Won:      true,
+			Payout:   19 * game.Dollar,
+		}},
+		Losers: []PlayerResult{{PlayerID: "p2"}},
+		Pool:   &PoolData{Total: 10 * game.Dollar},
+	})
+
+	// keys returns the wire keys of a message's data and of its first
+	// winner, as decoded from the encoding
+	keys := func(msg *Message, encoding Encoding) ([]string, []string) {
+		data, err := msg.Encode(encoding)
+		require.NoError(t, err)
+		decoded, err := DecodeMessage(data, encoding)
+		require.NoError(t, err)
+		var result map[string]any
+		require.NoError(t, decoded.GetData(&result))
+		winners, ok := result["winners"].([]any)
+		require.True(t, ok, encoding)
+		require.Len(t, winners, 1)
+		return sortedKeys(result), sortedKeys(winners[0].(map[string]any))
+	}
+
+	// Version 1 results carry every key of the current ones once, and
+	// winners their single bet besides
+	for _, encoding := range []Encoding{EncodingJSON, EncodingMsgPack} {
+		current, currentWinner := keys(msg, encoding)
+		legacy, legacyWinner := keys(msg.ForProtocol(1), encoding)
+		assert.Equal(t, current, legacy, encoding)
+		withBet := append([]string{"bet"}, currentWinner...)
+		sort.Strings(withBet)
+		assert.Equal(t, withBet, legacyWinner, encoding)
+	}
+	assert.NotContains(t, logged.String(), "msgpack")
+}
+
+// sortedKeys returns a decoded object's keys in order
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestServer_ProtocolHandshake(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+	url := "ws://" + listener.Addr().String() + "/ws"
+
+	// The client announces its version and learns the negotiated one
+	config := DefaultClientConfig()
+	config.ServerURL = url
+	client := NewNetworkClient(config, "p1", "Player 1", zaptest.NewLogger(t))
+	defer client.Disconnect()
+	require.NoError(t, client.Connect())
+	assert.Equal(t, ProtocolVersion, client.Protocol())
+
+	// Clients without a version are served as version 1
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	conn.Close()
+	assert.Equal(t, "1", resp.Header.Get(ProtocolHeader))
+	assert.Equal(t, "1, 2, 3", resp.Header.Get(ProtocolSupportedHeader))
+
+	// Unsupported versions are told to upgrade, then disconnected
+	conn, _, err = websocket.DefaultDialer.Dial(url+"?protocol=0", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	msg, err := DecodeMessage(data, EncodingJSON)
+	require.NoError(t, err)
+	assert.Equal(t, MsgUpgradeRequired, msg.Type)
+
+	var upgrade UpgradeRequiredData
+	require.NoError(t, msg.GetData(&upgrade))
+	assert.Equal(t, SupportedProtocolVersions(), upgrade.SupportedVersions)
+	assert.NotEmpty(t, upgrade.Message)
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseProtocolError), err)
+}
@@ -0,0 +1,143 @@
+package network
+
+import (
+	"sort"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+)
+
+// QueueBet holds a bet until the next betting phase opens, replacing any
+// bet the player already queued. If betting is open now the bet is placed
+// straight away. The amount is checked against the room limits and the
+// player's balance now, and again when the bet is placed.
+func (r *GameRoom) QueueBet(playerID string, amount game.Money, choice game.Side) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.queueBet(playerID, amount, choice, false)
+}
+
+// QueueInsuredBet queues a bet to be placed with the room's insurance
+func (r *GameRoom) QueueInsuredBet(playerID string, amount game.Money, choice game.Side) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.queueBet(playerID, amount, choice, true)
+}
+
+// queueBet queues a bet, insured or not. Callers must hold r.mu.
+func (r *GameRoom) queueBet(playerID string, amount game.Money, choice game.Side, insured bool) error {
+	player, exists := r.players[playerID]
+	if !exists {
+		return ErrPlayerNotFound
+	}
+	if amount < r.config.MinBet || amount > r.config.MaxBet {
+		return game.ErrInvalidBetAmount
+	}
+	if insured && !r.config.Insurance.Enabled() {
+		return game.ErrInsuranceUnavailable
+	}
+
+	bet := &BetData{PlayerID: playerID, Amount: amount, Choice: choice}
+	if insured {
+		r.insure(bet)
+	}
+	if player.Balance < betCost(bet) {
+		return game.ErrInsufficientBalance
+	}
+
+	if r.gameState == StateBetting {
+		if err := r.placeBet(playerID, amount, choice, insured, false, r.clock.Now()); err != nil {
+			return err
+		}
+		r.broadcastQueuedBet(r.placedBet(bet), QueuedBetPlaced, "")
+		return nil
+	}
+
+	r.queuedBets[playerID] = bet
+	r.lastActivity = r.clock.Now()
+
+	r.logger.Info("Bet queued for next round",
+		zap.String("room_id", r.id),
+		zap.String("player_id", playerID),
+		zap.Float64("amount", amount.Float64()),
+		zap.String("choice", choice.String()),
+	)
+	r.broadcastQueuedBet(bet, QueuedBetWaiting, "")
+	r.markActive(player)
+	return nil
+}
+
+// CancelQueuedBet withdraws the bet a player queued for the next round
+func (r *GameRoom) CancelQueuedBet(playerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bet, exists := r.queuedBets[playerID]
+	if !exists {
+		return ErrNoQueuedBet
+	}
+	delete(r.queuedBets, playerID)
+
+	r.broadcastQueuedBet(bet, QueuedBetWithdrawn, "")
+	return nil
+}
+
+// QueuedBet returns the bet a player queued for the next round, if any
+func (r *GameRoom) QueuedBet(playerID string) (BetData, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bet, exists := r.queuedBets[playerID]
+	if !exists {
+		return BetData{}, false
+	}
+	return *bet, true
+}
+
+// placeQueuedBets submits every queued bet as betting opens. Bets the
+// round no longer accepts, for example after the balance dropped, are
+// dropped and reported as failed. Callers must hold r.mu.
+func (r *GameRoom) placeQueuedBets() {
+	playerIDs := make([]string, 0, len(r.queuedBets))
+	for playerID := range r.queuedBets {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Strings(playerIDs)
+
+	for _, playerID := range playerIDs {
+		bet := r.queuedBets[playerID]
+		delete(r.queuedBets, playerID)
+
+		if err := r.placeBet(playerID, bet.Amount, bet.Choice, bet.Insured, false, r.clock.Now()); err != nil {
+			r.logger.Info("Queued bet rejected",
+				zap.String("room_id", r.id),
+				zap.String("player_id", playerID),
+				zap.Error(err),
+			)
+			r.broadcastQueuedBet(bet, QueuedBetFailed, err.Error())
+			continue
+		}
+		r.broadcastQueuedBet(r.placedBet(bet), QueuedBetPlaced, "")
+	}
+}
+
+// placedBet returns the bet a queued bet became once placed, which the
+// room's betting limits may have scaled down. Callers must hold r.mu.
+func (r *GameRoom) placedBet(queued *BetData) *BetData {
+	if placed := betOn(r.currentRound.Bets[queued.PlayerID], queued.Choice); placed != nil {
+		return placed
+	}
+	return queued
+}
+
+// broadcastQueuedBet announces a change to a player's queued bet
+func (r *GameRoom) broadcastQueuedBet(bet *BetData, status QueuedBetStatus, reason string) {
+	r.broadcastMessage(NewMessage(MsgQueuedBet, r.id, bet.PlayerID, &QueuedBetData{
+		Bet:    *bet,
+		Status: status,
+		Reason: r.localize(reason),
+	}))
+}
@@ -0,0 +1,210 @@
+package network
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+)
+
+// registryShards is how many locks the client registry spreads its clients
+// and rooms over
+const registryShards = 32
+
+// clientRegistry maps connected clients to the room they joined, nil while
+// they are in the lobby, and indexes each room's clients. Clients are
+// sharded by connection and rooms by ID, so broadcasts to one room neither
+// wait on the server lock nor scan the clients of every other room.
+//
+// Locks are taken client shard first, then room shard, and a room shard's
+// read lock is held while its clients are sent to, so a client removed
+// from the registry is no longer being written to.
+type clientRegistry struct {
+	clients [registryShards]clientShard
+	rooms   [registryShards]roomShard
+	ids     atomic.Uint64
+}
+
+// clientShard holds the room each of its clients joined
+type clientShard struct {
+	mu     sync.Mutex
+	joined map[*Client]*GameRoom
+}
+
+// roomShard holds the clients of each of its rooms
+type roomShard struct {
+	mu      sync.RWMutex
+	members map[*GameRoom]map[*Client]struct{}
+}
+
+// newClientRegistry creates an empty registry
+func newClientRegistry() *clientRegistry {
+	r := &clientRegistry{}
+	for i := range r.clients {
+		r.clients[i].joined = make(map[*Client]*GameRoom)
+		r.rooms[i].members = make(map[*GameRoom]map[*Client]struct{})
+	}
+	return r
+}
+
+// nextID returns the ID of a new connection, which picks its client shard
+func (r *clientRegistry) nextID() uint64 {
+	return r.ids.Add(1)
+}
+
+// clientShard returns the shard holding a client
+func (r *clientRegistry) clientShard(c *Client) *clientShard {
+	return &r.clients[c.id%registryShards]
+}
+
+// roomShard returns the shard holding a room's clients
+func (r *clientRegistry) roomShard(room *GameRoom) *roomShard {
+	hash := fnv.New32a()
+	hash.Write([]byte(room.ID()))
+	return &r.rooms[hash.Sum32()%registryShards]
+}
+
+// add registers a client in the lobby
+func (r *clientRegistry) add(c *Client) {
+	r.move(c, nil)
+}
+
+// remove forgets a client, returning the room it had joined and whether it
+// was registered at all
+func (r *clientRegistry) remove(c *Client) (*GameRoom, bool) {
+	shard := r.clientShard(c)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	room, exists := shard.joined[c]
+	if !exists {
+		return nil, false
+	}
+	delete(shard.joined, c)
+	r.leaveRoom(c, room)
+	return room, true
+}
+
+// move maps a client to a room, or to the lobby when room is nil,
+// registering it if needed. It returns the room the client was in before.
+func (r *clientRegistry) move(c *Client, room *GameRoom) *GameRoom {
+	shard := r.clientShard(c)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	previous := shard.joined[c]
+	shard.joined[c] = room
+	if previous != room {
+		r.leaveRoom(c, previous)
+		r.joinRoom(c, room)
+	}
+	return previous
+}
+
+// leave returns a client to the lobby if it is still in room, reporting
+// whether it was
+func (r *clientRegistry) leave(c *Client, room *GameRoom) bool {
+	shard := r.clientShard(c)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if joined, exists := shard.joined[c]; !exists || joined != room || room == nil {
+		return false
+	}
+	shard.joined[c] = nil
+	r.leaveRoom(c, room)
+	return true
+}
+
+// joined returns the room a client is in and whether it is registered
+func (r *clientRegistry) joined(c *Client) (*GameRoom, bool) {
+	shard := r.clientShard(c)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	room, exists := shard.joined[c]
+	return room, exists
+}
+
+// len returns how many clients are registered
+func (r *clientRegistry) len() int {
+	count := 0
+	for i := range r.clients {
+		shard := &r.clients[i]
+		shard.mu.Lock()
+		count += len(shard.joined)
+		shard.mu.Unlock()
+	}
+	return count
+}
+
+// each calls fn for every registered client, one shard at a time
+func (r *clientRegistry) each(fn func(c *Client)) {
+	for i := range r.clients {
+		shard := &r.clients[i]
+		shard.mu.Lock()
+		for c := range shard.joined {
+			fn(c)
+		}
+		shard.mu.Unlock()
+	}
+}
+
+// eachIn calls fn for the clients in room until it returns false. The
+// room's shard is read-locked throughout, so fn must not change the
+// registry.
+func (r *clientRegistry) eachIn(room *GameRoom, fn func(c *Client) bool) {
+	shard := r.roomShard(room)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	for c := range shard.members[room] {
+		if !fn(c) {
+			return
+		}
+	}
+}
+
+// members returns the clients in room
+func (r *clientRegistry) members(room *GameRoom) []*Client {
+	var clients []*Client
+	r.eachIn(room, func(c *Client) bool {
+		clients = append(clients, c)
+		return true
+	})
+	return clients
+}
+
+// joinRoom adds a client to a room's index. Callers must hold the client's
+// shard lock.
+func (r *clientRegistry) joinRoom(c *Client, room *GameRoom) {
+	if room == nil {
+		return
+	}
+	shard := r.roomShard(room)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	members, exists := shard.members[room]
+	if !exists {
+		members = make(map[*Client]struct{})
+		shard.members[room] = members
+	}
+	members[c] = struct{}{}
+}
+
+// leaveRoom removes a client from a room's index, dropping the room once
+// it has no clients left. Callers must hold the client's shard lock.
+func (r *clientRegistry) leaveRoom(c *Client, room *GameRoom) {
+	if room == nil {
+		return
+	}
+	shard := r.roomShard(room)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	members := shard.members[room]
+	delete(members, c)
+	if len(members) == 0 {
+		delete(shard.members, room)
+	}
+}
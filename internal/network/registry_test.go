@@ -0,0 +1,104 @@
+package network
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestClientRegistry_TracksRooms(t *testing.T) {
+	registry := newClientRegistry()
+	r1 := NewGameRoom("r1", "Room 1", nil, nil, zaptest.NewLogger(t))
+	defer r1.Stop()
+	r2 := NewGameRoom("r2", "Room 2", nil, nil, zaptest.NewLogger(t))
+	defer r2.Stop()
+
+	a := &Client{id: registry.nextID()}
+	b := &Client{id: registry.nextID()}
+	registry.add(a)
+	registry.add(b)
+	assert.Equal(t, 2, registry.len())
+
+	room, exists := registry.joined(a)
+	assert.True(t, exists)
+	assert.Nil(t, room)
+
+	assert.Nil(t, registry.move(a, r1))
+	registry.move(b, r1)
+	assert.ElementsMatch(t, []*Client{a, b}, registry.members(r1))
+
+	// Moving takes the client out of its previous room
+	assert.Same(t, r1, registry.move(a, r2))
+	assert.Equal(t, []*Client{b}, registry.members(r1))
+	assert.Equal(t, []*Client{a}, registry.members(r2))
+
+	// Leaving only applies to the room the client is in
+	assert.False(t, registry.leave(a, r1))
+	assert.True(t, registry.leave(a, r2))
+	assert.False(t, registry.leave(a, r2))
+	assert.Empty(t, registry.members(r2))
+
+	room, exists = registry.remove(b)
+	assert.True(t, exists)
+	assert.Same(t, r1, room)
+	assert.Empty(t, registry.members(r1))
+	_, exists = registry.remove(b)
+	assert.False(t, exists)
+	assert.Equal(t, 1, registry.len())
+}
+
+func TestServer_BroadcastToRoomDuringMoves(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	defer server.Stop()
+
+	rooms := make([]*GameRoom, 4)
+	for i := range rooms {
+		room, err := server.CreateRoom(fmt.Sprintf("r%d", i), "Room", nil)
+		require.NoError(t, err)
+		rooms[i] = room
+	}
+
+	clients := make([]*Client, 16)
+	for i := range clients {
+		clients[i] = &Client{
+			id:       server.clients.nextID(),
+			server:   server,
+			send:     make(chan []byte, 1024),
+			encoding: EncodingJSON,
+			protocol: ProtocolVersion,
+		}
+		server.clients.add(clients[i])
+	}
+
+	// Clients hop between rooms while every room is broadcast to
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client *Client) {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				server.clients.move(client, rooms[(i+n)%len(rooms)])
+			}
+			server.clients.remove(client)
+		}(i, client)
+	}
+	for _, room := range rooms {
+		wg.Add(1)
+		go func(room *GameRoom) {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				server.broadcastToRoom(room, NewMessage(MsgChat, room.ID(), "", ChatData{Text: "hi"}))
+			}
+		}(room)
+	}
+	wg.Wait()
+
+	assert.Zero(t, server.clients.len())
+	for _, room := range rooms {
+		assert.Empty(t, server.clients.members(room))
+	}
+}
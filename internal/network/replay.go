@@ -0,0 +1,326 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Limits on round recordings
+const (
+	// replaysKept is how many of its last rounds a room keeps for replay
+	replaysKept = 5
+	// maxRecordedEvents bounds a recording; past it only the round's phase
+	// changes, bets and result are recorded, not countdown or room updates
+	maxRecordedEvents = 2000
+	// maxReplayGap is the longest a replay waits between two events, so a
+	// round paused by vote does not stall its replay
+	maxReplayGap = 5 * time.Second
+)
+
+// ErrReplayNotFound is returned when a room has no recording of a round
+var ErrReplayNotFound = errors.New("no recording of that round")
+
+// RoundRecording is everything a room sent during one round, from the
+// start of betting until the result phase ended or the round was
+// cancelled. It opens with the room's full state, and room updates are
+// recorded in full, so playing it back needs nothing sent before it.
+type RoundRecording struct {
+	RoundID    string          `json:"round_id"`
+	RoomID     string          `json:"room_id"`
+	StartedAt  time.Time       `json:"started_at"`
+	DurationMs int64           `json:"duration_ms"`
+	Events     []RecordedEvent `json:"events"`
+}
+
+// RecordedEvent is a message a room sent, OffsetMs into its round
+type RecordedEvent struct {
+	OffsetMs int64    `json:"offset_ms"`
+	Message  *Message `json:"message"`
+}
+
+// Summary describes the recording without its events
+func (rec *RoundRecording) Summary() ReplaySummary {
+	return ReplaySummary{
+		RoundID:    rec.RoundID,
+		RoomID:     rec.RoomID,
+		StartedAt:  rec.StartedAt,
+		DurationMs: rec.DurationMs,
+		Events:     len(rec.Events),
+	}
+}
+
+// roundRecorder records the round in progress and keeps the last few. It
+// has its own lock as countdowns are broadcast under the room's read lock.
+type roundRecorder struct {
+	mu       sync.Mutex
+	current  *RoundRecording
+	recorded []*RoundRecording // Oldest first
+}
+
+// startRecording starts recording the current round with the room's state
+// as it opens. Callers must hold r.mu.
+func (r *GameRoom) startRecording() {
+	update, _ := r.roomUpdate()
+	now := r.clock.Now()
+
+	r.recorder.mu.Lock()
+	defer r.recorder.mu.Unlock()
+	r.recorder.current = &RoundRecording{
+		RoundID:   r.currentRound.ID,
+		RoomID:    r.id,
+		StartedAt: now,
+		Events: []RecordedEvent{{
+			Message: &Message{Type: MsgRoomUpdate, RoomID: r.id, Timestamp: now, Data: update},
+		}},
+	}
+}
+
+// record adds a message sent to the room to the round being recorded, if
+// any. Broadcast messages are never changed once sent, so the recording
+// shares them.
+func (r *GameRoom) record(msg *Message) {
+	r.recorder.mu.Lock()
+	defer r.recorder.mu.Unlock()
+
+	rec := r.recorder.current
+	if rec == nil {
+		return
+	}
+	if len(rec.Events) >= maxRecordedEvents && (msg.Type == MsgRoomUpdate || msg.Type == MsgTimerUpdate) {
+		return
+	}
+
+	// The replay belongs to no trace of the viewer's
+	recorded := *msg.fullRoomUpdate()
+	recorded.TraceParent = ""
+	rec.Events = append(rec.Events, RecordedEvent{
+		OffsetMs: r.clock.Now().Sub(rec.StartedAt).Milliseconds(),
+		Message:  &recorded,
+	})
+}
+
+// finishRecording stops recording the round, keeping it for replay unless
+// it is discarded, such as a round nobody bet in
+func (r *GameRoom) finishRecording(keep bool) {
+	r.recorder.mu.Lock()
+	defer r.recorder.mu.Unlock()
+
+	rec := r.recorder.current
+	r.recorder.current = nil
+	if rec == nil || !keep {
+		return
+	}
+
+	rec.DurationMs = r.clock.Now().Sub(rec.StartedAt).Milliseconds()
+	r.recorder.recorded = append(r.recorder.recorded, rec)
+	if len(r.recorder.recorded) > replaysKept {
+		r.recorder.recorded = slices.Delete(r.recorder.recorded, 0, len(r.recorder.recorded)-replaysKept)
+	}
+}
+
+// Replay returns the recording of one of the room's last rounds, or of its
+// last round when roundID is empty. It fails with ErrReplayNotFound once
+// the round is too old or if it is still being played.
+func (r *GameRoom) Replay(roundID string) (*RoundRecording, error) {
+	r.recorder.mu.Lock()
+	defer r.recorder.mu.Unlock()
+
+	for i := len(r.recorder.recorded) - 1; i >= 0; i-- {
+		if rec := r.recorder.recorded[i]; roundID == "" || rec.RoundID == roundID {
+			return rec, nil
+		}
+	}
+	return nil, ErrReplayNotFound
+}
+
+// Replays describes the room's recorded rounds, newest first
+func (r *GameRoom) Replays() []ReplaySummary {
+	r.recorder.mu.Lock()
+	defer r.recorder.mu.Unlock()
+
+	summaries := make([]ReplaySummary, 0, len(r.recorder.recorded))
+	for i := len(r.recorder.recorded) - 1; i >= 0; i-- {
+		summaries = append(summaries, r.recorder.recorded[i].Summary())
+	}
+	return summaries
+}
+
+// handleListReplays lists the rounds a room has recorded
+func (s *Server) handleListReplays(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	room, exists := s.GetRoom(r.PathValue("id"))
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorData{Code: "room_not_found", Message: ErrRoomNotFound.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(room.Replays())
+}
+
+// handleGetReplay returns a recorded round with all its events; a round of
+// "last" is the room's last round
+func (s *Server) handleGetReplay(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	room, exists := s.GetRoom(r.PathValue("id"))
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorData{Code: "room_not_found", Message: ErrRoomNotFound.Error()})
+		return
+	}
+
+	roundID := r.PathValue("round")
+	if roundID == "last" {
+		roundID = ""
+	}
+	rec, err := room.Replay(roundID)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorData{Code: "replay_not_found", Message: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(rec)
+}
+
+// replayPlayback is a recorded round being played back to a client
+type replayPlayback struct {
+	roundID string
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// handleReplayRound starts playing back a recorded round to the client,
+// replacing any replay it was already watching
+func (c *Client) handleReplayRound(msg *Message) {
+	var data ReplayData
+	if err := msg.GetData(&data); err != nil {
+		c.sendError("invalid_data", "Invalid replay request")
+		return
+	}
+
+	roomID := msg.RoomID
+	if roomID == "" {
+		if c.room == nil {
+			c.sendError("not_in_room", "Not currently in a room")
+			return
+		}
+		roomID = c.room.ID()
+	}
+	room, exists := c.server.GetRoom(roomID)
+	if !exists {
+		c.sendError("replay_failed", ErrRoomNotFound.Error())
+		return
+	}
+	rec, err := room.Replay(data.RoundID)
+	if err != nil {
+		c.sendError("replay_not_found", err.Error())
+		return
+	}
+
+	c.stopReplay()
+
+	speed := data.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	summary := rec.Summary()
+	c.sendMessage(NewMessage(MsgReplayRound, rec.RoomID, c.playerID, ReplayData{
+		RoundID: rec.RoundID,
+		Speed:   speed,
+		FromMs:  data.FromMs,
+		Replay:  &summary,
+	}))
+
+	ctx, cancel := context.WithCancel(c.server.ctx)
+	playback := &replayPlayback{roundID: rec.RoundID, cancel: cancel, done: make(chan struct{})}
+	c.replay = playback
+	go c.playReplay(ctx, playback, rec, speed, time.Duration(data.FromMs)*time.Millisecond)
+
+	c.server.logger.Debug("Replaying round",
+		zap.String("player_id", c.playerID),
+		zap.String("room_id", rec.RoomID),
+		zap.String("round_id", rec.RoundID),
+		zap.Float64("speed", speed),
+	)
+}
+
+// handleStopReplay stops the replay the client is watching, if any
+func (c *Client) handleStopReplay() {
+	roundID, stopped := c.stopReplay()
+	if !stopped {
+		return
+	}
+	c.sendMessage(NewMessage(MsgReplayEnd, "", c.playerID, ReplayEndData{RoundID: roundID, Stopped: true}))
+}
+
+// stopReplay stops the replay the client is watching and waits for it to
+// stop sending, reporting which round it was. Replays are only started and
+// stopped by the client's read loop.
+func (c *Client) stopReplay() (roundID string, stopped bool) {
+	playback := c.replay
+	if playback == nil {
+		return "", false
+	}
+	c.replay = nil
+
+	playback.cancel()
+	<-playback.done
+	return playback.roundID, true
+}
+
+// playReplay sends a recording's events to the client, spaced out as they
+// happened divided by speed, then ends the replay. Events before from are
+// sent at once.
+func (c *Client) playReplay(ctx context.Context, playback *replayPlayback, rec *RoundRecording, speed float64, from time.Duration) {
+	defer close(playback.done)
+	defer playback.cancel()
+
+	clock := c.server.scheduler.Clock()
+	position := from
+	for _, event := range rec.Events {
+		offset := time.Duration(event.OffsetMs) * time.Millisecond
+		if wait := min(offset-position, maxReplayGap); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-clock.After(time.Duration(float64(wait) / speed)):
+			}
+		}
+		position = max(position, offset)
+
+		if !c.sendReplay(ctx, NewMessage(MsgReplayEvent, rec.RoomID, "", ReplayEventData{
+			RoundID:  rec.RoundID,
+			OffsetMs: event.OffsetMs,
+			Event:    event.Message.ForProtocol(c.protocol),
+		})) {
+			return
+		}
+	}
+	c.sendReplay(ctx, NewMessage(MsgReplayEnd, rec.RoomID, "", ReplayEndData{RoundID: rec.RoundID}))
+}
+
+// sendReplay queues a replay message for the client, waiting for room in
+// its send buffer rather than dropping the message, and reports whether it
+// was queued before the replay stopped
+func (c *Client) sendReplay(ctx context.Context, msg *Message) bool {
+	data, err := msg.ForProtocol(c.protocol).Encode(c.encoding)
+	if err != nil {
+		c.server.logger.Error("Failed to encode replay message", zap.Error(err))
+		return false
+	}
+
+	select {
+	case c.send <- data:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,219 @@
+package network
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+)
+
+func TestGameRoom_RecordsRounds(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.SetRandomGenerator(&fixedCoin{side: game.Heads, seed: "seed-1"})
+
+	// The round in progress cannot be replayed yet
+	_, err := room.Replay("")
+	assert.ErrorIs(t, err, ErrReplayNotFound)
+
+	fake.Advance(2 * time.Second)
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	fake.Advance(8 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+	fake.Advance(room.config.ResultDuration)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateWaiting, room.GetGameState())
+
+	rec, err := room.Replay("")
+	require.NoError(t, err)
+	assert.Equal(t, "room", rec.RoomID)
+	assert.Equal(t, (10*time.Second + room.config.ResultDuration).Milliseconds(), rec.DurationMs)
+
+	// The recording opens with the room as betting started
+	require.NotEmpty(t, rec.Events)
+	opening, ok := rec.Events[0].Message.Data.(*RoomUpdateData)
+	require.True(t, ok)
+	assert.Equal(t, StateBetting, opening.GameState)
+	require.Len(t, opening.Players, 1)
+	assert.Equal(t, "p1", opening.Players[0].ID)
+
+	offsets := make(map[MessageType]int64)
+	for i, event := range rec.Events {
+		if i > 0 {
+			assert.GreaterOrEqual(t, event.OffsetMs, rec.Events[i-1].OffsetMs)
+		}
+		if update, ok := event.Message.Data.(*RoomUpdateData); ok {
+			assert.False(t, update.Delta, "room updates are recorded in full")
+		}
+		assert.Empty(t, event.Message.TraceParent)
+		if _, seen := offsets[event.Message.Type]; !seen {
+			offsets[event.Message.Type] = event.OffsetMs
+		}
+	}
+	assert.Equal(t, int64(2000), offsets[MsgBetPlaced])
+	assert.Equal(t, int64(10000), offsets[MsgGameResult])
+	assert.Equal(t, rec.RoundID, room.Replays()[0].RoundID)
+
+	// A round nobody bets in is not kept
+	fake.Advance(RoundBreakDuration)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateWaiting, room.GetGameState())
+
+	last, err := room.Replay("")
+	require.NoError(t, err)
+	assert.Equal(t, rec.RoundID, last.RoundID)
+	assert.Len(t, room.Replays(), 1)
+}
+
+// recordRound gives a room a finished recording of a round with a bet and
+// a result, the result lateMs into the round
+func recordRound(room *GameRoom, roundID string, lateMs int64) {
+	started := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(ms int64) time.Time { return started.Add(time.Duration(ms) * time.Millisecond) }
+
+	room.recorder.mu.Lock()
+	defer room.recorder.mu.Unlock()
+	room.recorder.recorded = append(room.recorder.recorded, &RoundRecording{
+		RoundID:    roundID,
+		RoomID:     room.ID(),
+		StartedAt:  started,
+		DurationMs: lateMs,
+		Events: []RecordedEvent{
+			{OffsetMs: 0, Message: &Message{Type: MsgBetPhase, RoomID: room.ID(), Timestamp: at(0),
+				Data: TimerData{Phase: StateBetting, SecondsLeft: 60, TotalSeconds: 60}}},
+			{OffsetMs: 40, Message: &Message{Type: MsgBetPlaced, RoomID: room.ID(), PlayerID: "p1", Timestamp: at(40),
+				Data: &BetData{PlayerID: "p1", Amount: 10 * game.Dollar, Choice: game.Heads}}},
+			{OffsetMs: lateMs, Message: &Message{Type: MsgGameResult, RoomID: room.ID(), Timestamp: at(lateMs),
+				Data: &GameResultData{RoundID: roundID, CoinResult: game.Tails}}},
+		},
+	})
+}
+
+func TestServer_ReplayRound(t *testing.T) {
+	server, url := startTestServer(t, DefaultServerConfig())
+	config := DefaultClientConfig()
+	config.ServerURL = url
+
+	player := NewNetworkClient(config, "p1", "Player 1", zap.NewNop())
+	defer player.Disconnect()
+	require.NoError(t, player.Connect())
+	require.NoError(t, player.JoinRoom("r1", 100*game.Dollar))
+	var room *GameRoom
+	waitFor(t, func() bool {
+		var ok bool
+		room, ok = server.GetRoom("r1")
+		return ok && len(room.GetPlayers()) == 1
+	})
+
+	// Nothing has been recorded yet
+	require.NoError(t, player.ReplayRound("", "", 0, 0))
+	var errorData ErrorData
+	require.NoError(t, nextEvent(t, player, MsgError).GetData(&errorData))
+	assert.Equal(t, "replay_not_found", errorData.Code)
+
+	recordRound(room, "round-1", 100)
+	recordRound(room, "round-2", 60*1000)
+
+	// A late joiner watches another room's first round four times faster
+	watcher := NewNetworkClient(config, "w1", "Late", zap.NewNop())
+	defer watcher.Disconnect()
+	require.NoError(t, watcher.Connect())
+	require.NoError(t, watcher.ReplayRound("r1", "round-1", 4, 0))
+
+	var started ReplayData
+	require.NoError(t, nextEvent(t, watcher, MsgReplayRound).GetData(&started))
+	assert.Equal(t, 4.0, started.Speed)
+	require.NotNil(t, started.Replay)
+	assert.Equal(t, "round-1", started.Replay.RoundID)
+	assert.Equal(t, 3, started.Replay.Events)
+
+	var types []MessageType
+	var bet BetData
+	for range 3 {
+		var event ReplayEventData
+		require.NoError(t, nextEvent(t, watcher, MsgReplayEvent).GetData(&event))
+		assert.Equal(t, "round-1", event.RoundID)
+		types = append(types, event.Event.Type)
+		if event.Event.Type == MsgBetPlaced {
+			require.NoError(t, event.Event.GetData(&bet))
+		}
+	}
+	assert.Equal(t, []MessageType{MsgBetPhase, MsgBetPlaced, MsgGameResult}, types)
+	assert.Equal(t, 10*game.Dollar, bet.Amount)
+
+	var ended ReplayEndData
+	require.NoError(t, nextEvent(t, watcher, MsgReplayEnd).GetData(&ended))
+	assert.Equal(t, "round-1", ended.RoundID)
+	assert.False(t, ended.Stopped)
+
+	// The last round, in the player's own room, waits on its result until
+	// stopped
+	require.NoError(t, player.ReplayRound("", "", 1, 0))
+	require.NoError(t, nextEvent(t, player, MsgReplayRound).GetData(&started))
+	assert.Equal(t, "round-2", started.Replay.RoundID)
+	nextEvent(t, player, MsgReplayEvent)
+	nextEvent(t, player, MsgReplayEvent)
+	require.NoError(t, player.StopReplay())
+	require.NoError(t, nextEvent(t, player, MsgReplayEnd).GetData(&ended))
+	assert.Equal(t, "round-2", ended.RoundID)
+	assert.True(t, ended.Stopped)
+
+	// Starting at the result sends everything before it at once
+	require.NoError(t, player.ReplayRound("", "round-2", 1, time.Minute))
+	nextEvent(t, player, MsgReplayRound)
+	var event ReplayEventData
+	for range 3 {
+		require.NoError(t, nextEvent(t, player, MsgReplayEvent).GetData(&event))
+	}
+	assert.Equal(t, int64(60*1000), event.OffsetMs)
+	nextEvent(t, player, MsgReplayEnd)
+
+	// Too fast a replay is refused
+	require.NoError(t, player.ReplayRound("", "", MaxReplaySpeed+1, 0))
+	require.NoError(t, nextEvent(t, player, MsgError).GetData(&errorData))
+	assert.Equal(t, "invalid_data", errorData.Code)
+}
+
+func TestServer_ReplayAPI(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zap.NewNop())
+	defer server.Stop()
+	room, err := server.CreateRoom("r1", "Room 1", DefaultRoomConfig())
+	require.NoError(t, err)
+	recordRound(room, "round-1", 100)
+	recordRound(room, "round-2", 200)
+	handler := server.Handler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/rooms/r1/replays", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var summaries []ReplaySummary
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&summaries))
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "round-2", summaries[0].RoundID)
+	assert.Equal(t, int64(200), summaries[0].DurationMs)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/rooms/r1/replays/last", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rec RoundRecording
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rec))
+	assert.Equal(t, "round-2", rec.RoundID)
+	require.Len(t, rec.Events, 3)
+	assert.Equal(t, MsgGameResult, rec.Events[2].Message.Type)
+
+	for _, target := range []string{"/rooms/r1/replays/round-9", "/rooms/r9/replays"} {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code, target)
+	}
+}
@@ -0,0 +1,287 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+)
+
+// Permission errors
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrRoomLimit        = errors.New("room limit reached")
+	ErrKickSelf         = errors.New("players cannot kick themselves")
+	// ErrKickByAdmin is returned for kicks sent over a connection, which
+	// cannot prove which player it acts for
+	ErrKickByAdmin = errors.New("players are kicked through the admin API")
+)
+
+// Permission is a server operation a role may be allowed
+type Permission string
+
+// Permissions
+const (
+	PermCreateRoom   Permission = "create_room"
+	PermKick         Permission = "kick"
+	PermChangeConfig Permission = "change_config"
+)
+
+// DefaultPlayerRoomLimit is how many rooms a player may have opened that
+// are still open, and DefaultModeratorRoomLimit the same for moderators
+const (
+	DefaultPlayerRoomLimit    = 3
+	DefaultModeratorRoomLimit = 20
+)
+
+// RolePolicy is what players holding a role may do
+type RolePolicy struct {
+	// CreateRooms allows opening rooms, explicitly or by joining one that
+	// does not exist; MaxRooms caps how many a player may have open at
+	// once, zero meaning no cap
+	CreateRooms bool `json:"create_rooms"`
+	MaxRooms    int  `json:"max_rooms,omitempty"`
+	// Kick allows removing players of a lower role from rooms
+	Kick bool `json:"kick"`
+	// ChangeConfig allows proposing and voting on room settings
+	ChangeConfig bool `json:"change_config"`
+}
+
+// Allows reports whether the policy grants a permission
+func (p RolePolicy) Allows(permission Permission) bool {
+	switch permission {
+	case PermCreateRoom:
+		return p.CreateRooms
+	case PermKick:
+		return p.Kick
+	case PermChangeConfig:
+		return p.ChangeConfig
+	}
+	return false
+}
+
+// DefaultRolePolicies returns what each role may do unless the server is
+// configured otherwise. Bots play in rooms others open and leave their
+// settings alone.
+func DefaultRolePolicies() map[game.Role]RolePolicy {
+	return map[game.Role]RolePolicy{
+		game.RoleAdmin:     {CreateRooms: true, Kick: true, ChangeConfig: true},
+		game.RoleModerator: {CreateRooms: true, MaxRooms: DefaultModeratorRoomLimit, Kick: true, ChangeConfig: true},
+		game.RolePlayer:    {CreateRooms: true, MaxRooms: DefaultPlayerRoomLimit, ChangeConfig: true},
+		game.RoleBot:       {},
+	}
+}
+
+// RoleData is a player's role, as the admin API reports and takes it
+type RoleData struct {
+	PlayerID string    `json:"player_id"`
+	Role     game.Role `json:"role"`
+}
+
+// Role returns the role on a player's record; players the server has no
+// record of are players
+func (s *Server) Role(ctx context.Context, playerID string) game.Role {
+	player, err := s.results.GetPlayer(ctx, playerID)
+	if err != nil {
+		return game.RolePlayer
+	}
+	return player.EffectiveRole()
+}
+
+// SetRole gives a player the server knows a role, recording it on their
+// player record so it lasts as long as the account
+func (s *Server) SetRole(ctx context.Context, playerID string, role game.Role) error {
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	player, err := s.results.GetPlayer(ctx, playerID)
+	if err != nil {
+		return ErrPlayerNotFound
+	}
+	if err := player.SetRole(role); err != nil {
+		return err
+	}
+	if err := s.results.SavePlayer(ctx, player); err != nil {
+		return err
+	}
+
+	s.logger.Info("Player role changed",
+		zap.String("player_id", playerID),
+		zap.String("role", string(role)),
+	)
+	s.config.Audit.Record(logger.AuditEvent{
+		Time:     s.scheduler.Clock().Now(),
+		Event:    logger.AuditRole,
+		PlayerID: playerID,
+		Reason:   "role set to " + string(role),
+	})
+	return nil
+}
+
+// policy returns what a role may do
+func (s *Server) policy(role game.Role) RolePolicy {
+	policies := s.config.RolePolicies
+	if policies == nil {
+		policies = DefaultRolePolicies()
+	}
+	return policies[role]
+}
+
+// authorize checks the role a player acts with grants a permission.
+// Clients acting for no player are allowed nothing.
+func (s *Server) authorize(playerID string, role game.Role, permission Permission) error {
+	if playerID == "" {
+		return fmt.Errorf("%w: join a room first", ErrPermissionDenied)
+	}
+	if !s.policy(role).Allows(permission) {
+		return fmt.Errorf("%w: %s role may not %s", ErrPermissionDenied, role, permissionText(permission))
+	}
+	return nil
+}
+
+// permissionText describes a permission for error messages
+func permissionText(permission Permission) string {
+	switch permission {
+	case PermCreateRoom:
+		return "create rooms"
+	case PermKick:
+		return "kick players"
+	case PermChangeConfig:
+		return "change room settings"
+	}
+	return string(permission)
+}
+
+// connectionRole returns the role a client acts with. A connection names
+// its player without proving it, so it is granted no more than a player's
+// role however the named player ranks; what moderators and admins may do
+// beyond that is done through the admin API. Roles below a player's, such
+// as bot, still apply.
+func (c *Client) connectionRole() game.Role {
+	role := c.server.Role(c.server.ctx, c.playerID)
+	if role.Outranks(game.RolePlayer) {
+		return game.RolePlayer
+	}
+	return role
+}
+
+// CreateRoomAs opens a room on a player's behalf, if their role allows
+// opening rooms and they have not reached their role's limit of open ones.
+// The caller vouches for playerID; connections open rooms with the role
+// connectionRole grants them.
+func (s *Server) CreateRoomAs(playerID, roomID, roomName string, config *RoomConfig) (*GameRoom, error) {
+	return s.createRoomAs(playerID, s.Role(s.ctx, playerID), roomID, roomName, config)
+}
+
+// createRoomAs opens a room on behalf of a player acting with role
+func (s *Server) createRoomAs(playerID string, role game.Role, roomID, roomName string, config *RoomConfig) (*GameRoom, error) {
+	if err := s.authorize(playerID, role, PermCreateRoom); err != nil {
+		return nil, err
+	}
+	limit := s.policy(role).MaxRooms
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if limit > 0 {
+		open := 0
+		for _, room := range s.rooms {
+			if room.createdBy == playerID {
+				open++
+			}
+		}
+		if open >= limit {
+			return nil, fmt.Errorf("%w: close one of your %d open rooms first", ErrRoomLimit, open)
+		}
+	}
+	return s.createRoom(playerID, roomID, roomName, config)
+}
+
+// KickAs removes a player from a room on a moderator's behalf, for callers
+// that vouch for moderatorID. Nobody may kick a player of their own role
+// or above, so moderators cannot remove each other or an admin.
+func (s *Server) KickAs(moderatorID, roomID, playerID string) error {
+	if moderatorID == playerID {
+		return ErrKickSelf
+	}
+	role, target := s.Role(s.ctx, moderatorID), s.Role(s.ctx, playerID)
+	if err := s.authorize(moderatorID, role, PermKick); err != nil {
+		return err
+	}
+	if !role.Outranks(target) {
+		return fmt.Errorf("%w: a %s cannot kick a %s", ErrPermissionDenied, role, target)
+	}
+
+	if err := s.KickPlayer(roomID, playerID); err != nil {
+		return err
+	}
+	s.logger.Info("Player kicked by moderator",
+		zap.String("room_id", roomID),
+		zap.String("player_id", playerID),
+		zap.String("moderator_id", moderatorID),
+	)
+	return nil
+}
+
+// handleKickPlayer refuses a kick sent over a connection. No role a
+// connection acts with may kick, so it is told to use the admin API.
+func (c *Client) handleKickPlayer(msg *Message) {
+	c.sendError("kick_failed", ErrKickByAdmin.Error())
+}
+
+// handleGetRole returns a player's role
+func (s *Server) handleGetRole(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	playerID := r.PathValue("player")
+	json.NewEncoder(w).Encode(RoleData{PlayerID: playerID, Role: s.Role(r.Context(), playerID)})
+}
+
+// handleSetRole gives a player the role in the JSON body
+func (s *Server) handleSetRole(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var data RoleData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{Code: "invalid_data", Message: "Invalid role data"})
+		return
+	}
+	s.writeRole(w, r, data.Role)
+}
+
+// handleResetRole makes a player a plain player again
+func (s *Server) handleResetRole(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	s.writeRole(w, r, game.RolePlayer)
+}
+
+// writeRole sets the path's player to role and reports the result
+func (s *Server) writeRole(w http.ResponseWriter, r *http.Request, role game.Role) {
+	playerID := r.PathValue("player")
+	if reason := checkID(playerID); reason != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{Code: "invalid_player", Message: "player ID " + reason})
+		return
+	}
+
+	if err := s.SetRole(r.Context(), playerID, role); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrPlayerNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, game.ErrInvalidRole), errors.Is(err, game.ErrRoleGuest):
+			status = http.StatusBadRequest
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorData{Code: "role_failed", Message: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(RoleData{PlayerID: playerID, Role: role})
+}
@@ -0,0 +1,214 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+// newRolesServer returns a server with players of every role on record
+func newRolesServer(t *testing.T, config *ServerConfig) *Server {
+	t.Helper()
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+
+	ctx := context.Background()
+	for _, role := range game.Roles {
+		require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: string(role)}))
+		require.NoError(t, server.SetRole(ctx, string(role), role))
+	}
+	return server
+}
+
+func TestServer_SetRole(t *testing.T) {
+	server := newRolesServer(t, DefaultServerConfig())
+	ctx := context.Background()
+
+	assert.Equal(t, game.RoleModerator, server.Role(ctx, "moderator"))
+	assert.Equal(t, game.RolePlayer, server.Role(ctx, "stranger"), "players without a record are players")
+
+	assert.ErrorIs(t, server.SetRole(ctx, "player", "owner"), game.ErrInvalidRole)
+	assert.ErrorIs(t, server.SetRole(ctx, "stranger", game.RoleAdmin), ErrPlayerNotFound)
+	guest := game.GuestIDPrefix + "1"
+	require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: guest}))
+	assert.ErrorIs(t, server.SetRole(ctx, guest, game.RoleAdmin), game.ErrRoleGuest)
+
+	// Roles are kept on the player record without touching the rest of it
+	require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: "alice", Balance: 42 * game.Dollar}))
+	require.NoError(t, server.SetRole(ctx, "alice", game.RoleBot))
+	player, err := server.results.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, game.RoleBot, player.Role)
+	assert.Equal(t, 42*game.Dollar, player.Balance)
+}
+
+func TestServer_CreateRoomAsEnforcesLimits(t *testing.T) {
+	config := DefaultServerConfig()
+	config.RolePolicies = DefaultRolePolicies()
+	config.RolePolicies[game.RolePlayer] = RolePolicy{CreateRooms: true, MaxRooms: 2}
+	server := newRolesServer(t, config)
+
+	_, err := server.CreateRoomAs("bot", "b1", "Bot Room", nil)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	for _, id := range []string{"r1", "r2"} {
+		_, err := server.CreateRoomAs("player", id, "Room", nil)
+		require.NoError(t, err)
+	}
+	_, err = server.CreateRoomAs("player", "r3", "Room", nil)
+	assert.ErrorIs(t, err, ErrRoomLimit)
+
+	// Rooms the server opens itself count against nobody, and closing a
+	// room frees its place
+	_, err = server.CreateRoom("lobby", "Lobby", nil)
+	require.NoError(t, err)
+	require.NoError(t, server.CloseRoom("r1"))
+	_, err = server.CreateRoomAs("player", "r3", "Room", nil)
+	assert.NoError(t, err)
+
+	// Admins have no cap
+	for _, id := range []string{"a1", "a2", "a3"} {
+		_, err := server.CreateRoomAs("admin", id, "Room", nil)
+		require.NoError(t, err)
+	}
+}
+
+func TestServer_KickAsFollowsRank(t *testing.T) {
+	server := newRolesServer(t, DefaultServerConfig())
+	room, err := server.CreateRoom("r1", "Room 1", nil)
+	require.NoError(t, err)
+	for _, role := range game.Roles {
+		require.NoError(t, room.AddPlayer(string(role), string(role), 100*game.Dollar))
+	}
+
+	assert.ErrorIs(t, server.KickAs("player", "r1", "bot"), ErrPermissionDenied, "players cannot kick")
+	assert.ErrorIs(t, server.KickAs("moderator", "r1", "admin"), ErrPermissionDenied, "nor anyone above them")
+	assert.ErrorIs(t, server.KickAs("moderator", "r1", "moderator"), ErrKickSelf)
+
+	require.NoError(t, server.KickAs("moderator", "r1", "player"))
+	require.NoError(t, server.KickAs("admin", "r1", "moderator"))
+	assert.NotContains(t, room.GetPlayers(), "player")
+	assert.NotContains(t, room.GetPlayers(), "moderator")
+	assert.Contains(t, room.GetPlayers(), "admin")
+}
+
+func TestClient_ConnectionsActAsPlayers(t *testing.T) {
+	server := newRolesServer(t, DefaultServerConfig())
+	room, err := server.CreateRoom("r1", "Room 1", nil)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("player", "Player", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("bot", "Bot", 100*game.Dollar))
+	errorCode := func(client *Client) string {
+		var msg Message
+		require.NoError(t, json.Unmarshal(<-client.send, &msg))
+		require.Equal(t, MsgError, msg.Type)
+		var reply ErrorData
+		require.NoError(t, msg.GetData(&reply))
+		return reply.Code
+	}
+
+	// A client that has joined nothing cannot borrow an admin's role by
+	// naming them on its messages
+	client := &Client{server: server, send: make(chan []byte, 1), protocol: ProtocolVersion}
+	client.handleKickPlayer(NewMessage(MsgKickPlayer, "r1", "admin", KickData{PlayerID: "player"}))
+	assert.Equal(t, "kick_failed", errorCode(client))
+	assert.Contains(t, room.GetPlayers(), "player")
+
+	client.handleCreateRoom(NewMessage(MsgCreateRoom, "r2", "admin", RoomCreateData{}))
+	assert.Equal(t, "room_creation_failed", errorCode(client))
+	_, exists := server.GetRoom("r2")
+	assert.False(t, exists)
+
+	// Nor by acting as one, since nothing proves a connection is the
+	// player it names: it kicks nobody and opens rooms as a player does
+	client = &Client{server: server, send: make(chan []byte, 1), protocol: ProtocolVersion, playerID: "admin", room: room}
+	client.handleKickPlayer(NewMessage(MsgKickPlayer, "r1", "admin", KickData{PlayerID: "bot"}))
+	assert.Equal(t, "kick_failed", errorCode(client))
+	assert.Contains(t, room.GetPlayers(), "bot")
+	for i := 1; i <= DefaultPlayerRoomLimit; i++ {
+		client.handleCreateRoom(NewMessage(MsgCreateRoom, fmt.Sprintf("admin-%d", i), "admin", RoomCreateData{}))
+		<-client.send
+	}
+	client.handleCreateRoom(NewMessage(MsgCreateRoom, "admin-more", "admin", RoomCreateData{}))
+	assert.Equal(t, "room_creation_failed", errorCode(client))
+
+	// Roles that restrict still do
+	assert.Equal(t, game.RoleBot, (&Client{server: server, playerID: "bot"}).connectionRole())
+	assert.ErrorIs(t, server.authorize("", game.RoleAdmin, PermCreateRoom), ErrPermissionDenied)
+}
+
+func TestServer_ConfigProposalNeedsPermission(t *testing.T) {
+	server := newRolesServer(t, DefaultServerConfig())
+	room, err := server.CreateRoom("r1", "Room 1", nil)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("bot", "Bot", 100*game.Dollar))
+
+	client := &Client{server: server, send: make(chan []byte, 1), protocol: ProtocolVersion, playerID: "bot", room: room}
+	client.handleConfigProposal(NewMessage(MsgConfigProposal, "r1", "bot", ConfigProposalData{
+		Settings: &RoomSettings{MaxPlayers: 4},
+	}))
+
+	var msg Message
+	require.NoError(t, json.Unmarshal(<-client.send, &msg))
+	require.Equal(t, MsgError, msg.Type)
+	var reply ErrorData
+	require.NoError(t, msg.GetData(&reply))
+	assert.Equal(t, "permission_denied", reply.Code)
+	assert.Nil(t, room.proposal)
+}
+
+func TestServer_RoleAdminAPI(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Admin = AdminCredentials{Username: "admin", Password: "secret"}
+	server := newRolesServer(t, config)
+	require.NoError(t, server.results.SavePlayer(context.Background(), &game.Player{ID: "alice"}))
+	handler := server.Handler()
+
+	request := func(method, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, "/admin/roles/alice", strings.NewReader(body))
+		request.SetBasicAuth("admin", "secret")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+	role := func(recorder *httptest.ResponseRecorder) game.Role {
+		var data RoleData
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&data))
+		return data.Role
+	}
+
+	recorder := request(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, game.RolePlayer, role(recorder))
+
+	recorder = request(http.MethodPut, `{"role":"moderator"}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, game.RoleModerator, role(recorder))
+	assert.Equal(t, game.RoleModerator, server.Role(context.Background(), "alice"))
+
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPut, `{"role":"owner"}`).Code)
+	missing := httptest.NewRequest(http.MethodPut, "/admin/roles/nobody", strings.NewReader(`{"role":"admin"}`))
+	missing.SetBasicAuth("admin", "secret")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, missing)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = request(http.MethodDelete, "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, game.RolePlayer, server.Role(context.Background(), "alice"))
+
+	// Without credentials nobody can hand out roles
+	unauthorized := httptest.NewRecorder()
+	handler.ServeHTTP(unauthorized, httptest.NewRequest(http.MethodPut, "/admin/roles/alice", strings.NewReader(`{"role":"admin"}`)))
+	assert.Equal(t, http.StatusUnauthorized, unauthorized.Code)
+}
@@ -2,17 +2,21 @@
 package network
 
 import (
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
+	"coinflip-game/internal/clock"
 	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+	"coinflip-game/internal/storage"
+	"coinflip-game/internal/tracing"
 )
 
 // Room constants
@@ -21,6 +25,7 @@ const (
 	DefaultMaxPlayers    = 8
 	BettingPhaseDuration = 60 * time.Second
 	ResultPhaseDuration  = 10 * time.Second
+	RoundBreakDuration   = 2 * time.Second
 	DefaultRoomTimeout   = 30 * time.Minute
 )
 
@@ -28,10 +33,16 @@ const (
 var (
 	ErrRoomFull        = errors.New("room is full")
 	ErrRoomNotFound    = errors.New("room not found")
+	ErrRoomClosed      = errors.New("room has been closed")
 	ErrPlayerNotFound  = errors.New("player not found in room")
 	ErrInvalidGamePhase = errors.New("invalid action for current game phase")
 	ErrBettingClosed   = errors.New("betting phase has ended")
-	ErrPlayerAlreadyBet = errors.New("player has already placed a bet this round")
+	ErrPlayerAlreadyBet = errors.New("player has already bet on that side this round")
+	ErrInvalidRoomConfig = errors.New("invalid room configuration")
+	ErrNoActiveRound   = errors.New("no active round")
+	ErrNoBetToCancel   = errors.New("player has no bet this round")
+	ErrNoQueuedBet     = errors.New("player has no bet queued for the next round")
+	ErrPracticeMixed   = errors.New("practice and real bets cannot be mixed in one round")
 )
 
 // GameRoom represents a multiplayer game room
@@ -44,15 +55,90 @@ type GameRoom struct {
 	currentRound  *GameRound
 	config        *RoomConfig
 	logger        *zap.Logger
-	
-	// Game timer
-	timer         *time.Timer
+	audit         *logger.AuditLogger
+	
+	// Server-wide ledger of what open rounds could pay out
+	liability     *LiabilityLedger
+	
+	// Where settled rounds are persisted, if anywhere
+	rounds        storage.RoundRepository
+	
+	// Source of each round's final seed and coin result
+	rng           game.RandomGenerator
+	
+	// Settings vote in progress, and settings agreed during a round that
+	// apply once it ends
+	proposal      *configProposal
+	pendingConfig *RoomConfig
+	
+	// The player who opened the room, whose vote decides pause requests
+	owner         string
+	
+	// The player whose request created the room, counted against their
+	// role's room limit; empty for rooms the server opened itself. Set
+	// before the room is registered and never changed.
+	createdBy     string
+	
+	// Pausing: the vote in progress, a pause agreed during the result
+	// phase that holds the room once the round ends, who paused it (empty
+	// when it ran short of players), and the phase and betting time left
+	// to resume with
+	pauseVote       *pauseProposal
+	pausePending    string
+	pausedBy        string
+	pausedPhase     GameState
+	pausedRemaining time.Duration
+	
+	// Bets players queued for the next betting phase, one per player
+	queuedBets    map[string]*BetData
+	// Bets players staged for the next rounds, one per round in order
+	betSlips      map[string][]*BetData
+	// What players who left a freeroll kept of its stack, to be seated
+	// with again on their return
+	freerollStacks map[string]game.Money
+	
+	// Spectators waiting for a seat while the room is full, first in line
+	// first; free seats are held for them
+	seatQueue     []QueuedPlayer
+	
+	// The happy hour boosting the room's payouts, if any
+	promotion     *Promotion
+	
+	// Game timer, driven by the shared scheduler and its clock
+	scheduler     *TimerScheduler
+	clock         clock.Clock
 	timerEnd      time.Time
+	// bettingGrace extends the current betting phase past timerEnd for
+	// players on slow connections
+	bettingGrace  time.Duration
+	
+	// Room updates: the players and state last broadcast, whether a
+	// coalesced update is waiting to go out, and how many deltas were sent
+	// since the last full update
+	sentPlayers   map[string]PlayerInfo
+	sentState     GameState
+	updatePending bool
+	sinceFull     int
+	
+	// Statistics since the room opened, and whether a broadcast of them is
+	// waiting for the stats interval to pass
+	stats         RoomStatsData
+	statsPending  bool
+	
+	// roundCtx holds the span tracing the current round, nil between rounds
+	roundCtx      context.Context
+	
+	// Recordings of the current round and the last few, for replays
+	recorder      roundRecorder
 	
 	// Event channels
 	eventChan     chan *Message
 	stopChan      chan struct{}
 	
+	// closed is set once the room is stopped; closeReason says why
+	closed        bool
+	closeReason   string
+	
 	// Game statistics
 	totalRounds   int
 	createdAt     time.Time
@@ -63,39 +149,105 @@ type GameRoom struct {
 type RoomPlayer struct {
 	ID           string
 	Name         string
-	Balance      float64
+	Balance      game.Money
 	IsReady      bool
 	IsOnline     bool
 	LastSeen     time.Time
-	CurrentBet   *BetData
+	CurrentBets  []*BetData
 	TotalGames   int
 	TotalWins    int
-	NetProfit    float64
+	NetProfit    game.Money
+	// WinStreak counts consecutive winning rounds; LongestStreak is the best
+	WinStreak     int
+	LongestStreak int
+	// Skin is the coin skin the player shows to the room
+	Skin          string
+	// Latency is the round trip time last measured to the player's client
+	Latency       time.Duration
+	// Practice is the ledger of the player's practice bets
+	Practice      game.Stats
+	// MissedRounds counts the rounds in a row settled without a bet from
+	// the player; Idle players no longer count towards MinPlayers
+	MissedRounds  int
+	Idle          bool
+	// Rating is the player's duel rating, set in duel rooms only
+	Rating        int
 }
 
 // GameRound represents a single game round
 type GameRound struct {
 	ID           string
 	StartTime    time.Time
-	Bets         map[string]*BetData
+	// Bets holds each player's positions, at most one per side
+	Bets         map[string][]*BetData
 	SeedCommits  map[string]string
 	SeedReveals  map[string]string
 	FinalSeed    string
 	CoinResult   game.Side
 	Results      map[string]*PlayerResult
 	State        GameState
+	// Pool is how a parimutuel round's pool was split, once flipped
+	Pool         *game.PoolSettlement
+	// Boost is the happy hour boost the round's winning bets are paid
+	Boost        float64
 }
 
 // RoomConfig contains room configuration
 type RoomConfig struct {
 	MinPlayers       int
 	MaxPlayers       int
-	MinBet           float64
-	MaxBet           float64
+	MinBet           game.Money
+	MaxBet           game.Money
 	PayoutRatio      float64
 	BettingDuration  time.Duration
 	ResultDuration   time.Duration
 	RequireConsensus bool
+	// UpdateInterval coalesces room updates: player changes within it go
+	// out as one update. Zero sends every change at once.
+	UpdateInterval time.Duration
+	// StreakBonus is added to the payout multiplier of a player's winning
+	// bet per consecutive round they won before it; zero disables it.
+	// MaxStreakMultiplier caps the multiplier, zero meaning no cap.
+	StreakBonus         float64
+	MaxStreakMultiplier float64
+	// Insurance is offered with every bet when enabled
+	Insurance game.Insurance
+	// MaxLatencyGrace caps how long betting stays open past its deadline
+	// so the slowest player's last bet still arrives; zero disables it
+	MaxLatencyGrace time.Duration
+	// Limits are the operator's caps on a round's pot and payout; players
+	// cannot change them through room settings
+	Limits BetLimits
+	// Mode is the room's style of play, classic when empty
+	Mode RoomMode
+	// Private rooms are left out of the room list; players join them by ID
+	// or through a friend's invite
+	Private bool
+	// EarlyCloseEnabled ends betting EarlyCloseDelay after every connected
+	// player has bet, rather than waiting out BettingDuration
+	EarlyCloseEnabled bool
+	EarlyCloseDelay   time.Duration
+	// Rake is the share of a parimutuel pool the house keeps
+	Rake float64
+	// Turbo rooms play short rounds with fixed phase lengths
+	Turbo bool
+	// Locale is the language the room's announcements and errors are in,
+	// one of SupportedLocales; English when empty
+	Locale string
+	// IdleRounds marks a player idle after that many rounds in a row
+	// without a bet; IdleReleaseRounds, if set, gives their seat to the
+	// queue after that many. Players are warned a round before each.
+	IdleRounds        int
+	IdleReleaseRounds int
+	// CountdownInterval is how often betting countdown updates go out;
+	// zero uses the scheduler's interval
+	CountdownInterval time.Duration
+	// StatsInterval is how often the room's statistics go out while
+	// rounds are played; zero sends them after every round
+	StatsInterval time.Duration
+	// Freeroll seats players with a stack of the room's own instead of
+	// their bankroll when its Stack is set
+	Freeroll Freeroll
 }
 
 // DefaultRoomConfig returns default room configuration
@@ -103,37 +255,251 @@ func DefaultRoomConfig() *RoomConfig {
 	return &RoomConfig{
 		MinPlayers:       DefaultMinPlayers,
 		MaxPlayers:       DefaultMaxPlayers,
-		MinBet:           1.0,
-		MaxBet:           100.0,
+		MinBet:           game.Dollar,
+		MaxBet:           100 * game.Dollar,
 		PayoutRatio:      2.0,
 		BettingDuration:  BettingPhaseDuration,
 		ResultDuration:   ResultPhaseDuration,
 		RequireConsensus: true,
+		MaxLatencyGrace:  DefaultMaxLatencyGrace,
+		UpdateInterval:   DefaultUpdateInterval,
+		EarlyCloseDelay:  DefaultEarlyCloseDelay,
+		StatsInterval:    DefaultStatsInterval,
+	}
+}
+
+// WithSettings returns a copy of the config with the non-zero settings
+// applied, brought within the operator's Limits. Insurance the settings
+// change must not favour players.
+func (c *RoomConfig) WithSettings(settings *RoomSettings) (*RoomConfig, error) {
+	merged := *c
+	if settings == nil {
+		return &merged, merged.Validate()
+	}
+	
+	if settings.MinPlayers > 0 {
+		merged.MinPlayers = settings.MinPlayers
+	}
+	if settings.MaxPlayers > 0 {
+		merged.MaxPlayers = settings.MaxPlayers
+	}
+	if settings.MinBet > 0 {
+		merged.MinBet = settings.MinBet
+	}
+	if settings.MaxBet > 0 {
+		merged.MaxBet = settings.MaxBet
+	}
+	if settings.PayoutRatio > 0 {
+		merged.PayoutRatio = settings.PayoutRatio
+	}
+	if settings.BettingSeconds > 0 {
+		merged.BettingDuration = time.Duration(settings.BettingSeconds) * time.Second
+	}
+	if settings.ResultSeconds > 0 {
+		merged.ResultDuration = time.Duration(settings.ResultSeconds) * time.Second
+	}
+	if settings.StreakBonus > 0 {
+		merged.StreakBonus = settings.StreakBonus
+	}
+	if settings.MaxStreakMultiplier > 0 {
+		merged.MaxStreakMultiplier = settings.MaxStreakMultiplier
+	}
+	if settings.InsuranceCost > 0 {
+		merged.Insurance.Cost = settings.InsuranceCost
+	}
+	if settings.InsuranceCoverage > 0 {
+		merged.Insurance.Coverage = settings.InsuranceCoverage
+	}
+	if settings.Mode != "" {
+		merged.applyMode(RoomMode(settings.Mode))
+	}
+	if settings.Private {
+		merged.Private = true
+	}
+	if settings.EarlyClose {
+		merged.EarlyCloseEnabled = true
+	}
+	if settings.EarlyCloseSeconds > 0 {
+		merged.EarlyCloseDelay = time.Duration(settings.EarlyCloseSeconds) * time.Second
+	}
+	if settings.Rake > 0 {
+		merged.Rake = settings.Rake
+	}
+	if settings.Locale != "" {
+		merged.Locale = settings.Locale
+	}
+	if settings.IdleRounds > 0 {
+		merged.IdleRounds = settings.IdleRounds
+	}
+	if settings.IdleReleaseRounds > 0 {
+		merged.IdleReleaseRounds = settings.IdleReleaseRounds
+	}
+	if settings.StartingStack > 0 {
+		merged.Freeroll.Stack = settings.StartingStack
+	}
+	// Last, so the turbo phase lengths win over any chosen above
+	if settings.Turbo || merged.Turbo {
+		merged.applyTurbo()
+	}
+	merged.Limits.clamp(&merged)
+	
+	// Players may not price insurance in their own favour
+	if insurance := merged.Insurance; insurance != c.Insurance && insurance.ExpectedValue(1) > 0 {
+		return nil, fmt.Errorf("%w: insurance covering %.2f of the stake must cost at least half that, %.2f, got %.2f",
+			ErrInvalidRoomConfig, insurance.Coverage, insurance.Coverage/2, insurance.Cost)
+	}
+	
+	return &merged, merged.Validate()
+}
+
+// Settings returns the config expressed as wire-level room settings
+func (c *RoomConfig) Settings() *RoomSettings {
+	return &RoomSettings{
+		MinPlayers:     c.MinPlayers,
+		MaxPlayers:     c.MaxPlayers,
+		MinBet:         c.MinBet,
+		MaxBet:         c.MaxBet,
+		PayoutRatio:    c.PayoutRatio,
+		BettingSeconds: int(c.BettingDuration.Seconds()),
+		ResultSeconds:  int(c.ResultDuration.Seconds()),
+		StreakBonus:         c.StreakBonus,
+		MaxStreakMultiplier: c.MaxStreakMultiplier,
+		InsuranceCost:       c.Insurance.Cost,
+		InsuranceCoverage:   c.Insurance.Coverage,
+		Mode:                string(c.Mode),
+		Private:             c.Private,
+		EarlyClose:          c.EarlyCloseEnabled,
+		EarlyCloseSeconds:   int(c.EarlyCloseDelay.Seconds()),
+		Rake:                c.Rake,
+		Turbo:               c.Turbo,
+		Locale:              c.Locale,
+		IdleRounds:          c.IdleRounds,
+		IdleReleaseRounds:   c.IdleReleaseRounds,
+		StartingStack:       c.Freeroll.Stack,
+		FreerollRate:        c.Freeroll.ConversionRate,
 	}
 }
 
-// NewGameRoom creates a new game room
-func NewGameRoom(id, name string, config *RoomConfig, logger *zap.Logger) *GameRoom {
+// Validate checks that the room configuration is consistent
+func (c *RoomConfig) Validate() error {
+	if c.MinPlayers < 1 || c.MaxPlayers < c.MinPlayers {
+		return fmt.Errorf("%w: players must satisfy 1 <= min (%d) <= max (%d)",
+			ErrInvalidRoomConfig, c.MinPlayers, c.MaxPlayers)
+	}
+	if c.MinBet <= 0 || c.MaxBet < c.MinBet {
+		return fmt.Errorf("%w: bets must satisfy 0 < min (%s) <= max (%s)",
+			ErrInvalidRoomConfig, c.MinBet, c.MaxBet)
+	}
+	if c.PayoutRatio <= 1.0 {
+		return fmt.Errorf("%w: payout ratio must be greater than 1.0, got %.2f",
+			ErrInvalidRoomConfig, c.PayoutRatio)
+	}
+	if c.BettingDuration <= 0 || c.ResultDuration <= 0 {
+		return fmt.Errorf("%w: phase durations must be positive", ErrInvalidRoomConfig)
+	}
+	if c.StreakBonus < 0 || (c.MaxStreakMultiplier != 0 && c.MaxStreakMultiplier < 1) {
+		return fmt.Errorf("%w: streak bonus must not be negative and its cap must be at least 1.0",
+			ErrInvalidRoomConfig)
+	}
+	if err := c.Insurance.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRoomConfig, err)
+	}
+	if !c.Mode.Valid() {
+		return fmt.Errorf("%w: unknown room mode %q", ErrInvalidRoomConfig, c.Mode)
+	}
+	if err := game.ValidateRake(c.Rake); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRoomConfig, err)
+	}
+	if c.Mode.Pooled() && (c.StreakBonus > 0 || c.Insurance.Enabled()) {
+		return fmt.Errorf("%w: %s rooms pay only from the pool, without streak bonus or insurance", ErrInvalidRoomConfig, c.Mode)
+	}
+	if c.Mode == ModeDuel && (c.MinPlayers != DuelSeats || c.MaxPlayers != DuelSeats) {
+		return fmt.Errorf("%w: duel rooms seat exactly %d players", ErrInvalidRoomConfig, DuelSeats)
+	}
+	if err := c.Limits.Validate(c.MinBet); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRoomConfig, err)
+	}
+	if err := c.Freeroll.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRoomConfig, err)
+	}
+	if c.MaxLatencyGrace < 0 || c.MaxLatencyGrace >= c.BettingDuration {
+		return fmt.Errorf("%w: latency grace must be between 0 and the betting duration", ErrInvalidRoomConfig)
+	}
+	if c.UpdateInterval < 0 || c.UpdateInterval >= c.BettingDuration {
+		return fmt.Errorf("%w: update interval must be between 0 and the betting duration", ErrInvalidRoomConfig)
+	}
+	if c.EarlyCloseDelay < 0 || (c.EarlyCloseEnabled && c.EarlyCloseDelay > c.BettingDuration) {
+		return fmt.Errorf("%w: early close delay must be between 0 and the betting duration", ErrInvalidRoomConfig)
+	}
+	if c.CountdownInterval < 0 || c.CountdownInterval >= c.BettingDuration {
+		return fmt.Errorf("%w: countdown interval must be between 0 and the betting duration", ErrInvalidRoomConfig)
+	}
+	if c.StatsInterval < 0 {
+		return fmt.Errorf("%w: stats interval cannot be negative", ErrInvalidRoomConfig)
+	}
+	// A round of warning comes before each idle step
+	if c.IdleRounds < 0 || c.IdleRounds == 1 || c.IdleReleaseRounds < 0 ||
+		(c.IdleReleaseRounds > 0 && c.IdleReleaseRounds <= c.IdleRounds) {
+		return fmt.Errorf("%w: idle rounds must be 0 or at least 2, and seats released after more rounds than that", ErrInvalidRoomConfig)
+	}
+	if _, ok := NormalizeLocale(c.Locale); !ok {
+		return fmt.Errorf("%w: unsupported locale %q", ErrInvalidRoomConfig, c.Locale)
+	}
+	return nil
+}
+
+// NewGameRoom creates a new game room. Rooms normally share the server's
+// scheduler; if scheduler is nil the room runs a private one until stopped.
+func NewGameRoom(id, name string, config *RoomConfig, scheduler *TimerScheduler, logger *zap.Logger) *GameRoom {
 	if config == nil {
 		config = DefaultRoomConfig()
 	}
 	
+	stopChan := make(chan struct{})
+	if scheduler == nil {
+		scheduler = NewTimerScheduler(DefaultSchedulerResolution, DefaultCountdownInterval, nil)
+		go scheduler.Run(stopChan)
+	}
+	now := scheduler.Clock().Now()
+	
 	room := &GameRoom{
 		id:           id,
 		name:         name,
 		players:      make(map[string]*RoomPlayer),
+		queuedBets:   make(map[string]*BetData),
+		betSlips:     make(map[string][]*BetData),
+		freerollStacks: make(map[string]game.Money),
 		gameState:    StateWaiting,
 		config:       config,
+		scheduler:    scheduler,
+		clock:        scheduler.Clock(),
+		rng:          game.NewDefaultRandomGenerator(),
 		logger:       logger,
 		eventChan:    make(chan *Message, 100),
-		stopChan:     make(chan struct{}),
-		createdAt:    time.Now(),
-		lastActivity: time.Now(),
+		stopChan:     stopChan,
+		createdAt:    now,
+		lastActivity: now,
 	}
 	
 	return room
 }
 
+// SetRandomGenerator replaces the source of the room's final seeds and coin
+// results, which defaults to crypto/rand
+func (r *GameRoom) SetRandomGenerator(rng game.RandomGenerator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rng = rng
+}
+
+// SetAuditLogger records joins, leaves, bets, flips, payouts and refunds
+// in this room to an audit trail
+func (r *GameRoom) SetAuditLogger(audit *logger.AuditLogger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.audit = audit
+}
+
 // ID returns the room ID
 func (r *GameRoom) ID() string {
 	r.mu.RLock()
@@ -149,34 +515,62 @@ func (r *GameRoom) Name() string {
 }
 
 // AddPlayer adds a player to the room
-func (r *GameRoom) AddPlayer(playerID, playerName string, balance float64) error {
+func (r *GameRoom) AddPlayer(playerID, playerName string, balance game.Money) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
-	if len(r.players) >= r.config.MaxPlayers {
+	// The server may have removed the room since the player looked it up
+	if r.closed {
+		return ErrRoomClosed
+	}
+	
+	// A player restored from a snapshot gets their seat back as it was
+	if existing, exists := r.players[playerID]; exists && !existing.IsOnline {
+		r.reclaimSeat(existing, playerName)
+		r.removeFromQueue(playerID)
+		r.sentPlayers = nil // the returning player needs every seat
+		r.broadcastRoomUpdate()
+		r.checkAndStartGame()
+		return nil
+	}
+	
+	if len(r.players)+r.queuedAhead(playerID) >= r.config.MaxPlayers {
 		return ErrRoomFull
 	}
+	r.removeFromQueue(playerID)
 	
+	// Two players of the same name would be hard to tell apart
 	player := &RoomPlayer{
 		ID:       playerID,
-		Name:     playerName,
+		Name:     r.uniqueName(playerID, playerName),
 		Balance:  balance,
 		IsReady:  false,
 		IsOnline: true,
-		LastSeen: time.Now(),
+		LastSeen: r.clock.Now(),
 	}
 	
 	r.players[playerID] = player
-	r.lastActivity = time.Now()
+	r.lastActivity = r.clock.Now()
+	if r.owner == "" {
+		r.owner = playerID
+	}
 	
 	r.logger.Info("Player joined room",
 		zap.String("room_id", r.id),
 		zap.String("player_id", playerID),
-		zap.String("player_name", playerName),
+		zap.String("player_name", player.Name),
 		zap.Int("total_players", len(r.players)),
 	)
+	r.audit.Record(logger.AuditEvent{
+		Time:     r.clock.Now(),
+		Event:    logger.AuditJoin,
+		PlayerID: playerID,
+		RoomID:   r.id,
+		Balance:  balance.Float64(),
+	})
 	
-	// Send room update to all players
+	// Send room update to all players; the newcomer needs every seat
+	r.sentPlayers = nil
 	r.broadcastRoomUpdate()
 	
 	// Auto-start betting if we have enough players and game is waiting
@@ -187,59 +581,152 @@ func (r *GameRoom) AddPlayer(playerID, playerName string, balance float64) error
 
 // RemovePlayer removes a player from the room
 func (r *GameRoom) RemovePlayer(playerID string) error {
+	_, err := r.removePlayer(playerID)
+	return err
+}
+
+// removePlayer removes a player from the room and returns the balance they
+// leave with, after any open bets are refunded
+func (r *GameRoom) removePlayer(playerID string) (game.Money, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
 	player, exists := r.players[playerID]
 	if !exists {
-		return ErrPlayerNotFound
+		return 0, ErrPlayerNotFound
 	}
 	
-	// Cancel any active bet
-	if r.currentRound != nil && r.currentRound.Bets[playerID] != nil {
-		// Refund the bet
-		player.Balance += r.currentRound.Bets[playerID].Amount
+	// Cancel any active bet; bets are already settled once results are out
+	if r.currentRound != nil && r.gameState != StateResult {
+		// Refund the bets, insurance premiums included
+		for _, bet := range r.currentRound.Bets[playerID] {
+			if bet.Practice {
+				continue
+			}
+			player.Balance += betEscrow(bet)
+			r.audit.Record(logger.AuditEvent{
+				Time:     r.clock.Now(),
+				Event:    logger.AuditRefund,
+				PlayerID: playerID,
+				RoomID:   r.id,
+				RoundID:  r.currentRound.ID,
+				BetID:    bet.BetID,
+				Amount:   betCost(bet).Float64(),
+				Balance:  player.Balance.Float64(),
+				Reason:   "player left",
+			})
+		}
 		delete(r.currentRound.Bets, playerID)
+		r.trackLiability()
 	}
 	
 	delete(r.players, playerID)
-	r.lastActivity = time.Now()
+	delete(r.queuedBets, playerID)
+	delete(r.betSlips, playerID)
+	r.lastActivity = r.clock.Now()
 	
 	r.logger.Info("Player left room",
 		zap.String("room_id", r.id),
 		zap.String("player_id", playerID),
 		zap.Int("remaining_players", len(r.players)),
 	)
+	r.audit.Record(logger.AuditEvent{
+		Time:     r.clock.Now(),
+		Event:    logger.AuditLeave,
+		PlayerID: playerID,
+		RoomID:   r.id,
+		Balance:  player.Balance.Float64(),
+	})
 	
 	// Check if we need to pause the game
-	if len(r.players) < r.config.MinPlayers && r.gameState == StateBetting {
-		r.pauseGame()
+	if r.activePlayers() < r.config.MinPlayers && r.gameState == StateBetting {
+		r.pauseGame("")
+	}
+	// The player left may have been the last one yet to bet
+	r.checkEarlyClose()
+	
+	// The majority needed for an open settings vote has changed
+	if r.proposal != nil {
+		delete(r.proposal.votes, playerID)
+		r.tallyProposal()
+	}
+	if r.pauseVote != nil {
+		delete(r.pauseVote.votes, playerID)
+		r.tallyPause()
 	}
 	
 	r.broadcastRoomUpdate()
-	return nil
+	return player.Balance, nil
 }
 
 // PlaceBet allows a player to place a bet
-func (r *GameRoom) PlaceBet(playerID string, amount float64, choice game.Side) error {
+func (r *GameRoom) PlaceBet(playerID string, amount game.Money, choice game.Side) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
+	return r.placeBet(playerID, amount, choice, false, false, r.clock.Now())
+}
+
+// PlaceInsuredBet places a bet with the room's insurance, escrowing the
+// premium along with the stake
+func (r *GameRoom) PlaceInsuredBet(playerID string, amount game.Money, choice game.Side) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	return r.placeBet(playerID, amount, choice, true, false, r.clock.Now())
+}
+
+// PlacePracticeBet places a bet that resolves with the round but escrows
+// nothing and pays nothing, counting only towards the player's practice
+// ledger
+func (r *GameRoom) PlacePracticeBet(playerID string, amount game.Money, choice game.Side) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	return r.placeBet(playerID, amount, choice, false, true, r.clock.Now())
+}
+
+// PlaceTimedBet places a bet, insured or practice as it asks, that the
+// player's client sent at sentAt on the room's clock. Betting closes at the
+// advertised deadline, but a bet sent before it that arrives within the
+// player's one-way delay is still accepted.
+func (r *GameRoom) PlaceTimedBet(playerID string, bet BetData, sentAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if bet.Practice && bet.Insured {
+		return game.ErrPracticeInsured
+	}
+	return r.placeBet(playerID, bet.Amount, bet.Choice, bet.Insured, bet.Practice, sentAt)
+}
+
+// placeBet escrows a bet sent at sentAt, and its premium if insured, in the
+// open round. Practice bets escrow nothing. Callers must hold r.mu.
+func (r *GameRoom) placeBet(playerID string, amount game.Money, choice game.Side, insured, practice bool, sentAt time.Time) error {
 	if r.gameState != StateBetting {
 		return ErrInvalidGamePhase
 	}
 	
+	// Practice rooms put no money at stake
+	if r.config.Mode == ModePractice {
+		practice = true
+	}
+	
 	player, exists := r.players[playerID]
 	if !exists {
 		return ErrPlayerNotFound
 	}
 	
 	if r.currentRound == nil {
-		return errors.New("no active round")
+		return ErrNoActiveRound
+	}
+	
+	if err := r.checkBetDeadline(player, sentAt); err != nil {
+		return err
 	}
 	
-	// Check if player already has a bet
-	if r.currentRound.Bets[playerID] != nil {
+	// Players may hedge across both sides, but hold one bet per side
+	if betOn(r.currentRound.Bets[playerID], choice) != nil {
 		return ErrPlayerAlreadyBet
 	}
 	
@@ -248,8 +735,16 @@ func (r *GameRoom) PlaceBet(playerID string, amount float64, choice game.Side) e
 		return game.ErrInvalidBetAmount
 	}
 	
-	if player.Balance < amount {
-		return game.ErrInsufficientBalance
+	if insured && practice {
+		return game.ErrPracticeInsured
+	}
+	if insured && !r.config.Insurance.Enabled() {
+		return game.ErrInsuranceUnavailable
+	}
+	
+	// A round settles a player either for real or for practice
+	if bets := r.currentRound.Bets[playerID]; len(bets) > 0 && bets[0].Practice != practice {
+		return ErrPracticeMixed
 	}
 	
 	// Create bet
@@ -258,26 +753,76 @@ func (r *GameRoom) PlaceBet(playerID string, amount float64, choice game.Side) e
 		Amount:   amount,
 		Choice:   choice,
 		BetID:    r.generateBetID(),
+		Practice: practice,
+	}
+	if insured {
+		r.insure(bet)
+	}
+	
+	// Bets past the room's limits are refused or scaled down to fit
+	if err := r.fitBet(bet); err != nil {
+		return err
+	}
+	
+	if player.Balance < betEscrow(bet) {
+		return game.ErrInsufficientBalance
+	}
+	if err := r.reserveLiability(bet); err != nil {
+		return err
 	}
 	
 	// Deduct from balance and add bet
-	player.Balance -= amount
-	player.CurrentBet = bet
-	r.currentRound.Bets[playerID] = bet
-	r.lastActivity = time.Now()
+	player.Balance -= betEscrow(bet)
+	r.currentRound.Bets[playerID] = append(r.currentRound.Bets[playerID], bet)
+	player.CurrentBets = r.currentRound.Bets[playerID]
+	r.lastActivity = r.clock.Now()
+	r.traceBet(bet)
+	r.markActive(player)
 	
 	r.logger.Info("Bet placed",
 		zap.String("room_id", r.id),
 		zap.String("player_id", playerID),
-		zap.Float64("amount", amount),
+		zap.Float64("amount", bet.Amount.Float64()),
 		zap.String("choice", choice.String()),
+		zap.Float64("premium", bet.Premium.Float64()),
+		zap.Bool("practice", practice),
 	)
+	if practice {
+		r.broadcastMessage(NewMessage(MsgBetPlaced, r.id, playerID, bet))
+		r.broadcastRoomUpdate()
+		r.checkEarlyClose()
+		return nil
+	}
+	r.audit.Record(logger.AuditEvent{
+		Time:     r.clock.Now(),
+		Event:    logger.AuditBet,
+		PlayerID: playerID,
+		RoomID:   r.id,
+		RoundID:  r.currentRound.ID,
+		BetID:    bet.BetID,
+		Amount:   bet.Amount.Float64(),
+		Choice:   choice.String(),
+		Balance:  (player.Balance + bet.Premium).Float64(),
+	})
+	if bet.Insured {
+		r.audit.Record(logger.AuditEvent{
+			Time:     r.clock.Now(),
+			Event:    logger.AuditInsurance,
+			PlayerID: playerID,
+			RoomID:   r.id,
+			RoundID:  r.currentRound.ID,
+			BetID:    bet.BetID,
+			Amount:   bet.Premium.Float64(),
+			Balance:  player.Balance.Float64(),
+		})
+	}
 	
 	// Broadcast bet placement
 	r.broadcastMessage(NewMessage(MsgBetPlaced, r.id, playerID, bet))
 	
 	// Broadcast updated room state with new player balances
 	r.broadcastRoomUpdate()
+	r.checkEarlyClose()
 	
 	return nil
 }
@@ -287,7 +832,7 @@ func (r *GameRoom) StartGame() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
-	if len(r.players) < r.config.MinPlayers {
+	if r.activePlayers() < r.config.MinPlayers {
 		return errors.New("not enough players to start game")
 	}
 	
@@ -298,19 +843,24 @@ func (r *GameRoom) StartGame() error {
 	// Create new round
 	r.currentRound = &GameRound{
 		ID:          r.generateRoundID(),
-		StartTime:   time.Now(),
-		Bets:        make(map[string]*BetData),
+		StartTime:   r.clock.Now(),
+		Bets:        make(map[string][]*BetData),
 		SeedCommits: make(map[string]string),
 		SeedReveals: make(map[string]string),
 		Results:     make(map[string]*PlayerResult),
 		State:       StateBetting,
+		Boost:       r.roundBoost(),
 	}
+	r.startRoundTrace()
 	
 	r.gameState = StateBetting
 	r.totalRounds++
+	r.startRecording()
 	
 	// Start betting timer
 	r.startBettingPhase()
+	r.placeQueuedBets()
+	r.placeSlipBets()
 	
 	r.logger.Info("Game round started",
 		zap.String("room_id", r.id),
@@ -323,45 +873,44 @@ func (r *GameRoom) StartGame() error {
 	return nil
 }
 
-// checkAndStartGame checks if we should start a new betting round
+// checkAndStartGame checks if we should start a new betting round, or
+// resume one paused while the room was short of players
 func (r *GameRoom) checkAndStartGame() {
+	if r.gameState == StatePaused && r.pausedBy == "" && r.activePlayers() >= r.config.MinPlayers {
+		r.resumeGame()
+		return
+	}
+	
 	// Only start if we have enough players and are in waiting state
-	if len(r.players) >= r.config.MinPlayers && r.gameState == StateWaiting {
+	if r.activePlayers() >= r.config.MinPlayers && r.gameState == StateWaiting {
 		r.logger.Info("Auto-starting betting round",
 			zap.String("room_id", r.id),
 			zap.Int("player_count", len(r.players)),
 			zap.Int("min_players", r.config.MinPlayers),
 		)
 		
-		// Use existing StartGame function which handles everything properly
-		go func() {
-			if err := r.StartGame(); err != nil {
-				r.logger.Error("Failed to auto-start game", zap.Error(err))
-			}
-		}()
+		// Start on the scheduler since we are holding the room lock
+		r.scheduler.Schedule(r.id, r.clock.Now(), nil, r.autoStart)
+	}
+}
+
+// autoStart starts a new round, logging rather than returning failures
+func (r *GameRoom) autoStart() {
+	if err := r.StartGame(); err != nil {
+		r.logger.Error("Failed to auto-start game", zap.Error(err))
 	}
 }
 
 // startBettingPhase starts the betting phase with timer
 func (r *GameRoom) startBettingPhase() {
-	r.timerEnd = time.Now().Add(r.config.BettingDuration)
+	r.timerEnd = r.clock.Now().Add(r.config.BettingDuration)
+	r.bettingGrace = r.latencyGrace()
 	
-	if r.timer != nil {
-		r.timer.Stop()
-	}
-	
-	r.timer = time.AfterFunc(r.config.BettingDuration, func() {
-		r.endBettingPhase()
-	})
+	// Countdown updates and the phase deadline are driven by the scheduler;
+	// bets are accepted for the grace period after the advertised deadline
+	r.scheduleBettingEnd(r.timerEnd.Add(r.bettingGrace))
 	
-	// Start timer broadcast routine
-	go r.broadcastTimer()
-	
-	r.broadcastMessage(NewMessage(MsgBetPhase, r.id, "", TimerData{
-		Phase:        StateBetting,
-		SecondsLeft:  int(r.config.BettingDuration.Seconds()),
-		TotalSeconds: int(r.config.BettingDuration.Seconds()),
-	}))
+	r.broadcastMessage(NewMessage(MsgBetPhase, r.id, "", r.bettingTimer(r.config.BettingDuration)))
 }
 
 // endBettingPhase ends the betting phase and starts revealing
@@ -385,62 +934,453 @@ func (r *GameRoom) endBettingPhase() {
 	if len(r.currentRound.Bets) == 0 {
 		r.gameState = StateWaiting
 		r.currentRound = nil
+		r.endRoundTrace("no bets placed")
+		r.finishRecording(false)
 		r.broadcastRoomUpdate()
 		return
 	}
 	
+	// A pool with stakes on one side only has nothing to win
+	if r.config.Mode.Pooled() && !r.poolContested() {
+		r.cancelRound(roundCancelledOneSided)
+		return
+	}
+	
 	// Generate final seed and determine result
-	r.generateFinalResult()
+	if err := r.generateFinalResult(); err != nil {
+		r.cancelRound(err.Error())
+		return
+	}
 	
 	// Start result phase
 	r.startResultPhase()
 }
 
-// generateFinalResult generates the final coin flip result
-func (r *GameRoom) generateFinalResult() {
-	// Generate secure random seed
-	seedBytes := make([]byte, 32)
-	rand.Read(seedBytes)
+// UpdateBet changes a player's stake on choice while betting is open. A
+// player with a single bet on the other side has it moved to choice. The
+// escrowed amount is adjusted by the difference, and an insured bet stays
+// insured with its premium repriced for the new stake.
+func (r *GameRoom) UpdateBet(playerID string, amount game.Money, choice game.Side) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	return r.updateBet(playerID, amount, choice, r.clock.Now())
+}
+
+// UpdateTimedBet changes a bet as UpdateBet does, for a change the player's
+// client sent at sentAt on the room's clock. Like a new bet, the change must
+// make the betting deadline, give or take the player's one-way delay.
+func (r *GameRoom) UpdateTimedBet(playerID string, amount game.Money, choice game.Side, sentAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	return r.updateBet(playerID, amount, choice, sentAt)
+}
+
+// updateBet changes a bet sent at sentAt. Callers must hold r.mu.
+func (r *GameRoom) updateBet(playerID string, amount game.Money, choice game.Side, sentAt time.Time) error {
+	if r.gameState != StateBetting || r.currentRound == nil {
+		return ErrBettingClosed
+	}
+	
+	player, exists := r.players[playerID]
+	if !exists {
+		return ErrPlayerNotFound
+	}
+	
+	if err := r.checkBetDeadline(player, sentAt); err != nil {
+		return err
+	}
+	
+	bets := r.currentRound.Bets[playerID]
+	index := betIndex(bets, choice)
+	if index < 0 && len(bets) == 1 {
+		index = 0
+	}
+	if index < 0 {
+		return ErrNoBetToCancel
+	}
+	
+	if amount < r.config.MinBet || amount > r.config.MaxBet {
+		return game.ErrInvalidBetAmount
+	}
+	
+	// Replace rather than mutate, as the old bet may still be queued for
+	// broadcast
+	bet := &BetData{
+		PlayerID: playerID,
+		Amount:   amount,
+		Choice:   choice,
+		BetID:    bets[index].BetID,
+		Practice: bets[index].Practice,
+	}
+	if bets[index].Insured {
+		r.insure(bet)
+	}
+	if err := r.fitBet(bet); err != nil {
+		return err
+	}
+	
+	// The current stake is already escrowed, so only the difference is due
+	previous := bets[index].Amount
+	escrowed := betEscrow(bets[index])
+	if player.Balance+escrowed < betEscrow(bet) {
+		return game.ErrInsufficientBalance
+	}
+	if err := r.reserveLiability(bet); err != nil {
+		return err
+	}
+	
+	bets[index] = bet
+	player.Balance += escrowed - betEscrow(bet)
+	player.CurrentBets = bets
+	r.lastActivity = r.clock.Now()
+	
+	r.logger.Info("Bet updated",
+		zap.String("room_id", r.id),
+		zap.String("player_id", playerID),
+		zap.String("bet_id", bet.BetID),
+		zap.Float64("previous_amount", previous.Float64()),
+		zap.Float64("amount", bet.Amount.Float64()),
+		zap.String("choice", choice.String()),
+	)
+	if !bet.Practice {
+		r.audit.Record(logger.AuditEvent{
+			Time:     r.clock.Now(),
+			Event:    logger.AuditBetUpdate,
+			PlayerID: playerID,
+			RoomID:   r.id,
+			RoundID:  r.currentRound.ID,
+			BetID:    bet.BetID,
+			Amount:   bet.Amount.Float64(),
+			Choice:   choice.String(),
+			Balance:  player.Balance.Float64(),
+		})
+	}
+	
+	r.broadcastMessage(NewMessage(MsgUpdateBet, r.id, playerID, bet))
+	r.broadcastRoomUpdate()
+	return nil
+}
+
+// CancelBet withdraws all of a player's bets and refunds them while betting
+// is open
+func (r *GameRoom) CancelBet(playerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	return r.cancelBet(playerID, r.clock.Now())
+}
+
+// CancelTimedBet withdraws a player's bets as CancelBet does, for a
+// cancellation the player's client sent at sentAt on the room's clock. Bets
+// cannot be withdrawn after the betting deadline any more than placed.
+func (r *GameRoom) CancelTimedBet(playerID string, sentAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	return r.cancelBet(playerID, sentAt)
+}
+
+// cancelBet withdraws a player's bets on a cancellation sent at sentAt.
+// Callers must hold r.mu.
+func (r *GameRoom) cancelBet(playerID string, sentAt time.Time) error {
+	if r.gameState != StateBetting || r.currentRound == nil {
+		return ErrBettingClosed
+	}
+	
+	player, exists := r.players[playerID]
+	if !exists {
+		return ErrPlayerNotFound
+	}
+	
+	if err := r.checkBetDeadline(player, sentAt); err != nil {
+		return err
+	}
 	
-	hash := sha256.Sum256(seedBytes)
-	r.currentRound.FinalSeed = hex.EncodeToString(hash[:])
+	bets := r.currentRound.Bets[playerID]
+	if len(bets) == 0 {
+		return ErrNoBetToCancel
+	}
+	
+	player.CurrentBets = nil
+	delete(r.currentRound.Bets, playerID)
+	r.lastActivity = r.clock.Now()
+	
+	for _, bet := range bets {
+		player.Balance += betEscrow(bet)
+		
+		r.logger.Info("Bet cancelled",
+			zap.String("room_id", r.id),
+			zap.String("player_id", playerID),
+			zap.String("bet_id", bet.BetID),
+			zap.Float64("refund", betEscrow(bet).Float64()),
+		)
+		if !bet.Practice {
+			r.audit.Record(logger.AuditEvent{
+				Time:     r.clock.Now(),
+				Event:    logger.AuditRefund,
+				PlayerID: playerID,
+				RoomID:   r.id,
+				RoundID:  r.currentRound.ID,
+				BetID:    bet.BetID,
+				Amount:   betCost(bet).Float64(),
+				Balance:  player.Balance.Float64(),
+				Reason:   "cancelled by player",
+			})
+		}
+		
+		r.broadcastMessage(NewMessage(MsgCancelBet, r.id, playerID, bet))
+	}
+	
+	r.trackLiability()
+	r.broadcastRoomUpdate()
+	return nil
+}
+
+// generateFinalResult generates the final coin flip result and each player's
+// outcome. Balances are only settled by applyResults once the result is out.
+func (r *GameRoom) generateFinalResult() (err error) {
+	_, span := tracer.Start(r.roundTrace(), "room.flip")
+	defer func() { tracing.End(span, err) }()
+	
+	// Generate secure random seed
+	seed, err := r.rng.GenerateSecureSeed()
+	if err != nil {
+		return fmt.Errorf("failed to generate final seed: %w", err)
+	}
+	r.currentRound.FinalSeed = seed
 	
 	// Determine coin result using the same logic as single-player
-	rng := game.NewDefaultRandomGenerator()
-	coinResult, _ := rng.FlipCoin(r.currentRound.FinalSeed)
+	coinResult, err := r.rng.FlipCoin(r.currentRound.FinalSeed)
+	if err != nil {
+		return fmt.Errorf("failed to flip coin: %w", err)
+	}
 	r.currentRound.CoinResult = coinResult
+	span.SetAttributes(attribute.String("coin.result", coinResult.String()))
 	
-	// Calculate results for each bet
-	for playerID, bet := range r.currentRound.Bets {
-		player := r.players[playerID]
-		won := bet.Choice == coinResult
-		
-		var payout float64
-		if won {
-			payout = bet.Amount * r.config.PayoutRatio
-			player.Balance += payout
-			player.TotalWins++
-			player.NetProfit += (payout - bet.Amount)
-		} else {
-			player.NetProfit -= bet.Amount
+	if r.config.Mode.Pooled() {
+		r.currentRound.Pool = game.SettlePool(r.poolStakes(), coinResult, r.config.Rake)
+	}
+	pool := r.currentRound.Pool
+	
+	// Calculate results for each player across all of their positions
+	for playerID, bets := range r.currentRound.Bets {
+		player, exists := r.players[playerID]
+		if !exists {
+			return fmt.Errorf("bets of unknown player %s", playerID)
 		}
 		
-		player.TotalGames++
-		player.CurrentBet = nil
+		practice := len(bets) > 0 && bets[0].Practice
+		streak := player.WinStreak
+		if practice {
+			streak = player.Practice.CurrentStreak
+		}
+		multiplier := r.payoutMultiplier(streak)
+		var wagered, payout, insurance game.Money
+		for _, bet := range bets {
+			wagered += betCost(bet)
+			payout += r.roundPayout(bet, coinResult, multiplier)
+			insurance += betInsurance(bet, coinResult)
+		}
 		
-		r.currentRound.Results[playerID] = &PlayerResult{
+		result := &PlayerResult{
 			PlayerID:   playerID,
 			PlayerName: player.Name,
-			Bet:        bet,
-			Won:        won,
+			Bets:       bets,
+			Wagered:    wagered,
+			Won:        payout+insurance > wagered,
 			Payout:     payout,
-			NewBalance: player.Balance,
+			NewBalance: player.Balance + payout + insurance,
+			Skin:       player.Skin,
+			Insurance:  insurance,
+		}
+		if bonus := r.streakMultiplier(streak); payout > 0 && bonus > 1 {
+			result.Multiplier = bonus
+		}
+		if boost := r.currentRound.Boost; payout > 0 && boost > 1 && pool == nil {
+			result.Boost = boost
+		}
+		if result.Won {
+			result.WinStreak = player.WinStreak + 1
 		}
+		if winning := betOn(bets, coinResult); pool != nil && winning != nil && !practice {
+			result.PoolShare = float64(winning.Amount) / float64(pool.Staked(coinResult))
+		}
+		if practice {
+			result.Practice = true
+			result.NewBalance = player.Balance
+			result.WinStreak = player.WinStreak
+		}
+		r.currentRound.Results[playerID] = result
+	}
+	
+	return nil
+}
+
+// applyResults settles the announced round into player balances and stats
+func (r *GameRoom) applyResults() {
+	now := r.clock.Now()
+	r.audit.Record(logger.AuditEvent{
+		Time:    now,
+		Event:   logger.AuditFlip,
+		RoomID:  r.id,
+		RoundID: r.currentRound.ID,
+		Result:  r.currentRound.CoinResult.String(),
+		Seed:    r.currentRound.FinalSeed,
+	})
+	
+	for playerID, result := range r.currentRound.Results {
+		player, exists := r.players[playerID]
+		if !exists {
+			continue
+		}
+		
+		// Practice bets only go to the practice ledger
+		if result.Practice {
+			for _, bet := range result.Bets {
+				payout := r.roundPayout(bet, r.currentRound.CoinResult, result.PayoutMultiplier())
+				player.Practice.Record(bet.Choice, r.currentRound.CoinResult, bet.Amount, payout)
+			}
+			player.CurrentBets = nil
+			continue
+		}
+		
+		balance := player.Balance
+		for _, bet := range result.Bets {
+			payout := r.roundPayout(bet, r.currentRound.CoinResult, result.PayoutMultiplier())
+			balance += payout
+			r.audit.Record(logger.AuditEvent{
+				Time:     now,
+				Event:    logger.AuditPayout,
+				PlayerID: playerID,
+				RoomID:   r.id,
+				RoundID:  r.currentRound.ID,
+				BetID:    bet.BetID,
+				Amount:   payout.Float64(),
+				Balance:  balance.Float64(),
+			})
+			if refund := betInsurance(bet, r.currentRound.CoinResult); refund > 0 {
+				balance += refund
+				r.audit.Record(logger.AuditEvent{
+					Time:     now,
+					Event:    logger.AuditPayout,
+					PlayerID: playerID,
+					RoomID:   r.id,
+					RoundID:  r.currentRound.ID,
+					BetID:    bet.BetID,
+					Amount:   refund.Float64(),
+					Balance:  balance.Float64(),
+					Reason:   "insurance",
+				})
+			}
+		}
+		
+		player.Balance = result.NewBalance
+		if result.Won {
+			player.TotalWins++
+		}
+		player.NetProfit += result.Payout + result.Insurance - result.Wagered
+		player.TotalGames++
+		player.WinStreak = result.WinStreak
+		player.LongestStreak = max(player.LongestStreak, player.WinStreak)
+		player.CurrentBets = nil
+	}
+}
+
+// CancelRound aborts the round in progress, refunding every bet
+func (r *GameRoom) CancelRound(reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if r.currentRound == nil {
+		return ErrNoActiveRound
+	}
+	
+	// Results are already paid out once the result phase starts
+	if r.gameState == StateResult {
+		return ErrInvalidGamePhase
+	}
+	
+	r.cancelRound(reason)
+	return nil
+}
+
+// cancelRound refunds all bets of the current round, announces the
+// cancellation and returns the room to waiting. Callers must hold r.mu.
+func (r *GameRoom) cancelRound(reason string) {
+	if r.currentRound == nil {
+		return
+	}
+	
+	r.scheduler.Cancel(r.id)
+	
+	refunds := make([]PlayerRefund, 0, len(r.currentRound.Bets))
+	for playerID, bets := range r.currentRound.Bets {
+		player, exists := r.players[playerID]
+		if !exists {
+			continue
+		}
+		
+		player.CurrentBets = nil
+		for _, bet := range bets {
+			if bet.Practice {
+				continue
+			}
+			player.Balance += betCost(bet)
+			r.audit.Record(logger.AuditEvent{
+				Time:     r.clock.Now(),
+				Event:    logger.AuditRefund,
+				PlayerID: playerID,
+				RoomID:   r.id,
+				RoundID:  r.currentRound.ID,
+				BetID:    bet.BetID,
+				Amount:   betCost(bet).Float64(),
+				Balance:  player.Balance.Float64(),
+				Reason:   reason,
+			})
+			refunds = append(refunds, PlayerRefund{
+				PlayerID:   playerID,
+				Amount:     betCost(bet),
+				NewBalance: player.Balance,
+			})
+		}
+	}
+	
+	r.logger.Error("Round cancelled",
+		zap.String("room_id", r.id),
+		zap.String("round_id", r.currentRound.ID),
+		zap.String("reason", reason),
+		zap.Int("refunded_bets", len(refunds)),
+	)
+	
+	r.broadcastMessage(NewMessage(MsgRoundCancelled, r.id, "", &RoundCancelledData{
+		RoundID: r.currentRound.ID,
+		Reason:  r.localize(reason),
+		Refunds: refunds,
+	}))
+	
+	r.gameState = StateWaiting
+	r.currentRound = nil
+	r.endRoundTrace(reason)
+	r.finishRecording(true)
+	r.trackLiability()
+	r.pausedBy = ""
+	r.pausedPhase = ""
+	r.pausedRemaining = 0
+	r.applyPendingConfig()
+	r.broadcastRoomUpdate()
+	
+	if r.activePlayers() >= r.config.MinPlayers {
+		r.scheduler.Schedule(r.id, r.clock.Now().Add(RoundBreakDuration), nil, r.autoStart)
 	}
 }
 
 // startResultPhase starts the result display phase
 func (r *GameRoom) startResultPhase() {
+	_, span := tracer.Start(r.roundTrace(), "room.settle")
+	defer span.End()
+	
 	r.gameState = StateResult
 	
 	// Prepare result data
@@ -459,7 +1399,16 @@ func (r *GameRoom) startResultPhase() {
 		FinalSeed:  r.currentRound.FinalSeed,
 		Winners:    winners,
 		Losers:     losers,
-		Timestamp:  time.Now(),
+		Timestamp:  r.clock.Now(),
+	}
+	if pool := r.currentRound.Pool; pool != nil {
+		resultData.Pool = &PoolData{
+			Total: pool.Total,
+			Heads: pool.Heads,
+			Tails: pool.Tails,
+			Rake:  pool.Rake,
+			Paid:  pool.Paid,
+		}
 	}
 	
 	r.logger.Info("Game result generated",
@@ -470,109 +1419,87 @@ func (r *GameRoom) startResultPhase() {
 		zap.Int("losers", len(losers)),
 	)
 	
-	// Broadcast result
-	r.broadcastMessage(NewMessage(MsgGameResult, r.id, "", resultData))
+	// Only settle balances once players can see the result
+	if !r.broadcastMessage(NewMessage(MsgGameResult, r.id, "", resultData)) {
+		r.cancelRound(roundCancelledBroadcastFailure)
+		return
+	}
+	r.applyResults()
+	r.recordRoundStats()
+	r.trackLiability()
+	r.saveRound(resultData)
+	r.trackIdlePlayers()
 	
 	// Schedule return to waiting state
-	time.AfterFunc(r.config.ResultDuration, func() {
-		r.mu.Lock()
-		defer r.mu.Unlock()
-		
-		r.gameState = StateWaiting
-		r.currentRound = nil
-		r.broadcastRoomUpdate()
-		
-		// Auto-start next round if enough players
-		if len(r.players) >= r.config.MinPlayers {
-			go func() {
-				time.Sleep(2 * time.Second) // Brief pause between rounds
-				r.StartGame()
-			}()
-		}
-	})
+	r.scheduler.Schedule(r.id, r.clock.Now().Add(r.config.ResultDuration), nil, r.endResultPhase)
 }
 
-// pauseGame pauses the current game
-func (r *GameRoom) pauseGame() {
-	if r.timer != nil {
-		r.timer.Stop()
+// endResultPhase returns the room to waiting and queues the next round
+func (r *GameRoom) endResultPhase() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	if r.gameState != StateResult {
+		return
 	}
-	r.gameState = StatePaused
 	
-	r.logger.Info("Game paused", zap.String("room_id", r.id))
+	r.gameState = StateWaiting
+	r.currentRound = nil
+	r.endRoundTrace("")
+	r.finishRecording(true)
+	r.applyPendingConfig()
+	
+	// A pause agreed while the result was showing starts now
+	if r.pausePending != "" {
+		r.pauseGame(r.pausePending)
+		return
+	}
 	r.broadcastRoomUpdate()
-}
-
-// broadcastTimer sends timer updates to all players
-func (r *GameRoom) broadcastTimer() {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
 	
-	for {
-		select {
-		case <-ticker.C:
-			r.mu.RLock()
-			if r.gameState != StateBetting {
-				r.mu.RUnlock()
-				return
-			}
-			
-			secondsLeft := int(time.Until(r.timerEnd).Seconds())
-			if secondsLeft <= 0 {
-				r.mu.RUnlock()
-				return
-			}
-			
-			timerData := TimerData{
-				Phase:        StateBetting,
-				SecondsLeft:  secondsLeft,
-				TotalSeconds: int(r.config.BettingDuration.Seconds()),
-			}
-			r.mu.RUnlock()
-			
-			r.broadcastMessage(NewMessage(MsgTimerUpdate, r.id, "", timerData))
-			
-		case <-r.stopChan:
-			return
-		}
+	// Auto-start next round after a brief pause if enough players
+	if r.activePlayers() >= r.config.MinPlayers {
+		r.scheduler.Schedule(r.id, r.clock.Now().Add(RoundBreakDuration), nil, r.autoStart)
 	}
 }
 
-// broadcastRoomUpdate sends room state to all players
-func (r *GameRoom) broadcastRoomUpdate() {
-	players := make([]PlayerInfo, 0, len(r.players))
-	for _, player := range r.players {
-		players = append(players, PlayerInfo{
-			ID:       player.ID,
-			Name:     player.Name,
-			Balance:  player.Balance,
-			IsReady:  player.IsReady,
-			HasBet:   player.CurrentBet != nil,
-			IsOnline: player.IsOnline,
-		})
+// broadcastTimer sends a betting countdown update to all players
+func (r *GameRoom) broadcastTimer(remaining time.Duration) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
+	if r.gameState != StateBetting {
+		return
 	}
 	
-	updateData := &RoomUpdateData{
-		RoomID:     r.id,
-		Players:    players,
-		GameState:  r.gameState,
-		Timer:      int(time.Until(r.timerEnd).Seconds()),
-		MinPlayers: r.config.MinPlayers,
-		MaxPlayers: r.config.MaxPlayers,
+	// Turbo rooms count down the last second too
+	timer := r.bettingTimer(remaining - r.bettingGrace)
+	if timer.MillisLeft <= 0 || (timer.SecondsLeft <= 0 && !r.config.Turbo) {
+		return
 	}
 	
-	r.broadcastMessage(NewMessage(MsgRoomUpdate, r.id, "", updateData))
+	r.broadcastMessage(NewMessage(MsgTimerUpdate, r.id, "", timer))
 }
 
-// broadcastMessage sends a message to all players in the room
-func (r *GameRoom) broadcastMessage(msg *Message) {
+// broadcastMessage sends a message to all players in the room, carrying
+// the trace of the round in progress, and reports whether it was queued
+func (r *GameRoom) broadcastMessage(msg *Message) bool {
+	if r.closed {
+		return false
+	}
+	if msg.TraceParent == "" {
+		msg.TraceParent = tracing.Inject(r.roundTrace())
+	}
+	
 	select {
 	case r.eventChan <- msg:
+		r.record(msg)
+		return true
 	default:
 		r.logger.Warn("Event channel full, dropping message",
 			zap.String("room_id", r.id),
 			zap.String("message_type", string(msg.Type)),
 		)
+		return false
 	}
 }
 
@@ -586,10 +1513,20 @@ func (r *GameRoom) Stop() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	
-	if r.timer != nil {
-		r.timer.Stop()
+	if r.closed {
+		return
+	}
+	
+	r.scheduler.Cancel(r.id)
+	r.scheduler.Cancel(r.updateKey())
+	r.scheduler.Cancel(r.statsKey())
+	r.liability.Set(r.id, 0)
+	if r.roundCtx != nil {
+		r.endRoundTrace("room stopped")
 	}
+	r.finishRecording(false)
 	
+	r.closed = true
 	close(r.stopChan)
 	close(r.eventChan)
 	
@@ -615,11 +1552,137 @@ func (r *GameRoom) GetGameState() GameState {
 	return r.gameState
 }
 
+// betSeq numbers the bets placed in every room
+var betSeq atomic.Uint64
+
 // Helper functions
+
+// generateBetID creates a unique identifier for a bet. The sequence keeps
+// IDs unique when bets are placed in the same nanosecond, such as a
+// player's hedge on both sides.
 func (r *GameRoom) generateBetID() string {
-	return fmt.Sprintf("bet_%d", time.Now().UnixNano())
+	return fmt.Sprintf("bet_%d_%d", time.Now().UnixNano(), betSeq.Add(1))
 }
 
 func (r *GameRoom) generateRoundID() string {
 	return fmt.Sprintf("round_%s_%d", r.id, time.Now().UnixNano())
-}
\ No newline at end of file
+}
+// betPayout returns what a single bet pays for the given coin result with
+// a streak and happy hour multiplier, zero or one meaning none. Each bet's
+// payout is rounded to the cent on its own, so a round's payout is the sum
+// of what its bets pay.
+func (r *GameRoom) betPayout(bet *BetData, coinResult game.Side, multiplier float64) game.Money {
+	if bet.Choice != coinResult {
+		return 0
+	}
+	ratio := r.config.PayoutRatio
+	if multiplier > 0 {
+		ratio *= multiplier
+	}
+	return bet.Amount.Mul(ratio)
+}
+
+// roundPayout returns what a bet of the current round pays: its share of
+// the pool in a pooled round, or its fixed payout otherwise. Only bets
+// on the side that came up are paid. Practice bets stay out of the pool.
+func (r *GameRoom) roundPayout(bet *BetData, coinResult game.Side, multiplier float64) game.Money {
+	if bet.Choice != coinResult {
+		return 0
+	}
+	if pool := r.currentRound.Pool; pool != nil && !bet.Practice {
+		return pool.Payouts[bet.BetID]
+	}
+	return r.betPayout(bet, coinResult, multiplier)
+}
+
+// poolStakes returns the real stakes of the current round, which make up
+// its parimutuel pool. Callers must hold r.mu.
+func (r *GameRoom) poolStakes() []game.PoolStake {
+	var stakes []game.PoolStake
+	for _, bets := range r.currentRound.Bets {
+		for _, bet := range bets {
+			if !bet.Practice {
+				stakes = append(stakes, game.PoolStake{ID: bet.BetID, Side: bet.Choice, Amount: bet.Amount})
+			}
+		}
+	}
+	return stakes
+}
+
+// poolContested reports whether the current round's pool can be settled:
+// it holds stakes on both sides, or none at all when only practice bets
+// were placed. Callers must hold r.mu.
+func (r *GameRoom) poolContested() bool {
+	staked := make(map[game.Side]game.Money, 2)
+	for _, stake := range r.poolStakes() {
+		staked[stake.Side] += stake.Amount
+	}
+	return len(staked) != 1
+}
+
+// insure prices the room's insurance into a bet
+func (r *GameRoom) insure(bet *BetData) {
+	bet.Insured = true
+	bet.Premium = r.config.Insurance.Premium(bet.Amount)
+	bet.Coverage = r.config.Insurance.Refund(bet.Amount)
+}
+
+// betCost returns everything a bet holds in escrow: its stake and any
+// insurance premium
+func betCost(bet *BetData) game.Money {
+	return bet.Amount + bet.Premium
+}
+
+// betEscrow returns what a bet takes from the player's balance while it is
+// open: its cost, or nothing for a practice bet
+func betEscrow(bet *BetData) game.Money {
+	if bet.Practice {
+		return 0
+	}
+	return betCost(bet)
+}
+
+// betInsurance returns what a bet's insurance refunds for the given coin
+// result: its coverage if it was insured and lost
+func betInsurance(bet *BetData, coinResult game.Side) game.Money {
+	if !bet.Insured || bet.Choice == coinResult {
+		return 0
+	}
+	return bet.Coverage
+}
+
+// streakMultiplier returns the payout multiplier a winning bet earns from
+// a win streak
+func (r *GameRoom) streakMultiplier(streak int) float64 {
+	return game.StreakMultiplier(streak, r.config.StreakBonus, r.config.MaxStreakMultiplier)
+}
+
+// betIndex returns the position of the bet on side, or -1
+func betIndex(bets []*BetData, side game.Side) int {
+	for i, bet := range bets {
+		if bet.Choice == side {
+			return i
+		}
+	}
+	return -1
+}
+
+// betOn returns the bet on side, if any
+func betOn(bets []*BetData, side game.Side) *BetData {
+	if i := betIndex(bets, side); i >= 0 {
+		return bets[i]
+	}
+	return nil
+}
+
+// copyBets snapshots bets for broadcasting
+func copyBets(bets []*BetData) []BetData {
+	if len(bets) == 0 {
+		return nil
+	}
+	copies := make([]BetData, 0, len(bets))
+	for _, bet := range bets {
+		copies = append(copies, *bet)
+	}
+	return copies
+}
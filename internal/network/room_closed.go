@@ -0,0 +1,89 @@
+package network
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// closeRoom stops a room, removes it and returns every client still
+// mapped to it to the lobby with the reason. Players left seated, as in an
+// abandoned room, give up their seats. Callers must hold s.mu.
+func (s *Server) closeRoom(room *GameRoom, reason string) {
+	room.close(reason)
+	delete(s.rooms, room.ID())
+
+	for id, player := range room.GetPlayers() {
+		s.sessions.LeaveRoom(id, room.ID(), player.Balance)
+	}
+
+	notified := 0
+	for _, client := range s.clients.members(room) {
+		if !s.clients.leave(client, room) {
+			continue
+		}
+		client.room = nil
+		client.sendRoomClosed(room)
+		notified++
+	}
+
+	s.logger.Info("Room closed",
+		zap.String("room_id", room.ID()),
+		zap.String("reason", reason),
+		zap.Int("clients_notified", notified),
+	)
+	s.recordEvent(ServerEvent{
+		Type:    EventRoomClosed,
+		RoomID:  room.ID(),
+		Message: fmt.Sprintf("Room %q closed: %s", room.Name(), reason),
+	})
+}
+
+// sendRoomClosed tells the client a room it joined no longer exists
+func (c *Client) sendRoomClosed(room *GameRoom) {
+	reason, _ := room.closedReason()
+	c.sendMessage(NewMessage(MsgRoomClosed, room.ID(), c.playerID, RoomClosedData{
+		RoomID: room.ID(),
+		Reason: reason,
+	}))
+}
+
+// close stops the room, recording why it was closed
+func (r *GameRoom) close(reason string) {
+	r.mu.Lock()
+	r.closeReason = reason
+	r.mu.Unlock()
+
+	r.Stop()
+}
+
+// closedReason reports whether the room has been stopped and why
+func (r *GameRoom) closedReason() (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.closeReason, r.closed
+}
+
+// leaveClosedRoom forgets the room the server closed, so the client is
+// back in the lobby and a reconnect does not try to rejoin it
+func (c *NetworkClient) leaveClosedRoom(msg *Message) {
+	var data RoomClosedData
+	if err := msg.GetData(&data); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	if c.currentRoom != data.RoomID {
+		c.mu.Unlock()
+		return
+	}
+	c.currentRoom = ""
+	c.mu.Unlock()
+	c.room.reset()
+
+	c.logger.Info("Room closed by the server",
+		zap.String("room_id", data.RoomID),
+		zap.String("reason", data.Reason),
+	)
+}
@@ -0,0 +1,154 @@
+package network
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+)
+
+// newCleanupServer creates a server whose cleanup follows a fake clock
+func newCleanupServer(t *testing.T) (*Server, *clock.Fake) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	config := DefaultServerConfig()
+	config.Clock = fake
+	config.Admin = AdminCredentials{Username: "admin", Password: "secret"}
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	return server, fake
+}
+
+// newCleanupClient returns a client of server without a connection, whose
+// outgoing messages are kept in its send channel
+func newCleanupClient(t *testing.T, server *Server, playerID string) *Client {
+	client := &Client{
+		server:   server,
+		playerID: playerID,
+		send:     make(chan []byte, 64),
+		encoding: EncodingJSON,
+		protocol: ProtocolVersion,
+	}
+	// Forgotten before the server stops, as it has no connection to close
+	t.Cleanup(func() {
+		server.clients.remove(client)
+	})
+	return client
+}
+
+// newOnlineClient returns a client as newCleanupClient does, registered
+// and bound to its player's session like a connected one
+func newOnlineClient(t *testing.T, server *Server, playerID string) *Client {
+	client := newCleanupClient(t, server, playerID)
+	server.clients.add(client)
+	server.sessions.Attach(playerID, playerID, client)
+	return client
+}
+
+// sentRoomClosed returns the room closures queued for a client
+func sentRoomClosed(t *testing.T, client *Client) []RoomClosedData {
+	t.Helper()
+
+	var closed []RoomClosedData
+	for {
+		select {
+		case data := <-client.send:
+			msg, err := DecodeMessage(data, EncodingJSON)
+			require.NoError(t, err)
+			if msg.Type != MsgRoomClosed {
+				continue
+			}
+			var closure RoomClosedData
+			require.NoError(t, msg.GetData(&closure))
+			closed = append(closed, closure)
+		default:
+			return closed
+		}
+	}
+}
+
+func TestServer_CleanupNotifiesClients(t *testing.T) {
+	server, fake := newCleanupServer(t)
+	room, err := server.CreateRoom("r1", "Room 1", nil)
+	require.NoError(t, err)
+
+	// A client that still believes it is in the room after its seat went
+	client := newCleanupClient(t, server, "p1")
+	server.mu.Lock()
+	server.clients.move(client, room)
+	client.room = room
+	server.mu.Unlock()
+
+	fake.Advance(DefaultRoomTimeout + time.Second)
+	server.performCleanup()
+
+	_, exists := server.GetRoom("r1")
+	assert.False(t, exists)
+	assert.Nil(t, client.room)
+	assert.Equal(t, []RoomClosedData{{RoomID: "r1", Reason: RoomClosedEmpty}}, sentRoomClosed(t, client))
+
+	// The stopped room turns away late joiners
+	assert.ErrorIs(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar), ErrRoomClosed)
+}
+
+func TestServer_JoinRacesCleanup(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		server, fake := newCleanupServer(t)
+		_, err := server.CreateRoom("r1", "Room 1", nil)
+		require.NoError(t, err)
+		fake.Advance(DefaultRoomTimeout + time.Second)
+
+		client := newCleanupClient(t, server, "p1")
+		join := NewMessage(MsgJoinRoom, "r1", "p1", RoomJoinData{PlayerName: "Player 1"})
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.handleJoinRoom(join)
+		}()
+		go func() {
+			defer wg.Done()
+			server.performCleanup()
+		}()
+		wg.Wait()
+
+		// Either the player holds a seat in a room the server still has, or
+		// they were told once that the room is gone
+		server.mu.RLock()
+		joined := client.room
+		server.mu.RUnlock()
+		closed := sentRoomClosed(t, client)
+		if joined == nil {
+			assert.Equal(t, []RoomClosedData{{RoomID: "r1", Reason: RoomClosedEmpty}}, closed)
+			continue
+		}
+		assert.Empty(t, closed)
+		current, exists := server.GetRoom("r1")
+		require.True(t, exists)
+		assert.Same(t, current, joined)
+		assert.Contains(t, joined.GetPlayers(), "p1")
+	}
+}
+
+func TestNetworkClient_RoomClosedReturnsToLobby(t *testing.T) {
+	client := NewNetworkClient(DefaultClientConfig(), "p1", "Player 1", zaptest.NewLogger(t))
+	client.currentRoom = "r1"
+
+	// A closure of another room is ignored
+	other, err := NewMessage(MsgRoomClosed, "r2", "p1", RoomClosedData{RoomID: "r2", Reason: RoomClosedEmpty}).Encode(EncodingJSON)
+	require.NoError(t, err)
+	client.handleMessage(websocket.TextMessage, other)
+	assert.Equal(t, "r1", client.GetCurrentRoom())
+
+	closed, err := NewMessage(MsgRoomClosed, "r1", "p1", RoomClosedData{RoomID: "r1", Reason: RoomClosedAbandoned}).Encode(EncodingJSON)
+	require.NoError(t, err)
+	client.handleMessage(websocket.TextMessage, closed)
+	assert.Empty(t, client.GetCurrentRoom())
+}
@@ -0,0 +1,88 @@
+package network
+
+import (
+	"time"
+)
+
+// DefaultStatsInterval is how often a room broadcasts its statistics while
+// rounds are played
+const DefaultStatsInterval = 30 * time.Second
+
+// statsKey is the scheduler key of the room's next stats broadcast
+func (r *GameRoom) statsKey() string {
+	return r.id + "#stats"
+}
+
+// RoomStats returns how the room has played since it opened
+func (r *GameRoom) RoomStats() RoomStatsData {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.stats
+}
+
+// recordRoundStats adds the settled round to the room's statistics and
+// schedules their broadcast. Callers must hold r.mu.
+func (r *GameRoom) recordRoundStats() {
+	stats := &r.stats
+	stats.RoundsPlayed++
+
+	coin := r.currentRound.CoinResult
+	if coin == stats.StreakSide {
+		stats.StreakLength++
+	} else {
+		stats.StreakSide, stats.StreakLength = coin, 1
+	}
+
+	for _, result := range r.currentRound.Results {
+		if result.Practice {
+			continue
+		}
+		paid := result.Payout + result.Insurance
+		stats.Volume += result.Wagered
+		stats.Paid += paid
+		if win := paid - result.Wagered; win > stats.BiggestWin {
+			stats.BiggestWin, stats.BiggestWinner = win, result.PlayerName
+		}
+	}
+	if stats.Volume > 0 {
+		stats.RTP = float64(stats.Paid) / float64(stats.Volume)
+	}
+
+	if r.config.StatsInterval <= 0 {
+		r.broadcastRoomStats()
+		return
+	}
+	if r.statsPending {
+		return
+	}
+	r.statsPending = true
+	r.scheduler.Schedule(r.statsKey(), r.clock.Now().Add(r.config.StatsInterval), nil, r.flushRoomStats)
+}
+
+// flushRoomStats sends the statistics gathered over the stats interval
+func (r *GameRoom) flushRoomStats() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.statsPending {
+		r.statsPending = false
+		r.broadcastRoomStats()
+	}
+}
+
+// broadcastRoomStats sends the room's statistics to everyone in it.
+// Callers must hold r.mu.
+func (r *GameRoom) broadcastRoomStats() {
+	stats := r.stats
+	r.broadcastMessage(NewMessage(MsgRoomStats, r.id, "", &stats))
+}
+
+// sendRoomStats gives a player who just joined the room's statistics
+// rather than have them wait for the next broadcast
+func (c *Client) sendRoomStats(room *GameRoom) {
+	stats := room.RoomStats()
+	if stats.RoundsPlayed == 0 {
+		return
+	}
+	c.sendMessage(NewMessage(MsgRoomStats, room.ID(), c.playerID, &stats))
+}
@@ -0,0 +1,70 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+// roomStatsSent returns the room statistics the room broadcast
+func roomStatsSent(room *GameRoom) []RoomStatsData {
+	var sent []RoomStatsData
+	for _, msg := range drainEvents(room) {
+		if msg.Type == MsgRoomStats {
+			sent = append(sent, *msg.Data.(*RoomStatsData))
+		}
+	}
+	return sent
+}
+
+func TestGameRoom_RoomStats(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.SetRandomGenerator(&fixedCoin{side: game.Heads, seed: "seed-1"})
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p2", 30*game.Dollar, game.Tails))
+	nextRound(t, room, scheduler, fake)
+	require.NoError(t, room.PlaceBet("p2", 5*game.Dollar, game.Heads))
+	require.NoError(t, room.PlacePracticeBet("p1", 50*game.Dollar, game.Heads))
+	nextRound(t, room, scheduler, fake)
+
+	stats := room.RoomStats()
+	assert.Equal(t, 2, stats.RoundsPlayed)
+	assert.Equal(t, 45*game.Dollar, stats.Volume, "practice bets are left out")
+	assert.Equal(t, 30*game.Dollar, stats.Paid)
+	assert.InDelta(t, 30.0/45.0, stats.RTP, 1e-9)
+	assert.Equal(t, 10*game.Dollar, stats.BiggestWin)
+	assert.Equal(t, "Player 1", stats.BiggestWinner)
+	assert.Equal(t, game.Heads, stats.StreakSide)
+	assert.Equal(t, 2, stats.StreakLength)
+}
+
+func TestGameRoom_RoomStatsBroadcast(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	// Rounds take 22 seconds
+	room.config.StatsInterval = 30 * time.Second
+	drainEvents(room)
+
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	nextRound(t, room, scheduler, fake)
+	assert.Empty(t, roomStatsSent(room), "stats wait for the interval")
+
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	nextRound(t, room, scheduler, fake)
+	sent := roomStatsSent(room)
+	require.Len(t, sent, 1, "rounds within an interval go out together")
+	assert.Equal(t, 2, sent[0].RoundsPlayed)
+
+	// Without an interval every round sends them
+	room.config.StatsInterval = 0
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	nextRound(t, room, scheduler, fake)
+	sent = roomStatsSent(room)
+	require.Len(t, sent, 1)
+	assert.Equal(t, 3, sent[0].RoundsPlayed)
+}
@@ -0,0 +1,590 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+)
+
+func TestGameRoom_PhasesFollowClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	scheduler := NewTimerScheduler(DefaultSchedulerResolution, DefaultCountdownInterval, fake)
+
+	config := DefaultRoomConfig()
+	config.MinPlayers = 1
+	config.BettingDuration = 10 * time.Second
+	config.ResultDuration = 5 * time.Second
+
+	room := NewGameRoom("room", "Room", config, scheduler, zaptest.NewLogger(t))
+	defer room.Stop()
+
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+
+	// Joining queues an immediate start on the scheduler
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+
+	fake.Advance(9 * time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateBetting, room.GetGameState())
+
+	fake.Advance(time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateResult, room.GetGameState())
+
+	fake.Advance(5 * time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateWaiting, room.GetGameState())
+
+	// The next round starts after the break between rounds
+	fake.Advance(RoundBreakDuration)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateBetting, room.GetGameState())
+}
+
+// newTestRoom creates a single-player room driven by a fake clock
+func newTestRoom(t *testing.T) (*GameRoom, *TimerScheduler, *clock.Fake) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	scheduler := NewTimerScheduler(DefaultSchedulerResolution, DefaultCountdownInterval, fake)
+
+	config := DefaultRoomConfig()
+	config.MinPlayers = 1
+	config.BettingDuration = 10 * time.Second
+
+	room := NewGameRoom("room", "Room", config, scheduler, zaptest.NewLogger(t))
+	t.Cleanup(room.Stop)
+
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+
+	return room, scheduler, fake
+}
+
+// drainEvents empties the room's event channel and returns the messages
+func drainEvents(room *GameRoom) []*Message {
+	var messages []*Message
+	for {
+		select {
+		case msg := <-room.GetEventChannel():
+			messages = append(messages, msg)
+		default:
+			return messages
+		}
+	}
+}
+
+// fixedCoin is a RandomGenerator whose coin always lands on side, with a
+// fixed seed or a failure to produce one
+type fixedCoin struct {
+	side    game.Side
+	seed    string
+	seedErr error
+}
+
+func (c *fixedCoin) GenerateSecureSeed() (string, error) {
+	if c.seedErr != nil {
+		return "", c.seedErr
+	}
+	return c.seed, nil
+}
+
+func (c *fixedCoin) FlipCoin(seed string) (game.Side, error) {
+	return c.side, nil
+}
+
+// roundResult returns the game result broadcast among messages
+func roundResult(t *testing.T, messages []*Message) *GameResultData {
+	t.Helper()
+
+	for _, message := range messages {
+		if data, ok := message.Data.(*GameResultData); ok {
+			return data
+		}
+	}
+	t.Fatal("no game result broadcast")
+	return nil
+}
+
+func TestGameRoom_RoundFlow(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.SetRandomGenerator(&fixedCoin{side: game.Tails, seed: "seed-1"})
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 50*game.Dollar))
+
+	require.NoError(t, room.PlaceBet("p1", 20*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p2", 15*game.Dollar, game.Tails))
+	assert.Equal(t, 80*game.Dollar, room.GetPlayers()["p1"].Balance)
+	assert.Equal(t, 35*game.Dollar, room.GetPlayers()["p2"].Balance)
+	drainEvents(room)
+
+	// Bets are escrowed until the coin is flipped when betting closes
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	result := roundResult(t, drainEvents(room))
+	assert.Equal(t, game.Tails, result.CoinResult)
+	assert.Equal(t, "seed-1", result.FinalSeed)
+
+	require.Len(t, result.Winners, 1)
+	winner := result.Winners[0]
+	assert.Equal(t, "p2", winner.PlayerID)
+	assert.Equal(t, 15*game.Dollar, winner.Wagered)
+	assert.Equal(t, 30*game.Dollar, winner.Payout)
+	assert.Equal(t, 65*game.Dollar, winner.NewBalance)
+	assert.Equal(t, 1, winner.WinStreak)
+
+	require.Len(t, result.Losers, 1)
+	loser := result.Losers[0]
+	assert.Equal(t, "p1", loser.PlayerID)
+	assert.Zero(t, loser.Payout)
+	assert.Equal(t, 80*game.Dollar, loser.NewBalance)
+
+	players := room.GetPlayers()
+	assert.Equal(t, 80*game.Dollar, players["p1"].Balance)
+	assert.Equal(t, -20*game.Dollar, players["p1"].NetProfit)
+	assert.Equal(t, 65*game.Dollar, players["p2"].Balance)
+	assert.Equal(t, 15*game.Dollar, players["p2"].NetProfit)
+
+	// The result shows for its phase, then the next round follows a break
+	fake.Advance(room.config.ResultDuration)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateWaiting, room.GetGameState())
+	assert.Empty(t, room.GetPlayers()["p2"].CurrentBets)
+
+	fake.Advance(RoundBreakDuration)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateBetting, room.GetGameState())
+}
+
+func TestGameRoom_SeedFailureCancelsRound(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.SetRandomGenerator(&fixedCoin{seedErr: errors.New("entropy exhausted")})
+	require.NoError(t, room.PlaceBet("p1", 25*game.Dollar, game.Heads))
+	drainEvents(room)
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateWaiting, room.GetGameState())
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+
+	var cancelled *RoundCancelledData
+	for _, message := range drainEvents(room) {
+		if data, ok := message.Data.(*RoundCancelledData); ok {
+			cancelled = data
+		}
+	}
+	require.NotNil(t, cancelled)
+	assert.Contains(t, cancelled.Reason, "entropy exhausted")
+	require.Len(t, cancelled.Refunds, 1)
+	assert.Equal(t, 25*game.Dollar, cancelled.Refunds[0].Amount)
+}
+
+func TestGameRoom_LeavingRefundsBets(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.SetRandomGenerator(&fixedCoin{side: game.Heads, seed: "seed"})
+	room.config.Insurance = game.Insurance{Cost: 0.1, Coverage: 0.5}
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	// The premium is refunded along with the stake
+	require.NoError(t, room.PlaceInsuredBet("p2", 20*game.Dollar, game.Tails))
+	assert.Equal(t, 78*game.Dollar, room.GetPlayers()["p2"].Balance)
+	refund, err := room.removePlayer("p2")
+	require.NoError(t, err)
+	assert.Equal(t, 100*game.Dollar, refund)
+
+	// Leaving once results are out keeps the settled round
+	require.NoError(t, room.AddPlayer("p3", "Player 3", 100*game.Dollar))
+	require.NoError(t, room.PlaceBet("p3", 10*game.Dollar, game.Heads))
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	refund, err = room.removePlayer("p3")
+	require.NoError(t, err)
+	assert.Equal(t, 110*game.Dollar, refund)
+
+	_, err = room.removePlayer("p3")
+	assert.ErrorIs(t, err, ErrPlayerNotFound)
+}
+
+func TestGameRoom_CancelRoundRefundsBets(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	require.NoError(t, room.PlaceBet("p1", 25*game.Dollar, game.Tails))
+	assert.Equal(t, 75*game.Dollar, room.GetPlayers()["p1"].Balance)
+	drainEvents(room)
+
+	require.NoError(t, room.CancelRound("server maintenance"))
+
+	assert.Equal(t, StateWaiting, room.GetGameState())
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+	assert.Empty(t, room.GetPlayers()["p1"].CurrentBets)
+
+	messages := drainEvents(room)
+	require.NotEmpty(t, messages)
+	assert.Equal(t, MsgRoundCancelled, messages[0].Type)
+
+	cancelled, ok := messages[0].Data.(*RoundCancelledData)
+	require.True(t, ok)
+	assert.Equal(t, "server maintenance", cancelled.Reason)
+	require.Len(t, cancelled.Refunds, 1)
+	assert.Equal(t, 25*game.Dollar, cancelled.Refunds[0].Amount)
+
+	assert.ErrorIs(t, room.CancelRound("again"), ErrNoActiveRound)
+}
+
+func TestGameRoom_CancelBet(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+
+	assert.ErrorIs(t, room.CancelBet("p1"), ErrNoBetToCancel)
+
+	require.NoError(t, room.PlaceBet("p1", 25*game.Dollar, game.Tails))
+	drainEvents(room)
+
+	require.NoError(t, room.CancelBet("p1"))
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+	assert.Empty(t, room.GetPlayers()["p1"].CurrentBets)
+
+	messages := drainEvents(room)
+	require.NotEmpty(t, messages)
+	assert.Equal(t, MsgCancelBet, messages[0].Type)
+
+	// A new bet is allowed after cancelling
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	assert.ErrorIs(t, room.CancelBet("p1"), ErrBettingClosed)
+}
+
+func TestGameRoom_UpdateBet(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+
+	assert.ErrorIs(t, room.UpdateBet("p1", 10*game.Dollar, game.Heads), ErrNoBetToCancel)
+
+	require.NoError(t, room.PlaceBet("p1", 25*game.Dollar, game.Heads))
+	drainEvents(room)
+
+	// Raising the stake escrows only the difference
+	require.NoError(t, room.UpdateBet("p1", 40*game.Dollar, game.Tails))
+	player := room.GetPlayers()["p1"]
+	assert.Equal(t, 60*game.Dollar, player.Balance)
+	assert.Equal(t, 40*game.Dollar, player.CurrentBets[0].Amount)
+	assert.Equal(t, game.Tails, player.CurrentBets[0].Choice)
+
+	messages := drainEvents(room)
+	require.NotEmpty(t, messages)
+	assert.Equal(t, MsgUpdateBet, messages[0].Type)
+
+	// Lowering it refunds the difference
+	require.NoError(t, room.UpdateBet("p1", 10*game.Dollar, game.Tails))
+	assert.Equal(t, 90*game.Dollar, room.GetPlayers()["p1"].Balance)
+
+	assert.ErrorIs(t, room.UpdateBet("p1", 101*game.Dollar, game.Tails), game.ErrInvalidBetAmount)
+	assert.Equal(t, 90*game.Dollar, room.GetPlayers()["p1"].Balance)
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	assert.ErrorIs(t, room.UpdateBet("p1", 20*game.Dollar, game.Heads), ErrBettingClosed)
+}
+
+func TestGameRoom_HedgedBetsSettle(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.SetRandomGenerator(&fixedCoin{side: game.Tails, seed: "seed"})
+
+	require.NoError(t, room.PlaceBet("p1", 30*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Tails))
+	assert.ErrorIs(t, room.PlaceBet("p1", 5*game.Dollar, game.Heads), ErrPlayerAlreadyBet)
+	assert.Equal(t, 60*game.Dollar, room.GetPlayers()["p1"].Balance)
+	drainEvents(room)
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	// Only the smaller position, on the winning side, pays out
+	result := roundResult(t, drainEvents(room))
+	assert.Empty(t, result.Winners)
+	require.Len(t, result.Losers, 1)
+	outcome := result.Losers[0]
+	assert.Len(t, outcome.Bets, 2)
+	assert.Equal(t, 40*game.Dollar, outcome.Wagered)
+	assert.Equal(t, 20*game.Dollar, outcome.Payout)
+	assert.False(t, outcome.Won)
+	assert.Equal(t, 80*game.Dollar, room.GetPlayers()["p1"].Balance)
+	assert.Equal(t, -20*game.Dollar, room.GetPlayers()["p1"].NetProfit)
+}
+
+func TestGameRoom_StreakBonus(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.config.StreakBonus = 0.25
+	room.players["p1"].WinStreak = 2
+
+	// Hedging both sides guarantees one winning bet whatever the coin shows
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Tails))
+	drainEvents(room)
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	var result *GameResultData
+	for _, message := range drainEvents(room) {
+		if data, ok := message.Data.(*GameResultData); ok {
+			result = data
+		}
+	}
+	require.NotNil(t, result)
+	require.Len(t, result.Winners, 1)
+
+	outcome := result.Winners[0]
+	assert.Equal(t, 1.5, outcome.Multiplier)
+	assert.Equal(t, 30*game.Dollar, outcome.Payout)
+	assert.Equal(t, 3, outcome.WinStreak)
+
+	player := room.GetPlayers()["p1"]
+	assert.Equal(t, 110*game.Dollar, player.Balance)
+	assert.Equal(t, 3, player.WinStreak)
+	assert.Equal(t, 3, player.LongestStreak)
+}
+
+func TestGameRoom_HedgedBetIDsDiffer(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+
+	// Both bets are placed within the same clock tick
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Tails))
+	bets := room.GetPlayers()["p1"].CurrentBets
+	require.Len(t, bets, 2)
+	assert.NotEqual(t, bets[0].BetID, bets[1].BetID)
+
+	ids := make(map[string]bool)
+	for range 1000 {
+		ids[room.generateBetID()] = true
+	}
+	assert.Len(t, ids, 1000)
+}
+
+func TestGameRoom_InsuredBet(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+
+	assert.ErrorIs(t, room.PlaceInsuredBet("p1", 20*game.Dollar, game.Heads), game.ErrInsuranceUnavailable)
+	room.config.Insurance = game.Insurance{Cost: 0.1, Coverage: 0.5}
+
+	// Hedging both sides guarantees one insured bet loses
+	require.NoError(t, room.PlaceInsuredBet("p1", 20*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceInsuredBet("p1", 20*game.Dollar, game.Tails))
+	assert.Equal(t, 56*game.Dollar, room.GetPlayers()["p1"].Balance)
+	drainEvents(room)
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	var result *GameResultData
+	for _, message := range drainEvents(room) {
+		if data, ok := message.Data.(*GameResultData); ok {
+			result = data
+		}
+	}
+	require.NotNil(t, result)
+
+	outcomes := append(result.Winners, result.Losers...)
+	require.Len(t, outcomes, 1)
+	outcome := outcomes[0]
+	assert.Equal(t, 44*game.Dollar, outcome.Wagered)
+	assert.Equal(t, 40*game.Dollar, outcome.Payout)
+	assert.Equal(t, 10*game.Dollar, outcome.Insurance)
+	assert.True(t, outcome.Won)
+
+	player := room.GetPlayers()["p1"]
+	assert.Equal(t, 106*game.Dollar, player.Balance)
+	assert.Equal(t, 6*game.Dollar, player.NetProfit)
+}
+
+func TestGameRoom_PracticeBet(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	room.config.Insurance = game.Insurance{Cost: 0.1, Coverage: 0.5}
+
+	// Practice bets escrow nothing, so even a hedge leaves the balance alone
+	require.NoError(t, room.PlacePracticeBet("p1", 20*game.Dollar, game.Heads))
+	require.NoError(t, room.PlacePracticeBet("p1", 20*game.Dollar, game.Tails))
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+	assert.ErrorIs(t, room.PlaceBet("p1", 20*game.Dollar, game.Heads), ErrPlayerAlreadyBet)
+	require.NoError(t, room.CancelBet("p1"))
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+
+	require.NoError(t, room.PlacePracticeBet("p1", 20*game.Dollar, game.Heads))
+	require.NoError(t, room.PlacePracticeBet("p1", 20*game.Dollar, game.Tails))
+	assert.ErrorIs(t, room.PlaceInsuredBet("p1", 20*game.Dollar, game.Heads), ErrPlayerAlreadyBet)
+	drainEvents(room)
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	var result *GameResultData
+	for _, message := range drainEvents(room) {
+		if data, ok := message.Data.(*GameResultData); ok {
+			result = data
+		}
+	}
+	require.NotNil(t, result)
+
+	outcomes := append(result.Winners, result.Losers...)
+	require.Len(t, outcomes, 1)
+	assert.True(t, outcomes[0].Practice)
+	assert.Equal(t, 40*game.Dollar, outcomes[0].Payout)
+	assert.Equal(t, 100*game.Dollar, outcomes[0].NewBalance)
+	assert.Zero(t, outcomes[0].WinStreak)
+
+	player := room.GetPlayers()["p1"]
+	assert.Equal(t, 100*game.Dollar, player.Balance)
+	assert.Zero(t, player.TotalGames)
+	assert.Zero(t, player.NetProfit)
+	assert.Equal(t, 2, player.Practice.GamesPlayed)
+	assert.Equal(t, 1, player.Practice.GamesWon)
+	assert.Zero(t, player.Practice.NetProfit)
+}
+
+func TestGameRoom_PracticeBetRules(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	room.config.Insurance = game.Insurance{Cost: 0.1, Coverage: 0.5}
+
+	require.NoError(t, room.PlaceBet("p1", 20*game.Dollar, game.Heads))
+	assert.ErrorIs(t, room.PlacePracticeBet("p1", 20*game.Dollar, game.Tails), ErrPracticeMixed)
+	require.NoError(t, room.CancelBet("p1"))
+
+	require.NoError(t, room.PlacePracticeBet("p1", 20*game.Dollar, game.Heads))
+	assert.ErrorIs(t, room.PlaceBet("p1", 20*game.Dollar, game.Tails), ErrPracticeMixed)
+	assert.ErrorIs(t, room.PlacePracticeBet("p1", 500*game.Dollar, game.Tails), game.ErrInvalidBetAmount)
+
+	// Updating keeps the bet a practice one
+	require.NoError(t, room.UpdateBet("p1", 90*game.Dollar, game.Heads))
+	assert.True(t, room.GetPlayers()["p1"].CurrentBets[0].Practice)
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+
+	// A cancelled round has nothing to refund
+	require.NoError(t, room.CancelRound("server maintenance"))
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+}
+
+func TestGameRoom_ResultBroadcastFailureCancelsRound(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	require.NoError(t, room.PlaceBet("p1", 25*game.Dollar, game.Heads))
+
+	// Fill the event channel so the result cannot be delivered
+	for room.broadcastMessage(NewMessage(MsgTimerUpdate, room.ID(), "", nil)) {
+	}
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+
+	assert.Equal(t, StateWaiting, room.GetGameState())
+	assert.Equal(t, 100*game.Dollar, room.GetPlayers()["p1"].Balance)
+	assert.Equal(t, 0, room.GetPlayers()["p1"].TotalGames)
+
+	// The room recovers and starts the next round after the break
+	drainEvents(room)
+	fake.Advance(RoundBreakDuration)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateBetting, room.GetGameState())
+}
+
+func TestGameRoom_AuditTrail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := logger.NewAuditLogger(path)
+	require.NoError(t, err)
+
+	room, scheduler, fake := newTestRoom(t)
+	room.SetAuditLogger(audit)
+
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	// Leaving after the result must not refund the settled bet
+	require.NoError(t, room.RemovePlayer("p1"))
+	require.NoError(t, audit.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	count, err := logger.VerifyAuditLog(file)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var events []logger.AuditEventType
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var event logger.AuditEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		events = append(events, event.Event)
+	}
+	assert.Equal(t, []logger.AuditEventType{
+		logger.AuditBet, logger.AuditFlip, logger.AuditPayout, logger.AuditLeave,
+	}, events)
+}
+
+func TestGameRoom_QueuedBetPlacedWhenBettingOpens(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+	assert.ErrorIs(t, room.PlaceBet("p1", 20*game.Dollar, game.Tails), ErrInvalidGamePhase)
+
+	// Betting is closed, so the bet waits for the next round
+	assert.ErrorIs(t, room.CancelQueuedBet("p1"), ErrNoQueuedBet)
+	assert.ErrorIs(t, room.QueueBet("p1", 5000*game.Dollar, game.Tails), game.ErrInvalidBetAmount)
+	require.NoError(t, room.QueueBet("p1", 20*game.Dollar, game.Tails))
+	queued, ok := room.QueuedBet("p1")
+	require.True(t, ok)
+	assert.Equal(t, 20*game.Dollar, queued.Amount)
+	drainEvents(room)
+
+	fake.Advance(ResultPhaseDuration)
+	scheduler.advance(fake.Now())
+	fake.Advance(RoundBreakDuration)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+
+	player := room.GetPlayers()["p1"]
+	require.Len(t, player.CurrentBets, 1)
+	assert.Equal(t, 20*game.Dollar, player.CurrentBets[0].Amount)
+	assert.Equal(t, game.Tails, player.CurrentBets[0].Choice)
+	_, ok = room.QueuedBet("p1")
+	assert.False(t, ok)
+
+	var status QueuedBetStatus
+	for _, msg := range drainEvents(room) {
+		if msg.Type == MsgQueuedBet {
+			status = msg.Data.(*QueuedBetData).Status
+		}
+	}
+	assert.Equal(t, QueuedBetPlaced, status)
+
+	// While betting is open a queued bet is placed straight away
+	require.NoError(t, room.QueueBet("p1", 5*game.Dollar, game.Heads))
+	assert.Len(t, room.GetPlayers()["p1"].CurrentBets, 2)
+}
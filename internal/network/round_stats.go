@@ -0,0 +1,136 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"coinflip-game/internal/storage"
+)
+
+// Round stats limits
+const (
+	// DefaultLeaderboardLimit is how many players the leaderboard lists
+	// unless asked otherwise, and MaxLeaderboardLimit the most it lists
+	DefaultLeaderboardLimit = 10
+	MaxLeaderboardLimit     = 100
+	// DefaultVolumeDays is how many days of volume are returned unless
+	// asked otherwise, and MaxVolumeDays the most that can be asked for
+	DefaultVolumeDays = 30
+	MaxVolumeDays     = 366
+)
+
+// ErrRoundStatsUnavailable is returned when the round repository keeps no
+// aggregate stats
+var ErrRoundStatsUnavailable = errors.New("round repository does not keep stats")
+
+// roundStats returns the round repository's stats, if it keeps any
+func (s *Server) roundStats() (storage.RoundStatsRepository, error) {
+	stats, ok := s.rounds.(storage.RoundStatsRepository)
+	if !ok {
+		return nil, ErrRoundStatsUnavailable
+	}
+	return stats, nil
+}
+
+// RoundLeaderboard returns the players with the best net result over every
+// stored round, best first
+func (s *Server) RoundLeaderboard(ctx context.Context, limit int) ([]storage.PlayerRoundStats, error) {
+	stats, err := s.roundStats()
+	if err != nil {
+		return nil, err
+	}
+	return stats.RoundLeaderboard(ctx, limit)
+}
+
+// DailyVolume returns the volume of each of the last days days, today
+// included, oldest first
+func (s *Server) DailyVolume(ctx context.Context, days int) ([]storage.DailyVolume, error) {
+	stats, err := s.roundStats()
+	if err != nil {
+		return nil, err
+	}
+	now := s.scheduler.Clock().Now()
+	return stats.DailyVolume(ctx, now.AddDate(0, 0, 1-days), now)
+}
+
+// RefreshRoundStats rebuilds the round repository's stats from its rounds
+func (s *Server) RefreshRoundStats(ctx context.Context) error {
+	stats, err := s.roundStats()
+	if err != nil {
+		return err
+	}
+	return stats.RefreshStats(ctx)
+}
+
+// queryCount reads a positive count from the query, up to max, or returns
+// fallback when it is missing
+func queryCount(r *http.Request, name string, fallback, max int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count <= 0 || count > max {
+		return 0, fmt.Errorf("%s must be an integer from 1 to %d", name, max)
+	}
+	return count, nil
+}
+
+// writeStatsError reports why a stats request failed
+func writeStatsError(w http.ResponseWriter, err error) {
+	status, code := http.StatusInternalServerError, "stats_failed"
+	if errors.Is(err, ErrRoundStatsUnavailable) {
+		status, code = http.StatusServiceUnavailable, "stats_unavailable"
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorData{Code: code, Message: err.Error()})
+}
+
+// handleRoundLeaderboard returns the players with the best net result.
+// ?limit= sets how many, up to MaxLeaderboardLimit.
+func (s *Server) handleRoundLeaderboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	limit, err := queryCount(r, "limit", DefaultLeaderboardLimit, MaxLeaderboardLimit)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{Code: "invalid_limit", Message: err.Error()})
+		return
+	}
+
+	players, err := s.RoundLeaderboard(r.Context(), limit)
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(players)
+}
+
+// handleDailyVolume returns the volume of each recent day. ?days= sets how
+// many, up to MaxVolumeDays.
+func (s *Server) handleDailyVolume(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	days, err := queryCount(r, "days", DefaultVolumeDays, MaxVolumeDays)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{Code: "invalid_days", Message: err.Error()})
+		return
+	}
+
+	volume, err := s.DailyVolume(r.Context(), days)
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(volume)
+}
+
+// handleRefreshStats rebuilds the round stats from the dashboard
+func (s *Server) handleRefreshStats(w http.ResponseWriter, r *http.Request) {
+	s.dashboardAction(w, r, s.RefreshRoundStats(r.Context()))
+}
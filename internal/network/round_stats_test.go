@@ -0,0 +1,94 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+// plainRounds hides a repository's stats, as a repository without any
+type plainRounds struct {
+	storage.RoundRepository
+}
+
+func TestServer_RoundStatsEndpoints(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Admin = AdminCredentials{Username: "admin", Password: "secret"}
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	handler := server.Handler()
+
+	now := time.Now()
+	for i, winner := range []string{"p1", "p2", "p1"} {
+		require.NoError(t, server.Rounds().SaveRound(context.Background(), &storage.Round{
+			ID:         string(rune('1' + i)),
+			RoomID:     "r1",
+			CoinResult: game.Heads,
+			SettledAt:  now.AddDate(0, 0, -i),
+			Outcomes: []storage.RoundOutcome{{
+				PlayerID: winner,
+				Bets:     []storage.RoundBet{{ID: "bet", Choice: game.Heads, Amount: 5 * game.Dollar}},
+				Wagered:  5 * game.Dollar,
+				Won:      true,
+				Payout:   10 * game.Dollar,
+			}},
+		}))
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/stats/leaderboard?limit=1", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var leaders []storage.PlayerRoundStats
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&leaders))
+	require.Len(t, leaders, 1)
+	assert.Equal(t, "p1", leaders[0].PlayerID)
+	assert.Equal(t, 10*game.Dollar, leaders[0].Net)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/stats/volume?days=2", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var volume []storage.DailyVolume
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&volume))
+	assert.Len(t, volume, 2, "today and yesterday")
+
+	for _, target := range []string{"/stats/leaderboard?limit=0", "/stats/leaderboard?limit=1000", "/stats/volume?days=many"} {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, target)
+	}
+
+	// Refreshing is an admin action
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/admin/stats/refresh", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, adminRequest(http.MethodPost, "/admin/stats/refresh", url.Values{"token": {server.adminToken}}))
+	assert.Equal(t, http.StatusSeeOther, recorder.Code)
+}
+
+func TestServer_RoundStatsUnavailable(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Rounds = plainRounds{storage.NewMemoryRoundRepository()}
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/stats/leaderboard", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	var response ErrorData
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.Equal(t, "stats_unavailable", response.Code)
+	assert.ErrorIs(t, server.RefreshRoundStats(context.Background()), ErrRoundStatsUnavailable)
+}
@@ -0,0 +1,71 @@
+package network
+
+import (
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/storage"
+)
+
+// SetRoundRepository sets where the room persists its settled rounds; nil
+// stops persisting them
+func (r *GameRoom) SetRoundRepository(rounds storage.RoundRepository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rounds = rounds
+}
+
+// saveRound persists a settled round. A round that fails to save has
+// still been settled, so the failure is only logged. Callers must hold
+// r.mu.
+func (r *GameRoom) saveRound(data *GameResultData) {
+	if r.rounds == nil {
+		return
+	}
+
+	round := &storage.Round{
+		ID:         data.RoundID,
+		RoomID:     r.id,
+		CoinResult: data.CoinResult,
+		FinalSeed:  data.FinalSeed,
+		StartedAt:  r.currentRound.StartTime,
+		SettledAt:  data.Timestamp,
+	}
+	for _, outcomes := range [][]PlayerResult{data.Winners, data.Losers} {
+		for _, outcome := range outcomes {
+			round.Outcomes = append(round.Outcomes, roundOutcome(outcome))
+		}
+	}
+
+	if err := r.rounds.SaveRound(r.roundTrace(), round); err != nil {
+		r.logger.Error("Failed to save round",
+			zap.String("room_id", r.id),
+			zap.String("round_id", data.RoundID),
+			zap.Error(err),
+		)
+	}
+}
+
+// roundOutcome converts a player's result to the form rounds are stored in
+func roundOutcome(outcome PlayerResult) storage.RoundOutcome {
+	stored := storage.RoundOutcome{
+		PlayerID:   outcome.PlayerID,
+		PlayerName: outcome.PlayerName,
+		Bets:       make([]storage.RoundBet, 0, len(outcome.Bets)),
+		Wagered:    outcome.Wagered,
+		Won:        outcome.Won,
+		Payout:     outcome.Payout,
+		Insurance:  outcome.Insurance,
+		NewBalance: outcome.NewBalance,
+		Practice:   outcome.Practice,
+	}
+	for _, bet := range outcome.Bets {
+		stored.Bets = append(stored.Bets, storage.RoundBet{
+			ID:       bet.BetID,
+			Choice:   bet.Choice,
+			Amount:   bet.Amount,
+			Premium:  bet.Premium,
+			Practice: bet.Practice,
+		})
+	}
+	return stored
+}
@@ -0,0 +1,43 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+func TestGameRoom_SavesSettledRounds(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	rounds := storage.NewMemoryRoundRepository()
+	room.SetRoundRepository(rounds)
+
+	started := fake.Now()
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	require.NoError(t, room.PlaceBet("p1", 4*game.Dollar, game.Tails))
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+
+	saved, err := rounds.GetRounds(context.Background(), "room", 0)
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	round := saved[0]
+	assert.Equal(t, "room", round.RoomID)
+	assert.Equal(t, started, round.StartedAt)
+	assert.Equal(t, fake.Now(), round.SettledAt)
+	assert.NotEmpty(t, round.FinalSeed)
+
+	require.Len(t, round.Outcomes, 1)
+	outcome := round.Outcomes[0]
+	assert.Equal(t, "p1", outcome.PlayerID)
+	assert.Equal(t, 14*game.Dollar, outcome.Wagered)
+	assert.Equal(t, room.GetPlayers()["p1"].Balance, outcome.NewBalance)
+	assert.Len(t, outcome.Bets, 2)
+	assert.Equal(t, round.CoinResult == game.Heads, outcome.Won)
+}
@@ -0,0 +1,104 @@
+package network
+
+import (
+	"net/http"
+
+	"coinflip-game/internal/tracing"
+)
+
+// Features turns the server's groups of routes on and off. The health
+// check is always served, for load balancers and orchestrators.
+type Features struct {
+	// WebSocket serves /ws, where players connect
+	WebSocket bool
+	// REST serves the read-only rooms, replays, players, stats and seasons
+	// endpoints
+	REST bool
+	// Metrics serves /metrics in the Prometheus text format
+	Metrics bool
+	// Admin serves the admin dashboard and every /admin endpoint
+	Admin bool
+}
+
+// AllFeatures returns every feature turned on
+func AllFeatures() Features {
+	return Features{WebSocket: true, REST: true, Metrics: true, Admin: true}
+}
+
+// router registers routes on a mux, tracing those that answer API requests
+type router struct {
+	mux *http.ServeMux
+}
+
+// handle registers an API route, traced as a span per request
+func (r router) handle(pattern string, handler http.HandlerFunc) {
+	r.mux.Handle(pattern, tracing.Middleware(tracer, pattern, handler))
+}
+
+// Handler returns the HTTP routes served by the server, those of its
+// disabled features left out
+func (s *Server) Handler() http.Handler {
+	r := router{mux: http.NewServeMux()}
+	features := s.config.Features
+
+	r.handle("/health", s.handleHealth)
+	if features.WebSocket {
+		s.webSocketRoutes(r)
+	}
+	if features.REST {
+		s.restRoutes(r)
+	}
+	if features.Metrics {
+		r.handle("GET /metrics", s.handleMetrics)
+	}
+	if features.Admin {
+		s.adminRoutes(r)
+	}
+	return r.mux
+}
+
+// webSocketRoutes registers where players connect. WebSocket connections
+// trace each message instead of the request, as a span for the whole
+// connection would say little.
+func (s *Server) webSocketRoutes(r router) {
+	r.mux.HandleFunc("/ws", s.handleWebSocket)
+}
+
+// restRoutes registers the read-only API
+func (s *Server) restRoutes(r router) {
+	r.handle("/rooms", s.handleRooms)
+	r.handle("GET /rooms/{id}/replays", s.handleListReplays)
+	r.handle("GET /rooms/{id}/replays/{round}", s.handleGetReplay)
+	r.handle("GET /players/{id}/stats", s.handlePlayerStats)
+	r.handle("GET /duels", s.handleDuels)
+	r.handle("GET /stats/distribution", s.handleDistribution)
+	r.handle("GET /stats/fairness", s.handleFairness)
+	r.handle("GET /stats/leaderboard", s.handleRoundLeaderboard)
+	r.handle("GET /stats/volume", s.handleDailyVolume)
+	r.handle("GET /seasons", s.handleSeasons)
+	r.handle("GET /seasons/current", s.handleCurrentSeason)
+	r.handle("GET /seasons/{id}", s.handleSeasons)
+}
+
+// adminRoutes registers the admin dashboard and the operators' endpoints
+func (s *Server) adminRoutes(r router) {
+	r.handle("/admin/archive", s.requireAdmin(s.handleArchive))
+	r.handle("POST /admin/balances/snapshot", s.requireAdmin(s.handleSnapshotBalances))
+	r.handle("POST /admin/balances/restore", s.requireAdmin(s.handleRestoreBalances))
+	r.handle("GET /admin/promos", s.requireAdmin(s.handleListPromos))
+	r.handle("POST /admin/promos", s.requireAdmin(s.handleCreatePromo))
+	r.handle("GET /admin/promotions", s.requireAdmin(s.handleListPromotions))
+	r.handle("POST /admin/promotions", s.requireAdmin(s.handleCreatePromotion))
+	r.handle("DELETE /admin/promotions/{id}", s.requireAdmin(s.handleCancelPromotion))
+	r.handle("GET /admin/sessions", s.requireAdmin(s.handleListSessions))
+	r.handle("GET /admin", s.requireAdmin(s.handleDashboard))
+	r.handle("GET /admin/dashboard", s.requireAdmin(s.handleDashboardData))
+	r.handle("GET /admin/events", s.requireAdmin(s.handleEvents))
+	r.handle("GET /admin/events/view", s.requireAdmin(s.handleEventPage))
+	r.handle("POST /admin/rooms/{id}/close", s.requireAdmin(s.handleCloseRoom))
+	r.handle("POST /admin/rooms/{id}/players/{player}/kick", s.requireAdmin(s.handleKickPlayer))
+	r.handle("POST /admin/stats/refresh", s.requireAdmin(s.handleRefreshStats))
+	r.handle("GET /admin/roles/{player}", s.requireAdmin(s.handleGetRole))
+	r.handle("PUT /admin/roles/{player}", s.requireAdmin(s.handleSetRole))
+	r.handle("DELETE /admin/roles/{player}", s.requireAdmin(s.handleResetRole))
+}
@@ -0,0 +1,65 @@
+package network
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestServer_Features(t *testing.T) {
+	status := func(features Features, target string) int {
+		config := DefaultServerConfig()
+		config.Admin = AdminCredentials{Username: "admin", Password: "secret"}
+		config.Features = features
+		server := NewServer(config, zaptest.NewLogger(t))
+		t.Cleanup(server.Stop)
+
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, adminRequest(http.MethodGet, target, nil))
+		return recorder.Code
+	}
+
+	all := AllFeatures()
+	assert.Equal(t, http.StatusOK, status(all, "/rooms"))
+	assert.Equal(t, http.StatusOK, status(all, "/metrics"))
+	assert.Equal(t, http.StatusOK, status(all, "/admin/events"))
+	assert.Equal(t, http.StatusBadRequest, status(all, "/ws"), "a plain request is not an upgrade")
+
+	none := Features{}
+	assert.Equal(t, http.StatusOK, status(none, "/health"), "the health check is always served")
+	assert.Equal(t, http.StatusNotFound, status(none, "/rooms"))
+	assert.Equal(t, http.StatusNotFound, status(none, "/seasons/current"))
+	assert.Equal(t, http.StatusNotFound, status(none, "/metrics"))
+	assert.Equal(t, http.StatusNotFound, status(none, "/admin/events"))
+	assert.Equal(t, http.StatusNotFound, status(none, "/ws"))
+
+	// The WebSocket server alone, with the API served elsewhere
+	assert.Equal(t, http.StatusBadRequest, status(Features{WebSocket: true}, "/ws"))
+	assert.Equal(t, http.StatusNotFound, status(Features{WebSocket: true}, "/rooms"))
+}
+
+func TestServer_Metrics(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+
+	room, err := server.CreateRoom("r1", "Room", nil)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, "# HELP coinflip_rooms Open rooms\n# TYPE coinflip_rooms gauge\ncoinflip_rooms 1\n")
+	assert.Contains(t, body, "coinflip_seated_players 1\n")
+	assert.Contains(t, body, "# TYPE coinflip_rooms_by_state gauge\ncoinflip_rooms_by_state{state=\"waiting\"} 1\ncoinflip_rooms_by_state{state=\"betting\"} 0\n")
+	assert.Contains(t, body, "coinflip_evicted_results_total 0\n")
+}
@@ -0,0 +1,152 @@
+// Package network provides the shared timer scheduler for multiplayer game rooms
+package network
+
+import (
+	"sync"
+	"time"
+
+	"coinflip-game/internal/clock"
+)
+
+// Scheduler defaults
+const (
+	DefaultSchedulerResolution = 100 * time.Millisecond
+	DefaultCountdownInterval   = 1 * time.Second
+)
+
+// TimerScheduler drives every room's phase deadlines and countdown ticks from
+// a single goroutine instead of one timer and broadcast goroutine per room.
+// Each key (normally a room ID) holds at most one timer; scheduling again
+// replaces it.
+type TimerScheduler struct {
+	mu         sync.Mutex
+	timers     map[string]*scheduledTimer
+	resolution time.Duration
+	interval   time.Duration
+	clock      clock.Clock
+}
+
+// scheduledTimer is a single pending deadline with optional countdown ticks
+type scheduledTimer struct {
+	deadline time.Time
+	nextTick time.Time
+	interval time.Duration
+	onTick   func(remaining time.Duration)
+	onExpire func()
+}
+
+// NewTimerScheduler creates a scheduler that checks timers every resolution
+// and delivers countdown ticks every interval. A nil clock uses system time.
+func NewTimerScheduler(resolution, interval time.Duration, clk clock.Clock) *TimerScheduler {
+	if resolution <= 0 {
+		resolution = DefaultSchedulerResolution
+	}
+	if interval <= 0 {
+		interval = DefaultCountdownInterval
+	}
+	if clk == nil {
+		clk = clock.New()
+	}
+
+	return &TimerScheduler{
+		timers:     make(map[string]*scheduledTimer),
+		resolution: resolution,
+		interval:   interval,
+		clock:      clk,
+	}
+}
+
+// Clock returns the time source the scheduler runs on
+func (s *TimerScheduler) Clock() clock.Clock {
+	return s.clock
+}
+
+// Schedule registers a timer for key that expires at deadline. While pending,
+// onTick (if set) is called every countdown interval with the time remaining;
+// onExpire (if set) is called once the deadline passes. Callbacks run on the
+// scheduler goroutine and must not block.
+func (s *TimerScheduler) Schedule(key string, deadline time.Time, onTick func(remaining time.Duration), onExpire func()) {
+	s.ScheduleEvery(key, deadline, 0, onTick, onExpire)
+}
+
+// ScheduleEvery is Schedule with onTick called every interval instead of
+// every countdown interval. Ticks come no more often than the scheduler's
+// resolution; an interval of zero or less uses the countdown interval.
+func (s *TimerScheduler) ScheduleEvery(key string, deadline time.Time, interval time.Duration, onTick func(remaining time.Duration), onExpire func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if interval <= 0 {
+		interval = s.interval
+	}
+	s.timers[key] = &scheduledTimer{
+		deadline: deadline,
+		nextTick: s.clock.Now().Add(interval),
+		interval: interval,
+		onTick:   onTick,
+		onExpire: onExpire,
+	}
+}
+
+// Cancel removes the pending timer for key, if any
+func (s *TimerScheduler) Cancel(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.timers, key)
+}
+
+// Pending returns the number of timers waiting to fire
+func (s *TimerScheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.timers)
+}
+
+// Run processes timers until stop is closed
+func (s *TimerScheduler) Run(stop <-chan struct{}) {
+	ticker := s.clock.NewTicker(s.resolution)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.Chan():
+			s.advance(s.clock.Now())
+		case <-stop:
+			return
+		}
+	}
+}
+
+// advance fires every tick and expiry due at now. Callbacks are collected
+// under the lock and invoked after releasing it so they may reschedule.
+func (s *TimerScheduler) advance(now time.Time) {
+	s.mu.Lock()
+	due := make([]func(), 0)
+	for key, timer := range s.timers {
+		if !now.Before(timer.deadline) {
+			delete(s.timers, key)
+			if timer.onExpire != nil {
+				due = append(due, timer.onExpire)
+			}
+			continue
+		}
+
+		if timer.onTick != nil && !now.Before(timer.nextTick) {
+			onTick := timer.onTick
+			remaining := timer.deadline.Sub(now)
+			due = append(due, func() { onTick(remaining) })
+
+			// Skip ticks missed while the loop was busy rather than bursting them
+			for !now.Before(timer.nextTick) {
+				timer.nextTick = timer.nextTick.Add(timer.interval)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	for _, fn := range due {
+		fn()
+	}
+}
@@ -0,0 +1,111 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"coinflip-game/internal/clock"
+)
+
+func TestTimerScheduler_TicksAndExpiry(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler := NewTimerScheduler(100*time.Millisecond, time.Second, clock.NewFake(start))
+
+	var ticks []time.Duration
+	expired := 0
+	scheduler.Schedule("room", start.Add(3*time.Second),
+		func(remaining time.Duration) { ticks = append(ticks, remaining) },
+		func() { expired++ },
+	)
+
+	// Nothing is due before the first countdown interval
+	scheduler.advance(start.Add(500 * time.Millisecond))
+	assert.Empty(t, ticks)
+
+	scheduler.advance(start.Add(time.Second))
+	scheduler.advance(start.Add(2 * time.Second))
+	assert.Equal(t, []time.Duration{2 * time.Second, time.Second}, ticks)
+	assert.Equal(t, 0, expired)
+
+	scheduler.advance(start.Add(3 * time.Second))
+	assert.Equal(t, 1, expired)
+	assert.Equal(t, 0, scheduler.Pending())
+
+	// Expired timers never fire again
+	scheduler.advance(start.Add(10 * time.Second))
+	assert.Equal(t, 1, expired)
+}
+
+func TestTimerScheduler_ScheduleEvery(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler := NewTimerScheduler(100*time.Millisecond, time.Second, clock.NewFake(start))
+
+	var ticks []time.Duration
+	scheduler.ScheduleEvery("turbo", start.Add(time.Second), 250*time.Millisecond,
+		func(remaining time.Duration) { ticks = append(ticks, remaining) }, nil)
+	scheduler.Schedule("classic", start.Add(time.Minute), func(time.Duration) { t.Fatal("ticked early") }, nil)
+
+	for step := 1; step < 4; step++ {
+		scheduler.advance(start.Add(time.Duration(step) * 250 * time.Millisecond))
+	}
+	assert.Equal(t, []time.Duration{750 * time.Millisecond, 500 * time.Millisecond, 250 * time.Millisecond}, ticks)
+}
+
+func TestTimerScheduler_MissedTicksAreSkipped(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	scheduler := NewTimerScheduler(100*time.Millisecond, time.Second, clock.NewFake(start))
+
+	ticks := 0
+	scheduler.Schedule("room", start.Add(time.Minute), func(time.Duration) { ticks++ }, nil)
+
+	scheduler.advance(start.Add(5500 * time.Millisecond))
+	assert.Equal(t, 1, ticks)
+
+	// The next tick is aligned to the interval rather than replaying the backlog
+	scheduler.advance(start.Add(5900 * time.Millisecond))
+	assert.Equal(t, 1, ticks)
+	scheduler.advance(start.Add(6 * time.Second))
+	assert.Equal(t, 2, ticks)
+}
+
+func TestTimerScheduler_CancelAndReplace(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	scheduler := NewTimerScheduler(100*time.Millisecond, time.Second, clock.NewFake(start))
+
+	fired := ""
+	scheduler.Schedule("a", start.Add(time.Second), nil, func() { fired += "a1" })
+	scheduler.Schedule("a", start.Add(2*time.Second), nil, func() { fired += "a2" })
+	scheduler.Schedule("b", start.Add(time.Second), nil, func() { fired += "b" })
+	scheduler.Cancel("b")
+
+	assert.Equal(t, 1, scheduler.Pending())
+
+	scheduler.advance(start.Add(time.Second))
+	assert.Equal(t, "", fired)
+
+	scheduler.advance(start.Add(2 * time.Second))
+	assert.Equal(t, "a2", fired)
+}
+
+func TestTimerScheduler_CallbackCanReschedule(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	scheduler := NewTimerScheduler(100*time.Millisecond, time.Second, clock.NewFake(start))
+
+	phases := make([]string, 0)
+	scheduler.Schedule("room", start.Add(time.Second), nil, func() {
+		phases = append(phases, "betting")
+		scheduler.Schedule("room", start.Add(2*time.Second), nil, func() {
+			phases = append(phases, "result")
+		})
+	})
+
+	scheduler.advance(start.Add(time.Second))
+	scheduler.advance(start.Add(2 * time.Second))
+
+	assert.Equal(t, []string{"betting", "result"}, phases)
+}
@@ -0,0 +1,239 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+// SeasonLeaders is how many of the season's leaders updates carry
+const SeasonLeaders = 10
+
+// DefaultSeasonHistory is how many ended seasons GET /seasons returns
+// unless asked otherwise, and MaxSeasonHistory the most it will
+const (
+	DefaultSeasonHistory = 10
+	MaxSeasonHistory     = 520
+)
+
+// seasonTimerKey is the scheduler key of the season rollover
+const seasonTimerKey = "#season"
+
+// CurrentSeason returns the running season's standings
+func (s *Server) CurrentSeason() *game.Season {
+	s.seasonMu.Lock()
+	defer s.seasonMu.Unlock()
+
+	return s.season.Season()
+}
+
+// Seasons returns up to limit ended seasons, newest first
+func (s *Server) Seasons(ctx context.Context, limit int) ([]*game.Season, error) {
+	return s.seasons.GetSeasons(ctx, limit)
+}
+
+// recordSeason counts a settled round towards the season's standings.
+// Practice rounds do not count.
+func (s *Server) recordSeason(ctx context.Context, outcome PlayerResult) {
+	if len(outcome.Bets) == 0 || outcome.Practice {
+		return
+	}
+	s.rolloverSeason(ctx)
+
+	s.seasonMu.Lock()
+	defer s.seasonMu.Unlock()
+	s.season.Record(outcome.PlayerID, outcome.PlayerName, outcome.Won,
+		outcome.Payout+outcome.Insurance-outcome.Wagered)
+}
+
+// scheduleSeasonRollover sets the scheduler to end the season on time,
+// so players hear of it even when no round is settling
+func (s *Server) scheduleSeasonRollover() {
+	s.seasonMu.Lock()
+	end := s.season.End()
+	s.seasonMu.Unlock()
+
+	s.scheduler.Schedule(seasonTimerKey, end, nil, func() {
+		// Archiving and notifying players must not hold up the scheduler
+		go func() {
+			s.rolloverSeason(s.ctx)
+			s.scheduleSeasonRollover()
+		}()
+	})
+}
+
+// rolloverSeason ends the season once its week is over: the standings are
+// archived, the leaders awarded their badges, and every online player sent
+// the new season along with how the old one ended
+func (s *Server) rolloverSeason(ctx context.Context) {
+	now := s.scheduler.Clock().Now()
+
+	s.seasonMu.Lock()
+	if !s.season.Ended(now) {
+		s.seasonMu.Unlock()
+		return
+	}
+	ended := s.season.Season()
+	s.season = game.NewSeasonBoard(now)
+	current := s.season.Season()
+	s.seasonMu.Unlock()
+
+	if err := s.seasons.SaveSeason(ctx, ended); err != nil {
+		s.logger.Error("Failed to archive season",
+			zap.String("season", ended.ID),
+			zap.Error(err),
+		)
+	}
+	badges := ended.Badges()
+	s.awardBadges(ctx, badges)
+	s.notifySeason(ctx, ended, badges)
+
+	s.logger.Info("Season ended",
+		zap.String("season", ended.ID),
+		zap.Int("players", len(ended.Standings)),
+		zap.Int("badges", len(badges)),
+		zap.String("next_season", current.ID),
+	)
+
+	for _, session := range s.sessions.Sessions() {
+		if !session.Online {
+			continue
+		}
+		update := seasonUpdate(current, session.PlayerID)
+		update.Ended = &SeasonEndData{
+			Season:   ended.ID,
+			Leaders:  seasonLeaders(ended),
+			Standing: seasonStanding(ended, session.PlayerID),
+		}
+		if badge, earned := badges[session.PlayerID]; earned {
+			update.Ended.Badge = &badge
+		}
+		s.sendToPlayer(session.PlayerID, NewMessage(MsgSeasonUpdate, "", session.PlayerID, update))
+	}
+}
+
+// awardBadges adds each season badge to its player's record
+func (s *Server) awardBadges(ctx context.Context, badges map[string]game.SeasonBadge) {
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	for playerID, badge := range badges {
+		player := s.playerRecord(ctx, playerID)
+		if slices.ContainsFunc(player.Badges, func(b game.SeasonBadge) bool { return b.Season == badge.Season }) {
+			continue
+		}
+		player.Badges = append(player.Badges, badge)
+		if err := s.results.SavePlayer(ctx, player); err != nil {
+			s.logger.Error("Failed to award season badge",
+				zap.String("player_id", playerID),
+				zap.String("season", badge.Season),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// sendSeasonStandings tells each player who settled a round where it
+// leaves them in the season
+func (s *Server) sendSeasonStandings(data *GameResultData) {
+	season := s.CurrentSeason()
+	for _, outcomes := range [][]PlayerResult{data.Winners, data.Losers} {
+		for _, outcome := range outcomes {
+			if outcome.Practice {
+				continue
+			}
+			s.sendToPlayer(outcome.PlayerID, NewMessage(MsgSeasonUpdate, "", outcome.PlayerID, seasonUpdate(season, outcome.PlayerID)))
+		}
+	}
+}
+
+// sendSeason tells the client's player where they stand this season
+func (c *Client) sendSeason(playerID string) {
+	c.sendMessage(NewMessage(MsgSeasonUpdate, "", playerID, seasonUpdate(c.server.CurrentSeason(), playerID)))
+}
+
+// seasonUpdate is a player's view of a season
+func seasonUpdate(season *game.Season, playerID string) *SeasonUpdateData {
+	return &SeasonUpdateData{
+		Season:   season.ID,
+		EndsAt:   season.End,
+		Leaders:  seasonLeaders(season),
+		Standing: seasonStanding(season, playerID),
+	}
+}
+
+// seasonLeaders returns the top SeasonLeaders standings of a season
+func seasonLeaders(season *game.Season) []game.SeasonStanding {
+	return season.Standings[:min(len(season.Standings), SeasonLeaders)]
+}
+
+// seasonStanding returns a player's standing in a season, or nil
+func seasonStanding(season *game.Season, playerID string) *game.SeasonStanding {
+	standing, ranked := season.Standing(playerID)
+	if !ranked {
+		return nil
+	}
+	return &standing
+}
+
+// handleCurrentSeason returns the running season's leaders
+func (s *Server) handleCurrentSeason(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	limit, err := queryCount(r, "limit", SeasonLeaders, MaxLeaderboardLimit)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{Code: "invalid_limit", Message: err.Error()})
+		return
+	}
+
+	season := s.CurrentSeason()
+	season.Standings = season.Standings[:min(len(season.Standings), limit)]
+	json.NewEncoder(w).Encode(season)
+}
+
+// handleSeasons returns the final standings of ended seasons, newest
+// first, or of the one named by the path
+func (s *Server) handleSeasons(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if id := r.PathValue("id"); id != "" {
+		season, err := s.seasons.GetSeason(r.Context(), id)
+		if err != nil {
+			writeSeasonError(w, err)
+			return
+		}
+		json.NewEncoder(w).Encode(season)
+		return
+	}
+
+	limit, err := queryCount(r, "limit", DefaultSeasonHistory, MaxSeasonHistory)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{Code: "invalid_limit", Message: err.Error()})
+		return
+	}
+	seasons, err := s.Seasons(r.Context(), limit)
+	if err != nil {
+		writeSeasonError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(seasons)
+}
+
+// writeSeasonError reports why a season request failed
+func writeSeasonError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, storage.ErrSeasonNotFound) {
+		status = http.StatusNotFound
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorData{Code: "season_failed", Message: err.Error()})
+}
@@ -0,0 +1,179 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+// settleRound records a round in which each winner gains and each loser
+// loses a dollar
+func settleRound(server *Server, winners, losers []string) {
+	data := &GameResultData{RoundID: "round", CoinResult: game.Heads}
+	for _, playerID := range winners {
+		data.Winners = append(data.Winners, PlayerResult{
+			PlayerID: playerID, PlayerName: playerID, Won: true,
+			Bets:    []*BetData{{Amount: game.Dollar, Choice: game.Heads}},
+			Wagered: game.Dollar, Payout: 2 * game.Dollar,
+		})
+	}
+	for _, playerID := range losers {
+		data.Losers = append(data.Losers, PlayerResult{
+			PlayerID: playerID, PlayerName: playerID,
+			Bets:    []*BetData{{Amount: game.Dollar, Choice: game.Tails}},
+			Wagered: game.Dollar,
+		})
+	}
+	server.recordResults(context.Background(), data)
+	server.sendSeasonStandings(data)
+}
+
+// sentSeasonUpdates returns the season updates queued for a client
+func sentSeasonUpdates(t *testing.T, client *Client) []SeasonUpdateData {
+	t.Helper()
+
+	var updates []SeasonUpdateData
+	for {
+		select {
+		case data := <-client.send:
+			msg, err := DecodeMessage(data, EncodingJSON)
+			require.NoError(t, err)
+			if msg.Type != MsgSeasonUpdate {
+				continue
+			}
+			var update SeasonUpdateData
+			require.NoError(t, msg.GetData(&update))
+			updates = append(updates, update)
+		default:
+			return updates
+		}
+	}
+}
+
+func TestServer_SeasonStandings(t *testing.T) {
+	server, _ := newCleanupServer(t)
+	alice := newOnlineClient(t, server, "alice")
+
+	settleRound(server, []string{"alice", "bob"}, nil)
+	settleRound(server, []string{"alice"}, []string{"bob"})
+
+	season := server.CurrentSeason()
+	assert.Equal(t, "2024-W01", season.ID)
+	require.Len(t, season.Standings, 2)
+	assert.Equal(t, "alice", season.Standings[0].PlayerID)
+	assert.Equal(t, 2, season.Standings[0].BestStreak)
+	assert.Equal(t, 2*game.Dollar, season.Standings[0].Net)
+
+	// Players hear their place after every round they settle
+	updates := sentSeasonUpdates(t, alice)
+	require.Len(t, updates, 2)
+	require.NotNil(t, updates[1].Standing)
+	assert.Equal(t, 1, updates[1].Standing.Rank)
+	assert.Equal(t, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), updates[1].EndsAt.UTC())
+	assert.Len(t, updates[1].Leaders, 2)
+
+	// Practice rounds do not count
+	server.recordResults(context.Background(), &GameResultData{CoinResult: game.Heads, Winners: []PlayerResult{{
+		PlayerID: "carol", Won: true, Practice: true,
+		Bets: []*BetData{{Amount: game.Dollar, Choice: game.Heads, Practice: true}},
+	}}})
+	assert.Len(t, server.CurrentSeason().Standings, 2)
+}
+
+func TestServer_SeasonRollover(t *testing.T) {
+	server, fake := newCleanupServer(t)
+	ctx := context.Background()
+	require.NoError(t, server.results.SavePlayer(ctx, &game.Player{ID: "alice"}))
+	alice := newOnlineClient(t, server, "alice")
+	carol := newOnlineClient(t, server, "carol")
+
+	settleRound(server, []string{"alice", "bob"}, []string{"carol"})
+	settleRound(server, []string{"alice"}, []string{"bob"})
+	sentSeasonUpdates(t, alice)
+	sentSeasonUpdates(t, carol)
+
+	// The scheduler ends the week on time
+	server.scheduleSeasonRollover()
+	fake.Advance(7 * 24 * time.Hour)
+	server.scheduler.advance(fake.Now())
+	waitFor(t, func() bool {
+		seasons, err := server.Seasons(ctx, 0)
+		return err == nil && len(seasons) == 1
+	})
+	assert.Equal(t, "2024-W02", server.CurrentSeason().ID)
+	assert.Empty(t, server.CurrentSeason().Standings)
+
+	archived, err := server.seasons.GetSeason(ctx, "2024-W01")
+	require.NoError(t, err)
+	assert.Len(t, archived.Standings, 3)
+
+	// The leaders keep their badges; Carol won nothing
+	player, err := server.results.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []game.SeasonBadge{{Season: "2024-W01", Rank: 1, BestStreak: 2}}, player.Badges)
+	player, err = server.results.GetPlayer(ctx, "carol")
+	require.NoError(t, err)
+	assert.Empty(t, player.Badges)
+
+	// Everyone online hears how the season ended for them
+	var updates []SeasonUpdateData
+	waitFor(t, func() bool {
+		updates = append(updates, sentSeasonUpdates(t, alice)...)
+		return len(updates) > 0
+	})
+	require.NotNil(t, updates[0].Ended)
+	assert.Equal(t, "2024-W02", updates[0].Season)
+	assert.Nil(t, updates[0].Standing)
+	require.NotNil(t, updates[0].Ended.Badge)
+	assert.Equal(t, "Gold", updates[0].Ended.Badge.Name())
+
+	updates = sentSeasonUpdates(t, carol)
+	require.Len(t, updates, 1)
+	assert.Nil(t, updates[0].Ended.Badge)
+	require.NotNil(t, updates[0].Ended.Standing)
+	assert.Equal(t, 3, updates[0].Ended.Standing.Rank)
+}
+
+func TestServer_SeasonEndpoints(t *testing.T) {
+	server, fake := newCleanupServer(t)
+	handler := server.Handler()
+	settleRound(server, []string{"alice"}, []string{"bob"})
+
+	get := func(target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		return recorder
+	}
+
+	recorder := get("/seasons/current?limit=1")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var season game.Season
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&season))
+	assert.Equal(t, "2024-W01", season.ID)
+	require.Len(t, season.Standings, 1)
+	assert.Equal(t, "alice", season.Standings[0].PlayerID)
+
+	// A round settled in a new week ends the old season even before the
+	// scheduler gets to it
+	fake.Advance(7 * 24 * time.Hour)
+	settleRound(server, []string{"bob"}, nil)
+
+	recorder = get("/seasons")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var seasons []game.Season
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&seasons))
+	require.Len(t, seasons, 1)
+	assert.Equal(t, "2024-W01", seasons[0].ID)
+
+	assert.Equal(t, http.StatusOK, get("/seasons/2024-W01").Code)
+	assert.Equal(t, http.StatusNotFound, get("/seasons/2023-W52").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/seasons?limit=0").Code)
+}
@@ -0,0 +1,157 @@
+package network
+
+import (
+	"errors"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// ErrAlreadySeated is returned when a seated player asks to wait for a seat
+var ErrAlreadySeated = errors.New("player already has a seat in the room")
+
+// QueueForSeat puts a spectator in line for a seat in the room and returns
+// their place in line, counting from 1. A player already in line keeps
+// their place.
+func (r *GameRoom) QueueForSeat(playerID, playerName string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, ErrRoomClosed
+	}
+	if _, seated := r.players[playerID]; seated {
+		return 0, ErrAlreadySeated
+	}
+	if ahead := r.queuedAhead(playerID); ahead < len(r.seatQueue) {
+		return ahead + 1, nil
+	}
+
+	r.seatQueue = append(r.seatQueue, QueuedPlayer{ID: playerID, Name: playerName})
+	r.lastActivity = r.clock.Now()
+
+	r.logger.Info("Player waiting for a seat",
+		zap.String("room_id", r.id),
+		zap.String("player_id", playerID),
+		zap.Int("position", len(r.seatQueue)),
+	)
+
+	// The spectator has seen none of the seats yet
+	r.sentPlayers = nil
+	r.broadcastRoomUpdate()
+	return len(r.seatQueue), nil
+}
+
+// LeaveSeatQueue takes a spectator out of line and reports whether they
+// were waiting
+func (r *GameRoom) LeaveSeatQueue(playerID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.removeFromQueue(playerID) {
+		return false
+	}
+	r.logger.Info("Player stopped waiting for a seat",
+		zap.String("room_id", r.id),
+		zap.String("player_id", playerID),
+	)
+	r.broadcastRoomUpdate()
+	return true
+}
+
+// nextInLine returns the spectator first in line when a seat is free
+func (r *GameRoom) nextInLine() (QueuedPlayer, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed || len(r.seatQueue) == 0 || len(r.players) >= r.config.MaxPlayers {
+		return QueuedPlayer{}, false
+	}
+	return r.seatQueue[0], true
+}
+
+// queuedAhead returns how many spectators wait in line ahead of the
+// player: all of them when the player is not in line. Callers must hold
+// r.mu.
+func (r *GameRoom) queuedAhead(playerID string) int {
+	if index := r.queueIndex(playerID); index >= 0 {
+		return index
+	}
+	return len(r.seatQueue)
+}
+
+// removeFromQueue takes the player out of line and reports whether they
+// were in it. Callers must hold r.mu.
+func (r *GameRoom) removeFromQueue(playerID string) bool {
+	index := r.queueIndex(playerID)
+	if index < 0 {
+		return false
+	}
+	r.seatQueue = slices.Delete(r.seatQueue, index, index+1)
+	return true
+}
+
+// queueIndex returns the player's index in line, or -1. Callers must hold
+// r.mu.
+func (r *GameRoom) queueIndex(playerID string) int {
+	return slices.IndexFunc(r.seatQueue, func(queued QueuedPlayer) bool {
+		return queued.ID == playerID
+	})
+}
+
+// seatFromQueue gives the room's free seats to the spectators first in
+// line. A spectator who cannot be seated loses their place and goes back
+// to the lobby.
+func (s *Server) seatFromQueue(room *GameRoom) {
+	for {
+		next, ok := room.nextInLine()
+		if !ok {
+			return
+		}
+
+		client := s.spectator(room, next.ID)
+		if client == nil {
+			// The spectator went without leaving the line
+			room.LeaveSeatQueue(next.ID)
+			continue
+		}
+
+		if err := client.takeSeat(room, next.Name); err != nil {
+			room.LeaveSeatQueue(next.ID)
+			s.mu.Lock()
+			if s.clients.leave(client, room) {
+				client.room = nil
+			}
+			s.mu.Unlock()
+
+			s.logger.Warn("Failed to seat player from the queue",
+				zap.String("room_id", room.ID()),
+				zap.String("player_id", next.ID),
+				zap.Error(err),
+			)
+			if errors.Is(err, ErrRoomClosed) {
+				return
+			}
+			client.sendError("join_failed", err.Error())
+			continue
+		}
+
+		s.logger.Info("Seated player from the queue",
+			zap.String("room_id", room.ID()),
+			zap.String("player_id", next.ID),
+		)
+	}
+}
+
+// spectator returns the client of a player watching the room, if they are
+// still connected to it
+func (s *Server) spectator(room *GameRoom, playerID string) *Client {
+	var spectator *Client
+	s.clients.eachIn(room, func(client *Client) bool {
+		if client.playerID == playerID {
+			spectator = client
+		}
+		return spectator == nil
+	})
+	return spectator
+}
@@ -0,0 +1,118 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+)
+
+// lastRoomUpdate returns the latest room update among the messages
+func lastRoomUpdate(t *testing.T, messages []*Message) *RoomUpdateData {
+	t.Helper()
+
+	for i := len(messages) - 1; i >= 0; i-- {
+		if data, ok := messages[i].Data.(*RoomUpdateData); ok {
+			return data
+		}
+	}
+	t.Fatal("no room update sent")
+	return nil
+}
+
+func TestGameRoom_SeatQueueHoldsFreeSeats(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	scheduler := NewTimerScheduler(DefaultSchedulerResolution, DefaultCountdownInterval, fake)
+	config := DefaultRoomConfig()
+	config.MaxPlayers = 2
+	config.UpdateInterval = 0
+	room := NewGameRoom("room", "Room", config, scheduler, zaptest.NewLogger(t))
+	t.Cleanup(room.Stop)
+
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	assert.ErrorIs(t, room.AddPlayer("p3", "Player 3", 100*game.Dollar), ErrRoomFull)
+	drainEvents(room)
+
+	position, err := room.QueueForSeat("p3", "Player 3")
+	require.NoError(t, err)
+	assert.Equal(t, 1, position)
+	position, err = room.QueueForSeat("p4", "Player 4")
+	require.NoError(t, err)
+	assert.Equal(t, 2, position)
+	position, err = room.QueueForSeat("p3", "Player 3")
+	require.NoError(t, err)
+	assert.Equal(t, 1, position, "queuing again keeps the place in line")
+	_, err = room.QueueForSeat("p1", "Player 1")
+	assert.ErrorIs(t, err, ErrAlreadySeated)
+
+	update := lastRoomUpdate(t, drainEvents(room))
+	assert.Equal(t, []QueuedPlayer{{ID: "p3", Name: "Player 3"}, {ID: "p4", Name: "Player 4"}}, update.SeatQueue)
+	assert.Equal(t, 2, update.QueuePosition("p4"))
+	assert.Zero(t, update.QueuePosition("p1"))
+	assert.Len(t, update.Players, 2, "spectators are sent every seat")
+
+	// A freed seat is held for the first in line
+	require.NoError(t, room.RemovePlayer("p1"))
+	next, ok := room.nextInLine()
+	require.True(t, ok)
+	assert.Equal(t, "p3", next.ID)
+	assert.ErrorIs(t, room.AddPlayer("p5", "Player 5", 100*game.Dollar), ErrRoomFull)
+	assert.ErrorIs(t, room.AddPlayer("p4", "Player 4", 100*game.Dollar), ErrRoomFull)
+	require.NoError(t, room.AddPlayer("p3", "Player 3", 100*game.Dollar))
+
+	update = lastRoomUpdate(t, drainEvents(room))
+	assert.Equal(t, []QueuedPlayer{{ID: "p4", Name: "Player 4"}}, update.SeatQueue)
+	_, ok = room.nextInLine()
+	assert.False(t, ok, "no seat is free")
+
+	assert.True(t, room.LeaveSeatQueue("p4"))
+	assert.False(t, room.LeaveSeatQueue("p4"))
+	assert.Empty(t, lastRoomUpdate(t, drainEvents(room)).SeatQueue)
+}
+
+func TestServer_SeatsQueuedSpectator(t *testing.T) {
+	server, _ := newCleanupServer(t)
+	config := DefaultRoomConfig()
+	config.MinPlayers = 1
+	config.MaxPlayers = 1
+	config.UpdateInterval = 0
+	room, err := server.CreateRoom("r1", "Room 1", config)
+	require.NoError(t, err)
+
+	seated := newCleanupClient(t, server, "p1")
+	seated.handleJoinRoom(NewMessage(MsgJoinRoom, "r1", "p1", RoomJoinData{PlayerName: "Player 1"}))
+	require.Contains(t, room.GetPlayers(), "p1")
+
+	// The second player watches the full room from the queue
+	waiting := newCleanupClient(t, server, "p2")
+	waiting.handleJoinRoom(NewMessage(MsgJoinRoom, "r1", "p2", RoomJoinData{PlayerName: "Player 2"}))
+	watching, _ := server.clients.joined(waiting)
+	assert.Same(t, room, watching)
+	assert.NotContains(t, room.GetPlayers(), "p2")
+
+	seated.handleLeaveRoom(NewMessage(MsgLeaveRoom, "r1", "p1", nil))
+	waitFor(t, func() bool {
+		_, ok := room.GetPlayers()["p2"]
+		return ok
+	})
+	session, ok := server.Sessions().Get("p2")
+	require.True(t, ok)
+	assert.Contains(t, session.Rooms, "r1")
+
+	// A spectator who disconnects gives up their place in line
+	late := newCleanupClient(t, server, "p3")
+	late.handleJoinRoom(NewMessage(MsgJoinRoom, "r1", "p3", RoomJoinData{PlayerName: "Player 3"}))
+	server.clients.remove(late)
+	require.NoError(t, room.RemovePlayer("p2"))
+	waitFor(t, func() bool {
+		_, ok := room.nextInLine()
+		return !ok
+	})
+	assert.Empty(t, room.GetPlayers())
+}
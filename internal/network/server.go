@@ -6,25 +6,94 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+	"coinflip-game/internal/notify"
+	"coinflip-game/internal/storage"
+	"coinflip-game/internal/tracing"
 )
 
 // Server manages WebSocket connections and game rooms
 type Server struct {
 	mu        sync.RWMutex
 	rooms     map[string]*GameRoom
-	clients   map[*Client]*GameRoom
+	// Connected clients and the room each joined, locked apart from mu
+	clients   *clientRegistry
 	upgrader  websocket.Upgrader
+	// Open WebSocket connections, against MaxConnections
+	connections atomic.Int64
 	logger    *zap.Logger
 	
 	// Server configuration
 	config    *ServerConfig
 	
+	// Shared timer loop for all rooms
+	scheduler *TimerScheduler
+	
+	// Completed round results and their archival
+	results         *storage.MemoryRepository
+	archiver        *storage.Archiver
+	archiveInterval time.Duration
+	// Periodic snapshots of player balances for point-in-time restores
+	balanceSnapshotter *storage.BalanceSnapshotter
+	balanceInterval    time.Duration
+	// Settled rounds, kept apart from the per-player results above
+	rounds          storage.RoundRepository
+	
+	// Coin outcomes per round and sides backed per bet, across all rooms
+	distributionMu sync.Mutex
+	distribution   DistributionData
+	
+	// Promo codes issued through the admin endpoints
+	promos    *PromoBook
+	
+	// Happy hours from the configuration and the admin endpoints
+	promotions *PromotionSchedule
+	
+	// The running season's standings, and the archive of ended seasons
+	seasonMu  sync.Mutex
+	season    *game.SeasonBoard
+	seasons   storage.SeasonRepository
+	
+	// Each player's connection, rooms and wallet, across rooms
+	sessions  *SessionManager
+	
+	// Recent log lines and the token guarding actions, for the admin
+	// dashboard
+	logs       *logger.LogBuffer
+	adminToken string
+	
+	// Recent room, kick, error and big win events, for the admin event log
+	events *EventLog
+	
+	// What every room's open round could pay out, against MaxLiability
+	liability *LiabilityLedger
+	
+	// Serializes inventory, friend list and balance changes so a skin is
+	// never paid for twice and no change to a player's record is lost
+	inventoryMu sync.Mutex
+	// Serializes room joins so a player's balance is never brought to two
+	// rooms at once
+	joinMu sync.Mutex
+	// Serializes duel matchmaking so two players looking for a duel at
+	// once are paired rather than given a room each; duelRooms numbers the
+	// duel rooms it opens
+	duelMu    sync.Mutex
+	duelRooms int
+	
 	// Channels
 	register   chan *Client
 	unregister chan *Client
@@ -33,16 +102,26 @@ type Server struct {
 	// Context for graceful shutdown
 	ctx        context.Context
 	cancel     context.CancelFunc
+	
+	// wrapConn, when set, wraps each client connection; tests use it to
+	// inject faults
+	wrapConn   func(wsConn) wsConn
 }
 
 // Client represents a WebSocket client connection
 type Client struct {
-	conn     *websocket.Conn
+	id       uint64 // Picks the client's shard of the server's registry
+	conn     wsConn
 	server   *Server
 	room     *GameRoom
 	playerID string
 	name     string
 	send     chan []byte
+	encoding Encoding
+	protocol int // Negotiated protocol version
+	latency  latencyTracker
+	skew     clockSkew // How far the client's clock runs behind the server's
+	replay   *replayPlayback // Round being played back, if any
 	mu       sync.RWMutex
 }
 
@@ -50,29 +129,140 @@ type Client struct {
 type ServerConfig struct {
 	Host            string
 	Port            int
+	// ReadTimeout bounds reading a request's headers, including the
+	// WebSocket upgrade; WriteTimeout bounds each message written
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	MaxMessageSize  int64
 	PingPeriod      time.Duration
 	PongWait        time.Duration
+	// LatencyInterval pings clients this often to measure their latency;
+	// zero leaves measurement to the PingPeriod keepalive
+	LatencyInterval time.Duration
 	MaxRooms        int
 	MaxClientsRoom  int
+	// MaxConnections caps the open WebSocket connections; upgrades past it
+	// are refused with 503 Service Unavailable. Zero means no cap.
+	MaxConnections  int
+	// BusyRetryAfter is the retry hint sent with server_busy responses,
+	// when the connection cap or MaxRooms is reached
+	BusyRetryAfter  time.Duration
 	CleanupInterval time.Duration
+	// CountdownInterval is how often rooms broadcast the betting countdown
+	CountdownInterval time.Duration
+	
+	// Clock drives room timers; nil uses the system clock
+	Clock clock.Clock
+	
+	// EnableCompression negotiates permessage-deflate with clients that support it
+	EnableCompression bool
+	
+	// RoomDefaults is the base configuration for every new room;
+	// per-room settings supplied by clients are applied on top of it
+	RoomDefaults *RoomConfig
+	
+	// MaxLiability caps what the open rounds of every room could pay out
+	// together; zero means no cap
+	MaxLiability game.Money
+	
+	// StartingBalance is what players the server has no record of join
+	// their first room with
+	StartingBalance game.Money
+	
+	// MaxOfflineWinnings caps what offline play may add to a player's
+	// wallet in one day, however many syncs it is sent in; zero means no
+	// cap
+	MaxOfflineWinnings game.Money
+	
+	// Audit receives every room's gameplay audit events; nil disables auditing
+	Audit *logger.AuditLogger
+	
+	// Export receives every completed round for analytics; nil disables
+	// exporting
+	Export *RoundExporter
+	
+	// Rounds persists every room's settled rounds; nil keeps them in memory
+	Rounds storage.RoundRepository
+	
+	// Seasons archives the standings of ended seasons; nil keeps them in
+	// memory
+	Seasons storage.SeasonRepository
+	
+	// Promos keeps the promo codes issued through the admin endpoints and
+	// who redeemed them; nil keeps them in memory
+	Promos storage.PromoRepository
+	
+	// Notifier sends the email and Telegram notifications players opt
+	// into; nil disables them
+	Notifier *notify.Notifier
+	
+	// Storage bounds what the server keeps of players and their results
+	// in memory; the zero value keeps everything
+	Storage storage.MemoryLimits
+	
+	// Random flips every room's coin; nil uses crypto/rand
+	Random game.RandomGenerator
+	
+	// Admin protects the admin dashboard; without a username and password
+	// the dashboard is disabled
+	Admin AdminCredentials
+	
+	// RolePolicies is what players of each role may do; nil uses
+	// DefaultRolePolicies, and a role missing from the map may do nothing
+	RolePolicies map[game.Role]RolePolicy
+	
+	// SnapshotFile persists room state every SnapshotInterval and on Stop,
+	// so rooms survive a restart; empty keeps rooms in memory only
+	SnapshotFile     string
+	SnapshotInterval time.Duration
+	
+	// Promotions are the happy hours repeated every day, on top of those
+	// scheduled through the admin endpoints
+	Promotions []PromotionRule
+	
+	// BigWin is the profit from one round at which a win is listed in the
+	// admin event log; zero lists none
+	BigWin game.Money
+	
+	// Features are the groups of routes the server serves
+	Features Features
+}
+
+// pingInterval returns how often clients are pinged: every LatencyInterval
+// when it is shorter than the keepalive PingPeriod
+func (c *ServerConfig) pingInterval() time.Duration {
+	interval := c.PingPeriod
+	if interval <= 0 {
+		interval = 54 * time.Second
+	}
+	if c.LatencyInterval > 0 && c.LatencyInterval < interval {
+		interval = c.LatencyInterval
+	}
+	return interval
 }
 
 // DefaultServerConfig returns default server configuration
 func DefaultServerConfig() *ServerConfig {
 	return &ServerConfig{
-		Host:            "localhost",
-		Port:            8080,
-		ReadTimeout:     60 * time.Second,
-		WriteTimeout:    10 * time.Second,
-		MaxMessageSize:  4096, // Increased for game result messages
-		PingPeriod:      54 * time.Second,
-		PongWait:        60 * time.Second,
-		MaxRooms:        100,
-		MaxClientsRoom:  8,
-		CleanupInterval: 5 * time.Minute,
+		Host:               "localhost",
+		Port:               8080,
+		ReadTimeout:        60 * time.Second,
+		WriteTimeout:       10 * time.Second,
+		MaxMessageSize:     4096, // Increased for game result messages
+		PingPeriod:         54 * time.Second,
+		PongWait:           60 * time.Second,
+		LatencyInterval:    DefaultLatencyInterval,
+		MaxRooms:           100,
+		MaxClientsRoom:     8,
+		BusyRetryAfter:     DefaultBusyRetryAfter,
+		CleanupInterval:    5 * time.Minute,
+		CountdownInterval:  DefaultCountdownInterval,
+		EnableCompression:  true,
+		RoomDefaults:       DefaultRoomConfig(),
+		StartingBalance:    DefaultStartingBalance,
+		MaxOfflineWinnings: DefaultMaxOfflineWinnings,
+		BigWin:             DefaultBigWin,
+		Features:           AllFeatures(),
 	}
 }
 
@@ -84,11 +274,26 @@ func NewServer(config *ServerConfig, logger *zap.Logger) *Server {
 	
 	ctx, cancel := context.WithCancel(context.Background())
 	
+	// Recent log lines are kept for the admin dashboard, and errors for
+	// the event log
+	logs := newDashboardLogs()
+	events := NewEventLog(DefaultEventLogSize)
+	
 	server := &Server{
 		rooms:      make(map[string]*GameRoom),
-		clients:    make(map[*Client]*GameRoom),
-		logger:     logger,
+		clients:    newClientRegistry(),
+		logger:     events.Attach(logs.Attach(logger)),
+		logs:       logs,
+		events:     events,
+		adminToken: newAdminToken(),
 		config:     config,
+		rounds:     config.Rounds,
+		seasons:    config.Seasons,
+		scheduler:  NewTimerScheduler(DefaultSchedulerResolution, config.CountdownInterval, config.Clock),
+		promos:     NewPromoBook(config.Promos, config.Clock),
+		promotions: NewPromotionSchedule(config.Promotions, config.Clock),
+		sessions:   NewSessionManager(config.Clock),
+		liability:  NewLiabilityLedger(config.MaxLiability),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan []byte),
@@ -96,9 +301,31 @@ func NewServer(config *ServerConfig, logger *zap.Logger) *Server {
 		cancel:     cancel,
 	}
 	
+	limits := config.Storage
+	if limits.Clock == nil {
+		limits.Clock = server.scheduler.Clock()
+	}
+	if limits.Keep == nil {
+		// Only players with nothing to lose are forgotten, so the limits
+		// never take a wallet, role, friend list or badge
+		limits.Keep = func(player *game.Player) bool {
+			return !player.Fresh(config.StartingBalance)
+		}
+	}
+	server.results = storage.NewLimitedMemoryRepository(limits)
+	if server.rounds == nil {
+		server.rounds = storage.NewMemoryRoundRepository()
+	}
+	if server.seasons == nil {
+		server.seasons = storage.NewMemorySeasonRepository()
+	}
+	server.season = game.NewSeasonBoard(server.scheduler.Clock().Now())
+	
 	server.upgrader = websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
+		ReadBufferSize:    1024,
+		WriteBufferSize:   1024,
+		Subprotocols:      SupportedSubprotocols,
+		EnableCompression: config.EnableCompression,
 		CheckOrigin: func(r *http.Request) bool {
 			// Allow all origins for development
 			// In production, implement proper origin checking
@@ -109,29 +336,64 @@ func NewServer(config *ServerConfig, logger *zap.Logger) *Server {
 	return server
 }
 
-// Start starts the WebSocket server
+// Start starts the WebSocket server on the configured host and port
 func (s *Server) Start() error {
+	address := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	
+	s.logger.Info("Starting WebSocket server", zap.String("address", address))
+	return s.Serve(listener)
+}
+
+// Serve runs the server on an existing listener until it fails
+func (s *Server) Serve(listener net.Listener) error {
 	// Start the main event loop
 	go s.run()
 	
 	// Start cleanup routine
 	go s.cleanup()
 	
-	// Setup HTTP handlers
-	http.HandleFunc("/ws", s.handleWebSocket)
-	http.HandleFunc("/rooms", s.handleRooms)
-	http.HandleFunc("/health", s.handleHealth)
+	// Start the shared room timer loop, which also ends each season
+	s.scheduleSeasonRollover()
+	s.schedulePromotions()
+	go s.scheduler.Run(s.ctx.Done())
 	
-	address := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
-	s.logger.Info("Starting WebSocket server", zap.String("address", address))
+	// Start result archival routine if configured
+	if s.archiver != nil {
+		go s.archiveLoop()
+	}
+	
+	// Start balance snapshots if configured
+	if s.balanceSnapshotter != nil {
+		go s.balanceSnapshotLoop()
+	}
 	
-	return http.ListenAndServe(address, nil)
+	// Start room snapshots if configured
+	if s.config.SnapshotFile != "" {
+		go s.snapshotLoop()
+	}
+	
+	httpServer := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: s.config.ReadTimeout,
+	}
+	return httpServer.Serve(listener)
 }
 
 // Stop stops the server gracefully
 func (s *Server) Stop() {
 	s.cancel()
 	
+	// Save rooms before they are torn down so they come back on restart
+	if s.config.SnapshotFile != "" {
+		if err := s.SaveSnapshot(s.config.SnapshotFile); err != nil {
+			s.logger.Error("Failed to save room snapshot", zap.Error(err))
+		}
+	}
+	
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	
@@ -141,9 +403,9 @@ func (s *Server) Stop() {
 	}
 	
 	// Close all client connections
-	for client := range s.clients {
+	s.clients.each(func(client *Client) {
 		client.close()
-	}
+	})
 	
 	s.logger.Info("Server stopped")
 }
@@ -179,22 +441,55 @@ func (s *Server) run() {
 
 // handleWebSocket handles WebSocket connection upgrades
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := s.upgrader.Upgrade(w, r, nil)
+	requested := r.URL.Query().Get(ProtocolParam)
+	protocol, protocolErr := NegotiateProtocol(requested)
+	
+	playerID := r.URL.Query().Get(PlayerParam)
+	if reason := checkID(playerID); reason != "" {
+		http.Error(w, "invalid player: "+reason, http.StatusBadRequest)
+		return
+	}
+	
+	header := http.Header{}
+	header.Set(ProtocolSupportedHeader, versionList())
+	if protocolErr == nil {
+		header.Set(ProtocolHeader, strconv.Itoa(protocol))
+	}
+	
+	// Past the connection cap, upgrades are refused before any work is done
+	if !s.acquireConnection() {
+		s.rejectBusy(w, r, s.busyError("connection limit reached"))
+		return
+	}
+	
+	conn, err := s.upgrader.Upgrade(w, r, header)
 	if err != nil {
+		s.releaseConnection()
 		s.logger.Error("Failed to upgrade connection", zap.Error(err))
 		return
 	}
 	
+	if protocolErr != nil {
+		s.rejectProtocol(conn, requested, protocolErr)
+		s.releaseConnection()
+		return
+	}
+	
 	client := &Client{
-		conn:   conn,
-		server: s,
-		send:   make(chan []byte, 256),
+		id:       s.clients.nextID(),
+		conn:     wrapConn(s.wrapConn, conn),
+		server:   s,
+		send:     make(chan []byte, 256),
+		encoding: EncodingForSubprotocol(conn.Subprotocol()),
+		protocol: protocol,
+		playerID: playerID,
 	}
 	
 	client.conn.SetReadLimit(s.config.MaxMessageSize)
 	client.conn.SetReadDeadline(time.Now().Add(s.config.PongWait))
-	client.conn.SetPongHandler(func(string) error {
+	client.conn.SetPongHandler(func(appData string) error {
 		client.conn.SetReadDeadline(time.Now().Add(s.config.PongWait))
+		client.observeLatency(appData)
 		return nil
 	})
 	
@@ -205,28 +500,44 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	go client.readPump()
 }
 
-// handleRooms returns available rooms
+// handleRooms returns the rooms open to everyone; private rooms are left out
 func (s *Server) handleRooms(w http.ResponseWriter, r *http.Request) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	
 	type RoomInfo struct {
 		ID          string `json:"id"`
-		Name        string `json:"name"`
-		Players     int    `json:"players"`
-		MaxPlayers  int    `json:"max_players"`
-		GameState   string `json:"game_state"`
+		Name        string        `json:"name"`
+		Players     int           `json:"players"`
+		MaxPlayers  int           `json:"max_players"`
+		GameState   string        `json:"game_state"`
+		Settings    *RoomSettings `json:"settings"`
+	}
+	
+	// ?locale=es lists only the rooms playing in that language
+	locale := r.URL.Query().Get("locale")
+	if locale != "" {
+		locale, _ = NormalizeLocale(locale)
 	}
 	
 	rooms := make([]RoomInfo, 0, len(s.rooms))
 	for _, room := range s.rooms {
+		// Votes change a room's settings under its own lock
+		config := room.GetConfig()
+		if config.Private {
+			continue
+		}
+		if locale != "" && room.Locale() != locale {
+			continue
+		}
 		players := room.GetPlayers()
 		rooms = append(rooms, RoomInfo{
 			ID:         room.ID(),
 			Name:       room.Name(),
 			Players:    len(players),
-			MaxPlayers: room.config.MaxPlayers,
+			MaxPlayers: config.MaxPlayers,
 			GameState:  string(room.GetGameState()),
+			Settings:   config.Settings(),
 		})
 	}
 	
@@ -237,6 +548,161 @@ func (s *Server) handleRooms(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleArchive triggers an immediate archival pass
+func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	
+	if s.archiver == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "archive_disabled",
+			Message: "result archival is not enabled on this server",
+		})
+		return
+	}
+	
+	report, err := s.runArchive(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "archive_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+	
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleListPromos returns every promo code and its usage
+func (s *Server) handleListPromos(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	
+	promos, err := s.promos.List(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "promo_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(promos)
+}
+
+// handleListSessions returns every player session the server tracks
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.sessions.Sessions())
+}
+
+// handleCreatePromo issues a new promo code
+func (s *Server) handleCreatePromo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	
+	var spec PromoSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "invalid_data",
+			Message: "Invalid promo code data",
+		})
+		return
+	}
+	
+	promo, err := s.promos.Create(r.Context(), spec)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrInvalidPromo):
+			status = http.StatusBadRequest
+		case errors.Is(err, ErrPromoExists):
+			status = http.StatusConflict
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "promo_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+	
+	s.logger.Info("Promo code created",
+		zap.String("code", promo.Code),
+		zap.Float64("value", promo.Value.Float64()),
+		zap.Int("max_uses", promo.MaxUses),
+	)
+	
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(promo)
+}
+
+// handlePlayerStats returns a player's lifetime statistics
+func (s *Server) handlePlayerStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	
+	playerID := r.PathValue("id")
+	stats, err := s.PlayerStats(r.Context(), playerID)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "invalid_player",
+			Message: err.Error(),
+		})
+		return
+	}
+	
+	rating := s.DuelRating(r.Context(), playerID)
+	json.NewEncoder(w).Encode(PlayerStatsData{
+		PlayerID: playerID,
+		Stats:    stats,
+		Rating:   &rating,
+	})
+}
+
+// handleDistribution returns the global heads/tails distribution
+func (s *Server) handleDistribution(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.Distribution())
+}
+
+// handleFairness analyzes stored results for signs of an unfair coin or
+// wrong payouts. ?limit= caps how many recent results are analyzed.
+func (s *Server) handleFairness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	
+	limit := DefaultFairnessLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorData{
+				Code:    "invalid_limit",
+				Message: "limit must be a positive integer",
+			})
+			return
+		}
+		limit = parsed
+	}
+	
+	report, err := s.Fairness(r.Context(), limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorData{
+			Code:    "fairness_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+	
+	json.NewEncoder(w).Encode(report)
+}
+
 // handleHealth returns server health status
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	s.mu.RLock()
@@ -245,18 +711,19 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":        "healthy",
+		"protocol_versions": SupportedProtocolVersions(),
 		"active_rooms":  len(s.rooms),
-		"active_clients": len(s.clients),
+		"active_clients": s.clients.len(),
+		"open_connections": s.connections.Load(),
+		"max_connections": s.config.MaxConnections,
+		"online_players": s.sessions.Online(),
 		"uptime":        time.Since(time.Now()).String(),
 	})
 }
 
 // registerClient registers a new client
 func (s *Server) registerClient(client *Client) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	
-	s.clients[client] = nil
+	s.clients.add(client)
 	s.logger.Info("Client connected", zap.String("remote_addr", client.conn.RemoteAddr().String()))
 }
 
@@ -265,12 +732,20 @@ func (s *Server) unregisterClient(client *Client) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	
-	if room, exists := s.clients[client]; exists {
-		delete(s.clients, client)
-		
+	// Once removed the client is no longer broadcast to, so its send
+	// channel can be closed
+	if room, exists := s.clients.remove(client); exists {
 		// Remove from room if in one
 		if room != nil && client.playerID != "" {
-			room.RemovePlayer(client.playerID)
+			if balance, err := room.removePlayer(client.playerID); err == nil {
+				s.sessions.LeaveRoom(client.playerID, room.ID(), balance)
+				s.storeBalance(s.ctx, client.playerID, room, balance)
+			} else {
+				room.LeaveSeatQueue(client.playerID)
+			}
+		}
+		if client.playerID != "" {
+			s.sessions.Detach(client.playerID, client)
 		}
 		
 		close(client.send)
@@ -288,34 +763,27 @@ func (s *Server) unregisterClient(client *Client) {
 	}
 }
 
-// broadcastMessage sends a message to all clients
+// broadcastMessage sends a message to all clients. Clients too slow to
+// keep up are disconnected, and unregistered once their connection closes.
 func (s *Server) broadcastMessage(message []byte) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	
-	for client := range s.clients {
+	s.clients.each(func(client *Client) {
 		select {
 		case client.send <- message:
 		default:
-			close(client.send)
-			delete(s.clients, client)
+			client.close()
 		}
-	}
+	})
 }
 
 // pingClients sends ping messages to all clients
 func (s *Server) pingClients() {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	
-	for client := range s.clients {
+	s.clients.each(func(client *Client) {
 		select {
 		case client.send <- []byte{}:
 		default:
-			close(client.send)
-			delete(s.clients, client)
+			client.close()
 		}
-	}
+	})
 }
 
 // cleanup removes empty rooms and inactive clients
@@ -337,19 +805,57 @@ func (s *Server) cleanup() {
 	}
 }
 
-// performCleanup removes empty rooms
+// archiveLoop periodically moves old results out of hot storage
+func (s *Server) archiveLoop() {
+	ticker := time.NewTicker(s.archiveInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			s.runArchive(s.ctx)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// runArchive performs one archival pass and logs the outcome
+func (s *Server) runArchive(ctx context.Context) (*storage.ArchiveReport, error) {
+	report, err := s.archiver.Archive(ctx)
+	if err != nil {
+		s.logger.Error("Result archival failed", zap.Error(err))
+		return nil, err
+	}
+	
+	s.logger.Info("Result archival completed",
+		zap.Int("archived_results", report.ArchivedResults),
+		zap.String("archive_file", report.ArchiveFile),
+		zap.Int("purged_files", report.PurgedFiles),
+	)
+	return report, nil
+}
+
+// performCleanup removes empty rooms, once created rooms have had time to
+// fill, and restored rooms nobody came back to
 func (s *Server) performCleanup() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	
-	for roomID, room := range s.rooms {
-		players := room.GetPlayers()
-		if len(players) == 0 {
-			room.Stop()
-			delete(s.rooms, roomID)
-			s.logger.Info("Removed empty room", zap.String("room_id", roomID))
+	now := s.scheduler.Clock().Now()
+	for _, room := range s.rooms {
+		switch {
+		case len(room.GetPlayers()) == 0 && !room.unclaimed(now):
+			s.closeRoom(room, RoomClosedEmpty)
+		case room.abandoned(now):
+			s.closeRoom(room, RoomClosedAbandoned)
 		}
 	}
+	
+	if expired := s.sessions.Expire(DefaultSessionTimeout); expired > 0 {
+		s.logger.Info("Expired player sessions", zap.Int("sessions", expired))
+	}
+	s.results.Prune()
 }
 
 // CreateRoom creates a new game room
@@ -357,28 +863,113 @@ func (s *Server) CreateRoom(roomID, roomName string, config *RoomConfig) (*GameR
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	
+	return s.createRoom("", roomID, roomName, config)
+}
+
+// createRoom creates a room on behalf of a player, or of the server when
+// createdBy is empty. Callers must hold s.mu.
+func (s *Server) createRoom(createdBy, roomID, roomName string, config *RoomConfig) (*GameRoom, error) {
 	if len(s.rooms) >= s.config.MaxRooms {
-		return nil, errors.New("maximum number of rooms reached")
+		return nil, s.busyError("maximum number of rooms reached")
 	}
 	
 	if _, exists := s.rooms[roomID]; exists {
 		return nil, errors.New("room already exists")
 	}
 	
-	room := NewGameRoom(roomID, roomName, config, s.logger)
-	s.rooms[roomID] = room
-	
-	// Start room event handling
-	go s.handleRoomEvents(room)
+	room := NewGameRoom(roomID, roomName, config, s.scheduler, s.logger)
+	room.createdBy = createdBy
+	s.registerRoom(room)
 	
 	s.logger.Info("Room created", 
 		zap.String("room_id", roomID),
 		zap.String("room_name", roomName),
+		zap.String("created_by", createdBy),
 	)
+	s.recordEvent(ServerEvent{
+		Type:     EventRoomCreated,
+		RoomID:   roomID,
+		PlayerID: createdBy,
+		Message:  fmt.Sprintf("Room %q created", roomName),
+	})
 	
 	return room, nil
 }
 
+// addRoom registers an existing room, such as one restored from a snapshot
+func (s *Server) addRoom(room *GameRoom) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	if _, exists := s.rooms[room.ID()]; exists {
+		return errors.New("room already exists")
+	}
+	
+	s.registerRoom(room)
+	return nil
+}
+
+// registerRoom starts serving a room. Callers must hold s.mu.
+func (s *Server) registerRoom(room *GameRoom) {
+	room.SetAuditLogger(s.config.Audit)
+	room.SetLiabilityLedger(s.liability)
+	room.SetRoundRepository(s.rounds)
+	if s.config.Random != nil {
+		room.SetRandomGenerator(s.config.Random)
+	}
+	room.SetPromotion(s.promotions.Active(room.ID()))
+	s.rooms[room.ID()] = room
+	
+	// Start room event handling
+	go s.handleRoomEvents(room)
+}
+
+// NewRoomConfig builds a room configuration from the server defaults
+// with the given per-room settings applied
+func (s *Server) NewRoomConfig(settings *RoomSettings) (*RoomConfig, error) {
+	base := s.config.RoomDefaults
+	if base == nil {
+		base = DefaultRoomConfig()
+	}
+	
+	roomConfig, err := base.WithSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	
+	if s.config.MaxClientsRoom > 0 && roomConfig.MaxPlayers > s.config.MaxClientsRoom {
+		return nil, fmt.Errorf("%w: max players %d exceeds server limit %d",
+			ErrInvalidRoomConfig, roomConfig.MaxPlayers, s.config.MaxClientsRoom)
+	}
+	
+	return roomConfig, nil
+}
+
+// Sessions returns the players the server tracks across connections and rooms
+func (s *Server) Sessions() *SessionManager {
+	return s.sessions
+}
+
+// Results returns the repository holding completed round results
+func (s *Server) Results() *storage.MemoryRepository {
+	return s.results
+}
+
+// Rounds returns the repository holding every room's settled rounds
+func (s *Server) Rounds() storage.RoundRepository {
+	return s.rounds
+}
+
+// SetArchiver enables periodic archival of completed results.
+// Must be called before Start.
+func (s *Server) SetArchiver(archiver *storage.Archiver, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	s.archiver = archiver
+	s.archiveInterval = interval
+}
+
 // GetRoom returns a room by ID
 func (s *Server) GetRoom(roomID string) (*GameRoom, bool) {
 	s.mu.RLock()
@@ -391,45 +982,259 @@ func (s *Server) GetRoom(roomID string) (*GameRoom, bool) {
 // handleRoomEvents handles events from a game room
 func (s *Server) handleRoomEvents(room *GameRoom) {
 	for message := range room.GetEventChannel() {
+		if message.Type == MsgGameResult {
+			if resultData, ok := message.Data.(*GameResultData); ok {
+				// Storing the results is part of the round's trace
+				ctx := tracing.Extract(s.ctx, message.TraceParent)
+				s.recordResults(ctx, resultData)
+				s.recordDuel(ctx, room, resultData)
+				s.recordBalances(resultData)
+				s.exportRound(room, resultData)
+				s.recordBigWins(room, resultData)
+			}
+		}
+		
 		// Broadcast room events to all clients in the room
 		s.broadcastToRoom(room, message)
+		
+		// Players hear where the round leaves them in the season after
+		// its result
+		if resultData, ok := message.Data.(*GameResultData); ok && message.Type == MsgGameResult {
+			s.sendSeasonStandings(resultData)
+		}
+		
+		// Every change of seats is followed by a room update, so a seat
+		// freed for the queue is noticed here
+		if message.Type == MsgRoomUpdate {
+			s.seatFromQueue(room)
+		}
+		
+		// A player the room released for idling gives up their seat
+		if idle, ok := message.Data.(*IdleData); ok && idle.Step == IdleStepRelease && idle.RoundsLeft == 0 {
+			s.releaseIdleSeat(room, message.PlayerID)
+		}
 	}
 }
 
-// broadcastToRoom sends a message to all clients in a specific room
-func (s *Server) broadcastToRoom(room *GameRoom, message *Message) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	
-	data, err := message.ToJSON()
-	if err != nil {
-		s.logger.Error("Failed to serialize message", zap.Error(err))
-		return
+// exportRound appends a completed round to the analytics export, if one
+// is configured
+func (s *Server) exportRound(room *GameRoom, data *GameResultData) {
+	if err := s.config.Export.Export(room.ID(), data); err != nil {
+		s.logger.Error("Failed to export round",
+			zap.String("room_id", room.ID()),
+			zap.String("round_id", data.RoundID),
+			zap.Error(err),
+		)
+	}
+}
+
+// recordBalances keeps each player's session wallet in step with the
+// balances a round settled at
+func (s *Server) recordBalances(data *GameResultData) {
+	for _, outcomes := range [][]PlayerResult{data.Winners, data.Losers} {
+		for _, outcome := range outcomes {
+			s.sessions.SetBalance(outcome.PlayerID, outcome.NewBalance)
+		}
 	}
+}
+
+// recordResults stores the outcome of every bet of a completed round, so a
+// player hedging both sides gets one result per position
+func (s *Server) recordResults(ctx context.Context, data *GameResultData) {
+	outcomes := make([]PlayerResult, 0, len(data.Winners)+len(data.Losers))
+	outcomes = append(outcomes, data.Winners...)
+	outcomes = append(outcomes, data.Losers...)
+	
+	s.recordDistribution(data.CoinResult, outcomes)
 	
-	for client, clientRoom := range s.clients {
-		if clientRoom == room {
-			select {
-			case client.send <- data:
-			default:
-				close(client.send)
-				delete(s.clients, client)
+	for _, outcome := range outcomes {
+		for _, bet := range outcome.Bets {
+			won := bet.Choice == data.CoinResult
+			result := &game.Result{
+				ID:        fmt.Sprintf("%s_%s_%s", data.RoundID, outcome.PlayerID, bet.Choice),
+				PlayerID:  outcome.PlayerID,
+				Side:      data.CoinResult,
+				Won:       won,
+				Payout:    betPayout(bet, data.CoinResult, outcome),
+				Timestamp: data.Timestamp,
+				Seed:      data.FinalSeed,
+				Multiplier: betMultiplier(bet, data.CoinResult, outcome),
+				Insurance:  betInsurance(bet, data.CoinResult),
+				Bet: &game.Bet{
+					ID:        bet.BetID,
+					Amount:    bet.Amount,
+					Choice:    bet.Choice,
+					Timestamp: data.Timestamp,
+					Premium:   bet.Premium,
+					Coverage:  bet.Coverage,
+					Practice:  bet.Practice,
+				},
+			}
+			
+			if err := s.results.SaveResult(ctx, result); err != nil {
+				s.logger.Error("Failed to record round result",
+					zap.String("round_id", data.RoundID),
+					zap.String("player_id", outcome.PlayerID),
+					zap.Error(err),
+				)
 			}
 		}
+		
+		s.recordPlayerStats(ctx, data.CoinResult, outcome)
+		s.recordSeason(ctx, outcome)
 	}
 }
 
-// Client methods
+// betPayout splits a player's round payout onto one of their bets. Only
+// bets on the winning side pay, and a player holds at most one of those.
+func betPayout(bet *BetData, coinResult game.Side, outcome PlayerResult) game.Money {
+	if bet.Choice != coinResult {
+		return 0
+	}
+	return outcome.Payout
+}
 
-// readPump handles reading messages from the WebSocket connection
-func (c *Client) readPump() {
+// betMultiplier returns the streak bonus and happy hour boost applied to
+// one of a player's bets, so the recorded result pays what it says
+func betMultiplier(bet *BetData, coinResult game.Side, outcome PlayerResult) float64 {
+	if bet.Choice != coinResult || outcome.PayoutMultiplier() <= 1 {
+		return 0
+	}
+	return outcome.PayoutMultiplier()
+}
+
+// recordPlayerStats adds one round outcome to the player's lifetime stats,
+// or to their practice ledger for practice bets
+func (s *Server) recordPlayerStats(ctx context.Context, coinResult game.Side, outcome PlayerResult) {
+	if len(outcome.Bets) == 0 {
+		return
+	}
+	
+	player := s.playerRecord(ctx, outcome.PlayerID)
+	
+	stats := &player.Stats
+	if outcome.Practice {
+		stats = &player.Practice
+	} else {
+		player.Balance = outcome.NewBalance
+		s.notifyOutcome(player, coinResult, outcome)
+	}
+	for _, bet := range outcome.Bets {
+		stats.Record(bet.Choice, coinResult, betCost(bet),
+			betPayout(bet, coinResult, outcome)+betInsurance(bet, coinResult))
+	}
+	
+	if err := s.results.SavePlayer(ctx, player); err != nil {
+		s.logger.Error("Failed to record player stats",
+			zap.String("player_id", outcome.PlayerID),
+			zap.Error(err),
+		)
+	}
+}
+
+// recordDistribution counts a round's coin result and every side bet on
+func (s *Server) recordDistribution(coinResult game.Side, outcomes []PlayerResult) {
+	s.distributionMu.Lock()
+	defer s.distributionMu.Unlock()
+	
+	s.distribution.Outcomes.Add(coinResult)
+	for _, outcome := range outcomes {
+		for _, bet := range outcome.Bets {
+			s.distribution.Choices.Add(bet.Choice)
+		}
+	}
+}
+
+// Distribution returns the heads/tails distribution of round results and
+// player choices since the server started
+func (s *Server) Distribution() DistributionData {
+	s.distributionMu.Lock()
+	defer s.distributionMu.Unlock()
+	
+	return s.distribution
+}
+
+// PlayerStats returns the lifetime statistics the server has recorded for a
+// player. Players without any settled bets get zero stats.
+func (s *Server) PlayerStats(ctx context.Context, playerID string) (*game.Stats, error) {
+	return s.results.GetStats(ctx, playerID)
+}
+
+// DefaultFairnessLimit is how many recent results the fairness report
+// analyzes unless asked otherwise
+const DefaultFairnessLimit = 10000
+
+// Fairness analyzes up to limit recent results against a fair coin and the
+// default rooms' payout ratio
+func (s *Server) Fairness(ctx context.Context, limit int) (*game.FairnessReport, error) {
+	results, err := s.results.GetResults(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load results: %w", err)
+	}
+	
+	payoutRatio := DefaultRoomConfig().PayoutRatio
+	if s.config.RoomDefaults != nil {
+		payoutRatio = s.config.RoomDefaults.PayoutRatio
+	}
+	
+	return game.AnalyzeFairness(results, payoutRatio), nil
+}
+
+// broadcastToRoom sends a message to all clients in a specific room
+func (s *Server) broadcastToRoom(room *GameRoom, message *Message) {
+	span := startBroadcastSpan(room, message)
+	defer span.End()
+	
+	// Encode once per wire format in use rather than once per client
+	type wireFormat struct {
+		encoding Encoding
+		protocol int
+	}
+	encoded := make(map[wireFormat][]byte)
+	recipients := 0
+	
+	s.clients.eachIn(room, func(client *Client) bool {
+		format := wireFormat{client.encoding, client.protocol}
+		data, ok := encoded[format]
+		if !ok {
+			var err error
+			data, err = message.ForProtocol(client.protocol).Encode(client.encoding)
+			if err != nil {
+				s.logger.Error("Failed to serialize message",
+					zap.String("encoding", string(client.encoding)),
+					zap.Error(err),
+				)
+				return false
+			}
+			encoded[format] = data
+		}
+		
+		// A client too slow to keep up is disconnected
+		select {
+		case client.send <- data:
+			recipients++
+		default:
+			client.close()
+		}
+		return true
+	})
+	span.SetAttributes(attribute.Int("broadcast.recipients", recipients))
+}
+
+// Client methods
+
+// readPump handles reading messages from the WebSocket connection
+func (c *Client) readPump() {
 	defer func() {
+		// Unregistering closes the send channel a replay writes to
+		c.stopReplay()
 		c.server.unregister <- c
 		c.conn.Close()
+		c.server.releaseConnection()
 	}()
 	
 	for {
-		_, messageBytes, err := c.conn.ReadMessage()
+		frameType, messageBytes, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				c.server.logger.Error("WebSocket error", zap.Error(err))
@@ -438,13 +1243,13 @@ func (c *Client) readPump() {
 		}
 		
 		// Parse and handle the message
-		c.handleMessage(messageBytes)
+		c.handleMessage(frameType, messageBytes)
 	}
 }
 
 // writePump handles writing messages to the WebSocket connection
 func (c *Client) writePump() {
-	ticker := time.NewTicker(c.server.config.PingPeriod)
+	ticker := time.NewTicker(c.server.config.pingInterval())
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
@@ -461,41 +1266,143 @@ func (c *Client) writePump() {
 			
 			if len(message) == 0 {
 				// Ping message
-				if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				if err := c.conn.WriteMessage(websocket.PingMessage, pingPayload(time.Now())); err != nil {
 					return
 				}
 			} else {
 				// Regular message
-				if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				frameType := websocket.TextMessage
+				if c.encoding.IsBinary() {
+					frameType = websocket.BinaryMessage
+				}
+				if err := c.conn.WriteMessage(frameType, message); err != nil {
 					return
 				}
 			}
 			
 		case <-ticker.C:
 			c.conn.SetWriteDeadline(time.Now().Add(c.server.config.WriteTimeout))
-			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			if err := c.conn.WriteMessage(websocket.PingMessage, pingPayload(time.Now())); err != nil {
 				return
 			}
 		}
 	}
 }
 
+// observeLatency records the round trip of a pong answering a latency ping
+// and passes it on to the client's room
+func (c *Client) observeLatency(appData string) {
+	rtt, ok := roundTrip(appData, time.Now())
+	if !ok {
+		return
+	}
+	latency := c.latency.observe(rtt)
+	
+	c.server.mu.RLock()
+	room, playerID := c.room, c.playerID
+	c.server.mu.RUnlock()
+	
+	if room != nil {
+		room.SetPlayerLatency(playerID, latency)
+	}
+}
+
+// Latency returns the client's smoothed round trip time, zero until the
+// first pong
+func (c *Client) Latency() time.Duration {
+	return c.latency.latency()
+}
+
 // handleMessage processes incoming messages from clients
-func (c *Client) handleMessage(messageBytes []byte) {
-	var msg Message
-	if err := json.Unmarshal(messageBytes, &msg); err != nil {
+func (c *Client) handleMessage(frameType int, messageBytes []byte) {
+	received := c.server.scheduler.Clock().Now()
+	
+	// Binary frames always carry msgpack; text frames always carry JSON
+	encoding := EncodingJSON
+	if frameType == websocket.BinaryMessage {
+		encoding = EncodingMsgPack
+	}
+	
+	msg, err := DecodeMessage(messageBytes, encoding)
+	if err != nil {
 		c.server.logger.Error("Failed to parse message", zap.Error(err))
 		c.sendError("invalid_message", "Failed to parse message")
 		return
 	}
 	
+	// Learn the client's clock from every message, once it was handled so
+	// a bet is timed by the skew measured before it arrived
+	defer c.skew.observe(msg.Timestamp, received, c.Latency())
+	
+	_, span := c.startMessageSpan(msg)
+	defer span.End()
+	defer c.linkRound(span)
+	
+	// Reject bad input before it reaches a handler
+	if err := ValidateMessage(msg); err != nil {
+		span.RecordError(err)
+		var invalid *ValidationError
+		errors.As(err, &invalid)
+		c.server.logger.Warn("Rejected invalid message",
+			zap.String("type", string(msg.Type)),
+			zap.String("field", invalid.Field),
+			zap.String("reason", invalid.Reason),
+		)
+		c.sendError(invalid.Code, invalid.Error())
+		return
+	}
+	
 	switch msg.Type {
 	case MsgJoinRoom:
-		c.handleJoinRoom(&msg)
+		c.handleJoinRoom(msg)
+	case MsgCreateRoom:
+		c.handleCreateRoom(msg)
 	case MsgLeaveRoom:
-		c.handleLeaveRoom(&msg)
+		c.handleLeaveRoom(msg)
 	case MsgBetPlaced:
-		c.handlePlaceBet(&msg)
+		c.handlePlaceBet(msg)
+	case MsgCancelBet:
+		c.handleCancelBet(msg)
+	case MsgUpdateBet:
+		c.handleUpdateBet(msg)
+	case MsgQueuedBet:
+		c.handleQueuedBet(msg)
+	case MsgBetSlip:
+		c.handleBetSlip(msg)
+	case MsgChat:
+		c.handleChat(msg)
+	case MsgConfigProposal:
+		c.handleConfigProposal(msg)
+	case MsgConfigVote:
+		c.handleConfigVote(msg)
+	case MsgPauseProposal:
+		c.handlePauseProposal(msg)
+	case MsgPauseVote:
+		c.handlePauseVote(msg)
+	case MsgPlayerStats:
+		c.handlePlayerStats(msg)
+	case MsgFindDuel:
+		c.handleFindDuel(msg)
+	case MsgRedeemCode:
+		c.handleRedeemCode(msg)
+	case MsgRegister:
+		c.handleRegister(msg)
+	case MsgWalletSync:
+		c.handleWalletSync(msg)
+	case MsgInventory, MsgBuySkin, MsgEquipSkin:
+		c.handleSkin(msg)
+	case MsgFriends, MsgAddFriend, MsgRemoveFriend:
+		c.handleFriends(msg)
+	case MsgNotifySettings:
+		c.handleNotifySettings(msg)
+	case MsgRoomInvite:
+		c.handleRoomInvite(msg)
+	case MsgReplayRound:
+		c.handleReplayRound(msg)
+	case MsgStopReplay:
+		c.handleStopReplay()
+	case MsgKickPlayer:
+		c.handleKickPlayer(msg)
 	default:
 		c.server.logger.Warn("Unknown message type", zap.String("type", string(msg.Type)))
 	}
@@ -509,36 +1416,185 @@ func (c *Client) handleJoinRoom(msg *Message) {
 		return
 	}
 	
+	// A connection acts for the player it connected or first joined as
+	if c.playerID != "" && msg.PlayerID != c.playerID {
+		c.sendError("join_failed", ErrWrongPlayer.Error())
+		return
+	}
+	
+	if joinData.Watch {
+		c.watchRoom(msg, joinData.PlayerName)
+		return
+	}
+	
+	// The client acts as the player it joins as from here on, including
+	// when opening the room
+	c.playerID = msg.PlayerID
+	c.name = joinData.PlayerName
+	
 	// Get or create room
 	room, exists := c.server.GetRoom(msg.RoomID)
 	if !exists {
 		// Auto-create room for development
-		var err error
-		room, err = c.server.CreateRoom(msg.RoomID, fmt.Sprintf("Room %s", msg.RoomID), DefaultRoomConfig())
+		roomConfig, err := c.server.NewRoomConfig(joinData.Settings)
+		if err != nil {
+			c.sendError("invalid_room_settings", err.Error())
+			return
+		}
+		
+		room, err = c.server.createRoomAs(c.playerID, c.connectionRole(), msg.RoomID, fmt.Sprintf("Room %s", msg.RoomID), roomConfig)
+		var busy *ServerBusyError
+		if errors.As(err, &busy) {
+			c.sendBusy(busy)
+			return
+		}
 		if err != nil {
 			c.sendError("room_creation_failed", err.Error())
 			return
 		}
 	}
 	
-	// Add player to room
-	c.playerID = msg.PlayerID
-	c.name = joinData.PlayerName
-	if err := room.AddPlayer(msg.PlayerID, joinData.PlayerName, joinData.Balance); err != nil {
-		c.sendError("join_failed", err.Error())
-		return
-	}
-	
-	// Update client-room mapping
+	// Map the client to the room first so it receives the room update
+	// broadcast by AddPlayer
 	c.server.mu.Lock()
-	c.server.clients[c] = room
+	previous := c.room
+	c.server.clients.move(c, room)
 	c.room = room
 	c.server.mu.Unlock()
 	
+	err := c.takeSeat(room, joinData.PlayerName)
+	if errors.Is(err, ErrRoomFull) {
+		// The client stays to watch the full room until a seat frees up
+		var position int
+		if position, err = room.QueueForSeat(msg.PlayerID, joinData.PlayerName); err == nil {
+			c.leavePreviousQueue(previous, room)
+			c.server.logger.Info("Player queued for a seat",
+				zap.String("player_id", msg.PlayerID),
+				zap.String("room_id", msg.RoomID),
+				zap.Int("position", position),
+			)
+			return
+		}
+	}
+	if err != nil {
+		c.server.mu.Lock()
+		// Cleanup returns clients to the lobby when it closes their room
+		notified := c.room != room
+		c.server.clients.move(c, previous)
+		c.room = previous
+		c.server.mu.Unlock()
+		
+		// The room was closed between looking it up and taking a seat
+		if errors.Is(err, ErrRoomClosed) {
+			if !notified {
+				c.sendRoomClosed(room)
+			}
+			return
+		}
+		c.sendError("join_failed", err.Error())
+		return
+	}
+	c.leavePreviousQueue(previous, room)
+	
 	c.server.logger.Info("Player joined room",
 		zap.String("player_id", msg.PlayerID),
 		zap.String("room_id", msg.RoomID),
 	)
+	c.sendSeason(msg.PlayerID)
+	c.sendRoomStats(room)
+}
+
+// takeSeat seats the client's player in the room with the balance the
+// server holds for them
+func (c *Client) takeSeat(room *GameRoom, playerName string) error {
+	// Joins are serialized so a player's balance is brought to one room
+	// at a time
+	c.server.joinMu.Lock()
+	defer c.server.joinMu.Unlock()
+	
+	// The server keeps every player's balance; the one in the join message
+	// is ignored so a tampered client cannot add funds
+	balance, err := c.server.JoinBalance(c.server.ctx, c.playerID, room)
+	if err == nil {
+		err = room.AddPlayer(c.playerID, playerName, balance)
+	}
+	if err != nil {
+		return err
+	}
+	
+	// Show the player's coin skin to the room
+	if skin := c.server.equippedSkin(c.server.ctx, c.playerID); skin != game.DefaultSkin {
+		room.SetSkin(c.playerID, skin)
+	}
+	c.server.showRating(room, c.playerID)
+	if latency := c.Latency(); latency > 0 {
+		room.SetPlayerLatency(c.playerID, latency)
+	}
+	
+	// A reclaimed seat keeps the balance it was left with
+	balance, _ = room.PlayerBalance(c.playerID)
+	c.server.sessions.Attach(c.playerID, playerName, c)
+	c.server.sessions.JoinRoom(c.playerID, room.ID(), balance)
+	
+	// Tell the player when someone in the room already had their name
+	if seated, ok := room.PlayerName(c.playerID); ok && seated != playerName {
+		c.sendMessage(NewMessage(MsgJoinRoom, room.ID(), c.playerID, RoomJoinData{
+			PlayerName:    seated,
+			RequestedName: playerName,
+			Balance:       balance,
+		}))
+	}
+	return nil
+}
+
+// leavePreviousQueue gives up the place in line the client held in the
+// room it watched before joining another
+func (c *Client) leavePreviousQueue(previous, joined *GameRoom) {
+	if previous != nil && previous != joined {
+		previous.LeaveSeatQueue(c.playerID)
+	}
+}
+
+// handleCreateRoom handles explicit room creation requests
+func (c *Client) handleCreateRoom(msg *Message) {
+	var createData RoomCreateData
+	if err := msg.GetData(&createData); err != nil {
+		c.sendError("invalid_data", "Invalid create room data")
+		return
+	}
+	
+	if msg.RoomID == "" {
+		c.sendError("invalid_data", "Room ID is required")
+		return
+	}
+	
+	roomConfig, err := c.server.NewRoomConfig(createData.Settings)
+	if err != nil {
+		c.sendError("invalid_room_settings", err.Error())
+		return
+	}
+	
+	roomName := createData.RoomName
+	if roomName == "" {
+		roomName = fmt.Sprintf("Room %s", msg.RoomID)
+	}
+	
+	_, err = c.server.createRoomAs(c.playerID, c.connectionRole(), msg.RoomID, roomName, roomConfig)
+	var busy *ServerBusyError
+	if errors.As(err, &busy) {
+		c.sendBusy(busy)
+		return
+	}
+	if err != nil {
+		c.sendError("room_creation_failed", err.Error())
+		return
+	}
+	
+	// Echo the effective settings back so the creator knows what was applied
+	c.sendMessage(NewMessage(MsgCreateRoom, msg.RoomID, c.playerID, RoomCreateData{
+		RoomName: roomName,
+		Settings: roomConfig.Settings(),
+	}))
 }
 
 // handleLeaveRoom handles room leave requests
@@ -548,10 +1604,15 @@ func (c *Client) handleLeaveRoom(msg *Message) {
 		return
 	}
 	
-	c.room.RemovePlayer(c.playerID)
+	if balance, err := c.room.removePlayer(c.playerID); err == nil {
+		c.server.sessions.LeaveRoom(c.playerID, c.room.ID(), balance)
+		c.server.storeBalance(c.server.ctx, c.playerID, c.room, balance)
+	} else {
+		c.room.LeaveSeatQueue(c.playerID)
+	}
 	
 	c.server.mu.Lock()
-	c.server.clients[c] = nil
+	c.server.clients.move(c, nil)
 	c.room = nil
 	c.server.mu.Unlock()
 }
@@ -569,20 +1630,413 @@ func (c *Client) handlePlaceBet(msg *Message) {
 		return
 	}
 	
-	if err := c.room.PlaceBet(c.playerID, betData.Amount, betData.Choice); err != nil {
-		c.sendError("bet_failed", err.Error())
+	if err := c.room.PlaceTimedBet(c.playerID, betData, c.sentAt(betData.SentAtMs)); err != nil {
+		c.sendBetError("bet_failed", err, &betData)
 		return
 	}
 }
 
+// sentAt moves the time a client sent a bet, in Unix milliseconds on its
+// clock, onto the server's clock; a bet without a timestamp counts as sent
+// when it arrived
+func (c *Client) sentAt(sentAtMs int64) time.Time {
+	if sentAtMs > 0 {
+		if corrected, ok := c.skew.correct(time.UnixMilli(sentAtMs)); ok {
+			return corrected
+		}
+	}
+	return c.server.scheduler.Clock().Now()
+}
+
+// handleQueuedBet queues the client's bet for the next round, or withdraws
+// it when the amount is zero
+func (c *Client) handleQueuedBet(msg *Message) {
+	if c.room == nil {
+		c.sendError("not_in_room", "Not currently in a room")
+		return
+	}
+	
+	var queued QueuedBetData
+	if err := msg.GetData(&queued); err != nil {
+		c.sendError("invalid_bet_data", "Invalid bet data")
+		return
+	}
+	
+	var err error
+	if queued.Bet.Amount == 0 {
+		err = c.room.CancelQueuedBet(c.playerID)
+	} else if queued.Bet.Insured {
+		err = c.room.QueueInsuredBet(c.playerID, queued.Bet.Amount, queued.Bet.Choice)
+	} else {
+		err = c.room.QueueBet(c.playerID, queued.Bet.Amount, queued.Bet.Choice)
+	}
+	if err != nil {
+		c.sendBetError("bet_failed", err, &queued.Bet)
+	}
+}
+
+// handleBetSlip stages the client's bets for the next rounds, or clears
+// the slip when it holds no bets
+func (c *Client) handleBetSlip(msg *Message) {
+	if c.room == nil {
+		c.sendError("not_in_room", "Not currently in a room")
+		return
+	}
+	
+	var slip BetSlipData
+	if err := msg.GetData(&slip); err != nil {
+		c.sendError("invalid_bet_data", "Invalid bet slip data")
+		return
+	}
+	
+	if err := c.room.SetBetSlip(c.playerID, slip.Bets); err != nil {
+		c.sendBetError("bet_failed", err, nil)
+	}
+}
+
+// handleUpdateBet changes the client's bet while betting is open
+func (c *Client) handleUpdateBet(msg *Message) {
+	if c.room == nil {
+		c.sendError("not_in_room", "Not currently in a room")
+		return
+	}
+	
+	var betData BetData
+	if err := msg.GetData(&betData); err != nil {
+		c.sendError("invalid_bet_data", "Invalid bet data")
+		return
+	}
+	
+	if err := c.room.UpdateTimedBet(c.playerID, betData.Amount, betData.Choice, c.sentAt(betData.SentAtMs)); err != nil {
+		c.sendBetError("update_bet_failed", err, &betData)
+		return
+	}
+}
+
+// handleCancelBet withdraws the client's bet while betting is open
+func (c *Client) handleCancelBet(msg *Message) {
+	if c.room == nil {
+		c.sendError("not_in_room", "Not currently in a room")
+		return
+	}
+	
+	// Cancellations may carry when they were sent, as bets do
+	var betData BetData
+	msg.GetData(&betData)
+	
+	if err := c.room.CancelTimedBet(c.playerID, c.sentAt(betData.SentAtMs)); err != nil {
+		c.sendError("cancel_bet_failed", err.Error())
+		return
+	}
+}
+
+// handleChat relays a chat message to everyone in the client's room
+func (c *Client) handleChat(msg *Message) {
+	if c.room == nil {
+		c.sendError("not_in_room", "Not currently in a room")
+		return
+	}
+	
+	var chat ChatData
+	if err := msg.GetData(&chat); err != nil {
+		c.sendError("invalid_data", "Invalid chat message")
+		return
+	}
+	
+	// ValidateMessage has checked the trimmed text's length and encoding
+	text := strings.TrimSpace(chat.Text)
+	name := c.name
+	if seated, ok := c.room.PlayerName(c.playerID); ok {
+		name = seated
+	}
+	c.server.broadcastToRoom(c.room, NewMessage(MsgChat, c.room.ID(), c.playerID, ChatData{
+		PlayerName: name,
+		Text:       text,
+	}))
+}
+
+// handleConfigProposal opens a vote on changing the client's room settings
+func (c *Client) handleConfigProposal(msg *Message) {
+	if c.room == nil {
+		c.sendError("not_in_room", "Not currently in a room")
+		return
+	}
+	
+	var proposal ConfigProposalData
+	if err := msg.GetData(&proposal); err != nil {
+		c.sendError("invalid_data", "Invalid settings proposal")
+		return
+	}
+	
+	// Anyone seated may vote, so only proposing needs the permission
+	if err := c.server.authorize(c.playerID, c.connectionRole(), PermChangeConfig); err != nil {
+		c.sendError("permission_denied", err.Error())
+		return
+	}
+	
+	if err := c.room.ProposeConfig(c.playerID, proposal.Settings); err != nil {
+		c.sendError("proposal_failed", err.Error())
+		return
+	}
+}
+
+// handleConfigVote records the client's vote on the room's open proposal
+func (c *Client) handleConfigVote(msg *Message) {
+	if c.room == nil {
+		c.sendError("not_in_room", "Not currently in a room")
+		return
+	}
+	
+	var vote ConfigVoteData
+	if err := msg.GetData(&vote); err != nil {
+		c.sendError("invalid_data", "Invalid settings vote")
+		return
+	}
+	
+	if err := c.room.VoteConfig(c.playerID, vote.ProposalID, vote.Approve); err != nil {
+		c.sendError("vote_failed", err.Error())
+		return
+	}
+}
+
+// handlePauseProposal asks the client's room to pause or resume
+func (c *Client) handlePauseProposal(msg *Message) {
+	if c.room == nil {
+		c.sendError("not_in_room", "Not currently in a room")
+		return
+	}
+	
+	var proposal PauseProposalData
+	if err := msg.GetData(&proposal); err != nil {
+		c.sendError("invalid_data", "Invalid pause request")
+		return
+	}
+	
+	if err := c.room.ProposePause(c.playerID, proposal.Action); err != nil {
+		c.sendError("pause_failed", err.Error())
+		return
+	}
+}
+
+// handlePauseVote records the client's vote on the room's open pause
+// proposal
+func (c *Client) handlePauseVote(msg *Message) {
+	if c.room == nil {
+		c.sendError("not_in_room", "Not currently in a room")
+		return
+	}
+	
+	var vote PauseVoteData
+	if err := msg.GetData(&vote); err != nil {
+		c.sendError("invalid_data", "Invalid pause vote")
+		return
+	}
+	
+	if err := c.room.VotePause(c.playerID, vote.ProposalID, vote.Approve); err != nil {
+		c.sendError("vote_failed", err.Error())
+		return
+	}
+}
+
+// handlePlayerStats replies with a player's lifetime statistics
+func (c *Client) handlePlayerStats(msg *Message) {
+	var statsData PlayerStatsData
+	if err := msg.GetData(&statsData); err != nil {
+		c.sendError("invalid_data", "Invalid player stats request")
+		return
+	}
+	
+	// Default to the requesting player's own stats
+	if statsData.PlayerID == "" {
+		statsData.PlayerID = msg.PlayerID
+	}
+	
+	stats, err := c.server.PlayerStats(c.server.ctx, statsData.PlayerID)
+	if err != nil {
+		c.sendError("invalid_player", err.Error())
+		return
+	}
+	
+	rating := c.server.DuelRating(c.server.ctx, statsData.PlayerID)
+	c.sendMessage(NewMessage(MsgPlayerStats, msg.RoomID, c.playerID, PlayerStatsData{
+		PlayerID: statsData.PlayerID,
+		Stats:    stats,
+		Rating:   &rating,
+	}))
+}
+
+// handleRedeemCode credits a promo code to the client's bankroll
+func (c *Client) handleRedeemCode(msg *Message) {
+	var redeem RedeemCodeData
+	if err := msg.GetData(&redeem); err != nil || redeem.Code == "" {
+		c.sendError("invalid_data", "Invalid promo code data")
+		return
+	}
+	
+	playerID, ok := c.identified("redeem_failed")
+	if !ok {
+		return
+	}
+	
+	credited, err := c.server.RedeemPromo(c.server.ctx, playerID, c.room, redeem.Code)
+	if err != nil {
+		c.sendError("redeem_failed", err.Error())
+		return
+	}
+	
+	c.sendMessage(NewMessage(MsgRedeemCode, msg.RoomID, playerID, credited))
+}
+
+// handleRegister upgrades the client's guest player to a registered account
+func (c *Client) handleRegister(msg *Message) {
+	var register RegisterData
+	if err := msg.GetData(&register); err != nil {
+		c.sendError("invalid_data", "Invalid register data")
+		return
+	}
+	
+	if c.room != nil {
+		c.sendError("register_failed", ErrPlayerInRoom.Error())
+		return
+	}
+	
+	// Only the guest the connection acts for can be registered
+	playerID, ok := c.identified("register_failed")
+	if !ok {
+		return
+	}
+	
+	registered, err := c.server.Register(c.server.ctx, playerID, register.Account)
+	if err != nil {
+		c.sendError("register_failed", err.Error())
+		return
+	}
+	c.playerID = registered.Account
+	
+	c.sendMessage(NewMessage(MsgRegister, msg.RoomID, registered.Account, registered))
+}
+
+// handleSkin reports, buys or equips the client's coin skins
+func (c *Client) handleSkin(msg *Message) {
+	var skinData SkinData
+	if err := msg.GetData(&skinData); err != nil {
+		c.sendError("invalid_data", "Invalid skin data")
+		return
+	}
+	
+	playerID, ok := c.identified("skin_failed")
+	if !ok {
+		return
+	}
+	
+	var (
+		reply *SkinData
+		err   error
+	)
+	switch msg.Type {
+	case MsgBuySkin:
+		reply, err = c.server.BuySkin(c.server.ctx, playerID, c.room, skinData.SkinID)
+	case MsgEquipSkin:
+		reply, err = c.server.EquipSkin(c.server.ctx, playerID, c.room, skinData.SkinID)
+	default:
+		reply, err = c.server.Inventory(c.server.ctx, playerID, c.room)
+	}
+	if err != nil {
+		c.sendError("skin_failed", err.Error())
+		return
+	}
+	
+	c.sendMessage(NewMessage(msg.Type, msg.RoomID, playerID, reply))
+}
+
+// handleFriends reports, adds or removes the client's friends
+func (c *Client) handleFriends(msg *Message) {
+	var friendsData FriendsData
+	if err := msg.GetData(&friendsData); err != nil {
+		c.sendError("invalid_data", "Invalid friends data")
+		return
+	}
+	
+	playerID, ok := c.identified("friends_failed")
+	if !ok {
+		return
+	}
+	
+	var (
+		reply *FriendsData
+		err   error
+	)
+	switch msg.Type {
+	case MsgAddFriend:
+		reply, err = c.server.AddFriend(c.server.ctx, playerID, friendsData.Friend)
+	case MsgRemoveFriend:
+		reply, err = c.server.RemoveFriend(c.server.ctx, playerID, friendsData.Friend)
+	default:
+		reply, err = c.server.Friends(c.server.ctx, playerID)
+	}
+	if err != nil {
+		c.sendError("friends_failed", err.Error())
+		return
+	}
+	
+	c.sendMessage(NewMessage(msg.Type, msg.RoomID, playerID, reply))
+}
+
+// handleRoomInvite invites one of the client's friends to their room
+func (c *Client) handleRoomInvite(msg *Message) {
+	if c.room == nil {
+		c.sendError("not_in_room", "Not currently in a room")
+		return
+	}
+	
+	var inviteData InviteData
+	if err := msg.GetData(&inviteData); err != nil {
+		c.sendError("invalid_data", "Invalid invite data")
+		return
+	}
+	
+	invite, err := c.server.Invite(c.server.ctx, c.playerID, c.room, inviteData.To)
+	if err != nil {
+		c.sendError("invite_failed", err.Error())
+		return
+	}
+	
+	c.sendMessage(NewMessage(MsgRoomInvite, invite.RoomID, c.playerID, invite))
+}
+
 // sendError sends an error message to the client
 func (c *Client) sendError(code, message string) {
+	// Players in a room read errors in the room's language
+	if room := c.room; room != nil {
+		message = Localize(room.Locale(), message)
+	}
 	errorMsg := NewMessage(MsgError, "", c.playerID, ErrorData{
 		Code:    code,
 		Message: message,
 	})
 	
-	if data, err := errorMsg.ToJSON(); err == nil {
+	c.sendMessage(errorMsg)
+}
+
+// sendBetError answers a refused bet with its reason from the rejection
+// catalog and the stake that would fit, in the room's language. bet is the
+// refused bet, or nil when the error covers several.
+func (c *Client) sendBetError(code string, err error, bet *BetData) {
+	room := c.room
+	if room == nil {
+		c.sendError(code, err.Error())
+		return
+	}
+	c.sendMessage(NewMessage(MsgError, "", c.playerID, ErrorData{
+		Code:      code,
+		Message:   Localize(room.Locale(), err.Error()),
+		Rejection: room.BetRejection(c.playerID, bet, err),
+	}))
+}
+
+// sendMessage queues a message for this client only
+func (c *Client) sendMessage(msg *Message) {
+	if data, err := msg.ForProtocol(c.protocol).Encode(c.encoding); err == nil {
 		select {
 		case c.send <- data:
 		default:
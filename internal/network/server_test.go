@@ -0,0 +1,119 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestServer_PlayerStats(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+
+	server.recordResults(context.Background(), &GameResultData{
+		RoundID:    "round_1",
+		CoinResult: game.Heads,
+		Winners: []PlayerResult{
+			{PlayerID: "p1", Bets: []*BetData{{Amount: 10 * game.Dollar, Choice: game.Heads}}, Won: true, Payout: 20 * game.Dollar, NewBalance: 1010 * game.Dollar},
+		},
+		Losers: []PlayerResult{
+			{PlayerID: "p2", Bets: []*BetData{{Amount: 5 * game.Dollar, Choice: game.Tails}}, NewBalance: 995 * game.Dollar},
+		},
+	})
+	server.recordResults(context.Background(), &GameResultData{
+		RoundID:    "round_2",
+		CoinResult: game.Tails,
+		Losers: []PlayerResult{
+			{PlayerID: "p1", Bets: []*BetData{{Amount: 30 * game.Dollar, Choice: game.Heads}}, NewBalance: 980 * game.Dollar},
+		},
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /players/{id}/stats", server.handlePlayerStats)
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/players/p1/stats", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response PlayerStatsData
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.NotNil(t, response.Stats)
+
+	assert.Equal(t, "p1", response.PlayerID)
+	assert.Equal(t, 2, response.Stats.GamesPlayed)
+	assert.Equal(t, 1, response.Stats.GamesWon)
+	assert.Equal(t, 50.0, response.Stats.WinRate)
+	assert.Equal(t, -20*game.Dollar, response.Stats.NetProfit)
+	assert.Equal(t, 20*game.Dollar, response.Stats.BiggestWin)
+	assert.Equal(t, game.Distribution{Heads: 2}, response.Stats.Choices)
+	assert.Equal(t, game.Distribution{Heads: 1, Tails: 1}, response.Stats.Outcomes)
+
+	// Unknown players have no settled bets yet
+	stats, err := server.PlayerStats(server.ctx, "nobody")
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.GamesPlayed)
+}
+
+func TestServer_Distribution(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+
+	server.recordResults(context.Background(), &GameResultData{
+		RoundID:    "round_1",
+		CoinResult: game.Heads,
+		Winners: []PlayerResult{
+			{PlayerID: "p1", Bets: []*BetData{{Amount: 10 * game.Dollar, Choice: game.Heads}}, Won: true, Payout: 20 * game.Dollar},
+		},
+		Losers: []PlayerResult{
+			{PlayerID: "p2", Bets: []*BetData{{Amount: 5 * game.Dollar, Choice: game.Tails}}},
+			{PlayerID: "p3", Bets: []*BetData{{Amount: 5 * game.Dollar, Choice: game.Tails}}},
+		},
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stats/distribution", server.handleDistribution)
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/stats/distribution", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response DistributionData
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	// One round, three bets
+	assert.Equal(t, game.Distribution{Heads: 1}, response.Outcomes)
+	assert.Equal(t, game.Distribution{Heads: 1, Tails: 2}, response.Choices)
+}
+
+func TestServer_StorageLimitsKeepPlayersWithState(t *testing.T) {
+	config := DefaultServerConfig()
+	config.Storage.MaxPlayers = 1
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+
+	records := []*game.Player{
+		{ID: "winner", Balance: DefaultStartingBalance + game.Dollar},
+		{ID: "admin", Balance: DefaultStartingBalance, Role: game.RoleAdmin},
+		{ID: "friend", Balance: DefaultStartingBalance, Friends: []string{"winner"}},
+		{ID: "visitor", Balance: DefaultStartingBalance},
+		{ID: "newcomer", Balance: DefaultStartingBalance},
+	}
+	for _, record := range records {
+		require.NoError(t, server.Results().SavePlayer(ctx, record))
+	}
+
+	// Only the players with nothing to lose are evicted past the cap
+	for _, playerID := range []string{"winner", "admin", "friend", "newcomer"} {
+		_, err := server.Results().GetPlayer(ctx, playerID)
+		assert.NoError(t, err, playerID)
+	}
+	_, err := server.Results().GetPlayer(ctx, "visitor")
+	assert.Error(t, err)
+}
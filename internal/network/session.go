@@ -0,0 +1,261 @@
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+)
+
+// DefaultSessionTimeout is how long the server remembers a player who has
+// disconnected and left every room
+const DefaultSessionTimeout = 30 * time.Minute
+
+// PlayerSession is the server's view of one player across connections and
+// rooms: whether they are connected, where they are seated and their last
+// known balance
+type PlayerSession struct {
+	PlayerID    string     `json:"player_id"`
+	Name        string     `json:"name"`
+	Online      bool       `json:"online"`
+	ConnectedAt time.Time  `json:"connected_at"`
+	LastSeen    time.Time  `json:"last_seen"`
+	Rooms       []string   `json:"rooms"`
+	Balance     game.Money `json:"balance"`
+}
+
+// playerSession is the mutable state behind a PlayerSession
+type playerSession struct {
+	name        string
+	client      *Client
+	connectedAt time.Time
+	lastSeen    time.Time
+	rooms       map[string]struct{}
+	balance     game.Money
+}
+
+// snapshot returns a copy of the session safe to hand out
+func (p *playerSession) snapshot(playerID string) PlayerSession {
+	rooms := make([]string, 0, len(p.rooms))
+	for roomID := range p.rooms {
+		rooms = append(rooms, roomID)
+	}
+	sort.Strings(rooms)
+
+	return PlayerSession{
+		PlayerID:    playerID,
+		Name:        p.name,
+		Online:      p.client != nil,
+		ConnectedAt: p.connectedAt,
+		LastSeen:    p.lastSeen,
+		Rooms:       rooms,
+		Balance:     p.balance,
+	}
+}
+
+// SessionManager maps player IDs to their connection, rooms and wallet, so
+// a player is tracked as one session however many rooms they sit in and
+// however often they reconnect
+type SessionManager struct {
+	mu       sync.RWMutex
+	sessions map[string]*playerSession
+	clock    clock.Clock
+}
+
+// NewSessionManager creates an empty session manager. A nil clock uses
+// system time.
+func NewSessionManager(clk clock.Clock) *SessionManager {
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &SessionManager{
+		sessions: make(map[string]*playerSession),
+		clock:    clk,
+	}
+}
+
+// session returns a player's session, creating it if needed. Callers must
+// hold m.mu.
+func (m *SessionManager) session(playerID string) *playerSession {
+	session, exists := m.sessions[playerID]
+	if !exists {
+		session = &playerSession{rooms: make(map[string]struct{})}
+		m.sessions[playerID] = session
+	}
+	session.lastSeen = m.clock.Now()
+	return session
+}
+
+// Attach binds a connection to a player's session and returns the
+// connection it replaces, if the player was already connected elsewhere
+func (m *SessionManager) Attach(playerID, name string, client *Client) *Client {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session := m.session(playerID)
+	if name != "" {
+		session.name = name
+	}
+	previous := session.client
+	if previous == client {
+		return nil
+	}
+	session.client = client
+	session.connectedAt = session.lastSeen
+	return previous
+}
+
+// Detach marks a player offline when the connection closing is the one
+// bound to their session. A connection replaced by a newer one leaves the
+// session as it is.
+func (m *SessionManager) Detach(playerID string, client *Client) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, exists := m.sessions[playerID]
+	if !exists || session.client != client {
+		return false
+	}
+	session.client = nil
+	session.lastSeen = m.clock.Now()
+	return true
+}
+
+// Client returns the connection bound to a player, if they are online
+func (m *SessionManager) Client(playerID string) (*Client, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, exists := m.sessions[playerID]
+	if !exists || session.client == nil {
+		return nil, false
+	}
+	return session.client, true
+}
+
+// JoinRoom records a player taking a seat in a room with a balance
+func (m *SessionManager) JoinRoom(playerID, roomID string, balance game.Money) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session := m.session(playerID)
+	session.rooms[roomID] = struct{}{}
+	session.balance = balance
+}
+
+// LeaveRoom records a player giving up their seat in a room, leaving with
+// a balance
+func (m *SessionManager) LeaveRoom(playerID, roomID string, balance game.Money) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, exists := m.sessions[playerID]
+	if !exists {
+		return
+	}
+	delete(session.rooms, roomID)
+	session.balance = balance
+	session.lastSeen = m.clock.Now()
+}
+
+// SetBalance records a player's latest balance, such as after a round
+func (m *SessionManager) SetBalance(playerID string, balance game.Money) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if session, exists := m.sessions[playerID]; exists {
+		session.balance = balance
+		session.lastSeen = m.clock.Now()
+	}
+}
+
+// Get returns a player's session
+func (m *SessionManager) Get(playerID string) (PlayerSession, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, exists := m.sessions[playerID]
+	if !exists {
+		return PlayerSession{}, false
+	}
+	return session.snapshot(playerID), true
+}
+
+// Sessions returns every session, ordered by player ID
+func (m *SessionManager) Sessions() []PlayerSession {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sessions := make([]PlayerSession, 0, len(m.sessions))
+	for playerID, session := range m.sessions {
+		sessions = append(sessions, session.snapshot(playerID))
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].PlayerID < sessions[j].PlayerID
+	})
+	return sessions
+}
+
+// Online returns how many players are connected
+func (m *SessionManager) Online() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	online := 0
+	for _, session := range m.sessions {
+		if session.client != nil {
+			online++
+		}
+	}
+	return online
+}
+
+// Expire forgets players who have been offline and out of every room for
+// longer than timeout, returning how many were removed
+func (m *SessionManager) Expire(timeout time.Duration) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	removed := 0
+	for playerID, session := range m.sessions {
+		if session.client == nil && len(session.rooms) == 0 && now.Sub(session.lastSeen) > timeout {
+			delete(m.sessions, playerID)
+			removed++
+		}
+	}
+	return removed
+}
+
+// PlayerBalance returns the balance of a player seated in the room
+func (r *GameRoom) PlayerBalance(playerID string) (game.Money, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	player, exists := r.players[playerID]
+	if !exists {
+		return 0, false
+	}
+	return player.Balance, true
+}
+
+// sendToPlayer sends a message to the connection of a player who is
+// online and reports whether it was sent. The server lock is held while
+// the message is queued, as unregistering a client closes its send channel
+// under it, and a connection already unregistered is skipped.
+func (s *Server) sendToPlayer(playerID string, msg *Message) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	client, online := s.sessions.Client(playerID)
+	if !online {
+		return false
+	}
+	if _, registered := s.clients.joined(client); !registered {
+		return false
+	}
+	client.sendMessage(msg)
+	return true
+}
@@ -0,0 +1,118 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+)
+
+func TestSessionManager_TracksPlayerAcrossRooms(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	sessions := NewSessionManager(fake)
+	first, second := &Client{}, &Client{}
+
+	assert.Nil(t, sessions.Attach("p1", "Player 1", first))
+	sessions.JoinRoom("p1", "r2", 100*game.Dollar)
+	sessions.JoinRoom("p1", "r1", 100*game.Dollar)
+	sessions.SetBalance("p1", 120*game.Dollar)
+
+	session, ok := sessions.Get("p1")
+	require.True(t, ok)
+	assert.True(t, session.Online)
+	assert.Equal(t, "Player 1", session.Name)
+	assert.Equal(t, []string{"r1", "r2"}, session.Rooms)
+	assert.Equal(t, 120*game.Dollar, session.Balance)
+
+	// A reconnect replaces the old connection, which no longer detaches
+	assert.Same(t, first, sessions.Attach("p1", "", second))
+	assert.False(t, sessions.Detach("p1", first))
+	client, ok := sessions.Client("p1")
+	require.True(t, ok)
+	assert.Same(t, second, client)
+
+	sessions.LeaveRoom("p1", "r2", 130*game.Dollar)
+	assert.True(t, sessions.Detach("p1", second))
+	assert.Zero(t, sessions.Online())
+
+	session, _ = sessions.Get("p1")
+	assert.False(t, session.Online)
+	assert.Equal(t, []string{"r1"}, session.Rooms)
+	assert.Equal(t, 130*game.Dollar, session.Balance)
+}
+
+func TestSessionManager_Expire(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	sessions := NewSessionManager(fake)
+	client := &Client{}
+
+	sessions.Attach("gone", "Gone", client)
+	sessions.Detach("gone", client)
+	sessions.JoinRoom("seated", "r1", 100*game.Dollar)
+	sessions.Attach("online", "Online", &Client{})
+
+	fake.Advance(DefaultSessionTimeout + time.Second)
+	assert.Equal(t, 1, sessions.Expire(DefaultSessionTimeout))
+
+	_, ok := sessions.Get("gone")
+	assert.False(t, ok)
+	assert.Len(t, sessions.Sessions(), 2, "seated and connected players are kept")
+}
+
+func TestServer_SessionFollowsConnection(t *testing.T) {
+	serverConfig := DefaultServerConfig()
+	serverConfig.StartingBalance = 250 * game.Dollar
+	server := NewServer(serverConfig, zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+
+	config := DefaultClientConfig()
+	config.ServerURL = "ws://" + listener.Addr().String() + "/ws"
+
+	client := NewNetworkClient(config, "p1", "Player 1", zaptest.NewLogger(t))
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.JoinRoom("r1", 250*game.Dollar))
+
+	waitFor(t, func() bool {
+		session, ok := server.Sessions().Get("p1")
+		return ok && session.Online && len(session.Rooms) == 1
+	})
+	session, _ := server.Sessions().Get("p1")
+	assert.Equal(t, []string{"r1"}, session.Rooms)
+	assert.Equal(t, 250*game.Dollar, session.Balance)
+
+	client.Disconnect()
+	waitFor(t, func() bool {
+		session, _ := server.Sessions().Get("p1")
+		return !session.Online && len(session.Rooms) == 0
+	})
+	session, _ = server.Sessions().Get("p1")
+	assert.Equal(t, 250*game.Dollar, session.Balance)
+}
+
+func TestServer_SendToPlayer(t *testing.T) {
+	server, _ := newCleanupServer(t)
+	alice := newOnlineClient(t, server, "alice")
+
+	assert.True(t, server.sendToPlayer("alice", NewMessage(MsgSeasonUpdate, "", "alice", nil)))
+	assert.Len(t, alice.send, 1)
+	assert.False(t, server.sendToPlayer("bob", NewMessage(MsgSeasonUpdate, "", "bob", nil)))
+
+	// A connection the server has let go of is not written to, as its
+	// send channel may already be closed
+	server.clients.remove(alice)
+	assert.False(t, server.sendToPlayer("alice", NewMessage(MsgSeasonUpdate, "", "alice", nil)))
+	assert.Len(t, alice.send, 1)
+}
@@ -0,0 +1,364 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+)
+
+// RestoreBettingGrace is the least betting time a restored round gets, so
+// players have a chance to reconnect before it closes
+const RestoreBettingGrace = 15 * time.Second
+
+// DefaultSnapshotInterval is how often rooms are snapshotted when no
+// interval is configured
+const DefaultSnapshotInterval = 30 * time.Second
+
+// ServerSnapshot is the persisted state of every room on a server
+type ServerSnapshot struct {
+	SavedAt time.Time       `json:"saved_at"`
+	Rooms   []*RoomSnapshot `json:"rooms"`
+}
+
+// RoomSnapshot is the persisted state of one room: its settings, players
+// with their balances and escrowed bets, and the phase it was in
+type RoomSnapshot struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Settings         *RoomSettings     `json:"settings"`
+	RequireConsensus bool              `json:"require_consensus"`
+	PendingSettings  *RoomSettings     `json:"pending_settings,omitempty"`
+	State            GameState         `json:"state"`
+	Owner            string            `json:"owner,omitempty"`
+	PausedBy         string            `json:"paused_by,omitempty"`
+	Players          []*PlayerSnapshot `json:"players"`
+
+	// The open betting round, if the room was taking bets or paused
+	// while taking them
+	RoundID      string    `json:"round_id,omitempty"`
+	RoundStarted time.Time `json:"round_started,omitempty"`
+	BettingEnds  time.Time `json:"betting_ends,omitempty"`
+	RoundBoost   float64   `json:"round_boost,omitempty"`
+
+	TotalRounds int       `json:"total_rounds"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PlayerSnapshot is a player's seat in a room. Bets are only kept while
+// the room is taking bets; their amounts are already off the balance.
+type PlayerSnapshot struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Balance       game.Money `json:"balance"`
+	Bets          []BetData  `json:"bets,omitempty"`
+	TotalGames    int        `json:"total_games"`
+	TotalWins     int        `json:"total_wins"`
+	NetProfit     game.Money `json:"net_profit"`
+	WinStreak     int        `json:"win_streak"`
+	LongestStreak int        `json:"longest_streak"`
+	Skin          string     `json:"skin,omitempty"`
+	Rating        int        `json:"rating,omitempty"`
+}
+
+// Snapshot captures the room's state for persistence
+func (r *GameRoom) Snapshot() *RoomSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := &RoomSnapshot{
+		ID:               r.id,
+		Name:             r.name,
+		Settings:         r.config.Settings(),
+		RequireConsensus: r.config.RequireConsensus,
+		State:            r.gameState,
+		Owner:            r.owner,
+		PausedBy:         r.pausedBy,
+		Players:          make([]*PlayerSnapshot, 0, len(r.players)),
+		TotalRounds:      r.totalRounds,
+		CreatedAt:        r.createdAt,
+	}
+	if r.pendingConfig != nil {
+		snapshot.PendingSettings = r.pendingConfig.Settings()
+	}
+
+	paused := r.gameState == StatePaused && r.pausedPhase == StateBetting
+	betting := (r.gameState == StateBetting || paused) && r.currentRound != nil
+	if betting {
+		snapshot.RoundID = r.currentRound.ID
+		snapshot.RoundStarted = r.currentRound.StartTime
+		snapshot.BettingEnds = r.timerEnd
+		snapshot.RoundBoost = r.currentRound.Boost
+		if paused {
+			snapshot.BettingEnds = r.clock.Now().Add(r.pausedRemaining)
+		}
+	}
+
+	for _, player := range r.players {
+		seat := &PlayerSnapshot{
+			ID:            player.ID,
+			Name:          player.Name,
+			Balance:       player.Balance,
+			TotalGames:    player.TotalGames,
+			TotalWins:     player.TotalWins,
+			NetProfit:     player.NetProfit,
+			WinStreak:     player.WinStreak,
+			LongestStreak: player.LongestStreak,
+			Skin:          player.Skin,
+			Rating:        player.Rating,
+		}
+		if betting {
+			seat.Bets = copyBets(r.currentRound.Bets[player.ID])
+		}
+		snapshot.Players = append(snapshot.Players, seat)
+	}
+
+	return snapshot
+}
+
+// RestoreGameRoom rebuilds a room from a snapshot. Players come back
+// offline until they rejoin. A round that was taking bets resumes with its
+// bets in escrow, paused again if it was paused; any other phase restarts
+// from waiting, since results are settled before they are announced.
+func RestoreGameRoom(snapshot *RoomSnapshot, scheduler *TimerScheduler, logger *zap.Logger) (*GameRoom, error) {
+	config, err := DefaultRoomConfig().WithSettings(snapshot.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("room %s: %w", snapshot.ID, err)
+	}
+	config.RequireConsensus = snapshot.RequireConsensus
+
+	room := NewGameRoom(snapshot.ID, snapshot.Name, config, scheduler, logger)
+	room.totalRounds = snapshot.TotalRounds
+	room.owner = snapshot.Owner
+	if !snapshot.CreatedAt.IsZero() {
+		room.createdAt = snapshot.CreatedAt
+	}
+	if snapshot.PendingSettings != nil {
+		pending, err := DefaultRoomConfig().WithSettings(snapshot.PendingSettings)
+		if err != nil {
+			return nil, fmt.Errorf("room %s pending settings: %w", snapshot.ID, err)
+		}
+		pending.RequireConsensus = snapshot.RequireConsensus
+		room.pendingConfig = pending
+	}
+
+	now := room.clock.Now()
+	for _, seat := range snapshot.Players {
+		room.players[seat.ID] = &RoomPlayer{
+			ID:            seat.ID,
+			Name:          seat.Name,
+			Balance:       seat.Balance,
+			LastSeen:      now,
+			TotalGames:    seat.TotalGames,
+			TotalWins:     seat.TotalWins,
+			NetProfit:     seat.NetProfit,
+			WinStreak:     seat.WinStreak,
+			LongestStreak: seat.LongestStreak,
+			Skin:          seat.Skin,
+			Rating:        seat.Rating,
+		}
+	}
+
+	if snapshot.RoundID != "" && (snapshot.State == StateBetting || snapshot.State == StatePaused) {
+		room.resumeBetting(snapshot)
+		if snapshot.State == StatePaused {
+			room.pauseGame(snapshot.PausedBy)
+		}
+		return room, nil
+	}
+
+	// Settings agreed during the last round apply before the next one
+	if room.pendingConfig != nil {
+		room.config, room.pendingConfig = room.pendingConfig, nil
+	}
+	return room, nil
+}
+
+// resumeBetting reopens a restored betting round with its escrowed bets.
+// The room is not shared yet, so no lock is needed.
+func (r *GameRoom) resumeBetting(snapshot *RoomSnapshot) {
+	r.currentRound = &GameRound{
+		ID:          snapshot.RoundID,
+		StartTime:   snapshot.RoundStarted,
+		Bets:        make(map[string][]*BetData),
+		SeedCommits: make(map[string]string),
+		SeedReveals: make(map[string]string),
+		Results:     make(map[string]*PlayerResult),
+		State:       StateBetting,
+		Boost:       snapshot.RoundBoost,
+	}
+	for _, seat := range snapshot.Players {
+		if len(seat.Bets) == 0 {
+			continue
+		}
+		bets := make([]*BetData, 0, len(seat.Bets))
+		for i := range seat.Bets {
+			bet := seat.Bets[i]
+			bets = append(bets, &bet)
+		}
+		r.currentRound.Bets[seat.ID] = bets
+		r.players[seat.ID].CurrentBets = bets
+	}
+
+	now := r.clock.Now()
+	remaining := snapshot.BettingEnds.Sub(now)
+	if remaining < RestoreBettingGrace {
+		remaining = RestoreBettingGrace
+	}
+	r.gameState = StateBetting
+	r.timerEnd = now.Add(remaining)
+	r.scheduleBettingEnd(r.timerEnd)
+}
+
+// reclaimSeat puts a reconnecting player back into their restored seat,
+// keeping the balance and bets the room holds for them. Callers must hold
+// r.mu.
+func (r *GameRoom) reclaimSeat(player *RoomPlayer, playerName string) {
+	player.IsOnline = true
+	player.LastSeen = r.clock.Now()
+	if playerName != "" {
+		player.Name = r.uniqueName(player.ID, playerName)
+	}
+	r.lastActivity = r.clock.Now()
+
+	r.logger.Info("Player reclaimed seat",
+		zap.String("room_id", r.id),
+		zap.String("player_id", player.ID),
+		zap.Float64("balance", player.Balance.Float64()),
+	)
+}
+
+// abandoned reports whether every seat has stayed offline for longer than
+// DefaultRoomTimeout, as when nobody returns to a restored room
+func (r *GameRoom) abandoned(now time.Time) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, player := range r.players {
+		if player.IsOnline {
+			return false
+		}
+	}
+	return now.Sub(r.lastActivity) > DefaultRoomTimeout
+}
+
+// unclaimed reports whether nobody has joined the room since it was
+// created, within DefaultRoomTimeout of its creation, so a room created
+// ahead of its players is not cleaned up before they arrive
+func (r *GameRoom) unclaimed(now time.Time) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.owner == "" && now.Sub(r.lastActivity) <= DefaultRoomTimeout
+}
+
+// Snapshot captures the state of every room
+func (s *Server) Snapshot() *ServerSnapshot {
+	s.mu.RLock()
+	rooms := make([]*GameRoom, 0, len(s.rooms))
+	for _, room := range s.rooms {
+		rooms = append(rooms, room)
+	}
+	s.mu.RUnlock()
+
+	snapshot := &ServerSnapshot{
+		SavedAt: s.scheduler.Clock().Now(),
+		Rooms:   make([]*RoomSnapshot, 0, len(rooms)),
+	}
+	for _, room := range rooms {
+		snapshot.Rooms = append(snapshot.Rooms, room.Snapshot())
+	}
+	return snapshot
+}
+
+// SaveSnapshot writes the state of every room to path. The file is
+// replaced atomically so a crash mid-write keeps the previous snapshot.
+func (s *Server) SaveSnapshot(path string) error {
+	data, err := json.MarshalIndent(s.Snapshot(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode room snapshot: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write room snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace room snapshot: %w", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot recreates the rooms saved at path and returns how many
+// were restored. A missing file restores nothing. Must be called before
+// Start.
+func (s *Server) RestoreSnapshot(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read room snapshot: %w", err)
+	}
+
+	var snapshot ServerSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return 0, fmt.Errorf("failed to decode room snapshot: %w", err)
+	}
+
+	restored := 0
+	for _, roomSnapshot := range snapshot.Rooms {
+		room, err := RestoreGameRoom(roomSnapshot, s.scheduler, s.logger)
+		if err != nil {
+			s.logger.Error("Failed to restore room", zap.String("room_id", roomSnapshot.ID), zap.Error(err))
+			continue
+		}
+		// Betting limits belong to the operator rather than the room, so
+		// restored rooms take the current ones
+		if s.config.RoomDefaults != nil {
+			room.config.Limits = s.config.RoomDefaults.Limits
+			if room.pendingConfig != nil {
+				room.pendingConfig.Limits = s.config.RoomDefaults.Limits
+			}
+		}
+		if err := s.addRoom(room); err != nil {
+			room.Stop()
+			s.logger.Error("Failed to restore room", zap.String("room_id", roomSnapshot.ID), zap.Error(err))
+			continue
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// snapshotLoop periodically saves room state to the configured file
+func (s *Server) snapshotLoop() {
+	interval := s.config.SnapshotInterval
+	if interval <= 0 {
+		interval = DefaultSnapshotInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.SaveSnapshot(s.config.SnapshotFile); err != nil {
+				s.logger.Error("Room snapshot failed", zap.Error(err))
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package network
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestRestoreGameRoom_ResumesBetting(t *testing.T) {
+	room, _, fake := newTestRoom(t)
+	require.NoError(t, room.PlaceBet("p1", 30*game.Dollar, game.Heads))
+	room.players["p1"].WinStreak = 2
+
+	fake.Advance(8 * time.Second)
+	snapshot := room.Snapshot()
+	assert.Equal(t, StateBetting, snapshot.State)
+	require.Len(t, snapshot.Players, 1)
+	assert.Equal(t, 70*game.Dollar, snapshot.Players[0].Balance)
+
+	scheduler := NewTimerScheduler(DefaultSchedulerResolution, DefaultCountdownInterval, fake)
+	restored, err := RestoreGameRoom(snapshot, scheduler, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	t.Cleanup(restored.Stop)
+
+	// The seat keeps its escrowed bet and comes back offline
+	player := restored.GetPlayers()["p1"]
+	assert.False(t, player.IsOnline)
+	assert.Equal(t, 70*game.Dollar, player.Balance)
+	assert.Equal(t, 2, player.WinStreak)
+	require.Len(t, player.CurrentBets, 1)
+	assert.Equal(t, 30*game.Dollar, player.CurrentBets[0].Amount)
+	assert.Equal(t, StateBetting, restored.GetGameState())
+
+	// Rejoining reclaims the seat rather than starting over with the
+	// balance the client claims
+	require.NoError(t, restored.AddPlayer("p1", "Player 1", 500*game.Dollar))
+	player = restored.GetPlayers()["p1"]
+	assert.True(t, player.IsOnline)
+	assert.Equal(t, 70*game.Dollar, player.Balance)
+
+	// Only two seconds were left, so the round gets the reconnect grace
+	fake.Advance(RestoreBettingGrace - time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateBetting, restored.GetGameState())
+
+	fake.Advance(time.Second)
+	scheduler.advance(fake.Now())
+	assert.Equal(t, StateResult, restored.GetGameState())
+}
+
+func TestRestoreGameRoom_StaysPaused(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	require.NoError(t, room.PlaceBet("p1", 30*game.Dollar, game.Heads))
+	fake.Advance(2 * time.Second)
+	scheduler.advance(fake.Now())
+	require.NoError(t, room.ProposePause("p1", PauseActionPause))
+
+	// The betting time left is kept however long the room sits paused
+	fake.Advance(time.Hour)
+	snapshot := room.Snapshot()
+	assert.Equal(t, StatePaused, snapshot.State)
+	assert.Equal(t, "p1", snapshot.PausedBy)
+	assert.Equal(t, fake.Now().Add(8*time.Second), snapshot.BettingEnds)
+
+	restoredScheduler := NewTimerScheduler(DefaultSchedulerResolution, DefaultCountdownInterval, fake)
+	restored, err := RestoreGameRoom(snapshot, restoredScheduler, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	t.Cleanup(restored.Stop)
+
+	assert.Equal(t, StatePaused, restored.GetGameState())
+	assert.Equal(t, "p1", restored.Owner())
+	require.Len(t, restored.GetPlayers()["p1"].CurrentBets, 1)
+
+	require.NoError(t, restored.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	require.NoError(t, restored.ProposePause("p1", PauseActionResume))
+
+	// Like any restored round, it resumes with at least the reconnect grace
+	fake.Advance(RestoreBettingGrace - time.Second)
+	restoredScheduler.advance(fake.Now())
+	assert.Equal(t, StateBetting, restored.GetGameState())
+	fake.Advance(time.Second)
+	restoredScheduler.advance(fake.Now())
+	assert.Equal(t, StateResult, restored.GetGameState())
+}
+
+func TestServer_SnapshotFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "rooms.json")
+
+	server := NewServer(nil, zaptest.NewLogger(t))
+	config := DefaultRoomConfig()
+	config.MinPlayers = 3
+	config.MaxBet = 250 * game.Dollar
+	room, err := server.CreateRoom("lobby", "Lobby", config)
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 120*game.Dollar))
+	require.NoError(t, server.SaveSnapshot(path))
+	server.Stop()
+
+	restarted := NewServer(nil, zaptest.NewLogger(t))
+	defer restarted.Stop()
+	restored, err := restarted.RestoreSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, restored)
+
+	room, exists := restarted.GetRoom("lobby")
+	require.True(t, exists)
+	assert.Equal(t, "Lobby", room.Name())
+	assert.Equal(t, 250*game.Dollar, room.GetConfig().MaxBet)
+	assert.Equal(t, 3, room.GetConfig().MinPlayers)
+	assert.Equal(t, 120*game.Dollar, room.GetPlayers()["p1"].Balance)
+	assert.Equal(t, StateWaiting, room.GetGameState())
+
+	// A server without a snapshot yet starts empty
+	count, err := restarted.RestoreSnapshot(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,122 @@
+package network
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"coinflip-game/internal/tracing"
+)
+
+// tracer traces rounds, client messages and broadcasts
+var tracer = tracing.Tracer("coinflip-game/internal/network")
+
+// startRoundTrace opens the span covering the current round, from the
+// start of betting until its result phase ends or it is cancelled. Bets,
+// the flip, settlement and the round's broadcasts all hang off it. Callers
+// must hold r.mu.
+func (r *GameRoom) startRoundTrace() {
+	r.roundCtx, _ = tracer.Start(context.Background(), "room.round",
+		trace.WithNewRoot(),
+		trace.WithAttributes(
+			attribute.String("room.id", r.id),
+			attribute.String("round.id", r.currentRound.ID),
+		),
+	)
+}
+
+// endRoundTrace closes the round's span, recording why it ended early
+// when reason is set. Callers must hold r.mu.
+func (r *GameRoom) endRoundTrace(reason string) {
+	span := r.roundSpan()
+	if reason != "" {
+		span.SetAttributes(attribute.String("round.cancel_reason", reason))
+	}
+	span.End()
+	r.roundCtx = nil
+}
+
+// roundTrace returns the context of the current round's span, or one
+// without a span for rounds that are not traced, such as those restored
+// from a snapshot. Callers must hold r.mu.
+func (r *GameRoom) roundTrace() context.Context {
+	if r.roundCtx == nil {
+		return context.Background()
+	}
+	return r.roundCtx
+}
+
+// roundSpan returns the current round's span, which does nothing when the
+// round is not traced. Callers must hold r.mu.
+func (r *GameRoom) roundSpan() trace.Span {
+	return trace.SpanFromContext(r.roundTrace())
+}
+
+// RoundSpanContext identifies the span of the round in progress, if any
+func (r *GameRoom) RoundSpanContext() trace.SpanContext {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.roundSpan().SpanContext()
+}
+
+// traceBet records a bet on the round's span
+func (r *GameRoom) traceBet(bet *BetData) {
+	r.roundSpan().AddEvent("bet placed", trace.WithAttributes(
+		attribute.String("player.id", bet.PlayerID),
+		attribute.String("bet.id", bet.BetID),
+		attribute.String("bet.choice", bet.Choice.String()),
+		attribute.Float64("bet.amount", bet.Amount.Float64()),
+		attribute.Bool("bet.insured", bet.Insured),
+		attribute.Bool("bet.practice", bet.Practice),
+	))
+}
+
+// startMessageSpan opens the span handling a client's message, continuing
+// the trace the client sent with it
+func (c *Client) startMessageSpan(msg *Message) (context.Context, trace.Span) {
+	ctx := tracing.Extract(c.server.ctx, msg.TraceParent)
+	return tracer.Start(ctx, "network.handle "+string(msg.Type),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("message.type", string(msg.Type)),
+			attribute.String("room.id", msg.RoomID),
+			attribute.String("player.id", msg.PlayerID),
+		),
+	)
+}
+
+// startBroadcastSpan opens the span sending a room message to its
+// players. Only messages sent during a traced round are traced; the span
+// returned for others does nothing.
+func startBroadcastSpan(room *GameRoom, message *Message) trace.Span {
+	ctx := tracing.Extract(context.Background(), message.TraceParent)
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return trace.SpanFromContext(ctx)
+	}
+
+	_, span := tracer.Start(ctx, "network.broadcast",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("room.id", room.ID()),
+			attribute.String("message.type", string(message.Type)),
+		),
+	)
+	return span
+}
+
+// linkRound links a message's span to the round in progress in the
+// client's room, so a player's request leads to the round it joined
+func (c *Client) linkRound(span trace.Span) {
+	c.server.mu.RLock()
+	room := c.room
+	c.server.mu.RUnlock()
+
+	if room == nil {
+		return
+	}
+	if round := room.RoundSpanContext(); round.IsValid() {
+		span.AddLink(trace.Link{SpanContext: round})
+	}
+}
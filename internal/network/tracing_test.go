@@ -0,0 +1,79 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"coinflip-game/internal/game"
+)
+
+// recordedSpans keeps the spans ended by this package's tests
+var recordedSpans = tracetest.NewSpanRecorder()
+
+func init() {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recordedSpans)))
+}
+
+// spansOfTrace returns the ended spans of one trace by name
+func spansOfTrace(traceID trace.TraceID) map[string]sdktrace.ReadOnlySpan {
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recordedSpans.Ended() {
+		if span.SpanContext().TraceID() == traceID {
+			spans[span.Name()] = span
+		}
+	}
+	return spans
+}
+
+func TestGameRoom_RoundTrace(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	round := room.RoundSpanContext()
+	require.True(t, round.IsValid())
+
+	// Round messages carry the round's trace to the server's broadcasts
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	for _, msg := range drainEvents(room) {
+		if msg.Type == MsgBetPlaced {
+			assert.Contains(t, msg.TraceParent, round.TraceID().String())
+		}
+	}
+
+	fake.Advance(room.config.BettingDuration + time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+	fake.Advance(room.config.ResultDuration + time.Second)
+	scheduler.advance(fake.Now())
+	assert.False(t, room.RoundSpanContext().IsValid())
+
+	spans := spansOfTrace(round.TraceID())
+	require.Contains(t, spans, "room.round")
+	require.Contains(t, spans, "room.flip")
+	require.Contains(t, spans, "room.settle")
+	assert.Equal(t, round.SpanID(), spans["room.flip"].Parent().SpanID())
+	assert.Equal(t, round.SpanID(), spans["room.settle"].Parent().SpanID())
+
+	events := spans["room.round"].Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "bet placed", events[0].Name)
+}
+
+func TestGameRoom_CancelledRoundTrace(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	round := room.RoundSpanContext()
+
+	require.NoError(t, room.PlaceBet("p1", 10*game.Dollar, game.Heads))
+	require.NoError(t, room.CancelRound("maintenance"))
+
+	spans := spansOfTrace(round.TraceID())
+	require.Contains(t, spans, "room.round")
+	assert.NotContains(t, spans, "room.flip")
+	assert.Contains(t, spans["room.round"].Attributes(), attribute.String("round.cancel_reason", "maintenance"))
+}
@@ -0,0 +1,46 @@
+package network
+
+import (
+	"time"
+)
+
+// Phase lengths and countdown pace of turbo rooms, which trade the time to
+// think for many more rounds an hour
+const (
+	TurboBettingDuration   = 10 * time.Second
+	TurboResultDuration    = 3 * time.Second
+	TurboCountdownInterval = 250 * time.Millisecond
+)
+
+// applyTurbo switches the config to turbo rounds. The phase lengths are
+// fixed, so they override any the settings choose, and closing early never
+// waits longer than betting does.
+func (c *RoomConfig) applyTurbo() {
+	c.Turbo = true
+	c.BettingDuration = TurboBettingDuration
+	c.ResultDuration = TurboResultDuration
+	c.CountdownInterval = TurboCountdownInterval
+	if c.EarlyCloseDelay > c.BettingDuration/2 {
+		c.EarlyCloseDelay = c.BettingDuration / 2
+	}
+}
+
+// scheduleBettingEnd ends betting at deadline, with countdown updates at
+// the room's pace until then. Callers must hold r.mu.
+func (r *GameRoom) scheduleBettingEnd(deadline time.Time) {
+	r.scheduler.ScheduleEvery(r.id, deadline, r.config.CountdownInterval, r.broadcastTimer, r.endBettingPhase)
+}
+
+// bettingTimer describes the betting countdown with remaining left to bet.
+// The milliseconds let clients run a smooth progress bar between updates.
+// Callers must hold r.mu.
+func (r *GameRoom) bettingTimer(remaining time.Duration) TimerData {
+	return TimerData{
+		Phase:        StateBetting,
+		SecondsLeft:  int(remaining.Round(time.Second).Seconds()),
+		TotalSeconds: int(r.config.BettingDuration.Seconds()),
+		MillisLeft:   remaining.Milliseconds(),
+		TotalMillis:  r.config.BettingDuration.Milliseconds(),
+		Turbo:        r.config.Turbo,
+	}
+}
@@ -0,0 +1,78 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+)
+
+func TestRoomConfig_TurboSettings(t *testing.T) {
+	config, err := DefaultRoomConfig().WithSettings(&RoomSettings{Turbo: true, BettingSeconds: 30, EarlyClose: true})
+	require.NoError(t, err)
+	assert.True(t, config.Turbo)
+	assert.Equal(t, TurboBettingDuration, config.BettingDuration, "turbo phase lengths are fixed")
+	assert.Equal(t, TurboResultDuration, config.ResultDuration)
+	assert.Equal(t, TurboCountdownInterval, config.CountdownInterval)
+	assert.LessOrEqual(t, config.EarlyCloseDelay, config.BettingDuration)
+
+	// Restoring or voting on a turbo room keeps it turbo
+	restored, err := DefaultRoomConfig().WithSettings(config.Settings())
+	require.NoError(t, err)
+	assert.True(t, restored.Turbo)
+	voted, err := config.WithSettings(&RoomSettings{ResultSeconds: 20})
+	require.NoError(t, err)
+	assert.Equal(t, TurboResultDuration, voted.ResultDuration)
+
+	config = DefaultRoomConfig()
+	config.CountdownInterval = config.BettingDuration
+	assert.ErrorIs(t, config.Validate(), ErrInvalidRoomConfig)
+}
+
+func TestGameRoom_TurboCountdown(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	scheduler := NewTimerScheduler(DefaultSchedulerResolution, DefaultCountdownInterval, fake)
+	config, err := DefaultRoomConfig().WithSettings(&RoomSettings{MinPlayers: 1, Turbo: true})
+	require.NoError(t, err)
+	room := NewGameRoom("turbo", "Turbo", config, scheduler, zaptest.NewLogger(t))
+	t.Cleanup(room.Stop)
+
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateBetting, room.GetGameState())
+
+	timers := func() []TimerData {
+		var found []TimerData
+		for _, msg := range drainEvents(room) {
+			var timer TimerData
+			if (msg.Type == MsgTimerUpdate || msg.Type == MsgBetPhase) && msg.GetData(&timer) == nil {
+				found = append(found, timer)
+			}
+		}
+		return found
+	}
+	opening := timers()
+	require.NotEmpty(t, opening)
+	assert.True(t, opening[0].Turbo)
+	assert.Equal(t, TurboBettingDuration.Milliseconds(), opening[0].TotalMillis)
+
+	// Updates come several times a second, down to the last second
+	for elapsed := TurboCountdownInterval; elapsed < TurboBettingDuration; elapsed += TurboCountdownInterval {
+		fake.Advance(TurboCountdownInterval)
+		scheduler.advance(fake.Now())
+	}
+	updates := timers()
+	assert.GreaterOrEqual(t, len(updates), 30)
+	last := updates[len(updates)-1]
+	assert.Greater(t, last.MillisLeft, int64(0))
+	assert.Less(t, last.MillisLeft, int64(time.Second/time.Millisecond))
+
+	fake.Advance(time.Second)
+	scheduler.advance(fake.Now())
+	assert.NotEqual(t, StateBetting, room.GetGameState())
+}
@@ -0,0 +1,559 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"coinflip-game/internal/game"
+)
+
+// Limits on client input, enforced by ValidateMessage before a message
+// reaches its handler. Lengths count characters, not bytes.
+const (
+	MaxIDLength         = 64 // Player, room, proposal and skin IDs and promo codes
+	MaxPlayerNameLength = 32 // Player names
+	MaxRoomNameLength   = 64 // Room names
+)
+
+// MaxBalance is the largest balance a player may join with
+const MaxBalance = 1e9 * game.Dollar
+
+// ErrInvalidMessage is matched by ValidationError
+var ErrInvalidMessage = errors.New("invalid message")
+
+// ValidationError reports a client message rejected by ValidateMessage.
+// Code is the error code the client is answered with.
+type ValidationError struct {
+	Code   string
+	Field  string
+	Reason string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// Is makes the error match ErrInvalidMessage
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidMessage
+}
+
+// messageValidators check the data of each client message type. A failed
+// check is answered with the code the type's handler uses for bad data.
+var messageValidators = map[MessageType]struct {
+	code     string
+	validate func(msg *Message) (field, reason string)
+}{
+	MsgJoinRoom:       {"invalid_data", validateJoinRoom},
+	MsgCreateRoom:     {"invalid_data", validateCreateRoom},
+	MsgBetPlaced:      {"invalid_bet_data", validateBet},
+	MsgUpdateBet:      {"invalid_bet_data", validateBet},
+	MsgQueuedBet:      {"invalid_bet_data", validateQueuedBet},
+	MsgBetSlip:        {"invalid_bet_data", validateBetSlip},
+	MsgChat:           {"invalid_chat", validateChat},
+	MsgConfigProposal: {"invalid_data", validateConfigProposal},
+	MsgConfigVote:     {"invalid_data", validateConfigVote},
+	MsgPauseProposal:  {"invalid_data", validatePauseProposal},
+	MsgPauseVote:      {"invalid_data", validatePauseVote},
+	MsgPlayerStats:    {"invalid_data", validatePlayerStats},
+	MsgFindDuel:       {"invalid_data", validateFindDuel},
+	MsgRedeemCode:     {"invalid_data", validateRedeemCode},
+	MsgRegister:       {"invalid_data", validateRegister},
+	MsgWalletSync:     {"invalid_data", validateWalletSync},
+	MsgInventory:      {"invalid_data", validateSkin},
+	MsgBuySkin:        {"invalid_data", validateSkin},
+	MsgEquipSkin:      {"invalid_data", validateSkin},
+	MsgFriends:        {"invalid_data", validateFriend},
+	MsgAddFriend:      {"invalid_data", validateFriend},
+	MsgRemoveFriend:   {"invalid_data", validateFriend},
+	MsgNotifySettings: {"invalid_data", validateNotifySettings},
+	MsgRoomInvite:     {"invalid_data", validateInvite},
+	MsgReplayRound:    {"invalid_data", validateReplay},
+	MsgKickPlayer:     {"invalid_data", validateKick},
+}
+
+// ValidateMessage checks a message received from a client: the IDs on
+// every message, and the data of every type the server handles. Handlers
+// only see messages that passed, so none of them can forget a check. The
+// error is a *ValidationError.
+func ValidateMessage(msg *Message) error {
+	if reason := checkID(msg.RoomID); reason != "" {
+		return &ValidationError{Code: "invalid_message", Field: "room_id", Reason: reason}
+	}
+	if reason := checkID(msg.PlayerID); reason != "" {
+		return &ValidationError{Code: "invalid_message", Field: "player_id", Reason: reason}
+	}
+
+	validator, ok := messageValidators[msg.Type]
+	if !ok {
+		return nil
+	}
+	if field, reason := validator.validate(msg); reason != "" {
+		return &ValidationError{Code: validator.code, Field: field, Reason: reason}
+	}
+	return nil
+}
+
+// validateJoinRoom checks the name, starting balance and settings a
+// player joins with
+func validateJoinRoom(msg *Message) (string, string) {
+	var data RoomJoinData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed join data"
+	}
+	if reason := checkText(data.PlayerName, MaxPlayerNameLength); reason != "" {
+		return "player_name", reason
+	}
+	if data.Balance < 0 || data.Balance > MaxBalance {
+		return "balance", fmt.Sprintf("must be between 0 and %d", int64(MaxBalance/game.Dollar))
+	}
+	if field, reason := checkCents(msg); reason != "" {
+		return field, reason
+	}
+	return checkSettings(data.Settings)
+}
+
+// validateFindDuel checks the name a player looks for a duel under
+func validateFindDuel(msg *Message) (string, string) {
+	var data FindDuelData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed find duel data"
+	}
+	if reason := checkText(data.PlayerName, MaxPlayerNameLength); reason != "" {
+		return "player_name", reason
+	}
+	return "", ""
+}
+
+// validateCreateRoom checks a new room's name and settings
+func validateCreateRoom(msg *Message) (string, string) {
+	var data RoomCreateData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed create room data"
+	}
+	if reason := checkText(data.RoomName, MaxRoomNameLength); reason != "" {
+		return "room_name", reason
+	}
+	if field, reason := checkCents(msg); reason != "" {
+		return field, reason
+	}
+	return checkSettings(data.Settings)
+}
+
+// validateBet checks a bet placed or changed during betting
+func validateBet(msg *Message) (string, string) {
+	var data BetData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed bet data"
+	}
+	if field, reason := checkBet(data, false); reason != "" {
+		return field, reason
+	}
+	return checkCents(msg)
+}
+
+// validateQueuedBet checks a bet queued for the next round, where an
+// amount of zero withdraws the queued bet
+func validateQueuedBet(msg *Message) (string, string) {
+	var data QueuedBetData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed queued bet data"
+	}
+	if field, reason := checkBet(data.Bet, true); reason != "" {
+		return field, reason
+	}
+	return checkCents(msg)
+}
+
+// validateBetSlip checks the bets staged on a slip, where no bets clear it
+func validateBetSlip(msg *Message) (string, string) {
+	var data BetSlipData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed bet slip data"
+	}
+	if len(data.Bets) > MaxBetSlipBets {
+		return "bets", fmt.Sprintf("must hold at most %d bets", MaxBetSlipBets)
+	}
+	for i, bet := range data.Bets {
+		if field, reason := checkBet(bet, false); reason != "" {
+			return fmt.Sprintf("bets[%d].%s", i, field), reason
+		}
+	}
+	return checkCents(msg)
+}
+
+// validateChat checks a chat message is printable text of a sensible length
+func validateChat(msg *Message) (string, string) {
+	var data ChatData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed chat message"
+	}
+	text := strings.TrimSpace(data.Text)
+	if text == "" {
+		return "text", fmt.Sprintf("chat messages must be 1-%d characters", MaxChatLength)
+	}
+	if reason := checkText(text, MaxChatLength); reason != "" {
+		return "text", reason
+	}
+	return "", ""
+}
+
+// validateConfigProposal checks proposed room settings
+func validateConfigProposal(msg *Message) (string, string) {
+	var data ConfigProposalData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed settings proposal"
+	}
+	if field, reason := checkCents(msg); reason != "" {
+		return field, reason
+	}
+	return checkSettings(data.Settings)
+}
+
+// validateConfigVote checks the proposal a vote is cast on
+func validateConfigVote(msg *Message) (string, string) {
+	var data ConfigVoteData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed settings vote"
+	}
+	if reason := checkID(data.ProposalID); reason != "" {
+		return "proposal_id", reason
+	}
+	return "", ""
+}
+
+// validatePauseProposal checks a pause request asks to pause or resume
+func validatePauseProposal(msg *Message) (string, string) {
+	var data PauseProposalData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed pause request"
+	}
+	if data.Action != PauseActionPause && data.Action != PauseActionResume {
+		return "action", fmt.Sprintf("must be %q or %q", PauseActionPause, PauseActionResume)
+	}
+	return "", ""
+}
+
+// validatePauseVote checks the pause proposal a vote is cast on
+func validatePauseVote(msg *Message) (string, string) {
+	var data PauseVoteData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed pause vote"
+	}
+	if reason := checkID(data.ProposalID); reason != "" {
+		return "proposal_id", reason
+	}
+	return "", ""
+}
+
+// validatePlayerStats checks the player whose statistics are requested
+func validatePlayerStats(msg *Message) (string, string) {
+	var data PlayerStatsData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed player stats request"
+	}
+	if reason := checkID(data.PlayerID); reason != "" {
+		return "player_id", reason
+	}
+	return "", ""
+}
+
+// validateRedeemCode checks a promo code is present and plausible
+func validateRedeemCode(msg *Message) (string, string) {
+	var data RedeemCodeData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed promo code data"
+	}
+	if data.Code == "" {
+		return "code", "is required"
+	}
+	if reason := checkID(data.Code); reason != "" {
+		return "code", reason
+	}
+	return "", ""
+}
+
+// validateReplay checks a replay request's round ID, speed and start
+func validateReplay(msg *Message) (string, string) {
+	var data ReplayData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed replay request"
+	}
+	if reason := checkID(data.RoundID); reason != "" {
+		return "round_id", reason
+	}
+	if data.Speed < 0 || data.Speed > MaxReplaySpeed {
+		return "speed", fmt.Sprintf("must be between 0 and %g", MaxReplaySpeed)
+	}
+	if data.FromMs < 0 {
+		return "from_ms", "cannot be negative"
+	}
+	return "", ""
+}
+
+// validateWalletSync checks the offline change a client reports is a
+// plausible amount
+func validateWalletSync(msg *Message) (string, string) {
+	var data WalletSyncData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed wallet sync data"
+	}
+	if data.Net < -MaxBalance || data.Net > MaxBalance {
+		return "net", fmt.Sprintf("must be between -%d and %d", int64(MaxBalance/game.Dollar), int64(MaxBalance/game.Dollar))
+	}
+	return checkCents(msg)
+}
+
+// validateRegister checks the account a guest registers as
+func validateRegister(msg *Message) (string, string) {
+	var data RegisterData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed register data"
+	}
+	if err := game.ValidateAccount(data.Account); err != nil {
+		return "account", err.Error()
+	}
+	return "", ""
+}
+
+// validateSkin checks the skin a player buys or equips
+func validateSkin(msg *Message) (string, string) {
+	var data SkinData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed skin data"
+	}
+	if reason := checkID(data.SkinID); reason != "" {
+		return "skin_id", reason
+	}
+	return "", ""
+}
+
+// validateFriend checks the account name or friend code a player adds or
+// removes
+func validateFriend(msg *Message) (string, string) {
+	var data FriendsData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed friends data"
+	}
+	if msg.Type != MsgFriends && data.Friend == "" {
+		return "friend", "an account name or friend code is required"
+	}
+	if reason := checkID(data.Friend); reason != "" {
+		return "friend", reason
+	}
+	return "", ""
+}
+
+// validateNotifySettings checks the addresses and triggers a player sets
+// up notifications with
+func validateNotifySettings(msg *Message) (string, string) {
+	var data NotifySettingsData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed notification settings"
+	}
+	if data.Settings == nil {
+		return "", ""
+	}
+	if err := data.Settings.Validate(); err != nil {
+		return "settings", err.Error()
+	}
+	return "", ""
+}
+
+// validateInvite checks the friend a room invite is sent to
+func validateInvite(msg *Message) (string, string) {
+	var data InviteData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed invite data"
+	}
+	if data.To == "" {
+		return "to", "a friend to invite is required"
+	}
+	if reason := checkID(data.To); reason != "" {
+		return "to", reason
+	}
+	return "", ""
+}
+
+// validateKick checks a kick names the room and the player to remove
+func validateKick(msg *Message) (string, string) {
+	if msg.RoomID == "" {
+		return "room_id", "a room is required"
+	}
+	var data KickData
+	if err := msg.GetData(&data); err != nil {
+		return "data", "malformed kick data"
+	}
+	if data.PlayerID == "" {
+		return "player_id", "a player to kick is required"
+	}
+	if reason := checkID(data.PlayerID); reason != "" {
+		return "player_id", reason
+	}
+	return "", ""
+}
+
+// checkBet checks a bet's amount is positive and its choice a side of the
+// coin. With withdraw set, a zero amount and no choice are allowed too.
+func checkBet(bet BetData, withdraw bool) (string, string) {
+	if withdraw && bet.Amount == 0 {
+		return "", ""
+	}
+	if bet.Amount <= 0 {
+		return "amount", "must be a positive number"
+	}
+	if !bet.Choice.IsValid() {
+		return "choice", "must be heads or tails"
+	}
+	if bet.SentAtMs < 0 {
+		return "sent_at_ms", "must not be negative"
+	}
+	return "", ""
+}
+
+// checkSettings checks room settings hold no negative or non-finite
+// values; whether they make a playable room is left to RoomConfig
+func checkSettings(settings *RoomSettings) (string, string) {
+	if settings == nil {
+		return "", ""
+	}
+
+	counts := []struct {
+		field string
+		value int
+	}{
+		{"min_players", settings.MinPlayers},
+		{"max_players", settings.MaxPlayers},
+		{"betting_seconds", settings.BettingSeconds},
+		{"result_seconds", settings.ResultSeconds},
+		{"early_close_seconds", settings.EarlyCloseSeconds},
+		{"idle_rounds", settings.IdleRounds},
+		{"idle_release_rounds", settings.IdleReleaseRounds},
+	}
+	for _, count := range counts {
+		if count.value < 0 {
+			return "settings." + count.field, "must not be negative"
+		}
+	}
+
+	if settings.MinBet < 0 {
+		return "settings.min_bet", "must not be negative"
+	}
+	if settings.MaxBet < 0 {
+		return "settings.max_bet", "must not be negative"
+	}
+
+	ratios := []struct {
+		field string
+		value float64
+	}{
+		{"payout_ratio", settings.PayoutRatio},
+		{"streak_bonus", settings.StreakBonus},
+		{"max_streak_multiplier", settings.MaxStreakMultiplier},
+		{"insurance_cost", settings.InsuranceCost},
+		{"insurance_coverage", settings.InsuranceCoverage},
+		{"rake", settings.Rake},
+	}
+	for _, ratio := range ratios {
+		if math.IsNaN(ratio.value) || math.IsInf(ratio.value, 0) || ratio.value < 0 {
+			return "settings." + ratio.field, "must be a non-negative number"
+		}
+	}
+
+	if !RoomMode(settings.Mode).Valid() {
+		return "settings.mode", "is not a known room mode"
+	}
+	return "", ""
+}
+
+// sentAmounts holds the money amounts of a client message as they were
+// sent, before decoding rounds them to whole cents
+type sentAmounts struct {
+	Balance float64 `json:"balance"`
+	Amount  float64 `json:"amount"`
+	Net     float64 `json:"net"`
+	Bet     struct {
+		Amount float64 `json:"amount"`
+	} `json:"bet"`
+	Bets []struct {
+		Amount float64 `json:"amount"`
+	} `json:"bets"`
+	Settings struct {
+		MinBet float64 `json:"min_bet"`
+		MaxBet float64 `json:"max_bet"`
+	} `json:"settings"`
+}
+
+// checkCents checks every money amount in a message is a whole number of
+// cents, so a bet of 10.555 is refused rather than quietly rounded
+func checkCents(msg *Message) (string, string) {
+	var sent sentAmounts
+	if err := msg.GetData(&sent); err != nil {
+		return "data", "malformed amounts"
+	}
+
+	amounts := []struct {
+		field string
+		value float64
+	}{
+		{"balance", sent.Balance},
+		{"amount", sent.Amount},
+		{"net", sent.Net},
+		{"bet.amount", sent.Bet.Amount},
+		{"settings.min_bet", sent.Settings.MinBet},
+		{"settings.max_bet", sent.Settings.MaxBet},
+	}
+	for i, bet := range sent.Bets {
+		amounts = append(amounts, struct {
+			field string
+			value float64
+		}{fmt.Sprintf("bets[%d].amount", i), bet.Amount})
+	}
+	for _, amount := range amounts {
+		if _, err := game.MoneyFromFloat(amount.value); err != nil {
+			return amount.field, "must be a whole number of cents"
+		}
+	}
+	return "", ""
+}
+
+// ValidateID checks an ID the way the server checks those clients send,
+// such as a room to join. An empty ID is valid.
+func ValidateID(id string) error {
+	if reason := checkID(id); reason != "" {
+		return errors.New(reason)
+	}
+	return nil
+}
+
+// checkID returns why an ID is unacceptable, or "" for a valid or empty ID
+func checkID(id string) string {
+	if reason := checkText(id, MaxIDLength); reason != "" {
+		return reason
+	}
+	if strings.IndexFunc(id, unicode.IsSpace) >= 0 {
+		return "must not contain spaces"
+	}
+	return ""
+}
+
+// checkText returns why a name or message is unacceptable, or "" for
+// valid or empty text. JSON decoding replaces malformed UTF-8 with
+// utf8.RuneError, so that is rejected along with invalid bytes.
+func checkText(text string, maxLength int) string {
+	if !utf8.ValidString(text) || strings.ContainsRune(text, utf8.RuneError) {
+		return "must be valid UTF-8"
+	}
+	if utf8.RuneCountInString(text) > maxLength {
+		return fmt.Sprintf("must be at most %d characters", maxLength)
+	}
+	if strings.IndexFunc(text, unicode.IsControl) >= 0 {
+		return "must not contain control characters"
+	}
+	return ""
+}
@@ -0,0 +1,235 @@
+package network
+
+import (
+	"math"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestValidateMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   *Message
+		code  string
+		field string
+	}{
+		{name: "join", msg: NewMessage(MsgJoinRoom, "lobby", "p1", RoomJoinData{PlayerName: "Alice", Balance: 1000 * game.Dollar})},
+		{name: "join with long name", msg: NewMessage(MsgJoinRoom, "lobby", "p1", RoomJoinData{PlayerName: strings.Repeat("a", MaxPlayerNameLength+1)}),
+			code: "invalid_data", field: "player_name"},
+		{name: "join with control characters", msg: NewMessage(MsgJoinRoom, "lobby", "p1", RoomJoinData{PlayerName: "Al\x1b[2Jice"}),
+			code: "invalid_data", field: "player_name"},
+		{name: "join with negative balance", msg: NewMessage(MsgJoinRoom, "lobby", "p1", RoomJoinData{PlayerName: "Alice", Balance: -game.Dollar}),
+			code: "invalid_data", field: "balance"},
+		{name: "join with absurd balance", msg: NewMessage(MsgJoinRoom, "lobby", "p1", RoomJoinData{PlayerName: "Alice", Balance: MaxBalance + game.Cent}),
+			code: "invalid_data", field: "balance"},
+		{name: "join with negative settings", msg: NewMessage(MsgJoinRoom, "lobby", "p1", RoomJoinData{Settings: &RoomSettings{MaxBet: -5 * game.Dollar}}),
+			code: "invalid_data", field: "settings.max_bet"},
+		{name: "long room ID", msg: NewMessage(MsgLeaveRoom, strings.Repeat("r", MaxIDLength+1), "p1", nil),
+			code: "invalid_message", field: "room_id"},
+		{name: "player ID with spaces", msg: NewMessage(MsgLeaveRoom, "lobby", "p 1", nil),
+			code: "invalid_message", field: "player_id"},
+		{name: "bet", msg: NewMessage(MsgBetPlaced, "lobby", "p1", BetData{Amount: 10 * game.Dollar, Choice: game.Heads})},
+		{name: "negative bet", msg: NewMessage(MsgBetPlaced, "lobby", "p1", BetData{Amount: -10 * game.Dollar, Choice: game.Heads}),
+			code: "invalid_bet_data", field: "amount"},
+		{name: "bet without side", msg: NewMessage(MsgUpdateBet, "lobby", "p1", BetData{Amount: 10 * game.Dollar}),
+			code: "invalid_bet_data", field: "choice"},
+		{name: "queued bet withdrawal", msg: NewMessage(MsgQueuedBet, "lobby", "p1", QueuedBetData{})},
+		{name: "negative queued bet", msg: NewMessage(MsgQueuedBet, "lobby", "p1", QueuedBetData{Bet: BetData{Amount: -game.Dollar, Choice: game.Tails}}),
+			code: "invalid_bet_data", field: "amount"},
+		{name: "bet slip", msg: NewMessage(MsgBetSlip, "lobby", "p1", BetSlipData{Bets: []BetData{{Amount: game.Dollar, Choice: game.Heads}, {Amount: 2 * game.Dollar, Choice: game.Tails}}})},
+		{name: "bet slip clear", msg: NewMessage(MsgBetSlip, "lobby", "p1", BetSlipData{})},
+		{name: "bet slip without side", msg: NewMessage(MsgBetSlip, "lobby", "p1", BetSlipData{Bets: []BetData{{Amount: game.Dollar, Choice: game.Heads}, {Amount: game.Dollar}}}),
+			code: "invalid_bet_data", field: "bets[1].choice"},
+		{name: "bet slip too long", msg: NewMessage(MsgBetSlip, "lobby", "p1", BetSlipData{Bets: make([]BetData, MaxBetSlipBets+1)}),
+			code: "invalid_bet_data", field: "bets"},
+		{name: "chat", msg: NewMessage(MsgChat, "lobby", "p1", ChatData{Text: "good luck 🍀"})},
+		{name: "blank chat", msg: NewMessage(MsgChat, "lobby", "p1", ChatData{Text: "   "}),
+			code: "invalid_chat", field: "text"},
+		{name: "malformed UTF-8 chat", msg: NewMessage(MsgChat, "lobby", "p1", ChatData{Text: "hi \xff\xfe"}),
+			code: "invalid_chat", field: "text"},
+		{name: "oversize chat", msg: NewMessage(MsgChat, "lobby", "p1", ChatData{Text: strings.Repeat("é", MaxChatLength+1)}),
+			code: "invalid_chat", field: "text"},
+		{name: "proposal with negative streak bonus", msg: NewMessage(MsgConfigProposal, "lobby", "p1", ConfigProposalData{Settings: &RoomSettings{StreakBonus: -0.5}}),
+			code: "invalid_data", field: "settings.streak_bonus"},
+		{name: "proposal with negative insurance coverage", msg: NewMessage(MsgConfigProposal, "lobby", "p1", ConfigProposalData{Settings: &RoomSettings{InsuranceCoverage: -0.5}}),
+			code: "invalid_data", field: "settings.insurance_coverage"},
+		{name: "pause request", msg: NewMessage(MsgPauseProposal, "lobby", "p1", PauseProposalData{Action: PauseActionResume})},
+		{name: "pause request with unknown action", msg: NewMessage(MsgPauseProposal, "lobby", "p1", PauseProposalData{Action: "stop"}),
+			code: "invalid_data", field: "action"},
+		{name: "empty promo code", msg: NewMessage(MsgRedeemCode, "", "p1", RedeemCodeData{}),
+			code: "invalid_data", field: "code"},
+		{name: "register", msg: NewMessage(MsgRegister, "", "guest_1", RegisterData{Account: "alice"})},
+		{name: "register with bad account", msg: NewMessage(MsgRegister, "", "guest_1", RegisterData{Account: "guest_alice"}),
+			code: "invalid_data", field: "account"},
+		{name: "add friend by code", msg: NewMessage(MsgAddFriend, "", "alice", FriendsData{Friend: "K3QF-7ZPA"})},
+		{name: "add friend without a name", msg: NewMessage(MsgAddFriend, "", "alice", FriendsData{}),
+			code: "invalid_data", field: "friend"},
+		{name: "ask for notification settings", msg: NewMessage(MsgNotifySettings, "", "alice", NotifySettingsData{})},
+		{name: "notify a bad email", msg: NewMessage(MsgNotifySettings, "", "alice", NotifySettingsData{Settings: &game.NotifySettings{Email: "alice"}}),
+			code: "invalid_data", field: "settings"},
+		{name: "invite without a friend", msg: NewMessage(MsgRoomInvite, "lobby", "alice", InviteData{}),
+			code: "invalid_data", field: "to"},
+		{name: "create private room", msg: NewMessage(MsgCreateRoom, "friday", "p1", RoomCreateData{RoomName: "Friday", Settings: &RoomSettings{Mode: "streak", Private: true}})},
+		{name: "create room with unknown mode", msg: NewMessage(MsgCreateRoom, "friday", "p1", RoomCreateData{Settings: &RoomSettings{Mode: "roulette"}}),
+			code: "invalid_data", field: "settings.mode"},
+		{name: "create room with negative early close", msg: NewMessage(MsgCreateRoom, "friday", "p1", RoomCreateData{Settings: &RoomSettings{EarlyClose: true, EarlyCloseSeconds: -1}}),
+			code: "invalid_data", field: "settings.early_close_seconds"},
+		{name: "unknown type", msg: NewMessage("mystery", "lobby", "p1", nil)},
+	}
+
+	for _, tt := range tests {
+		err := ValidateMessage(tt.msg)
+		if tt.code == "" {
+			assert.NoError(t, err, tt.name)
+			continue
+		}
+
+		var invalid *ValidationError
+		require.ErrorAs(t, err, &invalid, tt.name)
+		assert.ErrorIs(t, err, ErrInvalidMessage, tt.name)
+		assert.Equal(t, tt.code, invalid.Code, tt.name)
+		assert.Equal(t, tt.field, invalid.Field, tt.name)
+	}
+}
+
+func TestValidateMessage_NonFiniteAmounts(t *testing.T) {
+	// JSON cannot carry NaN or infinities, but msgpack frames can
+	for _, amount := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		bet := map[string]interface{}{"amount": amount, "choice": game.Heads}
+		data, err := NewMessage(MsgBetPlaced, "lobby", "p1", bet).Encode(EncodingMsgPack)
+		require.NoError(t, err)
+		msg, err := DecodeMessage(data, EncodingMsgPack)
+		require.NoError(t, err)
+		assert.ErrorIs(t, ValidateMessage(msg), ErrInvalidMessage, amount)
+	}
+}
+
+func TestValidateMessage_FractionsOfACent(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   *Message
+		code  string
+		field string
+	}{
+		{name: "bet", msg: NewMessage(MsgBetPlaced, "lobby", "p1", map[string]interface{}{"amount": 10.555, "choice": game.Heads}),
+			code: "invalid_bet_data", field: "amount"},
+		{name: "queued bet", msg: NewMessage(MsgQueuedBet, "lobby", "p1", map[string]interface{}{"bet": map[string]interface{}{"amount": 0.001, "choice": game.Tails}}),
+			code: "invalid_bet_data", field: "bet.amount"},
+		{name: "bet slip", msg: NewMessage(MsgBetSlip, "lobby", "p1", map[string]interface{}{"bets": []map[string]interface{}{{"amount": 5, "choice": game.Heads}, {"amount": 2.505, "choice": game.Tails}}}),
+			code: "invalid_bet_data", field: "bets[1].amount"},
+		{name: "join balance", msg: NewMessage(MsgJoinRoom, "lobby", "p1", map[string]interface{}{"player_name": "Alice", "balance": 99.999}),
+			code: "invalid_data", field: "balance"},
+		{name: "settings", msg: NewMessage(MsgConfigProposal, "lobby", "p1", map[string]interface{}{"settings": map[string]interface{}{"min_bet": 0.015}}),
+			code: "invalid_data", field: "settings.min_bet"},
+		{name: "whole cents", msg: NewMessage(MsgBetPlaced, "lobby", "p1", map[string]interface{}{"amount": 0.1 + 0.2, "choice": game.Heads})},
+	}
+
+	for _, tt := range tests {
+		for _, encoding := range []Encoding{EncodingJSON, EncodingMsgPack} {
+			data, err := tt.msg.Encode(encoding)
+			require.NoError(t, err)
+			msg, err := DecodeMessage(data, encoding)
+			require.NoError(t, err)
+
+			err = ValidateMessage(msg)
+			if tt.code == "" {
+				assert.NoError(t, err, tt.name)
+				continue
+			}
+			var invalid *ValidationError
+			require.ErrorAs(t, err, &invalid, tt.name)
+			assert.Equal(t, tt.code, invalid.Code, tt.name)
+			assert.Equal(t, tt.field, invalid.Field, tt.name)
+		}
+	}
+}
+
+func TestServer_RejectsInvalidMessages(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	join, err := NewMessage(MsgJoinRoom, "lobby", "p1", RoomJoinData{PlayerName: "Alice", Balance: 1e12 * game.Dollar}).Encode(EncodingJSON)
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, join))
+
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	msg, err := DecodeMessage(data, EncodingJSON)
+	require.NoError(t, err)
+	require.Equal(t, MsgError, msg.Type)
+
+	var reply ErrorData
+	require.NoError(t, msg.GetData(&reply))
+	assert.Equal(t, "invalid_data", reply.Code)
+	assert.Contains(t, reply.Message, "balance")
+
+	// The room was never created for the rejected player
+	_, exists := server.GetRoom("lobby")
+	assert.False(t, exists)
+}
+
+func FuzzValidateMessage(f *testing.F) {
+	for _, msg := range []*Message{
+		NewMessage(MsgJoinRoom, "lobby", "p1", RoomJoinData{PlayerName: "Alice", Balance: 1000 * game.Dollar}),
+		NewMessage(MsgBetPlaced, "lobby", "p1", BetData{Amount: 10 * game.Dollar, Choice: game.Heads}),
+		NewMessage(MsgQueuedBet, "lobby", "p1", QueuedBetData{Bet: BetData{Amount: 5 * game.Dollar, Choice: game.Tails}}),
+		NewMessage(MsgChat, "lobby", "p1", ChatData{Text: "hello"}),
+		NewMessage(MsgConfigProposal, "lobby", "p1", ConfigProposalData{Settings: &RoomSettings{MinBet: 2 * game.Dollar}}),
+	} {
+		data, err := msg.Encode(EncodingJSON)
+		require.NoError(f, err)
+		f.Add(data)
+	}
+	f.Add([]byte(`{"type":"bet_placed","data":{"amount":-0,"choice":"heads"}}`))
+	f.Add([]byte(`{"type":"chat","data":{"text":"\u0000\ud800"}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg, err := DecodeMessage(data, EncodingJSON)
+		if err != nil {
+			return
+		}
+		if ValidateMessage(msg) != nil {
+			return
+		}
+
+		// Whatever passes must be safe for the handlers to use as is
+		switch msg.Type {
+		case MsgJoinRoom:
+			var join RoomJoinData
+			require.NoError(t, msg.GetData(&join))
+			assert.True(t, join.Balance >= 0 && join.Balance <= MaxBalance, join.Balance)
+			assert.LessOrEqual(t, len([]rune(join.PlayerName)), MaxPlayerNameLength)
+		case MsgBetPlaced, MsgUpdateBet:
+			var bet BetData
+			require.NoError(t, msg.GetData(&bet))
+			assert.Positive(t, bet.Amount)
+			assert.True(t, bet.Choice.IsValid())
+		case MsgChat:
+			var chat ChatData
+			require.NoError(t, msg.GetData(&chat))
+			text := strings.TrimSpace(chat.Text)
+			assert.NotEmpty(t, text)
+			assert.LessOrEqual(t, len([]rune(text)), MaxChatLength)
+			assert.NotContains(t, text, "\x00")
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrProposalOpen = errors.New("a settings proposal is already open")
+	ErrNoProposal   = errors.New("no matching settings proposal")
+)
+
+// configProposal is a room settings change awaiting a majority of players
+type configProposal struct {
+	id         string
+	proposerID string
+	settings   *RoomSettings
+	config     *RoomConfig
+	votes      map[string]bool
+}
+
+// ProposeConfig opens a vote on changing the room's settings. Zero fields
+// keep their current value. The proposer's vote counts in favour, so in a
+// single-player room the change passes at once.
+func (r *GameRoom) ProposeConfig(playerID string, settings *RoomSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.players[playerID]; !exists {
+		return ErrPlayerNotFound
+	}
+	if r.proposal != nil {
+		return ErrProposalOpen
+	}
+	if settings == nil {
+		return fmt.Errorf("%w: no settings proposed", ErrInvalidRoomConfig)
+	}
+
+	config, err := r.config.WithSettings(settings)
+	if err != nil {
+		return err
+	}
+	// Seated players were given the stack the room opened with
+	if config.Freeroll.Stack != r.config.Freeroll.Stack {
+		return fmt.Errorf("%w: the starting stack is set when the room opens", ErrInvalidRoomConfig)
+	}
+	if config.MaxPlayers < len(r.players) {
+		return fmt.Errorf("%w: max players %d is below the %d players in the room",
+			ErrInvalidRoomConfig, config.MaxPlayers, len(r.players))
+	}
+
+	r.proposal = &configProposal{
+		id:         fmt.Sprintf("proposal_%s_%d", r.id, r.clock.Now().UnixNano()),
+		proposerID: playerID,
+		settings:   settings,
+		config:     config,
+		votes:      map[string]bool{playerID: true},
+	}
+	r.lastActivity = r.clock.Now()
+
+	r.logger.Info("Room settings proposed",
+		zap.String("room_id", r.id),
+		zap.String("proposal_id", r.proposal.id),
+		zap.String("player_id", playerID),
+	)
+
+	r.broadcastMessage(NewMessage(MsgConfigProposal, r.id, playerID, r.proposalData(ProposalOpen)))
+	r.tallyProposal()
+	return nil
+}
+
+// VoteConfig records a player's vote on the open proposal. Players may
+// change their vote until the proposal is decided.
+func (r *GameRoom) VoteConfig(playerID, proposalID string, approve bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.players[playerID]; !exists {
+		return ErrPlayerNotFound
+	}
+	if r.proposal == nil || r.proposal.id != proposalID {
+		return ErrNoProposal
+	}
+
+	r.proposal.votes[playerID] = approve
+	r.lastActivity = r.clock.Now()
+
+	r.tallyProposal()
+	return nil
+}
+
+// tallyProposal decides the open proposal once a majority of the players
+// currently in the room agrees or it can no longer pass, and otherwise
+// broadcasts the running tally. Callers must hold r.mu.
+func (r *GameRoom) tallyProposal() {
+	proposal := r.proposal
+	if proposal == nil {
+		return
+	}
+
+	yes, no := r.countVotes()
+	eligible := len(r.players)
+
+	status := ProposalOpen
+	switch {
+	case eligible == 0:
+		r.proposal = nil
+		return
+	case yes*2 > eligible:
+		status = ProposalPassed
+	case no*2 >= eligible:
+		status = ProposalRejected
+	}
+
+	r.broadcastMessage(NewMessage(MsgConfigVote, r.id, "", r.proposalData(status)))
+	if status == ProposalOpen {
+		return
+	}
+
+	r.proposal = nil
+	r.logger.Info("Room settings vote decided",
+		zap.String("room_id", r.id),
+		zap.String("proposal_id", proposal.id),
+		zap.String("status", string(status)),
+		zap.Int("yes", yes),
+		zap.Int("no", no),
+	)
+
+	if status == ProposalPassed {
+		// Settings only change between rounds
+		if r.currentRound != nil {
+			r.pendingConfig = proposal.config
+			return
+		}
+		r.applyConfig(proposal.config)
+	}
+}
+
+// countVotes counts the votes of players still in the room
+func (r *GameRoom) countVotes() (yes, no int) {
+	for playerID, approve := range r.proposal.votes {
+		if _, exists := r.players[playerID]; !exists {
+			continue
+		}
+		if approve {
+			yes++
+		} else {
+			no++
+		}
+	}
+	return yes, no
+}
+
+// proposalData describes the open proposal for broadcasting. Callers must
+// hold r.mu.
+func (r *GameRoom) proposalData(status ProposalStatus) *ConfigProposalData {
+	yes, no := r.countVotes()
+	return &ConfigProposalData{
+		ProposalID: r.proposal.id,
+		ProposerID: r.proposal.proposerID,
+		Settings:   r.proposal.settings,
+		Status:     status,
+		Yes:        yes,
+		No:         no,
+		Eligible:   len(r.players),
+	}
+}
+
+// applyConfig switches the room to agreed settings and announces them.
+// Callers must hold r.mu.
+func (r *GameRoom) applyConfig(config *RoomConfig) {
+	r.config = config
+	r.pendingConfig = nil
+
+	r.logger.Info("Room settings changed",
+		zap.String("room_id", r.id),
+		zap.Int("min_players", config.MinPlayers),
+		zap.Int("max_players", config.MaxPlayers),
+		zap.Float64("min_bet", config.MinBet.Float64()),
+		zap.Float64("max_bet", config.MaxBet.Float64()),
+		zap.Duration("betting_duration", config.BettingDuration),
+	)
+
+	r.broadcastMessage(NewMessage(MsgConfigChanged, r.id, "", config.Settings()))
+	r.broadcastRoomUpdate()
+	r.checkAndStartGame()
+}
+
+// applyPendingConfig applies settings agreed during the last round. Callers
+// must hold r.mu.
+func (r *GameRoom) applyPendingConfig() {
+	if r.pendingConfig != nil {
+		r.applyConfig(r.pendingConfig)
+	}
+}
+
+// GetConfig returns a copy of the room's current settings
+func (r *GameRoom) GetConfig() RoomConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return *r.config
+}
@@ -0,0 +1,130 @@
+package network
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+// lastProposal returns the most recent proposal broadcast among messages
+func lastProposal(t *testing.T, messages []*Message) *ConfigProposalData {
+	t.Helper()
+
+	var proposal *ConfigProposalData
+	for _, msg := range messages {
+		if data, ok := msg.Data.(*ConfigProposalData); ok {
+			proposal = data
+		}
+	}
+	require.NotNil(t, proposal, "no proposal broadcast")
+	return proposal
+}
+
+func TestGameRoom_ConfigVotePassesBetweenRounds(t *testing.T) {
+	room, scheduler, fake := newTestRoom(t)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	drainEvents(room)
+
+	require.NoError(t, room.ProposeConfig("p1", &RoomSettings{BettingSeconds: 20, MinBet: 5 * game.Dollar}))
+	assert.ErrorIs(t, room.ProposeConfig("p2", &RoomSettings{MinBet: 2 * game.Dollar}), ErrProposalOpen)
+
+	proposal := lastProposal(t, drainEvents(room))
+	assert.Equal(t, ProposalOpen, proposal.Status)
+	assert.Equal(t, 1, proposal.Yes)
+	assert.Equal(t, 2, proposal.Eligible)
+
+	assert.ErrorIs(t, room.VoteConfig("p2", "unknown", true), ErrNoProposal)
+	require.NoError(t, room.VoteConfig("p2", proposal.ProposalID, true))
+	assert.Equal(t, ProposalPassed, lastProposal(t, drainEvents(room)).Status)
+
+	// The round in progress keeps its settings
+	assert.Equal(t, game.Dollar, room.GetConfig().MinBet)
+	require.NoError(t, room.PlaceBet("p2", 2*game.Dollar, game.Heads))
+
+	fake.Advance(10 * time.Second)
+	scheduler.advance(fake.Now())
+	require.Equal(t, StateResult, room.GetGameState())
+	fake.Advance(ResultPhaseDuration)
+	scheduler.advance(fake.Now())
+
+	config := room.GetConfig()
+	assert.Equal(t, 5*game.Dollar, config.MinBet)
+	assert.Equal(t, 20*time.Second, config.BettingDuration)
+
+	var changed *RoomSettings
+	for _, msg := range drainEvents(room) {
+		if msg.Type == MsgConfigChanged {
+			changed = msg.Data.(*RoomSettings)
+		}
+	}
+	require.NotNil(t, changed)
+	assert.Equal(t, 20, changed.BettingSeconds)
+}
+
+func TestGameRoom_ConfigVoteRejected(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+
+	assert.ErrorIs(t, room.ProposeConfig("p1", &RoomSettings{MinBet: 500 * game.Dollar}), ErrInvalidRoomConfig)
+
+	require.NoError(t, room.ProposeConfig("p1", &RoomSettings{MinBet: 5 * game.Dollar}))
+	proposal := lastProposal(t, drainEvents(room))
+
+	// A tie cannot reach a majority
+	require.NoError(t, room.VoteConfig("p2", proposal.ProposalID, false))
+	assert.Equal(t, ProposalRejected, lastProposal(t, drainEvents(room)).Status)
+	assert.Equal(t, game.Dollar, room.GetConfig().MinBet)
+
+	// The room is free for a new proposal
+	require.NoError(t, room.ProposeConfig("p2", &RoomSettings{MinBet: 2 * game.Dollar}))
+}
+
+func TestGameRoom_ConfigVoteRetalliedWhenPlayerLeaves(t *testing.T) {
+	room, _, _ := newTestRoom(t)
+	require.NoError(t, room.AddPlayer("p2", "Player 2", 100*game.Dollar))
+	require.NoError(t, room.AddPlayer("p3", "Player 3", 100*game.Dollar))
+
+	require.NoError(t, room.ProposeConfig("p1", &RoomSettings{MaxBet: 50 * game.Dollar}))
+	require.NoError(t, room.RemovePlayer("p3"))
+	drainEvents(room)
+
+	// One of the two remaining players agreed, which is no majority yet
+	require.NoError(t, room.RemovePlayer("p2"))
+	assert.Equal(t, ProposalPassed, lastProposal(t, drainEvents(room)).Status)
+}
+
+// Run with -race: votes change a room's settings while the room list reads
+// them
+func TestServer_VoteWhileListingRooms(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	room, err := server.CreateRoom("voting", "Voting", DefaultRoomConfig())
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("p1", "Player 1", 100*game.Dollar))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			recorder := httptest.NewRecorder()
+			server.handleRooms(recorder, httptest.NewRequest(http.MethodGet, "/rooms", nil))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		}
+	}()
+
+	// A lone player's proposals pass at once
+	for i := 0; i < 50; i++ {
+		require.NoError(t, room.ProposeConfig("p1", &RoomSettings{MaxPlayers: 5 + i%2, MaxBet: game.Money(50+i) * game.Dollar}))
+	}
+	wg.Wait()
+	assert.Equal(t, 99*game.Dollar, room.GetConfig().MaxBet)
+}
@@ -0,0 +1,114 @@
+package network
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+)
+
+// DefaultStartingBalance is what new players join their first room with
+const DefaultStartingBalance = 1000 * game.Dollar
+
+// ErrSeatedElsewhere is returned when a player joins a room while holding a
+// seat, and the balance that comes with it, in another
+var ErrSeatedElsewhere = errors.New("leave your current room before joining another")
+
+// JoinBalance returns the balance a player brings to room. It is the
+// balance the server has on record, never an amount the client claims, so
+// a tampered join cannot add funds. Players the server has not seen before
+// are given the starting balance, which is recorded at once. A player
+// seated in another room must leave it first, so the same money is never
+// at two tables. A freeroll seats them with its own stack instead, or what
+// they kept of it from an earlier visit, leaving the balance on record as
+// it is.
+func (s *Server) JoinBalance(ctx context.Context, playerID string, room *GameRoom) (game.Money, error) {
+	if playerID == "" {
+		return 0, ErrPlayerNotFound
+	}
+	if roomID, seated := s.seatedElsewhere(playerID, room); seated {
+		s.logger.Warn("Join refused while seated in another room",
+			zap.String("player_id", playerID),
+			zap.String("room_id", room.ID()),
+			zap.String("seated_in", roomID),
+		)
+		return 0, ErrSeatedElsewhere
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	player, err := s.results.GetPlayer(ctx, playerID)
+	if err != nil {
+		player = s.playerRecord(ctx, playerID)
+		if err := s.results.SavePlayer(ctx, player); err != nil {
+			return 0, err
+		}
+		s.logger.Info("Opened player wallet",
+			zap.String("player_id", playerID),
+			zap.Float64("balance", player.Balance.Float64()),
+		)
+	}
+	return room.seatBalance(playerID, player.Balance), nil
+}
+
+// storeBalance records the balance a player left room with as their
+// balance on record. Leaving a freeroll pays what they won on its stack
+// into the balance on record instead, and the room keeps the rest of the
+// stack for their return.
+func (s *Server) storeBalance(ctx context.Context, playerID string, room *GameRoom, balance game.Money) {
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	player := s.playerRecord(ctx, playerID)
+	if freeroll := room.Freeroll(); freeroll.Enabled() {
+		winnings := freeroll.Winnings(balance)
+		player.Balance += winnings
+		room.keepStack(playerID, balance)
+		s.logger.Info("Settled freeroll stack",
+			zap.String("player_id", playerID),
+			zap.String("room_id", room.ID()),
+			zap.Float64("stack", balance.Float64()),
+			zap.Float64("winnings", winnings.Float64()),
+		)
+		if winnings > 0 {
+			s.config.Audit.Record(logger.AuditEvent{
+				Time:     s.scheduler.Clock().Now(),
+				Event:    logger.AuditCredit,
+				PlayerID: playerID,
+				RoomID:   room.ID(),
+				Amount:   winnings.Float64(),
+				Balance:  player.Balance.Float64(),
+				Reason:   "freeroll winnings",
+			})
+		}
+	} else {
+		player.Balance = balance
+	}
+	if err := s.results.SavePlayer(ctx, player); err != nil {
+		s.logger.Error("Failed to record player balance",
+			zap.String("player_id", playerID),
+			zap.Error(err),
+		)
+	}
+}
+
+// seatedElsewhere reports a room other than room where the player holds a
+// seat, online or kept for them to reclaim
+func (s *Server) seatedElsewhere(playerID string, room *GameRoom) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for id, other := range s.rooms {
+		if other == room {
+			continue
+		}
+		if _, ok := other.GetPlayers()[playerID]; ok {
+			return id, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,119 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/logger"
+)
+
+// DefaultMaxOfflineWinnings is the most offline play adds to a wallet in
+// one day by default
+const DefaultMaxOfflineWinnings = 1000 * game.Dollar
+
+// ErrWalletInUse is returned when syncing the wallet of a player seated in
+// a room, whose balance the room holds until they leave
+var ErrWalletInUse = errors.New("leave your room before syncing your wallet")
+
+// SyncWallet applies net, what a player's balance changed by playing
+// offline, to their wallet on record and returns the new balance and the
+// part of net that was applied. What offline play adds to a wallet in a
+// day, across every sync, is capped at MaxOfflineWinnings, so winnings
+// past it are not credited; losses made that day count against the cap.
+// Losses past the balance leave it at zero. A net of zero only reads the
+// balance, opening a wallet for a new player.
+func (s *Server) SyncWallet(ctx context.Context, playerID string, net game.Money) (balance, applied game.Money, err error) {
+	if playerID == "" {
+		return 0, 0, ErrPlayerNotFound
+	}
+
+	// Holding joinMu keeps the player from taking a seat, and the balance
+	// with it, while the wallet changes
+	s.joinMu.Lock()
+	defer s.joinMu.Unlock()
+
+	if roomID, seated := s.seatedElsewhere(playerID, nil); seated {
+		s.logger.Warn("Wallet sync refused while seated",
+			zap.String("player_id", playerID),
+			zap.String("seated_in", roomID),
+		)
+		return 0, 0, ErrWalletInUse
+	}
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	player := s.playerRecord(ctx, playerID)
+
+	day := s.scheduler.Clock().Now().UTC().Format(time.DateOnly)
+	credited := player.CreditedOffline(day)
+
+	applied = net
+	if limit := s.config.MaxOfflineWinnings; limit > 0 && applied > limit-credited {
+		applied = max(limit-credited, 0)
+	}
+	if player.Balance+applied < 0 {
+		applied = -player.Balance
+	}
+	player.Balance += applied
+	if applied != 0 {
+		player.Offline = &game.OfflineCredit{Day: day, Amount: credited + applied}
+	}
+
+	if err := s.results.SavePlayer(ctx, player); err != nil {
+		return 0, 0, err
+	}
+	s.sessions.SetBalance(playerID, player.Balance)
+
+	if applied != 0 {
+		s.config.Audit.Record(logger.AuditEvent{
+			Time:     s.scheduler.Clock().Now(),
+			Event:    logger.AuditCredit,
+			PlayerID: playerID,
+			Amount:   applied.Float64(),
+			Balance:  player.Balance.Float64(),
+			Reason:   "offline play",
+		})
+	}
+	s.logger.Info("Wallet synced",
+		zap.String("player_id", playerID),
+		zap.Float64("net", net.Float64()),
+		zap.Float64("applied", applied.Float64()),
+		zap.Float64("balance", player.Balance.Float64()),
+	)
+	return player.Balance, applied, nil
+}
+
+// handleWalletSync applies the client's offline play to its wallet
+func (c *Client) handleWalletSync(msg *Message) {
+	var sync WalletSyncData
+	if err := msg.GetData(&sync); err != nil {
+		c.sendError("invalid_data", "Invalid wallet sync data")
+		return
+	}
+
+	playerID, ok := c.identified("sync_failed")
+	if !ok {
+		return
+	}
+
+	balance, applied, err := c.server.SyncWallet(c.server.ctx, playerID, sync.Net)
+	if errors.Is(err, ErrWalletInUse) {
+		c.sendError("wallet_busy", err.Error())
+		return
+	}
+	if err != nil {
+		c.sendError("sync_failed", err.Error())
+		return
+	}
+
+	c.sendMessage(NewMessage(MsgWalletSync, msg.RoomID, playerID, WalletSyncData{
+		Net:     sync.Net,
+		Applied: applied,
+		Balance: balance,
+	}))
+}
@@ -0,0 +1,114 @@
+package network
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/storage"
+)
+
+func TestServer_SyncWallet(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	config := DefaultServerConfig()
+	config.Clock = fake
+	config.MaxOfflineWinnings = 500 * game.Dollar
+	server := NewServer(config, zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+
+	// A new player's wallet opens with the starting balance
+	balance, applied, err := server.SyncWallet(ctx, "alice", 120*game.Dollar)
+	require.NoError(t, err)
+	assert.Equal(t, 120*game.Dollar, applied)
+	assert.Equal(t, DefaultStartingBalance+120*game.Dollar, balance)
+
+	// Winnings past what is left of the day's cap are not credited
+	balance, applied, err = server.SyncWallet(ctx, "alice", 2000*game.Dollar)
+	require.NoError(t, err)
+	assert.Equal(t, 380*game.Dollar, applied)
+	assert.Equal(t, 1500*game.Dollar, balance)
+
+	// Sending them again adds nothing until the next day
+	_, applied, err = server.SyncWallet(ctx, "alice", 2000*game.Dollar)
+	require.NoError(t, err)
+	assert.Zero(t, applied)
+
+	fake.Advance(24 * time.Hour)
+	balance, applied, err = server.SyncWallet(ctx, "alice", 100*game.Dollar)
+	require.NoError(t, err)
+	assert.Equal(t, 100*game.Dollar, applied)
+	assert.Equal(t, 1600*game.Dollar, balance)
+
+	// The allowance is kept with the wallet
+	stored, err := server.Results().GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, &game.OfflineCredit{Day: "2024-01-02", Amount: 100 * game.Dollar}, stored.Offline)
+
+	// Losses past the balance leave it empty
+	balance, applied, err = server.SyncWallet(ctx, "alice", -5000*game.Dollar)
+	require.NoError(t, err)
+	assert.Equal(t, -1600*game.Dollar, applied)
+	assert.Zero(t, balance)
+
+	// A seated player's balance belongs to their room
+	room, err := server.CreateRoom("r1", "Room 1", DefaultRoomConfig())
+	require.NoError(t, err)
+	require.NoError(t, room.AddPlayer("bob", "Bob", 100*game.Dollar))
+	_, _, err = server.SyncWallet(ctx, "bob", game.Dollar)
+	assert.ErrorIs(t, err, ErrWalletInUse)
+}
+
+func TestBankroll_SyncsOfflinePlay(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+	ctx := context.Background()
+
+	engineConfig := game.Config{StartingBalance: DefaultStartingBalance, MinBet: game.Dollar, MaxBet: 100 * game.Dollar, PayoutRatio: 2.0}
+	engine := game.NewEngine(engineConfig, storage.NewMemoryRepository(), game.NewDefaultRandomGenerator(), zaptest.NewLogger(t))
+	cache, err := storage.OpenWalletCache("")
+	require.NoError(t, err)
+
+	clientConfig := DefaultClientConfig()
+	clientConfig.ServerURL = "ws://" + listener.Addr().String() + "/ws"
+	bankroll := NewBankroll(engine, cache, clientConfig, "alice", zaptest.NewLogger(t))
+
+	restored, err := bankroll.Restore(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStartingBalance, restored)
+
+	// Offline winnings reach the server wallet on the next sync
+	_, err = engine.AdjustBalance(ctx, "alice", 75*game.Dollar, "test")
+	require.NoError(t, err)
+	require.NoError(t, bankroll.Record(ctx))
+	assert.Equal(t, 75*game.Dollar, bankroll.Pending())
+
+	synced, err := bankroll.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 75*game.Dollar, synced.Applied)
+	assert.Equal(t, 1075*game.Dollar, synced.Balance)
+	assert.Zero(t, bankroll.Pending())
+
+	// Online play reaches the engine the same way
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "alice", Balance: 900 * game.Dollar}))
+	synced, err = bankroll.Sync(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, synced.Applied)
+	player, err := engine.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 900*game.Dollar, player.Balance)
+}
@@ -0,0 +1,95 @@
+package network
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestServer_JoinIgnoresClientBalance(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+	ctx := context.Background()
+	require.NoError(t, server.Results().SavePlayer(ctx, &game.Player{ID: "veteran", Balance: 40 * game.Dollar}))
+
+	config := DefaultClientConfig()
+	config.ServerURL = "ws://" + listener.Addr().String() + "/ws"
+
+	// A new player gets the starting balance, whatever the client claims
+	newcomer := NewNetworkClient(config, "newcomer", "Newcomer", zaptest.NewLogger(t))
+	require.NoError(t, newcomer.Connect())
+	t.Cleanup(newcomer.Disconnect)
+	require.NoError(t, newcomer.JoinRoom("r1", MaxBalance))
+
+	// A known player brings the balance on record
+	veteran := NewNetworkClient(config, "veteran", "Veteran", zaptest.NewLogger(t))
+	require.NoError(t, veteran.Connect())
+	t.Cleanup(veteran.Disconnect)
+	require.NoError(t, veteran.JoinRoom("r1", 5000*game.Dollar))
+
+	waitFor(t, func() bool {
+		room, ok := server.GetRoom("r1")
+		return ok && len(room.GetPlayers()) == 2
+	})
+	room, _ := server.GetRoom("r1")
+	players := room.GetPlayers()
+	assert.Equal(t, DefaultStartingBalance, players["newcomer"].Balance)
+	assert.Equal(t, 40*game.Dollar, players["veteran"].Balance)
+
+	// Leaving records the balance the player leaves with, and rejoining
+	// with a bigger claim brings back only that
+	_, err = room.Charge("veteran", 15*game.Dollar, "test")
+	require.NoError(t, err)
+	require.NoError(t, veteran.LeaveRoom())
+	waitFor(t, func() bool {
+		_, seated := room.GetPlayers()["veteran"]
+		return !seated
+	})
+	player, err := server.Results().GetPlayer(ctx, "veteran")
+	require.NoError(t, err)
+	assert.Equal(t, 25*game.Dollar, player.Balance)
+
+	require.NoError(t, veteran.JoinRoom("r1", 5000*game.Dollar))
+	waitFor(t, func() bool {
+		_, seated := room.GetPlayers()["veteran"]
+		return seated
+	})
+	assert.Equal(t, 25*game.Dollar, room.GetPlayers()["veteran"].Balance)
+}
+
+func TestServer_JoinBalanceOneRoomAtATime(t *testing.T) {
+	server := NewServer(DefaultServerConfig(), zaptest.NewLogger(t))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+
+	first, err := server.CreateRoom("r1", "Room 1", DefaultRoomConfig())
+	require.NoError(t, err)
+	second, err := server.CreateRoom("r2", "Room 2", DefaultRoomConfig())
+	require.NoError(t, err)
+
+	balance, err := server.JoinBalance(ctx, "p1", first)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStartingBalance, balance)
+	require.NoError(t, first.AddPlayer("p1", "Player 1", balance))
+
+	// The same money cannot be brought to a second table
+	_, err = server.JoinBalance(ctx, "p1", second)
+	assert.ErrorIs(t, err, ErrSeatedElsewhere)
+
+	// Rejoining the same room is left to the room to decide
+	_, err = server.JoinBalance(ctx, "p1", first)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,57 @@
+package network
+
+import (
+	"go.uber.org/zap"
+)
+
+// watchRoom follows a room's events without taking a seat, so the watcher
+// is not waited for, bets nothing and holds no balance in the room. Unlike
+// joining, watching never creates a room.
+func (c *Client) watchRoom(msg *Message, playerName string) {
+	room, exists := c.server.GetRoom(msg.RoomID)
+	if !exists {
+		c.sendError("join_failed", ErrRoomNotFound.Error())
+		return
+	}
+
+	c.playerID = msg.PlayerID
+	c.name = playerName
+	c.server.mu.Lock()
+	previous := c.room
+	c.server.clients.move(c, room)
+	c.room = room
+	c.server.mu.Unlock()
+	c.leavePreviousQueue(previous, room)
+
+	if err := room.resendUpdate(); err != nil {
+		c.server.mu.Lock()
+		notified := c.room != room
+		c.server.clients.move(c, previous)
+		c.room = previous
+		c.server.mu.Unlock()
+		if !notified {
+			c.sendRoomClosed(room)
+		}
+		return
+	}
+
+	c.server.logger.Info("Player watching room",
+		zap.String("player_id", msg.PlayerID),
+		zap.String("room_id", msg.RoomID),
+	)
+}
+
+// resendUpdate sends the room's seats and game state in full, for a
+// spectator who has seen none of them yet. It fails with ErrRoomClosed once
+// the room has closed.
+func (r *GameRoom) resendUpdate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrRoomClosed
+	}
+	r.sentPlayers = nil
+	r.broadcastRoomUpdate()
+	return nil
+}
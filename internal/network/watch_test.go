@@ -0,0 +1,72 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+)
+
+// nextEvent returns the client's next message of the type
+func nextEvent(t *testing.T, client *NetworkClient, msgType MessageType) *Message {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg := <-client.GetEventChannel():
+			if msg.Type == msgType {
+				return msg
+			}
+		case <-timeout:
+			t.Fatalf("no %s message", msgType)
+		}
+	}
+}
+
+func TestServer_WatchRoom(t *testing.T) {
+	server, url := startTestServer(t, DefaultServerConfig())
+	config := DefaultClientConfig()
+	config.ServerURL = url
+
+	watcher := NewNetworkClient(config, "w1", "Watcher", zap.NewNop())
+	defer watcher.Disconnect()
+	require.NoError(t, watcher.Connect())
+
+	// Watching never creates a room
+	require.NoError(t, watcher.WatchRoom("r1"))
+	var errorData ErrorData
+	require.NoError(t, nextEvent(t, watcher, MsgError).GetData(&errorData))
+	assert.Equal(t, "join_failed", errorData.Code)
+	_, exists := server.GetRoom("r1")
+	assert.False(t, exists)
+
+	player := NewNetworkClient(config, "p1", "Player 1", zap.NewNop())
+	defer player.Disconnect()
+	require.NoError(t, player.Connect())
+	require.NoError(t, player.JoinRoom("r1", 100*game.Dollar))
+	waitFor(t, func() bool {
+		room, ok := server.GetRoom("r1")
+		return ok && len(room.GetPlayers()) == 1
+	})
+
+	// The watcher sees the seats without taking one, then the room's events
+	require.NoError(t, watcher.WatchRoom("r1"))
+	var update RoomUpdateData
+	require.NoError(t, nextEvent(t, watcher, MsgRoomUpdate).GetData(&update))
+	require.Len(t, update.Players, 1)
+	assert.Equal(t, "p1", update.Players[0].ID)
+
+	require.NoError(t, player.SendChat("hello"))
+	var chat ChatData
+	require.NoError(t, nextEvent(t, watcher, MsgChat).GetData(&chat))
+	assert.Equal(t, "hello", chat.Text)
+	nextEvent(t, player, MsgChat)
+
+	room, _ := server.GetRoom("r1")
+	assert.NotContains(t, room.GetPlayers(), "w1")
+}
@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP accepts one mail on a local port and hands back its envelope
+// and data
+func fakeSMTP(t *testing.T) (int, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		var lines []string
+		reply("220 fake ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch command := strings.ToUpper(strings.Fields(line + " x")[0]); command {
+			case "EHLO", "HELO":
+				reply("250 fake")
+			case "MAIL", "RCPT":
+				lines = append(lines, line)
+				reply("250 OK")
+			case "DATA":
+				reply("354 go ahead")
+				for {
+					data, err := reader.ReadString('\n')
+					if err != nil || data == ".\r\n" {
+						break
+					}
+					lines = append(lines, strings.TrimRight(data, "\r\n"))
+				}
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				received <- lines
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port, received
+}
+
+func TestEmailSender_Send(t *testing.T) {
+	port, received := fakeSMTP(t)
+	sender, err := NewEmailSender("127.0.0.1", port, "", "", "Coin Flip <flips@example.com>")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, sender.Send(ctx, "alice@example.com", Message{Subject: "🎉 Big win", Text: "You won $500.00\non heads"}))
+
+	lines := <-received
+	assert.True(t, strings.HasPrefix(lines[0], "MAIL FROM:<flips@example.com>"), lines[0])
+	assert.Equal(t, "RCPT TO:<alice@example.com>", lines[1])
+	assert.Contains(t, lines, "To: alice@example.com")
+	assert.Contains(t, lines, "Subject: =?utf-8?q?=F0=9F=8E=89_Big_win?=")
+	assert.Contains(t, lines, "You won $500.00")
+	assert.Contains(t, lines, "on heads")
+}
+
+func TestEmailSender_RejectsHeaderInjection(t *testing.T) {
+	sender, err := NewEmailSender("127.0.0.1", 1, "", "", "flips@example.com")
+	require.NoError(t, err)
+	err = sender.Send(context.Background(), "alice@example.com\r\nBcc: eve@example.com", Message{Text: "hi"})
+	assert.ErrorContains(t, err, "invalid email address")
+}
+
+func TestNewEmailSender(t *testing.T) {
+	sender, err := NewEmailSender("smtp.example.com", 0, "", "", "flips@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:"+strconv.Itoa(DefaultSMTPPort), sender.addr)
+	assert.Nil(t, sender.auth, "no login without a username")
+
+	_, err = NewEmailSender("", 25, "", "", "flips@example.com")
+	assert.Error(t, err)
+}
@@ -0,0 +1,232 @@
+// Package notify sends players messages about their play while they are
+// away from the game, by email and Telegram. Messages are queued and sent
+// in the background, so a slow mail server never holds up a round.
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Channel is a way of reaching a player
+type Channel string
+
+const (
+	ChannelEmail    Channel = "email"
+	ChannelTelegram Channel = "telegram"
+)
+
+// Defaults for the notifier
+const (
+	DefaultQueueSize   = 256
+	DefaultTimeout     = 10 * time.Second
+	DefaultTelegramURL = "https://api.telegram.org"
+	DefaultSMTPPort    = 587
+)
+
+// ErrNoChannels is returned by New when no channel is configured
+var ErrNoChannels = errors.New("notifications need a Telegram bot token or an SMTP host")
+
+// Message is one notification
+type Message struct {
+	Subject string
+	Text    string
+}
+
+// Sender delivers a message to an address on its channel
+type Sender interface {
+	Send(ctx context.Context, to string, message Message) error
+}
+
+// Config controls how notifications are sent
+type Config struct {
+	// TelegramToken is the bot token from @BotFather, and TelegramURL the
+	// Bot API it is used with
+	TelegramToken string
+	TelegramURL   string
+	// SMTPHost, when set, sends email through it from EmailFrom. The
+	// username and password are optional.
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	EmailFrom    string
+	// QueueSize is how many messages wait to be sent before more are
+	// dropped, and Timeout bounds sending one
+	QueueSize int
+	Timeout   time.Duration
+}
+
+// Stats counts the notifications handled since the notifier started
+type Stats struct {
+	Sent    uint64 `json:"sent"`
+	Failed  uint64 `json:"failed"`
+	Dropped uint64 `json:"dropped"`
+}
+
+// delivery is a queued message for one address
+type delivery struct {
+	channel Channel
+	to      string
+	message Message
+}
+
+// Notifier queues messages and sends them in the background. A nil
+// *Notifier discards every message, so the server can leave notifications
+// unconfigured.
+type Notifier struct {
+	senders map[Channel]Sender
+	timeout time.Duration
+	logger  *zap.Logger
+
+	// mu guards closed, so nothing is queued once the queue is closed
+	mu     sync.RWMutex
+	closed bool
+	queue  chan delivery
+	done   chan struct{}
+
+	sent    atomic.Uint64
+	failed  atomic.Uint64
+	dropped atomic.Uint64
+}
+
+// New creates a notifier for the channels config sets up and starts
+// sending
+func New(config Config, logger *zap.Logger) (*Notifier, error) {
+	senders := make(map[Channel]Sender)
+	if config.TelegramToken != "" {
+		senders[ChannelTelegram] = NewTelegramSender(config.TelegramURL, config.TelegramToken)
+	}
+	if config.SMTPHost != "" {
+		email, err := NewEmailSender(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.EmailFrom)
+		if err != nil {
+			return nil, err
+		}
+		senders[ChannelEmail] = email
+	}
+	if len(senders) == 0 {
+		return nil, ErrNoChannels
+	}
+	return NewWithSenders(senders, config.QueueSize, config.Timeout, logger), nil
+}
+
+// NewWithSenders creates a notifier sending through the given senders and
+// starts sending. A queue size or timeout of zero uses the default.
+func NewWithSenders(senders map[Channel]Sender, queueSize int, timeout time.Duration, logger *zap.Logger) *Notifier {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	n := &Notifier{
+		senders: senders,
+		timeout: timeout,
+		logger:  logger,
+		queue:   make(chan delivery, queueSize),
+		done:    make(chan struct{}),
+	}
+	go n.run()
+	return n
+}
+
+// Channels reports whether each channel can be sent on
+func (n *Notifier) Channels() map[Channel]bool {
+	channels := make(map[Channel]bool)
+	if n == nil {
+		return channels
+	}
+	for channel := range n.senders {
+		channels[channel] = true
+	}
+	return channels
+}
+
+// Notify queues a message for an address on a channel, reporting whether
+// it was queued. Messages for channels that are not set up, or beyond a
+// full queue, are dropped.
+func (n *Notifier) Notify(channel Channel, to string, message Message) bool {
+	if n == nil || to == "" {
+		return false
+	}
+	if _, ok := n.senders[channel]; !ok {
+		return false
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		return false
+	}
+
+	select {
+	case n.queue <- delivery{channel: channel, to: to, message: message}:
+		return true
+	default:
+		n.dropped.Add(1)
+		n.logger.Warn("Notification queue full, dropping message",
+			zap.String("channel", string(channel)),
+			zap.String("subject", message.Subject),
+		)
+		return false
+	}
+}
+
+// Stats returns the notifications sent, failed and dropped so far
+func (n *Notifier) Stats() Stats {
+	if n == nil {
+		return Stats{}
+	}
+	return Stats{
+		Sent:    n.sent.Load(),
+		Failed:  n.failed.Load(),
+		Dropped: n.dropped.Load(),
+	}
+}
+
+// Close stops taking messages and waits for the queued ones to be sent
+func (n *Notifier) Close() error {
+	if n == nil {
+		return nil
+	}
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	<-n.done
+	return nil
+}
+
+// run sends queued messages until the queue is closed
+func (n *Notifier) run() {
+	defer close(n.done)
+
+	for d := range n.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+		err := n.senders[d.channel].Send(ctx, d.to, d.message)
+		cancel()
+
+		if err != nil {
+			n.failed.Add(1)
+			n.logger.Warn("Failed to send notification",
+				zap.String("channel", string(d.channel)),
+				zap.String("subject", d.message.Subject),
+				zap.Error(err),
+			)
+			continue
+		}
+		n.sent.Add(1)
+	}
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// recordingSender keeps what it is asked to send, failing for one address
+type recordingSender struct {
+	mu     sync.Mutex
+	sent   map[string][]Message
+	failTo string
+}
+
+func (r *recordingSender) Send(ctx context.Context, to string, message Message) error {
+	if to == r.failTo {
+		return errors.New("unreachable")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sent == nil {
+		r.sent = make(map[string][]Message)
+	}
+	r.sent[to] = append(r.sent[to], message)
+	return nil
+}
+
+func TestNotifier_SendsQueuedMessages(t *testing.T) {
+	sender := &recordingSender{failTo: "down@example.com"}
+	notifier := NewWithSenders(map[Channel]Sender{ChannelEmail: sender}, 0, 0, zaptest.NewLogger(t))
+
+	win := Message{Subject: "Big win", Text: "You won $500.00"}
+	assert.True(t, notifier.Notify(ChannelEmail, "alice@example.com", win))
+	assert.True(t, notifier.Notify(ChannelEmail, "down@example.com", win))
+	assert.False(t, notifier.Notify(ChannelTelegram, "12345", win), "telegram is not set up")
+	assert.False(t, notifier.Notify(ChannelEmail, "", win))
+	assert.Equal(t, map[Channel]bool{ChannelEmail: true}, notifier.Channels())
+
+	// Closing sends what was queued and takes no more
+	require.NoError(t, notifier.Close())
+	assert.False(t, notifier.Notify(ChannelEmail, "alice@example.com", win))
+	assert.Equal(t, []Message{win}, sender.sent["alice@example.com"])
+	assert.Equal(t, Stats{Sent: 1, Failed: 1}, notifier.Stats())
+	assert.NoError(t, notifier.Close())
+}
+
+func TestNotifier_Nil(t *testing.T) {
+	var notifier *Notifier
+	assert.False(t, notifier.Notify(ChannelEmail, "alice@example.com", Message{}))
+	assert.Empty(t, notifier.Channels())
+	assert.Equal(t, Stats{}, notifier.Stats())
+	assert.NoError(t, notifier.Close())
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{}, nil)
+	assert.ErrorIs(t, err, ErrNoChannels)
+
+	_, err = New(Config{SMTPHost: "smtp.example.com", EmailFrom: "not an address"}, nil)
+	assert.Error(t, err)
+
+	notifier, err := New(Config{TelegramToken: "123:abc", SMTPHost: "smtp.example.com", EmailFrom: "Coin Flip <flips@example.com>"}, nil)
+	require.NoError(t, err)
+	defer notifier.Close()
+	assert.Equal(t, map[Channel]bool{ChannelEmail: true, ChannelTelegram: true}, notifier.Channels())
+}
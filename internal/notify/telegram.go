@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TelegramSender sends messages through a Telegram bot to chat IDs or
+// @channel names
+type TelegramSender struct {
+	endpoint string
+	client   *http.Client
+}
+
+// telegramReply is the Bot API's answer to a request
+type telegramReply struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+// NewTelegramSender creates a sender for the bot with token on the Bot API
+// at apiURL, DefaultTelegramURL when empty
+func NewTelegramSender(apiURL, token string) *TelegramSender {
+	if apiURL == "" {
+		apiURL = DefaultTelegramURL
+	}
+	return &TelegramSender{
+		endpoint: strings.TrimSuffix(apiURL, "/") + "/bot" + token + "/sendMessage",
+		client:   &http.Client{},
+	}
+}
+
+// Send posts the message to the chat
+func (t *TelegramSender) Send(ctx context.Context, chat string, message Message) error {
+	text := message.Text
+	if message.Subject != "" {
+		text = message.Subject + "\n\n" + text
+	}
+	body, err := json.Marshal(map[string]string{"chat_id": chat, "text": text})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("invalid Telegram API URL")
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		// The URL carries the bot token, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	var reply telegramReply
+	if err := json.NewDecoder(response.Body).Decode(&reply); err != nil {
+		return fmt.Errorf("telegram replied with status %d", response.StatusCode)
+	}
+	if !reply.OK {
+		return fmt.Errorf("telegram refused the message: %s", reply.Description)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramSender_Send(t *testing.T) {
+	var received map[string]string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bot123:abc/sendMessage", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received["chat_id"] == "404" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(telegramReply{Description: "Bad Request: chat not found"})
+			return
+		}
+		json.NewEncoder(w).Encode(telegramReply{OK: true})
+	}))
+	defer api.Close()
+
+	sender := NewTelegramSender(api.URL+"/", "123:abc")
+	require.NoError(t, sender.Send(context.Background(), "-100123", Message{Subject: "Big win", Text: "You won $500.00"}))
+	assert.Equal(t, map[string]string{"chat_id": "-100123", "text": "Big win\n\nYou won $500.00"}, received)
+
+	err := sender.Send(context.Background(), "404", Message{Text: "hello"})
+	assert.ErrorContains(t, err, "chat not found")
+}
+
+func TestTelegramSender_HidesToken(t *testing.T) {
+	api := httptest.NewServer(http.NotFoundHandler())
+	api.Close()
+
+	err := NewTelegramSender(api.URL, "123:secret").Send(context.Background(), "1", Message{Text: "hello"})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package presence
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// dialIPC connects to the first Discord IPC socket found in the
+// directories Discord may put it in
+func dialIPC() (io.ReadWriteCloser, error) {
+	var dirs []string
+	for _, env := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+		if dir := os.Getenv(env); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	dirs = append(dirs, "/tmp")
+
+	for _, dir := range dirs {
+		// Flatpak and Snap builds of Discord keep theirs in a subdirectory
+		for _, sub := range []string{"", "app/com.discordapp.Discord", "snap.discord"} {
+			for i := 0; i < 10; i++ {
+				path := filepath.Join(dir, sub, fmt.Sprintf("discord-ipc-%d", i))
+				if conn, err := net.Dial("unix", path); err == nil {
+					return conn, nil
+				}
+			}
+		}
+	}
+	return nil, ErrUnavailable
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package presence
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// dialIPC opens the first Discord IPC named pipe available
+func dialIPC() (io.ReadWriteCloser, error) {
+	for i := 0; i < 10; i++ {
+		pipe, err := os.OpenFile(fmt.Sprintf(`\\.\pipe\discord-ipc-%d`, i), os.O_RDWR, 0)
+		if err == nil {
+			return pipe, nil
+		}
+	}
+	return nil, ErrUnavailable
+}
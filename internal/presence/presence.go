@@ -0,0 +1,284 @@
+// Package presence shows what the player is doing on their Discord profile
+// through the local Discord client's RPC socket, and hands back the game
+// invites the player accepts in Discord.
+package presence
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Frame opcodes of the Discord IPC protocol
+const (
+	opHandshake = 0
+	opFrame     = 1
+	opClose     = 2
+	opPing      = 3
+	opPong      = 4
+)
+
+// maxFrameSize bounds the frames read from Discord
+const maxFrameSize = 64 * 1024
+
+// Errors returned by the presence client
+var (
+	ErrUnavailable = errors.New("discord is not running")
+	ErrClosed      = errors.New("presence connection closed")
+)
+
+// joinSecretPrefix marks the join secrets of coin flip rooms
+const joinSecretPrefix = "coinflip-room:"
+
+// JoinSecret returns the secret Discord hands to friends who join the
+// player's game, naming the room to join
+func JoinSecret(roomID string) string {
+	return joinSecretPrefix + roomID
+}
+
+// RoomFromSecret returns the room a join secret names, reporting false for
+// secrets not made by JoinSecret
+func RoomFromSecret(secret string) (string, bool) {
+	roomID, ok := strings.CutPrefix(secret, joinSecretPrefix)
+	return roomID, ok && roomID != ""
+}
+
+// Activity is what the player's profile shows: Details on the first line
+// and State on the second, with how long the game has been going. A party
+// shows how full the room is, and a join secret lets friends ask to join.
+type Activity struct {
+	Details    string
+	State      string
+	Start      time.Time // Zero hides the elapsed time
+	PartyID    string
+	PartySize  int
+	PartyMax   int
+	JoinSecret string // Empty hides the join button
+}
+
+// Client is a connection to the local Discord client for one application
+type Client struct {
+	conn   io.ReadWriteCloser
+	onJoin func(secret string)
+
+	writeMu sync.Mutex
+	nonce   atomic.Uint64
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Dial connects to the Discord client running on this machine as the
+// application appID. onJoin, if set, receives the join secret of each game
+// invite the player accepts in Discord. It fails with ErrUnavailable when
+// Discord is not running.
+func Dial(appID string, onJoin func(secret string)) (*Client, error) {
+	conn, err := dialIPC()
+	if err != nil {
+		return nil, err
+	}
+	client, err := newClient(conn, appID, onJoin)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// newClient completes the handshake on an open connection and starts
+// reading events from it
+func newClient(conn io.ReadWriteCloser, appID string, onJoin func(secret string)) (*Client, error) {
+	c := &Client{conn: conn, onJoin: onJoin, done: make(chan struct{})}
+
+	if err := c.write(opHandshake, map[string]interface{}{"v": 1, "client_id": appID}); err != nil {
+		return nil, fmt.Errorf("discord handshake: %w", err)
+	}
+	op, payload, err := readFrame(conn)
+	if err != nil {
+		return nil, fmt.Errorf("discord handshake: %w", err)
+	}
+	if op == opClose {
+		return nil, fmt.Errorf("discord refused the connection: %s", closeReason(payload))
+	}
+
+	if onJoin != nil {
+		if err := c.command("SUBSCRIBE", "ACTIVITY_JOIN", nil); err != nil {
+			return nil, err
+		}
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// SetActivity shows activity on the player's profile; nil clears it
+func (c *Client) SetActivity(activity *Activity) error {
+	args := map[string]interface{}{"pid": os.Getpid()}
+	if activity != nil {
+		args["activity"] = activity.payload()
+	}
+	return c.command("SET_ACTIVITY", "", args)
+}
+
+// Done is closed once the connection to Discord is lost or closed
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close disconnects from Discord, which clears the activity
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.conn.Close()
+		close(c.done)
+	})
+	return err
+}
+
+// payload returns the activity as Discord expects it
+func (a *Activity) payload() map[string]interface{} {
+	activity := map[string]interface{}{"instance": false}
+	if a.Details != "" {
+		activity["details"] = a.Details
+	}
+	if a.State != "" {
+		activity["state"] = a.State
+	}
+	if !a.Start.IsZero() {
+		activity["timestamps"] = map[string]int64{"start": a.Start.Unix()}
+	}
+	if a.PartyID != "" {
+		party := map[string]interface{}{"id": a.PartyID}
+		if a.PartyMax > 0 {
+			party["size"] = [2]int{a.PartySize, a.PartyMax}
+		}
+		activity["party"] = party
+	}
+	if a.JoinSecret != "" {
+		activity["secrets"] = map[string]string{"join": a.JoinSecret}
+	}
+	return activity
+}
+
+// command sends an RPC command, subscribing to evt when it is set. Replies
+// are not waited for.
+func (c *Client) command(cmd, evt string, args map[string]interface{}) error {
+	message := map[string]interface{}{
+		"cmd":   cmd,
+		"nonce": strconv.FormatUint(c.nonce.Add(1), 10),
+	}
+	if evt != "" {
+		message["evt"] = evt
+	}
+	if args != nil {
+		message["args"] = args
+	}
+	return c.write(opFrame, message)
+}
+
+// event is an RPC message received from Discord
+type event struct {
+	Cmd  string          `json:"cmd"`
+	Evt  string          `json:"evt"`
+	Data json.RawMessage `json:"data"`
+}
+
+// readLoop answers pings and passes accepted invites to onJoin until the
+// connection closes
+func (c *Client) readLoop() {
+	defer c.Close()
+
+	for {
+		op, payload, err := readFrame(c.conn)
+		if err != nil {
+			return
+		}
+
+		switch op {
+		case opPing:
+			if c.writeRaw(opPong, payload) != nil {
+				return
+			}
+		case opClose:
+			return
+		case opFrame:
+			var msg event
+			if json.Unmarshal(payload, &msg) != nil || msg.Cmd != "DISPATCH" || msg.Evt != "ACTIVITY_JOIN" {
+				continue
+			}
+			var join struct {
+				Secret string `json:"secret"`
+			}
+			if json.Unmarshal(msg.Data, &join) == nil && join.Secret != "" && c.onJoin != nil {
+				c.onJoin(join.Secret)
+			}
+		}
+	}
+}
+
+// write sends v as a JSON frame
+func (c *Client) write(op uint32, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeRaw(op, payload)
+}
+
+// writeRaw sends a frame: its opcode and length, little endian, then the
+// payload
+func (c *Client) writeRaw(op uint32, payload []byte) error {
+	frame := make([]byte, 8+len(payload))
+	binary.LittleEndian.PutUint32(frame[0:4], op)
+	binary.LittleEndian.PutUint32(frame[4:8], uint32(len(payload)))
+	copy(frame[8:], payload)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	select {
+	case <-c.done:
+		return ErrClosed
+	default:
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// readFrame reads one frame's opcode and payload
+func readFrame(r io.Reader) (uint32, []byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	op := binary.LittleEndian.Uint32(header[0:4])
+	length := binary.LittleEndian.Uint32(header[4:8])
+	if length > maxFrameSize {
+		return 0, nil, fmt.Errorf("discord frame of %d bytes is too large", length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return op, payload, nil
+}
+
+// closeReason returns the message of a close frame
+func closeReason(payload []byte) string {
+	var reason struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(payload, &reason) != nil || reason.Message == "" {
+		return "no reason given"
+	}
+	return reason.Message
+}
@@ -0,0 +1,154 @@
+package presence
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDiscord is the Discord end of a client connection
+type fakeDiscord struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+// send writes a frame to the client
+func (d *fakeDiscord) send(op uint32, payload string) {
+	frame := make([]byte, 8+len(payload))
+	binary.LittleEndian.PutUint32(frame[0:4], op)
+	binary.LittleEndian.PutUint32(frame[4:8], uint32(len(payload)))
+	copy(frame[8:], payload)
+	_, err := d.conn.Write(frame)
+	require.NoError(d.t, err)
+}
+
+// receive reads a frame from the client
+func (d *fakeDiscord) receive() (uint32, map[string]interface{}) {
+	d.conn.SetReadDeadline(time.Now().Add(time.Second))
+	op, payload, err := readFrame(d.conn)
+	require.NoError(d.t, err)
+
+	var message map[string]interface{}
+	require.NoError(d.t, json.Unmarshal(payload, &message))
+	return op, message
+}
+
+// connect opens a client to a fake Discord that accepts the handshake and,
+// with onJoin set, the subscription to invites
+func connect(t *testing.T, onJoin func(secret string)) (*Client, *fakeDiscord) {
+	clientConn, discordConn := net.Pipe()
+	discord := &fakeDiscord{t: t, conn: discordConn}
+	t.Cleanup(func() { discordConn.Close() })
+
+	go func() {
+		op, handshake := discord.receive()
+		assert.Equal(t, uint32(opHandshake), op)
+		assert.Equal(t, "123456", handshake["client_id"])
+		discord.send(opFrame, `{"cmd":"DISPATCH","evt":"READY","data":{"v":1}}`)
+
+		if onJoin != nil {
+			_, subscribe := discord.receive()
+			assert.Equal(t, "SUBSCRIBE", subscribe["cmd"])
+			assert.Equal(t, "ACTIVITY_JOIN", subscribe["evt"])
+		}
+	}()
+
+	client, err := newClient(clientConn, "123456", onJoin)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client, discord
+}
+
+func TestClient_SetActivity(t *testing.T) {
+	client, discord := connect(t, nil)
+	start := time.Unix(1700000000, 0)
+
+	go client.SetActivity(&Activity{
+		Details:    "Betting in room lobby",
+		State:      "Balance $230.00",
+		Start:      start,
+		PartyID:    "lobby",
+		PartySize:  3,
+		PartyMax:   8,
+		JoinSecret: JoinSecret("lobby"),
+	})
+	op, message := discord.receive()
+	assert.Equal(t, uint32(opFrame), op)
+	assert.Equal(t, "SET_ACTIVITY", message["cmd"])
+	assert.NotEmpty(t, message["nonce"])
+
+	args := message["args"].(map[string]interface{})
+	assert.NotZero(t, args["pid"])
+	activity := args["activity"].(map[string]interface{})
+	assert.Equal(t, "Betting in room lobby", activity["details"])
+	assert.Equal(t, "Balance $230.00", activity["state"])
+	assert.Equal(t, map[string]interface{}{"start": float64(start.Unix())}, activity["timestamps"])
+	assert.Equal(t, map[string]interface{}{"id": "lobby", "size": []interface{}{float64(3), float64(8)}}, activity["party"])
+	assert.Equal(t, map[string]interface{}{"join": "coinflip-room:lobby"}, activity["secrets"])
+
+	// Clearing sends no activity at all
+	go client.SetActivity(nil)
+	_, message = discord.receive()
+	assert.NotContains(t, message["args"], "activity")
+}
+
+func TestClient_JoinInvites(t *testing.T) {
+	joins := make(chan string, 1)
+	client, discord := connect(t, func(secret string) { joins <- secret })
+
+	// Pings are answered with the same payload
+	discord.send(opPing, `{"n":1}`)
+	op, pong := discord.receive()
+	assert.Equal(t, uint32(opPong), op)
+	assert.Equal(t, float64(1), pong["n"])
+
+	discord.send(opFrame, `{"cmd":"DISPATCH","evt":"ACTIVITY_JOIN","data":{"secret":"coinflip-room:high-rollers"}}`)
+	select {
+	case secret := <-joins:
+		roomID, ok := RoomFromSecret(secret)
+		assert.True(t, ok)
+		assert.Equal(t, "high-rollers", roomID)
+	case <-time.After(time.Second):
+		t.Fatal("no join received")
+	}
+
+	// Discord closing the connection ends the client
+	discord.send(opClose, `{"code":1000,"message":"bye"}`)
+	select {
+	case <-client.Done():
+	case <-time.After(time.Second):
+		t.Fatal("client still open")
+	}
+	assert.ErrorIs(t, client.SetActivity(nil), ErrClosed)
+}
+
+func TestClient_HandshakeRefused(t *testing.T) {
+	clientConn, discordConn := net.Pipe()
+	defer discordConn.Close()
+	discord := &fakeDiscord{t: t, conn: discordConn}
+
+	go func() {
+		discord.receive()
+		discord.send(opClose, `{"code":4000,"message":"Invalid Client ID"}`)
+	}()
+
+	_, err := newClient(clientConn, "0", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid Client ID")
+}
+
+func TestRoomFromSecret(t *testing.T) {
+	roomID, ok := RoomFromSecret(JoinSecret("lobby"))
+	assert.True(t, ok)
+	assert.Equal(t, "lobby", roomID)
+
+	_, ok = RoomFromSecret("lobby")
+	assert.False(t, ok)
+	_, ok = RoomFromSecret(JoinSecret(""))
+	assert.False(t, ok)
+}
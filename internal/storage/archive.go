@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// Archive file naming
+const (
+	archiveFilePrefix = "results-"
+	archiveFileSuffix = ".jsonl.gz"
+	archiveTimeLayout = "20060102T150405Z"
+)
+
+// ArchiveConfig controls when results leave hot storage and how long archives are kept
+type ArchiveConfig struct {
+	// Directory receives the compressed archive files
+	Directory string
+	// ArchiveAfter is the age at which results are moved out of hot storage
+	ArchiveAfter time.Duration
+	// Retention is how long archive files are kept; zero keeps them forever
+	Retention time.Duration
+}
+
+// ArchiveReport describes the outcome of a single archival pass
+type ArchiveReport struct {
+	ArchivedResults int       `json:"archived_results"`
+	ArchiveFile     string    `json:"archive_file,omitempty"`
+	PurgedFiles     int       `json:"purged_files"`
+	Cutoff          time.Time `json:"cutoff"`
+}
+
+// Archiver moves old results from a MemoryRepository into gzip-compressed
+// JSON-lines files and deletes archive files past their retention period
+type Archiver struct {
+	repo   *MemoryRepository
+	config ArchiveConfig
+	now    func() time.Time
+}
+
+// NewArchiver creates a new archiver for the given repository
+func NewArchiver(repo *MemoryRepository, config ArchiveConfig) *Archiver {
+	return &Archiver{
+		repo:   repo,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Archive runs one archival pass: old results are written to a new archive
+// file and removed from the repository, then expired archive files are purged.
+// If the archive file cannot be written the results are restored.
+func (a *Archiver) Archive(ctx context.Context) (*ArchiveReport, error) {
+	if a.config.Directory == "" {
+		return nil, fmt.Errorf("archive directory cannot be empty")
+	}
+
+	now := a.now().UTC()
+	report := &ArchiveReport{
+		Cutoff: now.Add(-a.config.ArchiveAfter),
+	}
+
+	if err := os.MkdirAll(a.config.Directory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	results, err := a.repo.TakeResultsBefore(ctx, report.Cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect results: %w", err)
+	}
+
+	if len(results) > 0 {
+		path := filepath.Join(a.config.Directory, archiveFilePrefix+now.Format(archiveTimeLayout)+archiveFileSuffix)
+		if err := writeArchive(path, results); err != nil {
+			// The results are restored even if the pass was cancelled
+			// meanwhile, or they would be lost
+			if restoreErr := a.repo.RestoreResults(context.WithoutCancel(ctx), results); restoreErr != nil {
+				return nil, fmt.Errorf("failed to write archive (%v) and to restore results: %w", err, restoreErr)
+			}
+			return nil, fmt.Errorf("failed to write archive: %w", err)
+		}
+		report.ArchivedResults = len(results)
+		report.ArchiveFile = path
+	}
+
+	purged, err := a.purgeExpired(now)
+	if err != nil {
+		return report, fmt.Errorf("failed to purge expired archives: %w", err)
+	}
+	report.PurgedFiles = purged
+
+	return report, nil
+}
+
+// purgeExpired deletes archive files older than the retention period
+func (a *Archiver) purgeExpired(now time.Time) (int, error) {
+	if a.config.Retention <= 0 {
+		return 0, nil
+	}
+
+	entries, err := os.ReadDir(a.config.Directory)
+	if err != nil {
+		return 0, err
+	}
+
+	expiry := now.Add(-a.config.Retention)
+	purged := 0
+	for _, entry := range entries {
+		created, ok := archiveFileTime(entry.Name())
+		if !ok || !created.Before(expiry) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(a.config.Directory, entry.Name())); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// archiveFileTime extracts the creation time encoded in an archive file name
+func archiveFileTime(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, archiveFilePrefix) || !strings.HasSuffix(name, archiveFileSuffix) {
+		return time.Time{}, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, archiveFilePrefix), archiveFileSuffix)
+	created, err := time.Parse(archiveTimeLayout, stamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created, true
+}
+
+// writeArchive writes results to a gzip-compressed JSON-lines file
+func writeArchive(path string, results []*game.Result) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(file)
+	encoder := json.NewEncoder(gz)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			gz.Close()
+			file.Close()
+			os.Remove(path)
+			return err
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
+
+// ReadArchive reads all results from an archive file
+func ReadArchive(path string) ([]*game.Result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer gz.Close()
+
+	results := make([]*game.Result, 0)
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var result game.Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return nil, fmt.Errorf("failed to decode archived result: %w", err)
+		}
+		results = append(results, &result)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	return results, nil
+}
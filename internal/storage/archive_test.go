@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coinflip-game/internal/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_TakeResultsBefore(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	now := time.Now()
+
+	for i, age := range []time.Duration{72 * time.Hour, 48 * time.Hour, time.Hour} {
+		err := repo.SaveResult(ctx, &game.Result{
+			ID:        fmt.Sprintf("result_%d", i),
+			Side:      game.Heads,
+			Timestamp: now.Add(-age),
+		})
+		require.NoError(t, err)
+	}
+
+	taken, err := repo.TakeResultsBefore(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+
+	require.Len(t, taken, 2)
+	assert.Equal(t, "result_0", taken[0].ID) // Oldest first
+	assert.Equal(t, "result_1", taken[1].ID)
+	assert.Equal(t, 1, repo.GetResultCount())
+
+	require.NoError(t, repo.RestoreResults(ctx, taken))
+	assert.Equal(t, 3, repo.GetResultCount())
+}
+
+func TestArchiver_Archive(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	oldResult := &game.Result{
+		ID:        "old",
+		Side:      game.Tails,
+		Won:       true,
+		Payout:    20 * game.Dollar,
+		Timestamp: now.Add(-40 * 24 * time.Hour),
+		Seed:      "seed",
+		Bet:       &game.Bet{ID: "bet_old", Amount: 10 * game.Dollar, Choice: game.Tails},
+	}
+	require.NoError(t, repo.SaveResult(ctx, oldResult))
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "recent", Side: game.Heads, Timestamp: now.Add(-time.Hour)}))
+
+	dir := t.TempDir()
+
+	// An expired archive from a previous pass and an unrelated file
+	expired := filepath.Join(dir, "results-20230101T000000Z.jsonl.gz")
+	require.NoError(t, os.WriteFile(expired, []byte{}, 0644))
+	unrelated := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(unrelated, []byte("keep"), 0644))
+
+	archiver := NewArchiver(repo, ArchiveConfig{
+		Directory:    dir,
+		ArchiveAfter: 30 * 24 * time.Hour,
+		Retention:    90 * 24 * time.Hour,
+	})
+	archiver.now = func() time.Time { return now }
+
+	report, err := archiver.Archive(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.ArchivedResults)
+	assert.Equal(t, 1, report.PurgedFiles)
+	assert.Equal(t, 1, repo.GetResultCount())
+	assert.NoFileExists(t, expired)
+	assert.FileExists(t, unrelated)
+
+	archived, err := ReadArchive(report.ArchiveFile)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, oldResult.ID, archived[0].ID)
+	assert.Equal(t, oldResult.Payout, archived[0].Payout)
+	assert.Equal(t, oldResult.Bet.Amount, archived[0].Bet.Amount)
+	assert.True(t, oldResult.Timestamp.Equal(archived[0].Timestamp))
+
+	// A second pass has nothing left to archive
+	report, err = archiver.Archive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.ArchivedResults)
+	assert.Empty(t, report.ArchiveFile)
+}
+
+func TestArchiver_EmptyDirectory(t *testing.T) {
+	archiver := NewArchiver(NewMemoryRepository(), ArchiveConfig{})
+
+	report, err := archiver.Archive(context.Background())
+
+	assert.Error(t, err)
+	assert.Nil(t, report)
+}
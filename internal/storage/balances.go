@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// Balance snapshot file naming
+const (
+	balanceFilePrefix = "balances-"
+	balanceFileSuffix = ".json.gz"
+)
+
+// ErrNoBalanceSnapshot is returned when no balance snapshot was taken at
+// or before the requested time
+var ErrNoBalanceSnapshot = errors.New("no balance snapshot at or before that time")
+
+// BalanceRecord is one player's balance and stats at the time of a snapshot
+type BalanceRecord struct {
+	PlayerID string     `json:"player_id"`
+	Balance  game.Money `json:"balance"`
+	Stats    game.Stats `json:"stats"`
+	Practice game.Stats `json:"practice"`
+}
+
+// BalanceSnapshot holds every player's balance and stats at one moment
+type BalanceSnapshot struct {
+	TakenAt time.Time       `json:"taken_at"`
+	Players []BalanceRecord `json:"players"`
+}
+
+// BalanceSnapshotConfig controls where balance snapshots are written and
+// how long they are kept
+type BalanceSnapshotConfig struct {
+	// Directory receives the compressed snapshot files
+	Directory string
+	// Retention is how long snapshot files are kept; zero keeps them forever
+	Retention time.Duration
+}
+
+// BalanceSnapshotReport describes a snapshot just taken
+type BalanceSnapshotReport struct {
+	TakenAt      time.Time `json:"taken_at"`
+	Players      int       `json:"players"`
+	SnapshotFile string    `json:"snapshot_file"`
+	PurgedFiles  int       `json:"purged_files"`
+}
+
+// Balances returns every player's balance and stats, ordered by player ID
+func (r *MemoryRepository) Balances(ctx context.Context) ([]BalanceRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	records := make([]BalanceRecord, 0, len(r.players))
+	for _, player := range r.players {
+		records = append(records, BalanceRecord{
+			PlayerID: player.ID,
+			Balance:  player.Balance,
+			Stats:    player.Stats,
+			Practice: player.Practice,
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].PlayerID < records[j].PlayerID
+	})
+	return records, nil
+}
+
+// RestoreBalances puts players' balances and stats back to the recorded
+// ones, under one lock. Everything else about the players is kept, and
+// players missing from storage are created with the recorded values.
+func (r *MemoryRepository) RestoreBalances(ctx context.Context, records []BalanceRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if record.PlayerID == "" {
+			return fmt.Errorf("cannot restore balance without player ID")
+		}
+	}
+	for _, record := range records {
+		player, exists := r.players[record.PlayerID]
+		if !exists {
+			player = &game.Player{ID: record.PlayerID}
+			r.players[record.PlayerID] = player
+		}
+		player.Balance = record.Balance
+		player.Stats = record.Stats
+		player.Practice = record.Practice
+		r.touchPlayer(record.PlayerID)
+	}
+	r.enforceLimits()
+	return nil
+}
+
+// BalanceSnapshotter periodically writes every player's balance and stats
+// to gzip-compressed JSON files, so they can be restored as they were at an
+// earlier time after a bug or crash corrupts them
+type BalanceSnapshotter struct {
+	repo   *MemoryRepository
+	config BalanceSnapshotConfig
+	now    func() time.Time
+}
+
+// NewBalanceSnapshotter creates a snapshotter for the given repository
+func NewBalanceSnapshotter(repo *MemoryRepository, config BalanceSnapshotConfig) *BalanceSnapshotter {
+	return &BalanceSnapshotter{
+		repo:   repo,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Snapshot writes the current balances to a new snapshot file, then deletes
+// snapshot files past their retention period
+func (s *BalanceSnapshotter) Snapshot(ctx context.Context) (*BalanceSnapshotReport, error) {
+	if s.config.Directory == "" {
+		return nil, fmt.Errorf("snapshot directory cannot be empty")
+	}
+	if err := os.MkdirAll(s.config.Directory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	records, err := s.repo.Balances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect balances: %w", err)
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	path := filepath.Join(s.config.Directory, balanceFilePrefix+now.Format(archiveTimeLayout)+balanceFileSuffix)
+	if err := writeBalanceSnapshot(path, &BalanceSnapshot{TakenAt: now, Players: records}); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	report := &BalanceSnapshotReport{
+		TakenAt:      now,
+		Players:      len(records),
+		SnapshotFile: path,
+	}
+
+	purged, err := s.purgeExpired(now)
+	if err != nil {
+		return report, fmt.Errorf("failed to purge expired snapshots: %w", err)
+	}
+	report.PurgedFiles = purged
+	return report, nil
+}
+
+// Load reads the latest snapshot taken at or before at, returning
+// ErrNoBalanceSnapshot if there is none
+func (s *BalanceSnapshotter) Load(at time.Time) (*BalanceSnapshot, string, error) {
+	entries, err := os.ReadDir(s.config.Directory)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, "", err
+	}
+
+	var latest string
+	var latestTime time.Time
+	for _, entry := range entries {
+		taken, ok := balanceFileTime(entry.Name())
+		if !ok || taken.After(at) || (latest != "" && !taken.After(latestTime)) {
+			continue
+		}
+		latest, latestTime = entry.Name(), taken
+	}
+	if latest == "" {
+		return nil, "", fmt.Errorf("%w: %s", ErrNoBalanceSnapshot, at.Format(time.RFC3339))
+	}
+
+	path := filepath.Join(s.config.Directory, latest)
+	snapshot, err := ReadBalanceSnapshot(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return snapshot, path, nil
+}
+
+// purgeExpired deletes snapshot files older than the retention period
+func (s *BalanceSnapshotter) purgeExpired(now time.Time) (int, error) {
+	if s.config.Retention <= 0 {
+		return 0, nil
+	}
+
+	entries, err := os.ReadDir(s.config.Directory)
+	if err != nil {
+		return 0, err
+	}
+
+	expiry := now.Add(-s.config.Retention)
+	purged := 0
+	for _, entry := range entries {
+		taken, ok := balanceFileTime(entry.Name())
+		if !ok || !taken.Before(expiry) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.config.Directory, entry.Name())); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// balanceFileTime extracts the time encoded in a snapshot file name
+func balanceFileTime(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, balanceFilePrefix) || !strings.HasSuffix(name, balanceFileSuffix) {
+		return time.Time{}, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, balanceFilePrefix), balanceFileSuffix)
+	taken, err := time.Parse(archiveTimeLayout, stamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return taken, true
+}
+
+// writeBalanceSnapshot writes a snapshot to a gzip-compressed JSON file,
+// through a temporary file so a crash never leaves half a snapshot behind
+func writeBalanceSnapshot(path string, snapshot *BalanceSnapshot) error {
+	temp := path + ".tmp"
+	file, err := os.OpenFile(temp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(file)
+	if err := json.NewEncoder(gz).Encode(snapshot); err != nil {
+		gz.Close()
+		file.Close()
+		os.Remove(temp)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		file.Close()
+		os.Remove(temp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(temp)
+		return err
+	}
+	return os.Rename(temp, path)
+}
+
+// ReadBalanceSnapshot reads a balance snapshot file
+func ReadBalanceSnapshot(path string) (*BalanceSnapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer gz.Close()
+
+	var snapshot BalanceSnapshot
+	if err := json.NewDecoder(gz).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
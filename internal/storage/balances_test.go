@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coinflip-game/internal/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceSnapshotter_SnapshotAndRestore(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{
+		ID:      "alice",
+		Balance: 100 * game.Dollar,
+		Stats:   game.Stats{GamesPlayed: 3, GamesWon: 2},
+		Role:    game.RoleModerator,
+	}))
+
+	dir := t.TempDir()
+	expired := filepath.Join(dir, "balances-20240101T000000Z.json.gz")
+	require.NoError(t, os.WriteFile(expired, []byte{}, 0644))
+
+	snapshotter := NewBalanceSnapshotter(repo, BalanceSnapshotConfig{Directory: dir, Retention: 7 * 24 * time.Hour})
+	snapshotter.now = func() time.Time { return now }
+	report, err := snapshotter.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Players)
+	assert.Equal(t, 1, report.PurgedFiles)
+	assert.NoFileExists(t, expired)
+
+	// A later snapshot records the corrupted balance
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "alice", Balance: -5 * game.Dollar, Role: game.RoleModerator}))
+	snapshotter.now = func() time.Time { return now.Add(time.Hour) }
+	_, err = snapshotter.Snapshot(ctx)
+	require.NoError(t, err)
+
+	snapshot, _, err := snapshotter.Load(now.Add(30 * time.Minute))
+	require.NoError(t, err)
+	assert.True(t, now.Equal(snapshot.TakenAt))
+	require.NoError(t, repo.RestoreBalances(ctx, snapshot.Players))
+
+	alice, err := repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 100*game.Dollar, alice.Balance)
+	assert.Equal(t, 3, alice.Stats.GamesPlayed)
+	assert.Equal(t, game.RoleModerator, alice.Role, "everything but balances and stats is kept")
+
+	snapshot, _, err = snapshotter.Load(now.Add(2 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, -5*game.Dollar, snapshot.Players[0].Balance)
+
+	_, _, err = snapshotter.Load(now.Add(-time.Minute))
+	assert.ErrorIs(t, err, ErrNoBalanceSnapshot)
+}
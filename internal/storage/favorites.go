@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"coinflip-game/internal/game"
+)
+
+// FavoriteBook keeps each player's favorite bets between runs. Like the
+// wallet cache, guests get a new ID every run, so their favorites are kept
+// in memory only; an empty path keeps every player's in memory.
+type FavoriteBook struct {
+	mu        sync.Mutex
+	path      string
+	favorites map[string][]game.FavoriteBet
+}
+
+// OpenFavoriteBook loads the favorite bets at path. A missing file opens an
+// empty book.
+func OpenFavoriteBook(path string) (*FavoriteBook, error) {
+	book := &FavoriteBook{path: path, favorites: make(map[string][]game.FavoriteBet)}
+	if path == "" {
+		return book, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return book, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read favorite bets: %w", err)
+	}
+	if err := json.Unmarshal(data, &book.favorites); err != nil {
+		return nil, fmt.Errorf("failed to decode favorite bets: %w", err)
+	}
+	return book, nil
+}
+
+// List returns the player's favorite bets sorted by name
+func (b *FavoriteBook) List(playerID string) []game.FavoriteBet {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return slices.Clone(b.favorites[playerID])
+}
+
+// Get returns the player's favorite with the name, or ErrFavoriteNotFound
+func (b *FavoriteBook) Get(playerID, name string) (game.FavoriteBet, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, favorite := range b.favorites[playerID] {
+		if favorite.Name == name {
+			return favorite, nil
+		}
+	}
+	return game.FavoriteBet{}, fmt.Errorf("%w: %s", game.ErrFavoriteNotFound, name)
+}
+
+// Save stores a favorite, replacing the player's favorite with the same
+// name, and saves the book
+func (b *FavoriteBook) Save(playerID string, favorite game.FavoriteBet) error {
+	if err := favorite.Validate(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	favorites := slices.Clone(b.favorites[playerID])
+	i := slices.IndexFunc(favorites, func(f game.FavoriteBet) bool { return f.Name == favorite.Name })
+	switch {
+	case i >= 0:
+		favorites[i] = favorite
+	case len(favorites) >= game.MaxFavorites:
+		return game.ErrTooManyFavorites
+	default:
+		favorites = append(favorites, favorite)
+	}
+	slices.SortFunc(favorites, func(a, b game.FavoriteBet) int { return strings.Compare(a.Name, b.Name) })
+
+	b.favorites[playerID] = favorites
+	return b.save(playerID)
+}
+
+// Remove deletes the player's favorite with the name and saves the book
+func (b *FavoriteBook) Remove(playerID, name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	favorites := b.favorites[playerID]
+	i := slices.IndexFunc(favorites, func(f game.FavoriteBet) bool { return f.Name == name })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", game.ErrFavoriteNotFound, name)
+	}
+
+	favorites = slices.Delete(slices.Clone(favorites), i, i+1)
+	if len(favorites) == 0 {
+		delete(b.favorites, playerID)
+	} else {
+		b.favorites[playerID] = favorites
+	}
+	return b.save(playerID)
+}
+
+// save writes the favorites of registered players to the book's file,
+// replacing it atomically. It is skipped when only a guest's favorites
+// changed. Callers must hold b.mu.
+func (b *FavoriteBook) save(changed string) error {
+	if b.path == "" || game.IsGuest(changed) {
+		return nil
+	}
+
+	kept := make(map[string][]game.FavoriteBet, len(b.favorites))
+	for id, favorites := range b.favorites {
+		if !game.IsGuest(id) {
+			kept[id] = favorites
+		}
+	}
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode favorite bets: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return fmt.Errorf("failed to create favorite bets directory: %w", err)
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write favorite bets: %w", err)
+	}
+	if err := os.Rename(tmp, b.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace favorite bets: %w", err)
+	}
+	return nil
+}
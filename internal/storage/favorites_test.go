@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+func TestFavoriteBook_PersistsPerPlayer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "favorites.json")
+
+	book, err := OpenFavoriteBook(path)
+	require.NoError(t, err)
+	assert.Empty(t, book.List("alice"))
+
+	lucky := game.FavoriteBet{Name: "lucky25", Amount: game.BetAmount{Fixed: 25 * game.Dollar}, Choice: game.Tails}
+	half := game.FavoriteBet{Name: "allin", Amount: game.BetAmount{Percent: 50}, Choice: game.Heads, Insured: true}
+	require.NoError(t, book.Save("alice", lucky))
+	require.NoError(t, book.Save("alice", half))
+	require.NoError(t, book.Save("bob", lucky))
+	guest := game.NewGuestID()
+	require.NoError(t, book.Save(guest, lucky))
+
+	reopened, err := OpenFavoriteBook(path)
+	require.NoError(t, err)
+	assert.Equal(t, []game.FavoriteBet{half, lucky}, reopened.List("alice"), "sorted by name, shares kept relative")
+	assert.Equal(t, []game.FavoriteBet{lucky}, reopened.List("bob"))
+	assert.Empty(t, reopened.List(guest), "guest favorites are not saved")
+
+	// Saving under a taken name replaces the favorite
+	lucky.Amount = game.BetAmount{Fixed: 30 * game.Dollar}
+	require.NoError(t, reopened.Save("alice", lucky))
+	favorite, err := reopened.Get("alice", "lucky25")
+	require.NoError(t, err)
+	assert.Equal(t, "$30.00 on tails", favorite.Describe())
+
+	require.NoError(t, reopened.Remove("alice", "lucky25"))
+	_, err = reopened.Get("alice", "lucky25")
+	assert.ErrorIs(t, err, game.ErrFavoriteNotFound)
+	assert.ErrorIs(t, reopened.Remove("alice", "lucky25"), game.ErrFavoriteNotFound)
+
+	reopened, err = OpenFavoriteBook(path)
+	require.NoError(t, err)
+	assert.Equal(t, []game.FavoriteBet{half}, reopened.List("alice"))
+}
+
+func TestFavoriteBook_RejectsInvalid(t *testing.T) {
+	book, err := OpenFavoriteBook("")
+	require.NoError(t, err)
+
+	invalid := []game.FavoriteBet{
+		{Name: "", Amount: game.BetAmount{Fixed: game.Dollar}, Choice: game.Heads},
+		{Name: "my bet", Amount: game.BetAmount{Fixed: game.Dollar}, Choice: game.Heads},
+		{Name: "zero", Choice: game.Heads},
+		{Name: "edge", Amount: game.BetAmount{Fixed: game.Dollar}, Choice: "edge"},
+	}
+	for _, favorite := range invalid {
+		assert.ErrorIs(t, book.Save("alice", favorite), game.ErrInvalidFavorite, favorite.Name)
+	}
+
+	for i := range game.MaxFavorites {
+		name := string(rune('a'+i%26)) + string(rune('a'+i/26))
+		require.NoError(t, book.Save("alice", game.FavoriteBet{Name: name, Amount: game.BetAmount{Max: true}, Choice: game.Heads}))
+	}
+	extra := game.FavoriteBet{Name: "extra", Amount: game.BetAmount{Max: true}, Choice: game.Tails}
+	assert.ErrorIs(t, book.Save("alice", extra), game.ErrTooManyFavorites)
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"coinflip-game/internal/game"
+)
+
+// HistoryLog keeps single-player results between runs in a JSON-lines
+// file, appending each as it is settled. The results are hash-chained by
+// the engine, so game.VerifyChain can tell whether the file was edited.
+// An empty path keeps the results in memory only.
+type HistoryLog struct {
+	mu      sync.Mutex
+	path    string
+	results []*game.Result
+	heads   map[string]string // Latest result hash of each player
+}
+
+// OpenHistoryLog loads the history at path. A missing file opens an empty
+// history.
+func OpenHistoryLog(path string) (*HistoryLog, error) {
+	results, err := ReadHistory(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	log := &HistoryLog{path: path, heads: make(map[string]string)}
+	for _, result := range results {
+		log.results = append(log.results, result)
+		log.heads[result.PlayerID] = result.Hash
+	}
+	return log, nil
+}
+
+// ReadHistory reads every result in the history file at path, oldest
+// first
+func ReadHistory(path string) ([]*game.Result, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var results []*game.Result
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var result game.Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return nil, fmt.Errorf("failed to decode history line %d: %w", line, err)
+		}
+		results = append(results, &result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return results, nil
+}
+
+// Head returns the hash of the player's latest result, empty if they have
+// none
+func (l *HistoryLog) Head(playerID string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.heads[playerID]
+}
+
+// Results returns a copy of every result in the history, oldest first
+func (l *HistoryLog) Results() []*game.Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	results := make([]*game.Result, len(l.results))
+	for i, result := range l.results {
+		results[i] = copyResult(result)
+	}
+	return results
+}
+
+// Append adds a result to the end of the history and the file
+func (l *HistoryLog) Append(result *game.Result) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.path != "" {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+			return fmt.Errorf("failed to create history directory: %w", err)
+		}
+		file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open history: %w", err)
+		}
+		if _, err := file.Write(append(data, '\n')); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write history: %w", err)
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to write history: %w", err)
+		}
+	}
+
+	l.results = append(l.results, copyResult(result))
+	l.heads[result.PlayerID] = result.Hash
+	return nil
+}
+
+// WriteHistory replaces the history file at path with results, in the
+// order given, through a temporary file so a crash never leaves half a
+// history behind
+func WriteHistory(path string, results []*game.Result) error {
+	var data []byte
+	for _, result := range results {
+		line, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestHistoryLog_PersistsChain(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "data", "history.jsonl")
+
+	history, err := OpenHistoryLog(path)
+	require.NoError(t, err)
+	assert.Empty(t, history.Head("alice"))
+
+	play := func(rounds int) {
+		engine := game.NewEngine(game.Config{StartingBalance: 100 * game.Dollar, MinBet: game.Dollar, MaxBet: 50 * game.Dollar, PayoutRatio: 2},
+			NewMemoryRepository(), game.NewDefaultRandomGenerator(), zaptest.NewLogger(t))
+		require.NoError(t, engine.ResumeChain(ctx, "alice", history.Head("alice")))
+		session := engine.NewSession("alice")
+		for i := 0; i < rounds; i++ {
+			_, err := session.PlaceBet(ctx, game.Dollar, game.Heads)
+			require.NoError(t, err)
+			result, err := session.FlipCoin(ctx)
+			require.NoError(t, err)
+			require.NoError(t, history.Append(result))
+		}
+	}
+
+	// Two runs, each with its own engine, make one chain
+	play(2)
+	history, err = OpenHistoryLog(path)
+	require.NoError(t, err)
+	play(2)
+
+	results, err := ReadHistory(path)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.NoError(t, game.VerifyChain(results))
+	assert.Equal(t, results[3].Hash, history.Head("alice"))
+	assert.Len(t, history.Results(), 4)
+
+	// Editing a line in the file breaks the chain
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(string(data), "\n")
+	lines[1] = strings.Replace(lines[1], `"seed":"`, `"seed":"0`, 1)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644))
+
+	results, err = ReadHistory(path)
+	require.NoError(t, err)
+	var chainErr *game.ChainError
+	require.ErrorAs(t, game.VerifyChain(results), &chainErr)
+	assert.Equal(t, 1, chainErr.Index)
+}
+
+func TestHistoryLog_MissingFile(t *testing.T) {
+	history, err := OpenHistoryLog(filepath.Join(t.TempDir(), "missing.jsonl"))
+	require.NoError(t, err)
+	assert.Empty(t, history.Results())
+
+	memory, err := OpenHistoryLog("")
+	require.NoError(t, err)
+	require.NoError(t, memory.Append(&game.Result{ID: "r1", PlayerID: "alice", Hash: "abc"}))
+	assert.Equal(t, "abc", memory.Head("alice"))
+}
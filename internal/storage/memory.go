@@ -3,20 +3,40 @@
 package storage
 
 import (
+	"container/list"
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
+	"time"
 
 	"coinflip-game/internal/game"
+	"coinflip-game/internal/tracing"
 )
 
+// tracer traces repository reads and writes
+var tracer = tracing.Tracer("coinflip-game/internal/storage")
+
 // MemoryRepository implements the Repository interface using in-memory storage.
 // This is useful for testing and simple deployments where persistence is not required.
+// Every operation fails with the context's error, and changes nothing, once
+// the context is cancelled or past its deadline, including while it waited
+// for another operation to finish. NewLimitedMemoryRepository bounds how
+// much it keeps.
 type MemoryRepository struct {
 	mu      sync.RWMutex
 	results map[string]*game.Result
 	players map[string]*game.Player
+
+	// Eviction bookkeeping, kept only when limits are set: results oldest
+	// first, and players most recently used first under lruMu
+	limits      MemoryLimits
+	byAge       resultHeap
+	lruMu       sync.Mutex
+	lru         *list.List
+	lruElements map[string]*list.Element
+	evictions   MemoryStats
 }
 
 // NewMemoryRepository creates a new in-memory repository
@@ -29,6 +49,9 @@ func NewMemoryRepository() *MemoryRepository {
 
 // SaveResult saves a game result to memory
 func (r *MemoryRepository) SaveResult(ctx context.Context, result *game.Result) error {
+	_, span := tracer.Start(ctx, "storage.save_result")
+	defer span.End()
+
 	if result == nil {
 		return fmt.Errorf("result cannot be nil")
 	}
@@ -39,63 +62,37 @@ func (r *MemoryRepository) SaveResult(ctx context.Context, result *game.Result)
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
-
-	// Create a deep copy to avoid external mutations
-	resultCopy := &game.Result{
-		ID:        result.ID,
-		Side:      result.Side,
-		Won:       result.Won,
-		Payout:    result.Payout,
-		Timestamp: result.Timestamp,
-		Seed:      result.Seed,
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	// Deep copy the bet if it exists
-	if result.Bet != nil {
-		resultCopy.Bet = &game.Bet{
-			ID:        result.Bet.ID,
-			Amount:    result.Bet.Amount,
-			Choice:    result.Bet.Choice,
-			Timestamp: result.Bet.Timestamp,
-		}
-	}
-
-	r.results[result.ID] = resultCopy
+	// Create a deep copy to avoid external mutations
+	r.results[result.ID] = copyResult(result)
+	r.trackResult(result.ID, result.Timestamp)
+	r.enforceLimits()
 	return nil
 }
 
 // GetResults retrieves the most recent game results up to the specified limit
 func (r *MemoryRepository) GetResults(ctx context.Context, limit int) ([]*game.Result, error) {
+	_, span := tracer.Start(ctx, "storage.get_results")
+	defer span.End()
+
 	if limit <= 0 {
 		return []*game.Result{}, nil
 	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Convert map to slice for sorting
 	results := make([]*game.Result, 0, len(r.results))
 	for _, result := range r.results {
 		// Create copies to avoid external mutations
-		resultCopy := &game.Result{
-			ID:        result.ID,
-			Side:      result.Side,
-			Won:       result.Won,
-			Payout:    result.Payout,
-			Timestamp: result.Timestamp,
-			Seed:      result.Seed,
-		}
-
-		if result.Bet != nil {
-			resultCopy.Bet = &game.Bet{
-				ID:        result.Bet.ID,
-				Amount:    result.Bet.Amount,
-				Choice:    result.Bet.Choice,
-				Timestamp: result.Bet.Timestamp,
-			}
-		}
-
-		results = append(results, resultCopy)
+		results = append(results, copyResult(result))
 	}
 
 	// Sort by timestamp descending (most recent first)
@@ -111,14 +108,44 @@ func (r *MemoryRepository) GetResults(ctx context.Context, limit int) ([]*game.R
 	return results[:limit], nil
 }
 
+// QueryResults returns one page of the results matching the query. Pages
+// are copies, so the caller may keep them.
+func (r *MemoryRepository) QueryResults(ctx context.Context, query game.ResultQuery) (*game.ResultPage, error) {
+	_, span := tracer.Start(ctx, "storage.query_results")
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]*game.Result, 0, len(r.results))
+	for _, result := range r.results {
+		results = append(results, result)
+	}
+
+	page := query.Apply(results)
+	for i, result := range page.Results {
+		page.Results[i] = copyResult(result)
+	}
+	return page, nil
+}
+
 // GetStats calculates and returns statistics for a player based on their game history
 func (r *MemoryRepository) GetStats(ctx context.Context, playerID string) (*game.Stats, error) {
+	_, span := tracer.Start(ctx, "storage.get_stats")
+	defer span.End()
+
 	if playerID == "" {
 		return nil, fmt.Errorf("player ID cannot be empty")
 	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Find the player to get current stats
 	player, exists := r.players[playerID]
@@ -126,6 +153,7 @@ func (r *MemoryRepository) GetStats(ctx context.Context, playerID string) (*game
 		// Return empty stats for new players
 		return &game.Stats{}, nil
 	}
+	r.touchPlayer(playerID)
 
 	// Return a copy of the stats to avoid external mutations
 	statsCopy := game.Stats{
@@ -135,6 +163,11 @@ func (r *MemoryRepository) GetStats(ctx context.Context, playerID string) (*game
 		TotalWinnings: player.Stats.TotalWinnings,
 		NetProfit:     player.Stats.NetProfit,
 		WinRate:       player.Stats.WinRate,
+		BiggestWin:    player.Stats.BiggestWin,
+		CurrentStreak: player.Stats.CurrentStreak,
+		LongestStreak: player.Stats.LongestStreak,
+		Outcomes:      player.Stats.Outcomes,
+		Choices:       player.Stats.Choices,
 	}
 
 	return &statsCopy, nil
@@ -142,6 +175,9 @@ func (r *MemoryRepository) GetStats(ctx context.Context, playerID string) (*game
 
 // SavePlayer saves or updates a player in memory
 func (r *MemoryRepository) SavePlayer(ctx context.Context, player *game.Player) error {
+	_, span := tracer.Start(ctx, "storage.save_player")
+	defer span.End()
+
 	if player == nil {
 		return fmt.Errorf("player cannot be nil")
 	}
@@ -152,54 +188,142 @@ func (r *MemoryRepository) SavePlayer(ctx context.Context, player *game.Player)
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Create a deep copy to avoid external mutations
-	playerCopy := &game.Player{
-		ID:      player.ID,
-		Balance: player.Balance,
-		Stats: game.Stats{
-			GamesPlayed:   player.Stats.GamesPlayed,
-			GamesWon:      player.Stats.GamesWon,
-			TotalWagered:  player.Stats.TotalWagered,
-			TotalWinnings: player.Stats.TotalWinnings,
-			NetProfit:     player.Stats.NetProfit,
-			WinRate:       player.Stats.WinRate,
-		},
-	}
-
-	r.players[player.ID] = playerCopy
+	r.players[player.ID] = copyPlayer(player)
+	r.touchPlayer(player.ID)
+	r.enforceLimits()
 	return nil
 }
 
 // GetPlayer retrieves a player by ID from memory
 func (r *MemoryRepository) GetPlayer(ctx context.Context, playerID string) (*game.Player, error) {
+	_, span := tracer.Start(ctx, "storage.get_player")
+	defer span.End()
+
 	if playerID == "" {
 		return nil, fmt.Errorf("player ID cannot be empty")
 	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	player, exists := r.players[playerID]
 	if !exists {
 		return nil, fmt.Errorf("player not found: %s", playerID)
 	}
+	r.touchPlayer(playerID)
 
 	// Return a copy to avoid external mutations
-	playerCopy := &game.Player{
-		ID:      player.ID,
-		Balance: player.Balance,
-		Stats: game.Stats{
-			GamesPlayed:   player.Stats.GamesPlayed,
-			GamesWon:      player.Stats.GamesWon,
-			TotalWagered:  player.Stats.TotalWagered,
-			TotalWinnings: player.Stats.TotalWinnings,
-			NetProfit:     player.Stats.NetProfit,
-			WinRate:       player.Stats.WinRate,
-		},
+	return copyPlayer(player), nil
+}
+
+// MigratePlayer moves a player and their results to a new ID under one
+// lock, so readers see the data under either ID but never both or neither
+func (r *MemoryRepository) MigratePlayer(ctx context.Context, fromID, toID string) (*game.Player, error) {
+	if fromID == "" || toID == "" {
+		return nil, fmt.Errorf("player ID cannot be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	return playerCopy, nil
+	player, exists := r.players[fromID]
+	if !exists {
+		return nil, fmt.Errorf("player not found: %s", fromID)
+	}
+	if _, taken := r.players[toID]; taken {
+		return nil, fmt.Errorf("%w: %s", game.ErrAccountExists, toID)
+	}
+
+	player.ID = toID
+	r.players[toID] = player
+	delete(r.players, fromID)
+	r.forgetPlayerUse(fromID)
+	r.touchPlayer(toID)
+
+	for _, result := range r.results {
+		if result.PlayerID == fromID {
+			result.PlayerID = toID
+		}
+	}
+
+	return copyPlayer(player), nil
+}
+
+// FindByFriendCode looks a player up by their friend code
+func (r *MemoryRepository) FindByFriendCode(ctx context.Context, code string) (*game.Player, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	var playerID string
+	for id := range r.players {
+		if game.FriendCode(id) == code {
+			playerID = id
+			break
+		}
+	}
+	r.mu.RUnlock()
+
+	if playerID == "" {
+		return nil, fmt.Errorf("player not found: friend code %s", code)
+	}
+	return r.GetPlayer(ctx, playerID)
+}
+
+// TakeResultsBefore removes and returns all results with a timestamp before
+// the cutoff, oldest first. It is used to move results out of hot storage.
+func (r *MemoryRepository) TakeResultsBefore(ctx context.Context, cutoff time.Time) ([]*game.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	taken := make([]*game.Result, 0)
+	for id, result := range r.results {
+		if result.Timestamp.Before(cutoff) {
+			taken = append(taken, result)
+			delete(r.results, id)
+		}
+	}
+
+	sort.Slice(taken, func(i, j int) bool {
+		return taken[i].Timestamp.Before(taken[j].Timestamp)
+	})
+
+	return taken, nil
+}
+
+// RestoreResults puts previously taken results back into the repository
+func (r *MemoryRepository) RestoreResults(ctx context.Context, results []*game.Result) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if result == nil || result.ID == "" {
+			return fmt.Errorf("cannot restore result without ID")
+		}
+		r.results[result.ID] = copyResult(result)
+		r.trackResult(result.ID, result.Timestamp)
+	}
+	r.enforceLimits()
+
+	return nil
 }
 
 // Clear removes all data from the repository (useful for testing)
@@ -209,6 +333,14 @@ func (r *MemoryRepository) Clear() {
 
 	r.results = make(map[string]*game.Result)
 	r.players = make(map[string]*game.Player)
+	r.byAge = nil
+	r.evictions = MemoryStats{}
+	if r.lru != nil {
+		r.lruMu.Lock()
+		r.lru.Init()
+		clear(r.lruElements)
+		r.lruMu.Unlock()
+	}
 }
 
 // GetResultCount returns the total number of results stored
@@ -226,3 +358,46 @@ func (r *MemoryRepository) GetPlayerCount() int {
 
 	return len(r.players)
 }
+
+// copyPlayer creates a deep copy of a player
+func copyPlayer(player *game.Player) *game.Player {
+	playerCopy := *player
+	playerCopy.Inventory = player.Inventory.Clone()
+	playerCopy.Friends = slices.Clone(player.Friends)
+	playerCopy.Badges = slices.Clone(player.Badges)
+	playerCopy.Notify = player.Notify.Clone()
+	playerCopy.Duel = player.Duel.Clone()
+	playerCopy.Offline = player.Offline.Clone()
+	if player.OpenBets != nil {
+		playerCopy.OpenBets = make([]*game.Bet, len(player.OpenBets))
+		for i, bet := range player.OpenBets {
+			betCopy := *bet
+			playerCopy.OpenBets[i] = &betCopy
+		}
+	}
+	return &playerCopy
+}
+
+// copyResult creates a deep copy of a result
+func copyResult(result *game.Result) *game.Result {
+	resultCopy := &game.Result{
+		ID:         result.ID,
+		PlayerID:   result.PlayerID,
+		Side:       result.Side,
+		Won:        result.Won,
+		Payout:     result.Payout,
+		Timestamp:  result.Timestamp,
+		Seed:       result.Seed,
+		Multiplier: result.Multiplier,
+		Insurance:  result.Insurance,
+		PrevHash:   result.PrevHash,
+		Hash:       result.Hash,
+	}
+
+	if result.Bet != nil {
+		betCopy := *result.Bet
+		resultCopy.Bet = &betCopy
+	}
+
+	return resultCopy
+}
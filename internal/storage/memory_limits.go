@@ -0,0 +1,263 @@
+package storage
+
+import (
+	"container/heap"
+	"container/list"
+	"time"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+)
+
+// MemoryLimits bounds what a MemoryRepository keeps, for long-lived
+// servers. Zero fields are unlimited.
+type MemoryLimits struct {
+	// MaxResults caps the stored results; past it the oldest are evicted
+	MaxResults int
+	// MaxPlayers caps the stored players Keep does not spare; past it the
+	// least recently used player is evicted along with their results. An
+	// evicted player is forgotten entirely and starts over if they come
+	// back, so servers set Keep to spare players with anything to lose.
+	MaxPlayers int
+	// TTL evicts results older than it, and players saved or read no more
+	// recently than it along with their results. Expired entries are
+	// removed on the next write or Prune.
+	TTL time.Duration
+	// Keep, if set, exempts players it reports true for from MaxPlayers
+	// and TTL: they are kept however long they go unused, though their
+	// results still age out under MaxResults and TTL like any others
+	Keep func(player *game.Player) bool
+	// Clock tells the time for TTL; nil uses the system clock
+	Clock clock.Clock
+}
+
+// MemoryStats counts what a MemoryRepository holds and what its limits
+// have removed. Results removed with their player count with them.
+type MemoryStats struct {
+	Results int `json:"results"`
+	Players int `json:"players"`
+	// Evicted counts what was removed to stay within the caps, Expired
+	// what outlived the TTL
+	EvictedResults int64 `json:"evicted_results"`
+	EvictedPlayers int64 `json:"evicted_players"`
+	ExpiredResults int64 `json:"expired_results"`
+	ExpiredPlayers int64 `json:"expired_players"`
+}
+
+// NewLimitedMemoryRepository creates an in-memory repository that keeps
+// within limits
+func NewLimitedMemoryRepository(limits MemoryLimits) *MemoryRepository {
+	r := NewMemoryRepository()
+	if limits.Clock == nil {
+		limits.Clock = clock.New()
+	}
+	r.limits = limits
+	r.lru = list.New()
+	r.lruElements = make(map[string]*list.Element)
+	return r
+}
+
+// Stats returns the repository's counts and evictions so far
+func (r *MemoryRepository) Stats() MemoryStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := r.evictions
+	stats.Results = len(r.results)
+	stats.Players = len(r.players)
+	return stats
+}
+
+// Prune removes the results and players that have outlived the TTL
+// without waiting for the next write
+func (r *MemoryRepository) Prune() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.enforceLimits()
+}
+
+// tracksResults reports whether results are kept in age order for the
+// limits
+func (r *MemoryRepository) tracksResults() bool {
+	return r.limits.MaxResults > 0 || r.limits.TTL > 0
+}
+
+// tracksPlayers reports whether players are kept in order of use for the
+// limits
+func (r *MemoryRepository) tracksPlayers() bool {
+	return r.limits.MaxPlayers > 0 || r.limits.TTL > 0
+}
+
+// trackResult notes a stored result's age. Callers must hold r.mu for
+// writing.
+func (r *MemoryRepository) trackResult(id string, timestamp time.Time) {
+	if !r.tracksResults() {
+		return
+	}
+	heap.Push(&r.byAge, resultAge{id: id, timestamp: timestamp})
+
+	// Replaced and removed results leave stale entries behind; rebuild
+	// once they outnumber the live ones
+	if len(r.byAge) > 2*len(r.results)+64 {
+		r.byAge = r.byAge[:0]
+		for id, result := range r.results {
+			r.byAge = append(r.byAge, resultAge{id: id, timestamp: result.Timestamp})
+		}
+		heap.Init(&r.byAge)
+	}
+}
+
+// touchPlayer marks a player as just used. Players Keep spares are left
+// out of the order of use, so only the players the limits may remove are
+// in it. Readers holding r.mu for reading may call it, so the order of use
+// has its own lock.
+func (r *MemoryRepository) touchPlayer(playerID string) {
+	if !r.tracksPlayers() {
+		return
+	}
+	if r.keeps(playerID) {
+		r.forgetPlayerUse(playerID)
+		return
+	}
+	now := r.limits.Clock.Now()
+
+	r.lruMu.Lock()
+	defer r.lruMu.Unlock()
+	if element, exists := r.lruElements[playerID]; exists {
+		element.Value.(*playerUse).used = now
+		r.lru.MoveToFront(element)
+		return
+	}
+	r.lruElements[playerID] = r.lru.PushFront(&playerUse{id: playerID, used: now})
+}
+
+// forgetPlayerUse drops a player from the order of use
+func (r *MemoryRepository) forgetPlayerUse(playerID string) {
+	if !r.tracksPlayers() {
+		return
+	}
+
+	r.lruMu.Lock()
+	defer r.lruMu.Unlock()
+	if element, exists := r.lruElements[playerID]; exists {
+		r.lru.Remove(element)
+		delete(r.lruElements, playerID)
+	}
+}
+
+// enforceLimits removes what has outlived the TTL, then the least
+// recently used players and the oldest results past the caps. Callers
+// must hold r.mu for writing.
+func (r *MemoryRepository) enforceLimits() {
+	if !r.tracksResults() && !r.tracksPlayers() {
+		return
+	}
+
+	var cutoff time.Time
+	if r.limits.TTL > 0 {
+		cutoff = r.limits.Clock.Now().Add(-r.limits.TTL)
+	}
+
+	if r.tracksPlayers() {
+		expired := make(map[string]bool)
+		evicted := make(map[string]bool)
+
+		r.lruMu.Lock()
+		for element := r.lru.Back(); element != nil; element = r.lru.Back() {
+			use := element.Value.(*playerUse)
+			switch {
+			case r.limits.TTL > 0 && use.used.Before(cutoff):
+				expired[use.id] = true
+			case r.limits.MaxPlayers > 0 && r.lru.Len() > r.limits.MaxPlayers:
+				evicted[use.id] = true
+			default:
+				element = nil
+			}
+			if element == nil {
+				break
+			}
+			r.lru.Remove(element)
+			delete(r.lruElements, use.id)
+		}
+		r.lruMu.Unlock()
+
+		r.evictions.ExpiredPlayers += int64(len(expired))
+		r.evictions.EvictedPlayers += int64(len(evicted))
+		r.removePlayers(expired, &r.evictions.ExpiredResults)
+		r.removePlayers(evicted, &r.evictions.EvictedResults)
+	}
+
+	if r.tracksResults() {
+		for len(r.byAge) > 0 {
+			oldest := r.byAge[0]
+			result, exists := r.results[oldest.id]
+			if !exists || !result.Timestamp.Equal(oldest.timestamp) {
+				heap.Pop(&r.byAge)
+				continue
+			}
+
+			switch {
+			case r.limits.TTL > 0 && oldest.timestamp.Before(cutoff):
+				r.evictions.ExpiredResults++
+			case r.limits.MaxResults > 0 && len(r.results) > r.limits.MaxResults:
+				r.evictions.EvictedResults++
+			default:
+				return
+			}
+			heap.Pop(&r.byAge)
+			delete(r.results, oldest.id)
+		}
+	}
+}
+
+// keeps reports whether Keep spares a stored player from the limits.
+// Callers must hold r.mu.
+func (r *MemoryRepository) keeps(playerID string) bool {
+	player, exists := r.players[playerID]
+	return exists && r.limits.Keep != nil && r.limits.Keep(player)
+}
+
+// removePlayers deletes players and their results, counting the results
+// removed. Callers must hold r.mu for writing.
+func (r *MemoryRepository) removePlayers(playerIDs map[string]bool, removedResults *int64) {
+	if len(playerIDs) == 0 {
+		return
+	}
+	for playerID := range playerIDs {
+		delete(r.players, playerID)
+	}
+	for id, result := range r.results {
+		if playerIDs[result.PlayerID] {
+			delete(r.results, id)
+			*removedResults++
+		}
+	}
+}
+
+// playerUse is when a player was last saved or read
+type playerUse struct {
+	id   string
+	used time.Time
+}
+
+// resultAge is a result's place in the age order. Entries for results
+// since replaced or removed are skipped when they come up.
+type resultAge struct {
+	id        string
+	timestamp time.Time
+}
+
+// resultHeap orders results oldest first
+type resultHeap []resultAge
+
+func (h resultHeap) Len() int           { return len(h) }
+func (h resultHeap) Less(i, j int) bool { return h[i].timestamp.Before(h[j].timestamp) }
+func (h resultHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *resultHeap) Push(x any)        { *h = append(*h, x.(resultAge)) }
+func (h *resultHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
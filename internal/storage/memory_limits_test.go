@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/clock"
+	"coinflip-game/internal/game"
+)
+
+func TestMemoryRepository_MaxResults(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewLimitedMemoryRepository(MemoryLimits{MaxResults: 3})
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, repo.SaveResult(ctx, &game.Result{
+			ID: fmt.Sprintf("r%d", i), PlayerID: "alice", Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	// The oldest results make way for the newest
+	results, err := repo.GetResults(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, "r4", results[0].ID)
+	assert.Equal(t, "r2", results[2].ID)
+
+	// Replacing a result moves it to its new age
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "r2", PlayerID: "alice", Timestamp: start.Add(time.Hour)}))
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "r5", PlayerID: "alice", Timestamp: start.Add(5 * time.Minute)}))
+	results, err = repo.GetResults(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"r2", "r5", "r4"}, []string{results[0].ID, results[1].ID, results[2].ID})
+
+	assert.Equal(t, MemoryStats{Results: 3, EvictedResults: 3}, repo.Stats())
+}
+
+func TestMemoryRepository_MaxPlayers(t *testing.T) {
+	ctx := context.Background()
+	repo := NewLimitedMemoryRepository(MemoryLimits{MaxPlayers: 2})
+
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "alice"}))
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "bob"}))
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "r1", PlayerID: "bob"}))
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "r2", PlayerID: "alice"}))
+
+	// Reading Alice makes Bob the least recently used
+	_, err := repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "carol"}))
+
+	_, err = repo.GetPlayer(ctx, "bob")
+	assert.Error(t, err)
+	_, err = repo.GetPlayer(ctx, "alice")
+	assert.NoError(t, err)
+
+	// Bob's results went with him
+	results, err := repo.GetResults(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "r2", results[0].ID)
+
+	// A migrated player keeps their place
+	_, err = repo.MigratePlayer(ctx, "carol", "dave")
+	require.NoError(t, err)
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "erin"}))
+	_, err = repo.GetPlayer(ctx, "dave")
+	assert.NoError(t, err)
+	_, err = repo.GetPlayer(ctx, "alice")
+	assert.Error(t, err)
+
+	assert.Equal(t, MemoryStats{Results: 0, Players: 2, EvictedPlayers: 2, EvictedResults: 2}, repo.Stats())
+}
+
+func TestMemoryRepository_TTL(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	repo := NewLimitedMemoryRepository(MemoryLimits{TTL: time.Hour, Clock: fake})
+
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "alice"}))
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "bob"}))
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "old", PlayerID: "carol", Timestamp: fake.Now()}))
+
+	fake.Advance(45 * time.Minute)
+	_, err := repo.GetStats(ctx, "alice")
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "new", PlayerID: "carol", Timestamp: fake.Now()}))
+
+	// Nothing expires until something prunes it
+	fake.Advance(30 * time.Minute)
+	assert.Equal(t, 2, repo.GetPlayerCount())
+	repo.Prune()
+
+	_, err = repo.GetPlayer(ctx, "alice")
+	assert.NoError(t, err)
+	_, err = repo.GetPlayer(ctx, "bob")
+	assert.Error(t, err)
+	results, err := repo.GetResults(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "new", results[0].ID)
+
+	assert.Equal(t, MemoryStats{Results: 1, Players: 1, ExpiredPlayers: 1, ExpiredResults: 1}, repo.Stats())
+
+	repo.Clear()
+	assert.Equal(t, MemoryStats{}, repo.Stats())
+}
+
+func TestMemoryRepository_KeepSparesPlayers(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	repo := NewLimitedMemoryRepository(MemoryLimits{
+		MaxPlayers: 1,
+		TTL:        time.Hour,
+		Clock:      fake,
+		Keep:       func(player *game.Player) bool { return len(player.Friends) > 0 },
+	})
+
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "alice", Friends: []string{"bob"}}))
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "r1", PlayerID: "alice", Timestamp: fake.Now()}))
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "bob"}))
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "carol"}))
+
+	// Past the cap only players Keep does not spare make way
+	_, err := repo.GetPlayer(ctx, "bob")
+	assert.Error(t, err)
+	_, err = repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	_, err = repo.GetPlayer(ctx, "carol")
+	require.NoError(t, err)
+
+	// Nor do they expire, though their results still do
+	fake.Advance(2 * time.Hour)
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "r2", PlayerID: "alice", Timestamp: fake.Now()}))
+	repo.Prune()
+	_, err = repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	_, err = repo.GetPlayer(ctx, "carol")
+	assert.Error(t, err)
+	results, err := repo.GetResults(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "r2", results[0].ID)
+
+	assert.Equal(t, MemoryStats{Results: 1, Players: 1, EvictedPlayers: 1, ExpiredPlayers: 1, ExpiredResults: 1}, repo.Stats())
+}
+
+func TestMemoryRepository_UnlimitedKeepsEverything(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository()
+
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("p%d", i)
+		require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: id}))
+		require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: id, PlayerID: id}))
+	}
+	repo.Prune()
+
+	assert.Equal(t, MemoryStats{Results: 100, Players: 100}, repo.Stats())
+}
@@ -47,12 +47,12 @@ func TestMemoryRepository_SaveResult(t *testing.T) {
 				ID:        "test_result_1",
 				Side:      game.Heads,
 				Won:       true,
-				Payout:    20.0,
+				Payout:    20 * game.Dollar,
 				Timestamp: time.Now(),
 				Seed:      "test_seed",
 				Bet: &game.Bet{
 					ID:        "test_bet_1",
-					Amount:    10.0,
+					Amount:    10 * game.Dollar,
 					Choice:    game.Heads,
 					Timestamp: time.Now(),
 				},
@@ -64,7 +64,7 @@ func TestMemoryRepository_SaveResult(t *testing.T) {
 				ID:        "test_result_2",
 				Side:      game.Tails,
 				Won:       false,
-				Payout:    0.0,
+				Payout:    game.Money(0),
 				Timestamp: time.Now(),
 				Seed:      "test_seed_2",
 			},
@@ -171,6 +171,159 @@ func TestMemoryRepository_GetResults(t *testing.T) {
 	assert.Equal(t, 3, len(results))
 }
 
+func TestMemoryRepository_QueryResults(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Ten bets, alternating sides, one per day with growing stakes
+	for i := 0; i < 10; i++ {
+		choice := game.Heads
+		if i%2 == 1 {
+			choice = game.Tails
+		}
+		won := i%3 == 0
+		payout := game.Money(0)
+		if won {
+			payout = game.Money(i+1) * 20 * game.Dollar
+		}
+		require.NoError(t, repo.SaveResult(ctx, &game.Result{
+			ID:        fmt.Sprintf("result_%d", i),
+			Side:      game.Heads,
+			Bet:       &game.Bet{ID: fmt.Sprintf("bet_%d", i), Amount: game.Money(i+1) * 10 * game.Dollar, Choice: choice},
+			Won:       won,
+			Payout:    payout,
+			Timestamp: start.AddDate(0, 0, i),
+		}))
+	}
+
+	// Pages walk the newest results first without overlapping
+	query := game.ResultQuery{Limit: 4}
+	page, err := repo.QueryResults(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 10, page.Total)
+	require.Len(t, page.Results, 4)
+	assert.Equal(t, "result_9", page.Results[0].ID)
+	assert.True(t, page.HasMore(query))
+
+	query.Offset = 8
+	page, err = repo.QueryResults(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, page.Results, 2)
+	assert.Equal(t, "result_0", page.Results[1].ID)
+	assert.False(t, page.HasMore(query))
+
+	// Filters combine
+	won := true
+	page, err = repo.QueryResults(ctx, game.ResultQuery{Won: &won, Choice: game.Heads})
+	require.NoError(t, err)
+	ids := make([]string, 0, len(page.Results))
+	for _, result := range page.Results {
+		ids = append(ids, result.ID)
+	}
+	assert.Equal(t, []string{"result_6", "result_0"}, ids)
+
+	page, err = repo.QueryResults(ctx, game.ResultQuery{
+		MinAmount: 30 * game.Dollar,
+		MaxAmount: 60 * game.Dollar,
+		From:      start.AddDate(0, 0, 3),
+		To:        start.AddDate(0, 0, 5),
+		Sort:      game.SortByAmount,
+		Ascending: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Results, 2)
+	assert.Equal(t, 40*game.Dollar, page.Results[0].Bet.Amount)
+	assert.Equal(t, 50*game.Dollar, page.Results[1].Bet.Amount)
+
+	// Pages are copies
+	page.Results[0].Bet.Amount = 999
+	page, err = repo.QueryResults(ctx, game.ResultQuery{Sort: game.SortByPayout, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "result_9", page.Results[0].ID)
+	assert.Equal(t, 100*game.Dollar, page.Results[0].Bet.Amount)
+}
+
+func TestMemoryRepository_MigratePlayer(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "guest_1", Balance: 120 * game.Dollar, Stats: game.Stats{GamesPlayed: 2}}))
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "bob", Balance: 50 * game.Dollar}))
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "r1", PlayerID: "guest_1", Side: game.Heads, Timestamp: time.Now()}))
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "r2", PlayerID: "bob", Side: game.Tails, Timestamp: time.Now()}))
+
+	// A taken account changes nothing
+	_, err := repo.MigratePlayer(ctx, "guest_1", "bob")
+	assert.ErrorIs(t, err, game.ErrAccountExists)
+	_, err = repo.GetPlayer(ctx, "guest_1")
+	require.NoError(t, err)
+
+	_, err = repo.MigratePlayer(ctx, "guest_2", "carol")
+	assert.Error(t, err)
+
+	player, err := repo.MigratePlayer(ctx, "guest_1", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", player.ID)
+	assert.Equal(t, 120*game.Dollar, player.Balance)
+	assert.Equal(t, 2, player.Stats.GamesPlayed)
+
+	_, err = repo.GetPlayer(ctx, "guest_1")
+	assert.Error(t, err)
+	page, err := repo.QueryResults(ctx, game.ResultQuery{PlayerID: "alice"})
+	require.NoError(t, err)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, "r1", page.Results[0].ID)
+}
+
+func TestMemoryRepository_HonorsContext(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := repo.SavePlayer(cancelled, &game.Player{ID: "alice"})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, repo.GetPlayerCount(), "a cancelled save stores nothing")
+
+	// A deadline passing while another operation holds the lock
+	expiring, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	repo.mu.Lock()
+	done := make(chan error, 1)
+	go func() {
+		_, err := repo.GetResults(expiring, 10)
+		done <- err
+	}()
+	<-expiring.Done()
+	repo.mu.Unlock()
+	assert.ErrorIs(t, <-done, context.DeadlineExceeded)
+
+	_, err = repo.GetPlayer(cancelled, "alice")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestMemoryRepository_FindByFriendCode(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "alice", Friends: []string{"bob"}}))
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "bob"}))
+
+	player, err := repo.FindByFriendCode(ctx, game.FriendCode("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", player.ID)
+	assert.Equal(t, []string{"bob"}, player.Friends)
+
+	// The stored friend list is not shared with callers
+	player.Friends[0] = "mallory"
+	player, err = repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, player.Friends)
+
+	_, err = repo.FindByFriendCode(ctx, game.FriendCode("carol"))
+	assert.Error(t, err)
+}
+
 func TestMemoryRepository_SavePlayer(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -186,7 +339,7 @@ func TestMemoryRepository_SavePlayer(t *testing.T) {
 			name: "empty ID",
 			player: &game.Player{
 				ID:      "",
-				Balance: 100,
+				Balance: 100 * game.Dollar,
 			},
 			expectedError: "player ID cannot be empty",
 		},
@@ -194,13 +347,13 @@ func TestMemoryRepository_SavePlayer(t *testing.T) {
 			name: "successful save",
 			player: &game.Player{
 				ID:      "test_player_1",
-				Balance: 500.0,
+				Balance: 500 * game.Dollar,
 				Stats: game.Stats{
 					GamesPlayed:   10,
 					GamesWon:      6,
-					TotalWagered:  100.0,
-					TotalWinnings: 120.0,
-					NetProfit:     20.0,
+					TotalWagered:  100 * game.Dollar,
+					TotalWinnings: 120 * game.Dollar,
+					NetProfit:     20 * game.Dollar,
 					WinRate:       60.0,
 				},
 			},
@@ -252,7 +405,7 @@ func TestMemoryRepository_GetPlayer(t *testing.T) {
 	// Add a test player
 	testPlayer := &game.Player{
 		ID:      "test_player",
-		Balance: 750.0,
+		Balance: 750 * game.Dollar,
 		Stats: game.Stats{
 			GamesPlayed: 5,
 			GamesWon:    3,
@@ -275,7 +428,7 @@ func TestMemoryRepository_GetPlayer(t *testing.T) {
 	testPlayer.Balance = 999.0
 	player, err = repo.GetPlayer(ctx, "test_player")
 	assert.NoError(t, err)
-	assert.Equal(t, 750.0, player.Balance) // Should be unchanged
+	assert.Equal(t, 750*game.Dollar, player.Balance) // Should be unchanged
 }
 
 func TestMemoryRepository_GetStats(t *testing.T) {
@@ -297,13 +450,13 @@ func TestMemoryRepository_GetStats(t *testing.T) {
 	// Add a test player with stats
 	testPlayer := &game.Player{
 		ID:      "test_player",
-		Balance: 500.0,
+		Balance: 500 * game.Dollar,
 		Stats: game.Stats{
 			GamesPlayed:   20,
 			GamesWon:      12,
-			TotalWagered:  200.0,
-			TotalWinnings: 240.0,
-			NetProfit:     40.0,
+			TotalWagered:  200 * game.Dollar,
+			TotalWinnings: 240 * game.Dollar,
+			NetProfit:     40 * game.Dollar,
 			WinRate:       60.0,
 		},
 	}
@@ -335,7 +488,7 @@ func TestMemoryRepository_Clear(t *testing.T) {
 	}
 	testPlayer := &game.Player{
 		ID:      "test_player",
-		Balance: 100,
+		Balance: 100 * game.Dollar,
 	}
 
 	err := repo.SaveResult(ctx, testResult)
@@ -377,7 +530,7 @@ func TestMemoryRepository_ConcurrentAccess(t *testing.T) {
 
 				player := &game.Player{
 					ID:      fmt.Sprintf("player_%d_%d", id, j),
-					Balance: float64(id * j),
+					Balance: game.Money(id*j) * game.Dollar,
 				}
 				err = repo.SavePlayer(ctx, player)
 				assert.NoError(t, err)
@@ -421,18 +574,18 @@ func TestMemoryRepository_DataIntegrity(t *testing.T) {
 		ID:        "test_result",
 		Side:      game.Heads,
 		Won:       true,
-		Payout:    20.0,
+		Payout:    20 * game.Dollar,
 		Timestamp: time.Now(),
 		Bet: &game.Bet{
 			ID:     "test_bet",
-			Amount: 10.0,
+			Amount: 10 * game.Dollar,
 			Choice: game.Heads,
 		},
 	}
 
 	originalPlayer := &game.Player{
 		ID:      "test_player",
-		Balance: 500.0,
+		Balance: 500 * game.Dollar,
 		Stats: game.Stats{
 			GamesPlayed: 10,
 			GamesWon:    6,
@@ -458,14 +611,14 @@ func TestMemoryRepository_DataIntegrity(t *testing.T) {
 	require.Len(t, results, 1)
 
 	retrievedResult := results[0]
-	assert.True(t, retrievedResult.Won)               // Should still be true
-	assert.Equal(t, 20.0, retrievedResult.Payout)     // Should still be 20.0
-	assert.Equal(t, 10.0, retrievedResult.Bet.Amount) // Should still be 10.0
+	assert.True(t, retrievedResult.Won)                         // Should still be true
+	assert.Equal(t, 20*game.Dollar, retrievedResult.Payout)     // Should still be 20.0
+	assert.Equal(t, 10*game.Dollar, retrievedResult.Bet.Amount) // Should still be 10.0
 
 	retrievedPlayer, err := repo.GetPlayer(ctx, "test_player")
 	require.NoError(t, err)
-	assert.Equal(t, 500.0, retrievedPlayer.Balance)        // Should still be 500.0
-	assert.Equal(t, 10, retrievedPlayer.Stats.GamesPlayed) // Should still be 10
+	assert.Equal(t, 500*game.Dollar, retrievedPlayer.Balance) // Should still be 500.0
+	assert.Equal(t, 10, retrievedPlayer.Stats.GamesPlayed)    // Should still be 10
 }
 
 // Benchmark tests
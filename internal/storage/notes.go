@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// NoteBook keeps the private notes each player made about others between
+// runs. As with favorites, a guest's notes are kept in memory only, and an
+// empty path keeps every player's in memory.
+type NoteBook struct {
+	mu    sync.Mutex
+	path  string
+	notes map[string][]game.PlayerNote // By the player who wrote them
+}
+
+// OpenNoteBook loads the player notes at path. A missing file opens an
+// empty book.
+func OpenNoteBook(path string) (*NoteBook, error) {
+	book := &NoteBook{path: path, notes: make(map[string][]game.PlayerNote)}
+	if path == "" {
+		return book, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return book, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read player notes: %w", err)
+	}
+	if err := json.Unmarshal(data, &book.notes); err != nil {
+		return nil, fmt.Errorf("failed to decode player notes: %w", err)
+	}
+	return book, nil
+}
+
+// Get returns the owner's note about a player, if there is one
+func (b *NoteBook) Get(ownerID, playerID string) (game.PlayerNote, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, note := range b.notes[ownerID] {
+		if note.PlayerID == playerID {
+			return note, true
+		}
+	}
+	return game.PlayerNote{}, false
+}
+
+// Search returns the owner's notes matching the query, as PlayerNote.Matches
+// does, sorted by player name
+func (b *NoteBook) Search(ownerID, query string) []game.PlayerNote {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var found []game.PlayerNote
+	for _, note := range b.notes[ownerID] {
+		if note.Matches(query) {
+			found = append(found, note)
+		}
+	}
+	return found
+}
+
+// Save stores the owner's note about a player, replacing any earlier one,
+// and saves the book. An empty note removes the earlier one.
+func (b *NoteBook) Save(ownerID string, note game.PlayerNote) error {
+	if note.Empty() {
+		err := b.Remove(ownerID, note.PlayerID)
+		if errors.Is(err, game.ErrNoteNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := note.Validate(); err != nil {
+		return err
+	}
+	note.Tags = slices.Clone(note.Tags)
+	note.UpdatedAt = time.Now().UTC()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	notes := slices.Clone(b.notes[ownerID])
+	i := slices.IndexFunc(notes, func(n game.PlayerNote) bool { return n.PlayerID == note.PlayerID })
+	switch {
+	case i >= 0:
+		notes[i] = note
+	case len(notes) >= game.MaxNotes:
+		return game.ErrTooManyNotes
+	default:
+		notes = append(notes, note)
+	}
+	slices.SortStableFunc(notes, func(a, b game.PlayerNote) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	b.notes[ownerID] = notes
+	return b.save(ownerID)
+}
+
+// Remove deletes the owner's note about a player and saves the book
+func (b *NoteBook) Remove(ownerID, playerID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	notes := b.notes[ownerID]
+	i := slices.IndexFunc(notes, func(n game.PlayerNote) bool { return n.PlayerID == playerID })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", game.ErrNoteNotFound, playerID)
+	}
+
+	notes = slices.Delete(slices.Clone(notes), i, i+1)
+	if len(notes) == 0 {
+		delete(b.notes, ownerID)
+	} else {
+		b.notes[ownerID] = notes
+	}
+	return b.save(ownerID)
+}
+
+// save writes the notes of registered players to the book's file,
+// replacing it atomically. It is skipped when only a guest's notes
+// changed. Callers must hold b.mu.
+func (b *NoteBook) save(changed string) error {
+	if b.path == "" || game.IsGuest(changed) {
+		return nil
+	}
+
+	kept := make(map[string][]game.PlayerNote, len(b.notes))
+	for id, notes := range b.notes {
+		if !game.IsGuest(id) {
+			kept[id] = notes
+		}
+	}
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode player notes: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return fmt.Errorf("failed to create player notes directory: %w", err)
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write player notes: %w", err)
+	}
+	if err := os.Rename(tmp, b.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace player notes: %w", err)
+	}
+	return nil
+}
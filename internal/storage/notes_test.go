@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+func TestNoteBook_PersistsPerPlayer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "notes.json")
+
+	book, err := OpenNoteBook(path)
+	require.NoError(t, err)
+	_, ok := book.Get("alice", "bob")
+	assert.False(t, ok)
+
+	bob := game.PlayerNote{PlayerID: "bob", Name: "Bob", Tags: []string{"aggressive"}, Text: "Bets big early"}
+	carol := game.PlayerNote{PlayerID: "carol", Name: "carol", Tags: []string{"friend"}}
+	require.NoError(t, book.Save("alice", carol))
+	require.NoError(t, book.Save("alice", bob))
+	require.NoError(t, book.Save("dave", carol))
+	guest := game.NewGuestID()
+	require.NoError(t, book.Save(guest, bob))
+
+	reopened, err := OpenNoteBook(path)
+	require.NoError(t, err)
+	notes := reopened.Search("alice", "")
+	require.Len(t, notes, 2)
+	assert.Equal(t, []string{"Bob", "carol"}, []string{notes[0].Name, notes[1].Name}, "sorted by name, ignoring case")
+	assert.False(t, notes[0].UpdatedAt.IsZero())
+	assert.Len(t, reopened.Search("dave", ""), 1, "notes are kept per player")
+	assert.Empty(t, reopened.Search(guest, ""), "guest notes are not saved")
+
+	// Saving again replaces the note, and an empty note removes it
+	bob.Tags = []string{"cautious"}
+	require.NoError(t, reopened.Save("alice", bob))
+	note, ok := reopened.Get("alice", "bob")
+	require.True(t, ok)
+	assert.Equal(t, []string{"cautious"}, note.Tags)
+
+	require.NoError(t, reopened.Save("alice", game.PlayerNote{PlayerID: "carol", Name: "carol"}))
+	_, ok = reopened.Get("alice", "carol")
+	assert.False(t, ok)
+	require.NoError(t, reopened.Save("alice", game.PlayerNote{PlayerID: "nobody"}), "clearing a missing note is a no-op")
+	assert.ErrorIs(t, reopened.Remove("alice", "carol"), game.ErrNoteNotFound)
+}
+
+func TestNoteBook_Search(t *testing.T) {
+	book, err := OpenNoteBook("")
+	require.NoError(t, err)
+	require.NoError(t, book.Save("alice", game.PlayerNote{PlayerID: "p1", Name: "Bob", Tags: []string{"high roller"}}))
+	require.NoError(t, book.Save("alice", game.PlayerNote{PlayerID: "p2", Name: "Eve", Text: "Hedges every round"}))
+
+	found := book.Search("alice", "ROLLER")
+	require.Len(t, found, 1)
+	assert.Equal(t, "p1", found[0].PlayerID)
+	assert.Len(t, book.Search("alice", "e"), 2)
+	assert.Empty(t, book.Search("alice", "lucky"))
+}
+
+func TestNoteBook_RejectsInvalidNotes(t *testing.T) {
+	book, err := OpenNoteBook("")
+	require.NoError(t, err)
+	assert.ErrorIs(t, book.Save("alice", game.PlayerNote{PlayerID: "p1", Tags: []string{"Not Normal"}}), game.ErrInvalidNote)
+
+	path := filepath.Join(t.TempDir(), "notes.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+	_, err = OpenNoteBook(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// ErrPromoNotFound is returned when no promo code has the requested code
+var ErrPromoNotFound = errors.New("promo code not found")
+
+// PromoRecord is a stored promo code with the players who redeemed it
+type PromoRecord struct {
+	Code      string     `json:"code"`
+	Value     game.Money `json:"value"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt time.Time  `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Redeemers []string   `json:"redeemers,omitempty"`
+}
+
+// PromoRepository persists the server's promo codes
+type PromoRepository interface {
+	// SavePromo stores a promo code, replacing any with the same code
+	SavePromo(ctx context.Context, promo *PromoRecord) error
+	// GetPromo returns a promo code, or ErrPromoNotFound
+	GetPromo(ctx context.Context, code string) (*PromoRecord, error)
+	// GetPromos returns every promo code in the order they were first
+	// saved
+	GetPromos(ctx context.Context) ([]*PromoRecord, error)
+}
+
+// MemoryPromoRepository implements PromoRepository in memory. Like
+// MemoryRepository, it fails with the context's error once the context is
+// done.
+type MemoryPromoRepository struct {
+	mu     sync.RWMutex
+	promos map[string]*PromoRecord
+	// order lists the codes as first saved, so codes created in the same
+	// instant still list in the order they were created
+	order []string
+}
+
+// NewMemoryPromoRepository creates an empty in-memory promo repository
+func NewMemoryPromoRepository() *MemoryPromoRepository {
+	return &MemoryPromoRepository{promos: make(map[string]*PromoRecord)}
+}
+
+// SavePromo stores a copy of the promo code
+func (r *MemoryPromoRepository) SavePromo(ctx context.Context, promo *PromoRecord) error {
+	if err := validatePromo(promo); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, exists := r.promos[promo.Code]; !exists {
+		r.order = append(r.order, promo.Code)
+	}
+	r.promos[promo.Code] = copyPromo(promo)
+	return nil
+}
+
+// GetPromo returns a copy of the promo code
+func (r *MemoryPromoRepository) GetPromo(ctx context.Context, code string) (*PromoRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	promo, exists := r.promos[code]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrPromoNotFound, code)
+	}
+	return copyPromo(promo), nil
+}
+
+// GetPromos returns copies of every promo code
+func (r *MemoryPromoRepository) GetPromos(ctx context.Context) ([]*PromoRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	promos := make([]*PromoRecord, 0, len(r.order))
+	for _, code := range r.order {
+		promos = append(promos, copyPromo(r.promos[code]))
+	}
+	return promos, nil
+}
+
+// validatePromo rejects promo codes that cannot be stored
+func validatePromo(promo *PromoRecord) error {
+	if promo == nil || promo.Code == "" {
+		return fmt.Errorf("promo code cannot be empty")
+	}
+	return nil
+}
+
+// copyPromo returns a deep copy of a promo code
+func copyPromo(promo *PromoRecord) *PromoRecord {
+	copied := *promo
+	copied.Redeemers = slices.Clone(promo.Redeemers)
+	return &copied
+}
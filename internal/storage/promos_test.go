@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+// testPromoRepository checks the behaviour every PromoRepository shares
+func testPromoRepository(t *testing.T, repo PromoRepository) {
+	ctx := context.Background()
+
+	assert.Error(t, repo.SavePromo(ctx, &PromoRecord{}))
+	_, err := repo.GetPromo(ctx, "WELCOME")
+	assert.ErrorIs(t, err, ErrPromoNotFound)
+
+	for _, code := range []string{"WELCOME", "AUTUMN", "BONUS"} {
+		require.NoError(t, repo.SavePromo(ctx, &PromoRecord{Code: code, Value: 10 * game.Dollar, MaxUses: 2}))
+	}
+
+	// Replacing a code keeps its place in the list
+	require.NoError(t, repo.SavePromo(ctx, &PromoRecord{
+		Code: "WELCOME", Value: 10 * game.Dollar, MaxUses: 2, Uses: 1, Redeemers: []string{"p1"},
+	}))
+	promo, err := repo.GetPromo(ctx, "WELCOME")
+	require.NoError(t, err)
+	assert.Equal(t, 1, promo.Uses)
+	assert.Equal(t, []string{"p1"}, promo.Redeemers)
+
+	promos, err := repo.GetPromos(ctx)
+	require.NoError(t, err)
+	codes := make([]string, len(promos))
+	for i, promo := range promos {
+		codes[i] = promo.Code
+	}
+	assert.Equal(t, []string{"WELCOME", "AUTUMN", "BONUS"}, codes)
+
+	// Callers get copies
+	promo.Redeemers[0] = "p9"
+	promo, err = repo.GetPromo(ctx, "WELCOME")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p1"}, promo.Redeemers)
+}
+
+func TestMemoryPromoRepository(t *testing.T) {
+	testPromoRepository(t, NewMemoryPromoRepository())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewMemoryPromoRepository().GetPromos(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
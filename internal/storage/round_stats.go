@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// DayLayout formats the UTC day daily volume is kept by
+const DayLayout = "2006-01-02"
+
+// PlayerRoundStats sums a player's real-money outcomes over every stored
+// round. Practice outcomes are left out.
+type PlayerRoundStats struct {
+	PlayerID    string     `json:"player_id"`
+	PlayerName  string     `json:"player_name"`
+	Rounds      int        `json:"rounds"`
+	Wins        int        `json:"wins"`
+	Bets        int        `json:"bets"`
+	Wagered     game.Money `json:"wagered"`
+	PaidOut     game.Money `json:"paid_out"`
+	Net         game.Money `json:"net"`
+	LastRoundAt time.Time  `json:"last_round_at"`
+}
+
+// DailyVolume sums the real-money outcomes of the rounds settled on a UTC
+// day, such as "2026-10-16"
+type DailyVolume struct {
+	Day     string     `json:"day"`
+	Rounds  int        `json:"rounds"`
+	Bets    int        `json:"bets"`
+	Wagered game.Money `json:"wagered"`
+	PaidOut game.Money `json:"paid_out"`
+}
+
+// RoundStatsRepository answers aggregate questions about stored rounds
+// without the caller reading every round
+type RoundStatsRepository interface {
+	// PlayerRoundStats returns a player's lifetime stats; a player with no
+	// rounds gets zero stats
+	PlayerRoundStats(ctx context.Context, playerID string) (*PlayerRoundStats, error)
+	// RoundLeaderboard returns the players with the best net result, best
+	// first, up to limit; a limit of zero or less returns them all
+	RoundLeaderboard(ctx context.Context, limit int) ([]PlayerRoundStats, error)
+	// DailyVolume returns the volume of each day from from to to, both
+	// inclusive, oldest first; days without rounds are left out
+	DailyVolume(ctx context.Context, from, to time.Time) ([]DailyVolume, error)
+	// RefreshStats rebuilds the stats from the stored rounds
+	RefreshStats(ctx context.Context) error
+}
+
+// roundStats is what one or more rounds add to the stats
+type roundStats struct {
+	players map[string]*PlayerRoundStats
+	days    map[string]*DailyVolume
+}
+
+// newRoundStats returns empty stats
+func newRoundStats() *roundStats {
+	return &roundStats{
+		players: make(map[string]*PlayerRoundStats),
+		days:    make(map[string]*DailyVolume),
+	}
+}
+
+// add sums a round's real-money outcomes into the stats. A round with only
+// practice outcomes adds nothing.
+func (s *roundStats) add(round *Round) {
+	var volume DailyVolume
+	for _, outcome := range round.Outcomes {
+		if outcome.Practice {
+			continue
+		}
+		paidOut := outcome.Payout + outcome.Insurance
+
+		player, exists := s.players[outcome.PlayerID]
+		if !exists {
+			player = &PlayerRoundStats{PlayerID: outcome.PlayerID}
+			s.players[outcome.PlayerID] = player
+		}
+		if !round.SettledAt.Before(player.LastRoundAt) {
+			player.PlayerName = outcome.PlayerName
+			player.LastRoundAt = round.SettledAt
+		}
+		player.Rounds++
+		if outcome.Won {
+			player.Wins++
+		}
+		player.Bets += len(outcome.Bets)
+		player.Wagered += outcome.Wagered
+		player.PaidOut += paidOut
+		player.Net += paidOut - outcome.Wagered
+
+		volume.Bets += len(outcome.Bets)
+		volume.Wagered += outcome.Wagered
+		volume.PaidOut += paidOut
+		volume.Rounds = 1
+	}
+	if volume.Rounds == 0 {
+		return
+	}
+
+	volume.Day = round.SettledAt.UTC().Format(DayLayout)
+	day, exists := s.days[volume.Day]
+	if !exists {
+		s.days[volume.Day] = &volume
+		return
+	}
+	day.Rounds += volume.Rounds
+	day.Bets += volume.Bets
+	day.Wagered += volume.Wagered
+	day.PaidOut += volume.PaidOut
+}
+
+// leaderboard returns the players best net result first, ties by ID
+func (s *roundStats) leaderboard(limit int) []PlayerRoundStats {
+	players := make([]PlayerRoundStats, 0, len(s.players))
+	for _, player := range s.players {
+		players = append(players, *player)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].Net != players[j].Net {
+			return players[i].Net > players[j].Net
+		}
+		return players[i].PlayerID < players[j].PlayerID
+	})
+	if limit > 0 && limit < len(players) {
+		players = players[:limit]
+	}
+	return players
+}
+
+// volume returns the days from from to to, oldest first
+func (s *roundStats) volume(from, to time.Time) []DailyVolume {
+	first, last := from.UTC().Format(DayLayout), to.UTC().Format(DayLayout)
+	days := make([]DailyVolume, 0)
+	for _, day := range s.days {
+		if day.Day >= first && day.Day <= last {
+			days = append(days, *day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Day < days[j].Day })
+	return days
+}
+
+// stats sums every stored round. Callers must hold r.mu.
+func (r *MemoryRoundRepository) stats() *roundStats {
+	stats := newRoundStats()
+	for _, round := range r.rounds {
+		stats.add(round)
+	}
+	return stats
+}
+
+// PlayerRoundStats sums the player's outcomes over the stored rounds
+func (r *MemoryRoundRepository) PlayerRoundStats(ctx context.Context, playerID string) (*PlayerRoundStats, error) {
+	_, span := tracer.Start(ctx, "storage.player_round_stats")
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if player, exists := r.stats().players[playerID]; exists {
+		return player, nil
+	}
+	return &PlayerRoundStats{PlayerID: playerID}, nil
+}
+
+// RoundLeaderboard ranks the players of the stored rounds by net result
+func (r *MemoryRoundRepository) RoundLeaderboard(ctx context.Context, limit int) ([]PlayerRoundStats, error) {
+	_, span := tracer.Start(ctx, "storage.round_leaderboard")
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.stats().leaderboard(limit), nil
+}
+
+// DailyVolume sums the stored rounds by the day they settled
+func (r *MemoryRoundRepository) DailyVolume(ctx context.Context, from, to time.Time) ([]DailyVolume, error) {
+	_, span := tracer.Start(ctx, "storage.daily_volume")
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return r.stats().volume(from, to), nil
+}
+
+// RefreshStats does nothing: the memory repository sums its rounds on
+// every query, so its stats are never stale
+func (r *MemoryRoundRepository) RefreshStats(ctx context.Context) error {
+	return ctx.Err()
+}
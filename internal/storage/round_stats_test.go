@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"coinflip-game/internal/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsRound returns a round settled at the time with a real-money outcome
+// per player, each betting $10 on heads
+func statsRound(id string, settledAt time.Time, winners []string, losers ...string) *Round {
+	round := &Round{ID: id, RoomID: "r1", CoinResult: game.Heads, SettledAt: settledAt}
+	bet := []RoundBet{{ID: "bet", Choice: game.Heads, Amount: 10 * game.Dollar}}
+	for _, playerID := range winners {
+		round.Outcomes = append(round.Outcomes, RoundOutcome{
+			PlayerID: playerID, PlayerName: "Player " + playerID, Bets: bet,
+			Wagered: 10 * game.Dollar, Won: true, Payout: 20 * game.Dollar,
+		})
+	}
+	for _, playerID := range losers {
+		round.Outcomes = append(round.Outcomes, RoundOutcome{
+			PlayerID: playerID, PlayerName: "Player " + playerID, Bets: bet,
+			Wagered: 10 * game.Dollar,
+		})
+	}
+	return round
+}
+
+func TestMemoryRoundRepository_Stats(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRoundRepository()
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.SaveRound(ctx, statsRound("1", day, []string{"p1"}, "p2")))
+	require.NoError(t, repo.SaveRound(ctx, statsRound("2", day.Add(time.Hour), []string{"p1", "p2"})))
+	require.NoError(t, repo.SaveRound(ctx, statsRound("3", day.AddDate(0, 0, 1), nil, "p1", "p3")))
+
+	// Practice outcomes are left out, and a practice-only round adds no day
+	practice := statsRound("4", day.AddDate(0, 0, 2), []string{"p1"})
+	practice.Outcomes[0].Practice = true
+	require.NoError(t, repo.SaveRound(ctx, practice))
+
+	p1, err := repo.PlayerRoundStats(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, &PlayerRoundStats{
+		PlayerID: "p1", PlayerName: "Player p1",
+		Rounds: 3, Wins: 2, Bets: 3,
+		Wagered: 30 * game.Dollar, PaidOut: 40 * game.Dollar, Net: 10 * game.Dollar,
+		LastRoundAt: day.AddDate(0, 0, 1),
+	}, p1)
+
+	nobody, err := repo.PlayerRoundStats(ctx, "nobody")
+	require.NoError(t, err)
+	assert.Equal(t, &PlayerRoundStats{PlayerID: "nobody"}, nobody)
+
+	leaders, err := repo.RoundLeaderboard(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, leaders, 2)
+	assert.Equal(t, "p1", leaders[0].PlayerID, "ties go by player ID")
+	assert.Equal(t, "p2", leaders[1].PlayerID)
+	all, err := repo.RoundLeaderboard(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, -10*game.Dollar, all[2].Net)
+
+	volume, err := repo.DailyVolume(ctx, day.AddDate(0, 0, -7), day.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	assert.Equal(t, []DailyVolume{
+		{Day: "2024-01-01", Rounds: 2, Bets: 4, Wagered: 40 * game.Dollar, PaidOut: 60 * game.Dollar},
+		{Day: "2024-01-02", Rounds: 1, Bets: 2, Wagered: 20 * game.Dollar},
+	}, volume)
+
+	volume, err = repo.DailyVolume(ctx, day.AddDate(0, 0, 1), day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, volume, 1, "both ends are included")
+	assert.Equal(t, "2024-01-02", volume[0].Day)
+
+	// Replacing a round replaces what it adds
+	require.NoError(t, repo.SaveRound(ctx, statsRound("3", day.AddDate(0, 0, 1), []string{"p3"})))
+	p1, err = repo.PlayerRoundStats(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, p1.Rounds)
+	assert.Equal(t, day.Add(time.Hour), p1.LastRoundAt)
+
+	require.NoError(t, repo.RefreshStats(ctx))
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, repo.RefreshStats(cancelled), context.Canceled)
+}
+
+func TestRoundStats_NameFollowsNewestRound(t *testing.T) {
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	newer := statsRound("2", day.Add(time.Hour), []string{"p1"})
+	newer.Outcomes[0].PlayerName = "Renamed"
+
+	stats := newRoundStats()
+	stats.add(newer)
+	stats.add(statsRound("1", day, []string{"p1"}))
+
+	assert.Equal(t, "Renamed", stats.players["p1"].PlayerName)
+	assert.Equal(t, day.Add(time.Hour), stats.players["p1"].LastRoundAt)
+}
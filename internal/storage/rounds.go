@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// ErrRoundNotFound is returned when no round has the requested ID
+var ErrRoundNotFound = errors.New("round not found")
+
+// Round is a settled multiplayer round
+type Round struct {
+	ID         string         `json:"id"`
+	RoomID     string         `json:"room_id"`
+	CoinResult game.Side      `json:"coin_result"`
+	FinalSeed  string         `json:"final_seed"`
+	StartedAt  time.Time      `json:"started_at"`
+	SettledAt  time.Time      `json:"settled_at"`
+	Outcomes   []RoundOutcome `json:"outcomes"`
+}
+
+// RoundOutcome is one player's share of a settled round
+type RoundOutcome struct {
+	PlayerID   string     `json:"player_id"`
+	PlayerName string     `json:"player_name"`
+	Bets       []RoundBet `json:"bets"`
+	Wagered    game.Money `json:"wagered"`
+	Won        bool       `json:"won"`
+	Payout     game.Money `json:"payout"`
+	Insurance  game.Money `json:"insurance,omitempty"`
+	NewBalance game.Money `json:"new_balance"`
+	Practice   bool       `json:"practice,omitempty"`
+}
+
+// RoundBet is one bet placed in a settled round
+type RoundBet struct {
+	ID       string     `json:"id"`
+	Choice   game.Side  `json:"choice"`
+	Amount   game.Money `json:"amount"`
+	Premium  game.Money `json:"premium,omitempty"`
+	Practice bool       `json:"practice,omitempty"`
+}
+
+// RoundRepository persists settled multiplayer rounds. It is kept apart
+// from game.Repository, which holds single-player results and players.
+type RoundRepository interface {
+	// SaveRound stores a settled round, replacing any with the same ID
+	SaveRound(ctx context.Context, round *Round) error
+	// GetRounds returns the most recent rounds of a room, newest first, up
+	// to limit; an empty room ID returns rounds of every room, and a limit
+	// of zero or less returns them all
+	GetRounds(ctx context.Context, roomID string, limit int) ([]*Round, error)
+	// GetRoundByID returns a round, or ErrRoundNotFound
+	GetRoundByID(ctx context.Context, id string) (*Round, error)
+}
+
+// MemoryRoundRepository implements RoundRepository in memory. Like
+// MemoryRepository, it fails with the context's error once the context is
+// done.
+type MemoryRoundRepository struct {
+	mu     sync.RWMutex
+	rounds map[string]*Round
+}
+
+// NewMemoryRoundRepository creates an empty in-memory round repository
+func NewMemoryRoundRepository() *MemoryRoundRepository {
+	return &MemoryRoundRepository{rounds: make(map[string]*Round)}
+}
+
+// SaveRound stores a copy of the round
+func (r *MemoryRoundRepository) SaveRound(ctx context.Context, round *Round) error {
+	_, span := tracer.Start(ctx, "storage.save_round")
+	defer span.End()
+
+	if err := validateRound(round); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.rounds[round.ID] = copyRound(round)
+	return nil
+}
+
+// GetRounds returns copies of the room's most recent rounds
+func (r *MemoryRoundRepository) GetRounds(ctx context.Context, roomID string, limit int) ([]*Round, error) {
+	_, span := tracer.Start(ctx, "storage.get_rounds")
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rounds := make([]*Round, 0, len(r.rounds))
+	for _, round := range r.rounds {
+		if roomID == "" || round.RoomID == roomID {
+			rounds = append(rounds, copyRound(round))
+		}
+	}
+
+	sort.Slice(rounds, func(i, j int) bool {
+		return rounds[i].SettledAt.After(rounds[j].SettledAt)
+	})
+	if limit > 0 && limit < len(rounds) {
+		rounds = rounds[:limit]
+	}
+	return rounds, nil
+}
+
+// GetRoundByID returns a copy of the round
+func (r *MemoryRoundRepository) GetRoundByID(ctx context.Context, id string) (*Round, error) {
+	_, span := tracer.Start(ctx, "storage.get_round")
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	round, exists := r.rounds[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrRoundNotFound, id)
+	}
+	return copyRound(round), nil
+}
+
+// validateRound checks a round can be stored
+func validateRound(round *Round) error {
+	if round == nil {
+		return fmt.Errorf("round cannot be nil")
+	}
+	if round.ID == "" {
+		return fmt.Errorf("round ID cannot be empty")
+	}
+	return nil
+}
+
+// copyRound returns a deep copy of a round
+func copyRound(round *Round) *Round {
+	copied := *round
+	copied.Outcomes = slices.Clone(round.Outcomes)
+	for i := range copied.Outcomes {
+		copied.Outcomes[i].Bets = slices.Clone(round.Outcomes[i].Bets)
+	}
+	return &copied
+}
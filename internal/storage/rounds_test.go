@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"coinflip-game/internal/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRoundRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRoundRepository()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.EqualError(t, repo.SaveRound(ctx, nil), "round cannot be nil")
+	assert.EqualError(t, repo.SaveRound(ctx, &Round{}), "round ID cannot be empty")
+
+	for i, roomID := range []string{"a", "b", "a", "a"} {
+		require.NoError(t, repo.SaveRound(ctx, &Round{
+			ID:         string(rune('1' + i)),
+			RoomID:     roomID,
+			CoinResult: game.Heads,
+			SettledAt:  start.Add(time.Duration(i) * time.Minute),
+			Outcomes: []RoundOutcome{{
+				PlayerID: "p1",
+				Bets:     []RoundBet{{ID: "bet", Choice: game.Heads, Amount: game.Dollar}},
+				Won:      true,
+				Payout:   2 * game.Dollar,
+			}},
+		}))
+	}
+
+	rounds, err := repo.GetRounds(ctx, "a", 2)
+	require.NoError(t, err)
+	require.Len(t, rounds, 2)
+	assert.Equal(t, "4", rounds[0].ID, "newest first")
+	assert.Equal(t, "3", rounds[1].ID)
+
+	all, err := repo.GetRounds(ctx, "", 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	// Rounds handed out are copies
+	rounds[0].Outcomes[0].Bets[0].Amount = 100 * game.Dollar
+	round, err := repo.GetRoundByID(ctx, "4")
+	require.NoError(t, err)
+	assert.Equal(t, game.Dollar, round.Outcomes[0].Bets[0].Amount)
+
+	_, err = repo.GetRoundByID(ctx, "missing")
+	assert.ErrorIs(t, err, ErrRoundNotFound)
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"coinflip-game/internal/game"
+)
+
+// ErrSeasonNotFound is returned when no archived season has the requested ID
+var ErrSeasonNotFound = errors.New("season not found")
+
+// SeasonRepository archives the final standings of ended multiplayer
+// seasons
+type SeasonRepository interface {
+	// SaveSeason archives a season, replacing any with the same ID
+	SaveSeason(ctx context.Context, season *game.Season) error
+	// GetSeasons returns archived seasons, newest first, up to limit; a
+	// limit of zero or less returns them all
+	GetSeasons(ctx context.Context, limit int) ([]*game.Season, error)
+	// GetSeason returns an archived season, or ErrSeasonNotFound
+	GetSeason(ctx context.Context, id string) (*game.Season, error)
+}
+
+// MemorySeasonRepository implements SeasonRepository in memory. Like
+// MemoryRepository, it fails with the context's error once the context is
+// done.
+type MemorySeasonRepository struct {
+	mu      sync.RWMutex
+	seasons map[string]*game.Season
+}
+
+// NewMemorySeasonRepository creates an empty in-memory season archive
+func NewMemorySeasonRepository() *MemorySeasonRepository {
+	return &MemorySeasonRepository{seasons: make(map[string]*game.Season)}
+}
+
+// SaveSeason stores a copy of the season
+func (r *MemorySeasonRepository) SaveSeason(ctx context.Context, season *game.Season) error {
+	if season == nil || season.ID == "" {
+		return fmt.Errorf("season ID cannot be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.seasons[season.ID] = copySeason(season)
+	return nil
+}
+
+// GetSeasons returns copies of the most recent seasons
+func (r *MemorySeasonRepository) GetSeasons(ctx context.Context, limit int) ([]*game.Season, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	seasons := make([]*game.Season, 0, len(r.seasons))
+	for _, season := range r.seasons {
+		seasons = append(seasons, copySeason(season))
+	}
+
+	sort.Slice(seasons, func(i, j int) bool {
+		return seasons[i].Start.After(seasons[j].Start)
+	})
+	if limit > 0 && limit < len(seasons) {
+		seasons = seasons[:limit]
+	}
+	return seasons, nil
+}
+
+// GetSeason returns a copy of the season
+func (r *MemorySeasonRepository) GetSeason(ctx context.Context, id string) (*game.Season, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	season, exists := r.seasons[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrSeasonNotFound, id)
+	}
+	return copySeason(season), nil
+}
+
+// copySeason returns a deep copy of a season
+func copySeason(season *game.Season) *game.Season {
+	copied := *season
+	copied.Standings = slices.Clone(season.Standings)
+	return &copied
+}
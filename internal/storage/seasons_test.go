@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+func TestMemorySeasonRepository(t *testing.T) {
+	repo := NewMemorySeasonRepository()
+	ctx := context.Background()
+
+	first := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	for week := range 3 {
+		board := game.NewSeasonBoard(first.AddDate(0, 0, 7*week))
+		board.Record("alice", "Alice", true, game.Dollar)
+		require.NoError(t, repo.SaveSeason(ctx, board.Season()))
+	}
+	assert.Error(t, repo.SaveSeason(ctx, &game.Season{}))
+
+	seasons, err := repo.GetSeasons(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, seasons, 2)
+	assert.Equal(t, "2026-W43", seasons[0].ID, "newest first")
+	assert.Equal(t, "2026-W42", seasons[1].ID)
+
+	season, err := repo.GetSeason(ctx, "2026-W41")
+	require.NoError(t, err)
+	require.Len(t, season.Standings, 1)
+
+	// Callers get copies
+	season.Standings[0].Wins = 99
+	season, err = repo.GetSeason(ctx, "2026-W41")
+	require.NoError(t, err)
+	assert.Equal(t, 1, season.Standings[0].Wins)
+
+	_, err = repo.GetSeason(ctx, "2026-W01")
+	assert.ErrorIs(t, err, ErrSeasonNotFound)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = repo.GetSeasons(cancelled, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+}
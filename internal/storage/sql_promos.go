@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// promosSchema creates the table SQLPromoRepository stores promo codes in.
+// Each code is one row with the code as JSON; seq keeps the order codes
+// were first saved in.
+const promosSchema = `CREATE TABLE IF NOT EXISTS promos (
+	code VARCHAR(64) PRIMARY KEY,
+	seq  BIGINT      NOT NULL,
+	data TEXT        NOT NULL
+)`
+
+// SQLPromoRepository implements PromoRepository on a SQLite or MySQL
+// database
+type SQLPromoRepository struct {
+	db      *sql.DB
+	dialect SQLDialect
+}
+
+// NewSQLPromoRepository returns a promo repository on db, which speaks the
+// dialect. Call CreateSchema once before using it on a new database.
+func NewSQLPromoRepository(db *sql.DB, dialect SQLDialect) *SQLPromoRepository {
+	return &SQLPromoRepository{db: db, dialect: dialect}
+}
+
+// OpenSQLPromoRepository connects to the database at the URL, as OpenSQL
+// does, and creates the schema if it is missing. Close the repository when
+// done with it.
+func OpenSQLPromoRepository(ctx context.Context, url string) (*SQLPromoRepository, error) {
+	db, dialect, err := OpenSQL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	repo := NewSQLPromoRepository(db, dialect)
+	if err := repo.CreateSchema(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Close closes the repository's database
+func (r *SQLPromoRepository) Close() error {
+	return r.db.Close()
+}
+
+// CreateSchema creates the promos table if it is missing
+func (r *SQLPromoRepository) CreateSchema(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, promosSchema); err != nil {
+		return fmt.Errorf("failed to create promos schema: %w", err)
+	}
+	return nil
+}
+
+// SavePromo inserts the promo code after the codes already stored, or
+// replaces the one stored under its code
+func (r *SQLPromoRepository) SavePromo(ctx context.Context, promo *PromoRecord) error {
+	ctx, span := tracer.Start(ctx, "storage.save_promo")
+	defer span.End()
+
+	if err := validatePromo(promo); err != nil {
+		return err
+	}
+	data, err := json.Marshal(promo)
+	if err != nil {
+		return fmt.Errorf("failed to encode promo code %s: %w", promo.Code, err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save promo code %s: %w", promo.Code, err)
+	}
+	defer tx.Rollback()
+
+	// The row, or the gap where it would go, stays locked so a concurrent
+	// save of the code waits for this one
+	var seq int64
+	err = tx.QueryRowContext(ctx, `SELECT seq FROM promos WHERE code = ?`+r.dialect.forUpdate(), promo.Code).Scan(&seq)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = tx.ExecContext(ctx, `INSERT INTO promos (code, seq, data)
+			SELECT ?, COALESCE(MAX(seq), 0) + 1, ? FROM promos`, promo.Code, string(data))
+	case err == nil:
+		_, err = tx.ExecContext(ctx, `UPDATE promos SET data = ? WHERE code = ?`, string(data), promo.Code)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save promo code %s: %w", promo.Code, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save promo code %s: %w", promo.Code, err)
+	}
+	return nil
+}
+
+// GetPromo returns the promo code
+func (r *SQLPromoRepository) GetPromo(ctx context.Context, code string) (*PromoRecord, error) {
+	ctx, span := tracer.Start(ctx, "storage.get_promo")
+	defer span.End()
+
+	promo, err := scanPromo(r.db.QueryRowContext(ctx, `SELECT data FROM promos WHERE code = ?`, code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrPromoNotFound, code)
+	}
+	return promo, err
+}
+
+// GetPromos returns every promo code in the order they were first saved
+func (r *SQLPromoRepository) GetPromos(ctx context.Context) ([]*PromoRecord, error) {
+	ctx, span := tracer.Start(ctx, "storage.get_promos")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT data FROM promos ORDER BY seq`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load promo codes: %w", err)
+	}
+	defer rows.Close()
+
+	promos := make([]*PromoRecord, 0)
+	for rows.Next() {
+		promo, err := scanPromo(rows)
+		if err != nil {
+			return nil, err
+		}
+		promos = append(promos, promo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load promo codes: %w", err)
+	}
+	return promos, nil
+}
+
+// scanPromo reads a promo code from a row of its data
+func scanPromo(row interface{ Scan(...any) error }) (*PromoRecord, error) {
+	var data string
+	if err := row.Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to load promo code: %w", err)
+	}
+	var promo PromoRecord
+	if err := json.Unmarshal([]byte(data), &promo); err != nil {
+		return nil, fmt.Errorf("failed to decode promo code: %w", err)
+	}
+	return &promo, nil
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLPromoRepository(t *testing.T) {
+	ctx := context.Background()
+	url := "sqlite://" + filepath.Join(t.TempDir(), "promos.db")
+	repo, err := OpenSQLPromoRepository(ctx, url)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+
+	// Creating the schema again leaves it as it is
+	require.NoError(t, repo.CreateSchema(ctx))
+	testPromoRepository(t, repo)
+
+	// The codes outlive the connection
+	require.NoError(t, repo.Close())
+	reopened, err := OpenSQLPromoRepository(ctx, url)
+	require.NoError(t, err)
+	t.Cleanup(func() { reopened.Close() })
+	promos, err := reopened.GetPromos(ctx)
+	require.NoError(t, err)
+	require.Len(t, promos, 3)
+	require.Equal(t, "WELCOME", promos[0].Code)
+}
@@ -0,0 +1,330 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// repositorySchema creates the tables SQLRepository stores results and
+// players in. Each is one row holding the record as JSON, with the columns
+// it is looked up and ordered by alongside.
+var repositorySchema = []string{
+	`CREATE TABLE IF NOT EXISTS results (
+	id        VARCHAR(128) PRIMARY KEY,
+	player_id VARCHAR(128) NOT NULL,
+	played_at BIGINT       NOT NULL,
+	data      TEXT         NOT NULL
+)`,
+	`CREATE TABLE IF NOT EXISTS players (
+	id   VARCHAR(128) PRIMARY KEY,
+	data TEXT         NOT NULL
+)`,
+}
+
+// SQLRepository implements game.Repository on a SQLite or MySQL database,
+// for results and players that outlive the process. Like MemoryRepository
+// it can export and import them in bulk.
+type SQLRepository struct {
+	db      *sql.DB
+	dialect SQLDialect
+}
+
+// NewSQLRepository returns a repository on db, which speaks the dialect.
+// Call CreateSchema once before using it on a new database.
+func NewSQLRepository(db *sql.DB, dialect SQLDialect) *SQLRepository {
+	return &SQLRepository{db: db, dialect: dialect}
+}
+
+// OpenSQLRepository connects to the database at the URL, as OpenSQL does,
+// and creates the schema if it is missing. Close the repository when done
+// with it.
+func OpenSQLRepository(ctx context.Context, url string) (*SQLRepository, error) {
+	db, dialect, err := OpenSQL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	repo := NewSQLRepository(db, dialect)
+	if err := repo.CreateSchema(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Close closes the repository's database
+func (r *SQLRepository) Close() error {
+	return r.db.Close()
+}
+
+// CreateSchema creates the results and players tables and their index if
+// they are missing
+func (r *SQLRepository) CreateSchema(ctx context.Context) error {
+	for _, statement := range repositorySchema {
+		if _, err := r.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create repository schema: %w", err)
+		}
+	}
+	// The index speeds up listing the most recent results
+	if err := r.dialect.createIndex(ctx, r.db, "results_played_at", "results", "played_at"); err != nil {
+		return fmt.Errorf("failed to create repository schema: %w", err)
+	}
+	return nil
+}
+
+// execer runs statements on the database or in a transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// saveResult inserts the result or replaces the one with its ID
+func (r *SQLRepository) saveResult(ctx context.Context, db execer, result *game.Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result %s: %w", result.ID, err)
+	}
+	_, err = db.ExecContext(ctx, `INSERT INTO results (id, player_id, played_at, data) VALUES (?, ?, ?, ?)`+
+		r.dialect.onConflict("id")+
+		`player_id = `+r.dialect.inserted("player_id")+`, played_at = `+r.dialect.inserted("played_at")+`, data = `+r.dialect.inserted("data"),
+		result.ID, result.PlayerID, result.Timestamp.UnixNano(), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save result %s: %w", result.ID, err)
+	}
+	return nil
+}
+
+// savePlayer inserts the player or replaces the one with their ID
+func (r *SQLRepository) savePlayer(ctx context.Context, db execer, player *game.Player) error {
+	data, err := json.Marshal(player)
+	if err != nil {
+		return fmt.Errorf("failed to encode player %s: %w", player.ID, err)
+	}
+	_, err = db.ExecContext(ctx, `INSERT INTO players (id, data) VALUES (?, ?)`+
+		r.dialect.onConflict("id")+`data = `+r.dialect.inserted("data"),
+		player.ID, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save player %s: %w", player.ID, err)
+	}
+	return nil
+}
+
+// SaveResult saves a game result to the database
+func (r *SQLRepository) SaveResult(ctx context.Context, result *game.Result) error {
+	ctx, span := tracer.Start(ctx, "storage.save_result")
+	defer span.End()
+
+	if result == nil {
+		return fmt.Errorf("result cannot be nil")
+	}
+	if result.ID == "" {
+		return fmt.Errorf("result ID cannot be empty")
+	}
+	return r.saveResult(ctx, r.db, result)
+}
+
+// GetResults retrieves the most recent game results up to the specified limit
+func (r *SQLRepository) GetResults(ctx context.Context, limit int) ([]*game.Result, error) {
+	ctx, span := tracer.Start(ctx, "storage.get_results")
+	defer span.End()
+
+	if limit <= 0 {
+		return []*game.Result{}, nil
+	}
+	return r.queryResults(ctx, `SELECT data FROM results ORDER BY played_at DESC, id LIMIT ?`, limit)
+}
+
+// queryResults reads the results a query of their data column returns
+func (r *SQLRepository) queryResults(ctx context.Context, query string, args ...any) ([]*game.Result, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load results: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*game.Result, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read result: %w", err)
+		}
+		var result game.Result
+		if err := json.Unmarshal([]byte(data), &result); err != nil {
+			return nil, fmt.Errorf("failed to decode result: %w", err)
+		}
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load results: %w", err)
+	}
+	return results, nil
+}
+
+// GetStats returns the stats kept with the player, empty for a player not
+// yet stored
+func (r *SQLRepository) GetStats(ctx context.Context, playerID string) (*game.Stats, error) {
+	ctx, span := tracer.Start(ctx, "storage.get_stats")
+	defer span.End()
+
+	if playerID == "" {
+		return nil, fmt.Errorf("player ID cannot be empty")
+	}
+	player, err := r.getPlayer(ctx, playerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &game.Stats{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &player.Stats, nil
+}
+
+// SavePlayer saves or updates a player in the database
+func (r *SQLRepository) SavePlayer(ctx context.Context, player *game.Player) error {
+	ctx, span := tracer.Start(ctx, "storage.save_player")
+	defer span.End()
+
+	if player == nil {
+		return fmt.Errorf("player cannot be nil")
+	}
+	if player.ID == "" {
+		return fmt.Errorf("player ID cannot be empty")
+	}
+	return r.savePlayer(ctx, r.db, player)
+}
+
+// GetPlayer retrieves a player by ID from the database
+func (r *SQLRepository) GetPlayer(ctx context.Context, playerID string) (*game.Player, error) {
+	ctx, span := tracer.Start(ctx, "storage.get_player")
+	defer span.End()
+
+	if playerID == "" {
+		return nil, fmt.Errorf("player ID cannot be empty")
+	}
+	player, err := r.getPlayer(ctx, playerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("player not found: %s", playerID)
+	}
+	return player, err
+}
+
+// getPlayer reads the player's row, failing with sql.ErrNoRows if there is
+// none
+func (r *SQLRepository) getPlayer(ctx context.Context, playerID string) (*game.Player, error) {
+	var data string
+	if err := r.db.QueryRowContext(ctx, `SELECT data FROM players WHERE id = ?`, playerID).Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to load player %s: %w", playerID, err)
+	}
+	var player game.Player
+	if err := json.Unmarshal([]byte(data), &player); err != nil {
+		return nil, fmt.Errorf("failed to decode player %s: %w", playerID, err)
+	}
+	return &player, nil
+}
+
+// Export returns every result, oldest first, and every player, ordered by
+// ID
+func (r *SQLRepository) Export(ctx context.Context) (*Export, error) {
+	ctx, span := tracer.Start(ctx, "storage.export")
+	defer span.End()
+
+	results, err := r.queryResults(ctx, `SELECT data FROM results ORDER BY played_at, id`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT data FROM players ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load players: %w", err)
+	}
+	defer rows.Close()
+	players := make([]*game.Player, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read player: %w", err)
+		}
+		var player game.Player
+		if err := json.Unmarshal([]byte(data), &player); err != nil {
+			return nil, fmt.Errorf("failed to decode player: %w", err)
+		}
+		players = append(players, &player)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load players: %w", err)
+	}
+
+	return &Export{
+		Version:    ExportVersion,
+		ExportedAt: time.Now(),
+		Results:    results,
+		Players:    players,
+	}, nil
+}
+
+// Import merges an export into the database in one transaction, as
+// MemoryRepository.Import merges into memory
+func (r *SQLRepository) Import(ctx context.Context, export *Export, overwrite bool) (*ImportReport, error) {
+	ctx, span := tracer.Start(ctx, "storage.import")
+	defer span.End()
+
+	if err := validateExport(export); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import: %w", err)
+	}
+	defer tx.Rollback()
+
+	exists := func(table, id string) (bool, error) {
+		var found int
+		err := tx.QueryRowContext(ctx, `SELECT 1 FROM `+table+` WHERE id = ?`+r.dialect.forUpdate(), id).Scan(&found)
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	report := &ImportReport{}
+	for _, result := range export.Results {
+		stored, err := exists("results", result.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import: %w", err)
+		}
+		if stored {
+			report.DuplicateResults++
+			continue
+		}
+		if err := r.saveResult(ctx, tx, result); err != nil {
+			return nil, err
+		}
+		report.Results++
+	}
+	for _, player := range export.Players {
+		stored, err := exists("players", player.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import: %w", err)
+		}
+		if stored && !overwrite {
+			report.KeptPlayers++
+			continue
+		}
+		if err := r.savePlayer(ctx, tx, player); err != nil {
+			return nil, err
+		}
+		report.Players++
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to import: %w", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+// newSQLiteRepository opens a repository on a new SQLite database in the
+// test's temporary directory
+func newSQLiteRepository(t *testing.T) *SQLRepository {
+	t.Helper()
+	repo, err := OpenSQLRepository(context.Background(), "sqlite://"+filepath.Join(t.TempDir(), "coinflip.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestSQLRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepository(t)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.EqualError(t, repo.SaveResult(ctx, &game.Result{}), "result ID cannot be empty")
+	for i, side := range []game.Side{game.Heads, game.Tails, game.Heads} {
+		require.NoError(t, repo.SaveResult(ctx, &game.Result{
+			ID:        string(rune('1' + i)),
+			PlayerID:  "alice",
+			Side:      side,
+			Bet:       &game.Bet{Amount: game.Dollar, Choice: game.Heads},
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	results, err := repo.GetResults(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "3", results[0].ID, "newest first")
+	assert.Equal(t, game.Dollar, results[0].Bet.Amount)
+
+	_, err = repo.GetPlayer(ctx, "alice")
+	assert.ErrorContains(t, err, "player not found")
+	stats, err := repo.GetStats(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, &game.Stats{}, stats)
+
+	player := &game.Player{ID: "alice", Balance: 120 * game.Dollar, Stats: game.Stats{GamesPlayed: 3}}
+	require.NoError(t, repo.SavePlayer(ctx, player))
+	player.Balance = 90 * game.Dollar
+	require.NoError(t, repo.SavePlayer(ctx, player))
+	stored, err := repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 90*game.Dollar, stored.Balance)
+	stats, err = repo.GetStats(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.GamesPlayed)
+}
+
+func TestSQLRepository_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	source := NewMemoryRepository()
+	require.NoError(t, source.SaveResult(ctx, &game.Result{ID: "r2", PlayerID: "alice", Side: game.Tails, Timestamp: start.Add(time.Minute), Hash: "h2"}))
+	require.NoError(t, source.SaveResult(ctx, &game.Result{ID: "r1", PlayerID: "alice", Side: game.Heads, Timestamp: start, Hash: "h1"}))
+	require.NoError(t, source.SavePlayer(ctx, &game.Player{ID: "bob", Balance: 80 * game.Dollar}))
+	require.NoError(t, source.SavePlayer(ctx, &game.Player{ID: "alice", Balance: 120 * game.Dollar}))
+	export, err := source.Export(ctx)
+	require.NoError(t, err)
+
+	// Merging keeps what the database already has
+	repo := newSQLiteRepository(t)
+	require.NoError(t, repo.SaveResult(ctx, &game.Result{ID: "r1", PlayerID: "alice", Side: game.Tails, Timestamp: start}))
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "alice", Balance: 5 * game.Dollar}))
+
+	var destination TransferRepository = repo
+	report, err := destination.Import(ctx, export, false)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportReport{Results: 1, DuplicateResults: 1, Players: 1, KeptPlayers: 1}, report)
+	alice, err := repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 5*game.Dollar, alice.Balance)
+
+	// Overwriting replaces players but never a stored result
+	report, err = destination.Import(ctx, export, true)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportReport{DuplicateResults: 2, Players: 2}, report)
+
+	exported, err := destination.Export(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ExportVersion, exported.Version)
+	require.Len(t, exported.Results, 2)
+	assert.Equal(t, "r1", exported.Results[0].ID, "results are oldest first")
+	assert.Equal(t, game.Tails, exported.Results[0].Side)
+	assert.Equal(t, "h2", exported.Results[1].Hash)
+	require.Len(t, exported.Players, 2)
+	assert.Equal(t, "alice", exported.Players[0].ID, "players are ordered by ID")
+	assert.Equal(t, 120*game.Dollar, exported.Players[0].Balance)
+
+	// Nothing is imported from an export with a record missing its ID
+	_, err = destination.Import(ctx, &Export{Results: []*game.Result{{ID: "r3"}, {}}}, false)
+	assert.Error(t, err)
+	results, err := repo.GetResults(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+}
@@ -0,0 +1,322 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// roundStatsSchema creates the tables SQLRoundRepository keeps its stats
+// in. They are updated with every saved round, so stats queries read a row
+// per player or day however many rounds are stored; RefreshStats rebuilds
+// them from the rounds table. The leaderboard reads round_player_stats by
+// its net column, which CreateSchema indexes.
+var roundStatsSchema = []string{
+	`CREATE TABLE IF NOT EXISTS round_player_stats (
+	player_id     VARCHAR(128) PRIMARY KEY,
+	player_name   VARCHAR(128) NOT NULL,
+	rounds        BIGINT       NOT NULL,
+	wins          BIGINT       NOT NULL,
+	bets          BIGINT       NOT NULL,
+	wagered       BIGINT       NOT NULL,
+	paid_out      BIGINT       NOT NULL,
+	net           BIGINT       NOT NULL,
+	last_round_at BIGINT       NOT NULL
+)`,
+	`CREATE TABLE IF NOT EXISTS round_daily_volume (
+	day      VARCHAR(10) PRIMARY KEY,
+	rounds   BIGINT      NOT NULL,
+	bets     BIGINT      NOT NULL,
+	wagered  BIGINT      NOT NULL,
+	paid_out BIGINT      NOT NULL
+)`,
+}
+
+const playerStatsColumns = `player_id, player_name, rounds, wins, bets, wagered, paid_out, net, last_round_at`
+
+const dailyVolumeColumns = `day, rounds, bets, wagered, paid_out`
+
+// createStatsSchema creates the stats tables and their index if they are
+// missing
+func (r *SQLRoundRepository) createStatsSchema(ctx context.Context) error {
+	for _, statement := range roundStatsSchema {
+		if _, err := r.db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return r.dialect.createIndex(ctx, r.db, "round_player_stats_net", "round_player_stats", "net")
+}
+
+// updateRoundStats adds a round's outcomes to the stats tables, or takes
+// them away when sign is -1 because the round is being replaced. A round
+// taken away leaves player names and last round times alone; RefreshStats
+// recomputes those.
+func (r *SQLRoundRepository) updateRoundStats(ctx context.Context, tx *sql.Tx, round *Round, sign int64) error {
+	stats := newRoundStats()
+	stats.add(round)
+
+	for _, player := range stats.players {
+		if sign < 0 {
+			if err := removePlayerStats(ctx, tx, player); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.addPlayerStats(ctx, tx, player); err != nil {
+			return err
+		}
+	}
+	for _, day := range stats.days {
+		if sign < 0 {
+			if err := removeDailyVolume(ctx, tx, day); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.addDailyVolume(ctx, tx, day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addPlayerStats inserts a player's row to the stats or, if they have one,
+// adds to it in the same statement. The name and last round time are only
+// taken from the added stats if they are the player's newest; the name is
+// assigned first, as MySQL would otherwise compare against the new time.
+func (r *SQLRoundRepository) addPlayerStats(ctx context.Context, tx *sql.Tx, player *PlayerRoundStats) error {
+	newer := `round_player_stats.last_round_at <= ` + r.dialect.inserted("last_round_at")
+	add := func(column string) string {
+		return column + ` = round_player_stats.` + column + ` + ` + r.dialect.inserted(column)
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO round_player_stats (`+playerStatsColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`+
+		r.dialect.onConflict("player_id")+
+		`player_name = CASE WHEN `+newer+` THEN `+r.dialect.inserted("player_name")+` ELSE round_player_stats.player_name END, `+
+		strings.Join([]string{add("rounds"), add("wins"), add("bets"), add("wagered"), add("paid_out"), add("net")}, `, `)+`, `+
+		`last_round_at = CASE WHEN `+newer+` THEN `+r.dialect.inserted("last_round_at")+` ELSE round_player_stats.last_round_at END`,
+		player.PlayerID, player.PlayerName, player.Rounds, player.Wins, player.Bets,
+		int64(player.Wagered), int64(player.PaidOut), int64(player.Net), player.LastRoundAt.UnixNano(),
+	)
+	return err
+}
+
+// removePlayerStats takes a player's stats away from their row, dropping
+// the row once no rounds are left in it
+func removePlayerStats(ctx context.Context, tx *sql.Tx, player *PlayerRoundStats) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE round_player_stats SET
+		rounds = rounds - ?, wins = wins - ?, bets = bets - ?,
+		wagered = wagered - ?, paid_out = paid_out - ?, net = net - ?
+		WHERE player_id = ?`,
+		player.Rounds, player.Wins, player.Bets,
+		int64(player.Wagered), int64(player.PaidOut), int64(player.Net),
+		player.PlayerID,
+	); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `DELETE FROM round_player_stats WHERE player_id = ? AND rounds <= 0`, player.PlayerID)
+	return err
+}
+
+// addDailyVolume inserts a day's row to the stats or, if it has one, adds
+// to it in the same statement
+func (r *SQLRoundRepository) addDailyVolume(ctx context.Context, tx *sql.Tx, day *DailyVolume) error {
+	add := func(column string) string {
+		return column + ` = round_daily_volume.` + column + ` + ` + r.dialect.inserted(column)
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO round_daily_volume (`+dailyVolumeColumns+`) VALUES (?, ?, ?, ?, ?)`+
+		r.dialect.onConflict("day")+
+		strings.Join([]string{add("rounds"), add("bets"), add("wagered"), add("paid_out")}, `, `),
+		day.Day, day.Rounds, day.Bets, int64(day.Wagered), int64(day.PaidOut),
+	)
+	return err
+}
+
+// removeDailyVolume takes a day's volume away from its row, dropping the
+// row once no rounds are left in it
+func removeDailyVolume(ctx context.Context, tx *sql.Tx, day *DailyVolume) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE round_daily_volume SET
+		rounds = rounds - ?, bets = bets - ?, wagered = wagered - ?, paid_out = paid_out - ?
+		WHERE day = ?`,
+		day.Rounds, day.Bets, int64(day.Wagered), int64(day.PaidOut),
+		day.Day,
+	); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `DELETE FROM round_daily_volume WHERE day = ? AND rounds <= 0`, day.Day)
+	return err
+}
+
+// insertPlayerStats adds a player's row to the stats
+func insertPlayerStats(ctx context.Context, tx *sql.Tx, player *PlayerRoundStats) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO round_player_stats (`+playerStatsColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		player.PlayerID, player.PlayerName, player.Rounds, player.Wins, player.Bets,
+		int64(player.Wagered), int64(player.PaidOut), int64(player.Net), player.LastRoundAt.UnixNano(),
+	)
+	return err
+}
+
+// insertDailyVolume adds a day's row to the stats
+func insertDailyVolume(ctx context.Context, tx *sql.Tx, day *DailyVolume) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO round_daily_volume (`+dailyVolumeColumns+`) VALUES (?, ?, ?, ?, ?)`,
+		day.Day, day.Rounds, day.Bets, int64(day.Wagered), int64(day.PaidOut),
+	)
+	return err
+}
+
+// RefreshStats rebuilds the stats tables from every stored round, for a
+// database whose rounds were written before the tables existed or changed
+// behind the repository's back. Stats queries see the old tables until it
+// commits.
+func (r *SQLRoundRepository) RefreshStats(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "storage.refresh_stats")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to refresh stats: %w", err)
+	}
+	defer tx.Rollback()
+
+	stats, err := sumRounds(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh stats: %w", err)
+	}
+
+	for _, statement := range []string{`DELETE FROM round_player_stats`, `DELETE FROM round_daily_volume`} {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to refresh stats: %w", err)
+		}
+	}
+	for _, player := range stats.players {
+		if err := insertPlayerStats(ctx, tx, player); err != nil {
+			return fmt.Errorf("failed to refresh stats: %w", err)
+		}
+	}
+	for _, day := range stats.days {
+		if err := insertDailyVolume(ctx, tx, day); err != nil {
+			return fmt.Errorf("failed to refresh stats: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to refresh stats: %w", err)
+	}
+	return nil
+}
+
+// sumRounds reads every stored round, one at a time, into stats
+func sumRounds(ctx context.Context, tx *sql.Tx) (*roundStats, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT `+roundColumns+` FROM rounds`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := newRoundStats()
+	for rows.Next() {
+		round, err := scanRound(rows)
+		if err != nil {
+			return nil, err
+		}
+		stats.add(round)
+	}
+	return stats, rows.Err()
+}
+
+// PlayerRoundStats reads the player's row of the stats
+func (r *SQLRoundRepository) PlayerRoundStats(ctx context.Context, playerID string) (*PlayerRoundStats, error) {
+	ctx, span := tracer.Start(ctx, "storage.player_round_stats")
+	defer span.End()
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+playerStatsColumns+` FROM round_player_stats WHERE player_id = ?`, playerID)
+	player, err := scanPlayerStats(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &PlayerRoundStats{PlayerID: playerID}, nil
+	}
+	return player, err
+}
+
+// RoundLeaderboard reads the players with the best net result from the
+// stats
+func (r *SQLRoundRepository) RoundLeaderboard(ctx context.Context, limit int) ([]PlayerRoundStats, error) {
+	ctx, span := tracer.Start(ctx, "storage.round_leaderboard")
+	defer span.End()
+
+	query := `SELECT ` + playerStatsColumns + ` FROM round_player_stats ORDER BY net DESC, player_id`
+	var args []any
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	players := make([]PlayerRoundStats, 0)
+	for rows.Next() {
+		player, err := scanPlayerStats(rows)
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, *player)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load leaderboard: %w", err)
+	}
+	return players, nil
+}
+
+// DailyVolume reads the days from from to to from the stats
+func (r *SQLRoundRepository) DailyVolume(ctx context.Context, from, to time.Time) ([]DailyVolume, error) {
+	ctx, span := tracer.Start(ctx, "storage.daily_volume")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+dailyVolumeColumns+` FROM round_daily_volume WHERE day >= ? AND day <= ? ORDER BY day`,
+		from.UTC().Format(DayLayout), to.UTC().Format(DayLayout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load daily volume: %w", err)
+	}
+	defer rows.Close()
+
+	days := make([]DailyVolume, 0)
+	for rows.Next() {
+		var day DailyVolume
+		var wagered, paidOut int64
+		if err := rows.Scan(&day.Day, &day.Rounds, &day.Bets, &wagered, &paidOut); err != nil {
+			return nil, fmt.Errorf("failed to read daily volume: %w", err)
+		}
+		day.Wagered, day.PaidOut = game.Money(wagered), game.Money(paidOut)
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load daily volume: %w", err)
+	}
+	return days, nil
+}
+
+// scanPlayerStats reads a player's stats from a row of playerStatsColumns
+func scanPlayerStats(row interface{ Scan(...any) error }) (*PlayerRoundStats, error) {
+	var (
+		player                PlayerRoundStats
+		wagered, paidOut, net int64
+		lastRoundAt           int64
+	)
+	if err := row.Scan(&player.PlayerID, &player.PlayerName, &player.Rounds, &player.Wins, &player.Bets,
+		&wagered, &paidOut, &net, &lastRoundAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read player stats: %w", err)
+	}
+
+	player.Wagered, player.PaidOut, player.Net = game.Money(wagered), game.Money(paidOut), game.Money(net)
+	player.LastRoundAt = time.Unix(0, lastRoundAt).UTC()
+	return &player, nil
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// roundsSchema creates the table SQLRoundRepository stores rounds in. Each
+// round is one row with its outcomes as JSON, since they are only ever
+// read back whole.
+const roundsSchema = `CREATE TABLE IF NOT EXISTS rounds (
+	id          VARCHAR(128) PRIMARY KEY,
+	room_id     VARCHAR(128) NOT NULL,
+	coin_result VARCHAR(8)   NOT NULL,
+	final_seed  VARCHAR(128) NOT NULL,
+	started_at  BIGINT       NOT NULL,
+	settled_at  BIGINT       NOT NULL,
+	outcomes    TEXT         NOT NULL
+)`
+
+const roundColumns = `id, room_id, coin_result, final_seed, started_at, settled_at, outcomes`
+
+// SQLRoundRepository implements RoundRepository on a SQLite or MySQL
+// database. Times are stored as Unix nanoseconds so either driver can scan
+// them.
+type SQLRoundRepository struct {
+	db      *sql.DB
+	dialect SQLDialect
+}
+
+// NewSQLRoundRepository returns a round repository on db, which speaks the
+// dialect. Call CreateSchema once before using it on a new database.
+func NewSQLRoundRepository(db *sql.DB, dialect SQLDialect) *SQLRoundRepository {
+	return &SQLRoundRepository{db: db, dialect: dialect}
+}
+
+// OpenSQLRoundRepository connects to the database at the URL, as OpenSQL
+// does, and creates the schema if it is missing. Close the repository when
+// done with it.
+func OpenSQLRoundRepository(ctx context.Context, url string) (*SQLRoundRepository, error) {
+	db, dialect, err := OpenSQL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	repo := NewSQLRoundRepository(db, dialect)
+	if err := repo.CreateSchema(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Close closes the repository's database
+func (r *SQLRoundRepository) Close() error {
+	return r.db.Close()
+}
+
+// CreateSchema creates the rounds table, its index and the stats tables if
+// they are missing. Rounds already stored when the stats tables are created
+// are only counted after RefreshStats.
+func (r *SQLRoundRepository) CreateSchema(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, roundsSchema); err != nil {
+		return fmt.Errorf("failed to create rounds schema: %w", err)
+	}
+	// The index speeds up listing a room's recent rounds
+	if err := r.dialect.createIndex(ctx, r.db, "rounds_room_settled", "rounds", "room_id, settled_at"); err != nil {
+		return fmt.Errorf("failed to create rounds schema: %w", err)
+	}
+	if err := r.createStatsSchema(ctx); err != nil {
+		return fmt.Errorf("failed to create rounds schema: %w", err)
+	}
+	return nil
+}
+
+// SaveRound inserts the round or replaces the one with its ID, updating the
+// stats tables in the same transaction
+func (r *SQLRoundRepository) SaveRound(ctx context.Context, round *Round) error {
+	ctx, span := tracer.Start(ctx, "storage.save_round")
+	defer span.End()
+
+	if err := validateRound(round); err != nil {
+		return err
+	}
+	outcomes, err := json.Marshal(round.Outcomes)
+	if err != nil {
+		return fmt.Errorf("failed to encode round %s: %w", round.ID, err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save round %s: %w", round.ID, err)
+	}
+	defer tx.Rollback()
+
+	// A replaced round's outcomes come out of the stats first. The row stays
+	// locked so a concurrent save of the round waits to see this one's.
+	old, err := scanRound(tx.QueryRowContext(ctx, `SELECT `+roundColumns+` FROM rounds WHERE id = ?`+r.dialect.forUpdate(), round.ID))
+	switch {
+	case err == nil:
+		if err := r.updateRoundStats(ctx, tx, old, -1); err != nil {
+			return fmt.Errorf("failed to save round %s: %w", round.ID, err)
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("failed to save round %s: %w", round.ID, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO rounds (`+roundColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`+
+		r.dialect.onConflict("id")+r.replaceColumns("room_id", "coin_result", "final_seed", "started_at", "settled_at", "outcomes"),
+		round.ID, round.RoomID, string(round.CoinResult), round.FinalSeed,
+		round.StartedAt.UnixNano(), round.SettledAt.UnixNano(), string(outcomes),
+	); err != nil {
+		return fmt.Errorf("failed to save round %s: %w", round.ID, err)
+	}
+	if err := r.updateRoundStats(ctx, tx, round, 1); err != nil {
+		return fmt.Errorf("failed to save round %s: %w", round.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save round %s: %w", round.ID, err)
+	}
+	return nil
+}
+
+// replaceColumns is an upsert's update setting each column to the value
+// inserted
+func (r *SQLRoundRepository) replaceColumns(columns ...string) string {
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + ` = ` + r.dialect.inserted(column)
+	}
+	return strings.Join(assignments, `, `)
+}
+
+// GetRounds returns the room's most recent rounds
+func (r *SQLRoundRepository) GetRounds(ctx context.Context, roomID string, limit int) ([]*Round, error) {
+	ctx, span := tracer.Start(ctx, "storage.get_rounds")
+	defer span.End()
+
+	query := `SELECT ` + roundColumns + ` FROM rounds`
+	var args []any
+	if roomID != "" {
+		query += ` WHERE room_id = ?`
+		args = append(args, roomID)
+	}
+	query += ` ORDER BY settled_at DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rounds: %w", err)
+	}
+	defer rows.Close()
+
+	rounds := make([]*Round, 0)
+	for rows.Next() {
+		round, err := scanRound(rows)
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, round)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load rounds: %w", err)
+	}
+	return rounds, nil
+}
+
+// GetRoundByID returns the round with the ID
+func (r *SQLRoundRepository) GetRoundByID(ctx context.Context, id string) (*Round, error) {
+	ctx, span := tracer.Start(ctx, "storage.get_round")
+	defer span.End()
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+roundColumns+` FROM rounds WHERE id = ?`, id)
+	round, err := scanRound(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrRoundNotFound, id)
+	}
+	return round, err
+}
+
+// scanRound reads a round from a row of roundColumns
+func scanRound(row interface{ Scan(...any) error }) (*Round, error) {
+	var (
+		round      Round
+		coinResult string
+		startedAt  int64
+		settledAt  int64
+		outcomes   string
+	)
+	if err := row.Scan(&round.ID, &round.RoomID, &coinResult, &round.FinalSeed,
+		&startedAt, &settledAt, &outcomes); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read round: %w", err)
+	}
+
+	round.CoinResult = game.Side(coinResult)
+	round.StartedAt = time.Unix(0, startedAt).UTC()
+	round.SettledAt = time.Unix(0, settledAt).UTC()
+	if err := json.Unmarshal([]byte(outcomes), &round.Outcomes); err != nil {
+		return nil, fmt.Errorf("failed to decode round %s: %w", round.ID, err)
+	}
+	return &round, nil
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"coinflip-game/internal/game"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteRoundRepository opens a round repository on a new SQLite
+// database in the test's temporary directory
+func newSQLiteRoundRepository(t *testing.T) *SQLRoundRepository {
+	t.Helper()
+	url := "sqlite://" + filepath.Join(t.TempDir(), "rounds.db")
+	repo, err := OpenSQLRoundRepository(context.Background(), url)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestOpenSQL(t *testing.T) {
+	ctx := context.Background()
+
+	_, _, err := OpenSQL(ctx, "rounds.db")
+	assert.Error(t, err)
+	_, _, err = OpenSQL(ctx, "postgres://localhost/coinflip")
+	assert.ErrorContains(t, err, "unsupported database")
+
+	db, dialect, err := OpenSQL(ctx, "sqlite://"+filepath.Join(t.TempDir(), "coinflip.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	assert.Equal(t, DialectSQLite, dialect)
+}
+
+func TestSQLRoundRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRoundRepository(t)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Creating the schema again leaves it as it is
+	require.NoError(t, repo.CreateSchema(ctx))
+
+	assert.EqualError(t, repo.SaveRound(ctx, nil), "round cannot be nil")
+	for i, roomID := range []string{"a", "b", "a", "a"} {
+		require.NoError(t, repo.SaveRound(ctx, &Round{
+			ID:         string(rune('1' + i)),
+			RoomID:     roomID,
+			CoinResult: game.Heads,
+			FinalSeed:  "seed",
+			StartedAt:  start,
+			SettledAt:  start.Add(time.Duration(i) * time.Minute),
+			Outcomes: []RoundOutcome{{
+				PlayerID: "p1",
+				Bets:     []RoundBet{{ID: "bet", Choice: game.Heads, Amount: game.Dollar}},
+				Won:      true,
+				Payout:   2 * game.Dollar,
+			}},
+		}))
+	}
+
+	rounds, err := repo.GetRounds(ctx, "a", 2)
+	require.NoError(t, err)
+	require.Len(t, rounds, 2)
+	assert.Equal(t, "4", rounds[0].ID, "newest first")
+	assert.Equal(t, "3", rounds[1].ID)
+	assert.Equal(t, game.Dollar, rounds[0].Outcomes[0].Bets[0].Amount)
+	assert.Equal(t, start.Add(3*time.Minute), rounds[0].SettledAt)
+
+	all, err := repo.GetRounds(ctx, "", 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	// Saving a round again replaces it
+	replaced := all[0]
+	replaced.CoinResult = game.Tails
+	replaced.Outcomes[0].Won, replaced.Outcomes[0].Payout = false, 0
+	require.NoError(t, repo.SaveRound(ctx, replaced))
+	round, err := repo.GetRoundByID(ctx, replaced.ID)
+	require.NoError(t, err)
+	assert.Equal(t, game.Tails, round.CoinResult)
+	assert.False(t, round.Outcomes[0].Won)
+	all, err = repo.GetRounds(ctx, "", 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	_, err = repo.GetRoundByID(ctx, "missing")
+	assert.ErrorIs(t, err, ErrRoundNotFound)
+}
+
+func TestSQLRoundRepository_ConcurrentSaves(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRoundRepository(t)
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Saves of the same round wait for each other rather than each taking
+	// it for new
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.SaveRound(ctx, statsRound("1", day, []string{"p1"}))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	p1, err := repo.PlayerRoundStats(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, p1.Rounds)
+	assert.Equal(t, 10*game.Dollar, p1.Net)
+}
+
+func TestSQLRoundRepository_Stats(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRoundRepository(t)
+	memory := NewMemoryRoundRepository()
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	renamed := statsRound("3", day.AddDate(0, 0, 1), nil, "p1", "p3")
+	renamed.Outcomes[0].PlayerName = "Renamed"
+	practice := statsRound("4", day.AddDate(0, 0, 2), []string{"p1"})
+	practice.Outcomes[0].Practice = true
+	// Saved out of order, so the newest round's name is not the last saved
+	for _, round := range []*Round{
+		renamed,
+		statsRound("1", day, []string{"p1"}, "p2"),
+		statsRound("2", day.Add(time.Hour), []string{"p1", "p2"}),
+		practice,
+	} {
+		require.NoError(t, repo.SaveRound(ctx, round))
+		require.NoError(t, memory.SaveRound(ctx, round))
+	}
+
+	// The tables kept up to date with each round agree with stats summed
+	// from the rounds
+	assertSameStats := func() {
+		t.Helper()
+		for _, playerID := range []string{"p1", "p2", "p3", "nobody"} {
+			want, err := memory.PlayerRoundStats(ctx, playerID)
+			require.NoError(t, err)
+			got, err := repo.PlayerRoundStats(ctx, playerID)
+			require.NoError(t, err)
+			assert.Equal(t, want, got, playerID)
+		}
+		want, err := memory.RoundLeaderboard(ctx, 2)
+		require.NoError(t, err)
+		got, err := repo.RoundLeaderboard(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		wantVolume, err := memory.DailyVolume(ctx, day.AddDate(0, 0, -7), day.AddDate(0, 0, 7))
+		require.NoError(t, err)
+		gotVolume, err := repo.DailyVolume(ctx, day.AddDate(0, 0, -7), day.AddDate(0, 0, 7))
+		require.NoError(t, err)
+		assert.Equal(t, wantVolume, gotVolume)
+	}
+	assertSameStats()
+	p1, err := repo.PlayerRoundStats(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", p1.PlayerName)
+
+	// Replacing a round replaces what it adds; the name and last round time
+	// it leaves behind wait for a refresh
+	replacement := statsRound("3", day.AddDate(0, 0, 1), []string{"p3"})
+	require.NoError(t, repo.SaveRound(ctx, replacement))
+	require.NoError(t, memory.SaveRound(ctx, replacement))
+	p1, err = repo.PlayerRoundStats(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, p1.Rounds)
+	assert.Equal(t, "Renamed", p1.PlayerName)
+	assert.Equal(t, day.AddDate(0, 0, 1), p1.LastRoundAt)
+
+	require.NoError(t, repo.RefreshStats(ctx))
+	assertSameStats()
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"coinflip-game/internal/game"
+)
+
+// WithTransaction runs fn under the repository's write lock, against a
+// view that holds fn's writes back until it returns nil. They are then all
+// applied at once; if fn fails, or the context is done by then, none are.
+// fn must use only tx, since the repository itself is locked.
+func (r *MemoryRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx game.Repository) error) error {
+	ctx, span := tracer.Start(ctx, "storage.transaction")
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx := &memoryTx{
+		repo:    r,
+		players: make(map[string]*game.Player),
+		results: make(map[string]*game.Result),
+	}
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for id, player := range tx.players {
+		r.players[id] = player
+		r.touchPlayer(id)
+	}
+	for id, result := range tx.results {
+		r.results[id] = result
+		r.trackResult(id, result.Timestamp)
+	}
+	r.enforceLimits()
+	return nil
+}
+
+// memoryTx is a MemoryRepository transaction. Its writes are staged, and
+// its reads see them over the repository's data. The repository's write
+// lock is held for as long as it is in use.
+type memoryTx struct {
+	repo    *MemoryRepository
+	players map[string]*game.Player
+	results map[string]*game.Result
+}
+
+// SaveResult stages a result
+func (tx *memoryTx) SaveResult(ctx context.Context, result *game.Result) error {
+	if result == nil {
+		return fmt.Errorf("result cannot be nil")
+	}
+	if result.ID == "" {
+		return fmt.Errorf("result ID cannot be empty")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.results[result.ID] = copyResult(result)
+	return nil
+}
+
+// GetResults returns the most recent results, staged ones included
+func (tx *memoryTx) GetResults(ctx context.Context, limit int) ([]*game.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		return []*game.Result{}, nil
+	}
+
+	results := make([]*game.Result, 0, len(tx.repo.results)+len(tx.results))
+	for id, result := range tx.repo.results {
+		if _, staged := tx.results[id]; !staged {
+			results = append(results, copyResult(result))
+		}
+	}
+	for _, result := range tx.results {
+		results = append(results, copyResult(result))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Timestamp.After(results[j].Timestamp)
+	})
+	return results[:min(limit, len(results))], nil
+}
+
+// GetStats returns the player's stats, or empty stats for a new player
+func (tx *memoryTx) GetStats(ctx context.Context, playerID string) (*game.Stats, error) {
+	if playerID == "" {
+		return nil, fmt.Errorf("player ID cannot be empty")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	player := tx.player(playerID)
+	if player == nil {
+		return &game.Stats{}, nil
+	}
+	stats := player.Stats
+	return &stats, nil
+}
+
+// SavePlayer stages a player
+func (tx *memoryTx) SavePlayer(ctx context.Context, player *game.Player) error {
+	if player == nil {
+		return fmt.Errorf("player cannot be nil")
+	}
+	if player.ID == "" {
+		return fmt.Errorf("player ID cannot be empty")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx.players[player.ID] = copyPlayer(player)
+	return nil
+}
+
+// GetPlayer returns a copy of the player as staged, or as stored
+func (tx *memoryTx) GetPlayer(ctx context.Context, playerID string) (*game.Player, error) {
+	if playerID == "" {
+		return nil, fmt.Errorf("player ID cannot be empty")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	player := tx.player(playerID)
+	if player == nil {
+		return nil, fmt.Errorf("player not found: %s", playerID)
+	}
+	return copyPlayer(player), nil
+}
+
+// player returns the staged player, or the stored one, without copying
+func (tx *memoryTx) player(playerID string) *game.Player {
+	if player, staged := tx.players[playerID]; staged {
+		return player
+	}
+	return tx.repo.players[playerID]
+}
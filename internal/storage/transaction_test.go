@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/game"
+)
+
+func TestMemoryRepository_WithTransaction(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	require.NoError(t, repo.SavePlayer(ctx, &game.Player{ID: "alice", Balance: 100 * game.Dollar}))
+
+	result := &game.Result{ID: "r1", PlayerID: "alice", Side: game.Heads, Timestamp: time.Now()}
+	failed := errors.New("failed")
+	err := repo.WithTransaction(ctx, func(ctx context.Context, tx game.Repository) error {
+		require.NoError(t, tx.SavePlayer(ctx, &game.Player{ID: "alice", Balance: 120 * game.Dollar}))
+		require.NoError(t, tx.SaveResult(ctx, result))
+
+		// Reads see the transaction's own writes
+		player, err := tx.GetPlayer(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, 120*game.Dollar, player.Balance)
+		results, err := tx.GetResults(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+
+	// A failed transaction changes nothing
+	player, err := repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 100*game.Dollar, player.Balance)
+	assert.Zero(t, repo.GetResultCount())
+
+	err = repo.WithTransaction(ctx, func(ctx context.Context, tx game.Repository) error {
+		if err := tx.SavePlayer(ctx, &game.Player{ID: "alice", Balance: 120 * game.Dollar}); err != nil {
+			return err
+		}
+		return tx.SaveResult(ctx, result)
+	})
+	require.NoError(t, err)
+	player, err = repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 120*game.Dollar, player.Balance)
+	assert.Equal(t, 1, repo.GetResultCount())
+
+	// Nor does one whose context is done before it commits
+	cancelled, cancel := context.WithCancel(ctx)
+	err = repo.WithTransaction(cancelled, func(ctx context.Context, tx game.Repository) error {
+		cancel()
+		return tx.SavePlayer(context.Background(), &game.Player{ID: "bob", Balance: game.Dollar})
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, repo.GetPlayerCount())
+}
+
+func TestMemoryRepository_EngineSettlesInTransaction(t *testing.T) {
+	repo := NewMemoryRepository()
+	var _ game.Transactor = repo
+
+	config := game.Config{StartingBalance: 1000 * game.Dollar, MinBet: game.Dollar, MaxBet: 100 * game.Dollar, PayoutRatio: 2.0}
+	engine := game.NewEngine(config, repo, game.NewDefaultRandomGenerator(), zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	session := engine.NewSession("alice")
+	_, err := session.PlaceBet(ctx, 10*game.Dollar, game.Heads)
+	require.NoError(t, err)
+	result, err := session.FlipCoin(ctx)
+	require.NoError(t, err)
+
+	player, err := repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, result.Hash, player.ChainHead)
+	assert.Equal(t, 1, player.Stats.GamesPlayed)
+	assert.Equal(t, 1, repo.GetResultCount())
+}
+
+// interruptedRepository is a MemoryRepository whose transactions fail
+// after their writes are made, before they commit
+type interruptedRepository struct {
+	*MemoryRepository
+}
+
+func (r interruptedRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx game.Repository) error) error {
+	return r.MemoryRepository.WithTransaction(ctx, func(ctx context.Context, tx game.Repository) error {
+		if err := fn(ctx, tx); err != nil {
+			return err
+		}
+		return errors.New("connection lost")
+	})
+}
+
+func TestMemoryRepository_EnginePlacesInTransaction(t *testing.T) {
+	repo := NewMemoryRepository()
+	config := game.Config{StartingBalance: 1000 * game.Dollar, MinBet: game.Dollar, MaxBet: 100 * game.Dollar, PayoutRatio: 2.0}
+	ctx := context.Background()
+
+	// A placement failing between its writes and their commit takes
+	// nothing and records no bet
+	interrupted := game.NewEngine(config, interruptedRepository{repo}, game.NewDefaultRandomGenerator(), zaptest.NewLogger(t))
+	session := interrupted.NewSession("alice")
+	_, err := session.PlaceBet(ctx, 10*game.Dollar, game.Heads)
+	assert.ErrorContains(t, err, "connection lost")
+	assert.Nil(t, session.CurrentBet())
+	player, err := repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1000*game.Dollar, player.Balance)
+	assert.Empty(t, player.OpenBets)
+
+	// The stake and the open bet are saved together, and the bet is closed
+	// when it settles or is refunded
+	engine := game.NewEngine(config, repo, game.NewDefaultRandomGenerator(), zaptest.NewLogger(t))
+	session = engine.NewSession("alice")
+	bet, err := session.PlaceBet(ctx, 10*game.Dollar, game.Heads)
+	require.NoError(t, err)
+	player, err = repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 990*game.Dollar, player.Balance)
+	require.Len(t, player.OpenBets, 1)
+	assert.Equal(t, bet.ID, player.OpenBets[0].ID)
+
+	_, err = session.FlipCoin(ctx)
+	require.NoError(t, err)
+	player, err = repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, player.OpenBets)
+
+	_, err = session.PlaceBet(ctx, 10*game.Dollar, game.Tails)
+	require.NoError(t, err)
+	require.NoError(t, session.CancelBet(ctx))
+	player, err = repo.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, player.OpenBets)
+}
@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// ExportVersion is the version of the export format written by WriteExport
+const ExportVersion = 1
+
+// Export is a copy of a repository's results and players that can be
+// imported into another, such as when moving to another storage backend or
+// machine
+type Export struct {
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Results    []*game.Result `json:"results"`
+	Players    []*game.Player `json:"players"`
+}
+
+// ImportReport counts what an import added and what it left as it was
+type ImportReport struct {
+	// Results were added; DuplicateResults were already stored under the
+	// same ID and left alone
+	Results          int `json:"results"`
+	DuplicateResults int `json:"duplicate_results"`
+	// Players were added or, when overwriting, replaced; KeptPlayers were
+	// already stored and kept
+	Players     int `json:"players"`
+	KeptPlayers int `json:"kept_players"`
+}
+
+// TransferRepository is a repository whose results and players can be
+// copied out and merged in bulk, such as when switching storage backends.
+// MemoryRepository and SQLRepository implement it.
+type TransferRepository interface {
+	game.Repository
+	// Export returns every result, oldest first, and every player, ordered
+	// by ID
+	Export(ctx context.Context) (*Export, error)
+	// Import merges an export in all at once. Results are added unless one
+	// with the same ID is already stored. Players already stored are kept
+	// as they are, or replaced by the exported ones when overwrite is set.
+	// Nothing is imported if any result or player lacks an ID.
+	Import(ctx context.Context, export *Export, overwrite bool) (*ImportReport, error)
+}
+
+// validateExport checks that every result and player in an export can be
+// stored
+func validateExport(export *Export) error {
+	if export == nil {
+		return fmt.Errorf("export cannot be nil")
+	}
+	for _, result := range export.Results {
+		if result == nil || result.ID == "" {
+			return fmt.Errorf("cannot import result without ID")
+		}
+	}
+	for _, player := range export.Players {
+		if player == nil || player.ID == "" {
+			return fmt.Errorf("cannot import player without ID")
+		}
+	}
+	return nil
+}
+
+// Export returns a copy of every result, oldest first, and every player,
+// ordered by ID
+func (r *MemoryRepository) Export(ctx context.Context) (*Export, error) {
+	_, span := tracer.Start(ctx, "storage.export")
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	export := &Export{
+		Version:    ExportVersion,
+		ExportedAt: time.Now(),
+		Results:    make([]*game.Result, 0, len(r.results)),
+		Players:    make([]*game.Player, 0, len(r.players)),
+	}
+	for _, result := range r.results {
+		export.Results = append(export.Results, copyResult(result))
+	}
+	for _, player := range r.players {
+		export.Players = append(export.Players, copyPlayer(player))
+	}
+	sort.Slice(export.Results, func(i, j int) bool {
+		return export.Results[i].Timestamp.Before(export.Results[j].Timestamp)
+	})
+	sort.Slice(export.Players, func(i, j int) bool {
+		return export.Players[i].ID < export.Players[j].ID
+	})
+	return export, nil
+}
+
+// Import merges an export into the repository under one lock, as
+// TransferRepository describes
+func (r *MemoryRepository) Import(ctx context.Context, export *Export, overwrite bool) (*ImportReport, error) {
+	_, span := tracer.Start(ctx, "storage.import")
+	defer span.End()
+
+	if err := validateExport(export); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{}
+	for _, result := range export.Results {
+		if _, exists := r.results[result.ID]; exists {
+			report.DuplicateResults++
+			continue
+		}
+		r.results[result.ID] = copyResult(result)
+		r.trackResult(result.ID, result.Timestamp)
+		report.Results++
+	}
+	for _, player := range export.Players {
+		if _, exists := r.players[player.ID]; exists && !overwrite {
+			report.KeptPlayers++
+			continue
+		}
+		r.players[player.ID] = copyPlayer(player)
+		r.touchPlayer(player.ID)
+		report.Players++
+	}
+	r.enforceLimits()
+	return report, nil
+}
+
+// WriteExport writes an export to a JSON file, through a temporary file so
+// a crash never leaves half an export behind
+func WriteExport(path string, export *Export) error {
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// ReadExport reads an export file written by WriteExport
+func ReadExport(path string) (*Export, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var export Export
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to decode export: %w", err)
+	}
+	if export.Version < 1 || export.Version > ExportVersion {
+		return nil, fmt.Errorf("%s is not an export this version can read (version %d)", path, export.Version)
+	}
+	return &export, nil
+}
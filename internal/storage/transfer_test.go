@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+func TestMemoryRepository_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	source := NewMemoryRepository()
+	require.NoError(t, source.SaveResult(ctx, &game.Result{ID: "r2", PlayerID: "alice", Side: game.Tails, Timestamp: start.Add(time.Minute)}))
+	require.NoError(t, source.SaveResult(ctx, &game.Result{ID: "r1", PlayerID: "alice", Side: game.Heads, Timestamp: start}))
+	require.NoError(t, source.SavePlayer(ctx, &game.Player{ID: "bob", Balance: 80 * game.Dollar}))
+	require.NoError(t, source.SavePlayer(ctx, &game.Player{ID: "alice", Balance: 120 * game.Dollar}))
+
+	export, err := source.Export(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ExportVersion, export.Version)
+	require.Len(t, export.Results, 2)
+	assert.Equal(t, "r1", export.Results[0].ID, "results are oldest first")
+	require.Len(t, export.Players, 2)
+	assert.Equal(t, "alice", export.Players[0].ID, "players are ordered by ID")
+
+	// Merging keeps what the destination already has
+	destination := NewMemoryRepository()
+	require.NoError(t, destination.SaveResult(ctx, &game.Result{ID: "r1", PlayerID: "alice", Side: game.Tails, Timestamp: start}))
+	require.NoError(t, destination.SavePlayer(ctx, &game.Player{ID: "alice", Balance: 5 * game.Dollar}))
+
+	report, err := destination.Import(ctx, export, false)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportReport{Results: 1, DuplicateResults: 1, Players: 1, KeptPlayers: 1}, report)
+	assert.Equal(t, 2, destination.GetResultCount())
+	alice, err := destination.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 5*game.Dollar, alice.Balance)
+	bob, err := destination.GetPlayer(ctx, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, 80*game.Dollar, bob.Balance)
+
+	// Overwriting replaces players but never a stored result
+	report, err = destination.Import(ctx, export, true)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportReport{DuplicateResults: 2, Players: 2}, report)
+	alice, err = destination.GetPlayer(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 120*game.Dollar, alice.Balance)
+	results, err := destination.GetResults(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, game.Tails, results[1].Side)
+}
+
+func TestMemoryRepository_ImportRejectsMissingIDs(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository()
+
+	_, err := repo.Import(ctx, &Export{
+		Results: []*game.Result{{ID: "r1"}, {}},
+	}, false)
+	assert.Error(t, err)
+	_, err = repo.Import(ctx, &Export{Players: []*game.Player{{}}}, false)
+	assert.Error(t, err)
+	assert.Zero(t, repo.GetResultCount(), "nothing is imported")
+}
+
+func TestExportFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup", "coinflip.json")
+	export := &Export{
+		Version: ExportVersion,
+		Results: []*game.Result{{ID: "r1", PlayerID: "alice", Side: game.Heads, Hash: "abc"}},
+		Players: []*game.Player{{ID: "alice", Balance: 42 * game.Dollar}},
+	}
+	require.NoError(t, WriteExport(path, export))
+
+	read, err := ReadExport(path)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", read.Results[0].Hash)
+	assert.Equal(t, 42*game.Dollar, read.Players[0].Balance)
+
+	// Other JSON files are not mistaken for exports
+	other := filepath.Join(t.TempDir(), "wallet.json")
+	require.NoError(t, os.WriteFile(other, []byte(`{"alice":{"balance":1}}`), 0644))
+	_, err = ReadExport(other)
+	assert.Error(t, err)
+}
+
+func TestWriteHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("stale\n"), 0644))
+
+	results := []*game.Result{
+		{ID: "r1", PlayerID: "alice", Side: game.Heads, Hash: "h1"},
+		{ID: "r2", PlayerID: "alice", Side: game.Tails, PrevHash: "h1", Hash: "h2"},
+	}
+	require.NoError(t, WriteHistory(path, results))
+
+	read, err := ReadHistory(path)
+	require.NoError(t, err)
+	require.Len(t, read, 2)
+	assert.Equal(t, "r1", read[0].ID)
+	assert.Equal(t, "h1", read[1].PrevHash)
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"coinflip-game/internal/game"
+)
+
+// WalletEntry is a player's practice balance as the offline cache last
+// saw it, next to their server balance at the last sync
+type WalletEntry struct {
+	Balance  game.Money `json:"balance"`
+	Synced   game.Money `json:"synced"`
+	SyncedAt time.Time  `json:"synced_at,omitempty"`
+}
+
+// Pending returns what the balance changed by since the last sync
+func (e WalletEntry) Pending() game.Money {
+	return e.Balance - e.Synced
+}
+
+// WalletCache keeps players' practice balances between runs so offline
+// play can be reconciled with the server wallet once it is reachable.
+// Guests get a new ID every run, so their entries are kept in memory only;
+// an empty path keeps every entry in memory.
+type WalletCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]WalletEntry
+}
+
+// OpenWalletCache loads the wallet cache at path. A missing file opens an
+// empty cache.
+func OpenWalletCache(path string) (*WalletCache, error) {
+	cache := &WalletCache{path: path, entries: make(map[string]WalletEntry)}
+	if path == "" {
+		return cache, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		return nil, fmt.Errorf("failed to decode wallet cache: %w", err)
+	}
+	return cache, nil
+}
+
+// Get returns the player's entry
+func (c *WalletCache) Get(playerID string) (WalletEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[playerID]
+	return entry, exists
+}
+
+// Put stores the player's entry and saves the cache
+func (c *WalletCache) Put(playerID string, entry WalletEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[playerID] = entry
+	if game.IsGuest(playerID) {
+		return nil
+	}
+	return c.save()
+}
+
+// Rename moves an entry to a new player ID, such as when a guest registers
+// an account, and saves the cache
+func (c *WalletCache) Rename(fromID, toID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[fromID]
+	if !exists {
+		return nil
+	}
+	delete(c.entries, fromID)
+	c.entries[toID] = entry
+	return c.save()
+}
+
+// save writes the entries of registered players to the cache file. The
+// file is replaced atomically so a crash mid-write keeps the previous one.
+// Callers must hold c.mu.
+func (c *WalletCache) save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	kept := make(map[string]WalletEntry, len(c.entries))
+	for id, entry := range c.entries {
+		if !game.IsGuest(id) {
+			kept[id] = entry
+		}
+	}
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode wallet cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create wallet cache directory: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write wallet cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace wallet cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"coinflip-game/internal/game"
+)
+
+func TestWalletCache_PersistsAccounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "wallet.json")
+
+	cache, err := OpenWalletCache(path)
+	require.NoError(t, err)
+	_, exists := cache.Get("alice")
+	assert.False(t, exists)
+
+	syncedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := WalletEntry{Balance: 1250 * game.Dollar, Synced: 1000 * game.Dollar, SyncedAt: syncedAt}
+	require.NoError(t, cache.Put("alice", entry))
+	guest := game.NewGuestID()
+	require.NoError(t, cache.Put(guest, WalletEntry{Balance: 900 * game.Dollar, Synced: 1000 * game.Dollar}))
+	assert.Equal(t, -100*game.Dollar, mustGet(t, cache, guest).Pending())
+
+	reopened, err := OpenWalletCache(path)
+	require.NoError(t, err)
+	assert.Equal(t, entry, mustGet(t, reopened, "alice"))
+	assert.Equal(t, 250*game.Dollar, mustGet(t, reopened, "alice").Pending())
+	_, exists = reopened.Get(guest)
+	assert.False(t, exists, "guest entries are not saved")
+
+	// A guest who registers keeps their pending play
+	require.NoError(t, cache.Rename(guest, "bob"))
+	reopened, err = OpenWalletCache(path)
+	require.NoError(t, err)
+	assert.Equal(t, -100*game.Dollar, mustGet(t, reopened, "bob").Pending())
+}
+
+func mustGet(t *testing.T, cache *WalletCache, playerID string) WalletEntry {
+	t.Helper()
+
+	entry, exists := cache.Get(playerID)
+	require.True(t, exists, playerID)
+	return entry
+}
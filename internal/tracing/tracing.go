@@ -0,0 +1,176 @@
+// Package tracing sets up OpenTelemetry tracing for the application.
+// Instrumented packages start spans from the global tracer provider, which
+// discards them until Setup installs one that exports to an OTLP collector.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultServiceName names the service spans are reported under
+const DefaultServiceName = "coinflip"
+
+// traceParentHeader is the W3C trace context field carried between
+// processes
+const traceParentHeader = "traceparent"
+
+// propagator reads and writes traceparent fields whether or not Setup ran,
+// so a disabled process still passes its caller's trace along
+var propagator = propagation.TraceContext{}
+
+// Config controls where spans are exported
+type Config struct {
+	Enabled bool
+	// Endpoint is the OTLP/HTTP collector, as host:port or a full URL such
+	// as https://collector:4318/v1/traces
+	Endpoint string
+	// Insecure sends spans over plain HTTP to a host:port endpoint
+	Insecure    bool
+	ServiceName string
+	// SampleRatio is the share of new traces recorded, from 0 to 1. Spans
+	// continuing a trace follow the caller's decision.
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The returned function flushes pending spans and must be
+// called before the process exits. When tracing is disabled spans are
+// discarded and shutting down does nothing.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+	if !config.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, fmt.Errorf("trace sample ratio must be between 0 and 1, got %v", config.SampleRatio)
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = DefaultServiceName
+	}
+
+	var opts []otlptracehttp.Option
+	switch {
+	case config.Endpoint == "":
+		return nil, errors.New("trace endpoint cannot be empty")
+	case strings.HasPrefix(config.Endpoint, "http://"), strings.HasPrefix(config.Endpoint, "https://"):
+		opts = append(opts, otlptracehttp.WithEndpointURL(config.Endpoint))
+	default:
+		opts = append(opts, otlptracehttp.WithEndpoint(config.Endpoint))
+		if config.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", config.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the named tracer of the global provider
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// Inject returns the traceparent of the span in ctx, for carrying the
+// trace to another process, or "" when there is none
+func Inject(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier[traceParentHeader]
+}
+
+// InjectHeader adds the traceparent of the span in ctx to an outgoing HTTP
+// request's headers
+func InjectHeader(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract returns ctx continuing the trace a traceparent names. An empty
+// or malformed traceparent leaves ctx unchanged.
+func Extract(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	carrier := propagation.MapCarrier{traceParentHeader: traceParent}
+	return propagator.Extract(ctx, carrier)
+}
+
+// Middleware traces each request to an HTTP route, continuing the trace of
+// a caller that sent a traceparent header. Spans are named for the route
+// pattern rather than the path, so IDs in paths do not multiply span names.
+func Middleware(tracer trace.Tracer, route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+		span.SetAttributes(attribute.Int("http.response.status_code", recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// statusRecorder remembers the status code a handler responded with
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// End records err on span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
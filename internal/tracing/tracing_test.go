@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newRecordingTracer returns a tracer whose ended spans the recorder keeps
+func newRecordingTracer() (trace.Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return provider.Tracer("test"), recorder
+}
+
+func TestInjectExtract(t *testing.T) {
+	tracer, _ := newRecordingTracer()
+	ctx, span := tracer.Start(context.Background(), "caller")
+	defer span.End()
+
+	traceParent := Inject(ctx)
+	require.NotEmpty(t, traceParent)
+
+	remote := trace.SpanContextFromContext(Extract(context.Background(), traceParent))
+	assert.True(t, remote.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), remote.SpanID())
+
+	// Nothing to carry without a span, and nothing to continue from junk
+	assert.Empty(t, Inject(context.Background()))
+	assert.False(t, trace.SpanContextFromContext(Extract(context.Background(), "not-a-trace")).IsValid())
+}
+
+func TestMiddleware(t *testing.T) {
+	tracer, recorder := newRecordingTracer()
+	handler := Middleware(tracer, "GET /players/{id}/stats", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, trace.SpanContextFromContext(r.Context()).IsValid())
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	caller, span := tracer.Start(context.Background(), "cli")
+	req := httptest.NewRequest(http.MethodGet, "/players/p1/stats", nil)
+	InjectHeader(caller, req.Header)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	served := spans[0]
+	assert.Equal(t, "GET /players/{id}/stats", served.Name())
+	assert.Equal(t, span.SpanContext().TraceID(), served.SpanContext().TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), served.Parent().SpanID())
+	assert.Contains(t, served.Attributes(), attribute.Int("http.response.status_code", http.StatusNotFound))
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	// Spans reach the collector by the time shutdown returns
+	received := make(chan string, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- r.URL.Path:
+		default:
+		}
+	}))
+	defer collector.Close()
+
+	shutdown, err = Setup(context.Background(), Config{Enabled: true, Endpoint: collector.URL + "/v1/traces", SampleRatio: 1})
+	require.NoError(t, err)
+	_, span := Tracer("test").Start(context.Background(), "round")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+	select {
+	case path := <-received:
+		assert.Equal(t, "/v1/traces", path)
+	default:
+		t.Fatal("no spans were exported")
+	}
+
+	_, err = Setup(context.Background(), Config{Enabled: true, Endpoint: "localhost:4318", SampleRatio: 2})
+	assert.Error(t, err)
+	_, err = Setup(context.Background(), Config{Enabled: true, SampleRatio: 1})
+	assert.Error(t, err)
+}
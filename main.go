@@ -1,21 +1,24 @@
-//go:build !gui && !server
+//go:build !gui && !server && !admin
 
 // main.go is the CLI entry point for the multiplayer coin flip game.
 // This provides both single-player and multiplayer CLI functionality.
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"coinflip-game/internal/config"
 	"coinflip-game/internal/logger"
+	"coinflip-game/internal/tracing"
 	"coinflip-game/cmd/cli/commands"
 )
 
 func main() {
-	// Load configuration
-	cfg, err := config.Load("")
+	// Load configuration, from the profile chosen with --profile if any
+	cfg, err := config.LoadProfile(config.ProfileFromArgs(os.Args[1:]))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
@@ -29,10 +32,26 @@ func main() {
 	}
 	defer log.Sync()
 
+	// Export traces if configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.ToTracingConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize tracing: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create and execute root command
 	rootCmd := commands.NewRootCommand(cfg, log)
 	
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	code := commands.Execute(context.Background(), rootCmd)
+
+	// Flush spans, without hanging on an unreachable collector
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to flush traces: %v\n", err)
+	}
+	cancel()
+	
+	if code != commands.ExitOK {
+		os.Exit(code)
 	}
 }
\ No newline at end of file
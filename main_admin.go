@@ -0,0 +1,38 @@
+//go:build admin
+
+// main_admin.go is the administration entry point for the multiplayer coin flip game.
+// This talks to a running server's admin endpoints for maintenance tasks.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"coinflip-game/cmd/admin/commands"
+	"coinflip-game/internal/config"
+	"coinflip-game/internal/logger"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	log, err := logger.New(cfg.Logging.Level, cfg.Logging.Development)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer log.Sync()
+
+	// Create and execute root command
+	rootCmd := commands.NewRootCommand(cfg, log)
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -5,21 +5,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
-
-	"go.uber.org/zap"
+	"time"
 
+	"coinflip-game/cmd/cli/commands"
 	"coinflip-game/internal/config"
 	"coinflip-game/internal/logger"
-	"coinflip-game/internal/network"
+	"coinflip-game/internal/tracing"
 )
 
 func main() {
-	// Load configuration
-	cfg, err := config.Load("")
+	// Load configuration, from the profile chosen with --profile if any
+	cfg, err := config.LoadProfile(config.ProfileFromArgs(os.Args[1:]))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
@@ -33,45 +32,27 @@ func main() {
 	}
 	defer log.Sync()
 
-	// Create server configuration from app config with defaults
-	serverConfig := network.DefaultServerConfig()
-	if cfg.Multiplayer.ServerHost != "" {
-		serverConfig.Host = cfg.Multiplayer.ServerHost
-	}
-	if cfg.Multiplayer.ServerPort > 0 {
-		serverConfig.Port = cfg.Multiplayer.ServerPort
-	}
-	if cfg.Multiplayer.MaxRooms > 0 {
-		serverConfig.MaxRooms = cfg.Multiplayer.MaxRooms
-	}
-	if cfg.Multiplayer.MaxPlayers > 0 {
-		serverConfig.MaxClientsRoom = cfg.Multiplayer.MaxPlayers
+	// Export traces if configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.ToTracingConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize tracing: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Create and start the multiplayer server
-	server := network.NewServer(serverConfig, log)
-
-	// Handle graceful shutdown
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	// Run the server with the same flags as `coinflip serve`
+	serveCmd := commands.NewServeCommand(cfg, log)
 
-	go func() {
-		<-c
-		log.Info("Shutting down server...")
-		server.Stop()
-		os.Exit(0)
-	}()
+	code := commands.Execute(context.Background(), serveCmd)
 
-	log.Info("Starting multiplayer coin flip server",
-		zap.String("host", serverConfig.Host),
-		zap.Int("port", serverConfig.Port),
-		zap.Int("max_rooms", serverConfig.MaxRooms),
-		zap.Int("max_players_per_room", serverConfig.MaxClientsRoom),
-	)
+	// Flush spans, without hanging on an unreachable collector
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to flush traces: %v\n", err)
+	}
+	cancel()
 
-	// Start the server (this blocks)
-	if err := server.Start(); err != nil {
-		log.Error("Server failed to start", zap.Error(err))
-		os.Exit(1)
+	if code != commands.ExitOK {
+		log.Sync()
+		os.Exit(code)
 	}
-}
\ No newline at end of file
+}
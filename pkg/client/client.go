@@ -0,0 +1,620 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// Options configures a Client
+type Options struct {
+	// ServerURL is the server's WebSocket endpoint, e.g. ws://localhost:8080/ws
+	ServerURL string
+
+	// PlayerID identifies the player; a unique ID is generated when empty
+	PlayerID string
+
+	// PlayerName is shown to other players; defaults to PlayerID
+	PlayerName string
+
+	// Encoding is the preferred wire format, "json" (default) or "msgpack"
+	Encoding string
+
+	// Compression negotiates permessage-deflate with the server
+	Compression bool
+
+	// Logger receives connection diagnostics; nil discards them
+	Logger *zap.Logger
+}
+
+// Client is a connection to the multiplayer server for one player. It is
+// safe for concurrent use.
+type Client struct {
+	conn       *network.NetworkClient
+	playerID   string
+	playerName string
+
+	mu      sync.Mutex
+	roomID  string
+	waiters map[*waiter]struct{}
+	closed  bool
+
+	// Registered callbacks, in the order they were registered
+	onResult       []func(*Result)
+	onRoomUpdate   []func(*Room)
+	onBettingOpen  []func(*BettingPhase)
+	onCancellation []func(*Cancellation)
+	onRoomClosed   []func(*RoomClosed)
+	onDisconnect   []func(error)
+
+	callbacks *callbackQueue
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a client without connecting it
+func New(opts Options) *Client {
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	playerID := opts.PlayerID
+	if playerID == "" {
+		playerID = fmt.Sprintf("sdk_%d", time.Now().UnixNano())
+	}
+	playerName := opts.PlayerName
+	if playerName == "" {
+		playerName = playerID
+	}
+
+	config := network.DefaultClientConfig()
+	if opts.ServerURL != "" {
+		config.ServerURL = opts.ServerURL
+	}
+	if opts.Encoding != "" {
+		config.Encoding = network.Encoding(opts.Encoding)
+	}
+	config.EnableCompression = opts.Compression
+	// Reconnects would rejoin with a made-up balance; callers reconnect explicitly
+	config.MaxReconnects = 0
+
+	return &Client{
+		conn:       network.NewNetworkClient(config, playerID, playerName, logger),
+		playerID:   playerID,
+		playerName: playerName,
+		waiters:    make(map[*waiter]struct{}),
+		callbacks:  newCallbackQueue(),
+		done:       make(chan struct{}),
+	}
+}
+
+// Dial creates a client and connects it to the server
+func Dial(ctx context.Context, opts Options) (*Client, error) {
+	c := New(opts)
+	if err := c.Connect(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Connect opens the connection to the server
+func (c *Client) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.conn.Connect()
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		go func() {
+			if <-errCh == nil {
+				c.conn.Disconnect()
+			}
+		}()
+		return ctx.Err()
+	}
+
+	go c.dispatch()
+	go c.callbacks.run(c.done)
+	return nil
+}
+
+// Close disconnects from the server. Pending calls fail with ErrClosed.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+
+		close(c.done)
+		c.conn.Disconnect()
+		c.failWaiters(ErrClosed)
+	})
+	return nil
+}
+
+// PlayerID returns the ID the client plays under
+func (c *Client) PlayerID() string {
+	return c.playerID
+}
+
+// PlayerName returns the name shown to other players
+func (c *Client) PlayerName() string {
+	return c.playerName
+}
+
+// RoomID returns the room the client last joined, or "" if none
+func (c *Client) RoomID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.roomID
+}
+
+// OnResult registers fn to be called with every settled round. Each On*
+// method adds to the callbacks registered before, which run in order.
+func (c *Client) OnResult(fn func(*Result)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onResult = append(c.onResult, fn)
+}
+
+// OnRoomUpdate registers fn to be called whenever the room state changes
+func (c *Client) OnRoomUpdate(fn func(*Room)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onRoomUpdate = append(c.onRoomUpdate, fn)
+}
+
+// OnBettingOpen registers fn to be called when a betting phase starts
+func (c *Client) OnBettingOpen(fn func(*BettingPhase)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onBettingOpen = append(c.onBettingOpen, fn)
+}
+
+// OnRoundCancelled registers fn to be called when the server aborts a round
+func (c *Client) OnRoundCancelled(fn func(*Cancellation)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onCancellation = append(c.onCancellation, fn)
+}
+
+// OnRoomClosed registers fn to be called when the server closes the room
+// the client is in
+func (c *Client) OnRoomClosed(fn func(*RoomClosed)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onRoomClosed = append(c.onRoomClosed, fn)
+}
+
+// OnDisconnect registers fn to be called if the connection is lost
+func (c *Client) OnDisconnect(fn func(error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onDisconnect = append(c.onDisconnect, fn)
+}
+
+// Join enters a room, creating it if needed, and returns its state once
+// the server has seated the player. When the room is full the player waits
+// in line for a seat, watching the room through OnRoomUpdate, and gives up
+// their place if ctx ends first.
+func (c *Client) Join(ctx context.Context, roomID string, balance float64) (*Room, error) {
+	money, err := game.MoneyFromFloat(balance)
+	if err != nil {
+		return nil, ErrInvalidAmount
+	}
+
+	w := c.addWaiter(func(msg *network.Message) (interface{}, bool, error) {
+		switch msg.Type {
+		case network.MsgRoomUpdate:
+			var update network.RoomUpdateData
+			if msg.RoomID != roomID || msg.GetData(&update) != nil {
+				return nil, false, nil
+			}
+			room := roomFromUpdate(&update)
+			if _, seated := room.Player(c.playerID); !seated {
+				return nil, false, nil
+			}
+			return room, true, nil
+		case network.MsgRoomClosed:
+			var data network.RoomClosedData
+			if msg.GetData(&data) != nil || data.RoomID != roomID {
+				return nil, false, nil
+			}
+			return nil, true, fmt.Errorf("%w: %s", ErrRoomClosed, data.Reason)
+		case network.MsgError:
+			return rejection(msg, ErrJoinRejected)
+		}
+		return nil, false, nil
+	})
+
+	if err := c.conn.JoinRoom(roomID, money); err != nil {
+		c.removeWaiter(w)
+		return nil, c.connError(err)
+	}
+
+	value, err := c.wait(ctx, w)
+	if err != nil {
+		if ctx.Err() != nil {
+			c.conn.LeaveRoom()
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.roomID = roomID
+	c.mu.Unlock()
+	return value.(*Room), nil
+}
+
+// Leave exits the current room. Any unsettled bet is refunded by the server.
+func (c *Client) Leave(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.RoomID() == "" {
+		return ErrNotInRoom
+	}
+
+	if err := c.conn.LeaveRoom(); err != nil {
+		return c.connError(err)
+	}
+
+	c.mu.Lock()
+	c.roomID = ""
+	c.mu.Unlock()
+	return nil
+}
+
+// Bet places a bet in the current room and returns it once the server has
+// accepted it. Bets are only accepted during the betting phase; a second bet
+// on the other side hedges the first. The amount must be whole cents.
+func (c *Client) Bet(ctx context.Context, amount float64, side Side) (*Bet, error) {
+	if side != Heads && side != Tails {
+		return nil, ErrInvalidSide
+	}
+	stake, err := game.MoneyFromFloat(amount)
+	if err != nil {
+		return nil, ErrInvalidAmount
+	}
+	if c.RoomID() == "" {
+		return nil, ErrNotInRoom
+	}
+
+	w := c.addWaiter(func(msg *network.Message) (interface{}, bool, error) {
+		switch msg.Type {
+		case network.MsgBetPlaced:
+			var bet network.BetData
+			if msg.PlayerID != c.playerID || msg.GetData(&bet) != nil {
+				return nil, false, nil
+			}
+			accepted := betFromData(&bet)
+			return &accepted, true, nil
+		case network.MsgError:
+			return rejection(msg, ErrBetRejected, ErrNotInRoom)
+		}
+		return nil, false, nil
+	})
+
+	if err := c.conn.PlaceBet(stake, game.Side(side)); err != nil {
+		c.removeWaiter(w)
+		return nil, c.connError(err)
+	}
+
+	value, err := c.wait(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	return value.(*Bet), nil
+}
+
+// NextResult waits for the next round to be settled in the current room
+func (c *Client) NextResult(ctx context.Context) (*Result, error) {
+	roomID := c.RoomID()
+	if roomID == "" {
+		return nil, ErrNotInRoom
+	}
+
+	w := c.addWaiter(func(msg *network.Message) (interface{}, bool, error) {
+		var data network.GameResultData
+		if msg.Type != network.MsgGameResult || msg.RoomID != roomID || msg.GetData(&data) != nil {
+			return nil, false, nil
+		}
+		return resultFromData(roomID, &data), true, nil
+	})
+
+	value, err := c.wait(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	return value.(*Result), nil
+}
+
+// Stats fetches a player's lifetime statistics from the server
+func (c *Client) Stats(ctx context.Context, playerID string) (*Stats, error) {
+	w := c.addWaiter(func(msg *network.Message) (interface{}, bool, error) {
+		switch msg.Type {
+		case network.MsgPlayerStats:
+			var data network.PlayerStatsData
+			if msg.GetData(&data) != nil || data.PlayerID != playerID {
+				return nil, false, nil
+			}
+			return statsFromData(data.Stats), true, nil
+		case network.MsgError:
+			return rejection(msg)
+		}
+		return nil, false, nil
+	})
+
+	if err := c.conn.RequestPlayerStats(playerID); err != nil {
+		c.removeWaiter(w)
+		return nil, c.connError(err)
+	}
+
+	value, err := c.wait(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	return value.(*Stats), nil
+}
+
+// rejection turns an error message into a *ServerError for a waiter. With
+// categories given, only errors in one of them are claimed.
+func rejection(msg *network.Message, categories ...error) (interface{}, bool, error) {
+	var data network.ErrorData
+	if err := msg.GetData(&data); err != nil {
+		return nil, false, nil
+	}
+
+	serverErr := &ServerError{Code: data.Code, Message: data.Message}
+	if len(categories) == 0 {
+		return nil, true, serverErr
+	}
+	for _, category := range categories {
+		if errors.Is(serverErr, category) {
+			return nil, true, serverErr
+		}
+	}
+	return nil, false, nil
+}
+
+// connError maps connection failures of the underlying client
+func (c *Client) connError(err error) error {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+
+	if closed {
+		return ErrClosed
+	}
+	if !c.conn.IsConnected() {
+		return fmt.Errorf("%w: %v", ErrNotConnected, err)
+	}
+	return err
+}
+
+// dispatch routes server messages to waiting calls and callbacks
+func (c *Client) dispatch() {
+	events := c.conn.GetEventChannel()
+	errs := c.conn.GetErrorChannel()
+
+	for {
+		select {
+		case <-c.done:
+			return
+
+		case err := <-errs:
+			c.mu.Lock()
+			closed := c.closed
+			c.mu.Unlock()
+			if closed {
+				return
+			}
+
+			c.failWaiters(fmt.Errorf("%w: %v", ErrNotConnected, err))
+
+			c.mu.Lock()
+			fns := c.onDisconnect
+			c.mu.Unlock()
+			if len(fns) > 0 {
+				c.callbacks.push(func() { runCallbacks(fns, err) })
+			}
+			return
+
+		case msg := <-events:
+			c.resolveWaiters(msg)
+			c.queueCallback(msg)
+		}
+	}
+}
+
+// queueCallback schedules the registered callback for msg, if any
+func (c *Client) queueCallback(msg *network.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch msg.Type {
+	case network.MsgGameResult:
+		var data network.GameResultData
+		if len(c.onResult) > 0 && msg.GetData(&data) == nil {
+			fns, result := c.onResult, resultFromData(msg.RoomID, &data)
+			c.callbacks.push(func() { runCallbacks(fns, result) })
+		}
+
+	case network.MsgRoomUpdate:
+		var data network.RoomUpdateData
+		if len(c.onRoomUpdate) > 0 && msg.GetData(&data) == nil {
+			fns, room := c.onRoomUpdate, roomFromUpdate(&data)
+			c.callbacks.push(func() { runCallbacks(fns, room) })
+		}
+
+	case network.MsgBetPhase:
+		var data network.TimerData
+		if len(c.onBettingOpen) > 0 && msg.GetData(&data) == nil {
+			fns, phase := c.onBettingOpen, &BettingPhase{RoomID: msg.RoomID, SecondsLeft: data.SecondsLeft}
+			c.callbacks.push(func() { runCallbacks(fns, phase) })
+		}
+
+	case network.MsgRoundCancelled:
+		var data network.RoundCancelledData
+		if len(c.onCancellation) > 0 && msg.GetData(&data) == nil {
+			fns, cancellation := c.onCancellation, cancellationFromData(msg.RoomID, &data)
+			c.callbacks.push(func() { runCallbacks(fns, cancellation) })
+		}
+
+	case network.MsgRoomClosed:
+		var data network.RoomClosedData
+		if msg.GetData(&data) != nil || data.RoomID != c.roomID {
+			return
+		}
+		c.roomID = ""
+		if len(c.onRoomClosed) > 0 {
+			fns, closed := c.onRoomClosed, &RoomClosed{RoomID: data.RoomID, Reason: data.Reason}
+			c.callbacks.push(func() { runCallbacks(fns, closed) })
+		}
+	}
+}
+
+// runCallbacks calls each registered callback with the event
+func runCallbacks[T any](fns []func(T), event T) {
+	for _, fn := range fns {
+		fn(event)
+	}
+}
+
+// waiter is a call blocked until a matching server message arrives
+type waiter struct {
+	match  func(*network.Message) (interface{}, bool, error)
+	result chan waitResult
+}
+
+type waitResult struct {
+	value interface{}
+	err   error
+}
+
+func (c *Client) addWaiter(match func(*network.Message) (interface{}, bool, error)) *waiter {
+	w := &waiter{match: match, result: make(chan waitResult, 1)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		w.result <- waitResult{err: ErrClosed}
+		return w
+	}
+	c.waiters[w] = struct{}{}
+	return w
+}
+
+func (c *Client) removeWaiter(w *waiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.waiters, w)
+}
+
+// wait blocks until w is resolved or ctx is done
+func (c *Client) wait(ctx context.Context, w *waiter) (interface{}, error) {
+	select {
+	case res := <-w.result:
+		return res.value, res.err
+	case <-ctx.Done():
+		c.removeWaiter(w)
+		return nil, ctx.Err()
+	}
+}
+
+// resolveWaiters completes every waiter that msg answers
+func (c *Client) resolveWaiters(msg *network.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for w := range c.waiters {
+		value, ok, err := w.match(msg)
+		if !ok {
+			continue
+		}
+		w.result <- waitResult{value: value, err: err}
+		delete(c.waiters, w)
+
+		// Errors are not tied to a request, so one error answers one call
+		if msg.Type == network.MsgError {
+			return
+		}
+	}
+}
+
+// failWaiters completes every pending call with err
+func (c *Client) failWaiters(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for w := range c.waiters {
+		w.result <- waitResult{err: err}
+		delete(c.waiters, w)
+	}
+}
+
+// callbackQueue runs callbacks in order on a single goroutine without ever
+// blocking the dispatcher
+type callbackQueue struct {
+	mu      sync.Mutex
+	pending []func()
+	signal  chan struct{}
+}
+
+func newCallbackQueue() *callbackQueue {
+	return &callbackQueue{signal: make(chan struct{}, 1)}
+}
+
+func (q *callbackQueue) push(fn func()) {
+	q.mu.Lock()
+	q.pending = append(q.pending, fn)
+	q.mu.Unlock()
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (q *callbackQueue) run(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-q.signal:
+		}
+
+		q.mu.Lock()
+		batch := q.pending
+		q.pending = nil
+		q.mu.Unlock()
+
+		for _, fn := range batch {
+			fn()
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"coinflip-game/internal/network"
+)
+
+// startServer runs a real server with one-player rooms and short phases
+func startServer(t *testing.T) string {
+	return startServerWith(t, nil)
+}
+
+// startServerWith runs a server like startServer after letting configure
+// change its room defaults
+func startServerWith(t *testing.T, configure func(*network.RoomConfig)) string {
+	t.Helper()
+
+	config := network.DefaultServerConfig()
+	config.RoomDefaults = network.DefaultRoomConfig()
+	config.RoomDefaults.MinPlayers = 1
+	config.RoomDefaults.BettingDuration = time.Second
+	config.RoomDefaults.ResultDuration = time.Second
+	if configure != nil {
+		configure(config.RoomDefaults)
+	}
+
+	server := network.NewServer(config, zaptest.NewLogger(t))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+
+	return "ws://" + listener.Addr().String() + "/ws"
+}
+
+func TestClient_JoinBetResult(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := Dial(ctx, Options{ServerURL: startServer(t), PlayerName: "bot"})
+	require.NoError(t, err)
+	defer c.Close()
+
+	bettingOpen := make(chan struct{}, 1)
+	c.OnBettingOpen(func(*BettingPhase) {
+		select {
+		case bettingOpen <- struct{}{}:
+		default:
+		}
+	})
+	results := make(chan *Result, 1)
+	c.OnResult(func(r *Result) {
+		results <- r
+	})
+	// A second callback runs alongside the first
+	tallied := make(chan string, 1)
+	c.OnResult(func(r *Result) {
+		tallied <- r.RoundID
+	})
+
+	room, err := c.Join(ctx, "sdk", 500)
+	require.NoError(t, err)
+	assert.Equal(t, "sdk", c.RoomID())
+
+	player, ok := room.Player(c.PlayerID())
+	require.True(t, ok)
+	assert.Equal(t, "bot", player.Name)
+
+	if room.Phase != PhaseBetting {
+		select {
+		case <-bettingOpen:
+		case <-ctx.Done():
+			t.Fatal("betting never opened")
+		}
+	}
+
+	bet, err := c.Bet(ctx, 10, Heads)
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, bet.Amount)
+	assert.Equal(t, Heads, bet.Side)
+
+	result, err := c.NextResult(ctx)
+	require.NoError(t, err)
+
+	outcome, ok := result.Outcome(c.PlayerID())
+	require.True(t, ok)
+	require.Len(t, outcome.Bets, 1)
+	assert.Equal(t, bet.ID, outcome.Bets[0].ID)
+	assert.Equal(t, outcome.Won, result.Side == Heads)
+
+	// The callback sees the same round
+	select {
+	case r := <-results:
+		assert.Equal(t, result.RoundID, r.RoundID)
+	case <-ctx.Done():
+		t.Fatal("OnResult was not called")
+	}
+	select {
+	case roundID := <-tallied:
+		assert.Equal(t, result.RoundID, roundID)
+	case <-ctx.Done():
+		t.Fatal("the second OnResult callback was not called")
+	}
+
+	stats, err := c.Stats(ctx, c.PlayerID())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.GamesPlayed)
+}
+
+func TestClient_JoinWaitsForSeat(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	url := startServerWith(t, func(room *network.RoomConfig) {
+		room.MaxPlayers = 1
+	})
+	seated, err := Dial(ctx, Options{ServerURL: url})
+	require.NoError(t, err)
+	defer seated.Close()
+	_, err = seated.Join(ctx, "full", 500)
+	require.NoError(t, err)
+
+	waiting, err := Dial(ctx, Options{ServerURL: url})
+	require.NoError(t, err)
+	defer waiting.Close()
+	inLine := make(chan struct{})
+	var once sync.Once
+	waiting.OnRoomUpdate(func(room *Room) {
+		if room.QueuePosition(waiting.PlayerID()) == 1 {
+			once.Do(func() { close(inLine) })
+		}
+	})
+
+	joined := make(chan *Room, 1)
+	go func() {
+		room, err := waiting.Join(ctx, "full", 500)
+		assert.NoError(t, err)
+		joined <- room
+	}()
+
+	select {
+	case <-inLine:
+	case <-ctx.Done():
+		t.Fatal("never queued for a seat")
+	}
+	require.NoError(t, seated.Leave(ctx))
+
+	select {
+	case room := <-joined:
+		require.NotNil(t, room)
+		_, ok := room.Player(waiting.PlayerID())
+		assert.True(t, ok)
+		assert.Empty(t, room.Waiting)
+	case <-ctx.Done():
+		t.Fatal("never seated")
+	}
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := Dial(ctx, Options{ServerURL: startServer(t)})
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.Bet(ctx, 10, Heads)
+	assert.ErrorIs(t, err, ErrNotInRoom)
+
+	_, err = c.Join(ctx, "sdk", 500)
+	require.NoError(t, err)
+
+	_, err = c.Bet(ctx, 10, Side("edge"))
+	assert.ErrorIs(t, err, ErrInvalidSide)
+
+	// Above the room's maximum bet
+	_, err = c.Bet(ctx, 1e6, Tails)
+	assert.ErrorIs(t, err, ErrBetRejected)
+
+	var serverErr *ServerError
+	require.True(t, errors.As(err, &serverErr))
+	assert.Equal(t, "bet_failed", serverErr.Code)
+
+	require.NoError(t, c.Close())
+	_, err = c.NextResult(ctx)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestDial_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Dial(ctx, Options{ServerURL: startServer(t)})
+	assert.Error(t, err)
+}
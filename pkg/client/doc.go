@@ -0,0 +1,37 @@
+// Package client is the public Go SDK for the multiplayer coin flip server.
+//
+// It wraps the WebSocket protocol in a small blocking API so bots and
+// alternative frontends do not need to know the wire format:
+//
+//	c, err := client.Dial(ctx, client.Options{ServerURL: "ws://localhost:8080/ws"})
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	c.OnResult(func(r *client.Result) {
+//		fmt.Println("coin landed on", r.Side)
+//	})
+//
+//	if _, err := c.Join(ctx, "lobby", 1000); err != nil {
+//		return err
+//	}
+//	_, err = c.Bet(ctx, 10, client.Heads)
+//
+// Join, Bet and Stats wait for the server's answer and honour context
+// cancellation. Requests the server refuses return a *ServerError, which
+// matches ErrJoinRejected, ErrBetRejected or ErrNotInRoom with errors.Is.
+//
+// Callbacks registered with the On* methods run one at a time, in the order
+// the server sent the events, on a goroutine owned by the client. They may
+// call back into the client, for example to bet from OnBettingOpen.
+// Registering a callback never replaces one registered before, so separate
+// parts of a program can each watch the same events.
+//
+// # Stability
+//
+// The package follows semantic versioning together with the module:
+// exported identifiers are only added, never removed or changed, within a
+// major version. The types here are deliberately independent of the
+// server's internal message structs so the protocol can evolve behind them.
+package client
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the client. Server refusals are reported as
+// *ServerError values that match the Rejected errors with errors.Is.
+var (
+	ErrNotConnected  = errors.New("not connected to server")
+	ErrClosed        = errors.New("client closed")
+	ErrNotInRoom     = errors.New("not in a room")
+	ErrInvalidSide   = errors.New("side must be heads or tails")
+	ErrInvalidAmount = errors.New("amount must be a whole number of cents")
+	ErrJoinRejected  = errors.New("join rejected")
+	ErrBetRejected   = errors.New("bet rejected")
+	ErrRoomClosed    = errors.New("room closed")
+)
+
+// ServerError is an error reported by the server
+type ServerError struct {
+	Code    string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("server error (%s): %s", e.Code, e.Message)
+}
+
+// Is reports whether the server error belongs to the target category
+func (e *ServerError) Is(target error) bool {
+	switch target {
+	case ErrJoinRejected:
+		return e.Code == "join_failed" || e.Code == "room_creation_failed" || e.Code == "invalid_room_settings"
+	case ErrBetRejected:
+		return e.Code == "bet_failed" || e.Code == "invalid_bet_data"
+	case ErrNotInRoom:
+		return e.Code == "not_in_room"
+	}
+	return false
+}
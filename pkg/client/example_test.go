@@ -0,0 +1,66 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"coinflip-game/pkg/client"
+)
+
+// A bot that bets on heads every round until it is stopped
+func Example_bot() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	c, err := client.Dial(ctx, client.Options{
+		ServerURL:  "ws://localhost:8080/ws",
+		PlayerName: "heads-bot",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	c.OnBettingOpen(func(phase *client.BettingPhase) {
+		if _, err := c.Bet(ctx, 5, client.Heads); err != nil {
+			log.Printf("bet refused: %v", err)
+		}
+	})
+	c.OnResult(func(r *client.Result) {
+		if outcome, ok := r.Outcome(c.PlayerID()); ok {
+			fmt.Printf("round %s: won=%v balance=%.2f\n", r.RoundID, outcome.Won, outcome.Balance)
+		}
+	})
+
+	if _, err := c.Join(ctx, "lobby", 1000); err != nil {
+		log.Fatal(err)
+	}
+	<-ctx.Done()
+}
+
+// Telling a refused bet apart from other failures
+func ExampleClient_Bet() {
+	ctx := context.Background()
+
+	c, err := client.Dial(ctx, client.Options{ServerURL: "ws://localhost:8080/ws"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.Join(ctx, "lobby", 100); err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = c.Bet(ctx, 500, client.Tails)
+	var serverErr *client.ServerError
+	switch {
+	case errors.As(err, &serverErr) && errors.Is(err, client.ErrBetRejected):
+		fmt.Println("server refused the bet:", serverErr.Message)
+	case err != nil:
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,254 @@
+package client
+
+import (
+	"time"
+
+	"coinflip-game/internal/game"
+	"coinflip-game/internal/network"
+)
+
+// Side is a face of the coin
+type Side string
+
+const (
+	Heads Side = "heads"
+	Tails Side = "tails"
+)
+
+// Phase is the stage of the round a room is in
+type Phase string
+
+const (
+	PhaseWaiting   Phase = "waiting"
+	PhaseBetting   Phase = "betting"
+	PhaseRevealing Phase = "revealing"
+	PhaseResult    Phase = "result"
+	PhasePaused    Phase = "paused"
+)
+
+// Player is a participant as seen by everyone in the room
+type Player struct {
+	ID      string
+	Name    string
+	Balance float64
+	HasBet  bool
+	Online  bool
+	// Bets are the player's positions this round, at most one per side
+	Bets []Bet
+}
+
+// Room is a snapshot of a room's state
+type Room struct {
+	ID          string
+	Phase       Phase
+	SecondsLeft int
+	MinPlayers  int
+	MaxPlayers  int
+	Players     []Player
+	// Waiting lists the IDs of spectators waiting for a seat in the full
+	// room, first in line first
+	Waiting []string
+}
+
+// Player returns the player with the given ID
+func (r *Room) Player(id string) (Player, bool) {
+	for _, player := range r.Players {
+		if player.ID == id {
+			return player, true
+		}
+	}
+	return Player{}, false
+}
+
+// QueuePosition returns the player's place in line for a seat, counting
+// from 1, or 0 when they are not waiting
+func (r *Room) QueuePosition(id string) int {
+	for i, waiting := range r.Waiting {
+		if waiting == id {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Bet is a bet accepted by the server. Requested is set when the room's
+// betting limits lowered the stake to Amount.
+type Bet struct {
+	ID        string
+	Amount    float64
+	Side      Side
+	Requested float64
+}
+
+// Outcome is one player's share of a settled round. Won is set when the
+// payout exceeds the total wagered across both sides.
+type Outcome struct {
+	PlayerID   string
+	PlayerName string
+	Bets       []Bet
+	Wagered    float64
+	Won        bool
+	Payout     float64
+	Balance    float64
+}
+
+// Result is a settled round
+type Result struct {
+	RoomID   string
+	RoundID  string
+	Side     Side
+	Seed     string
+	Time     time.Time
+	Outcomes []Outcome
+}
+
+// Outcome returns the given player's outcome, if they bet this round
+func (r *Result) Outcome(playerID string) (Outcome, bool) {
+	for _, outcome := range r.Outcomes {
+		if outcome.PlayerID == playerID {
+			return outcome, true
+		}
+	}
+	return Outcome{}, false
+}
+
+// Refund is a bet returned when a round is cancelled
+type Refund struct {
+	PlayerID string
+	Amount   float64
+	Balance  float64
+}
+
+// Cancellation is a round aborted by the server with all bets refunded
+type Cancellation struct {
+	RoomID  string
+	RoundID string
+	Reason  string
+	Refunds []Refund
+}
+
+// RoomClosed is a room the server closed while the client was in it. The
+// client is back in the lobby and may join another room.
+type RoomClosed struct {
+	RoomID string
+	Reason string
+}
+
+// BettingPhase announces that bets are open in a room
+type BettingPhase struct {
+	RoomID      string
+	SecondsLeft int
+}
+
+// Stats are a player's lifetime statistics recorded by the server
+type Stats struct {
+	GamesPlayed   int
+	GamesWon      int
+	TotalWagered  float64
+	TotalWinnings float64
+	NetProfit     float64
+	WinRate       float64
+	BiggestWin    float64
+}
+
+func roomFromUpdate(data *network.RoomUpdateData) *Room {
+	room := &Room{
+		ID:          data.RoomID,
+		Phase:       Phase(data.GameState),
+		SecondsLeft: data.Timer,
+		MinPlayers:  data.MinPlayers,
+		MaxPlayers:  data.MaxPlayers,
+		Players:     make([]Player, 0, len(data.Players)),
+	}
+	for _, queued := range data.SeatQueue {
+		room.Waiting = append(room.Waiting, queued.ID)
+	}
+	for _, player := range data.Players {
+		bets := make([]Bet, 0, len(player.Bets))
+		for i := range player.Bets {
+			bets = append(bets, betFromData(&player.Bets[i]))
+		}
+		room.Players = append(room.Players, Player{
+			ID:      player.ID,
+			Name:    player.Name,
+			Balance: player.Balance.Float64(),
+			HasBet:  player.HasBet,
+			Online:  player.IsOnline,
+			Bets:    bets,
+		})
+	}
+	return room
+}
+
+func betFromData(data *network.BetData) Bet {
+	if data == nil {
+		return Bet{}
+	}
+	return Bet{
+		ID:        data.BetID,
+		Amount:    data.Amount.Float64(),
+		Side:      Side(data.Choice),
+		Requested: data.Requested.Float64(),
+	}
+}
+
+func resultFromData(roomID string, data *network.GameResultData) *Result {
+	result := &Result{
+		RoomID:   roomID,
+		RoundID:  data.RoundID,
+		Side:     Side(data.CoinResult),
+		Seed:     data.FinalSeed,
+		Time:     data.Timestamp,
+		Outcomes: make([]Outcome, 0, len(data.Winners)+len(data.Losers)),
+	}
+	for _, players := range [][]network.PlayerResult{data.Winners, data.Losers} {
+		for _, player := range players {
+			bets := make([]Bet, 0, len(player.Bets))
+			for _, bet := range player.Bets {
+				bets = append(bets, betFromData(bet))
+			}
+			result.Outcomes = append(result.Outcomes, Outcome{
+				PlayerID:   player.PlayerID,
+				PlayerName: player.PlayerName,
+				Bets:       bets,
+				Wagered:    player.Wagered.Float64(),
+				Won:        player.Won,
+				Payout:     player.Payout.Float64(),
+				Balance:    player.NewBalance.Float64(),
+			})
+		}
+	}
+	return result
+}
+
+func cancellationFromData(roomID string, data *network.RoundCancelledData) *Cancellation {
+	cancellation := &Cancellation{
+		RoomID:  roomID,
+		RoundID: data.RoundID,
+		Reason:  data.Reason,
+		Refunds: make([]Refund, 0, len(data.Refunds)),
+	}
+	for _, refund := range data.Refunds {
+		cancellation.Refunds = append(cancellation.Refunds, Refund{
+			PlayerID: refund.PlayerID,
+			Amount:   refund.Amount.Float64(),
+			Balance:  refund.NewBalance.Float64(),
+		})
+	}
+	return cancellation
+}
+
+func statsFromData(stats *game.Stats) *Stats {
+	if stats == nil {
+		return &Stats{}
+	}
+	return &Stats{
+		GamesPlayed:   stats.GamesPlayed,
+		GamesWon:      stats.GamesWon,
+		TotalWagered:  stats.TotalWagered.Float64(),
+		TotalWinnings: stats.TotalWinnings.Float64(),
+		NetProfit:     stats.NetProfit.Float64(),
+		WinRate:       stats.WinRate,
+		BiggestWin:    stats.BiggestWin.Float64(),
+	}
+}